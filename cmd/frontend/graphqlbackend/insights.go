@@ -15,15 +15,167 @@ import (
 // InsightsResolver is the root resolver.
 type InsightsResolver interface {
 	Insights(ctx context.Context, args *InsightsArgs) (InsightConnectionResolver, error)
+	InsightsLanguageStatistics(ctx context.Context, args *InsightsLanguageStatisticsArgs) (InsightsLanguageStatisticsResolver, error)
+	InsightExport(ctx context.Context, args *InsightExportArgs) (InsightExportResolver, error)
+	InsightProblems(ctx context.Context) ([]InsightProblemResolver, error)
+	InsightDuplicates(ctx context.Context) ([]InsightDuplicateGroupResolver, error)
+	InsightSnapshotComparison(ctx context.Context, args *InsightSnapshotComparisonArgs) (InsightSnapshotComparisonResolver, error)
+	InsightSeriesImport(ctx context.Context, args *InsightSeriesImportArgs) (InsightSeriesImportResolver, error)
+	InsightSeriesRecomputation(ctx context.Context, args *InsightSeriesRecomputationArgs) (InsightSeriesRecomputationResolver, error)
+
+	// Mutations
+	RefreshInsightSeries(ctx context.Context, args *RefreshInsightSeriesArgs) (*EmptyResponse, error)
+	ResumeInsightSeries(ctx context.Context, args *ResumeInsightSeriesArgs) (*EmptyResponse, error)
+	ExportInsight(ctx context.Context, args *ExportInsightArgs) (InsightExportResolver, error)
+	CompareInsightSeriesSnapshots(ctx context.Context, args *CompareInsightSeriesSnapshotsArgs) (InsightSnapshotComparisonResolver, error)
+	ImportInsightSeriesPoints(ctx context.Context, args *ImportInsightSeriesPointsArgs) (InsightSeriesImportResolver, error)
+	RecomputeInsightSeries(ctx context.Context, args *RecomputeInsightSeriesArgs) (InsightSeriesRecomputationResolver, error)
+	CreateInsightSeriesAlertRule(ctx context.Context, args *CreateInsightSeriesAlertRuleArgs) (InsightSeriesAlertRuleResolver, error)
+	DeleteInsightSeriesAlertRule(ctx context.Context, args *DeleteInsightSeriesAlertRuleArgs) (*EmptyResponse, error)
 }
 
 type InsightsArgs struct {
 	Ids *[]graphql.ID
 }
 
+type InsightsLanguageStatisticsArgs struct {
+	Repository string
+}
+
+type RefreshInsightSeriesArgs struct {
+	SeriesID string
+}
+
+type ResumeInsightSeriesArgs struct {
+	SeriesID string
+}
+
+type InsightExportArgs struct {
+	ID graphql.ID
+}
+
+type ExportInsightArgs struct {
+	InsightID string
+	Format    string
+}
+
+type InsightSnapshotComparisonArgs struct {
+	ID graphql.ID
+}
+
+type CompareInsightSeriesSnapshotsArgs struct {
+	SeriesID string
+	From     DateTime
+	To       DateTime
+}
+
+type InsightSeriesImportArgs struct {
+	ID graphql.ID
+}
+
+type ImportInsightSeriesPointsArgs struct {
+	SeriesID   string
+	Format     string
+	Data       string
+	OnConflict string
+}
+
+type InsightSeriesRecomputationArgs struct {
+	ID graphql.ID
+}
+
+type RecomputeInsightSeriesArgs struct {
+	SeriesID string
+}
+
+type CreateInsightSeriesAlertRuleArgs struct {
+	Input struct {
+		SeriesID    string
+		Condition   string
+		Threshold   float64
+		WindowDays  *int32
+		NotifyEmail bool
+		WebhookURL  *string
+	}
+}
+
+type DeleteInsightSeriesAlertRuleArgs struct {
+	ID graphql.ID
+}
+
+type InsightSeriesAlertRuleResolver interface {
+	ID() graphql.ID
+	Condition() string
+	Threshold() float64
+	WindowDays() *int32
+	NotifyEmail() bool
+	WebhookURL() *string
+	Firing() bool
+	LastNotifiedAt() *DateTime
+}
+
+type InsightExportResolver interface {
+	ID() graphql.ID
+	InsightID() string
+	Format() string
+	State() string
+	Failure() *string
+	Data() *string
+}
+
+type InsightSnapshotComparisonResolver interface {
+	ID() graphql.ID
+	SeriesID() string
+	From() DateTime
+	To() DateTime
+	State() string
+	Failure() *string
+	Changes() (*[]InsightSnapshotRepositoryChangeResolver, error)
+}
+
+type InsightSeriesImportResolver interface {
+	ID() graphql.ID
+	SeriesID() string
+	State() string
+	Failure() *string
+	ImportedPoints() *int32
+	SkippedPoints() *int32
+}
+
+type InsightSeriesRecomputationResolver interface {
+	ID() graphql.ID
+	SeriesID() string
+	State() string
+	Failure() *string
+	DeletedPoints() *int32
+	RecordedPoints() int32
+	PendingBackfillJobs() int32
+}
+
+type InsightSnapshotRepositoryChangeResolver interface {
+	Repository() string
+	FromValue() float64
+	ToValue() float64
+	Delta() float64
+}
+
+type InsightsLanguageStatisticsResolver interface {
+	Commit() string
+	ComputedAt() DateTime
+	Languages() []LanguageStatisticsResolver
+}
+
 type InsightsDataPointResolver interface {
 	DateTime() DateTime
 	Value() float64
+	Approximate() bool
+}
+
+type InsightsRepositoryDataPointResolver interface {
+	Repository() string
+	DateTime() DateTime
+	Value() float64
+	Approximate() bool
 }
 
 type InsightStatusResolver interface {
@@ -31,6 +183,13 @@ type InsightStatusResolver interface {
 	PendingJobs() int32
 	CompletedJobs() int32
 	FailedJobs() int32
+	LastAttemptAt() *DateTime
+	LastSuccessAt() *DateTime
+	LastError() *string
+	ConsecutiveFailures() int32
+	BackfillQueuedAt() *DateTime
+	PendingBackfillJobs() int32
+	PausedAt() *DateTime
 }
 
 type InsightsPointsArgs struct {
@@ -40,17 +199,49 @@ type InsightsPointsArgs struct {
 	ExcludeRepoRegex *string
 }
 
+type InsightsRepositoryPointsArgs struct {
+	DateTime         DateTime
+	First            int32
+	IncludeRepoRegex *string
+	ExcludeRepoRegex *string
+}
+
 type InsightSeriesResolver interface {
+	SeriesID() string
 	Label() string
 	Points(ctx context.Context, args *InsightsPointsArgs) ([]InsightsDataPointResolver, error)
+	RepositoryPoints(ctx context.Context, args *InsightsRepositoryPointsArgs) ([]InsightsRepositoryDataPointResolver, error)
 	Status(ctx context.Context) (InsightStatusResolver, error)
+	AlertRules(ctx context.Context) ([]InsightSeriesAlertRuleResolver, error)
 }
 
 type InsightResolver interface {
 	Title() string
 	Description() string
-	Series() []InsightSeriesResolver
+	Series(ctx context.Context) ([]InsightSeriesResolver, error)
 	ID() string
+	Problems(ctx context.Context) ([]InsightProblemResolver, error)
+}
+
+type InsightProblemResolver interface {
+	InsightID() string
+	SeriesID() *string
+	SeriesLabel() *string
+	Message() string
+	DetectedAt() DateTime
+}
+
+type InsightDuplicateGroupResolver interface {
+	Canonical() InsightDuplicateResolver
+	Duplicates() []InsightDuplicateResolver
+	DetectedAt() DateTime
+}
+
+type InsightDuplicateResolver interface {
+	InsightID() string
+	Title() string
+	User(ctx context.Context) (*UserResolver, error)
+	Organization(ctx context.Context) (*OrgResolver, error)
 }
 
 type InsightConnectionResolver interface {
@@ -2,6 +2,18 @@ package graphqlbackend
 
 import "github.com/sourcegraph/sourcegraph/internal/inventory"
 
+// LanguageStatisticsResolver resolves the statistics of a single language.
+type LanguageStatisticsResolver interface {
+	Name() string
+	TotalBytes() float64
+	TotalLines() int32
+}
+
+// NewLanguageStatisticsResolver returns a resolver for the given language statistics.
+func NewLanguageStatisticsResolver(l inventory.Lang) LanguageStatisticsResolver {
+	return &languageStatisticsResolver{l: l}
+}
+
 type languageStatisticsResolver struct {
 	l inventory.Lang
 }
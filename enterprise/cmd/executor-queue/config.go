@@ -1,22 +1,127 @@
 package main
 
 import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
 	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/hostname"
 )
 
 type Config struct {
 	env.BaseConfig
 
-	Port int
+	Port                            int
+	AdminUsername                   string
+	AdminPassword                   string
+	UnredactedUsername              string
+	UnredactedPassword              string
+	QueuedCountRefreshInterval      time.Duration
+	ShutdownTimeout                 time.Duration
+	ReplicaID                       string
+	LeaderElectionInterval          time.Duration
+	ExecutorActiveThreshold         time.Duration
+	ExecutorRetention               time.Duration
+	ExecutorDeadThreshold           time.Duration
+	DeadExecutorCheckInterval       time.Duration
+	AuditLogRetention               time.Duration
+	SchedulerInterval               time.Duration
+	ReadReplicaDSN                  string
+	HealthCheckInterval             time.Duration
+	ConcurrencyLimitRefreshInterval time.Duration
+	EventPollInterval               time.Duration
+	MaxRequestBodySize              int
+	MaxRequestBodySizes             map[string]int64
+	AllowUnknownRequestFields       bool
 }
 
 func (c *Config) Load() {
 	c.Port = c.GetInt("EXECUTOR_QUEUE_API_PORT", "3191", "The port to listen on.")
+	c.AdminUsername = c.GetOptional("EXECUTOR_QUEUE_ADMIN_USERNAME", "The username required to access the admin API. The admin API is disabled if unset.")
+	c.AdminPassword = c.GetOptional("EXECUTOR_QUEUE_ADMIN_PASSWORD", "The password required to access the admin API. The admin API is disabled if unset.")
+	c.UnredactedUsername = c.GetOptional("EXECUTOR_QUEUE_ADMIN_UNREDACTED_USERNAME", "The username that may additionally request unredacted job records from the admin API. Unredacted job records are never returned if unset.")
+	c.UnredactedPassword = c.GetOptional("EXECUTOR_QUEUE_ADMIN_UNREDACTED_PASSWORD", "The password that may additionally request unredacted job records from the admin API. Unredacted job records are never returned if unset.")
+	c.QueuedCountRefreshInterval = c.GetInterval("EXECUTOR_QUEUE_QUEUED_COUNT_REFRESH_INTERVAL", "30s", "Interval between refreshes of the queued job counts reported to Prometheus.")
+	c.ShutdownTimeout = c.GetInterval("EXECUTOR_QUEUE_SHUTDOWN_TIMEOUT", "30s", "The maximum duration to wait for in-flight requests to complete on shutdown.")
+	c.ReplicaID = c.Get("EXECUTOR_QUEUE_REPLICA_ID", hostname.Get(), "A unique identifier of this replica. Defaults to the hostname.")
+	c.LeaderElectionInterval = c.GetInterval("EXECUTOR_QUEUE_LEADER_ELECTION_INTERVAL", "10s", "Interval between leader election attempts and leadership checks.")
+	c.ExecutorActiveThreshold = c.GetInterval("EXECUTOR_QUEUE_EXECUTOR_ACTIVE_THRESHOLD", "1m", "Executors that have sent a heartbeat within this duration are reported as active.")
+	c.ExecutorRetention = c.GetInterval("EXECUTOR_QUEUE_EXECUTOR_RETENTION", "24h", "Executors that have not sent a heartbeat within this duration are removed from the executor registry.")
+	c.ExecutorDeadThreshold = c.GetInterval("EXECUTOR_QUEUE_EXECUTOR_DEAD_THRESHOLD", "30s", "Executors that have not sent a heartbeat within this duration are declared dead and their jobs are requeued immediately. Set to zero to wait for jobs to stall instead.")
+	c.DeadExecutorCheckInterval = c.GetInterval("EXECUTOR_QUEUE_DEAD_EXECUTOR_CHECK_INTERVAL", "5s", "Interval between checks for dead executors.")
+	c.AuditLogRetention = c.GetInterval("EXECUTOR_QUEUE_AUDIT_LOG_RETENTION", "2160h", "Audit log entries older than this duration are removed. Set to zero to retain entries indefinitely.")
+	c.SchedulerInterval = c.GetInterval("EXECUTOR_QUEUE_SCHEDULER_INTERVAL", "10s", "Interval between checks for scheduled jobs that are due to be enqueued.")
+	c.ReadReplicaDSN = c.GetOptional("EXECUTOR_QUEUE_READ_REPLICA_DSN", "The DSN of a read replica of the frontend database from which queued counts and job listings are read. All queries are sent to the primary if unset.")
+	c.HealthCheckInterval = c.GetInterval("EXECUTOR_QUEUE_HEALTH_CHECK_INTERVAL", "10s", "Interval between checks of the database connection and schema version reported by the readiness endpoints.")
+	c.ConcurrencyLimitRefreshInterval = c.GetInterval("EXECUTOR_QUEUE_CONCURRENCY_LIMIT_REFRESH_INTERVAL", "10s", "Interval between reloads of the queue concurrency limits set through the admin API.")
+	c.EventPollInterval = c.GetInterval("EXECUTOR_QUEUE_EVENT_POLL_INTERVAL", "1s", "Interval at which the admin event stream reads new job state transitions.")
+	c.MaxRequestBodySize = c.GetInt("EXECUTOR_QUEUE_MAX_REQUEST_BODY_SIZE", "1048576", "The maximum size, in bytes, of the body of a request to the executor API. Endpoints carrying command output or checkpoints have larger limits.")
+	c.AllowUnknownRequestFields = c.GetBool("EXECUTOR_QUEUE_ALLOW_UNKNOWN_REQUEST_FIELDS", "false", "Accept executor API requests with fields unknown to this version of the executor-queue.")
+
+	maxRequestBodySizes, err := parseRequestBodySizes(c.GetOptional("EXECUTOR_QUEUE_MAX_REQUEST_BODY_SIZES", "A comma-separated list of path=bytes pairs overriding the maximum request body size of individual executor API endpoints (e.g., addExecutionLogEntry=134217728)."))
+	if err != nil {
+		c.AddError(errors.Wrap(err, "invalid EXECUTOR_QUEUE_MAX_REQUEST_BODY_SIZES"))
+	}
+	c.MaxRequestBodySizes = maxRequestBodySizes
+}
+
+func (c *Config) Validate() error {
+	if (c.AdminUsername == "") != (c.AdminPassword == "") {
+		c.AddError(errors.New("EXECUTOR_QUEUE_ADMIN_USERNAME and EXECUTOR_QUEUE_ADMIN_PASSWORD must be supplied together"))
+	}
+	if (c.UnredactedUsername == "") != (c.UnredactedPassword == "") {
+		c.AddError(errors.New("EXECUTOR_QUEUE_ADMIN_UNREDACTED_USERNAME and EXECUTOR_QUEUE_ADMIN_UNREDACTED_PASSWORD must be supplied together"))
+	}
+	if c.MaxRequestBodySize <= 0 {
+		c.AddError(errors.New("EXECUTOR_QUEUE_MAX_REQUEST_BODY_SIZE must be positive"))
+	}
+	if c.UnredactedUsername != "" && c.UnredactedUsername == c.AdminUsername {
+		c.AddError(errors.New("EXECUTOR_QUEUE_ADMIN_UNREDACTED_USERNAME must differ from EXECUTOR_QUEUE_ADMIN_USERNAME"))
+	}
+
+	return c.BaseConfig.Validate()
 }
 
 func (c *Config) ServerOptions() apiserver.ServerOptions {
 	return apiserver.ServerOptions{
-		Port: c.Port,
+		Port:               c.Port,
+		AdminUsername:      c.AdminUsername,
+		AdminPassword:      c.AdminPassword,
+		UnredactedUsername: c.UnredactedUsername,
+		UnredactedPassword: c.UnredactedPassword,
+		ShutdownTimeout:    c.ShutdownTimeout,
+		EventPollInterval:  c.EventPollInterval,
+
+		MaxRequestBodySize:        int64(c.MaxRequestBodySize),
+		MaxRequestBodySizes:       c.MaxRequestBodySizes,
+		AllowUnknownRequestFields: c.AllowUnknownRequestFields,
 	}
 }
+
+// parseRequestBodySizes parses a comma-separated list of path=bytes pairs.
+func parseRequestBodySizes(value string) (map[string]int64, error) {
+	sizes := map[string]int64{}
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("malformed pair %q", pair)
+		}
+
+		size, err := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+		if err != nil || size <= 0 {
+			return nil, errors.Errorf("malformed size for %q", parts[0])
+		}
+
+		sizes[strings.TrimSpace(parts[0])] = size
+	}
+
+	return sizes, nil
+}
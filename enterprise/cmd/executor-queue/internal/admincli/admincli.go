@@ -0,0 +1,218 @@
+// Package admincli implements the admin subcommands of the executor-queue binary, which wrap
+// the admin API so that operators can inspect and manipulate queues from within a running
+// container (e.g. via kubectl exec) without writing SQL.
+package admincli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// maxPageSize is the maximum number of jobs returned by the admin API in a single page.
+const maxPageSize = 100
+
+const usage = `Usage: executor-queue admin [flags] <command> [arguments]
+
+Commands:
+  queues                          list queues with their depth and the age of the oldest queued job
+  requeue-failed [flags] <queue>  move failed jobs of a queue back into the queued state
+  drain [flags] <queue>           mark all queued jobs of a queue as failed
+
+Flags:
+`
+
+// Run executes the admin subcommand described by the given arguments, which exclude the
+// leading "admin" argument, and writes its output to the given writer.
+func Run(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("admin", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.Usage = func() {
+		fmt.Fprint(out, usage)
+		flags.PrintDefaults()
+	}
+
+	var (
+		apiURL   = flags.String("url", "http://127.0.0.1:"+getenv("EXECUTOR_QUEUE_API_PORT", "3191"), "The URL of the executor-queue.")
+		username = flags.String("username", os.Getenv("EXECUTOR_QUEUE_ADMIN_USERNAME"), "The admin API username. Defaults to EXECUTOR_QUEUE_ADMIN_USERNAME.")
+		password = flags.String("password", os.Getenv("EXECUTOR_QUEUE_ADMIN_PASSWORD"), "The admin API password. Defaults to EXECUTOR_QUEUE_ADMIN_PASSWORD.")
+	)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no command supplied")
+	}
+
+	c := newClient(*apiURL, *username, *password)
+
+	command, args := flags.Arg(0), flags.Args()[1:]
+	switch command {
+	case "queues":
+		return listQueues(ctx, c, out)
+	case "requeue-failed":
+		return requeueFailed(ctx, c, args, out)
+	case "drain":
+		return drain(ctx, c, args, out)
+	}
+
+	flags.Usage()
+	return errors.Errorf("unknown command %q", command)
+}
+
+// queueStats mirrors the response of the admin queue listing endpoint.
+type queueStats struct {
+	Name                   string   `json:"name"`
+	Paused                 bool     `json:"paused"`
+	Queued                 int      `json:"queued"`
+	OldestQueuedAgeSeconds *float64 `json:"oldestQueuedAgeSeconds"`
+}
+
+func listQueues(ctx context.Context, c *client, out io.Writer) error {
+	var stats []queueStats
+	if err := c.do(ctx, "GET", "queues", nil, &stats); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "QUEUE\tPAUSED\tQUEUED\tOLDEST")
+	for _, s := range stats {
+		oldest := "-"
+		if s.OldestQueuedAgeSeconds != nil {
+			oldest = (time.Duration(*s.OldestQueuedAgeSeconds) * time.Second).String()
+		}
+		fmt.Fprintf(w, "%s\t%t\t%d\t%s\n", s.Name, s.Paused, s.Queued, oldest)
+	}
+
+	return w.Flush()
+}
+
+func requeueFailed(ctx context.Context, c *client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("requeue-failed", flag.ContinueOnError)
+	flags.SetOutput(out)
+	limit := flags.Int("limit", maxPageSize, "The maximum number of jobs to requeue.")
+	dryRun := flags.Bool("dry-run", false, "List the jobs that would be requeued without requeueing them.")
+	queueName, err := parseQueueArg(flags, args)
+	if err != nil {
+		return err
+	}
+
+	// Collect the jobs before requeueing any of them, as listings may be served by a read
+	// replica that does not yet reflect the requeues.
+	var ids []int
+	for len(ids) < *limit {
+		pageSize := *limit - len(ids)
+		if pageSize > maxPageSize {
+			pageSize = maxPageSize
+		}
+
+		page, err := listJobIDs(ctx, c, queueName, "failed", pageSize, len(ids))
+		if err != nil {
+			return err
+		}
+		ids = append(ids, page...)
+
+		if len(page) < pageSize {
+			break
+		}
+	}
+
+	if !*dryRun {
+		for i, id := range ids {
+			if err := c.do(ctx, "POST", fmt.Sprintf("%s/jobs/%d/requeue", queueName, id), nil, nil); err != nil {
+				return errors.Wrapf(err, "requeueing job %d (%d jobs requeued)", id, i)
+			}
+		}
+	}
+
+	if *dryRun {
+		fmt.Fprintf(out, "would requeue %d failed jobs in queue %q: %v\n", len(ids), queueName, ids)
+	} else {
+		fmt.Fprintf(out, "requeued %d failed jobs in queue %q: %v\n", len(ids), queueName, ids)
+	}
+	return nil
+}
+
+func drain(ctx context.Context, c *client, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("drain", flag.ContinueOnError)
+	flags.SetOutput(out)
+	confirm := flags.Bool("yes", false, "Confirm that the queued jobs should be marked as failed.")
+	queueName, err := parseQueueArg(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if !*confirm {
+		var stats []queueStats
+		if err := c.do(ctx, "GET", "queues", nil, &stats); err != nil {
+			return err
+		}
+		for _, s := range stats {
+			if s.Name == queueName {
+				fmt.Fprintf(out, "queue %q has %d queued jobs\n", queueName, s.Queued)
+			}
+		}
+		return errors.New("refusing to drain without -yes: queued jobs will be marked as failed")
+	}
+
+	var payload struct {
+		JobIDs []int `json:"jobIds"`
+	}
+	if err := c.do(ctx, "POST", queueName+"/drain", nil, &payload); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "marked %d queued jobs in queue %q as failed: %v\n", len(payload.JobIDs), queueName, payload.JobIDs)
+	return nil
+}
+
+// listJobIDs returns the identifiers of a page of jobs in the given state.
+func listJobIDs(ctx context.Context, c *client, queueName, state string, limit, offset int) ([]int, error) {
+	var records []struct {
+		ID int `json:"id"`
+	}
+	query := url.Values{
+		"state":  []string{state},
+		"limit":  []string{strconv.Itoa(limit)},
+		"offset": []string{strconv.Itoa(offset)},
+	}
+	if err := c.do(ctx, "GET", queueName+"/jobs", query, &records); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	return ids, nil
+}
+
+// parseQueueArg parses the flags of a subcommand that operates on a single queue and returns
+// the name of that queue.
+func parseQueueArg(flags *flag.FlagSet, args []string) (string, error) {
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return "", errors.Errorf("%s expects exactly one queue name", flags.Name())
+	}
+
+	return flags.Arg(0), nil
+}
+
+func getenv(name, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
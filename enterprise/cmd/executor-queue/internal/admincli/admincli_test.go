@@ -0,0 +1,159 @@
+package admincli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeAdminAPI serves a single queue with the given failed and queued jobs.
+type fakeAdminAPI struct {
+	mu        sync.Mutex
+	failed    []int
+	queued    []int
+	requeued  []int
+	usernames []string
+}
+
+func (f *fakeAdminAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	username, _, _ := r.BasicAuth()
+	f.usernames = append(f.usernames, username)
+
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/admin/queues":
+		age := 90.0
+		_ = json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"name": "batches", "paused": true, "queued": len(f.queued), "oldestQueuedAgeSeconds": age},
+			{"name": "codeintel", "paused": false, "queued": 0},
+		})
+
+	case r.Method == "GET" && r.URL.Path == "/admin/batches/jobs":
+		if r.URL.Query().Get("state") != "failed" {
+			http.Error(w, "unexpected query", http.StatusBadRequest)
+			return
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		records := []map[string]int{}
+		for i, id := range f.failed {
+			if i < offset {
+				continue
+			}
+			if len(records) == limit {
+				break
+			}
+			records = append(records, map[string]int{"ID": id})
+		}
+		_ = json.NewEncoder(w).Encode(records)
+
+	case r.Method == "POST" && strings.HasPrefix(r.URL.Path, "/admin/batches/jobs/"):
+		var id int
+		fmt.Sscanf(r.URL.Path, "/admin/batches/jobs/%d/requeue", &id)
+		for i, failedID := range f.failed {
+			if failedID == id {
+				f.failed = append(f.failed[:i], f.failed[i+1:]...)
+				break
+			}
+		}
+		f.requeued = append(f.requeued, id)
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "POST" && r.URL.Path == "/admin/batches/drain":
+		_ = json.NewEncoder(w).Encode(map[string][]int{"jobIds": f.queued})
+		f.queued = nil
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func runAdmin(t *testing.T, api http.Handler, args ...string) (string, error) {
+	ts := httptest.NewServer(api)
+	t.Cleanup(ts.Close)
+
+	var out bytes.Buffer
+	err := Run(context.Background(), append([]string{"-url", ts.URL, "-username", "admin", "-password", "hunter2"}, args...), &out)
+	return out.String(), err
+}
+
+func TestQueues(t *testing.T) {
+	out, err := runAdmin(t, &fakeAdminAPI{queued: []int{1, 2}}, "queues")
+	if err != nil {
+		t.Fatalf("unexpected error listing queues: %s", err)
+	}
+
+	expected := "" +
+		"QUEUE      PAUSED  QUEUED  OLDEST\n" +
+		"batches    true    2       1m30s\n" +
+		"codeintel  false   0       -\n"
+	if diff := cmp.Diff(expected, out); diff != "" {
+		t.Errorf("unexpected output (-want +got):\n%s", diff)
+	}
+}
+
+func TestRequeueFailed(t *testing.T) {
+	api := &fakeAdminAPI{failed: []int{4, 5, 6}}
+	if _, err := runAdmin(t, api, "requeue-failed", "-limit", "2", "batches"); err != nil {
+		t.Fatalf("unexpected error requeueing jobs: %s", err)
+	}
+
+	if diff := cmp.Diff([]int{4, 5}, api.requeued); diff != "" {
+		t.Errorf("unexpected requeued jobs (-want +got):\n%s", diff)
+	}
+	for _, username := range api.usernames {
+		if username != "admin" {
+			t.Errorf("unexpected username. want=%q have=%q", "admin", username)
+		}
+	}
+}
+
+func TestRequeueFailedDryRun(t *testing.T) {
+	api := &fakeAdminAPI{failed: []int{4, 5}}
+	out, err := runAdmin(t, api, "requeue-failed", "-dry-run", "batches")
+	if err != nil {
+		t.Fatalf("unexpected error requeueing jobs: %s", err)
+	}
+
+	if len(api.requeued) != 0 {
+		t.Errorf("unexpected requeued jobs: %v", api.requeued)
+	}
+	if !strings.Contains(out, "would requeue 2 failed jobs") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	api := &fakeAdminAPI{queued: []int{1, 2}}
+	if _, err := runAdmin(t, api, "drain", "batches"); err == nil {
+		t.Fatalf("expected an error draining without confirmation")
+	}
+	if len(api.queued) != 2 {
+		t.Fatalf("unexpected drain without confirmation")
+	}
+
+	out, err := runAdmin(t, api, "drain", "-yes", "batches")
+	if err != nil {
+		t.Fatalf("unexpected error draining queue: %s", err)
+	}
+	if !strings.Contains(out, "marked 2 queued jobs") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestUnknownQueue(t *testing.T) {
+	if _, err := runAdmin(t, &fakeAdminAPI{}, "drain", "-yes", "unknown"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("unexpected error. want=%q have=%v", "404", err)
+	}
+}
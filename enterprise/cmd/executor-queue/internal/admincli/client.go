@@ -0,0 +1,88 @@
+package admincli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// requestTimeout is the maximum duration of a single admin API request.
+const requestTimeout = time.Minute
+
+// client makes basic-auth authenticated requests to the admin API of an executor-queue.
+type client struct {
+	baseURL  string
+	username string
+	password string
+	http     *http.Client
+}
+
+func newClient(baseURL, username, password string) *client {
+	return &client{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		username: username,
+		password: password,
+		http:     &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// do sends a request to the given admin API path and decodes a successful JSON response into
+// the given value, which may be nil for responses without a body.
+func (c *client) do(ctx context.Context, method, path string, query url.Values, out interface{}) error {
+	u := c.baseURL + "/admin/" + strings.TrimPrefix(path, "/")
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return statusError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// statusError converts an unsuccessful response into an error, including the error message
+// reported by the admin API if there is one.
+func statusError(resp *http.Response) error {
+	content, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	var payload struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(content, &payload); err == nil && payload.Error != "" {
+		return errors.Errorf("%s: %s", resp.Status, payload.Error)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return errors.Errorf("%s: check EXECUTOR_QUEUE_ADMIN_USERNAME and EXECUTOR_QUEUE_ADMIN_PASSWORD", resp.Status)
+	case http.StatusNotFound:
+		return errors.Errorf("%s: unknown queue or job, or the admin API is disabled", resp.Status)
+	}
+
+	if message := strings.TrimSpace(string(content)); message != "" {
+		return errors.Errorf("%s: %s", resp.Status, message)
+	}
+	return errors.New(resp.Status)
+}
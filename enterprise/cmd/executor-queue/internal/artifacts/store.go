@@ -0,0 +1,82 @@
+package artifacts
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/uploadstore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// Store writes artifacts uploaded by executors to a blob storage backend.
+type Store interface {
+	// Upload writes the content in the given reader to the artifact at the given key and
+	// returns the number of bytes written.
+	Upload(ctx context.Context, key string, r io.Reader) (int64, error)
+}
+
+// NewStore creates a store for the configured backend. If no backend is configured, a nil
+// store is returned and artifact uploads should be disabled.
+func NewStore(ctx context.Context, config *Config, observationContext *observation.Context) (Store, error) {
+	switch config.Backend {
+	case "":
+		return nil, nil
+
+	case "local":
+		return NewDiskStore(config.Dir), nil
+	}
+
+	store, err := uploadstore.CreateLazy(ctx, config.uploadStoreConfig(), observationContext)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating artifact upload store")
+	}
+
+	return store, nil
+}
+
+type diskStore struct {
+	dir string
+}
+
+// NewDiskStore creates a store that writes artifacts to files under the given directory.
+// The artifact key is used as the path of the file relative to the directory.
+func NewDiskStore(dir string) Store {
+	return &diskStore{dir: dir}
+}
+
+// Upload writes the content of the given reader to a temporary file that is moved into place
+// only once it is written completely, so that a partial upload never replaces an artifact.
+func (s *diskStore) Upload(ctx context.Context, key string, r io.Reader) (_ int64, err error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return 0, err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	n, err := io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(f.Name(), path); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,63 @@
+package artifacts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/cockroachdb/errors"
+)
+
+func TestDiskStoreUpload(t *testing.T) {
+	dir := t.TempDir()
+	store := NewDiskStore(dir)
+
+	n, err := store.Upload(context.Background(), "batches/42/output.json", strings.NewReader(`{"ok": true}`))
+	if err != nil {
+		t.Fatalf("unexpected error uploading artifact: %s", err)
+	}
+	if n != 12 {
+		t.Errorf("unexpected size. want=%d have=%d", 12, n)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(dir, "batches", "42", "output.json"))
+	if err != nil {
+		t.Fatalf("unexpected error reading artifact: %s", err)
+	}
+	if string(contents) != `{"ok": true}` {
+		t.Errorf("unexpected contents. want=%q have=%q", `{"ok": true}`, contents)
+	}
+}
+
+func TestDiskStoreUploadError(t *testing.T) {
+	dir := t.TempDir()
+	store := NewDiskStore(dir)
+
+	if _, err := store.Upload(context.Background(), "batches/42/output.json", strings.NewReader("previous")); err != nil {
+		t.Fatalf("unexpected error uploading artifact: %s", err)
+	}
+
+	r := iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("partial")))
+	if _, err := store.Upload(context.Background(), "batches/42/output.json", r); !errors.Is(err, iotest.ErrTimeout) {
+		t.Fatalf("unexpected error. want=%q have=%v", iotest.ErrTimeout, err)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(dir, "batches", "42", "output.json"))
+	if err != nil {
+		t.Fatalf("unexpected error reading artifact: %s", err)
+	}
+	if string(contents) != "previous" {
+		t.Errorf("unexpected contents. want=%q have=%q", "previous", contents)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "batches", "42"))
+	if err != nil {
+		t.Fatalf("unexpected error reading directory: %s", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected temporary file to be removed, found %d entries", len(entries))
+	}
+}
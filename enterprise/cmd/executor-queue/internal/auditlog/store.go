@@ -0,0 +1,192 @@
+package auditlog
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// Operations recorded in the audit log.
+const (
+	OperationDequeue      = "dequeue"
+	OperationMarkComplete = "markComplete"
+	OperationMarkErrored  = "markErrored"
+	OperationMarkFailed   = "markFailed"
+	OperationRequeue      = "requeue"
+	OperationDelete       = "delete"
+)
+
+// Entry is a single record of a state transition of a job in an executor queue.
+type Entry struct {
+	ID        int64     `json:"id"`
+	QueueName string    `json:"queueName"`
+	JobID     int       `json:"jobId"`
+	Operation string    `json:"operation"`
+	Actor     string    `json:"actor"`
+	Message   string    `json:"message,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Store appends to and reads from the executor_queue_audit_log table. Entries are never
+// modified; they are only removed once they exceed the configured retention period.
+type Store struct {
+	*basestore.Store
+}
+
+// NewStore creates a new audit log store backed by the given database.
+func NewStore(db dbutil.DB) *Store {
+	return &Store{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// Append adds the given entry to the audit log. The identifier and creation time of the
+// entry are assigned by the database.
+func (s *Store) Append(ctx context.Context, entry Entry) error {
+	return s.Exec(ctx, sqlf.Sprintf(appendQuery, entry.QueueName, entry.JobID, entry.Operation, entry.Actor, entry.Message))
+}
+
+const appendQuery = `
+-- source: enterprise/cmd/executor-queue/internal/auditlog/store.go:Append
+INSERT INTO executor_queue_audit_log (queue_name, job_id, operation, actor, message) VALUES (%s, %s, %s, %s, %s)
+`
+
+// ListOptions filters the entries returned from List.
+type ListOptions struct {
+	// QueueName, if set, restricts the listing to entries of the given queue.
+	QueueName string
+
+	// JobID, if non-zero, restricts the listing to entries of the given job.
+	JobID int
+
+	// Since and Until, if non-zero, restrict the listing to entries created within the
+	// half-open interval [Since, Until).
+	Since time.Time
+	Until time.Time
+
+	// AfterID, if non-zero, restricts the listing to entries appended after the entry with
+	// the given identifier.
+	AfterID int64
+
+	Limit  int
+	Offset int
+}
+
+// List returns the entries matching the given options in the order they were appended.
+func (s *Store) List(ctx context.Context, opts ListOptions) ([]Entry, error) {
+	return scanEntries(s.Query(ctx, sqlf.Sprintf(
+		listQuery,
+		sqlf.Join(listConditions(opts), " AND "),
+		opts.Limit,
+		opts.Offset,
+	)))
+}
+
+const listQuery = `
+-- source: enterprise/cmd/executor-queue/internal/auditlog/store.go:List
+SELECT id, queue_name, job_id, operation, actor, message, created_at
+FROM executor_queue_audit_log
+WHERE %s
+ORDER BY id
+LIMIT %s OFFSET %s
+`
+
+func listConditions(opts ListOptions) []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if opts.QueueName != "" {
+		conds = append(conds, sqlf.Sprintf("queue_name = %s", opts.QueueName))
+	}
+	if opts.JobID != 0 {
+		conds = append(conds, sqlf.Sprintf("job_id = %s", opts.JobID))
+	}
+	if !opts.Since.IsZero() {
+		conds = append(conds, sqlf.Sprintf("created_at >= %s", opts.Since))
+	}
+	if !opts.Until.IsZero() {
+		conds = append(conds, sqlf.Sprintf("created_at < %s", opts.Until))
+	}
+	if opts.AfterID != 0 {
+		conds = append(conds, sqlf.Sprintf("id > %s", opts.AfterID))
+	}
+
+	return conds
+}
+
+// CountOperations returns the number of entries of each operation per queue that were created
+// at or after the given time.
+func (s *Store) CountOperations(ctx context.Context, since time.Time) (_ map[string]map[string]int, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(countOperationsQuery, since))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	counts := map[string]map[string]int{}
+	for rows.Next() {
+		var queueName, operation string
+		var count int
+		if err := rows.Scan(&queueName, &operation, &count); err != nil {
+			return nil, err
+		}
+
+		if _, ok := counts[queueName]; !ok {
+			counts[queueName] = map[string]int{}
+		}
+		counts[queueName][operation] = count
+	}
+
+	return counts, nil
+}
+
+const countOperationsQuery = `
+-- source: enterprise/cmd/executor-queue/internal/auditlog/store.go:CountOperations
+SELECT queue_name, operation, COUNT(*)
+FROM executor_queue_audit_log
+WHERE created_at >= %s
+GROUP BY queue_name, operation
+`
+
+// DeleteBefore removes entries created before the given time and returns the number of
+// deleted entries.
+func (s *Store) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(deleteBeforeQuery, before)))
+	return count, err
+}
+
+const deleteBeforeQuery = `
+-- source: enterprise/cmd/executor-queue/internal/auditlog/store.go:DeleteBefore
+WITH deleted AS (
+	DELETE FROM executor_queue_audit_log WHERE created_at < %s RETURNING id
+)
+SELECT COUNT(*) FROM deleted
+`
+
+func scanEntries(rows *sql.Rows, queryErr error) (_ []Entry, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var entries []Entry
+	for rows.Next() {
+		var entry Entry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.QueueName,
+			&entry.JobID,
+			&entry.Operation,
+			&entry.Actor,
+			&entry.Message,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
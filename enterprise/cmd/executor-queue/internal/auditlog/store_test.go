@@ -0,0 +1,69 @@
+package auditlog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := NewStore(db)
+	ctx := context.Background()
+
+	for _, entry := range []Entry{
+		{QueueName: "codeintel", JobID: 1, Operation: OperationDequeue, Actor: "e1"},
+		{QueueName: "codeintel", JobID: 1, Operation: OperationMarkErrored, Actor: "e1", Message: "oops"},
+		{QueueName: "batches", JobID: 1, Operation: OperationDequeue, Actor: "e2"},
+	} {
+		if err := store.Append(ctx, entry); err != nil {
+			t.Fatalf("unexpected error appending entry: %s", err)
+		}
+	}
+
+	entries, err := store.List(ctx, ListOptions{QueueName: "codeintel", JobID: 1, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error listing entries: %s", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("unexpected number of entries. want=%d have=%d", 2, len(entries))
+	}
+	if entries[0].Operation != OperationDequeue || entries[1].Message != "oops" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	entries, err = store.List(ctx, ListOptions{AfterID: entries[0].ID, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error listing entries: %s", err)
+	}
+	if len(entries) != 2 || entries[0].Operation != OperationMarkErrored || entries[1].QueueName != "batches" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	counts, err := store.CountOperations(ctx, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error counting operations: %s", err)
+	}
+	expectedCounts := map[string]map[string]int{
+		"codeintel": {OperationDequeue: 1, OperationMarkErrored: 1},
+		"batches":   {OperationDequeue: 1},
+	}
+	if diff := cmp.Diff(expectedCounts, counts); diff != "" {
+		t.Errorf("unexpected counts (-want +got):\n%s", diff)
+	}
+
+	count, err := store.DeleteBefore(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error deleting entries: %s", err)
+	}
+	if count != 3 {
+		t.Errorf("unexpected number of deleted entries. want=%d have=%d", 3, count)
+	}
+}
@@ -0,0 +1,71 @@
+package checkpoints
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// Store persists the progress checkpoints reported by executors in the executor_job_checkpoints
+// table. Checkpoints are opaque to the queue: they are written by the job, stored as given, and
+// handed back to whichever executor next dequeues the job.
+type Store struct {
+	*basestore.Store
+}
+
+// NewStore creates a new checkpoint store backed by the given database.
+func NewStore(db dbutil.DB) *Store {
+	return &Store{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// Upsert replaces the checkpoint of the given job.
+func (s *Store) Upsert(ctx context.Context, queueName string, jobID int, data []byte) error {
+	return s.Exec(ctx, sqlf.Sprintf(upsertQuery, queueName, jobID, data))
+}
+
+const upsertQuery = `
+-- source: enterprise/cmd/executor-queue/internal/checkpoints/store.go:Upsert
+INSERT INTO executor_job_checkpoints (queue_name, job_id, data)
+VALUES (%s, %s, %s)
+ON CONFLICT (queue_name, job_id) DO UPDATE
+SET
+	data = EXCLUDED.data,
+	updated_at = NOW()
+`
+
+// Get returns the checkpoint of the given job, if one has been recorded.
+func (s *Store) Get(ctx context.Context, queueName string, jobID int) (data []byte, _ bool, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(getQuery, queueName, jobID))
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	if !rows.Next() {
+		return nil, false, nil
+	}
+	if err := rows.Scan(&data); err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+const getQuery = `
+-- source: enterprise/cmd/executor-queue/internal/checkpoints/store.go:Get
+SELECT data FROM executor_job_checkpoints WHERE queue_name = %s AND job_id = %s
+`
+
+// Delete removes the checkpoint of the given job.
+func (s *Store) Delete(ctx context.Context, queueName string, jobID int) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteQuery, queueName, jobID))
+}
+
+const deleteQuery = `
+-- source: enterprise/cmd/executor-queue/internal/checkpoints/store.go:Delete
+DELETE FROM executor_job_checkpoints WHERE queue_name = %s AND job_id = %s
+`
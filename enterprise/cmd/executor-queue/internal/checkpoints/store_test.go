@@ -0,0 +1,54 @@
+package checkpoints
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := NewStore(db)
+	ctx := context.Background()
+
+	if _, ok, err := store.Get(ctx, "batches", 1); err != nil {
+		t.Fatalf("unexpected error getting checkpoint: %s", err)
+	} else if ok {
+		t.Fatalf("expected no checkpoint")
+	}
+
+	for _, data := range []string{"step 1", "step 2"} {
+		if err := store.Upsert(ctx, "batches", 1, []byte(data)); err != nil {
+			t.Fatalf("unexpected error upserting checkpoint: %s", err)
+		}
+	}
+	if err := store.Upsert(ctx, "codeintel", 1, []byte("other")); err != nil {
+		t.Fatalf("unexpected error upserting checkpoint: %s", err)
+	}
+
+	data, ok, err := store.Get(ctx, "batches", 1)
+	if err != nil {
+		t.Fatalf("unexpected error getting checkpoint: %s", err)
+	}
+	if !ok || string(data) != "step 2" {
+		t.Errorf("unexpected checkpoint. want=%q have=%q", "step 2", data)
+	}
+
+	if err := store.Delete(ctx, "batches", 1); err != nil {
+		t.Fatalf("unexpected error deleting checkpoint: %s", err)
+	}
+	if _, ok, err := store.Get(ctx, "batches", 1); err != nil {
+		t.Fatalf("unexpected error getting checkpoint: %s", err)
+	} else if ok {
+		t.Errorf("expected checkpoint to be deleted")
+	}
+	if _, ok, err := store.Get(ctx, "codeintel", 1); err != nil {
+		t.Fatalf("unexpected error getting checkpoint: %s", err)
+	} else if !ok {
+		t.Errorf("expected checkpoint of other queue to remain")
+	}
+}
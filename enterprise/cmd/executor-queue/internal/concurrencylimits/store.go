@@ -0,0 +1,80 @@
+package concurrencylimits
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// Store persists the concurrency limits set through the admin API in the
+// executor_queue_concurrency_limits table. These take precedence over the limits configured
+// via the environment so that an operator can adjust a queue without redeploying.
+type Store struct {
+	*basestore.Store
+}
+
+// NewStore creates a new concurrency limit store backed by the given database.
+func NewStore(db dbutil.DB) *Store {
+	return &Store{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// List returns the maximum number of processing jobs of each queue with a stored limit.
+func (s *Store) List(ctx context.Context) (_ map[string]int, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(listQuery))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	limits := map[string]int{}
+	for rows.Next() {
+		var queueName string
+		var maxProcessing int
+		if err := rows.Scan(&queueName, &maxProcessing); err != nil {
+			return nil, err
+		}
+
+		limits[queueName] = maxProcessing
+	}
+
+	return limits, nil
+}
+
+const listQuery = `
+-- source: enterprise/cmd/executor-queue/internal/concurrencylimits/store.go:List
+SELECT queue_name, max_processing FROM executor_queue_concurrency_limits
+`
+
+// Set replaces the limit of the given queue. A limit of zero lifts any limit on the queue.
+func (s *Store) Set(ctx context.Context, queueName string, maxProcessing int) error {
+	return s.Exec(ctx, sqlf.Sprintf(setQuery, queueName, maxProcessing))
+}
+
+const setQuery = `
+-- source: enterprise/cmd/executor-queue/internal/concurrencylimits/store.go:Set
+INSERT INTO executor_queue_concurrency_limits (queue_name, max_processing)
+VALUES (%s, %s)
+ON CONFLICT (queue_name) DO UPDATE
+SET
+	max_processing = EXCLUDED.max_processing,
+	updated_at = NOW()
+`
+
+// Delete removes the stored limit of the given queue, reverting it to its configured limit.
+// This method returns a boolean flag indicating if a limit was stored.
+func (s *Store) Delete(ctx context.Context, queueName string) (bool, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(deleteQuery, queueName)))
+	return count > 0, err
+}
+
+const deleteQuery = `
+-- source: enterprise/cmd/executor-queue/internal/concurrencylimits/store.go:Delete
+WITH deleted AS (
+	DELETE FROM executor_queue_concurrency_limits WHERE queue_name = %s RETURNING queue_name
+)
+SELECT COUNT(*) FROM deleted
+`
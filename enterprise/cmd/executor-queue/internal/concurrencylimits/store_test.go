@@ -0,0 +1,55 @@
+package concurrencylimits
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := NewStore(db)
+	ctx := context.Background()
+
+	for queueName, maxProcessing := range map[string]int{"codeintel": 5, "batches": 0} {
+		if err := store.Set(ctx, queueName, maxProcessing); err != nil {
+			t.Fatalf("unexpected error setting limit: %s", err)
+		}
+	}
+	if err := store.Set(ctx, "codeintel", 10); err != nil {
+		t.Fatalf("unexpected error setting limit: %s", err)
+	}
+
+	limits, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing limits: %s", err)
+	}
+	if diff := cmp.Diff(map[string]int{"codeintel": 10, "batches": 0}, limits); diff != "" {
+		t.Errorf("unexpected limits (-want +got):\n%s", diff)
+	}
+
+	if ok, err := store.Delete(ctx, "codeintel"); err != nil {
+		t.Fatalf("unexpected error deleting limit: %s", err)
+	} else if !ok {
+		t.Errorf("expected limit to exist")
+	}
+	if ok, err := store.Delete(ctx, "codeintel"); err != nil {
+		t.Fatalf("unexpected error deleting limit: %s", err)
+	} else if ok {
+		t.Errorf("expected limit to be deleted")
+	}
+
+	limits, err = store.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing limits: %s", err)
+	}
+	if diff := cmp.Diff(map[string]int{"batches": 0}, limits); diff != "" {
+		t.Errorf("unexpected limits (-want +got):\n%s", diff)
+	}
+}
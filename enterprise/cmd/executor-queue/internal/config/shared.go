@@ -1,6 +1,20 @@
 package config
 
-import "github.com/sourcegraph/sourcegraph/internal/env"
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/cockroachdb/errors"
+
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
 
 // SharedConfig defines common items that are used by multiple queues.
 type SharedConfig struct {
@@ -9,10 +23,315 @@ type SharedConfig struct {
 	FrontendURL      string
 	FrontendUsername string
 	FrontendPassword string
+
+	// JobTTLs maps queue names to the maximum duration a job may remain queued
+	// before being marked as failed. Queues without an entry never expire jobs.
+	JobTTLs map[string]time.Duration
+
+	// MaxProcessing maps queue names to the maximum number of jobs of that queue that may be
+	// processing at once. Queues without an entry are not restricted.
+	MaxProcessing map[string]int
+
+	// QueueBackends maps queue names to the name of the backend storing the jobs of that queue.
+	// Queues without an entry are stored in Postgres.
+	QueueBackends map[string]string
+
+	// PriorityAgingIntervals maps queue names to the time a job must spend queued for its
+	// priority to be raised by one. Queues without an entry do not age their jobs.
+	PriorityAgingIntervals map[string]time.Duration
+
+	JanitorInterval time.Duration
+
+	// TransientMaxRetries, TransientRetryBackoff, and TransientMaxRetryBackoff configure the
+	// exponential backoff applied to jobs that fail with a transient error.
+	TransientMaxRetries      int
+	TransientRetryBackoff    time.Duration
+	TransientMaxRetryBackoff time.Duration
+
+	// InfrastructureMaxRetries is the number of times a job that fails due to an executor
+	// infrastructure error is requeued, without delay, before it is marked as failed.
+	InfrastructureMaxRetries int
+
+	// MinExecutorVersions and MaxExecutorVersions map queue names to the oldest and newest
+	// executor versions allowed to dequeue from that queue.
+	MinExecutorVersions map[string]*semver.Version
+	MaxExecutorVersions map[string]*semver.Version
+
+	// RedactionRules maps queue names to the rules applied to the job records of that queue
+	// returned by the admin API, in addition to the queue's default rules.
+	RedactionRules map[string]apiserver.RedactionRules
+
+	// DequeueLockStrategy and DequeueCandidateBatchSize configure how the queue stores claim
+	// records under concurrent dequeues.
+	DequeueLockStrategy       dbworkerstore.LockStrategy
+	DequeueCandidateBatchSize int
 }
 
 func (c *SharedConfig) Load() {
 	c.FrontendURL = c.Get("EXECUTOR_FRONTEND_URL", "", "The external URL of the sourcegraph instance.")
 	c.FrontendUsername = c.Get("EXECUTOR_FRONTEND_USERNAME", "", "The username supplied to the frontend.")
 	c.FrontendPassword = c.Get("EXECUTOR_FRONTEND_PASSWORD", "", "The password supplied to the frontend.")
+	c.JanitorInterval = c.GetInterval("EXECUTOR_QUEUE_JANITOR_INTERVAL", "1m", "Interval between janitor runs.")
+
+	jobTTLs, err := parseDurationMap(c.GetOptional("EXECUTOR_QUEUE_JOB_TTLS", "A comma-separated list of queue=duration pairs (e.g. codeintel=72h,batches=24h) controlling how long jobs may remain queued before they expire."))
+	if err != nil {
+		c.AddError(errors.Wrap(err, "invalid value for EXECUTOR_QUEUE_JOB_TTLS"))
+	}
+	c.JobTTLs = jobTTLs
+
+	maxProcessing, err := parseIntMap(c.GetOptional("EXECUTOR_QUEUE_MAX_PROCESSING", "A comma-separated list of queue=count pairs (e.g. codeintel=50) limiting how many jobs of each queue may be processing at once across all executors."))
+	if err != nil {
+		c.AddError(errors.Wrap(err, "invalid value for EXECUTOR_QUEUE_MAX_PROCESSING"))
+	}
+	c.MaxProcessing = maxProcessing
+
+	queueBackends, err := parseStringMap(c.GetOptional("EXECUTOR_QUEUE_BACKENDS", "A comma-separated list of queue=backend pairs (e.g. codeintel=postgres) selecting the registered backend that stores the jobs of each queue."))
+	if err != nil {
+		c.AddError(errors.Wrap(err, "invalid value for EXECUTOR_QUEUE_BACKENDS"))
+	}
+	c.QueueBackends = queueBackends
+
+	priorityAgingIntervals, err := parseDurationMap(c.GetOptional("EXECUTOR_QUEUE_PRIORITY_AGING_INTERVALS", "A comma-separated list of queue=duration pairs (e.g. insights=1m) controlling how long a job must wait in a queue that dequeues by priority for its priority to be raised by one."))
+	if err != nil {
+		c.AddError(errors.Wrap(err, "invalid value for EXECUTOR_QUEUE_PRIORITY_AGING_INTERVALS"))
+	}
+	c.PriorityAgingIntervals = priorityAgingIntervals
+
+	c.TransientMaxRetries = c.GetInt("EXECUTOR_QUEUE_TRANSIENT_MAX_RETRIES", "3", "The number of times a job that fails with a transient error is retried.")
+	c.TransientRetryBackoff = c.GetInterval("EXECUTOR_QUEUE_TRANSIENT_RETRY_BACKOFF", "30s", "The delay before a job that failed with a transient error is first retried. The delay doubles with each retry.")
+	c.TransientMaxRetryBackoff = c.GetInterval("EXECUTOR_QUEUE_TRANSIENT_MAX_RETRY_BACKOFF", "10m", "The maximum delay before a job that failed with a transient error is retried.")
+	c.InfrastructureMaxRetries = c.GetInt("EXECUTOR_QUEUE_INFRASTRUCTURE_MAX_RETRIES", "3", "The number of times a job that fails due to an executor infrastructure error is retried.")
+
+	minExecutorVersions, err := parseVersionMap(c.GetOptional("EXECUTOR_QUEUE_MIN_EXECUTOR_VERSIONS", "A comma-separated list of queue=version pairs (e.g. codeintel=3.31.0) controlling the oldest executor version allowed to dequeue from each queue."))
+	if err != nil {
+		c.AddError(errors.Wrap(err, "invalid value for EXECUTOR_QUEUE_MIN_EXECUTOR_VERSIONS"))
+	}
+	c.MinExecutorVersions = minExecutorVersions
+
+	maxExecutorVersions, err := parseVersionMap(c.GetOptional("EXECUTOR_QUEUE_MAX_EXECUTOR_VERSIONS", "A comma-separated list of queue=version pairs (e.g. batches=3.33.0) controlling the newest executor version allowed to dequeue from each queue."))
+	if err != nil {
+		c.AddError(errors.Wrap(err, "invalid value for EXECUTOR_QUEUE_MAX_EXECUTOR_VERSIONS"))
+	}
+	c.MaxExecutorVersions = maxExecutorVersions
+
+	redactionRules, err := parseRedactionRules(c.GetOptional("EXECUTOR_QUEUE_REDACTION_RULES", `A JSON object mapping queue names to the fields and patterns redacted from job records returned by the admin API, e.g. {"batches": {"fieldPaths": ["BatchSpec"], "patterns": ["token=(\\S+)"]}}.`))
+	if err != nil {
+		c.AddError(errors.Wrap(err, "invalid value for EXECUTOR_QUEUE_REDACTION_RULES"))
+	}
+	c.RedactionRules = redactionRules
+
+	c.DequeueLockStrategy = dbworkerstore.LockStrategy(c.Get("EXECUTOR_QUEUE_DEQUEUE_LOCK_STRATEGY", string(dbworkerstore.LockStrategySkipLocked), "The strategy used to claim jobs under concurrent dequeues: skip-locked (FOR UPDATE SKIP LOCKED) or advisory (transaction-scoped advisory locks)."))
+	c.DequeueCandidateBatchSize = c.GetInt("EXECUTOR_QUEUE_DEQUEUE_CANDIDATE_BATCH_SIZE", strconv.Itoa(dbworkerstore.DefaultCandidateBatchSize), "The number of candidate jobs considered by each dequeue under the advisory lock strategy.")
+}
+
+func (c *SharedConfig) Validate() error {
+	if c.DequeueLockStrategy != dbworkerstore.LockStrategySkipLocked && c.DequeueLockStrategy != dbworkerstore.LockStrategyAdvisory {
+		c.AddError(errors.Errorf("EXECUTOR_QUEUE_DEQUEUE_LOCK_STRATEGY must be one of %q or %q", dbworkerstore.LockStrategySkipLocked, dbworkerstore.LockStrategyAdvisory))
+	}
+	if c.DequeueCandidateBatchSize <= 0 {
+		c.AddError(errors.New("EXECUTOR_QUEUE_DEQUEUE_CANDIDATE_BATCH_SIZE must be positive"))
+	}
+
+	for queueName, maxProcessing := range c.MaxProcessing {
+		if maxProcessing < 0 {
+			c.AddError(errors.Errorf("the maximum number of processing jobs of queue %q must not be negative", queueName))
+		}
+	}
+
+	for queueName, backend := range c.QueueBackends {
+		if !apiserver.IsBackend(backend) {
+			c.AddError(errors.Errorf("unknown backend %q for queue %q; available backends are %s", backend, queueName, strings.Join(apiserver.Backends(), ", ")))
+		}
+	}
+
+	for queueName, interval := range c.PriorityAgingIntervals {
+		if interval < 0 {
+			c.AddError(errors.Errorf("the priority aging interval of queue %q must not be negative", queueName))
+		}
+	}
+
+	for queueName, min := range c.MinExecutorVersions {
+		if max, ok := c.MaxExecutorVersions[queueName]; ok && max.LessThan(min) {
+			c.AddError(errors.Errorf("the maximum executor version of queue %q is older than its minimum executor version", queueName))
+		}
+	}
+
+	return c.BaseConfig.Validate()
+}
+
+// RetryPolicies returns the retry policy of each failure class reported by executors. Jobs
+// failing due to a user error are never retried.
+func (c *SharedConfig) RetryPolicies() map[string]apiserver.RetryPolicy {
+	return map[string]apiserver.RetryPolicy{
+		apiclient.FailureClassUserError: {},
+		apiclient.FailureClassTransient: {
+			MaxNumRetries: c.TransientMaxRetries,
+			Backoff:       c.TransientRetryBackoff,
+			MaxBackoff:    c.TransientMaxRetryBackoff,
+		},
+		apiclient.FailureClassInfrastructure: {
+			MaxNumRetries: c.InfrastructureMaxRetries,
+		},
+	}
+}
+
+// JobTTL returns the maximum duration a job in the given queue may remain queued. A zero
+// duration indicates that jobs in the queue never expire.
+func (c *SharedConfig) JobTTL(queueName string) time.Duration {
+	return c.JobTTLs[queueName]
+}
+
+// QueueBackend returns the name of the backend storing the jobs of the given queue.
+func (c *SharedConfig) QueueBackend(queueName string) string {
+	if backend, ok := c.QueueBackends[queueName]; ok {
+		return backend
+	}
+	return apiserver.PostgresBackend
+}
+
+// PriorityAgingInterval returns the time a job in the given queue must spend queued for its
+// priority to be raised by one. A zero duration indicates that the priority of jobs in the queue
+// is fixed.
+func (c *SharedConfig) PriorityAgingInterval(queueName string) time.Duration {
+	return c.PriorityAgingIntervals[queueName]
+}
+
+// ExecutorVersionRange returns the range of executor versions allowed to dequeue from the
+// given queue. Queues without configured bounds accept executors of any version.
+func (c *SharedConfig) ExecutorVersionRange(queueName string) apiserver.ExecutorVersionRange {
+	return apiserver.ExecutorVersionRange{
+		Min: c.MinExecutorVersions[queueName],
+		Max: c.MaxExecutorVersions[queueName],
+	}
+}
+
+// DequeueOptions returns the options controlling how the queue stores claim records.
+func (c *SharedConfig) DequeueOptions() dbworkerstore.DequeueOptions {
+	return dbworkerstore.DequeueOptions{
+		LockStrategy:       c.DequeueLockStrategy,
+		CandidateBatchSize: c.DequeueCandidateBatchSize,
+	}
+}
+
+// parseStringMap parses a comma-separated list of key=value pairs.
+func parseStringMap(value string) (map[string]string, error) {
+	m := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("malformed pair %q", pair)
+		}
+
+		m[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return m, nil
+}
+
+// parseDurationMap parses a comma-separated list of key=duration pairs.
+func parseDurationMap(value string) (map[string]time.Duration, error) {
+	m := map[string]time.Duration{}
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("malformed pair %q", pair)
+		}
+
+		duration, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "malformed duration for %q", parts[0])
+		}
+
+		m[strings.TrimSpace(parts[0])] = duration
+	}
+
+	return m, nil
+}
+
+// parseIntMap parses a comma-separated list of key=integer pairs.
+func parseIntMap(value string) (map[string]int, error) {
+	m := map[string]int{}
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("malformed pair %q", pair)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "malformed integer for %q", parts[0])
+		}
+
+		m[strings.TrimSpace(parts[0])] = n
+	}
+
+	return m, nil
+}
+
+// parseVersionMap parses a comma-separated list of key=version pairs.
+func parseVersionMap(value string) (map[string]*semver.Version, error) {
+	m := map[string]*semver.Version{}
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("malformed pair %q", pair)
+		}
+
+		version, err := semver.NewVersion(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "malformed version for %q", parts[0])
+		}
+
+		m[strings.TrimSpace(parts[0])] = version
+	}
+
+	return m, nil
+}
+
+// parseRedactionRules parses a JSON object mapping keys to objects with "fieldPaths" and
+// "patterns" arrays. Patterns are compiled as regular expressions.
+func parseRedactionRules(value string) (map[string]apiserver.RedactionRules, error) {
+	m := map[string]apiserver.RedactionRules{}
+	if strings.TrimSpace(value) == "" {
+		return m, nil
+	}
+
+	var raw map[string]struct {
+		FieldPaths []string `json:"fieldPaths"`
+		Patterns   []string `json:"patterns"`
+	}
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return nil, err
+	}
+
+	for key, rules := range raw {
+		patterns := make([]*regexp.Regexp, 0, len(rules.Patterns))
+		for _, pattern := range rules.Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, errors.Wrapf(err, "malformed pattern for %q", key)
+			}
+			patterns = append(patterns, re)
+		}
+
+		m[key] = apiserver.RedactionRules{FieldPaths: rules.FieldPaths, Patterns: patterns}
+	}
+
+	return m, nil
 }
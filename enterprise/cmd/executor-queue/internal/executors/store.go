@@ -0,0 +1,209 @@
+package executors
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// Executor describes an executor instance that has recently sent a heartbeat.
+type Executor struct {
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	Hostname        string    `json:"hostname"`
+	QueueName       string    `json:"queueName"`
+	OS              string    `json:"os"`
+	Architecture    string    `json:"architecture"`
+	ExecutorVersion string    `json:"executorVersion"`
+	FirstSeenAt     time.Time `json:"firstSeenAt"`
+	LastSeenAt      time.Time `json:"lastSeenAt"`
+
+	// Region is the region of the executor-queue instance that received the most recent
+	// heartbeat from the executor. It is empty outside of federated deployments.
+	Region string `json:"region,omitempty"`
+
+	// DeclaredDeadAt is set once the executor has stopped sending heartbeats for long enough
+	// that its jobs were requeued. It is cleared if the executor sends another heartbeat.
+	DeclaredDeadAt *time.Time `json:"declaredDeadAt,omitempty"`
+}
+
+// Store tracks executor heartbeats in the executor_heartbeats table.
+type Store struct {
+	*basestore.Store
+}
+
+// NewStore creates a new executor store backed by the given database.
+func NewStore(db dbutil.DB) *Store {
+	return &Store{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// UpsertHeartbeat records a heartbeat from the given executor. The executor is identified by
+// its name; the first-seen time of an existing executor is preserved.
+func (s *Store) UpsertHeartbeat(ctx context.Context, executor Executor) error {
+	return s.Exec(ctx, sqlf.Sprintf(
+		upsertHeartbeatQuery,
+		executor.Name,
+		executor.Hostname,
+		executor.QueueName,
+		executor.OS,
+		executor.Architecture,
+		executor.ExecutorVersion,
+		executor.Region,
+	))
+}
+
+const upsertHeartbeatQuery = `
+-- source: enterprise/cmd/executor-queue/internal/executors/store.go:UpsertHeartbeat
+INSERT INTO executor_heartbeats (name, hostname, queue_name, os, architecture, executor_version, region)
+VALUES (%s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT (name) DO UPDATE
+SET
+	hostname = EXCLUDED.hostname,
+	queue_name = EXCLUDED.queue_name,
+	os = EXCLUDED.os,
+	architecture = EXCLUDED.architecture,
+	executor_version = EXCLUDED.executor_version,
+	region = EXCLUDED.region,
+	last_seen_at = NOW(),
+	declared_dead_at = NULL
+`
+
+// ListOptions filters the executors returned from List.
+type ListOptions struct {
+	// QueueName, if set, restricts the listing to executors polling the given queue.
+	QueueName string
+
+	// SeenSince, if non-zero, restricts the listing to executors that have sent a
+	// heartbeat at or after the given time.
+	SeenSince time.Time
+
+	// Region, if set, restricts the listing to executors whose most recent heartbeat was
+	// received by an executor-queue instance of the given region.
+	Region string
+}
+
+// List returns the executors matching the given options, most recently seen first.
+func (s *Store) List(ctx context.Context, opts ListOptions) ([]Executor, error) {
+	return scanExecutors(s.Query(ctx, sqlf.Sprintf(listQuery, sqlf.Join(listConditions(opts), " AND "))))
+}
+
+const listQuery = `
+-- source: enterprise/cmd/executor-queue/internal/executors/store.go:List
+SELECT id, name, hostname, queue_name, os, architecture, executor_version, first_seen_at, last_seen_at, region, declared_dead_at
+FROM executor_heartbeats
+WHERE %s
+ORDER BY last_seen_at DESC, id
+`
+
+func listConditions(opts ListOptions) []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if opts.QueueName != "" {
+		conds = append(conds, sqlf.Sprintf("queue_name = %s", opts.QueueName))
+	}
+	if !opts.SeenSince.IsZero() {
+		conds = append(conds, sqlf.Sprintf("last_seen_at >= %s", opts.SeenSince))
+	}
+	if opts.Region != "" {
+		conds = append(conds, sqlf.Sprintf("region = %s", opts.Region))
+	}
+
+	return conds
+}
+
+// CountActive returns the number of executors per queue that have sent a heartbeat at or
+// after the given time.
+func (s *Store) CountActive(ctx context.Context, seenSince time.Time) (_ map[string]int, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(countActiveQuery, seenSince))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var queueName string
+		var count int
+		if err := rows.Scan(&queueName, &count); err != nil {
+			return nil, err
+		}
+
+		counts[queueName] = count
+	}
+
+	return counts, nil
+}
+
+const countActiveQuery = `
+-- source: enterprise/cmd/executor-queue/internal/executors/store.go:CountActive
+SELECT queue_name, COUNT(*) FROM executor_heartbeats WHERE last_seen_at >= %s GROUP BY queue_name
+`
+
+// DeclareDead marks executors that have not sent a heartbeat since the given time as dead and
+// returns them. Each executor is returned only once, so that concurrent callers do not act on
+// the same executor twice, until it sends another heartbeat.
+func (s *Store) DeclareDead(ctx context.Context, seenBefore time.Time) ([]Executor, error) {
+	return scanExecutors(s.Query(ctx, sqlf.Sprintf(declareDeadQuery, seenBefore)))
+}
+
+const declareDeadQuery = `
+-- source: enterprise/cmd/executor-queue/internal/executors/store.go:DeclareDead
+UPDATE executor_heartbeats
+SET declared_dead_at = NOW()
+WHERE id IN (
+	SELECT id FROM executor_heartbeats
+	WHERE last_seen_at < %s AND declared_dead_at IS NULL
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING id, name, hostname, queue_name, os, architecture, executor_version, first_seen_at, last_seen_at, region, declared_dead_at
+`
+
+// DeleteInactive removes executors that have not sent a heartbeat since the given time and
+// returns the number of deleted executors.
+func (s *Store) DeleteInactive(ctx context.Context, seenBefore time.Time) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(deleteInactiveQuery, seenBefore)))
+	return count, err
+}
+
+const deleteInactiveQuery = `
+-- source: enterprise/cmd/executor-queue/internal/executors/store.go:DeleteInactive
+WITH deleted AS (
+	DELETE FROM executor_heartbeats WHERE last_seen_at < %s RETURNING id
+)
+SELECT COUNT(*) FROM deleted
+`
+
+func scanExecutors(rows *sql.Rows, queryErr error) (_ []Executor, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var executors []Executor
+	for rows.Next() {
+		var executor Executor
+		if err := rows.Scan(
+			&executor.ID,
+			&executor.Name,
+			&executor.Hostname,
+			&executor.QueueName,
+			&executor.OS,
+			&executor.Architecture,
+			&executor.ExecutorVersion,
+			&executor.FirstSeenAt,
+			&executor.LastSeenAt,
+			&executor.Region,
+			&executor.DeclaredDeadAt,
+		); err != nil {
+			return nil, err
+		}
+
+		executors = append(executors, executor)
+	}
+
+	return executors, nil
+}
@@ -0,0 +1,74 @@
+package executors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := NewStore(db)
+	ctx := context.Background()
+
+	for _, executor := range []Executor{
+		{Name: "e1", Hostname: "h1", QueueName: "codeintel", OS: "linux", Architecture: "amd64", ExecutorVersion: "1.0.0"},
+		{Name: "e2", Hostname: "h2", QueueName: "batches", OS: "linux", Architecture: "amd64", ExecutorVersion: "1.0.0", Region: "eu"},
+		{Name: "e1", Hostname: "h1", QueueName: "codeintel", OS: "linux", Architecture: "amd64", ExecutorVersion: "1.1.0"},
+	} {
+		if err := store.UpsertHeartbeat(ctx, executor); err != nil {
+			t.Fatalf("unexpected error recording heartbeat: %s", err)
+		}
+	}
+
+	executors, err := store.List(ctx, ListOptions{QueueName: "codeintel"})
+	if err != nil {
+		t.Fatalf("unexpected error listing executors: %s", err)
+	}
+	if len(executors) != 1 {
+		t.Fatalf("unexpected number of executors. want=%d have=%d", 1, len(executors))
+	}
+	if executors[0].ExecutorVersion != "1.1.0" {
+		t.Errorf("unexpected executor version. want=%q have=%q", "1.1.0", executors[0].ExecutorVersion)
+	}
+
+	if executors, err := store.List(ctx, ListOptions{Region: "eu"}); err != nil {
+		t.Fatalf("unexpected error listing executors: %s", err)
+	} else if len(executors) != 1 || executors[0].Name != "e2" {
+		t.Errorf("unexpected executors in region: %v", executors)
+	}
+
+	counts, err := store.CountActive(ctx, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error counting executors: %s", err)
+	}
+	if counts["codeintel"] != 1 || counts["batches"] != 1 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+
+	dead, err := store.DeclareDead(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error declaring executors dead: %s", err)
+	}
+	if len(dead) != 2 || dead[0].DeclaredDeadAt == nil {
+		t.Errorf("unexpected dead executors: %v", dead)
+	}
+	if dead, err := store.DeclareDead(ctx, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error declaring executors dead: %s", err)
+	} else if len(dead) != 0 {
+		t.Errorf("unexpected executors declared dead twice: %v", dead)
+	}
+
+	count, err := store.DeleteInactive(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error deleting executors: %s", err)
+	}
+	if count != 2 {
+		t.Errorf("unexpected number of deleted executors. want=%d have=%d", 2, count)
+	}
+}
@@ -0,0 +1,68 @@
+package federation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// reportTimeout is the maximum duration of a single report request.
+const reportTimeout = 10 * time.Second
+
+// ParentClient sends reports to the admin API of a parent instance.
+type ParentClient interface {
+	Report(ctx context.Context, report Report) error
+}
+
+type parentClient struct {
+	url      string
+	username string
+	password string
+	client   *http.Client
+}
+
+var _ ParentClient = &parentClient{}
+
+// NewParentClient returns a client that posts reports to the parent instance at the given URL,
+// authenticating with the given admin API credentials.
+func NewParentClient(url, username, password string) ParentClient {
+	return &parentClient{
+		url:      strings.TrimSuffix(url, "/") + "/admin/federation/reports",
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: reportTimeout},
+	}
+}
+
+func (c *parentClient) Report(ctx context.Context, report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		content, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("unexpected status code %d from parent: %s", resp.StatusCode, content)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package federation
+
+import (
+	"github.com/keegancsmith/sqlf"
+)
+
+// DequeueCondition returns the condition restricting the jobs of the given queue handed out by
+// an instance with the given role and region, or nil if the instance may hand out any job.
+// Regional instances hand out untagged jobs and jobs tagged for their own region; the parent
+// hands out only untagged jobs.
+//
+// The condition references the job's id column unqualified, so it can be applied to the store
+// of any queue.
+func DequeueCondition(role Role, region, queueName string) *sqlf.Query {
+	switch role {
+	case RoleParent:
+		return sqlf.Sprintf(untaggedConditionFmtstr, queueName)
+	case RoleRegion:
+		return sqlf.Sprintf(regionConditionFmtstr, queueName, region)
+	}
+
+	return nil
+}
+
+const untaggedConditionFmtstr = `
+NOT EXISTS (
+	SELECT 1 FROM executor_job_regions jr
+	WHERE jr.queue_name = %s AND jr.job_id = id
+)
+`
+
+const regionConditionFmtstr = `
+NOT EXISTS (
+	SELECT 1 FROM executor_job_regions jr
+	WHERE jr.queue_name = %s AND jr.job_id = id AND jr.region <> %s
+)
+`
+
+// TaggedCondition returns the condition matching the jobs of the given queue tagged for the
+// given region.
+func TaggedCondition(region, queueName string) *sqlf.Query {
+	return sqlf.Sprintf(taggedConditionFmtstr, queueName, region)
+}
+
+const taggedConditionFmtstr = `
+EXISTS (
+	SELECT 1 FROM executor_job_regions jr
+	WHERE jr.queue_name = %s AND jr.job_id = id AND jr.region = %s
+)
+`
+
+// WithDequeueCondition returns a DequeueConditions hook that appends the given condition to the
+// conditions returned by the given hook, which may be nil.
+func WithDequeueCondition(dequeueConditions func(executorLabels []string) []*sqlf.Query, condition *sqlf.Query) func(executorLabels []string) []*sqlf.Query {
+	return func(executorLabels []string) []*sqlf.Query {
+		var conditions []*sqlf.Query
+		if dequeueConditions != nil {
+			conditions = dequeueConditions(executorLabels)
+		}
+
+		return append(conditions, condition)
+	}
+}
@@ -0,0 +1,66 @@
+package federation
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+// Role is the part an executor-queue instance plays in a federated deployment.
+type Role string
+
+const (
+	// RoleNone instances hand out any job and neither send nor receive reports.
+	RoleNone Role = "none"
+
+	// RoleParent instances hand out only jobs without a region and receive the reports of
+	// regional instances.
+	RoleParent Role = "parent"
+
+	// RoleRegion instances hand out jobs without a region and jobs tagged for their own region,
+	// and report their queue statistics to a parent instance.
+	RoleRegion Role = "region"
+)
+
+type Config struct {
+	env.BaseConfig
+
+	Role           Role
+	Region         string
+	ParentURL      string
+	ParentUsername string
+	ParentPassword string
+	ReportInterval time.Duration
+}
+
+func (c *Config) Load() {
+	c.Role = Role(c.Get("EXECUTOR_QUEUE_FEDERATION_ROLE", string(RoleNone), "The role of this instance in a multi-region deployment: none, parent, or region."))
+	c.Region = c.GetOptional("EXECUTOR_QUEUE_REGION", "The region served by this instance. Required if EXECUTOR_QUEUE_FEDERATION_ROLE is region.")
+	c.ParentURL = c.GetOptional("EXECUTOR_QUEUE_FEDERATION_PARENT_URL", "The URL of the parent executor-queue instance to which queue statistics are reported, e.g. http://executor-queue.us-central:3191. Required if EXECUTOR_QUEUE_FEDERATION_ROLE is region.")
+	c.ParentUsername = c.GetOptional("EXECUTOR_QUEUE_FEDERATION_PARENT_USERNAME", "The admin API username of the parent executor-queue instance.")
+	c.ParentPassword = c.GetOptional("EXECUTOR_QUEUE_FEDERATION_PARENT_PASSWORD", "The admin API password of the parent executor-queue instance.")
+	c.ReportInterval = c.GetInterval("EXECUTOR_QUEUE_FEDERATION_REPORT_INTERVAL", "30s", "Interval between reports of queue statistics to the parent executor-queue instance.")
+
+	switch c.Role {
+	case RoleNone:
+	case RoleParent:
+		if c.Region != "" {
+			c.AddError(errors.New("EXECUTOR_QUEUE_REGION must not be supplied if EXECUTOR_QUEUE_FEDERATION_ROLE is parent"))
+		}
+	case RoleRegion:
+		if c.Region == "" {
+			c.AddError(errors.New("EXECUTOR_QUEUE_REGION must be supplied if EXECUTOR_QUEUE_FEDERATION_ROLE is region"))
+		}
+		if u, err := url.Parse(c.ParentURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			c.AddError(errors.New("EXECUTOR_QUEUE_FEDERATION_PARENT_URL must be an http or https URL"))
+		}
+		if c.ParentUsername == "" || c.ParentPassword == "" {
+			c.AddError(errors.New("EXECUTOR_QUEUE_FEDERATION_PARENT_USERNAME and EXECUTOR_QUEUE_FEDERATION_PARENT_PASSWORD must be supplied if EXECUTOR_QUEUE_FEDERATION_ROLE is region"))
+		}
+	default:
+		c.AddError(errors.Errorf("EXECUTOR_QUEUE_FEDERATION_ROLE must be one of none, parent, or region, got %q", c.Role))
+	}
+}
@@ -0,0 +1,3 @@
+package federation
+
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/federation -i QueueCounter -i ExecutorLister -i ParentClient -o mock_iface_test.go
@@ -0,0 +1,102 @@
+package federation
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// QueueCounter counts the jobs of a queue.
+type QueueCounter interface {
+	QueuedCount(ctx context.Context, conditions []*sqlf.Query) (int, error)
+	ProcessingCount(ctx context.Context, conditions []*sqlf.Query) (int, error)
+}
+
+// ExecutorLister lists the executors in the executor registry.
+type ExecutorLister interface {
+	List(ctx context.Context, opts executors.ListOptions) ([]executors.Executor, error)
+}
+
+type reporter struct {
+	region          string
+	queues          map[string]QueueCounter
+	executorLister  ExecutorLister
+	activeThreshold time.Duration
+	client          ParentClient
+}
+
+var _ goroutine.Handler = &reporter{}
+var _ goroutine.ErrorHandler = &reporter{}
+
+// NewReporter returns a background routine that periodically sends the statistics of the given
+// queues, as seen by the given region, to a parent instance. Executors that have sent a
+// heartbeat to an instance of the region within the given threshold are reported as active.
+//
+// Every replica of a regional instance reports the same statistics, so the parent stores the
+// most recent report of each region rather than summing them.
+func NewReporter(region string, queues map[string]QueueCounter, executorLister ExecutorLister, activeThreshold time.Duration, client ParentClient, interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, &reporter{
+		region:          region,
+		queues:          queues,
+		executorLister:  executorLister,
+		activeThreshold: activeThreshold,
+		client:          client,
+	})
+}
+
+func (r *reporter) Handle(ctx context.Context) error {
+	report, err := r.report(ctx)
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(r.client.Report(ctx, report), "Report")
+}
+
+func (r *reporter) report(ctx context.Context) (Report, error) {
+	activeExecutors, err := r.executorLister.List(ctx, executors.ListOptions{
+		Region:    r.region,
+		SeenSince: time.Now().Add(-r.activeThreshold),
+	})
+	if err != nil {
+		return Report{}, errors.Wrap(err, "List")
+	}
+
+	numActiveExecutors := map[string]int{}
+	for _, executor := range activeExecutors {
+		numActiveExecutors[executor.QueueName]++
+	}
+
+	report := Report{Region: r.region, Queues: make([]QueueReport, 0, len(r.queues))}
+	for queueName, queue := range r.queues {
+		queued, err := queue.QueuedCount(ctx, []*sqlf.Query{DequeueCondition(RoleRegion, r.region, queueName)})
+		if err != nil {
+			return Report{}, errors.Wrapf(err, "QueuedCount %q", queueName)
+		}
+		processing, err := queue.ProcessingCount(ctx, []*sqlf.Query{TaggedCondition(r.region, queueName)})
+		if err != nil {
+			return Report{}, errors.Wrapf(err, "ProcessingCount %q", queueName)
+		}
+
+		report.Queues = append(report.Queues, QueueReport{
+			QueueName:       queueName,
+			Queued:          queued,
+			Processing:      processing,
+			ActiveExecutors: numActiveExecutors[queueName],
+		})
+	}
+	sort.Slice(report.Queues, func(i, j int) bool { return report.Queues[i].QueueName < report.Queues[j].QueueName })
+
+	return report, nil
+}
+
+func (r *reporter) HandleError(err error) {
+	log15.Error("Failed to report queue statistics to parent", "region", r.region, "error", err)
+}
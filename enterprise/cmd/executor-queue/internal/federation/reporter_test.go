@@ -0,0 +1,93 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+)
+
+func TestReporter(t *testing.T) {
+	codeintelQueue := NewMockQueueCounter()
+	codeintelQueue.QueuedCountFunc.SetDefaultReturn(5, nil)
+	codeintelQueue.ProcessingCountFunc.SetDefaultReturn(2, nil)
+	batchesQueue := NewMockQueueCounter()
+	batchesQueue.QueuedCountFunc.SetDefaultReturn(1, nil)
+
+	executorLister := NewMockExecutorLister()
+	executorLister.ListFunc.SetDefaultReturn([]executors.Executor{
+		{Name: "e1", QueueName: "codeintel"},
+		{Name: "e2", QueueName: "codeintel"},
+		{Name: "e3", QueueName: "batches"},
+	}, nil)
+	client := NewMockParentClient()
+
+	r := &reporter{
+		region:         "eu",
+		queues:         map[string]QueueCounter{"codeintel": codeintelQueue, "batches": batchesQueue},
+		executorLister: executorLister,
+		client:         client,
+	}
+	if err := r.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error reporting: %s", err)
+	}
+
+	if call := executorLister.ListFunc.History()[0]; call.Arg1.Region != "eu" {
+		t.Errorf("unexpected executor region. want=%q have=%q", "eu", call.Arg1.Region)
+	}
+	if len(client.ReportFunc.History()) != 1 {
+		t.Fatalf("unexpected number of calls to Report. want=%d have=%d", 1, len(client.ReportFunc.History()))
+	}
+	expected := Report{
+		Region: "eu",
+		Queues: []QueueReport{
+			{QueueName: "batches", Queued: 1, ActiveExecutors: 1},
+			{QueueName: "codeintel", Queued: 5, Processing: 2, ActiveExecutors: 2},
+		},
+	}
+	if diff := cmp.Diff(expected, client.ReportFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected report (-want +got):\n%s", diff)
+	}
+}
+
+func TestParentClient(t *testing.T) {
+	report := Report{Region: "eu", Queues: []QueueReport{{QueueName: "codeintel", Queued: 3}}}
+
+	var payload Report
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/admin/federation/reports" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if username, password, _ := r.BasicAuth(); username != "admin" || password != "hunter2" {
+			t.Errorf("unexpected credentials: %s:%s", username, password)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("unexpected error decoding payload: %s", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	if err := NewParentClient(ts.URL+"/", "admin", "hunter2").Report(context.Background(), report); err != nil {
+		t.Fatalf("unexpected error reporting: %s", err)
+	}
+	if diff := cmp.Diff(report, payload); diff != "" {
+		t.Errorf("unexpected report (-want +got):\n%s", diff)
+	}
+}
+
+func TestParentClientErrorStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	if err := NewParentClient(ts.URL, "admin", "hunter2").Report(context.Background(), Report{Region: "eu"}); err == nil {
+		t.Fatalf("expected an error reporting")
+	}
+}
@@ -0,0 +1,154 @@
+package federation
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// Report is the set of queue statistics periodically sent by a regional instance to its parent.
+type Report struct {
+	Region string        `json:"region"`
+	Queues []QueueReport `json:"queues"`
+}
+
+// QueueReport holds the statistics of a single queue as seen by a regional instance.
+type QueueReport struct {
+	QueueName string `json:"queueName"`
+
+	// Queued is the number of queued jobs the region may hand out, including untagged jobs
+	// that may equally be handed out by other regions.
+	Queued int `json:"queued"`
+
+	// Processing is the number of jobs tagged for the region that are being processed.
+	Processing int `json:"processing"`
+
+	// ActiveExecutors is the number of executors of the region that recently sent a heartbeat.
+	ActiveExecutors int `json:"activeExecutors"`
+}
+
+// RegionStats is the most recent report of a queue received from a region.
+type RegionStats struct {
+	Region     string    `json:"region"`
+	ReportedAt time.Time `json:"reportedAt"`
+	QueueReport
+}
+
+// Store persists job region tags in the executor_job_regions table and the reports received
+// from regional instances in the executor_queue_region_stats table.
+type Store struct {
+	*basestore.Store
+}
+
+// NewStore creates a new federation store backed by the given database.
+func NewStore(db dbutil.DB) *Store {
+	return &Store{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+// SetJobRegion tags the given job so that it is only handed out by instances of the given
+// region. An existing tag is replaced.
+func (s *Store) SetJobRegion(ctx context.Context, queueName string, jobID int, region string) error {
+	return s.Exec(ctx, sqlf.Sprintf(setJobRegionQuery, queueName, jobID, region))
+}
+
+const setJobRegionQuery = `
+-- source: enterprise/cmd/executor-queue/internal/federation/store.go:SetJobRegion
+INSERT INTO executor_job_regions (queue_name, job_id, region)
+VALUES (%s, %s, %s)
+ON CONFLICT (queue_name, job_id) DO UPDATE SET region = EXCLUDED.region
+`
+
+// DeleteJobRegion removes the region tag of the given job so that it may be handed out by any
+// instance. This method returns a boolean flag indicating if the job was tagged.
+func (s *Store) DeleteJobRegion(ctx context.Context, queueName string, jobID int) (bool, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(deleteJobRegionQuery, queueName, jobID)))
+	return count > 0, err
+}
+
+const deleteJobRegionQuery = `
+-- source: enterprise/cmd/executor-queue/internal/federation/store.go:DeleteJobRegion
+WITH deleted AS (
+	DELETE FROM executor_job_regions WHERE queue_name = %s AND job_id = %s RETURNING job_id
+)
+SELECT COUNT(*) FROM deleted
+`
+
+// UpsertReport replaces the stored statistics of each queue in the given report.
+func (s *Store) UpsertReport(ctx context.Context, report Report) error {
+	if len(report.Queues) == 0 {
+		return nil
+	}
+
+	values := make([]*sqlf.Query, 0, len(report.Queues))
+	for _, queue := range report.Queues {
+		values = append(values, sqlf.Sprintf(
+			"(%s, %s, %s, %s, %s)",
+			report.Region,
+			queue.QueueName,
+			queue.Queued,
+			queue.Processing,
+			queue.ActiveExecutors,
+		))
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(upsertReportQuery, sqlf.Join(values, ", ")))
+}
+
+const upsertReportQuery = `
+-- source: enterprise/cmd/executor-queue/internal/federation/store.go:UpsertReport
+INSERT INTO executor_queue_region_stats (region, queue_name, queued, processing, active_executors)
+VALUES %s
+ON CONFLICT (region, queue_name) DO UPDATE
+SET
+	queued = EXCLUDED.queued,
+	processing = EXCLUDED.processing,
+	active_executors = EXCLUDED.active_executors,
+	reported_at = NOW()
+`
+
+// ListRegionStats returns the most recent statistics reported by each region, ordered by region
+// and queue name. Statistics reported before the given time are omitted, unless it is zero.
+func (s *Store) ListRegionStats(ctx context.Context, reportedSince time.Time) (_ []RegionStats, err error) {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if !reportedSince.IsZero() {
+		conds = append(conds, sqlf.Sprintf("reported_at >= %s", reportedSince))
+	}
+
+	rows, err := s.Query(ctx, sqlf.Sprintf(listRegionStatsQuery, sqlf.Join(conds, " AND ")))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var stats []RegionStats
+	for rows.Next() {
+		var stat RegionStats
+		if err := rows.Scan(
+			&stat.Region,
+			&stat.QueueName,
+			&stat.Queued,
+			&stat.Processing,
+			&stat.ActiveExecutors,
+			&stat.ReportedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, stat)
+	}
+
+	return stats, nil
+}
+
+const listRegionStatsQuery = `
+-- source: enterprise/cmd/executor-queue/internal/federation/store.go:ListRegionStats
+SELECT region, queue_name, queued, processing, active_executors, reported_at
+FROM executor_queue_region_stats
+WHERE %s
+ORDER BY region, queue_name
+`
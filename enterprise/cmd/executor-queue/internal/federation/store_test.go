@@ -0,0 +1,77 @@
+package federation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestStoreJobRegions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := NewStore(db)
+	ctx := context.Background()
+
+	if err := store.SetJobRegion(ctx, "codeintel", 42, "us"); err != nil {
+		t.Fatalf("unexpected error setting region: %s", err)
+	}
+	if err := store.SetJobRegion(ctx, "codeintel", 42, "eu"); err != nil {
+		t.Fatalf("unexpected error setting region: %s", err)
+	}
+
+	if ok, err := store.DeleteJobRegion(ctx, "codeintel", 42); err != nil {
+		t.Fatalf("unexpected error deleting region: %s", err)
+	} else if !ok {
+		t.Errorf("expected region to exist")
+	}
+	if ok, err := store.DeleteJobRegion(ctx, "codeintel", 42); err != nil {
+		t.Fatalf("unexpected error deleting region: %s", err)
+	} else if ok {
+		t.Errorf("unexpected region after deletion")
+	}
+}
+
+func TestStoreRegionStats(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := NewStore(db)
+	ctx := context.Background()
+
+	for _, report := range []Report{
+		{Region: "us", Queues: []QueueReport{{QueueName: "codeintel", Queued: 3, Processing: 1, ActiveExecutors: 2}}},
+		{Region: "eu", Queues: []QueueReport{{QueueName: "codeintel", Queued: 1}, {QueueName: "batches", Queued: 2}}},
+		{Region: "us", Queues: []QueueReport{{QueueName: "codeintel", Queued: 5, Processing: 2, ActiveExecutors: 2}}},
+	} {
+		if err := store.UpsertReport(ctx, report); err != nil {
+			t.Fatalf("unexpected error storing report: %s", err)
+		}
+	}
+
+	stats, err := store.ListRegionStats(ctx, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error listing region stats: %s", err)
+	}
+	expected := []RegionStats{
+		{Region: "eu", QueueReport: QueueReport{QueueName: "batches", Queued: 2}},
+		{Region: "eu", QueueReport: QueueReport{QueueName: "codeintel", Queued: 1}},
+		{Region: "us", QueueReport: QueueReport{QueueName: "codeintel", Queued: 5, Processing: 2, ActiveExecutors: 2}},
+	}
+	if diff := cmp.Diff(expected, stats, cmpopts.IgnoreFields(RegionStats{}, "ReportedAt")); diff != "" {
+		t.Errorf("unexpected region stats (-want +got):\n%s", diff)
+	}
+
+	if stats, err := store.ListRegionStats(ctx, time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("unexpected error listing region stats: %s", err)
+	} else if len(stats) != 0 {
+		t.Errorf("unexpected stale region stats: %v", stats)
+	}
+}
@@ -0,0 +1,143 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/debugserver"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+const (
+	// DatabaseComponent is ready while the frontend database can be reached.
+	DatabaseComponent = "database"
+
+	// MigrationsComponent is ready once the frontend database schema has been migrated to at
+	// least the latest version known to this binary.
+	MigrationsComponent = "migrations"
+)
+
+// QueueComponent returns the name of the component that is ready once the given queue has
+// been initialized.
+func QueueComponent(queueName string) string {
+	return "queue:" + queueName
+}
+
+// Checker periodically checks the database connection and schema version and reports the
+// result to the readiness tracker served by the debug server.
+type Checker struct {
+	db              dbutil.DB
+	readiness       *debugserver.Readiness
+	migrationsTable string
+	expectedVersion int64
+}
+
+var _ goroutine.Handler = &Checker{}
+var _ goroutine.ErrorHandler = &Checker{}
+
+// NewChecker creates a new checker of the given database. The schema version is expected to
+// match the latest migration embedded in this binary.
+func NewChecker(db dbutil.DB, database *dbconn.Database, readiness *debugserver.Readiness) (*Checker, error) {
+	expectedVersion, err := latestMigrationVersion(database.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	readiness.Register(DatabaseComponent)
+	readiness.Register(MigrationsComponent)
+
+	return &Checker{
+		db:              db,
+		readiness:       readiness,
+		migrationsTable: database.MigrationsTable,
+		expectedVersion: expectedVersion,
+	}, nil
+}
+
+// NewRoutine returns a background routine that runs the checks at the given interval.
+func (c *Checker) NewRoutine(interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, c)
+}
+
+func (c *Checker) Handle(ctx context.Context) error {
+	if _, err := c.db.ExecContext(ctx, pingQuery); err != nil {
+		c.readiness.SetNotReady(DatabaseComponent, err.Error())
+		c.readiness.SetNotReady(MigrationsComponent, "database is unreachable")
+		return errors.Wrap(err, "pinging database")
+	}
+	c.readiness.SetReady(DatabaseComponent)
+
+	var version int64
+	var dirty bool
+	if err := c.db.QueryRowContext(ctx, fmt.Sprintf(schemaVersionQuery, c.migrationsTable)).Scan(&version, &dirty); err != nil {
+		c.readiness.SetNotReady(MigrationsComponent, err.Error())
+		return errors.Wrap(err, "reading schema version")
+	}
+
+	switch {
+	case dirty:
+		c.readiness.SetNotReady(MigrationsComponent, fmt.Sprintf("schema version %d is dirty", version))
+	case version < c.expectedVersion:
+		c.readiness.SetNotReady(MigrationsComponent, fmt.Sprintf("schema version %d is behind expected version %d", version, c.expectedVersion))
+	default:
+		// A schema ahead of the expected version is the result of a rollback, which the
+		// migrations are written to tolerate
+		c.readiness.SetReady(MigrationsComponent)
+	}
+
+	return nil
+}
+
+const pingQuery = `
+-- source: enterprise/cmd/executor-queue/internal/health/checker.go:Handle
+SELECT 1
+`
+
+const schemaVersionQuery = `
+-- source: enterprise/cmd/executor-queue/internal/health/checker.go:Handle
+SELECT version, dirty FROM %s
+`
+
+func (c *Checker) HandleError(err error) {
+	log15.Error("Failed to check executor-queue health", "error", err)
+}
+
+// latestMigrationVersion returns the highest version of the up migrations in the given
+// migration assets.
+func latestMigrationVersion(migrations fs.FS) (int64, error) {
+	entries, err := fs.ReadDir(migrations, ".")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest int64
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+
+		version, err := strconv.ParseInt(strings.SplitN(name, "_", 2)[0], 10, 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "malformed migration name %q", name)
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+
+	if latest == 0 {
+		return 0, errors.New("no migrations found")
+	}
+
+	return latest, nil
+}
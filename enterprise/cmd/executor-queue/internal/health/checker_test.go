@@ -0,0 +1,64 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/debugserver"
+)
+
+func TestLatestMigrationVersion(t *testing.T) {
+	migrations := fstest.MapFS{
+		"1528395862_first.up.sql":    &fstest.MapFile{},
+		"1528395862_first.down.sql":  &fstest.MapFile{},
+		"1528395864_third.up.sql":    &fstest.MapFile{},
+		"1528395864_third.down.sql":  &fstest.MapFile{},
+		"1528395863_second.up.sql":   &fstest.MapFile{},
+		"1528395863_second.down.sql": &fstest.MapFile{},
+		"README.md":                  &fstest.MapFile{},
+	}
+
+	version, err := latestMigrationVersion(migrations)
+	if err != nil {
+		t.Fatalf("unexpected error reading migrations: %s", err)
+	}
+	if version != 1528395864 {
+		t.Errorf("unexpected version. want=%d have=%d", 1528395864, version)
+	}
+
+	if _, err := latestMigrationVersion(fstest.MapFS{"bad.up.sql": &fstest.MapFile{}}); err == nil {
+		t.Errorf("expected error for malformed migration name")
+	}
+}
+
+func TestChecker(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+
+	readiness := debugserver.NewReadiness()
+	checker, err := NewChecker(db, dbconn.Frontend, readiness)
+	if err != nil {
+		t.Fatalf("unexpected error creating checker: %s", err)
+	}
+
+	if err := checker.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error checking health: %s", err)
+	}
+	if status := readiness.Status(); !status.Ready {
+		t.Errorf("expected migrated database to be ready: %+v", status)
+	}
+
+	// A schema behind the latest migration is not ready
+	checker.expectedVersion++
+	if err := checker.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error checking health: %s", err)
+	}
+	if status := readiness.Status(); status.Ready {
+		t.Errorf("expected outdated schema to not be ready: %+v", status)
+	}
+}
@@ -0,0 +1,48 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// AuditLogStore removes entries from the audit log.
+type AuditLogStore interface {
+	DeleteBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+type auditLogPruner struct {
+	store     AuditLogStore
+	retention time.Duration
+}
+
+var _ goroutine.Handler = &auditLogPruner{}
+var _ goroutine.ErrorHandler = &auditLogPruner{}
+
+// NewAuditLogPruner returns a background routine that periodically removes audit log entries
+// older than the given retention period.
+func NewAuditLogPruner(store AuditLogStore, retention, interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, &auditLogPruner{
+		store:     store,
+		retention: retention,
+	})
+}
+
+func (h *auditLogPruner) Handle(ctx context.Context) error {
+	count, err := h.store.DeleteBefore(ctx, time.Now().Add(-h.retention))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log15.Info("Pruned expired audit log entries", "count", count)
+	}
+
+	return nil
+}
+
+func (h *auditLogPruner) HandleError(err error) {
+	log15.Error("Failed to prune audit log", "error", err)
+}
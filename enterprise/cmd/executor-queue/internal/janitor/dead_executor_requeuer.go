@@ -0,0 +1,85 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// DeadExecutorStore declares executors that stopped sending heartbeats as dead.
+type DeadExecutorStore interface {
+	DeclareDead(ctx context.Context, seenBefore time.Time) ([]executors.Executor, error)
+}
+
+type deadExecutorRequeuer struct {
+	executorStore DeadExecutorStore
+	stores        map[string]apiserver.QueueBackend
+	threshold     time.Duration
+	metrics       *metrics
+}
+
+var _ goroutine.Handler = &deadExecutorRequeuer{}
+var _ goroutine.ErrorHandler = &deadExecutorRequeuer{}
+
+// NewDeadExecutorRequeuer returns a background routine that periodically declares executors
+// that have not sent a heartbeat within the given threshold as dead and immediately requeues
+// the jobs they were processing. Without it, those jobs are only reset once their own heartbeat
+// exceeds the queue's stalled max age.
+func NewDeadExecutorRequeuer(executorStore DeadExecutorStore, stores map[string]apiserver.QueueBackend, threshold, interval time.Duration, metrics *metrics) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, &deadExecutorRequeuer{
+		executorStore: executorStore,
+		stores:        stores,
+		threshold:     threshold,
+		metrics:       metrics,
+	})
+}
+
+func (h *deadExecutorRequeuer) Handle(ctx context.Context) error {
+	deadExecutors, err := h.executorStore.DeclareDead(ctx, time.Now().Add(-h.threshold))
+	if err != nil {
+		return errors.Wrap(err, "DeclareDead")
+	}
+
+	var errs error
+	for _, executor := range deadExecutors {
+		store, ok := h.stores[executor.QueueName]
+		if !ok {
+			continue
+		}
+
+		// Jobs that cannot be requeued here are still reset by the stalled job resetter
+		// once their heartbeat exceeds the stalled max age.
+		resetIDs, erroredIDs, err := store.ResetWorker(ctx, executor.Name)
+		if err != nil {
+			h.metrics.numRecordResetErrors.WithLabelValues(executor.QueueName).Inc()
+			errs = multierror.Append(errs, errors.Wrapf(err, "ResetWorker %q", executor.Name))
+			continue
+		}
+
+		if len(resetIDs) > 0 || len(erroredIDs) > 0 {
+			log15.Info(
+				"Requeued jobs of dead executor",
+				"queue", executor.QueueName,
+				"executor", executor.Name,
+				"lastSeenAt", executor.LastSeenAt,
+				"requeued", len(resetIDs),
+				"errored", len(erroredIDs),
+			)
+		}
+		h.metrics.numFastRequeued.WithLabelValues(executor.QueueName).Add(float64(len(resetIDs)))
+		h.metrics.numRecordResetFailures.WithLabelValues(executor.QueueName).Add(float64(len(erroredIDs)))
+	}
+
+	return errs
+}
+
+func (h *deadExecutorRequeuer) HandleError(err error) {
+	log15.Error("Failed to requeue jobs of dead executors", "error", err)
+}
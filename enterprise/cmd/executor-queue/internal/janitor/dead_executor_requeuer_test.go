@@ -0,0 +1,61 @@
+package janitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+type testDeadExecutorStore struct {
+	seenBefore []time.Time
+	executors  []executors.Executor
+}
+
+func (s *testDeadExecutorStore) DeclareDead(ctx context.Context, seenBefore time.Time) ([]executors.Executor, error) {
+	s.seenBefore = append(s.seenBefore, seenBefore)
+	return s.executors, nil
+}
+
+func TestDeadExecutorRequeuer(t *testing.T) {
+	executorStore := &testDeadExecutorStore{executors: []executors.Executor{
+		{Name: "e1", QueueName: "codeintel"},
+		{Name: "e2", QueueName: "batches"},
+		{Name: "e3", QueueName: "unknown"},
+	}}
+	codeintelStore := workerstoremocks.NewMockStore()
+	codeintelStore.ResetWorkerFunc.SetDefaultReturn([]int{1, 2}, []int{3}, nil)
+	batchesStore := workerstoremocks.NewMockStore()
+
+	requeuer := &deadExecutorRequeuer{
+		executorStore: executorStore,
+		stores:        map[string]apiserver.QueueBackend{"codeintel": codeintelStore, "batches": batchesStore},
+		threshold:     time.Minute,
+		metrics:       newMetrics(&observation.TestContext),
+	}
+
+	start := time.Now()
+	if err := requeuer.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error requeueing jobs: %s", err)
+	}
+
+	if len(executorStore.seenBefore) != 1 || executorStore.seenBefore[0].After(start.Add(-time.Minute+time.Second)) {
+		t.Errorf("unexpected DeclareDead calls: %v", executorStore.seenBefore)
+	}
+
+	var hostnames []string
+	for _, store := range []*workerstoremocks.MockStore{codeintelStore, batchesStore} {
+		for _, call := range store.ResetWorkerFunc.History() {
+			hostnames = append(hostnames, call.Arg1)
+		}
+	}
+	if diff := cmp.Diff([]string{"e1", "e2"}, hostnames); diff != "" {
+		t.Errorf("unexpected reset workers (-want +got):\n%s", diff)
+	}
+}
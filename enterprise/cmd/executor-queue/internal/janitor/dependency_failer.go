@@ -0,0 +1,49 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+type dependencyFailer struct {
+	queueName string
+	store     apiserver.QueueBackend
+	metrics   *metrics
+}
+
+var _ goroutine.Handler = &dependencyFailer{}
+
+// NewDependencyFailer returns a background routine that periodically marks jobs in the given
+// queue as failed when a job they depend on has failed or was deleted. Queues whose store has
+// no dependency queue name configured are left untouched.
+func NewDependencyFailer(queueName string, store apiserver.QueueBackend, interval time.Duration, metrics *metrics) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, &dependencyFailer{
+		queueName: queueName,
+		store:     store,
+		metrics:   metrics,
+	})
+}
+
+func (h *dependencyFailer) Handle(ctx context.Context) error {
+	ids, err := h.store.MarkDependentsFailed(ctx)
+	if err != nil {
+		return errors.Wrap(err, "MarkDependentsFailed")
+	}
+	if len(ids) > 0 {
+		log15.Info("Failed jobs with failed dependencies", "queue", h.queueName, "count", len(ids))
+		h.metrics.numDependentsFailed.WithLabelValues(h.queueName).Add(float64(len(ids)))
+	}
+
+	return nil
+}
+
+func (h *dependencyFailer) HandleError(err error) {
+	h.metrics.numErrors.WithLabelValues(h.queueName).Inc()
+	log15.Error("Failed to fail jobs with failed dependencies", "queue", h.queueName, "error", err)
+}
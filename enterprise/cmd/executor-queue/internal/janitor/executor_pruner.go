@@ -0,0 +1,50 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// ExecutorStore removes executors from the executor registry.
+type ExecutorStore interface {
+	DeleteInactive(ctx context.Context, seenBefore time.Time) (int, error)
+}
+
+type executorPruner struct {
+	store     ExecutorStore
+	retention time.Duration
+}
+
+var _ goroutine.Handler = &executorPruner{}
+var _ goroutine.ErrorHandler = &executorPruner{}
+
+// NewExecutorPruner returns a background routine that periodically removes executors from
+// the executor registry that have not sent a heartbeat within the given retention period.
+// Executors generate a new name on each start, so without pruning the registry would grow
+// with every executor restart.
+func NewExecutorPruner(store ExecutorStore, retention, interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, &executorPruner{
+		store:     store,
+		retention: retention,
+	})
+}
+
+func (h *executorPruner) Handle(ctx context.Context) error {
+	count, err := h.store.DeleteInactive(ctx, time.Now().Add(-h.retention))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log15.Info("Pruned inactive executors", "count", count)
+	}
+
+	return nil
+}
+
+func (h *executorPruner) HandleError(err error) {
+	log15.Error("Failed to prune inactive executors", "error", err)
+}
@@ -0,0 +1,61 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+type jobExpirer struct {
+	queueName    string
+	queueOptions apiserver.QueueOptions
+	ttl          time.Duration
+	metrics      *metrics
+}
+
+var _ goroutine.Handler = &jobExpirer{}
+
+// NewJobExpirer returns a background routine that periodically marks jobs in the given
+// queue as failed when they have remained in the queued state for longer than the given
+// TTL. The queue must supply a FilterConditions hook supporting the MinAge filter.
+func NewJobExpirer(queueName string, queueOptions apiserver.QueueOptions, ttl, interval time.Duration, metrics *metrics) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, &jobExpirer{
+		queueName:    queueName,
+		queueOptions: queueOptions,
+		ttl:          ttl,
+		metrics:      metrics,
+	})
+}
+
+func (h *jobExpirer) Handle(ctx context.Context) error {
+	if h.queueOptions.FilterConditions == nil {
+		return errors.Wrap(apiserver.ErrUnsupportedFilter, "no FilterConditions hook")
+	}
+
+	conditions, err := h.queueOptions.FilterConditions(apiserver.JobFilter{MinAge: h.ttl})
+	if err != nil {
+		return errors.Wrap(err, "FilterConditions")
+	}
+
+	ids, err := h.queueOptions.Store.MarkQueuedFailed(ctx, conditions, fmt.Sprintf("job expired after remaining queued for longer than %s", h.ttl))
+	if err != nil {
+		return errors.Wrap(err, "MarkQueuedFailed")
+	}
+	if len(ids) > 0 {
+		log15.Info("Expired queued jobs", "queue", h.queueName, "count", len(ids))
+		h.metrics.numJobsExpired.WithLabelValues(h.queueName).Add(float64(len(ids)))
+	}
+
+	return nil
+}
+
+func (h *jobExpirer) HandleError(err error) {
+	h.metrics.numErrors.WithLabelValues(h.queueName).Inc()
+	log15.Error("Failed to expire queued jobs", "queue", h.queueName, "error", err)
+}
@@ -0,0 +1,51 @@
+package janitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestJobExpirer(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.MarkQueuedFailedFunc.SetDefaultReturn([]int{1, 2, 3}, nil)
+
+	var filters []apiserver.JobFilter
+	filterConditions := func(filter apiserver.JobFilter) ([]*sqlf.Query, error) {
+		filters = append(filters, filter)
+		return []*sqlf.Query{sqlf.Sprintf("TRUE")}, nil
+	}
+
+	expirer := &jobExpirer{
+		queueName:    "test",
+		queueOptions: apiserver.QueueOptions{Store: store, FilterConditions: filterConditions},
+		ttl:          time.Hour,
+		metrics:      newMetrics(&observation.TestContext),
+	}
+
+	if err := expirer.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error expiring jobs: %s", err)
+	}
+
+	if len(filters) != 1 || filters[0].MinAge != time.Hour {
+		t.Errorf("unexpected filters: %v", filters)
+	}
+	if value := len(store.MarkQueuedFailedFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to MarkQueuedFailed. want=%d have=%d", 1, value)
+	}
+
+	call := store.MarkQueuedFailedFunc.History()[0]
+	if len(call.Arg1) != 1 {
+		t.Errorf("unexpected number of conditions. want=%d have=%d", 1, len(call.Arg1))
+	}
+	if !strings.Contains(call.Arg2, "1h0m0s") {
+		t.Errorf("unexpected failure message %q", call.Arg2)
+	}
+}
@@ -0,0 +1,71 @@
+package janitor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+type metrics struct {
+	numJobsExpired         *prometheus.CounterVec
+	numDependentsFailed    *prometheus.CounterVec
+	numErrors              *prometheus.CounterVec
+	numRecordResets        *prometheus.CounterVec
+	numRecordResetFailures *prometheus.CounterVec
+	numRecordResetErrors   *prometheus.CounterVec
+	numFastRequeued        *prometheus.CounterVec
+}
+
+var NewMetrics = newMetrics
+
+func newMetrics(observationContext *observation.Context) *metrics {
+	counter := func(name, help string) *prometheus.CounterVec {
+		counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: name,
+			Help: help,
+		}, []string{"queue"})
+
+		observationContext.Registerer.MustRegister(counter)
+		return counter
+	}
+
+	numJobsExpired := counter(
+		"src_executor_queue_jobs_expired_total",
+		"The number of queued jobs marked as failed after exceeding their TTL.",
+	)
+	numDependentsFailed := counter(
+		"src_executor_queue_dependents_failed_total",
+		"The number of queued jobs marked as failed because a job they depend on failed or was deleted.",
+	)
+	numErrors := counter(
+		"src_executor_queue_janitor_errors_total",
+		"The number of errors that occur during an executor-queue janitor job.",
+	)
+
+	numRecordResets := counter(
+		"src_executor_queue_record_resets_total",
+		"The number of stalled jobs moved back into the queued state.",
+	)
+	numRecordResetFailures := counter(
+		"src_executor_queue_record_reset_failures_total",
+		"The number of stalled jobs marked as errored after exceeding the maximum number of resets.",
+	)
+	numRecordResetErrors := counter(
+		"src_executor_queue_record_reset_errors_total",
+		"The number of errors that occur while resetting stalled jobs.",
+	)
+	numFastRequeued := counter(
+		"src_executor_queue_fast_requeued_jobs_total",
+		"The number of jobs moved back into the queued state because the executor processing them was declared dead.",
+	)
+
+	return &metrics{
+		numJobsExpired:         numJobsExpired,
+		numDependentsFailed:    numDependentsFailed,
+		numErrors:              numErrors,
+		numRecordResets:        numRecordResets,
+		numRecordResetFailures: numRecordResetFailures,
+		numRecordResetErrors:   numRecordResetErrors,
+		numFastRequeued:        numFastRequeued,
+	}
+}
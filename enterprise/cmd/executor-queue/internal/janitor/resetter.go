@@ -0,0 +1,23 @@
+package janitor
+
+import (
+	"time"
+
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+)
+
+// NewResetter returns a background routine that periodically moves jobs in the given queue
+// back to the queued state once their executor has stopped sending heartbeats. The stall
+// threshold and maximum number of resets are configured on the store itself.
+func NewResetter(queueName string, store apiserver.QueueBackend, interval time.Duration, metrics *metrics) *dbworker.Resetter {
+	return dbworker.NewResetter(store, dbworker.ResetterOptions{
+		Name:     "executor_queue_" + queueName + "_resetter",
+		Interval: interval,
+		Metrics: dbworker.ResetterMetrics{
+			RecordResets:        metrics.numRecordResets.WithLabelValues(queueName),
+			RecordResetFailures: metrics.numRecordResetFailures.WithLabelValues(queueName),
+			Errors:              metrics.numRecordResetErrors.WithLabelValues(queueName),
+		},
+	})
+}
@@ -0,0 +1,107 @@
+package leader
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/database/locker"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// lockKey is the advisory lock key held by the leading executor-queue replica.
+const lockKey = 1
+
+// Elector elects a single leader among executor-queue replicas sharing a database. The
+// leader holds an advisory lock in an open transaction for as long as it is running.
+type Elector struct {
+	replicaID string
+	locker    *locker.Locker
+	tx        *locker.Locker
+	unlock    locker.UnlockFunc
+	leader    int32
+}
+
+var _ goroutine.Handler = &Elector{}
+var _ goroutine.ErrorHandler = &Elector{}
+var _ goroutine.Finalizer = &Elector{}
+
+// NewElector creates a new elector for the replica with the given identifier.
+func NewElector(db dbutil.DB, replicaID string) *Elector {
+	return &Elector{
+		replicaID: replicaID,
+		locker:    locker.NewWithDB(db, "executor_queue_leader"),
+	}
+}
+
+// NewRoutine returns a background routine that attempts to acquire leadership at the given
+// interval and, once acquired, checks at the same interval that the lock is still held.
+func (e *Elector) NewRoutine(interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, e)
+}
+
+// IsLeader returns true if this replica currently holds leadership.
+func (e *Elector) IsLeader() bool {
+	return atomic.LoadInt32(&e.leader) == 1
+}
+
+func (e *Elector) Handle(ctx context.Context) (err error) {
+	if e.tx != nil {
+		// Ensure the connection holding the lock is still alive
+		if err := e.tx.Exec(ctx, sqlf.Sprintf(pingQuery)); err != nil {
+			e.release(err)
+			return err
+		}
+
+		return nil
+	}
+
+	// The transaction holding the lock must outlive the handler context so that it can be
+	// released explicitly during shutdown.
+	tx, err := e.locker.Transact(context.Background())
+	if err != nil {
+		return err
+	}
+
+	locked, unlock, err := tx.LockInTransaction(ctx, lockKey, false)
+	if err != nil || !locked {
+		return tx.Done(err)
+	}
+
+	e.tx, e.unlock = tx, unlock
+	atomic.StoreInt32(&e.leader, 1)
+	log15.Info("Acquired executor-queue leadership", "replica", e.replicaID)
+	return nil
+}
+
+const pingQuery = `
+-- source: enterprise/cmd/executor-queue/internal/leader/elector.go:Handle
+SELECT 1
+`
+
+func (e *Elector) HandleError(err error) {
+	log15.Error("Failed to elect executor-queue leader", "replica", e.replicaID, "error", err)
+}
+
+func (e *Elector) OnShutdown() {
+	e.release(nil)
+}
+
+// release relinquishes leadership, if held.
+func (e *Elector) release(err error) {
+	if e.tx == nil {
+		return
+	}
+
+	atomic.StoreInt32(&e.leader, 0)
+	if doneErr := e.tx.Done(e.unlock(err)); doneErr != nil && err == nil {
+		log15.Error("Failed to release executor-queue leadership", "replica", e.replicaID, "error", doneErr)
+	}
+
+	e.tx, e.unlock = nil, nil
+	log15.Info("Released executor-queue leadership", "replica", e.replicaID)
+}
@@ -0,0 +1,44 @@
+package leader
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestElector(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+
+	e1 := NewElector(db, "replica-1")
+	e2 := NewElector(db, "replica-2")
+
+	if err := e1.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error electing leader: %s", err)
+	}
+	if err := e2.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error electing leader: %s", err)
+	}
+	if !e1.IsLeader() {
+		t.Errorf("expected first replica to be leader")
+	}
+	if e2.IsLeader() {
+		t.Errorf("expected second replica to be follower")
+	}
+
+	// Leadership is handed off once the leader shuts down
+	e1.OnShutdown()
+	if err := e2.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error electing leader: %s", err)
+	}
+	if e1.IsLeader() {
+		t.Errorf("expected first replica to have released leadership")
+	}
+	if !e2.IsLeader() {
+		t.Errorf("expected second replica to be leader")
+	}
+	e2.OnShutdown()
+}
@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// ExecutorCounter counts the executors per queue that have sent a heartbeat since a given time.
+type ExecutorCounter interface {
+	CountActive(ctx context.Context, seenSince time.Time) (map[string]int, error)
+}
+
+// activeExecutorsReporter periodically refreshes the number of active executors per queue.
+type activeExecutorsReporter struct {
+	counter    ExecutorCounter
+	queueNames []string
+	threshold  time.Duration
+	isLeader   func() bool
+	clock      glock.Clock
+	desc       *prometheus.Desc
+	mu         sync.RWMutex
+	counts     map[string]int
+}
+
+var _ goroutine.Handler = &activeExecutorsReporter{}
+var _ goroutine.ErrorHandler = &activeExecutorsReporter{}
+var _ prometheus.Collector = &activeExecutorsReporter{}
+
+// NewActiveExecutorsReporter returns a background routine that refreshes the number of
+// executors that have sent a heartbeat within the given threshold for each of the given
+// queues. The counts are reported via the src_executor_queue_active_executors gauge.
+//
+// As with NewQueuedCountReporter, only the leading replica refreshes and reports counts.
+func NewActiveExecutorsReporter(counter ExecutorCounter, queueNames []string, threshold time.Duration, isLeader func() bool, interval time.Duration, registerer prometheus.Registerer) goroutine.BackgroundRoutine {
+	return newActiveExecutorsReporter(counter, queueNames, threshold, isLeader, interval, registerer, glock.NewRealClock())
+}
+
+func newActiveExecutorsReporter(counter ExecutorCounter, queueNames []string, threshold time.Duration, isLeader func() bool, interval time.Duration, registerer prometheus.Registerer, clock glock.Clock) goroutine.BackgroundRoutine {
+	if isLeader == nil {
+		isLeader = func() bool { return true }
+	}
+
+	reporter := &activeExecutorsReporter{
+		counter:    counter,
+		queueNames: queueNames,
+		threshold:  threshold,
+		isLeader:   isLeader,
+		clock:      clock,
+		desc:       prometheus.NewDesc("src_executor_queue_active_executors", "Number of executors that have recently sent a heartbeat.", []string{"queue"}, nil),
+	}
+
+	registerer.MustRegister(reporter)
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, reporter)
+}
+
+func (r *activeExecutorsReporter) Handle(ctx context.Context) error {
+	if !r.isLeader() {
+		return nil
+	}
+
+	counts, err := r.counter.CountActive(ctx, r.clock.Now().Add(-r.threshold))
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.counts = counts
+	r.mu.Unlock()
+
+	return nil
+}
+
+func (r *activeExecutorsReporter) HandleError(err error) {
+	log15.Error("Failed to count active executors", "error", err)
+}
+
+func (r *activeExecutorsReporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- r.desc
+}
+
+func (r *activeExecutorsReporter) Collect(ch chan<- prometheus.Metric) {
+	if !r.isLeader() {
+		return
+	}
+
+	for _, queueName := range r.queueNames {
+		ch <- prometheus.MustNewConstMetric(r.desc, prometheus.GaugeValue, r.activeCount(queueName), queueName)
+	}
+}
+
+func (r *activeExecutorsReporter) activeCount(queueName string) float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return float64(r.counts[queueName])
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestActiveExecutorsReporter(t *testing.T) {
+	counter := NewMockExecutorCounter()
+	counter.CountActiveFunc.SetDefaultReturn(map[string]int{"codeintel": 3}, nil)
+
+	clock := glock.NewMockClock()
+	reporter := &activeExecutorsReporter{counter: counter, queueNames: []string{"batches", "codeintel"}, threshold: time.Minute, isLeader: func() bool { return true }, clock: clock}
+
+	if err := reporter.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error refreshing counts: %s", err)
+	}
+
+	if value := len(counter.CountActiveFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to CountActive. want=%d have=%d", 1, value)
+	}
+	if seenSince := counter.CountActiveFunc.History()[0].Arg1; !seenSince.Equal(clock.Now().Add(-time.Minute)) {
+		t.Errorf("unexpected seen since. want=%s have=%s", clock.Now().Add(-time.Minute), seenSince)
+	}
+
+	if value := reporter.activeCount("codeintel"); value != 3 {
+		t.Errorf("unexpected count. want=%d have=%f", 3, value)
+	}
+	if value := reporter.activeCount("batches"); value != 0 {
+		t.Errorf("unexpected count. want=%d have=%f", 0, value)
+	}
+}
+
+func TestActiveExecutorsReporterCollect(t *testing.T) {
+	testCases := []struct {
+		isLeader        bool
+		expectedMetrics int
+	}{
+		{isLeader: true, expectedMetrics: 2},
+		{isLeader: false, expectedMetrics: 0},
+	}
+
+	for _, testCase := range testCases {
+		isLeader := testCase.isLeader
+		registry := prometheus.NewRegistry()
+		newActiveExecutorsReporter(NewMockExecutorCounter(), []string{"batches", "codeintel"}, time.Minute, func() bool { return isLeader }, time.Minute, registry, glock.NewMockClock())
+
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatalf("unexpected error gathering metrics: %s", err)
+		}
+
+		numMetrics := 0
+		for _, family := range families {
+			numMetrics += len(family.GetMetric())
+		}
+		if numMetrics != testCase.expectedMetrics {
+			t.Errorf("unexpected number of metrics. want=%d have=%d", testCase.expectedMetrics, numMetrics)
+		}
+	}
+}
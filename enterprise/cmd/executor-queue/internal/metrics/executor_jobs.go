@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/keegancsmith/sqlf"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// ExecutorLister lists the executors in the executor registry.
+type ExecutorLister interface {
+	List(ctx context.Context, opts executors.ListOptions) ([]executors.Executor, error)
+}
+
+// executorKey identifies an executor polling a particular queue.
+type executorKey struct {
+	queueName    string
+	executorName string
+}
+
+// ExecutorJobsReporter reports the number of jobs processing and completed per executor
+// hostname. Only executors that have sent a heartbeat within the active threshold are
+// reported, so the number of series is bounded by the size of the live executor registry.
+type ExecutorJobsReporter struct {
+	lister         ExecutorLister
+	stores         map[string]apiserver.QueueBackend
+	threshold      time.Duration
+	isLeader       func() bool
+	clock          glock.Clock
+	processingDesc *prometheus.Desc
+	completedDesc  *prometheus.Desc
+	mu             sync.RWMutex
+	executors      []executors.Executor
+	processing     map[executorKey]int
+	completed      map[executorKey]int
+}
+
+var _ goroutine.Handler = &ExecutorJobsReporter{}
+var _ goroutine.ErrorHandler = &ExecutorJobsReporter{}
+var _ prometheus.Collector = &ExecutorJobsReporter{}
+
+// NewExecutorJobsReporter creates a reporter of per-executor job counts for the given queues.
+// The number of jobs processing per executor is reported via the
+// src_executor_queue_executor_processing_jobs gauge by the leading replica only, as it is
+// read from the database. The number of jobs each executor completed through this replica is
+// reported via the src_executor_queue_executor_completed_jobs_total counter by every replica.
+func NewExecutorJobsReporter(lister ExecutorLister, stores map[string]apiserver.QueueBackend, threshold time.Duration, isLeader func() bool, registerer prometheus.Registerer) *ExecutorJobsReporter {
+	return newExecutorJobsReporter(lister, stores, threshold, isLeader, registerer, glock.NewRealClock())
+}
+
+func newExecutorJobsReporter(lister ExecutorLister, stores map[string]apiserver.QueueBackend, threshold time.Duration, isLeader func() bool, registerer prometheus.Registerer, clock glock.Clock) *ExecutorJobsReporter {
+	if isLeader == nil {
+		isLeader = func() bool { return true }
+	}
+
+	labels := []string{"queue", "hostname"}
+
+	reporter := &ExecutorJobsReporter{
+		lister:         lister,
+		stores:         stores,
+		threshold:      threshold,
+		isLeader:       isLeader,
+		clock:          clock,
+		processingDesc: prometheus.NewDesc("src_executor_queue_executor_processing_jobs", "Number of jobs in the processing state per executor.", labels, nil),
+		completedDesc:  prometheus.NewDesc("src_executor_queue_executor_completed_jobs_total", "Number of jobs completed per executor.", labels, nil),
+		processing:     map[executorKey]int{},
+		completed:      map[executorKey]int{},
+	}
+
+	registerer.MustRegister(reporter)
+	return reporter
+}
+
+// NewRoutine returns a background routine that refreshes the live executor registry and the
+// number of jobs processing per executor at the given interval.
+func (r *ExecutorJobsReporter) NewRoutine(interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, r)
+}
+
+// JobCompletedFunc returns a function that records a job of the given queue as completed by
+// the executor with the given name.
+func (r *ExecutorJobsReporter) JobCompletedFunc(queueName string) func(executorName string) {
+	return func(executorName string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.completed[executorKey{queueName: queueName, executorName: executorName}]++
+	}
+}
+
+func (r *ExecutorJobsReporter) Handle(ctx context.Context) error {
+	liveExecutors, err := r.lister.List(ctx, executors.ListOptions{SeenSince: r.clock.Now().Add(-r.threshold)})
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	processing := map[executorKey]int{}
+	if r.isLeader() {
+		for _, executor := range liveExecutors {
+			queueStore, ok := r.stores[executor.QueueName]
+			if !ok {
+				continue
+			}
+
+			count, err := queueStore.ProcessingCount(ctx, []*sqlf.Query{sqlf.Sprintf("worker_hostname = %s", executor.Name)})
+			if err != nil {
+				errs = multierror.Append(errs, err)
+				continue
+			}
+
+			processing[executorKey{queueName: executor.QueueName, executorName: executor.Name}] = count
+		}
+	}
+
+	live := make(map[executorKey]struct{}, len(liveExecutors))
+	for _, executor := range liveExecutors {
+		live[executorKey{queueName: executor.QueueName, executorName: executor.Name}] = struct{}{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.executors = liveExecutors
+	r.processing = processing
+
+	// Drop the completion counts of executors that have left the registry
+	for key := range r.completed {
+		if _, ok := live[key]; !ok {
+			delete(r.completed, key)
+		}
+	}
+
+	return errs
+}
+
+func (r *ExecutorJobsReporter) HandleError(err error) {
+	log15.Error("Failed to refresh per-executor job counts", "error", err)
+}
+
+func (r *ExecutorJobsReporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- r.processingDesc
+	ch <- r.completedDesc
+}
+
+func (r *ExecutorJobsReporter) Collect(ch chan<- prometheus.Metric) {
+	processing, completed := r.counts()
+
+	if r.isLeader() {
+		for key, count := range processing {
+			ch <- prometheus.MustNewConstMetric(r.processingDesc, prometheus.GaugeValue, float64(count), key.queueName, key.hostname)
+		}
+	}
+	for key, count := range completed {
+		ch <- prometheus.MustNewConstMetric(r.completedDesc, prometheus.CounterValue, float64(count), key.queueName, key.hostname)
+	}
+}
+
+// hostnameKey identifies the executors of a particular queue running on the same host.
+type hostnameKey struct {
+	queueName string
+	hostname  string
+}
+
+// counts returns the number of jobs processing and completed per hostname of each live
+// executor. Executors sharing a hostname are reported together.
+func (r *ExecutorJobsReporter) counts() (processing, completed map[hostnameKey]int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	processing = map[hostnameKey]int{}
+	completed = map[hostnameKey]int{}
+	for _, executor := range r.executors {
+		key := executorKey{queueName: executor.QueueName, executorName: executor.Name}
+		hostname := hostnameKey{queueName: executor.QueueName, hostname: executor.Hostname}
+
+		processing[hostname] += r.processing[key]
+		completed[hostname] += r.completed[key]
+	}
+
+	return processing, completed
+}
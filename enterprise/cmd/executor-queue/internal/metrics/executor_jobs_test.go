@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestExecutorJobsReporter(t *testing.T) {
+	lister := NewMockExecutorLister()
+	lister.ListFunc.SetDefaultReturn([]executors.Executor{
+		{Name: "e1", Hostname: "h1", QueueName: "codeintel"},
+		{Name: "e2", Hostname: "h2", QueueName: "codeintel"},
+		{Name: "e3", Hostname: "h2", QueueName: "codeintel"},
+		{Name: "e4", Hostname: "h4", QueueName: "unknown"},
+	}, nil)
+
+	codeintelStore := workerstoremocks.NewMockStore()
+	codeintelStore.ProcessingCountFunc.SetDefaultHook(func(ctx context.Context, conditions []*sqlf.Query) (int, error) {
+		return map[string]int{"e1": 3, "e2": 1, "e3": 2}[conditions[0].Args()[0].(string)], nil
+	})
+
+	clock := glock.NewMockClock()
+	reporter := newExecutorJobsReporter(lister, map[string]apiserver.QueueBackend{"codeintel": codeintelStore}, time.Minute, nil, prometheus.NewRegistry(), clock)
+
+	completed := reporter.JobCompletedFunc("codeintel")
+	completed("e1")
+	completed("e3")
+	completed("e3")
+	completed("e5")
+
+	if err := reporter.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error refreshing counts: %s", err)
+	}
+
+	if seenSince := lister.ListFunc.History()[0].Arg1.SeenSince; !seenSince.Equal(clock.Now().Add(-time.Minute)) {
+		t.Errorf("unexpected seen since. want=%s have=%s", clock.Now().Add(-time.Minute), seenSince)
+	}
+	if value := len(codeintelStore.ProcessingCountFunc.History()); value != 3 {
+		t.Errorf("unexpected number of calls to ProcessingCount. want=%d have=%d", 3, value)
+	}
+
+	processing, completedCounts := reporter.counts()
+
+	expectedProcessing := map[hostnameKey]int{
+		{queueName: "codeintel", hostname: "h1"}: 3,
+		{queueName: "codeintel", hostname: "h2"}: 3,
+		{queueName: "unknown", hostname: "h4"}:   0,
+	}
+	if diff := cmp.Diff(expectedProcessing, processing, cmp.AllowUnexported(hostnameKey{})); diff != "" {
+		t.Errorf("unexpected processing counts (-want +got):\n%s", diff)
+	}
+
+	// The completion of e5, which is not in the live registry, is dropped
+	expectedCompleted := map[hostnameKey]int{
+		{queueName: "codeintel", hostname: "h1"}: 1,
+		{queueName: "codeintel", hostname: "h2"}: 2,
+		{queueName: "unknown", hostname: "h4"}:   0,
+	}
+	if diff := cmp.Diff(expectedCompleted, completedCounts, cmp.AllowUnexported(hostnameKey{})); diff != "" {
+		t.Errorf("unexpected completed counts (-want +got):\n%s", diff)
+	}
+}
+
+func TestExecutorJobsReporterCollect(t *testing.T) {
+	testCases := []struct {
+		isLeader        bool
+		expectedMetrics int
+	}{
+		{isLeader: true, expectedMetrics: 4},
+		{isLeader: false, expectedMetrics: 2},
+	}
+
+	for _, testCase := range testCases {
+		isLeader := testCase.isLeader
+		lister := NewMockExecutorLister()
+		lister.ListFunc.SetDefaultReturn([]executors.Executor{
+			{Name: "e1", Hostname: "h1", QueueName: "codeintel"},
+			{Name: "e2", Hostname: "h2", QueueName: "batches"},
+		}, nil)
+
+		registry := prometheus.NewRegistry()
+		stores := map[string]apiserver.QueueBackend{"codeintel": workerstoremocks.NewMockStore(), "batches": workerstoremocks.NewMockStore()}
+		reporter := newExecutorJobsReporter(lister, stores, time.Minute, func() bool { return isLeader }, registry, glock.NewMockClock())
+		if err := reporter.Handle(context.Background()); err != nil {
+			t.Fatalf("unexpected error refreshing counts: %s", err)
+		}
+
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatalf("unexpected error gathering metrics: %s", err)
+		}
+
+		numMetrics := 0
+		for _, family := range families {
+			numMetrics += len(family.GetMetric())
+		}
+		if numMetrics != testCase.expectedMetrics {
+			t.Errorf("unexpected number of metrics. want=%d have=%d", testCase.expectedMetrics, numMetrics)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+package metrics
+
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/metrics -i ExecutorCounter -o mock_executor_counter_test.go
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/metrics -i ExecutorLister -o mock_executor_lister_test.go
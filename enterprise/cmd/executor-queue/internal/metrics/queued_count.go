@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/derision-test/glock"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// queuedCountReporter periodically refreshes the number of queued jobs in a single queue
+// so that Prometheus scrapes are served from memory rather than from the database.
+type queuedCountReporter struct {
+	queueName     string
+	store         apiserver.QueueBackend
+	isLeader      func() bool
+	clock         glock.Clock
+	countDesc     *prometheus.Desc
+	stalenessDesc *prometheus.Desc
+	mu            sync.RWMutex
+	count         int
+	lastRefresh   time.Time
+}
+
+var _ goroutine.Handler = &queuedCountReporter{}
+var _ goroutine.ErrorHandler = &queuedCountReporter{}
+var _ prometheus.Collector = &queuedCountReporter{}
+
+// NewQueuedCountReporter returns a background routine that refreshes the number of queued
+// jobs in the given queue at the given interval. The cached count is reported via the
+// src_executor_total gauge, and the time since the last successful refresh is reported
+// via the src_executor_queue_queued_count_staleness_seconds gauge.
+//
+// When multiple replicas share a database, only the replica for which isLeader returns
+// true refreshes and reports the count so that the queue is not counted more than once.
+// A nil isLeader function indicates a single replica.
+func NewQueuedCountReporter(queueName string, store apiserver.QueueBackend, isLeader func() bool, interval time.Duration, registerer prometheus.Registerer) goroutine.BackgroundRoutine {
+	return newQueuedCountReporter(queueName, store, isLeader, interval, registerer, glock.NewRealClock())
+}
+
+func newQueuedCountReporter(queueName string, store apiserver.QueueBackend, isLeader func() bool, interval time.Duration, registerer prometheus.Registerer, clock glock.Clock) goroutine.BackgroundRoutine {
+	if isLeader == nil {
+		isLeader = func() bool { return true }
+	}
+
+	constLabels := prometheus.Labels{"queue": queueName}
+
+	reporter := &queuedCountReporter{
+		queueName:     queueName,
+		store:         store,
+		isLeader:      isLeader,
+		clock:         clock,
+		countDesc:     prometheus.NewDesc("src_executor_total", "Total number of jobs in the queued state.", nil, constLabels),
+		stalenessDesc: prometheus.NewDesc("src_executor_queue_queued_count_staleness_seconds", "Time since the number of jobs in the queued state was last refreshed.", nil, constLabels),
+		lastRefresh:   clock.Now(),
+	}
+
+	registerer.MustRegister(reporter)
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, reporter)
+}
+
+func (r *queuedCountReporter) Handle(ctx context.Context) error {
+	if !r.isLeader() {
+		return nil
+	}
+
+	count, err := r.store.QueuedCount(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.count = count
+	r.lastRefresh = r.clock.Now()
+	r.mu.Unlock()
+
+	return nil
+}
+
+func (r *queuedCountReporter) HandleError(err error) {
+	log15.Error("Failed to get queued job count", "queue", r.queueName, "error", err)
+}
+
+func (r *queuedCountReporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- r.countDesc
+	ch <- r.stalenessDesc
+}
+
+func (r *queuedCountReporter) Collect(ch chan<- prometheus.Metric) {
+	if !r.isLeader() {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(r.countDesc, prometheus.GaugeValue, r.queuedCount())
+	ch <- prometheus.MustNewConstMetric(r.stalenessDesc, prometheus.GaugeValue, r.staleness())
+}
+
+func (r *queuedCountReporter) queuedCount() float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return float64(r.count)
+}
+
+func (r *queuedCountReporter) staleness() float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.clock.Now().Sub(r.lastRefresh).Seconds()
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/derision-test/glock"
+	"github.com/prometheus/client_golang/prometheus"
+
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestQueuedCountReporter(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.QueuedCountFunc.PushReturn(42, nil)
+	store.QueuedCountFunc.PushReturn(0, errors.New("database unavailable"))
+
+	clock := glock.NewMockClock()
+	reporter := &queuedCountReporter{queueName: "test", store: store, isLeader: func() bool { return true }, clock: clock, lastRefresh: clock.Now()}
+
+	if err := reporter.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error refreshing count: %s", err)
+	}
+	clock.Advance(time.Minute)
+
+	if err := reporter.Handle(context.Background()); err == nil {
+		t.Fatalf("expected error refreshing count")
+	}
+	clock.Advance(time.Minute)
+
+	// A failed refresh retains the previous count
+	if value := reporter.queuedCount(); value != 42 {
+		t.Errorf("unexpected count. want=%d have=%f", 42, value)
+	}
+	if value := reporter.staleness(); value != (2 * time.Minute).Seconds() {
+		t.Errorf("unexpected staleness. want=%f have=%f", (2 * time.Minute).Seconds(), value)
+	}
+}
+
+func TestQueuedCountReporterCollect(t *testing.T) {
+	testCases := []struct {
+		isLeader         bool
+		expectedFamilies int
+	}{
+		{isLeader: true, expectedFamilies: 2},
+		{isLeader: false, expectedFamilies: 0},
+	}
+
+	for _, testCase := range testCases {
+		isLeader := testCase.isLeader
+		store := workerstoremocks.NewMockStore()
+		registry := prometheus.NewRegistry()
+		newQueuedCountReporter("test", store, func() bool { return isLeader }, time.Minute, registry, glock.NewMockClock())
+
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatalf("unexpected error gathering metrics: %s", err)
+		}
+		if len(families) != testCase.expectedFamilies {
+			t.Errorf("unexpected number of metrics. want=%d have=%d", testCase.expectedFamilies, len(families))
+		}
+	}
+}
+
+func TestQueuedCountReporterFollowerDoesNotRefresh(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	clock := glock.NewMockClock()
+	reporter := &queuedCountReporter{queueName: "test", store: store, isLeader: func() bool { return false }, clock: clock, lastRefresh: clock.Now()}
+
+	if err := reporter.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error refreshing count: %s", err)
+	}
+	if value := len(store.QueuedCountFunc.History()); value != 0 {
+		t.Errorf("unexpected number of calls to QueuedCount. want=%d have=%d", 0, value)
+	}
+}
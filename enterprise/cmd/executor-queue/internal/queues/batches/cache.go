@@ -0,0 +1,80 @@
+package batches
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+)
+
+// cacheKeyVersion is mixed into every cache key so that all keys can be invalidated at once
+// should the result of executing a batch spec come to depend on anything else.
+const cacheKeyVersion = 1
+
+// cacheKey returns the content-addressed cache key of the given execution, whose batch spec
+// must have been decoded. Executing the same batch spec on behalf of the same user in the same
+// namespace creates an equivalent batch spec, so the key covers only those values.
+func cacheKey(exec *btypes.BatchSpecExecution) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "v%d\x00%d\x00%d\x00%d\x00", cacheKeyVersion, exec.UserID, exec.NamespaceUserID, exec.NamespaceOrgID)
+	h.Write([]byte(exec.BatchSpec))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// completeFromCache returns a CompleteFromCache hook that completes a batch spec execution
+// with the batch spec created by a previous execution with the same cache key. The result
+// reported by the executor is the rand ID of that batch spec, which must still exist and must
+// have been created by the same user in the same namespace.
+func completeFromCache(db dbutil.DB, key encryption.Key) func(ctx context.Context, jobID int, executorName string, hit apiclient.CacheHit) (bool, error) {
+	return func(ctx context.Context, jobID int, executorName string, hit apiclient.CacheHit) (bool, error) {
+		batchesStore := store.New(db, key)
+
+		exec, err := batchesStore.GetBatchSpecExecution(ctx, store.GetBatchSpecExecutionOpts{ID: int64(jobID)})
+		if err != nil {
+			if err == store.ErrNoResults {
+				return false, nil
+			}
+			return false, err
+		}
+		if hit.CacheKey != cacheKey(exec) {
+			return false, errors.Wrap(apiserver.ErrInvalidCacheHit, "cache key does not match the batch spec execution")
+		}
+
+		batchSpec, err := batchesStore.GetBatchSpec(ctx, store.GetBatchSpecOpts{RandID: hit.Result})
+		if err != nil {
+			if err == store.ErrNoResults {
+				return false, errors.Wrapf(apiserver.ErrInvalidCacheHit, "unknown batch spec %q", hit.Result)
+			}
+			return false, err
+		}
+		if batchSpec.UserID != exec.UserID || batchSpec.NamespaceUserID != exec.NamespaceUserID || batchSpec.NamespaceOrgID != exec.NamespaceOrgID {
+			return false, errors.Wrapf(apiserver.ErrInvalidCacheHit, "batch spec %q belongs to another user or namespace", hit.Result)
+		}
+
+		_, ok, err := basestore.ScanFirstInt(batchesStore.Query(ctx, sqlf.Sprintf(completeFromCacheQuery, batchSpec.ID, jobID, executorName)))
+		return ok, err
+	}
+}
+
+// completeFromCacheQuery mirrors the query with which the batch spec executor store marks an
+// execution as complete, but takes the batch spec from the cache hit rather than from the
+// execution logs.
+const completeFromCacheQuery = `
+-- source: enterprise/cmd/executor-queue/internal/queues/batches/cache.go:completeFromCache
+UPDATE batch_spec_executions
+SET state = 'completed', finished_at = clock_timestamp(), batch_spec_id = %s
+WHERE id = %s AND state = 'processing' AND worker_hostname = %s
+RETURNING id
+`
@@ -0,0 +1,32 @@
+package batches
+
+import (
+	"testing"
+
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+)
+
+func TestCacheKey(t *testing.T) {
+	exec := btypes.BatchSpecExecution{ID: 42, UserID: 1, NamespaceUserID: 1, BatchSpec: "name: hello-world"}
+	key := cacheKey(&exec)
+
+	// Executions of the same batch spec share a cache key
+	other := exec
+	other.ID = 43
+	if value := cacheKey(&other); value != key {
+		t.Errorf("unexpected cache key. want=%q have=%q", key, value)
+	}
+
+	for _, modify := range []func(exec *btypes.BatchSpecExecution){
+		func(exec *btypes.BatchSpecExecution) { exec.BatchSpec = "name: goodbye-world" },
+		func(exec *btypes.BatchSpecExecution) { exec.UserID = 2 },
+		func(exec *btypes.BatchSpecExecution) { exec.NamespaceUserID, exec.NamespaceOrgID = 0, 1 },
+	} {
+		other := exec
+		modify(&other)
+
+		if value := cacheKey(&other); value == key {
+			t.Errorf("unexpected shared cache key for %+v", other)
+		}
+	}
+}
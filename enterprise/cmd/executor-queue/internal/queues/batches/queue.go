@@ -3,24 +3,104 @@ package batches
 import (
 	"context"
 	"database/sql"
+	"regexp"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
 
 	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/background"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
-func QueueOptions(db dbutil.DB, config *Config, observationContext *observation.Context) apiserver.QueueOptions {
+// QueueOptions returns the options for the batches queue. The given key decrypts the
+// batch specs of executions at dequeue, and may be nil if encryption is not configured.
+func QueueOptions(db dbutil.DB, config *Config, key encryption.Key, observationContext *observation.Context) apiserver.QueueOptions {
 	recordTransformer := func(ctx context.Context, record workerutil.Record) (apiclient.Job, error) {
-		return transformRecord(ctx, db, record.(*btypes.BatchSpecExecution), config)
+		return transformRecord(ctx, db, record.(*btypes.BatchSpecExecution), config, key)
 	}
 
 	return apiserver.QueueOptions{
-		Store:             background.NewExecutorStore(basestore.NewWithDB(db, sql.TxOptions{}), observationContext),
+		Store:             WorkerStore(db, config, key, observationContext),
 		RecordTransformer: recordTransformer,
+		FilterConditions:  filterConditions,
+		RecordQueuedAt:    recordQueuedAt,
+		DequeueConditions: dequeueConditions,
+		CanaryPercentage:  config.CanaryPercentage,
+		RetryPolicies:     config.Shared.RetryPolicies(),
+		RedactionRules:    redactionRules,
+		CompleteFromCache: completeFromCache(db, key),
+	}
+}
+
+// redactionRules remove the credentials handed to src-cli from the batch spec executions
+// returned by the admin API. Executors replace these values in the execution logs they upload,
+// so these rules only catch values that slipped through, e.g. in the output of a failed step.
+var redactionRules = apiserver.RedactionRules{
+	Patterns: []*regexp.Regexp{
+		regexp.MustCompile(`SRC_ACCESS_TOKEN=(\S+)`),
+		regexp.MustCompile(`://([^/\s:@]+:[^/\s@]+)@`),
+	},
+}
+
+// WorkerStore returns the store over the batch spec executions of the given database that
+// backs the batches queue.
+func WorkerStore(db dbutil.DB, config *Config, key encryption.Key, observationContext *observation.Context) dbworkerstore.Store {
+	return background.NewExecutorStoreWithResetOptions(basestore.NewWithDB(db, sql.TxOptions{}), key, config.StalledMaxAge, config.MaxNumResets, config.Shared.DequeueOptions(), observationContext)
+}
+
+// dequeueConditions enforces the batchChanges.executionQuotas.maxProcessingPerNamespace
+// site config limit by skipping executions whose namespace already has that many
+// executions processing. Concurrent dequeues may briefly exceed the limit, so it
+// should be treated as a soft limit.
+func dequeueConditions(executorLabels []string) []*sqlf.Query {
+	quotas := conf.Get().BatchChangesExecutionQuotas
+	if quotas == nil || quotas.MaxProcessingPerNamespace <= 0 {
+		return nil
+	}
+
+	return []*sqlf.Query{sqlf.Sprintf(
+		maxProcessingPerNamespaceConditionQuery,
+		btypes.BatchSpecExecutionStateProcessing,
+		quotas.MaxProcessingPerNamespace,
+	)}
+}
+
+const maxProcessingPerNamespaceConditionQuery = `
+(
+	SELECT COUNT(*)
+	FROM batch_spec_executions p
+	WHERE
+		p.state = %s AND
+		p.namespace_user_id IS NOT DISTINCT FROM batch_spec_executions.namespace_user_id AND
+		p.namespace_org_id IS NOT DISTINCT FROM batch_spec_executions.namespace_org_id
+) < %s
+`
+
+func recordQueuedAt(record workerutil.Record) time.Time {
+	return record.(*btypes.BatchSpecExecution).CreatedAt
+}
+
+// filterConditions converts admin job list filters into conditions over the
+// batch_spec_executions table. Batch spec executions are not associated with a
+// single repository, so filtering by repository is not supported.
+func filterConditions(filter apiserver.JobFilter) ([]*sqlf.Query, error) {
+	if filter.RepositoryName != "" {
+		return nil, apiserver.ErrUnsupportedFilter
+	}
+
+	var conds []*sqlf.Query
+	if filter.MinAge != 0 {
+		conds = append(conds, sqlf.Sprintf("batch_spec_executions.created_at <= %s", time.Now().Add(-filter.MinAge)))
 	}
+
+	return conds, nil
 }
@@ -5,15 +5,24 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"time"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
 )
 
-// transformRecord transforms a *btypes.BatchSpecExecution into an apiclient.Job.
-func transformRecord(ctx context.Context, db dbutil.DB, exec *btypes.BatchSpecExecution, config *Config) (apiclient.Job, error) {
+// transformRecord transforms a *btypes.BatchSpecExecution into an apiclient.Job. The batch
+// spec of the execution is decrypted with the given key if it is stored encrypted, and
+// decompressed if it is stored compressed.
+func transformRecord(ctx context.Context, db dbutil.DB, exec *btypes.BatchSpecExecution, config *Config, key encryption.Key) (apiclient.Job, error) {
+	if err := store.DecodeBatchSpecExecution(ctx, key, exec); err != nil {
+		return apiclient.Job{}, err
+	}
+
 	// TODO: createAccessToken is a bit of technical debt until we figure out a
 	// better solution. The problem is that src-cli needs to make requests to
 	// the Sourcegraph instance *on behalf of the user*.
@@ -85,6 +94,9 @@ func transformRecord(ctx context.Context, db dbutil.DB, exec *btypes.BatchSpecEx
 				Env: cliEnv,
 			},
 		},
+		MaxExecutionTime: maxExecutionTime(exec.BatchSpec, config.MaxExecutionTimeCeiling),
+		CacheKey:         cacheKey(exec),
+		TraceContext:     exec.TraceContext,
 		RedactedValues: map[string]string{
 			// 🚨 SECURITY: Catch leak of upload endpoint. This is necessary in addition
 			// to the below in case the username or password contains illegal URL characters,
@@ -105,6 +117,27 @@ func transformRecord(ctx context.Context, db dbutil.DB, exec *btypes.BatchSpecEx
 	}, nil
 }
 
+// maxExecutionTime returns the maximum execution time requested by the changeset template of
+// the given raw batch spec, capped at the given ceiling. An empty string is returned if the spec
+// does not request one, leaving the executor's default in place. Specs that fail to validate are
+// passed to src-cli as-is so that it can report the validation errors to the user.
+func maxExecutionTime(rawSpec string, ceiling time.Duration) string {
+	spec, err := btypes.NewBatchSpecFromRaw(rawSpec)
+	if err != nil || spec.Spec.ChangesetTemplate.MaxExecutionTime == "" {
+		return ""
+	}
+
+	duration, err := time.ParseDuration(spec.Spec.ChangesetTemplate.MaxExecutionTime)
+	if err != nil || duration <= 0 {
+		return ""
+	}
+	if duration > ceiling {
+		duration = ceiling
+	}
+
+	return duration.String()
+}
+
 const (
 	accessTokenNote  = "batch-spec-execution"
 	accessTokenScope = "user:all"
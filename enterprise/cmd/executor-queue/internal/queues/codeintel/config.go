@@ -1,7 +1,12 @@
 package codeintel
 
 import (
+	"time"
+
+	"github.com/cockroachdb/errors"
+
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/config"
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 )
 
@@ -9,6 +14,63 @@ type Config struct {
 	env.BaseConfig
 
 	Shared *config.SharedConfig
+
+	// HeartbeatInterval is the interval at which executors processing this queue are
+	// expected to send heartbeats. StalledMaxAge must leave room for several missed
+	// heartbeats before a job is considered stalled.
+	HeartbeatInterval time.Duration
+
+	// StalledMaxAge is the maximum duration between heartbeats before a processing job
+	// is moved back into the queued state.
+	StalledMaxAge time.Duration
+
+	// MaxNumResets is the maximum number of times a stalled job is moved back into the
+	// queued state before it is marked as errored.
+	MaxNumResets int
+
+	// CanaryPercentage is the percentage of jobs handed only to executors on the canary channel.
+	CanaryPercentage int
+
+	// ResourceHints maps indexer image names to the resources their index jobs are expected to
+	// need. These replace the default hints of the same image.
+	ResourceHints map[string]apiclient.ResourceHints
+
+	// DependencyFreshness is the maximum age of the uploads of an index job's dependency
+	// repositories for the job to be dequeued ahead of jobs still waiting on a dependency.
+	// Zero disables dependency ordering.
+	DependencyFreshness time.Duration
 }
 
-func (c *Config) Load() {}
+// minHeartbeatsPerStalledMaxAge is the minimum number of heartbeat intervals that must
+// fit within StalledMaxAge.
+const minHeartbeatsPerStalledMaxAge = 5
+
+func (c *Config) Load() {
+	c.HeartbeatInterval = c.GetInterval("EXECUTOR_QUEUE_CODEINTEL_HEARTBEAT_INTERVAL", "1s", "The interval at which executors are expected to send heartbeats.")
+	c.StalledMaxAge = c.GetInterval("EXECUTOR_QUEUE_CODEINTEL_STALLED_MAX_AGE", "25s", "The maximum duration between heartbeats before a job is considered stalled.")
+	c.MaxNumResets = c.GetInt("EXECUTOR_QUEUE_CODEINTEL_MAX_NUM_RESETS", "3", "The maximum number of times a stalled job is requeued before it is marked as errored.")
+	c.CanaryPercentage = c.GetInt("EXECUTOR_QUEUE_CODEINTEL_CANARY_PERCENTAGE", "0", "The percentage of jobs (0-100) routed to executors on the canary channel.")
+	c.DependencyFreshness = c.GetInterval("EXECUTOR_QUEUE_CODEINTEL_DEPENDENCY_FRESHNESS", "24h", "The maximum age of the uploads of an index job's dependency repositories for the job to be dequeued first. Zero disables dependency ordering.")
+
+	resourceHints, err := parseResourceHints(c.GetOptional("EXECUTOR_QUEUE_CODEINTEL_RESOURCE_HINTS", `A JSON object mapping indexer image names to the resources their index jobs need, e.g. {"sourcegraph/scip-java": {"numCpus": 8, "memory": "24G", "diskSpace": "40G"}}.`))
+	if err != nil {
+		c.AddError(errors.Wrap(err, "invalid value for EXECUTOR_QUEUE_CODEINTEL_RESOURCE_HINTS"))
+	}
+	c.ResourceHints = resourceHints
+}
+
+func (c *Config) Validate() error {
+	if c.StalledMaxAge < minHeartbeatsPerStalledMaxAge*c.HeartbeatInterval {
+		c.AddError(errors.Errorf("EXECUTOR_QUEUE_CODEINTEL_STALLED_MAX_AGE must be at least %d times EXECUTOR_QUEUE_CODEINTEL_HEARTBEAT_INTERVAL", minHeartbeatsPerStalledMaxAge))
+	}
+
+	if c.CanaryPercentage < 0 || c.CanaryPercentage > 100 {
+		c.AddError(errors.New("EXECUTOR_QUEUE_CODEINTEL_CANARY_PERCENTAGE must be between 0 and 100"))
+	}
+
+	if c.DependencyFreshness < 0 {
+		c.AddError(errors.New("EXECUTOR_QUEUE_CODEINTEL_DEPENDENCY_FRESHNESS must not be negative"))
+	}
+
+	return c.BaseConfig.Validate()
+}
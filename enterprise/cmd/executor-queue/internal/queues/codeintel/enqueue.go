@@ -0,0 +1,52 @@
+package codeintel
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/schedules"
+	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// newEnqueuer returns an enqueuer that inserts the index job described by a schedule payload,
+// which uses the JSON representation of an index, e.g. {"repositoryId": 42, "commit": "deadbeef",
+// "indexer": "sourcegraph/lsif-go:latest", "indexer_args": ["lsif-go"]}.
+func newEnqueuer(indexStore *store.Store) schedules.Enqueuer {
+	return func(ctx context.Context, tx *basestore.Store, payload json.RawMessage) (int, error) {
+		index, err := indexFromPayload(payload)
+		if err != nil {
+			return 0, err
+		}
+
+		return indexStore.With(tx).InsertIndex(ctx, index)
+	}
+}
+
+// indexFromPayload decodes the index job described by the given schedule payload.
+func indexFromPayload(payload json.RawMessage) (store.Index, error) {
+	var index store.Index
+	if err := json.Unmarshal(payload, &index); err != nil {
+		return store.Index{}, errors.Wrap(err, "malformed payload")
+	}
+	if index.RepositoryID == 0 || index.Commit == "" || index.Indexer == "" {
+		return store.Index{}, errors.New("payload must specify repositoryId, commit, and indexer")
+	}
+
+	// Only the fields describing the job are taken from the payload
+	return store.Index{
+		State:                  "queued",
+		RepositoryID:           index.RepositoryID,
+		Commit:                 index.Commit,
+		Root:                   index.Root,
+		DockerSteps:            index.DockerSteps,
+		LocalSteps:             index.LocalSteps,
+		Indexer:                index.Indexer,
+		IndexerArgs:            index.IndexerArgs,
+		Outfile:                index.Outfile,
+		ExecutorLabels:         index.ExecutorLabels,
+		DependencyRepositories: index.DependencyRepositories,
+	}, nil
+}
@@ -0,0 +1,34 @@
+package codeintel
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+)
+
+func TestIndexFromPayload(t *testing.T) {
+	index, err := indexFromPayload(json.RawMessage(`{"id": 7, "state": "completed", "repositoryId": 42, "commit": "deadbeef", "indexer": "sourcegraph/lsif-go:latest", "indexer_args": ["lsif-go"]}`))
+	if err != nil {
+		t.Fatalf("unexpected error decoding payload: %s", err)
+	}
+
+	expected := store.Index{
+		State:        "queued",
+		RepositoryID: 42,
+		Commit:       "deadbeef",
+		Indexer:      "sourcegraph/lsif-go:latest",
+		IndexerArgs:  []string{"lsif-go"},
+	}
+	if diff := cmp.Diff(expected, index); diff != "" {
+		t.Errorf("unexpected index (-want +got):\n%s", diff)
+	}
+
+	for _, payload := range []string{`[]`, `{}`, `{"repositoryId": 42, "commit": "deadbeef"}`} {
+		if _, err := indexFromPayload(json.RawMessage(payload)); err == nil {
+			t.Errorf("expected an error decoding %s", payload)
+		}
+	}
+}
@@ -3,6 +3,10 @@ package codeintel
 import (
 	"context"
 	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 
 	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
 	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
@@ -11,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
 func QueueOptions(db dbutil.DB, config *Config, observationContext *observation.Context) apiserver.QueueOptions {
@@ -19,7 +24,49 @@ func QueueOptions(db dbutil.DB, config *Config, observationContext *observation.
 	}
 
 	return apiserver.QueueOptions{
-		Store:             store.WorkerutilIndexStore(basestore.NewWithDB(db, sql.TxOptions{}), observationContext),
+		Store:             WorkerStore(db, config, observationContext),
 		RecordTransformer: recordTransformer,
+		FilterConditions:  filterConditions,
+		RecordQueuedAt:    recordQueuedAt,
+		DequeueConditions: dequeueConditions,
+		CanaryPercentage:  config.CanaryPercentage,
+		RetryPolicies:     config.Shared.RetryPolicies(),
+		Enqueue:           newEnqueuer(store.NewWithDB(db, observationContext)),
+	}
+}
+
+// WorkerStore returns the store over the index records of the given database that backs the
+// codeintel queue. Records are read through the lsif_indexes_with_repository_name view, which
+// hides the index records of soft-deleted repositories from counts, dequeues, and listings until
+// the codeintel janitor hard-deletes them.
+func WorkerStore(db dbutil.DB, config *Config, observationContext *observation.Context) dbworkerstore.Store {
+	return store.WorkerutilIndexStoreWithResetOptions(basestore.NewWithDB(db, sql.TxOptions{}), config.StalledMaxAge, config.MaxNumResets, config.DependencyFreshness, config.Shared.DequeueOptions(), observationContext)
+}
+
+// dequeueConditions restricts executors to index jobs whose executor label selector is
+// a subset of the executor's labels. Index jobs without a selector match every executor.
+func dequeueConditions(executorLabels []string) []*sqlf.Query {
+	if executorLabels == nil {
+		executorLabels = []string{}
+	}
+
+	return []*sqlf.Query{sqlf.Sprintf("u.executor_labels <@ %s", pq.Array(executorLabels))}
+}
+
+func recordQueuedAt(record workerutil.Record) time.Time {
+	return record.(store.Index).QueuedAt
+}
+
+// filterConditions converts admin job list filters into conditions over the
+// lsif_indexes_with_repository_name view.
+func filterConditions(filter apiserver.JobFilter) ([]*sqlf.Query, error) {
+	var conds []*sqlf.Query
+	if filter.MinAge != 0 {
+		conds = append(conds, sqlf.Sprintf("u.queued_at <= %s", time.Now().Add(-filter.MinAge)))
+	}
+	if filter.RepositoryName != "" {
+		conds = append(conds, sqlf.Sprintf("u.repository_name = %s", filter.RepositoryName))
 	}
+
+	return conds, nil
 }
@@ -0,0 +1,119 @@
+package codeintel
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/config"
+	store "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/stores/dbstore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+func TestWorkerStoreSoftDeletedRepositories(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	ctx := context.Background()
+
+	indexStore := store.NewWithDB(db, &observation.TestContext)
+	workerStore := WorkerStore(db, &Config{Shared: &config.SharedConfig{}, StalledMaxAge: time.Minute, MaxNumResets: 3}, &observation.TestContext)
+
+	var ids []int
+	for _, repositoryID := range []int{50, 51} {
+		insertRepo(t, db, repositoryID)
+
+		id, err := indexStore.InsertIndex(ctx, store.Index{State: "queued", RepositoryID: repositoryID, Commit: "deadbeef", Indexer: "sourcegraph/lsif-go"})
+		if err != nil {
+			t.Fatalf("unexpected error inserting index: %s", err)
+		}
+		ids = append(ids, id)
+	}
+	assertQueuedCount(t, workerStore, 2)
+
+	// Index records of a soft-deleted repository are neither counted, listed, nor dequeued
+	softDeleteRepo(t, db, 51)
+	assertQueuedCount(t, workerStore, 1)
+
+	records, err := workerStore.List(ctx, dbworkerstore.ListOptions{States: []string{"queued"}})
+	if err != nil {
+		t.Fatalf("unexpected error listing jobs: %s", err)
+	}
+	if len(records) != 1 || records[0].RecordID() != ids[0] {
+		t.Errorf("unexpected listed jobs. want=[%d] have=%v", ids[0], records)
+	}
+	if _, ok, err := workerStore.Get(ctx, ids[1]); err != nil {
+		t.Fatalf("unexpected error getting job: %s", err)
+	} else if ok {
+		t.Errorf("unexpected job %d of soft-deleted repository", ids[1])
+	}
+
+	record, ok, err := workerStore.Dequeue(ctx, "test", dequeueConditions(nil))
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	}
+	if !ok || record.RecordID() != ids[0] {
+		t.Fatalf("unexpected dequeued job. want=%d have=%v", ids[0], record)
+	}
+	if _, ok, err := workerStore.Dequeue(ctx, "test", dequeueConditions(nil)); err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	} else if ok {
+		t.Errorf("unexpected job dequeued from soft-deleted repository")
+	}
+	assertProcessingCount(t, workerStore, 1)
+
+	// A job being processed when its repository is soft-deleted is no longer counted
+	softDeleteRepo(t, db, 50)
+	assertQueuedCount(t, workerStore, 0)
+	assertProcessingCount(t, workerStore, 0)
+
+	// Index records are hard-deleted once the repository's grace period has elapsed
+	deleted, err := indexStore.DeleteIndexesWithoutRepository(ctx, time.Now().Add(store.DeletedRepositoryGracePeriod+time.Minute))
+	if err != nil {
+		t.Fatalf("unexpected error deleting indexes: %s", err)
+	}
+	if deleted[50] != 1 || deleted[51] != 1 {
+		t.Errorf("unexpected deleted indexes: %v", deleted)
+	}
+}
+
+func assertQueuedCount(t *testing.T, workerStore dbworkerstore.Store, expected int) {
+	t.Helper()
+
+	if count, err := workerStore.QueuedCount(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error counting queued jobs: %s", err)
+	} else if count != expected {
+		t.Errorf("unexpected queued count. want=%d have=%d", expected, count)
+	}
+}
+
+func assertProcessingCount(t *testing.T, workerStore dbworkerstore.Store, expected int) {
+	t.Helper()
+
+	if count, err := workerStore.ProcessingCount(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error counting processing jobs: %s", err)
+	} else if count != expected {
+		t.Errorf("unexpected processing count. want=%d have=%d", expected, count)
+	}
+}
+
+func insertRepo(t *testing.T, db *sql.DB, id int) {
+	q := sqlf.Sprintf(`INSERT INTO repo (id, name) VALUES (%s, %s)`, id, fmt.Sprintf("n-%d", id))
+	if _, err := db.ExecContext(context.Background(), q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+		t.Fatalf("unexpected error inserting repository: %s", err)
+	}
+}
+
+func softDeleteRepo(t *testing.T, db *sql.DB, id int) {
+	q := sqlf.Sprintf(`UPDATE repo SET deleted_at = NOW() WHERE id = %s`, id)
+	if _, err := db.ExecContext(context.Background(), q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+		t.Fatalf("unexpected error soft-deleting repository: %s", err)
+	}
+}
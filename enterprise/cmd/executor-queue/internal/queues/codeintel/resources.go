@@ -0,0 +1,76 @@
+package codeintel
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+)
+
+// defaultResourceHints are the resources needed by indexers known to exceed the default size of
+// an executor's virtual machine, keyed by image name.
+var defaultResourceHints = map[string]apiclient.ResourceHints{
+	"sourcegraph/lsif-java": {Memory: "16G"},
+	"sourcegraph/scip-java": {Memory: "16G"},
+}
+
+// parseResourceHints parses a JSON object mapping indexer image names to resource hints.
+func parseResourceHints(value string) (map[string]apiclient.ResourceHints, error) {
+	hints := map[string]apiclient.ResourceHints{}
+	if strings.TrimSpace(value) == "" {
+		return hints, nil
+	}
+
+	if err := json.Unmarshal([]byte(value), &hints); err != nil {
+		return nil, err
+	}
+
+	for image, hint := range hints {
+		if hint.NumCPUs < 0 {
+			return nil, errors.Errorf("negative number of CPUs for %q", image)
+		}
+	}
+
+	return hints, nil
+}
+
+// resourceHints returns the resource hints of the given indexer image, or nil if the indexer
+// has none. Hints configured through the environment replace the default hints of an image.
+func (c *Config) resourceHints(indexer string) *apiclient.ResourceHints {
+	name := imageName(indexer)
+	if name == "" {
+		return nil
+	}
+
+	hints, ok := c.ResourceHints[name]
+	if !ok {
+		if hints, ok = defaultResourceHints[name]; !ok {
+			return nil
+		}
+	}
+	if hints == (apiclient.ResourceHints{}) {
+		return nil
+	}
+
+	return &hints
+}
+
+// imageName returns the name of the given docker image without its registry, tag, or digest,
+// e.g. "sourcegraph/scip-java" for "index.docker.io/sourcegraph/scip-java:latest".
+func imageName(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+
+	// The first path component names a registry if it looks like a hostname
+	if parts := strings.SplitN(image, "/", 2); len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		image = parts[1]
+	}
+
+	return image
+}
@@ -0,0 +1,57 @@
+package codeintel
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+)
+
+func TestResourceHints(t *testing.T) {
+	config := &Config{
+		ResourceHints: map[string]apiclient.ResourceHints{
+			"sourcegraph/lsif-go":   {NumCPUs: 8},
+			"sourcegraph/lsif-java": {},
+		},
+	}
+
+	testCases := []struct {
+		indexer  string
+		expected *apiclient.ResourceHints
+	}{
+		{indexer: "sourcegraph/lsif-go", expected: &apiclient.ResourceHints{NumCPUs: 8}},
+		{indexer: "sourcegraph/lsif-go:latest", expected: &apiclient.ResourceHints{NumCPUs: 8}},
+		{indexer: "sourcegraph/scip-java@sha256:deadbeef", expected: &apiclient.ResourceHints{Memory: "16G"}},
+		{indexer: "index.docker.io/sourcegraph/scip-java:latest", expected: &apiclient.ResourceHints{Memory: "16G"}},
+		{indexer: "localhost:5000/sourcegraph/lsif-go:1.2", expected: &apiclient.ResourceHints{NumCPUs: 8}},
+		{indexer: "sourcegraph/lsif-java", expected: nil},
+		{indexer: "sourcegraph/lsif-node", expected: nil},
+		{indexer: "", expected: nil},
+	}
+
+	for _, testCase := range testCases {
+		if diff := cmp.Diff(testCase.expected, config.resourceHints(testCase.indexer)); diff != "" {
+			t.Errorf("unexpected resource hints for %q (-want +got):\n%s", testCase.indexer, diff)
+		}
+	}
+}
+
+func TestParseResourceHints(t *testing.T) {
+	hints, err := parseResourceHints(`{"sourcegraph/scip-java": {"numCpus": 8, "memory": "24G", "diskSpace": "40G"}}`)
+	if err != nil {
+		t.Fatalf("unexpected error parsing resource hints: %s", err)
+	}
+	expected := map[string]apiclient.ResourceHints{
+		"sourcegraph/scip-java": {NumCPUs: 8, Memory: "24G", DiskSpace: "40G"},
+	}
+	if diff := cmp.Diff(expected, hints); diff != "" {
+		t.Errorf("unexpected resource hints (-want +got):\n%s", diff)
+	}
+
+	for _, value := range []string{`[]`, `{"sourcegraph/scip-java": {"numCpus": -1}}`} {
+		if _, err := parseResourceHints(value); err == nil {
+			t.Errorf("expected an error parsing %q", value)
+		}
+	}
+}
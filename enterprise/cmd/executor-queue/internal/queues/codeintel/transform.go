@@ -58,6 +58,8 @@ func transformRecord(index store.Index, config *Config) (apiclient.Job, error) {
 		Commit:         index.Commit,
 		RepositoryName: index.RepositoryName,
 		DockerSteps:    dockerSteps,
+		ResourceHints:  config.resourceHints(index.Indexer),
+		TraceContext:   index.TraceContext,
 		CliSteps: []apiclient.CliStep{
 			{
 				Commands: []string{
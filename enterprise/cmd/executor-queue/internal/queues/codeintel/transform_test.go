@@ -22,10 +22,11 @@ func TestTransformRecord(t *testing.T) {
 				Root:     "web",
 			},
 		},
-		Root:        "web",
-		Indexer:     "lsif-node",
-		IndexerArgs: []string{"-p", "."},
-		Outfile:     "",
+		Root:         "web",
+		Indexer:      "lsif-node",
+		IndexerArgs:  []string{"-p", "."},
+		Outfile:      "",
+		TraceContext: store.TraceContext{"uber-trace-id": "4bf92f3577b34da6:a3ce929d0e0e4736:0:1"},
 	}
 	config := &Config{
 		Shared: &config.SharedConfig{
@@ -45,6 +46,7 @@ func TestTransformRecord(t *testing.T) {
 		Commit:              "deadbeef",
 		RepositoryName:      "linux",
 		VirtualMachineFiles: nil,
+		TraceContext:        map[string]string{"uber-trace-id": "4bf92f3577b34da6:a3ce929d0e0e4736:0:1"},
 		DockerSteps: []apiclient.DockerStep{
 			{
 				Image:    "alpine",
@@ -162,3 +164,23 @@ func TestTransformRecordWithoutIndexer(t *testing.T) {
 		t.Errorf("unexpected job (-want +got):\n%s", diff)
 	}
 }
+
+func TestTransformRecordResourceHints(t *testing.T) {
+	index := store.Index{
+		ID:             42,
+		Commit:         "deadbeef",
+		RepositoryName: "linux",
+		Indexer:        "sourcegraph/scip-java:latest",
+	}
+	config := &Config{
+		Shared: &config.SharedConfig{FrontendURL: "https://test.io"},
+	}
+
+	job, err := transformRecord(index, config)
+	if err != nil {
+		t.Fatalf("unexpected error transforming record: %s", err)
+	}
+	if diff := cmp.Diff(&apiclient.ResourceHints{Memory: "16G"}, job.ResourceHints); diff != "" {
+		t.Errorf("unexpected resource hints (-want +got):\n%s", diff)
+	}
+}
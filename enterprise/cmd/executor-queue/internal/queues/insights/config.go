@@ -0,0 +1,60 @@
+package insights
+
+import (
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/config"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+type Config struct {
+	env.BaseConfig
+
+	Shared *config.SharedConfig
+
+	// HeartbeatInterval is the interval at which executors processing this queue are
+	// expected to send heartbeats. StalledMaxAge must leave room for several missed
+	// heartbeats before a job is considered stalled.
+	HeartbeatInterval time.Duration
+
+	// StalledMaxAge is the maximum duration between heartbeats before a processing job
+	// is moved back into the queued state.
+	StalledMaxAge time.Duration
+
+	// MaxNumResets is the maximum number of times a stalled job is moved back into the
+	// queued state before it is marked as errored.
+	MaxNumResets int
+
+	// CanaryPercentage is the percentage of jobs handed only to executors on the canary channel.
+	CanaryPercentage int
+
+	// SearchImage is the image in which executors request the search of a job from the frontend.
+	// The image must provide curl.
+	SearchImage string
+}
+
+// minHeartbeatsPerStalledMaxAge is the minimum number of heartbeat intervals that must
+// fit within StalledMaxAge.
+const minHeartbeatsPerStalledMaxAge = 5
+
+func (c *Config) Load() {
+	c.HeartbeatInterval = c.GetInterval("EXECUTOR_QUEUE_INSIGHTS_HEARTBEAT_INTERVAL", "1s", "The interval at which executors are expected to send heartbeats.")
+	c.StalledMaxAge = c.GetInterval("EXECUTOR_QUEUE_INSIGHTS_STALLED_MAX_AGE", "60s", "The maximum duration between heartbeats before a job is considered stalled.")
+	c.MaxNumResets = c.GetInt("EXECUTOR_QUEUE_INSIGHTS_MAX_NUM_RESETS", "3", "The maximum number of times a stalled job is requeued before it is marked as errored.")
+	c.CanaryPercentage = c.GetInt("EXECUTOR_QUEUE_INSIGHTS_CANARY_PERCENTAGE", "0", "The percentage of jobs (0-100) routed to executors on the canary channel.")
+	c.SearchImage = c.Get("EXECUTOR_QUEUE_INSIGHTS_SEARCH_IMAGE", "curlimages/curl:7.78.0", "The image providing curl in which executors request searches from the frontend.")
+}
+
+func (c *Config) Validate() error {
+	if c.StalledMaxAge < minHeartbeatsPerStalledMaxAge*c.HeartbeatInterval {
+		c.AddError(errors.Errorf("EXECUTOR_QUEUE_INSIGHTS_STALLED_MAX_AGE must be at least %d times EXECUTOR_QUEUE_INSIGHTS_HEARTBEAT_INTERVAL", minHeartbeatsPerStalledMaxAge))
+	}
+
+	if c.CanaryPercentage < 0 || c.CanaryPercentage > 100 {
+		c.AddError(errors.New("EXECUTOR_QUEUE_INSIGHTS_CANARY_PERCENTAGE must be between 0 and 100"))
+	}
+
+	return c.BaseConfig.Validate()
+}
@@ -0,0 +1,55 @@
+package insights
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/schedules"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// schedulePayload describes the query runner job enqueued by a schedule.
+type schedulePayload struct {
+	SeriesID    string `json:"seriesId"`
+	SearchQuery string `json:"searchQuery"`
+	Cost        int    `json:"cost"`
+	Priority    int    `json:"priority"`
+}
+
+var _ schedules.Enqueuer = enqueue
+
+// enqueue inserts the query runner job described by a schedule payload, e.g.
+// {"seriesId": "s:087855E6A2444083", "searchQuery": "errorf count:all"}.
+//
+// Scheduled jobs record their results at the time they are processed, so they are processed
+// by the worker rather than by executors.
+func enqueue(ctx context.Context, tx *basestore.Store, payload json.RawMessage) (int, error) {
+	job, err := jobFromPayload(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	return queryrunner.EnqueueJob(ctx, tx, job)
+}
+
+// jobFromPayload decodes the query runner job described by the given schedule payload.
+func jobFromPayload(payload json.RawMessage) (*queryrunner.Job, error) {
+	var p schedulePayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, errors.Wrap(err, "malformed payload")
+	}
+	if p.SeriesID == "" || p.SearchQuery == "" {
+		return nil, errors.New("payload must specify seriesId and searchQuery")
+	}
+
+	return &queryrunner.Job{
+		SeriesID:    p.SeriesID,
+		SearchQuery: p.SearchQuery,
+		Cost:        p.Cost,
+		Priority:    p.Priority,
+		State:       "queued",
+	}, nil
+}
@@ -0,0 +1,50 @@
+package insights
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/keegancsmith/sqlf"
+
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// QueueOptions returns the options for the insights queue, which hands the historical backfill
+// jobs of the code insights query runner to executors. The given insights database receives the
+// series points recorded as jobs complete.
+func QueueOptions(db dbutil.DB, insightsDB dbutil.DB, config *Config, observationContext *observation.Context) apiserver.QueueOptions {
+	recordTransformer := func(ctx context.Context, record workerutil.Record) (apiclient.Job, error) {
+		return transformRecord(record.(*queryrunner.Job), config)
+	}
+
+	return apiserver.QueueOptions{
+		Store:             WorkerStore(db, insightsDB, config, observationContext),
+		RecordTransformer: recordTransformer,
+		DequeueConditions: dequeueConditions,
+		CanaryPercentage:  config.CanaryPercentage,
+		RetryPolicies:     config.Shared.RetryPolicies(),
+		Enqueue:           enqueue,
+	}
+}
+
+// WorkerStore returns the store over the query runner jobs of the given database that backs the
+// insights queue.
+func WorkerStore(db dbutil.DB, insightsDB dbutil.DB, config *Config, observationContext *observation.Context) dbworkerstore.Store {
+	insightsStore := store.New(insightsDB, store.NewInsightPermissionStore(db))
+	return queryrunner.NewExecutorStore(basestore.NewWithDB(db, sql.TxOptions{}), insightsStore, config.StalledMaxAge, config.MaxNumResets, config.Shared.PriorityAgingInterval("insights"), config.Shared.DequeueOptions(), observationContext)
+}
+
+// dequeueConditions restricts executors to historical backfill jobs, and only while the
+// insights.query.worker.backfillOnExecutors site config setting is enabled and the
+// insights.query.worker.paused setting is not. Other jobs are left to the worker.
+func dequeueConditions(executorLabels []string) []*sqlf.Query {
+	return queryrunner.ExecutorDequeueConditions()
+}
@@ -0,0 +1,68 @@
+package insights
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+)
+
+const searchRoute = "/.executors/insights/search"
+
+// searchCommand requests the match counts of the job's search query from the frontend. The
+// response is printed to stdout, from which it is read when the job is marked as complete.
+const searchCommand = `curl --fail --silent --show-error -H 'Content-Type: application/json' --data "$INSIGHTS_SEARCH_PAYLOAD" "$INSIGHTS_SEARCH_URL"`
+
+func transformRecord(job *queryrunner.Job, config *Config) (apiclient.Job, error) {
+	searchURL, err := makeURL(config.Shared.FrontendURL, config.Shared.FrontendUsername, config.Shared.FrontendPassword)
+	if err != nil {
+		return apiclient.Job{}, err
+	}
+
+	redactedSearchURL, err := makeURL(config.Shared.FrontendURL, "USERNAME_REMOVED", "PASSWORD_REMOVED")
+	if err != nil {
+		return apiclient.Job{}, err
+	}
+
+	payload, err := json.Marshal(apiclient.InsightsSearchRequest{Query: job.SearchQuery})
+	if err != nil {
+		return apiclient.Job{}, err
+	}
+
+	return apiclient.Job{
+		ID: job.ID,
+		DockerSteps: []apiclient.DockerStep{
+			{
+				Image:    config.SearchImage,
+				Commands: []string{searchCommand},
+				Env: []string{
+					fmt.Sprintf("INSIGHTS_SEARCH_URL=%s", searchURL),
+					fmt.Sprintf("INSIGHTS_SEARCH_PAYLOAD=%s", payload),
+				},
+			},
+		},
+		RedactedValues: map[string]string{
+			// 🚨 SECURITY: Catch leak of the search endpoint. This is necessary in addition
+			// to the below in case the username or password contains illegal URL characters,
+			// which are then urlencoded and are not replaceable via byte comparison.
+			searchURL: redactedSearchURL,
+
+			// 🚨 SECURITY: Catch uses of fragments pulled from the URL in the command's output.
+			config.Shared.FrontendUsername: "USERNAME_REMOVED",
+			config.Shared.FrontendPassword: "PASSWORD_REMOVED",
+		},
+	}, nil
+}
+
+func makeURL(base, username, password string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+
+	u.User = url.UserPassword(username, password)
+	u.Path = searchRoute
+	return u.String(), nil
+}
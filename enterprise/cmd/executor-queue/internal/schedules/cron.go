@@ -0,0 +1,211 @@
+package schedules
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Cron is a parsed five-field cron expression (minute, hour, day of month, month, and day
+// of week). Expressions are evaluated in UTC.
+type Cron struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+
+	// dayOfMonthStar and dayOfWeekStar are true if the corresponding field starts with an
+	// asterisk. When both day fields are restricted, a day matches if either field matches.
+	dayOfMonthStar, dayOfWeekStar bool
+}
+
+// cronMacros are the supported shorthands for common expressions.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	minuteField     = cronField{name: "minute", min: 0, max: 59}
+	hourField       = cronField{name: "hour", min: 0, max: 23}
+	dayOfMonthField = cronField{name: "day of month", min: 1, max: 31}
+	monthField      = cronField{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	dayOfWeekField  = cronField{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// ParseCron parses the given cron expression. Each field accepts an asterisk, values, ranges
+// (1-5), steps (*/15 or 0-30/10), and comma-separated lists thereof. Months and days of the
+// week may be given by their three-letter English names, and both 0 and 7 denote Sunday. The
+// macros @yearly, @annually, @monthly, @weekly, @daily, and @hourly are also accepted.
+func ParseCron(expression string) (*Cron, error) {
+	if macro, ok := cronMacros[strings.ToLower(strings.TrimSpace(expression))]; ok {
+		expression = macro
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("expected 5 fields, found %d", len(fields))
+	}
+
+	c := &Cron{
+		dayOfMonthStar: strings.HasPrefix(fields[2], "*"),
+		dayOfWeekStar:  strings.HasPrefix(fields[4], "*"),
+	}
+
+	for i, target := range []struct {
+		field cronField
+		bits  *uint64
+	}{
+		{minuteField, &c.minute},
+		{hourField, &c.hour},
+		{dayOfMonthField, &c.dayOfMonth},
+		{monthField, &c.month},
+		{dayOfWeekField, &c.dayOfWeek},
+	} {
+		bits, err := target.field.parse(fields[i])
+		if err != nil {
+			return nil, err
+		}
+		*target.bits = bits
+	}
+
+	// Sunday may be written as 7
+	if c.dayOfWeek&(1<<7) != 0 {
+		c.dayOfWeek |= 1
+	}
+
+	return c, nil
+}
+
+// parse returns the set of values matched by the given field as a bitset.
+func (f cronField) parse(value string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, errors.Errorf("invalid step in %s field %q", f.name, part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		var lo, hi int
+		if rangePart == "*" {
+			lo, hi = f.min, f.max
+		} else if i := strings.Index(rangePart, "-"); i >= 0 {
+			var err error
+			if lo, err = f.parseValue(rangePart[:i]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.parseValue(rangePart[i+1:]); err != nil {
+				return 0, err
+			}
+			if hi < lo {
+				return 0, errors.Errorf("invalid range in %s field %q", f.name, part)
+			}
+		} else {
+			var err error
+			if lo, err = f.parseValue(rangePart); err != nil {
+				return 0, err
+			}
+
+			// A single value with a step (e.g. 5/15) extends to the end of the field
+			hi = lo
+			if step > 1 {
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+func (f cronField) parseValue(value string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(value, name) {
+			return f.min + i, nil
+		}
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < f.min || n > f.max {
+		return 0, errors.Errorf("invalid value in %s field %q", f.name, value)
+	}
+
+	return n, nil
+}
+
+// maxCronSearchYears bounds the search for the next matching time of expressions that can
+// never match (e.g. February 30th).
+const maxCronSearchYears = 5
+
+// Next returns the first time strictly after the given time matched by the expression. The
+// zero time is returned if the expression does not match any time in the next few years.
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + maxCronSearchYears
+
+	for t.Year() <= limit {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (c *Cron) matchesDay(t time.Time) bool {
+	dayOfMonth := c.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := c.dayOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if c.dayOfMonthStar || c.dayOfWeekStar {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
+
+// NextRunAt parses the given cron expression and returns the first time after the given time
+// matched by it. An error is returned if the expression is invalid or never matches.
+func NextRunAt(expression string, after time.Time) (time.Time, error) {
+	c, err := ParseCron(expression)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := c.Next(after)
+	if next.IsZero() {
+		return time.Time{}, errors.Errorf("%q does not match any time in the next %d years", expression, maxCronSearchYears)
+	}
+
+	return next, nil
+}
@@ -0,0 +1,65 @@
+package schedules
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	// Friday
+	now := time.Date(2021, 8, 27, 10, 30, 15, 0, time.UTC)
+
+	testCases := []struct {
+		expression string
+		expected   time.Time
+	}{
+		{"* * * * *", time.Date(2021, 8, 27, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2021, 8, 27, 10, 45, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2021, 8, 28, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2021, 8, 27, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * mon", time.Date(2021, 8, 30, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2021, 8, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 5", time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2021, 8, 27, 11, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, testCase := range testCases {
+		cron, err := ParseCron(testCase.expression)
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %s", testCase.expression, err)
+		}
+
+		if next := cron.Next(now); !next.Equal(testCase.expected) {
+			t.Errorf("unexpected next time for %q. want=%s have=%s", testCase.expression, testCase.expected, next)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+		"@every 5m",
+	} {
+		if _, err := ParseCron(expression); err == nil {
+			t.Errorf("expected an error parsing %q", expression)
+		}
+	}
+}
+
+func TestNextRunAtNeverMatches(t *testing.T) {
+	if _, err := NextRunAt("0 0 30 feb *", time.Now()); err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,120 @@
+package schedules
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// Enqueuer inserts a job described by the given payload into a queue through the given store,
+// which is within the transaction that records the run, and returns the identifier of the job.
+type Enqueuer func(ctx context.Context, tx *basestore.Store, payload json.RawMessage) (int, error)
+
+// maxSchedulesPerRun is the maximum number of due schedules processed by a single run of the
+// scheduler. Remaining schedules are processed on the following runs.
+const maxSchedulesPerRun = 100
+
+type scheduler struct {
+	store     *Store
+	enqueuers map[string]Enqueuer
+	isLeader  func() bool
+	now       func() time.Time
+}
+
+var _ goroutine.Handler = &scheduler{}
+var _ goroutine.ErrorHandler = &scheduler{}
+
+// NewScheduler returns a background routine that periodically enqueues a job for each due
+// schedule using the enqueuer of the schedule's queue. Only the replica for which isLeader
+// returns true enqueues jobs.
+//
+// Runs missed while the scheduler was not running (e.g. during a deploy) are not made up for:
+// a due schedule enqueues a single job and next runs at the first matching time from now.
+func NewScheduler(store *Store, enqueuers map[string]Enqueuer, isLeader func() bool, interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, newScheduler(store, enqueuers, isLeader, time.Now))
+}
+
+func newScheduler(store *Store, enqueuers map[string]Enqueuer, isLeader func() bool, now func() time.Time) *scheduler {
+	if isLeader == nil {
+		isLeader = func() bool { return true }
+	}
+
+	return &scheduler{
+		store:     store,
+		enqueuers: enqueuers,
+		isLeader:  isLeader,
+		now:       now,
+	}
+}
+
+func (h *scheduler) Handle(ctx context.Context) (err error) {
+	if !h.isLeader() {
+		return nil
+	}
+
+	tx, err := h.store.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	now := h.now()
+
+	schedules, err := tx.Due(ctx, now, maxSchedulesPerRun)
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range schedules {
+		nextRunAt, err := NextRunAt(schedule.Schedule, now)
+		if err != nil {
+			// Schedules are validated on creation, so this is not expected to happen
+			log15.Error("Skipping scheduled job with an invalid schedule", "id", schedule.ID, "schedule", schedule.Schedule, "error", err)
+			continue
+		}
+
+		var jobID *int
+		var errorMessage *string
+		if id, err := h.enqueue(ctx, tx, schedule); err != nil {
+			message := err.Error()
+			errorMessage = &message
+			log15.Error("Failed to enqueue scheduled job", "id", schedule.ID, "queue", schedule.QueueName, "error", err)
+		} else {
+			jobID = &id
+			log15.Debug("Enqueued scheduled job", "id", schedule.ID, "queue", schedule.QueueName, "jobID", id)
+		}
+
+		if err := tx.MarkRun(ctx, schedule.ID, now, nextRunAt, jobID, errorMessage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enqueue enqueues the job of the given schedule within a savepoint, so that a failure to
+// enqueue is recorded on the schedule without aborting the runs of other schedules.
+func (h *scheduler) enqueue(ctx context.Context, tx *Store, schedule Schedule) (_ int, err error) {
+	enqueue, ok := h.enqueuers[schedule.QueueName]
+	if !ok {
+		return 0, errors.Errorf("queue %q does not support scheduled jobs", schedule.QueueName)
+	}
+
+	savepoint, err := tx.Store.Transact(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = savepoint.Done(err) }()
+
+	return enqueue(ctx, savepoint, schedule.Payload)
+}
+
+func (h *scheduler) HandleError(err error) {
+	log15.Error("Failed to run scheduled jobs", "error", err)
+}
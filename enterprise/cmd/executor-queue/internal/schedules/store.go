@@ -0,0 +1,206 @@
+package schedules
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// Schedule enqueues a job into an executor queue each time its cron expression matches.
+type Schedule struct {
+	ID        int             `json:"id"`
+	QueueName string          `json:"queueName"`
+	Schedule  string          `json:"schedule"`
+	Payload   json.RawMessage `json:"payload"`
+	Paused    bool            `json:"paused"`
+	NextRunAt time.Time       `json:"nextRunAt"`
+	LastRunAt *time.Time      `json:"lastRunAt"`
+	LastJobID *int            `json:"lastJobId"`
+	LastError *string         `json:"lastError"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// Store reads and writes the executor_scheduled_jobs table.
+type Store struct {
+	*basestore.Store
+}
+
+// NewStore creates a new schedule store backed by the given database.
+func NewStore(db dbutil.DB) *Store {
+	return &Store{Store: basestore.NewWithDB(db, sql.TxOptions{})}
+}
+
+func (s *Store) Transact(ctx context.Context) (*Store, error) {
+	txBase, err := s.Store.Transact(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{Store: txBase}, nil
+}
+
+// Create inserts the given schedule and returns it as stored. The identifier and timestamps of
+// the given schedule are ignored.
+func (s *Store) Create(ctx context.Context, schedule Schedule) (Schedule, error) {
+	payload := schedule.Payload
+	if len(payload) == 0 {
+		payload = json.RawMessage(`{}`)
+	}
+
+	schedules, err := scanSchedules(s.Query(ctx, sqlf.Sprintf(createQuery, schedule.QueueName, schedule.Schedule, string(payload), schedule.Paused, schedule.NextRunAt)))
+	if err != nil || len(schedules) == 0 {
+		return Schedule{}, err
+	}
+
+	return schedules[0], nil
+}
+
+const createQuery = `
+-- source: enterprise/cmd/executor-queue/internal/schedules/store.go:Create
+INSERT INTO executor_scheduled_jobs (queue_name, schedule, payload, paused, next_run_at)
+VALUES (%s, %s, %s, %s, %s)
+RETURNING ` + scheduleColumns
+
+// Get returns the schedule with the given identifier, and a false-valued flag if it does not exist.
+func (s *Store) Get(ctx context.Context, id int) (Schedule, bool, error) {
+	schedules, err := scanSchedules(s.Query(ctx, sqlf.Sprintf(getQuery, id)))
+	if err != nil || len(schedules) == 0 {
+		return Schedule{}, false, err
+	}
+
+	return schedules[0], true, nil
+}
+
+const getQuery = `
+-- source: enterprise/cmd/executor-queue/internal/schedules/store.go:Get
+SELECT ` + scheduleColumns + ` FROM executor_scheduled_jobs WHERE id = %s
+`
+
+// ListOptions filters the schedules returned from List.
+type ListOptions struct {
+	// QueueName, if set, restricts the listing to schedules of the given queue.
+	QueueName string
+}
+
+// List returns the schedules matching the given options ordered by identifier.
+func (s *Store) List(ctx context.Context, opts ListOptions) ([]Schedule, error) {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if opts.QueueName != "" {
+		conds = append(conds, sqlf.Sprintf("queue_name = %s", opts.QueueName))
+	}
+
+	return scanSchedules(s.Query(ctx, sqlf.Sprintf(listQuery, sqlf.Join(conds, " AND "))))
+}
+
+const listQuery = `
+-- source: enterprise/cmd/executor-queue/internal/schedules/store.go:List
+SELECT ` + scheduleColumns + ` FROM executor_scheduled_jobs WHERE %s ORDER BY id
+`
+
+// SetPaused pauses or resumes the schedule with the given identifier. Resumed schedules next run
+// at the given time, so that runs missed while paused are skipped. A false-valued flag is returned
+// if the schedule does not exist.
+func (s *Store) SetPaused(ctx context.Context, id int, paused bool, nextRunAt time.Time) (bool, error) {
+	_, ok, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(setPausedQuery, paused, paused, nextRunAt, id)))
+	return ok, err
+}
+
+const setPausedQuery = `
+-- source: enterprise/cmd/executor-queue/internal/schedules/store.go:SetPaused
+UPDATE executor_scheduled_jobs
+SET
+	paused = %s,
+	next_run_at = CASE WHEN %s THEN next_run_at ELSE %s END,
+	updated_at = NOW()
+WHERE id = %s
+RETURNING id
+`
+
+// Delete removes the schedule with the given identifier. A false-valued flag is returned if the
+// schedule does not exist. Jobs already enqueued by the schedule are not affected.
+func (s *Store) Delete(ctx context.Context, id int) (bool, error) {
+	_, ok, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(deleteQuery, id)))
+	return ok, err
+}
+
+const deleteQuery = `
+-- source: enterprise/cmd/executor-queue/internal/schedules/store.go:Delete
+DELETE FROM executor_scheduled_jobs WHERE id = %s RETURNING id
+`
+
+// Due returns up to limit unpaused schedules whose next run is at or before the given time. The
+// schedules are locked until the end of the current transaction, and schedules locked by another
+// transaction are skipped.
+func (s *Store) Due(ctx context.Context, now time.Time, limit int) ([]Schedule, error) {
+	return scanSchedules(s.Query(ctx, sqlf.Sprintf(dueQuery, now, limit)))
+}
+
+const dueQuery = `
+-- source: enterprise/cmd/executor-queue/internal/schedules/store.go:Due
+SELECT ` + scheduleColumns + `
+FROM executor_scheduled_jobs
+WHERE NOT paused AND next_run_at <= %s
+ORDER BY next_run_at
+LIMIT %s
+FOR UPDATE SKIP LOCKED
+`
+
+// MarkRun records a run of the schedule with the given identifier at the given time. The job
+// identifier is recorded if the run succeeded, and the error message otherwise.
+func (s *Store) MarkRun(ctx context.Context, id int, runAt, nextRunAt time.Time, jobID *int, errorMessage *string) error {
+	return s.Exec(ctx, sqlf.Sprintf(markRunQuery, runAt, nextRunAt, jobID, jobID, errorMessage, id))
+}
+
+const markRunQuery = `
+-- source: enterprise/cmd/executor-queue/internal/schedules/store.go:MarkRun
+UPDATE executor_scheduled_jobs
+SET
+	last_run_at = %s,
+	next_run_at = %s,
+	last_job_id = COALESCE(%s, last_job_id),
+	last_error = CASE WHEN %s::integer IS NULL THEN %s ELSE NULL END,
+	updated_at = NOW()
+WHERE id = %s
+`
+
+const scheduleColumns = `id, queue_name, schedule, payload, paused, next_run_at, last_run_at, last_job_id, last_error, created_at, updated_at`
+
+func scanSchedules(rows *sql.Rows, queryErr error) (_ []Schedule, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	var schedules []Schedule
+	for rows.Next() {
+		var schedule Schedule
+		var payload []byte
+		if err := rows.Scan(
+			&schedule.ID,
+			&schedule.QueueName,
+			&schedule.Schedule,
+			&payload,
+			&schedule.Paused,
+			&schedule.NextRunAt,
+			&schedule.LastRunAt,
+			&schedule.LastJobID,
+			&schedule.LastError,
+			&schedule.CreatedAt,
+			&schedule.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		schedule.Payload = payload
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
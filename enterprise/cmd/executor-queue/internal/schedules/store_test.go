@@ -0,0 +1,137 @@
+package schedules
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+)
+
+func TestStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := NewStore(db)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	created, err := store.Create(ctx, Schedule{QueueName: "codeintel", Schedule: "@daily", Payload: json.RawMessage(`{"repositoryId": 42}`), NextRunAt: now})
+	if err != nil {
+		t.Fatalf("unexpected error creating schedule: %s", err)
+	}
+	if _, err := store.Create(ctx, Schedule{QueueName: "insights", Schedule: "@hourly", NextRunAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("unexpected error creating schedule: %s", err)
+	}
+
+	schedules, err := store.List(ctx, ListOptions{QueueName: "codeintel"})
+	if err != nil {
+		t.Fatalf("unexpected error listing schedules: %s", err)
+	}
+	if len(schedules) != 1 || schedules[0].ID != created.ID {
+		t.Fatalf("unexpected schedules: %+v", schedules)
+	}
+
+	tx, err := store.Transact(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error opening transaction: %s", err)
+	}
+	due, err := tx.Due(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("unexpected error fetching due schedules: %s", err)
+	}
+	if len(due) != 1 || due[0].ID != created.ID {
+		t.Fatalf("unexpected due schedules: %+v", due)
+	}
+	jobID := 7
+	if err := tx.MarkRun(ctx, created.ID, now, now.Add(24*time.Hour), &jobID, nil); err != nil {
+		t.Fatalf("unexpected error marking run: %s", err)
+	}
+	if err := tx.Done(nil); err != nil {
+		t.Fatalf("unexpected error committing transaction: %s", err)
+	}
+
+	schedule, ok, err := store.Get(ctx, created.ID)
+	if err != nil || !ok {
+		t.Fatalf("unexpected error getting schedule: %v", err)
+	}
+	if schedule.LastJobID == nil || *schedule.LastJobID != 7 || !schedule.NextRunAt.Equal(now.Add(24*time.Hour)) {
+		t.Errorf("unexpected schedule after run: %+v", schedule)
+	}
+
+	if ok, err := store.SetPaused(ctx, created.ID, true, time.Time{}); err != nil || !ok {
+		t.Fatalf("unexpected error pausing schedule: %v", err)
+	}
+	if due, err := store.Due(ctx, now.Add(48*time.Hour), 10); err != nil {
+		t.Fatalf("unexpected error fetching due schedules: %s", err)
+	} else if len(due) != 1 || due[0].QueueName != "insights" {
+		t.Errorf("unexpected due schedules: %+v", due)
+	}
+
+	if ok, err := store.Delete(ctx, created.ID); err != nil || !ok {
+		t.Fatalf("unexpected error deleting schedule: %v", err)
+	}
+	if ok, err := store.Delete(ctx, created.ID); err != nil || ok {
+		t.Fatalf("expected schedule to be deleted: %v", err)
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := NewStore(db)
+	ctx := context.Background()
+	now := time.Date(2021, 8, 27, 10, 30, 0, 0, time.UTC)
+
+	for _, queueName := range []string{"test", "broken", "unknown"} {
+		if _, err := store.Create(ctx, Schedule{QueueName: queueName, Schedule: "*/15 * * * *", NextRunAt: now.Add(-time.Hour)}); err != nil {
+			t.Fatalf("unexpected error creating schedule: %s", err)
+		}
+	}
+
+	var payloads []string
+	enqueuers := map[string]Enqueuer{
+		"test": func(ctx context.Context, tx *basestore.Store, payload json.RawMessage) (int, error) {
+			payloads = append(payloads, string(payload))
+			return 42, nil
+		},
+		"broken": func(ctx context.Context, tx *basestore.Store, payload json.RawMessage) (int, error) {
+			return 0, errors.New("oops")
+		},
+	}
+
+	if err := newScheduler(store, enqueuers, nil, func() time.Time { return now }).Handle(ctx); err != nil {
+		t.Fatalf("unexpected error running scheduler: %s", err)
+	}
+	if len(payloads) != 1 {
+		t.Errorf("unexpected number of enqueued jobs. want=%d have=%d", 1, len(payloads))
+	}
+
+	schedules, err := store.List(ctx, ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing schedules: %s", err)
+	}
+	for _, schedule := range schedules {
+		if !schedule.NextRunAt.Equal(now.Add(15 * time.Minute)) {
+			t.Errorf("unexpected next run of %s schedule: %s", schedule.QueueName, schedule.NextRunAt)
+		}
+
+		switch schedule.QueueName {
+		case "test":
+			if schedule.LastJobID == nil || *schedule.LastJobID != 42 || schedule.LastError != nil {
+				t.Errorf("unexpected test schedule: %+v", schedule)
+			}
+		default:
+			if schedule.LastJobID != nil || schedule.LastError == nil {
+				t.Errorf("unexpected %s schedule: %+v", schedule.QueueName, schedule)
+			}
+		}
+	}
+}
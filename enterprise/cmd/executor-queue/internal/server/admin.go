@@ -0,0 +1,198 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/auditlog"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// JobFilter describes the filters accepted by the admin job listing endpoint.
+type JobFilter struct {
+	// State, if set, restricts the listing to jobs in the given state.
+	State string
+
+	// MinAge, if non-zero, restricts the listing to jobs enqueued at least this long ago.
+	MinAge time.Duration
+
+	// RepositoryName, if set, restricts the listing to jobs operating on the given repository.
+	RepositoryName string
+}
+
+// ErrUnsupportedFilter is returned by a queue's FilterConditions hook when the queue
+// cannot honor one of the supplied filters.
+var ErrUnsupportedFilter = errors.New("unsupported filter")
+
+// maxListLimit is the maximum number of jobs returned from a single list request.
+const maxListLimit = 100
+
+// listJobs returns the jobs matching the given filter.
+func (h *handler) listJobs(ctx context.Context, filter JobFilter, limit, offset int) ([]workerutil.Record, error) {
+	var conditions []*sqlf.Query
+	if h.FilterConditions != nil {
+		var err error
+		if conditions, err = h.FilterConditions(filter); err != nil {
+			return nil, err
+		}
+	} else if filter.MinAge != 0 || filter.RepositoryName != "" {
+		return nil, ErrUnsupportedFilter
+	}
+
+	if limit <= 0 || limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	var states []string
+	if filter.State != "" {
+		states = append(states, filter.State)
+	}
+
+	return h.Store.List(ctx, store.ListOptions{
+		States:     states,
+		Conditions: conditions,
+		Limit:      limit,
+		Offset:     offset,
+	})
+}
+
+// getJob returns the job with the given identifier, including its execution logs.
+func (h *handler) getJob(ctx context.Context, jobID int) (workerutil.Record, error) {
+	record, exists, err := h.Store.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrUnknownJob
+	}
+
+	return record, nil
+}
+
+// requeueJob moves the job with the given identifier back into the queued state so that
+// it can be immediately dequeued by another executor.
+func (h *handler) requeueJob(ctx context.Context, actor string, jobID int) error {
+	if _, err := h.getJob(ctx, jobID); err != nil {
+		return err
+	}
+
+	if err := h.Store.Requeue(ctx, jobID, time.Now()); err != nil {
+		return err
+	}
+
+	h.audit(ctx, jobID, auditlog.OperationRequeue, actor, "")
+	return nil
+}
+
+// deleteJob removes the job with the given identifier from the queue.
+func (h *handler) deleteJob(ctx context.Context, actor string, jobID int) error {
+	ok, err := h.Store.Delete(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUnknownJob
+	}
+
+	h.audit(ctx, jobID, auditlog.OperationDelete, actor, "")
+	h.deleteCheckpoint(ctx, jobID)
+	h.deleteJobRegion(ctx, jobID)
+	return nil
+}
+
+// QueueStats summarizes the state of a queue for the admin queue listing endpoint.
+type QueueStats struct {
+	Name   string `json:"name"`
+	Paused bool   `json:"paused"`
+
+	// Queued is the number of jobs that can be dequeued, including errored jobs that will be
+	// retried.
+	Queued int `json:"queued"`
+
+	// OldestQueuedAgeSeconds is the time since the oldest job in the queued state was enqueued.
+	// It is omitted for empty queues and for queues without a RecordQueuedAt hook.
+	OldestQueuedAgeSeconds *float64 `json:"oldestQueuedAgeSeconds,omitempty"`
+
+	// Processing is the number of jobs currently being processed by executors.
+	Processing int `json:"processing"`
+
+	// MaxProcessing is the concurrency limit of the queue. It is omitted for unrestricted queues.
+	MaxProcessing int `json:"maxProcessing,omitempty"`
+}
+
+// queueStats returns the depth and age statistics of this queue.
+func (h *handler) queueStats(ctx context.Context) (QueueStats, error) {
+	stats := QueueStats{
+		Name:          h.queueName,
+		Paused:        h.pausedQueues.IsPaused(h.queueName),
+		MaxProcessing: h.concurrencyLimits.MaxProcessing(h.queueName),
+	}
+
+	queued, err := h.Store.QueuedCount(ctx, nil)
+	if err != nil {
+		return QueueStats{}, err
+	}
+	stats.Queued = queued
+
+	processing, err := h.Store.ProcessingCount(ctx, nil)
+	if err != nil {
+		return QueueStats{}, err
+	}
+	stats.Processing = processing
+
+	if h.RecordQueuedAt != nil {
+		records, err := h.Store.List(ctx, store.ListOptions{States: []string{"queued"}, Limit: 1})
+		if err != nil {
+			return QueueStats{}, err
+		}
+		if len(records) > 0 {
+			age := time.Since(h.RecordQueuedAt(records[0])).Seconds()
+			stats.OldestQueuedAgeSeconds = &age
+		}
+	}
+
+	return stats, nil
+}
+
+// setConcurrencyLimit overrides the concurrency limit of this queue. A limit of zero lifts the
+// queue's limit, including one configured at startup.
+func (h *handler) setConcurrencyLimit(ctx context.Context, store ConcurrencyLimitStore, maxProcessing int) error {
+	if err := store.Set(ctx, h.queueName, maxProcessing); err != nil {
+		return err
+	}
+
+	h.concurrencyLimits.setOverride(h.queueName, maxProcessing)
+	return nil
+}
+
+// resetConcurrencyLimit reverts the concurrency limit of this queue to the limit configured at
+// startup.
+func (h *handler) resetConcurrencyLimit(ctx context.Context, store ConcurrencyLimitStore) error {
+	if _, err := store.Delete(ctx, h.queueName); err != nil {
+		return err
+	}
+
+	h.concurrencyLimits.clearOverride(h.queueName)
+	return nil
+}
+
+// drainQueue marks every job in the queued state as failed so that it is never handed out to an
+// executor. Jobs that are already being processed are not affected. The identifiers of the
+// failed jobs are returned.
+func (h *handler) drainQueue(ctx context.Context, actor string) ([]int, error) {
+	ids, err := h.Store.MarkQueuedFailed(ctx, nil, fmt.Sprintf("job was drained from the queue by %s", actor))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		h.audit(ctx, id, auditlog.OperationMarkFailed, actor, "drained")
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,476 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/auditlog"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/schedules"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestListJobs(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.ListFunc.SetDefaultReturn([]workerutil.Record{testRecord{ID: 42}, testRecord{ID: 43}}, nil)
+
+	filterConditions := func(filter JobFilter) ([]*sqlf.Query, error) {
+		if filter.RepositoryName != "" {
+			return []*sqlf.Query{sqlf.Sprintf("repository_name = %s", filter.RepositoryName)}, nil
+		}
+		return nil, nil
+	}
+
+	handler := newHandler(QueueOptions{Store: store, FilterConditions: filterConditions})
+	records, err := handler.listJobs(context.Background(), JobFilter{State: "queued", RepositoryName: "github.com/test/test"}, 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error listing jobs: %s", err)
+	}
+	if diff := cmp.Diff([]workerutil.Record{testRecord{ID: 42}, testRecord{ID: 43}}, records); diff != "" {
+		t.Errorf("unexpected records (-want +got):\n%s", diff)
+	}
+
+	if value := len(store.ListFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to List. want=%d have=%d", 1, value)
+	}
+	options := store.ListFunc.History()[0].Arg1
+	if diff := cmp.Diff([]string{"queued"}, options.States); diff != "" {
+		t.Errorf("unexpected states (-want +got):\n%s", diff)
+	}
+	if len(options.Conditions) != 1 {
+		t.Errorf("unexpected number of conditions. want=%d have=%d", 1, len(options.Conditions))
+	}
+	if options.Limit != maxListLimit {
+		t.Errorf("unexpected limit. want=%d have=%d", maxListLimit, options.Limit)
+	}
+	if options.Offset != 10 {
+		t.Errorf("unexpected offset. want=%d have=%d", 10, options.Offset)
+	}
+}
+
+func TestListJobsUnsupportedFilter(t *testing.T) {
+	handler := newHandler(QueueOptions{Store: workerstoremocks.NewMockStore()})
+	if _, err := handler.listJobs(context.Background(), JobFilter{MinAge: time.Hour}, 0, 0); err != ErrUnsupportedFilter {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrUnsupportedFilter, err)
+	}
+}
+
+func TestGetJobUnknownJob(t *testing.T) {
+	handler := newHandler(QueueOptions{Store: workerstoremocks.NewMockStore()})
+	if _, err := handler.getJob(context.Background(), 42); err != ErrUnknownJob {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrUnknownJob, err)
+	}
+}
+
+func TestRequeueJob(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.GetFunc.SetDefaultReturn(testRecord{ID: 42}, true, nil)
+
+	handler := newHandler(QueueOptions{Store: store})
+	if err := handler.requeueJob(context.Background(), "admin:admin", 42); err != nil {
+		t.Fatalf("unexpected error requeueing job: %s", err)
+	}
+
+	if value := len(store.RequeueFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to Requeue. want=%d have=%d", 1, value)
+	}
+	if call := store.RequeueFunc.History()[0]; call.Arg1 != 42 {
+		t.Errorf("unexpected job identifier. want=%d have=%d", 42, call.Arg1)
+	}
+}
+
+func TestRequeueJobAudited(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.GetFunc.SetDefaultReturn(testRecord{ID: 42}, true, nil)
+	auditLogStore := NewMockAuditLogStore()
+
+	handler := newHandler(QueueOptions{Store: store})
+	handler.queueName = "test"
+	handler.auditLogStore = auditLogStore
+
+	if err := handler.requeueJob(context.Background(), "admin:admin", 42); err != nil {
+		t.Fatalf("unexpected error requeueing job: %s", err)
+	}
+
+	if value := len(auditLogStore.AppendFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to Append. want=%d have=%d", 1, value)
+	}
+	expectedEntry := auditlog.Entry{QueueName: "test", JobID: 42, Operation: auditlog.OperationRequeue, Actor: "admin:admin"}
+	if diff := cmp.Diff(expectedEntry, auditLogStore.AppendFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected audit log entry (-want +got):\n%s", diff)
+	}
+}
+
+func TestRequeueJobUnknownJob(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+
+	handler := newHandler(QueueOptions{Store: store})
+	if err := handler.requeueJob(context.Background(), "admin:admin", 42); err != ErrUnknownJob {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrUnknownJob, err)
+	}
+	if value := len(store.RequeueFunc.History()); value != 0 {
+		t.Fatalf("unexpected number of calls to Requeue. want=%d have=%d", 0, value)
+	}
+}
+
+func TestDeleteJobUnknownJob(t *testing.T) {
+	handler := newHandler(QueueOptions{Store: workerstoremocks.NewMockStore()})
+	if err := handler.deleteJob(context.Background(), "admin:admin", 42); err != ErrUnknownJob {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrUnknownJob, err)
+	}
+}
+
+func TestDrainQueue(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.MarkQueuedFailedFunc.SetDefaultReturn([]int{42, 43}, nil)
+	auditLogStore := NewMockAuditLogStore()
+
+	handler := newHandler(QueueOptions{Store: store})
+	handler.queueName = "test"
+	handler.auditLogStore = auditLogStore
+
+	ids, err := handler.drainQueue(context.Background(), "admin:admin")
+	if err != nil {
+		t.Fatalf("unexpected error draining queue: %s", err)
+	}
+	if diff := cmp.Diff([]int{42, 43}, ids); diff != "" {
+		t.Errorf("unexpected job identifiers (-want +got):\n%s", diff)
+	}
+
+	if value := len(store.MarkQueuedFailedFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to MarkQueuedFailed. want=%d have=%d", 1, value)
+	}
+	if call := store.MarkQueuedFailedFunc.History()[0]; len(call.Arg1) != 0 || !strings.Contains(call.Arg2, "admin:admin") {
+		t.Errorf("unexpected arguments to MarkQueuedFailed: %v %q", call.Arg1, call.Arg2)
+	}
+
+	var auditedIDs []int
+	for _, call := range auditLogStore.AppendFunc.History() {
+		if call.Arg1.Operation != auditlog.OperationMarkFailed {
+			t.Errorf("unexpected audit log operation. want=%q have=%q", auditlog.OperationMarkFailed, call.Arg1.Operation)
+		}
+		auditedIDs = append(auditedIDs, call.Arg1.JobID)
+	}
+	if diff := cmp.Diff([]int{42, 43}, auditedIDs); diff != "" {
+		t.Errorf("unexpected audited job identifiers (-want +got):\n%s", diff)
+	}
+}
+
+func TestListQueues(t *testing.T) {
+	enqueuedAt := time.Now().Add(-time.Hour)
+
+	codeintelStore := workerstoremocks.NewMockStore()
+	codeintelStore.QueuedCountFunc.SetDefaultReturn(3, nil)
+	codeintelStore.ProcessingCountFunc.SetDefaultReturn(2, nil)
+	codeintelStore.ListFunc.SetDefaultReturn([]workerutil.Record{testRecord{ID: 42}}, nil)
+	batchesStore := workerstoremocks.NewMockStore()
+
+	pausedQueues := NewPausedQueues()
+	pausedQueues.Set([]string{"batches"})
+
+	concurrencyLimits := NewConcurrencyLimits(map[string]int{"codeintel": 5})
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{AdminUsername: "admin", AdminPassword: "hunter2", PausedQueues: pausedQueues, ConcurrencyLimits: concurrencyLimits}, map[string]QueueOptions{
+		"codeintel": {Store: codeintelStore, RecordQueuedAt: func(record workerutil.Record) time.Time { return enqueuedAt }},
+		"batches":   {Store: batchesStore},
+	}, nil)(router)
+
+	req := httptest.NewRequest("GET", "/admin/queues", nil)
+	req.SetBasicAuth("admin", "hunter2")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code. want=%d have=%d", http.StatusOK, w.Code)
+	}
+
+	var stats []QueueStats
+	if err := json.NewDecoder(w.Body).Decode(&stats); err != nil {
+		t.Fatalf("unexpected error decoding response: %s", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("unexpected number of queues. want=%d have=%d", 2, len(stats))
+	}
+	if stats[0].Name != "batches" || !stats[0].Paused || stats[0].OldestQueuedAgeSeconds != nil {
+		t.Errorf("unexpected batches stats: %+v", stats[0])
+	}
+	if stats[1].Name != "codeintel" || stats[1].Paused || stats[1].Queued != 3 || stats[1].Processing != 2 || stats[1].MaxProcessing != 5 {
+		t.Errorf("unexpected codeintel stats: %+v", stats[1])
+	}
+	if age := stats[1].OldestQueuedAgeSeconds; age == nil || *age < time.Hour.Seconds() {
+		t.Errorf("unexpected oldest queued age: %v", age)
+	}
+	if value := len(batchesStore.ListFunc.History()); value != 0 {
+		t.Errorf("unexpected number of calls to List. want=%d have=%d", 0, value)
+	}
+}
+
+func TestSetConcurrencyLimit(t *testing.T) {
+	concurrencyLimitStore := NewMockConcurrencyLimitStore()
+	concurrencyLimits := NewConcurrencyLimits(map[string]int{"test": 10})
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{
+		AdminUsername:         "admin",
+		AdminPassword:         "hunter2",
+		ConcurrencyLimits:     concurrencyLimits,
+		ConcurrencyLimitStore: concurrencyLimitStore,
+	}, map[string]QueueOptions{"test": {Store: workerstoremocks.NewMockStore()}}, nil)(router)
+
+	testCases := []struct {
+		method         string
+		body           string
+		expectedStatus int
+		expectedLimit  int
+	}{
+		{method: "PUT", body: `{"maxProcessing": 3}`, expectedStatus: http.StatusNoContent, expectedLimit: 3},
+		{method: "PUT", body: `{"maxProcessing": -1}`, expectedStatus: http.StatusBadRequest, expectedLimit: 3},
+		{method: "PUT", body: `{"maxProcessing": 0}`, expectedStatus: http.StatusNoContent, expectedLimit: 0},
+		{method: "DELETE", expectedStatus: http.StatusNoContent, expectedLimit: 10},
+	}
+
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(testCase.method, "/admin/test/concurrency-limit", strings.NewReader(testCase.body))
+		req.SetBasicAuth("admin", "hunter2")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != testCase.expectedStatus {
+			t.Errorf("unexpected status code for %s %s. want=%d have=%d", testCase.method, testCase.body, testCase.expectedStatus, w.Code)
+		}
+		if value := concurrencyLimits.MaxProcessing("test"); value != testCase.expectedLimit {
+			t.Errorf("unexpected limit after %s %s. want=%d have=%d", testCase.method, testCase.body, testCase.expectedLimit, value)
+		}
+	}
+
+	if value := len(concurrencyLimitStore.SetFunc.History()); value != 2 {
+		t.Errorf("unexpected number of calls to Set. want=%d have=%d", 2, value)
+	}
+	if value := len(concurrencyLimitStore.DeleteFunc.History()); value != 1 {
+		t.Errorf("unexpected number of calls to Delete. want=%d have=%d", 1, value)
+	}
+}
+
+func TestAdminRoutesRequireAuth(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.GetFunc.SetDefaultReturn(testRecord{ID: 42}, true, nil)
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{AdminUsername: "admin", AdminPassword: "hunter2"}, map[string]QueueOptions{"test": {Store: store}}, nil)(router)
+
+	testCases := []struct {
+		username       string
+		password       string
+		expectedStatus int
+	}{
+		{expectedStatus: http.StatusUnauthorized},
+		{username: "admin", password: "letmein", expectedStatus: http.StatusForbidden},
+		{username: "admin", password: "hunter2", expectedStatus: http.StatusOK},
+	}
+
+	for _, testCase := range testCases {
+		req := httptest.NewRequest("GET", "/admin/test/jobs/42", nil)
+		if testCase.username != "" {
+			req.SetBasicAuth(testCase.username, testCase.password)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != testCase.expectedStatus {
+			t.Errorf("unexpected status code. want=%d have=%d", testCase.expectedStatus, w.Code)
+		}
+	}
+}
+
+func TestListExecutors(t *testing.T) {
+	executorStore := NewMockExecutorStore()
+	executorStore.ListFunc.SetDefaultReturn([]executors.Executor{{ID: 1, Name: "deadbeef", QueueName: "test"}}, nil)
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{AdminUsername: "admin", AdminPassword: "hunter2", ExecutorStore: executorStore}, map[string]QueueOptions{"test": {Store: workerstoremocks.NewMockStore()}}, nil)(router)
+
+	req := httptest.NewRequest("GET", "/admin/executors?queue=test&maxAge=5m", nil)
+	req.SetBasicAuth("admin", "hunter2")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code. want=%d have=%d", http.StatusOK, w.Code)
+	}
+
+	var list []executors.Executor
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("unexpected error decoding response: %s", err)
+	}
+	if len(list) != 1 || list[0].Name != "deadbeef" {
+		t.Errorf("unexpected executors: %v", list)
+	}
+
+	if value := len(executorStore.ListFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to List. want=%d have=%d", 1, value)
+	}
+	opts := executorStore.ListFunc.History()[0].Arg1
+	if opts.QueueName != "test" {
+		t.Errorf("unexpected queue name. want=%q have=%q", "test", opts.QueueName)
+	}
+	if opts.SeenSince.IsZero() {
+		t.Errorf("expected seen since to be set")
+	}
+}
+
+func TestListAuditLog(t *testing.T) {
+	auditLogStore := NewMockAuditLogStore()
+	auditLogStore.ListFunc.SetDefaultReturn([]auditlog.Entry{{ID: 1, QueueName: "test", JobID: 42, Operation: auditlog.OperationDequeue, Actor: "deadbeef"}}, nil)
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{AdminUsername: "admin", AdminPassword: "hunter2", AuditLogStore: auditLogStore}, map[string]QueueOptions{"test": {Store: workerstoremocks.NewMockStore()}}, nil)(router)
+
+	testCases := []struct {
+		query          string
+		expectedStatus int
+	}{
+		{query: "queue=test&jobId=42&since=2021-01-01T00:00:00Z", expectedStatus: http.StatusOK},
+		{query: "jobId=abc", expectedStatus: http.StatusBadRequest},
+		{query: "since=yesterday", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, testCase := range testCases {
+		req := httptest.NewRequest("GET", "/admin/audit-log?"+testCase.query, nil)
+		req.SetBasicAuth("admin", "hunter2")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != testCase.expectedStatus {
+			t.Errorf("unexpected status code for %q. want=%d have=%d", testCase.query, testCase.expectedStatus, w.Code)
+		}
+	}
+
+	if value := len(auditLogStore.ListFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to List. want=%d have=%d", 1, value)
+	}
+	opts := auditLogStore.ListFunc.History()[0].Arg1
+	if opts.QueueName != "test" || opts.JobID != 42 || opts.Since.IsZero() {
+		t.Errorf("unexpected list options: %+v", opts)
+	}
+	if opts.Limit != maxAuditLogListLimit {
+		t.Errorf("unexpected limit. want=%d have=%d", maxAuditLogListLimit, opts.Limit)
+	}
+}
+
+func TestCreateSchedule(t *testing.T) {
+	scheduleStore := NewMockScheduleStore()
+	scheduleStore.CreateFunc.SetDefaultHook(func(ctx context.Context, schedule schedules.Schedule) (schedules.Schedule, error) {
+		schedule.ID = 1
+		return schedule, nil
+	})
+
+	enqueue := func(ctx context.Context, tx *basestore.Store, payload json.RawMessage) (int, error) { return 0, nil }
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{AdminUsername: "admin", AdminPassword: "hunter2", ScheduleStore: scheduleStore}, map[string]QueueOptions{
+		"test":        {Store: workerstoremocks.NewMockStore(), Enqueue: enqueue},
+		"unscheduled": {Store: workerstoremocks.NewMockStore()},
+	}, nil)(router)
+
+	testCases := []struct {
+		body           string
+		expectedStatus int
+	}{
+		{body: `{"queueName": "test", "schedule": "*/15 * * * *", "payload": {"repositoryId": 42}}`, expectedStatus: http.StatusCreated},
+		{body: `{"queueName": "unscheduled", "schedule": "*/15 * * * *"}`, expectedStatus: http.StatusBadRequest},
+		{body: `{"queueName": "test", "schedule": "every minute"}`, expectedStatus: http.StatusBadRequest},
+		{body: `{"queueName": "test", "schedule": "0 0 30 feb *"}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, testCase := range testCases {
+		req := httptest.NewRequest("POST", "/admin/schedules", strings.NewReader(testCase.body))
+		req.SetBasicAuth("admin", "hunter2")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != testCase.expectedStatus {
+			t.Errorf("unexpected status code for %s. want=%d have=%d", testCase.body, testCase.expectedStatus, w.Code)
+		}
+	}
+
+	if value := len(scheduleStore.CreateFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to Create. want=%d have=%d", 1, value)
+	}
+	schedule := scheduleStore.CreateFunc.History()[0].Arg1
+	if schedule.QueueName != "test" || string(schedule.Payload) != `{"repositoryId": 42}` {
+		t.Errorf("unexpected schedule: %+v", schedule)
+	}
+	if schedule.NextRunAt.IsZero() || schedule.NextRunAt.Minute()%15 != 0 {
+		t.Errorf("unexpected next run: %s", schedule.NextRunAt)
+	}
+}
+
+func TestSetSchedulePaused(t *testing.T) {
+	scheduleStore := NewMockScheduleStore()
+	scheduleStore.GetFunc.SetDefaultHook(func(ctx context.Context, id int) (schedules.Schedule, bool, error) {
+		return schedules.Schedule{ID: id, Schedule: "@hourly"}, id == 1, nil
+	})
+	scheduleStore.SetPausedFunc.SetDefaultReturn(true, nil)
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{AdminUsername: "admin", AdminPassword: "hunter2", ScheduleStore: scheduleStore}, map[string]QueueOptions{"test": {Store: workerstoremocks.NewMockStore()}}, nil)(router)
+
+	testCases := []struct {
+		path           string
+		expectedStatus int
+	}{
+		{path: "/admin/schedules/1/pause", expectedStatus: http.StatusNoContent},
+		{path: "/admin/schedules/1/resume", expectedStatus: http.StatusNoContent},
+		{path: "/admin/schedules/2/pause", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, testCase := range testCases {
+		req := httptest.NewRequest("POST", testCase.path, nil)
+		req.SetBasicAuth("admin", "hunter2")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != testCase.expectedStatus {
+			t.Errorf("unexpected status code for %s. want=%d have=%d", testCase.path, testCase.expectedStatus, w.Code)
+		}
+	}
+
+	history := scheduleStore.SetPausedFunc.History()
+	if len(history) != 2 {
+		t.Fatalf("unexpected number of calls to SetPaused. want=%d have=%d", 2, len(history))
+	}
+	if !history[0].Arg2 || !history[0].Arg3.IsZero() {
+		t.Errorf("unexpected pause call: %+v", history[0])
+	}
+	if history[1].Arg2 || !history[1].Arg3.After(time.Now()) {
+		t.Errorf("unexpected resume call: %+v", history[1])
+	}
+}
+
+func TestDeleteScheduleUnknownSchedule(t *testing.T) {
+	scheduleStore := NewMockScheduleStore()
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{AdminUsername: "admin", AdminPassword: "hunter2", ScheduleStore: scheduleStore}, map[string]QueueOptions{"test": {Store: workerstoremocks.NewMockStore()}}, nil)(router)
+
+	req := httptest.NewRequest("DELETE", "/admin/schedules/42", nil)
+	req.SetBasicAuth("admin", "hunter2")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unexpected status code. want=%d have=%d", http.StatusNotFound, w.Code)
+	}
+}
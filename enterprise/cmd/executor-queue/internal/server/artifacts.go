@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+var (
+	errMalformedArtifactUpload = errors.New("malformed artifact upload")
+	errArtifactTooLarge        = errors.New("artifact too large")
+)
+
+// maxArtifactFieldSize is the largest value, in bytes, accepted for the non-file fields of an
+// artifact upload.
+const maxArtifactFieldSize = 1024
+
+// artifactNamePattern matches the artifact names accepted by the upload endpoint. Names are
+// used as the final component of the artifact's storage key, so path separators are rejected.
+var artifactNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// POST /{queueName}/uploadArtifact
+func (h *handler) handleUploadArtifact(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, func() (int, interface{}, error) {
+		mr, err := r.MultipartReader()
+		if err != nil {
+			return http.StatusBadRequest, errorResponse{Error: err.Error()}, nil
+		}
+
+		artifacts, err := h.uploadArtifacts(r.Context(), mr)
+		switch {
+		case err == ErrUnknownJob:
+			return http.StatusNotFound, nil, nil
+		case errors.Is(err, errArtifactTooLarge):
+			return http.StatusRequestEntityTooLarge, errorResponse{Error: err.Error()}, nil
+		case errors.Is(err, errMalformedArtifactUpload):
+			return http.StatusBadRequest, errorResponse{Error: err.Error()}, nil
+		}
+
+		return http.StatusOK, artifacts, err
+	})
+}
+
+// uploadArtifacts streams each artifact of the given multipart body into the artifact store.
+// The executorName and jobId fields must precede the artifact parts, as the job must be owned
+// by the uploading executor before any artifact is written.
+func (h *handler) uploadArtifacts(ctx context.Context, mr *multipart.Reader) ([]apiclient.Artifact, error) {
+	var (
+		executorName string
+		jobID        int
+		verified     bool
+		artifacts    []apiclient.Artifact
+	)
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(errMalformedArtifactUpload, err.Error())
+		}
+
+		switch part.FormName() {
+		case "executorName":
+			if executorName, err = readArtifactField(part); err != nil {
+				return nil, err
+			}
+
+		case "jobId":
+			value, err := readArtifactField(part)
+			if err != nil {
+				return nil, err
+			}
+			if jobID, err = strconv.Atoi(value); err != nil {
+				return nil, errors.Wrapf(errMalformedArtifactUpload, "invalid jobId %q", value)
+			}
+
+		case "artifact":
+			if !verified {
+				if err := h.verifyArtifactOwner(ctx, executorName, jobID); err != nil {
+					return nil, err
+				}
+				verified = true
+			}
+
+			artifact, err := h.uploadArtifact(ctx, jobID, part)
+			if err != nil {
+				return nil, err
+			}
+			artifacts = append(artifacts, artifact)
+
+		default:
+			return nil, errors.Wrapf(errMalformedArtifactUpload, "unexpected field %q", part.FormName())
+		}
+	}
+
+	if len(artifacts) == 0 {
+		return nil, errors.Wrap(errMalformedArtifactUpload, "no artifacts")
+	}
+
+	return artifacts, nil
+}
+
+// verifyArtifactOwner returns ErrUnknownJob if the given job is not being processed by the given
+// executor. Ownership is checked with a heartbeat, which also keeps a job with a long upload from
+// being considered stalled.
+func (h *handler) verifyArtifactOwner(ctx context.Context, executorName string, jobID int) error {
+	if executorName == "" || jobID == 0 {
+		return errors.Wrap(errMalformedArtifactUpload, "executorName and jobId must precede artifacts")
+	}
+
+	knownIDs, err := h.Store.Heartbeat(ctx, []int{jobID}, store.HeartbeatOptions{
+		WorkerHostname: executorName,
+	})
+	if err != nil {
+		return err
+	}
+	if len(knownIDs) == 0 {
+		return ErrUnknownJob
+	}
+
+	return nil
+}
+
+func (h *handler) uploadArtifact(ctx context.Context, jobID int, part *multipart.Part) (apiclient.Artifact, error) {
+	name := part.FileName()
+	if !artifactNamePattern.MatchString(name) || name == "." || name == ".." {
+		return apiclient.Artifact{}, errors.Wrapf(errMalformedArtifactUpload, "invalid artifact name %q", name)
+	}
+
+	key := fmt.Sprintf("%s/%d/%s", h.queueName, jobID, name)
+	r := &sizeLimitedReader{r: part, remaining: h.maxArtifactSize}
+
+	size, err := h.artifactStore.Upload(ctx, key, r)
+	if r.exceeded {
+		// The store may wrap or replace the reader's error, so check the reader directly
+		return apiclient.Artifact{}, errors.Wrapf(errArtifactTooLarge, "%s exceeds %d bytes", name, h.maxArtifactSize)
+	}
+	if err != nil {
+		return apiclient.Artifact{}, errors.Wrapf(err, "uploading artifact %q", name)
+	}
+
+	return apiclient.Artifact{Name: name, Key: key, Size: size}, nil
+}
+
+func readArtifactField(part *multipart.Part) (string, error) {
+	value, err := io.ReadAll(io.LimitReader(part, maxArtifactFieldSize+1))
+	if err != nil {
+		return "", errors.Wrap(errMalformedArtifactUpload, err.Error())
+	}
+	if len(value) > maxArtifactFieldSize {
+		return "", errors.Wrapf(errMalformedArtifactUpload, "field %q is too large", part.FormName())
+	}
+
+	return string(value), nil
+}
+
+// sizeLimitedReader reads from the underlying reader until more than the remaining number of
+// bytes have been read, after which it fails with errArtifactTooLarge.
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	if r.exceeded {
+		return 0, errArtifactTooLarge
+	}
+
+	// Read one byte past the limit to distinguish an artifact that is exactly at the limit
+	// from one that exceeds it
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.r.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		r.exceeded = true
+		return 0, errArtifactTooLarge
+	}
+
+	return n, err
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestUploadArtifact(t *testing.T) {
+	s := workerstoremocks.NewMockStore()
+	s.HeartbeatFunc.SetDefaultReturn([]int{42}, nil)
+
+	uploaded := map[string]string{}
+	artifactStore := NewMockArtifactStore()
+	artifactStore.UploadFunc.SetDefaultHook(func(ctx context.Context, key string, r io.Reader) (int64, error) {
+		contents, err := io.ReadAll(r)
+		uploaded[key] = string(contents)
+		return int64(len(contents)), err
+	})
+
+	router := newArtifactTestRouter(s, artifactStore, 16)
+	w := serveArtifactUpload(router, "deadbeef", "42", map[string]string{
+		"output.json": `{"ok": true}`,
+		"diff.patch":  "",
+	})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code. want=%d have=%d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var artifacts []apiclient.Artifact
+	if err := json.Unmarshal(w.Body.Bytes(), &artifacts); err != nil {
+		t.Fatalf("unexpected error decoding response: %s", err)
+	}
+	expectedArtifacts := []apiclient.Artifact{
+		{Name: "diff.patch", Key: "test/42/diff.patch", Size: 0},
+		{Name: "output.json", Key: "test/42/output.json", Size: 12},
+	}
+	if diff := cmp.Diff(expectedArtifacts, artifacts); diff != "" {
+		t.Errorf("unexpected artifacts (-want +got):\n%s", diff)
+	}
+
+	expectedUploads := map[string]string{
+		"test/42/diff.patch":  "",
+		"test/42/output.json": `{"ok": true}`,
+	}
+	if diff := cmp.Diff(expectedUploads, uploaded); diff != "" {
+		t.Errorf("unexpected uploads (-want +got):\n%s", diff)
+	}
+
+	if value := len(s.HeartbeatFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to Heartbeat. want=%d have=%d", 1, value)
+	}
+	if name := s.HeartbeatFunc.History()[0].Arg2.WorkerHostname; name != "deadbeef" {
+		t.Errorf("unexpected worker hostname. want=%q have=%q", "deadbeef", name)
+	}
+}
+
+func TestUploadArtifactUnknownJob(t *testing.T) {
+	s := workerstoremocks.NewMockStore()
+	s.HeartbeatFunc.SetDefaultHook(func(ctx context.Context, ids []int, options store.HeartbeatOptions) ([]int, error) {
+		return nil, nil
+	})
+	artifactStore := NewMockArtifactStore()
+
+	router := newArtifactTestRouter(s, artifactStore, 16)
+	w := serveArtifactUpload(router, "deadbeef", "42", map[string]string{"output.json": "{}"})
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unexpected status code. want=%d have=%d", http.StatusNotFound, w.Code)
+	}
+	if value := len(artifactStore.UploadFunc.History()); value != 0 {
+		t.Errorf("unexpected number of calls to Upload. want=%d have=%d", 0, value)
+	}
+}
+
+func TestUploadArtifactTooLarge(t *testing.T) {
+	s := workerstoremocks.NewMockStore()
+	s.HeartbeatFunc.SetDefaultReturn([]int{42}, nil)
+	artifactStore := NewMockArtifactStore()
+	artifactStore.UploadFunc.SetDefaultHook(func(ctx context.Context, key string, r io.Reader) (int64, error) {
+		return io.Copy(io.Discard, r)
+	})
+
+	router := newArtifactTestRouter(s, artifactStore, 16)
+
+	if w := serveArtifactUpload(router, "deadbeef", "42", map[string]string{"output.txt": strings.Repeat("x", 16)}); w.Code != http.StatusOK {
+		t.Errorf("unexpected status code. want=%d have=%d", http.StatusOK, w.Code)
+	}
+	if w := serveArtifactUpload(router, "deadbeef", "42", map[string]string{"output.txt": strings.Repeat("x", 17)}); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("unexpected status code. want=%d have=%d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
+
+func TestUploadArtifactMalformed(t *testing.T) {
+	s := workerstoremocks.NewMockStore()
+	s.HeartbeatFunc.SetDefaultReturn([]int{42}, nil)
+	artifactStore := NewMockArtifactStore()
+
+	router := newArtifactTestRouter(s, artifactStore, 16)
+
+	testCases := []struct {
+		name         string
+		executorName string
+		jobID        string
+		artifacts    map[string]string
+	}{
+		{name: "missing executor", jobID: "42", artifacts: map[string]string{"output.json": "{}"}},
+		{name: "invalid job id", executorName: "deadbeef", jobID: "forty-two", artifacts: map[string]string{"output.json": "{}"}},
+		{name: "no artifacts", executorName: "deadbeef", jobID: "42"},
+		{name: "parent directory", executorName: "deadbeef", jobID: "42", artifacts: map[string]string{"..": "{}"}},
+		{name: "invalid name", executorName: "deadbeef", jobID: "42", artifacts: map[string]string{"out put.json": "{}"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if w := serveArtifactUpload(router, testCase.executorName, testCase.jobID, testCase.artifacts); w.Code != http.StatusBadRequest {
+				t.Errorf("unexpected status code. want=%d have=%d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+
+	if value := len(artifactStore.UploadFunc.History()); value != 0 {
+		t.Errorf("unexpected number of calls to Upload. want=%d have=%d", 0, value)
+	}
+}
+
+func TestUploadArtifactDisabled(t *testing.T) {
+	router := newArtifactTestRouter(workerstoremocks.NewMockStore(), nil, 16)
+
+	if w := serveArtifactUpload(router, "deadbeef", "42", map[string]string{"output.json": "{}"}); w.Code != http.StatusNotFound {
+		t.Errorf("unexpected status code. want=%d have=%d", http.StatusNotFound, w.Code)
+	}
+}
+
+func newArtifactTestRouter(s store.Store, artifactStore ArtifactStore, maxArtifactSize int64) *mux.Router {
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{ArtifactStore: artifactStore, MaxArtifactSize: maxArtifactSize}, map[string]QueueOptions{"test": {Store: s}}, nil)(router)
+	return router
+}
+
+// serveArtifactUpload sends an upload request for the given artifacts, ordered by name, to the
+// given router.
+func serveArtifactUpload(router *mux.Router, executorName, jobID string, artifacts map[string]string) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	if executorName != "" {
+		_ = mw.WriteField("executorName", executorName)
+	}
+	if jobID != "" {
+		_ = mw.WriteField("jobId", jobID)
+	}
+
+	names := make([]string, 0, len(artifacts))
+	for name := range artifacts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		part, _ := mw.CreateFormFile("artifact", name)
+		_, _ = io.WriteString(part, artifacts[name])
+	}
+	_ = mw.Close()
+
+	req := httptest.NewRequest("POST", "/test/uploadArtifact", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
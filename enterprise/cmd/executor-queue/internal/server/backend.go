@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// QueueBackend stores the jobs of a single queue. It mirrors the job operations of a dbworker
+// store, which is the Postgres implementation used by default, without requiring access to the
+// underlying database handle. Backends that do not keep jobs in Postgres must still interpret the
+// conditions produced by the queue's hooks, or delegate the operations accepting conditions to
+// the default backend.
+type QueueBackend interface {
+	QueuedCount(ctx context.Context, conditions []*sqlf.Query) (int, error)
+	ProcessingCount(ctx context.Context, conditions []*sqlf.Query) (int, error)
+	Dequeue(ctx context.Context, workerHostname string, conditions []*sqlf.Query) (workerutil.Record, bool, error)
+	DequeueBatch(ctx context.Context, workerHostname string, conditions []*sqlf.Query, limit int) ([]workerutil.Record, error)
+	List(ctx context.Context, options store.ListOptions) ([]workerutil.Record, error)
+	Get(ctx context.Context, id int) (workerutil.Record, bool, error)
+	Delete(ctx context.Context, id int) (bool, error)
+	Heartbeat(ctx context.Context, ids []int, options store.HeartbeatOptions) (knownIDs []int, err error)
+	Requeue(ctx context.Context, id int, after time.Time) error
+	AddExecutionLogEntry(ctx context.Context, id int, entry workerutil.ExecutionLogEntry, options store.ExecutionLogEntryOptions) (entryID int, err error)
+	UpdateExecutionLogEntry(ctx context.Context, recordID, entryID int, entry workerutil.ExecutionLogEntry, options store.ExecutionLogEntryOptions) error
+	MarkComplete(ctx context.Context, id int, options store.MarkFinalOptions) (bool, error)
+	MarkErrored(ctx context.Context, id int, failureMessage string, options store.MarkFinalOptions) (bool, error)
+	MarkFailed(ctx context.Context, id int, failureMessage string, options store.MarkFinalOptions) (bool, error)
+	MarkQueuedFailed(ctx context.Context, conditions []*sqlf.Query, failureMessage string) ([]int, error)
+	MarkDependentsFailed(ctx context.Context) ([]int, error)
+	ResetStalled(ctx context.Context) (resetIDs, erroredIDs []int, err error)
+	ResetWorker(ctx context.Context, workerHostname string) (resetIDs, erroredIDs []int, err error)
+}
+
+var _ QueueBackend = store.Store(nil)
+
+// PostgresBackend is the name of the default backend, which stores jobs in the queue's table
+// through a dbworker store.
+const PostgresBackend = "postgres"
+
+// BackendFactory creates the backend of the given queue. The Postgres backend of the queue is
+// supplied so that a backend may delegate operations it does not implement itself, such as
+// listing jobs for the admin API.
+type BackendFactory func(queueName string, postgres QueueBackend) (QueueBackend, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]BackendFactory{}
+)
+
+// RegisterBackend makes a queue backend available under the given name. It is meant to be
+// called from the init function of the package implementing the backend, which deployments
+// link into their build of the executor-queue. RegisterBackend panics if the name is already
+// taken.
+func RegisterBackend(name string, factory BackendFactory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	if _, ok := backends[name]; ok || name == PostgresBackend {
+		panic("executor-queue: queue backend " + name + " registered twice")
+	}
+	backends[name] = factory
+}
+
+// Backends returns the names of the available queue backends.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	names := []string{PostgresBackend}
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+
+	return names
+}
+
+// IsBackend returns true if a queue backend is available under the given name.
+func IsBackend(name string) bool {
+	if name == PostgresBackend {
+		return true
+	}
+
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	_, ok := backends[name]
+	return ok
+}
+
+// NewQueueBackend creates the backend with the given name for the given queue. The Postgres
+// backend is returned as-is if the name is empty or names the Postgres backend.
+func NewQueueBackend(name, queueName string, postgres QueueBackend) (QueueBackend, error) {
+	if name == "" || name == PostgresBackend {
+		return postgres, nil
+	}
+
+	backendsMu.RLock()
+	factory, ok := backends[name]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("unknown queue backend %q", name)
+	}
+
+	return factory(queueName, postgres)
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestNewQueueBackend(t *testing.T) {
+	postgres := workerstoremocks.NewMockStore()
+	custom := workerstoremocks.NewMockStore()
+
+	var factoryQueueName string
+	var factoryPostgres QueueBackend
+	RegisterBackend("test", func(queueName string, postgres QueueBackend) (QueueBackend, error) {
+		factoryQueueName, factoryPostgres = queueName, postgres
+		return custom, nil
+	})
+	t.Cleanup(func() { delete(backends, "test") })
+
+	if diff := cmp.Diff([]string{PostgresBackend, "test"}, Backends()); diff != "" {
+		t.Errorf("unexpected backends (-want +got):\n%s", diff)
+	}
+	for name, expected := range map[string]bool{PostgresBackend: true, "test": true, "redis": false} {
+		if value := IsBackend(name); value != expected {
+			t.Errorf("unexpected availability of backend %q. want=%v have=%v", name, expected, value)
+		}
+	}
+
+	for _, name := range []string{"", PostgresBackend} {
+		backend, err := NewQueueBackend(name, "codeintel", postgres)
+		if err != nil {
+			t.Fatalf("unexpected error creating backend %q: %s", name, err)
+		}
+		if backend != postgres {
+			t.Errorf("expected backend %q to be the postgres backend", name)
+		}
+	}
+
+	backend, err := NewQueueBackend("test", "codeintel", postgres)
+	if err != nil {
+		t.Fatalf("unexpected error creating backend: %s", err)
+	}
+	if backend != custom {
+		t.Errorf("expected the backend returned by the factory")
+	}
+	if factoryQueueName != "codeintel" || factoryPostgres != postgres {
+		t.Errorf("unexpected factory arguments. queue=%q", factoryQueueName)
+	}
+
+	if _, err := NewQueueBackend("redis", "codeintel", postgres); err == nil {
+		t.Errorf("expected an error creating an unknown backend")
+	}
+}
+
+func TestRegisterBackendTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected registering the postgres backend to panic")
+		}
+	}()
+
+	RegisterBackend(PostgresBackend, nil)
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// ConcurrencyLimits tracks the maximum number of jobs of each queue that may be processing at
+// once, across all executors. Limits configured at startup can be overridden while the server
+// is running. A queue without a limit, or with a limit of zero, is not restricted. A nil value
+// restricts no queues.
+type ConcurrencyLimits struct {
+	mu        sync.RWMutex
+	defaults  map[string]int
+	overrides map[string]int
+}
+
+// NewConcurrencyLimits creates concurrency limits with the given per-queue defaults.
+func NewConcurrencyLimits(defaults map[string]int) *ConcurrencyLimits {
+	return &ConcurrencyLimits{defaults: defaults, overrides: map[string]int{}}
+}
+
+// MaxProcessing returns the maximum number of processing jobs of the given queue. A zero
+// value indicates that the queue is not restricted.
+func (l *ConcurrencyLimits) MaxProcessing(queueName string) int {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if maxProcessing, ok := l.overrides[queueName]; ok {
+		return maxProcessing
+	}
+	return l.defaults[queueName]
+}
+
+// SetOverrides replaces the overridden limits of all queues.
+func (l *ConcurrencyLimits) SetOverrides(overrides map[string]int) {
+	if overrides == nil {
+		overrides = map[string]int{}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.overrides = overrides
+}
+
+// setOverride overrides the limit of a single queue.
+func (l *ConcurrencyLimits) setOverride(queueName string, maxProcessing int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.overrides[queueName] = maxProcessing
+}
+
+// clearOverride reverts the limit of a single queue to its default.
+func (l *ConcurrencyLimits) clearOverride(queueName string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.overrides, queueName)
+}
+
+type concurrencyLimitRefresher struct {
+	store  ConcurrencyLimitStore
+	limits *ConcurrencyLimits
+}
+
+var _ goroutine.Handler = &concurrencyLimitRefresher{}
+var _ goroutine.ErrorHandler = &concurrencyLimitRefresher{}
+
+// NewConcurrencyLimitRefresher returns a background routine that periodically reloads the
+// overridden limits from the given store. Overrides set through the admin API take effect
+// immediately on the replica serving the request and on other replicas after their next
+// refresh.
+func NewConcurrencyLimitRefresher(store ConcurrencyLimitStore, limits *ConcurrencyLimits, interval time.Duration) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, &concurrencyLimitRefresher{
+		store:  store,
+		limits: limits,
+	})
+}
+
+func (h *concurrencyLimitRefresher) Handle(ctx context.Context) error {
+	overrides, err := h.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	h.limits.SetOverrides(overrides)
+	return nil
+}
+
+func (h *concurrencyLimitRefresher) HandleError(err error) {
+	log15.Error("Failed to refresh queue concurrency limits", "error", err)
+}
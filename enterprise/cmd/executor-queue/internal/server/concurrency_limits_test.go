@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConcurrencyLimits(t *testing.T) {
+	var nilLimits *ConcurrencyLimits
+	if value := nilLimits.MaxProcessing("codeintel"); value != 0 {
+		t.Errorf("unexpected limit. want=%d have=%d", 0, value)
+	}
+
+	limits := NewConcurrencyLimits(map[string]int{"codeintel": 10})
+	if value := limits.MaxProcessing("codeintel"); value != 10 {
+		t.Errorf("unexpected limit. want=%d have=%d", 10, value)
+	}
+	if value := limits.MaxProcessing("batches"); value != 0 {
+		t.Errorf("unexpected limit. want=%d have=%d", 0, value)
+	}
+
+	// An override of zero lifts the configured limit
+	limits.setOverride("codeintel", 0)
+	if value := limits.MaxProcessing("codeintel"); value != 0 {
+		t.Errorf("unexpected limit. want=%d have=%d", 0, value)
+	}
+	limits.clearOverride("codeintel")
+	if value := limits.MaxProcessing("codeintel"); value != 10 {
+		t.Errorf("unexpected limit. want=%d have=%d", 10, value)
+	}
+}
+
+func TestConcurrencyLimitRefresher(t *testing.T) {
+	store := NewMockConcurrencyLimitStore()
+	store.ListFunc.SetDefaultReturn(map[string]int{"batches": 3}, nil)
+
+	limits := NewConcurrencyLimits(map[string]int{"codeintel": 10})
+	limits.setOverride("codeintel", 5)
+
+	refresher := &concurrencyLimitRefresher{store: store, limits: limits}
+	if err := refresher.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error refreshing limits: %s", err)
+	}
+
+	if value := limits.MaxProcessing("codeintel"); value != 10 {
+		t.Errorf("unexpected limit. want=%d have=%d", 10, value)
+	}
+	if value := limits.MaxProcessing("batches"); value != 3 {
+		t.Errorf("unexpected limit. want=%d have=%d", 3, value)
+	}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/auditlog"
+)
+
+// JobEvent describes a state transition of a job as sent over the admin event stream.
+type JobEvent struct {
+	ID        int64     `json:"id"`
+	QueueName string    `json:"queueName"`
+	JobID     int       `json:"jobId"`
+	State     string    `json:"state"`
+	Operation string    `json:"operation"`
+	Actor     string    `json:"actor"`
+	Message   string    `json:"message,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// operationStates maps each audited operation to the state the job is in after it.
+var operationStates = map[string]string{
+	auditlog.OperationDequeue:      "processing",
+	auditlog.OperationMarkComplete: "completed",
+	auditlog.OperationMarkErrored:  "errored",
+	auditlog.OperationMarkFailed:   "failed",
+	auditlog.OperationRequeue:      "queued",
+	auditlog.OperationDelete:       "deleted",
+}
+
+const (
+	// defaultEventPollInterval is the interval at which the event stream reads new audit log
+	// entries if no interval is configured.
+	defaultEventPollInterval = time.Second
+
+	// eventBatchSize is the maximum number of audit log entries read by a single poll.
+	eventBatchSize = 100
+
+	// eventKeepaliveInterval is the maximum time the event stream stays silent. Proxies tend
+	// to close idle connections.
+	eventKeepaliveInterval = 15 * time.Second
+)
+
+// GET /admin/events
+//
+// Events are read from the audit log so that transitions served by any replica are streamed.
+// Clients that reconnect with a Last-Event-ID header resume after that event; otherwise the
+// stream starts with transitions made after the request.
+func handleStreamEvents(auditLogStore AuditLogStore, drainer *drainer, pollInterval time.Duration) http.HandlerFunc {
+	if pollInterval <= 0 {
+		pollInterval = defaultEventPollInterval
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		opts := auditlog.ListOptions{QueueName: r.URL.Query().Get("queue"), Limit: eventBatchSize}
+		if value := r.Header.Get("Last-Event-ID"); value != "" {
+			lastEventID, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid Last-Event-ID: %s", err), http.StatusBadRequest)
+				return
+			}
+			opts.AfterID = lastEventID
+		} else {
+			opts.Since = time.Now()
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		lastWrite := time.Now()
+		for !drainer.isDraining() {
+			entries, err := auditLogStore.List(r.Context(), opts)
+			if err != nil && r.Context().Err() == nil {
+				log15.Error("Failed to read audit log for event stream", "error", err)
+			}
+
+			for _, entry := range entries {
+				if err := writeJobEvent(w, entry); err != nil {
+					return
+				}
+
+				// Subsequent polls pick up where this one left off
+				opts.AfterID = entry.ID
+				opts.Since = time.Time{}
+			}
+			if len(entries) > 0 {
+				flusher.Flush()
+				lastWrite = time.Now()
+			} else if time.Since(lastWrite) >= eventKeepaliveInterval {
+				if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+				lastWrite = time.Now()
+			}
+
+			if len(entries) == eventBatchSize {
+				// Catch up without waiting for the next tick
+				continue
+			}
+
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// writeJobEvent writes the given audit log entry as a server-sent event named after the state
+// the job transitioned into.
+func writeJobEvent(w http.ResponseWriter, entry auditlog.Entry) error {
+	state, ok := operationStates[entry.Operation]
+	if !ok {
+		state = entry.Operation
+	}
+
+	event := JobEvent{
+		ID:        entry.ID,
+		QueueName: entry.QueueName,
+		JobID:     entry.JobID,
+		State:     state,
+		Operation: entry.Operation,
+		Actor:     entry.Actor,
+		Message:   entry.Message,
+		CreatedAt: entry.CreatedAt,
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.State, data)
+	return err
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/auditlog"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestStreamEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	auditLogStore := NewMockAuditLogStore()
+	auditLogStore.ListFunc.PushReturn([]auditlog.Entry{
+		{ID: 1, QueueName: "test", JobID: 42, Operation: auditlog.OperationDequeue, Actor: "e1"},
+		{ID: 2, QueueName: "test", JobID: 42, Operation: auditlog.OperationMarkComplete, Actor: "e1"},
+	}, nil)
+	auditLogStore.ListFunc.SetDefaultHook(func(ctx context.Context, opts auditlog.ListOptions) ([]auditlog.Entry, error) {
+		cancel()
+		return nil, nil
+	})
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{
+		AdminUsername:     "admin",
+		AdminPassword:     "hunter2",
+		AuditLogStore:     auditLogStore,
+		EventPollInterval: time.Millisecond,
+	}, map[string]QueueOptions{"test": {Store: workerstoremocks.NewMockStore()}}, nil)(router)
+
+	req := httptest.NewRequest("GET", "/admin/events?queue=test", nil).WithContext(ctx)
+	req.SetBasicAuth("admin", "hunter2")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code. want=%d have=%d", http.StatusOK, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("unexpected content type %q", contentType)
+	}
+
+	body := w.Body.String()
+	for _, expected := range []string{
+		"id: 1\nevent: processing\ndata: {",
+		"id: 2\nevent: completed\ndata: {",
+		`"jobId":42`,
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("expected %q in event stream:\n%s", expected, body)
+		}
+	}
+
+	history := auditLogStore.ListFunc.History()
+	if len(history) != 2 {
+		t.Fatalf("unexpected number of calls to List. want=%d have=%d", 2, len(history))
+	}
+	if opts := history[0].Arg1; opts.QueueName != "test" || opts.Since.IsZero() || opts.AfterID != 0 {
+		t.Errorf("unexpected options of first poll: %+v", opts)
+	}
+	if opts := history[1].Arg1; !opts.Since.IsZero() || opts.AfterID != 2 {
+		t.Errorf("unexpected options of second poll: %+v", opts)
+	}
+}
+
+func TestStreamEventsLastEventID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	auditLogStore := NewMockAuditLogStore()
+	auditLogStore.ListFunc.SetDefaultHook(func(ctx context.Context, opts auditlog.ListOptions) ([]auditlog.Entry, error) {
+		cancel()
+		return nil, nil
+	})
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{AdminUsername: "admin", AdminPassword: "hunter2", AuditLogStore: auditLogStore}, map[string]QueueOptions{"test": {Store: workerstoremocks.NewMockStore()}}, nil)(router)
+
+	for _, testCase := range []struct {
+		lastEventID    string
+		expectedStatus int
+	}{
+		{lastEventID: "17", expectedStatus: http.StatusOK},
+		{lastEventID: "latest", expectedStatus: http.StatusBadRequest},
+	} {
+		req := httptest.NewRequest("GET", "/admin/events", nil).WithContext(ctx)
+		req.SetBasicAuth("admin", "hunter2")
+		req.Header.Set("Last-Event-ID", testCase.lastEventID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != testCase.expectedStatus {
+			t.Errorf("unexpected status code for %q. want=%d have=%d", testCase.lastEventID, testCase.expectedStatus, w.Code)
+		}
+	}
+
+	history := auditLogStore.ListFunc.History()
+	if len(history) != 1 {
+		t.Fatalf("unexpected number of calls to List. want=%d have=%d", 1, len(history))
+	}
+	if opts := history[0].Arg1; !opts.Since.IsZero() || opts.AfterID != 17 {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+}
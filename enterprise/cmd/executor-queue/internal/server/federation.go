@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/federation"
+)
+
+// SetJobRegionRequest is the payload of a request to restrict a job to the executor-queue
+// instances of a region.
+type SetJobRegionRequest struct {
+	Region string `json:"region"`
+}
+
+// PUT /admin/{queueName}/jobs/{id}/region
+func (h *handler) handleSetJobRegion(w http.ResponseWriter, r *http.Request) {
+	var payload SetJobRegionRequest
+
+	h.wrapHandler(w, r, &payload, func() (int, interface{}, error) {
+		if payload.Region == "" {
+			return http.StatusBadRequest, errorResponse{Error: "region must not be empty"}, nil
+		}
+
+		err := h.setJobRegion(r.Context(), idFromRequest(r), payload.Region)
+		if err == ErrUnknownJob {
+			return http.StatusNotFound, nil, nil
+		}
+		return http.StatusNoContent, nil, err
+	})
+}
+
+// DELETE /admin/{queueName}/jobs/{id}/region
+func (h *handler) handleDeleteJobRegion(w http.ResponseWriter, r *http.Request) {
+	h.wrapAdminHandler(w, r, func() (int, interface{}, error) {
+		ok, err := h.federationStore.DeleteJobRegion(r.Context(), h.queueName, idFromRequest(r))
+		if err == nil && !ok {
+			return http.StatusNotFound, nil, nil
+		}
+		return http.StatusNoContent, nil, err
+	})
+}
+
+// setJobRegion restricts the given job to the executor-queue instances of the given region.
+func (h *handler) setJobRegion(ctx context.Context, jobID int, region string) error {
+	if _, err := h.getJob(ctx, jobID); err != nil {
+		return err
+	}
+
+	return h.federationStore.SetJobRegion(ctx, h.queueName, jobID, region)
+}
+
+// deleteJobRegion removes the region tag of a deleted job.
+func (h *handler) deleteJobRegion(ctx context.Context, jobID int) {
+	if h.federationStore == nil {
+		return
+	}
+
+	if _, err := h.federationStore.DeleteJobRegion(ctx, h.queueName, jobID); err != nil {
+		log15.Error("Failed to delete job region", "queue", h.queueName, "jobID", jobID, "error", err)
+	}
+}
+
+// POST /admin/federation/reports
+func handleFederationReport(federationStore FederationStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var payload federation.Report
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to unmarshal payload: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+
+		writeResponse(w, func() (int, interface{}, error) {
+			if payload.Region == "" {
+				return http.StatusBadRequest, errorResponse{Error: "region must not be empty"}, nil
+			}
+
+			err := federationStore.UpsertReport(r.Context(), payload)
+			return http.StatusNoContent, nil, err
+		})
+	}
+}
+
+// GET /admin/federation/regions
+func handleListRegionStats(federationStore FederationStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, func() (int, interface{}, error) {
+			var reportedSince time.Time
+			if value := r.URL.Query().Get("maxAge"); value != "" {
+				maxAge, err := time.ParseDuration(value)
+				if err != nil {
+					return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid maxAge: %s", err)}, nil
+				}
+				reportedSince = time.Now().Add(-maxAge)
+			}
+
+			stats, err := federationStore.ListRegionStats(r.Context(), reportedSince)
+			if stats == nil {
+				stats = []federation.RegionStats{}
+			}
+			return http.StatusOK, stats, err
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/federation"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestSetJobRegion(t *testing.T) {
+	s := workerstoremocks.NewMockStore()
+	s.GetFunc.SetDefaultHook(func(ctx context.Context, id int) (workerutil.Record, bool, error) {
+		return testRecord{ID: id}, id == 42, nil
+	})
+	federationStore := NewMockFederationStore()
+	federationStore.DeleteJobRegionFunc.SetDefaultReturn(true, nil)
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{
+		AdminUsername:   "admin",
+		AdminPassword:   "hunter2",
+		FederationStore: federationStore,
+	}, map[string]QueueOptions{"test": {Store: s}}, nil)(router)
+
+	testCases := []struct {
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{method: "PUT", path: "/admin/test/jobs/42/region", body: `{"region": "eu"}`, expectedStatus: http.StatusNoContent},
+		{method: "PUT", path: "/admin/test/jobs/42/region", body: `{"region": ""}`, expectedStatus: http.StatusBadRequest},
+		{method: "PUT", path: "/admin/test/jobs/43/region", body: `{"region": "eu"}`, expectedStatus: http.StatusNotFound},
+		{method: "DELETE", path: "/admin/test/jobs/42/region", expectedStatus: http.StatusNoContent},
+	}
+
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(testCase.method, testCase.path, strings.NewReader(testCase.body))
+		req.SetBasicAuth("admin", "hunter2")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != testCase.expectedStatus {
+			t.Errorf("unexpected status code for %s %s %s. want=%d have=%d", testCase.method, testCase.path, testCase.body, testCase.expectedStatus, w.Code)
+		}
+	}
+
+	if value := len(federationStore.SetJobRegionFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to SetJobRegion. want=%d have=%d", 1, value)
+	}
+	if call := federationStore.SetJobRegionFunc.History()[0]; call.Arg1 != "test" || call.Arg2 != 42 || call.Arg3 != "eu" {
+		t.Errorf("unexpected region tag: %s/%d=%s", call.Arg1, call.Arg2, call.Arg3)
+	}
+}
+
+func TestDeleteJobDeletesRegion(t *testing.T) {
+	s := workerstoremocks.NewMockStore()
+	s.DeleteFunc.SetDefaultReturn(true, nil)
+	federationStore := NewMockFederationStore()
+
+	handler := newHandler(QueueOptions{Store: s})
+	handler.queueName = "test"
+	handler.federationStore = federationStore
+
+	if err := handler.deleteJob(context.Background(), "admin:admin", 42); err != nil {
+		t.Fatalf("unexpected error deleting job: %s", err)
+	}
+	if value := len(federationStore.DeleteJobRegionFunc.History()); value != 1 {
+		t.Errorf("unexpected number of calls to DeleteJobRegion. want=%d have=%d", 1, value)
+	}
+}
+
+func TestHeartbeatRecordsRegion(t *testing.T) {
+	executorStore := NewMockExecutorStore()
+
+	handler := newHandler(QueueOptions{Store: workerstoremocks.NewMockStore()})
+	handler.queueName = "test"
+	handler.region = "eu"
+	handler.executorStore = executorStore
+
+	if _, err := handler.heartbeat(context.Background(), executors.Executor{Name: "deadbeef"}, nil, nil); err != nil {
+		t.Fatalf("unexpected error performing heartbeat: %s", err)
+	}
+	if call := executorStore.UpsertHeartbeatFunc.History()[0]; call.Arg1.Region != "eu" {
+		t.Errorf("unexpected executor region. want=%q have=%q", "eu", call.Arg1.Region)
+	}
+}
+
+func TestFederationReports(t *testing.T) {
+	federationStore := NewMockFederationStore()
+	federationStore.ListRegionStatsFunc.SetDefaultReturn([]federation.RegionStats{
+		{Region: "eu", QueueReport: federation.QueueReport{QueueName: "test", Queued: 3}},
+	}, nil)
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{
+		AdminUsername:    "admin",
+		AdminPassword:    "hunter2",
+		FederationStore:  federationStore,
+		FederationParent: true,
+	}, map[string]QueueOptions{"test": {Store: workerstoremocks.NewMockStore()}}, nil)(router)
+
+	report := federation.Report{Region: "eu", Queues: []federation.QueueReport{{QueueName: "test", Queued: 3, ActiveExecutors: 1}}}
+	body, _ := json.Marshal(report)
+	req := httptest.NewRequest("POST", "/admin/federation/reports", strings.NewReader(string(body)))
+	req.SetBasicAuth("admin", "hunter2")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status code. want=%d have=%d", http.StatusNoContent, w.Code)
+	}
+	if diff := cmp.Diff(report, federationStore.UpsertReportFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected report (-want +got):\n%s", diff)
+	}
+
+	req = httptest.NewRequest("GET", "/admin/federation/regions?maxAge=5m", nil)
+	req.SetBasicAuth("admin", "hunter2")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code. want=%d have=%d", http.StatusOK, w.Code)
+	}
+	var stats []federation.RegionStats
+	if err := json.NewDecoder(w.Body).Decode(&stats); err != nil {
+		t.Fatalf("unexpected error decoding response: %s", err)
+	}
+	if len(stats) != 1 || stats[0].Region != "eu" || stats[0].Queued != 3 {
+		t.Errorf("unexpected region stats: %v", stats)
+	}
+	if call := federationStore.ListRegionStatsFunc.History()[0]; call.Arg1.IsZero() {
+		t.Errorf("expected maxAge to restrict the listing")
+	}
+}
+
+func TestFederationReportsRequireParent(t *testing.T) {
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{
+		AdminUsername:   "admin",
+		AdminPassword:   "hunter2",
+		FederationStore: NewMockFederationStore(),
+	}, map[string]QueueOptions{"test": {Store: workerstoremocks.NewMockStore()}}, nil)(router)
+
+	req := httptest.NewRequest("POST", "/admin/federation/reports", strings.NewReader(`{"region": "eu"}`))
+	req.SetBasicAuth("admin", "hunter2")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code == http.StatusNoContent {
+		t.Errorf("unexpected report accepted by regional instance")
+	}
+}
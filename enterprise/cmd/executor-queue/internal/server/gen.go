@@ -0,0 +1,9 @@
+package server
+
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server -i ExecutorStore -o mock_executor_store_test.go
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server -i AuditLogStore -o mock_audit_log_store_test.go
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server -i CheckpointStore -o mock_checkpoint_store_test.go
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server -i ArtifactStore -o mock_artifact_store_test.go
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server -i ScheduleStore -o mock_schedule_store_test.go
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server -i ConcurrencyLimitStore -o mock_concurrency_limit_store_test.go
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server -i FederationStore -o mock_federation_store_test.go
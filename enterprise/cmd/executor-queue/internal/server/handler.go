@@ -3,10 +3,15 @@ package server
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/inconshreveable/log15"
+	"github.com/keegancsmith/sqlf"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/auditlog"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/schedules"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
@@ -14,31 +19,126 @@ import (
 
 type handler struct {
 	QueueOptions
+	queueName         string
+	executorStore     ExecutorStore
+	auditLogStore     AuditLogStore
+	checkpointStore   CheckpointStore
+	artifactStore     ArtifactStore
+	maxArtifactSize   int64
+	pausedQueues      *PausedQueues
+	concurrencyLimits *ConcurrencyLimits
+	region            string
+	federationStore   FederationStore
+	unredactedAccess  *basicAuthCredentials
+	jobTimer          *jobTimer
+	drainer           *drainer
 }
 
 type QueueOptions struct {
-	// Store is a required dbworker store store for each registered queue.
-	Store store.Store
+	// Store is the required backend storing the jobs of each registered queue. Queues are
+	// backed by a dbworker store over Postgres unless another backend is selected through
+	// NewQueueBackend.
+	Store QueueBackend
 
 	// RecordTransformer is a required hook for each registered queue that transforms a generic
 	// record from that queue into the job to be given to an executor.
 	RecordTransformer func(ctx context.Context, record workerutil.Record) (apiclient.Job, error)
+
+	// FilterConditions is an optional hook for each registered queue that converts the filters
+	// supplied to the admin job listing endpoint into conditions over the queue's store. Queues
+	// that cannot honor a given filter should return ErrUnsupportedFilter.
+	FilterConditions func(filter JobFilter) ([]*sqlf.Query, error)
+
+	// RecordQueuedAt is an optional hook for each registered queue that returns the time at
+	// which the given record was enqueued. This is used to observe the time spent in the queue.
+	RecordQueuedAt func(record workerutil.Record) time.Time
+
+	// DequeueConditions is an optional hook for each registered queue that converts the labels
+	// supplied by a dequeuing executor into conditions over the queue's store, restricting the
+	// executor to jobs whose label selector it satisfies. Queues without this hook hand out
+	// jobs to any executor.
+	DequeueConditions func(executorLabels []string) []*sqlf.Query
+
+	// CanaryPercentage is the percentage of jobs in this queue that are handed only to
+	// executors on the canary channel. The remaining jobs are handed only to executors on
+	// the stable channel.
+	CanaryPercentage int
+
+	// CompleteFromCache is an optional hook for each registered queue that completes a job with
+	// the result of a previous job reported by the executor, rather than with the output found in
+	// the job's execution logs. The hook must reject results whose cache key does not match the
+	// job's with an error wrapping ErrInvalidCacheHit. A false-valued flag indicates that the job
+	// is not processing or is not owned by the executor. Queues without this hook reject cache hits.
+	CompleteFromCache func(ctx context.Context, jobID int, executorName string, hit apiclient.CacheHit) (bool, error)
+
+	// Enqueue is an optional hook for each registered queue that inserts a job described by a
+	// queue-specific JSON payload. Schedules can only be created for queues with this hook.
+	Enqueue schedules.Enqueuer
+
+	// ExecutorVersions is the range of executor versions allowed to dequeue jobs from this
+	// queue. Executors outside of this range receive an upgrade-required error.
+	ExecutorVersions ExecutorVersionRange
+
+	// RetryPolicies maps the failure classes reported by executors to the retry policy applied
+	// to jobs marked as errored with that class. Jobs marked as errored without a class, or with
+	// a class that has no policy, are moved into the errored state as-is.
+	RetryPolicies map[string]RetryPolicy
+
+	// RedactionRules describe the values removed from the job records of this queue returned
+	// by the admin API, such as the credentials embedded in job payloads.
+	RedactionRules RedactionRules
+
+	// Metrics are the optional histograms observed for this queue.
+	Metrics QueueMetrics
+}
+
+// RetryPolicy controls how a job marked as errored with a particular failure class is retried.
+type RetryPolicy struct {
+	// MaxNumRetries is the number of times a job is retried before it is marked as failed. Jobs
+	// are marked as failed immediately if this value is zero.
+	MaxNumRetries int
+
+	// Backoff is the delay before the first retry. The delay doubles with each retry.
+	Backoff time.Duration
+
+	// MaxBackoff, if positive, caps the delay between retries.
+	MaxBackoff time.Duration
 }
 
 func newHandler(queueOptions QueueOptions) *handler {
 	return &handler{
 		QueueOptions: queueOptions,
+		jobTimer:     newJobTimer(),
 	}
 }
 
 var ErrUnknownJob = errors.New("unknown job")
 
-// dequeue selects a job record from the database and stashes metadata including
-// the job record and the locking transaction. If no job is available for processing,
-// a false-valued flag is returned.
-func (h *handler) dequeue(ctx context.Context, executorName, executorHostname string) (_ apiclient.Job, dequeued bool, _ error) {
+// ErrInvalidCacheHit is returned when an executor reports a cache hit that does not apply to
+// the job being completed, or when the queue does not accept cache hits.
+var ErrInvalidCacheHit = errors.New("invalid cache hit")
+
+// dequeue selects a job record from the database and marks it as processing by the
+// given executor. If no job is available for processing, or if the server is shutting
+// down, the queue is paused, or the queue is at its concurrency limit, a false-valued flag is
+// returned.
+func (h *handler) dequeue(ctx context.Context, executorName, executorHostname string, executorLabels []string, executorChannel string) (_ apiclient.Job, dequeued bool, _ error) {
+	start := time.Now()
+	defer func() { observe(h.Metrics.DequeueLatency, time.Since(start)) }()
+
+	if h.drainer.isDraining() {
+		// Do not hand out new jobs while shutting down
+		return apiclient.Job{}, false, nil
+	}
+	if h.pausedQueues.IsPaused(h.queueName) {
+		return apiclient.Job{}, false, nil
+	}
+	if slots, err := h.availableSlots(ctx, 1); err != nil || slots == 0 {
+		return apiclient.Job{}, false, err
+	}
+
 	// We explicitly DON'T want to use executorHostname here, it is NOT guaranteed to be unique.
-	record, dequeued, err := h.Store.Dequeue(ctx, executorName, nil)
+	record, dequeued, err := h.Store.Dequeue(ctx, executorName, h.dequeueConditions(executorLabels, executorChannel))
 	if err != nil {
 		return apiclient.Job{}, false, err
 	}
@@ -46,16 +146,143 @@ func (h *handler) dequeue(ctx context.Context, executorName, executorHostname st
 		return apiclient.Job{}, false, nil
 	}
 
+	job, err := h.prepareJob(ctx, executorName, record)
+	if err != nil {
+		return apiclient.Job{}, false, err
+	}
+
+	return job, true, nil
+}
+
+// maxDequeueBatchSize is the maximum number of jobs handed to an executor by a single
+// batch dequeue request.
+const maxDequeueBatchSize = 100
+
+// dequeueBatch selects up to numJobs job records from the database and marks them as
+// processing by the given executor in a single transaction. If no job is available for
+// processing, or if the server is shutting down, the queue is paused, or the queue is at its
+// concurrency limit, an empty slice is returned. Fewer jobs are dequeued when handing out all of
+// them would exceed the concurrency limit.
+func (h *handler) dequeueBatch(ctx context.Context, executorName, executorHostname string, executorLabels []string, executorChannel string, numJobs int) (_ []apiclient.Job, err error) {
+	start := time.Now()
+	defer func() { observe(h.Metrics.DequeueLatency, time.Since(start)) }()
+
+	if h.drainer.isDraining() {
+		// Do not hand out new jobs while shutting down
+		return nil, nil
+	}
+	if h.pausedQueues.IsPaused(h.queueName) {
+		return nil, nil
+	}
+	if numJobs > maxDequeueBatchSize {
+		numJobs = maxDequeueBatchSize
+	}
+	if numJobs, err = h.availableSlots(ctx, numJobs); err != nil || numJobs == 0 {
+		return nil, err
+	}
+
+	// We explicitly DON'T want to use executorHostname here, it is NOT guaranteed to be unique.
+	records, err := h.Store.DequeueBatch(ctx, executorName, h.dequeueConditions(executorLabels, executorChannel), numJobs)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]apiclient.Job, 0, len(records))
+	for _, record := range records {
+		job, err := h.prepareJob(ctx, executorName, record)
+		if err != nil {
+			// The record has been marked as failed. The remaining records are already
+			// claimed by this executor, so hand them out rather than failing the request.
+			log15.Error("Failed to transform dequeued record", "queue", h.queueName, "recordID", record.RecordID(), "error", err)
+			continue
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// availableSlots returns how many of the requested number of jobs may be dequeued without
+// exceeding the queue's concurrency limit. The processing jobs are counted without locking, so
+// concurrent dequeues may briefly exceed the limit by up to the number of jobs they request.
+func (h *handler) availableSlots(ctx context.Context, numJobs int) (int, error) {
+	maxProcessing := h.concurrencyLimits.MaxProcessing(h.queueName)
+	if maxProcessing <= 0 {
+		return numJobs, nil
+	}
+
+	processing, err := h.Store.ProcessingCount(ctx, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "ProcessingCount")
+	}
+	if slots := maxProcessing - processing; slots < numJobs {
+		if slots < 0 {
+			return 0, nil
+		}
+		return slots, nil
+	}
+
+	return numJobs, nil
+}
+
+// dequeueConditions returns the conditions restricting the jobs handed to an executor
+// with the given labels and release channel.
+func (h *handler) dequeueConditions(executorLabels []string, executorChannel string) []*sqlf.Query {
+	var conditions []*sqlf.Query
+	if h.DequeueConditions != nil {
+		conditions = h.DequeueConditions(executorLabels)
+	}
+	if condition := canaryCondition(h.CanaryPercentage, executorChannel); condition != nil {
+		conditions = append(conditions, condition)
+	}
+
+	return conditions
+}
+
+// prepareJob transforms a record dequeued by the given executor into the job handed to that
+// executor. Records that cannot be transformed are marked as failed.
+func (h *handler) prepareJob(ctx context.Context, executorName string, record workerutil.Record) (apiclient.Job, error) {
 	job, err := h.RecordTransformer(ctx, record)
 	if err != nil {
 		if _, err := h.Store.MarkFailed(ctx, record.RecordID(), fmt.Sprintf("failed to transform record: %s", err), store.MarkFinalOptions{}); err != nil {
 			log15.Error("Failed to mark record as failed", "recordID", record.RecordID(), "error", err)
 		}
 
-		return apiclient.Job{}, false, err
+		return apiclient.Job{}, err
 	}
 
-	return job, true, nil
+	h.audit(ctx, record.RecordID(), auditlog.OperationDequeue, executorName, "")
+	job.Checkpoint = h.getCheckpoint(ctx, record.RecordID())
+
+	now := time.Now()
+	if h.RecordQueuedAt != nil {
+		observe(h.Metrics.TimeInQueue, now.Sub(h.RecordQueuedAt(record)))
+	}
+	h.jobTimer.start(record.RecordID(), now)
+
+	return job, nil
+}
+
+// canaryCondition restricts the given executor channel to its share of jobs. Jobs are split
+// by identifier rather than at random so that a retried job stays on the same channel.
+func canaryCondition(canaryPercentage int, executorChannel string) *sqlf.Query {
+	if executorChannel == apiclient.ExecutorChannelCanary {
+		return sqlf.Sprintf("mod(id, 100) < %s", canaryPercentage)
+	}
+	if canaryPercentage <= 0 {
+		return nil
+	}
+
+	return sqlf.Sprintf("mod(id, 100) >= %s", canaryPercentage)
+}
+
+// observeProcessingDuration observes the processing duration of the given job, if it
+// was dequeued by this server.
+func (h *handler) observeProcessingDuration(jobID int) {
+	if duration, ok := h.jobTimer.stop(jobID, time.Now()); ok {
+		observe(h.Metrics.ProcessingDuration, duration)
+	}
 }
 
 // addExecutionLogEntry calls AddExecutionLogEntry for the given job.
@@ -101,20 +328,67 @@ func (h *handler) markComplete(ctx context.Context, executorName string, jobID i
 	if !ok {
 		return ErrUnknownJob
 	}
+	if err == nil {
+		h.observeProcessingDuration(jobID)
+		h.Metrics.jobCompleted(executorName)
+		h.audit(ctx, jobID, auditlog.OperationMarkComplete, executorName, "")
+		h.deleteCheckpoint(ctx, jobID)
+	}
 	return err
 }
 
-// markErrored calls MarkErrored for the given job.
-func (h *handler) markErrored(ctx context.Context, executorName string, jobID int, errorMessage string) error {
-	ok, err := h.Store.MarkErrored(ctx, jobID, errorMessage, store.MarkFinalOptions{
+// markCompleteFromCache calls the CompleteFromCache hook for the given job.
+func (h *handler) markCompleteFromCache(ctx context.Context, executorName string, jobID int, hit apiclient.CacheHit) error {
+	if h.CompleteFromCache == nil {
+		return errors.Wrapf(ErrInvalidCacheHit, "queue %q does not accept cache hits", h.queueName)
+	}
+
+	ok, err := h.CompleteFromCache(ctx, jobID, executorName, hit)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUnknownJob
+	}
+
+	h.observeProcessingDuration(jobID)
+	h.Metrics.jobCompleted(executorName)
+	h.audit(ctx, jobID, auditlog.OperationMarkComplete, executorName, "cache hit")
+	h.deleteCheckpoint(ctx, jobID)
+	return nil
+}
+
+// markErrored calls MarkErrored for the given job, applying the retry policy of the given
+// failure class.
+func (h *handler) markErrored(ctx context.Context, executorName string, jobID int, errorMessage, failureClass string) error {
+	policy, ok := h.RetryPolicies[failureClass]
+	if ok && policy.MaxNumRetries <= 0 {
+		return h.markFailed(ctx, executorName, jobID, errorMessage)
+	}
+
+	options := store.MarkFinalOptions{
 		// We pass the WorkerHostname, so the store enforces the record to be owned by this executor. When
 		// the previous executor didn't report heartbeats anymore, but is still alive and reporting state,
 		// both executors that ever got the job would be writing to the same record. This prevents it.
 		WorkerHostname: executorName,
-	})
+	}
+	if ok {
+		options.Backoff = &store.BackoffOptions{
+			// The first attempt is not a retry
+			MaxNumFailures: policy.MaxNumRetries + 1,
+			Delay:          policy.Backoff,
+			MaxDelay:       policy.MaxBackoff,
+		}
+	}
+
+	ok, err := h.Store.MarkErrored(ctx, jobID, errorMessage, options)
 	if !ok {
 		return ErrUnknownJob
 	}
+	if err == nil {
+		h.observeProcessingDuration(jobID)
+		h.audit(ctx, jobID, auditlog.OperationMarkErrored, executorName, errorMessage)
+	}
 	return err
 }
 
@@ -129,15 +403,109 @@ func (h *handler) markFailed(ctx context.Context, executorName string, jobID int
 	if !ok {
 		return ErrUnknownJob
 	}
+	if err == nil {
+		h.observeProcessingDuration(jobID)
+		h.audit(ctx, jobID, auditlog.OperationMarkFailed, executorName, errorMessage)
+		h.deleteCheckpoint(ctx, jobID)
+	}
 	return err
 }
 
-// heartbeat calls Heartbeat for the given jobs.
-func (h *handler) heartbeat(ctx context.Context, executorName string, ids []int) (knownIDs []int, err error) {
-	return h.Store.Heartbeat(ctx, ids, store.HeartbeatOptions{
+// audit appends an entry for the given job to the audit log. The operation has already been
+// performed at this point, so a failure to record it is logged rather than returned.
+func (h *handler) audit(ctx context.Context, jobID int, operation, actor, message string) {
+	if h.auditLogStore == nil {
+		return
+	}
+
+	if err := h.auditLogStore.Append(ctx, auditlog.Entry{
+		QueueName: h.queueName,
+		JobID:     jobID,
+		Operation: operation,
+		Actor:     actor,
+		Message:   message,
+	}); err != nil {
+		log15.Error("Failed to append to audit log", "queue", h.queueName, "jobID", jobID, "operation", operation, "error", err)
+	}
+}
+
+// heartbeat records the executor in the executor registry, calls Heartbeat for the given jobs,
+// and stores the checkpoints reported for the jobs that are still owned by the executor.
+func (h *handler) heartbeat(ctx context.Context, executor executors.Executor, ids []int, checkpoints map[int][]byte) (knownIDs []int, err error) {
+	if h.executorStore != nil {
+		executor.QueueName = h.queueName
+		executor.Region = h.region
+
+		// Failing to update the registry must not cause the executor's jobs to be reset
+		if err := h.executorStore.UpsertHeartbeat(ctx, executor); err != nil {
+			log15.Error("Failed to record executor heartbeat", "executorName", executor.Name, "error", err)
+		}
+	}
+
+	knownIDs, err = h.Store.Heartbeat(ctx, ids, store.HeartbeatOptions{
 		// We pass the WorkerHostname, so the store enforces the record to be owned by this executor. When
 		// the previous executor didn't report heartbeats anymore, but is still alive and reporting state,
 		// both executors that ever got the job would be writing to the same record. This prevents it.
-		WorkerHostname: executorName,
+		WorkerHostname: executor.Name,
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only store checkpoints of jobs the store confirmed are owned by this executor, so that an
+	// executor that lost a job cannot overwrite the progress of its new owner.
+	for _, id := range knownIDs {
+		if data, ok := checkpoints[id]; ok {
+			h.putCheckpoint(ctx, executor.Name, id, data)
+		}
+	}
+
+	return knownIDs, nil
+}
+
+// maxCheckpointSize is the largest checkpoint, in bytes, that is stored for a job.
+const maxCheckpointSize = 1024 * 1024
+
+// putCheckpoint stores the checkpoint of the given job. A checkpoint that cannot be stored only
+// means the job restarts from an older checkpoint, so failures are logged rather than returned.
+func (h *handler) putCheckpoint(ctx context.Context, executorName string, jobID int, data []byte) {
+	if h.checkpointStore == nil {
+		return
+	}
+
+	if len(data) > maxCheckpointSize {
+		log15.Warn("Discarding oversized checkpoint", "queue", h.queueName, "jobID", jobID, "executorName", executorName, "size", len(data))
+		return
+	}
+
+	if err := h.checkpointStore.Upsert(ctx, h.queueName, jobID, data); err != nil {
+		log15.Error("Failed to store checkpoint", "queue", h.queueName, "jobID", jobID, "error", err)
+	}
+}
+
+// getCheckpoint returns the checkpoint of the given job, or nil if there is none. The job can
+// always restart from scratch, so a failure to read the checkpoint does not fail the dequeue.
+func (h *handler) getCheckpoint(ctx context.Context, jobID int) []byte {
+	if h.checkpointStore == nil {
+		return nil
+	}
+
+	data, _, err := h.checkpointStore.Get(ctx, h.queueName, jobID)
+	if err != nil {
+		log15.Error("Failed to read checkpoint", "queue", h.queueName, "jobID", jobID, "error", err)
+		return nil
+	}
+
+	return data
+}
+
+// deleteCheckpoint removes the checkpoint of a job that will not be processed again.
+func (h *handler) deleteCheckpoint(ctx context.Context, jobID int) {
+	if h.checkpointStore == nil {
+		return
+	}
+
+	if err := h.checkpointStore.Delete(ctx, h.queueName, jobID); err != nil {
+		log15.Error("Failed to delete checkpoint", "queue", h.queueName, "jobID", jobID, "error", err)
+	}
 }
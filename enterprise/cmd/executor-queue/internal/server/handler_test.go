@@ -3,9 +3,13 @@ package server
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
@@ -38,7 +42,7 @@ func TestDequeue(t *testing.T) {
 
 	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
 
-	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
+	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected error dequeueing job: %s", err)
 	}
@@ -53,10 +57,215 @@ func TestDequeue(t *testing.T) {
 	}
 }
 
+func TestDequeueBatch(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.DequeueBatchFunc.SetDefaultReturn([]workerutil.Record{testRecord{ID: 42}, testRecord{ID: 43}, testRecord{ID: 44}}, nil)
+	recordTransformer := func(ctx context.Context, record workerutil.Record) (apiclient.Job, error) {
+		if record.RecordID() == 43 {
+			return apiclient.Job{}, errors.New("malformed record")
+		}
+
+		return apiclient.Job{ID: record.RecordID()}, nil
+	}
+
+	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
+
+	jobs, err := handler.dequeueBatch(context.Background(), "deadbeef", "test", nil, "", maxDequeueBatchSize+1)
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing jobs: %s", err)
+	}
+	if diff := cmp.Diff([]apiclient.Job{{ID: 42}, {ID: 44}}, jobs); diff != "" {
+		t.Errorf("unexpected jobs (-want +got):\n%s", diff)
+	}
+
+	if limit := store.DequeueBatchFunc.History()[0].Arg3; limit != maxDequeueBatchSize {
+		t.Errorf("unexpected limit. want=%d have=%d", maxDequeueBatchSize, limit)
+	}
+	if value := len(store.MarkFailedFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to MarkFailed. want=%d have=%d", 1, value)
+	}
+	if id := store.MarkFailedFunc.History()[0].Arg1; id != 43 {
+		t.Errorf("unexpected failed record. want=%d have=%d", 43, id)
+	}
+}
+
+func TestDequeueCheckpoint(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.DequeueFunc.SetDefaultReturn(testRecord{ID: 42}, true, nil)
+	recordTransformer := func(ctx context.Context, record workerutil.Record) (apiclient.Job, error) {
+		return apiclient.Job{ID: record.RecordID()}, nil
+	}
+	checkpointStore := NewMockCheckpointStore()
+	checkpointStore.GetFunc.SetDefaultReturn([]byte("step 3"), true, nil)
+
+	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
+	handler.queueName = "test"
+	handler.checkpointStore = checkpointStore
+
+	job, _, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	}
+	if string(job.Checkpoint) != "step 3" {
+		t.Errorf("unexpected checkpoint. want=%q have=%q", "step 3", job.Checkpoint)
+	}
+	if call := checkpointStore.GetFunc.History()[0]; call.Arg1 != "test" || call.Arg2 != 42 {
+		t.Errorf("unexpected checkpoint lookup. want=%q/%d have=%q/%d", "test", 42, call.Arg1, call.Arg2)
+	}
+}
+
+func TestDequeueConditions(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	dequeueConditions := func(executorLabels []string) []*sqlf.Query {
+		if diff := cmp.Diff([]string{"gpu"}, executorLabels); diff != "" {
+			t.Errorf("unexpected labels (-want +got):\n%s", diff)
+		}
+
+		return []*sqlf.Query{sqlf.Sprintf("labels <@ %s", executorLabels)}
+	}
+
+	handler := newHandler(QueueOptions{Store: store, DequeueConditions: dequeueConditions})
+	if _, _, err := handler.dequeue(context.Background(), "deadbeef", "test", []string{"gpu"}, ""); err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	}
+
+	if value := len(store.DequeueFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to Dequeue. want=%d have=%d", 1, value)
+	}
+	if value := len(store.DequeueFunc.History()[0].Arg2); value != 1 {
+		t.Errorf("unexpected number of conditions. want=%d have=%d", 1, value)
+	}
+}
+
+func TestCanaryCondition(t *testing.T) {
+	testCases := []struct {
+		canaryPercentage int
+		executorChannel  string
+		expectedQuery    string
+	}{
+		{canaryPercentage: 0, executorChannel: "", expectedQuery: ""},
+		{canaryPercentage: 0, executorChannel: apiclient.ExecutorChannelStable, expectedQuery: ""},
+		{canaryPercentage: 0, executorChannel: apiclient.ExecutorChannelCanary, expectedQuery: "mod(id, 100) < $1"},
+		{canaryPercentage: 5, executorChannel: "", expectedQuery: "mod(id, 100) >= $1"},
+		{canaryPercentage: 5, executorChannel: apiclient.ExecutorChannelStable, expectedQuery: "mod(id, 100) >= $1"},
+		{canaryPercentage: 5, executorChannel: apiclient.ExecutorChannelCanary, expectedQuery: "mod(id, 100) < $1"},
+	}
+
+	for _, testCase := range testCases {
+		condition := canaryCondition(testCase.canaryPercentage, testCase.executorChannel)
+
+		query := ""
+		if condition != nil {
+			query = condition.Query(sqlf.PostgresBindVar)
+			if diff := cmp.Diff([]interface{}{testCase.canaryPercentage}, condition.Args()); diff != "" {
+				t.Errorf("unexpected args (-want +got):\n%s", diff)
+			}
+		}
+		if query != testCase.expectedQuery {
+			t.Errorf("unexpected query for percentage=%d channel=%q. want=%q have=%q", testCase.canaryPercentage, testCase.executorChannel, testCase.expectedQuery, query)
+		}
+	}
+}
+
+func TestDequeueDraining(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.DequeueFunc.SetDefaultReturn(testRecord{ID: 42}, true, nil)
+
+	handler := newHandler(QueueOptions{Store: store})
+	handler.drainer = &drainer{}
+	handler.drainer.drain()
+
+	_, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, "")
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	}
+	if dequeued {
+		t.Fatalf("did not expect a job to be dequeued")
+	}
+	if value := len(store.DequeueFunc.History()); value != 0 {
+		t.Fatalf("unexpected number of calls to Dequeue. want=%d have=%d", 0, value)
+	}
+}
+
+func TestDequeuePaused(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.DequeueFunc.SetDefaultReturn(testRecord{ID: 42}, true, nil)
+	store.DequeueBatchFunc.SetDefaultReturn([]workerutil.Record{testRecord{ID: 42}}, nil)
+	recordTransformer := func(ctx context.Context, record workerutil.Record) (apiclient.Job, error) {
+		return apiclient.Job{ID: record.RecordID()}, nil
+	}
+
+	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
+	handler.queueName = "test"
+	handler.pausedQueues = NewPausedQueues()
+	handler.pausedQueues.Set([]string{"test"})
+
+	if _, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, ""); err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	} else if dequeued {
+		t.Fatalf("did not expect a job to be dequeued")
+	}
+	if jobs, err := handler.dequeueBatch(context.Background(), "deadbeef", "test", nil, "", 3); err != nil {
+		t.Fatalf("unexpected error dequeueing jobs: %s", err)
+	} else if len(jobs) != 0 {
+		t.Fatalf("did not expect jobs to be dequeued")
+	}
+	if value := len(store.DequeueFunc.History()) + len(store.DequeueBatchFunc.History()); value != 0 {
+		t.Fatalf("unexpected number of calls to Dequeue. want=%d have=%d", 0, value)
+	}
+
+	// Resume the queue
+	handler.pausedQueues.Set(nil)
+
+	if _, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, ""); err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	} else if !dequeued {
+		t.Fatalf("expected a job to be dequeued")
+	}
+}
+
+func TestDequeueConcurrencyLimit(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.ProcessingCountFunc.SetDefaultReturn(4, nil)
+	store.DequeueFunc.SetDefaultReturn(testRecord{ID: 42}, true, nil)
+	store.DequeueBatchFunc.SetDefaultReturn([]workerutil.Record{testRecord{ID: 42}}, nil)
+	recordTransformer := func(ctx context.Context, record workerutil.Record) (apiclient.Job, error) {
+		return apiclient.Job{ID: record.RecordID()}, nil
+	}
+
+	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
+	handler.queueName = "test"
+	handler.concurrencyLimits = NewConcurrencyLimits(map[string]int{"test": 6})
+
+	if _, err := handler.dequeueBatch(context.Background(), "deadbeef", "test", nil, "", 5); err != nil {
+		t.Fatalf("unexpected error dequeueing jobs: %s", err)
+	}
+	if limit := store.DequeueBatchFunc.History()[0].Arg3; limit != 2 {
+		t.Errorf("unexpected limit. want=%d have=%d", 2, limit)
+	}
+
+	// Fill the remaining slots
+	store.ProcessingCountFunc.SetDefaultReturn(6, nil)
+
+	if _, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, ""); err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	} else if dequeued {
+		t.Fatalf("did not expect a job to be dequeued")
+	}
+	if jobs, err := handler.dequeueBatch(context.Background(), "deadbeef", "test", nil, "", 5); err != nil {
+		t.Fatalf("unexpected error dequeueing jobs: %s", err)
+	} else if len(jobs) != 0 {
+		t.Fatalf("did not expect jobs to be dequeued")
+	}
+	if value := len(store.DequeueFunc.History()) + len(store.DequeueBatchFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to Dequeue. want=%d have=%d", 1, value)
+	}
+}
+
 func TestDequeueNoRecord(t *testing.T) {
 	handler := newHandler(QueueOptions{Store: workerstoremocks.NewMockStore()})
 
-	_, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
+	_, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected error dequeueing job: %s", err)
 	}
@@ -76,7 +285,7 @@ func TestAddExecutionLogEntry(t *testing.T) {
 
 	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
 
-	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
+	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected error dequeueing job: %s", err)
 	}
@@ -131,7 +340,7 @@ func TestUpdateExecutionLogEntry(t *testing.T) {
 
 	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
 
-	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
+	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected error dequeueing job: %s", err)
 	}
@@ -187,7 +396,7 @@ func TestMarkComplete(t *testing.T) {
 
 	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
 
-	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
+	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected error dequeueing job: %s", err)
 	}
@@ -228,7 +437,7 @@ func TestMarkErrored(t *testing.T) {
 
 	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
 
-	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
+	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected error dequeueing job: %s", err)
 	}
@@ -236,7 +445,7 @@ func TestMarkErrored(t *testing.T) {
 		t.Fatalf("expected a job to be dequeued")
 	}
 
-	if err := handler.markErrored(context.Background(), "deadbeef", job.ID, "OH NO"); err != nil {
+	if err := handler.markErrored(context.Background(), "deadbeef", job.ID, "OH NO", ""); err != nil {
 		t.Fatalf("unexpected error completing job: %s", err)
 	}
 
@@ -252,12 +461,58 @@ func TestMarkErrored(t *testing.T) {
 	}
 }
 
+func TestMarkErroredRetryPolicies(t *testing.T) {
+	retryPolicies := map[string]RetryPolicy{
+		apiclient.FailureClassUserError: {},
+		apiclient.FailureClassTransient: {MaxNumRetries: 3, Backoff: time.Second, MaxBackoff: time.Minute},
+	}
+
+	testCases := []struct {
+		failureClass    string
+		expectedFailed  bool
+		expectedBackoff *store.BackoffOptions
+	}{
+		{failureClass: ""},
+		{failureClass: "unknown"},
+		{failureClass: apiclient.FailureClassUserError, expectedFailed: true},
+		{failureClass: apiclient.FailureClassTransient, expectedBackoff: &store.BackoffOptions{MaxNumFailures: 4, Delay: time.Second, MaxDelay: time.Minute}},
+	}
+
+	for _, testCase := range testCases {
+		s := workerstoremocks.NewMockStore()
+		s.MarkErroredFunc.SetDefaultReturn(true, nil)
+		s.MarkFailedFunc.SetDefaultReturn(true, nil)
+		handler := newHandler(QueueOptions{Store: s, RetryPolicies: retryPolicies})
+
+		if err := handler.markErrored(context.Background(), "deadbeef", 42, "OH NO", testCase.failureClass); err != nil {
+			t.Fatalf("unexpected error marking job as errored: %s", err)
+		}
+
+		if testCase.expectedFailed {
+			if value := len(s.MarkFailedFunc.History()); value != 1 {
+				t.Errorf("unexpected number of calls to MarkFailed for %q. want=%d have=%d", testCase.failureClass, 1, value)
+			}
+			if value := len(s.MarkErroredFunc.History()); value != 0 {
+				t.Errorf("unexpected number of calls to MarkErrored for %q. want=%d have=%d", testCase.failureClass, 0, value)
+			}
+			continue
+		}
+
+		if value := len(s.MarkErroredFunc.History()); value != 1 {
+			t.Fatalf("unexpected number of calls to MarkErrored for %q. want=%d have=%d", testCase.failureClass, 1, value)
+		}
+		if diff := cmp.Diff(testCase.expectedBackoff, s.MarkErroredFunc.History()[0].Arg3.Backoff); diff != "" {
+			t.Errorf("unexpected backoff for %q (-want +got):\n%s", testCase.failureClass, diff)
+		}
+	}
+}
+
 func TestMarkErroredUnknownJob(t *testing.T) {
 	store := workerstoremocks.NewMockStore()
 	store.MarkErroredFunc.SetDefaultReturn(false, nil)
 	handler := newHandler(QueueOptions{Store: store})
 
-	if err := handler.markErrored(context.Background(), "deadbeef", 42, "OH NO"); err != ErrUnknownJob {
+	if err := handler.markErrored(context.Background(), "deadbeef", 42, "OH NO", ""); err != ErrUnknownJob {
 		t.Fatalf("unexpected error. want=%q have=%q", ErrUnknownJob, err)
 	}
 }
@@ -272,7 +527,7 @@ func TestMarkFailed(t *testing.T) {
 
 	handler := newHandler(QueueOptions{Store: store, RecordTransformer: recordTransformer})
 
-	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test")
+	job, dequeued, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, "")
 	if err != nil {
 		t.Fatalf("unexpected error dequeueing job: %s", err)
 	}
@@ -318,13 +573,86 @@ func TestHeartbeat(t *testing.T) {
 
 	handler := newHandler(QueueOptions{Store: s, RecordTransformer: recordTransformer})
 
-	if knownIDs, err := handler.heartbeat(context.Background(), "deadbeef", []int{testKnownID, 10}); err != nil {
+	if knownIDs, err := handler.heartbeat(context.Background(), executors.Executor{Name: "deadbeef"}, []int{testKnownID, 10}, nil); err != nil {
 		t.Fatalf("unexpected error performing heartbeat: %s", err)
 	} else if diff := cmp.Diff([]int{testKnownID}, knownIDs); diff != "" {
 		t.Errorf("unexpected unknown ids (-want +got):\n%s", diff)
 	}
 }
 
+func TestHeartbeatCheckpoints(t *testing.T) {
+	s := workerstoremocks.NewMockStore()
+	s.HeartbeatFunc.SetDefaultReturn([]int{42}, nil)
+	checkpointStore := NewMockCheckpointStore()
+
+	handler := newHandler(QueueOptions{Store: s})
+	handler.queueName = "test"
+	handler.checkpointStore = checkpointStore
+
+	checkpoints := map[int][]byte{
+		42: []byte("step 3"),
+		43: []byte("step 1"), // no longer owned by this executor
+		44: make([]byte, maxCheckpointSize+1),
+	}
+	if _, err := handler.heartbeat(context.Background(), executors.Executor{Name: "deadbeef"}, []int{42, 43, 44}, checkpoints); err != nil {
+		t.Fatalf("unexpected error performing heartbeat: %s", err)
+	}
+
+	if value := len(checkpointStore.UpsertFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to Upsert. want=%d have=%d", 1, value)
+	}
+	if call := checkpointStore.UpsertFunc.History()[0]; call.Arg1 != "test" || call.Arg2 != 42 || string(call.Arg3) != "step 3" {
+		t.Errorf("unexpected checkpoint upsert: %s/%d=%q", call.Arg1, call.Arg2, call.Arg3)
+	}
+}
+
+func TestMarkCompleteDeletesCheckpoint(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.MarkCompleteFunc.SetDefaultReturn(true, nil)
+	checkpointStore := NewMockCheckpointStore()
+
+	handler := newHandler(QueueOptions{Store: store})
+	handler.queueName = "test"
+	handler.checkpointStore = checkpointStore
+
+	if err := handler.markComplete(context.Background(), "deadbeef", 42); err != nil {
+		t.Fatalf("unexpected error marking job as complete: %s", err)
+	}
+	if value := len(checkpointStore.DeleteFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to Delete. want=%d have=%d", 1, value)
+	}
+}
+
+func TestHeartbeatRecordsExecutor(t *testing.T) {
+	s := workerstoremocks.NewMockStore()
+	executorStore := NewMockExecutorStore()
+	executorStore.UpsertHeartbeatFunc.SetDefaultReturn(errors.New("registry unavailable"))
+
+	handler := newHandler(QueueOptions{Store: s})
+	handler.queueName = "test"
+	handler.executorStore = executorStore
+
+	executor := executors.Executor{Name: "deadbeef", Hostname: "test-host", OS: "linux", Architecture: "amd64", ExecutorVersion: "3.30.0"}
+	if _, err := handler.heartbeat(context.Background(), executor, []int{42}, nil); err != nil {
+		t.Fatalf("unexpected error performing heartbeat: %s", err)
+	}
+
+	if value := len(executorStore.UpsertHeartbeatFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to UpsertHeartbeat. want=%d have=%d", 1, value)
+	}
+	executor.QueueName = "test"
+	if diff := cmp.Diff(executor, executorStore.UpsertHeartbeatFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected executor (-want +got):\n%s", diff)
+	}
+
+	if value := len(s.HeartbeatFunc.History()); value != 1 {
+		t.Fatalf("unexpected number of calls to Heartbeat. want=%d have=%d", 1, value)
+	}
+	if name := s.HeartbeatFunc.History()[0].Arg2.WorkerHostname; name != "deadbeef" {
+		t.Errorf("unexpected worker hostname. want=%q have=%q", "deadbeef", name)
+	}
+}
+
 type testRecord struct {
 	ID      int
 	Payload string
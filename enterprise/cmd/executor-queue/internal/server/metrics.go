@@ -0,0 +1,84 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// QueueMetrics are the metrics observed by the handler of a single queue. Any nil
+// field is ignored.
+type QueueMetrics struct {
+	// DequeueLatency observes the time taken to serve a dequeue request.
+	DequeueLatency prometheus.Observer
+
+	// TimeInQueue observes the time between a job being enqueued and being dequeued. This
+	// requires the queue to supply a RecordQueuedAt hook.
+	TimeInQueue prometheus.Observer
+
+	// ProcessingDuration observes the time between a job being dequeued and being marked
+	// as completed, errored, or failed.
+	ProcessingDuration prometheus.Observer
+
+	// JobCompleted is called with the name of the executor that marked a job as completed,
+	// including jobs completed from a cache hit.
+	JobCompleted func(executorName string)
+}
+
+// maxTrackedJobAge is the age after which the dequeue time of a job that was never
+// finalized through this server (e.g., a job reset after its executor died) is dropped.
+const maxTrackedJobAge = 24 * time.Hour
+
+// jobTimer tracks the time each in-progress job was dequeued so that its processing
+// duration can be observed once the job is finalized.
+type jobTimer struct {
+	mu      sync.Mutex
+	started map[int]time.Time
+}
+
+func newJobTimer() *jobTimer {
+	return &jobTimer{started: map[int]time.Time{}}
+}
+
+// start records the dequeue time of the given job and drops stale entries.
+func (t *jobTimer) start(jobID int, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for id, started := range t.started {
+		if now.Sub(started) > maxTrackedJobAge {
+			delete(t.started, id)
+		}
+	}
+
+	t.started[jobID] = now
+}
+
+// stop returns the time elapsed since the given job was dequeued. If the dequeue time
+// of the job is not known, a false-valued flag is returned.
+func (t *jobTimer) stop(jobID int, now time.Time) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	started, ok := t.started[jobID]
+	if !ok {
+		return 0, false
+	}
+
+	delete(t.started, jobID)
+	return now.Sub(started), true
+}
+
+// jobCompleted calls the JobCompleted hook for the given executor, if one is set.
+func (m QueueMetrics) jobCompleted(executorName string) {
+	if m.JobCompleted != nil {
+		m.JobCompleted(executorName)
+	}
+}
+
+func observe(observer prometheus.Observer, duration time.Duration) {
+	if observer != nil {
+		observer.Observe(duration.Seconds())
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestQueueMetrics(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.DequeueFunc.SetDefaultReturn(testRecord{ID: 42}, true, nil)
+	store.MarkCompleteFunc.SetDefaultReturn(true, nil)
+	recordTransformer := func(ctx context.Context, record workerutil.Record) (apiclient.Job, error) {
+		return apiclient.Job{ID: 42}, nil
+	}
+	queuedAt := time.Now().Add(-time.Hour)
+	recordQueuedAt := func(record workerutil.Record) time.Time {
+		return queuedAt
+	}
+
+	dequeueLatency := &testObserver{}
+	timeInQueue := &testObserver{}
+	processingDuration := &testObserver{}
+	var completedBy []string
+
+	handler := newHandler(QueueOptions{
+		Store:             store,
+		RecordTransformer: recordTransformer,
+		RecordQueuedAt:    recordQueuedAt,
+		Metrics: QueueMetrics{
+			DequeueLatency:     dequeueLatency,
+			TimeInQueue:        timeInQueue,
+			ProcessingDuration: processingDuration,
+			JobCompleted:       func(executorName string) { completedBy = append(completedBy, executorName) },
+		},
+	})
+
+	if _, _, err := handler.dequeue(context.Background(), "deadbeef", "test", nil, ""); err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	}
+	if err := handler.markComplete(context.Background(), "deadbeef", 42); err != nil {
+		t.Fatalf("unexpected error completing job: %s", err)
+	}
+	if err := handler.markComplete(context.Background(), "deadbeef", 42); err != nil {
+		t.Fatalf("unexpected error completing job: %s", err)
+	}
+
+	if len(dequeueLatency.values) != 1 {
+		t.Errorf("unexpected number of dequeue latency observations. want=%d have=%d", 1, len(dequeueLatency.values))
+	}
+	if len(timeInQueue.values) != 1 {
+		t.Errorf("unexpected number of time in queue observations. want=%d have=%d", 1, len(timeInQueue.values))
+	} else if value := timeInQueue.values[0]; value < time.Hour.Seconds() {
+		t.Errorf("unexpected time in queue. want>=%f have=%f", time.Hour.Seconds(), value)
+	}
+	if len(processingDuration.values) != 1 {
+		t.Errorf("unexpected number of processing duration observations. want=%d have=%d", 1, len(processingDuration.values))
+	}
+	if diff := cmp.Diff([]string{"deadbeef", "deadbeef"}, completedBy); diff != "" {
+		t.Errorf("unexpected completing executors (-want +got):\n%s", diff)
+	}
+}
+
+func TestJobTimerDropsStaleJobs(t *testing.T) {
+	now := time.Now()
+	timer := newJobTimer()
+	timer.start(42, now)
+	timer.start(43, now.Add(maxTrackedJobAge+time.Minute))
+
+	if _, ok := timer.stop(42, now); ok {
+		t.Errorf("expected stale job to be dropped")
+	}
+	if _, ok := timer.stop(43, now); !ok {
+		t.Errorf("expected job to be tracked")
+	}
+}
+
+type testObserver struct {
+	values []float64
+}
+
+func (o *testObserver) Observe(value float64) {
+	o.values = append(o.values, value)
+}
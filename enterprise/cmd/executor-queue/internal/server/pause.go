@@ -0,0 +1,73 @@
+package server
+
+import (
+	"sort"
+	"sync"
+)
+
+// PausedQueues tracks the queues that do not hand out new jobs. Jobs that were dequeued
+// before a queue was paused can still be reported on. A nil value pauses no queues.
+type PausedQueues struct {
+	mu    sync.RWMutex
+	names map[string]struct{}
+}
+
+func NewPausedQueues() *PausedQueues {
+	return &PausedQueues{names: map[string]struct{}{}}
+}
+
+// Set replaces the set of paused queues and returns whether the set changed.
+func (p *PausedQueues) Set(queueNames []string) (changed bool) {
+	names := make(map[string]struct{}, len(queueNames))
+	for _, name := range queueNames {
+		names[name] = struct{}{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(names) == len(p.names) {
+		for name := range names {
+			if _, ok := p.names[name]; !ok {
+				changed = true
+				break
+			}
+		}
+	} else {
+		changed = true
+	}
+
+	p.names = names
+	return changed
+}
+
+// IsPaused returns true if the given queue is paused.
+func (p *PausedQueues) IsPaused(queueName string) bool {
+	if p == nil {
+		return false
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	_, ok := p.names[queueName]
+	return ok
+}
+
+// List returns the names of the paused queues in sorted order.
+func (p *PausedQueues) List() []string {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	names := make([]string, 0, len(p.names))
+	for name := range p.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
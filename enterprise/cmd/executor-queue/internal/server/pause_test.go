@@ -0,0 +1,35 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPausedQueues(t *testing.T) {
+	var nilQueues *PausedQueues
+	if nilQueues.IsPaused("codeintel") {
+		t.Errorf("expected nil paused queues to pause nothing")
+	}
+
+	pausedQueues := NewPausedQueues()
+	if !pausedQueues.Set([]string{"codeintel", "batches", "codeintel"}) {
+		t.Errorf("expected set to change")
+	}
+	if diff := cmp.Diff([]string{"batches", "codeintel"}, pausedQueues.List()); diff != "" {
+		t.Errorf("unexpected paused queues (-want +got):\n%s", diff)
+	}
+	if !pausedQueues.IsPaused("codeintel") {
+		t.Errorf("expected codeintel to be paused")
+	}
+
+	if pausedQueues.Set([]string{"batches", "codeintel"}) {
+		t.Errorf("did not expect set to change")
+	}
+	if !pausedQueues.Set([]string{"batches"}) {
+		t.Errorf("expected set to change")
+	}
+	if pausedQueues.IsPaused("codeintel") {
+		t.Errorf("did not expect codeintel to be paused")
+	}
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// readReplicaStore serves the read-only QueuedCount and List methods from a store backed by a
+// read replica and all remaining methods from a store backed by the primary database.
+type readReplicaStore struct {
+	QueueBackend
+	replica store.Store
+}
+
+// NewReadReplicaStore returns a store that reads queued counts and job listings from the given
+// replica store, which must operate over the same table as the given primary store. Results of
+// these methods may lag behind the primary. Dequeues, state transitions, and lookups of single
+// jobs are always served by the primary.
+func NewReadReplicaStore(primary QueueBackend, replica store.Store) QueueBackend {
+	return &readReplicaStore{QueueBackend: primary, replica: replica}
+}
+
+func (s *readReplicaStore) QueuedCount(ctx context.Context, conditions []*sqlf.Query) (int, error) {
+	return s.replica.QueuedCount(ctx, conditions)
+}
+
+func (s *readReplicaStore) List(ctx context.Context, options store.ListOptions) ([]workerutil.Record, error) {
+	return s.replica.List(ctx, options)
+}
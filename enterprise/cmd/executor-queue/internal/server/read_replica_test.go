@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestReadReplicaStore(t *testing.T) {
+	primary := workerstoremocks.NewMockStore()
+	replica := workerstoremocks.NewMockStore()
+	replica.QueuedCountFunc.SetDefaultReturn(42, nil)
+	s := NewReadReplicaStore(primary, replica)
+
+	count, err := s.QueuedCount(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error counting jobs: %s", err)
+	}
+	if count != 42 {
+		t.Errorf("unexpected count. want=%d have=%d", 42, count)
+	}
+	if _, err := s.List(context.Background(), store.ListOptions{}); err != nil {
+		t.Fatalf("unexpected error listing jobs: %s", err)
+	}
+	if _, _, err := s.Get(context.Background(), 42); err != nil {
+		t.Fatalf("unexpected error getting job: %s", err)
+	}
+	if _, _, err := s.Dequeue(context.Background(), "deadbeef", nil); err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	}
+
+	if len(replica.QueuedCountFunc.History()) != 1 || len(primary.QueuedCountFunc.History()) != 0 {
+		t.Errorf("expected QueuedCount to be served by the replica")
+	}
+	if len(replica.ListFunc.History()) != 1 || len(primary.ListFunc.History()) != 0 {
+		t.Errorf("expected List to be served by the replica")
+	}
+	if len(primary.GetFunc.History()) != 1 || len(primary.DequeueFunc.History()) != 1 {
+		t.Errorf("expected Get and Dequeue to be served by the primary")
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// redactedValue replaces the values removed from job records by redaction rules.
+const redactedValue = "REDACTED"
+
+// RedactionRules describe the values removed from the job records returned by the admin API.
+type RedactionRules struct {
+	// FieldPaths are the dot-separated paths of fields whose values are replaced wholesale. A
+	// segment of "*" matches every field of an object. Arrays are traversed transparently, so
+	// "ExecutionLogs.Out" matches the output of every execution log entry.
+	FieldPaths []string
+
+	// Patterns are matched against every string value of the record. The first capturing group
+	// of each match, or the entire match for patterns without groups, is replaced, leaving the
+	// remainder of the string intact.
+	Patterns []*regexp.Regexp
+}
+
+// With returns the union of the receiver and the given rules.
+func (r RedactionRules) With(other RedactionRules) RedactionRules {
+	return RedactionRules{
+		FieldPaths: append(append([]string(nil), r.FieldPaths...), other.FieldPaths...),
+		Patterns:   append(append([]*regexp.Regexp(nil), r.Patterns...), other.Patterns...),
+	}
+}
+
+func (r RedactionRules) empty() bool {
+	return len(r.FieldPaths) == 0 && len(r.Patterns) == 0
+}
+
+// redact returns the JSON encoding of the given value, as a json.RawMessage, with the values
+// matched by the rules replaced. The value is returned as-is if there are no rules.
+func (r RedactionRules) redact(v interface{}) (interface{}, error) {
+	if r.empty() {
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	for _, path := range r.FieldPaths {
+		decoded = redactPath(decoded, strings.Split(path, "."))
+	}
+	decoded = r.redactPatterns(decoded)
+
+	data, err = json.Marshal(decoded)
+	return json.RawMessage(data), err
+}
+
+// redactPath replaces the values at the given path within the given decoded JSON value.
+func redactPath(v interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return redactedValue
+	}
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if path[0] == "*" || path[0] == key {
+				value[key] = redactPath(child, path[1:])
+			}
+		}
+
+	case []interface{}:
+		for i, child := range value {
+			value[i] = redactPath(child, path)
+		}
+	}
+
+	return v
+}
+
+// redactPatterns replaces the matches of the rules' patterns within every string of the given
+// decoded JSON value.
+func (r RedactionRules) redactPatterns(v interface{}) interface{} {
+	if len(r.Patterns) == 0 {
+		return v
+	}
+
+	switch value := v.(type) {
+	case string:
+		for _, pattern := range r.Patterns {
+			value = replaceFirstGroup(pattern, value)
+		}
+		return value
+
+	case map[string]interface{}:
+		for key, child := range value {
+			value[key] = r.redactPatterns(child)
+		}
+
+	case []interface{}:
+		for i, child := range value {
+			value[i] = r.redactPatterns(child)
+		}
+	}
+
+	return v
+}
+
+// replaceFirstGroup replaces the first capturing group of each match of the given pattern, or
+// the entire match if the pattern has no groups, with the redacted value.
+func replaceFirstGroup(pattern *regexp.Regexp, value string) string {
+	group := 0
+	if pattern.NumSubexp() > 0 {
+		group = 1
+	}
+
+	var b strings.Builder
+	last, replaced := 0, false
+	for _, match := range pattern.FindAllStringSubmatchIndex(value, -1) {
+		start, end := match[2*group], match[2*group+1]
+		if start < 0 {
+			// The group did not participate in the match
+			continue
+		}
+
+		b.WriteString(value[last:start])
+		b.WriteString(redactedValue)
+		last, replaced = end, true
+	}
+	if !replaced {
+		return value
+	}
+
+	b.WriteString(value[last:])
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+type testRedactionRecord struct {
+	ID      int
+	Token   string
+	Nested  map[string]string
+	Entries []testRedactionEntry
+}
+
+type testRedactionEntry struct {
+	Command []string
+	Out     string
+}
+
+func (r testRedactionRecord) RecordID() int { return r.ID }
+
+func TestRedactionRules(t *testing.T) {
+	rules := RedactionRules{
+		FieldPaths: []string{"Token", "Nested.*", "Entries.Out"},
+		Patterns:   []*regexp.Regexp{regexp.MustCompile(`SECRET=(\S+)`), regexp.MustCompile(`hunter\d`)},
+	}
+
+	record := testRedactionRecord{
+		ID:     42,
+		Token:  "deadbeef",
+		Nested: map[string]string{"a": "1", "b": "2"},
+		Entries: []testRedactionEntry{
+			{Command: []string{"env", "SECRET=abc", "OTHER=def"}, Out: "secret output"},
+			{Command: []string{"echo", "hunter2 and hunter3"}},
+		},
+	}
+
+	redacted, err := rules.redact([]workerutil.Record{record})
+	if err != nil {
+		t.Fatalf("unexpected error redacting: %s", err)
+	}
+
+	var have interface{}
+	if err := json.Unmarshal(redacted.(json.RawMessage), &have); err != nil {
+		t.Fatalf("unexpected error decoding redacted record: %s", err)
+	}
+	var want interface{}
+	if err := json.Unmarshal([]byte(`[{
+		"ID": 42,
+		"Token": "REDACTED",
+		"Nested": {"a": "REDACTED", "b": "REDACTED"},
+		"Entries": [
+			{"Command": ["env", "SECRET=REDACTED", "OTHER=def"], "Out": "REDACTED"},
+			{"Command": ["echo", "REDACTED and REDACTED"], "Out": "REDACTED"}
+		]
+	}]`), &want); err != nil {
+		t.Fatalf("unexpected error decoding expected record: %s", err)
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("unexpected redacted record (-want +got):\n%s", diff)
+	}
+}
+
+func TestRedactionRulesEmpty(t *testing.T) {
+	record := testRedactionRecord{ID: 42, Token: "deadbeef"}
+
+	redacted, err := RedactionRules{}.redact(record)
+	if err != nil {
+		t.Fatalf("unexpected error redacting: %s", err)
+	}
+	if diff := cmp.Diff(record, redacted); diff != "" {
+		t.Errorf("unexpected record (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetJobRedacted(t *testing.T) {
+	s := workerstoremocks.NewMockStore()
+	s.GetFunc.SetDefaultHook(func(ctx context.Context, id int) (workerutil.Record, bool, error) {
+		return testRedactionRecord{ID: id, Token: "deadbeef"}, true, nil
+	})
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{
+		AdminUsername:      "admin",
+		AdminPassword:      "hunter2",
+		UnredactedUsername: "superadmin",
+		UnredactedPassword: "hunter3",
+	}, map[string]QueueOptions{"test": {Store: s, RedactionRules: RedactionRules{FieldPaths: []string{"Token"}}}}, nil)(router)
+
+	testCases := []struct {
+		path           string
+		username       string
+		password       string
+		expectedStatus int
+		expectedToken  string
+	}{
+		{path: "/admin/test/jobs/42", username: "admin", password: "hunter2", expectedStatus: http.StatusOK, expectedToken: "REDACTED"},
+		{path: "/admin/test/jobs/42?unredacted=true", username: "admin", password: "hunter2", expectedStatus: http.StatusForbidden},
+		{path: "/admin/test/jobs/42", username: "superadmin", password: "hunter3", expectedStatus: http.StatusOK, expectedToken: "REDACTED"},
+		{path: "/admin/test/jobs/42?unredacted=true", username: "superadmin", password: "hunter3", expectedStatus: http.StatusOK, expectedToken: "deadbeef"},
+		{path: "/admin/test/jobs/42?unredacted=true", username: "superadmin", password: "hunter2", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, testCase := range testCases {
+		req := httptest.NewRequest("GET", testCase.path, nil)
+		req.SetBasicAuth(testCase.username, testCase.password)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != testCase.expectedStatus {
+			t.Errorf("unexpected status code for %s as %s. want=%d have=%d", testCase.path, testCase.username, testCase.expectedStatus, w.Code)
+			continue
+		}
+		if testCase.expectedStatus != http.StatusOK {
+			continue
+		}
+
+		var record testRedactionRecord
+		if err := json.NewDecoder(w.Body).Decode(&record); err != nil {
+			t.Fatalf("unexpected error decoding response: %s", err)
+		}
+		if record.Token != testCase.expectedToken {
+			t.Errorf("unexpected token for %s as %s. want=%q have=%q", testCase.path, testCase.username, testCase.expectedToken, record.Token)
+		}
+	}
+}
+
+func TestListJobsUnredactedWithoutCredentials(t *testing.T) {
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{AdminUsername: "admin", AdminPassword: "hunter2"}, map[string]QueueOptions{"test": {Store: workerstoremocks.NewMockStore()}}, nil)(router)
+
+	req := httptest.NewRequest("GET", "/admin/test/jobs?unredacted=true", nil)
+	req.SetBasicAuth("admin", "hunter2")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("unexpected status code. want=%d have=%d", http.StatusForbidden, w.Code)
+	}
+}
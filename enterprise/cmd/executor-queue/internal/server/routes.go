@@ -2,22 +2,112 @@ package server
 
 import (
 	"bytes"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
+	"sort"
+	"strconv"
+	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/gorilla/mux"
 	"github.com/inconshreveable/log15"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/auditlog"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/schedules"
 	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 )
 
-func setupRoutes(queueOptionsMap map[string]QueueOptions) func(router *mux.Router) {
+func setupRoutes(options ServerOptions, queueOptionsMap map[string]QueueOptions, drainer *drainer) func(router *mux.Router) {
 	return func(router *mux.Router) {
+		var adminRouter *mux.Router
+		var unredactedAccess *basicAuthCredentials
+		if options.AdminUsername != "" && options.AdminPassword != "" {
+			credentials := []basicAuthCredentials{{username: options.AdminUsername, password: options.AdminPassword}}
+			if options.UnredactedUsername != "" && options.UnredactedPassword != "" {
+				unredactedAccess = &basicAuthCredentials{username: options.UnredactedUsername, password: options.UnredactedPassword}
+				credentials = append(credentials, *unredactedAccess)
+			}
+
+			// 🚨 SECURITY: Admin routes expose job payloads and are secured by basic auth.
+			adminRouter = router.PathPrefix("/admin/").Subrouter()
+			adminRouter.Use(basicAuthMiddleware(credentials...))
+
+			if options.ExecutorStore != nil {
+				adminRouter.Path("/executors").Methods("GET").HandlerFunc(handleListExecutors(options.ExecutorStore))
+			}
+			if options.AuditLogStore != nil {
+				adminRouter.Path("/audit-log").Methods("GET").HandlerFunc(handleListAuditLog(options.AuditLogStore))
+				adminRouter.Path("/events").Methods("GET").HandlerFunc(handleStreamEvents(options.AuditLogStore, drainer, options.EventPollInterval))
+			}
+			if options.ScheduleStore != nil {
+				schedulableQueues := map[string]struct{}{}
+				for name, queueOptions := range queueOptionsMap {
+					if queueOptions.Enqueue != nil {
+						schedulableQueues[name] = struct{}{}
+					}
+				}
+
+				adminRouter.Path("/schedules").Methods("GET").HandlerFunc(handleListSchedules(options.ScheduleStore))
+				adminRouter.Path("/schedules").Methods("POST").HandlerFunc(handleCreateSchedule(options.ScheduleStore, schedulableQueues))
+				adminRouter.Path("/schedules/{id:[0-9]+}/pause").Methods("POST").HandlerFunc(handleSetSchedulePaused(options.ScheduleStore, true))
+				adminRouter.Path("/schedules/{id:[0-9]+}/resume").Methods("POST").HandlerFunc(handleSetSchedulePaused(options.ScheduleStore, false))
+				adminRouter.Path("/schedules/{id:[0-9]+}").Methods("DELETE").HandlerFunc(handleDeleteSchedule(options.ScheduleStore))
+			}
+			if options.FederationStore != nil && options.FederationParent {
+				adminRouter.Path("/federation/reports").Methods("POST").HandlerFunc(handleFederationReport(options.FederationStore))
+				adminRouter.Path("/federation/regions").Methods("GET").HandlerFunc(handleListRegionStats(options.FederationStore))
+			}
+		}
+
+		// 🚨 SECURITY: Request bodies are validated before being decoded so that executors cannot
+		// make the server buffer oversized or malformed payloads.
+		validators := map[string]*requestValidator{}
+		validator := func(path string) *requestValidator {
+			if _, ok := validators[path]; !ok {
+				validators[path] = newRequestValidator(options, path)
+			}
+			return validators[path]
+		}
+
+		handlers := make([]*handler, 0, len(queueOptionsMap))
 		for name, queueOptions := range queueOptionsMap {
 			h := newHandler(queueOptions)
+			h.queueName = name
+			h.executorStore = options.ExecutorStore
+			h.auditLogStore = options.AuditLogStore
+			h.checkpointStore = options.CheckpointStore
+			h.artifactStore = options.ArtifactStore
+			h.maxArtifactSize = options.MaxArtifactSize
+			h.pausedQueues = options.PausedQueues
+			h.concurrencyLimits = options.ConcurrencyLimits
+			h.region = options.Region
+			h.federationStore = options.FederationStore
+			h.unredactedAccess = unredactedAccess
+			h.drainer = drainer
+			handlers = append(handlers, h)
+
+			if adminRouter != nil {
+				adminSubRouter := adminRouter.PathPrefix(fmt.Sprintf("/{queueName:(?:%s)}/", regexp.QuoteMeta(name))).Subrouter()
+				adminSubRouter.Path("/jobs").Methods("GET").HandlerFunc(h.handleListJobs)
+				adminSubRouter.Path("/jobs/{id:[0-9]+}").Methods("GET").HandlerFunc(h.handleGetJob)
+				adminSubRouter.Path("/jobs/{id:[0-9]+}").Methods("DELETE").HandlerFunc(h.handleDeleteJob)
+				adminSubRouter.Path("/jobs/{id:[0-9]+}/requeue").Methods("POST").HandlerFunc(h.handleRequeueJob)
+				adminSubRouter.Path("/drain").Methods("POST").HandlerFunc(h.handleDrainQueue)
+
+				if options.FederationStore != nil {
+					adminSubRouter.Path("/jobs/{id:[0-9]+}/region").Methods("PUT").HandlerFunc(h.handleSetJobRegion)
+					adminSubRouter.Path("/jobs/{id:[0-9]+}/region").Methods("DELETE").HandlerFunc(h.handleDeleteJobRegion)
+				}
+				if options.ConcurrencyLimits != nil && options.ConcurrencyLimitStore != nil {
+					adminSubRouter.Path("/concurrency-limit").Methods("PUT").HandlerFunc(h.handleSetConcurrencyLimit(options.ConcurrencyLimitStore))
+					adminSubRouter.Path("/concurrency-limit").Methods("DELETE").HandlerFunc(h.handleResetConcurrencyLimit(options.ConcurrencyLimitStore))
+				}
+			}
 
 			subRouter := router.PathPrefix(fmt.Sprintf("/{queueName:(?:%s)}/", regexp.QuoteMeta(name))).Subrouter()
 			routes := map[string]func(w http.ResponseWriter, r *http.Request){
@@ -30,8 +120,18 @@ func setupRoutes(queueOptionsMap map[string]QueueOptions) func(router *mux.Route
 				"heartbeat":               h.handleHeartbeat,
 			}
 			for path, handler := range routes {
-				subRouter.Path(fmt.Sprintf("/%s", path)).Methods("POST").HandlerFunc(handler)
+				subRouter.Path(fmt.Sprintf("/%s", path)).Methods("POST").HandlerFunc(validator(path).middleware(handler))
+			}
+			if options.ArtifactStore != nil {
+				// Artifact uploads are streamed and restricted by MaxArtifactSize instead
+				subRouter.Path("/uploadArtifact").Methods("POST").HandlerFunc(h.handleUploadArtifact)
 			}
+			subRouter.Path("/openapi.json").Methods("GET").HandlerFunc(handleOpenAPISpec(name))
+		}
+
+		if adminRouter != nil {
+			sort.Slice(handlers, func(i, j int) bool { return handlers[i].queueName < handlers[j].queueName })
+			adminRouter.Path("/queues").Methods("GET").HandlerFunc(handleListQueues(handlers))
 		}
 	}
 }
@@ -41,7 +141,20 @@ func (h *handler) handleDequeue(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.DequeueRequest
 
 	h.wrapHandler(w, r, &payload, func() (int, interface{}, error) {
-		job, dequeued, err := h.dequeue(r.Context(), payload.ExecutorName, payload.ExecutorHostname)
+		if response, ok := h.ExecutorVersions.check(payload.ExecutorVersion); !ok {
+			return http.StatusUpgradeRequired, response, nil
+		}
+
+		if payload.NumJobs > 0 {
+			jobs, err := h.dequeueBatch(r.Context(), payload.ExecutorName, payload.ExecutorHostname, payload.ExecutorLabels, payload.ExecutorChannel, payload.NumJobs)
+			if len(jobs) == 0 {
+				return http.StatusNoContent, nil, err
+			}
+
+			return http.StatusOK, jobs, err
+		}
+
+		job, dequeued, err := h.dequeue(r.Context(), payload.ExecutorName, payload.ExecutorHostname, payload.ExecutorLabels, payload.ExecutorChannel)
 		if !dequeued {
 			return http.StatusNoContent, nil, err
 		}
@@ -75,10 +188,18 @@ func (h *handler) handleMarkComplete(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.MarkCompleteRequest
 
 	h.wrapHandler(w, r, &payload, func() (int, interface{}, error) {
-		err := h.markComplete(r.Context(), payload.ExecutorName, payload.JobID)
+		var err error
+		if payload.CacheHit != nil {
+			err = h.markCompleteFromCache(r.Context(), payload.ExecutorName, payload.JobID, *payload.CacheHit)
+		} else {
+			err = h.markComplete(r.Context(), payload.ExecutorName, payload.JobID)
+		}
 		if err == ErrUnknownJob {
 			return http.StatusNotFound, nil, nil
 		}
+		if errors.Is(err, ErrInvalidCacheHit) {
+			return http.StatusBadRequest, errorResponse{Error: err.Error()}, nil
+		}
 
 		return http.StatusNoContent, nil, err
 	})
@@ -89,7 +210,7 @@ func (h *handler) handleMarkErrored(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.MarkErroredRequest
 
 	h.wrapHandler(w, r, &payload, func() (int, interface{}, error) {
-		err := h.markErrored(r.Context(), payload.ExecutorName, payload.JobID, payload.ErrorMessage)
+		err := h.markErrored(r.Context(), payload.ExecutorName, payload.JobID, payload.ErrorMessage, payload.FailureClass)
 		if err == ErrUnknownJob {
 			return http.StatusNotFound, nil, nil
 		}
@@ -117,13 +238,356 @@ func (h *handler) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
 	var payload apiclient.HeartbeatRequest
 
 	h.wrapHandler(w, r, &payload, func() (int, interface{}, error) {
-		unknownIDs, err := h.heartbeat(r.Context(), payload.ExecutorName, payload.JobIDs)
+		executor := executors.Executor{
+			Name:            payload.ExecutorName,
+			Hostname:        payload.ExecutorHostname,
+			OS:              payload.OS,
+			Architecture:    payload.Architecture,
+			ExecutorVersion: payload.ExecutorVersion,
+		}
+
+		unknownIDs, err := h.heartbeat(r.Context(), executor, payload.JobIDs, payload.Checkpoints)
 		return http.StatusOK, unknownIDs, err
 	})
 }
 
+// GET /{queueName}/openapi.json
+func handleOpenAPISpec(queueName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, func() (int, interface{}, error) {
+			return http.StatusOK, apiclient.OpenAPISpec(queueName), nil
+		})
+	}
+}
+
+// GET /admin/{queueName}/jobs
+func (h *handler) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	h.wrapAdminHandler(w, r, func() (int, interface{}, error) {
+		query := r.URL.Query()
+
+		unredacted, ok := h.unredactedRequested(r)
+		if !ok {
+			return http.StatusForbidden, errorResponse{Error: "unredacted job records require unredacted access credentials"}, nil
+		}
+
+		filter := JobFilter{
+			State:          query.Get("state"),
+			RepositoryName: query.Get("repository"),
+		}
+		if value := query.Get("minAge"); value != "" {
+			minAge, err := time.ParseDuration(value)
+			if err != nil {
+				return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid minAge: %s", err)}, nil
+			}
+			filter.MinAge = minAge
+		}
+
+		limit, err := parseOptionalInt(query.Get("limit"))
+		if err != nil {
+			return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid limit: %s", err)}, nil
+		}
+		offset, err := parseOptionalInt(query.Get("offset"))
+		if err != nil {
+			return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid offset: %s", err)}, nil
+		}
+
+		records, err := h.listJobs(r.Context(), filter, limit, offset)
+		if errors.Is(err, ErrUnsupportedFilter) {
+			return http.StatusBadRequest, errorResponse{Error: err.Error()}, nil
+		}
+		if err != nil || unredacted {
+			return http.StatusOK, records, err
+		}
+
+		redacted, err := h.RedactionRules.redact(records)
+		return http.StatusOK, redacted, err
+	})
+}
+
+// GET /admin/{queueName}/jobs/{id}
+func (h *handler) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	h.wrapAdminHandler(w, r, func() (int, interface{}, error) {
+		unredacted, ok := h.unredactedRequested(r)
+		if !ok {
+			return http.StatusForbidden, errorResponse{Error: "unredacted job records require unredacted access credentials"}, nil
+		}
+
+		record, err := h.getJob(r.Context(), idFromRequest(r))
+		if err == ErrUnknownJob {
+			return http.StatusNotFound, nil, nil
+		}
+		if err != nil || unredacted {
+			return http.StatusOK, record, err
+		}
+
+		redacted, err := h.RedactionRules.redact(record)
+		return http.StatusOK, redacted, err
+	})
+}
+
+// unredactedRequested returns whether the given admin request asks for unredacted job records
+// via the unredacted query parameter. A false-valued second flag is returned if it does but was
+// not made with the unredacted access credentials.
+func (h *handler) unredactedRequested(r *http.Request) (unredacted, ok bool) {
+	if unredacted, _ = strconv.ParseBool(r.URL.Query().Get("unredacted")); !unredacted {
+		return false, true
+	}
+
+	// 🚨 SECURITY: Only the unredacted access credentials may read the values removed by the
+	// queue's redaction rules.
+	return true, h.unredactedAccess != nil && h.unredactedAccess.match(r)
+}
+
+// POST /admin/{queueName}/jobs/{id}/requeue
+func (h *handler) handleRequeueJob(w http.ResponseWriter, r *http.Request) {
+	h.wrapAdminHandler(w, r, func() (int, interface{}, error) {
+		err := h.requeueJob(r.Context(), adminActor(r), idFromRequest(r))
+		if err == ErrUnknownJob {
+			return http.StatusNotFound, nil, nil
+		}
+		return http.StatusNoContent, nil, err
+	})
+}
+
+// DELETE /admin/{queueName}/jobs/{id}
+func (h *handler) handleDeleteJob(w http.ResponseWriter, r *http.Request) {
+	h.wrapAdminHandler(w, r, func() (int, interface{}, error) {
+		err := h.deleteJob(r.Context(), adminActor(r), idFromRequest(r))
+		if err == ErrUnknownJob {
+			return http.StatusNotFound, nil, nil
+		}
+		return http.StatusNoContent, nil, err
+	})
+}
+
+// POST /admin/{queueName}/drain
+func (h *handler) handleDrainQueue(w http.ResponseWriter, r *http.Request) {
+	h.wrapAdminHandler(w, r, func() (int, interface{}, error) {
+		ids, err := h.drainQueue(r.Context(), adminActor(r))
+		if ids == nil {
+			ids = []int{}
+		}
+		return http.StatusOK, drainQueueResponse{JobIDs: ids}, err
+	})
+}
+
+type drainQueueResponse struct {
+	JobIDs []int `json:"jobIds"`
+}
+
+// SetConcurrencyLimitRequest is the payload of a request to override the concurrency limit of
+// a queue.
+type SetConcurrencyLimitRequest struct {
+	MaxProcessing int `json:"maxProcessing"`
+}
+
+// PUT /admin/{queueName}/concurrency-limit
+func (h *handler) handleSetConcurrencyLimit(concurrencyLimitStore ConcurrencyLimitStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var payload SetConcurrencyLimitRequest
+
+		h.wrapHandler(w, r, &payload, func() (int, interface{}, error) {
+			if payload.MaxProcessing < 0 {
+				return http.StatusBadRequest, errorResponse{Error: "maxProcessing must not be negative"}, nil
+			}
+
+			err := h.setConcurrencyLimit(r.Context(), concurrencyLimitStore, payload.MaxProcessing)
+			return http.StatusNoContent, nil, err
+		})
+	}
+}
+
+// DELETE /admin/{queueName}/concurrency-limit
+func (h *handler) handleResetConcurrencyLimit(concurrencyLimitStore ConcurrencyLimitStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.wrapAdminHandler(w, r, func() (int, interface{}, error) {
+			err := h.resetConcurrencyLimit(r.Context(), concurrencyLimitStore)
+			return http.StatusNoContent, nil, err
+		})
+	}
+}
+
+// GET /admin/queues
+func handleListQueues(handlers []*handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, func() (int, interface{}, error) {
+			stats := make([]QueueStats, 0, len(handlers))
+			for _, h := range handlers {
+				queueStats, err := h.queueStats(r.Context())
+				if err != nil {
+					return 0, nil, errors.Wrapf(err, "queue %q", h.queueName)
+				}
+				stats = append(stats, queueStats)
+			}
+
+			return http.StatusOK, stats, nil
+		})
+	}
+}
+
+// GET /admin/executors
+func handleListExecutors(executorStore ExecutorStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, func() (int, interface{}, error) {
+			query := r.URL.Query()
+
+			opts := executors.ListOptions{QueueName: query.Get("queue")}
+			if value := query.Get("maxAge"); value != "" {
+				maxAge, err := time.ParseDuration(value)
+				if err != nil {
+					return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid maxAge: %s", err)}, nil
+				}
+				opts.SeenSince = time.Now().Add(-maxAge)
+			}
+
+			list, err := executorStore.List(r.Context(), opts)
+			if list == nil {
+				list = []executors.Executor{}
+			}
+			return http.StatusOK, list, err
+		})
+	}
+}
+
+// GET /admin/audit-log
+func handleListAuditLog(auditLogStore AuditLogStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, func() (int, interface{}, error) {
+			query := r.URL.Query()
+
+			opts := auditlog.ListOptions{QueueName: query.Get("queue")}
+
+			var err error
+			if opts.JobID, err = parseOptionalInt(query.Get("jobId")); err != nil {
+				return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid jobId: %s", err)}, nil
+			}
+			if opts.Since, err = parseOptionalTime(query.Get("since")); err != nil {
+				return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid since: %s", err)}, nil
+			}
+			if opts.Until, err = parseOptionalTime(query.Get("until")); err != nil {
+				return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid until: %s", err)}, nil
+			}
+			if opts.Limit, err = parseOptionalInt(query.Get("limit")); err != nil {
+				return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid limit: %s", err)}, nil
+			}
+			if opts.Offset, err = parseOptionalInt(query.Get("offset")); err != nil {
+				return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid offset: %s", err)}, nil
+			}
+			if opts.Limit <= 0 || opts.Limit > maxAuditLogListLimit {
+				opts.Limit = maxAuditLogListLimit
+			}
+
+			entries, err := auditLogStore.List(r.Context(), opts)
+			if entries == nil {
+				entries = []auditlog.Entry{}
+			}
+			return http.StatusOK, entries, err
+		})
+	}
+}
+
+// GET /admin/schedules
+func handleListSchedules(scheduleStore ScheduleStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, func() (int, interface{}, error) {
+			list, err := scheduleStore.List(r.Context(), schedules.ListOptions{QueueName: r.URL.Query().Get("queue")})
+			if list == nil {
+				list = []schedules.Schedule{}
+			}
+			return http.StatusOK, list, err
+		})
+	}
+}
+
+// CreateScheduleRequest is the payload of a request to create a schedule.
+type CreateScheduleRequest struct {
+	QueueName string          `json:"queueName"`
+	Schedule  string          `json:"schedule"`
+	Payload   json.RawMessage `json:"payload"`
+	Paused    bool            `json:"paused"`
+}
+
+// POST /admin/schedules
+func handleCreateSchedule(scheduleStore ScheduleStore, schedulableQueues map[string]struct{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var payload CreateScheduleRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, fmt.Sprintf("Failed to unmarshal payload: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+
+		writeResponse(w, func() (int, interface{}, error) {
+			if _, ok := schedulableQueues[payload.QueueName]; !ok {
+				return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("queue %q does not support scheduled jobs", payload.QueueName)}, nil
+			}
+			nextRunAt, err := schedules.NextRunAt(payload.Schedule, time.Now())
+			if err != nil {
+				return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid schedule: %s", err)}, nil
+			}
+
+			schedule, err := scheduleStore.Create(r.Context(), schedules.Schedule{
+				QueueName: payload.QueueName,
+				Schedule:  payload.Schedule,
+				Payload:   payload.Payload,
+				Paused:    payload.Paused,
+				NextRunAt: nextRunAt,
+			})
+			return http.StatusCreated, schedule, err
+		})
+	}
+}
+
+// POST /admin/schedules/{id}/pause
+// POST /admin/schedules/{id}/resume
+func handleSetSchedulePaused(scheduleStore ScheduleStore, paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, func() (int, interface{}, error) {
+			schedule, ok, err := scheduleStore.Get(r.Context(), idFromRequest(r))
+			if err != nil || !ok {
+				return http.StatusNotFound, nil, err
+			}
+
+			// Resumed schedules next run at the first matching time from now rather than
+			// immediately making up for the runs missed while paused
+			var nextRunAt time.Time
+			if !paused {
+				if nextRunAt, err = schedules.NextRunAt(schedule.Schedule, time.Now()); err != nil {
+					return 0, nil, err
+				}
+			}
+
+			ok, err = scheduleStore.SetPaused(r.Context(), schedule.ID, paused, nextRunAt)
+			if err == nil && !ok {
+				return http.StatusNotFound, nil, nil
+			}
+			return http.StatusNoContent, nil, err
+		})
+	}
+}
+
+// DELETE /admin/schedules/{id}
+func handleDeleteSchedule(scheduleStore ScheduleStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, func() (int, interface{}, error) {
+			ok, err := scheduleStore.Delete(r.Context(), idFromRequest(r))
+			if err == nil && !ok {
+				return http.StatusNotFound, nil, nil
+			}
+			return http.StatusNoContent, nil, err
+		})
+	}
+}
+
+// maxAuditLogListLimit is the maximum number of audit log entries returned from a single
+// export request.
+const maxAuditLogListLimit = 1000
+
 type errorResponse struct {
 	Error string `json:"error"`
+
+	// Details lists the individual problems with a rejected request, such as each violation of
+	// the request schema.
+	Details []string `json:"details,omitempty"`
 }
 
 // wrapHandler decodes the request body into the given payload pointer, then calls the given
@@ -134,10 +598,23 @@ type errorResponse struct {
 // response body.
 func (h *handler) wrapHandler(w http.ResponseWriter, r *http.Request, payload interface{}, handler func() (int, interface{}, error)) {
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, fmt.Sprintf("Failed to unmarshal payload: %s", err.Error()), http.StatusBadRequest)
+		writeResponse(w, func() (int, interface{}, error) {
+			return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("failed to unmarshal payload: %s", err)}, nil
+		})
 		return
 	}
 
+	writeResponse(w, handler)
+}
+
+// wrapAdminHandler calls the given handler function and writes its response in the same
+// manner as wrapHandler. Admin requests carry their parameters in the URL, so no request
+// body is decoded.
+func (h *handler) wrapAdminHandler(w http.ResponseWriter, r *http.Request, handler func() (int, interface{}, error)) {
+	writeResponse(w, handler)
+}
+
+func writeResponse(w http.ResponseWriter, handler func() (int, interface{}, error)) {
 	status, payload, err := handler()
 	if err != nil {
 		log15.Error("Handler returned an error", "err", err)
@@ -159,3 +636,70 @@ func (h *handler) wrapHandler(w http.ResponseWriter, r *http.Request, payload in
 		_, _ = io.Copy(w, bytes.NewReader(data))
 	}
 }
+
+// basicAuthCredentials are a basic auth username and password accepted by the admin API.
+type basicAuthCredentials struct {
+	username string
+	password string
+}
+
+// match returns whether the given request carries these credentials.
+func (c basicAuthCredentials) match(r *http.Request) bool {
+	requestUsername, requestPassword, _ := r.BasicAuth()
+	return subtle.ConstantTimeCompare([]byte(requestUsername), []byte(c.username)) == 1 && subtle.ConstantTimeCompare([]byte(requestPassword), []byte(c.password)) == 1
+}
+
+// basicAuthMiddleware rejects requests that do not have a basic auth username and password matching
+// one of the expected sets of credentials.
+func basicAuthMiddleware(credentials ...basicAuthCredentials) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, _, ok := r.BasicAuth(); !ok {
+				w.Header().Add("WWW-Authenticate", `Basic realm="Sourcegraph"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			for _, c := range credentials {
+				if c.match(r) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			w.WriteHeader(http.StatusForbidden)
+		})
+	}
+}
+
+// idFromRequest returns the job identifier from the request's route variables. The route
+// pattern guarantees that the value is numeric.
+func idFromRequest(r *http.Request) int {
+	id, _ := strconv.Atoi(mux.Vars(r)["id"])
+	return id
+}
+
+// adminActor returns the audit log actor for the given admin request.
+func adminActor(r *http.Request) string {
+	username, _, _ := r.BasicAuth()
+	return "admin:" + username
+}
+
+// parseOptionalTime parses the given string as an RFC 3339 timestamp. An empty string parses
+// as the zero time.
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseOptionalInt parses the given string as an integer. An empty string parses as zero.
+func parseOptionalInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.Atoi(value)
+}
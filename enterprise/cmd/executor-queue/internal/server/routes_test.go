@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor/queueclient"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestRoutesServeExecutorRoutes(t *testing.T) {
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{ArtifactStore: NewMockArtifactStore()}, map[string]QueueOptions{"test": {Store: workerstoremocks.NewMockStore()}}, nil)(router)
+
+	for _, route := range apiclient.Routes {
+		var match mux.RouteMatch
+		if !router.Match(httptest.NewRequest("POST", "/test/"+route.Path, nil), &match) || match.MatchErr != nil {
+			t.Errorf("no handler registered for route %s", route.Name)
+		}
+	}
+}
+
+func TestQueueClient(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.DequeueFunc.SetDefaultReturn(testRecord{ID: 42}, true, nil)
+	store.HeartbeatFunc.SetDefaultReturn([]int{42}, nil)
+	recordTransformer := func(ctx context.Context, record workerutil.Record) (apiclient.Job, error) {
+		return apiclient.Job{ID: record.RecordID(), RepositoryName: "github.com/test/test"}, nil
+	}
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{}, map[string]QueueOptions{"test": {Store: store, RecordTransformer: recordTransformer}}, nil)(router)
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := queueclient.New(queueclient.Options{URL: ts.URL})
+
+	job, ok, err := client.Dequeue(context.Background(), "test", apiclient.DequeueRequest{ExecutorName: "deadbeef"})
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	}
+	if !ok {
+		t.Fatalf("expected a job to be dequeued")
+	}
+	if diff := cmp.Diff(apiclient.Job{ID: 42, RepositoryName: "github.com/test/test"}, job); diff != "" {
+		t.Errorf("unexpected job (-want +got):\n%s", diff)
+	}
+
+	knownIDs, err := client.Heartbeat(context.Background(), "test", apiclient.HeartbeatRequest{ExecutorName: "deadbeef", JobIDs: []int{42, 43}})
+	if err != nil {
+		t.Fatalf("unexpected error sending heartbeat: %s", err)
+	}
+	if diff := cmp.Diff([]int{42}, knownIDs); diff != "" {
+		t.Errorf("unexpected known job identifiers (-want +got):\n%s", diff)
+	}
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{}, map[string]QueueOptions{"test": {Store: workerstoremocks.NewMockStore()}}, nil)(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/test/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code. want=%d have=%d", http.StatusOK, w.Code)
+	}
+
+	var spec struct {
+		Paths map[string]interface{} `json:"paths"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&spec); err != nil {
+		t.Fatalf("unexpected error decoding OpenAPI document: %s", err)
+	}
+	if _, ok := spec.Paths["/test/dequeue"]; !ok {
+		t.Errorf("expected the dequeue route of the test queue to be described")
+	}
+}
+
+func TestMarkCompleteFromCache(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+
+	var hits []apiclient.CacheHit
+	completeFromCache := func(ctx context.Context, jobID int, executorName string, hit apiclient.CacheHit) (bool, error) {
+		if hit.CacheKey != "deadbeef" {
+			return false, errors.Wrap(ErrInvalidCacheHit, "cache key mismatch")
+		}
+
+		hits = append(hits, hit)
+		return jobID == 42, nil
+	}
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{}, map[string]QueueOptions{
+		"test":     {Store: store, CompleteFromCache: completeFromCache},
+		"uncached": {Store: store},
+	}, nil)(router)
+
+	testCases := []struct {
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{path: "/test/markComplete", body: `{"executorName": "e1", "jobId": 42, "cacheHit": {"cacheKey": "deadbeef", "result": "r1"}}`, expectedStatus: http.StatusNoContent},
+		{path: "/test/markComplete", body: `{"executorName": "e1", "jobId": 43, "cacheHit": {"cacheKey": "deadbeef", "result": "r1"}}`, expectedStatus: http.StatusNotFound},
+		{path: "/test/markComplete", body: `{"executorName": "e1", "jobId": 42, "cacheHit": {"cacheKey": "cafebabe", "result": "r1"}}`, expectedStatus: http.StatusBadRequest},
+		{path: "/uncached/markComplete", body: `{"executorName": "e1", "jobId": 42, "cacheHit": {"cacheKey": "deadbeef", "result": "r1"}}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, testCase := range testCases {
+		req := httptest.NewRequest("POST", testCase.path, strings.NewReader(testCase.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != testCase.expectedStatus {
+			t.Errorf("unexpected status code for %s %s. want=%d have=%d", testCase.path, testCase.body, testCase.expectedStatus, w.Code)
+		}
+	}
+
+	if len(hits) != 2 || hits[0].Result != "r1" {
+		t.Errorf("unexpected cache hits: %v", hits)
+	}
+	if value := len(store.MarkCompleteFunc.History()); value != 0 {
+		t.Errorf("unexpected number of calls to MarkComplete. want=%d have=%d", 0, value)
+	}
+}
@@ -1,9 +1,19 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"sync/atomic"
+	"time"
 
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/auditlog"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/federation"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/schedules"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/httpserver"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
@@ -13,13 +23,183 @@ import (
 // server.
 type ServerOptions struct {
 	Port int
+
+	// AdminUsername and AdminPassword are the basic auth credentials required to access
+	// the admin API. The admin routes are not registered if these values are empty.
+	AdminUsername string
+	AdminPassword string
+
+	// UnredactedUsername and UnredactedPassword are the basic auth credentials that, in addition
+	// to those of the admin API, may request unredacted job records. Unredacted job records are
+	// never returned if these values are empty.
+	UnredactedUsername string
+	UnredactedPassword string
+
+	// ShutdownTimeout is the maximum duration to wait for in-flight requests (e.g., heartbeats
+	// and job completion reports) to finish once the server begins shutting down.
+	ShutdownTimeout time.Duration
+
+	// ExecutorStore, if set, records the executors that send heartbeats and backs the
+	// admin executor listing endpoint.
+	ExecutorStore ExecutorStore
+
+	// AuditLogStore, if set, records job state transitions performed through the API and
+	// backs the admin audit log export endpoint.
+	AuditLogStore AuditLogStore
+
+	// EventPollInterval is the interval at which the admin event stream reads new entries
+	// from the audit log. Defaults to one second.
+	EventPollInterval time.Duration
+
+	// CheckpointStore, if set, persists the progress checkpoints reported by executors with
+	// their heartbeats so that a job can resume after it is reassigned to another executor.
+	CheckpointStore CheckpointStore
+
+	// ArtifactStore, if set, receives the artifacts uploaded by executors for the jobs they
+	// are processing. The artifact upload routes are not registered if this value is nil.
+	ArtifactStore ArtifactStore
+
+	// MaxArtifactSize is the maximum size, in bytes, of a single uploaded artifact.
+	MaxArtifactSize int64
+
+	// MaxRequestBodySize is the maximum size, in bytes, of the JSON body of a request to the
+	// executor API. Larger bodies are rejected with 413 Request Entity Too Large before they are
+	// read into memory in full. Endpoints carrying command output or checkpoints have larger
+	// default limits. Defaults to 1MiB.
+	MaxRequestBodySize int64
+
+	// MaxRequestBodySizes overrides the maximum request body size of individual endpoints of the
+	// executor API, keyed by path (e.g., "addExecutionLogEntry").
+	MaxRequestBodySizes map[string]int64
+
+	// AllowUnknownRequestFields, if set, accepts request bodies with properties unknown to the
+	// executor API. By default, such requests are rejected along with those missing required
+	// properties or holding values of the wrong type.
+	AllowUnknownRequestFields bool
+
+	// ScheduleStore, if set, backs the admin endpoints that create, list, pause, resume, and
+	// delete the schedules on which jobs are enqueued.
+	ScheduleStore ScheduleStore
+
+	// PausedQueues, if set, lists the queues that do not hand out new jobs. The set may be
+	// updated while the server is running.
+	PausedQueues *PausedQueues
+
+	// ConcurrencyLimits, if set, caps the number of jobs of each queue that may be processing
+	// at once. Queues at their limit hand out no new jobs.
+	ConcurrencyLimits *ConcurrencyLimits
+
+	// ConcurrencyLimitStore, if set along with ConcurrencyLimits, backs the admin endpoints
+	// that override the concurrency limit of a queue.
+	ConcurrencyLimitStore ConcurrencyLimitStore
+
+	// Region is the region served by this instance in a federated deployment. It is recorded
+	// with the heartbeats of executors so that regional statistics count only local executors.
+	Region string
+
+	// FederationStore, if set, backs the admin endpoints that restrict jobs to the instances of
+	// a region.
+	FederationStore FederationStore
+
+	// FederationParent, if set along with FederationStore, registers the admin endpoints that
+	// receive and list the statistics reported by regional instances.
+	FederationParent bool
+
+	// Tracer, if set, is used to trace incoming requests in place of the global tracer.
+	Tracer opentracing.Tracer
+}
+
+// ExecutorStore records executor heartbeats in the executor registry.
+type ExecutorStore interface {
+	UpsertHeartbeat(ctx context.Context, executor executors.Executor) error
+	List(ctx context.Context, opts executors.ListOptions) ([]executors.Executor, error)
+}
+
+// AuditLogStore records job state transitions in the audit log.
+type AuditLogStore interface {
+	Append(ctx context.Context, entry auditlog.Entry) error
+	List(ctx context.Context, opts auditlog.ListOptions) ([]auditlog.Entry, error)
+}
+
+// CheckpointStore persists the progress checkpoints of jobs.
+type CheckpointStore interface {
+	Upsert(ctx context.Context, queueName string, jobID int, data []byte) error
+	Get(ctx context.Context, queueName string, jobID int) ([]byte, bool, error)
+	Delete(ctx context.Context, queueName string, jobID int) error
+}
+
+// ScheduleStore manages the schedules on which jobs are enqueued.
+type ScheduleStore interface {
+	Create(ctx context.Context, schedule schedules.Schedule) (schedules.Schedule, error)
+	Get(ctx context.Context, id int) (schedules.Schedule, bool, error)
+	List(ctx context.Context, opts schedules.ListOptions) ([]schedules.Schedule, error)
+	SetPaused(ctx context.Context, id int, paused bool, nextRunAt time.Time) (bool, error)
+	Delete(ctx context.Context, id int) (bool, error)
+}
+
+// ConcurrencyLimitStore persists the concurrency limits overridden through the admin API.
+type ConcurrencyLimitStore interface {
+	List(ctx context.Context) (map[string]int, error)
+	Set(ctx context.Context, queueName string, maxProcessing int) error
+	Delete(ctx context.Context, queueName string) (bool, error)
+}
+
+// FederationStore persists job region tags and the statistics reported by regional instances.
+type FederationStore interface {
+	SetJobRegion(ctx context.Context, queueName string, jobID int, region string) error
+	DeleteJobRegion(ctx context.Context, queueName string, jobID int) (bool, error)
+	UpsertReport(ctx context.Context, report federation.Report) error
+	ListRegionStats(ctx context.Context, reportedSince time.Time) ([]federation.RegionStats, error)
+}
+
+// ArtifactStore writes uploaded artifacts to blob storage.
+type ArtifactStore interface {
+	Upload(ctx context.Context, key string, r io.Reader) (int64, error)
 }
 
 // NewServer returns an HTTP job queue server.
+//
+// On shutdown, the server stops handing out new jobs and waits for in-flight requests to
+// finish before closing. Dequeued jobs are not held open by the server (ownership is recorded
+// on the job record itself), so jobs being processed by executors remain valid and can be
+// reported on once a new server instance becomes available.
 func NewServer(options ServerOptions, queueOptions map[string]QueueOptions) goroutine.BackgroundRoutine {
 	addr := fmt.Sprintf(":%d", options.Port)
-	router := setupRoutes(queueOptions)
-	httpHandler := ot.Middleware(httpserver.NewHandler(router))
-	server := httpserver.NewFromAddr(addr, &http.Server{Handler: httpHandler})
-	return server
+	drainer := &drainer{}
+	router := setupRoutes(options, queueOptions, drainer)
+
+	tracer := options.Tracer
+	if tracer == nil {
+		tracer = opentracing.GlobalTracer()
+	}
+	httpHandler := ot.MiddlewareWithTracer(tracer, httpserver.NewHandler(router))
+
+	return &server{
+		BackgroundRoutine: httpserver.NewFromAddrWithShutdownTimeout(addr, &http.Server{Handler: httpHandler}, options.ShutdownTimeout),
+		drainer:           drainer,
+	}
+}
+
+type server struct {
+	goroutine.BackgroundRoutine
+	drainer *drainer
+}
+
+// Stop refuses subsequent dequeue requests then gracefully shuts down the HTTP server.
+func (s *server) Stop() {
+	s.drainer.drain()
+	s.BackgroundRoutine.Stop()
+}
+
+// drainer signals to handlers that the server is shutting down. A nil drainer is never draining.
+type drainer struct {
+	draining int32
+}
+
+func (d *drainer) drain() {
+	atomic.StoreInt32(&d.draining, 1)
+}
+
+func (d *drainer) isDraining() bool {
+	return d != nil && atomic.LoadInt32(&d.draining) == 1
 }
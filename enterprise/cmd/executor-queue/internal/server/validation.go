@@ -0,0 +1,121 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+	"github.com/xeipuuv/gojsonschema"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+)
+
+// defaultMaxRequestBodySize is the maximum size, in bytes, of the body of a request to an
+// endpoint of the executor API without a more specific default.
+const defaultMaxRequestBodySize = 1024 * 1024
+
+// defaultMaxRequestBodySizes are the maximum sizes, in bytes, of the bodies of requests to the
+// endpoints of the executor API that carry command output or checkpoints.
+var defaultMaxRequestBodySizes = map[string]int64{
+	"addExecutionLogEntry":    64 * 1024 * 1024,
+	"updateExecutionLogEntry": 64 * 1024 * 1024,
+	"heartbeat":               16 * 1024 * 1024,
+}
+
+// maxRequestBodySize returns the maximum size, in bytes, of the body of a request to the
+// endpoint with the given path.
+func (o ServerOptions) maxRequestBodySize(path string) int64 {
+	if size, ok := o.MaxRequestBodySizes[path]; ok && size > 0 {
+		return size
+	}
+	if size, ok := defaultMaxRequestBodySizes[path]; ok {
+		return size
+	}
+	if o.MaxRequestBodySize > 0 {
+		return o.MaxRequestBodySize
+	}
+
+	return defaultMaxRequestBodySize
+}
+
+// requestValidator rejects request bodies that exceed a size limit or that do not conform to the
+// JSON schema of the request type of an endpoint.
+type requestValidator struct {
+	maxSize int64
+	schema  *gojsonschema.Schema
+}
+
+// newRequestValidator creates a validator of the requests to the endpoint of the executor API
+// with the given path. Endpoints without a JSON request body are only restricted in size. The
+// request schemas are derived from the request types of the executor API, so a schema that does
+// not compile is a bug and causes a panic.
+func newRequestValidator(options ServerOptions, path string) *requestValidator {
+	v := &requestValidator{maxSize: options.maxRequestBodySize(path)}
+
+	if schema, ok := apiclient.RequestSchema(path, options.AllowUnknownRequestFields); ok {
+		compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(schema))
+		if err != nil {
+			panic(errors.Wrapf(err, "compiling request schema of %q", path))
+		}
+		v.schema = compiled
+	}
+
+	return v
+}
+
+// middleware returns a handler that reads the body of each request, up to the size limit of the
+// validator, and calls the given handler with the buffered body once it is valid. Invalid
+// requests are rejected with a 4xx status and an error response listing the violations.
+func (v *requestValidator) middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, v.maxSize))
+		if err != nil {
+			if int64(len(data)) >= v.maxSize {
+				writeResponse(w, func() (int, interface{}, error) {
+					return http.StatusRequestEntityTooLarge, errorResponse{Error: fmt.Sprintf("request body exceeds the limit of %d bytes", v.maxSize)}, nil
+				})
+				return
+			}
+
+			writeResponse(w, func() (int, interface{}, error) {
+				return http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("failed to read request body: %s", err)}, nil
+			})
+			return
+		}
+
+		if v.schema != nil {
+			if details, err := v.validate(data); err != nil || len(details) > 0 {
+				message := "request body does not match the request schema"
+				if err != nil {
+					message = fmt.Sprintf("malformed request body: %s", err)
+				}
+
+				writeResponse(w, func() (int, interface{}, error) {
+					return http.StatusBadRequest, errorResponse{Error: message, Details: details}, nil
+				})
+				return
+			}
+		}
+
+		r.Body = ioutil.NopCloser(bytes.NewReader(data))
+		next(w, r)
+	}
+}
+
+// validate returns a description of each violation of the request schema by the given body. An
+// error is returned if the body is not a JSON document.
+func (v *requestValidator) validate(data []byte) ([]string, error) {
+	result, err := v.schema.Validate(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	details := make([]string, 0, len(result.Errors()))
+	for _, resultErr := range result.Errors() {
+		details = append(details, resultErr.String())
+	}
+
+	return details, nil
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestRequestValidation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		options        ServerOptions
+		path           string
+		body           string
+		expectedStatus int
+		expectDetails  bool
+	}{
+		{
+			name:           "valid",
+			path:           "markComplete",
+			body:           `{"executorName": "deadbeef", "jobId": 42}`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "nil slices",
+			path:           "addExecutionLogEntry",
+			body:           `{"executorName": "deadbeef", "jobId": 42, "key": "step.0", "command": null}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "wrong type",
+			path:           "markComplete",
+			body:           `{"executorName": "deadbeef", "jobId": "42"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectDetails:  true,
+		},
+		{
+			name:           "unknown field",
+			path:           "markComplete",
+			body:           `{"executorName": "deadbeef", "jobId": 42, "jobID": 43}`,
+			expectedStatus: http.StatusBadRequest,
+			expectDetails:  true,
+		},
+		{
+			name:           "unknown field allowed",
+			options:        ServerOptions{AllowUnknownRequestFields: true},
+			path:           "markComplete",
+			body:           `{"executorName": "deadbeef", "jobId": 42, "jobID": 43}`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "malformed",
+			path:           "markComplete",
+			body:           `{"executorName": `,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too large",
+			options:        ServerOptions{MaxRequestBodySize: 32},
+			path:           "markComplete",
+			body:           `{"executorName": "deadbeef", "jobId": 42}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "endpoint limit",
+			options:        ServerOptions{MaxRequestBodySize: 32, MaxRequestBodySizes: map[string]int64{"markComplete": 64}},
+			path:           "markComplete",
+			body:           `{"executorName": "deadbeef", "jobId": 42}`,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "endpoint default limit",
+			options:        ServerOptions{MaxRequestBodySize: 32},
+			path:           "addExecutionLogEntry",
+			body:           `{"executorName": "deadbeef", "jobId": 42, "key": "step.0", "out": "` + strings.Repeat("x", 1024) + `"}`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			s := workerstoremocks.NewMockStore()
+			s.MarkCompleteFunc.SetDefaultReturn(true, nil)
+			s.AddExecutionLogEntryFunc.SetDefaultReturn(1, nil)
+
+			router := mux.NewRouter()
+			setupRoutes(testCase.options, map[string]QueueOptions{"test": {Store: s}}, nil)(router)
+
+			req := httptest.NewRequest("POST", "/test/"+testCase.path, strings.NewReader(testCase.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != testCase.expectedStatus {
+				t.Fatalf("unexpected status code. want=%d have=%d body=%s", testCase.expectedStatus, w.Code, w.Body.String())
+			}
+			if w.Code < 400 {
+				return
+			}
+
+			var response errorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("unexpected error decoding response: %s", err)
+			}
+			if response.Error == "" {
+				t.Errorf("expected an error message")
+			}
+			if hasDetails := len(response.Details) > 0; hasDetails != testCase.expectDetails {
+				t.Errorf("unexpected details. want=%v have=%q", testCase.expectDetails, response.Details)
+			}
+		})
+	}
+}
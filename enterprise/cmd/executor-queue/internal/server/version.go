@@ -0,0 +1,65 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/version"
+)
+
+// ExecutorVersionRange is the inclusive range of executor versions allowed to dequeue from a
+// queue. A nil bound leaves the range open on that side.
+type ExecutorVersionRange struct {
+	Min *semver.Version
+	Max *semver.Version
+}
+
+// check returns a description of why executors reporting the given version may not dequeue
+// from the queue, and a false-valued flag if they may not.
+//
+// Development builds and versions that are not semver-compatible (e.g. insiders builds)
+// cannot be ordered and are always allowed. Executors that do not report a version predate
+// the version handshake and are refused if the range has a lower bound.
+func (r ExecutorVersionRange) check(executorVersion string) (apiclient.UpgradeRequiredResponse, bool) {
+	if r.Min == nil && r.Max == nil {
+		return apiclient.UpgradeRequiredResponse{}, true
+	}
+
+	response := apiclient.UpgradeRequiredResponse{ExecutorVersion: executorVersion}
+	if r.Min != nil {
+		response.MinVersion = r.Min.String()
+	}
+	if r.Max != nil {
+		response.MaxVersion = r.Max.String()
+	}
+
+	if executorVersion == "" {
+		if r.Min == nil {
+			return apiclient.UpgradeRequiredResponse{}, true
+		}
+
+		response.Error = fmt.Sprintf("executor did not report its version; upgrade the executor to at least %s", r.Min)
+		return response, false
+	}
+
+	if version.IsDev(executorVersion) {
+		return apiclient.UpgradeRequiredResponse{}, true
+	}
+	v, err := semver.NewVersion(executorVersion)
+	if err != nil {
+		return apiclient.UpgradeRequiredResponse{}, true
+	}
+
+	if r.Min != nil && v.LessThan(r.Min) {
+		response.Error = fmt.Sprintf("executor version %s is not supported; upgrade the executor to at least %s", executorVersion, r.Min)
+		return response, false
+	}
+	if r.Max != nil && v.GreaterThan(r.Max) {
+		response.Error = fmt.Sprintf("executor version %s is not supported; downgrade the executor to at most %s or upgrade Sourcegraph", executorVersion, r.Max)
+		return response, false
+	}
+
+	return apiclient.UpgradeRequiredResponse{}, true
+}
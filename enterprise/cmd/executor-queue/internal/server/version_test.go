@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	apiclient "github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+func TestExecutorVersionRangeCheck(t *testing.T) {
+	versionRange := ExecutorVersionRange{
+		Min: semver.MustParse("3.31.0"),
+		Max: semver.MustParse("3.33.0"),
+	}
+
+	testCases := map[string]bool{
+		"":                          false,
+		"3.30.2":                    false,
+		"3.31.0":                    true,
+		"3.32.1":                    true,
+		"3.33.0":                    true,
+		"3.34.0":                    false,
+		"0.0.0+dev":                 true,
+		"106271_2021-08-24_e2fd3b0": true,
+	}
+
+	for executorVersion, expected := range testCases {
+		response, ok := versionRange.check(executorVersion)
+		if ok != expected {
+			t.Errorf("unexpected result for version %q. want=%v have=%v", executorVersion, expected, ok)
+		}
+		if !ok && (response.MinVersion != "3.31.0" || response.MaxVersion != "3.33.0" || response.Error == "") {
+			t.Errorf("unexpected response for version %q: %+v", executorVersion, response)
+		}
+	}
+}
+
+func TestExecutorVersionRangeCheckOpen(t *testing.T) {
+	if _, ok := (ExecutorVersionRange{}).check(""); !ok {
+		t.Errorf("expected an unbounded range to allow executors without a version")
+	}
+	if _, ok := (ExecutorVersionRange{Max: semver.MustParse("3.33.0")}).check(""); !ok {
+		t.Errorf("expected a range without a lower bound to allow executors without a version")
+	}
+}
+
+func TestDequeueUpgradeRequired(t *testing.T) {
+	s := workerstoremocks.NewMockStore()
+
+	router := mux.NewRouter()
+	setupRoutes(ServerOptions{}, map[string]QueueOptions{
+		"test": {Store: s, ExecutorVersions: ExecutorVersionRange{Min: semver.MustParse("3.31.0")}},
+	}, nil)(router)
+
+	req := httptest.NewRequest("POST", "/test/dequeue", strings.NewReader(`{"executorName": "deadbeef", "executorVersion": "3.30.0"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUpgradeRequired {
+		t.Fatalf("unexpected status code. want=%d have=%d", http.StatusUpgradeRequired, w.Code)
+	}
+
+	var response apiclient.UpgradeRequiredResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("unexpected error decoding response: %s", err)
+	}
+	expected := apiclient.UpgradeRequiredResponse{
+		Error:           "executor version 3.30.0 is not supported; upgrade the executor to at least 3.31.0",
+		ExecutorVersion: "3.30.0",
+		MinVersion:      "3.31.0",
+	}
+	if diff := cmp.Diff(expected, response); diff != "" {
+		t.Errorf("unexpected response (-want +got):\n%s", diff)
+	}
+
+	if value := len(s.DequeueFunc.History()); value != 0 {
+		t.Errorf("unexpected number of calls to Dequeue. want=%d have=%d", 0, value)
+	}
+}
@@ -0,0 +1,54 @@
+package slo
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+type Config struct {
+	env.BaseConfig
+
+	Interval   time.Duration
+	Thresholds Thresholds
+	WebhookURL string
+}
+
+func (c *Config) Load() {
+	c.Interval = c.GetInterval("EXECUTOR_QUEUE_SLO_INTERVAL", "1m", "Interval between evaluations of the queue alert conditions.")
+	c.Thresholds.MaxOldestQueuedJobAge = c.GetInterval("EXECUTOR_QUEUE_SLO_MAX_QUEUED_JOB_AGE", "1h", "The age of the oldest queued job of a queue above which the queue is in breach. Set to zero to disable.")
+	c.Thresholds.MaxFailurePercentage = c.GetInt("EXECUTOR_QUEUE_SLO_MAX_FAILURE_PERCENTAGE", "25", "The percentage of finished jobs of a queue marked as errored or failed within the failure window above which the queue is in breach. Set to zero to disable.")
+	c.Thresholds.FailureWindow = c.GetInterval("EXECUTOR_QUEUE_SLO_FAILURE_WINDOW", "1h", "The window over which the failure rate of a queue is computed.")
+	c.Thresholds.MinFailureSamples = c.GetInt("EXECUTOR_QUEUE_SLO_MIN_FAILURE_SAMPLES", "10", "The minimum number of finished jobs within the failure window for the failure rate of a queue to be evaluated.")
+	c.Thresholds.MaxHeartbeatGap = c.GetInterval("EXECUTOR_QUEUE_SLO_MAX_HEARTBEAT_GAP", "5m", "The time since the last heartbeat of an executor above which its queue is in breach. Set to zero to disable.")
+	c.Thresholds.HeartbeatGapLookback = c.GetInterval("EXECUTOR_QUEUE_SLO_HEARTBEAT_GAP_LOOKBACK", "1h", "Executors that have not sent a heartbeat within this duration are considered removed rather than stalled.")
+	c.WebhookURL = c.GetOptional("EXECUTOR_QUEUE_SLO_WEBHOOK_URL", "A URL to which breached and resolved alert conditions are posted. Notifications are disabled if unset.")
+
+	if c.Thresholds.MaxFailurePercentage < 0 || c.Thresholds.MaxFailurePercentage > 100 {
+		c.AddError(errors.New("EXECUTOR_QUEUE_SLO_MAX_FAILURE_PERCENTAGE must be between 0 and 100"))
+	}
+	if c.Thresholds.MaxFailurePercentage > 0 && c.Thresholds.FailureWindow <= 0 {
+		c.AddError(errors.New("EXECUTOR_QUEUE_SLO_FAILURE_WINDOW must be positive"))
+	}
+	if c.Thresholds.MaxHeartbeatGap > 0 && c.Thresholds.HeartbeatGapLookback <= c.Thresholds.MaxHeartbeatGap {
+		c.AddError(errors.New("EXECUTOR_QUEUE_SLO_HEARTBEAT_GAP_LOOKBACK must exceed EXECUTOR_QUEUE_SLO_MAX_HEARTBEAT_GAP"))
+	}
+	if c.WebhookURL != "" {
+		if u, err := url.Parse(c.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			c.AddError(errors.New("EXECUTOR_QUEUE_SLO_WEBHOOK_URL must be an http or https URL"))
+		}
+	}
+}
+
+// Notifier returns the notifier configured to receive alerts, or nil if notifications are
+// disabled.
+func (c *Config) Notifier() Notifier {
+	if c.WebhookURL == "" {
+		return nil
+	}
+
+	return NewWebhookNotifier(c.WebhookURL)
+}
@@ -0,0 +1,3 @@
+package slo
+
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/slo -i OperationCounter -i ExecutorLister -i Notifier -o mock_iface_test.go
@@ -0,0 +1,303 @@
+package slo
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/derision-test/glock"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/auditlog"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// Queue is a queue whose alert conditions are evaluated.
+type Queue struct {
+	Store apiserver.QueueBackend
+
+	// RecordQueuedAt returns the time at which the given record was enqueued. The age of the
+	// oldest queued job is not evaluated for queues without this hook.
+	RecordQueuedAt func(record workerutil.Record) time.Time
+}
+
+// OperationCounter counts the audit log entries of each operation per queue created since
+// a given time.
+type OperationCounter interface {
+	CountOperations(ctx context.Context, since time.Time) (map[string]map[string]int, error)
+}
+
+// ExecutorLister lists the executors in the executor registry.
+type ExecutorLister interface {
+	List(ctx context.Context, opts executors.ListOptions) ([]executors.Executor, error)
+}
+
+// Alert describes a queue entering or leaving breach of an alert condition.
+type Alert struct {
+	QueueName string    `json:"queue"`
+	Condition string    `json:"condition"`
+	Breached  bool      `json:"breached"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Time      time.Time `json:"time"`
+}
+
+// Notifier delivers alerts to an external system.
+type Notifier interface {
+	Notify(ctx context.Context, alerts []Alert) error
+}
+
+// monitor periodically evaluates the alert conditions of each queue, reports their values
+// to Prometheus, and notifies the notifier when a queue enters or leaves breach.
+type monitor struct {
+	queues           map[string]Queue
+	queueNames       []string
+	operationCounter OperationCounter
+	executorLister   ExecutorLister
+	thresholds       Thresholds
+	notifier         Notifier
+	isLeader         func() bool
+	clock            glock.Clock
+	valueDescs       map[string]*prometheus.Desc
+	breachedDesc     *prometheus.Desc
+	mu               sync.RWMutex
+	values           map[string]map[string]float64
+	breached         map[string]map[string]bool
+}
+
+var _ goroutine.Handler = &monitor{}
+var _ goroutine.ErrorHandler = &monitor{}
+var _ prometheus.Collector = &monitor{}
+
+// NewMonitor returns a background routine that evaluates the alert conditions of the given
+// queues against the given thresholds at the given interval. The value of each condition is
+// reported via the src_executor_queue_oldest_queued_job_age_seconds,
+// src_executor_queue_failure_rate, and src_executor_queue_heartbeat_gap_seconds gauges, and
+// whether each queue is in breach of each condition via the src_executor_queue_slo_breached
+// gauge. The notifier, if non-nil, receives an alert each time a queue enters or leaves breach.
+//
+// As with the queued count reporter, only the leading replica evaluates conditions and reports
+// values. A new leader notifies breaches that are still ongoing once more.
+func NewMonitor(queues map[string]Queue, operationCounter OperationCounter, executorLister ExecutorLister, thresholds Thresholds, notifier Notifier, isLeader func() bool, interval time.Duration, registerer prometheus.Registerer) goroutine.BackgroundRoutine {
+	m := newMonitor(queues, operationCounter, executorLister, thresholds, notifier, isLeader, glock.NewRealClock())
+	registerer.MustRegister(m)
+	return goroutine.NewPeriodicGoroutine(context.Background(), interval, m)
+}
+
+func newMonitor(queues map[string]Queue, operationCounter OperationCounter, executorLister ExecutorLister, thresholds Thresholds, notifier Notifier, isLeader func() bool, clock glock.Clock) *monitor {
+	if isLeader == nil {
+		isLeader = func() bool { return true }
+	}
+
+	queueNames := make([]string, 0, len(queues))
+	for queueName := range queues {
+		queueNames = append(queueNames, queueName)
+	}
+	sort.Strings(queueNames)
+
+	return &monitor{
+		queues:           queues,
+		queueNames:       queueNames,
+		operationCounter: operationCounter,
+		executorLister:   executorLister,
+		thresholds:       thresholds,
+		notifier:         notifier,
+		isLeader:         isLeader,
+		clock:            clock,
+		valueDescs: map[string]*prometheus.Desc{
+			ConditionOldestQueuedJobAge: prometheus.NewDesc("src_executor_queue_oldest_queued_job_age_seconds", "Time since the oldest job in the queued state was enqueued.", []string{"queue"}, nil),
+			ConditionFailureRate:        prometheus.NewDesc("src_executor_queue_failure_rate", "Fraction of recently finished jobs that were marked as errored or failed.", []string{"queue"}, nil),
+			ConditionHeartbeatGap:       prometheus.NewDesc("src_executor_queue_heartbeat_gap_seconds", "Longest time since the last heartbeat of a recently seen executor.", []string{"queue"}, nil),
+		},
+		breachedDesc: prometheus.NewDesc("src_executor_queue_slo_breached", "Whether the queue is in breach of the alert condition.", []string{"queue", "condition"}, nil),
+		breached:     map[string]map[string]bool{},
+	}
+}
+
+func (m *monitor) Handle(ctx context.Context) error {
+	if !m.isLeader() {
+		// Forget breach states so that ongoing breaches are notified again if this replica
+		// regains leadership
+		m.setValues(nil, map[string]map[string]bool{})
+		return nil
+	}
+
+	now := m.clock.Now()
+	values, breached, err := m.evaluate(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	alerts := m.changes(values, breached, now)
+	if len(alerts) > 0 && m.notifier != nil {
+		if err := m.notifier.Notify(ctx, alerts); err != nil {
+			// Keep the previous breach states so that the alerts are sent again on the next run
+			m.setValues(values, nil)
+			return errors.Wrap(err, "Notify")
+		}
+	}
+	for _, alert := range alerts {
+		log15.Warn("Queue alert condition changed", "queue", alert.QueueName, "condition", alert.Condition, "breached", alert.Breached, "value", alert.Value, "threshold", alert.Threshold)
+	}
+
+	m.setValues(values, breached)
+	return nil
+}
+
+func (m *monitor) HandleError(err error) {
+	log15.Error("Failed to evaluate queue alert conditions", "error", err)
+}
+
+// evaluate returns the value of each enabled condition of each queue, and whether each queue
+// is in breach of each of those conditions.
+func (m *monitor) evaluate(ctx context.Context, now time.Time) (values map[string]map[string]float64, breached map[string]map[string]bool, _ error) {
+	values = make(map[string]map[string]float64, len(m.queues))
+	breached = make(map[string]map[string]bool, len(m.queues))
+	for _, queueName := range m.queueNames {
+		values[queueName] = map[string]float64{}
+		breached[queueName] = map[string]bool{}
+	}
+
+	set := func(queueName, condition string, value float64, evaluated bool) {
+		values[queueName][condition] = value
+		breached[queueName][condition] = evaluated && value > m.thresholds.threshold(condition)
+	}
+
+	if m.thresholds.MaxOldestQueuedJobAge > 0 {
+		for _, queueName := range m.queueNames {
+			queue := m.queues[queueName]
+			if queue.RecordQueuedAt == nil {
+				continue
+			}
+
+			records, err := queue.Store.List(ctx, store.ListOptions{States: []string{"queued"}, Limit: 1})
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "listing queued jobs of %s", queueName)
+			}
+
+			var age time.Duration
+			if len(records) > 0 {
+				if age = now.Sub(queue.RecordQueuedAt(records[0])); age < 0 {
+					age = 0
+				}
+			}
+			set(queueName, ConditionOldestQueuedJobAge, age.Seconds(), true)
+		}
+	}
+
+	if m.thresholds.MaxFailurePercentage > 0 {
+		counts, err := m.operationCounter.CountOperations(ctx, now.Add(-m.thresholds.FailureWindow))
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "CountOperations")
+		}
+
+		for _, queueName := range m.queueNames {
+			failed := counts[queueName][auditlog.OperationMarkErrored] + counts[queueName][auditlog.OperationMarkFailed]
+			finished := failed + counts[queueName][auditlog.OperationMarkComplete]
+
+			var rate float64
+			if finished > 0 {
+				rate = float64(failed) / float64(finished)
+			}
+			set(queueName, ConditionFailureRate, rate, finished >= m.thresholds.MinFailureSamples)
+		}
+	}
+
+	if m.thresholds.MaxHeartbeatGap > 0 {
+		registered, err := m.executorLister.List(ctx, executors.ListOptions{SeenSince: now.Add(-m.thresholds.HeartbeatGapLookback)})
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "listing executors")
+		}
+
+		gaps := map[string]time.Duration{}
+		for _, executor := range registered {
+			if gap := now.Sub(executor.LastSeenAt); gap > gaps[executor.QueueName] {
+				gaps[executor.QueueName] = gap
+			}
+		}
+		for _, queueName := range m.queueNames {
+			set(queueName, ConditionHeartbeatGap, gaps[queueName].Seconds(), true)
+		}
+	}
+
+	return values, breached, nil
+}
+
+// changes returns an alert for each condition whose breach state differs from the previous
+// evaluation, in a deterministic order.
+func (m *monitor) changes(values map[string]map[string]float64, breached map[string]map[string]bool, now time.Time) (alerts []Alert) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, queueName := range m.queueNames {
+		conditions := make([]string, 0, len(breached[queueName]))
+		for condition := range breached[queueName] {
+			conditions = append(conditions, condition)
+		}
+		sort.Strings(conditions)
+
+		for _, condition := range conditions {
+			if breached[queueName][condition] == m.breached[queueName][condition] {
+				continue
+			}
+
+			alerts = append(alerts, Alert{
+				QueueName: queueName,
+				Condition: condition,
+				Breached:  breached[queueName][condition],
+				Value:     values[queueName][condition],
+				Threshold: m.thresholds.threshold(condition),
+				Time:      now,
+			})
+		}
+	}
+
+	return alerts
+}
+
+// setValues replaces the reported values and, if non-nil, the breach states.
+func (m *monitor) setValues(values map[string]map[string]float64, breached map[string]map[string]bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values = values
+	if breached != nil {
+		m.breached = breached
+	}
+}
+
+func (m *monitor) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range m.valueDescs {
+		ch <- desc
+	}
+	ch <- m.breachedDesc
+}
+
+func (m *monitor) Collect(ch chan<- prometheus.Metric) {
+	if !m.isLeader() {
+		return
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, queueName := range m.queueNames {
+		for condition, value := range m.values[queueName] {
+			ch <- prometheus.MustNewConstMetric(m.valueDescs[condition], prometheus.GaugeValue, value, queueName)
+		}
+		for condition, breached := range m.breached[queueName] {
+			var value float64
+			if breached {
+				value = 1
+			}
+			ch <- prometheus.MustNewConstMetric(m.breachedDesc, prometheus.GaugeValue, value, queueName, condition)
+		}
+	}
+}
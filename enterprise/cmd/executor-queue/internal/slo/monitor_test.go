@@ -0,0 +1,168 @@
+package slo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/derision-test/glock"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/auditlog"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	workerstoremocks "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store/mocks"
+)
+
+type testRecord struct {
+	id       int
+	queuedAt time.Time
+}
+
+func (r testRecord) RecordID() int { return r.id }
+
+var testThresholds = Thresholds{
+	MaxOldestQueuedJobAge: time.Hour,
+	MaxFailurePercentage:  25,
+	FailureWindow:         time.Hour,
+	MinFailureSamples:     4,
+	MaxHeartbeatGap:       5 * time.Minute,
+	HeartbeatGapLookback:  time.Hour,
+}
+
+func TestMonitor(t *testing.T) {
+	clock := glock.NewMockClock()
+	now := clock.Now()
+
+	codeintelStore := workerstoremocks.NewMockStore()
+	codeintelStore.ListFunc.SetDefaultReturn([]workerutil.Record{testRecord{id: 1, queuedAt: now.Add(-2 * time.Hour)}}, nil)
+	batchesStore := workerstoremocks.NewMockStore()
+
+	queues := map[string]Queue{
+		"codeintel": {Store: codeintelStore, RecordQueuedAt: func(record workerutil.Record) time.Time { return record.(testRecord).queuedAt }},
+		"batches":   {Store: batchesStore, RecordQueuedAt: func(record workerutil.Record) time.Time { return record.(testRecord).queuedAt }},
+		"insights":  {Store: workerstoremocks.NewMockStore()},
+	}
+
+	operationCounter := NewMockOperationCounter()
+	operationCounter.CountOperationsFunc.SetDefaultReturn(map[string]map[string]int{
+		// Below the minimum number of samples
+		"codeintel": {auditlog.OperationMarkFailed: 2, auditlog.OperationMarkComplete: 1},
+		"batches":   {auditlog.OperationMarkErrored: 1, auditlog.OperationMarkFailed: 1, auditlog.OperationMarkComplete: 2},
+	}, nil)
+
+	executorLister := NewMockExecutorLister()
+	executorLister.ListFunc.SetDefaultReturn([]executors.Executor{
+		{Name: "e1", QueueName: "codeintel", LastSeenAt: now.Add(-10 * time.Second)},
+		{Name: "e2", QueueName: "insights", LastSeenAt: now.Add(-10 * time.Minute)},
+		{Name: "e3", QueueName: "insights", LastSeenAt: now.Add(-time.Minute)},
+	}, nil)
+
+	notifier := NewMockNotifier()
+	m := newMonitor(queues, operationCounter, executorLister, testThresholds, notifier, nil, clock)
+
+	if err := m.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error evaluating conditions: %s", err)
+	}
+
+	expectedValues := map[string]map[string]float64{
+		"batches": {
+			ConditionOldestQueuedJobAge: 0,
+			ConditionFailureRate:        0.5,
+			ConditionHeartbeatGap:       0,
+		},
+		"codeintel": {
+			ConditionOldestQueuedJobAge: (2 * time.Hour).Seconds(),
+			ConditionFailureRate:        2.0 / 3.0,
+			ConditionHeartbeatGap:       10,
+		},
+		"insights": {
+			ConditionFailureRate:  0,
+			ConditionHeartbeatGap: (10 * time.Minute).Seconds(),
+		},
+	}
+	if diff := cmp.Diff(expectedValues, m.values); diff != "" {
+		t.Errorf("unexpected values (-want +got):\n%s", diff)
+	}
+
+	if len(notifier.NotifyFunc.History()) != 1 {
+		t.Fatalf("unexpected number of notifications. want=%d have=%d", 1, len(notifier.NotifyFunc.History()))
+	}
+	expectedAlerts := []Alert{
+		{QueueName: "batches", Condition: ConditionFailureRate, Breached: true, Value: 0.5, Threshold: 0.25, Time: now},
+		{QueueName: "codeintel", Condition: ConditionOldestQueuedJobAge, Breached: true, Value: (2 * time.Hour).Seconds(), Threshold: time.Hour.Seconds(), Time: now},
+		{QueueName: "insights", Condition: ConditionHeartbeatGap, Breached: true, Value: (10 * time.Minute).Seconds(), Threshold: (5 * time.Minute).Seconds(), Time: now},
+	}
+	if diff := cmp.Diff(expectedAlerts, notifier.NotifyFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected alerts (-want +got):\n%s", diff)
+	}
+
+	// Unchanged breach states are not notified again
+	if err := m.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error evaluating conditions: %s", err)
+	}
+	if len(notifier.NotifyFunc.History()) != 1 {
+		t.Fatalf("unexpected number of notifications. want=%d have=%d", 1, len(notifier.NotifyFunc.History()))
+	}
+
+	// Resolved breaches are notified
+	codeintelStore.ListFunc.SetDefaultReturn(nil, nil)
+	if err := m.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error evaluating conditions: %s", err)
+	}
+	if len(notifier.NotifyFunc.History()) != 2 {
+		t.Fatalf("unexpected number of notifications. want=%d have=%d", 2, len(notifier.NotifyFunc.History()))
+	}
+	expectedAlerts = []Alert{
+		{QueueName: "codeintel", Condition: ConditionOldestQueuedJobAge, Breached: false, Value: 0, Threshold: time.Hour.Seconds(), Time: now},
+	}
+	if diff := cmp.Diff(expectedAlerts, notifier.NotifyFunc.History()[1].Arg1); diff != "" {
+		t.Errorf("unexpected alerts (-want +got):\n%s", diff)
+	}
+}
+
+func TestMonitorNotifyError(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	store.ListFunc.SetDefaultReturn([]workerutil.Record{testRecord{id: 1}}, nil)
+	queues := map[string]Queue{
+		"test": {Store: store, RecordQueuedAt: func(record workerutil.Record) time.Time { return record.(testRecord).queuedAt }},
+	}
+
+	notifier := NewMockNotifier()
+	notifier.NotifyFunc.PushReturn(errors.New("webhook unavailable"))
+
+	thresholds := Thresholds{MaxOldestQueuedJobAge: time.Hour}
+	m := newMonitor(queues, NewMockOperationCounter(), NewMockExecutorLister(), thresholds, notifier, nil, glock.NewMockClockAt(time.Now()))
+
+	if err := m.Handle(context.Background()); err == nil {
+		t.Fatalf("expected an error notifying alerts")
+	}
+	if err := m.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error evaluating conditions: %s", err)
+	}
+
+	// The alert is sent again after a failed notification
+	if len(notifier.NotifyFunc.History()) != 2 {
+		t.Fatalf("unexpected number of notifications. want=%d have=%d", 2, len(notifier.NotifyFunc.History()))
+	}
+	if !m.breached["test"][ConditionOldestQueuedJobAge] {
+		t.Errorf("expected queue to be in breach")
+	}
+}
+
+func TestMonitorFollowerDoesNotEvaluate(t *testing.T) {
+	store := workerstoremocks.NewMockStore()
+	operationCounter := NewMockOperationCounter()
+	executorLister := NewMockExecutorLister()
+	queues := map[string]Queue{"test": {Store: store, RecordQueuedAt: func(record workerutil.Record) time.Time { return time.Time{} }}}
+
+	m := newMonitor(queues, operationCounter, executorLister, testThresholds, nil, func() bool { return false }, glock.NewMockClock())
+	if err := m.Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error evaluating conditions: %s", err)
+	}
+
+	if value := len(store.ListFunc.History()) + len(operationCounter.CountOperationsFunc.History()) + len(executorLister.ListFunc.History()); value != 0 {
+		t.Errorf("unexpected number of queries. want=%d have=%d", 0, value)
+	}
+}
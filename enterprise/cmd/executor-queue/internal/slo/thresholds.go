@@ -0,0 +1,52 @@
+package slo
+
+import "time"
+
+// Conditions evaluated for each queue.
+const (
+	ConditionOldestQueuedJobAge = "oldest_queued_job_age"
+	ConditionFailureRate        = "failure_rate"
+	ConditionHeartbeatGap       = "heartbeat_gap"
+)
+
+// Thresholds are the values above which a queue is in breach of an alert condition. A
+// condition whose threshold is zero is not evaluated.
+type Thresholds struct {
+	// MaxOldestQueuedJobAge is the maximum age of the oldest queued job.
+	MaxOldestQueuedJobAge time.Duration
+
+	// MaxFailurePercentage is the maximum percentage of finished jobs that were marked as
+	// errored or failed within FailureWindow.
+	MaxFailurePercentage int
+
+	// FailureWindow is the window over which the failure rate is computed.
+	FailureWindow time.Duration
+
+	// MinFailureSamples is the number of finished jobs within FailureWindow below which the
+	// failure rate is not evaluated, so that a single failure in a quiet queue does not
+	// breach the threshold.
+	MinFailureSamples int
+
+	// MaxHeartbeatGap is the maximum time since the last heartbeat of any executor of the
+	// queue that sent a heartbeat within HeartbeatGapLookback.
+	MaxHeartbeatGap time.Duration
+
+	// HeartbeatGapLookback bounds the executors considered for the heartbeat gap. Executors
+	// that were scaled down stop counting towards the gap once this duration has passed.
+	HeartbeatGapLookback time.Duration
+}
+
+// threshold returns the threshold of the given condition in the unit reported for that
+// condition: seconds for durations and a fraction for the failure rate.
+func (t Thresholds) threshold(condition string) float64 {
+	switch condition {
+	case ConditionOldestQueuedJobAge:
+		return t.MaxOldestQueuedJobAge.Seconds()
+	case ConditionFailureRate:
+		return float64(t.MaxFailurePercentage) / 100
+	case ConditionHeartbeatGap:
+		return t.MaxHeartbeatGap.Seconds()
+	}
+
+	return 0
+}
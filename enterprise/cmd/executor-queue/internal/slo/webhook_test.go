@@ -0,0 +1,47 @@
+package slo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	alerts := []Alert{
+		{QueueName: "codeintel", Condition: ConditionFailureRate, Breached: true, Value: 0.5, Threshold: 0.25, Time: time.Unix(1600000000, 0).UTC()},
+	}
+
+	var payload webhookPayload
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("unexpected error decoding payload: %s", err)
+		}
+	}))
+	defer ts.Close()
+
+	if err := NewWebhookNotifier(ts.URL).Notify(context.Background(), alerts); err != nil {
+		t.Fatalf("unexpected error notifying: %s", err)
+	}
+	if diff := cmp.Diff(alerts, payload.Alerts); diff != "" {
+		t.Errorf("unexpected alerts (-want +got):\n%s", diff)
+	}
+}
+
+func TestWebhookNotifierErrorStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	if err := NewWebhookNotifier(ts.URL).Notify(context.Background(), []Alert{{QueueName: "test"}}); err == nil {
+		t.Fatalf("expected an error notifying")
+	}
+}
@@ -0,0 +1,62 @@
+package telemetry
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+type Config struct {
+	env.BaseConfig
+
+	TracesEndpoint  string
+	MetricsEndpoint string
+	MetricsInterval time.Duration
+	Headers         map[string]string
+}
+
+func (c *Config) Load() {
+	c.TracesEndpoint = c.GetOptional("EXECUTOR_QUEUE_OTLP_TRACES_ENDPOINT", "The OTLP/HTTP URL to which traces are exported, e.g. http://otel-collector:4318/v1/traces. Traces are sent to the Jaeger tracer configured via site configuration if unset.")
+	c.MetricsEndpoint = c.GetOptional("EXECUTOR_QUEUE_OTLP_METRICS_ENDPOINT", "The OTLP/HTTP URL to which metrics are exported, e.g. http://otel-collector:4318/v1/metrics. Metrics are only exposed for Prometheus scrapes if unset.")
+	c.MetricsInterval = c.GetInterval("EXECUTOR_QUEUE_OTLP_METRICS_INTERVAL", "30s", "Interval between metric exports to the OTLP metrics endpoint.")
+
+	for name, endpoint := range map[string]string{
+		"EXECUTOR_QUEUE_OTLP_TRACES_ENDPOINT":  c.TracesEndpoint,
+		"EXECUTOR_QUEUE_OTLP_METRICS_ENDPOINT": c.MetricsEndpoint,
+	} {
+		if endpoint == "" {
+			continue
+		}
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			c.AddError(errors.Errorf("%s must be an http or https URL", name))
+		}
+	}
+
+	headers, err := parseHeaders(c.GetOptional("EXECUTOR_QUEUE_OTLP_HEADERS", "A comma-separated list of key=value pairs sent as headers with each OTLP export request, e.g. for collector authentication."))
+	if err != nil {
+		c.AddError(errors.Wrap(err, "EXECUTOR_QUEUE_OTLP_HEADERS"))
+	}
+	c.Headers = headers
+}
+
+// parseHeaders parses a comma-separated list of key=value pairs.
+func parseHeaders(value string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, errors.Errorf("malformed header %q: expected key=value", pair)
+		}
+		headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return headers, nil
+}
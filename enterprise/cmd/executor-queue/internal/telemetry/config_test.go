@@ -0,0 +1,30 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseHeaders(t *testing.T) {
+	headers, err := parseHeaders("authorization=Bearer abc=, x-scope-orgid = sourcegraph ,")
+	if err != nil {
+		t.Fatalf("unexpected error parsing headers: %s", err)
+	}
+
+	expected := map[string]string{
+		"authorization": "Bearer abc=",
+		"x-scope-orgid": "sourcegraph",
+	}
+	if diff := cmp.Diff(expected, headers); diff != "" {
+		t.Errorf("unexpected headers (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseHeadersMalformed(t *testing.T) {
+	for _, value := range []string{"authorization", "=value", "a=b,c"} {
+		if _, err := parseHeaders(value); err == nil {
+			t.Errorf("expected an error parsing %q", value)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// exportTimeout is the maximum duration of a single metrics export request.
+const exportTimeout = 10 * time.Second
+
+// metricsExporter periodically pushes the metrics of a Prometheus gatherer to an OTLP/HTTP
+// metrics endpoint, so that a collector can receive them without scraping this service.
+type metricsExporter struct {
+	gatherer  prometheus.Gatherer
+	endpoint  string
+	headers   map[string]string
+	resource  *resourcepb.Resource
+	startTime time.Time
+	client    *http.Client
+}
+
+var _ goroutine.Handler = &metricsExporter{}
+var _ goroutine.ErrorHandler = &metricsExporter{}
+
+// NewMetricsExporter returns a background routine that exports the metrics of the given
+// gatherer to the OTLP/HTTP metrics endpoint of the given configuration. Counters and
+// histograms are exported as cumulative values, as they are reported to Prometheus.
+func NewMetricsExporter(gatherer prometheus.Gatherer, config *Config, res *resource.Resource) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(context.Background(), config.MetricsInterval, newMetricsExporter(gatherer, config, res, time.Now()))
+}
+
+func newMetricsExporter(gatherer prometheus.Gatherer, config *Config, res *resource.Resource, startTime time.Time) *metricsExporter {
+	return &metricsExporter{
+		gatherer:  gatherer,
+		endpoint:  config.MetricsEndpoint,
+		headers:   config.Headers,
+		resource:  &resourcepb.Resource{Attributes: convertAttributes(res.Attributes())},
+		startTime: startTime,
+		client:    &http.Client{Timeout: exportTimeout},
+	}
+}
+
+func (e *metricsExporter) Handle(ctx context.Context) error {
+	families, err := e.gatherer.Gather()
+	if err != nil {
+		return errors.Wrap(err, "Gather")
+	}
+
+	payload, err := proto.Marshal(&collectormetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{
+			{
+				Resource: e.resource,
+				InstrumentationLibraryMetrics: []*metricspb.InstrumentationLibraryMetrics{
+					{Metrics: convertMetricFamilies(families, e.startTime, time.Now())},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		content, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("unexpected status code %d from OTLP metrics endpoint: %s", resp.StatusCode, content)
+	}
+
+	return nil
+}
+
+func (e *metricsExporter) HandleError(err error) {
+	log15.Error("Failed to export metrics", "error", err)
+}
+
+// convertMetricFamilies converts gathered Prometheus metric families into OTLP metrics. Counters
+// become monotonic cumulative sums, gauges and untyped metrics become gauges, and histograms
+// and summaries keep their shape.
+func convertMetricFamilies(families []*dto.MetricFamily, startTime, now time.Time) []*metricspb.Metric {
+	start := uint64(startTime.UnixNano())
+	timestamp := uint64(now.UnixNano())
+
+	metrics := make([]*metricspb.Metric, 0, len(families))
+	for _, family := range families {
+		metric := &metricspb.Metric{Name: family.GetName(), Description: family.GetHelp()}
+
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			points := make([]*metricspb.NumberDataPoint, 0, len(family.Metric))
+			for _, m := range family.Metric {
+				points = append(points, numberDataPoint(m, m.GetCounter().GetValue(), start, timestamp))
+			}
+			metric.Data = &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+				DataPoints:             points,
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				IsMonotonic:            true,
+			}}
+
+		case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+			points := make([]*metricspb.NumberDataPoint, 0, len(family.Metric))
+			for _, m := range family.Metric {
+				value := m.GetGauge().GetValue()
+				if family.GetType() == dto.MetricType_UNTYPED {
+					value = m.GetUntyped().GetValue()
+				}
+				points = append(points, numberDataPoint(m, value, 0, timestamp))
+			}
+			metric.Data = &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{DataPoints: points}}
+
+		case dto.MetricType_HISTOGRAM:
+			points := make([]*metricspb.HistogramDataPoint, 0, len(family.Metric))
+			for _, m := range family.Metric {
+				points = append(points, histogramDataPoint(m, start, timestamp))
+			}
+			metric.Data = &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+				DataPoints:             points,
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			}}
+
+		case dto.MetricType_SUMMARY:
+			points := make([]*metricspb.SummaryDataPoint, 0, len(family.Metric))
+			for _, m := range family.Metric {
+				points = append(points, summaryDataPoint(m, start, timestamp))
+			}
+			metric.Data = &metricspb.Metric_Summary{Summary: &metricspb.Summary{DataPoints: points}}
+
+		default:
+			continue
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	return metrics
+}
+
+func numberDataPoint(m *dto.Metric, value float64, start, timestamp uint64) *metricspb.NumberDataPoint {
+	return &metricspb.NumberDataPoint{
+		Attributes:        convertLabels(m.Label),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      timestamp,
+		Value:             &metricspb.NumberDataPoint_AsDouble{AsDouble: value},
+	}
+}
+
+// histogramDataPoint converts a Prometheus histogram, whose buckets count all observations up
+// to their upper bound, into an OTLP histogram, whose buckets count the observations between
+// consecutive bounds and end with an overflow bucket.
+func histogramDataPoint(m *dto.Metric, start, timestamp uint64) *metricspb.HistogramDataPoint {
+	histogram := m.GetHistogram()
+
+	bounds := make([]float64, 0, len(histogram.Bucket))
+	counts := make([]uint64, 0, len(histogram.Bucket)+1)
+	var previous uint64
+	for _, bucket := range histogram.Bucket {
+		bounds = append(bounds, bucket.GetUpperBound())
+		counts = append(counts, bucket.GetCumulativeCount()-previous)
+		previous = bucket.GetCumulativeCount()
+	}
+	counts = append(counts, histogram.GetSampleCount()-previous)
+
+	return &metricspb.HistogramDataPoint{
+		Attributes:        convertLabels(m.Label),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      timestamp,
+		Count:             histogram.GetSampleCount(),
+		Sum:               histogram.GetSampleSum(),
+		BucketCounts:      counts,
+		ExplicitBounds:    bounds,
+	}
+}
+
+func summaryDataPoint(m *dto.Metric, start, timestamp uint64) *metricspb.SummaryDataPoint {
+	summary := m.GetSummary()
+
+	quantiles := make([]*metricspb.SummaryDataPoint_ValueAtQuantile, 0, len(summary.Quantile))
+	for _, quantile := range summary.Quantile {
+		quantiles = append(quantiles, &metricspb.SummaryDataPoint_ValueAtQuantile{
+			Quantile: quantile.GetQuantile(),
+			Value:    quantile.GetValue(),
+		})
+	}
+
+	return &metricspb.SummaryDataPoint{
+		Attributes:        convertLabels(m.Label),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      timestamp,
+		Count:             summary.GetSampleCount(),
+		Sum:               summary.GetSampleSum(),
+		QuantileValues:    quantiles,
+	}
+}
+
+func convertLabels(labels []*dto.LabelPair) []*commonpb.KeyValue {
+	attributes := make([]*commonpb.KeyValue, 0, len(labels))
+	for _, label := range labels {
+		attributes = append(attributes, &commonpb.KeyValue{
+			Key:   label.GetName(),
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: label.GetValue()}},
+		})
+	}
+
+	return attributes
+}
+
+func convertAttributes(attrs []attribute.KeyValue) []*commonpb.KeyValue {
+	attributes := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		attributes = append(attributes, &commonpb.KeyValue{
+			Key:   string(attr.Key),
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: attr.Value.Emit()}},
+		})
+	}
+	sort.Slice(attributes, func(i, j int) bool { return attributes[i].Key < attributes[j].Key })
+
+	return attributes
+}
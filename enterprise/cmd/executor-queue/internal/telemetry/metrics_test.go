@@ -0,0 +1,155 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestConvertMetricFamilies(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_total", Help: "Test counter."}, []string{"queue"})
+	counter.WithLabelValues("batches").Add(3)
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge", Help: "Test gauge."})
+	gauge.Set(1.5)
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_seconds", Help: "Test histogram.", Buckets: []float64{1, 5}})
+	for _, value := range []float64{0.5, 2, 3, 10} {
+		histogram.Observe(value)
+	}
+	registry.MustRegister(counter, gauge, histogram)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error gathering metrics: %s", err)
+	}
+
+	start := time.Unix(1000, 0)
+	now := time.Unix(1060, 0)
+	metrics := convertMetricFamilies(families, start, now)
+
+	expected := []*metricspb.Metric{
+		{
+			Name:        "test_gauge",
+			Description: "Test gauge.",
+			Data: &metricspb.Metric_Gauge{Gauge: &metricspb.Gauge{DataPoints: []*metricspb.NumberDataPoint{
+				{TimeUnixNano: uint64(now.UnixNano()), Value: &metricspb.NumberDataPoint_AsDouble{AsDouble: 1.5}},
+			}}},
+		},
+		{
+			Name:        "test_seconds",
+			Description: "Test histogram.",
+			Data: &metricspb.Metric_Histogram{Histogram: &metricspb.Histogram{
+				DataPoints: []*metricspb.HistogramDataPoint{
+					{
+						StartTimeUnixNano: uint64(start.UnixNano()),
+						TimeUnixNano:      uint64(now.UnixNano()),
+						Count:             4,
+						Sum:               15.5,
+						BucketCounts:      []uint64{1, 2, 1},
+						ExplicitBounds:    []float64{1, 5},
+					},
+				},
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			}},
+		},
+		{
+			Name:        "test_total",
+			Description: "Test counter.",
+			Data: &metricspb.Metric_Sum{Sum: &metricspb.Sum{
+				DataPoints: []*metricspb.NumberDataPoint{
+					{
+						Attributes: []*commonpb.KeyValue{
+							{Key: "queue", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "batches"}}},
+						},
+						StartTimeUnixNano: uint64(start.UnixNano()),
+						TimeUnixNano:      uint64(now.UnixNano()),
+						Value:             &metricspb.NumberDataPoint_AsDouble{AsDouble: 3},
+					},
+				},
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				IsMonotonic:            true,
+			}},
+		},
+	}
+	if diff := cmp.Diff(expected, metrics, protocmp.Transform()); diff != "" {
+		t.Errorf("unexpected metrics (-want +got):\n%s", diff)
+	}
+}
+
+func TestMetricsExporterHandle(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge", Help: "Test gauge."})
+	gauge.Set(42)
+	registry.MustRegister(gauge)
+
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r
+		bodies <- body
+	}))
+	defer ts.Close()
+
+	config := &Config{
+		MetricsEndpoint: ts.URL + "/v1/metrics",
+		Headers:         map[string]string{"Authorization": "Bearer token"},
+	}
+	res := resource.NewWithAttributes("", attribute.String("service.name", "executor-queue"))
+
+	if err := newMetricsExporter(registry, config, res, time.Now()).Handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error exporting metrics: %s", err)
+	}
+
+	r := <-requests
+	if r.URL.Path != "/v1/metrics" {
+		t.Errorf("unexpected path. want=%q have=%q", "/v1/metrics", r.URL.Path)
+	}
+	if value := r.Header.Get("Content-Type"); value != "application/x-protobuf" {
+		t.Errorf("unexpected content type. want=%q have=%q", "application/x-protobuf", value)
+	}
+	if value := r.Header.Get("Authorization"); value != "Bearer token" {
+		t.Errorf("unexpected authorization header. want=%q have=%q", "Bearer token", value)
+	}
+
+	var payload collectormetricspb.ExportMetricsServiceRequest
+	if err := proto.Unmarshal(<-bodies, &payload); err != nil {
+		t.Fatalf("unexpected error decoding payload: %s", err)
+	}
+	if len(payload.ResourceMetrics) != 1 {
+		t.Fatalf("unexpected number of resource metrics. want=%d have=%d", 1, len(payload.ResourceMetrics))
+	}
+	if attrs := payload.ResourceMetrics[0].Resource.Attributes; len(attrs) != 1 || attrs[0].Value.GetStringValue() != "executor-queue" {
+		t.Errorf("unexpected resource attributes: %v", attrs)
+	}
+	metrics := payload.ResourceMetrics[0].InstrumentationLibraryMetrics[0].Metrics
+	if len(metrics) != 1 || metrics[0].GetGauge().DataPoints[0].GetAsDouble() != 42 {
+		t.Errorf("unexpected metrics: %v", metrics)
+	}
+}
+
+func TestMetricsExporterHandleErrorStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	exporter := newMetricsExporter(prometheus.NewRegistry(), &Config{MetricsEndpoint: ts.URL}, resource.Empty(), time.Now())
+	if err := exporter.Handle(context.Background()); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
@@ -0,0 +1,18 @@
+package telemetry
+
+import (
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+
+	"github.com/sourcegraph/sourcegraph/internal/version"
+)
+
+// NewResource returns the resource describing this replica in exported traces and metrics.
+func NewResource(replicaID string) *resource.Resource {
+	return resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String("executor-queue"),
+		semconv.ServiceInstanceIDKey.String(replicaID),
+		semconv.ServiceVersionKey.String(version.Version()),
+	)
+}
@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing/opentracing-go"
+	otbridge "go.opentelemetry.io/otel/bridge/opentracing"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// shutdownTimeout is the maximum duration spent flushing buffered spans on shutdown.
+const shutdownTimeout = 5 * time.Second
+
+// NewTracer returns an opentracing tracer that exports spans to the OTLP/HTTP traces endpoint
+// of the given configuration in batches, along with a background routine that flushes any
+// buffered spans when stopped. Whether a span is recorded at all is still governed by the
+// tracing policy of the site configuration.
+func NewTracer(ctx context.Context, config *Config, res *resource.Resource) (opentracing.Tracer, goroutine.BackgroundRoutine, error) {
+	u, err := url.Parse(config.TracesEndpoint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(u.Path),
+		otlptracehttp.WithHeaders(config.Headers),
+	}
+	if u.Scheme == "http" {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	tracer := otbridge.NewBridgeTracer()
+	tracer.SetOpenTelemetryTracer(provider.Tracer("github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue"))
+	tracer.SetTextMapPropagator(propagation.TraceContext{})
+	tracer.SetWarningHandler(func(msg string) { log15.Debug("OpenTelemetry bridge warning", "message", msg) })
+
+	return tracer, &tracerFlusher{provider: provider}, nil
+}
+
+// tracerFlusher is a background routine that shuts down a tracer provider when stopped.
+type tracerFlusher struct {
+	provider *sdktrace.TracerProvider
+}
+
+func (f *tracerFlusher) Start() {}
+
+func (f *tracerFlusher) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := f.provider.Shutdown(ctx); err != nil {
+		log15.Error("Failed to flush spans", "error", err)
+	}
+}
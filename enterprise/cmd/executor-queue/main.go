@@ -3,25 +3,49 @@ package main
 import (
 	"context"
 	"database/sql"
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"github.com/inconshreveable/log15"
 	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/admincli"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/artifacts"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/auditlog"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/checkpoints"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/concurrencylimits"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/config"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/executors"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/federation"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/health"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/janitor"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/leader"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/metrics"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/queues/batches"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/queues/codeintel"
+	insightsqueue "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/queues/insights"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/schedules"
 	apiserver "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/server"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/slo"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor-queue/internal/telemetry"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/logging"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/tracer"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
 type configuration interface {
@@ -30,11 +54,27 @@ type configuration interface {
 }
 
 func main() {
+	// Operators can run admin commands against the executor-queue from within its container
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		if err := admincli.Run(context.Background(), os.Args[2:], os.Stdout); err != nil {
+			if err != flag.ErrHelp {
+				fmt.Fprintf(os.Stderr, "error: %s\n", err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
 	serviceConfig := &Config{}
 	sharedConfig := &config.SharedConfig{}
 	codeintelConfig := &codeintel.Config{Shared: sharedConfig}
 	batchesConfig := &batches.Config{Shared: sharedConfig}
-	configs := []configuration{serviceConfig, sharedConfig, codeintelConfig, batchesConfig}
+	insightsConfig := &insightsqueue.Config{Shared: sharedConfig}
+	artifactsConfig := &artifacts.Config{}
+	sloConfig := &slo.Config{}
+	telemetryConfig := &telemetry.Config{}
+	federationConfig := &federation.Config{}
+	configs := []configuration{serviceConfig, sharedConfig, codeintelConfig, batchesConfig, insightsConfig, artifactsConfig, sloConfig, telemetryConfig, federationConfig}
 
 	for _, config := range configs {
 		config.Load()
@@ -54,65 +94,289 @@ func main() {
 	}
 
 	// Initialize tracing/metrics
+	var telemetryRoutines []goroutine.BackgroundRoutine
+	telemetryResource := telemetry.NewResource(serviceConfig.ReplicaID)
+
+	tracer := opentracing.GlobalTracer()
+	if telemetryConfig.TracesEndpoint != "" {
+		otlpTracer, flusher, err := telemetry.NewTracer(context.Background(), telemetryConfig, telemetryResource)
+		if err != nil {
+			log.Fatalf("failed to create OTLP trace exporter: %s", err)
+		}
+		tracer = otlpTracer
+		telemetryRoutines = append(telemetryRoutines, flusher)
+	}
+	if telemetryConfig.MetricsEndpoint != "" {
+		telemetryRoutines = append(telemetryRoutines, telemetry.NewMetricsExporter(prometheus.DefaultGatherer, telemetryConfig, telemetryResource))
+	}
+
 	observationContext := &observation.Context{
 		Logger:     log15.Root(),
-		Tracer:     &trace.Tracer{Tracer: opentracing.GlobalTracer()},
+		Tracer:     &trace.Tracer{Tracer: tracer},
 		Registerer: prometheus.DefaultRegisterer,
 	}
 
-	// Start debug server
-	ready := make(chan struct{})
-	go debugserver.NewServerRoutine(ready).Start()
+	// Start debug server, which reports the service as ready once the database is reachable
+	// and migrated and each queue has been initialized
+	readiness := debugserver.NewReadiness()
+	go debugserver.NewServerRoutineWithReadiness(readiness).Start()
+
+	if err := keyring.Init(context.Background()); err != nil {
+		log.Fatalf("Failed to intialise keyring: %v", err)
+	}
 
 	// Connect to databases
 	db := connectToDatabase()
+	insightsDB := connectToInsightsDatabase()
 
-	// Migrations may take a while, but after they're done we'll immediately
-	// spin up a server and can accept traffic. Inform external clients we'll
-	// be ready for traffic.
-	close(ready)
+	healthChecker, err := health.NewChecker(db, dbconn.Frontend, readiness)
+	if err != nil {
+		log.Fatalf("failed to create health checker: %s", err)
+	}
 
 	// Initialize queues
 	queueOptions := map[string]apiserver.QueueOptions{
 		"codeintel": codeintel.QueueOptions(db, codeintelConfig, observationContext),
-		"batches":   batches.QueueOptions(db, batchesConfig, observationContext),
+		"batches":   batches.QueueOptions(db, batchesConfig, keyring.Default().BatchChangesCredentialKey, observationContext),
+	}
+	if insightsDB != nil {
+		queueOptions["insights"] = insightsqueue.QueueOptions(db, insightsDB, insightsConfig, observationContext)
 	}
 
+	// Serve queued counts and job listings from the read replica, if one is configured
+	if replicaDB := connectToReadReplica(serviceConfig.ReadReplicaDSN); replicaDB != nil {
+		// The replica stores register their operation metrics under a distinct prefix so that
+		// they do not collide with those of the primary stores
+		replicaObservationContext := &observation.Context{
+			Logger:     observationContext.Logger,
+			Tracer:     observationContext.Tracer,
+			Registerer: prometheus.WrapRegistererWithPrefix("read_replica_", prometheus.DefaultRegisterer),
+		}
+
+		replicaStores := map[string]dbworkerstore.Store{
+			"codeintel": codeintel.WorkerStore(replicaDB, codeintelConfig, replicaObservationContext),
+			"batches":   batches.WorkerStore(replicaDB, batchesConfig, keyring.Default().BatchChangesCredentialKey, replicaObservationContext),
+		}
+		if insightsDB != nil {
+			replicaStores["insights"] = insightsqueue.WorkerStore(replicaDB, insightsDB, insightsConfig, replicaObservationContext)
+		}
+
+		for queueName, options := range queueOptions {
+			options.Store = apiserver.NewReadReplicaStore(options.Store, replicaStores[queueName])
+			queueOptions[queueName] = options
+		}
+	}
+
+	// Replace the Postgres backend of queues configured to store their jobs elsewhere
 	for queueName, options := range queueOptions {
-		// Make local copy of queue name for capture below
-		queueName, store := queueName, options.Store
-
-		prometheus.DefaultRegisterer.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
-			Name:        "src_executor_total",
-			Help:        "Total number of jobs in the queued state.",
-			ConstLabels: map[string]string{"queue": queueName},
-		}, func() float64 {
-			// TODO(efritz) - do not count soft-deleted code intel index records
-			count, err := store.QueuedCount(context.Background(), nil)
-			if err != nil {
-				log15.Error("Failed to get queued job count", "queue", queueName, "error", err)
-			}
+		backend, err := apiserver.NewQueueBackend(sharedConfig.QueueBackend(queueName), queueName, options.Store)
+		if err != nil {
+			log.Fatalf("failed to create backend of queue %q: %s", queueName, err)
+		}
+		options.Store = backend
+		queueOptions[queueName] = options
+	}
+
+	dequeueLatency := newQueueHistogram("src_executor_queue_dequeue_duration_seconds", "Time taken to serve a dequeue request.")
+	timeInQueue := newQueueHistogram("src_executor_queue_time_in_queue_seconds", "Time between a job being enqueued and being dequeued.")
+	processingDuration := newQueueHistogram("src_executor_queue_processing_duration_seconds", "Time between a job being dequeued and being marked as completed, errored, or failed.")
+
+	for queueName, options := range queueOptions {
+		options.Metrics = apiserver.QueueMetrics{
+			DequeueLatency:     dequeueLatency.WithLabelValues(queueName),
+			TimeInQueue:        timeInQueue.WithLabelValues(queueName),
+			ProcessingDuration: processingDuration.WithLabelValues(queueName),
+		}
+		options.ExecutorVersions = sharedConfig.ExecutorVersionRange(queueName)
+		options.RedactionRules = options.RedactionRules.With(sharedConfig.RedactionRules[queueName])
+		if condition := federation.DequeueCondition(federationConfig.Role, federationConfig.Region, queueName); condition != nil {
+			options.DequeueConditions = federation.WithDequeueCondition(options.DequeueConditions, condition)
+		}
+		queueOptions[queueName] = options
+		readiness.SetReady(health.QueueComponent(queueName))
+	}
+
+	// Elect a single replica to report queue-wide metrics
+	elector := leader.NewElector(db, serviceConfig.ReplicaID)
+
+	executorStore := executors.NewStore(db)
+	serverOptions := serviceConfig.ServerOptions()
+	serverOptions.ExecutorStore = executorStore
+	auditLogStore := auditlog.NewStore(db)
+	serverOptions.AuditLogStore = auditLogStore
+	serverOptions.CheckpointStore = checkpoints.NewStore(db)
+	scheduleStore := schedules.NewStore(db)
+	serverOptions.ScheduleStore = scheduleStore
+
+	artifactStore, err := artifacts.NewStore(context.Background(), artifactsConfig, observationContext)
+	if err != nil {
+		log.Fatalf("failed to create artifact store: %s", err)
+	}
+	serverOptions.ArtifactStore = artifactStore
+	serverOptions.MaxArtifactSize = artifactsConfig.MaxSize
+	serverOptions.PausedQueues = watchPausedQueues()
+	concurrencyLimitStore := concurrencylimits.NewStore(db)
+	serverOptions.ConcurrencyLimits = apiserver.NewConcurrencyLimits(sharedConfig.MaxProcessing)
+	serverOptions.ConcurrencyLimitStore = concurrencyLimitStore
+	if federationConfig.Role != federation.RoleNone {
+		serverOptions.Region = federationConfig.Region
+		serverOptions.FederationStore = federation.NewStore(db)
+		serverOptions.FederationParent = federationConfig.Role == federation.RoleParent
+	}
+	serverOptions.Tracer = tracer
+
+	queueNames := make([]string, 0, len(queueOptions))
+	queueStores := map[string]apiserver.QueueBackend{}
+	enqueuers := map[string]schedules.Enqueuer{}
+	sloQueues := map[string]slo.Queue{}
+	for queueName, options := range queueOptions {
+		queueNames = append(queueNames, queueName)
+		queueStores[queueName] = options.Store
+		sloQueues[queueName] = slo.Queue{Store: options.Store, RecordQueuedAt: options.RecordQueuedAt}
+
+		if options.Enqueue != nil {
+			enqueuers[queueName] = options.Enqueue
+		}
+	}
+
+	// Report per-executor job counts for the executors in the live registry
+	executorJobsReporter := metrics.NewExecutorJobsReporter(executorStore, queueStores, serviceConfig.ExecutorActiveThreshold, elector.IsLeader, prometheus.DefaultRegisterer)
+	for queueName, options := range queueOptions {
+		options.Metrics.JobCompleted = executorJobsReporter.JobCompletedFunc(queueName)
+		queueOptions[queueName] = options
+	}
 
-			return float64(count)
-		}))
+	routines := []goroutine.BackgroundRoutine{
+		apiserver.NewServer(serverOptions, queueOptions),
+		elector.NewRoutine(serviceConfig.LeaderElectionInterval),
+		metrics.NewActiveExecutorsReporter(executorStore, queueNames, serviceConfig.ExecutorActiveThreshold, elector.IsLeader, serviceConfig.QueuedCountRefreshInterval, prometheus.DefaultRegisterer),
+		executorJobsReporter.NewRoutine(serviceConfig.QueuedCountRefreshInterval),
+		janitor.NewExecutorPruner(executorStore, serviceConfig.ExecutorRetention, sharedConfig.JanitorInterval),
+		schedules.NewScheduler(scheduleStore, enqueuers, elector.IsLeader, serviceConfig.SchedulerInterval),
+		slo.NewMonitor(sloQueues, auditLogStore, executorStore, sloConfig.Thresholds, sloConfig.Notifier(), elector.IsLeader, sloConfig.Interval, prometheus.DefaultRegisterer),
+		healthChecker.NewRoutine(serviceConfig.HealthCheckInterval),
+		apiserver.NewConcurrencyLimitRefresher(concurrencyLimitStore, serverOptions.ConcurrencyLimits, serviceConfig.ConcurrencyLimitRefreshInterval),
 	}
+	if serviceConfig.AuditLogRetention > 0 {
+		routines = append(routines, janitor.NewAuditLogPruner(auditLogStore, serviceConfig.AuditLogRetention, sharedConfig.JanitorInterval))
+	}
+	routines = append(routines, telemetryRoutines...)
+
+	if federationConfig.Role == federation.RoleRegion {
+		queueCounters := make(map[string]federation.QueueCounter, len(queueStores))
+		for queueName, store := range queueStores {
+			queueCounters[queueName] = store
+		}
+
+		parentClient := federation.NewParentClient(federationConfig.ParentURL, federationConfig.ParentUsername, federationConfig.ParentPassword)
+		routines = append(routines, federation.NewReporter(federationConfig.Region, queueCounters, executorStore, serviceConfig.ExecutorActiveThreshold, parentClient, federationConfig.ReportInterval))
+	}
+
+	janitorMetrics := janitor.NewMetrics(observationContext)
+	if serviceConfig.ExecutorDeadThreshold > 0 {
+		routines = append(routines, janitor.NewDeadExecutorRequeuer(executorStore, queueStores, serviceConfig.ExecutorDeadThreshold, serviceConfig.DeadExecutorCheckInterval, janitorMetrics))
+	}
+	for queueName, options := range queueOptions {
+		routines = append(routines, metrics.NewQueuedCountReporter(queueName, options.Store, elector.IsLeader, serviceConfig.QueuedCountRefreshInterval, prometheus.DefaultRegisterer))
+		routines = append(routines, janitor.NewResetter(queueName, options.Store, sharedConfig.JanitorInterval, janitorMetrics))
+		routines = append(routines, janitor.NewDependencyFailer(queueName, options.Store, sharedConfig.JanitorInterval, janitorMetrics))
+
+		if ttl := sharedConfig.JobTTL(queueName); ttl > 0 {
+			routines = append(routines, janitor.NewJobExpirer(queueName, options, ttl, sharedConfig.JanitorInterval, janitorMetrics))
+		}
+	}
+
+	// Shut down gracefully when the orchestrator asks us to terminate so that in-flight
+	// executor requests can complete before the process exits.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
 
-	server := apiserver.NewServer(serviceConfig.ServerOptions(), queueOptions)
-	goroutine.MonitorBackgroundRoutines(context.Background(), server)
+	goroutine.MonitorBackgroundRoutines(ctx, routines...)
 }
 
-func connectToDatabase() *sql.DB {
-	postgresDSN := conf.Get().ServiceConnections.PostgresDSN
+func newQueueHistogram(name, help string) *prometheus.HistogramVec {
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    name,
+		Help:    help,
+		Buckets: prometheus.ExponentialBuckets(0.01, 4, 12),
+	}, []string{"queue"})
+
+	prometheus.DefaultRegisterer.MustRegister(histogram)
+	return histogram
+}
+
+// watchPausedQueues returns the set of queues paused in the site configuration, which is
+// updated as the site configuration changes.
+func watchPausedQueues() *apiserver.PausedQueues {
+	pausedQueues := apiserver.NewPausedQueues()
+
 	conf.Watch(func() {
-		if newDSN := conf.Get().ServiceConnections.PostgresDSN; postgresDSN != newDSN {
-			log.Fatalf("detected database DSN change, restarting to take effect: %s", newDSN)
+		if pausedQueues.Set(conf.Get().ExecutorsPausedQueues) {
+			log15.Info("Updated paused executor queues", "queues", pausedQueues.List())
 		}
 	})
 
-	db, err := dbconn.New(dbconn.Opts{DSN: postgresDSN, DBName: "frontend", AppName: "executor-queue"})
+	return pausedQueues
+}
+
+// connectToReadReplica returns a connection to the read replica of the frontend database with
+// the given DSN, or nil if no DSN is supplied.
+func connectToReadReplica(dsn string) *sql.DB {
+	if dsn == "" {
+		return nil
+	}
+
+	db, err := dbconn.New(dbconn.Opts{DSN: dsn, DBName: "frontend_replica", AppName: "executor-queue"})
+	if err != nil {
+		log.Fatalf("failed to connect to read replica: %s", err)
+	}
+
+	return db
+}
+
+// connectToInsightsDatabase returns a connection to the code insights database, or nil if
+// code insights are disabled, in which case the insights queue is not served.
+func connectToInsightsDatabase() *sql.DB {
+	if !insights.IsEnabled() {
+		return nil
+	}
+
+	db, err := insights.InitializeCodeInsightsDB("executor-queue")
+	if err != nil {
+		log.Fatalf("failed to initialize codeinsights store: %s", err)
+	}
+
+	return db
+}
+
+func connectToDatabase() *dbconn.SwappableDB {
+	postgresDSN := conf.Get().ServiceConnections.PostgresDSN
+
+	db, err := dbconn.NewSwappable(dbconn.Opts{DSN: postgresDSN, DBName: "frontend", AppName: "executor-queue"})
 	if err != nil {
 		log.Fatalf("failed to initialize store: %s", err)
 	}
 
+	// Swap the connection pool in place on DSN changes so that in-flight transactions
+	// are not dropped. On failure the previous pool remains in use.
+	var mu sync.Mutex
+	conf.Watch(func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		newDSN := conf.Get().ServiceConnections.PostgresDSN
+		if newDSN == postgresDSN {
+			return
+		}
+
+		if err := db.Swap(newDSN); err != nil {
+			log15.Error("Failed to connect to database with new DSN", "error", err)
+			return
+		}
+
+		log15.Info("Detected database DSN change, swapped connection pool")
+		postgresDSN = newDSN
+	})
+
 	return db
 }
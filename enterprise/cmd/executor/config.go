@@ -1,7 +1,10 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
+	"runtime"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,8 +12,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/apiclient"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/command"
 	apiworker "github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/worker"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/hostname"
+	"github.com/sourcegraph/sourcegraph/internal/version"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 )
 
@@ -22,6 +27,7 @@ type Config struct {
 	FrontendPassword     string
 	QueueName            string
 	QueuePollInterval    time.Duration
+	HeartbeatInterval    time.Duration
 	MaximumNumJobs       int
 	FirecrackerImage     string
 	UseFirecracker       bool
@@ -32,6 +38,9 @@ type Config struct {
 	DisableHealthServer  bool
 	HealthServerPort     int
 	MaximumRuntimePerJob time.Duration
+	Labels               []string
+	Channel              string
+	JaegerEnabled        bool
 }
 
 func (c *Config) Load() {
@@ -40,6 +49,7 @@ func (c *Config) Load() {
 	c.FrontendPassword = c.Get("EXECUTOR_FRONTEND_PASSWORD", "", "The password supplied to the frontend.")
 	c.QueueName = c.Get("EXECUTOR_QUEUE_NAME", "", "The name of the queue to listen to.")
 	c.QueuePollInterval = c.GetInterval("EXECUTOR_QUEUE_POLL_INTERVAL", "1s", "Interval between dequeue requests.")
+	c.HeartbeatInterval = c.GetInterval("EXECUTOR_HEARTBEAT_INTERVAL", "1s", "Interval between heartbeat requests. Must match the heartbeat interval configured for the queue.")
 	c.MaximumNumJobs = c.GetInt("EXECUTOR_MAXIMUM_NUM_JOBS", "1", "Number of virtual machines or containers that can be running at once.")
 	c.UseFirecracker = c.GetBool("EXECUTOR_USE_FIRECRACKER", "true", "Whether to isolate commands in virtual machines.")
 	c.FirecrackerImage = c.Get("EXECUTOR_FIRECRACKER_IMAGE", "sourcegraph/ignite-ubuntu:insiders", "The base image to use for virtual machines.")
@@ -50,6 +60,25 @@ func (c *Config) Load() {
 	c.DisableHealthServer = c.GetBool("EXECUTOR_DISABLE_HEALTHSERVER", "false", "Whether or not to disable the health server.")
 	c.HealthServerPort = c.GetInt("EXECUTOR_HEALTH_SERVER_PORT", "3192", "The port to listen on for the health server.")
 	c.MaximumRuntimePerJob = c.GetInterval("EXECUTOR_MAXIMUM_RUNTIME_PER_JOB", "30m", "The maximum wall time that can be spent on a single job.")
+	c.Labels = parseLabels(c.GetOptional("EXECUTOR_LABELS", "A comma-separated list of labels describing the capabilities of this executor (e.g. gpu)."))
+	c.JaegerEnabled = c.GetBool("EXECUTOR_JAEGER_ENABLED", "false", "Whether to report the spans of jobs enqueued by traced requests to Jaeger, as configured by the JAEGER_* environment variables.")
+	c.Channel = c.Get("EXECUTOR_CHANNEL", executor.ExecutorChannelStable, "The release channel of this executor (stable or canary). Canary executors only receive the share of jobs routed to canary by the queue.")
+
+	if c.Channel != executor.ExecutorChannelStable && c.Channel != executor.ExecutorChannelCanary {
+		c.AddError(fmt.Errorf("invalid value %q for EXECUTOR_CHANNEL: must be %q or %q", c.Channel, executor.ExecutorChannelStable, executor.ExecutorChannelCanary))
+	}
+}
+
+// parseLabels splits a comma-separated list of labels, discarding empty values.
+func parseLabels(value string) []string {
+	var labels []string
+	for _, label := range strings.Split(value, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+
+	return labels
 }
 
 func (c *Config) APIWorkerOptions(transport http.RoundTripper) apiworker.Options {
@@ -74,7 +103,7 @@ func (c *Config) WorkerOptions() workerutil.WorkerOptions {
 		Name:              "precise_code_intel_index_worker",
 		NumHandlers:       c.MaximumNumJobs,
 		Interval:          c.QueuePollInterval,
-		HeartbeatInterval: 1 * time.Second,
+		HeartbeatInterval: c.HeartbeatInterval,
 		Metrics:           makeWorkerMetrics(c.QueueName),
 	}
 }
@@ -102,6 +131,11 @@ func (c *Config) ClientOptions(transport http.RoundTripper) apiclient.Options {
 		// Be unique but also descriptive.
 		ExecutorName:      hn + "-" + uuid.New().String(),
 		ExecutorHostname:  hn,
+		ExecutorLabels:    c.Labels,
+		ExecutorChannel:   c.Channel,
+		OS:                runtime.GOOS,
+		Architecture:      runtime.GOARCH,
+		ExecutorVersion:   version.Version(),
 		PathPrefix:        "/.executors/queue",
 		EndpointOptions:   c.EndpointOptions(),
 		BaseClientOptions: c.BaseClientOptions(transport),
@@ -10,6 +10,8 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor/queueclient"
 )
 
 // BaseClient is an abstract HTTP API-backed data access layer. Instances of this
@@ -60,9 +62,12 @@ func NewBaseClient(options BaseClientOptions) *BaseClient {
 }
 
 // Do performs the given HTTP request and returns the body. If there is no content
-// to be read due to a 204 response, then a false-valued flag is returned.
+// to be read due to a 204 response, then a false-valued flag is returned. Requests
+// without a content type are sent as JSON.
 func (c *BaseClient) Do(ctx context.Context, req *http.Request) (hasContent bool, _ io.ReadCloser, err error) {
-	req.Header.Set("Content-Type", "application/json")
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	req.Header.Set("User-Agent", c.options.UserAgent)
 	req = req.WithContext(ctx)
 
@@ -72,11 +77,14 @@ func (c *BaseClient) Do(ctx context.Context, req *http.Request) (hasContent bool
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
+		defer resp.Body.Close()
 
 		if resp.StatusCode == http.StatusNoContent {
 			return false, nil, nil
 		}
+		if resp.StatusCode == http.StatusUpgradeRequired {
+			return false, nil, queueclient.DecodeUpgradeRequiredError(resp.Body)
+		}
 
 		return false, nil, errors.Errorf("unexpected status code %d", resp.StatusCode)
 	}
@@ -84,6 +92,10 @@ func (c *BaseClient) Do(ctx context.Context, req *http.Request) (hasContent bool
 	return true, resp.Body, nil
 }
 
+// UpgradeRequiredError is returned when the queue refuses to hand out jobs to this executor
+// because its version is not supported.
+type UpgradeRequiredError = queueclient.UpgradeRequiredError
+
 // DoAndDecode performs the given HTTP request and unmarshals the response body into the
 // given interface pointer. If the response body was empty due to a 204 response, then a
 // false-valued flag is returned.
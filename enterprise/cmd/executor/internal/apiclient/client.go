@@ -2,25 +2,28 @@ package apiclient
 
 import (
 	"context"
-	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
-	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/cockroachdb/errors"
 	"github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor/queueclient"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 )
 
 // Client is the client used to communicate with a remote job queue API.
 type Client struct {
-	options    Options
-	client     *BaseClient
-	operations *operations
+	options     Options
+	client      *BaseClient
+	queueClient *queueclient.Client
+	operations  *operations
 }
 
 type Options struct {
@@ -30,6 +33,20 @@ type Options struct {
 	// ExecutorHostname is the hostname of the system it is running on.
 	ExecutorHostname string
 
+	// ExecutorLabels describe the capabilities of the requesting executor. Only jobs whose
+	// label selector is satisfied by these labels are dequeued.
+	ExecutorLabels []string
+
+	// ExecutorChannel is the release channel (stable or canary) of the requesting executor.
+	// The queue routes a configurable share of jobs to canary executors.
+	ExecutorChannel string
+
+	// OS, Architecture, and ExecutorVersion describe the requesting executor. These values
+	// are reported with each heartbeat.
+	OS              string
+	Architecture    string
+	ExecutorVersion string
+
 	// PathPrefix is the path prefix added to all requests.
 	PathPrefix string
 
@@ -53,8 +70,16 @@ type EndpointOptions struct {
 
 func New(options Options, observationContext *observation.Context) *Client {
 	return &Client{
-		options:    options,
-		client:     NewBaseClient(options.BaseClientOptions),
+		options: options,
+		client:  NewBaseClient(options.BaseClientOptions),
+		queueClient: queueclient.New(queueclient.Options{
+			URL:        options.EndpointOptions.URL,
+			PathPrefix: options.PathPrefix,
+			Username:   options.EndpointOptions.Username,
+			Password:   options.EndpointOptions.Password,
+			UserAgent:  options.BaseClientOptions.UserAgent,
+			Transport:  options.BaseClientOptions.Transport,
+		}),
 		operations: newOperations(observationContext),
 	}
 }
@@ -65,15 +90,42 @@ func (c *Client) Dequeue(ctx context.Context, queueName string, job *executor.Jo
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.makeRequest("POST", fmt.Sprintf("%s/dequeue", queueName), executor.DequeueRequest{
+	dequeued, ok, err := c.queueClient.Dequeue(ctx, queueName, executor.DequeueRequest{
 		ExecutorName:     c.options.ExecutorName,
 		ExecutorHostname: c.options.ExecutorHostname,
+		ExecutorLabels:   c.options.ExecutorLabels,
+		ExecutorChannel:  c.options.ExecutorChannel,
+		ExecutorVersion:  c.options.ExecutorVersion,
+	})
+	if ok {
+		*job = dequeued
+	}
+
+	return ok, err
+}
+
+// DequeueBatch claims up to numJobs jobs from the given queue in a single request. An empty
+// slice is returned if there are no jobs to process.
+func (c *Client) DequeueBatch(ctx context.Context, queueName string, numJobs int) (jobs []executor.Job, err error) {
+	ctx, endObservation := c.operations.dequeueBatch.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("queueName", queueName),
+		log.Int("numJobs", numJobs),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	jobs, _, err = c.queueClient.DequeueBatch(ctx, queueName, executor.DequeueRequest{
+		ExecutorName:     c.options.ExecutorName,
+		ExecutorHostname: c.options.ExecutorHostname,
+		ExecutorLabels:   c.options.ExecutorLabels,
+		ExecutorChannel:  c.options.ExecutorChannel,
+		ExecutorVersion:  c.options.ExecutorVersion,
+		NumJobs:          numJobs,
 	})
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	return c.client.DoAndDecode(ctx, req, &job)
+	return jobs, nil
 }
 
 func (c *Client) AddExecutionLogEntry(ctx context.Context, queueName string, jobID int, entry workerutil.ExecutionLogEntry) (entryID int, err error) {
@@ -83,17 +135,11 @@ func (c *Client) AddExecutionLogEntry(ctx context.Context, queueName string, job
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.makeRequest("POST", fmt.Sprintf("%s/addExecutionLogEntry", queueName), executor.AddExecutionLogEntryRequest{
+	return c.queueClient.AddExecutionLogEntry(ctx, queueName, executor.AddExecutionLogEntryRequest{
 		ExecutorName:      c.options.ExecutorName,
 		JobID:             jobID,
 		ExecutionLogEntry: entry,
 	})
-	if err != nil {
-		return entryID, err
-	}
-
-	_, err = c.client.DoAndDecode(ctx, req, &entryID)
-	return entryID, err
 }
 
 func (c *Client) UpdateExecutionLogEntry(ctx context.Context, queueName string, jobID, entryID int, entry workerutil.ExecutionLogEntry) (err error) {
@@ -104,17 +150,12 @@ func (c *Client) UpdateExecutionLogEntry(ctx context.Context, queueName string,
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.makeRequest("POST", fmt.Sprintf("%s/updateExecutionLogEntry", queueName), executor.UpdateExecutionLogEntryRequest{
+	return c.queueClient.UpdateExecutionLogEntry(ctx, queueName, executor.UpdateExecutionLogEntryRequest{
 		ExecutorName:      c.options.ExecutorName,
 		JobID:             jobID,
 		EntryID:           entryID,
 		ExecutionLogEntry: entry,
 	})
-	if err != nil {
-		return err
-	}
-
-	return c.client.DoAndDrop(ctx, req)
 }
 
 func (c *Client) MarkComplete(ctx context.Context, queueName string, jobID int) (err error) {
@@ -124,34 +165,25 @@ func (c *Client) MarkComplete(ctx context.Context, queueName string, jobID int)
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.makeRequest("POST", fmt.Sprintf("%s/markComplete", queueName), executor.MarkCompleteRequest{
+	return c.queueClient.MarkComplete(ctx, queueName, executor.MarkCompleteRequest{
 		ExecutorName: c.options.ExecutorName,
 		JobID:        jobID,
 	})
-	if err != nil {
-		return err
-	}
-
-	return c.client.DoAndDrop(ctx, req)
 }
 
-func (c *Client) MarkErrored(ctx context.Context, queueName string, jobID int, errorMessage string) (err error) {
+func (c *Client) MarkErrored(ctx context.Context, queueName string, jobID int, errorMessage, failureClass string) (err error) {
 	ctx, endObservation := c.operations.markErrored.With(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.String("queueName", queueName),
 		log.Int("jobID", jobID),
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.makeRequest("POST", fmt.Sprintf("%s/markErrored", queueName), executor.MarkErroredRequest{
+	return c.queueClient.MarkErrored(ctx, queueName, executor.MarkErroredRequest{
 		ExecutorName: c.options.ExecutorName,
 		JobID:        jobID,
 		ErrorMessage: errorMessage,
+		FailureClass: failureClass,
 	})
-	if err != nil {
-		return err
-	}
-
-	return c.client.DoAndDrop(ctx, req)
 }
 
 func (c *Client) MarkFailed(ctx context.Context, queueName string, jobID int, errorMessage string) (err error) {
@@ -161,83 +193,104 @@ func (c *Client) MarkFailed(ctx context.Context, queueName string, jobID int, er
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.makeRequest("POST", fmt.Sprintf("%s/markFailed", queueName), executor.MarkErroredRequest{
+	return c.queueClient.MarkFailed(ctx, queueName, executor.MarkErroredRequest{
 		ExecutorName: c.options.ExecutorName,
 		JobID:        jobID,
 		ErrorMessage: errorMessage,
 	})
-	if err != nil {
-		return err
-	}
-
-	return c.client.DoAndDrop(ctx, req)
 }
 
 func (c *Client) Ping(ctx context.Context, queueName string, jobIDs []int) (err error) {
-	req, err := c.makeRequest("POST", fmt.Sprintf("%s/heartbeat", queueName), executor.HeartbeatRequest{
+	_, err = c.queueClient.Heartbeat(ctx, queueName, executor.HeartbeatRequest{
 		ExecutorName: c.options.ExecutorName,
 	})
-	if err != nil {
-		return err
-	}
-
-	return c.client.DoAndDrop(ctx, req)
+	return err
 }
 
-func (c *Client) Heartbeat(ctx context.Context, queueName string, jobIDs []int) (knownIDs []int, err error) {
+func (c *Client) Heartbeat(ctx context.Context, queueName string, jobIDs []int, checkpoints map[int][]byte) (knownIDs []int, err error) {
 	ctx, endObservation := c.operations.heartbeat.With(ctx, &err, observation.Args{LogFields: []log.Field{
 		log.String("queueName", queueName),
 		log.String("jobIDs", intsToString(jobIDs)),
 	}})
 	defer endObservation(1, observation.Args{})
 
-	req, err := c.makeRequest("POST", fmt.Sprintf("%s/heartbeat", queueName), executor.HeartbeatRequest{
-		ExecutorName: c.options.ExecutorName,
-		JobIDs:       jobIDs,
+	knownIDs, err = c.queueClient.Heartbeat(ctx, queueName, executor.HeartbeatRequest{
+		ExecutorName:     c.options.ExecutorName,
+		JobIDs:           jobIDs,
+		ExecutorHostname: c.options.ExecutorHostname,
+		OS:               c.options.OS,
+		Architecture:     c.options.Architecture,
+		ExecutorVersion:  c.options.ExecutorVersion,
+		Checkpoints:      checkpoints,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err := c.client.DoAndDecode(ctx, req, &knownIDs); err != nil {
-		return nil, err
-	}
-
 	return knownIDs, nil
 }
 
-func (c *Client) makeRequest(method, path string, payload interface{}) (*http.Request, error) {
-	u, err := makeURL(
-		c.options.EndpointOptions.URL,
-		c.options.EndpointOptions.Username,
-		c.options.EndpointOptions.Password,
-		c.options.PathPrefix,
-		path,
-	)
+// UploadArtifact streams the content of the given reader to the job queue as an artifact of the
+// given job. The content is not buffered, so the reader may be arbitrarily large as long as it
+// fits within the artifact size limit of the job queue.
+func (c *Client) UploadArtifact(ctx context.Context, queueName string, jobID int, name string, r io.Reader) (artifact executor.Artifact, err error) {
+	ctx, endObservation := c.operations.uploadArtifact.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("queueName", queueName),
+		log.Int("jobID", jobID),
+		log.String("name", name),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	u, err := c.queueClient.URL(queueName, "uploadArtifact")
 	if err != nil {
-		return nil, err
+		return executor.Artifact{}, err
 	}
+	u.User = url.UserPassword(c.options.EndpointOptions.Username, c.options.EndpointOptions.Password)
 
-	return MakeJSONRequest(method, u, payload)
-}
+	pr, pw := io.Pipe()
+	defer pr.Close()
 
-func makeURL(base, username, password string, path ...string) (*url.URL, error) {
-	u, err := makeRelativeURL(base, path...)
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeArtifactUpload(mw, c.options.ExecutorName, jobID, name, r))
+	}()
+
+	req, err := http.NewRequest("POST", u.String(), pr)
 	if err != nil {
-		return nil, err
+		return executor.Artifact{}, err
 	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
 
-	u.User = url.UserPassword(username, password)
-	return u, nil
+	var artifacts []executor.Artifact
+	if _, err := c.client.DoAndDecode(ctx, req, &artifacts); err != nil {
+		return executor.Artifact{}, err
+	}
+	if len(artifacts) != 1 {
+		return executor.Artifact{}, errors.Errorf("unexpected number of uploaded artifacts %d", len(artifacts))
+	}
+
+	return artifacts[0], nil
 }
 
-func makeRelativeURL(base string, path ...string) (*url.URL, error) {
-	baseURL, err := url.Parse(base)
+// writeArtifactUpload writes the multipart body of an artifact upload request. The job fields
+// must precede the artifact, as the job queue verifies ownership of the job before storing it.
+func writeArtifactUpload(mw *multipart.Writer, executorName string, jobID int, name string, r io.Reader) error {
+	if err := mw.WriteField("executorName", executorName); err != nil {
+		return err
+	}
+	if err := mw.WriteField("jobId", strconv.Itoa(jobID)); err != nil {
+		return err
+	}
+
+	part, err := mw.CreateFormFile("artifact", name)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return err
 	}
 
-	return baseURL.ResolveReference(&url.URL{Path: filepath.Join(path...)}), nil
+	return mw.Close()
 }
 
 func intsToString(ints []int) string {
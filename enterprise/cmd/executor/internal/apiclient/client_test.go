@@ -6,9 +6,11 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
@@ -42,6 +44,50 @@ func TestDequeue(t *testing.T) {
 	})
 }
 
+func TestDequeueBatch(t *testing.T) {
+	spec := routeSpec{
+		expectedMethod:   "POST",
+		expectedPath:     "/.executors/queue/test_queue/dequeue",
+		expectedUsername: "test",
+		expectedPassword: "hunter2",
+		expectedPayload:  `{"executorHostname": "", "executorName": "deadbeef", "numJobs": 3}`,
+		responseStatus:   http.StatusOK,
+		responsePayload:  `[{"id": 42}, {"id": 43}]`,
+	}
+
+	testRoute(t, spec, func(client *Client) {
+		jobs, err := client.DequeueBatch(context.Background(), "test_queue", 3)
+		if err != nil {
+			t.Fatalf("unexpected error dequeueing records: %s", err)
+		}
+		if len(jobs) != 2 || jobs[0].ID != 42 || jobs[1].ID != 43 {
+			t.Errorf("unexpected jobs: %+v", jobs)
+		}
+	})
+}
+
+func TestDequeueBatchNoRecords(t *testing.T) {
+	spec := routeSpec{
+		expectedMethod:   "POST",
+		expectedPath:     "/.executors/queue/test_queue/dequeue",
+		expectedUsername: "test",
+		expectedPassword: "hunter2",
+		expectedPayload:  `{"executorHostname": "", "executorName": "deadbeef", "numJobs": 3}`,
+		responseStatus:   http.StatusNoContent,
+		responsePayload:  ``,
+	}
+
+	testRoute(t, spec, func(client *Client) {
+		jobs, err := client.DequeueBatch(context.Background(), "test_queue", 3)
+		if err != nil {
+			t.Fatalf("unexpected error dequeueing records: %s", err)
+		}
+		if len(jobs) != 0 {
+			t.Errorf("unexpected jobs: %+v", jobs)
+		}
+	})
+}
+
 func TestDequeueNoRecord(t *testing.T) {
 	spec := routeSpec{
 		expectedMethod:   "POST",
@@ -82,6 +128,36 @@ func TestDequeueBadResponse(t *testing.T) {
 	})
 }
 
+func TestDequeueUpgradeRequired(t *testing.T) {
+	spec := routeSpec{
+		expectedMethod:   "POST",
+		expectedPath:     "/.executors/queue/test_queue/dequeue",
+		expectedUsername: "test",
+		expectedPassword: "hunter2",
+		expectedPayload:  `{"executorHostname": "", "executorName": "deadbeef"}`,
+		responseStatus:   http.StatusUpgradeRequired,
+		responsePayload:  `{"error": "upgrade the executor", "executorVersion": "3.30.0", "minVersion": "3.31.0"}`,
+	}
+
+	testRoute(t, spec, func(client *Client) {
+		_, err := client.Dequeue(context.Background(), "test_queue", nil)
+
+		var upgradeErr *UpgradeRequiredError
+		if !errors.As(err, &upgradeErr) {
+			t.Fatalf("expected an upgrade required error, got %v", err)
+		}
+
+		expected := executor.UpgradeRequiredResponse{
+			Error:           "upgrade the executor",
+			ExecutorVersion: "3.30.0",
+			MinVersion:      "3.31.0",
+		}
+		if diff := cmp.Diff(expected, upgradeErr.UpgradeRequiredResponse); diff != "" {
+			t.Errorf("unexpected response (-want +got):\n%s", diff)
+		}
+	})
+}
+
 func TestAddExecutionLogEntry(t *testing.T) {
 	entry := workerutil.ExecutionLogEntry{
 		Key:        "foo",
@@ -274,13 +350,13 @@ func TestMarkErrored(t *testing.T) {
 		expectedPath:     "/.executors/queue/test_queue/markErrored",
 		expectedUsername: "test",
 		expectedPassword: "hunter2",
-		expectedPayload:  `{"executorName": "deadbeef", "jobId": 42, "errorMessage": "OH NO"}`,
+		expectedPayload:  `{"executorName": "deadbeef", "jobId": 42, "errorMessage": "OH NO", "failureClass": "transient"}`,
 		responseStatus:   http.StatusNoContent,
 		responsePayload:  ``,
 	}
 
 	testRoute(t, spec, func(client *Client) {
-		if err := client.MarkErrored(context.Background(), "test_queue", 42, "OH NO"); err != nil {
+		if err := client.MarkErrored(context.Background(), "test_queue", 42, "OH NO", executor.FailureClassTransient); err != nil {
 			t.Fatalf("unexpected error completing job: %s", err)
 		}
 	})
@@ -298,7 +374,7 @@ func TestMarkErroredBadResponse(t *testing.T) {
 	}
 
 	testRoute(t, spec, func(client *Client) {
-		if err := client.MarkErrored(context.Background(), "test_queue", 42, "OH NO"); err == nil {
+		if err := client.MarkErrored(context.Background(), "test_queue", 42, "OH NO", ""); err == nil {
 			t.Fatalf("expected an error")
 		}
 	})
@@ -334,7 +410,7 @@ func TestHeartbeat(t *testing.T) {
 	}
 
 	testRoute(t, spec, func(client *Client) {
-		unknownIDs, err := client.Heartbeat(context.Background(), "test_queue", []int{1, 2, 3})
+		unknownIDs, err := client.Heartbeat(context.Background(), "test_queue", []int{1, 2, 3}, nil)
 		if err != nil {
 			t.Fatalf("unexpected error performing heartbeat: %s", err)
 		}
@@ -357,7 +433,75 @@ func TestHeartbeatBadResponse(t *testing.T) {
 	}
 
 	testRoute(t, spec, func(client *Client) {
-		if _, err := client.Heartbeat(context.Background(), "test_queue", []int{1, 2, 3}); err == nil {
+		if _, err := client.Heartbeat(context.Background(), "test_queue", []int{1, 2, 3}, nil); err == nil {
+			t.Fatalf("expected an error")
+		}
+	})
+}
+
+func TestUploadArtifact(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.executors/queue/test_queue/uploadArtifact" {
+			t.Errorf("unexpected path. want=%s have=%s", "/.executors/queue/test_queue/uploadArtifact", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1024); err != nil {
+			t.Fatalf("unexpected error parsing multipart form: %s", err)
+		}
+		if value := r.FormValue("executorName"); value != "deadbeef" {
+			t.Errorf("unexpected executor name. want=%q have=%q", "deadbeef", value)
+		}
+		if value := r.FormValue("jobId"); value != "42" {
+			t.Errorf("unexpected job id. want=%q have=%q", "42", value)
+		}
+
+		f, header, err := r.FormFile("artifact")
+		if err != nil {
+			t.Fatalf("unexpected error reading artifact: %s", err)
+		}
+		defer f.Close()
+		content, err := io.ReadAll(f)
+		if err != nil {
+			t.Fatalf("unexpected error reading artifact: %s", err)
+		}
+		if header.Filename != "output.json" || string(content) != `{"ok": true}` {
+			t.Errorf("unexpected artifact %q: %q", header.Filename, content)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"name": "output.json", "key": "test_queue/42/output.json", "size": 12}]`))
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(handler))
+	defer ts.Close()
+
+	client := New(Options{
+		ExecutorName:    "deadbeef",
+		PathPrefix:      "/.executors/queue",
+		EndpointOptions: EndpointOptions{URL: ts.URL},
+	}, &observation.TestContext)
+
+	artifact, err := client.UploadArtifact(context.Background(), "test_queue", 42, "output.json", strings.NewReader(`{"ok": true}`))
+	if err != nil {
+		t.Fatalf("unexpected error uploading artifact: %s", err)
+	}
+	expected := executor.Artifact{Name: "output.json", Key: "test_queue/42/output.json", Size: 12}
+	if diff := cmp.Diff(expected, artifact); diff != "" {
+		t.Errorf("unexpected artifact (-want +got):\n%s", diff)
+	}
+}
+
+func TestUploadArtifactBadResponse(t *testing.T) {
+	spec := routeSpec{
+		expectedMethod:   "POST",
+		expectedPath:     "/.executors/queue/test_queue/uploadArtifact",
+		expectedUsername: "test",
+		expectedPassword: "hunter2",
+		responseStatus:   http.StatusRequestEntityTooLarge,
+		responsePayload:  `{"error": "artifact too large"}`,
+	}
+
+	testRoute(t, spec, func(client *Client) {
+		if _, err := client.UploadArtifact(context.Background(), "test_queue", 42, "output.json", strings.NewReader("payload")); err == nil {
 			t.Fatalf("expected an error")
 		}
 	})
@@ -9,12 +9,14 @@ import (
 
 type operations struct {
 	dequeue                 *observation.Operation
+	dequeueBatch            *observation.Operation
 	addExecutionLogEntry    *observation.Operation
 	updateExecutionLogEntry *observation.Operation
 	markComplete            *observation.Operation
 	markErrored             *observation.Operation
 	markFailed              *observation.Operation
 	heartbeat               *observation.Operation
+	uploadArtifact          *observation.Operation
 }
 
 func newOperations(observationContext *observation.Context) *operations {
@@ -35,11 +37,13 @@ func newOperations(observationContext *observation.Context) *operations {
 
 	return &operations{
 		dequeue:                 op("Dequeue"),
+		dequeueBatch:            op("DequeueBatch"),
 		addExecutionLogEntry:    op("AddExecutionLogEntry"),
 		updateExecutionLogEntry: op("UpdateExecutionLogEntry"),
 		markComplete:            op("MarkComplete"),
 		markErrored:             op("MarkErrored"),
 		markFailed:              op("MarkFailed"),
 		heartbeat:               op("Heartbeat"),
+		uploadArtifact:          op("UploadArtifact"),
 	}
 }
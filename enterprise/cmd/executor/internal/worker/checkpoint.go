@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"bytes"
+	"os"
+	"sync"
+)
+
+// checkpointFilename is the name of the file, within the scripts directory of the workspace,
+// through which a job reads and writes its progress checkpoint. The executor does not interpret
+// the contents of this file.
+const checkpointFilename = "checkpoint"
+
+// checkpointTracker tracks the checkpoint files of the jobs being processed by this executor
+// so that changed checkpoints can be sent to the queue along with the next heartbeat.
+type checkpointTracker struct {
+	mu    sync.Mutex
+	paths map[int]string
+	sent  map[int][]byte
+}
+
+func newCheckpointTracker() *checkpointTracker {
+	return &checkpointTracker{
+		paths: map[int]string{},
+		sent:  map[int][]byte{},
+	}
+}
+
+// track begins watching the checkpoint file at the given path for the given job. The initial
+// checkpoint is the one handed to the executor with the job, and is not sent back to the queue.
+func (t *checkpointTracker) track(jobID int, path string, initial []byte) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.paths[jobID] = path
+	t.sent[jobID] = initial
+}
+
+// untrack stops watching the checkpoint file of the given job.
+func (t *checkpointTracker) untrack(jobID int) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.paths, jobID)
+	delete(t.sent, jobID)
+}
+
+// changed returns the checkpoints of the given jobs that differ from the checkpoint last sent
+// to the queue. Jobs that have not written a checkpoint are omitted.
+func (t *checkpointTracker) changed(jobIDs []int) map[int][]byte {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var checkpoints map[int][]byte
+	for _, id := range jobIDs {
+		path, ok := t.paths[id]
+		if !ok {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil || bytes.Equal(data, t.sent[id]) {
+			continue
+		}
+
+		if checkpoints == nil {
+			checkpoints = map[int][]byte{}
+		}
+		checkpoints[id] = data
+	}
+
+	return checkpoints
+}
+
+// markSent records the given checkpoints as successfully sent to the queue.
+func (t *checkpointTracker) markSent(checkpoints map[int][]byte) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, data := range checkpoints {
+		if _, ok := t.paths[id]; ok {
+			t.sent[id] = data
+		}
+	}
+}
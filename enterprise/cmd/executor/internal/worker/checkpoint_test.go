@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckpointTracker(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, checkpointFilename)
+
+	tracker := newCheckpointTracker()
+	tracker.track(42, path, []byte("step 1"))
+
+	// No checkpoint file has been written yet
+	if checkpoints := tracker.changed([]int{42}); len(checkpoints) != 0 {
+		t.Errorf("unexpected checkpoints: %v", checkpoints)
+	}
+
+	// The initial checkpoint is not sent back to the queue
+	if err := os.WriteFile(path, []byte("step 1"), os.ModePerm); err != nil {
+		t.Fatalf("unexpected error writing checkpoint: %s", err)
+	}
+	if checkpoints := tracker.changed([]int{42}); len(checkpoints) != 0 {
+		t.Errorf("unexpected checkpoints: %v", checkpoints)
+	}
+
+	if err := os.WriteFile(path, []byte("step 2"), os.ModePerm); err != nil {
+		t.Fatalf("unexpected error writing checkpoint: %s", err)
+	}
+	checkpoints := tracker.changed([]int{42, 43})
+	if diff := cmp.Diff(map[int][]byte{42: []byte("step 2")}, checkpoints); diff != "" {
+		t.Errorf("unexpected checkpoints (-want +got):\n%s", diff)
+	}
+
+	// Checkpoints are resent until a heartbeat succeeds
+	if diff := cmp.Diff(checkpoints, tracker.changed([]int{42})); diff != "" {
+		t.Errorf("unexpected checkpoints (-want +got):\n%s", diff)
+	}
+	tracker.markSent(checkpoints)
+	if checkpoints := tracker.changed([]int{42}); len(checkpoints) != 0 {
+		t.Errorf("unexpected checkpoints: %v", checkpoints)
+	}
+
+	tracker.untrack(42)
+	if err := os.WriteFile(path, []byte("step 3"), os.ModePerm); err != nil {
+		t.Fatalf("unexpected error writing checkpoint: %s", err)
+	}
+	if checkpoints := tracker.changed([]int{42}); len(checkpoints) != 0 {
+		t.Errorf("unexpected checkpoints: %v", checkpoints)
+	}
+}
@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"sync"
+
+	"github.com/cockroachdb/errors"
+)
+
+// failureClassError annotates a handler error with the failure class reported to the queue
+// when the job is marked as errored.
+type failureClassError struct {
+	class string
+	err   error
+}
+
+func (e *failureClassError) Error() string { return e.err.Error() }
+func (e *failureClassError) Unwrap() error { return e.err }
+
+// withFailureClass annotates the given error with the given failure class.
+func withFailureClass(class string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &failureClassError{class: class, err: err}
+}
+
+// failureClassOf returns the failure class of the given error, or an empty string if the
+// error has not been classified.
+func failureClassOf(err error) string {
+	var e *failureClassError
+	if errors.As(err, &e) {
+		return e.class
+	}
+
+	return ""
+}
+
+// failureClassTracker hands the failure class of a job from the handler to the store shim.
+// The worker only passes the error message of a failed job to the store, so the class is
+// recorded separately when the handler returns.
+type failureClassTracker struct {
+	mu      sync.Mutex
+	classes map[int]string
+}
+
+func newFailureClassTracker() *failureClassTracker {
+	return &failureClassTracker{classes: map[int]string{}}
+}
+
+// record stores the failure class of the given job.
+func (t *failureClassTracker) record(jobID int, class string) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.classes[jobID] = class
+}
+
+// pop returns and forgets the failure class of the given job.
+func (t *failureClassTracker) pop(jobID int) string {
+	if t == nil {
+		return ""
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	class := t.classes[jobID]
+	delete(t.classes, jobID)
+	return class
+}
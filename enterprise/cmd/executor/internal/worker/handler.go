@@ -23,6 +23,8 @@ import (
 
 type handler struct {
 	store         workerutil.Store
+	checkpoints   *checkpointTracker
+	failures      *failureClassTracker
 	options       Options
 	operations    *command.Operations
 	runnerFactory func(dir string, logger *command.Logger, options command.Options, operations *command.Operations) command.Runner
@@ -34,17 +36,45 @@ var _ workerutil.Handler = &handler{}
 // fresh docker container, and uploads the results to the external frontend API.
 func (h *handler) Handle(ctx context.Context, record workerutil.Record) (err error) {
 	job := record.(executor.Job)
-	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(h.options.MaximumRuntimePerJob))
+	maximumRuntime := h.maximumRuntime(job)
+	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(maximumRuntime))
 	defer cancel()
 
+	ctx, finishSpan := startJobSpan(ctx, job)
+	defer func() { finishSpan(err) }()
+
 	wrapError := func(err error, message string) error {
 		if errors.Is(err, context.DeadlineExceeded) {
-			err = errors.Errorf("job exceeded maximum execution time of %s", h.options.MaximumRuntimePerJob)
+			err = errors.Errorf("job exceeded maximum execution time of %s", maximumRuntime)
 		}
 
 		return errors.Wrap(err, message)
 	}
 
+	// Steps fail because of the job itself, unless they were interrupted by the executor
+	// shutting down.
+	wrapStepError := func(err error, message string) error {
+		class := executor.FailureClassUserError
+		if errors.Is(err, context.Canceled) {
+			class = executor.FailureClassInfrastructure
+		}
+
+		return withFailureClass(class, wrapError(err, message))
+	}
+
+	// Report the failure class of the job along with the error. Errors that were not
+	// classified where they occurred originate in the executor itself.
+	defer func() {
+		if err != nil {
+			class := failureClassOf(err)
+			if class == "" {
+				class = executor.FailureClassInfrastructure
+			}
+
+			h.failures.record(job.ID, class)
+		}
+	}()
+
 	start := time.Now()
 	defer func() {
 		if honey.Enabled() {
@@ -70,7 +100,7 @@ func (h *handler) Handle(ctx context.Context, record workerutil.Record) (err err
 	hostRunner := h.runnerFactory("", logger, command.Options{}, h.operations)
 	workingDirectory, err := h.prepareWorkspace(ctx, hostRunner, job.RepositoryName, job.Commit)
 	if err != nil {
-		return wrapError(err, "failed to prepare workspace")
+		return withFailureClass(executor.FailureClassTransient, wrapError(err, "failed to prepare workspace"))
 	}
 	defer func() {
 		_ = os.RemoveAll(workingDirectory)
@@ -84,7 +114,7 @@ func (h *handler) Handle(ctx context.Context, record workerutil.Record) (err err
 		}
 
 		if !strings.HasPrefix(path, workingDirectory) {
-			return errors.Errorf("refusing to write outside of working directory")
+			return withFailureClass(executor.FailureClassUserError, errors.Errorf("refusing to write outside of working directory"))
 		}
 
 		if err := os.WriteFile(path, []byte(content), os.ModePerm); err != nil {
@@ -92,6 +122,17 @@ func (h *handler) Handle(ctx context.Context, record workerutil.Record) (err err
 		}
 	}
 
+	// Hand the job the checkpoint of a previous attempt, if any, and watch for new checkpoints
+	// written by the job so they can be reported with the next heartbeat.
+	checkpointPath := filepath.Join(workingDirectory, command.ScriptsPath, checkpointFilename)
+	if len(job.Checkpoint) > 0 {
+		if err := os.WriteFile(checkpointPath, job.Checkpoint, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	h.checkpoints.track(job.ID, checkpointPath, job.Checkpoint)
+	defer h.checkpoints.untrack(job.ID)
+
 	name, err := uuid.NewRandom()
 	if err != nil {
 		return err
@@ -157,7 +198,7 @@ func (h *handler) Handle(ctx context.Context, record workerutil.Record) (err err
 		log15.Info(fmt.Sprintf("Running docker step #%d", i), "jobID", job.ID, "repositoryName", job.RepositoryName, "commit", job.Commit)
 
 		if err := runner.Run(ctx, dockerStepCommand); err != nil {
-			return wrapError(err, "failed to perform docker step")
+			return wrapStepError(err, "failed to perform docker step")
 		}
 	}
 
@@ -174,7 +215,7 @@ func (h *handler) Handle(ctx context.Context, record workerutil.Record) (err err
 		}
 
 		if err := runner.Run(ctx, cliStepCommand); err != nil {
-			return wrapError(err, "failed to perform src-cli step")
+			return wrapStepError(err, "failed to perform src-cli step")
 		}
 	}
 
@@ -226,3 +267,17 @@ func createHoneyEvent(ctx context.Context, job executor.Job, err error, duration
 
 	return honey.EventWithFields("executor", fields)
 }
+
+// maximumRuntime returns the maximum wall time that can be spent on the given job. The
+// execution time requested by the job replaces the configured maximum runtime per job.
+func (h *handler) maximumRuntime(job executor.Job) time.Duration {
+	if job.MaxExecutionTime != "" {
+		if duration, err := time.ParseDuration(job.MaxExecutionTime); err == nil && duration > 0 {
+			return duration
+		}
+
+		log15.Warn("Ignoring malformed maximum execution time", "jobID", job.ID, "maxExecutionTime", job.MaxExecutionTime)
+	}
+
+	return h.options.MaximumRuntimePerJob
+}
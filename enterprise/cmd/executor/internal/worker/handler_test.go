@@ -5,7 +5,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/executor/internal/command"
@@ -107,3 +109,74 @@ func TestHandle(t *testing.T) {
 		t.Errorf("unexpected commands (-want +got):\n%s", diff)
 	}
 }
+
+func TestHandleFailureClass(t *testing.T) {
+	testDir := "/tmp/codeintel"
+	makeTempDir = func() (string, error) { return testDir, nil }
+
+	testCases := []struct {
+		name          string
+		setupErr      error
+		runErr        error
+		expectedClass string
+	}{
+		{name: "setup", setupErr: errors.New("no vm"), expectedClass: executor.FailureClassInfrastructure},
+		{name: "step", runErr: errors.New("exit status 1"), expectedClass: executor.FailureClassUserError},
+		{name: "interrupted step", runErr: context.Canceled, expectedClass: executor.FailureClassInfrastructure},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if err := os.MkdirAll(filepath.Join(testDir, command.ScriptsPath), os.ModePerm); err != nil {
+				t.Fatalf("unexpected error creating workspace: %s", err)
+			}
+
+			runner := NewMockRunner()
+			runner.SetupFunc.SetDefaultReturn(testCase.setupErr)
+			runner.RunFunc.SetDefaultReturn(testCase.runErr)
+
+			failures := newFailureClassTracker()
+			handler := &handler{
+				failures:   failures,
+				operations: command.NewOperations(&observation.TestContext),
+				runnerFactory: func(dir string, logger *command.Logger, options command.Options, operations *command.Operations) command.Runner {
+					if dir == "" {
+						return NewMockRunner()
+					}
+
+					return runner
+				},
+			}
+
+			job := executor.Job{ID: 42, DockerSteps: []executor.DockerStep{{Image: "alpine", Commands: []string{"false"}}}}
+			if err := handler.Handle(context.Background(), job); err == nil {
+				t.Fatalf("expected an error")
+			}
+
+			if class := failures.pop(42); class != testCase.expectedClass {
+				t.Errorf("unexpected failure class. want=%q have=%q", testCase.expectedClass, class)
+			}
+		})
+	}
+}
+
+func TestHandlerMaximumRuntime(t *testing.T) {
+	handler := &handler{options: Options{MaximumRuntimePerJob: 30 * time.Minute}}
+
+	testCases := []struct {
+		maxExecutionTime string
+		expected         time.Duration
+	}{
+		{maxExecutionTime: "", expected: 30 * time.Minute},
+		{maxExecutionTime: "2h0m0s", expected: 2 * time.Hour},
+		{maxExecutionTime: "5m", expected: 5 * time.Minute},
+		{maxExecutionTime: "0s", expected: 30 * time.Minute},
+		{maxExecutionTime: "forever", expected: 30 * time.Minute},
+	}
+
+	for _, testCase := range testCases {
+		if value := handler.maximumRuntime(executor.Job{MaxExecutionTime: testCase.maxExecutionTime}); value != testCase.expected {
+			t.Errorf("unexpected maximum runtime for %q. want=%s have=%s", testCase.maxExecutionTime, testCase.expected, value)
+		}
+	}
+}
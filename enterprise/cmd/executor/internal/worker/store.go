@@ -10,8 +10,10 @@ import (
 )
 
 type storeShim struct {
-	queueName  string
-	queueStore QueueStore
+	queueName   string
+	queueStore  QueueStore
+	checkpoints *checkpointTracker
+	failures    *failureClassTracker
 }
 
 type QueueStore interface {
@@ -19,9 +21,9 @@ type QueueStore interface {
 	AddExecutionLogEntry(ctx context.Context, queueName string, jobID int, entry workerutil.ExecutionLogEntry) (int, error)
 	UpdateExecutionLogEntry(ctx context.Context, queueName string, jobID, entryID int, entry workerutil.ExecutionLogEntry) error
 	MarkComplete(ctx context.Context, queueName string, jobID int) error
-	MarkErrored(ctx context.Context, queueName string, jobID int, errorMessage string) error
+	MarkErrored(ctx context.Context, queueName string, jobID int, errorMessage, failureClass string) error
 	MarkFailed(ctx context.Context, queueName string, jobID int, errorMessage string) error
-	Heartbeat(ctx context.Context, queueName string, jobIDs []int) (knownIDs []int, err error)
+	Heartbeat(ctx context.Context, queueName string, jobIDs []int, checkpoints map[int][]byte) (knownIDs []int, err error)
 }
 
 var _ workerutil.Store = &storeShim{}
@@ -41,7 +43,15 @@ func (s *storeShim) Dequeue(ctx context.Context, workerHostname string, extraArg
 }
 
 func (s *storeShim) Heartbeat(ctx context.Context, ids []int) (knownIDs []int, err error) {
-	return s.queueStore.Heartbeat(ctx, s.queueName, ids)
+	checkpoints := s.checkpoints.changed(ids)
+
+	knownIDs, err = s.queueStore.Heartbeat(ctx, s.queueName, ids, checkpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	s.checkpoints.markSent(checkpoints)
+	return knownIDs, nil
 }
 
 func (s *storeShim) AddExecutionLogEntry(ctx context.Context, id int, entry workerutil.ExecutionLogEntry) (int, error) {
@@ -57,9 +67,10 @@ func (s *storeShim) MarkComplete(ctx context.Context, id int) (bool, error) {
 }
 
 func (s *storeShim) MarkErrored(ctx context.Context, id int, errorMessage string) (bool, error) {
-	return true, s.queueStore.MarkErrored(ctx, s.queueName, id, errorMessage)
+	return true, s.queueStore.MarkErrored(ctx, s.queueName, id, errorMessage, s.failures.pop(id))
 }
 
 func (s *storeShim) MarkFailed(ctx context.Context, id int, errorMessage string) (bool, error) {
+	s.failures.pop(id)
 	return true, s.queueStore.MarkFailed(ctx, s.queueName, id, errorMessage)
 }
@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+// startJobSpan starts a span covering the handling of the given job, which follows from the
+// span of the request that enqueued the job. Jobs without a trace context are not traced, in
+// which case the given context is returned as-is. The returned function finishes the span,
+// recording the error with which the job failed, if any.
+func startJobSpan(ctx context.Context, job executor.Job) (context.Context, func(err error)) {
+	// The request that enqueued the job was traced, so its continuation is traced as well
+	tracedCtx := ot.WithShouldTrace(ctx, true)
+
+	spanContext := ot.ExtractSpanContext(tracedCtx, job.TraceContext)
+	if spanContext == nil {
+		return ctx, func(err error) {}
+	}
+
+	span, tracedCtx := ot.StartSpanFromContext(tracedCtx, "executor.Handle", opentracing.FollowsFrom(spanContext))
+	span.SetTag("jobID", job.ID)
+	span.SetTag("repositoryName", job.RepositoryName)
+	span.SetTag("commit", job.Commit)
+
+	return tracedCtx, func(err error) {
+		if err != nil {
+			ext.Error.Set(span, true)
+			span.LogFields(log.Error(err))
+		}
+		span.Finish()
+	}
+}
@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+)
+
+func TestStartJobSpan(t *testing.T) {
+	tracer := mocktracer.New()
+	globalTracer := opentracing.GlobalTracer()
+	opentracing.SetGlobalTracer(tracer)
+	t.Cleanup(func() { opentracing.SetGlobalTracer(globalTracer) })
+
+	parent := tracer.StartSpan("enqueue")
+	traceContext := map[string]string{}
+	if err := tracer.Inject(parent.Context(), opentracing.TextMap, opentracing.TextMapCarrier(traceContext)); err != nil {
+		t.Fatalf("unexpected error injecting span context: %s", err)
+	}
+
+	ctx, finish := startJobSpan(context.Background(), executor.Job{ID: 42, TraceContext: traceContext})
+	if opentracing.SpanFromContext(ctx) == nil {
+		t.Fatalf("expected a span in the context")
+	}
+	finish(errors.New("oops"))
+
+	spans := tracer.FinishedSpans()
+	if len(spans) != 1 {
+		t.Fatalf("unexpected number of finished spans. want=%d have=%d", 1, len(spans))
+	}
+	span := spans[0]
+
+	parentContext := parent.Context().(mocktracer.MockSpanContext)
+	if span.SpanContext.TraceID != parentContext.TraceID || span.ParentID != parentContext.SpanID {
+		t.Errorf("expected the job span to follow from the enqueuing span")
+	}
+	if value := span.Tag("jobID"); value != 42 {
+		t.Errorf("unexpected job ID tag. want=%d have=%v", 42, value)
+	}
+	if value := span.Tag("error"); value != true {
+		t.Errorf("expected the job span to be marked as failed")
+	}
+}
+
+func TestStartJobSpanUntraced(t *testing.T) {
+	tracer := mocktracer.New()
+	globalTracer := opentracing.GlobalTracer()
+	opentracing.SetGlobalTracer(tracer)
+	t.Cleanup(func() { opentracing.SetGlobalTracer(globalTracer) })
+
+	ctx, finish := startJobSpan(context.Background(), executor.Job{ID: 42})
+	finish(nil)
+
+	if opentracing.SpanFromContext(ctx) != nil {
+		t.Errorf("expected no span in the context")
+	}
+	if spans := tracer.FinishedSpans(); len(spans) != 0 {
+		t.Errorf("unexpected number of finished spans. want=%d have=%d", 0, len(spans))
+	}
+}
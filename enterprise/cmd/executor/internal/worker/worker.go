@@ -57,7 +57,9 @@ type Options struct {
 // it thinks may have been dropped.
 func NewWorker(options Options, observationContext *observation.Context) goroutine.BackgroundRoutine {
 	queueStore := apiclient.New(options.ClientOptions, observationContext)
-	store := &storeShim{queueName: options.QueueName, queueStore: queueStore}
+	checkpoints := newCheckpointTracker()
+	failures := newFailureClassTracker()
+	store := &storeShim{queueName: options.QueueName, queueStore: queueStore, checkpoints: checkpoints, failures: failures}
 
 	if !connectToFrontend(queueStore, options) {
 		os.Exit(1)
@@ -65,6 +67,8 @@ func NewWorker(options Options, observationContext *observation.Context) gorouti
 
 	handler := &handler{
 		store:         store,
+		checkpoints:   checkpoints,
+		failures:      failures,
 		options:       options,
 		operations:    command.NewOperations(observationContext),
 		runnerFactory: command.NewRunner,
@@ -36,6 +36,14 @@ func main() {
 		log.Fatalf("failed to read config: %s", err)
 	}
 
+	if config.JaegerEnabled {
+		closer, err := initJaeger()
+		if err != nil {
+			log.Fatalf("failed to initialize tracer: %s", err)
+		}
+		defer closer.Close()
+	}
+
 	// Initialize tracing/metrics
 	observationContext := &observation.Context{
 		Logger:     log15.Root(),
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/cockroachdb/errors"
+	"github.com/opentracing/opentracing-go"
+	jaeger "github.com/uber/jaeger-client-go"
+	jaegercfg "github.com/uber/jaeger-client-go/config"
+	jaegerlog "github.com/uber/jaeger-client-go/log"
+	jaegermetrics "github.com/uber/jaeger-lib/metrics"
+)
+
+// initJaeger sets a Jaeger tracer, configured by the JAEGER_* environment variables, as the
+// global tracer. The executor has no access to the site configuration that enables tracing in
+// other services, and only traces jobs that carry the trace context of the request that
+// enqueued them.
+func initJaeger() (io.Closer, error) {
+	cfg, err := jaegercfg.FromEnv()
+	if err != nil {
+		return nil, errors.Wrap(err, "jaegercfg.FromEnv failed")
+	}
+	cfg.ServiceName = "executor"
+	if reflect.DeepEqual(cfg.Sampler, &jaegercfg.SamplerConfig{}) {
+		// Sample every job span; jobs are only traced if their enqueuing request was sampled
+		cfg.Sampler.Type = jaeger.SamplerTypeConst
+		cfg.Sampler.Param = 1
+	}
+
+	tracer, closer, err := cfg.NewTracer(
+		jaegercfg.Logger(jaegerlog.StdLogger),
+		jaegercfg.Metrics(jaegermetrics.NullFactory),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "jaegercfg.NewTracer failed")
+	}
+
+	opentracing.SetGlobalTracer(tracer)
+	return closer, nil
+}
@@ -2552,6 +2552,9 @@ type MockEnqueuerDBStore struct {
 	// HandleFunc is an instance of a mock function object controlling the
 	// behavior of the method Handle.
 	HandleFunc *EnqueuerDBStoreHandleFunc
+	// IndexQueueSizeFunc is an instance of a mock function object
+	// controlling the behavior of the method IndexQueueSize.
+	IndexQueueSizeFunc *EnqueuerDBStoreIndexQueueSizeFunc
 	// InsertIndexFunc is an instance of a mock function object controlling
 	// the behavior of the method InsertIndex.
 	InsertIndexFunc *EnqueuerDBStoreInsertIndexFunc
@@ -2593,6 +2596,11 @@ func NewMockEnqueuerDBStore() *MockEnqueuerDBStore {
 				return nil
 			},
 		},
+		IndexQueueSizeFunc: &EnqueuerDBStoreIndexQueueSizeFunc{
+			defaultHook: func(context.Context) (int, error) {
+				return 0, nil
+			},
+		},
 		InsertIndexFunc: &EnqueuerDBStoreInsertIndexFunc{
 			defaultHook: func(context.Context, dbstore.Index) (int, error) {
 				return 0, nil
@@ -2631,6 +2639,9 @@ func NewMockEnqueuerDBStoreFrom(i EnqueuerDBStore) *MockEnqueuerDBStore {
 		HandleFunc: &EnqueuerDBStoreHandleFunc{
 			defaultHook: i.Handle,
 		},
+		IndexQueueSizeFunc: &EnqueuerDBStoreIndexQueueSizeFunc{
+			defaultHook: i.IndexQueueSize,
+		},
 		InsertIndexFunc: &EnqueuerDBStoreInsertIndexFunc{
 			defaultHook: i.InsertIndex,
 		},
@@ -3185,6 +3196,114 @@ func (c EnqueuerDBStoreHandleFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// EnqueuerDBStoreIndexQueueSizeFunc describes the behavior when the
+// IndexQueueSize method of the parent MockEnqueuerDBStore instance is
+// invoked.
+type EnqueuerDBStoreIndexQueueSizeFunc struct {
+	defaultHook func(context.Context) (int, error)
+	hooks       []func(context.Context) (int, error)
+	history     []EnqueuerDBStoreIndexQueueSizeFuncCall
+	mutex       sync.Mutex
+}
+
+// IndexQueueSize delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockEnqueuerDBStore) IndexQueueSize(v0 context.Context) (int, error) {
+	r0, r1 := m.IndexQueueSizeFunc.nextHook()(v0)
+	m.IndexQueueSizeFunc.appendCall(EnqueuerDBStoreIndexQueueSizeFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the IndexQueueSize
+// method of the parent MockEnqueuerDBStore instance is invoked and the hook
+// queue is empty.
+func (f *EnqueuerDBStoreIndexQueueSizeFunc) SetDefaultHook(hook func(context.Context) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// IndexQueueSize method of the parent MockEnqueuerDBStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *EnqueuerDBStoreIndexQueueSizeFunc) PushHook(hook func(context.Context) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *EnqueuerDBStoreIndexQueueSizeFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *EnqueuerDBStoreIndexQueueSizeFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *EnqueuerDBStoreIndexQueueSizeFunc) nextHook() func(context.Context) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *EnqueuerDBStoreIndexQueueSizeFunc) appendCall(r0 EnqueuerDBStoreIndexQueueSizeFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of EnqueuerDBStoreIndexQueueSizeFuncCall
+// objects describing the invocations of this function.
+func (f *EnqueuerDBStoreIndexQueueSizeFunc) History() []EnqueuerDBStoreIndexQueueSizeFuncCall {
+	f.mutex.Lock()
+	history := make([]EnqueuerDBStoreIndexQueueSizeFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// EnqueuerDBStoreIndexQueueSizeFuncCall is an object that describes an
+// invocation of method IndexQueueSize on an instance of
+// MockEnqueuerDBStore.
+type EnqueuerDBStoreIndexQueueSizeFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c EnqueuerDBStoreIndexQueueSizeFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c EnqueuerDBStoreIndexQueueSizeFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // EnqueuerDBStoreInsertIndexFunc describes the behavior when the
 // InsertIndex method of the parent MockEnqueuerDBStore instance is invoked.
 type EnqueuerDBStoreInsertIndexFunc struct {
@@ -0,0 +1,44 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+)
+
+// insightsSearchHandler creates an HTTP handler that performs the search query of a code insights
+// backfill job on behalf of an executor and responds with the number of matches per repository.
+//
+// 🚨 SECURITY: The search is performed without authentication, as it is by the worker. Only the
+// match counts are returned to the executor, never the matches themselves.
+func insightsSearchHandler(search func(ctx context.Context, query string) (queryrunner.MatchCounts, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload executor.InsightsSearchRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, fmt.Sprintf("failed to unmarshal payload: %s", err), http.StatusBadRequest)
+			return
+		}
+		if payload.Query == "" {
+			http.Error(w, "no query supplied", http.StatusBadRequest)
+			return
+		}
+
+		matchCounts, err := search(r.Context(), payload.Query)
+		if err != nil {
+			log15.Error("Failed to perform insights search", "query", payload.Query, "err", err)
+			http.Error(w, fmt.Sprintf("failed to perform search: %s", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(matchCounts); err != nil {
+			log15.Error("Failed to write payload to client", "err", err)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+)
+
+func TestInsightsSearchHandler(t *testing.T) {
+	search := func(ctx context.Context, query string) (queryrunner.MatchCounts, error) {
+		if query == "broken" {
+			return nil, errors.New("oops")
+		}
+		return queryrunner.MatchCounts{"UmVwb3NpdG9yeTox": len(query)}, nil
+	}
+	handler := insightsSearchHandler(search)
+
+	testCases := []struct {
+		payload        string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{payload: `{"query": "TODO"}`, expectedStatus: http.StatusOK, expectedBody: `{"UmVwb3NpdG9yeTox":4}` + "\n"},
+		{payload: `{"query": ""}`, expectedStatus: http.StatusBadRequest},
+		{payload: `not json`, expectedStatus: http.StatusBadRequest},
+		{payload: `{"query": "broken"}`, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, testCase := range testCases {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/.executors/insights/search", strings.NewReader(testCase.payload)))
+
+		if w.Code != testCase.expectedStatus {
+			t.Errorf("unexpected status code for %s. want=%d have=%d", testCase.payload, testCase.expectedStatus, w.Code)
+		}
+		if testCase.expectedBody != "" && w.Body.String() != testCase.expectedBody {
+			t.Errorf("unexpected body. want=%q have=%q", testCase.expectedBody, w.Body.String())
+		}
+	}
+}
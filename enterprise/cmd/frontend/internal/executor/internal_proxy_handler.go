@@ -14,6 +14,7 @@ import (
 	"github.com/inconshreveable/log15"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 )
 
@@ -65,9 +66,15 @@ func newInternalProxyHandler(uploadHandler http.Handler) (func() http.Handler, e
 		// Proxy only the known routes in the executor queue API
 		base.Path("/queue/{rest:.*/(?:dequeue|addExecutionLogEntry|updateExecutionLogEntry|markComplete|markErrored|markFailed|heartbeat)}").Handler(reverseProxy(queueOrigin))
 
+		// Stream artifact uploads, which may be too large to buffer in memory
+		base.Path("/queue/{rest:.*/uploadArtifact}").Methods("POST").Handler(streamingReverseProxy(queueOrigin))
+
 		// Upload LSIF indexes without a sudo access token or github tokens
 		base.Path("/lsif/upload").Methods("POST").Handler(uploadHandler)
 
+		// Perform the search queries of code insights backfill jobs
+		base.Path("/insights/search").Methods("POST").Handler(insightsSearchHandler(queryrunner.SearchMatchCounts))
+
 		return basicAuthMiddleware(base)
 	}
 
@@ -107,8 +114,20 @@ var client = http.DefaultClient
 // requests to unreachable (internal) routes sent back to the client, and the built-in reverse
 // proxy does not give sufficient control over redirects.
 func reverseProxy(target *url.URL) http.Handler {
+	return proxy(target, makeProxyRequest)
+}
+
+// streamingReverseProxy creates an HTTP handler that will proxy requests to the given target
+// URL without buffering the request body. See makeStreamingProxyRequest for details. Redirects
+// of the proxied request cannot be followed, so this should only be used for targets that do
+// not redirect.
+func streamingReverseProxy(target *url.URL) http.Handler {
+	return proxy(target, makeStreamingProxyRequest)
+}
+
+func proxy(target *url.URL, makeRequest func(r *http.Request, target *url.URL) (*http.Request, error)) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		r, err := makeProxyRequest(r, target)
+		r, err := makeRequest(r, target)
 		if err != nil {
 			log15.Error("Failed to construct proxy request", "err", err)
 			http.Error(w, fmt.Sprintf("failed to construct proxy request: %s", err), http.StatusInternalServerError)
@@ -152,12 +171,7 @@ func makeProxyRequest(r *http.Request, target *url.URL) (*http.Request, error) {
 		return nil, err
 	}
 
-	u := r.URL
-	u.Scheme = target.Scheme
-	u.Host = target.Host
-	u.Path = path.Join("/", target.Path, getRest(r))
-
-	req, err := http.NewRequest(r.Method, u.String(), bytes.NewReader(content))
+	req, err := http.NewRequest(r.Method, proxyURL(r, target), bytes.NewReader(content))
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +181,30 @@ func makeProxyRequest(r *http.Request, target *url.URL) (*http.Request, error) {
 	return req, nil
 }
 
+// makeStreamingProxyRequest returns a new HTTP request object with the given HTTP request's
+// headers and body. The URL is constructed as in makeProxyRequest. The body of the given
+// request is read as the resulting request is sent, so the resulting request's GetBody field
+// is not populated.
+func makeStreamingProxyRequest(r *http.Request, target *url.URL) (*http.Request, error) {
+	req, err := http.NewRequest(r.Method, proxyURL(r, target), r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	copyHeader(req.Header, r.Header)
+	req.ContentLength = r.ContentLength
+	return req, nil
+}
+
+// proxyURL returns the given request's URL rebased onto the given target URL.
+func proxyURL(r *http.Request, target *url.URL) string {
+	u := r.URL
+	u.Scheme = target.Scheme
+	u.Host = target.Host
+	u.Path = path.Join("/", target.Path, getRest(r))
+	return u.String()
+}
+
 // copyHeader adds the header values from src to dst.
 func copyHeader(dst, src http.Header) {
 	for k, vs := range src {
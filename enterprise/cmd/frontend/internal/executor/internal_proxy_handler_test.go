@@ -213,3 +213,46 @@ func TestReverseProxyRedirectWithPayload(t *testing.T) {
 		t.Errorf("unexpected header value. want=%s have=%s", "foobarbaz", value)
 	}
 }
+
+func TestStreamingReverseProxyPayload(t *testing.T) {
+	originServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contents, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Add("payload", string(contents))
+		w.Header().Add("content-type", r.Header.Get("content-type"))
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer originServer.Close()
+
+	url, err := url.Parse(originServer.URL)
+	if err != nil {
+		t.Fatalf("unexpected error parsing url: %s", err)
+	}
+
+	proxyServer := httptest.NewServer(streamingReverseProxy(url))
+	defer proxyServer.Close()
+
+	req, err := http.NewRequest("POST", proxyServer.URL, bytes.NewReader([]byte("foobarbaz")))
+	if err != nil {
+		t.Fatalf("unexpected error creating request: %s", err)
+	}
+	req.Header.Add("content-type", "multipart/form-data; boundary=foo")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error performing request: %s", err)
+	}
+	if resp.StatusCode != http.StatusTeapot {
+		t.Errorf("unexpected status code. want=%d have=%d", http.StatusTeapot, resp.StatusCode)
+	}
+	if value := resp.Header.Get("payload"); value != "foobarbaz" {
+		t.Errorf("unexpected header value. want=%s have=%s", "foobarbaz", value)
+	}
+	if value := resp.Header.Get("content-type"); value != "multipart/form-data; boundary=foo" {
+		t.Errorf("unexpected header value. want=%s have=%s", "multipart/form-data; boundary=foo", value)
+	}
+}
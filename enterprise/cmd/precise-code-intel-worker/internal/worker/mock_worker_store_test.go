@@ -21,24 +21,45 @@ type MockWorkerStore struct {
 	// AddExecutionLogEntryFunc is an instance of a mock function object
 	// controlling the behavior of the method AddExecutionLogEntry.
 	AddExecutionLogEntryFunc *WorkerStoreAddExecutionLogEntryFunc
+	// DeleteFunc is an instance of a mock function object controlling the
+	// behavior of the method Delete.
+	DeleteFunc *WorkerStoreDeleteFunc
 	// DequeueFunc is an instance of a mock function object controlling the
 	// behavior of the method Dequeue.
 	DequeueFunc *WorkerStoreDequeueFunc
+	// DequeueBatchFunc is an instance of a mock function object controlling
+	// the behavior of the method DequeueBatch.
+	DequeueBatchFunc *WorkerStoreDequeueBatchFunc
+	// GetFunc is an instance of a mock function object controlling the
+	// behavior of the method Get.
+	GetFunc *WorkerStoreGetFunc
 	// HandleFunc is an instance of a mock function object controlling the
 	// behavior of the method Handle.
 	HandleFunc *WorkerStoreHandleFunc
 	// HeartbeatFunc is an instance of a mock function object controlling
 	// the behavior of the method Heartbeat.
 	HeartbeatFunc *WorkerStoreHeartbeatFunc
+	// ListFunc is an instance of a mock function object controlling the
+	// behavior of the method List.
+	ListFunc *WorkerStoreListFunc
 	// MarkCompleteFunc is an instance of a mock function object controlling
 	// the behavior of the method MarkComplete.
 	MarkCompleteFunc *WorkerStoreMarkCompleteFunc
+	// MarkDependentsFailedFunc is an instance of a mock function object
+	// controlling the behavior of the method MarkDependentsFailed.
+	MarkDependentsFailedFunc *WorkerStoreMarkDependentsFailedFunc
 	// MarkErroredFunc is an instance of a mock function object controlling
 	// the behavior of the method MarkErrored.
 	MarkErroredFunc *WorkerStoreMarkErroredFunc
 	// MarkFailedFunc is an instance of a mock function object controlling
 	// the behavior of the method MarkFailed.
 	MarkFailedFunc *WorkerStoreMarkFailedFunc
+	// MarkQueuedFailedFunc is an instance of a mock function object
+	// controlling the behavior of the method MarkQueuedFailed.
+	MarkQueuedFailedFunc *WorkerStoreMarkQueuedFailedFunc
+	// ProcessingCountFunc is an instance of a mock function object
+	// controlling the behavior of the method ProcessingCount.
+	ProcessingCountFunc *WorkerStoreProcessingCountFunc
 	// QueuedCountFunc is an instance of a mock function object controlling
 	// the behavior of the method QueuedCount.
 	QueuedCountFunc *WorkerStoreQueuedCountFunc
@@ -48,6 +69,9 @@ type MockWorkerStore struct {
 	// ResetStalledFunc is an instance of a mock function object controlling
 	// the behavior of the method ResetStalled.
 	ResetStalledFunc *WorkerStoreResetStalledFunc
+	// ResetWorkerFunc is an instance of a mock function object controlling
+	// the behavior of the method ResetWorker.
+	ResetWorkerFunc *WorkerStoreResetWorkerFunc
 	// UpdateExecutionLogEntryFunc is an instance of a mock function object
 	// controlling the behavior of the method UpdateExecutionLogEntry.
 	UpdateExecutionLogEntryFunc *WorkerStoreUpdateExecutionLogEntryFunc
@@ -62,11 +86,26 @@ func NewMockWorkerStore() *MockWorkerStore {
 				return 0, nil
 			},
 		},
+		DeleteFunc: &WorkerStoreDeleteFunc{
+			defaultHook: func(context.Context, int) (bool, error) {
+				return false, nil
+			},
+		},
 		DequeueFunc: &WorkerStoreDequeueFunc{
 			defaultHook: func(context.Context, string, []*sqlf.Query) (workerutil.Record, bool, error) {
 				return nil, false, nil
 			},
 		},
+		DequeueBatchFunc: &WorkerStoreDequeueBatchFunc{
+			defaultHook: func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error) {
+				return nil, nil
+			},
+		},
+		GetFunc: &WorkerStoreGetFunc{
+			defaultHook: func(context.Context, int) (workerutil.Record, bool, error) {
+				return nil, false, nil
+			},
+		},
 		HandleFunc: &WorkerStoreHandleFunc{
 			defaultHook: func() *basestore.TransactableHandle {
 				return nil
@@ -77,11 +116,21 @@ func NewMockWorkerStore() *MockWorkerStore {
 				return nil, nil
 			},
 		},
+		ListFunc: &WorkerStoreListFunc{
+			defaultHook: func(context.Context, store.ListOptions) ([]workerutil.Record, error) {
+				return nil, nil
+			},
+		},
 		MarkCompleteFunc: &WorkerStoreMarkCompleteFunc{
 			defaultHook: func(context.Context, int, store.MarkFinalOptions) (bool, error) {
 				return false, nil
 			},
 		},
+		MarkDependentsFailedFunc: &WorkerStoreMarkDependentsFailedFunc{
+			defaultHook: func(context.Context) ([]int, error) {
+				return nil, nil
+			},
+		},
 		MarkErroredFunc: &WorkerStoreMarkErroredFunc{
 			defaultHook: func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
 				return false, nil
@@ -92,6 +141,16 @@ func NewMockWorkerStore() *MockWorkerStore {
 				return false, nil
 			},
 		},
+		MarkQueuedFailedFunc: &WorkerStoreMarkQueuedFailedFunc{
+			defaultHook: func(context.Context, []*sqlf.Query, string) ([]int, error) {
+				return nil, nil
+			},
+		},
+		ProcessingCountFunc: &WorkerStoreProcessingCountFunc{
+			defaultHook: func(context.Context, []*sqlf.Query) (int, error) {
+				return 0, nil
+			},
+		},
 		QueuedCountFunc: &WorkerStoreQueuedCountFunc{
 			defaultHook: func(context.Context, []*sqlf.Query) (int, error) {
 				return 0, nil
@@ -107,6 +166,11 @@ func NewMockWorkerStore() *MockWorkerStore {
 				return nil, nil, nil
 			},
 		},
+		ResetWorkerFunc: &WorkerStoreResetWorkerFunc{
+			defaultHook: func(context.Context, string) ([]int, []int, error) {
+				return nil, nil, nil
+			},
+		},
 		UpdateExecutionLogEntryFunc: &WorkerStoreUpdateExecutionLogEntryFunc{
 			defaultHook: func(context.Context, int, int, workerutil.ExecutionLogEntry, store.ExecutionLogEntryOptions) error {
 				return nil
@@ -123,24 +187,45 @@ func NewMockWorkerStoreFrom(i store.Store) *MockWorkerStore {
 		AddExecutionLogEntryFunc: &WorkerStoreAddExecutionLogEntryFunc{
 			defaultHook: i.AddExecutionLogEntry,
 		},
+		DeleteFunc: &WorkerStoreDeleteFunc{
+			defaultHook: i.Delete,
+		},
 		DequeueFunc: &WorkerStoreDequeueFunc{
 			defaultHook: i.Dequeue,
 		},
+		DequeueBatchFunc: &WorkerStoreDequeueBatchFunc{
+			defaultHook: i.DequeueBatch,
+		},
+		GetFunc: &WorkerStoreGetFunc{
+			defaultHook: i.Get,
+		},
 		HandleFunc: &WorkerStoreHandleFunc{
 			defaultHook: i.Handle,
 		},
 		HeartbeatFunc: &WorkerStoreHeartbeatFunc{
 			defaultHook: i.Heartbeat,
 		},
+		ListFunc: &WorkerStoreListFunc{
+			defaultHook: i.List,
+		},
 		MarkCompleteFunc: &WorkerStoreMarkCompleteFunc{
 			defaultHook: i.MarkComplete,
 		},
+		MarkDependentsFailedFunc: &WorkerStoreMarkDependentsFailedFunc{
+			defaultHook: i.MarkDependentsFailed,
+		},
 		MarkErroredFunc: &WorkerStoreMarkErroredFunc{
 			defaultHook: i.MarkErrored,
 		},
 		MarkFailedFunc: &WorkerStoreMarkFailedFunc{
 			defaultHook: i.MarkFailed,
 		},
+		MarkQueuedFailedFunc: &WorkerStoreMarkQueuedFailedFunc{
+			defaultHook: i.MarkQueuedFailed,
+		},
+		ProcessingCountFunc: &WorkerStoreProcessingCountFunc{
+			defaultHook: i.ProcessingCount,
+		},
 		QueuedCountFunc: &WorkerStoreQueuedCountFunc{
 			defaultHook: i.QueuedCount,
 		},
@@ -150,6 +235,9 @@ func NewMockWorkerStoreFrom(i store.Store) *MockWorkerStore {
 		ResetStalledFunc: &WorkerStoreResetStalledFunc{
 			defaultHook: i.ResetStalled,
 		},
+		ResetWorkerFunc: &WorkerStoreResetWorkerFunc{
+			defaultHook: i.ResetWorker,
+		},
 		UpdateExecutionLogEntryFunc: &WorkerStoreUpdateExecutionLogEntryFunc{
 			defaultHook: i.UpdateExecutionLogEntry,
 		},
@@ -274,6 +362,114 @@ func (c WorkerStoreAddExecutionLogEntryFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// WorkerStoreDeleteFunc describes the behavior when the Delete method of
+// the parent MockWorkerStore instance is invoked.
+type WorkerStoreDeleteFunc struct {
+	defaultHook func(context.Context, int) (bool, error)
+	hooks       []func(context.Context, int) (bool, error)
+	history     []WorkerStoreDeleteFuncCall
+	mutex       sync.Mutex
+}
+
+// Delete delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockWorkerStore) Delete(v0 context.Context, v1 int) (bool, error) {
+	r0, r1 := m.DeleteFunc.nextHook()(v0, v1)
+	m.DeleteFunc.appendCall(WorkerStoreDeleteFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Delete method of the
+// parent MockWorkerStore instance is invoked and the hook queue is empty.
+func (f *WorkerStoreDeleteFunc) SetDefaultHook(hook func(context.Context, int) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Delete method of the parent MockWorkerStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *WorkerStoreDeleteFunc) PushHook(hook func(context.Context, int) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WorkerStoreDeleteFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WorkerStoreDeleteFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, int) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *WorkerStoreDeleteFunc) nextHook() func(context.Context, int) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WorkerStoreDeleteFunc) appendCall(r0 WorkerStoreDeleteFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WorkerStoreDeleteFuncCall objects
+// describing the invocations of this function.
+func (f *WorkerStoreDeleteFunc) History() []WorkerStoreDeleteFuncCall {
+	f.mutex.Lock()
+	history := make([]WorkerStoreDeleteFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WorkerStoreDeleteFuncCall is an object that describes an invocation of
+// method Delete on an instance of MockWorkerStore.
+type WorkerStoreDeleteFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WorkerStoreDeleteFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WorkerStoreDeleteFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // WorkerStoreDequeueFunc describes the behavior when the Dequeue method of
 // the parent MockWorkerStore instance is invoked.
 type WorkerStoreDequeueFunc struct {
@@ -389,6 +585,232 @@ func (c WorkerStoreDequeueFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// WorkerStoreDequeueBatchFunc describes the behavior when the DequeueBatch
+// method of the parent MockWorkerStore instance is invoked.
+type WorkerStoreDequeueBatchFunc struct {
+	defaultHook func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error)
+	hooks       []func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error)
+	history     []WorkerStoreDequeueBatchFuncCall
+	mutex       sync.Mutex
+}
+
+// DequeueBatch delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockWorkerStore) DequeueBatch(v0 context.Context, v1 string, v2 []*sqlf.Query, v3 int) ([]workerutil.Record, error) {
+	r0, r1 := m.DequeueBatchFunc.nextHook()(v0, v1, v2, v3)
+	m.DequeueBatchFunc.appendCall(WorkerStoreDequeueBatchFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the DequeueBatch method
+// of the parent MockWorkerStore instance is invoked and the hook queue is
+// empty.
+func (f *WorkerStoreDequeueBatchFunc) SetDefaultHook(hook func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DequeueBatch method of the parent MockWorkerStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *WorkerStoreDequeueBatchFunc) PushHook(hook func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WorkerStoreDequeueBatchFunc) SetDefaultReturn(r0 []workerutil.Record, r1 error) {
+	f.SetDefaultHook(func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WorkerStoreDequeueBatchFunc) PushReturn(r0 []workerutil.Record, r1 error) {
+	f.PushHook(func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error) {
+		return r0, r1
+	})
+}
+
+func (f *WorkerStoreDequeueBatchFunc) nextHook() func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WorkerStoreDequeueBatchFunc) appendCall(r0 WorkerStoreDequeueBatchFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WorkerStoreDequeueBatchFuncCall objects
+// describing the invocations of this function.
+func (f *WorkerStoreDequeueBatchFunc) History() []WorkerStoreDequeueBatchFuncCall {
+	f.mutex.Lock()
+	history := make([]WorkerStoreDequeueBatchFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WorkerStoreDequeueBatchFuncCall is an object that describes an invocation
+// of method DequeueBatch on an instance of MockWorkerStore.
+type WorkerStoreDequeueBatchFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []*sqlf.Query
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []workerutil.Record
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WorkerStoreDequeueBatchFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WorkerStoreDequeueBatchFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// WorkerStoreGetFunc describes the behavior when the Get method of the
+// parent MockWorkerStore instance is invoked.
+type WorkerStoreGetFunc struct {
+	defaultHook func(context.Context, int) (workerutil.Record, bool, error)
+	hooks       []func(context.Context, int) (workerutil.Record, bool, error)
+	history     []WorkerStoreGetFuncCall
+	mutex       sync.Mutex
+}
+
+// Get delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockWorkerStore) Get(v0 context.Context, v1 int) (workerutil.Record, bool, error) {
+	r0, r1, r2 := m.GetFunc.nextHook()(v0, v1)
+	m.GetFunc.appendCall(WorkerStoreGetFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the Get method of the
+// parent MockWorkerStore instance is invoked and the hook queue is empty.
+func (f *WorkerStoreGetFunc) SetDefaultHook(hook func(context.Context, int) (workerutil.Record, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Get method of the parent MockWorkerStore instance invokes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *WorkerStoreGetFunc) PushHook(hook func(context.Context, int) (workerutil.Record, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WorkerStoreGetFunc) SetDefaultReturn(r0 workerutil.Record, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) (workerutil.Record, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WorkerStoreGetFunc) PushReturn(r0 workerutil.Record, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int) (workerutil.Record, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *WorkerStoreGetFunc) nextHook() func(context.Context, int) (workerutil.Record, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WorkerStoreGetFunc) appendCall(r0 WorkerStoreGetFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WorkerStoreGetFuncCall objects describing
+// the invocations of this function.
+func (f *WorkerStoreGetFunc) History() []WorkerStoreGetFuncCall {
+	f.mutex.Lock()
+	history := make([]WorkerStoreGetFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WorkerStoreGetFuncCall is an object that describes an invocation of
+// method Get on an instance of MockWorkerStore.
+type WorkerStoreGetFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 workerutil.Record
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WorkerStoreGetFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WorkerStoreGetFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // WorkerStoreHandleFunc describes the behavior when the Handle method of
 // the parent MockWorkerStore instance is invoked.
 type WorkerStoreHandleFunc struct {
@@ -600,6 +1022,114 @@ func (c WorkerStoreHeartbeatFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// WorkerStoreListFunc describes the behavior when the List method of the
+// parent MockWorkerStore instance is invoked.
+type WorkerStoreListFunc struct {
+	defaultHook func(context.Context, store.ListOptions) ([]workerutil.Record, error)
+	hooks       []func(context.Context, store.ListOptions) ([]workerutil.Record, error)
+	history     []WorkerStoreListFuncCall
+	mutex       sync.Mutex
+}
+
+// List delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockWorkerStore) List(v0 context.Context, v1 store.ListOptions) ([]workerutil.Record, error) {
+	r0, r1 := m.ListFunc.nextHook()(v0, v1)
+	m.ListFunc.appendCall(WorkerStoreListFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the List method of the
+// parent MockWorkerStore instance is invoked and the hook queue is empty.
+func (f *WorkerStoreListFunc) SetDefaultHook(hook func(context.Context, store.ListOptions) ([]workerutil.Record, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// List method of the parent MockWorkerStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *WorkerStoreListFunc) PushHook(hook func(context.Context, store.ListOptions) ([]workerutil.Record, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WorkerStoreListFunc) SetDefaultReturn(r0 []workerutil.Record, r1 error) {
+	f.SetDefaultHook(func(context.Context, store.ListOptions) ([]workerutil.Record, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WorkerStoreListFunc) PushReturn(r0 []workerutil.Record, r1 error) {
+	f.PushHook(func(context.Context, store.ListOptions) ([]workerutil.Record, error) {
+		return r0, r1
+	})
+}
+
+func (f *WorkerStoreListFunc) nextHook() func(context.Context, store.ListOptions) ([]workerutil.Record, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WorkerStoreListFunc) appendCall(r0 WorkerStoreListFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WorkerStoreListFuncCall objects describing
+// the invocations of this function.
+func (f *WorkerStoreListFunc) History() []WorkerStoreListFuncCall {
+	f.mutex.Lock()
+	history := make([]WorkerStoreListFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WorkerStoreListFuncCall is an object that describes an invocation of
+// method List on an instance of MockWorkerStore.
+type WorkerStoreListFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 store.ListOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []workerutil.Record
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WorkerStoreListFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WorkerStoreListFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // WorkerStoreMarkCompleteFunc describes the behavior when the MarkComplete
 // method of the parent MockWorkerStore instance is invoked.
 type WorkerStoreMarkCompleteFunc struct {
@@ -708,7 +1238,116 @@ func (c WorkerStoreMarkCompleteFuncCall) Args() []interface{} {
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c WorkerStoreMarkCompleteFuncCall) Results() []interface{} {
+func (c WorkerStoreMarkCompleteFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// WorkerStoreMarkDependentsFailedFunc describes the behavior when the
+// MarkDependentsFailed method of the parent MockWorkerStore instance is
+// invoked.
+type WorkerStoreMarkDependentsFailedFunc struct {
+	defaultHook func(context.Context) ([]int, error)
+	hooks       []func(context.Context) ([]int, error)
+	history     []WorkerStoreMarkDependentsFailedFuncCall
+	mutex       sync.Mutex
+}
+
+// MarkDependentsFailed delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockWorkerStore) MarkDependentsFailed(v0 context.Context) ([]int, error) {
+	r0, r1 := m.MarkDependentsFailedFunc.nextHook()(v0)
+	m.MarkDependentsFailedFunc.appendCall(WorkerStoreMarkDependentsFailedFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the MarkDependentsFailed
+// method of the parent MockWorkerStore instance is invoked and the hook
+// queue is empty.
+func (f *WorkerStoreMarkDependentsFailedFunc) SetDefaultHook(hook func(context.Context) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// MarkDependentsFailed method of the parent MockWorkerStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *WorkerStoreMarkDependentsFailedFunc) PushHook(hook func(context.Context) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WorkerStoreMarkDependentsFailedFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WorkerStoreMarkDependentsFailedFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *WorkerStoreMarkDependentsFailedFunc) nextHook() func(context.Context) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WorkerStoreMarkDependentsFailedFunc) appendCall(r0 WorkerStoreMarkDependentsFailedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WorkerStoreMarkDependentsFailedFuncCall
+// objects describing the invocations of this function.
+func (f *WorkerStoreMarkDependentsFailedFunc) History() []WorkerStoreMarkDependentsFailedFuncCall {
+	f.mutex.Lock()
+	history := make([]WorkerStoreMarkDependentsFailedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WorkerStoreMarkDependentsFailedFuncCall is an object that describes an
+// invocation of method MarkDependentsFailed on an instance of
+// MockWorkerStore.
+type WorkerStoreMarkDependentsFailedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WorkerStoreMarkDependentsFailedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WorkerStoreMarkDependentsFailedFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
@@ -942,6 +1581,228 @@ func (c WorkerStoreMarkFailedFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// WorkerStoreMarkQueuedFailedFunc describes the behavior when the
+// MarkQueuedFailed method of the parent MockWorkerStore instance is
+// invoked.
+type WorkerStoreMarkQueuedFailedFunc struct {
+	defaultHook func(context.Context, []*sqlf.Query, string) ([]int, error)
+	hooks       []func(context.Context, []*sqlf.Query, string) ([]int, error)
+	history     []WorkerStoreMarkQueuedFailedFuncCall
+	mutex       sync.Mutex
+}
+
+// MarkQueuedFailed delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockWorkerStore) MarkQueuedFailed(v0 context.Context, v1 []*sqlf.Query, v2 string) ([]int, error) {
+	r0, r1 := m.MarkQueuedFailedFunc.nextHook()(v0, v1, v2)
+	m.MarkQueuedFailedFunc.appendCall(WorkerStoreMarkQueuedFailedFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the MarkQueuedFailed
+// method of the parent MockWorkerStore instance is invoked and the hook
+// queue is empty.
+func (f *WorkerStoreMarkQueuedFailedFunc) SetDefaultHook(hook func(context.Context, []*sqlf.Query, string) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// MarkQueuedFailed method of the parent MockWorkerStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *WorkerStoreMarkQueuedFailedFunc) PushHook(hook func(context.Context, []*sqlf.Query, string) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WorkerStoreMarkQueuedFailedFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context, []*sqlf.Query, string) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WorkerStoreMarkQueuedFailedFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context, []*sqlf.Query, string) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *WorkerStoreMarkQueuedFailedFunc) nextHook() func(context.Context, []*sqlf.Query, string) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WorkerStoreMarkQueuedFailedFunc) appendCall(r0 WorkerStoreMarkQueuedFailedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WorkerStoreMarkQueuedFailedFuncCall objects
+// describing the invocations of this function.
+func (f *WorkerStoreMarkQueuedFailedFunc) History() []WorkerStoreMarkQueuedFailedFuncCall {
+	f.mutex.Lock()
+	history := make([]WorkerStoreMarkQueuedFailedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WorkerStoreMarkQueuedFailedFuncCall is an object that describes an
+// invocation of method MarkQueuedFailed on an instance of MockWorkerStore.
+type WorkerStoreMarkQueuedFailedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []*sqlf.Query
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WorkerStoreMarkQueuedFailedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WorkerStoreMarkQueuedFailedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// WorkerStoreProcessingCountFunc describes the behavior when the
+// ProcessingCount method of the parent MockWorkerStore instance is invoked.
+type WorkerStoreProcessingCountFunc struct {
+	defaultHook func(context.Context, []*sqlf.Query) (int, error)
+	hooks       []func(context.Context, []*sqlf.Query) (int, error)
+	history     []WorkerStoreProcessingCountFuncCall
+	mutex       sync.Mutex
+}
+
+// ProcessingCount delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockWorkerStore) ProcessingCount(v0 context.Context, v1 []*sqlf.Query) (int, error) {
+	r0, r1 := m.ProcessingCountFunc.nextHook()(v0, v1)
+	m.ProcessingCountFunc.appendCall(WorkerStoreProcessingCountFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ProcessingCount
+// method of the parent MockWorkerStore instance is invoked and the hook
+// queue is empty.
+func (f *WorkerStoreProcessingCountFunc) SetDefaultHook(hook func(context.Context, []*sqlf.Query) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ProcessingCount method of the parent MockWorkerStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *WorkerStoreProcessingCountFunc) PushHook(hook func(context.Context, []*sqlf.Query) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WorkerStoreProcessingCountFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context, []*sqlf.Query) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WorkerStoreProcessingCountFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context, []*sqlf.Query) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *WorkerStoreProcessingCountFunc) nextHook() func(context.Context, []*sqlf.Query) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WorkerStoreProcessingCountFunc) appendCall(r0 WorkerStoreProcessingCountFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WorkerStoreProcessingCountFuncCall objects
+// describing the invocations of this function.
+func (f *WorkerStoreProcessingCountFunc) History() []WorkerStoreProcessingCountFuncCall {
+	f.mutex.Lock()
+	history := make([]WorkerStoreProcessingCountFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WorkerStoreProcessingCountFuncCall is an object that describes an
+// invocation of method ProcessingCount on an instance of MockWorkerStore.
+type WorkerStoreProcessingCountFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []*sqlf.Query
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WorkerStoreProcessingCountFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WorkerStoreProcessingCountFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // WorkerStoreQueuedCountFunc describes the behavior when the QueuedCount
 // method of the parent MockWorkerStore instance is invoked.
 type WorkerStoreQueuedCountFunc struct {
@@ -1269,6 +2130,118 @@ func (c WorkerStoreResetStalledFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// WorkerStoreResetWorkerFunc describes the behavior when the ResetWorker
+// method of the parent MockWorkerStore instance is invoked.
+type WorkerStoreResetWorkerFunc struct {
+	defaultHook func(context.Context, string) ([]int, []int, error)
+	hooks       []func(context.Context, string) ([]int, []int, error)
+	history     []WorkerStoreResetWorkerFuncCall
+	mutex       sync.Mutex
+}
+
+// ResetWorker delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockWorkerStore) ResetWorker(v0 context.Context, v1 string) ([]int, []int, error) {
+	r0, r1, r2 := m.ResetWorkerFunc.nextHook()(v0, v1)
+	m.ResetWorkerFunc.appendCall(WorkerStoreResetWorkerFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the ResetWorker method
+// of the parent MockWorkerStore instance is invoked and the hook queue is
+// empty.
+func (f *WorkerStoreResetWorkerFunc) SetDefaultHook(hook func(context.Context, string) ([]int, []int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ResetWorker method of the parent MockWorkerStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *WorkerStoreResetWorkerFunc) PushHook(hook func(context.Context, string) ([]int, []int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WorkerStoreResetWorkerFunc) SetDefaultReturn(r0 []int, r1 []int, r2 error) {
+	f.SetDefaultHook(func(context.Context, string) ([]int, []int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WorkerStoreResetWorkerFunc) PushReturn(r0 []int, r1 []int, r2 error) {
+	f.PushHook(func(context.Context, string) ([]int, []int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *WorkerStoreResetWorkerFunc) nextHook() func(context.Context, string) ([]int, []int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WorkerStoreResetWorkerFunc) appendCall(r0 WorkerStoreResetWorkerFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WorkerStoreResetWorkerFuncCall objects
+// describing the invocations of this function.
+func (f *WorkerStoreResetWorkerFunc) History() []WorkerStoreResetWorkerFuncCall {
+	f.mutex.Lock()
+	history := make([]WorkerStoreResetWorkerFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WorkerStoreResetWorkerFuncCall is an object that describes an invocation
+// of method ResetWorker on an instance of MockWorkerStore.
+type WorkerStoreResetWorkerFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 []int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WorkerStoreResetWorkerFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WorkerStoreResetWorkerFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // WorkerStoreUpdateExecutionLogEntryFunc describes the behavior when the
 // UpdateExecutionLogEntry method of the parent MockWorkerStore instance is
 // invoked.
@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	searchrepos "github.com/sourcegraph/sourcegraph/internal/search/repos"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
 type IndexScheduler struct {
@@ -116,6 +117,11 @@ func (s *IndexScheduler) Handle(ctx context.Context) error {
 			if errors.HasType(err, &gitserver.RevisionNotFoundError{}) {
 				continue
 			}
+			if errors.Is(err, dbworkerstore.ErrQueueFull) {
+				// Stop enqueueing until the next run to let executors drain the queue
+				log15.Warn("Index queue is full, deferring remaining repositories to the next run")
+				break
+			}
 
 			if queueErr != nil {
 				queueErr = err
@@ -17,9 +17,6 @@ type metrics struct {
 	// Resetter metrics
 	numUploadResetFailures          prometheus.Counter
 	numUploadResetErrors            prometheus.Counter
-	numIndexResets                  prometheus.Counter
-	numIndexResetFailures           prometheus.Counter
-	numIndexResetErrors             prometheus.Counter
 	numDependencyIndexResets        prometheus.Counter
 	numDependencyIndexResetFailures prometheus.Counter
 	numDependencyIndexResetErrors   prometheus.Counter
@@ -68,19 +65,6 @@ func newMetrics(observationContext *observation.Context) *metrics {
 		"The number of errors that occur during upload record resets.",
 	)
 
-	numIndexResets := counter(
-		"src_codeintel_background_index_record_resets_total",
-		"The number of index records reset.",
-	)
-	numIndexResetFailures := counter(
-		"src_codeintel_background_index_record_reset_failures_total",
-		"The number of dependency index reset failures.",
-	)
-	numIndexResetErrors := counter(
-		"src_codeintel_background_index_record_reset_errors_total",
-		"The number of errors that occur during index records reset.",
-	)
-
 	numDependencyIndexResets := counter(
 		"src_codeintel_background_dependency_index_record_resets_total",
 		"The number of dependency index records reset.",
@@ -102,9 +86,6 @@ func newMetrics(observationContext *observation.Context) *metrics {
 		numUploadResets:                 numUploadResets,
 		numUploadResetFailures:          numUploadResetFailures,
 		numUploadResetErrors:            numUploadResetErrors,
-		numIndexResets:                  numIndexResets,
-		numIndexResetFailures:           numIndexResetFailures,
-		numIndexResetErrors:             numIndexResetErrors,
 		numDependencyIndexResets:        numDependencyIndexResets,
 		numDependencyIndexResetFailures: numDependencyIndexResetFailures,
 		numDependencyIndexResetErrors:   numDependencyIndexResetErrors,
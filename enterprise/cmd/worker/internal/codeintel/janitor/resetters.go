@@ -23,21 +23,6 @@ func NewUploadResetter(s dbworkerstore.Store, interval time.Duration, metrics *m
 	})
 }
 
-// NewIndexResetter returns a background routine that periodically resets index
-// records that are marked as being processed but are no longer being processed
-// by a worker.
-func NewIndexResetter(s dbworkerstore.Store, interval time.Duration, metrics *metrics, observationContext *observation.Context) *dbworker.Resetter {
-	return dbworker.NewResetter(s, dbworker.ResetterOptions{
-		Name:     "precise_code_intel_index_worker_resetter",
-		Interval: interval,
-		Metrics: dbworker.ResetterMetrics{
-			RecordResets:        metrics.numIndexResets,
-			RecordResetFailures: metrics.numIndexResetFailures,
-			Errors:              metrics.numIndexResetErrors,
-		},
-	})
-}
-
 // NewDependencyIndexResetter returns a background routine that periodically resets
 // dependency index records that are marked as being processed but are no longer being
 // processed by a worker.
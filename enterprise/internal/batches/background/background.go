@@ -36,8 +36,6 @@ func Routines(ctx context.Context, batchesStore *store.Store, cf *httpcli.Factor
 
 		newBulkOperationWorker(ctx, batchesStore, sourcer, metrics),
 		newBulkOperationWorkerResetter(batchesStore, metrics),
-
-		newBatchSpecExecutionResetter(batchesStore, observationContext, metrics),
 	}
 	return routines
 }
@@ -0,0 +1,101 @@
+package background
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/oobmigration"
+)
+
+const batchSpecCompressionMigrationCountPerRun = 10
+
+// batchSpecCompressionMigrator compresses the batch specs of existing batch spec executions
+// with the configured algorithm. The down migration decompresses them again.
+type batchSpecCompressionMigrator struct {
+	store       *store.Store
+	compression func() string
+}
+
+var _ oobmigration.Migrator = &batchSpecCompressionMigrator{}
+
+// Progress returns the ratio of executions whose batch specs are compressed. If compression
+// is disabled, there is nothing to migrate.
+func (m *batchSpecCompressionMigrator) Progress(ctx context.Context) (float64, error) {
+	if m.compression() == "" {
+		return 1, nil
+	}
+
+	progress, _, err := basestore.ScanFirstFloat(m.store.Query(ctx, sqlf.Sprintf(batchSpecCompressionMigratorProgressQuery)))
+	if err != nil {
+		return 0, err
+	}
+
+	return progress, nil
+}
+
+const batchSpecCompressionMigratorProgressQuery = `
+-- source: enterprise/internal/batches/background/batch_spec_compression_migrator.go:Progress
+SELECT CASE c2.count WHEN 0 THEN 1 ELSE CAST((c2.count - c1.count) AS float) / CAST(c2.count AS float) END FROM
+	(SELECT COUNT(*) as count FROM batch_spec_executions WHERE batch_spec_compression = '') c1,
+	(SELECT COUNT(*) as count FROM batch_spec_executions) c2
+`
+
+func (m *batchSpecCompressionMigrator) Up(ctx context.Context) error {
+	compression := m.compression()
+	if compression == "" {
+		return nil
+	}
+
+	return m.migrate(ctx, sqlf.Sprintf("batch_spec_compression = ''"), compression)
+}
+
+func (m *batchSpecCompressionMigrator) Down(ctx context.Context) error {
+	return m.migrate(ctx, sqlf.Sprintf("batch_spec_compression != ''"), "")
+}
+
+// migrate rewrites the batch specs of a batch of executions matching the given condition with
+// the given compression algorithm.
+func (m *batchSpecCompressionMigrator) migrate(ctx context.Context, condition *sqlf.Query, compression string) error {
+	tx, err := m.store.Transact(ctx)
+	if err != nil {
+		return errors.Wrap(err, "starting transaction")
+	}
+
+	f := func() error {
+		ids, err := basestore.ScanInts(tx.Query(ctx, sqlf.Sprintf(
+			batchSpecCompressionMigratorSelectQuery,
+			condition,
+			batchSpecCompressionMigrationCountPerRun,
+		)))
+		if err != nil {
+			return errors.Wrap(err, "listing batch spec executions")
+		}
+
+		for _, id := range ids {
+			exec, err := tx.GetBatchSpecExecution(ctx, store.GetBatchSpecExecutionOpts{ID: int64(id)})
+			if err != nil {
+				return errors.Wrapf(err, "getting batch spec execution %d", id)
+			}
+
+			if err := tx.UpdateBatchSpecExecutionCompression(ctx, exec, compression); err != nil {
+				return errors.Wrapf(err, "updating batch spec execution %d", id)
+			}
+		}
+
+		return nil
+	}
+	return tx.Done(f())
+}
+
+const batchSpecCompressionMigratorSelectQuery = `
+-- source: enterprise/internal/batches/background/batch_spec_compression_migrator.go:migrate
+SELECT id FROM batch_spec_executions
+WHERE %s
+ORDER BY id
+LIMIT %s
+FOR UPDATE SKIP LOCKED
+`
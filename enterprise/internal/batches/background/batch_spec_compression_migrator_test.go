@@ -0,0 +1,102 @@
+package background
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
+	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	et "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
+)
+
+func TestBatchSpecCompressionMigrator(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.NewDB(t, "")
+	user := ct.CreateTestUser(t, db, true)
+
+	cstore := store.New(db, et.TestKey{})
+
+	compression := ""
+	migrator := &batchSpecCompressionMigrator{
+		store:       cstore,
+		compression: func() string { return compression },
+	}
+
+	t.Run("no executions", func(t *testing.T) {
+		assertProgress(t, ctx, 1.0, migrator)
+	})
+
+	// Create enough executions to validate that it takes multiple Up invocations
+	batchSpec := `name: testing`
+	execs := make([]*btypes.BatchSpecExecution, 0, 2*batchSpecCompressionMigrationCountPerRun)
+	for i := 0; i < cap(execs); i++ {
+		exec := &btypes.BatchSpecExecution{
+			BatchSpec:       batchSpec,
+			UserID:          user.ID,
+			NamespaceUserID: user.ID,
+		}
+		if err := cstore.CreateBatchSpecExecution(ctx, exec); err != nil {
+			t.Fatal(err)
+		}
+		execs = append(execs, exec)
+	}
+
+	t.Run("compression disabled", func(t *testing.T) {
+		if err := migrator.Up(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		assertProgress(t, ctx, 1.0, migrator)
+	})
+
+	compression = store.BatchSpecCompressionZstd
+
+	t.Run("completely unmigrated", func(t *testing.T) {
+		assertProgress(t, ctx, 0.0, migrator)
+	})
+
+	t.Run("first migrate up", func(t *testing.T) {
+		if err := migrator.Up(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		assertProgress(t, ctx, 0.5, migrator)
+	})
+
+	t.Run("second migrate up", func(t *testing.T) {
+		if err := migrator.Up(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		assertProgress(t, ctx, 1.0, migrator)
+	})
+
+	assertBatchSpecs := func(t *testing.T) {
+		for _, exec := range execs {
+			have, err := cstore.GetBatchSpecExecution(ctx, store.GetBatchSpecExecutionOpts{ID: exec.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have.BatchSpec != batchSpec {
+				t.Errorf("unexpected batch spec for execution %d. want=%q have=%q", exec.ID, batchSpec, have.BatchSpec)
+			}
+		}
+	}
+
+	t.Run("check batch specs", assertBatchSpecs)
+
+	t.Run("first migrate down", func(t *testing.T) {
+		if err := migrator.Down(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		assertProgress(t, ctx, 0.5, migrator)
+	})
+
+	t.Run("second migrate down", func(t *testing.T) {
+		if err := migrator.Down(ctx); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		assertProgress(t, ctx, 0.0, migrator)
+	})
+
+	t.Run("check batch specs after down", assertBatchSpecs)
+}
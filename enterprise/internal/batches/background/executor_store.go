@@ -15,6 +15,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
@@ -40,13 +41,27 @@ var executorWorkerStoreOptions = dbworkerstore.Options{
 	StalledMaxAge:     executorStalledJobMaximumAge,
 	MaxNumResets:      executorMaximumNumResets,
 	// Explicitly disable retries.
-	MaxNumRetries: 0,
+	MaxNumRetries:       0,
+	DependencyQueueName: store.BatchSpecExecutionDependencyQueueName,
 }
 
 // NewExecutorStore creates a dbworker store that wraps the batch_spec_executions
-// table.
-func NewExecutorStore(s basestore.ShareableStore, observationContext *observation.Context) dbworkerstore.Store {
-	return &executorStore{Store: dbworkerstore.NewWithMetrics(s.Handle(), executorWorkerStoreOptions, observationContext)}
+// table. The given key is used to decrypt batch specs, and may be nil if encryption
+// is not configured.
+func NewExecutorStore(s basestore.ShareableStore, key encryption.Key, observationContext *observation.Context) dbworkerstore.Store {
+	return &executorStore{Store: dbworkerstore.NewWithMetrics(s.Handle(), executorWorkerStoreOptions, observationContext), key: key}
+}
+
+// NewExecutorStoreWithResetOptions creates an executor store that uses the given stalled max
+// age and maximum number of resets in place of the defaults, and dequeues records according
+// to the given dequeue options.
+func NewExecutorStoreWithResetOptions(s basestore.ShareableStore, key encryption.Key, stalledMaxAge time.Duration, maxNumResets int, dequeueOptions dbworkerstore.DequeueOptions, observationContext *observation.Context) dbworkerstore.Store {
+	options := executorWorkerStoreOptions
+	options.StalledMaxAge = stalledMaxAge
+	options.MaxNumResets = maxNumResets
+	options.Dequeue = dequeueOptions
+
+	return &executorStore{Store: dbworkerstore.NewWithMetrics(s.Handle(), options, observationContext), key: key}
 }
 
 var _ dbworkerstore.Store = &executorStore{}
@@ -56,6 +71,7 @@ var _ dbworkerstore.Store = &executorStore{}
 // separate columns when marking a job as complete.
 type executorStore struct {
 	dbworkerstore.Store
+	key encryption.Key
 }
 
 // markCompleteQuery is taken from internal/workerutil/dbworker/store/store.go
@@ -69,7 +85,7 @@ RETURNING id
 `
 
 func (s *executorStore) MarkComplete(ctx context.Context, id int, options dbworkerstore.MarkFinalOptions) (_ bool, err error) {
-	batchesStore := store.New(s.Store.Handle().DB(), nil)
+	batchesStore := store.New(s.Store.Handle().DB(), s.key)
 
 	batchSpecRandID, err := loadAndExtractBatchSpecRandID(ctx, batchesStore, int64(id))
 	if err != nil {
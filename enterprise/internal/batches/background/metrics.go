@@ -15,7 +15,6 @@ type batchChangesMetrics struct {
 	bulkProcessorWorkerMetrics         workerutil.WorkerMetrics
 	reconcilerWorkerResetterMetrics    dbworker.ResetterMetrics
 	bulkProcessorWorkerResetterMetrics dbworker.ResetterMetrics
-	executionResetterMetrics           dbworker.ResetterMetrics
 }
 
 func newMetrics(observationContext *observation.Context) batchChangesMetrics {
@@ -24,7 +23,6 @@ func newMetrics(observationContext *observation.Context) batchChangesMetrics {
 		bulkProcessorWorkerMetrics:         workerutil.NewMetrics(observationContext, "batch_changes_bulk_processor", nil),
 		reconcilerWorkerResetterMetrics:    makeResetterMetrics(observationContext, "batch_changes_reconciler"),
 		bulkProcessorWorkerResetterMetrics: makeResetterMetrics(observationContext, "batch_changes_bulk_processor"),
-		executionResetterMetrics:           makeResetterMetrics(observationContext, "batch_spec_executor"),
 	}
 }
 
@@ -22,6 +22,11 @@ const (
 	// site credential migration. It is defined in
 	// `1528395821_oob_site_credential_encryption_up.sql`.
 	BatchChangesSiteCredentialMigrationID = 10
+
+	// BatchChangesBatchSpecCompressionMigrationID is the ID of the row holding the
+	// batch spec compression migration. It is defined in
+	// `1528395865_add_batch_spec_executions_batch_spec_compression.up.sql`.
+	BatchChangesBatchSpecCompressionMigrationID = 11
 )
 
 // RegisterMigrations registers all currently implemented out of band migrations
@@ -39,6 +44,10 @@ func RegisterMigrations(cstore *store.Store, outOfBandMigrationRunner *oobmigrat
 			store:        cstore,
 			allowDecrypt: allowDecrypt,
 		},
+		BatchChangesBatchSpecCompressionMigrationID: &batchSpecCompressionMigrator{
+			store:       cstore,
+			compression: store.BatchSpecCompression,
+		},
 	}
 
 	for id, migrator := range migrations {
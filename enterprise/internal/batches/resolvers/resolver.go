@@ -1455,6 +1455,11 @@ func (r *Resolver) CreateBatchSpecExecution(ctx context.Context, args *graphqlba
 		exec.NamespaceUserID = actor.UID
 	}
 
+	svc := service.New(r.store)
+	if err := svc.CheckExecutionQuota(ctx, exec.NamespaceUserID, exec.NamespaceOrgID); err != nil {
+		return nil, err
+	}
+
 	if err := r.store.CreateBatchSpecExecution(ctx, exec); err != nil {
 		return nil, err
 	}
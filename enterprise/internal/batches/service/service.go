@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/batches/store"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/auth"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
@@ -489,6 +490,37 @@ func (s *Service) CheckNamespaceAccess(ctx context.Context, namespaceUserID, nam
 // ErrNoNamespace is returned by checkNamespaceAccess if no valid namespace ID is given.
 var ErrNoNamespace = errors.New("no namespace given")
 
+// ErrExecutionQuotaExceeded is returned by CheckExecutionQuota if the namespace
+// already has the maximum number of queued batch spec executions.
+var ErrExecutionQuotaExceeded = errors.New("namespace has reached the maximum number of queued batch spec executions")
+
+// CheckExecutionQuota checks whether another batch spec execution can be
+// enqueued in the given namespace without exceeding the
+// batchChanges.executionQuotas.maxQueuedPerNamespace site config limit.
+func (s *Service) CheckExecutionQuota(ctx context.Context, namespaceUserID, namespaceOrgID int32) error {
+	quotas := conf.Get().BatchChangesExecutionQuotas
+	if quotas == nil || quotas.MaxQueuedPerNamespace <= 0 {
+		return nil
+	}
+
+	opts := store.CountBatchSpecExecutionsOpts{State: btypes.BatchSpecExecutionStateQueued}
+	if namespaceOrgID != 0 {
+		opts.NamespaceOrgID = namespaceOrgID
+	} else {
+		opts.NamespaceUserID = namespaceUserID
+	}
+
+	count, err := s.store.CountBatchSpecExecutions(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if count >= quotas.MaxQueuedPerNamespace {
+		return ErrExecutionQuotaExceeded
+	}
+
+	return nil
+}
+
 // FetchUsernameForBitbucketServerToken fetches the username associated with a
 // Bitbucket server token.
 //
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io"
+
+	"github.com/cockroachdb/errors"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+const (
+	// BatchSpecCompressionGzip denotes a batch spec compressed with gzip.
+	BatchSpecCompressionGzip = "gzip"
+
+	// BatchSpecCompressionZstd denotes a batch spec compressed with zstd.
+	BatchSpecCompressionZstd = "zstd"
+)
+
+// BatchSpecCompression returns the algorithm configured to compress the batch specs of new
+// batch spec executions, or an empty string if batch specs are stored uncompressed.
+func BatchSpecCompression() string {
+	switch compression := conf.Get().BatchChangesExecutionPayloadCompression; compression {
+	case BatchSpecCompressionGzip, BatchSpecCompressionZstd:
+		return compression
+	default:
+		return ""
+	}
+}
+
+// compressBatchSpec compresses the given batch spec with the given algorithm. As batch specs
+// are stored in a text column, the compressed spec is base64 encoded. The batch spec is
+// returned unmodified if no algorithm is given.
+func compressBatchSpec(compression, batchSpec string) (string, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+
+	switch compression {
+	case "":
+		return batchSpec, nil
+	case BatchSpecCompressionGzip:
+		w = gzip.NewWriter(&buf)
+	case BatchSpecCompressionZstd:
+		zw, err := zstd.NewWriter(&buf)
+		if err != nil {
+			return "", err
+		}
+		w = zw
+	default:
+		return "", errors.Errorf("unknown batch spec compression %q", compression)
+	}
+
+	if _, err := io.WriteString(w, batchSpec); err != nil {
+		return "", errors.Wrap(err, "compressing batch spec")
+	}
+	if err := w.Close(); err != nil {
+		return "", errors.Wrap(err, "compressing batch spec")
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decompressBatchSpec reverses compressBatchSpec.
+func decompressBatchSpec(compression, stored string) (string, error) {
+	if compression == "" {
+		return stored, nil
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(stored)
+	if err != nil {
+		return "", errors.Wrap(err, "decoding compressed batch spec")
+	}
+
+	var r io.Reader
+	switch compression {
+	case BatchSpecCompressionGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return "", errors.Wrap(err, "decompressing batch spec")
+		}
+		defer gr.Close()
+		r = gr
+	case BatchSpecCompressionZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return "", errors.Wrap(err, "decompressing batch spec")
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return "", errors.Errorf("unknown batch spec compression %q", compression)
+	}
+
+	batchSpec, err := io.ReadAll(r)
+	if err != nil {
+		return "", errors.Wrap(err, "decompressing batch spec")
+	}
+
+	return string(batchSpec), nil
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompressBatchSpec(t *testing.T) {
+	batchSpec := strings.Repeat("steps:\n  - run: echo hello world\n", 1000)
+
+	for _, compression := range []string{"", BatchSpecCompressionGzip, BatchSpecCompressionZstd} {
+		t.Run(compression, func(t *testing.T) {
+			stored, err := compressBatchSpec(compression, batchSpec)
+			if err != nil {
+				t.Fatalf("unexpected error compressing batch spec: %s", err)
+			}
+			if compression != "" && len(stored) >= len(batchSpec) {
+				t.Errorf("expected compressed batch spec to be smaller. original=%d compressed=%d", len(batchSpec), len(stored))
+			}
+
+			decompressed, err := decompressBatchSpec(compression, stored)
+			if err != nil {
+				t.Fatalf("unexpected error decompressing batch spec: %s", err)
+			}
+			if decompressed != batchSpec {
+				t.Errorf("unexpected batch spec after round trip")
+			}
+		})
+	}
+
+	if _, err := compressBatchSpec("lz4", batchSpec); err == nil {
+		t.Errorf("expected error for unknown compression")
+	}
+	if _, err := decompressBatchSpec(BatchSpecCompressionGzip, "not base64!"); err == nil {
+		t.Errorf("expected error for malformed batch spec")
+	}
+}
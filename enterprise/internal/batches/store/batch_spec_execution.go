@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/cockroachdb/errors"
 	"github.com/keegancsmith/sqlf"
@@ -11,6 +12,9 @@ import (
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/envelope"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
@@ -34,6 +38,9 @@ var BatchSpecExecutionColumns = []*sqlf.Query{
 	sqlf.Sprintf(`batch_spec_executions.user_id`),
 	sqlf.Sprintf(`batch_spec_executions.namespace_user_id`),
 	sqlf.Sprintf(`batch_spec_executions.namespace_org_id`),
+	sqlf.Sprintf(`batch_spec_executions.encryption_key_id`),
+	sqlf.Sprintf(`batch_spec_executions.batch_spec_compression`),
+	sqlf.Sprintf(`batch_spec_executions.trace_context`),
 }
 
 var batchSpecExecutionInsertColumns = []*sqlf.Query{
@@ -44,9 +51,16 @@ var batchSpecExecutionInsertColumns = []*sqlf.Query{
 	sqlf.Sprintf("namespace_org_id"),
 	sqlf.Sprintf("created_at"),
 	sqlf.Sprintf("updated_at"),
+	sqlf.Sprintf("encryption_key_id"),
+	sqlf.Sprintf("batch_spec_compression"),
+	sqlf.Sprintf("trace_context"),
 }
 
-// CreateBatchSpecExecution creates the given BatchSpecExecution.
+// CreateBatchSpecExecution creates the given BatchSpecExecution. The batch spec is compressed
+// with the configured algorithm (see BatchSpecCompression) and, if the store has an encryption
+// key, then envelope encrypted before it is written. The given BatchSpecExecution retains the
+// plaintext batch spec. Unless it carries a trace context already, the span context of the
+// given context is stored with the BatchSpecExecution.
 func (s *Store) CreateBatchSpecExecution(ctx context.Context, b *btypes.BatchSpecExecution) error {
 	if b.CreatedAt.IsZero() {
 		b.CreatedAt = s.now()
@@ -56,20 +70,108 @@ func (s *Store) CreateBatchSpecExecution(ctx context.Context, b *btypes.BatchSpe
 		b.UpdatedAt = b.CreatedAt
 	}
 
-	q, err := createBatchSpecExecutionQuery(b)
+	if b.TraceContext == nil {
+		b.TraceContext = ot.InjectSpanContext(ctx)
+	}
+
+	compression := BatchSpecCompression()
+	batchSpec, encryptionKeyID, err := encodeBatchSpec(ctx, s.key, compression, b.BatchSpec)
+	if err != nil {
+		return err
+	}
+
+	q, err := createBatchSpecExecutionQuery(b, batchSpec, encryptionKeyID, compression)
+	if err != nil {
+		return err
+	}
+	if err := s.query(ctx, q, func(sc scanner) error { return scanBatchSpecExecution(b, sc) }); err != nil {
+		return err
+	}
+
+	return DecodeBatchSpecExecution(ctx, s.key, b)
+}
+
+// encodeBatchSpec returns the batch spec as stored in the database: compressed with the given
+// algorithm, then envelope encrypted with the given key, if any.
+func encodeBatchSpec(ctx context.Context, key encryption.Key, compression, batchSpec string) (stored, encryptionKeyID string, _ error) {
+	batchSpec, err := compressBatchSpec(compression, batchSpec)
+	if err != nil {
+		return "", "", err
+	}
+
+	if key == nil {
+		return batchSpec, "", nil
+	}
+
+	version, err := key.Version(ctx)
+	if err != nil {
+		return "", "", errors.Wrap(err, "getting key version")
+	}
+
+	encrypted, err := envelope.Encrypt(ctx, key, []byte(batchSpec))
+	if err != nil {
+		return "", "", errors.Wrap(err, "encrypting batch spec")
+	}
+
+	return string(encrypted), version.JSON(), nil
+}
+
+// DecodeBatchSpecExecution replaces the batch spec of the given execution as stored in the
+// database with its plaintext, decrypting and then decompressing it as necessary, and clears
+// its EncryptionKeyID and Compression. Executions that are neither encrypted nor compressed
+// are left as-is.
+func DecodeBatchSpecExecution(ctx context.Context, key encryption.Key, b *btypes.BatchSpecExecution) error {
+	if b.EncryptionKeyID != "" {
+		if key == nil {
+			return errors.New("batch spec execution is encrypted, but no key is available to decrypt it")
+		}
+
+		batchSpec, err := envelope.Decrypt(ctx, key, []byte(b.BatchSpec))
+		if err != nil {
+			return errors.Wrap(err, "decrypting batch spec")
+		}
+
+		b.BatchSpec = string(batchSpec)
+		b.EncryptionKeyID = ""
+	}
+
+	batchSpec, err := decompressBatchSpec(b.Compression, b.BatchSpec)
+	if err != nil {
+		return err
+	}
+
+	b.BatchSpec = batchSpec
+	b.Compression = ""
+	return nil
+}
+
+// UpdateBatchSpecExecutionCompression rewrites the batch spec of the given execution, which
+// must hold the plaintext batch spec, compressed with the given algorithm. An empty algorithm
+// stores the batch spec uncompressed. The batch spec is re-encrypted if the store has an
+// encryption key.
+func (s *Store) UpdateBatchSpecExecutionCompression(ctx context.Context, b *btypes.BatchSpecExecution, compression string) error {
+	batchSpec, encryptionKeyID, err := encodeBatchSpec(ctx, s.key, compression, b.BatchSpec)
 	if err != nil {
 		return err
 	}
-	return s.query(ctx, q, func(sc scanner) error { return scanBatchSpecExecution(b, sc) })
+
+	return s.Exec(ctx, sqlf.Sprintf(updateBatchSpecExecutionCompressionQueryFmtstr, batchSpec, encryptionKeyID, compression, b.ID))
 }
 
+var updateBatchSpecExecutionCompressionQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_executions.go:UpdateBatchSpecExecutionCompression
+UPDATE batch_spec_executions
+SET batch_spec = %s, encryption_key_id = %s, batch_spec_compression = %s
+WHERE id = %s
+`
+
 var createBatchSpecExecutionQueryFmtstr = `
 -- source: enterprise/internal/batches/store/batch_spec_executions.go:CreateBatchSpecExecution
 INSERT INTO batch_spec_executions (%s)
-VALUES (%s, %s, %s, %s, %s, %s, %s)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING %s`
 
-func createBatchSpecExecutionQuery(c *btypes.BatchSpecExecution) (*sqlf.Query, error) {
+func createBatchSpecExecutionQuery(c *btypes.BatchSpecExecution, batchSpec, encryptionKeyID, compression string) (*sqlf.Query, error) {
 	if c.RandID == "" {
 		var err error
 		if c.RandID, err = RandomID(); err != nil {
@@ -77,20 +179,90 @@ func createBatchSpecExecutionQuery(c *btypes.BatchSpecExecution) (*sqlf.Query, e
 		}
 	}
 
+	var traceContext string
+	if c.TraceContext != nil {
+		encoded, err := json.Marshal(c.TraceContext)
+		if err != nil {
+			return nil, err
+		}
+		traceContext = string(encoded)
+	}
+
 	return sqlf.Sprintf(
 		createBatchSpecExecutionQueryFmtstr,
 		sqlf.Join(batchSpecExecutionInsertColumns, ", "),
 		c.RandID,
-		c.BatchSpec,
+		batchSpec,
 		c.UserID,
 		nullInt32Column(c.NamespaceUserID),
 		nullInt32Column(c.NamespaceOrgID),
 		c.CreatedAt,
 		c.UpdatedAt,
+		encryptionKeyID,
+		compression,
+		nullStringColumn(traceContext),
 		sqlf.Join(BatchSpecExecutionColumns, ", "),
 	), nil
 }
 
+// BatchSpecExecutionDependencyQueueName is the queue name under which dependencies between
+// BatchSpecExecutions are recorded.
+const BatchSpecExecutionDependencyQueueName = "batch_spec_executions"
+
+// AddBatchSpecExecutionDependencies records that the BatchSpecExecution with the given ID must
+// not be executed until all of the given BatchSpecExecutions have completed. If one of them
+// fails, the dependent execution fails as well.
+func (s *Store) AddBatchSpecExecutionDependencies(ctx context.Context, id int64, dependsOn []int64) error {
+	dependsOnIDs := make([]int, 0, len(dependsOn))
+	for _, dependsOnID := range dependsOn {
+		dependsOnIDs = append(dependsOnIDs, int(dependsOnID))
+	}
+
+	return dbworkerstore.AddDependencies(ctx, s.Store, BatchSpecExecutionDependencyQueueName, int(id), dependsOnIDs)
+}
+
+// CountBatchSpecExecutionsOpts captures the query options needed for counting
+// BatchSpecExecutions.
+type CountBatchSpecExecutionsOpts struct {
+	State           btypes.BatchSpecExecutionState
+	NamespaceUserID int32
+	NamespaceOrgID  int32
+}
+
+// CountBatchSpecExecutions returns the number of BatchSpecExecutions matching the given options.
+func (s *Store) CountBatchSpecExecutions(ctx context.Context, opts CountBatchSpecExecutionsOpts) (int, error) {
+	return s.queryCount(ctx, countBatchSpecExecutionsQuery(&opts))
+}
+
+var countBatchSpecExecutionsQueryFmtstr = `
+-- source: enterprise/internal/batches/store/batch_spec_executions.go:CountBatchSpecExecutions
+SELECT COUNT(batch_spec_executions.id)
+FROM batch_spec_executions
+WHERE %s
+`
+
+func countBatchSpecExecutionsQuery(opts *CountBatchSpecExecutionsOpts) *sqlf.Query {
+	var preds []*sqlf.Query
+
+	if opts.State != "" {
+		preds = append(preds, sqlf.Sprintf("batch_spec_executions.state = %s", opts.State))
+	}
+
+	if opts.NamespaceUserID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_executions.namespace_user_id = %s", opts.NamespaceUserID))
+	}
+
+	if opts.NamespaceOrgID != 0 {
+		preds = append(preds, sqlf.Sprintf("batch_spec_executions.namespace_org_id = %s", opts.NamespaceOrgID))
+	}
+
+	if len(preds) == 0 {
+		preds = append(preds, sqlf.Sprintf("TRUE"))
+	}
+
+	return sqlf.Sprintf(countBatchSpecExecutionsQueryFmtstr, sqlf.Join(preds, "\n AND "))
+}
+
 // GetBatchSpecExecutionOpts captures the query options needed for getting a BatchSpecExecution.
 type GetBatchSpecExecutionOpts struct {
 	ID     int64
@@ -116,6 +288,10 @@ func (s *Store) GetBatchSpecExecution(ctx context.Context, opts GetBatchSpecExec
 		return nil, ErrNoResults
 	}
 
+	if err := DecodeBatchSpecExecution(ctx, s.key, &b); err != nil {
+		return nil, err
+	}
+
 	return &b, nil
 }
 
@@ -150,6 +326,7 @@ func getBatchSpecExecutionQuery(opts *GetBatchSpecExecutionOpts) (*sqlf.Query, e
 
 func scanBatchSpecExecution(b *btypes.BatchSpecExecution, sc scanner) error {
 	var executionLogs []dbworkerstore.ExecutionLogEntry
+	var traceContext dbutil.NullJSONRawMessage
 
 	if err := sc.Scan(
 		&b.ID,
@@ -170,10 +347,19 @@ func scanBatchSpecExecution(b *btypes.BatchSpecExecution, sc scanner) error {
 		&b.UserID,
 		&dbutil.NullInt32{N: &b.NamespaceUserID},
 		&dbutil.NullInt32{N: &b.NamespaceOrgID},
+		&b.EncryptionKeyID,
+		&b.Compression,
+		&traceContext,
 	); err != nil {
 		return err
 	}
 
+	if traceContext.Raw != nil {
+		if err := json.Unmarshal(traceContext.Raw, &b.TraceContext); err != nil {
+			return err
+		}
+	}
+
 	for _, entry := range executionLogs {
 		b.ExecutionLogs = append(b.ExecutionLogs, workerutil.ExecutionLogEntry(entry))
 	}
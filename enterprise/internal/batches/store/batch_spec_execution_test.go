@@ -6,13 +6,16 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
 
 	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/testing"
 	btypes "github.com/sourcegraph/sourcegraph/enterprise/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 )
 
 func testStoreChangesetSpecExecutions(t *testing.T, ctx context.Context, s *Store, clock ct.Clock) {
 	testBatchSpec := `theSpec: yeah`
+	testTraceContext := map[string]string{"uber-trace-id": "4bf92f3577b34da6:a3ce929d0e0e4736:0:1"}
 
 	execs := make([]*btypes.BatchSpecExecution, 0, 2)
 	for i := 0; i < cap(execs); i++ {
@@ -22,6 +25,9 @@ func testStoreChangesetSpecExecutions(t *testing.T, ctx context.Context, s *Stor
 			UserID:          int32(i + 123),
 			NamespaceUserID: int32(i + 345),
 		}
+		if i == 1 {
+			c.TraceContext = testTraceContext
+		}
 
 		execs = append(execs, c)
 	}
@@ -43,6 +49,9 @@ func testStoreChangesetSpecExecutions(t *testing.T, ctx context.Context, s *Stor
 				UserID:          int32(i + 123),
 				NamespaceUserID: int32(i + 345),
 			}
+			if i == 1 {
+				want.TraceContext = testTraceContext
+			}
 
 			if have.ID == 0 {
 				t.Fatal("ID should not be zero")
@@ -58,6 +67,43 @@ func testStoreChangesetSpecExecutions(t *testing.T, ctx context.Context, s *Stor
 		}
 	})
 
+	t.Run("Encrypted", func(t *testing.T) {
+		for _, exec := range execs {
+			stored, _, err := basestore.ScanFirstString(s.Query(ctx, sqlf.Sprintf("SELECT batch_spec FROM batch_spec_executions WHERE id = %s", exec.ID)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if encrypted := stored != testBatchSpec; encrypted != (s.key != nil) {
+				t.Fatalf("unexpected stored batch spec %q", stored)
+			}
+		}
+	})
+
+	t.Run("UpdateCompression", func(t *testing.T) {
+		for _, compression := range []string{BatchSpecCompressionZstd, BatchSpecCompressionGzip, ""} {
+			if err := s.UpdateBatchSpecExecutionCompression(ctx, execs[0], compression); err != nil {
+				t.Fatal(err)
+			}
+
+			stored, _, err := basestore.ScanFirstString(s.Query(ctx, sqlf.Sprintf("SELECT batch_spec_compression FROM batch_spec_executions WHERE id = %s", execs[0].ID)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored != compression {
+				t.Fatalf("unexpected compression. want=%q have=%q", compression, stored)
+			}
+
+			have, err := s.GetBatchSpecExecution(ctx, GetBatchSpecExecutionOpts{ID: execs[0].ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have.BatchSpec != testBatchSpec {
+				t.Fatalf("unexpected batch spec. want=%q have=%q", testBatchSpec, have.BatchSpec)
+			}
+		}
+	})
+
 	t.Run("Get", func(t *testing.T) {
 		t.Run("GetByID", func(t *testing.T) {
 			for i, exec := range execs {
@@ -100,4 +146,50 @@ func testStoreChangesetSpecExecutions(t *testing.T, ctx context.Context, s *Stor
 			}
 		})
 	})
+
+	t.Run("Count", func(t *testing.T) {
+		count, err := s.CountBatchSpecExecutions(ctx, CountBatchSpecExecutionsOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if have, want := count, len(execs); have != want {
+			t.Fatalf("have count: %d, want: %d", have, want)
+		}
+
+		count, err = s.CountBatchSpecExecutions(ctx, CountBatchSpecExecutionsOpts{
+			State:           btypes.BatchSpecExecutionStateQueued,
+			NamespaceUserID: execs[0].NamespaceUserID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if have, want := count, 1; have != want {
+			t.Fatalf("have count: %d, want: %d", have, want)
+		}
+	})
+
+	t.Run("AddDependencies", func(t *testing.T) {
+		if err := s.AddBatchSpecExecutionDependencies(ctx, execs[1].ID, []int64{execs[0].ID}); err != nil {
+			t.Fatal(err)
+		}
+
+		dependsOn, err := basestore.ScanInts(s.Query(ctx, sqlf.Sprintf(
+			"SELECT depends_on_job_id FROM workerutil_job_dependencies WHERE queue_name = %s AND job_id = %s",
+			BatchSpecExecutionDependencyQueueName,
+			execs[1].ID,
+		)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff([]int{int(execs[0].ID)}, dependsOn); diff != "" {
+			t.Fatal(diff)
+		}
+
+		if err := s.AddBatchSpecExecutionDependencies(ctx, execs[0].ID, []int64{execs[1].ID}); err == nil {
+			t.Fatal("expected an error adding a cyclic dependency")
+		}
+	})
 }
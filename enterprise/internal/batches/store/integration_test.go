@@ -34,7 +34,6 @@ func TestIntegration(t *testing.T) {
 		t.Run("UserDeleteCascades", storeTest(db, nil, testUserDeleteCascades))
 		t.Run("ChangesetJobs", storeTest(db, nil, testStoreChangesetJobs))
 		t.Run("BulkOperations", storeTest(db, nil, testStoreBulkOperations))
-		t.Run("BatchSpecExecutions", storeTest(db, nil, testStoreChangesetSpecExecutions))
 
 		for name, key := range map[string]encryption.Key{
 			"no key":   nil,
@@ -42,6 +41,7 @@ func TestIntegration(t *testing.T) {
 		} {
 			t.Run(name, func(t *testing.T) {
 				t.Run("SiteCredentials", storeTest(db, key, testStoreSiteCredentials))
+				t.Run("BatchSpecExecutions", storeTest(db, key, testStoreChangesetSpecExecutions))
 			})
 		}
 	})
@@ -80,11 +80,12 @@ type BatchChangeImportChangeset struct {
 }
 
 type ChangesetTemplate struct {
-	Title     string                   `json:"title,omitempty" yaml:"title,omitempty"`
-	Body      string                   `json:"body,omitempty" yaml:"body,omitempty"`
-	Branch    string                   `json:"branch,omitempty" yaml:"branch,omitempty"`
-	Commit    CommitTemplate           `json:"commit,omitempty" yaml:"commit,omitempty"`
-	Published overridable.BoolOrString `json:"published,omitempty" yaml:"published,omitempty"`
+	Title            string                   `json:"title,omitempty" yaml:"title,omitempty"`
+	Body             string                   `json:"body,omitempty" yaml:"body,omitempty"`
+	Branch           string                   `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Commit           CommitTemplate           `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Published        overridable.BoolOrString `json:"published,omitempty" yaml:"published,omitempty"`
+	MaxExecutionTime string                   `json:"maxExecutionTime,omitempty" yaml:"maxExecutionTime,omitempty"`
 }
 
 type CommitTemplate struct {
@@ -35,6 +35,18 @@ type BatchSpecExecution struct {
 	UserID          int32
 	NamespaceUserID int32
 	NamespaceOrgID  int32
+
+	// EncryptionKeyID is non-empty while BatchSpec holds the envelope encrypted batch spec
+	// as stored in the database. See store.DecodeBatchSpecExecution.
+	EncryptionKeyID string
+
+	// Compression is the algorithm with which the batch spec was compressed before it was
+	// stored, and is non-empty while BatchSpec holds the stored batch spec.
+	Compression string
+
+	// TraceContext is the span context of the request that created the execution, encoded as
+	// an OpenTracing text map, or nil if the request was not traced.
+	TraceContext map[string]string
 }
 
 func (i BatchSpecExecution) RecordID() int {
@@ -12,12 +12,14 @@ type Config struct {
 	MaximumRepositoriesInspectedPerSecond    rate.Limit
 	MaximumRepositoriesUpdatedPerSecond      rate.Limit
 	MaximumIndexJobsPerInferredConfiguration int
+	MaximumQueueDepth                        int
 }
 
 func (c *Config) Load() {
 	c.MaximumRepositoriesInspectedPerSecond = toRate(c.GetInt("PRECISE_CODE_INTEL_AUTO_INDEX_MAXIMUM_REPOSITORIES_INSPECTED_PER_SECOND", "0", "The maximum number of repositories inspected for auto-indexing per second. Set to zero to disable limit."))
 	c.MaximumRepositoriesUpdatedPerSecond = toRate(c.GetInt("PRECISE_CODE_INTEL_AUTO_INDEX_MAXIMUM_REPOSITORIES_UPDATED_PER_SECOND", "0", "The maximum number of repositories cloned or fetched for auto-indexing per second. Set to zero to disable limit."))
 	c.MaximumIndexJobsPerInferredConfiguration = c.GetInt("PRECISE_CODE_INTEL_AUTO_INDEX_MAXIMUM_INDEX_JOBS_PER_INFERRED_CONFIGURATION", "25", "Repositories with a number of inferred auto-index jobs exceeding this threshold will be auto-indexed.")
+	c.MaximumQueueDepth = c.GetInt("PRECISE_CODE_INTEL_AUTO_INDEX_MAXIMUM_QUEUE_DEPTH", "0", "The maximum number of queued index jobs. Index jobs are not enqueued while the queue is at this depth. Set to zero to disable limit.")
 }
 
 func toRate(value int) rate.Limit {
@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/config"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/autoindex/inference"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
@@ -168,6 +169,10 @@ func (s *IndexEnqueuer) queueIndexForRepositoryAndCommit(ctx context.Context, re
 		}
 	}
 
+	if err := s.checkQueueDepth(ctx); err != nil {
+		return err
+	}
+
 	indexes, err := s.getIndexRecords(ctx, repositoryID, commit)
 	if err != nil {
 		return err
@@ -180,6 +185,25 @@ func (s *IndexEnqueuer) queueIndexForRepositoryAndCommit(ctx context.Context, re
 	return s.queueIndexes(ctx, repositoryID, commit, indexes)
 }
 
+// checkQueueDepth returns ErrQueueFull if the index queue has reached its configured maximum depth.
+// This check happens before inferring index jobs so that producers backing off do not perform any
+// unnecessary gitserver requests.
+func (s *IndexEnqueuer) checkQueueDepth(ctx context.Context) error {
+	if s.config.MaximumQueueDepth <= 0 {
+		return nil
+	}
+
+	size, err := s.dbStore.IndexQueueSize(ctx)
+	if err != nil {
+		return errors.Wrap(err, "dbstore.IndexQueueSize")
+	}
+	if size >= s.config.MaximumQueueDepth {
+		return dbworkerstore.ErrQueueFull
+	}
+
+	return nil
+}
+
 // queueIndexes inserts a set of index records into the database. It is assumed that the given repository id an
 // commit are the same for each given index record. In the same transaction as the insert, the repository's row
 // is updated in the lsif_indexable_repositories table as a crude form of rate limiting.
@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 	"github.com/sourcegraph/sourcegraph/lib/codeintel/semantic"
 )
 
@@ -353,6 +354,31 @@ func TestQueueIndexesForRepositoryInferredTooLarge(t *testing.T) {
 	}
 }
 
+func TestQueueIndexesForRepositoryQueueFull(t *testing.T) {
+	mockDBStore := NewMockDBStore()
+	mockDBStore.TransactFunc.SetDefaultReturn(mockDBStore, nil)
+	mockDBStore.IsQueuedFunc.SetDefaultReturn(false, nil)
+	mockDBStore.IndexQueueSizeFunc.SetDefaultReturn(10, nil)
+
+	mockGitserverClient := NewMockGitserverClient()
+	mockGitserverClient.HeadFunc.SetDefaultReturn("c42", true, nil)
+
+	config := testConfig
+	config.MaximumQueueDepth = 10
+	scheduler := NewIndexEnqueuer(mockDBStore, mockGitserverClient, nil, &config, &observation.TestContext)
+
+	if err := scheduler.QueueIndexesForRepository(context.Background(), 42); err != dbworkerstore.ErrQueueFull {
+		t.Fatalf("unexpected error. want=%q have=%q", dbworkerstore.ErrQueueFull, err)
+	}
+
+	if len(mockGitserverClient.ListFilesFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to ListFiles. want=%d have=%d", 0, len(mockGitserverClient.ListFilesFunc.History()))
+	}
+	if len(mockDBStore.InsertIndexFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to InsertIndex. want=%d have=%d", 0, len(mockDBStore.InsertIndexFunc.History()))
+	}
+}
+
 func TestQueueIndexesForPackage(t *testing.T) {
 	mockDBStore := NewMockDBStore()
 	mockDBStore.TransactFunc.SetDefaultReturn(mockDBStore, nil)
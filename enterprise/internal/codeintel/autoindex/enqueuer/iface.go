@@ -19,6 +19,7 @@ type DBStore interface {
 
 	DirtyRepositories(ctx context.Context) (map[int]int, error)
 	IsQueued(ctx context.Context, repositoryID int, commit string) (bool, error)
+	IndexQueueSize(ctx context.Context) (int, error)
 	InsertIndex(ctx context.Context, index dbstore.Index) (int, error)
 	GetRepositoriesWithIndexConfiguration(ctx context.Context) ([]int, error)
 	GetIndexConfigurationByRepositoryID(ctx context.Context, repositoryID int) (dbstore.IndexConfiguration, bool, error)
@@ -13,9 +13,9 @@ import (
 // getIndexRecords determines the set of index records that should be enqueued for the given commit.
 // For each repository, we look for index configuration in the following order:
 //
-//  - in the database
-//  - committed to `sourcegraph.yaml` in the repository
-//  - inferred from the repository structure
+//   - in the database
+//   - committed to `sourcegraph.yaml` in the repository
+//   - inferred from the repository structure
 func (s *IndexEnqueuer) getIndexRecords(ctx context.Context, repositoryID int, commit string) ([]store.Index, error) {
 	fns := []func(ctx context.Context, repositoryID int, commit string) ([]store.Index, bool, error){
 		s.getIndexRecordsFromConfigurationInDatabase,
@@ -119,15 +119,17 @@ func convertIndexConfiguration(repositoryID int, commit string, indexConfigurati
 		}
 
 		indexes = append(indexes, store.Index{
-			Commit:       commit,
-			RepositoryID: repositoryID,
-			State:        "queued",
-			DockerSteps:  dockerSteps,
-			LocalSteps:   indexJob.LocalSteps,
-			Root:         indexJob.Root,
-			Indexer:      indexJob.Indexer,
-			IndexerArgs:  indexJob.IndexerArgs,
-			Outfile:      indexJob.Outfile,
+			Commit:                 commit,
+			RepositoryID:           repositoryID,
+			State:                  "queued",
+			DockerSteps:            dockerSteps,
+			LocalSteps:             indexJob.LocalSteps,
+			Root:                   indexJob.Root,
+			Indexer:                indexJob.Indexer,
+			IndexerArgs:            indexJob.IndexerArgs,
+			Outfile:                indexJob.Outfile,
+			ExecutorLabels:         indexJob.ExecutorLabels,
+			DependencyRepositories: indexJob.DependencyRepositories,
 		})
 	}
 
@@ -148,15 +150,17 @@ func convertInferredConfiguration(repositoryID int, commit string, indexJobs []c
 		}
 
 		indexes = append(indexes, store.Index{
-			RepositoryID: repositoryID,
-			Commit:       commit,
-			State:        "queued",
-			DockerSteps:  dockerSteps,
-			LocalSteps:   indexJob.LocalSteps,
-			Root:         indexJob.Root,
-			Indexer:      indexJob.Indexer,
-			IndexerArgs:  indexJob.IndexerArgs,
-			Outfile:      indexJob.Outfile,
+			RepositoryID:           repositoryID,
+			Commit:                 commit,
+			State:                  "queued",
+			DockerSteps:            dockerSteps,
+			LocalSteps:             indexJob.LocalSteps,
+			Root:                   indexJob.Root,
+			Indexer:                indexJob.Indexer,
+			IndexerArgs:            indexJob.IndexerArgs,
+			Outfile:                indexJob.Outfile,
+			ExecutorLabels:         indexJob.ExecutorLabels,
+			DependencyRepositories: indexJob.DependencyRepositories,
 		})
 	}
 
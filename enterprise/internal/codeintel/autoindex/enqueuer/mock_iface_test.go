@@ -35,6 +35,9 @@ type MockDBStore struct {
 	// HandleFunc is an instance of a mock function object controlling the
 	// behavior of the method Handle.
 	HandleFunc *DBStoreHandleFunc
+	// IndexQueueSizeFunc is an instance of a mock function object
+	// controlling the behavior of the method IndexQueueSize.
+	IndexQueueSizeFunc *DBStoreIndexQueueSizeFunc
 	// InsertIndexFunc is an instance of a mock function object controlling
 	// the behavior of the method InsertIndex.
 	InsertIndexFunc *DBStoreInsertIndexFunc
@@ -75,6 +78,11 @@ func NewMockDBStore() *MockDBStore {
 				return nil
 			},
 		},
+		IndexQueueSizeFunc: &DBStoreIndexQueueSizeFunc{
+			defaultHook: func(context.Context) (int, error) {
+				return 0, nil
+			},
+		},
 		InsertIndexFunc: &DBStoreInsertIndexFunc{
 			defaultHook: func(context.Context, dbstore.Index) (int, error) {
 				return 0, nil
@@ -112,6 +120,9 @@ func NewMockDBStoreFrom(i DBStore) *MockDBStore {
 		HandleFunc: &DBStoreHandleFunc{
 			defaultHook: i.Handle,
 		},
+		IndexQueueSizeFunc: &DBStoreIndexQueueSizeFunc{
+			defaultHook: i.IndexQueueSize,
+		},
 		InsertIndexFunc: &DBStoreInsertIndexFunc{
 			defaultHook: i.InsertIndex,
 		},
@@ -659,6 +670,112 @@ func (c DBStoreHandleFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// DBStoreIndexQueueSizeFunc describes the behavior when the IndexQueueSize
+// method of the parent MockDBStore instance is invoked.
+type DBStoreIndexQueueSizeFunc struct {
+	defaultHook func(context.Context) (int, error)
+	hooks       []func(context.Context) (int, error)
+	history     []DBStoreIndexQueueSizeFuncCall
+	mutex       sync.Mutex
+}
+
+// IndexQueueSize delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDBStore) IndexQueueSize(v0 context.Context) (int, error) {
+	r0, r1 := m.IndexQueueSizeFunc.nextHook()(v0)
+	m.IndexQueueSizeFunc.appendCall(DBStoreIndexQueueSizeFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the IndexQueueSize
+// method of the parent MockDBStore instance is invoked and the hook queue
+// is empty.
+func (f *DBStoreIndexQueueSizeFunc) SetDefaultHook(hook func(context.Context) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// IndexQueueSize method of the parent MockDBStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *DBStoreIndexQueueSizeFunc) PushHook(hook func(context.Context) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBStoreIndexQueueSizeFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBStoreIndexQueueSizeFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBStoreIndexQueueSizeFunc) nextHook() func(context.Context) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBStoreIndexQueueSizeFunc) appendCall(r0 DBStoreIndexQueueSizeFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBStoreIndexQueueSizeFuncCall objects
+// describing the invocations of this function.
+func (f *DBStoreIndexQueueSizeFunc) History() []DBStoreIndexQueueSizeFuncCall {
+	f.mutex.Lock()
+	history := make([]DBStoreIndexQueueSizeFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBStoreIndexQueueSizeFuncCall is an object that describes an invocation
+// of method IndexQueueSize on an instance of MockDBStore.
+type DBStoreIndexQueueSizeFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBStoreIndexQueueSizeFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBStoreIndexQueueSizeFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DBStoreInsertIndexFunc describes the behavior when the InsertIndex method
 // of the parent MockDBStore instance is invoked.
 type DBStoreInsertIndexFunc struct {
@@ -125,6 +125,12 @@ func insertIndexes(t testing.TB, db *sql.DB, indexes ...Index) {
 		if index.LocalSteps == nil {
 			index.LocalSteps = []string{}
 		}
+		if index.ExecutorLabels == nil {
+			index.ExecutorLabels = []string{}
+		}
+		if index.DependencyRepositories == nil {
+			index.DependencyRepositories = []string{}
+		}
 
 		// Ensure we have a repo for the inner join in select queries
 		insertRepo(t, db, index.RepositoryID, index.RepositoryName)
@@ -148,8 +154,10 @@ func insertIndexes(t testing.TB, db *sql.DB, indexes ...Index) {
 				indexer_args,
 				outfile,
 				execution_logs,
-				local_steps
-			) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+				local_steps,
+				executor_labels,
+				dependency_repositories
+			) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 		`,
 			index.ID,
 			index.Commit,
@@ -169,6 +177,8 @@ func insertIndexes(t testing.TB, db *sql.DB, indexes ...Index) {
 			index.Outfile,
 			pq.Array(dbworkerstore.ExecutionLogEntries(index.ExecutionLogs)),
 			pq.Array(index.LocalSteps),
+			pq.Array(index.ExecutorLabels),
+			pq.Array(index.DependencyRepositories),
 		)
 
 		if _, err := db.ExecContext(context.Background(), query.Query(sqlf.PostgresBindVar), query.Args()...); err != nil {
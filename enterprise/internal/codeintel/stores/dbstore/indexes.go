@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
@@ -20,27 +21,30 @@ import (
 // Index is a subset of the lsif_indexes table and stores both processed and unprocessed
 // records.
 type Index struct {
-	ID                 int                            `json:"id"`
-	Commit             string                         `json:"commit"`
-	QueuedAt           time.Time                      `json:"queuedAt"`
-	State              string                         `json:"state"`
-	FailureMessage     *string                        `json:"failureMessage"`
-	StartedAt          *time.Time                     `json:"startedAt"`
-	FinishedAt         *time.Time                     `json:"finishedAt"`
-	ProcessAfter       *time.Time                     `json:"processAfter"`
-	NumResets          int                            `json:"numResets"`
-	NumFailures        int                            `json:"numFailures"`
-	RepositoryID       int                            `json:"repositoryId"`
-	LocalSteps         []string                       `json:"local_steps"`
-	RepositoryName     string                         `json:"repositoryName"`
-	DockerSteps        []DockerStep                   `json:"docker_steps"`
-	Root               string                         `json:"root"`
-	Indexer            string                         `json:"indexer"`
-	IndexerArgs        []string                       `json:"indexer_args"` // TODO - convert this to `IndexCommand string`
-	Outfile            string                         `json:"outfile"`
-	ExecutionLogs      []workerutil.ExecutionLogEntry `json:"execution_logs"`
-	Rank               *int                           `json:"placeInQueue"`
-	AssociatedUploadID *int                           `json:"associatedUpload"`
+	ID                     int                            `json:"id"`
+	Commit                 string                         `json:"commit"`
+	QueuedAt               time.Time                      `json:"queuedAt"`
+	State                  string                         `json:"state"`
+	FailureMessage         *string                        `json:"failureMessage"`
+	StartedAt              *time.Time                     `json:"startedAt"`
+	FinishedAt             *time.Time                     `json:"finishedAt"`
+	ProcessAfter           *time.Time                     `json:"processAfter"`
+	NumResets              int                            `json:"numResets"`
+	NumFailures            int                            `json:"numFailures"`
+	RepositoryID           int                            `json:"repositoryId"`
+	LocalSteps             []string                       `json:"local_steps"`
+	RepositoryName         string                         `json:"repositoryName"`
+	DockerSteps            []DockerStep                   `json:"docker_steps"`
+	Root                   string                         `json:"root"`
+	Indexer                string                         `json:"indexer"`
+	IndexerArgs            []string                       `json:"indexer_args"` // TODO - convert this to `IndexCommand string`
+	Outfile                string                         `json:"outfile"`
+	ExecutionLogs          []workerutil.ExecutionLogEntry `json:"execution_logs"`
+	ExecutorLabels         []string                       `json:"executor_labels"`
+	DependencyRepositories []string                       `json:"dependency_repositories"`
+	TraceContext           TraceContext                   `json:"traceContext"`
+	Rank                   *int                           `json:"placeInQueue"`
+	AssociatedUploadID     *int                           `json:"associatedUpload"`
 }
 
 func (i Index) RecordID() int {
@@ -80,6 +84,9 @@ func scanIndexes(rows *sql.Rows, queryErr error) (_ []Index, err error) {
 			pq.Array(&executionLogs),
 			&index.Rank,
 			pq.Array(&index.LocalSteps),
+			pq.Array(&index.ExecutorLabels),
+			pq.Array(&index.DependencyRepositories),
+			&index.TraceContext,
 			&index.AssociatedUploadID,
 		); err != nil {
 			return nil, err
@@ -163,6 +170,9 @@ SELECT
 	u.execution_logs,
 	s.rank,
 	u.local_steps,
+	u.executor_labels,
+	u.dependency_repositories,
+	u.trace_context,
 	` + indexAssociatedUploadIDQueryFragment + `
 FROM lsif_indexes_with_repository_name u
 LEFT JOIN (` + indexRankQueryFragment + `) s
@@ -219,6 +229,9 @@ SELECT
 	u.execution_logs,
 	s.rank,
 	u.local_steps,
+	u.executor_labels,
+	u.dependency_repositories,
+	u.trace_context,
 	` + indexAssociatedUploadIDQueryFragment + `
 FROM lsif_indexes_with_repository_name u
 LEFT JOIN (` + indexRankQueryFragment + `) s
@@ -318,6 +331,9 @@ SELECT
 	u.execution_logs,
 	s.rank,
 	u.local_steps,
+	u.executor_labels,
+	u.dependency_repositories,
+	u.trace_context,
 	` + indexAssociatedUploadIDQueryFragment + `
 FROM lsif_indexes_with_repository_name u
 LEFT JOIN (` + indexRankQueryFragment + `) s
@@ -366,7 +382,22 @@ SELECT COUNT(*) WHERE EXISTS (
 )
 `
 
-// InsertIndex inserts a new index and returns its identifier.
+// IndexQueueSize returns the number of indexes in the queued state.
+func (s *Store) IndexQueueSize(ctx context.Context) (_ int, err error) {
+	ctx, endObservation := s.operations.indexQueueSize.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	count, _, err := basestore.ScanFirstInt(s.Store.Query(ctx, sqlf.Sprintf(indexQueueSizeQuery)))
+	return count, err
+}
+
+const indexQueueSizeQuery = `
+-- source: enterprise/internal/codeintel/stores/dbstore/indexes.go:IndexQueueSize
+SELECT COUNT(*) FROM lsif_indexes WHERE state = 'queued'
+`
+
+// InsertIndex inserts a new index and returns its identifier. Unless the index carries a trace
+// context already, the span context of the given context is stored with the index.
 func (s *Store) InsertIndex(ctx context.Context, index Index) (id int, err error) {
 	ctx, endObservation := s.operations.insertIndex.With(ctx, &err, observation.Args{})
 	defer func() {
@@ -384,6 +415,15 @@ func (s *Store) InsertIndex(ctx context.Context, index Index) (id int, err error
 	if index.LocalSteps == nil {
 		index.LocalSteps = []string{}
 	}
+	if index.ExecutorLabels == nil {
+		index.ExecutorLabels = []string{}
+	}
+	if index.DependencyRepositories == nil {
+		index.DependencyRepositories = []string{}
+	}
+	if index.TraceContext == nil {
+		index.TraceContext = ot.InjectSpanContext(ctx)
+	}
 
 	id, _, err = basestore.ScanFirstInt(s.Store.Query(
 		ctx,
@@ -399,6 +439,9 @@ func (s *Store) InsertIndex(ctx context.Context, index Index) (id int, err error
 			pq.Array(index.IndexerArgs),
 			index.Outfile,
 			pq.Array(dbworkerstore.ExecutionLogEntries(index.ExecutionLogs)),
+			pq.Array(index.ExecutorLabels),
+			pq.Array(index.DependencyRepositories),
+			index.TraceContext,
 		),
 	))
 
@@ -417,8 +460,11 @@ INSERT INTO lsif_indexes (
 	indexer,
 	indexer_args,
 	outfile,
-	execution_logs
-) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
+	execution_logs,
+	executor_labels,
+	dependency_repositories,
+	trace_context
+) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING id
 `
 
@@ -443,6 +489,9 @@ var indexColumnsWithNullRank = []*sqlf.Query{
 	sqlf.Sprintf(`u.execution_logs`),
 	sqlf.Sprintf("NULL"),
 	sqlf.Sprintf(`u.local_steps`),
+	sqlf.Sprintf(`u.executor_labels`),
+	sqlf.Sprintf(`u.dependency_repositories`),
+	sqlf.Sprintf(`u.trace_context`),
 	sqlf.Sprintf(indexAssociatedUploadIDQueryFragment),
 }
 
@@ -50,11 +50,13 @@ func TestGetIndexByID(t *testing.T) {
 				Commands: []string{"yarn install --frozen-lockfile --no-progress"},
 			},
 		},
-		LocalSteps:  []string{"echo hello"},
-		Root:        "/foo/bar",
-		Indexer:     "sourcegraph/lsif-tsc:latest",
-		IndexerArgs: []string{"lib/**/*.js", "test/**/*.js", "--allowJs", "--checkJs"},
-		Outfile:     "dump.lsif",
+		LocalSteps:             []string{"echo hello"},
+		Root:                   "/foo/bar",
+		Indexer:                "sourcegraph/lsif-tsc:latest",
+		IndexerArgs:            []string{"lib/**/*.js", "test/**/*.js", "--allowJs", "--checkJs"},
+		Outfile:                "dump.lsif",
+		ExecutorLabels:         []string{"gpu"},
+		DependencyRepositories: []string{"github.com/sourcegraph/go-diff"},
 		ExecutionLogs: []workerutil.ExecutionLogEntry{
 			{Command: []string{"op", "1"}, Out: "Indexing\nUploading\nDone with 1.\n"},
 			{Command: []string{"op", "2"}, Out: "Indexing\nUploading\nDone with 2.\n"},
@@ -370,6 +372,30 @@ func TestIsQueued(t *testing.T) {
 	}
 }
 
+func TestIndexQueueSize(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	db := dbtesting.GetDB(t)
+	store := testStore(db)
+
+	insertIndexes(t, db,
+		Index{ID: 1, State: "queued"},
+		Index{ID: 2, State: "queued"},
+		Index{ID: 3, State: "processing"},
+		Index{ID: 4, State: "completed"},
+		Index{ID: 5, State: "errored"},
+	)
+
+	size, err := store.IndexQueueSize(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error getting index queue size: %s", err)
+	}
+	if size != 2 {
+		t.Errorf("unexpected queue size. want=%d have=%d", 2, size)
+	}
+}
+
 func TestInsertIndex(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -389,11 +415,13 @@ func TestInsertIndex(t *testing.T) {
 				Commands: []string{"yarn install --frozen-lockfile --no-progress"},
 			},
 		},
-		LocalSteps:  []string{"echo hello"},
-		Root:        "/foo/bar",
-		Indexer:     "sourcegraph/lsif-tsc:latest",
-		IndexerArgs: []string{"lib/**/*.js", "test/**/*.js", "--allowJs", "--checkJs"},
-		Outfile:     "dump.lsif",
+		LocalSteps:             []string{"echo hello"},
+		Root:                   "/foo/bar",
+		Indexer:                "sourcegraph/lsif-tsc:latest",
+		IndexerArgs:            []string{"lib/**/*.js", "test/**/*.js", "--allowJs", "--checkJs"},
+		Outfile:                "dump.lsif",
+		ExecutorLabels:         []string{"gpu"},
+		DependencyRepositories: []string{"github.com/sourcegraph/go-diff"},
 		ExecutionLogs: []workerutil.ExecutionLogEntry{
 			{Command: []string{"op", "1"}, Out: "Indexing\nUploading\nDone with 1.\n"},
 			{Command: []string{"op", "2"}, Out: "Indexing\nUploading\nDone with 2.\n"},
@@ -420,11 +448,13 @@ func TestInsertIndex(t *testing.T) {
 				Commands: []string{"yarn install --frozen-lockfile --no-progress"},
 			},
 		},
-		LocalSteps:  []string{"echo hello"},
-		Root:        "/foo/bar",
-		Indexer:     "sourcegraph/lsif-tsc:latest",
-		IndexerArgs: []string{"lib/**/*.js", "test/**/*.js", "--allowJs", "--checkJs"},
-		Outfile:     "dump.lsif",
+		LocalSteps:             []string{"echo hello"},
+		Root:                   "/foo/bar",
+		Indexer:                "sourcegraph/lsif-tsc:latest",
+		IndexerArgs:            []string{"lib/**/*.js", "test/**/*.js", "--allowJs", "--checkJs"},
+		Outfile:                "dump.lsif",
+		ExecutorLabels:         []string{"gpu"},
+		DependencyRepositories: []string{"github.com/sourcegraph/go-diff"},
 		ExecutionLogs: []workerutil.ExecutionLogEntry{
 			{Command: []string{"op", "1"}, Out: "Indexing\nUploading\nDone with 1.\n"},
 			{Command: []string{"op", "2"}, Out: "Indexing\nUploading\nDone with 2.\n"},
@@ -0,0 +1,34 @@
+package dbstore
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/cockroachdb/errors"
+)
+
+// TraceContext is the span context of the request that enqueued an index job, encoded as an
+// OpenTracing text map. A nil TraceContext is stored as null.
+type TraceContext map[string]string
+
+func (c *TraceContext) Scan(value interface{}) error {
+	if value == nil {
+		*c = nil
+		return nil
+	}
+
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.Errorf("value is not []byte: %T", value)
+	}
+
+	return json.Unmarshal(b, c)
+}
+
+func (c TraceContext) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(c)
+}
@@ -62,6 +62,42 @@ func WorkerutilIndexStore(s basestore.ShareableStore, observationContext *observ
 	return dbworkerstore.NewWithMetrics(s.Handle(), indexWorkerStoreOptions, observationContext)
 }
 
+// WorkerutilIndexStoreWithResetOptions creates an index store that uses the given stalled
+// max age and maximum number of resets in place of StalledIndexMaxAge and IndexMaxNumResets,
+// and dequeues records according to the given dequeue options. If dependencyFreshness is
+// positive, index records whose dependency repositories all have an upload completed within
+// that duration are dequeued before the index records that are still waiting on a dependency.
+func WorkerutilIndexStoreWithResetOptions(s basestore.ShareableStore, stalledMaxAge time.Duration, maxNumResets int, dependencyFreshness time.Duration, dequeueOptions dbworkerstore.DequeueOptions, observationContext *observation.Context) dbworkerstore.Store {
+	options := indexWorkerStoreOptions
+	options.StalledMaxAge = stalledMaxAge
+	options.MaxNumResets = maxNumResets
+	options.Dequeue = dequeueOptions
+	if dependencyFreshness > 0 {
+		options.OrderByExpression = sqlf.Sprintf(indexDependencyOrderExpression, dependencyFreshness/time.Second)
+	}
+
+	return dbworkerstore.NewWithMetrics(s.Handle(), options, observationContext)
+}
+
+// indexDependencyOrderExpression orders index records that do not wait on a dependency
+// repository without a fresh upload first, and by their position in the queue otherwise.
+const indexDependencyOrderExpression = `
+EXISTS (
+	SELECT 1
+	FROM unnest(u.dependency_repositories) AS d(name)
+	WHERE NOT EXISTS (
+		SELECT 1
+		FROM lsif_uploads du
+		JOIN repo dr ON dr.id = du.repository_id
+		WHERE
+			dr.name = d.name AND
+			dr.deleted_at IS NULL AND
+			du.state = 'completed' AND
+			du.finished_at >= NOW() - (%s * interval '1 second')
+	)
+), u.queued_at, u.id
+`
+
 // StalledDependencyIndexingJobMaxAge is the maximum allowable duration between updating
 // the state of a dependency indexing job as "processing" and locking the job row during
 // processing. An unlocked row that is marked as processing likely indicates that the worker
@@ -0,0 +1,66 @@
+package dbstore
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+func TestWorkerutilIndexStoreDependencyOrder(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	testCases := []struct {
+		dependencyFreshness time.Duration
+		expectedIDs         []int
+	}{
+		// Index 3 depends on a fresh upload, and index 4 has no dependencies
+		{dependencyFreshness: 24 * time.Hour, expectedIDs: []int{3, 4, 1, 2}},
+		{dependencyFreshness: 0, expectedIDs: []int{1, 2, 3, 4}},
+	}
+
+	for _, testCase := range testCases {
+		db := dbtesting.GetDB(t)
+		now := time.Now()
+		finishedRecently := now.Add(-time.Hour)
+		finishedLongAgo := now.Add(-48 * time.Hour)
+
+		insertUploads(t, db,
+			Upload{ID: 10, RepositoryID: 51, State: "completed", FinishedAt: &finishedRecently},
+			Upload{ID: 11, RepositoryID: 52, State: "completed", FinishedAt: &finishedLongAgo},
+		)
+		insertIndexes(t, db,
+			Index{ID: 1, State: "queued", QueuedAt: now.Add(-4 * time.Minute), DependencyRepositories: []string{"n-52"}},
+			Index{ID: 2, State: "queued", QueuedAt: now.Add(-3 * time.Minute), DependencyRepositories: []string{"n-51", "n-53"}},
+			Index{ID: 3, State: "queued", QueuedAt: now.Add(-2 * time.Minute), DependencyRepositories: []string{"n-51"}},
+			Index{ID: 4, State: "queued", QueuedAt: now.Add(-1 * time.Minute)},
+		)
+
+		workerStore := WorkerutilIndexStoreWithResetOptions(basestore.NewWithDB(db, sql.TxOptions{}), StalledIndexMaxAge, IndexMaxNumResets, testCase.dependencyFreshness, dbworkerstore.DequeueOptions{}, &observation.TestContext)
+
+		var ids []int
+		for {
+			record, ok, err := workerStore.Dequeue(context.Background(), "test", nil)
+			if err != nil {
+				t.Fatalf("unexpected error dequeueing index: %s", err)
+			}
+			if !ok {
+				break
+			}
+			ids = append(ids, record.RecordID())
+		}
+
+		if diff := cmp.Diff(testCase.expectedIDs, ids); diff != "" {
+			t.Errorf("unexpected dequeue order with freshness %s (-want +got):\n%s", testCase.dependencyFreshness, diff)
+		}
+	}
+}
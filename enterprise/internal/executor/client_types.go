@@ -34,12 +34,48 @@ type Job struct {
 	// environment variables, as well as secret values passed along with the dequeued job
 	// payload, which may be sensitive (e.g. shared API tokens, URLs with credentials).
 	RedactedValues map[string]string `json:"redactedValues"`
+
+	// Checkpoint is the most recent progress checkpoint reported for this job by a previous
+	// executor. It is opaque to the executor and is made available to the job so that it can
+	// resume rather than restart.
+	Checkpoint []byte `json:"checkpoint,omitempty"`
+
+	// ResourceHints, if set, describe the resources the job is expected to need. Executors may
+	// use them to run the job in an appropriately sized virtual machine or container.
+	ResourceHints *ResourceHints `json:"resourceHints,omitempty"`
+
+	// MaxExecutionTime, if set, is the maximum wall time that may be spent on this job, as
+	// a duration string such as "2h". It replaces the executor's maximum runtime per job.
+	MaxExecutionTime string `json:"maxExecutionTime,omitempty"`
+
+	// CacheKey, if set, is a content-addressed key of the work described by this job. Jobs with
+	// the same cache key produce the same result, so an executor that has the result of a previous
+	// job with this key may report it via markComplete instead of running the job.
+	CacheKey string `json:"cacheKey,omitempty"`
+
+	// TraceContext, if set, is the span context of the request that enqueued this job, encoded
+	// as an OpenTracing text map. Executors continue the trace so that the spans of the job are
+	// linked to the user action that caused it.
+	TraceContext map[string]string `json:"traceContext,omitempty"`
 }
 
 func (j Job) RecordID() int {
 	return j.ID
 }
 
+// ResourceHints describe the resources a job is expected to need. Values are in the format of
+// the corresponding executor settings, and empty values leave the executor's defaults in place.
+type ResourceHints struct {
+	// NumCPUs is the number of virtual CPUs the job needs.
+	NumCPUs int `json:"numCpus,omitempty"`
+
+	// Memory is the amount of memory the job needs, e.g. "16G".
+	Memory string `json:"memory,omitempty"`
+
+	// DiskSpace is the amount of disk space the job needs, e.g. "40G".
+	DiskSpace string `json:"diskSpace,omitempty"`
+}
+
 type DockerStep struct {
 	// Image specifies the docker image.
 	Image string `json:"image"`
@@ -66,10 +102,38 @@ type CliStep struct {
 }
 
 type DequeueRequest struct {
-	ExecutorName     string `json:"executorName"`
-	ExecutorHostname string `json:"executorHostname"`
+	ExecutorName     string   `json:"executorName"`
+	ExecutorHostname string   `json:"executorHostname"`
+	ExecutorLabels   []string `json:"executorLabels,omitempty"`
+	ExecutorChannel  string   `json:"executorChannel,omitempty"`
+
+	// ExecutorVersion is the version of the requesting executor. Queues that restrict the
+	// supported executor versions refuse to hand out jobs to executors outside of that range.
+	ExecutorVersion string `json:"executorVersion,omitempty"`
+
+	// NumJobs, if positive, requests up to this many jobs at once. The response is then a list
+	// of jobs rather than a single job.
+	NumJobs int `json:"numJobs,omitempty"`
 }
 
+// UpgradeRequiredResponse is the body of the 426 Upgrade Required response returned to a
+// dequeue request from an executor whose version is not supported by the queue.
+type UpgradeRequiredResponse struct {
+	Error           string `json:"error"`
+	ExecutorVersion string `json:"executorVersion"`
+
+	// MinVersion and MaxVersion are the inclusive bounds of the executor versions supported
+	// by the queue. An empty value leaves the range open on that side.
+	MinVersion string `json:"minVersion,omitempty"`
+	MaxVersion string `json:"maxVersion,omitempty"`
+}
+
+// Executor release channels. Executors that do not report a channel are on the stable channel.
+const (
+	ExecutorChannelStable = "stable"
+	ExecutorChannelCanary = "canary"
+)
+
 type AddExecutionLogEntryRequest struct {
 	ExecutorName string `json:"executorName"`
 	JobID        int    `json:"jobId"`
@@ -86,15 +150,77 @@ type UpdateExecutionLogEntryRequest struct {
 type MarkCompleteRequest struct {
 	ExecutorName string `json:"executorName"`
 	JobID        int    `json:"jobId"`
+
+	// CacheHit, if set, completes the job with the result of a previous job with the same cache
+	// key. The job's execution logs are not consulted, so the job need not have been run.
+	CacheHit *CacheHit `json:"cacheHit,omitempty"`
+}
+
+// CacheHit is the result of a previous job reported in place of running a job.
+type CacheHit struct {
+	// CacheKey is the cache key of the job being completed, as handed to the executor.
+	CacheKey string `json:"cacheKey"`
+
+	// Result is the queue-specific result of the previous job, e.g. the identifier of the batch
+	// spec created by a previous batch spec execution.
+	Result string `json:"result"`
 }
 
 type MarkErroredRequest struct {
 	ExecutorName string `json:"executorName"`
 	JobID        int    `json:"jobId"`
 	ErrorMessage string `json:"errorMessage"`
+
+	// FailureClass, if set, is one of the FailureClass constants and selects the retry policy
+	// the queue applies to the job.
+	FailureClass string `json:"failureClass,omitempty"`
 }
 
+// Failure classes reported by executors with markErrored.
+const (
+	// FailureClassUserError indicates that the job itself is broken (e.g. a step exited with a
+	// non-zero status) and will fail again if retried.
+	FailureClassUserError = "user-error"
+
+	// FailureClassTransient indicates a failure that is likely to go away on its own, such as a
+	// network error while cloning the repository.
+	FailureClassTransient = "transient"
+
+	// FailureClassInfrastructure indicates that the executor could not run the job, such as a
+	// failure to start a virtual machine.
+	FailureClassInfrastructure = "infrastructure"
+)
+
 type HeartbeatRequest struct {
 	ExecutorName string `json:"executorName"`
 	JobIDs       []int  `json:"jobIds"`
+
+	// The following fields describe the executor and are recorded in the executor registry.
+	// They are optional so that older executors can continue to heartbeat.
+	ExecutorHostname string `json:"executorHostname,omitempty"`
+	OS               string `json:"os,omitempty"`
+	Architecture     string `json:"architecture,omitempty"`
+	ExecutorVersion  string `json:"executorVersion,omitempty"`
+
+	// Checkpoints maps job identifiers to the progress checkpoint most recently written by that
+	// job. Only checkpoints that changed since the previous heartbeat need to be sent.
+	Checkpoints map[int][]byte `json:"checkpoints,omitempty"`
+}
+
+// Artifact describes a job output uploaded to the executor-queue.
+//
+// Artifacts are uploaded as a multipart/form-data request with the fields executorName and
+// jobId, followed by one file part named artifact per uploaded file. The filename of each
+// file part is used as the name of the artifact.
+type Artifact struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+	Size int64  `json:"size"`
+}
+
+// InsightsSearchRequest is the payload sent by executors to the frontend to perform the search
+// query of a code insights backfill job. The response is a JSON object mapping the GraphQL ID of
+// each repository in the search results to the number of matches in that repository.
+type InsightsSearchRequest struct {
+	Query string `json:"query"`
 }
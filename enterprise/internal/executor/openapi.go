@@ -0,0 +1,324 @@
+package executor
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// OpenAPISpec returns the OpenAPI 3 document describing Routes for the given queues. If no
+// queue names are given, the document describes the routes of any queue with a queueName path
+// parameter.
+func OpenAPISpec(queueNames ...string) map[string]interface{} {
+	b := &schemaBuilder{components: map[string]interface{}{}}
+
+	var paths []string
+	operations := map[string]map[string]interface{}{}
+	for _, route := range groupRoutes() {
+		paths = append(paths, route[0].Path)
+		operations[route[0].Path] = b.operation(route, len(queueNames) == 0)
+	}
+
+	pathItems := map[string]interface{}{}
+	for _, path := range paths {
+		if len(queueNames) == 0 {
+			pathItems["/{queueName}/"+path] = map[string]interface{}{"post": operations[path]}
+			continue
+		}
+
+		for _, queueName := range queueNames {
+			pathItems["/"+queueName+"/"+path] = map[string]interface{}{"post": operations[path]}
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Executor job queue API",
+			"description": "The routes called by executors to claim jobs and report on their progress.",
+			"version":     "1.0.0",
+		},
+		"paths": pathItems,
+		"components": map[string]interface{}{
+			"schemas": b.components,
+			"securitySchemes": map[string]interface{}{
+				"basicAuth": map[string]interface{}{"type": "http", "scheme": "basic"},
+			},
+		},
+		"security": []interface{}{map[string]interface{}{"basicAuth": []string{}}},
+	}
+}
+
+// groupRoutes groups the routes sharing a path, in order of their first appearance.
+func groupRoutes() [][]Route {
+	var groups [][]Route
+	indexes := map[string]int{}
+	for _, route := range Routes {
+		if i, ok := indexes[route.Path]; ok {
+			groups[i] = append(groups[i], route)
+			continue
+		}
+
+		indexes[route.Path] = len(groups)
+		groups = append(groups, []Route{route})
+	}
+
+	return groups
+}
+
+// RequestSchema returns a JSON schema (draft 7) validating the JSON request body of the route
+// with the given path, or false if the route does not accept a JSON body. Unlike the schemas of
+// the OpenAPI document, the schema accepts null in place of slices, maps, and pointers, which
+// encoding/json produces for nil values, and does not require any property, as older executors
+// may omit those added since. Properties unknown to the request type are rejected unless
+// allowUnknownFields is set.
+func RequestSchema(path string, allowUnknownFields bool) (map[string]interface{}, bool) {
+	for _, route := range Routes {
+		if route.Path != path || route.Request == nil {
+			continue
+		}
+
+		b := &schemaBuilder{
+			components: map[string]interface{}{},
+			refPrefix:  "#/definitions/",
+			nullable:   true,
+			optional:   true,
+			closed:     !allowUnknownFields,
+		}
+		schema := b.schema(reflect.TypeOf(route.Request))
+		schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+		schema["definitions"] = b.components
+		return schema, true
+	}
+
+	return nil, false
+}
+
+// schemaBuilder converts Go types into OpenAPI schemas. Named struct types are added to the
+// shared component schemas and referenced by name.
+type schemaBuilder struct {
+	components map[string]interface{}
+
+	// refPrefix is the prefix of references to component schemas. Defaults to the location of
+	// the component schemas of an OpenAPI document.
+	refPrefix string
+
+	// nullable permits null in place of the values of slices, maps, and pointers.
+	nullable bool
+
+	// optional omits the required properties of objects.
+	optional bool
+
+	// closed rejects the properties of objects not described by their struct type.
+	closed bool
+}
+
+// operation returns the OpenAPI operation of the given routes, which share a path.
+func (b *schemaBuilder) operation(routes []Route, queueNameParameter bool) map[string]interface{} {
+	var (
+		descriptions      []string
+		responses         []interface{}
+		mayBeEmpty        bool
+		mayRequireUpgrade bool
+	)
+	for _, route := range routes {
+		descriptions = append(descriptions, route.Description)
+		if route.Response != nil {
+			responses = append(responses, b.schema(reflect.TypeOf(route.Response)))
+		}
+		mayBeEmpty = mayBeEmpty || route.MayBeEmpty || route.Response == nil
+		mayRequireUpgrade = mayRequireUpgrade || route.MayRequireUpgrade
+	}
+
+	operation := map[string]interface{}{
+		"operationId": routes[0].Path,
+		"description": strings.Join(descriptions, "\n\n"),
+	}
+	if queueNameParameter {
+		operation["parameters"] = []interface{}{
+			map[string]interface{}{
+				"name":     "queueName",
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]interface{}{"type": "string"},
+			},
+		}
+	}
+
+	if request := routes[0].Request; request != nil {
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  jsonContent(b.schema(reflect.TypeOf(request))),
+		}
+	} else {
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"multipart/form-data": map[string]interface{}{"schema": artifactUploadSchema},
+			},
+		}
+	}
+
+	responseCodes := map[string]interface{}{
+		"default": map[string]interface{}{"description": "The request failed."},
+	}
+	switch len(responses) {
+	case 0:
+	case 1:
+		responseCodes["200"] = map[string]interface{}{"description": "OK", "content": jsonContent(responses[0])}
+	default:
+		responseCodes["200"] = map[string]interface{}{"description": "OK", "content": jsonContent(map[string]interface{}{"oneOf": responses})}
+	}
+	if mayBeEmpty {
+		responseCodes["204"] = map[string]interface{}{"description": "No Content"}
+	}
+	if mayRequireUpgrade {
+		responseCodes["426"] = map[string]interface{}{
+			"description": "The version of the executor is not supported by the queue.",
+			"content":     jsonContent(b.schema(reflect.TypeOf(UpgradeRequiredResponse{}))),
+		}
+	}
+	operation["responses"] = responseCodes
+
+	return operation
+}
+
+// artifactUploadSchema describes the multipart/form-data body of an artifact upload.
+var artifactUploadSchema = map[string]interface{}{
+	"type":     "object",
+	"required": []string{"executorName", "jobId", "artifact"},
+	"properties": map[string]interface{}{
+		"executorName": map[string]interface{}{"type": "string"},
+		"jobId":        map[string]interface{}{"type": "integer"},
+		"artifact": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "string", "format": "binary"},
+		},
+	},
+}
+
+func jsonContent(schema interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schema returns the schema of the JSON encoding of values of the given type.
+func (b *schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.orNull(b.schema(t.Elem()))
+
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return b.orNull(map[string]interface{}{"type": "string", "format": "byte"})
+		}
+		return b.orNull(map[string]interface{}{"type": "array", "items": b.schema(t.Elem())})
+
+	case reflect.Map:
+		return b.orNull(map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())})
+
+	case reflect.Struct:
+		if _, ok := b.components[t.Name()]; !ok {
+			// Reserve the name before descending into the fields of recursive types
+			b.components[t.Name()] = nil
+			b.components[t.Name()] = b.structSchema(t)
+		}
+		refPrefix := b.refPrefix
+		if refPrefix == "" {
+			refPrefix = "#/components/schemas/"
+		}
+		return map[string]interface{}{"$ref": refPrefix + t.Name()}
+	}
+
+	return map[string]interface{}{}
+}
+
+// orNull returns the given schema, amended to also accept null if the builder permits null
+// values.
+func (b *schemaBuilder) orNull(schema map[string]interface{}) map[string]interface{} {
+	if !b.nullable {
+		return schema
+	}
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+		return schema
+	}
+
+	return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+}
+
+// structSchema returns the schema of the JSON object encoding of the given struct type. Fields
+// without the omitempty option are always encoded and are therefore required.
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	b.addFields(t, properties, &required)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if !b.optional {
+		schema["required"] = required
+	}
+	if b.closed {
+		schema["additionalProperties"] = false
+	}
+
+	return schema
+}
+
+func (b *schemaBuilder) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		// Fields of untagged embedded structs are promoted into the enclosing object
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			b.addFields(field.Type, properties, required)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, options := field.Name, ""
+		if tag != "" {
+			parts := strings.SplitN(tag, ",", 2)
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			if len(parts) > 1 {
+				options = parts[1]
+			}
+		}
+
+		properties[name] = b.schema(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package queueclient is a typed client of the job queue API that executors call. The methods
+// of Client are generated from the routes described by the executor package, which also back
+// the OpenAPI document of the API (see openapi.json).
+package queueclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+)
+
+// Options configure the target and transport of a Client.
+type Options struct {
+	// URL is the base URL of the job queue API.
+	URL string
+
+	// PathPrefix is the path prefix added to all requests, e.g. /.executors/queue when the
+	// job queue API is reached through the frontend.
+	PathPrefix string
+
+	// Username and Password are the basic-auth credentials included with all requests.
+	Username string
+	Password string
+
+	// UserAgent specifies the user agent string to supply on requests.
+	UserAgent string
+
+	// Transport is a configurable round tripper, which can include things like tracing,
+	// metrics, and request/response decoration.
+	Transport http.RoundTripper
+}
+
+// Client calls the job queue API.
+type Client struct {
+	options    Options
+	httpClient *http.Client
+}
+
+// New returns a client with the given options.
+func New(options Options) *Client {
+	httpClient := http.DefaultClient
+	if options.Transport != nil {
+		httpClient = &http.Client{Transport: options.Transport}
+	}
+
+	return &Client{options: options, httpClient: httpClient}
+}
+
+// UpgradeRequiredError is returned when the queue refuses to hand out jobs to the executor
+// because its version is not supported.
+type UpgradeRequiredError struct {
+	executor.UpgradeRequiredResponse
+}
+
+func (e *UpgradeRequiredError) Error() string {
+	return e.UpgradeRequiredResponse.Error
+}
+
+// DecodeUpgradeRequiredError decodes the body of a 426 Upgrade Required response into an
+// UpgradeRequiredError.
+func DecodeUpgradeRequiredError(body io.Reader) error {
+	var response executor.UpgradeRequiredResponse
+	if err := json.NewDecoder(body).Decode(&response); err != nil || response.Error == "" {
+		return errors.Errorf("unexpected status code %d", http.StatusUpgradeRequired)
+	}
+
+	return &UpgradeRequiredError{UpgradeRequiredResponse: response}
+}
+
+// URL returns the URL of the given route of the given queue.
+func (c *Client) URL(queueName, route string) (*url.URL, error) {
+	u, err := url.Parse(c.options.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.ResolveReference(&url.URL{Path: path.Join(c.options.PathPrefix, queueName, route)}), nil
+}
+
+// do sends the given request value as the JSON body of a POST request to the given route of
+// the given queue and decodes the JSON response into the given response pointer, if one is
+// supplied. If the response has no content due to a 204 response, a false-valued flag is
+// returned.
+func (c *Client) do(ctx context.Context, queueName, route string, request, response interface{}) (hasContent bool, err error) {
+	u, err := c.URL(queueName, route)
+	if err != nil {
+		return false, err
+	}
+
+	contents, err := json.Marshal(request)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(contents))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.options.UserAgent)
+	req.SetBasicAuth(c.options.Username, c.options.Password)
+
+	resp, err := ctxhttp.Do(ctx, c.httpClient, req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent:
+		return false, nil
+	case http.StatusUpgradeRequired:
+		return false, DecodeUpgradeRequiredError(resp.Body)
+	default:
+		return false, errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	if response == nil {
+		return true, nil
+	}
+	return true, json.NewDecoder(resp.Body).Decode(response)
+}
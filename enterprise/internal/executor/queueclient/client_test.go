@@ -0,0 +1,123 @@
+package queueclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+)
+
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	expected, err := json.MarshalIndent(executor.OpenAPISpec(), "", "  ")
+	if err != nil {
+		t.Fatalf("unexpected error marshalling OpenAPI document: %s", err)
+	}
+	contents, err := os.ReadFile("openapi.json")
+	if err != nil {
+		t.Fatalf("unexpected error reading openapi.json: %s", err)
+	}
+	if string(contents) != string(expected)+"\n" {
+		t.Errorf("openapi.json is out of date: run go generate ./enterprise/internal/executor/queueclient")
+	}
+
+	clientType := reflect.TypeOf(&Client{})
+	for _, route := range executor.Routes {
+		if route.Request == nil {
+			continue
+		}
+
+		m, ok := clientType.MethodByName(route.Name)
+		if !ok {
+			t.Errorf("missing method %s: run go generate ./enterprise/internal/executor/queueclient", route.Name)
+			continue
+		}
+		if requestType := m.Type.In(3); requestType != reflect.TypeOf(route.Request) {
+			t.Errorf("unexpected request type of %s. want=%s have=%s", route.Name, reflect.TypeOf(route.Request), requestType)
+		}
+	}
+}
+
+func TestDequeue(t *testing.T) {
+	var request executor.DequeueRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.executors/queue/test/dequeue" {
+			t.Errorf("unexpected path. want=%q have=%q", "/.executors/queue/test/dequeue", r.URL.Path)
+		}
+		if username, password, _ := r.BasicAuth(); username != "test" || password != "hunter2" {
+			t.Errorf("unexpected credentials. want=%q have=%q", "test:hunter2", username+":"+password)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("unexpected error decoding request: %s", err)
+		}
+
+		_ = json.NewEncoder(w).Encode(executor.Job{ID: 42, RepositoryName: "github.com/test/test"})
+	}))
+	defer ts.Close()
+
+	client := New(Options{URL: ts.URL, PathPrefix: "/.executors/queue", Username: "test", Password: "hunter2"})
+	job, ok, err := client.Dequeue(context.Background(), "test", executor.DequeueRequest{ExecutorName: "deadbeef"})
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing job: %s", err)
+	}
+	if !ok {
+		t.Fatalf("expected a job")
+	}
+	if diff := cmp.Diff(executor.Job{ID: 42, RepositoryName: "github.com/test/test"}, job); diff != "" {
+		t.Errorf("unexpected job (-want +got):\n%s", diff)
+	}
+	if request.ExecutorName != "deadbeef" {
+		t.Errorf("unexpected executor name. want=%q have=%q", "deadbeef", request.ExecutorName)
+	}
+}
+
+func TestDequeueNoContent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	_, ok, err := New(Options{URL: ts.URL}).DequeueBatch(context.Background(), "test", executor.DequeueRequest{NumJobs: 2})
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing jobs: %s", err)
+	}
+	if ok {
+		t.Errorf("expected no jobs")
+	}
+}
+
+func TestDequeueUpgradeRequired(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUpgradeRequired)
+		_ = json.NewEncoder(w).Encode(executor.UpgradeRequiredResponse{Error: "executor too old", ExecutorVersion: "1.0.0", MinVersion: "2.0.0"})
+	}))
+	defer ts.Close()
+
+	_, _, err := New(Options{URL: ts.URL}).Dequeue(context.Background(), "test", executor.DequeueRequest{ExecutorVersion: "1.0.0"})
+
+	var upgradeErr *UpgradeRequiredError
+	if !errors.As(err, &upgradeErr) {
+		t.Fatalf("unexpected error. want=%T have=%v", upgradeErr, err)
+	}
+	if upgradeErr.MinVersion != "2.0.0" {
+		t.Errorf("unexpected min version. want=%q have=%q", "2.0.0", upgradeErr.MinVersion)
+	}
+}
+
+func TestMarkCompleteUnexpectedStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	if err := New(Options{URL: ts.URL}).MarkComplete(context.Background(), "test", executor.MarkCompleteRequest{JobID: 42}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
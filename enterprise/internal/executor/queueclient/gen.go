@@ -0,0 +1,3 @@
+package queueclient
+
+//go:generate go run ./gen
@@ -0,0 +1,99 @@
+// Command gen generates the methods of queueclient.Client and the OpenAPI document of the job
+// queue API from the routes described by the executor package. It is run from the queueclient
+// directory via go generate.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/format"
+	"log"
+	"os"
+	"reflect"
+	"text/template"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+)
+
+func main() {
+	if err := generateClient("routes.go"); err != nil {
+		log.Fatalf("failed to generate client: %s", err)
+	}
+	if err := generateSpec("openapi.json"); err != nil {
+		log.Fatalf("failed to generate OpenAPI document: %s", err)
+	}
+}
+
+type method struct {
+	executor.Route
+	RequestType  string
+	ResponseType string
+}
+
+var clientTemplate = template.Must(template.New("client").Parse(`// Code generated by gen/main.go; DO NOT EDIT.
+
+package queueclient
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/executor"
+)
+{{range .}}
+// {{.Description}}
+//
+// {{.Name}} calls POST /{queueName}/{{.Path}}.
+{{- if not .ResponseType}}
+func (c *Client) {{.Name}}(ctx context.Context, queueName string, request {{.RequestType}}) error {
+	_, err := c.do(ctx, queueName, "{{.Path}}", request, nil)
+	return err
+}
+{{- else if .MayBeEmpty}} A false-valued flag is returned if the response has no content.
+func (c *Client) {{.Name}}(ctx context.Context, queueName string, request {{.RequestType}}) (response {{.ResponseType}}, ok bool, err error) {
+	ok, err = c.do(ctx, queueName, "{{.Path}}", request, &response)
+	return response, ok, err
+}
+{{- else}}
+func (c *Client) {{.Name}}(ctx context.Context, queueName string, request {{.RequestType}}) (response {{.ResponseType}}, err error) {
+	_, err = c.do(ctx, queueName, "{{.Path}}", request, &response)
+	return response, err
+}
+{{- end}}
+{{end}}`))
+
+// generateClient writes a method of queueclient.Client for each route with a JSON request body.
+func generateClient(filename string) error {
+	var methods []method
+	for _, route := range executor.Routes {
+		if route.Request == nil {
+			continue
+		}
+
+		m := method{Route: route, RequestType: reflect.TypeOf(route.Request).String()}
+		if route.Response != nil {
+			m.ResponseType = reflect.TypeOf(route.Response).String()
+		}
+		methods = append(methods, m)
+	}
+
+	var buf bytes.Buffer
+	if err := clientTemplate.Execute(&buf, methods); err != nil {
+		return err
+	}
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, source, 0644)
+}
+
+// generateSpec writes the OpenAPI document describing the routes of any queue.
+func generateSpec(filename string) error {
+	contents, err := json.MarshalIndent(executor.OpenAPISpec(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, append(contents, '\n'), 0644)
+}
@@ -0,0 +1,99 @@
+package executor
+
+// Route describes an endpoint of the job queue API that executors call for each queue. Routes
+// are served under /{queueName}/{Path} with the POST method. This table is the source of the
+// OpenAPI document of the job queue API and of the generated queueclient package, so the
+// apiserver must register a handler for each of these paths.
+type Route struct {
+	// Name is the name of the corresponding method of the generated client. Several routes may
+	// share a path when the shape of the response depends on the request.
+	Name string
+
+	// Path is the path of the route relative to the queue.
+	Path string
+
+	// Description documents the route in the OpenAPI document and the generated client.
+	Description string
+
+	// Request is a zero value of the JSON request body. Routes without a request value accept
+	// a multipart/form-data body instead and are not part of the generated client.
+	Request interface{}
+
+	// Response is a zero value of the JSON response body, or nil if the route responds with
+	// 204 No Content on success.
+	Response interface{}
+
+	// MayBeEmpty is true if the route may respond with 204 No Content in place of a response
+	// body, e.g. when there is no job to dequeue.
+	MayBeEmpty bool
+
+	// MayRequireUpgrade is true if the route responds with 426 Upgrade Required and an
+	// UpgradeRequiredResponse to executors whose version the queue does not support.
+	MayRequireUpgrade bool
+}
+
+// Routes lists the endpoints of the job queue API that executors call.
+var Routes = []Route{
+	{
+		Name:              "Dequeue",
+		Path:              "dequeue",
+		Description:       "Dequeue claims a job for the requesting executor. There is no job if the queue is empty or paused.",
+		Request:           DequeueRequest{},
+		Response:          Job{},
+		MayBeEmpty:        true,
+		MayRequireUpgrade: true,
+	},
+	{
+		Name:              "DequeueBatch",
+		Path:              "dequeue",
+		Description:       "DequeueBatch claims up to NumJobs jobs for the requesting executor. NumJobs must be positive.",
+		Request:           DequeueRequest{},
+		Response:          []Job{},
+		MayBeEmpty:        true,
+		MayRequireUpgrade: true,
+	},
+	{
+		Name:        "AddExecutionLogEntry",
+		Path:        "addExecutionLogEntry",
+		Description: "AddExecutionLogEntry appends an entry to the execution log of a job and returns the identifier of the new entry.",
+		Request:     AddExecutionLogEntryRequest{},
+		Response:    0,
+	},
+	{
+		Name:        "UpdateExecutionLogEntry",
+		Path:        "updateExecutionLogEntry",
+		Description: "UpdateExecutionLogEntry replaces an entry of the execution log of a job.",
+		Request:     UpdateExecutionLogEntryRequest{},
+	},
+	{
+		Name:        "MarkComplete",
+		Path:        "markComplete",
+		Description: "MarkComplete marks a job as completed.",
+		Request:     MarkCompleteRequest{},
+	},
+	{
+		Name:        "MarkErrored",
+		Path:        "markErrored",
+		Description: "MarkErrored marks a job as errored. The failure class selects the retry policy applied to the job.",
+		Request:     MarkErroredRequest{},
+	},
+	{
+		Name:        "MarkFailed",
+		Path:        "markFailed",
+		Description: "MarkFailed marks a job as failed so that it is not retried. The failure class is ignored.",
+		Request:     MarkErroredRequest{},
+	},
+	{
+		Name:        "Heartbeat",
+		Path:        "heartbeat",
+		Description: "Heartbeat records the liveness of the executor and of the given jobs, and returns the identifiers of the jobs the executor still owns.",
+		Request:     HeartbeatRequest{},
+		Response:    []int{},
+	},
+	{
+		Name:        "UploadArtifact",
+		Path:        "uploadArtifact",
+		Description: "UploadArtifact stores the output files of a job. The request is a multipart/form-data body described by Artifact.",
+		Response:    []Artifact{},
+	},
+}
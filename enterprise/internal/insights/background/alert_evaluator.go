@@ -0,0 +1,323 @@
+package background
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/webhookrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/txemail"
+	"github.com/sourcegraph/sourcegraph/internal/txemail/txtypes"
+)
+
+// newAlertEvaluator returns a background goroutine which will periodically check the alert rules
+// of series against the most recent recording of their series, and notify the users whose rules
+// start firing by email and/or webhook.
+func newAlertEvaluator(ctx context.Context, alertStore AlertStore, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_alert_evaluator",
+		metrics.WithCountHelp("Total number of insights alert evaluator executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "AlertEvaluator.Run",
+		Metrics: metrics,
+	})
+
+	notifications := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_insights_alert_notifications_total",
+		Help: "The number of notifications sent for insights alert rules that started firing.",
+	})
+	observationContext.Registerer.MustRegister(notifications)
+
+	doer, err := httpcli.NewFactory(
+		httpcli.NewMiddleware(httpcli.ContextErrorMiddleware),
+		httpcli.NewTimeoutOpt(alertWebhookTimeout),
+		httpcli.ExternalTransportOpt,
+		httpcli.TracedTransportOpt,
+	).Doer()
+	if err != nil {
+		panic("insights: failed to create the alert evaluator HTTP client. This should not happen: " + err.Error())
+	}
+
+	evaluator := &alertEvaluator{
+		alertStore: alertStore,
+		notify: func(ctx context.Context, n alertNotification) error {
+			if err := sendAlertNotification(ctx, doer, conf.Get().InsightsWebhookSecret, n); err != nil {
+				return err
+			}
+			notifications.Inc()
+			return nil
+		},
+		now: time.Now,
+	}
+
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 1*time.Minute, goroutine.NewHandlerWithErrorMessage(
+		"insights_alert_evaluator",
+		evaluator.Handler,
+	), operation)
+}
+
+// AlertStore is a subset of the API exposed by the store.Store (only the subset used by the alert
+// evaluator.)
+type AlertStore interface {
+	ListAlertRules(ctx context.Context, opts store.ListAlertRulesOpts) ([]store.AlertRule, error)
+	LatestSeriesPointTime(ctx context.Context, seriesID string) (time.Time, bool, error)
+	SeriesValueAt(ctx context.Context, seriesID string, t time.Time) (float64, bool, error)
+	RecordAlertEvaluation(ctx context.Context, id int, evaluatedTime time.Time, firing, notified bool) error
+}
+
+const (
+	// alertRecordingSettleDelay is the time after which a recording of a series is checked against
+	// its alert rules. The data points of a recording are recorded one repository at a time, so
+	// this keeps rules from being checked against a recording that is only partially recorded.
+	alertRecordingSettleDelay = 10 * time.Minute
+
+	// alertWebhookTimeout is the time after which notifications posted to webhooks time out.
+	alertWebhookTimeout = 30 * time.Second
+)
+
+// alertEvaluator checks each alert rule once against every new recording of its series. Rules
+// only notify when they start firing, so a condition that holds for many recordings in a row
+// results in a single notification.
+type alertEvaluator struct {
+	alertStore AlertStore
+	notify     func(ctx context.Context, n alertNotification) error
+	now        func() time.Time
+}
+
+// alertNotification describes an alert rule that started firing.
+type alertNotification struct {
+	Rule  store.AlertRule
+	Time  time.Time
+	Value float64
+
+	// Previous is the value of the series at the start of the window of percent change rules.
+	Previous *float64
+}
+
+func (e *alertEvaluator) Handler(ctx context.Context) error {
+	rules, err := e.alertStore.ListAlertRules(ctx, store.ListAlertRulesOpts{})
+	if err != nil {
+		return errors.Wrap(err, "ListAlertRules")
+	}
+
+	var multi error
+	for _, rule := range rules {
+		if err := e.evaluate(ctx, rule); err != nil {
+			multi = multierror.Append(multi, errors.Wrapf(err, "alert rule %d", rule.ID))
+		}
+	}
+	return multi
+}
+
+func (e *alertEvaluator) evaluate(ctx context.Context, rule store.AlertRule) error {
+	latest, ok, err := e.alertStore.LatestSeriesPointTime(ctx, rule.SeriesID)
+	if err != nil {
+		return errors.Wrap(err, "LatestSeriesPointTime")
+	}
+	if !ok || latest.After(e.now().Add(-alertRecordingSettleDelay)) {
+		return nil
+	}
+	if rule.LastEvaluatedTime != nil && !latest.After(*rule.LastEvaluatedTime) {
+		// The rule was already checked against this recording.
+		return nil
+	}
+
+	// 🚨 SECURITY: Rules check the values of the series as the user who created them would see
+	// them in the insight, i.e. only counting the repositories that user can see.
+	ctx = actor.WithActor(ctx, actor.FromUser(rule.UserID))
+	n, firing, err := checkAlertRule(ctx, e.alertStore, rule, latest)
+	if err != nil {
+		return err
+	}
+
+	notify := firing && !rule.Firing
+	if notify {
+		if err := e.notify(ctx, n); err != nil {
+			// The evaluation is not recorded, so the notification is retried on the next run.
+			return errors.Wrap(err, "notify")
+		}
+	}
+	return e.alertStore.RecordAlertEvaluation(ctx, rule.ID, latest, firing, notify)
+}
+
+// checkAlertRule returns true if the given alert rule fires for the recording of its series at the
+// given time, along with the notification of the rule.
+func checkAlertRule(ctx context.Context, alertStore AlertStore, rule store.AlertRule, recordTime time.Time) (alertNotification, bool, error) {
+	n := alertNotification{Rule: rule, Time: recordTime}
+	value, ok, err := alertStore.SeriesValueAt(ctx, rule.SeriesID, recordTime)
+	if err != nil || !ok {
+		return n, false, err
+	}
+	n.Value = value
+
+	switch rule.Condition {
+	case store.AlertAbove:
+		return n, value > rule.Threshold, nil
+	case store.AlertBelow:
+		return n, value < rule.Threshold, nil
+	case store.AlertPercentChange:
+		previous, ok, err := alertStore.SeriesValueAt(ctx, rule.SeriesID, recordTime.AddDate(0, 0, -rule.WindowDays))
+		if err != nil || !ok || previous == 0 {
+			// The series has no value to compare to, or its change from zero is undefined.
+			return n, false, err
+		}
+		n.Previous = &previous
+		change := (value - previous) / math.Abs(previous) * 100
+		if rule.Threshold < 0 {
+			return n, change <= rule.Threshold, nil
+		}
+		return n, change >= rule.Threshold, nil
+	}
+	return n, false, errors.Errorf("unknown alert condition %q", rule.Condition)
+}
+
+// Message returns a human readable description of why the rule of n fired.
+func (n alertNotification) Message() string {
+	label := n.Rule.SeriesLabel
+	if label == "" {
+		label = n.Rule.SeriesID
+	}
+	switch {
+	case n.Rule.Condition == store.AlertPercentChange && n.Previous != nil:
+		change := (n.Value - *n.Previous) / math.Abs(*n.Previous) * 100
+		return fmt.Sprintf("%s changed by %s%% over %d days, from %s to %s", label, formatAlertValue(change), n.Rule.WindowDays, formatAlertValue(*n.Previous), formatAlertValue(n.Value))
+	case n.Rule.Condition == store.AlertBelow:
+		return fmt.Sprintf("%s is %s, below %s", label, formatAlertValue(n.Value), formatAlertValue(n.Rule.Threshold))
+	default:
+		return fmt.Sprintf("%s is %s, above %s", label, formatAlertValue(n.Value), formatAlertValue(n.Rule.Threshold))
+	}
+}
+
+func formatAlertValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// sendAlertNotification notifies the user of the rule of n by email and/or posts n to the webhook
+// of the rule, as configured by the rule.
+func sendAlertNotification(ctx context.Context, doer httpcli.Doer, secret string, n alertNotification) error {
+	var multi error
+	if n.Rule.NotifyEmail {
+		if err := sendAlertEmail(ctx, n); err != nil {
+			multi = multierror.Append(multi, errors.Wrap(err, "sending email"))
+		}
+	}
+	if n.Rule.WebhookURL != nil {
+		if err := postAlertWebhook(ctx, doer, secret, *n.Rule.WebhookURL, n); err != nil {
+			multi = multierror.Append(multi, errors.Wrap(err, "posting webhook"))
+		}
+	}
+	return multi
+}
+
+func sendAlertEmail(ctx context.Context, n alertNotification) error {
+	email, err := api.InternalClient.UserEmailsGetEmail(ctx, n.Rule.UserID)
+	if err != nil {
+		return errors.Wrap(err, "UserEmailsGetEmail")
+	}
+	if email == nil {
+		// Retrying would not help, so the notification is dropped.
+		log15.Warn("insights: unable to send alert email to user with unknown email address", "userID", n.Rule.UserID, "ruleID", n.Rule.ID)
+		return nil
+	}
+	return api.InternalClient.SendEmail(ctx, txtypes.Message{
+		To:       []string{*email},
+		Template: alertEmailTemplates,
+		Data: struct {
+			Label   string
+			Message string
+		}{
+			Label:   n.Rule.SeriesLabel,
+			Message: n.Message(),
+		},
+	})
+}
+
+var alertEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `[Code Insights] {{.Message}}`,
+	Text: `
+Your Code Insights alert rule fired: {{.Message}}.
+
+You are receiving this notification because you created an alert rule on this insight series.
+`,
+	HTML: `
+<p>Your Code Insights alert rule fired: <strong>{{.Message}}</strong>.</p>
+
+<p>You are receiving this notification because you created an alert rule on this insight series.</p>
+`,
+})
+
+// alertWebhookPayload is the body of the notifications posted to the webhooks of alert rules.
+type alertWebhookPayload struct {
+	// ID identifies the notification, so that receivers can discard duplicates of notifications
+	// that were retried.
+	ID string `json:"id"`
+
+	RuleID        int       `json:"ruleId"`
+	SeriesID      string    `json:"seriesId"`
+	SeriesLabel   string    `json:"seriesLabel"`
+	Condition     string    `json:"condition"`
+	Threshold     float64   `json:"threshold"`
+	WindowDays    int       `json:"windowDays,omitempty"`
+	Time          time.Time `json:"time"`
+	Value         float64   `json:"value"`
+	PreviousValue *float64  `json:"previousValue,omitempty"`
+	Message       string    `json:"message"`
+}
+
+func postAlertWebhook(ctx context.Context, doer httpcli.Doer, secret, webhookURL string, n alertNotification) error {
+	body, err := json.Marshal(alertWebhookPayload{
+		ID:            fmt.Sprintf("%d:%d", n.Rule.ID, n.Time.Unix()),
+		RuleID:        n.Rule.ID,
+		SeriesID:      n.Rule.SeriesID,
+		SeriesLabel:   n.Rule.SeriesLabel,
+		Condition:     string(n.Rule.Condition),
+		Threshold:     n.Rule.Threshold,
+		WindowDays:    n.Rule.WindowDays,
+		Time:          n.Time.UTC(),
+		Value:         n.Value,
+		PreviousValue: n.Previous,
+		Message:       n.Message(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "invalid webhook URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(webhookrunner.SignatureHeader, webhookrunner.Sign(secret, body))
+	}
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package background
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/webhookrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestAlertEvaluator(t *testing.T) {
+	now := time.Date(2021, 9, 10, 12, 0, 0, 0, time.UTC)
+	latest := now.Add(-time.Hour)
+	evaluated := latest.Add(-24 * time.Hour)
+
+	alertStore := NewMockAlertStore()
+	alertStore.ListAlertRulesFunc.SetDefaultReturn([]store.AlertRule{
+		{ID: 1, SeriesID: "s:one", UserID: 1, Condition: store.AlertAbove, Threshold: 10},
+		{ID: 2, SeriesID: "s:one", UserID: 1, Condition: store.AlertBelow, Threshold: 10},
+		// Already firing, so it does not notify again.
+		{ID: 3, SeriesID: "s:one", UserID: 1, Condition: store.AlertAbove, Threshold: 5, Firing: true, LastEvaluatedTime: &evaluated},
+		// Already checked against the latest recording.
+		{ID: 4, SeriesID: "s:one", UserID: 1, Condition: store.AlertAbove, Threshold: 5, LastEvaluatedTime: &latest},
+		{ID: 5, SeriesID: "s:one", UserID: 2, Condition: store.AlertPercentChange, Threshold: 50, WindowDays: 7},
+		// The latest recording is too recent to be checked yet.
+		{ID: 6, SeriesID: "s:recent", UserID: 1, Condition: store.AlertAbove, Threshold: 0},
+	}, nil)
+	alertStore.LatestSeriesPointTimeFunc.SetDefaultHook(func(ctx context.Context, seriesID string) (time.Time, bool, error) {
+		if seriesID == "s:recent" {
+			return now.Add(-time.Minute), true, nil
+		}
+		return latest, true, nil
+	})
+	alertStore.SeriesValueAtFunc.SetDefaultHook(func(ctx context.Context, seriesID string, at time.Time) (float64, bool, error) {
+		if actor.FromContext(ctx).UID == 0 {
+			t.Errorf("expected values to be queried as the user of the rule")
+		}
+		if at.Equal(latest) {
+			return 12, true, nil
+		}
+		return 8, true, nil
+	})
+
+	var notified []int
+	e := &alertEvaluator{
+		alertStore: alertStore,
+		notify: func(ctx context.Context, n alertNotification) error {
+			notified = append(notified, n.Rule.ID)
+			return nil
+		},
+		now: func() time.Time { return now },
+	}
+	if err := e.Handler(context.Background()); err != nil {
+		t.Fatalf("unexpected error evaluating alert rules: %s", err)
+	}
+
+	if want := []int{1, 5}; len(notified) != len(want) || notified[0] != want[0] || notified[1] != want[1] {
+		t.Errorf("unexpected notified rules. want=%v have=%v", want, notified)
+	}
+
+	type evaluation struct {
+		firing, notified bool
+	}
+	want := map[int]evaluation{
+		1: {firing: true, notified: true},
+		2: {},
+		3: {firing: true},
+		5: {firing: true, notified: true},
+	}
+	history := alertStore.RecordAlertEvaluationFunc.History()
+	if len(history) != len(want) {
+		t.Fatalf("unexpected number of recorded evaluations. want=%d have=%d", len(want), len(history))
+	}
+	for _, call := range history {
+		if have := (evaluation{firing: call.Arg3, notified: call.Arg4}); have != want[call.Arg1] {
+			t.Errorf("unexpected evaluation of rule %d. want=%+v have=%+v", call.Arg1, want[call.Arg1], have)
+		}
+		if !call.Arg2.Equal(latest) {
+			t.Errorf("unexpected evaluated time of rule %d. want=%s have=%s", call.Arg1, latest, call.Arg2)
+		}
+	}
+}
+
+func TestAlertEvaluatorNotifyError(t *testing.T) {
+	now := time.Date(2021, 9, 10, 12, 0, 0, 0, time.UTC)
+
+	alertStore := NewMockAlertStore()
+	alertStore.ListAlertRulesFunc.SetDefaultReturn([]store.AlertRule{
+		{ID: 1, SeriesID: "s:one", UserID: 1, Condition: store.AlertAbove, Threshold: 10},
+	}, nil)
+	alertStore.LatestSeriesPointTimeFunc.SetDefaultReturn(now.Add(-time.Hour), true, nil)
+	alertStore.SeriesValueAtFunc.SetDefaultReturn(12, true, nil)
+
+	e := &alertEvaluator{
+		alertStore: alertStore,
+		notify: func(ctx context.Context, n alertNotification) error {
+			return errors.New("connection refused")
+		},
+		now: func() time.Time { return now },
+	}
+	if err := e.Handler(context.Background()); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	// The evaluation is not recorded, so that the notification is retried.
+	if history := alertStore.RecordAlertEvaluationFunc.History(); len(history) != 0 {
+		t.Errorf("unexpected number of recorded evaluations. want=%d have=%d", 0, len(history))
+	}
+}
+
+func TestCheckAlertRulePercentChange(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 9, 10, 0, 0, 0, 0, time.UTC)
+	values := map[time.Time]float64{now: 60, now.AddDate(0, 0, -7): 100}
+
+	alertStore := NewMockAlertStore()
+	alertStore.SeriesValueAtFunc.SetDefaultHook(func(ctx context.Context, seriesID string, at time.Time) (float64, bool, error) {
+		value, ok := values[at]
+		return value, ok, nil
+	})
+
+	for _, testCase := range []struct {
+		name       string
+		threshold  float64
+		windowDays int
+		firing     bool
+	}{
+		{"decrease", -40, 7, true},
+		{"smaller decrease than the threshold", -50, 7, false},
+		{"increase", 10, 7, false},
+		{"no value at the start of the window", -10, 3, false},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			rule := store.AlertRule{SeriesID: "s:one", Condition: store.AlertPercentChange, Threshold: testCase.threshold, WindowDays: testCase.windowDays}
+			_, firing, err := checkAlertRule(ctx, alertStore, rule, now)
+			if err != nil {
+				t.Fatalf("unexpected error checking rule: %s", err)
+			}
+			if firing != testCase.firing {
+				t.Errorf("unexpected firing. want=%v have=%v", testCase.firing, firing)
+			}
+		})
+	}
+}
+
+func TestPostAlertWebhook(t *testing.T) {
+	var payload alertWebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if want := webhookrunner.Sign("secret", body); r.Header.Get(webhookrunner.SignatureHeader) != want {
+			t.Errorf("unexpected signature. want=%q have=%q", want, r.Header.Get(webhookrunner.SignatureHeader))
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("unexpected error decoding payload: %s", err)
+		}
+	}))
+	defer server.Close()
+
+	recordTime := time.Date(2021, 9, 10, 0, 0, 0, 0, time.UTC)
+	n := alertNotification{
+		Rule:  store.AlertRule{ID: 1, SeriesID: "s:one", SeriesLabel: "TODOs", Condition: store.AlertAbove, Threshold: 10},
+		Time:  recordTime,
+		Value: 12.5,
+	}
+	if err := postAlertWebhook(context.Background(), http.DefaultClient, "secret", server.URL, n); err != nil {
+		t.Fatalf("unexpected error posting webhook: %s", err)
+	}
+	if want := "TODOs is 12.5, above 10"; payload.Message != want {
+		t.Errorf("unexpected message. want=%q have=%q", want, payload.Message)
+	}
+	if want := "1:1631232000"; payload.ID != want {
+		t.Errorf("unexpected notification ID. want=%q have=%q", want, payload.ID)
+	}
+}
@@ -0,0 +1,131 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// newInsightBackfiller returns a background goroutine which will periodically look for insight
+// data series that have not been backfilled yet, and enqueue work to generate their historical
+// data. Unlike the historical enqueuer, which sweeps every insight and takes hours to complete,
+// the backfiller only considers new series, so that charts of new insights are populated soon
+// after they are created.
+func newInsightBackfiller(ctx context.Context, workerBaseStore *basestore.Store, insightStore *store.InsightStore, settingStore discovery.SettingStore, insightsStore *store.Store, limiter *rate.Limiter, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_backfiller",
+		metrics.WithCountHelp("Total number of insights backfiller executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "Backfiller.Run",
+		Metrics: metrics,
+	})
+
+	backfiller := &backfiller{
+		seriesStore: insightStore,
+		buildFrames: newHistoricalEnqueuer(workerBaseStore, insightStore, settingStore, insightsStore, limiter).buildFrames,
+	}
+
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, time.Minute, goroutine.NewHandlerWithErrorMessage(
+		"insights_backfiller",
+		backfiller.Handler,
+	), operation)
+}
+
+// BackfillStore is a subset of the API exposed by the store.InsightStore (only the subset used
+// by the backfiller.)
+type BackfillStore interface {
+	GetSeriesToBackfill(ctx context.Context) ([]types.InsightSeries, error)
+	StampBackfill(ctx context.Context, series types.InsightSeries) (types.InsightSeries, error)
+	BusinessDaysOnlySeries(ctx context.Context, seriesIDs []string) (map[string]bool, error)
+}
+
+// backfiller enqueues the work that generates the historical data of new insight data series.
+// It reuses the frames, commit lookups, and rate limit of the historical enqueuer: for every
+// repository and every historical timeframe of a new series, a queryrunner job searching the
+// repository at the commit nearest to the timeframe is enqueued, and its result is recorded at
+// the historical point in time. Timeframes that end before the first commit of a repository are
+// recorded as zero immediately.
+//
+// A series is stamped as backfilled once its work has been enqueued for all repositories, and is
+// not considered again. Gaps that arise later (e.g. for new repositories) are filled by the
+// historical enqueuer.
+type backfiller struct {
+	seriesStore BackfillStore
+	buildFrames func(ctx context.Context, uniqueSeries map[string]insights.TimeSeries, sortedSeriesIDs []string) error
+}
+
+func (b *backfiller) Handler(ctx context.Context) error {
+	newSeries, err := b.seriesStore.GetSeriesToBackfill(ctx)
+	if err != nil {
+		return errors.Wrap(err, "GetSeriesToBackfill")
+	}
+	if len(newSeries) == 0 {
+		return nil
+	}
+
+	// Series share their data if they use the same query, in which case the work is enqueued
+	// only once.
+	var (
+		uniqueSeries    = map[string]insights.TimeSeries{}
+		sortedSeriesIDs []string
+	)
+	for _, series := range newSeries {
+		if series.Webhook != "" {
+			continue // webhook series have no history to backfill
+		}
+		if series.Expression != "" {
+			continue // derived series are computed from the series they are derived from
+		}
+		if _, exists := uniqueSeries[series.SeriesID]; exists {
+			continue
+		}
+		uniqueSeries[series.SeriesID] = insights.TimeSeries{
+			Query:             series.Query,
+			Repositories:      series.Repositories,
+			RepositoryPattern: series.RepositoryPattern,
+		}
+		sortedSeriesIDs = append(sortedSeriesIDs, series.SeriesID)
+	}
+	businessDaysOnly, err := b.seriesStore.BusinessDaysOnlySeries(ctx, sortedSeriesIDs)
+	if err != nil {
+		return errors.Wrap(err, "BusinessDaysOnlySeries")
+	}
+	for seriesID := range businessDaysOnly {
+		if series, ok := uniqueSeries[seriesID]; ok {
+			series.BusinessDaysOnly = true
+			uniqueSeries[seriesID] = series
+		}
+	}
+
+	log15.Info("insights: backfilling new series", "series_ids", sortedSeriesIDs)
+
+	// Walk every repository once for all new series. If the walk is interrupted, the series stay
+	// unstamped and are backfilled again on the next run; frames that have data already are skipped.
+	if err := b.buildFrames(ctx, uniqueSeries, sortedSeriesIDs); err != nil {
+		return errors.Wrap(err, "buildFrames")
+	}
+
+	var multi error
+	for _, series := range newSeries {
+		if _, err := b.seriesStore.StampBackfill(ctx, series); err != nil {
+			multi = multierror.Append(multi, errors.Wrapf(err, "StampBackfill %s", series.SeriesID))
+		}
+	}
+
+	return multi
+}
@@ -0,0 +1,93 @@
+package background
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestBackfiller(t *testing.T) {
+	ctx := context.Background()
+
+	seriesStore := NewMockBackfillStore()
+	seriesStore.GetSeriesToBackfillFunc.SetDefaultReturn([]types.InsightSeries{
+		{ID: 1, SeriesID: "s:1", Query: "errorf"},
+		{ID: 2, SeriesID: "s:2", Query: "fmt.Printf"},
+		{ID: 3, SeriesID: "s:1", Query: "errorf"},
+		{ID: 4, SeriesID: "w:1", Webhook: "https://example.com/getData"},
+	}, nil)
+	seriesStore.BusinessDaysOnlySeriesFunc.SetDefaultReturn(map[string]bool{"s:2": true}, nil)
+
+	var builtSeries map[string]insights.TimeSeries
+	var builtSeriesIDs []string
+	b := &backfiller{
+		seriesStore: seriesStore,
+		buildFrames: func(ctx context.Context, uniqueSeries map[string]insights.TimeSeries, sortedSeriesIDs []string) error {
+			builtSeries, builtSeriesIDs = uniqueSeries, sortedSeriesIDs
+			return nil
+		},
+	}
+
+	if err := b.Handler(ctx); err != nil {
+		t.Fatalf("unexpected error backfilling: %s", err)
+	}
+
+	expectedSeries := map[string]insights.TimeSeries{
+		"s:1": {Query: "errorf"},
+		"s:2": {Query: "fmt.Printf", BusinessDaysOnly: true},
+	}
+	if diff := cmp.Diff(expectedSeries, builtSeries); diff != "" {
+		t.Errorf("unexpected series (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"s:1", "s:2"}, builtSeriesIDs); diff != "" {
+		t.Errorf("unexpected series IDs (-want +got):\n%s", diff)
+	}
+
+	var stamped []int
+	for _, call := range seriesStore.StampBackfillFunc.History() {
+		stamped = append(stamped, call.Arg1.ID)
+	}
+	if diff := cmp.Diff([]int{1, 2, 3, 4}, stamped); diff != "" {
+		t.Errorf("unexpected stamped series (-want +got):\n%s", diff)
+	}
+}
+
+func TestBackfillerInterrupted(t *testing.T) {
+	seriesStore := NewMockBackfillStore()
+	seriesStore.GetSeriesToBackfillFunc.SetDefaultReturn([]types.InsightSeries{{ID: 1, SeriesID: "s:1", Query: "errorf"}}, nil)
+
+	b := &backfiller{
+		seriesStore: seriesStore,
+		buildFrames: func(ctx context.Context, uniqueSeries map[string]insights.TimeSeries, sortedSeriesIDs []string) error {
+			return errors.New("database unavailable")
+		},
+	}
+
+	if err := b.Handler(context.Background()); err == nil {
+		t.Fatalf("expected an error backfilling")
+	}
+	if value := len(seriesStore.StampBackfillFunc.History()); value != 0 {
+		t.Errorf("unexpected number of stamped series. want=%d have=%d", 0, value)
+	}
+}
+
+func TestBackfillerNoSeries(t *testing.T) {
+	seriesStore := NewMockBackfillStore()
+
+	b := &backfiller{
+		seriesStore: seriesStore,
+		buildFrames: func(ctx context.Context, uniqueSeries map[string]insights.TimeSeries, sortedSeriesIDs []string) error {
+			t.Fatalf("unexpected call to buildFrames")
+			return nil
+		},
+	}
+
+	if err := b.Handler(context.Background()); err != nil {
+		t.Fatalf("unexpected error backfilling: %s", err)
+	}
+}
@@ -14,7 +14,12 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/exportrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/importrunner"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/recomputerunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/snapshotrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/webhookrunner"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -35,6 +40,7 @@ func GetBackgroundJobs(ctx context.Context, mainAppDB *sql.DB, insightsDB *sql.D
 	// DB, not the TimescaleDB (which we use only for storing insights data.)
 	workerBaseStore := basestore.NewWithDB(mainAppDB, sql.TxOptions{})
 	settingStore := database.Settings(mainAppDB)
+	insightStore := store.NewInsightStore(insightsDB)
 
 	// Create basic metrics for recording information about background jobs.
 	observationContext := &observation.Context{
@@ -43,33 +49,100 @@ func GetBackgroundJobs(ctx context.Context, mainAppDB *sql.DB, insightsDB *sql.D
 		Registerer: prometheus.DefaultRegisterer,
 	}
 	queryRunnerWorkerMetrics, queryRunnerResetterMetrics := newWorkerMetrics(observationContext, "query_runner_worker")
+	webhookRunnerWorkerMetrics, webhookRunnerResetterMetrics := newWorkerMetrics(observationContext, "webhook_runner_worker")
+	exportRunnerWorkerMetrics, exportRunnerResetterMetrics := newWorkerMetrics(observationContext, "export_runner_worker")
+	snapshotRunnerWorkerMetrics, snapshotRunnerResetterMetrics := newWorkerMetrics(observationContext, "snapshot_runner_worker")
+	importRunnerWorkerMetrics, importRunnerResetterMetrics := newWorkerMetrics(observationContext, "import_runner_worker")
+	recomputeRunnerWorkerMetrics, recomputeRunnerResetterMetrics := newWorkerMetrics(observationContext, "recompute_runner_worker")
 
 	// Start background goroutines for all of our workers.
 	routines := []goroutine.BackgroundRoutine{
 		// Register the background goroutine which discovers and enqueues insights work.
-		newInsightEnqueuer(ctx, workerBaseStore, settingStore, observationContext),
+		newInsightEnqueuer(ctx, workerBaseStore, insightStore, settingStore, insightsStore, observationContext),
 
 		// Register the query-runner worker and resetter, which executes search queries and records
 		// results to TimescaleDB.
-		queryrunner.NewWorker(ctx, workerBaseStore, insightsStore, queryRunnerWorkerMetrics),
+		queryrunner.NewWorker(ctx, workerBaseStore, insightsStore, queryRunnerWorkerMetrics, observationContext),
 		queryrunner.NewResetter(ctx, workerBaseStore, queryRunnerResetterMetrics),
 		queryrunner.NewCleaner(ctx, workerBaseStore, observationContext),
 
-		// TODO(slimsag): future: register another worker here for webhook querying.
+		// Register the webhook-runner worker and resetter, which fetches the data points of
+		// webhook series from their webhooks and records them to TimescaleDB.
+		webhookrunner.NewWorker(ctx, workerBaseStore, insightsStore, webhookRunnerWorkerMetrics),
+		webhookrunner.NewResetter(ctx, workerBaseStore, webhookRunnerResetterMetrics),
+		webhookrunner.NewCleaner(ctx, workerBaseStore, observationContext),
+
+		// Register the export-runner worker and resetter, which generate the exports of insights
+		// too large to be exported right away.
+		exportrunner.NewWorker(ctx, workerBaseStore, insightStore, settingStore, insightsStore, exportRunnerWorkerMetrics),
+		exportrunner.NewResetter(ctx, workerBaseStore, exportRunnerResetterMetrics),
+		exportrunner.NewCleaner(ctx, workerBaseStore, observationContext),
+
+		// Register the snapshot-runner worker and resetter, which compare series between two
+		// points in time.
+		snapshotrunner.NewWorker(ctx, workerBaseStore, insightStore, settingStore, insightsStore, snapshotRunnerWorkerMetrics),
+		snapshotrunner.NewResetter(ctx, workerBaseStore, snapshotRunnerResetterMetrics),
+		snapshotrunner.NewCleaner(ctx, workerBaseStore, observationContext),
+
+		// Register the import-runner worker and resetter, which import data points uploaded by
+		// users into series.
+		importrunner.NewWorker(ctx, workerBaseStore, insightStore, settingStore, insightsStore, importRunnerWorkerMetrics),
+		importrunner.NewResetter(ctx, workerBaseStore, importRunnerResetterMetrics),
+		importrunner.NewCleaner(ctx, workerBaseStore, observationContext),
+
+		// Register the recompute-runner worker and resetter, which delete the data of series and
+		// schedule its recomputation on behalf of site admins.
+		recomputerunner.NewWorker(ctx, workerBaseStore, insightStore, settingStore, insightsStore, recomputeRunnerWorkerMetrics),
+		recomputerunner.NewResetter(ctx, workerBaseStore, recomputeRunnerResetterMetrics),
+		recomputerunner.NewCleaner(ctx, workerBaseStore, observationContext),
 	}
 
 	// todo(insights) add setting to disable this indexer
 	routines = append(routines, compression.NewCommitIndexerWorker(ctx, mainAppDB, insightsDB))
 
-	// Register the background goroutine which discovers historical gaps in data and enqueues
-	// work to fill them - if not disabled.
+	// Register the background goroutines which discover historical gaps in data and enqueue
+	// work to fill them, and which backfill the historical data of new series - if not disabled.
+	// Both share a single rate limit.
 	disableHistorical, _ := strconv.ParseBool(os.Getenv("DISABLE_CODE_INSIGHTS_HISTORICAL"))
 	if !disableHistorical {
-		routines = append(routines, newInsightHistoricalEnqueuer(ctx, workerBaseStore, settingStore, insightsStore, observationContext))
+		limiter := newHistoricalRateLimiter()
+		routines = append(routines,
+			newInsightHistoricalEnqueuer(ctx, workerBaseStore, insightStore, settingStore, insightsStore, limiter, observationContext),
+			newInsightBackfiller(ctx, workerBaseStore, insightStore, settingStore, insightsStore, limiter, observationContext),
+		)
 	}
 
+	// Register the background goroutine which records the language statistics served by language
+	// statistics insights.
+	routines = append(routines, newLanguageStatsRecorder(ctx, mainAppDB, insightsStore, observationContext))
+
+	// Register the background goroutine which downsamples and prunes old data points.
+	routines = append(routines, newRetentionEnforcer(ctx, insightsStore, observationContext))
+
+	// Register the background goroutine which cancels the pending work of the data series no
+	// longer used by any insight, and purges them after a grace period.
+	routines = append(routines, newSeriesCleaner(ctx, workerBaseStore, insightStore, observationContext))
+
+	// Register the background goroutine which retries the queries of data points that could not
+	// be recorded.
+	routines = append(routines, newDirtyQueryRetrier(ctx, workerBaseStore, insightsStore, observationContext))
+
+	// Register the background goroutine which checks the alert rules of series against their new
+	// recordings, and notifies the users of the rules that start firing.
+	routines = append(routines, newAlertEvaluator(ctx, insightsStore, observationContext))
+
+	// Register the background goroutine which computes the data points of derived series from
+	// those of the series they are derived from.
+	routines = append(routines, newDerivedSeriesRecorder(ctx, workerBaseStore, insightStore, settingStore, insightsStore, observationContext))
+
 	routines = append(routines, discovery.NewMigrateSettingInsightsJob(ctx, mainAppDB, insightsDB))
 
+	// Validates the discovered insights without enqueueing anything, and records their problems.
+	routines = append(routines, discovery.NewValidateInsightsJob(ctx, mainAppDB, insightsDB))
+
+	// Re-links the data of series to their new series IDs when the encoding of series IDs changes.
+	routines = append(routines, discovery.NewMigrateSeriesIDsJob(ctx, mainAppDB, insightsDB))
+
 	return routines
 }
 
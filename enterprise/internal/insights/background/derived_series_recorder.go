@@ -0,0 +1,226 @@
+package background
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/insights/expression"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// newDerivedSeriesRecorder returns a background goroutine which will periodically compute the data
+// points of derived series from the data points recorded for the series they are derived from, and
+// record them into the insights store.
+func newDerivedSeriesRecorder(ctx context.Context, workerBaseStore *basestore.Store, insightStore discovery.InsightStore, settingStore discovery.SettingStore, derivedStore DerivedSeriesStore, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_derived_series_recorder",
+		metrics.WithCountHelp("Total number of insights derived series recorder executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "DerivedSeriesRecorder.Run",
+		Metrics: metrics,
+	})
+
+	recorder := &derivedSeriesRecorder{
+		derivedStore: derivedStore,
+		discover: func(ctx context.Context) ([]insights.SearchInsight, error) {
+			return discovery.Discover(ctx, insightStore, settingStore, insights.NewLoader(workerBaseStore.Handle().DB()), discovery.InsightFilterArgs{})
+		},
+		limits: discovery.LicenseLimits,
+		now:    time.Now,
+		failed: map[derivedPoint]struct{}{},
+	}
+
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 1*time.Minute, goroutine.NewHandlerWithErrorMessage(
+		"insights_derived_series_recorder",
+		recorder.Handler,
+	), operation)
+}
+
+// DerivedSeriesStore is a subset of the API exposed by the store.Store (only the subset used by the
+// derived series recorder.)
+type DerivedSeriesStore interface {
+	DerivedSeriesPendingTimes(ctx context.Context, seriesID string, operandSeriesIDs []string, before time.Time) ([]time.Time, error)
+	SeriesTotalsAt(ctx context.Context, seriesID string, times []time.Time) ([]store.SeriesTotal, error)
+	RecordSeriesPoint(ctx context.Context, v store.RecordSeriesPointArgs) error
+}
+
+// derivedSeriesSettleDelay is the time after which the data points recorded for a series are used
+// to compute the series derived from it. The data points of a recording are recorded one
+// repository at a time, so this keeps derived series from being computed from a partial recording.
+const derivedSeriesSettleDelay = 10 * time.Minute
+
+// derivedSeriesRecorder records a data point of each derived series at every time at which any of
+// the series it is derived from was recorded, including backfilled times. The value of each series
+// it is derived from is its value at that time, carrying the last recorded data point of each
+// repository forward.
+//
+// Data points of derived series are recorded without a repository. They aggregate the data points
+// of every repository, so they are only served to users that can see every repository.
+type derivedSeriesRecorder struct {
+	derivedStore DerivedSeriesStore
+	discover     func(ctx context.Context) ([]insights.SearchInsight, error)
+	limits       func() (discovery.InsightLimits, error)
+	now          func() time.Time
+
+	// failed holds the data points that could not be computed, e.g. because they divide by zero,
+	// so that they are not computed again on every run. It is kept in memory only, so they are
+	// computed again after a restart.
+	failed map[derivedPoint]struct{}
+}
+
+type derivedPoint struct {
+	seriesID string
+	time     time.Time
+}
+
+func (r *derivedSeriesRecorder) Handler(ctx context.Context) error {
+	foundInsights, err := r.discover(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Discover")
+	}
+	// Series over the limits of the license are not recorded; the insight enqueuer records them as
+	// failures, as it does invalid series.
+	limits, err := r.limits()
+	if err != nil {
+		return errors.Wrap(err, "LicenseLimits")
+	}
+	foundInsights, _ = discovery.ApplyLimits(foundInsights, limits)
+
+	derived := map[string]*expression.Expr{}
+	for _, insight := range foundInsights {
+		for _, series := range insight.Series {
+			if series.Expression == "" || discovery.ValidateSeries(series) != nil {
+				continue
+			}
+			expr, err := expression.Parse(series.Expression)
+			if err != nil {
+				continue
+			}
+			derived[discovery.Encode(series)] = expr
+		}
+	}
+
+	// A failure to record one series must not prevent recording the others.
+	var multi error
+	for _, seriesID := range derivedSeriesOrder(derived) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.record(ctx, seriesID, derived[seriesID]); err != nil {
+			multi = multierror.Append(multi, errors.Wrapf(err, "derived series %q", seriesID))
+		}
+	}
+	return multi
+}
+
+// derivedSeriesOrder returns the IDs of the given derived series in an order in which every series
+// comes after the derived series it is derived from, so that a single run records them all.
+func derivedSeriesOrder(derived map[string]*expression.Expr) []string {
+	depths := map[string]int{}
+	var depth func(seriesID string) int
+	depth = func(seriesID string) int {
+		if d, ok := depths[seriesID]; ok {
+			return d
+		}
+		// Series IDs are hashes of the expressions that refer to them, so there are no cycles.
+		depths[seriesID] = 0
+		d := 0
+		for _, operand := range derived[seriesID].Operands() {
+			if _, ok := derived[operand.SeriesID]; ok {
+				if operandDepth := depth(operand.SeriesID) + 1; operandDepth > d {
+					d = operandDepth
+				}
+			}
+		}
+		depths[seriesID] = d
+		return d
+	}
+
+	seriesIDs := make([]string, 0, len(derived))
+	for seriesID := range derived {
+		depth(seriesID)
+		seriesIDs = append(seriesIDs, seriesID)
+	}
+	sort.Slice(seriesIDs, func(i, j int) bool {
+		if depths[seriesIDs[i]] != depths[seriesIDs[j]] {
+			return depths[seriesIDs[i]] < depths[seriesIDs[j]]
+		}
+		return seriesIDs[i] < seriesIDs[j]
+	})
+	return seriesIDs
+}
+
+func (r *derivedSeriesRecorder) record(ctx context.Context, seriesID string, expr *expression.Expr) error {
+	operands := expr.Operands()
+	operandSeriesIDs := make([]string, 0, len(operands))
+	for _, operand := range operands {
+		operandSeriesIDs = append(operandSeriesIDs, operand.SeriesID)
+	}
+
+	pending, err := r.derivedStore.DerivedSeriesPendingTimes(ctx, seriesID, operandSeriesIDs, r.now().Add(-derivedSeriesSettleDelay))
+	if err != nil {
+		return errors.Wrap(err, "DerivedSeriesPendingTimes")
+	}
+	times := pending[:0]
+	for _, t := range pending {
+		if _, ok := r.failed[derivedPoint{seriesID: seriesID, time: t.UTC()}]; !ok {
+			times = append(times, t)
+		}
+	}
+	if len(times) == 0 {
+		return nil
+	}
+
+	values := make(map[time.Time]map[string]float64, len(times))
+	approximate := make(map[time.Time]bool, len(times))
+	for _, operandSeriesID := range operandSeriesIDs {
+		totals, err := r.derivedStore.SeriesTotalsAt(ctx, operandSeriesID, times)
+		if err != nil {
+			return errors.Wrapf(err, "SeriesTotalsAt %q", operandSeriesID)
+		}
+		for _, total := range totals {
+			t := total.Time.UTC()
+			if values[t] == nil {
+				values[t] = map[string]float64{}
+			}
+			values[t][operandSeriesID] = total.Value
+			approximate[t] = approximate[t] || total.Approximate
+		}
+	}
+
+	for _, t := range times {
+		t = t.UTC()
+		// Evaluation fails if the value of a series at that time is missing, e.g. because it had
+		// not been recorded yet, or if the expression divides by zero.
+		value, err := expr.Eval(values[t])
+		if err != nil {
+			log15.Debug("insights: failed to compute derived series data point", "seriesID", seriesID, "time", t, "error", err)
+			r.failed[derivedPoint{seriesID: seriesID, time: t}] = struct{}{}
+			continue
+		}
+		if err := r.derivedStore.RecordSeriesPoint(ctx, store.RecordSeriesPointArgs{
+			SeriesID: seriesID,
+			Point: store.SeriesPoint{
+				Time:        t,
+				Value:       value,
+				Approximate: approximate[t],
+			},
+		}); err != nil {
+			return errors.Wrap(err, "RecordSeriesPoint")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package background
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/insights/expression"
+)
+
+func TestDerivedSeriesRecorder(t *testing.T) {
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	t1 := now.Add(-48 * time.Hour)
+	t2 := now.Add(-24 * time.Hour)
+
+	todos := insights.TimeSeries{Name: "TODOs", Query: "TODO"}
+	lines := insights.TimeSeries{Name: "lines", Webhook: "https://example.com/lines"}
+	// Derived series are discovered with their expressions resolved.
+	perKLOC := insights.TimeSeries{Name: "TODOs per KLOC", Expression: "(${" + discovery.Encode(todos) + "} / ${" + discovery.Encode(lines) + "}) * 1000"}
+	doubled := insights.TimeSeries{Name: "doubled", Expression: "${" + discovery.Encode(perKLOC) + "} * 2"}
+	invalid := insights.TimeSeries{Name: "invalid", Expression: "$1 + 1"}
+
+	totals := map[string]map[time.Time]store.SeriesTotal{
+		discovery.Encode(todos): {
+			t1: {Time: t1, Value: 10, Approximate: true},
+			t2: {Time: t2, Value: 20},
+		},
+		discovery.Encode(lines): {
+			t1: {Time: t1, Value: 0},
+			t2: {Time: t2, Value: 4000},
+		},
+	}
+	recorded := map[string][]store.SeriesPoint{}
+
+	derivedStore := NewMockDerivedSeriesStore()
+	derivedStore.DerivedSeriesPendingTimesFunc.SetDefaultHook(func(ctx context.Context, seriesID string, operandSeriesIDs []string, before time.Time) ([]time.Time, error) {
+		if want := now.Add(-derivedSeriesSettleDelay); !before.Equal(want) {
+			t.Errorf("unexpected settle time. want=%s have=%s", want, before)
+		}
+		var pending []time.Time
+		for _, pt := range []time.Time{t1, t2} {
+			if _, ok := totals[seriesID][pt]; !ok {
+				pending = append(pending, pt)
+			}
+		}
+		return pending, nil
+	})
+	derivedStore.SeriesTotalsAtFunc.SetDefaultHook(func(ctx context.Context, seriesID string, times []time.Time) ([]store.SeriesTotal, error) {
+		var result []store.SeriesTotal
+		for _, pt := range times {
+			if total, ok := totals[seriesID][pt]; ok {
+				result = append(result, total)
+			}
+		}
+		return result, nil
+	})
+	derivedStore.RecordSeriesPointFunc.SetDefaultHook(func(ctx context.Context, args store.RecordSeriesPointArgs) error {
+		recorded[args.SeriesID] = append(recorded[args.SeriesID], args.Point)
+		if totals[args.SeriesID] == nil {
+			totals[args.SeriesID] = map[time.Time]store.SeriesTotal{}
+		}
+		totals[args.SeriesID][args.Point.Time] = store.SeriesTotal{Time: args.Point.Time, Value: args.Point.Value, Approximate: args.Point.Approximate}
+		return nil
+	})
+
+	r := &derivedSeriesRecorder{
+		derivedStore: derivedStore,
+		discover: func(ctx context.Context) ([]insights.SearchInsight, error) {
+			return []insights.SearchInsight{
+				{ID: "a", Series: []insights.TimeSeries{doubled, invalid}},
+				{ID: "b", Series: []insights.TimeSeries{todos, lines, perKLOC}},
+			}, nil
+		},
+		limits: func() (discovery.InsightLimits, error) { return discovery.InsightLimits{}, nil },
+		now:    func() time.Time { return now },
+		failed: map[derivedPoint]struct{}{},
+	}
+	if err := r.Handler(context.Background()); err != nil {
+		t.Fatalf("unexpected error recording derived series: %s", err)
+	}
+
+	// The data point of the derived series dividing by zero is not recorded, nor is the data point
+	// derived from it.
+	want := map[string][]store.SeriesPoint{
+		discovery.Encode(perKLOC): {{Time: t2, Value: 5}},
+		discovery.Encode(doubled): {{Time: t2, Value: 10}},
+	}
+	if diff := cmp.Diff(want, recorded); diff != "" {
+		t.Errorf("unexpected recorded points (-want +got):\n%s", diff)
+	}
+
+	// Data points that could not be computed are not computed again.
+	recorded = map[string][]store.SeriesPoint{}
+	if err := r.Handler(context.Background()); err != nil {
+		t.Fatalf("unexpected error recording derived series: %s", err)
+	}
+	if len(recorded) != 0 {
+		t.Errorf("unexpected recorded points on second run: %v", recorded)
+	}
+	if calls := len(derivedStore.SeriesTotalsAtFunc.History()); calls != 3 {
+		t.Errorf("unexpected number of SeriesTotalsAt calls. want=%d have=%d", 3, calls)
+	}
+}
+
+func TestDerivedSeriesOrder(t *testing.T) {
+	parse := func(s string) *expression.Expr {
+		expr, err := expression.Parse(s)
+		if err != nil {
+			t.Fatalf("unexpected error parsing expression: %s", err)
+		}
+		return expr
+	}
+	order := derivedSeriesOrder(map[string]*expression.Expr{
+		"d:c": parse("${d:b} + ${d:a}"),
+		"d:b": parse("${d:a} * 2"),
+		"d:a": parse("${s:x} / ${s:y}"),
+		"d:0": parse("${s:x} - 1"),
+	})
+	if diff := cmp.Diff([]string{"d:0", "d:a", "d:b", "d:c"}, order); diff != "" {
+		t.Errorf("unexpected order (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,143 @@
+package background
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/insights/priority"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// newDirtyQueryRetrier returns a background goroutine which will periodically enqueue work for
+// the query runner worker to retry the queries of data points that could not be recorded, e.g.
+// because the search timed out or repositories were still being cloned.
+func newDirtyQueryRetrier(ctx context.Context, workerBaseStore *basestore.Store, dirtyQueryStore DirtyQueryStore, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_dirty_query_retrier",
+		metrics.WithCountHelp("Total number of insights dirty query retrier executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "DirtyQueryRetrier.Run",
+		Metrics: metrics,
+	})
+
+	retrier := &dirtyQueryRetrier{
+		dirtyQueryStore: dirtyQueryStore,
+		repoCounter:     database.Repos(workerBaseStore.Handle().DB()),
+		enqueueQueryRunnerJob: func(ctx context.Context, job *queryrunner.Job) error {
+			_, err := queryrunner.EnqueueJob(ctx, workerBaseStore, job)
+			return err
+		},
+		now: time.Now,
+	}
+
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 5*time.Minute, goroutine.NewHandlerWithErrorMessage(
+		"insights_dirty_query_retrier",
+		retrier.Handler,
+	), operation)
+}
+
+// DirtyQueryStore is a subset of the API exposed by the store.Store (only the subset used by the
+// dirty query retrier.)
+type DirtyQueryStore interface {
+	DirtyQueriesToRetry(ctx context.Context, maxAttempts, limit int) ([]store.DirtyQuery, error)
+	RecordDirtyQueryAttempt(ctx context.Context, id int, nextAttemptAt time.Time) error
+}
+
+const (
+	// maxDirtyQueryAttempts is the number of retries after which a dirty query is given up on. With
+	// the backoff below, queries are retried for roughly two days.
+	maxDirtyQueryAttempts = 8
+
+	// dirtyQueryRetryDelay is the delay after the first retry of a dirty query, which doubles with
+	// every further retry.
+	dirtyQueryRetryDelay = 10 * time.Minute
+
+	// dirtyQueryBatchSize is the maximum number of dirty queries retried in a single run.
+	dirtyQueryBatchSize = 100
+)
+
+// dirtyQueryRetrier enqueues jobs for dirty queries which are due for a retry. The jobs record
+// their data point at the time of the original data point, and resolve the dirty query once they
+// succeed. Retries back off exponentially until the maximum number of attempts is reached.
+type dirtyQueryRetrier struct {
+	dirtyQueryStore       DirtyQueryStore
+	repoCounter           discovery.RepoCounter
+	enqueueQueryRunnerJob func(ctx context.Context, job *queryrunner.Job) error
+	now                   func() time.Time
+}
+
+func (r *dirtyQueryRetrier) Handler(ctx context.Context) error {
+	dirtyQueries, err := r.dirtyQueryStore.DirtyQueriesToRetry(ctx, maxDirtyQueryAttempts, dirtyQueryBatchSize)
+	if err != nil {
+		return errors.Wrap(err, "DirtyQueriesToRetry")
+	}
+
+	var (
+		multi     error
+		estimator = discovery.NewCostEstimator(r.repoCounter)
+	)
+	for _, dirtyQuery := range dirtyQueries {
+		// The query of a dirty query is already scoped to the repositories of its series.
+		estimateCost := estimator.SeriesCost
+		if dirtyQuery.PinnedRepo != nil {
+			estimateCost = estimator.PinnedRepoCost
+		}
+		cost, err := estimateCost(ctx, insights.TimeSeries{Query: dirtyQuery.Query})
+		if err != nil {
+			multi = multierror.Append(multi, errors.Wrapf(err, "estimating cost of dirty query %d", dirtyQuery.ID))
+			cost = priority.Unindexed
+		}
+
+		forTime := dirtyQuery.ForTime
+		err = r.enqueueQueryRunnerJob(ctx, &queryrunner.Job{
+			SeriesID:    dirtyQuery.SeriesID,
+			SearchQuery: dirtyQuery.Query,
+			RecordTime:  &forTime,
+			State:       "queued",
+			Priority:    int(priority.Low),
+			Cost:        int(cost),
+			// Retries of pinned queries search the same revision as the original query, if it
+			// was resolved.
+			PinnedRepo:     dirtyQuery.PinnedRepo,
+			PinnedRevision: dirtyQuery.PinnedRevision,
+			// Guards against retrying the query twice for the same attempt should recording the
+			// attempt fail.
+			IdempotencyKey: fmt.Sprintf("dirty-query-retrier:%d:%d", dirtyQuery.ID, dirtyQuery.Attempts),
+		})
+		if errors.Is(err, dbworkerstore.ErrQueueFull) {
+			// Back off until the next run; the query runner needs to catch up first.
+			return multierror.Append(multi, err)
+		}
+		if err != nil {
+			multi = multierror.Append(multi, err)
+			continue
+		}
+
+		nextAttemptAt := r.now().Add(dirtyQueryRetryBackoff(dirtyQuery.Attempts))
+		if err := r.dirtyQueryStore.RecordDirtyQueryAttempt(ctx, dirtyQuery.ID, nextAttemptAt); err != nil {
+			multi = multierror.Append(multi, errors.Wrap(err, "RecordDirtyQueryAttempt"))
+		}
+	}
+	return multi
+}
+
+// dirtyQueryRetryBackoff returns the delay after which a dirty query that was retried the given
+// number of times before is retried again, should the current retry fail.
+func dirtyQueryRetryBackoff(attempts int) time.Duration {
+	return dirtyQueryRetryDelay << attempts
+}
@@ -0,0 +1,102 @@
+package background
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/insights/priority"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+func TestDirtyQueryRetrier(t *testing.T) {
+	now := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+	forTime := now.Add(-24 * time.Hour)
+	pinnedRepo := "github.com/sourcegraph/sourcegraph"
+
+	dirtyQueryStore := NewMockDirtyQueryStore()
+	dirtyQueryStore.DirtyQueriesToRetryFunc.SetDefaultReturn([]store.DirtyQuery{
+		{ID: 1, SeriesID: "one", Query: "errorf count:9999999", ForTime: forTime},
+		{ID: 2, SeriesID: "two", Query: "printf count:9999999", ForTime: forTime, Attempts: 2, PinnedRepo: &pinnedRepo},
+	}, nil)
+	repoCounter := discovery.NewMockRepoCounter()
+	repoCounter.CountFunc.SetDefaultReturn(100, nil)
+
+	var enqueued []*queryrunner.Job
+	r := &dirtyQueryRetrier{
+		dirtyQueryStore: dirtyQueryStore,
+		repoCounter:     repoCounter,
+		enqueueQueryRunnerJob: func(ctx context.Context, job *queryrunner.Job) error {
+			enqueued = append(enqueued, job)
+			return nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	if err := r.Handler(context.Background()); err != nil {
+		t.Fatalf("unexpected error retrying dirty queries: %s", err)
+	}
+
+	if len(enqueued) != 2 {
+		t.Fatalf("unexpected number of enqueued jobs. want=%d have=%d", 2, len(enqueued))
+	}
+	for _, job := range enqueued {
+		if job.RecordTime == nil || !job.RecordTime.Equal(forTime) {
+			t.Errorf("unexpected record time of series %q. want=%s have=%v", job.SeriesID, forTime, job.RecordTime)
+		}
+	}
+	// The retry pinned to a single repository out of 100 is much cheaper.
+	if want := int(priority.Indexed); enqueued[0].Cost != want {
+		t.Errorf("unexpected cost of the first query. want=%d have=%d", want, enqueued[0].Cost)
+	}
+	if want := int(priority.Unindexed) / 100; enqueued[1].Cost != want {
+		t.Errorf("unexpected cost of the second query. want=%d have=%d", want, enqueued[1].Cost)
+	}
+
+	history := dirtyQueryStore.RecordDirtyQueryAttemptFunc.History()
+	if len(history) != 2 {
+		t.Fatalf("unexpected number of recorded attempts. want=%d have=%d", 2, len(history))
+	}
+	// Retries back off exponentially.
+	if want := now.Add(10 * time.Minute); !history[0].Arg2.Equal(want) {
+		t.Errorf("unexpected next attempt of the first query. want=%s have=%s", want, history[0].Arg2)
+	}
+	if want := now.Add(40 * time.Minute); !history[1].Arg2.Equal(want) {
+		t.Errorf("unexpected next attempt of the second query. want=%s have=%s", want, history[1].Arg2)
+	}
+}
+
+func TestDirtyQueryRetrierQueueFull(t *testing.T) {
+	dirtyQueryStore := NewMockDirtyQueryStore()
+	dirtyQueryStore.DirtyQueriesToRetryFunc.SetDefaultReturn([]store.DirtyQuery{
+		{ID: 1, SeriesID: "one", Query: "errorf"},
+		{ID: 2, SeriesID: "two", Query: "printf"},
+	}, nil)
+
+	var enqueueCalls int
+	r := &dirtyQueryRetrier{
+		dirtyQueryStore: dirtyQueryStore,
+		repoCounter:     discovery.NewMockRepoCounter(),
+		enqueueQueryRunnerJob: func(ctx context.Context, job *queryrunner.Job) error {
+			enqueueCalls++
+			return dbworkerstore.ErrQueueFull
+		},
+		now: time.Now,
+	}
+
+	if err := r.Handler(context.Background()); !errors.Is(err, dbworkerstore.ErrQueueFull) {
+		t.Fatalf("unexpected error. want=%q have=%q", dbworkerstore.ErrQueueFull, err)
+	}
+	if enqueueCalls != 1 {
+		t.Errorf("unexpected number of enqueue calls. want=%d have=%d", 1, enqueueCalls)
+	}
+	// Attempts are only recorded for enqueued retries.
+	if value := len(dirtyQueryStore.RecordDirtyQueryAttemptFunc.History()); value != 0 {
+		t.Errorf("unexpected number of recorded attempts. want=%d have=%d", 0, value)
+	}
+}
@@ -0,0 +1,157 @@
+package exportrunner
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// The formats insights can be exported in.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// MaxInlineSeries is the maximum number of series of an insight that is exported right away when
+// the export is requested. The exports of insights with more series are generated by the export
+// runner worker in the background.
+const MaxInlineSeries = 10
+
+// ExportStore is a subset of the API exposed by the store.Store (only the subset used by the
+// export runner.)
+type ExportStore interface {
+	SeriesPoints(ctx context.Context, opts store.SeriesPointsOpts) ([]store.SeriesPoint, error)
+	CaptureValues(ctx context.Context, seriesID string) ([]string, error)
+}
+
+// ValidFormat reports whether insights can be exported in the given format.
+func ValidFormat(format string) bool {
+	return format == FormatCSV || format == FormatJSON
+}
+
+// exportedInsight is the JSON representation of an exported insight.
+type exportedInsight struct {
+	ID          string           `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Series      []exportedSeries `json:"series"`
+}
+
+// exportedSeries is the JSON representation of a series of an exported insight. Series generated
+// from capture groups are exported as one series per captured value, labeled with that value.
+type exportedSeries struct {
+	SeriesID string          `json:"seriesId"`
+	Label    string          `json:"label"`
+	Query    string          `json:"query"`
+	Points   []exportedPoint `json:"points"`
+}
+
+// exportedPoint is the JSON representation of a data point of an exported series.
+type exportedPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Export returns all the data points of all the series of the given insight in the given format.
+//
+// 🚨 SECURITY: The data points are aggregated over the repositories the actor of the given context
+// can access, so exports must be generated as the user who requested them.
+func Export(ctx context.Context, exportStore ExportStore, insight insights.SearchInsight, format string) ([]byte, error) {
+	if !ValidFormat(format) {
+		return nil, errors.Errorf("unsupported export format %q", format)
+	}
+
+	exported := exportedInsight{
+		ID:          insight.ID,
+		Title:       insight.Title,
+		Description: insight.Description,
+		Series:      []exportedSeries{},
+	}
+	for _, series := range insight.Series {
+		seriesID := discovery.Encode(series)
+		if !series.Split() {
+			points, err := seriesPoints(ctx, exportStore, seriesID, nil)
+			if err != nil {
+				return nil, err
+			}
+			exported.Series = append(exported.Series, exportedSeries{SeriesID: seriesID, Label: series.Name, Query: series.Query, Points: points})
+			continue
+		}
+
+		values, err := exportStore.CaptureValues(ctx, seriesID)
+		if err != nil {
+			return nil, errors.Wrap(err, "CaptureValues")
+		}
+		for _, value := range values {
+			value := value
+			points, err := seriesPoints(ctx, exportStore, seriesID, &value)
+			if err != nil {
+				return nil, err
+			}
+			exported.Series = append(exported.Series, exportedSeries{SeriesID: seriesID, Label: value, Query: series.Query, Points: points})
+		}
+	}
+
+	if format == FormatJSON {
+		return json.Marshal(exported)
+	}
+	return marshalCSV(exported)
+}
+
+// seriesPoints returns the data points of the given series in chronological order. If capture is
+// non-nil, only the points recorded for the captured value are returned.
+func seriesPoints(ctx context.Context, exportStore ExportStore, seriesID string, capture *string) ([]exportedPoint, error) {
+	points, err := exportStore.SeriesPoints(ctx, store.SeriesPointsOpts{SeriesID: &seriesID, Capture: capture})
+	if err != nil {
+		return nil, errors.Wrap(err, "SeriesPoints")
+	}
+	exported := make([]exportedPoint, 0, len(points))
+	for _, point := range points {
+		exported = append(exported, exportedPoint{Time: point.Time.UTC(), Value: point.Value})
+	}
+	sort.SliceStable(exported, func(i, j int) bool {
+		return exported[i].Time.Before(exported[j].Time)
+	})
+	return exported, nil
+}
+
+// csvHeader is the header row of CSV exports. Each following row holds a single data point.
+var csvHeader = []string{"insight_id", "insight_title", "series_id", "series_label", "query", "time", "value"}
+
+func marshalCSV(insight exportedInsight) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, series := range insight.Series {
+		for _, point := range series.Points {
+			if err := w.Write([]string{
+				insight.ID,
+				insight.Title,
+				series.SeriesID,
+				series.Label,
+				series.Query,
+				point.Time.Format(time.RFC3339),
+				strconv.FormatFloat(point.Value, 'f', -1, 64),
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
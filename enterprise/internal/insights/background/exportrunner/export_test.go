@@ -0,0 +1,82 @@
+package exportrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	insight := insights.SearchInsight{
+		ID:          "insight",
+		Title:       "Errors",
+		Description: "Errors, by kind",
+		Series: []insights.TimeSeries{
+			{Name: "all", Query: "errors.New"},
+			{Name: "kinds", Query: `errors\.(\w+)\(`, GeneratedFromCaptureGroups: true},
+		},
+	}
+	allID := discovery.Encode(insight.Series[0])
+	kindsID := discovery.Encode(insight.Series[1])
+
+	exportStore := NewMockExportStore()
+	exportStore.CaptureValuesFunc.SetDefaultHook(func(ctx context.Context, seriesID string) ([]string, error) {
+		if seriesID != kindsID {
+			t.Errorf("unexpected capture values series. want=%q have=%q", kindsID, seriesID)
+		}
+		return []string{"New", "Wrap"}, nil
+	})
+	exportStore.SeriesPointsFunc.SetDefaultHook(func(ctx context.Context, opts store.SeriesPointsOpts) ([]store.SeriesPoint, error) {
+		switch {
+		case *opts.SeriesID == allID:
+			// Points are returned most recent first.
+			return []store.SeriesPoint{{Time: day.Add(24 * time.Hour), Value: 3}, {Time: day, Value: 1.5}}, nil
+		case opts.Capture != nil && *opts.Capture == "Wrap":
+			return []store.SeriesPoint{{Time: day, Value: 2}}, nil
+		}
+		return nil, nil
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		data, err := Export(ctx, exportStore, insight, FormatCSV)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := "insight_id,insight_title,series_id,series_label,query,time,value\n" +
+			"insight,Errors," + allID + ",all,errors.New,2021-09-01T00:00:00Z,1.5\n" +
+			"insight,Errors," + allID + ",all,errors.New,2021-09-02T00:00:00Z,3\n" +
+			"insight,Errors," + kindsID + `,Wrap,errors\.(\w+)\(,2021-09-01T00:00:00Z,2` + "\n"
+		if diff := cmp.Diff(want, string(data)); diff != "" {
+			t.Errorf("unexpected export (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, err := Export(ctx, exportStore, insight, FormatJSON)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := `{"id":"insight","title":"Errors","description":"Errors, by kind","series":[` +
+			`{"seriesId":"` + allID + `","label":"all","query":"errors.New","points":[{"time":"2021-09-01T00:00:00Z","value":1.5},{"time":"2021-09-02T00:00:00Z","value":3}]},` +
+			`{"seriesId":"` + kindsID + `","label":"New","query":"errors\\.(\\w+)\\(","points":[]},` +
+			`{"seriesId":"` + kindsID + `","label":"Wrap","query":"errors\\.(\\w+)\\(","points":[{"time":"2021-09-01T00:00:00Z","value":2}]}]}`
+		if diff := cmp.Diff(want, string(data)); diff != "" {
+			t.Errorf("unexpected export (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		if _, err := Export(ctx, exportStore, insight, "xml"); err == nil {
+			t.Fatal("expected error exporting in an unsupported format")
+		}
+	})
+}
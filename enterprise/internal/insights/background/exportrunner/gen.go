@@ -0,0 +1,3 @@
+package exportrunner
+
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/exportrunner -i ExportStore -o mock_export_store.go
@@ -0,0 +1,77 @@
+package exportrunner
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+var _ workerutil.Handler = &workHandler{}
+
+// workHandler implements the dbworker.Handler interface by generating the exports of insights and
+// storing them on their jobs.
+type workHandler struct {
+	workerBaseStore *basestore.Store
+	insightStore    discovery.InsightStore
+	settingStore    discovery.SettingStore
+	exportStore     ExportStore
+}
+
+func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err error) {
+	defer func() {
+		if err != nil {
+			log15.Error("insights.exportrunner.workHandler", "error", err)
+		}
+	}()
+
+	// Dequeue the job to get information about it, like what insight to export.
+	job, err := dequeueJob(ctx, r.workerBaseStore, record.RecordID())
+	if err != nil {
+		return err
+	}
+
+	// 🚨 SECURITY: Generate the export as the user who requested it, so that it only holds the
+	// insights and data points visible to them.
+	ctx = actor.WithActor(ctx, actor.FromUser(job.UserID))
+	namespaces, err := discovery.VisibleNamespaces(ctx, r.workerBaseStore.Handle().DB())
+	if err != nil {
+		return errors.Wrap(err, "VisibleNamespaces")
+	}
+	discovered, err := discovery.Discover(ctx, r.insightStore, r.settingStore, insights.NewLoader(r.workerBaseStore.Handle().DB()), discovery.InsightFilterArgs{
+		Ids:        []string{job.InsightID},
+		Namespaces: namespaces,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Discover")
+	}
+	insight, ok := findInsight(discovered, job.InsightID)
+	if !ok {
+		// The insight was deleted, or is no longer visible to the user since the export was
+		// requested. Retrying would not change that.
+		return errcode.MakeNonRetryable(errors.Errorf("insight %q not found", job.InsightID))
+	}
+
+	data, err := Export(ctx, r.exportStore, insight, job.Format)
+	if err != nil {
+		return err
+	}
+	return setJobData(ctx, r.workerBaseStore, job.ID, data)
+}
+
+// findInsight returns the insight with the given ID among the given insights.
+func findInsight(discovered []insights.SearchInsight, id string) (insights.SearchInsight, bool) {
+	for _, insight := range discovered {
+		if insight.ID == id {
+			return insight, true
+		}
+	}
+	return insights.SearchInsight{}, false
+}
@@ -0,0 +1,262 @@
+package exportrunner
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// This file contains all the methods required to:
+//
+// 1. Create the export runner worker
+// 2. Enqueue jobs for the export runner to execute.
+// 3. Dequeue jobs from the export runner.
+// 4. Serialize jobs for the export runner into the DB.
+//
+
+// NewWorker returns a worker that will generate the exports of insights too large to be exported
+// right away, and store them alongside the job for the user who requested them.
+func NewWorker(ctx context.Context, workerBaseStore *basestore.Store, insightStore discovery.InsightStore, settingStore discovery.SettingStore, exportStore ExportStore, metrics workerutil.WorkerMetrics) *workerutil.Worker {
+	workerStore := createDBWorkerStore(workerBaseStore)
+
+	options := workerutil.WorkerOptions{
+		Name:              "insights_export_runner_worker",
+		NumHandlers:       1,
+		Interval:          5 * time.Second,
+		HeartbeatInterval: 15 * time.Second,
+		Metrics:           metrics,
+	}
+
+	return dbworker.NewWorker(ctx, workerStore, &workHandler{
+		workerBaseStore: workerBaseStore,
+		insightStore:    insightStore,
+		settingStore:    settingStore,
+		exportStore:     exportStore,
+	}, options)
+}
+
+// NewResetter returns a resetter that will reset pending export runner jobs if they take too long
+// to complete.
+func NewResetter(ctx context.Context, workerBaseStore *basestore.Store, metrics dbworker.ResetterMetrics) *dbworker.Resetter {
+	workerStore := createDBWorkerStore(workerBaseStore)
+	options := dbworker.ResetterOptions{
+		Name:     "insights_export_runner_worker_resetter",
+		Interval: 1 * time.Minute,
+		Metrics:  metrics,
+	}
+	return dbworker.NewResetter(workerStore, options)
+}
+
+var workerStoreOptions = dbworkerstore.Options{
+	Name:              "insights_export_runner_jobs_store",
+	TableName:         "insights_export_jobs",
+	ColumnExpressions: jobsColumns,
+	Scan:              scanJobs,
+
+	// Exports read every data point of every series of an insight, which may take a while for
+	// insights with many series.
+	StalledMaxAge:     5 * time.Minute,
+	RetryAfter:        1 * time.Minute,
+	MaxNumRetries:     3,
+	OrderByExpression: sqlf.Sprintf("id"),
+}
+
+// createDBWorkerStore creates the dbworker store for the export runner worker.
+//
+// See internal/workerutil/dbworker for more information about dbworkers.
+func createDBWorkerStore(s *basestore.Store) dbworkerstore.Store {
+	return dbworkerstore.New(s.Handle(), workerStoreOptions)
+}
+
+// EnqueueJob enqueues a job for the export runner worker to execute later. Exports generated right
+// away are stored by enqueuing a job in the "completed" state along with its data, so that they are
+// retrieved the same way as the exports generated by the worker.
+func EnqueueJob(ctx context.Context, workerBaseStore *basestore.Store, job *Job) (id int, err error) {
+	id, _, err = basestore.ScanFirstInt(workerBaseStore.Query(
+		ctx,
+		sqlf.Sprintf(
+			enqueueJobFmtStr,
+			job.InsightID,
+			job.Format,
+			job.UserID,
+			job.Data,
+			job.State,
+			job.FinishedAt,
+			job.ProcessAfter,
+		),
+	))
+	return
+}
+
+const enqueueJobFmtStr = `
+-- source: enterprise/internal/insights/background/exportrunner/worker.go:EnqueueJob
+INSERT INTO insights_export_jobs (
+	insight_id,
+	format,
+	user_id,
+	data,
+	state,
+	finished_at,
+	process_after
+) VALUES (%s, %s, %s, %s, %s, %s, %s)
+RETURNING id
+`
+
+// GetJob returns the export job with the given ID, if it exists.
+func GetJob(ctx context.Context, workerBaseStore *basestore.Store, id int) (*Job, bool, error) {
+	rows, err := workerBaseStore.Query(ctx, sqlf.Sprintf(getJobFmtStr, id))
+	if err != nil {
+		return nil, false, err
+	}
+	jobs, err := doScanJobs(rows, nil)
+	if err != nil || len(jobs) == 0 {
+		return nil, false, err
+	}
+	return jobs[0], true, nil
+}
+
+func dequeueJob(ctx context.Context, workerBaseStore *basestore.Store, recordID int) (*Job, error) {
+	job, ok, err := GetJob(ctx, workerBaseStore, recordID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.Errorf("expected 1 job to dequeue, found 0")
+	}
+	return job, nil
+}
+
+const getJobFmtStr = `
+-- source: enterprise/internal/insights/background/exportrunner/worker.go:GetJob
+SELECT
+	insight_id,
+	format,
+	user_id,
+	data,
+	id,
+	state,
+	failure_message,
+	started_at,
+	finished_at,
+	process_after,
+	num_resets,
+	num_failures,
+	execution_logs
+FROM insights_export_jobs
+WHERE id = %s;
+`
+
+// setJobData stores the generated export on the job with the given ID.
+func setJobData(ctx context.Context, workerBaseStore *basestore.Store, id int, data []byte) error {
+	return workerBaseStore.Exec(ctx, sqlf.Sprintf(setJobDataFmtStr, data, id))
+}
+
+const setJobDataFmtStr = `
+-- source: enterprise/internal/insights/background/exportrunner/worker.go:setJobData
+UPDATE insights_export_jobs SET data = %s WHERE id = %s
+`
+
+// Job represents a single job for the export runner worker to perform. When enqueued, it is stored
+// in the insights_export_jobs table - then the worker dequeues it by reading it from that table.
+//
+// See internal/workerutil/dbworker for more information about dbworkers.
+type Job struct {
+	// Export runner fields.
+	InsightID string
+	Format    string // FormatCSV or FormatJSON
+	UserID    int32  // The user who requested the export, who the export is generated for.
+	Data      []byte // The generated export, once completed.
+
+	// Standard/required dbworker fields. If enqueuing a job, these may all be zero values except State.
+	ID             int
+	State          string // If enqueing a job, set to "queued"
+	FailureMessage *string
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+	ProcessAfter   *time.Time
+	NumResets      int32
+	NumFailures    int32
+	ExecutionLogs  []workerutil.ExecutionLogEntry
+}
+
+// Implements the internal/workerutil.Record interface, used by the work handler to locate the job
+// once executing (see work_handler.go:Handle).
+func (j *Job) RecordID() int {
+	return j.ID
+}
+
+func scanJobs(rows *sql.Rows, err error) (workerutil.Record, bool, error) {
+	records, err := doScanJobs(rows, err)
+	if err != nil {
+		return &Job{}, false, err
+	}
+	return records[0], true, nil
+}
+
+func doScanJobs(rows *sql.Rows, err error) ([]*Job, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+	var jobs []*Job
+	for rows.Next() {
+		j := &Job{}
+		if err := rows.Scan(
+			// Export runner fields.
+			&j.InsightID,
+			&j.Format,
+			&j.UserID,
+			&j.Data,
+
+			// Standard/required dbworker fields.
+			&j.ID,
+			&j.State,
+			&j.FailureMessage,
+			&j.StartedAt,
+			&j.FinishedAt,
+			&j.ProcessAfter,
+			&j.NumResets,
+			&j.NumFailures,
+			pq.Array(&j.ExecutionLogs),
+		); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// jobsColumns omits the data of exports, which is not needed by the worker until it handles the
+// job, and then only written.
+var jobsColumns = []*sqlf.Query{
+	sqlf.Sprintf("insights_export_jobs.insight_id"),
+	sqlf.Sprintf("insights_export_jobs.format"),
+	sqlf.Sprintf("insights_export_jobs.user_id"),
+	sqlf.Sprintf("NULL::bytea"),
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("state"),
+	sqlf.Sprintf("failure_message"),
+	sqlf.Sprintf("started_at"),
+	sqlf.Sprintf("finished_at"),
+	sqlf.Sprintf("process_after"),
+	sqlf.Sprintf("num_resets"),
+	sqlf.Sprintf("num_failures"),
+	sqlf.Sprintf("execution_logs"),
+}
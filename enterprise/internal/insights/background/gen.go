@@ -1,3 +1,10 @@
 package background
 
 //go:generate ../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background -i RepoStore -o mock_repo_store.go
+//go:generate ../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background -i BackfillStore -o mock_backfill_store.go
+//go:generate ../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background -i LanguageStatsStore -o mock_language_stats_store.go
+//go:generate ../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background -i RetentionStore -o mock_retention_store.go
+//go:generate ../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background -i DirtyQueryStore -o mock_dirty_query_store.go
+//go:generate ../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background -i SeriesCleanerStore -o mock_series_cleaner_store.go
+//go:generate ../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background -i AlertStore -o mock_alert_store.go
+//go:generate ../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background -i DerivedSeriesStore -o mock_derived_series_store.go
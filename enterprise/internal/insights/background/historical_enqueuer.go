@@ -3,7 +3,6 @@ package background
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -69,7 +68,7 @@ import (
 // insights across all user settings, and determine for which dates they do not have data and attempt
 // to backfill them by enqueueing work for executing searches with `before:` and `after:` filter
 // ranges.
-func newInsightHistoricalEnqueuer(ctx context.Context, workerBaseStore *basestore.Store, settingStore discovery.SettingStore, insightsStore *store.Store, observationContext *observation.Context) goroutine.BackgroundRoutine {
+func newInsightHistoricalEnqueuer(ctx context.Context, workerBaseStore *basestore.Store, insightStore discovery.InsightStore, settingStore discovery.SettingStore, insightsStore *store.Store, limiter *rate.Limiter, observationContext *observation.Context) goroutine.BackgroundRoutine {
 	metrics := metrics.NewOperationMetrics(
 		observationContext.Registerer,
 		"insights_historical_enqueuer",
@@ -80,6 +79,22 @@ func newInsightHistoricalEnqueuer(ctx context.Context, workerBaseStore *basestor
 		Metrics: metrics,
 	})
 
+	historicalEnqueuer := newHistoricalEnqueuer(workerBaseStore, insightStore, settingStore, insightsStore, limiter)
+
+	// We use a periodic goroutine here just for metrics tracking. We specify 5s here so it runs as
+	// fast as possible without wasting CPU cycles, but in reality the handler itself can take
+	// minutes to hours to complete as it intentionally enqueues work slowly to avoid putting
+	// pressure on the system.
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 15*time.Minute, goroutine.NewHandlerWithErrorMessage(
+		"insights_historical_enqueuer",
+		historicalEnqueuer.Handler,
+	), operation)
+}
+
+// newHistoricalRateLimiter returns the rate limiter shared by all goroutines enqueueing work for
+// historical data, which is kept in sync with the insights.historical.worker.rateLimit site
+// configuration.
+func newHistoricalRateLimiter() *rate.Limiter {
 	defaultRateLimit := rate.Limit(10.0)
 	getRateLimit := getRateLimit(defaultRateLimit)
 
@@ -91,6 +106,13 @@ func newInsightHistoricalEnqueuer(ctx context.Context, workerBaseStore *basestor
 		limiter.SetLimit(val)
 	})
 
+	return limiter
+}
+
+// newHistoricalEnqueuer returns a historicalEnqueuer configured by the site configuration. Each
+// goroutine must use its own historicalEnqueuer, as the repository iterator is not safe for
+// concurrent use.
+func newHistoricalEnqueuer(workerBaseStore *basestore.Store, insightStore discovery.InsightStore, settingStore discovery.SettingStore, insightsStore *store.Store, limiter *rate.Limiter) *historicalEnqueuer {
 	repoStore := database.Repos(workerBaseStore.Handle().DB())
 
 	framesToBackfill := func() int {
@@ -117,6 +139,7 @@ func newInsightHistoricalEnqueuer(ctx context.Context, workerBaseStore *basestor
 
 	historicalEnqueuer := &historicalEnqueuer{
 		now:           time.Now,
+		insightStore:  insightStore,
 		settingStore:  settingStore,
 		insightsStore: insightsStore,
 		loader:        insights.NewLoader(repoStore.Handle().DB()),
@@ -126,8 +149,9 @@ func newInsightHistoricalEnqueuer(ctx context.Context, workerBaseStore *basestor
 			_, err := queryrunner.EnqueueJob(ctx, workerBaseStore, job)
 			return err
 		},
-		gitFirstEverCommit:   (&cachedGitFirstEverCommit{impl: git.FirstEverCommit}).gitFirstEverCommit,
-		gitFindNearestCommit: git.FindNearestCommit,
+		gitFirstEverCommit: (&cachedGitFirstEverCommit{impl: git.FirstEverCommit}).gitFirstEverCommit,
+		limits:             discovery.LicenseLimits,
+		calendar:           discovery.SiteRecordingCalendar,
 
 		// Fill e.g. the last 52 weeks of data, recording 1 point per week.
 		framesToBackfill: framesToBackfill,
@@ -147,14 +171,7 @@ func newInsightHistoricalEnqueuer(ctx context.Context, workerBaseStore *basestor
 		}).ForEach,
 	}
 
-	// We use a periodic goroutine here just for metrics tracking. We specify 5s here so it runs as
-	// fast as possible without wasting CPU cycles, but in reality the handler itself can take
-	// minutes to hours to complete as it intentionally enqueues work slowly to avoid putting
-	// pressure on the system.
-	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 15*time.Minute, goroutine.NewHandlerWithErrorMessage(
-		"insights_historical_enqueuer",
-		historicalEnqueuer.Handler,
-	), operation)
+	return historicalEnqueuer
 }
 
 func getRateLimit(defaultValue rate.Limit) func() rate.Limit {
@@ -176,6 +193,7 @@ func getRateLimit(defaultValue rate.Limit) func() rate.Limit {
 // historicalEnqueuer.)
 type RepoStore interface {
 	GetByName(ctx context.Context, name api.RepoName) (*types.Repo, error)
+	Count(ctx context.Context, opt database.ReposListOptions) (int, error)
 }
 
 // historicalEnqueuer effectively enqueues jobs that generate historical data for insights. Right
@@ -214,14 +232,16 @@ type RepoStore interface {
 type historicalEnqueuer struct {
 	// Required fields used for mocking in tests.
 	now                   func() time.Time
+	insightStore          discovery.InsightStore
 	settingStore          discovery.SettingStore
 	insightsStore         store.Interface
 	loader                insights.Loader
 	repoStore             RepoStore
 	enqueueQueryRunnerJob func(ctx context.Context, job *queryrunner.Job) error
 	gitFirstEverCommit    func(ctx context.Context, repoName api.RepoName) (*git.Commit, error)
-	gitFindNearestCommit  func(ctx context.Context, repoName api.RepoName, revSpec string, target time.Time) (*git.Commit, error)
 	frameFilter           compression.DataFrameFilter
+	limits                func() (discovery.InsightLimits, error)
+	calendar              func() (insights.RecordingCalendar, error)
 
 	// framesToBackfill describes the number of historical timeframes to backfill data for.
 	framesToBackfill func() int
@@ -236,13 +256,21 @@ type historicalEnqueuer struct {
 
 func (h *historicalEnqueuer) Handler(ctx context.Context) error {
 	// Discover all insights on the instance.
-	foundInsights, err := discovery.Discover(ctx, h.settingStore, h.loader, discovery.InsightFilterArgs{})
+	foundInsights, err := discovery.Discover(ctx, h.insightStore, h.settingStore, h.loader, discovery.InsightFilterArgs{})
 	if err != nil {
 		return errors.Wrap(err, "Discover")
 	}
+	// Insights and series over the limits of the license are not backfilled either; the insight
+	// enqueuer records them as failures.
+	limits, err := h.limits()
+	if err != nil {
+		return errors.Wrap(err, "LicenseLimits")
+	}
+	foundInsights, _ = discovery.ApplyLimits(foundInsights, limits)
 
 	// Deduplicate series that may be unique (e.g. different name/description) but do not have
-	// unique data (i.e. use the same exact search query or webhook URL.)
+	// unique data (i.e. use the same exact search query or webhook URL.) Such series are backfilled
+	// on business days only if all of them are.
 	var (
 		uniqueSeries    = map[string]insights.TimeSeries{}
 		sortedSeriesIDs []string
@@ -250,13 +278,31 @@ func (h *historicalEnqueuer) Handler(ctx context.Context) error {
 	)
 	for _, insight := range foundInsights {
 		for _, series := range insight.Series {
+			if series.Webhook != "" {
+				// Webhooks are only asked for the data points of the present; there is no
+				// repository history to derive their past data points from.
+				continue
+			}
+			if series.Expression != "" {
+				// Derived series are computed from the backfilled data points of the series
+				// they are derived from by the derived series recorder.
+				continue
+			}
+			if err := discovery.ValidateSeries(series); err != nil {
+				multi = multierror.Append(multi, errors.Wrapf(err, "series %q of insight %q", series.Name, insight.ID))
+				continue
+			}
 			seriesID := discovery.Encode(series)
 			if err != nil {
 				multi = multierror.Append(multi, err)
 				continue
 			}
-			_, exists := uniqueSeries[seriesID]
+			existing, exists := uniqueSeries[seriesID]
 			if exists {
+				if !series.BusinessDaysOnly {
+					existing.BusinessDaysOnly = false
+					uniqueSeries[seriesID] = existing
+				}
 				continue
 			}
 			uniqueSeries[seriesID] = series
@@ -281,14 +327,81 @@ func (h *historicalEnqueuer) buildFrames(ctx context.Context, uniqueSeries map[s
 	}
 	var multi error
 
+	// Every job searches a single repository at a historical revision, so the cost of the jobs of
+	// a series only needs to be estimated once.
+	var (
+		estimator = discovery.NewCostEstimator(h.repoStore)
+		costs     = make(map[string]priority.Cost, len(uniqueSeries))
+	)
+	for _, seriesID := range sortedSeriesIDs {
+		cost, err := estimator.PinnedRepoCost(ctx, uniqueSeries[seriesID])
+		if err != nil {
+			log15.Warn("insights: failed to estimate cost of historical series jobs", "series_id", seriesID, "error", err)
+			cost = priority.Unindexed
+		}
+		costs[seriesID] = cost
+	}
+
 	frames := Frames(h.framesToBackfill(), h.frameLength(), h.now())
+	calendar, err := h.calendar()
+	if err != nil {
+		return errors.Wrap(err, "SiteRecordingCalendar")
+	}
 
-	hardErr := h.allReposIterator(ctx, h.buildForRepo(ctx, uniqueSeries, sortedSeriesIDs, frames, multi))
-	return hardErr
+	// Resume the series whose previous pass was interrupted (e.g. by a deploy) where they stopped.
+	checkpoints, err := h.insightsStore.BackfillCheckpoints(ctx, sortedSeriesIDs)
+	if err != nil {
+		return errors.Wrap(err, "BackfillCheckpoints")
+	}
+
+	hardErr := h.allReposIterator(ctx, h.buildForRepo(ctx, uniqueSeries, sortedSeriesIDs, costs, frames, calendar, checkpoints, multi))
+	if hardErr != nil {
+		return hardErr
+	}
+
+	// The pass over all repositories is complete, so the next pass starts over from the first
+	// repository to pick up new repositories and timeframes.
+	return h.insightsStore.ClearBackfillCheckpoints(ctx, sortedSeriesIDs)
 }
 
-func (h *historicalEnqueuer) buildForRepo(ctx context.Context, uniqueSeries map[string]insights.TimeSeries, sortedSeriesIDs []string, frames []compression.Frame, softErr error) func(repoName string) error {
+// buildForRepo returns the function invoked by the repository iterator to build historical data
+// for each repository.
+//
+// The repository and timeframe of every series are checkpointed as work is done. The series with a
+// checkpoint skip the repositories up to the one of their checkpoint, and the timeframes already
+// done in it; this relies on the repository iterator visiting repositories in a stable order.
+func (h *historicalEnqueuer) buildForRepo(ctx context.Context, uniqueSeries map[string]insights.TimeSeries, sortedSeriesIDs []string, costs map[string]priority.Cost, frames []compression.Frame, calendar insights.RecordingCalendar, checkpoints map[string]store.BackfillCheckpoint, softErr error) func(repoName string) error {
+	// resuming holds the checkpoints of the series that have not reached the repository of their
+	// checkpoint yet.
+	resuming := make(map[string]store.BackfillCheckpoint, len(checkpoints))
+	for seriesID, checkpoint := range checkpoints {
+		resuming[seriesID] = checkpoint
+	}
+	checkpoint := func(seriesID, repoName string, frameFrom time.Time) {
+		if err := h.insightsStore.SaveBackfillCheckpoint(ctx, store.BackfillCheckpoint{
+			SeriesID:  seriesID,
+			RepoName:  repoName,
+			FrameFrom: frameFrom,
+		}); err != nil {
+			// The backfill can go on: the work done since the previous checkpoint is only skipped
+			// when it is resumed, not redone.
+			log15.Error("insights: failed to save backfill checkpoint", "series_id", seriesID, "error", err)
+		}
+	}
+
 	return func(repoName string) error {
+		// Skip the repository before looking it up if every series already backfilled it.
+		var pending bool
+		for _, seriesID := range sortedSeriesIDs {
+			if c, ok := resuming[seriesID]; !ok || c.RepoName == repoName {
+				pending = true
+				break
+			}
+		}
+		if !pending {
+			return nil
+		}
+
 		// Lookup the repository (we need its database ID)
 		repo, err := h.repoStore.GetByName(ctx, api.RepoName(repoName))
 		if err != nil {
@@ -323,9 +436,25 @@ func (h *historicalEnqueuer) buildForRepo(ctx context.Context, uniqueSeries map[
 		// For every series that we want to potentially gather historical data for, try.
 		for _, seriesID := range sortedSeriesIDs {
 			series := uniqueSeries[seriesID]
+			if !discovery.InRepositoryScope(series, repoName) {
+				// The series does not search this repository, so it has no history in it.
+				continue
+			}
+
+			var resumeBefore *time.Time
+			if c, ok := resuming[seriesID]; ok {
+				if c.RepoName != repoName {
+					continue // backfilled by the interrupted pass
+				}
+				delete(resuming, seriesID)
+				resumeBefore = &c.FrameFrom
+			}
 
 			for i := len(filtered) - 1; i >= 0; i-- {
 				currentFrame := filtered[i]
+				if resumeBefore != nil && !currentFrame.From.Before(*resumeBefore) {
+					continue // backfilled by the interrupted pass
+				}
 
 				err := h.limiter.Wait(ctx)
 				if err != nil {
@@ -355,6 +484,8 @@ func (h *historicalEnqueuer) buildForRepo(ctx context.Context, uniqueSeries map[
 					firstHEADCommit: firstHEADCommit,
 					seriesID:        seriesID,
 					series:          series,
+					cost:            costs[seriesID],
+					calendar:        calendar,
 				})
 				if err != nil {
 					softErr = multierror.Append(softErr, err)
@@ -363,8 +494,13 @@ func (h *historicalEnqueuer) buildForRepo(ctx context.Context, uniqueSeries map[
 				if hardErr != nil {
 					return multierror.Append(softErr, hardErr)
 				}
+				checkpoint(seriesID, repoName, currentFrame.From)
 			}
 
+			if len(frames) > 0 {
+				// Every timeframe of the repository is backfilled, including those filtered out.
+				checkpoint(seriesID, repoName, frames[0].From)
+			}
 		}
 		return nil
 	}
@@ -384,6 +520,12 @@ type buildSeriesContext struct {
 	// The series we're building historical data for.
 	seriesID string
 	series   insights.TimeSeries
+
+	// The estimated cost of the search for the historical data of the series in the repository.
+	cost priority.Cost
+
+	// The recording calendar, whose business days the series may be recorded on only.
+	calendar insights.RecordingCalendar
 }
 
 func Frames(numFrames int, frameLength time.Duration, current time.Time) []compression.Frame {
@@ -425,16 +567,29 @@ func (h *historicalEnqueuer) buildSeries(ctx context.Context, bctx *buildSeriesC
 	// We're trying to find the # of search results at the middle of the timeframe, ideally.
 	frameDuration := bctx.to.Sub(bctx.from)
 	frameMidpoint := bctx.from.Add(frameDuration / 2)
+	recordTime := frameMidpoint
+	if bctx.series.BusinessDaysOnly {
+		// Series recorded on business days only have no data points on other days, so the data
+		// point of the timeframe is recorded on the last business day before its middle instead.
+		recordTime = bctx.calendar.LastBusinessDay(frameMidpoint)
+		if recordTime.Before(bctx.from) {
+			return nil, nil // there is no business day in the first half of the timeframe
+		}
+	}
 
 	// Optimization: If the timeframe we're building data for ends before the first commit in the
 	// repository, then we know there are no results (the repository didn't have any commits at all
 	// at that point in time.)
 	repoName := string(bctx.repo.Name)
 	if bctx.to.Before(bctx.firstHEADCommit.Author.Date) {
+		if bctx.series.Split() {
+			// There is no captured value, author or team to record zero matches for.
+			return nil, nil
+		}
 		if err := h.insightsStore.RecordSeriesPoint(ctx, store.RecordSeriesPointArgs{
 			SeriesID: bctx.seriesID,
 			Point: store.SeriesPoint{
-				Time:  frameMidpoint,
+				Time:  recordTime,
 				Value: 0, // no matches
 			},
 			RepoName: &repoName,
@@ -462,31 +617,16 @@ func (h *historicalEnqueuer) buildSeries(ctx context.Context, bctx *buildSeriesC
 	//    whatever commit is closest) and perform a live/unindexed search for that `repo:<repo>@commit`
 	//    which will effectively search the repo at that point in time.
 	//
-	// We do the 2nd, and start by trying to locate the commit nearest to the middle of the
-	// timeframe we're trying to fill in historical data for.
-	nearestCommit, err := h.gitFindNearestCommit(ctx, bctx.repo.Name, "HEAD", frameMidpoint)
-	if err != nil {
-		if errors.HasType(err, &gitserver.RevisionNotFoundError{}) || vcs.IsRepoNotExist(err) {
-			return // no error - repo may not be cloned yet (or not even pushed to code host yet)
-		}
-		hardErr = errors.Wrap(err, "FindNearestCommit")
-		return
-	}
-	if nearestCommit == nil {
-		return // repository has no commits / is empty. Maybe not yet pushed to code host.
-	}
-
-	// Build the search query we will run. The most important part here is
-	query = withCountUnlimited(query)
-	query = fmt.Sprintf("%s repo:^%s$@%s", query, regexp.QuoteMeta(repoName), string(nearestCommit.ID))
-
+	// We do the 2nd: the job is pinned to the repository, and the query runner locates the commit
+	// nearest to the middle of the timeframe right before it runs the search.
 	hardErr = h.enqueueQueryRunnerJob(ctx, &queryrunner.Job{
 		SeriesID:    bctx.seriesID,
-		SearchQuery: query,
-		RecordTime:  &frameMidpoint,
+		SearchQuery: queryrunner.WithCountUnlimited(query),
+		RecordTime:  &recordTime,
+		PinnedRepo:  &repoName,
 		State:       "queued",
-		Priority:    int(priority.FromTimeInterval(frameMidpoint, time.Now())), // eventually we will use the end of the historical range, for now current time works fine
-		Cost:        int(priority.Unindexed),
+		Priority:    int(priority.FromTimeInterval(recordTime, time.Now())), // eventually we will use the end of the historical range, for now current time works fine
+		Cost:        int(bctx.cost),
 	})
 	return
 }
@@ -28,6 +28,11 @@ type testParams struct {
 	frames                int
 	recordSleepOperations bool
 	haveData              bool
+
+	// checkpoint, if set, is the backfill checkpoint of every series.
+	checkpoint *store.BackfillCheckpoint
+
+	calendar insights.RecordingCalendar
 }
 
 type testResults struct {
@@ -61,6 +66,15 @@ func testHistoricalEnqueuer(t *testing.T, p *testParams) *testResults {
 		}
 		return 0, nil
 	})
+	insightsStore.BackfillCheckpointsFunc.SetDefaultHook(func(ctx context.Context, seriesIDs []string) (map[string]store.BackfillCheckpoint, error) {
+		checkpoints := map[string]store.BackfillCheckpoint{}
+		if p.checkpoint != nil {
+			for _, seriesID := range seriesIDs {
+				checkpoints[seriesID] = *p.checkpoint
+			}
+		}
+		return checkpoints, nil
+	})
 	insightsStore.RecordSeriesPointFunc.SetDefaultHook(func(ctx context.Context, args store.RecordSeriesPointArgs) error {
 		r.operations = append(r.operations, fmt.Sprintf("recordSeriesPoint(point=%v, repoName=%v)", args.Point.String(), *args.RepoName))
 		return nil
@@ -81,7 +95,7 @@ func testHistoricalEnqueuer(t *testing.T, p *testParams) *testResults {
 	})
 
 	enqueueQueryRunnerJob := func(ctx context.Context, job *queryrunner.Job) error {
-		r.operations = append(r.operations, fmt.Sprintf(`enqueueQueryRunnerJob("%s", "%s")`, job.RecordTime.Format(time.RFC3339), job.SearchQuery))
+		r.operations = append(r.operations, fmt.Sprintf(`enqueueQueryRunnerJob("%s", "%s", pinnedRepo=%s)`, job.RecordTime.Format(time.RFC3339), job.SearchQuery, *job.PinnedRepo))
 		return nil
 	}
 
@@ -104,27 +118,24 @@ func testHistoricalEnqueuer(t *testing.T, p *testParams) *testResults {
 		return &git.Commit{Author: git.Signature{Date: yearsAgo}}, nil
 	}
 
-	gitFindNearestCommit := func(ctx context.Context, repoName api.RepoName, revSpec string, target time.Time) (*git.Commit, error) {
-		nearby := target.Add(-2 * 24 * time.Hour)
-		return &git.Commit{Author: git.Signature{Date: nearby}}, nil
-	}
-
 	limiter := rate.NewLimiter(10, 1)
 
 	historicalEnqueuer := &historicalEnqueuer{
 		now:                   clock,
+		insightStore:          discovery.NewMockInsightStore(),
 		settingStore:          settingStore,
 		insightsStore:         insightsStore,
 		repoStore:             repoStore,
 		enqueueQueryRunnerJob: enqueueQueryRunnerJob,
 		allReposIterator:      allReposIterator,
 		gitFirstEverCommit:    gitFirstEverCommit,
-		gitFindNearestCommit:  gitFindNearestCommit,
 		limiter:               limiter,
 		frameFilter:           &dataFrameFilter,
 		loader:                insights.NewMockLoader(),
 		framesToBackfill:      func() int { return p.frames },
 		frameLength:           func() time.Duration { return 7 * 24 * time.Hour },
+		limits:                func() (discovery.InsightLimits, error) { return discovery.InsightLimits{}, nil },
+		calendar:              func() (insights.RecordingCalendar, error) { return p.calendar, nil },
 	}
 
 	// If we do an iteration without any insights or repos, we should expect no sleep calls to be made.
@@ -172,21 +183,21 @@ func Test_historicalEnqueuer(t *testing.T) {
 		want := autogold.Want("no_data", &testResults{
 			allReposIteratorCalls: 1, reposGetByName: 2,
 			operations: []string{
-				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "errorf count:9999999 repo:^repo/0$@")`,
-				`enqueueQueryRunnerJob("2020-12-21T12:00:01Z", "errorf count:9999999 repo:^repo/0$@")`,
-				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "fmt.Printf count:9999999 repo:^repo/0$@")`,
-				`enqueueQueryRunnerJob("2020-12-21T12:00:01Z", "fmt.Printf count:9999999 repo:^repo/0$@")`,
-				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "gitserver.Exec count:9999999 repo:^repo/0$@")`,
-				`enqueueQueryRunnerJob("2020-12-21T12:00:01Z", "gitserver.Exec count:9999999 repo:^repo/0$@")`,
-				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "gitserver.Close count:9999999 repo:^repo/0$@")`,
-				`enqueueQueryRunnerJob("2020-12-21T12:00:01Z", "gitserver.Close count:9999999 repo:^repo/0$@")`,
-				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "errorf count:9999999 repo:^repo/1$@")`,
+				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "errorf count:all", pinnedRepo=repo/0)`,
+				`enqueueQueryRunnerJob("2020-12-21T12:00:01Z", "errorf count:all", pinnedRepo=repo/0)`,
+				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "fmt.Printf count:all", pinnedRepo=repo/0)`,
+				`enqueueQueryRunnerJob("2020-12-21T12:00:01Z", "fmt.Printf count:all", pinnedRepo=repo/0)`,
+				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "gitserver.Exec count:all", pinnedRepo=repo/0)`,
+				`enqueueQueryRunnerJob("2020-12-21T12:00:01Z", "gitserver.Exec count:all", pinnedRepo=repo/0)`,
+				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "gitserver.Close count:all", pinnedRepo=repo/0)`,
+				`enqueueQueryRunnerJob("2020-12-21T12:00:01Z", "gitserver.Close count:all", pinnedRepo=repo/0)`,
+				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "errorf count:all", pinnedRepo=repo/1)`,
 				`recordSeriesPoint(point=SeriesPoint{Time: "2020-12-21 12:00:01 +0000 UTC", Value: 0, Metadata: }, repoName=repo/1)`,
-				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "fmt.Printf count:9999999 repo:^repo/1$@")`,
+				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "fmt.Printf count:all", pinnedRepo=repo/1)`,
 				`recordSeriesPoint(point=SeriesPoint{Time: "2020-12-21 12:00:01 +0000 UTC", Value: 0, Metadata: }, repoName=repo/1)`,
-				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "gitserver.Exec count:9999999 repo:^repo/1$@")`,
+				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "gitserver.Exec count:all", pinnedRepo=repo/1)`,
 				`recordSeriesPoint(point=SeriesPoint{Time: "2020-12-21 12:00:01 +0000 UTC", Value: 0, Metadata: }, repoName=repo/1)`,
-				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "gitserver.Close count:9999999 repo:^repo/1$@")`,
+				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "gitserver.Close count:all", pinnedRepo=repo/1)`,
 				`recordSeriesPoint(point=SeriesPoint{Time: "2020-12-21 12:00:01 +0000 UTC", Value: 0, Metadata: }, repoName=repo/1)`,
 			},
 		})
@@ -197,4 +208,66 @@ func Test_historicalEnqueuer(t *testing.T) {
 			recordSleepOperations: true,
 		}))
 	})
+
+	// Test that an interrupted backfill resumes from its checkpoint:
+	//
+	// * repo/0 is skipped without being looked up, as it comes before the checkpoint.
+	// * The most recent timeframe of repo/1 is skipped, as it was backfilled before the interruption.
+	//
+	t.Run("resume_from_checkpoint", func(t *testing.T) {
+		want := autogold.Want("resume_from_checkpoint", &testResults{
+			allReposIteratorCalls: 1, reposGetByName: 1,
+			operations: []string{
+				`recordSeriesPoint(point=SeriesPoint{Time: "2020-12-21 12:00:01 +0000 UTC", Value: 0, Metadata: }, repoName=repo/1)`,
+				`recordSeriesPoint(point=SeriesPoint{Time: "2020-12-21 12:00:01 +0000 UTC", Value: 0, Metadata: }, repoName=repo/1)`,
+				`recordSeriesPoint(point=SeriesPoint{Time: "2020-12-21 12:00:01 +0000 UTC", Value: 0, Metadata: }, repoName=repo/1)`,
+				`recordSeriesPoint(point=SeriesPoint{Time: "2020-12-21 12:00:01 +0000 UTC", Value: 0, Metadata: }, repoName=repo/1)`,
+			},
+		})
+		want.Equal(t, testHistoricalEnqueuer(t, &testParams{
+			settings:              testRealGlobalSettings,
+			numRepos:              2,
+			frames:                2,
+			recordSleepOperations: true,
+			checkpoint: &store.BackfillCheckpoint{
+				RepoName:  "repo/1",
+				FrameFrom: time.Date(2020, 12, 25, 0, 0, 1, 0, time.UTC),
+			},
+		}))
+	})
+
+	// Test that the data points of series recorded on business days only are recorded on the last
+	// business day before the middle of their timeframe: 2020-12-28 is a holiday, and 2020-12-26 and
+	// 2020-12-27 are weekend days.
+	t.Run("business_days_only", func(t *testing.T) {
+		want := autogold.Want("business_days_only", &testResults{
+			allReposIteratorCalls: 1, reposGetByName: 1,
+			operations: []string{
+				`enqueueQueryRunnerJob("2020-12-25T12:00:01Z", "errorf count:all", pinnedRepo=repo/0)`,
+				`enqueueQueryRunnerJob("2020-12-21T12:00:01Z", "errorf count:all", pinnedRepo=repo/0)`,
+				`enqueueQueryRunnerJob("2020-12-28T12:00:01Z", "fmt.Printf count:all", pinnedRepo=repo/0)`,
+				`enqueueQueryRunnerJob("2020-12-21T12:00:01Z", "fmt.Printf count:all", pinnedRepo=repo/0)`,
+			},
+		})
+		want.Equal(t, testHistoricalEnqueuer(t, &testParams{
+			settings: &api.Settings{ID: 1, Contents: `{
+				"insights": [
+					{
+						"title": "business days",
+						"series": [
+							{"label": "errors.Errorf", "search": "errorf", "businessDaysOnly": true},
+							{"label": "printf", "search": "fmt.Printf"},
+							{"label": "printf on business days", "search": "fmt.Printf", "businessDaysOnly": true},
+						]
+					}
+				]
+			}`},
+			numRepos: 1,
+			frames:   2,
+			calendar: insights.RecordingCalendar{
+				WeekendDays: []time.Weekday{time.Saturday, time.Sunday},
+				Holidays:    []string{"2020-12-28"},
+			},
+		}))
+	})
 }
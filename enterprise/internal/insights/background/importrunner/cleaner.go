@@ -0,0 +1,58 @@
+package importrunner
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// importRetention is the time for which the outcome of imports remains available after they were
+// processed. Import jobs hold the imported data, which is not needed once it is imported.
+const importRetention = 24 * time.Hour
+
+// NewCleaner returns a background goroutine which will periodically find jobs left in the
+// "completed" or "failed" state that finished over importRetention ago and removes them, along with
+// the data they imported.
+func NewCleaner(ctx context.Context, workerBaseStore *basestore.Store, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_import_runner_cleaner",
+		metrics.WithCountHelp("Total number of insights importrunner cleaner executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "ImportRunner.Cleaner.Run",
+		Metrics: metrics,
+	})
+
+	// We look for jobs to cleanup every hour.
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 1*time.Hour, goroutine.NewHandlerWithErrorMessage(
+		"insights_import_runner_cleaner",
+		func(ctx context.Context) error {
+			_, err := cleanJobs(ctx, workerBaseStore)
+			return err
+		},
+	), operation)
+}
+
+// cleanJobs removes completed and failed jobs that finished over importRetention ago, and returns
+// the number of removed jobs.
+func cleanJobs(ctx context.Context, workerBaseStore *basestore.Store) (numCleaned int, err error) {
+	numCleaned, _, err = basestore.ScanFirstInt(workerBaseStore.Query(
+		ctx,
+		sqlf.Sprintf(cleanJobsFmtStr, time.Now().Add(-importRetention)),
+	))
+	return
+}
+
+const cleanJobsFmtStr = `
+-- source: enterprise/internal/insights/background/importrunner/cleaner.go:cleanJobs
+WITH deleted AS (
+	DELETE FROM insights_import_jobs WHERE (state='completed' OR state='failed') AND finished_at < %s RETURNING *
+) SELECT count(*) FROM deleted
+`
@@ -0,0 +1,4 @@
+package importrunner
+
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/importrunner -i ImportStore -o mock_import_store.go
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/importrunner -i RepoStore -o mock_repo_store.go
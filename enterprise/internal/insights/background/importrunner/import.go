@@ -0,0 +1,339 @@
+package importrunner
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// The formats data points can be imported from.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// The ways imported data points that conflict with recorded data points are resolved. A data point
+// conflicts with the data points of the same series and repository recorded in the same recording
+// interval.
+const (
+	OnConflictSkip    = "skip"    // keep the recorded data points
+	OnConflictReplace = "replace" // replace the recorded data points with the imported one
+)
+
+// MaxPoints is the maximum number of data points of a single import.
+const MaxPoints = 10000
+
+// ImportStore is a subset of the API exposed by the store.Store (only the subset used by the
+// import runner.)
+type ImportStore interface {
+	ImportSeriesPoint(ctx context.Context, args store.ImportSeriesPointArgs) (bool, error)
+}
+
+// RepoStore is a subset of the API exposed by the database.Repos() store (only the subset used by
+// the import runner.)
+type RepoStore interface {
+	GetByName(ctx context.Context, name api.RepoName) (*types.Repo, error)
+}
+
+// Point is a data point to import. Repository is empty for data points that are not recorded for
+// a repository.
+type Point struct {
+	Time       time.Time
+	Value      float64
+	Repository string
+}
+
+// ValidFormat reports whether data points can be imported from the given format.
+func ValidFormat(format string) bool {
+	return format == FormatCSV || format == FormatJSON
+}
+
+// ValidOnConflict reports whether the given conflict resolution is supported.
+func ValidOnConflict(onConflict string) bool {
+	return onConflict == OnConflictSkip || onConflict == OnConflictReplace
+}
+
+// ImportableSeries returns the series with the given ID that the current user may import data
+// points into.
+//
+// 🚨 SECURITY: Users may only import data points into the series of insights they can see, and only
+// site admins into the series of global insights, which every user sees.
+func ImportableSeries(ctx context.Context, db dbutil.DB, insightStore discovery.InsightStore, settingStore discovery.SettingStore, seriesID string) (insights.TimeSeries, error) {
+	namespaces, err := discovery.VisibleNamespaces(ctx, db)
+	if err != nil {
+		return insights.TimeSeries{}, errors.Wrap(err, "VisibleNamespaces")
+	}
+	discovered, err := discovery.Discover(ctx, insightStore, settingStore, insights.NewLoader(db), discovery.InsightFilterArgs{
+		Namespaces: namespaces,
+		SeriesIDs:  []string{seriesID},
+	})
+	if err != nil {
+		return insights.TimeSeries{}, errors.Wrap(err, "Discover")
+	}
+	for _, insight := range discovered {
+		for _, series := range insight.Series {
+			if discovery.Encode(series) != seriesID {
+				continue
+			}
+			if series.Namespace.IsGlobal() {
+				if err := backend.CheckCurrentUserIsSiteAdmin(ctx, db); err != nil {
+					return insights.TimeSeries{}, err
+				}
+			}
+			return series, nil
+		}
+	}
+	return insights.TimeSeries{}, errors.Errorf("insight series %q not found", seriesID)
+}
+
+// ParsePoints parses the data points of the given data in the given format.
+//
+// CSV data starts with a header row naming its columns: "time", "value", and optionally
+// "repository". JSON data is an object like {"points": [{"time": ..., "value": ..., "repository":
+// ...}]}. Times are either RFC 3339 timestamps or dates like 2021-09-01, which are at midnight in
+// the time zone of the given calendar. Data points must have finite values, and must not be after
+// the given time.
+func ParsePoints(format string, data []byte, calendar insights.RecordingCalendar, now time.Time) ([]Point, error) {
+	var (
+		points []Point
+		err    error
+	)
+	switch format {
+	case FormatCSV:
+		points, err = parseCSV(data, calendar)
+	case FormatJSON:
+		points, err = parseJSON(data, calendar)
+	default:
+		return nil, errors.Errorf("unsupported import format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(points) == 0 {
+		return nil, errors.New("no data points to import")
+	}
+	if len(points) > MaxPoints {
+		return nil, errors.Errorf("too many data points to import: %d, at most %d data points can be imported at once", len(points), MaxPoints)
+	}
+	for i, point := range points {
+		if math.IsNaN(point.Value) || math.IsInf(point.Value, 0) {
+			return nil, errors.Errorf("data point %d: value must be a finite number", i+1)
+		}
+		if point.Time.After(now) {
+			return nil, errors.Errorf("data point %d: time %s is in the future", i+1, point.Time.Format(time.RFC3339))
+		}
+	}
+	return points, nil
+}
+
+func parseCSV(data []byte, calendar insights.RecordingCalendar) ([]Point, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading CSV header")
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "time", "value", "repository":
+			columns[name] = i
+		default:
+			return nil, errors.Errorf("unknown CSV column %q, expected time, value, and optionally repository", name)
+		}
+	}
+	for _, name := range []string{"time", "value"} {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.Errorf("missing CSV column %q", name)
+		}
+	}
+
+	var points []Point
+	for row := 2; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			return points, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading CSV")
+		}
+		t, err := parseTime(record[columns["time"]], calendar)
+		if err != nil {
+			return nil, errors.Wrapf(err, "row %d", row)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[columns["value"]]), 64)
+		if err != nil {
+			return nil, errors.Errorf("row %d: invalid value %q", row, record[columns["value"]])
+		}
+		point := Point{Time: t, Value: value}
+		if i, ok := columns["repository"]; ok {
+			point.Repository = strings.TrimSpace(record[i])
+		}
+		points = append(points, point)
+	}
+}
+
+// importedPoints is the JSON representation of the data points to import.
+type importedPoints struct {
+	Points []struct {
+		Time       string   `json:"time"`
+		Value      *float64 `json:"value"`
+		Repository string   `json:"repository"`
+	} `json:"points"`
+}
+
+func parseJSON(data []byte, calendar insights.RecordingCalendar) ([]Point, error) {
+	var imported importedPoints
+	if err := json.Unmarshal(data, &imported); err != nil {
+		return nil, errors.Wrap(err, "decoding JSON")
+	}
+	points := make([]Point, 0, len(imported.Points))
+	for i, p := range imported.Points {
+		t, err := parseTime(p.Time, calendar)
+		if err != nil {
+			return nil, errors.Wrapf(err, "data point %d", i+1)
+		}
+		if p.Value == nil {
+			return nil, errors.Errorf("data point %d: missing value", i+1)
+		}
+		points = append(points, Point{Time: t, Value: *p.Value, Repository: p.Repository})
+	}
+	return points, nil
+}
+
+// parseTime parses an RFC 3339 timestamp, or a date at midnight in the time zone of the given
+// calendar.
+func parseTime(s string, calendar insights.RecordingCalendar) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	location := calendar.Location
+	if location == nil {
+		location = time.UTC
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, location); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.Errorf("invalid time %q, expected an RFC 3339 timestamp or a date like 2006-01-02", s)
+}
+
+// ValidatePoints returns an error if the given data points cannot be imported into the given
+// series. Series recorded per repository, i.e. search series, need the repository of every data
+// point: a data point without repository would be added to the data points of every repository
+// when the series is charted. Conversely, webhook series are not recorded per repository. Only a
+// single data point per repository can be imported in each recording interval of the series, the
+// boundaries of which are those of the given calendar.
+func ValidatePoints(series insights.TimeSeries, calendar insights.RecordingCalendar, points []Point) error {
+	switch {
+	case series.Expression != "":
+		return errors.New("data points cannot be imported into series derived from other series, import them into the series they are derived from instead")
+	case series.GeneratedFromCaptureGroups:
+		return errors.New("data points cannot be imported into series generated from capture groups")
+	case series.GroupBy != "":
+		return errors.New("data points cannot be imported into series grouped by author or team")
+	}
+
+	type intervalKey struct {
+		start      time.Time
+		repository string
+	}
+	seen := map[intervalKey]int{}
+	for i, point := range points {
+		if series.Webhook != "" && point.Repository != "" {
+			return errors.Errorf("data point %d: webhook series are not recorded per repository, but the data point has repository %q", i+1, point.Repository)
+		}
+		if series.Webhook == "" && point.Repository == "" {
+			return errors.Errorf("data point %d: search series are recorded per repository, but the data point has no repository", i+1)
+		}
+		key := intervalKey{start: calendar.Start(series.Interval, point.Time).UTC(), repository: point.Repository}
+		if j, ok := seen[key]; ok {
+			return errors.Errorf("data points %d and %d are in the same recording interval of the series", j+1, i+1)
+		}
+		seen[key] = i
+	}
+	return nil
+}
+
+// Import imports the given data points into the given series, and returns the number of imported
+// data points and of data points skipped because they conflict with recorded data points. The
+// repositories of the data points are looked up before any data point is imported, so that an
+// import with unknown repositories imports nothing.
+//
+// 🚨 SECURITY: Repositories are looked up as the actor of the given context, so imports must run
+// as the user who requested them.
+func Import(ctx context.Context, importStore ImportStore, repoStore RepoStore, seriesID string, series insights.TimeSeries, calendar insights.RecordingCalendar, points []Point, onConflict string) (imported, skipped int, err error) {
+	if !ValidOnConflict(onConflict) {
+		return 0, 0, errors.Errorf("unsupported conflict resolution %q", onConflict)
+	}
+	if err := ValidatePoints(series, calendar, points); err != nil {
+		return 0, 0, errcode.MakeNonRetryable(err)
+	}
+
+	repos := map[string]*types.Repo{}
+	for _, point := range points {
+		if point.Repository == "" {
+			continue
+		}
+		if _, ok := repos[point.Repository]; ok {
+			continue
+		}
+		repo, err := repoStore.GetByName(ctx, api.RepoName(point.Repository))
+		if err != nil {
+			if errcode.IsNotFound(err) {
+				// Retrying would not make the repository exist, or visible to the user.
+				return 0, 0, errcode.MakeNonRetryable(errors.Errorf("repository %q not found", point.Repository))
+			}
+			return 0, 0, errors.Wrap(err, "GetByName")
+		}
+		repos[point.Repository] = repo
+	}
+
+	for _, point := range points {
+		args := store.ImportSeriesPointArgs{
+			RecordSeriesPointArgs: store.RecordSeriesPointArgs{
+				SeriesID: seriesID,
+				Point:    store.SeriesPoint{Time: point.Time, Value: point.Value},
+			},
+			ConflictFrom: calendar.Start(series.Interval, point.Time),
+			ConflictTo:   calendar.Next(series.Interval, point.Time),
+			Replace:      onConflict == OnConflictReplace,
+		}
+		if repo, ok := repos[point.Repository]; ok {
+			repoName := string(repo.Name)
+			args.RepoName = &repoName
+			args.RepoID = &repo.ID
+		}
+		ok, err := importStore.ImportSeriesPoint(ctx, args)
+		if err != nil {
+			return imported, skipped, errors.Wrap(err, "ImportSeriesPoint")
+		}
+		if ok {
+			imported++
+		} else {
+			skipped++
+		}
+	}
+	return imported, skipped, nil
+}
@@ -0,0 +1,243 @@
+package importrunner
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestParsePoints(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	calendar := insights.RecordingCalendar{Location: berlin}
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	want := []Point{
+		{Time: time.Date(2021, 9, 1, 0, 0, 0, 0, berlin), Value: 3, Repository: "github.com/a/a"},
+		{Time: time.Date(2021, 9, 2, 12, 0, 0, 0, time.UTC), Value: 4.5, Repository: "github.com/b/b"},
+	}
+	tests := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{
+			name:   "csv",
+			format: FormatCSV,
+			data:   "Repository, Time, Value\ngithub.com/a/a,2021-09-01,3\ngithub.com/b/b,2021-09-02T12:00:00Z,4.5\n",
+		},
+		{
+			name:   "json",
+			format: FormatJSON,
+			data:   `{"points": [{"time": "2021-09-01", "value": 3, "repository": "github.com/a/a"}, {"time": "2021-09-02T12:00:00Z", "value": 4.5, "repository": "github.com/b/b"}]}`,
+		},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			points, err := ParsePoints(tst.format, []byte(tst.data), calendar, now)
+			if err != nil {
+				t.Fatalf("unexpected error parsing points: %s", err)
+			}
+			if diff := cmp.Diff(want, points); diff != "" {
+				t.Errorf("unexpected points (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePointsErrors(t *testing.T) {
+	now := time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   string
+	}{
+		{name: "unknown format", format: "xml", data: "<points/>", want: `unsupported import format "xml"`},
+		{name: "empty", format: FormatCSV, data: "", want: "no data points to import"},
+		{name: "unknown column", format: FormatCSV, data: "time,value,count\n", want: `unknown CSV column "count"`},
+		{name: "missing column", format: FormatCSV, data: "time\n2021-09-01\n", want: `missing CSV column "value"`},
+		{name: "invalid time", format: FormatCSV, data: "time,value\n2021-09-01,1\nyesterday,2\n", want: `row 3: invalid time "yesterday"`},
+		{name: "invalid value", format: FormatCSV, data: "time,value\n2021-09-01,many\n", want: `row 2: invalid value "many"`},
+		{name: "infinite value", format: FormatCSV, data: "time,value\n2021-09-01,+Inf\n", want: "data point 1: value must be a finite number"},
+		{name: "future", format: FormatCSV, data: "time,value\n2021-10-02,1\n", want: "data point 1: time 2021-10-02T00:00:00Z is in the future"},
+		{name: "missing value", format: FormatJSON, data: `{"points": [{"time": "2021-09-01"}]}`, want: "data point 1: missing value"},
+		{name: "malformed json", format: FormatJSON, data: `{"points": `, want: "decoding JSON"},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			_, err := ParsePoints(tst.format, []byte(tst.data), insights.RecordingCalendar{}, now)
+			if err == nil {
+				t.Fatalf("expected error %q, got none", tst.want)
+			}
+			if !strings.Contains(err.Error(), tst.want) {
+				t.Errorf("unexpected error. want=%q have=%q", tst.want, err)
+			}
+		})
+	}
+}
+
+func TestValidatePoints(t *testing.T) {
+	day := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	search := insights.TimeSeries{Query: "errorf", Interval: insights.Daily}
+	webhook := insights.TimeSeries{Webhook: "https://example.com", Interval: insights.Weekly}
+
+	tests := []struct {
+		name   string
+		series insights.TimeSeries
+		points []Point
+		want   string
+	}{
+		{
+			name:   "search",
+			series: search,
+			points: []Point{
+				{Time: day, Repository: "a"},
+				{Time: day, Repository: "b"},
+				{Time: day.AddDate(0, 0, 1), Repository: "a"},
+			},
+		},
+		{
+			name:   "webhook",
+			series: webhook,
+			points: []Point{{Time: day}, {Time: day.AddDate(0, 0, 7)}},
+		},
+		{
+			name:   "search without repository",
+			series: search,
+			points: []Point{{Time: day}},
+			want:   "data point 1: search series are recorded per repository, but the data point has no repository",
+		},
+		{
+			name:   "webhook with repository",
+			series: webhook,
+			points: []Point{{Time: day, Repository: "a"}},
+			want:   `data point 1: webhook series are not recorded per repository, but the data point has repository "a"`,
+		},
+		{
+			name:   "same interval",
+			series: webhook,
+			points: []Point{{Time: day}, {Time: day.AddDate(0, 0, 2)}},
+			want:   "data points 1 and 2 are in the same recording interval of the series",
+		},
+		{
+			name:   "derived",
+			series: insights.TimeSeries{Expression: "a + b"},
+			points: []Point{{Time: day}},
+			want:   "data points cannot be imported into series derived from other series, import them into the series they are derived from instead",
+		},
+		{
+			name:   "capture groups",
+			series: insights.TimeSeries{Query: "(\\w+)", GeneratedFromCaptureGroups: true},
+			points: []Point{{Time: day, Repository: "a"}},
+			want:   "data points cannot be imported into series generated from capture groups",
+		},
+	}
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			err := ValidatePoints(tst.series, insights.RecordingCalendar{}, tst.points)
+			have := ""
+			if err != nil {
+				have = err.Error()
+			}
+			if have != tst.want {
+				t.Errorf("unexpected error. want=%q have=%q", tst.want, have)
+			}
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+	series := insights.TimeSeries{Query: "errorf", Interval: insights.Daily}
+	points := []Point{
+		{Time: day, Value: 1, Repository: "github.com/a/a"},
+		{Time: day.AddDate(0, 0, 1), Value: 2, Repository: "github.com/a/a"},
+		{Time: day, Value: 3, Repository: "github.com/b/b"},
+	}
+
+	repoStore := NewMockRepoStore()
+	repoStore.GetByNameFunc.SetDefaultHook(func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		switch name {
+		case "github.com/a/a":
+			return &types.Repo{ID: 1, Name: name}, nil
+		case "github.com/b/b":
+			return &types.Repo{ID: 2, Name: name}, nil
+		}
+		return nil, &database.RepoNotFoundErr{Name: name}
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		for _, onConflict := range []string{OnConflictSkip, OnConflictReplace} {
+			importStore := NewMockImportStore()
+			importStore.ImportSeriesPointFunc.SetDefaultHook(func(ctx context.Context, args store.ImportSeriesPointArgs) (bool, error) {
+				// The data point of github.com/b/b conflicts with a recorded one.
+				return args.Replace || *args.RepoID != 2, nil
+			})
+
+			imported, skipped, err := Import(ctx, importStore, repoStore, "s", series, insights.RecordingCalendar{}, points, onConflict)
+			if err != nil {
+				t.Fatalf("unexpected error importing points: %s", err)
+			}
+			wantImported, wantSkipped := 2, 1
+			if onConflict == OnConflictReplace {
+				wantImported, wantSkipped = 3, 0
+			}
+			if imported != wantImported || skipped != wantSkipped {
+				t.Errorf("unexpected counts for %s. want=%d,%d have=%d,%d", onConflict, wantImported, wantSkipped, imported, skipped)
+			}
+
+			history := importStore.ImportSeriesPointFunc.History()
+			if len(history) != len(points) {
+				t.Fatalf("unexpected number of imported points. want=%d have=%d", len(points), len(history))
+			}
+			args := history[1].Arg1
+			want := store.ImportSeriesPointArgs{
+				RecordSeriesPointArgs: store.RecordSeriesPointArgs{
+					SeriesID: "s",
+					Point:    store.SeriesPoint{Time: day.AddDate(0, 0, 1), Value: 2},
+					RepoName: args.RepoName,
+					RepoID:   args.RepoID,
+				},
+				ConflictFrom: time.Date(2021, 9, 2, 0, 0, 0, 0, time.UTC),
+				ConflictTo:   time.Date(2021, 9, 3, 0, 0, 0, 0, time.UTC),
+				Replace:      onConflict == OnConflictReplace,
+			}
+			if diff := cmp.Diff(want, args); diff != "" {
+				t.Errorf("unexpected import args (-want +got):\n%s", diff)
+			}
+			if *args.RepoName != "github.com/a/a" || *args.RepoID != 1 {
+				t.Errorf("unexpected repository. want=%q have=%q", "github.com/a/a", *args.RepoName)
+			}
+		}
+	})
+
+	t.Run("unknown repository", func(t *testing.T) {
+		importStore := NewMockImportStore()
+		unknown := append(points, Point{Time: day, Value: 4, Repository: "github.com/c/c"})
+
+		_, _, err := Import(ctx, importStore, repoStore, "s", series, insights.RecordingCalendar{}, unknown, OnConflictSkip)
+		if err == nil || err.Error() != `repository "github.com/c/c" not found` {
+			t.Fatalf("unexpected error. want=%q have=%v", `repository "github.com/c/c" not found`, err)
+		}
+		if !errcode.IsNonRetryable(err) {
+			t.Errorf("expected the error to be non-retryable")
+		}
+		if calls := len(importStore.ImportSeriesPointFunc.History()); calls != 0 {
+			t.Errorf("unexpected number of imported points. want=%d have=%d", 0, calls)
+		}
+	})
+}
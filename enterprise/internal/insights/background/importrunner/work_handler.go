@@ -0,0 +1,67 @@
+package importrunner
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+var _ workerutil.Handler = &workHandler{}
+
+// workHandler implements the dbworker.Handler interface by importing the data points of jobs into
+// their series.
+type workHandler struct {
+	workerBaseStore *basestore.Store
+	insightStore    discovery.InsightStore
+	settingStore    discovery.SettingStore
+	importStore     ImportStore
+	repoStore       RepoStore
+	calendar        func() (insights.RecordingCalendar, error)
+}
+
+func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err error) {
+	defer func() {
+		if err != nil {
+			log15.Error("insights.importrunner.workHandler", "error", err)
+		}
+	}()
+
+	// Dequeue the job to get information about it, like what data points to import.
+	job, err := dequeueJob(ctx, r.workerBaseStore, record.RecordID())
+	if err != nil {
+		return err
+	}
+
+	// 🚨 SECURITY: Import the data points as the user who requested it, who must still be allowed
+	// to import into the series, and may only name the repositories visible to them.
+	ctx = actor.WithActor(ctx, actor.FromUser(job.UserID))
+	series, err := ImportableSeries(ctx, r.workerBaseStore.Handle().DB(), r.insightStore, r.settingStore, job.SeriesID)
+	if err != nil {
+		// The series was deleted, or the user may no longer import into it since the import was
+		// requested. Retrying would not change that.
+		return errcode.MakeNonRetryable(err)
+	}
+
+	calendar, err := r.calendar()
+	if err != nil {
+		return errors.Wrap(err, "SiteRecordingCalendar")
+	}
+	points, err := ParsePoints(job.Format, job.Data, calendar, time.Now())
+	if err != nil {
+		return errcode.MakeNonRetryable(err)
+	}
+	imported, skipped, err := Import(ctx, r.importStore, r.repoStore, job.SeriesID, series, calendar, points, job.OnConflict)
+	if err != nil {
+		return err
+	}
+	return setJobResult(ctx, r.workerBaseStore, job.ID, imported, skipped)
+}
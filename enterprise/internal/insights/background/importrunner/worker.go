@@ -0,0 +1,278 @@
+package importrunner
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// This file contains all the methods required to:
+//
+// 1. Create the import runner worker
+// 2. Enqueue jobs for the import runner to execute.
+// 3. Dequeue jobs from the import runner.
+// 4. Serialize jobs for the import runner into the DB.
+//
+
+// NewWorker returns a worker that will import data points into series, and record the number of
+// imported data points on the job for the user who requested the import.
+func NewWorker(ctx context.Context, workerBaseStore *basestore.Store, insightStore discovery.InsightStore, settingStore discovery.SettingStore, importStore ImportStore, metrics workerutil.WorkerMetrics) *workerutil.Worker {
+	workerStore := createDBWorkerStore(workerBaseStore)
+
+	options := workerutil.WorkerOptions{
+		Name:              "insights_import_runner_worker",
+		NumHandlers:       1,
+		Interval:          5 * time.Second,
+		HeartbeatInterval: 15 * time.Second,
+		Metrics:           metrics,
+	}
+
+	return dbworker.NewWorker(ctx, workerStore, &workHandler{
+		workerBaseStore: workerBaseStore,
+		insightStore:    insightStore,
+		settingStore:    settingStore,
+		importStore:     importStore,
+		repoStore:       database.Repos(workerBaseStore.Handle().DB()),
+		calendar:        discovery.SiteRecordingCalendar,
+	}, options)
+}
+
+// NewResetter returns a resetter that will reset pending import runner jobs if they take too long
+// to complete.
+func NewResetter(ctx context.Context, workerBaseStore *basestore.Store, metrics dbworker.ResetterMetrics) *dbworker.Resetter {
+	workerStore := createDBWorkerStore(workerBaseStore)
+	options := dbworker.ResetterOptions{
+		Name:     "insights_import_runner_worker_resetter",
+		Interval: 1 * time.Minute,
+		Metrics:  metrics,
+	}
+	return dbworker.NewResetter(workerStore, options)
+}
+
+var workerStoreOptions = dbworkerstore.Options{
+	Name:              "insights_import_runner_jobs_store",
+	TableName:         "insights_import_jobs",
+	ColumnExpressions: jobsColumns,
+	Scan:              scanJobs,
+
+	// Imports write up to MaxPoints data points, each in its own transaction. A retried import
+	// resolves the data points imported by the failed attempt as conflicts.
+	StalledMaxAge:     5 * time.Minute,
+	RetryAfter:        1 * time.Minute,
+	MaxNumRetries:     3,
+	OrderByExpression: sqlf.Sprintf("id"),
+}
+
+// createDBWorkerStore creates the dbworker store for the import runner worker.
+//
+// See internal/workerutil/dbworker for more information about dbworkers.
+func createDBWorkerStore(s *basestore.Store) dbworkerstore.Store {
+	return dbworkerstore.New(s.Handle(), workerStoreOptions)
+}
+
+// EnqueueJob enqueues a job for the import runner worker to execute later.
+func EnqueueJob(ctx context.Context, workerBaseStore *basestore.Store, job *Job) (id int, err error) {
+	id, _, err = basestore.ScanFirstInt(workerBaseStore.Query(
+		ctx,
+		sqlf.Sprintf(
+			enqueueJobFmtStr,
+			job.SeriesID,
+			job.Format,
+			job.OnConflict,
+			job.UserID,
+			job.Data,
+			job.State,
+			job.ProcessAfter,
+		),
+	))
+	return
+}
+
+const enqueueJobFmtStr = `
+-- source: enterprise/internal/insights/background/importrunner/worker.go:EnqueueJob
+INSERT INTO insights_import_jobs (
+	series_id,
+	format,
+	on_conflict,
+	user_id,
+	data,
+	state,
+	process_after
+) VALUES (%s, %s, %s, %s, %s, %s, %s)
+RETURNING id
+`
+
+// GetJob returns the import job with the given ID, if it exists. The imported data is omitted.
+func GetJob(ctx context.Context, workerBaseStore *basestore.Store, id int) (*Job, bool, error) {
+	return getJob(ctx, workerBaseStore, id, sqlf.Sprintf("NULL::bytea"))
+}
+
+func dequeueJob(ctx context.Context, workerBaseStore *basestore.Store, recordID int) (*Job, error) {
+	job, ok, err := getJob(ctx, workerBaseStore, recordID, sqlf.Sprintf("data"))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.Errorf("expected 1 job to dequeue, found 0")
+	}
+	return job, nil
+}
+
+func getJob(ctx context.Context, workerBaseStore *basestore.Store, id int, data *sqlf.Query) (*Job, bool, error) {
+	rows, err := workerBaseStore.Query(ctx, sqlf.Sprintf(getJobFmtStr, data, id))
+	if err != nil {
+		return nil, false, err
+	}
+	jobs, err := doScanJobs(rows, nil)
+	if err != nil || len(jobs) == 0 {
+		return nil, false, err
+	}
+	return jobs[0], true, nil
+}
+
+const getJobFmtStr = `
+-- source: enterprise/internal/insights/background/importrunner/worker.go:GetJob
+SELECT
+	series_id,
+	format,
+	on_conflict,
+	user_id,
+	%s,
+	imported_points,
+	skipped_points,
+	id,
+	state,
+	failure_message,
+	started_at,
+	finished_at,
+	process_after,
+	num_resets,
+	num_failures,
+	execution_logs
+FROM insights_import_jobs
+WHERE id = %s;
+`
+
+// setJobResult records the number of imported and skipped data points on the job with the given ID.
+func setJobResult(ctx context.Context, workerBaseStore *basestore.Store, id, imported, skipped int) error {
+	return workerBaseStore.Exec(ctx, sqlf.Sprintf(setJobResultFmtStr, imported, skipped, id))
+}
+
+const setJobResultFmtStr = `
+-- source: enterprise/internal/insights/background/importrunner/worker.go:setJobResult
+UPDATE insights_import_jobs SET imported_points = %s, skipped_points = %s WHERE id = %s
+`
+
+// Job represents a single job for the import runner worker to perform. When enqueued, it is stored
+// in the insights_import_jobs table - then the worker dequeues it by reading it from that table.
+//
+// See internal/workerutil/dbworker for more information about dbworkers.
+type Job struct {
+	// Import runner fields.
+	SeriesID       string
+	Format         string // FormatCSV or FormatJSON.
+	OnConflict     string // OnConflictSkip or OnConflictReplace.
+	UserID         int32  // The user who requested the import, who repositories are looked up for.
+	Data           []byte // The imported data.
+	ImportedPoints *int   // The number of imported data points, once completed.
+	SkippedPoints  *int   // The number of data points skipped because of conflicts, once completed.
+
+	// Standard/required dbworker fields. If enqueuing a job, these may all be zero values except State.
+	ID             int
+	State          string // If enqueing a job, set to "queued"
+	FailureMessage *string
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+	ProcessAfter   *time.Time
+	NumResets      int32
+	NumFailures    int32
+	ExecutionLogs  []workerutil.ExecutionLogEntry
+}
+
+// Implements the internal/workerutil.Record interface, used by the work handler to locate the job
+// once executing (see work_handler.go:Handle).
+func (j *Job) RecordID() int {
+	return j.ID
+}
+
+func scanJobs(rows *sql.Rows, err error) (workerutil.Record, bool, error) {
+	records, err := doScanJobs(rows, err)
+	if err != nil {
+		return &Job{}, false, err
+	}
+	return records[0], true, nil
+}
+
+func doScanJobs(rows *sql.Rows, err error) ([]*Job, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+	var jobs []*Job
+	for rows.Next() {
+		j := &Job{}
+		if err := rows.Scan(
+			// Import runner fields.
+			&j.SeriesID,
+			&j.Format,
+			&j.OnConflict,
+			&j.UserID,
+			&j.Data,
+			&j.ImportedPoints,
+			&j.SkippedPoints,
+
+			// Standard/required dbworker fields.
+			&j.ID,
+			&j.State,
+			&j.FailureMessage,
+			&j.StartedAt,
+			&j.FinishedAt,
+			&j.ProcessAfter,
+			&j.NumResets,
+			&j.NumFailures,
+			pq.Array(&j.ExecutionLogs),
+		); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// jobsColumns omits the imported data, which is only read by the worker once it handles the job.
+var jobsColumns = []*sqlf.Query{
+	sqlf.Sprintf("insights_import_jobs.series_id"),
+	sqlf.Sprintf("insights_import_jobs.format"),
+	sqlf.Sprintf("insights_import_jobs.on_conflict"),
+	sqlf.Sprintf("insights_import_jobs.user_id"),
+	sqlf.Sprintf("NULL::bytea"),
+	sqlf.Sprintf("insights_import_jobs.imported_points"),
+	sqlf.Sprintf("insights_import_jobs.skipped_points"),
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("state"),
+	sqlf.Sprintf("failure_message"),
+	sqlf.Sprintf("started_at"),
+	sqlf.Sprintf("finished_at"),
+	sqlf.Sprintf("process_after"),
+	sqlf.Sprintf("num_resets"),
+	sqlf.Sprintf("num_failures"),
+	sqlf.Sprintf("execution_logs"),
+}
@@ -2,7 +2,9 @@ package background
 
 import (
 	"context"
-	"strings"
+	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/insights/priority"
@@ -11,19 +13,29 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/webhookrunner"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
 // newInsightEnqueuer returns a background goroutine which will periodically find all of the search
 // and webhook insights across all user settings, and enqueue work for the query runner and webhook
-// runner workers to perform.
-func newInsightEnqueuer(ctx context.Context, workerBaseStore *basestore.Store, settingStore discovery.SettingStore, observationContext *observation.Context) goroutine.BackgroundRoutine {
+// runner workers to perform. Series of insights newly defined in settings are enqueued as soon as
+// the settings change, without waiting for the next periodic run, by a run restricted to the
+// namespaces of the changed settings. Series that fail to be enqueued are recorded in the given
+// store until they are enqueued.
+func newInsightEnqueuer(ctx context.Context, workerBaseStore *basestore.Store, insightStore discovery.InsightStore, settingStore discovery.SettingStore, insightsStore store.Interface, observationContext *observation.Context) goroutine.BackgroundRoutine {
 	metrics := metrics.NewOperationMetrics(
 		observationContext.Registerer,
 		"insights_enqueuer",
@@ -34,89 +46,572 @@ func newInsightEnqueuer(ctx context.Context, workerBaseStore *basestore.Store, s
 		Metrics: metrics,
 	})
 
+	failures := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_insights_enqueuer_series_failures_total",
+		Help: "The number of failed attempts to enqueue insight series, by cause.",
+	}, []string{"cause"})
+	observationContext.Registerer.MustRegister(failures)
+	failingSeries := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "src_insights_enqueuer_failing_series",
+		Help: "The number of insight series whose most recent attempt to be enqueued failed, by cause.",
+	}, []string{"cause"})
+	observationContext.Registerer.MustRegister(failingSeries)
+	failureStore := &countingEnqueueFailureStore{enqueueFailureStore: insightsStore, failures: failures}
+
 	// Note: We run this goroutine once every 10 minutes, and StalledMaxAge in queryrunner/ is
 	// set to 60s. If you change this, make sure the StalledMaxAge is less than this period
 	// otherwise there is a fair chance we could enqueue work faster than it can be completed.
 	//
+	// Each run only enqueues the series that are due according to their recording interval, so
+	// the period bounds how late a recording may be rather than how often series are recorded.
+	//
 	// See also https://github.com/sourcegraph/sourcegraph/pull/17227#issuecomment-779515187 for some very rough
 	// data retention / scale concerns.
-	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 12*time.Hour, goroutine.NewHandlerWithErrorMessage(
-		"insights_enqueuer",
-		func(ctx context.Context) error {
-			queryRunnerEnqueueJob := func(ctx context.Context, job *queryrunner.Job) error {
-				_, err := queryrunner.EnqueueJob(ctx, workerBaseStore, job)
-				return err
-			}
-			return discoverAndEnqueueInsights(ctx, time.Now, settingStore, insights.NewLoader(workerBaseStore.Handle().DB()), queryRunnerEnqueueJob)
-		},
-	), operation)
+	var (
+		// mu serializes the periodic runs and the runs triggered by settings changes, which share
+		// the schedule.
+		mu       sync.Mutex
+		schedule = recordingSchedule{}
+	)
+	run := func(ctx context.Context, scope enqueueScope) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		queryRunnerEnqueueJob := func(ctx context.Context, job *queryrunner.Job) error {
+			_, err := queryrunner.EnqueueJob(ctx, workerBaseStore, job)
+			return err
+		}
+		webhookRunnerEnqueueJob := func(ctx context.Context, job *webhookrunner.Job) error {
+			_, err := webhookrunner.EnqueueJob(ctx, workerBaseStore, job)
+			return err
+		}
+		limits, err := discovery.LicenseLimits()
+		if err != nil {
+			return errors.Wrap(err, "LicenseLimits")
+		}
+		calendar, err := discovery.SiteRecordingCalendar()
+		if err != nil {
+			return errors.Wrap(err, "SiteRecordingCalendar")
+		}
+		// Repository counts are cached by the estimator, so every run counts them afresh.
+		estimator := discovery.NewCostEstimator(database.Repos(workerBaseStore.Handle().DB()))
+		err = discoverAndEnqueueInsights(ctx, time.Now, insightStore, settingStore, insights.NewLoader(workerBaseStore.Handle().DB()), failureStore, insightsStore, limits, calendar, estimator, schedule, scope, queryRunnerEnqueueJob, webhookRunnerEnqueueJob)
+		if countErr := countFailingSeries(ctx, failureStore, failingSeries); countErr != nil {
+			err = multierror.Append(err, countErr)
+		}
+		return err
+	}
+
+	// Every run discovers every insight. The first run waits for the services restarted by the
+	// same deploy to start, and is spread over a minute by the jitter, which then keeps later
+	// runs from staying in lockstep with other periodic routines.
+	enqueuer := goroutine.NewPeriodicGoroutineWithMetrics(ctx, enqueuerInterval, &enqueuerHandler{
+		run: func(ctx context.Context) error { return run(ctx, enqueueScope{}) },
+	}, operation,
+		goroutine.WithInitialDelay(5*time.Minute),
+		goroutine.WithJitter(0.1),
+		goroutine.WithIntervalOverride(enqueuerIntervalFromSiteConfig),
+	)
+
+	// Setting a non-positive interval keeps the interval, but makes the current wait take the
+	// new value of insights.enqueuer.intervalSeconds into account.
+	go conf.Watch(func() { enqueuer.SetInterval(0) })
+
+	return goroutine.CombinedRoutine{
+		enqueuer,
+		newSettingsWatcher(ctx, workerBaseStore, func(ctx context.Context, namespaces []insights.Namespace) error {
+			return run(ctx, enqueueScope{
+				filter:        discovery.InsightFilterArgs{Namespaces: namespaces},
+				newSeriesOnly: true,
+			})
+		}, observationContext),
+	}
+}
+
+const (
+	// enqueuerInterval is the default interval between runs of the insights enqueuer.
+	enqueuerInterval = 10 * time.Minute
+
+	// maxEnqueuerBackoff is the longest interval between runs of the insights enqueuer while the
+	// query runner queue is full.
+	maxEnqueuerBackoff = 2 * time.Hour
+)
+
+// enqueuerHandler runs the insights enqueuer, backing off while the query runner queue is full.
+type enqueuerHandler struct {
+	run func(ctx context.Context) error
+}
+
+var (
+	_ goroutine.ErrorHandler     = &enqueuerHandler{}
+	_ goroutine.IntervalAdjuster = &enqueuerHandler{}
+)
+
+func (h *enqueuerHandler) Handle(ctx context.Context) error {
+	return h.run(ctx)
+}
+
+func (h *enqueuerHandler) HandleError(err error) {
+	log15.Error("An error occurred in a background task", "handler", "insights_enqueuer", "error", err)
+}
+
+// AdjustInterval doubles the interval, up to maxEnqueuerBackoff, after a run that stopped because
+// the query runner queue is full, and restores the default interval after any other run. An
+// interval set in the site configuration takes precedence.
+func (h *enqueuerHandler) AdjustInterval(interval time.Duration, err error) time.Duration {
+	if !errors.Is(err, dbworkerstore.ErrQueueFull) {
+		return enqueuerInterval
+	}
+	if interval *= 2; interval > maxEnqueuerBackoff {
+		interval = maxEnqueuerBackoff
+	}
+	return interval
+}
+
+// enqueuerIntervalFromSiteConfig returns the interval of the insights enqueuer set in the site
+// configuration, or zero to use the default interval.
+func enqueuerIntervalFromSiteConfig() time.Duration {
+	return time.Duration(conf.Get().InsightsEnqueuerIntervalSeconds) * time.Second
 }
 
 const queryJobOffsetTime = 30 * time.Second
 
-// discoverAndEnqueueInsights discovers insights defined in the given setting store from user/org/global
-// settings and enqueues them to be executed and have insights recorded.
+// recordingSchedule maps series IDs to the time at which the series is next due to be recorded.
+// Series that are not in the schedule are due immediately, unless they have a data point in their
+// current interval already.
+//
+// The schedule is kept in memory only. After a restart the series that have no data point in their
+// current interval are due again, and the idempotency key of the enqueued job, which names the
+// interval being recorded, prevents the series from being recorded twice within the same interval.
+type recordingSchedule map[string]time.Time
+
+// enqueueScope restricts the series enqueued by a run of the insight enqueuer, so that runs
+// triggered by a change only enqueue the series the change is about. The zero value enqueues every
+// due series.
+type enqueueScope struct {
+	// filter restricts the discovered insights. Runs restricted by a filter do not forget the
+	// series that they do not discover.
+	filter discovery.InsightFilterArgs
+
+	// newSeriesOnly restricts the run to the series that are not in the schedule yet.
+	newSeriesOnly bool
+}
+
+// seriesPointStore looks up the data points recorded for series. It is implemented by store.Store.
+type seriesPointStore interface {
+	LatestSeriesPointTimes(ctx context.Context, seriesIDs []string, since time.Time) (map[string]time.Time, error)
+}
+
+// Causes of the failures to enqueue series, as recorded in the enqueueFailureStore.
+const (
+	enqueueFailureInvalid   = "invalid"    // the series definition is invalid
+	enqueueFailureOverLimit = "over_limit" // the series exceeds the insight limits of the license
+	enqueueFailureQueueFull = "queue_full" // the query runner queue is full
+	enqueueFailureEnqueue   = "enqueue"    // the job could not be enqueued
+)
+
+// enqueueFailureStore records the series that the enqueuer failed to enqueue, so that failures are
+// not only logged as part of the error of a run. It is implemented by store.Store.
+type enqueueFailureStore interface {
+	RecordSeriesEnqueueFailure(ctx context.Context, seriesID, cause string, err error) error
+	ClearSeriesEnqueueFailures(ctx context.Context, seriesIDs []string) error
+	SeriesEnqueueFailures(ctx context.Context, opts store.SeriesEnqueueFailuresOpts) ([]store.SeriesEnqueueFailure, error)
+}
+
+// countingEnqueueFailureStore counts the failures recorded in the underlying store by cause.
+type countingEnqueueFailureStore struct {
+	enqueueFailureStore
+	failures *prometheus.CounterVec
+}
+
+func (s *countingEnqueueFailureStore) RecordSeriesEnqueueFailure(ctx context.Context, seriesID, cause string, err error) error {
+	s.failures.WithLabelValues(cause).Inc()
+	return s.enqueueFailureStore.RecordSeriesEnqueueFailure(ctx, seriesID, cause, err)
+}
+
+// countFailingSeries sets the given gauge to the number of series whose most recent attempt to be
+// enqueued failed, by cause. The failures are persisted, so the gauge survives restarts.
+func countFailingSeries(ctx context.Context, failureStore enqueueFailureStore, failingSeries *prometheus.GaugeVec) error {
+	failures, err := failureStore.SeriesEnqueueFailures(ctx, store.SeriesEnqueueFailuresOpts{})
+	if err != nil {
+		return errors.Wrap(err, "SeriesEnqueueFailures")
+	}
+	counts := map[string]int{enqueueFailureInvalid: 0, enqueueFailureOverLimit: 0, enqueueFailureQueueFull: 0, enqueueFailureEnqueue: 0}
+	for _, f := range failures {
+		counts[f.Cause]++
+	}
+	for cause, count := range counts {
+		failingSeries.WithLabelValues(cause).Set(float64(count))
+	}
+	return nil
+}
+
+// seriesCostEstimator estimates the cost of the search for the current data point of a series. It is
+// implemented by discovery.CostEstimator.
+type seriesCostEstimator interface {
+	SeriesCost(ctx context.Context, series insights.TimeSeries) (priority.Cost, error)
+}
+
+// discoverAndEnqueueInsights discovers insights defined in the given insight store, or in user/org/global
+// settings if they have not been migrated yet, and enqueues the series that are due according to the given schedule to be executed and
+// have insights recorded. Search series that only differ in their pattern are batched into a single search. The schedule is updated with
+// the next recording time of enqueued series, and of series not in the schedule that have a data point in their current interval in the
+// given point store already. The given scope restricts the discovered insights and the enqueued series.
+//
+// Insights and series beyond the given limits are not enqueued (see discovery.ApplyLimits). Series that are invalid, over the limits, or
+// fail to be enqueued are recorded in the failure store with their cause, and are cleared from it once they are enqueued or no longer
+// exist. The cost of the enqueued jobs is estimated by the given estimator.
+//
+// Recording intervals are aligned in the time zone of the given calendar, and series recorded on
+// business days only are not due on the other days of the calendar.
 func discoverAndEnqueueInsights(
 	ctx context.Context,
 	now func() time.Time,
+	insightStore discovery.InsightStore,
 	settingStore discovery.SettingStore,
 	loader insights.Loader,
+	failureStore enqueueFailureStore,
+	pointStore seriesPointStore,
+	limits discovery.InsightLimits,
+	calendar insights.RecordingCalendar,
+	estimator seriesCostEstimator,
+	schedule recordingSchedule,
+	scope enqueueScope,
 	enqueueQueryRunnerJob func(ctx context.Context, job *queryrunner.Job) error,
+	enqueueWebhookRunnerJob func(ctx context.Context, job *webhookrunner.Job) error,
 ) error {
-	foundInsights, err := discovery.Discover(ctx, settingStore, loader, discovery.InsightFilterArgs{})
+	filter := scope.filter
+	if limits.MaxInsights > 0 {
+		// Which insights are within the limit depends on every insight, so the run cannot be
+		// restricted to some of them.
+		filter = discovery.InsightFilterArgs{}
+	}
+	foundInsights, err := discovery.Discover(ctx, insightStore, settingStore, loader, filter)
 	if err != nil {
 		return errors.Wrap(err, "Discover")
 	}
+	foundInsights, overLimit := discovery.ApplyLimits(foundInsights, limits)
 
 	// Deduplicate series that may be unique (e.g. different name/description) but do not have
-	// unique data (i.e. use the same exact search query or webhook URL.)
+	// unique data (i.e. use the same exact search query or webhook URL.) Such series are recorded
+	// at the shortest of their intervals, and on business days only if all of them are. Series that
+	// are not enqueued because they are invalid or over the limits are skipped.
 	var (
-		uniqueSeries = map[string]insights.TimeSeries{}
-		multi        error
-		offset       time.Duration
+		uniqueSeries    = map[string]insights.TimeSeries{}
+		sortedSeriesIDs []string
+		skippedSeries   = map[string]struct{}{}
+		multi           error
 	)
+	recordFailure := func(seriesID, cause string, err error) {
+		if recordErr := failureStore.RecordSeriesEnqueueFailure(ctx, seriesID, cause, err); recordErr != nil {
+			multi = multierror.Append(multi, errors.Wrap(recordErr, "RecordSeriesEnqueueFailure"))
+		}
+	}
+	// batchCost estimates the cost of searching for the given series at once, which is that of its
+	// most expensive search. Series whose cost cannot be estimated are assumed to search every
+	// indexed repository.
+	batchCost := func(batch []dueSeries) priority.Cost {
+		var max priority.Cost
+		for _, d := range batch {
+			cost, err := estimator.SeriesCost(ctx, d.series)
+			if err != nil {
+				multi = multierror.Append(multi, errors.Wrapf(err, "estimating cost of series %q", d.seriesID))
+				cost = priority.Indexed
+			}
+			if cost > max {
+				max = cost
+			}
+		}
+		return max
+	}
 	for _, insight := range foundInsights {
 		for _, series := range insight.Series {
 			seriesID := discovery.Encode(series)
-			if err != nil {
+			if err := discovery.ValidateSeries(series); err != nil {
+				// Invalid series are not recorded, but do not prevent other series from being recorded.
+				err = errors.Wrapf(err, "series %q of insight %q", series.Name, insight.ID)
 				multi = multierror.Append(multi, err)
+				if _, ok := skippedSeries[seriesID]; !ok {
+					skippedSeries[seriesID] = struct{}{}
+					recordFailure(seriesID, enqueueFailureInvalid, err)
+				}
 				continue
 			}
-			_, enqueuedAlready := uniqueSeries[seriesID]
-			if enqueuedAlready {
-				continue
+			existing, ok := uniqueSeries[seriesID]
+			if !ok {
+				sortedSeriesIDs = append(sortedSeriesIDs, seriesID)
+			} else {
+				businessDaysOnly := series.BusinessDaysOnly && existing.BusinessDaysOnly
+				if !series.Interval.Shorter(existing.Interval) {
+					series = existing
+				}
+				series.BusinessDaysOnly = businessDaysOnly
 			}
 			uniqueSeries[seriesID] = series
+		}
+	}
+	for _, problem := range overLimit {
+		// The series may be within the limits as part of another insight.
+		if _, ok := uniqueSeries[problem.SeriesID]; ok {
+			continue
+		}
+		if _, ok := skippedSeries[problem.SeriesID]; !ok {
+			skippedSeries[problem.SeriesID] = struct{}{}
+			recordFailure(problem.SeriesID, enqueueFailureOverLimit, errors.New(problem.Problem))
+		}
+	}
+
+	// Forget series that no longer exist. A filtered run does not discover every series, so it
+	// cannot tell which series no longer exist.
+	if filter.Unfiltered() {
+		for seriesID := range schedule {
+			if _, ok := uniqueSeries[seriesID]; !ok {
+				delete(schedule, seriesID)
+			}
+		}
+		if !scope.newSeriesOnly {
+			if err := clearRemovedSeriesFailures(ctx, failureStore, uniqueSeries, skippedSeries); err != nil {
+				multi = multierror.Append(multi, err)
+			}
+		}
+	}
+
+	// Series that are not in the schedule yet, e.g. after a restart, are not due if they have been
+	// recorded in their current interval already.
+	if err := scheduleRecordedSeries(ctx, now(), calendar, pointStore, schedule, sortedSeriesIDs, uniqueSeries); err != nil {
+		multi = multierror.Append(multi, err)
+	}
+
+	var due []dueSeries
+	for _, seriesID := range sortedSeriesIDs {
+		if discovery.IsDerivedSeries(seriesID) {
+			// Derived series are recorded by the derived series recorder instead.
+			continue
+		}
+		series := uniqueSeries[seriesID]
+		current := now()
+		if nextRecording, ok := schedule[seriesID]; ok && (scope.newSeriesOnly || current.Before(nextRecording)) {
+			continue
+		}
+		if series.BusinessDaysOnly && !calendar.IsBusinessDay(current) {
+			// The series stays due, and is recorded on the next business day instead.
+			continue
+		}
+		due = append(due, dueSeries{
+			index:         len(due),
+			seriesID:      seriesID,
+			series:        series,
+			current:       current,
+			intervalStart: calendar.Start(series.Interval, current),
+		})
+	}
+
+	var offset time.Duration
+	for _, batch := range batchDueSeries(due) {
+		first := batch[0]
 
-			// Enqueue jobs for each unique series, offsetting each job execution by a minute so we
-			// don't execute all queries at once and harm search performance in general.
-			processAfter := now().Add(offset)
-			offset += queryJobOffsetTime
+		// Enqueue jobs for each batch of due series, offsetting each job execution by a minute so
+		// we don't execute all queries at once and harm search performance in general.
+		processAfter := first.current.Add(offset)
+		offset += queryJobOffsetTime
+		// Guards against enqueueing the series twice for the same interval when the enqueuer is
+		// restarted, e.g. after a crash.
+		idempotencyKey := fmt.Sprintf("insight-enqueuer:%s:%s", first.seriesID, first.intervalStart.Format(time.RFC3339))
+		switch {
+		case first.series.Webhook != "":
+			err = enqueueWebhookRunnerJob(ctx, &webhookrunner.Job{
+				SeriesID:       first.seriesID,
+				WebhookURL:     first.series.Webhook,
+				ProcessAfter:   &processAfter,
+				State:          "queued",
+				IdempotencyKey: idempotencyKey,
+			})
+		case len(batch) == 1:
 			err = enqueueQueryRunnerJob(ctx, &queryrunner.Job{
-				SeriesID:     seriesID,
-				SearchQuery:  withCountUnlimited(series.Query),
-				ProcessAfter: &processAfter,
-				State:        "queued",
-				Priority:     int(priority.High),
-				Cost:         int(priority.Indexed),
+				SeriesID:       first.seriesID,
+				SearchQuery:    queryrunner.WithCountUnlimited(discovery.ScopedQuery(first.series)),
+				ProcessAfter:   &processAfter,
+				State:          "queued",
+				Priority:       int(priority.High),
+				Cost:           int(batchCost(batch)),
+				IdempotencyKey: idempotencyKey,
 			})
-			if err != nil {
-				multi = multierror.Append(multi, err)
+		default:
+			err = enqueueBatchedQueryRunnerJob(ctx, batch, processAfter, batchCost(batch), enqueueQueryRunnerJob)
+		}
+		if errors.Is(err, dbworkerstore.ErrQueueFull) {
+			// Back off until the next run; the query runner needs to catch up first.
+			for _, d := range batch {
+				recordFailure(d.seriesID, enqueueFailureQueueFull, err)
 			}
+			return multierror.Append(multi, err)
+		}
+		if err != nil {
+			multi = multierror.Append(multi, err)
+			for _, d := range batch {
+				recordFailure(d.seriesID, enqueueFailureEnqueue, err)
+			}
+			continue
+		}
+		seriesIDs := make([]string, 0, len(batch))
+		for _, d := range batch {
+			schedule[d.seriesID] = calendar.Next(d.series.Interval, d.current)
+			seriesIDs = append(seriesIDs, d.seriesID)
+		}
+		if err := failureStore.ClearSeriesEnqueueFailures(ctx, seriesIDs); err != nil {
+			multi = multierror.Append(multi, errors.Wrap(err, "ClearSeriesEnqueueFailures"))
 		}
 	}
 	return multi
 }
 
-// withCountUnlimited adds `count:9999999` to the given search query string iff `count:` does not
-// exist in the query string. This is extremely important as otherwise the number of results our
-// search query would return would be incomplete and fluctuate.
-//
-// TODO(slimsag): future: we should pull in the search query parser to avoid cases where `count:`
-// is actually e.g. a search query like `content:"count:"`.
-func withCountUnlimited(s string) string {
-	if strings.Contains(s, "count:") {
-		return s
+// scheduleRecordedSeries schedules the series that are not in the schedule but have a data point in
+// their current interval at the start of the next interval after that data point. Intervals are
+// aligned in the time zone of the given calendar.
+func scheduleRecordedSeries(ctx context.Context, current time.Time, calendar insights.RecordingCalendar, pointStore seriesPointStore, schedule recordingSchedule, seriesIDs []string, uniqueSeries map[string]insights.TimeSeries) error {
+	var (
+		unscheduled []string
+		since       time.Time
+	)
+	for _, seriesID := range seriesIDs {
+		if _, ok := schedule[seriesID]; ok {
+			continue
+		}
+		start := calendar.Start(uniqueSeries[seriesID].Interval, current)
+		if len(unscheduled) == 0 || start.Before(since) {
+			since = start
+		}
+		unscheduled = append(unscheduled, seriesID)
+	}
+	if len(unscheduled) == 0 {
+		return nil
+	}
+
+	latest, err := pointStore.LatestSeriesPointTimes(ctx, unscheduled, since)
+	if err != nil {
+		return errors.Wrap(err, "LatestSeriesPointTimes")
+	}
+	for _, seriesID := range unscheduled {
+		recorded, ok := latest[seriesID]
+		if !ok {
+			continue
+		}
+		interval := uniqueSeries[seriesID].Interval
+		if recorded.Before(calendar.Start(interval, current)) {
+			continue
+		}
+		schedule[seriesID] = calendar.Next(interval, recorded)
+	}
+	return nil
+}
+
+// clearRemovedSeriesFailures clears the failures of the series that are neither valid nor skipped
+// series of the discovered insights, e.g. because the query of an invalid series was fixed.
+func clearRemovedSeriesFailures(ctx context.Context, failureStore enqueueFailureStore, uniqueSeries map[string]insights.TimeSeries, skippedSeries map[string]struct{}) error {
+	failures, err := failureStore.SeriesEnqueueFailures(ctx, store.SeriesEnqueueFailuresOpts{})
+	if err != nil {
+		return errors.Wrap(err, "SeriesEnqueueFailures")
+	}
+	var removed []string
+	for _, f := range failures {
+		_, valid := uniqueSeries[f.SeriesID]
+		_, skipped := skippedSeries[f.SeriesID]
+		if !valid && !skipped {
+			removed = append(removed, f.SeriesID)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	return errors.Wrap(failureStore.ClearSeriesEnqueueFailures(ctx, removed), "ClearSeriesEnqueueFailures")
+}
+
+// maxSeriesPerBatch is the maximum number of series whose search queries are batched into a single
+// search. Larger batches search for more patterns at once, which makes each search slower and more
+// likely to hit result limits.
+const maxSeriesPerBatch = 10
+
+// dueSeries is a series that is due to be recorded.
+type dueSeries struct {
+	index    int // the position of the series in the due series
+	seriesID string
+	series   insights.TimeSeries
+	current  time.Time // the time at which the series was found to be due
+
+	// intervalStart is the start of the recording interval of the series at the current time.
+	intervalStart time.Time
+}
+
+// batchDueSeries groups the given due series into batches whose search queries can be searched for
+// at once (see queryrunner.BatchQueries). Only series that record the same interval are batched, so
+// that the batch records a single interval. Webhook series and series generated from capture
+// groups or grouped by attribution are never batched. Batches are ordered by their first series.
+func batchDueSeries(due []dueSeries) [][]dueSeries {
+	var (
+		batches    [][]dueSeries
+		groups     = map[string][]dueSeries{} // interval start -> series recording that interval
+		groupOrder []string
+	)
+	for _, d := range due {
+		if d.series.Webhook != "" || discovery.IsSplitSeries(d.seriesID) {
+			batches = append(batches, []dueSeries{d})
+			continue
+		}
+		intervalStart := d.intervalStart.Format(time.RFC3339)
+		if _, ok := groups[intervalStart]; !ok {
+			groupOrder = append(groupOrder, intervalStart)
+		}
+		groups[intervalStart] = append(groups[intervalStart], d)
+	}
+
+	for _, intervalStart := range groupOrder {
+		group := groups[intervalStart]
+		searchQueries := make([]string, 0, len(group))
+		for _, d := range group {
+			searchQueries = append(searchQueries, queryrunner.WithCountUnlimited(discovery.ScopedQuery(d.series)))
+		}
+		for _, indexes := range queryrunner.BatchQueries(searchQueries, maxSeriesPerBatch) {
+			batch := make([]dueSeries, 0, len(indexes))
+			for _, i := range indexes {
+				batch = append(batch, group[i])
+			}
+			batches = append(batches, batch)
+		}
+	}
+
+	sort.Slice(batches, func(i, j int) bool {
+		return batches[i][0].index < batches[j][0].index
+	})
+	return batches
+}
+
+// enqueueBatchedQueryRunnerJob enqueues a single query runner job which searches for the search
+// queries of all the given series at once, and records the matches for each of them.
+func enqueueBatchedQueryRunnerJob(ctx context.Context, batch []dueSeries, processAfter time.Time, cost priority.Cost, enqueueQueryRunnerJob func(ctx context.Context, job *queryrunner.Job) error) error {
+	var (
+		searchQueries = make([]string, 0, len(batch))
+		batchedSeries = make([]queryrunner.BatchedSeries, 0, len(batch))
+	)
+	for _, d := range batch {
+		searchQuery := queryrunner.WithCountUnlimited(discovery.ScopedQuery(d.series))
+		searchQueries = append(searchQueries, searchQuery)
+		batchedSeries = append(batchedSeries, queryrunner.BatchedSeries{SeriesID: d.seriesID, SearchQuery: searchQuery})
 	}
-	return s + " count:9999999"
+	batchSearchQuery, err := queryrunner.BatchSearchQuery(searchQueries)
+	if err != nil {
+		return errors.Wrap(err, "BatchSearchQuery")
+	}
+
+	batchSeriesID := queryrunner.BatchSeriesID(batchSearchQuery)
+	first := batch[0]
+	return enqueueQueryRunnerJob(ctx, &queryrunner.Job{
+		SeriesID:       batchSeriesID,
+		SearchQuery:    batchSearchQuery,
+		BatchedSeries:  batchedSeries,
+		ProcessAfter:   &processAfter,
+		State:          "queued",
+		Priority:       int(priority.High),
+		Cost:           int(cost),
+		IdempotencyKey: fmt.Sprintf("insight-enqueuer:%s:%s", batchSeriesID, first.intervalStart.Format(time.RFC3339)),
+	})
 }
@@ -3,16 +3,26 @@ package background
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/insights/priority"
 
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-multierror"
 	"github.com/hexops/autogold"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/webhookrunner"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
 )
 
 var testRealGlobalSettings = &api.Settings{ID: 1, Contents: `{
@@ -50,6 +60,10 @@ var testRealGlobalSettings = &api.Settings{ID: 1, Contents: `{
 			  {
 				"label": "duplicate",
 				"search": "gitserver.Close",
+			  },
+			  {
+				"label": "webhook",
+				"webhook": "https://example.com/getData",
 			  }
 			]
 		  }
@@ -60,9 +74,10 @@ var testRealGlobalSettings = &api.Settings{ID: 1, Contents: `{
 // Test_discoverAndEnqueueInsights tests that insight discovery and job enqueueing works and
 // adheres to a few properties:
 //
-// 1. Webhook insights are not enqueued (not yet supported.)
+// 1. Webhook insights are enqueued for the webhook runner.
 // 2. Duplicate insights are deduplicated / do not submit multiple jobs.
 // 3. Jobs are scheduled not to all run at the same time.
+// 4. Search series that only differ in their pattern are batched into a single search.
 //
 func Test_discoverAndEnqueueInsights(t *testing.T) {
 	// Setup the setting store and job enqueuer mocks.
@@ -74,6 +89,11 @@ func Test_discoverAndEnqueueInsights(t *testing.T) {
 		enqueued = append(enqueued, job)
 		return nil
 	}
+	var enqueuedWebhooks []*webhookrunner.Job
+	enqueueWebhookRunnerJob := func(ctx context.Context, job *webhookrunner.Job) error {
+		enqueuedWebhooks = append(enqueuedWebhooks, job)
+		return nil
+	}
 
 	loader := insights.NewMockLoader()
 
@@ -84,7 +104,7 @@ func Test_discoverAndEnqueueInsights(t *testing.T) {
 	}
 	clock := func() time.Time { return now }
 
-	if err := discoverAndEnqueueInsights(ctx, clock, settingStore, loader, enqueueQueryRunnerJob); err != nil {
+	if err := discoverAndEnqueueInsights(ctx, clock, discovery.NewMockInsightStore(), settingStore, loader, store.NewMockInterface(), store.NewMockInterface(), discovery.InsightLimits{}, insights.RecordingCalendar{}, discovery.NewCostEstimator(discovery.NewMockRepoCounter()), recordingSchedule{}, enqueueScope{}, enqueueQueryRunnerJob, enqueueWebhookRunnerJob); err != nil {
 		t.Fatal(err)
 	}
 
@@ -95,11 +115,35 @@ func Test_discoverAndEnqueueInsights(t *testing.T) {
 	}
 	autogold.Want("0", `[
   {
-    "SeriesID": "s:087855E6A24440837303FD8A252E9893E8ABDFECA55B61AC83DA1B521906626E",
-    "SearchQuery": "errorf count:9999999",
+    "SeriesID": "b:C52FC657AC0430D421E14C2DA11C739F598FC14A7F7DF2AF4A717FD199A689FD",
+    "SearchQuery": "count:all (errorf OR fmt.Printf OR gitserver.Exec OR gitserver.Close)",
     "RecordTime": null,
     "Cost": 500,
     "Priority": 10,
+    "PinnedRepo": null,
+    "PinnedRevision": null,
+    "BatchedSeries": [
+      {
+        "seriesId": "s:087855E6A24440837303FD8A252E9893E8ABDFECA55B61AC83DA1B521906626E",
+        "searchQuery": "errorf count:all"
+      },
+      {
+        "seriesId": "s:7FBD292BF97936C4B6397688CFFB05DEA95E650C3D5B653AAEA8F77BBD25CE93",
+        "searchQuery": "fmt.Printf count:all"
+      },
+      {
+        "seriesId": "s:FB8CFBB7C7C28834957FBE1B830EDD79C5E710FD55B0ACF246C0D7267C5462B4",
+        "searchQuery": "gitserver.Exec count:all"
+      },
+      {
+        "seriesId": "s:2B55C7CE2EB30BFFAF1F0276E525B36BB71908E3893A27F416F62A3E23542566",
+        "searchQuery": "gitserver.Close count:all"
+      }
+    ],
+    "SearchTimeout": 0,
+    "ShardRepos": null,
+    "Version": 0,
+    "IdempotencyKey": "insight-enqueuer:b:C52FC657AC0430D421E14C2DA11C739F598FC14A7F7DF2AF4A717FD199A689FD:2020-03-01T00:00:00Z",
     "ID": 0,
     "State": "queued",
     "FailureMessage": null,
@@ -109,13 +153,19 @@ func Test_discoverAndEnqueueInsights(t *testing.T) {
     "NumResets": 0,
     "NumFailures": 0,
     "ExecutionLogs": null
-  },
+  }
+]`).Equal(t, string(enqueuedJSON))
+
+	enqueuedWebhooksJSON, err := json.MarshalIndent(enqueuedWebhooks, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	autogold.Want("webhooks", `[
   {
-    "SeriesID": "s:7FBD292BF97936C4B6397688CFFB05DEA95E650C3D5B653AAEA8F77BBD25CE93",
-    "SearchQuery": "fmt.Printf count:9999999",
+    "SeriesID": "w:F686B15DD7AAE81293119EB4C2DF1621D4AE5241BA0C19D6FA1BCB03483F4163",
+    "WebhookURL": "https://example.com/getData",
     "RecordTime": null,
-    "Cost": 500,
-    "Priority": 10,
+    "IdempotencyKey": "insight-enqueuer:w:F686B15DD7AAE81293119EB4C2DF1621D4AE5241BA0C19D6FA1BCB03483F4163:2020-03-01T00:00:00Z",
     "ID": 0,
     "State": "queued",
     "FailureMessage": null,
@@ -125,38 +175,511 @@ func Test_discoverAndEnqueueInsights(t *testing.T) {
     "NumResets": 0,
     "NumFailures": 0,
     "ExecutionLogs": null
-  },
-  {
-    "SeriesID": "s:FB8CFBB7C7C28834957FBE1B830EDD79C5E710FD55B0ACF246C0D7267C5462B4",
-    "SearchQuery": "gitserver.Exec count:9999999",
-    "RecordTime": null,
-    "Cost": 500,
-    "Priority": 10,
-    "ID": 0,
-    "State": "queued",
-    "FailureMessage": null,
-    "StartedAt": null,
-    "FinishedAt": null,
-    "ProcessAfter": "2020-03-01T00:01:00Z",
-    "NumResets": 0,
-    "NumFailures": 0,
-    "ExecutionLogs": null
-  },
-  {
-    "SeriesID": "s:2B55C7CE2EB30BFFAF1F0276E525B36BB71908E3893A27F416F62A3E23542566",
-    "SearchQuery": "gitserver.Close count:9999999",
-    "RecordTime": null,
-    "Cost": 500,
-    "Priority": 10,
-    "ID": 0,
-    "State": "queued",
-    "FailureMessage": null,
-    "StartedAt": null,
-    "FinishedAt": null,
-    "ProcessAfter": "2020-03-01T00:01:30Z",
-    "NumResets": 0,
-    "NumFailures": 0,
-    "ExecutionLogs": null
   }
-]`).Equal(t, string(enqueuedJSON))
+]`).Equal(t, string(enqueuedWebhooksJSON))
+}
+
+func noopEnqueueWebhookRunnerJob(ctx context.Context, job *webhookrunner.Job) error {
+	return nil
+}
+
+// Test_discoverAndEnqueueInsightsQueueFull tests that enqueueing stops once the query runner
+// queue reports that it is full.
+func Test_discoverAndEnqueueInsightsQueueFull(t *testing.T) {
+	ctx := context.Background()
+	settingStore := discovery.NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultReturn(testRealGlobalSettings, nil)
+	var calls int
+	enqueueQueryRunnerJob := func(ctx context.Context, job *queryrunner.Job) error {
+		calls++
+		return dbworkerstore.ErrQueueFull
+	}
+
+	failureStore := store.NewMockInterface()
+	err := discoverAndEnqueueInsights(ctx, time.Now, discovery.NewMockInsightStore(), settingStore, insights.NewMockLoader(), failureStore, store.NewMockInterface(), discovery.InsightLimits{}, insights.RecordingCalendar{}, discovery.NewCostEstimator(discovery.NewMockRepoCounter()), recordingSchedule{}, enqueueScope{}, enqueueQueryRunnerJob, noopEnqueueWebhookRunnerJob)
+	if !errors.Is(err, dbworkerstore.ErrQueueFull) {
+		t.Fatalf("unexpected error. want=%q have=%q", dbworkerstore.ErrQueueFull, err)
+	}
+	if calls != 1 {
+		t.Errorf("unexpected number of enqueue attempts. want=%d have=%d", 1, calls)
+	}
+
+	// The series of the batch that did not fit in the queue are recorded as failures.
+	failures := failureStore.RecordSeriesEnqueueFailureFunc.History()
+	if len(failures) == 0 {
+		t.Fatalf("expected enqueue failures to be recorded")
+	}
+	for _, call := range failures {
+		if call.Arg2 != enqueueFailureQueueFull {
+			t.Errorf("unexpected cause of failure of series %q. want=%q have=%q", call.Arg1, enqueueFailureQueueFull, call.Arg2)
+		}
+	}
+}
+
+func TestEnqueuerHandlerAdjustInterval(t *testing.T) {
+	queueFull := multierror.Append(errors.New("failed to count failing series"), dbworkerstore.ErrQueueFull)
+	handler := &enqueuerHandler{}
+
+	for _, testCase := range []struct {
+		name     string
+		interval time.Duration
+		err      error
+		want     time.Duration
+	}{
+		{name: "queue full", interval: enqueuerInterval, err: queueFull, want: 2 * enqueuerInterval},
+		{name: "queue still full", interval: 8 * enqueuerInterval, err: queueFull, want: maxEnqueuerBackoff},
+		{name: "queue drained", interval: maxEnqueuerBackoff, err: nil, want: enqueuerInterval},
+		{name: "other error", interval: 4 * enqueuerInterval, err: errors.New("oops"), want: enqueuerInterval},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if have := handler.AdjustInterval(testCase.interval, testCase.err); have != testCase.want {
+				t.Errorf("unexpected interval. want=%s have=%s", testCase.want, have)
+			}
+		})
+	}
+}
+
+// Test_discoverAndEnqueueInsightsSchedule tests that series are only enqueued once they are due
+// according to their recording interval.
+func Test_discoverAndEnqueueInsightsSchedule(t *testing.T) {
+	ctx := context.Background()
+	settingStore := discovery.NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultReturn(&api.Settings{ID: 1, Contents: `{
+		"insights": [
+			{
+				"title": "intervals",
+				"description": "series with different intervals",
+				"series": [
+					{"label": "hourly", "search": "hourly", "interval": "hourly"},
+					{"label": "daily", "search": "daily"},
+					{"label": "weekly", "search": "weekly", "interval": "weekly"},
+					{"label": "weekly duplicate", "search": "hourly", "interval": "weekly"},
+				]
+			}
+		]
+	}`}, nil)
+	var enqueued []string
+	enqueueQueryRunnerJob := func(ctx context.Context, job *queryrunner.Job) error {
+		if len(job.BatchedSeries) == 0 {
+			enqueued = append(enqueued, job.SearchQuery)
+		}
+		for _, series := range job.BatchedSeries {
+			enqueued = append(enqueued, series.SearchQuery)
+		}
+		return nil
+	}
+
+	// A Sunday at noon.
+	now := time.Date(2020, time.March, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	schedule := recordingSchedule{}
+
+	for _, step := range []struct {
+		advance  time.Duration
+		expected []string
+	}{
+		{0, []string{"hourly count:all", "daily count:all", "weekly count:all"}},
+		{10 * time.Minute, nil},
+		{time.Hour, []string{"hourly count:all"}},
+		{12 * time.Hour, []string{"hourly count:all", "daily count:all", "weekly count:all"}},
+	} {
+		now = now.Add(step.advance)
+		enqueued = nil
+
+		if err := discoverAndEnqueueInsights(ctx, clock, discovery.NewMockInsightStore(), settingStore, insights.NewMockLoader(), store.NewMockInterface(), store.NewMockInterface(), discovery.InsightLimits{}, insights.RecordingCalendar{}, discovery.NewCostEstimator(discovery.NewMockRepoCounter()), schedule, enqueueScope{}, enqueueQueryRunnerJob, noopEnqueueWebhookRunnerJob); err != nil {
+			t.Fatalf("unexpected error enqueueing insights: %s", err)
+		}
+		if diff := cmp.Diff(step.expected, enqueued); diff != "" {
+			t.Errorf("unexpected enqueued queries at %s (-want +got):\n%s", now, diff)
+		}
+	}
+}
+
+// Test_discoverAndEnqueueInsightsBusinessDays tests that series recorded on business days only are
+// not enqueued on the other days of the recording calendar, and that intervals are aligned in the
+// time zone of the calendar.
+func Test_discoverAndEnqueueInsightsBusinessDays(t *testing.T) {
+	ctx := context.Background()
+	settingStore := discovery.NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultReturn(&api.Settings{ID: 1, Contents: `{
+		"insights": [
+			{
+				"title": "business days",
+				"series": [
+					{"label": "every day", "search": "every"},
+					{"label": "business days", "search": "business", "businessDaysOnly": true},
+				]
+			}
+		]
+	}`}, nil)
+	var (
+		enqueued        []string
+		idempotencyKeys []string
+	)
+	enqueueQueryRunnerJob := func(ctx context.Context, job *queryrunner.Job) error {
+		if len(job.BatchedSeries) == 0 {
+			enqueued = append(enqueued, job.SearchQuery)
+		}
+		for _, series := range job.BatchedSeries {
+			enqueued = append(enqueued, series.SearchQuery)
+		}
+		idempotencyKeys = append(idempotencyKeys, job.IdempotencyKey)
+		return nil
+	}
+
+	calendar := insights.RecordingCalendar{
+		Location:    time.FixedZone("UTC+10", 10*60*60),
+		WeekendDays: []time.Weekday{time.Saturday, time.Sunday},
+		Holidays:    []string{"2020-03-02"},
+	}
+	// Friday 20:00 UTC is already Saturday in the time zone of the calendar.
+	now := time.Date(2020, time.February, 28, 20, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	schedule := recordingSchedule{}
+
+	for _, step := range []struct {
+		advance  time.Duration
+		expected []string
+	}{
+		{0, []string{"every count:all"}},
+		{24 * time.Hour, []string{"every count:all"}},
+		{24 * time.Hour, []string{"every count:all"}}, // a holiday
+		{24 * time.Hour, []string{"every count:all", "business count:all"}},
+		{time.Hour, nil},
+	} {
+		now = now.Add(step.advance)
+		enqueued = nil
+
+		if err := discoverAndEnqueueInsights(ctx, clock, discovery.NewMockInsightStore(), settingStore, insights.NewMockLoader(), store.NewMockInterface(), store.NewMockInterface(), discovery.InsightLimits{}, calendar, discovery.NewCostEstimator(discovery.NewMockRepoCounter()), schedule, enqueueScope{}, enqueueQueryRunnerJob, noopEnqueueWebhookRunnerJob); err != nil {
+			t.Fatalf("unexpected error enqueueing insights: %s", err)
+		}
+		if diff := cmp.Diff(step.expected, enqueued); diff != "" {
+			t.Errorf("unexpected enqueued queries at %s (-want +got):\n%s", now, diff)
+		}
+	}
+
+	// The first job records the day that started at midnight in the time zone of the calendar.
+	if want, have := ":2020-02-29T00:00:00+10:00", idempotencyKeys[0]; !strings.HasSuffix(have, want) {
+		t.Errorf("unexpected idempotency key. want suffix=%s have=%s", want, have)
+	}
+}
+
+// Test_discoverAndEnqueueInsightsNewSeriesOnly tests that only series that are not in the
+// schedule yet are enqueued when enqueueing new series only, even if other series are due.
+func Test_discoverAndEnqueueInsightsNewSeriesOnly(t *testing.T) {
+	ctx := context.Background()
+	settingStore := discovery.NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultReturn(&api.Settings{ID: 1, Contents: `{
+		"insights": [
+			{
+				"title": "errors",
+				"series": [
+					{"label": "existing", "search": "errorf"},
+					{"label": "new", "search": "log15.Error"},
+				]
+			}
+		]
+	}`}, nil)
+	var enqueued []string
+	enqueueQueryRunnerJob := func(ctx context.Context, job *queryrunner.Job) error {
+		enqueued = append(enqueued, job.SearchQuery)
+		return nil
+	}
+
+	now := time.Now()
+	schedule := recordingSchedule{
+		discovery.Encode(insights.TimeSeries{Query: "errorf"}): now.Add(-time.Hour), // due
+	}
+	if err := discoverAndEnqueueInsights(ctx, func() time.Time { return now }, discovery.NewMockInsightStore(), settingStore, insights.NewMockLoader(), store.NewMockInterface(), store.NewMockInterface(), discovery.InsightLimits{}, insights.RecordingCalendar{}, discovery.NewCostEstimator(discovery.NewMockRepoCounter()), schedule, enqueueScope{newSeriesOnly: true}, enqueueQueryRunnerJob, noopEnqueueWebhookRunnerJob); err != nil {
+		t.Fatalf("unexpected error enqueueing insights: %s", err)
+	}
+	if diff := cmp.Diff([]string{"log15.Error count:all"}, enqueued); diff != "" {
+		t.Errorf("unexpected enqueued queries (-want +got):\n%s", diff)
+	}
+	if _, ok := schedule[discovery.Encode(insights.TimeSeries{Query: "log15.Error"})]; !ok {
+		t.Errorf("expected the new series to be scheduled")
+	}
+}
+
+// Test_discoverAndEnqueueInsightsRecordedSeries tests that series that are not in the schedule but
+// have been recorded in their current interval already are scheduled instead of enqueued.
+func Test_discoverAndEnqueueInsightsRecordedSeries(t *testing.T) {
+	ctx := context.Background()
+	settingStore := discovery.NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultReturn(&api.Settings{ID: 1, Contents: `{
+		"insights": [
+			{
+				"title": "errors",
+				"series": [
+					{"label": "recorded today", "search": "errorf"},
+					{"label": "recorded last hour", "search": "log15.Error", "interval": "hourly"},
+				]
+			}
+		]
+	}`}, nil)
+	var enqueued []string
+	enqueueQueryRunnerJob := func(ctx context.Context, job *queryrunner.Job) error {
+		enqueued = append(enqueued, job.SearchQuery)
+		return nil
+	}
+
+	now := time.Date(2020, time.March, 1, 12, 30, 0, 0, time.UTC)
+	daily := discovery.Encode(insights.TimeSeries{Query: "errorf"})
+	hourly := discovery.Encode(insights.TimeSeries{Query: "log15.Error"})
+	pointStore := store.NewMockInterface()
+	pointStore.LatestSeriesPointTimesFunc.SetDefaultHook(func(ctx context.Context, seriesIDs []string, since time.Time) (map[string]time.Time, error) {
+		if want := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC); !since.Equal(want) {
+			t.Errorf("unexpected since. want=%s have=%s", want, since)
+		}
+		return map[string]time.Time{
+			daily:  now.Add(-time.Hour),
+			hourly: now.Add(-time.Hour),
+		}, nil
+	})
+
+	schedule := recordingSchedule{}
+	if err := discoverAndEnqueueInsights(ctx, func() time.Time { return now }, discovery.NewMockInsightStore(), settingStore, insights.NewMockLoader(), store.NewMockInterface(), pointStore, discovery.InsightLimits{}, insights.RecordingCalendar{}, discovery.NewCostEstimator(discovery.NewMockRepoCounter()), schedule, enqueueScope{}, enqueueQueryRunnerJob, noopEnqueueWebhookRunnerJob); err != nil {
+		t.Fatalf("unexpected error enqueueing insights: %s", err)
+	}
+	if diff := cmp.Diff([]string{"log15.Error count:all"}, enqueued); diff != "" {
+		t.Errorf("unexpected enqueued queries (-want +got):\n%s", diff)
+	}
+	want := recordingSchedule{
+		daily:  time.Date(2020, time.March, 2, 0, 0, 0, 0, time.UTC),
+		hourly: time.Date(2020, time.March, 1, 13, 0, 0, 0, time.UTC),
+	}
+	if diff := cmp.Diff(want, schedule); diff != "" {
+		t.Errorf("unexpected schedule (-want +got):\n%s", diff)
+	}
+}
+
+// Test_discoverAndEnqueueInsightsFiltered tests that a filtered run only enqueues the series it
+// discovers, and does not forget the series that it does not discover.
+func Test_discoverAndEnqueueInsightsFiltered(t *testing.T) {
+	ctx := context.Background()
+	settingStore := discovery.NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultReturn(&api.Settings{ID: 1, Contents: `{
+		"insights": [
+			{
+				"title": "errors",
+				"series": [
+					{"label": "other", "search": "errorf"},
+					{"label": "filtered", "search": "log15.Error"},
+				]
+			}
+		]
+	}`}, nil)
+	var enqueued []string
+	enqueueQueryRunnerJob := func(ctx context.Context, job *queryrunner.Job) error {
+		enqueued = append(enqueued, job.SearchQuery)
+		return nil
+	}
+
+	now := time.Now()
+	other := discovery.Encode(insights.TimeSeries{Query: "errorf"})
+	filtered := discovery.Encode(insights.TimeSeries{Query: "log15.Error"})
+	schedule := recordingSchedule{other: now.Add(-time.Hour)} // due
+	scope := enqueueScope{filter: discovery.InsightFilterArgs{SeriesIDs: []string{filtered}}}
+	if err := discoverAndEnqueueInsights(ctx, func() time.Time { return now }, discovery.NewMockInsightStore(), settingStore, insights.NewMockLoader(), store.NewMockInterface(), store.NewMockInterface(), discovery.InsightLimits{}, insights.RecordingCalendar{}, discovery.NewCostEstimator(discovery.NewMockRepoCounter()), schedule, scope, enqueueQueryRunnerJob, noopEnqueueWebhookRunnerJob); err != nil {
+		t.Fatalf("unexpected error enqueueing insights: %s", err)
+	}
+	if diff := cmp.Diff([]string{"log15.Error count:all"}, enqueued); diff != "" {
+		t.Errorf("unexpected enqueued queries (-want +got):\n%s", diff)
+	}
+	if _, ok := schedule[other]; !ok {
+		t.Errorf("expected the series that was not discovered to remain scheduled")
+	}
+}
+
+// Test_discoverAndEnqueueInsightsInvalidSeries tests that series with invalid search queries are
+// reported, and do not prevent other series from being enqueued.
+func Test_discoverAndEnqueueInsightsInvalidSeries(t *testing.T) {
+	ctx := context.Background()
+	settingStore := discovery.NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultReturn(&api.Settings{ID: 1, Contents: `{
+		"insights": [
+			{
+				"title": "revisions",
+				"description": "series searching for revisions",
+				"series": [
+					{"label": "valid", "search": "errorf"},
+					{"label": "invalid", "search": "repo:sourcegraph@main errorf"},
+				]
+			}
+		]
+	}`}, nil)
+	var enqueued []string
+	enqueueQueryRunnerJob := func(ctx context.Context, job *queryrunner.Job) error {
+		enqueued = append(enqueued, job.SearchQuery)
+		return nil
+	}
+
+	// A failure of a series that no longer exists is cleared.
+	failureStore := store.NewMockInterface()
+	failureStore.SeriesEnqueueFailuresFunc.SetDefaultReturn([]store.SeriesEnqueueFailure{{SeriesID: "s:removed", Cause: enqueueFailureInvalid}}, nil)
+
+	err := discoverAndEnqueueInsights(ctx, time.Now, discovery.NewMockInsightStore(), settingStore, insights.NewMockLoader(), failureStore, store.NewMockInterface(), discovery.InsightLimits{}, insights.RecordingCalendar{}, discovery.NewCostEstimator(discovery.NewMockRepoCounter()), recordingSchedule{}, enqueueScope{}, enqueueQueryRunnerJob, noopEnqueueWebhookRunnerJob)
+	if err == nil || !strings.Contains(err.Error(), `series "invalid"`) {
+		t.Fatalf("unexpected error. want error for series %q have=%v", "invalid", err)
+	}
+	if diff := cmp.Diff([]string{"errorf count:all"}, enqueued); diff != "" {
+		t.Errorf("unexpected enqueued queries (-want +got):\n%s", diff)
+	}
+
+	var recorded []string
+	for _, call := range failureStore.RecordSeriesEnqueueFailureFunc.History() {
+		recorded = append(recorded, call.Arg1+" "+call.Arg2)
+	}
+	invalidSeriesID := discovery.Encode(insights.TimeSeries{Query: "repo:sourcegraph@main errorf"})
+	if diff := cmp.Diff([]string{invalidSeriesID + " " + enqueueFailureInvalid}, recorded); diff != "" {
+		t.Errorf("unexpected recorded failures (-want +got):\n%s", diff)
+	}
+	var cleared [][]string
+	for _, call := range failureStore.ClearSeriesEnqueueFailuresFunc.History() {
+		cleared = append(cleared, call.Arg1)
+	}
+	if diff := cmp.Diff([][]string{{"s:removed"}, {discovery.Encode(insights.TimeSeries{Query: "errorf"})}}, cleared); diff != "" {
+		t.Errorf("unexpected cleared failures (-want +got):\n%s", diff)
+	}
+}
+
+// Test_discoverAndEnqueueInsightsOverLimit tests that insights and series beyond the given limits
+// are not enqueued, and are recorded as failures.
+func Test_discoverAndEnqueueInsightsOverLimit(t *testing.T) {
+	ctx := context.Background()
+	settingStore := discovery.NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultReturn(&api.Settings{ID: 1, Contents: `{
+		"insights": [
+			{
+				"title": "errors",
+				"series": [
+					{"label": "errorf", "search": "errorf"},
+					{"label": "panic", "search": "panic("},
+				]
+			},
+			{
+				"title": "printing",
+				"series": [{"label": "printf", "search": "printf"}]
+			}
+		]
+	}`}, nil)
+	var enqueued []string
+	enqueueQueryRunnerJob := func(ctx context.Context, job *queryrunner.Job) error {
+		enqueued = append(enqueued, job.SearchQuery)
+		return nil
+	}
+	failureStore := store.NewMockInterface()
+
+	limits := discovery.InsightLimits{MaxInsights: 1, MaxSeriesPerInsight: 1}
+	err := discoverAndEnqueueInsights(ctx, time.Now, discovery.NewMockInsightStore(), settingStore, insights.NewMockLoader(), failureStore, store.NewMockInterface(), limits, insights.RecordingCalendar{}, discovery.NewCostEstimator(discovery.NewMockRepoCounter()), recordingSchedule{}, enqueueScope{}, enqueueQueryRunnerJob, noopEnqueueWebhookRunnerJob)
+	if err != nil {
+		t.Fatalf("unexpected error enqueueing insights: %s", err)
+	}
+	if diff := cmp.Diff([]string{"errorf count:all"}, enqueued); diff != "" {
+		t.Errorf("unexpected enqueued queries (-want +got):\n%s", diff)
+	}
+
+	var recorded []string
+	for _, call := range failureStore.RecordSeriesEnqueueFailureFunc.History() {
+		recorded = append(recorded, call.Arg1+" "+call.Arg2)
+	}
+	expected := []string{
+		discovery.Encode(insights.TimeSeries{Query: "panic("}) + " " + enqueueFailureOverLimit,
+		discovery.Encode(insights.TimeSeries{Query: "printf"}) + " " + enqueueFailureOverLimit,
+	}
+	if diff := cmp.Diff(expected, recorded); diff != "" {
+		t.Errorf("unexpected recorded failures (-want +got):\n%s", diff)
+	}
+}
+
+func Test_discoverAndEnqueueInsightsRepositoryScope(t *testing.T) {
+	ctx := context.Background()
+	settingStore := discovery.NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultReturn(&api.Settings{ID: 1, Contents: `{
+		"insights": [
+			{
+				"title": "all repositories",
+				"series": [{"label": "errors", "search": "errorf"}]
+			},
+			{
+				"title": "our services",
+				"repositories": ["github.com/sourcegraph/sourcegraph", "github.com/sourcegraph/zoekt"],
+				"series": [{"label": "errors", "search": "errorf"}]
+			}
+		]
+	}`}, nil)
+	var enqueued []string
+	enqueueQueryRunnerJob := func(ctx context.Context, job *queryrunner.Job) error {
+		enqueued = append(enqueued, job.SearchQuery)
+		return nil
+	}
+
+	err := discoverAndEnqueueInsights(ctx, time.Now, discovery.NewMockInsightStore(), settingStore, insights.NewMockLoader(), store.NewMockInterface(), store.NewMockInterface(), discovery.InsightLimits{}, insights.RecordingCalendar{}, discovery.NewCostEstimator(discovery.NewMockRepoCounter()), recordingSchedule{}, enqueueScope{}, enqueueQueryRunnerJob, noopEnqueueWebhookRunnerJob)
+	if err != nil {
+		t.Fatalf("unexpected error enqueueing insights: %s", err)
+	}
+	expected := []string{
+		"errorf count:all",
+		`repo:^(github\.com/sourcegraph/sourcegraph|github\.com/sourcegraph/zoekt)$ errorf count:all`,
+	}
+	if diff := cmp.Diff(expected, enqueued); diff != "" {
+		t.Errorf("unexpected enqueued queries (-want +got):\n%s", diff)
+	}
+}
+
+// Test_discoverAndEnqueueInsightsCost tests that the cost of enqueued jobs is estimated from the
+// number of repositories their series search.
+func Test_discoverAndEnqueueInsightsCost(t *testing.T) {
+	ctx := context.Background()
+	settingStore := discovery.NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultReturn(&api.Settings{ID: 1, Contents: `{
+		"insights": [
+			{
+				"title": "all repositories",
+				"series": [{"label": "errors", "search": "errorf"}]
+			},
+			{
+				"title": "our services",
+				"repositories": ["github.com/sourcegraph/sourcegraph", "github.com/sourcegraph/zoekt"],
+				"series": [{"label": "errors", "search": "errorf"}]
+			}
+		]
+	}`}, nil)
+	repoCounter := discovery.NewMockRepoCounter()
+	repoCounter.CountFunc.SetDefaultReturn(100, nil)
+	var costs []int
+	enqueueQueryRunnerJob := func(ctx context.Context, job *queryrunner.Job) error {
+		costs = append(costs, job.Cost)
+		return nil
+	}
+
+	err := discoverAndEnqueueInsights(ctx, time.Now, discovery.NewMockInsightStore(), settingStore, insights.NewMockLoader(), store.NewMockInterface(), store.NewMockInterface(), discovery.InsightLimits{}, insights.RecordingCalendar{}, discovery.NewCostEstimator(repoCounter), recordingSchedule{}, enqueueScope{}, enqueueQueryRunnerJob, noopEnqueueWebhookRunnerJob)
+	if err != nil {
+		t.Fatalf("unexpected error enqueueing insights: %s", err)
+	}
+	// The series scoped to 2 out of 100 repositories costs a fiftieth of the series searching all
+	// of them.
+	expected := []int{int(priority.Indexed), int(priority.Indexed) / 50}
+	if diff := cmp.Diff(expected, costs); diff != "" {
+		t.Errorf("unexpected enqueued job costs (-want +got):\n%s", diff)
+	}
+}
+
+func TestCountFailingSeries(t *testing.T) {
+	failureStore := store.NewMockInterface()
+	failureStore.SeriesEnqueueFailuresFunc.SetDefaultReturn([]store.SeriesEnqueueFailure{
+		{SeriesID: "s:one", Cause: enqueueFailureInvalid},
+		{SeriesID: "s:two", Cause: enqueueFailureInvalid},
+		{SeriesID: "s:three", Cause: enqueueFailureQueueFull},
+	}, nil)
+	failingSeries := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test"}, []string{"cause"})
+
+	if err := countFailingSeries(context.Background(), failureStore, failingSeries); err != nil {
+		t.Fatalf("unexpected error counting failing series: %s", err)
+	}
+	for cause, want := range map[string]float64{enqueueFailureInvalid: 2, enqueueFailureQueueFull: 1, enqueueFailureEnqueue: 0} {
+		if have := testutil.ToFloat64(failingSeries.WithLabelValues(cause)); have != want {
+			t.Errorf("unexpected number of failing series of cause %q. want=%v have=%v", cause, want, have)
+		}
+	}
 }
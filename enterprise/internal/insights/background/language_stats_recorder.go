@@ -0,0 +1,155 @@
+package background
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/inventory"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// newLanguageStatsRecorder returns a background goroutine which will periodically compute the
+// language statistics of the repositories of all language statistics insights, and record them
+// into the insights store from which they are served. Previously these were computed on every
+// page load of an insight.
+func newLanguageStatsRecorder(ctx context.Context, mainAppDB *sql.DB, insightsStore *store.Store, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_language_stats_recorder",
+		metrics.WithCountHelp("Total number of insights language statistics recorder executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "LanguageStatsRecorder.Run",
+		Metrics: metrics,
+	})
+
+	repoStore := database.Repos(mainAppDB)
+	recorder := &languageStatsRecorder{
+		statsStore: insightsStore,
+		listInsights: func(ctx context.Context) ([]insights.LangStatsInsight, error) {
+			return insights.GetLangStatsInsights(ctx, mainAppDB, insights.All)
+		},
+		getRepo: repoStore.GetByName,
+		resolveHEAD: func(ctx context.Context, repo api.RepoName) (api.CommitID, error) {
+			return git.ResolveRevision(ctx, repo, "HEAD", git.ResolveRevisionOptions{NoEnsureRevision: true})
+		},
+		getInventory: func(ctx context.Context, repo *types.Repo, commit api.CommitID) (*inventory.Inventory, error) {
+			return backend.Repos.GetInventory(ctx, repo, commit, false)
+		},
+	}
+
+	// TODO(insights): consider adding a setting for the recording interval
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 30*time.Minute, goroutine.NewHandlerWithErrorMessage(
+		"insights_language_stats_recorder",
+		recorder.Handler,
+	), operation)
+}
+
+// LanguageStatsStore is a subset of the API exposed by the store.Store (only the subset used by
+// the language statistics recorder.)
+type LanguageStatsStore interface {
+	LanguageStats(ctx context.Context, repoID api.RepoID) (*store.LanguageStats, error)
+	RecordLanguageStats(ctx context.Context, stats store.LanguageStats) error
+}
+
+// languageStatsRecorder records the language statistics of each repository configured in a
+// language statistics insight at the HEAD of its default branch. Statistics are only recomputed
+// once the HEAD of a repository has moved since they were last recorded.
+type languageStatsRecorder struct {
+	statsStore   LanguageStatsStore
+	listInsights func(ctx context.Context) ([]insights.LangStatsInsight, error)
+	getRepo      func(ctx context.Context, name api.RepoName) (*types.Repo, error)
+	resolveHEAD  func(ctx context.Context, repo api.RepoName) (api.CommitID, error)
+	getInventory func(ctx context.Context, repo *types.Repo, commit api.CommitID) (*inventory.Inventory, error)
+}
+
+func (r *languageStatsRecorder) Handler(ctx context.Context) error {
+	// 🚨 SECURITY: Statistics are recorded for every configured repository regardless of who
+	// configured it, so they are only served to users that can access the repository.
+	ctx = actor.WithInternalActor(ctx)
+
+	langStatsInsights, err := r.listInsights(ctx)
+	if err != nil {
+		return errors.Wrap(err, "GetLangStatsInsights")
+	}
+
+	// Multiple insights may describe the same repository, in which case its statistics are
+	// computed only once.
+	repoNames := map[string]struct{}{}
+	for _, insight := range langStatsInsights {
+		if insight.Repository != "" {
+			repoNames[insight.Repository] = struct{}{}
+		}
+	}
+	sortedRepoNames := make([]string, 0, len(repoNames))
+	for name := range repoNames {
+		sortedRepoNames = append(sortedRepoNames, name)
+	}
+	sort.Strings(sortedRepoNames)
+
+	// A failure to record one repository must not prevent recording the others.
+	var multi error
+	for _, name := range sortedRepoNames {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.record(ctx, api.RepoName(name)); err != nil {
+			multi = multierror.Append(multi, errors.Wrapf(err, "repo %s", name))
+		}
+	}
+	return multi
+}
+
+func (r *languageStatsRecorder) record(ctx context.Context, name api.RepoName) error {
+	repo, err := r.getRepo(ctx, name)
+	if err != nil {
+		return errors.Wrap(err, "GetByName")
+	}
+
+	commit, err := r.resolveHEAD(ctx, repo.Name)
+	if err != nil {
+		if errors.HasType(err, &gitserver.RevisionNotFoundError{}) {
+			// Empty repositories have no languages to record.
+			return nil
+		}
+		return errors.Wrap(err, "ResolveRevision")
+	}
+
+	previous, err := r.statsStore.LanguageStats(ctx, repo.ID)
+	if err != nil {
+		return errors.Wrap(err, "LanguageStats")
+	}
+	if previous != nil && previous.Commit == commit {
+		return nil
+	}
+
+	inv, err := r.getInventory(ctx, repo, commit)
+	if err != nil {
+		return errors.Wrap(err, "GetInventory")
+	}
+
+	log15.Debug("insights: recording language statistics", "repo", repo.Name, "commit", commit)
+	return r.statsStore.RecordLanguageStats(ctx, store.LanguageStats{
+		RepoID:    repo.ID,
+		RepoName:  string(repo.Name),
+		Commit:    commit,
+		Languages: inv.Languages,
+	})
+}
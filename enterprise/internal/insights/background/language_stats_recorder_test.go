@@ -0,0 +1,127 @@
+package background
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/inventory"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestLanguageStatsRecorder(t *testing.T) {
+	repos := map[api.RepoName]*types.Repo{
+		"github.com/sourcegraph/sourcegraph": {ID: 1, Name: "github.com/sourcegraph/sourcegraph"},
+		"github.com/sourcegraph/src-cli":     {ID: 2, Name: "github.com/sourcegraph/src-cli"},
+		"github.com/sourcegraph/empty":       {ID: 3, Name: "github.com/sourcegraph/empty"},
+		"github.com/sourcegraph/unchanged":   {ID: 4, Name: "github.com/sourcegraph/unchanged"},
+	}
+	heads := map[api.RepoName]api.CommitID{
+		"github.com/sourcegraph/sourcegraph": "c1",
+		"github.com/sourcegraph/src-cli":     "c2",
+		"github.com/sourcegraph/unchanged":   "c4",
+	}
+
+	statsStore := NewMockLanguageStatsStore()
+	statsStore.LanguageStatsFunc.SetDefaultHook(func(ctx context.Context, repoID api.RepoID) (*store.LanguageStats, error) {
+		if repoID == 4 {
+			return &store.LanguageStats{RepoID: 4, Commit: "c4"}, nil
+		}
+		return nil, nil
+	})
+
+	var inventoried []api.CommitID
+	r := &languageStatsRecorder{
+		statsStore: statsStore,
+		listInsights: func(ctx context.Context) ([]insights.LangStatsInsight, error) {
+			return []insights.LangStatsInsight{
+				{ID: "a", Repository: "github.com/sourcegraph/src-cli"},
+				{ID: "b", Repository: "github.com/sourcegraph/sourcegraph"},
+				{ID: "c", Repository: "github.com/sourcegraph/src-cli"},
+				{ID: "d", Repository: "github.com/sourcegraph/empty"},
+				{ID: "e", Repository: "github.com/sourcegraph/unchanged"},
+				{ID: "f"},
+			}, nil
+		},
+		getRepo: func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+			return repos[name], nil
+		},
+		resolveHEAD: func(ctx context.Context, repo api.RepoName) (api.CommitID, error) {
+			if commit, ok := heads[repo]; ok {
+				return commit, nil
+			}
+			return "", &gitserver.RevisionNotFoundError{Repo: repo, Spec: "HEAD"}
+		},
+		getInventory: func(ctx context.Context, repo *types.Repo, commit api.CommitID) (*inventory.Inventory, error) {
+			inventoried = append(inventoried, commit)
+			return &inventory.Inventory{Languages: []inventory.Lang{{Name: "Go", TotalBytes: uint64(repo.ID), TotalLines: 1}}}, nil
+		},
+	}
+
+	if err := r.Handler(context.Background()); err != nil {
+		t.Fatalf("unexpected error recording language stats: %s", err)
+	}
+
+	// Each repository is inventoried once, and only if its HEAD has moved.
+	if diff := cmp.Diff([]api.CommitID{"c1", "c2"}, inventoried); diff != "" {
+		t.Errorf("unexpected inventoried commits (-want +got):\n%s", diff)
+	}
+
+	var recorded []store.LanguageStats
+	for _, call := range statsStore.RecordLanguageStatsFunc.History() {
+		recorded = append(recorded, call.Arg1)
+	}
+	expected := []store.LanguageStats{
+		{
+			RepoID:    1,
+			RepoName:  "github.com/sourcegraph/sourcegraph",
+			Commit:    "c1",
+			Languages: []inventory.Lang{{Name: "Go", TotalBytes: 1, TotalLines: 1}},
+		},
+		{
+			RepoID:    2,
+			RepoName:  "github.com/sourcegraph/src-cli",
+			Commit:    "c2",
+			Languages: []inventory.Lang{{Name: "Go", TotalBytes: 2, TotalLines: 1}},
+		},
+	}
+	if diff := cmp.Diff(expected, recorded); diff != "" {
+		t.Errorf("unexpected recorded language stats (-want +got):\n%s", diff)
+	}
+}
+
+func TestLanguageStatsRecorderPartialFailure(t *testing.T) {
+	statsStore := NewMockLanguageStatsStore()
+
+	r := &languageStatsRecorder{
+		statsStore: statsStore,
+		listInsights: func(ctx context.Context) ([]insights.LangStatsInsight, error) {
+			return []insights.LangStatsInsight{{Repository: "a"}, {Repository: "b"}}, nil
+		},
+		getRepo: func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+			if name == "a" {
+				return nil, errors.New("repo not found")
+			}
+			return &types.Repo{ID: 2, Name: name}, nil
+		},
+		resolveHEAD: func(ctx context.Context, repo api.RepoName) (api.CommitID, error) {
+			return "c", nil
+		},
+		getInventory: func(ctx context.Context, repo *types.Repo, commit api.CommitID) (*inventory.Inventory, error) {
+			return &inventory.Inventory{}, nil
+		},
+	}
+
+	if err := r.Handler(context.Background()); err == nil {
+		t.Fatalf("expected an error recording language stats")
+	}
+	if value := len(statsStore.RecordLanguageStatsFunc.History()); value != 1 {
+		t.Errorf("unexpected number of recorded language stats. want=%d have=%d", 1, value)
+	}
+}
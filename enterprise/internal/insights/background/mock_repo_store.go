@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	api "github.com/sourcegraph/sourcegraph/internal/api"
+	database "github.com/sourcegraph/sourcegraph/internal/database"
 	types "github.com/sourcegraph/sourcegraph/internal/types"
 )
 
@@ -15,6 +16,9 @@ import (
 // github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background)
 // used for unit testing.
 type MockRepoStore struct {
+	// CountFunc is an instance of a mock function object controlling the
+	// behavior of the method Count.
+	CountFunc *RepoStoreCountFunc
 	// GetByNameFunc is an instance of a mock function object controlling
 	// the behavior of the method GetByName.
 	GetByNameFunc *RepoStoreGetByNameFunc
@@ -24,6 +28,11 @@ type MockRepoStore struct {
 // methods return zero values for all results, unless overwritten.
 func NewMockRepoStore() *MockRepoStore {
 	return &MockRepoStore{
+		CountFunc: &RepoStoreCountFunc{
+			defaultHook: func(context.Context, database.ReposListOptions) (int, error) {
+				return 0, nil
+			},
+		},
 		GetByNameFunc: &RepoStoreGetByNameFunc{
 			defaultHook: func(context.Context, api.RepoName) (*types.Repo, error) {
 				return nil, nil
@@ -36,12 +45,123 @@ func NewMockRepoStore() *MockRepoStore {
 // All methods delegate to the given implementation, unless overwritten.
 func NewMockRepoStoreFrom(i RepoStore) *MockRepoStore {
 	return &MockRepoStore{
+		CountFunc: &RepoStoreCountFunc{
+			defaultHook: i.Count,
+		},
 		GetByNameFunc: &RepoStoreGetByNameFunc{
 			defaultHook: i.GetByName,
 		},
 	}
 }
 
+// RepoStoreCountFunc describes the behavior when the Count method of the
+// parent MockRepoStore instance is invoked.
+type RepoStoreCountFunc struct {
+	defaultHook func(context.Context, database.ReposListOptions) (int, error)
+	hooks       []func(context.Context, database.ReposListOptions) (int, error)
+	history     []RepoStoreCountFuncCall
+	mutex       sync.Mutex
+}
+
+// Count delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockRepoStore) Count(v0 context.Context, v1 database.ReposListOptions) (int, error) {
+	r0, r1 := m.CountFunc.nextHook()(v0, v1)
+	m.CountFunc.appendCall(RepoStoreCountFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Count method of the
+// parent MockRepoStore instance is invoked and the hook queue is empty.
+func (f *RepoStoreCountFunc) SetDefaultHook(hook func(context.Context, database.ReposListOptions) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Count method of the parent MockRepoStore instance invokes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *RepoStoreCountFunc) PushHook(hook func(context.Context, database.ReposListOptions) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *RepoStoreCountFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context, database.ReposListOptions) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *RepoStoreCountFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context, database.ReposListOptions) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *RepoStoreCountFunc) nextHook() func(context.Context, database.ReposListOptions) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *RepoStoreCountFunc) appendCall(r0 RepoStoreCountFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of RepoStoreCountFuncCall objects describing
+// the invocations of this function.
+func (f *RepoStoreCountFunc) History() []RepoStoreCountFuncCall {
+	f.mutex.Lock()
+	history := make([]RepoStoreCountFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// RepoStoreCountFuncCall is an object that describes an invocation of
+// method Count on an instance of MockRepoStore.
+type RepoStoreCountFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 database.ReposListOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c RepoStoreCountFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c RepoStoreCountFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // RepoStoreGetByNameFunc describes the behavior when the GetByName method
 // of the parent MockRepoStore instance is invoked.
 type RepoStoreGetByNameFunc struct {
@@ -0,0 +1,178 @@
+package queryrunner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// This file contains the methods required to record series grouped by attribution, where the
+// matches of a search query are bucketed by the author or team they are attributed to, and each
+// author or team forms its own series. Attributed matches are recorded like those of series
+// generated from capture groups, with the author or team as the captured value of their points.
+
+// Teams attributes commit authors and paths to the teams of the insights.attribution.teams site
+// setting.
+type Teams struct {
+	members map[string][]string // lowercase commit author email -> team names
+	owners  []pathOwner         // in order of precedence, the last match wins
+}
+
+type pathOwner struct {
+	pattern *regexp.Regexp
+	team    string
+}
+
+// AttributionTeams returns the teams of the insights.attribution.teams site setting.
+func AttributionTeams() (*Teams, error) {
+	return NewTeams(conf.Get().InsightsAttributionTeams)
+}
+
+// ValidateAttributionTeams is a site configuration validator which reports invalid
+// insights.attribution.teams site settings.
+func ValidateAttributionTeams(c conf.Unified) conf.Problems {
+	if _, err := NewTeams(c.InsightsAttributionTeams); err != nil {
+		return conf.NewSiteProblems(err.Error())
+	}
+	return nil
+}
+
+// NewTeams returns the given teams, or an error if one of their paths is invalid.
+func NewTeams(teams []*schema.InsightsAttributionTeam) (*Teams, error) {
+	t := &Teams{members: map[string][]string{}}
+	for _, team := range teams {
+		for _, member := range team.Members {
+			email := strings.ToLower(strings.TrimSpace(member))
+			t.members[email] = append(t.members[email], team.Name)
+		}
+		for _, path := range team.Paths {
+			pattern, err := codeownersPattern(path)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid path of team %q", team.Name)
+			}
+			t.owners = append(t.owners, pathOwner{pattern: pattern, team: team.Name})
+		}
+	}
+	return t, nil
+}
+
+// AuthorTeams returns the teams whose members include the commit author with the given email.
+func (t *Teams) AuthorTeams(email string) []string {
+	return t.members[strings.ToLower(email)]
+}
+
+// PathTeam returns the team owning the given path, relative to the repository root. As in a
+// CODEOWNERS file, the last path that matches wins.
+func (t *Teams) PathTeam(path string) (string, bool) {
+	for i := len(t.owners) - 1; i >= 0; i-- {
+		if t.owners[i].pattern.MatchString(path) {
+			return t.owners[i].team, true
+		}
+	}
+	return "", false
+}
+
+// codeownersPattern returns the regexp matching the paths matched by the given path of a CODEOWNERS
+// file: paths starting with / are relative to the repository root and otherwise match at any depth,
+// paths ending with / only match the files of a directory, and * and ** match any characters except
+// /, and any characters. A path also matches the files of the directories it matches.
+func codeownersPattern(path string) (*regexp.Regexp, error) {
+	path = strings.TrimSpace(path)
+	anchored := strings.HasPrefix(path, "/")
+	dir := strings.HasSuffix(path, "/")
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil, errors.New("empty path")
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(path); i++ {
+		switch {
+		case strings.HasPrefix(path[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case path[i] == '*':
+			b.WriteString("[^/]*")
+		case path[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(path[i : i+1]))
+		}
+	}
+	if dir {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
+
+// attribute returns the authors or teams the matches of the given search result are attributed
+// to. Commit and diff results are attributed to their commit author, or the teams of their commit
+// author, and file results to the team owning their path. Other results are not attributed.
+func attribute(r result, groupBy insights.Attribution, teams *Teams) []string {
+	switch r := r.(type) {
+	case *commitSearchResult:
+		email := strings.ToLower(r.Commit.Author.Person.Email)
+		if email == "" {
+			return nil
+		}
+		if groupBy == insights.AttributeToAuthor {
+			return []string{email}
+		}
+		return teams.AuthorTeams(email)
+	case *fileMatch:
+		if groupBy != insights.AttributeToTeam {
+			return nil
+		}
+		if team, ok := teams.PathTeam(r.File.Path); ok {
+			return []string{team}
+		}
+	}
+	return nil
+}
+
+// SearchAttributionMatchCounts performs the given search query and counts the matches attributed
+// to each author or team in each repository of the results, keyed like the match counts of
+// captured values. Matches that cannot be attributed are not counted.
+func SearchAttributionMatchCounts(ctx context.Context, searchQuery string, groupBy insights.Attribution, teams *Teams) (CaptureMatchCounts, error) {
+	// 🚨 SECURITY: As for SearchMatchCounts, the search is performed without authentication.
+	// Authors and teams are recorded like match counts, so they must only be exposed together with
+	// the repositories they were recorded for.
+	results, err := search(ctx, searchQuery)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSearchResponse(results, searchQuery); err != nil {
+		return nil, err
+	}
+
+	attributionCounts := CaptureMatchCounts{}
+	for _, result := range results.Data.Search.Results.Results {
+		decoded, err := decodeResult(result)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf(`for query "%s"`, searchQuery))
+		}
+
+		for _, label := range attribute(decoded, groupBy, teams) {
+			if attributionCounts[label] == nil {
+				attributionCounts[label] = MatchCounts{}
+			}
+			attributionCounts[label][decoded.repoID()] += decoded.matchCount()
+		}
+	}
+
+	return attributionCounts, incompleteResults(results, searchQuery)
+}
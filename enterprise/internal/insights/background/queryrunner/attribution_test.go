@@ -0,0 +1,125 @@
+package queryrunner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestCodeownersPattern(t *testing.T) {
+	for _, testCase := range []struct {
+		path    string
+		match   []string
+		noMatch []string
+	}{
+		{path: "*.go", match: []string{"main.go", "cmd/frontend/main.go"}, noMatch: []string{"main.go.txt", "README.md"}},
+		{path: "/docs/", match: []string{"docs/index.md", "docs/dev/index.md"}, noMatch: []string{"docs", "client/docs/index.md"}},
+		{path: "docs", match: []string{"docs", "docs/index.md", "client/docs/index.md"}, noMatch: []string{"documentation/index.md"}},
+		{path: "/cmd/*/main.go", match: []string{"cmd/frontend/main.go"}, noMatch: []string{"cmd/frontend/internal/main.go", "enterprise/cmd/frontend/main.go"}},
+		{path: "/enterprise/**/insights", match: []string{"enterprise/internal/insights/insights.go", "enterprise/a/b/insights"}, noMatch: []string{"internal/insights/insights.go"}},
+		{path: "/go.mod", match: []string{"go.mod"}, noMatch: []string{"lib/go.mod", "go_mod"}},
+	} {
+		pattern, err := codeownersPattern(testCase.path)
+		if err != nil {
+			t.Fatalf("unexpected error for path %q: %s", testCase.path, err)
+		}
+		for _, path := range testCase.match {
+			if !pattern.MatchString(path) {
+				t.Errorf("expected %q to match %q", testCase.path, path)
+			}
+		}
+		for _, path := range testCase.noMatch {
+			if pattern.MatchString(path) {
+				t.Errorf("unexpected match of %q by %q", path, testCase.path)
+			}
+		}
+	}
+
+	for _, path := range []string{"", "/", " // "} {
+		if _, err := codeownersPattern(path); err == nil {
+			t.Errorf("expected an error for path %q", path)
+		}
+	}
+}
+
+func TestTeams(t *testing.T) {
+	teams, err := NewTeams([]*schema.InsightsAttributionTeam{
+		{Name: "backend", Members: []string{"Alice@example.com", "bob@example.com"}, Paths: []string{"*.go", "/cmd/"}},
+		{Name: "frontend", Members: []string{"bob@example.com"}, Paths: []string{"/client/", "/cmd/frontend/"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating teams: %s", err)
+	}
+
+	for email, want := range map[string][]string{
+		"alice@example.com": {"backend"},
+		"BOB@example.com":   {"backend", "frontend"},
+		"carol@example.com": nil,
+	} {
+		if diff := cmp.Diff(want, teams.AuthorTeams(email)); diff != "" {
+			t.Errorf("unexpected teams of %q (-want +got):\n%s", email, diff)
+		}
+	}
+
+	for path, want := range map[string]string{
+		"internal/search/search.go": "backend",
+		"cmd/gitserver/README.md":   "backend",
+		// The last matching path wins, like in CODEOWNERS files.
+		"cmd/frontend/main.go":     "frontend",
+		"client/web/src/index.tsx": "frontend",
+		"README.md":                "",
+	} {
+		have, _ := teams.PathTeam(path)
+		if have != want {
+			t.Errorf("unexpected team owning %q. want=%q have=%q", path, want, have)
+		}
+	}
+
+	if _, err := NewTeams([]*schema.InsightsAttributionTeam{{Name: "invalid", Paths: []string{"/"}}}); err == nil {
+		t.Errorf("expected an error creating teams with an invalid path")
+	}
+}
+
+func TestAttribute(t *testing.T) {
+	teams, err := NewTeams([]*schema.InsightsAttributionTeam{
+		{Name: "backend", Members: []string{"alice@example.com"}, Paths: []string{"*.go"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating teams: %s", err)
+	}
+
+	decode := func(raw string) result {
+		decoded, err := decodeResult(json.RawMessage(raw))
+		if err != nil {
+			t.Fatalf("unexpected error decoding result: %s", err)
+		}
+		return decoded
+	}
+	commit := decode(`{"__typename": "CommitSearchResult", "commit": {"repository": {"id": "repo"}, "author": {"person": {"email": "Alice@example.com"}}}}`)
+	goFile := decode(`{"__typename": "FileMatch", "repository": {"id": "repo"}, "file": {"path": "cmd/main.go"}}`)
+	readme := decode(`{"__typename": "FileMatch", "repository": {"id": "repo"}, "file": {"path": "README.md"}}`)
+	repo := decode(`{"__typename": "Repository", "id": "repo", "name": "github.com/a/b"}`)
+
+	for _, testCase := range []struct {
+		name    string
+		result  result
+		groupBy insights.Attribution
+		want    []string
+	}{
+		{name: "commit by author", result: commit, groupBy: insights.AttributeToAuthor, want: []string{"alice@example.com"}},
+		{name: "commit by team", result: commit, groupBy: insights.AttributeToTeam, want: []string{"backend"}},
+		// File matches have no author.
+		{name: "file by author", result: goFile, groupBy: insights.AttributeToAuthor, want: nil},
+		{name: "file by team", result: goFile, groupBy: insights.AttributeToTeam, want: []string{"backend"}},
+		{name: "unowned file by team", result: readme, groupBy: insights.AttributeToTeam, want: nil},
+		{name: "repository by team", result: repo, groupBy: insights.AttributeToTeam, want: nil},
+	} {
+		if diff := cmp.Diff(testCase.want, attribute(testCase.result, testCase.groupBy, teams)); diff != "" {
+			t.Errorf("unexpected attribution of %s (-want +got):\n%s", testCase.name, diff)
+		}
+	}
+}
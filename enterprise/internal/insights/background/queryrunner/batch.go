@@ -0,0 +1,230 @@
+package queryrunner
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+// This file contains the methods required to batch the search queries of several series into a
+// single search. Many series search the same repositories and differ only in their pattern, so
+// searching for all of their patterns at once reduces the load on search considerably. The
+// matches of the batched search are then attributed to the series whose pattern they match.
+
+// BatchedSeries describes a series whose search query is part of a batched search (see
+// Job.BatchedSeries).
+type BatchedSeries struct {
+	SeriesID    string `json:"seriesId"`
+	SearchQuery string `json:"searchQuery"`
+}
+
+// batchablePattern matches the patterns which can be batched. They must be literals that the
+// query parser reads as a single pattern when combined with other patterns, and whose matches
+// can be told apart reliably.
+var batchablePattern = regexp.MustCompile(`^[A-Za-z0-9_.][A-Za-z0-9_.\-]*$`)
+
+// batchableQuery is a search query which can be batched with other search queries that have the
+// same parameters.
+type batchableQuery struct {
+	parameters    string
+	pattern       string
+	caseSensitive bool
+}
+
+// parseBatchableQuery parses the given search query as a batchable query. Only queries that
+// consist of parameters and a single literal pattern, and whose results are only file and
+// repository matches, are batchable. It returns false if the query is not batchable.
+func parseBatchableQuery(searchQuery string) (batchableQuery, bool) {
+	nodes, err := query.Parse(searchQuery, query.SearchTypeLiteral)
+	if err != nil {
+		return batchableQuery{}, false
+	}
+	parameters, pattern, err := query.PartitionSearchPattern(nodes)
+	if err != nil {
+		return batchableQuery{}, false
+	}
+	p, ok := pattern.(query.Pattern)
+	if !ok || p.Negated || p.Annotation.Labels.IsSet(query.Regexp) || p.Annotation.Labels.IsSet(query.Quoted) || !batchablePattern.MatchString(p.Value) {
+		return batchableQuery{}, false
+	}
+
+	b := batchableQuery{pattern: p.Value}
+	parameterNodes := make([]query.Node, 0, len(parameters))
+	for _, parameter := range parameters {
+		switch strings.ToLower(parameter.Field) {
+		case query.FieldType, query.FieldSelect, query.FieldPatternType:
+			// These change the kind of results, or how the pattern is matched.
+			return batchableQuery{}, false
+		case query.FieldCase:
+			b.caseSensitive = query.ParseYesNoOnly(parameter.Value) == query.Yes
+		}
+		parameterNodes = append(parameterNodes, parameter)
+	}
+	b.parameters = query.StringHuman(parameterNodes)
+	return b, true
+}
+
+// BatchQueries groups the given search queries into batches of at most maxBatchSize search queries
+// which can be searched for at once, and returns the indexes of the search queries in each batch.
+// Search queries which cannot be batched form batches of their own. Batches are ordered by their
+// first search query.
+//
+// Patterns which contain one another are never batched, as the search reports overlapping matches
+// of several patterns as a single match.
+func BatchQueries(searchQueries []string, maxBatchSize int) [][]int {
+	var (
+		batches      [][]int
+		batchQueries [][]batchableQuery
+		openBatches  = map[string][]int{} // batch key -> indexes of batches which are not full
+	)
+	for i, searchQuery := range searchQueries {
+		b, ok := parseBatchableQuery(searchQuery)
+		if !ok {
+			batches = append(batches, []int{i})
+			batchQueries = append(batchQueries, nil)
+			continue
+		}
+
+		added := false
+		for _, batch := range openBatches[b.parameters] {
+			if len(batches[batch]) >= maxBatchSize || overlapsAny(b, batchQueries[batch]) {
+				continue
+			}
+			batches[batch] = append(batches[batch], i)
+			batchQueries[batch] = append(batchQueries[batch], b)
+			added = true
+			break
+		}
+		if !added {
+			openBatches[b.parameters] = append(openBatches[b.parameters], len(batches))
+			batches = append(batches, []int{i})
+			batchQueries = append(batchQueries, []batchableQuery{b})
+		}
+	}
+	return batches
+}
+
+// overlapsAny returns true if the pattern of the given query contains, or is contained in, the
+// pattern of any of the given other queries.
+func overlapsAny(b batchableQuery, others []batchableQuery) bool {
+	pattern := strings.ToLower(b.pattern)
+	for _, other := range others {
+		otherPattern := strings.ToLower(other.pattern)
+		if strings.Contains(pattern, otherPattern) || strings.Contains(otherPattern, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// BatchSearchQuery returns the search query which searches for the patterns of all the given
+// search queries at once. The search queries must have been batched together by BatchQueries.
+func BatchSearchQuery(searchQueries []string) (string, error) {
+	var (
+		parameters string
+		patterns   []string
+	)
+	for i, searchQuery := range searchQueries {
+		b, ok := parseBatchableQuery(searchQuery)
+		if !ok {
+			return "", errors.Errorf("query %q cannot be batched", searchQuery)
+		}
+		if i > 0 && b.parameters != parameters {
+			return "", errors.Errorf("query %q cannot be batched with query %q", searchQuery, searchQueries[0])
+		}
+		parameters = b.parameters
+		patterns = append(patterns, b.pattern)
+	}
+	return fmt.Sprintf("%s (%s)", parameters, strings.Join(patterns, " OR ")), nil
+}
+
+// BatchSeriesID returns the series ID of jobs with the given batched search query. It does not
+// identify a series with data of its own; the data of a batched search is recorded for the batched
+// series.
+func BatchSeriesID(batchSearchQuery string) string {
+	return fmt.Sprintf("b:%X", sha256.Sum256([]byte(batchSearchQuery)))
+}
+
+// SearchBatchedMatchCounts performs the given batched search query and counts the matches of each
+// of the given batched series in each repository of the results, keyed by series ID.
+func SearchBatchedMatchCounts(ctx context.Context, batchSearchQuery string, batchedSeries []BatchedSeries) (map[string]MatchCounts, error) {
+	queries := make(map[string]batchableQuery, len(batchedSeries))
+	for _, series := range batchedSeries {
+		b, ok := parseBatchableQuery(series.SearchQuery)
+		if !ok {
+			return nil, errors.Errorf("query %q of series %q cannot be batched", series.SearchQuery, series.SeriesID)
+		}
+		queries[series.SeriesID] = b
+	}
+
+	// 🚨 SECURITY: As for SearchMatchCounts, the search is performed without authentication.
+	results, err := search(ctx, batchSearchQuery)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSearchResponse(results, batchSearchQuery); err != nil {
+		return nil, err
+	}
+
+	seriesCounts := make(map[string]MatchCounts, len(batchedSeries))
+	for _, series := range batchedSeries {
+		seriesCounts[series.SeriesID] = MatchCounts{}
+	}
+	for _, result := range results.Data.Search.Results.Results {
+		decoded, err := decodeResult(result)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf(`for query "%s"`, batchSearchQuery))
+		}
+		for seriesID, b := range queries {
+			if count := patternMatchCount(decoded, b); count > 0 {
+				seriesCounts[seriesID][decoded.repoID()] += count
+			}
+		}
+	}
+	return seriesCounts, incompleteResults(results, batchSearchQuery)
+}
+
+// patternMatchCount returns the number of matches the given result would have had in a search for
+// the pattern of the given query alone, counted in the same way as by matchCount.
+func patternMatchCount(r result, b batchableQuery) int {
+	count := func(s string) int {
+		if !b.caseSensitive {
+			return strings.Count(strings.ToLower(s), strings.ToLower(b.pattern))
+		}
+		return strings.Count(s, b.pattern)
+	}
+
+	switch r := r.(type) {
+	case *fileMatch:
+		matches := 0
+		for _, lineMatch := range r.LineMatches {
+			preview := []rune(lineMatch.Preview)
+			for _, offsetAndLength := range lineMatch.OffsetAndLengths {
+				if len(offsetAndLength) != 2 {
+					continue
+				}
+				offset, length := offsetAndLength[0], offsetAndLength[1]
+				if offset < 0 || length < 0 || offset+length > len(preview) {
+					continue
+				}
+				// Adjacent matches of different patterns may be reported as a single range.
+				matches += count(string(preview[offset : offset+length]))
+			}
+		}
+		if matches == 0 && count(r.File.Path) > 0 {
+			matches = 1 // the file path matches the pattern
+		}
+		return matches
+	case *repository:
+		if count(r.Name) > 0 {
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+package queryrunner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBatchQueries(t *testing.T) {
+	searchQueries := []string{
+		"errorf count:9999999",
+		"repo:^github\\.com/sourcegraph/sourcegraph$ TODO",
+		"fmt.Printf count:9999999",
+		"error count:9999999",      // contained in errorf
+		"/err(or)?/ count:9999999", // regexp
+		"repo:^github\\.com/sourcegraph/sourcegraph$ FIXME",
+		"-file:vendor errorf count:9999999", // other parameters
+		"lang:go select:repo errorf",        // changes the kind of results
+		"log15.Error count:9999999",
+	}
+
+	want := [][]int{{0, 2, 8}, {1, 5}, {3}, {4}, {6}, {7}}
+	if diff := cmp.Diff(want, BatchQueries(searchQueries, 10)); diff != "" {
+		t.Errorf("unexpected batches (-want +got):\n%s", diff)
+	}
+
+	want = [][]int{{0, 2}, {1, 5}, {3}, {4}, {6}, {7}, {8}} // log15.Error contains error
+	if diff := cmp.Diff(want, BatchQueries(searchQueries, 2)); diff != "" {
+		t.Errorf("unexpected batches of at most 2 queries (-want +got):\n%s", diff)
+	}
+}
+
+func TestBatchSearchQuery(t *testing.T) {
+	batchSearchQuery, err := BatchSearchQuery([]string{"lang:go errorf count:9999999", "lang:go fmt.Printf count:9999999"})
+	if err != nil {
+		t.Fatalf("unexpected error batching queries: %s", err)
+	}
+	if want := "lang:go count:9999999 (errorf OR fmt.Printf)"; batchSearchQuery != want {
+		t.Errorf("unexpected batch search query. want=%q have=%q", want, batchSearchQuery)
+	}
+
+	for _, searchQueries := range [][]string{
+		{"lang:go errorf", "/err(or)?/"},
+		{"lang:go errorf", "lang:python errorf"},
+	} {
+		if _, err := BatchSearchQuery(searchQueries); err == nil {
+			t.Errorf("expected an error for queries %q", searchQueries)
+		}
+	}
+}
+
+func TestPatternMatchCount(t *testing.T) {
+	var match fileMatch
+	if err := json.Unmarshal([]byte(`{
+		"__typename": "FileMatch",
+		"repository": {"id": "UmVwb3NpdG9yeTox"},
+		"file": {"path": "internal/errorf.go"},
+		"lineMatches": [
+			{"preview": "return Errorf(\"unexpected\")", "offsetAndLengths": [[7, 6]]},
+			{"preview": "fmt.Printf(errorf)", "offsetAndLengths": [[0, 10], [11, 6]]}
+		]
+	}`), &match); err != nil {
+		t.Fatalf("unexpected error decoding match: %s", err)
+	}
+
+	for _, testCase := range []struct {
+		query    string
+		expected int
+	}{
+		{"errorf", 2},
+		{"errorf case:yes", 1},
+		{"fmt.Printf", 1},
+		{"internal", 1}, // the path matches
+		{"log15", 0},
+	} {
+		b, ok := parseBatchableQuery(testCase.query)
+		if !ok {
+			t.Fatalf("expected query %q to be batchable", testCase.query)
+		}
+		if count := patternMatchCount(&match, b); count != testCase.expected {
+			t.Errorf("unexpected match count for query %q. want=%d have=%d", testCase.query, testCase.expected, count)
+		}
+	}
+
+	b, _ := parseBatchableQuery("sourcegraph")
+	if count := patternMatchCount(&repository{ID: "UmVwb3NpdG9yeTox", Name: "github.com/sourcegraph/sourcegraph"}, b); count != 1 {
+		t.Errorf("unexpected match count for repository. want=%d have=%d", 1, count)
+	}
+}
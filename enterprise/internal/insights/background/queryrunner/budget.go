@@ -0,0 +1,77 @@
+package queryrunner
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// This file contains the methods required to keep series with pathological queries, such as
+// structural searches of every repository, from taking up the whole query runner. The searches of
+// each job are timed against the time budget of a series. Series whose jobs exceed the budget too
+// many times in a row have their circuit broken: they are paused, and their jobs are skipped, until
+// a site admin resumes them.
+
+// defaultCircuitBreakerThreshold is the number of consecutive jobs of a series over budget after
+// which the series is paused, unless the site configuration says otherwise.
+const defaultCircuitBreakerThreshold = 3
+
+// SeriesTimeBudget returns the time the searches of a single job may take for each series the job
+// records, or zero if series have no time budget.
+func SeriesTimeBudget() time.Duration {
+	return time.Duration(conf.Get().InsightsQueryWorkerSeriesTimeBudgetSeconds) * time.Second
+}
+
+// CircuitBreakerThreshold returns the number of consecutive jobs of a series over budget after
+// which the series is paused.
+func CircuitBreakerThreshold() int {
+	if threshold := conf.Get().InsightsQueryWorkerCircuitBreakerThreshold; threshold > 0 {
+		return threshold
+	}
+	return defaultCircuitBreakerThreshold
+}
+
+// overBudget returns whether searches that took the given time exceed the given time budget. A
+// zero budget is never exceeded.
+func overBudget(searchTime, budget time.Duration) bool {
+	return budget > 0 && searchTime > budget
+}
+
+// skipPausedSeries removes the given paused series from the given job, and returns false if the
+// job records no other series. The search query of a batched job is narrowed to the remaining
+// batched series.
+//
+// The data points of paused series are not recorded later on: once the series is resumed, the
+// insight enqueuer records its present-day data again, and the historical enqueuer backfills the
+// frames left without data.
+func skipPausedSeries(job *Job, paused map[string]bool) (bool, error) {
+	if len(paused) == 0 {
+		return true, nil
+	}
+	if len(job.BatchedSeries) == 0 {
+		return !paused[job.SeriesID], nil
+	}
+
+	remaining := make([]BatchedSeries, 0, len(job.BatchedSeries))
+	searchQueries := make([]string, 0, len(job.BatchedSeries))
+	for _, series := range job.BatchedSeries {
+		if paused[series.SeriesID] {
+			continue
+		}
+		remaining = append(remaining, series)
+		searchQueries = append(searchQueries, series.SearchQuery)
+	}
+	if len(remaining) == 0 {
+		return false, nil
+	}
+	if len(remaining) == len(job.BatchedSeries) {
+		return true, nil
+	}
+	searchQuery, err := BatchSearchQuery(searchQueries)
+	if err != nil {
+		return false, err
+	}
+	job.SearchQuery = searchQuery
+	job.BatchedSeries = remaining
+	return true, nil
+}
@@ -0,0 +1,64 @@
+package queryrunner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOverBudget(t *testing.T) {
+	for _, testCase := range []struct {
+		searchTime, budget time.Duration
+		want               bool
+	}{
+		{searchTime: time.Minute, budget: 0, want: false},
+		{searchTime: 10 * time.Second, budget: 30 * time.Second, want: false},
+		{searchTime: 30 * time.Second, budget: 30 * time.Second, want: false},
+		{searchTime: 31 * time.Second, budget: 30 * time.Second, want: true},
+	} {
+		if have := overBudget(testCase.searchTime, testCase.budget); have != testCase.want {
+			t.Errorf("unexpected over budget for search time %s and budget %s. want=%t have=%t", testCase.searchTime, testCase.budget, testCase.want, have)
+		}
+	}
+}
+
+func TestSkipPausedSeries(t *testing.T) {
+	t.Run("single series", func(t *testing.T) {
+		job := &Job{SeriesID: "s:structural", SearchQuery: "patterntype:structural fmt.Sprintf(...)"}
+		if ok, err := skipPausedSeries(job, map[string]bool{"s:other": true}); err != nil || !ok {
+			t.Errorf("unexpected skip of series that is not paused. ok=%t err=%v", ok, err)
+		}
+		if ok, err := skipPausedSeries(job, map[string]bool{"s:structural": true}); err != nil || ok {
+			t.Errorf("unexpected recording of paused series. ok=%t err=%v", ok, err)
+		}
+	})
+
+	t.Run("batched series", func(t *testing.T) {
+		batched := []BatchedSeries{
+			{SeriesID: "s:errorf", SearchQuery: "lang:go errorf"},
+			{SeriesID: "s:warnf", SearchQuery: "lang:go warnf"},
+			{SeriesID: "s:infof", SearchQuery: "lang:go infof"},
+		}
+		job := &Job{SeriesID: "batch", SearchQuery: "lang:go (errorf OR warnf OR infof)", BatchedSeries: batched}
+
+		ok, err := skipPausedSeries(job, map[string]bool{"s:warnf": true})
+		if err != nil {
+			t.Fatalf("unexpected error skipping paused series: %s", err)
+		}
+		if !ok {
+			t.Fatalf("unexpected skip of job with series that are not paused")
+		}
+		if diff := cmp.Diff([]BatchedSeries{batched[0], batched[2]}, job.BatchedSeries); diff != "" {
+			t.Errorf("unexpected batched series (-want +got):\n%s", diff)
+		}
+		if want := "lang:go (errorf OR infof)"; job.SearchQuery != want {
+			t.Errorf("unexpected search query. want=%q have=%q", want, job.SearchQuery)
+		}
+
+		ok, err = skipPausedSeries(job, map[string]bool{"s:errorf": true, "s:infof": true})
+		if err != nil || ok {
+			t.Errorf("unexpected recording of job whose series are all paused. ok=%t err=%v", ok, err)
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package queryrunner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+// This file contains the methods required to record series generated from capture groups, where
+// each distinct value matched by the first capture group of a regexp query forms its own series.
+
+// CaptureMatchCounts is the number of search matches of each captured value in each repository,
+// keyed by captured value and then by GraphQL repository ID.
+type CaptureMatchCounts map[string]MatchCounts
+
+// captureGroupPattern returns the regexp whose first capture group determines the series that a
+// match of the given search query belongs to. This is the first regexp pattern of the query that
+// has a capture group. The search backend matches the patterns of a query independently, so the
+// pattern is applied to the matched text to find the captured value.
+func captureGroupPattern(searchQuery string) (*regexp.Regexp, error) {
+	q, err := query.Parse(searchQuery, query.SearchTypeRegex)
+	if err != nil {
+		return nil, errors.Wrap(err, "Parse")
+	}
+
+	caseSensitive := false
+	query.VisitParameter(q, func(field, value string, negated bool, annotation query.Annotation) {
+		if field == query.FieldCase && query.ParseYesNoOnly(value) == query.Yes {
+			caseSensitive = true
+		}
+	})
+
+	var patterns []string
+	query.VisitPattern(q, func(value string, negated bool, annotation query.Annotation) {
+		if !negated && !annotation.Labels.IsSet(query.Literal) {
+			patterns = append(patterns, value)
+		}
+	})
+
+	for _, pattern := range patterns {
+		if !caseSensitive {
+			pattern = "(?i:" + pattern + ")"
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %q", pattern)
+		}
+		if re.NumSubexp() > 0 {
+			return re, nil
+		}
+	}
+
+	return nil, errors.Errorf("query %q has no regexp pattern with a capture group", searchQuery)
+}
+
+// withRegexpPatternType makes the given search query match its patterns as regular expressions,
+// unless the query selects a pattern type itself.
+func withRegexpPatternType(searchQuery string) string {
+	if nodes, err := query.Parse(searchQuery, query.SearchTypeRegex); err == nil {
+		hasPatternType := false
+		query.VisitField(query.LowercaseFieldNames(nodes), query.FieldPatternType, func(string, bool, query.Annotation) {
+			hasPatternType = true
+		})
+		if hasPatternType {
+			return searchQuery
+		}
+	}
+	return searchQuery + " patternType:regexp"
+}
+
+// SearchCaptureMatchCounts performs the given search query and counts the matches of each value
+// captured by the first capture group of the query in each repository of the results. Matches
+// that capture no value, such as commit or repository results, are not counted.
+func SearchCaptureMatchCounts(ctx context.Context, searchQuery string) (CaptureMatchCounts, error) {
+	pattern, err := captureGroupPattern(searchQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: As for SearchMatchCounts, the search is performed without authentication.
+	// Captured values are recorded like match counts, so they must only be exposed together with
+	// the repositories they were recorded for.
+	searchQuery = withRegexpPatternType(searchQuery)
+	results, err := search(ctx, searchQuery)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSearchResponse(results, searchQuery); err != nil {
+		return nil, err
+	}
+
+	captureCounts := CaptureMatchCounts{}
+	for _, result := range results.Data.Search.Results.Results {
+		decoded, err := decodeResult(result)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf(`for query "%s"`, searchQuery))
+		}
+		fileMatch, ok := decoded.(*fileMatch)
+		if !ok {
+			continue
+		}
+
+		for _, value := range fileMatch.captures(pattern) {
+			if captureCounts[value] == nil {
+				captureCounts[value] = MatchCounts{}
+			}
+			captureCounts[value][fileMatch.repoID()]++
+		}
+	}
+
+	return captureCounts, incompleteResults(results, searchQuery)
+}
+
+// captures returns the value captured by the first capture group of the given pattern for each
+// match in the file.
+func (r *fileMatch) captures(pattern *regexp.Regexp) []string {
+	var values []string
+	for _, lineMatch := range r.LineMatches {
+		preview := []rune(lineMatch.Preview)
+		for _, offsetAndLength := range lineMatch.OffsetAndLengths {
+			if len(offsetAndLength) != 2 {
+				continue
+			}
+			offset, length := offsetAndLength[0], offsetAndLength[1]
+			if offset < 0 || length < 0 || offset+length > len(preview) {
+				continue
+			}
+
+			submatches := pattern.FindStringSubmatch(string(preview[offset : offset+length]))
+			if len(submatches) < 2 || submatches[1] == "" {
+				continue
+			}
+			values = append(values, submatches[1])
+		}
+	}
+	return values
+}
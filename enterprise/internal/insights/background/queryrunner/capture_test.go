@@ -0,0 +1,73 @@
+package queryrunner
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCaptureGroupPattern(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{`go (\d\.\d+)`, `(?i:(\d\.\d+))`},
+		{`lang:go go\s(\d+) case:yes -file:vendor`, `go\s(\d+)`},
+		{`repo:^github\.com/sourcegraph/sourcegraph$@abc123 import (\w+)\.go patternType:regexp`, `(?i:(\w+)\.go)`},
+	}
+
+	for _, testCase := range testCases {
+		pattern, err := captureGroupPattern(testCase.query)
+		if err != nil {
+			t.Fatalf("unexpected error for query %q: %s", testCase.query, err)
+		}
+		if pattern.String() != testCase.expected {
+			t.Errorf("unexpected pattern for query %q. want=%q have=%q", testCase.query, testCase.expected, pattern.String())
+		}
+	}
+
+	for _, query := range []string{`go \d+`, `"go (\d+)"`, `lang:go`} {
+		if _, err := captureGroupPattern(query); err == nil {
+			t.Errorf("expected an error for query %q", query)
+		}
+	}
+}
+
+func TestWithRegexpPatternType(t *testing.T) {
+	if have := withRegexpPatternType(`go (\d+)`); have != `go (\d+) patternType:regexp` {
+		t.Errorf("unexpected query. have=%q", have)
+	}
+	if have := withRegexpPatternType(`go (\d+) patterntype:structural`); have != `go (\d+) patterntype:structural` {
+		t.Errorf("unexpected query. have=%q", have)
+	}
+	if have := withRegexpPatternType(`go (\d+) content:"patternType:"`); have != `go (\d+) content:"patternType:" patternType:regexp` {
+		t.Errorf("unexpected query. have=%q", have)
+	}
+}
+
+func TestFileMatchCaptures(t *testing.T) {
+	var match fileMatch
+	if err := json.Unmarshal([]byte(`{
+		"__typename": "FileMatch",
+		"repository": {"id": "UmVwb3NpdG9yeTox"},
+		"lineMatches": [
+			{"preview": "go 1.16", "offsetAndLengths": [[0, 7]]},
+			{"preview": "// go 1.15 or go 1.16", "offsetAndLengths": [[3, 7], [14, 7]]},
+			{"preview": "ünïcode go 1.17", "offsetAndLengths": [[8, 7], [40, 2]]},
+			{"preview": "go version", "offsetAndLengths": [[0, 10]]}
+		]
+	}`), &match); err != nil {
+		t.Fatalf("unexpected error decoding file match: %s", err)
+	}
+
+	pattern, err := captureGroupPattern(`go (\d\.\d+)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []string{"1.16", "1.15", "1.16", "1.17"}
+	if diff := cmp.Diff(expected, match.captures(pattern)); diff != "" {
+		t.Errorf("unexpected captures (-want +got):\n%s", diff)
+	}
+}
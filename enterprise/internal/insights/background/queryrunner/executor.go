@@ -0,0 +1,219 @@
+package queryrunner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// This file contains the methods required to process historical backfill jobs on executors
+// rather than in the worker. The executor performs the search query through the frontend and
+// prints the resulting match counts, which are recorded when the job is marked as complete.
+
+// ExecutorSearchStepKey is the execution log key of the executor step that prints the match
+// counts of a job's search query as JSON.
+const ExecutorSearchStepKey = "step.docker.0"
+
+// BackfillOnExecutors returns true if historical backfill jobs are processed by executors.
+func BackfillOnExecutors() bool {
+	return conf.Get().InsightsQueryWorkerBackfillOnExecutors
+}
+
+// ExecutorDequeueConditions returns the conditions restricting executors to historical backfill
+// jobs of a single series that counts matches. No job is handed to executors unless backfilling on executors
+// is enabled, or while the query runner is paused.
+func ExecutorDequeueConditions() []*sqlf.Query {
+	if !BackfillOnExecutors() || Paused() {
+		return []*sqlf.Query{sqlf.Sprintf("FALSE")}
+	}
+
+	return []*sqlf.Query{
+		sqlf.Sprintf("insights_query_runner_jobs.record_time IS NOT NULL"),
+		sqlf.Sprintf("NOT %s", splitSeriesCondition),
+		sqlf.Sprintf("NOT %s", batchedSeriesCondition),
+	}
+}
+
+// splitSeriesCondition matches the jobs of series generated from capture groups or grouped by
+// attribution, which are identified by the prefix of their series ID (see discovery.IsSplitSeries).
+var splitSeriesCondition = sqlf.Sprintf("(insights_query_runner_jobs.series_id LIKE 'c:%%' OR insights_query_runner_jobs.series_id LIKE 'a:%%' OR insights_query_runner_jobs.series_id LIKE 't:%%')")
+
+// batchedSeriesCondition matches batched jobs, which are historical jobs if they are the jobs of
+// shards of a batched job (see shard.go).
+var batchedSeriesCondition = sqlf.Sprintf("insights_query_runner_jobs.batched_series IS NOT NULL")
+
+// NewExecutorStore creates a dbworker store over the query runner jobs for use by the executor
+// queue. Marking a job as complete records the match counts printed by its executor into the
+// given insights store.
+//
+// Jobs are dequeued in order of priority. If priorityAgingInterval is positive, the priority of
+// a queued job is raised by one for each such interval it has spent in the queue so that jobs of
+// low priority are not starved by a steady stream of jobs of high priority.
+func NewExecutorStore(workerBaseStore *basestore.Store, insightsStore *store.Store, stalledMaxAge time.Duration, maxNumResets int, priorityAgingInterval time.Duration, dequeueOptions dbworkerstore.DequeueOptions, observationContext *observation.Context) dbworkerstore.Store {
+	options := workerStoreOptions
+	options.Name = "insights_query_runner_executor_store"
+	options.StalledMaxAge = stalledMaxAge
+	options.MaxNumResets = maxNumResets
+	options.Dequeue = dequeueOptions
+	if priorityAgingInterval > 0 {
+		options.OrderByExpression = sqlf.Sprintf(agedPriorityOrderExpression, priorityAgingInterval.Seconds())
+	}
+
+	return &executorStore{
+		Store:           dbworkerstore.NewWithMetrics(workerBaseStore.Handle(), options, observationContext),
+		workerBaseStore: workerBaseStore,
+		insightsStore:   insightsStore,
+
+		gitFindNearestCommit: git.FindNearestCommit,
+	}
+}
+
+// agedPriorityOrderExpression orders jobs by their priority, lowered (i.e., raised in importance)
+// by the number of aging intervals, in seconds, that each job has spent in the queue.
+const agedPriorityOrderExpression = `
+insights_query_runner_jobs.priority - EXTRACT(EPOCH FROM NOW() - insights_query_runner_jobs.queued_at) / %s::float,
+insights_query_runner_jobs.id
+`
+
+// executorStore is a thin wrapper around dbworkerstore.Store that pins the search query of a job
+// when the job is dequeued, and records the match counts in the execution logs of a job when the
+// job is marked as complete.
+type executorStore struct {
+	dbworkerstore.Store
+	workerBaseStore *basestore.Store
+	insightsStore   *store.Store
+
+	gitFindNearestCommit findNearestCommitFunc
+}
+
+var _ dbworkerstore.Store = &executorStore{}
+
+func (s *executorStore) Dequeue(ctx context.Context, workerHostname string, conditions []*sqlf.Query) (workerutil.Record, bool, error) {
+	record, dequeued, err := s.Store.Dequeue(ctx, workerHostname, conditions)
+	if err != nil || !dequeued {
+		return record, dequeued, err
+	}
+
+	// Executors only see the search query, so resolve the pinned revision before handing it out.
+	job := record.(*Job)
+	options := dbworkerstore.MarkFinalOptions{WorkerHostname: workerHostname}
+	if err := checkJobVersion(job); err != nil {
+		if _, markErr := s.Store.MarkErrored(ctx, job.ID, err.Error(), options); markErr != nil {
+			return nil, false, errors.Wrap(markErr, "MarkErrored")
+		}
+		return nil, false, nil
+	}
+
+	// Like the worker, skip the jobs of series paused by their circuit breaker.
+	paused, err := s.insightsStore.CircuitBrokenSeries(ctx, jobSeriesIDs(job))
+	if err != nil {
+		return nil, false, errors.Wrap(err, "CircuitBrokenSeries")
+	}
+	ok, err := skipPausedSeries(job, paused)
+	if err != nil {
+		if _, markErr := s.Store.MarkErrored(ctx, job.ID, err.Error(), options); markErr != nil {
+			return nil, false, errors.Wrap(markErr, "MarkErrored")
+		}
+		return nil, false, nil
+	}
+	if !ok {
+		if _, err := s.Store.MarkComplete(ctx, job.ID, options); err != nil {
+			return nil, false, errors.Wrap(err, "MarkComplete")
+		}
+		return nil, false, nil
+	}
+
+	query, ok, err := pinSearchQuery(ctx, s.workerBaseStore, s.gitFindNearestCommit, job)
+	if err != nil {
+		if _, markErr := s.Store.MarkErrored(ctx, job.ID, err.Error(), options); markErr != nil {
+			return nil, false, errors.Wrap(markErr, "MarkErrored")
+		}
+		return nil, false, nil
+	}
+	if !ok {
+		// There is nothing to search, and so nothing to record.
+		if _, err := s.Store.MarkComplete(ctx, job.ID, options); err != nil {
+			return nil, false, errors.Wrap(err, "MarkComplete")
+		}
+		return nil, false, nil
+	}
+	job.SearchQuery = shardSearchQuery(job, query)
+	return job, true, nil
+}
+
+func (s *executorStore) MarkComplete(ctx context.Context, id int, options dbworkerstore.MarkFinalOptions) (bool, error) {
+	// Series points are recorded in another database, so ensure the reporting executor still
+	// owns the job before recording anything on its behalf.
+	owned, err := s.isOwnedBy(ctx, id, options.WorkerHostname)
+	if err != nil || !owned {
+		return false, err
+	}
+
+	job, err := dequeueJob(ctx, s.workerBaseStore, id)
+	if err != nil {
+		return false, err
+	}
+
+	matchCounts, err := extractMatchCounts(job.ExecutionLogs)
+	if err != nil {
+		return s.Store.MarkErrored(ctx, id, fmt.Sprintf("failed to extract match counts: %s", err), options)
+	}
+
+	if err := RecordMatchCounts(ctx, s.workerBaseStore, s.insightsStore, job, matchCounts, false); err != nil {
+		return s.Store.MarkErrored(ctx, id, fmt.Sprintf("failed to record match counts: %s", err), options)
+	}
+
+	return s.Store.MarkComplete(ctx, id, options)
+}
+
+func (s *executorStore) isOwnedBy(ctx context.Context, id int, workerHostname string) (bool, error) {
+	_, ok, err := basestore.ScanFirstInt(s.workerBaseStore.Query(ctx, sqlf.Sprintf(isOwnedByQuery, id, workerHostname, workerHostname)))
+	return ok, err
+}
+
+const isOwnedByQuery = `
+-- source: enterprise/internal/insights/background/queryrunner/executor.go:isOwnedBy
+SELECT id FROM insights_query_runner_jobs
+WHERE id = %s AND state = 'processing' AND (%s = '' OR worker_hostname = %s)
+`
+
+// extractMatchCounts returns the match counts printed to stdout by the search step.
+func extractMatchCounts(logs []workerutil.ExecutionLogEntry) (MatchCounts, error) {
+	for _, entry := range logs {
+		if entry.Key != ExecutorSearchStepKey {
+			continue
+		}
+
+		const outputLinePrefix = "stdout: "
+
+		for _, line := range strings.Split(entry.Out, "\n") {
+			if !strings.HasPrefix(line, outputLinePrefix) {
+				continue
+			}
+
+			var matchCounts MatchCounts
+			if err := json.Unmarshal([]byte(line[len(outputLinePrefix):]), &matchCounts); err != nil {
+				return nil, errors.Wrap(err, "malformed match counts")
+			}
+
+			return matchCounts, nil
+		}
+
+		return nil, errors.New("search step printed no match counts")
+	}
+
+	return nil, errors.New("no search step in execution logs")
+}
@@ -0,0 +1,104 @@
+package queryrunner
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+func TestExtractMatchCounts(t *testing.T) {
+	logs := []workerutil.ExecutionLogEntry{
+		{Key: "setup.git.init", Out: "stdout: Initialized empty Git repository\n"},
+		{Key: ExecutorSearchStepKey, Out: "stderr: % Total\nstdout: {\"UmVwb3NpdG9yeTox\":3,\"UmVwb3NpdG9yeToy\":1}\n"},
+	}
+
+	matchCounts, err := extractMatchCounts(logs)
+	if err != nil {
+		t.Fatalf("unexpected error extracting match counts: %s", err)
+	}
+	expected := MatchCounts{"UmVwb3NpdG9yeTox": 3, "UmVwb3NpdG9yeToy": 1}
+	if diff := cmp.Diff(expected, matchCounts); diff != "" {
+		t.Errorf("unexpected match counts (-want +got):\n%s", diff)
+	}
+}
+
+func TestExtractMatchCountsErrors(t *testing.T) {
+	testCases := map[string][]workerutil.ExecutionLogEntry{
+		"no search step": {{Key: "setup.git.init", Out: "stdout: {}\n"}},
+		"no output":      {{Key: ExecutorSearchStepKey, Out: "stderr: curl: (22) 500\n"}},
+		"malformed":      {{Key: ExecutorSearchStepKey, Out: "stdout: <html>\n"}},
+	}
+
+	for name, logs := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := extractMatchCounts(logs); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestExecutorStorePriorityAging(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	testCases := []struct {
+		priorityAgingInterval time.Duration
+		expectedIDs           []int
+	}{
+		// Job 1 has waited 100 minutes, raising its priority from 150 to 50, ahead of job 3
+		{priorityAgingInterval: time.Minute, expectedIDs: []int{2, 1, 3}},
+		{priorityAgingInterval: 0, expectedIDs: []int{2, 3, 1}},
+	}
+
+	for _, testCase := range testCases {
+		ctx := context.Background()
+		workerBaseStore := basestore.NewWithDB(dbtesting.GetDB(t), sql.TxOptions{})
+
+		for _, job := range []struct {
+			id       int
+			priority int
+			waited   time.Duration
+		}{
+			{id: 1, priority: 150, waited: 100 * time.Minute},
+			{id: 2, priority: 10, waited: time.Minute},
+			{id: 3, priority: 100, waited: time.Minute},
+		} {
+			if err := workerBaseStore.Exec(ctx, sqlf.Sprintf(
+				`INSERT INTO insights_query_runner_jobs (id, series_id, search_query, priority, queued_at) VALUES (%s, 's', 'q', %s, %s)`,
+				job.id, job.priority, time.Now().Add(-job.waited),
+			)); err != nil {
+				t.Fatalf("unexpected error inserting job: %s", err)
+			}
+		}
+
+		workerStore := NewExecutorStore(workerBaseStore, nil, time.Minute, 3, testCase.priorityAgingInterval, dbworkerstore.DequeueOptions{}, &observation.TestContext)
+
+		var ids []int
+		for {
+			record, ok, err := workerStore.Dequeue(ctx, "test", nil)
+			if err != nil {
+				t.Fatalf("unexpected error dequeueing job: %s", err)
+			}
+			if !ok {
+				break
+			}
+			ids = append(ids, record.RecordID())
+		}
+
+		if diff := cmp.Diff(testCase.expectedIDs, ids); diff != "" {
+			t.Errorf("unexpected dequeue order with aging interval %s (-want +got):\n%s", testCase.priorityAgingInterval, diff)
+		}
+	}
+}
@@ -38,7 +38,11 @@ const gqlSearchQuery = `query Search(
 					repository {
 						id
 					}
+					file {
+						path
+					}
 					lineMatches {
+						preview
 						offsetAndLengths
 					}
 					symbols {
@@ -55,10 +59,16 @@ const gqlSearchQuery = `query Search(
 						repository {
 							id
 						}
+						author {
+							person {
+								email
+							}
+						}
 					}
 				}
 				... on Repository {
 					id
+					name
 				}
 			}
 			alert {
@@ -111,7 +111,11 @@ type fileMatch struct {
 	Repository struct {
 		ID string
 	}
+	File struct {
+		Path string
+	}
 	LineMatches []struct {
+		Preview          string
 		OffsetAndLengths [][]int
 	}
 	Symbols []struct {
@@ -144,6 +148,11 @@ type commitSearchResult struct {
 		Repository struct {
 			ID string
 		}
+		Author struct {
+			Person struct {
+				Email string
+			}
+		}
 	}
 }
 
@@ -160,7 +169,8 @@ func (r *commitSearchResult) matchCount() int {
 }
 
 type repository struct {
-	ID string
+	ID   string
+	Name string
 }
 
 func (r *repository) repoID() string {
@@ -0,0 +1,186 @@
+package queryrunner
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/compression"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// This file contains the methods required to record the present-day data of series incrementally.
+//
+// Most repositories do not change between two recordings of a series, yet a full search searches
+// all of them every time. An incremental recording only searches the repositories that have new
+// commits since the previous recording, according to the commit index (see the compression
+// package), and carries the previous values of the other repositories forward. Repositories that
+// start matching without having matched in the previous recording are only found by full
+// searches, which is one of the reasons series are still recorded from a full search every
+// insights.incremental.fullRecomputeAfterDays days.
+
+// maxIncrementalSearchRepos is the maximum number of changed repositories searched by an
+// incremental recording. Series with more changed repositories are recorded from a full search,
+// which is cheaper than a search scoped to that many repositories by name.
+const maxIncrementalSearchRepos = 100
+
+// FullRecomputeInterval returns the interval after which series are recorded from a full search
+// again, or zero if series are never recorded incrementally.
+func FullRecomputeInterval() time.Duration {
+	return time.Duration(conf.Get().InsightsIncrementalFullRecomputeAfterDays) * 24 * time.Hour
+}
+
+// incrementalMetadata is the metadata of the data points carried forward by incremental
+// recordings. AsOf is the time up to which the repository is known to have no new commits since
+// it was last searched, so that the next incremental recording looks for commits after that time.
+type incrementalMetadata struct {
+	AsOf time.Time `json:"asOf"`
+}
+
+// carriedPoint is a data point of the previous recording of a series that is carried forward.
+type carriedPoint struct {
+	store.RepoPoint
+	asOf time.Time
+}
+
+// incrementalPlan describes an incremental recording of one or more series.
+type incrementalPlan struct {
+	// searchRepos are the names of the repositories to search, sorted by name.
+	searchRepos []string
+
+	// carried are the data points carried forward, keyed by series ID.
+	carried map[string][]carriedPoint
+}
+
+// incrementalStore is a subset of the API exposed by the store.Store (only the subset used to plan
+// incremental recordings.)
+type incrementalStore interface {
+	SeriesRunStatus(ctx context.Context, seriesID string) (store.SeriesRunStatus, bool, error)
+	LatestRepoPoints(ctx context.Context, seriesID string) ([]store.RepoPoint, error)
+}
+
+// incrementalEligible returns true if the given job may be recorded incrementally: only jobs that
+// record the present-day data of series may, except for series generated from capture groups or
+// grouped by attribution, whose labels are not known per repository ahead of the search.
+func incrementalEligible(job *Job) bool {
+	return job.RecordTime == nil && job.PinnedRepo == nil && !discovery.IsSplitSeries(job.SeriesID)
+}
+
+// planIncrementalRecording plans the incremental recording of the given series at the given time.
+// It returns false if the series must be recorded from a full search instead, e.g. because one of
+// them was not recorded from a full search within fullRecomputeInterval or its previous recording
+// failed.
+func planIncrementalRecording(ctx context.Context, insightsStore incrementalStore, commitStore compression.CommitStore, now time.Time, fullRecomputeInterval time.Duration, seriesIDs []string) (*incrementalPlan, bool, error) {
+	if fullRecomputeInterval <= 0 {
+		return nil, false, nil
+	}
+
+	plan := &incrementalPlan{carried: make(map[string][]carriedPoint, len(seriesIDs))}
+	searchRepos := map[string]struct{}{}
+	for _, seriesID := range seriesIDs {
+		status, ok, err := insightsStore.SeriesRunStatus(ctx, seriesID)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok || status.ConsecutiveFailures > 0 || status.LastFullRunAt == nil || now.Sub(*status.LastFullRunAt) >= fullRecomputeInterval {
+			return nil, false, nil
+		}
+
+		points, err := insightsStore.LatestRepoPoints(ctx, seriesID)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, point := range points {
+			asOf := point.Time
+			if len(point.Metadata) > 0 {
+				var metadata incrementalMetadata
+				if err := json.Unmarshal(point.Metadata, &metadata); err == nil && !metadata.AsOf.IsZero() {
+					asOf = metadata.AsOf
+				}
+			}
+
+			indexedAt, unchanged := repoUnchangedSince(ctx, commitStore, point.RepoID, asOf)
+			if !unchanged {
+				searchRepos[point.RepoName] = struct{}{}
+				continue
+			}
+			// The commit index is stamped with a different time for each repository. Rounding it
+			// keeps the number of distinct metadata values small, and only makes the next
+			// recording look for commits over a slightly longer time.
+			if rounded := indexedAt.Truncate(time.Hour); rounded.After(asOf) {
+				asOf = rounded
+			}
+			plan.carried[seriesID] = append(plan.carried[seriesID], carriedPoint{RepoPoint: point, asOf: asOf})
+		}
+	}
+	if len(searchRepos) > maxIncrementalSearchRepos {
+		return nil, false, nil
+	}
+
+	for name := range searchRepos {
+		plan.searchRepos = append(plan.searchRepos, name)
+	}
+	sort.Strings(plan.searchRepos)
+
+	// Repositories searched for one series are searched for all of the series, so their values are
+	// recorded from the search rather than carried forward.
+	for seriesID, points := range plan.carried {
+		carried := points[:0]
+		for _, point := range points {
+			if _, ok := searchRepos[point.RepoName]; !ok {
+				carried = append(carried, point)
+			}
+		}
+		plan.carried[seriesID] = carried
+	}
+	return plan, true, nil
+}
+
+// repoUnchangedSince returns true if the commit index shows that the given repository has no new
+// commits since the given time, along with the time up to which the repository is indexed.
+// Repositories that are not indexed up to a later time are considered changed.
+func repoUnchangedSince(ctx context.Context, commitStore compression.CommitStore, repoID api.RepoID, since time.Time) (time.Time, bool) {
+	metadata, err := commitStore.GetMetadata(ctx, repoID)
+	if err != nil || !metadata.Enabled || !metadata.LastIndexedAt.After(since) {
+		// The commit index is optional, so repositories that are not indexed are searched.
+		return time.Time{}, false
+	}
+	commits, err := commitStore.Get(ctx, repoID, since, metadata.LastIndexedAt)
+	if err != nil {
+		log15.Error("insights: unable to retrieve commits for incremental recording", "repo_id", repoID, "since", since, "error", err)
+		return time.Time{}, false
+	}
+	return metadata.LastIndexedAt, len(commits) == 0
+}
+
+// incrementalSearchQuery returns the given search query restricted to the repositories searched
+// by the given plan.
+func incrementalSearchQuery(searchQuery string, plan *incrementalPlan) string {
+	return discovery.ScopedQuery(insights.TimeSeries{Query: searchQuery, Repositories: plan.searchRepos})
+}
+
+// recordCarriedPoints records the data points carried forward by the given plan at the given time.
+func recordCarriedPoints(ctx context.Context, insightsStore *store.Store, plan *incrementalPlan, recordTime time.Time) error {
+	for seriesID, points := range plan.carried {
+		for _, point := range points {
+			repoName, repoID := point.RepoName, point.RepoID
+			if err := insightsStore.RecordSeriesPoint(ctx, store.RecordSeriesPointArgs{
+				SeriesID: seriesID,
+				Point:    store.SeriesPoint{Time: recordTime, Value: point.Value, Approximate: point.Approximate},
+				RepoName: &repoName,
+				RepoID:   &repoID,
+				Metadata: incrementalMetadata{AsOf: point.asOf.UTC()},
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package queryrunner
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/compression"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+type fakeIncrementalStore struct {
+	statuses map[string]store.SeriesRunStatus
+	points   map[string][]store.RepoPoint
+}
+
+func (s fakeIncrementalStore) SeriesRunStatus(ctx context.Context, seriesID string) (store.SeriesRunStatus, bool, error) {
+	status, ok := s.statuses[seriesID]
+	return status, ok, nil
+}
+
+func (s fakeIncrementalStore) LatestRepoPoints(ctx context.Context, seriesID string) ([]store.RepoPoint, error) {
+	return s.points[seriesID], nil
+}
+
+func TestPlanIncrementalRecording(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 9, 10, 12, 30, 0, 0, time.UTC)
+	previous := now.Add(-24 * time.Hour)
+	lastFullRunAt := now.Add(-3 * 24 * time.Hour)
+	indexedAt := now.Add(-20 * time.Minute)
+
+	// repo1 has no new commits, repo2 has new commits, and repo3 is not indexed.
+	commitStore := compression.NewMockCommitStore()
+	commitStore.GetMetadataFunc.SetDefaultHook(func(ctx context.Context, repoID api.RepoID) (compression.CommitIndexMetadata, error) {
+		if repoID == 3 {
+			return compression.CommitIndexMetadata{}, sql.ErrNoRows
+		}
+		return compression.CommitIndexMetadata{RepoId: int(repoID), Enabled: true, LastIndexedAt: indexedAt}, nil
+	})
+	commitStore.GetFunc.SetDefaultHook(func(ctx context.Context, repoID api.RepoID, start, end time.Time) ([]compression.CommitStamp, error) {
+		if repoID == 2 {
+			return []compression.CommitStamp{{RepoID: 2, Commit: "abc", CommittedAt: start.Add(time.Minute)}}, nil
+		}
+		return nil, nil
+	})
+
+	points := []store.RepoPoint{
+		{RepoID: 1, RepoName: "repo1", Time: previous, Value: 1},
+		{RepoID: 2, RepoName: "repo2", Time: previous, Value: 2},
+		{RepoID: 3, RepoName: "repo3", Time: previous, Value: 3},
+	}
+	insightsStore := fakeIncrementalStore{
+		statuses: map[string]store.SeriesRunStatus{
+			"s:one":     {LastFullRunAt: &lastFullRunAt},
+			"s:two":     {LastFullRunAt: &lastFullRunAt},
+			"s:failing": {LastFullRunAt: &lastFullRunAt, ConsecutiveFailures: 1},
+			"s:stale":   {LastFullRunAt: &previous},
+		},
+		points: map[string][]store.RepoPoint{
+			"s:one": points,
+			"s:two": points[:1],
+		},
+	}
+
+	t.Run("incremental", func(t *testing.T) {
+		plan, ok, err := planIncrementalRecording(ctx, insightsStore, commitStore, now, 7*24*time.Hour, []string{"s:one", "s:two"})
+		if err != nil {
+			t.Fatalf("unexpected error planning recording: %s", err)
+		}
+		if !ok {
+			t.Fatalf("expected incremental recording")
+		}
+		if diff := cmp.Diff([]string{"repo2", "repo3"}, plan.searchRepos); diff != "" {
+			t.Errorf("unexpected searched repositories (-want +got):\n%s", diff)
+		}
+		carried := []carriedPoint{{RepoPoint: points[0], asOf: indexedAt.Truncate(time.Hour)}}
+		want := map[string][]carriedPoint{"s:one": carried, "s:two": carried}
+		if diff := cmp.Diff(want, plan.carried, cmp.AllowUnexported(carriedPoint{})); diff != "" {
+			t.Errorf("unexpected carried points (-want +got):\n%s", diff)
+		}
+		if query := incrementalSearchQuery("foo count:all", plan); query != "repo:^(repo2|repo3)$ foo count:all" {
+			t.Errorf("unexpected search query. want=%q have=%q", "repo:^(repo2|repo3)$ foo count:all", query)
+		}
+	})
+
+	for _, testCase := range []struct {
+		name                  string
+		seriesIDs             []string
+		fullRecomputeInterval time.Duration
+	}{
+		{"disabled", []string{"s:one"}, 0},
+		{"never recorded", []string{"s:one", "s:new"}, 7 * 24 * time.Hour},
+		{"previous recording failed", []string{"s:failing"}, 7 * 24 * time.Hour},
+		{"full recomputation due", []string{"s:one"}, 2 * 24 * time.Hour},
+		{"full recomputation due for one series", []string{"s:one", "s:stale"}, 24 * time.Hour},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, ok, err := planIncrementalRecording(ctx, insightsStore, commitStore, now, testCase.fullRecomputeInterval, testCase.seriesIDs); err != nil {
+				t.Fatalf("unexpected error planning recording: %s", err)
+			} else if ok {
+				t.Errorf("expected full recording")
+			}
+		})
+	}
+}
+
+func TestPlanIncrementalRecordingAsOf(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2021, 9, 10, 12, 30, 0, 0, time.UTC)
+	lastFullRunAt := now.Add(-24 * time.Hour)
+	asOf := now.Add(-2 * time.Hour)
+
+	// Commits are looked for after the time the carried point was last known to be up to date,
+	// rather than after the time it was recorded at.
+	var since time.Time
+	commitStore := compression.NewMockCommitStore()
+	commitStore.GetMetadataFunc.SetDefaultReturn(compression.CommitIndexMetadata{Enabled: true, LastIndexedAt: now}, nil)
+	commitStore.GetFunc.SetDefaultHook(func(ctx context.Context, repoID api.RepoID, start, end time.Time) ([]compression.CommitStamp, error) {
+		since = start
+		return nil, nil
+	})
+	insightsStore := fakeIncrementalStore{
+		statuses: map[string]store.SeriesRunStatus{"s:one": {LastFullRunAt: &lastFullRunAt}},
+		points: map[string][]store.RepoPoint{"s:one": {
+			{RepoID: 1, RepoName: "repo1", Time: now.Add(-time.Hour), Value: 1, Metadata: []byte(`{"asOf": "2021-09-10T10:30:00Z"}`)},
+		}},
+	}
+
+	plan, ok, err := planIncrementalRecording(ctx, insightsStore, commitStore, now, 7*24*time.Hour, []string{"s:one"})
+	if err != nil {
+		t.Fatalf("unexpected error planning recording: %s", err)
+	}
+	if !ok {
+		t.Fatalf("expected incremental recording")
+	}
+	if !since.Equal(asOf) {
+		t.Errorf("unexpected start of commits. want=%s have=%s", asOf, since)
+	}
+	if len(plan.searchRepos) != 0 {
+		t.Errorf("unexpected searched repositories: %v", plan.searchRepos)
+	}
+	if carried := plan.carried["s:one"]; len(carried) != 1 || !carried[0].asOf.Equal(now.Truncate(time.Hour)) {
+		t.Errorf("unexpected carried points: %+v", carried)
+	}
+}
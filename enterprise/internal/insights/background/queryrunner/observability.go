@@ -0,0 +1,121 @@
+package queryrunner
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// maxLabeledSeries is the maximum number of distinct series IDs used as the series label of the
+// per-series metrics. Series IDs are created by users, so the label is bounded to keep the
+// cardinality of the metrics in check.
+const maxLabeledSeries = 100
+
+// otherSeriesLabel is the series label of the series beyond the first maxLabeledSeries.
+const otherSeriesLabel = "other"
+
+type operations struct {
+	// handle traces the handling of a job, and search traces the search query of a job.
+	handle *observation.Operation
+	search *observation.Operation
+
+	// seriesMetrics records the duration and the errors of the jobs recording each series,
+	// labeled by series and by kind of recording (see recordingKind).
+	seriesMetrics *metrics.OperationMetrics
+	seriesLabels  *seriesLabels
+
+	// cost sums the estimated cost of the handled jobs by kind of recording, which is the demand
+	// the jobs put on search for capacity planning.
+	cost *prometheus.CounterVec
+}
+
+var (
+	singletonOperations *operations
+	once                sync.Once
+)
+
+// newOperations returns the operations of the query runner worker. Metrics are registered once,
+// as the worker may be created more than once per process.
+func newOperations(observationContext *observation.Context) *operations {
+	once.Do(func() {
+		op := func(name string) *observation.Operation {
+			return observationContext.Operation(observation.Op{
+				Name: "insights.queryrunner." + name,
+			})
+		}
+
+		cost := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "src_insights_query_runner_cost_total",
+			Help: "Total estimated cost of the query runner jobs handled.",
+		}, []string{"kind"})
+		observationContext.Registerer.MustRegister(cost)
+
+		singletonOperations = &operations{
+			handle: op("Handle"),
+			search: op("Search"),
+			seriesMetrics: metrics.NewOperationMetrics(
+				observationContext.Registerer,
+				"insights_query_runner_series",
+				metrics.WithLabels("series", "kind"),
+				metrics.WithCountHelp("Total number of query runner jobs recording each series."),
+				metrics.WithDurationHelp("Time in seconds spent handling the query runner jobs recording each series."),
+				metrics.WithErrorsHelp("Total number of query runner jobs recording each series that failed."),
+			),
+			seriesLabels: newSeriesLabels(maxLabeledSeries),
+			cost:         cost,
+		}
+	})
+	return singletonOperations
+}
+
+// observeSeries records the duration and the outcome of a job for each series it records data
+// points of. Jobs recording several series are counted once for each of them.
+func (o *operations) observeSeries(job *Job, duration time.Duration, err error) {
+	kind := recordingKind(job)
+	for _, seriesID := range jobSeriesIDs(job) {
+		o.seriesMetrics.Observe(duration.Seconds(), 1, &err, o.seriesLabels.label(seriesID), kind)
+	}
+}
+
+// observeCost adds the estimated cost of the given job to the total cost of the handled jobs.
+func (o *operations) observeCost(job *Job) {
+	o.cost.WithLabelValues(recordingKind(job)).Add(float64(job.Cost))
+}
+
+// recordingKind returns whether the given job records present-day data points ("current") or
+// historical data points ("historical"). It is used as a metric label, so it has few values.
+func recordingKind(job *Job) string {
+	if job.RecordTime != nil {
+		return "historical"
+	}
+	return "current"
+}
+
+// seriesLabels assigns series labels to series IDs: the first series seen are labeled by their
+// series ID, up to a maximum, and all others share the same label.
+type seriesLabels struct {
+	mu     sync.Mutex
+	max    int
+	labels map[string]struct{}
+}
+
+func newSeriesLabels(max int) *seriesLabels {
+	return &seriesLabels{max: max, labels: map[string]struct{}{}}
+}
+
+func (l *seriesLabels) label(seriesID string) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.labels[seriesID]; ok {
+		return seriesID
+	}
+	if len(l.labels) >= l.max {
+		return otherSeriesLabel
+	}
+	l.labels[seriesID] = struct{}{}
+	return seriesID
+}
@@ -0,0 +1,74 @@
+package queryrunner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+)
+
+func TestSeriesLabels(t *testing.T) {
+	labels := newSeriesLabels(2)
+	for _, testCase := range []struct {
+		seriesID string
+		want     string
+	}{
+		{"s:one", "s:one"},
+		{"s:two", "s:two"},
+		{"s:three", otherSeriesLabel},
+		{"s:one", "s:one"},
+		{"s:three", otherSeriesLabel},
+	} {
+		if have := labels.label(testCase.seriesID); have != testCase.want {
+			t.Errorf("unexpected label of series %q. want=%q have=%q", testCase.seriesID, testCase.want, have)
+		}
+	}
+}
+
+func TestObserveSeries(t *testing.T) {
+	o := &operations{
+		seriesMetrics: metrics.NewOperationMetrics(prometheus.NewRegistry(), "test", metrics.WithLabels("series", "kind")),
+		seriesLabels:  newSeriesLabels(maxLabeledSeries),
+	}
+
+	recordTime := time.Now()
+	o.observeSeries(&Job{SeriesID: "s:one", RecordTime: &recordTime}, time.Second, errors.New("search timed out"))
+	o.observeSeries(&Job{SeriesID: "s:batch", BatchedSeries: []BatchedSeries{{SeriesID: "s:one"}, {SeriesID: "s:two"}}}, time.Second, nil)
+
+	for _, testCase := range []struct {
+		series, kind string
+		wantCount    float64
+		wantErrors   float64
+	}{
+		{"s:one", "historical", 1, 1},
+		{"s:one", "current", 1, 0},
+		{"s:two", "current", 1, 0},
+		{"s:batch", "current", 0, 0},
+	} {
+		if have := testutil.ToFloat64(o.seriesMetrics.Count.WithLabelValues(testCase.series, testCase.kind)); have != testCase.wantCount {
+			t.Errorf("unexpected count of %s %s. want=%v have=%v", testCase.kind, testCase.series, testCase.wantCount, have)
+		}
+		if have := testutil.ToFloat64(o.seriesMetrics.Errors.WithLabelValues(testCase.series, testCase.kind)); have != testCase.wantErrors {
+			t.Errorf("unexpected errors of %s %s. want=%v have=%v", testCase.kind, testCase.series, testCase.wantErrors, have)
+		}
+	}
+}
+
+func TestObserveCost(t *testing.T) {
+	o := &operations{cost: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test"}, []string{"kind"})}
+
+	recordTime := time.Now()
+	o.observeCost(&Job{SeriesID: "s:one", Cost: 500})
+	o.observeCost(&Job{SeriesID: "s:two", Cost: 250})
+	o.observeCost(&Job{SeriesID: "s:one", Cost: 50, RecordTime: &recordTime})
+
+	for kind, want := range map[string]float64{"current": 750, "historical": 50} {
+		if have := testutil.ToFloat64(o.cost.WithLabelValues(kind)); have != want {
+			t.Errorf("unexpected cost of %s jobs. want=%v have=%v", kind, want, have)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package queryrunner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// This file contains the commit resolution step of jobs pinned to the revision a repository had at
+// the job's record time. Historical data points are only accurate if they are computed from the
+// repository as it was at the time they are recorded at.
+
+// findNearestCommitFunc finds the commit nearest to the target time (see git.FindNearestCommit).
+type findNearestCommitFunc func(ctx context.Context, repoName api.RepoName, revSpec string, target time.Time) (*git.Commit, error)
+
+// pinnedRevisionMetadata is the metadata recorded with the data points of pinned jobs, describing
+// the revision that was searched.
+type pinnedRevisionMetadata struct {
+	Revision string `json:"revision"`
+}
+
+// pinSearchQuery returns the search query of the given job, restricted to the pinned revision of
+// its pinned repository if it has one. The revision is resolved via gitserver the first time and
+// stored with the job. It returns false if the repository has no commits to search, e.g. because it
+// is not cloned yet, in which case there is nothing to record.
+func pinSearchQuery(ctx context.Context, workerBaseStore *basestore.Store, findNearestCommit findNearestCommitFunc, job *Job) (string, bool, error) {
+	if job.PinnedRepo == nil {
+		return job.SearchQuery, true, nil
+	}
+
+	if job.PinnedRevision == nil {
+		if job.RecordTime == nil {
+			return "", false, errors.New("pinned job has no record time")
+		}
+
+		commit, err := findNearestCommit(ctx, api.RepoName(*job.PinnedRepo), "HEAD", *job.RecordTime)
+		if err != nil {
+			if errors.HasType(err, &gitserver.RevisionNotFoundError{}) || vcs.IsRepoNotExist(err) {
+				return "", false, nil // repo may not be cloned yet (or not even pushed to code host yet)
+			}
+			return "", false, errors.Wrap(err, "FindNearestCommit")
+		}
+		if commit == nil {
+			return "", false, nil // repository has no commits / is empty
+		}
+
+		revision := string(commit.ID)
+		if err := workerBaseStore.Exec(ctx, sqlf.Sprintf(pinRevisionFmtStr, revision, job.ID)); err != nil {
+			return "", false, errors.Wrap(err, "storing pinned revision")
+		}
+		job.PinnedRevision = &revision
+	}
+
+	return fmt.Sprintf("%s repo:^%s$@%s", job.SearchQuery, regexp.QuoteMeta(*job.PinnedRepo), *job.PinnedRevision), true, nil
+}
+
+const pinRevisionFmtStr = `
+-- source: enterprise/internal/insights/background/queryrunner/pin.go:pinSearchQuery
+UPDATE insights_query_runner_jobs SET pinned_revision = %s WHERE id = %s
+`
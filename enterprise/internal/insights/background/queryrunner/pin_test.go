@@ -0,0 +1,74 @@
+package queryrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestPinSearchQuery(t *testing.T) {
+	recordTime := time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)
+	repo, revision := "github.com/sourcegraph/sourcegraph", "deadbeef"
+
+	unexpectedFindNearestCommit := func(ctx context.Context, repoName api.RepoName, revSpec string, target time.Time) (*git.Commit, error) {
+		t.Fatalf("unexpected call to FindNearestCommit")
+		return nil, nil
+	}
+
+	for _, tc := range []struct {
+		name              string
+		job               *Job
+		findNearestCommit findNearestCommitFunc
+		wantQuery         string
+		wantOK            bool
+	}{
+		{
+			name:              "not pinned",
+			job:               &Job{SearchQuery: "errorf count:9999999"},
+			findNearestCommit: unexpectedFindNearestCommit,
+			wantQuery:         "errorf count:9999999",
+			wantOK:            true,
+		},
+		{
+			name:              "revision already resolved",
+			job:               &Job{SearchQuery: "errorf count:9999999", RecordTime: &recordTime, PinnedRepo: &repo, PinnedRevision: &revision},
+			findNearestCommit: unexpectedFindNearestCommit,
+			wantQuery:         `errorf count:9999999 repo:^github\.com/sourcegraph/sourcegraph$@deadbeef`,
+			wantOK:            true,
+		},
+		{
+			name: "repository not cloned",
+			job:  &Job{SearchQuery: "errorf count:9999999", RecordTime: &recordTime, PinnedRepo: &repo},
+			findNearestCommit: func(ctx context.Context, repoName api.RepoName, revSpec string, target time.Time) (*git.Commit, error) {
+				return nil, &gitserver.RevisionNotFoundError{Repo: repoName, Spec: revSpec}
+			},
+		},
+		{
+			name: "repository empty",
+			job:  &Job{SearchQuery: "errorf count:9999999", RecordTime: &recordTime, PinnedRepo: &repo},
+			findNearestCommit: func(ctx context.Context, repoName api.RepoName, revSpec string, target time.Time) (*git.Commit, error) {
+				if repoName != api.RepoName(repo) || !target.Equal(recordTime) {
+					t.Errorf("unexpected arguments. repoName=%q target=%s", repoName, target)
+				}
+				return nil, nil
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			query, ok, err := pinSearchQuery(context.Background(), nil, tc.findNearestCommit, tc.job)
+			if err != nil {
+				t.Fatalf("unexpected error pinning search query: %s", err)
+			}
+			if ok != tc.wantOK {
+				t.Errorf("unexpected ok. want=%v have=%v", tc.wantOK, ok)
+			}
+			if query != tc.wantQuery {
+				t.Errorf("unexpected query. want=%q have=%q", tc.wantQuery, query)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package queryrunner
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+)
+
+// This file contains the scheduling of jobs on the query runner worker:
+//
+// 1. Jobs are dequeued in order of their priority, which is raised the longer they wait so that
+//    jobs of low priority (e.g. historical backfill jobs) are not starved by a steady stream of
+//    jobs of high priority.
+// 2. The total cost of the jobs being handled at once is kept within the cost budget of the
+//    worker, so that cheap jobs run alongside expensive ones instead of queueing behind them.
+// 3. Jobs that do not fit the remaining budget for long enough hold back all other jobs until
+//    the budget has drained for them to run, so that expensive jobs are not starved by a steady
+//    stream of cheap jobs.
+// 4. The number of jobs handled at once and the number of searches running at once are read from
+//    the site configuration (or the environment) every time, so that they can be changed without
+//    restarting the worker.
+// 5. No job is dequeued while the query runner is paused by the site configuration, so that site
+//    admins can shed search load without scaling the worker down.
+//
+
+// workerPriorityAgingInterval is the time a job must spend queued for its priority to be raised by
+// one on the query runner worker. A historical backfill job of priority 365 overtakes newly
+// enqueued jobs of high priority after about six hours.
+const workerPriorityAgingInterval = time.Minute
+
+// costStarvationAge is the time after which a queued job that does not fit the remaining cost
+// budget holds back all other jobs.
+const costStarvationAge = 10 * time.Minute
+
+// Paused returns true if the execution of jobs is paused by the site configuration. Jobs remain
+// queued while execution is paused, and jobs being handled already are completed.
+func Paused() bool {
+	return conf.Get().InsightsQueryWorkerPaused
+}
+
+// CostBudget returns the maximum total cost of the jobs handled at once by a worker node, or zero
+// if the cost of jobs is not limited.
+func CostBudget() int64 {
+	return int64(conf.Get().InsightsQueryWorkerCostBudget)
+}
+
+// costBudgetConditions returns the dequeue conditions that keep the total cost of the jobs being
+// handled within the given budget, given the total cost of the jobs being handled already. The
+// given conditions restrict the jobs the worker may dequeue at all.
+//
+// If no job is being handled, any job may be dequeued regardless of its cost so that jobs costing
+// more than the budget still run, but jobs that have waited costStarvationAge go first. Otherwise,
+// only jobs fitting the remaining budget are dequeued, and none at all once a job has waited
+// costStarvationAge without fitting, so that the budget drains for it.
+func costBudgetConditions(budget, costInUse int64, conditions []*sqlf.Query) []*sqlf.Query {
+	if costInUse == 0 {
+		return []*sqlf.Query{sqlf.Sprintf(
+			"(NOT EXISTS (%s) OR insights_query_runner_jobs.queued_at < NOW() - (%s * '1 second'::interval))",
+			starvedJobs(conditions),
+			costStarvationAge.Seconds(),
+		)}
+	}
+
+	remaining := budget - costInUse
+	unfitting := append([]*sqlf.Query{sqlf.Sprintf("insights_query_runner_jobs.cost > %s", remaining)}, conditions...)
+	return []*sqlf.Query{
+		sqlf.Sprintf("insights_query_runner_jobs.cost <= %s", remaining),
+		sqlf.Sprintf("NOT EXISTS (%s)", starvedJobs(unfitting)),
+	}
+}
+
+// starvedJobs returns a query selecting the jobs matching the given conditions that have been
+// queued for costStarvationAge. Column references in the conditions refer to the selected jobs.
+func starvedJobs(conditions []*sqlf.Query) *sqlf.Query {
+	return sqlf.Sprintf(
+		starvedJobsQuery,
+		costStarvationAge.Seconds(),
+		sqlf.Join(append([]*sqlf.Query{sqlf.Sprintf("TRUE")}, conditions...), " AND "),
+	)
+}
+
+const starvedJobsQuery = `
+SELECT 1 FROM insights_query_runner_jobs
+WHERE
+	insights_query_runner_jobs.state = 'queued' AND
+	(insights_query_runner_jobs.process_after IS NULL OR insights_query_runner_jobs.process_after <= NOW()) AND
+	insights_query_runner_jobs.queued_at < NOW() - (%s * '1 second'::interval) AND
+	%s
+`
+
+// maxWorkerConcurrency is the maximum number of jobs handled at once by a worker node, whatever its
+// configuration. The worker is created with this many handlers, and Concurrency limits the number
+// of them in use.
+const maxWorkerConcurrency = 64
+
+var (
+	envWorkerConcurrency       = env.Get("INSIGHTS_QUERY_WORKER_CONCURRENCY", "", "Number of concurrent executions of code insights queries on a worker node, overriding the insights.query.worker.concurrency site configuration.")
+	envWorkerSearchConcurrency = env.Get("INSIGHTS_QUERY_WORKER_SEARCH_CONCURRENCY", "", "Maximum number of code insights searches running at once on a worker node, overriding the insights.query.worker.searchConcurrency site configuration.")
+)
+
+// Concurrency returns the maximum number of jobs handled at once by a worker node, which is at
+// least one and at most maxWorkerConcurrency.
+func Concurrency() int {
+	concurrency := conf.Get().InsightsQueryWorkerConcurrency
+	if override, err := strconv.Atoi(envWorkerConcurrency); err == nil {
+		concurrency = override
+	}
+	if concurrency <= 0 {
+		return 1
+	}
+	if concurrency > maxWorkerConcurrency {
+		return maxWorkerConcurrency
+	}
+	return concurrency
+}
+
+// SearchConcurrency returns the maximum number of searches running at once on a worker node, or
+// zero if searches are only limited by Concurrency.
+func SearchConcurrency() int {
+	if override, err := strconv.Atoi(envWorkerSearchConcurrency); err == nil {
+		return override
+	}
+	return conf.Get().InsightsQueryWorkerSearchConcurrency
+}
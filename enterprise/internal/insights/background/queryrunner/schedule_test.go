@@ -0,0 +1,226 @@
+package queryrunner
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestPreDequeueConcurrency(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{InsightsQueryWorkerConcurrency: 2}})
+	defer conf.Mock(nil)
+
+	ctx := context.Background()
+	handler := &workHandler{}
+
+	for _, dequeueable := range []bool{true, true, false} {
+		have, _, err := handler.PreDequeue(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if have != dequeueable {
+			t.Errorf("unexpected dequeueable with %d jobs handled. want=%v have=%v", handler.handling, dequeueable, have)
+		}
+		handler.PreHandle(ctx, &Job{})
+	}
+
+	// Raising the concurrency takes effect right away.
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{InsightsQueryWorkerConcurrency: 4}})
+	if dequeueable, _, err := handler.PreDequeue(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !dequeueable {
+		t.Errorf("expected job to be dequeueable after raising the concurrency")
+	}
+}
+
+func TestPreDequeuePaused(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{InsightsQueryWorkerPaused: true, InsightsQueryWorkerBackfillOnExecutors: true}})
+	defer conf.Mock(nil)
+
+	ctx := context.Background()
+	handler := &workHandler{}
+	if dequeueable, _, err := handler.PreDequeue(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if dequeueable {
+		t.Errorf("unexpected dequeueable job while paused")
+	}
+	if diff := cmp.Diff([]string{"FALSE"}, queryStrings(ExecutorDequeueConditions())); diff != "" {
+		t.Errorf("unexpected executor dequeue conditions while paused (-want +got):\n%s", diff)
+	}
+
+	// Resuming takes effect right away.
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{InsightsQueryWorkerBackfillOnExecutors: true}})
+	if dequeueable, _, err := handler.PreDequeue(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !dequeueable {
+		t.Errorf("expected job to be dequeueable after resuming")
+	}
+	if conditions := ExecutorDequeueConditions(); len(conditions) != 3 {
+		t.Errorf("unexpected number of executor dequeue conditions after resuming. want=%d have=%d", 3, len(conditions))
+	}
+}
+
+func queryStrings(queries []*sqlf.Query) []string {
+	strs := make([]string, 0, len(queries))
+	for _, q := range queries {
+		strs = append(strs, q.Query(sqlf.PostgresBindVar))
+	}
+	return strs
+}
+
+func TestConcurrency(t *testing.T) {
+	defer func(worker, search string) {
+		envWorkerConcurrency, envWorkerSearchConcurrency = worker, search
+	}(envWorkerConcurrency, envWorkerSearchConcurrency)
+	defer conf.Mock(nil)
+
+	for _, testCase := range []struct {
+		name                  string
+		concurrency           int
+		searchConcurrency     int
+		env, searchEnv        string
+		wantConcurrency       int
+		wantSearchConcurrency int
+	}{
+		{name: "defaults", wantConcurrency: 1, wantSearchConcurrency: 0},
+		{name: "site configuration", concurrency: 8, searchConcurrency: 2, wantConcurrency: 8, wantSearchConcurrency: 2},
+		{name: "environment overrides", concurrency: 8, searchConcurrency: 2, env: "3", searchEnv: "1", wantConcurrency: 3, wantSearchConcurrency: 1},
+		{name: "invalid environment", concurrency: 8, env: "many", wantConcurrency: 8},
+		{name: "above maximum", concurrency: 1000, wantConcurrency: maxWorkerConcurrency},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				InsightsQueryWorkerConcurrency:       testCase.concurrency,
+				InsightsQueryWorkerSearchConcurrency: testCase.searchConcurrency,
+			}})
+			envWorkerConcurrency, envWorkerSearchConcurrency = testCase.env, testCase.searchEnv
+
+			if have := Concurrency(); have != testCase.wantConcurrency {
+				t.Errorf("unexpected concurrency. want=%d have=%d", testCase.wantConcurrency, have)
+			}
+			if have := SearchConcurrency(); have != testCase.wantSearchConcurrency {
+				t.Errorf("unexpected search concurrency. want=%d have=%d", testCase.wantSearchConcurrency, have)
+			}
+		})
+	}
+}
+
+func TestPreDequeueCostBudget(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{InsightsQueryWorkerCostBudget: 5500, InsightsQueryWorkerConcurrency: 10}})
+	defer conf.Mock(nil)
+
+	ctx := context.Background()
+	handler := &workHandler{}
+
+	for _, testCase := range []struct {
+		job         *Job
+		dequeueable bool
+	}{
+		{&Job{Cost: 5000}, true},
+		{&Job{Cost: 500}, false}, // the budget is used up
+	} {
+		handler.PreHandle(ctx, testCase.job)
+		dequeueable, _, err := handler.PreDequeue(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if dequeueable != testCase.dequeueable {
+			t.Errorf("unexpected dequeueable with cost %d in use. want=%v have=%v", handler.costInUse, testCase.dequeueable, dequeueable)
+		}
+	}
+
+	handler.PostHandle(ctx, &Job{Cost: 5000})
+	handler.PostHandle(ctx, &Job{Cost: 500})
+	if handler.costInUse != 0 {
+		t.Errorf("unexpected cost in use. want=%d have=%d", 0, handler.costInUse)
+	}
+}
+
+func TestCostBudgetConditions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	type queuedJob struct {
+		id       int
+		priority int
+		cost     int
+		waited   time.Duration
+	}
+
+	testCases := []struct {
+		name        string
+		costInUse   int64
+		jobs        []queuedJob
+		expectedIDs []int
+	}{
+		{
+			name:        "only jobs fitting the remaining budget",
+			costInUse:   1000,
+			jobs:        []queuedJob{{id: 1, priority: 1, cost: 5000, waited: time.Minute}, {id: 2, priority: 10, cost: 500, waited: time.Minute}},
+			expectedIDs: []int{2},
+		},
+		{
+			name:        "starved jobs hold back other jobs",
+			costInUse:   1000,
+			jobs:        []queuedJob{{id: 1, priority: 1, cost: 5000, waited: time.Hour}, {id: 2, priority: 10, cost: 500, waited: time.Minute}},
+			expectedIDs: nil,
+		},
+		{
+			name:        "starved jobs go first when idle",
+			costInUse:   0,
+			jobs:        []queuedJob{{id: 1, priority: 100, cost: 5000, waited: time.Hour}, {id: 2, priority: 1, cost: 500, waited: time.Minute}},
+			expectedIDs: []int{1, 2},
+		},
+		{
+			name:        "any job when idle",
+			costInUse:   0,
+			jobs:        []queuedJob{{id: 1, priority: 100, cost: 10000, waited: time.Minute}, {id: 2, priority: 1, cost: 500, waited: time.Minute}},
+			expectedIDs: []int{2, 1},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			workerBaseStore := basestore.NewWithDB(dbtesting.GetDB(t), sql.TxOptions{})
+
+			for _, job := range testCase.jobs {
+				if err := workerBaseStore.Exec(ctx, sqlf.Sprintf(
+					`INSERT INTO insights_query_runner_jobs (id, series_id, search_query, priority, cost, queued_at) VALUES (%s, 's', 'q', %s, %s, %s)`,
+					job.id, job.priority, job.cost, time.Now().Add(-job.waited),
+				)); err != nil {
+					t.Fatalf("unexpected error inserting job: %s", err)
+				}
+			}
+
+			workerStore := createDBWorkerStore(workerBaseStore)
+			conditions := costBudgetConditions(5500, testCase.costInUse, nil)
+
+			var ids []int
+			for {
+				record, ok, err := workerStore.Dequeue(ctx, "test", conditions)
+				if err != nil {
+					t.Fatalf("unexpected error dequeueing job: %s", err)
+				}
+				if !ok {
+					break
+				}
+				ids = append(ids, record.RecordID())
+			}
+
+			if diff := cmp.Diff(testCase.expectedIDs, ids); diff != "" {
+				t.Errorf("unexpected dequeued jobs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package queryrunner
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// searchLimiter limits the searches executed by the query runner worker, both in the number of
+// searches started per second and in the number of searches running at once. It is shared by all
+// the handlers of the worker, so that the searches of a worker node never exceed its limits no
+// matter how many jobs it handles at once, or how jobs were scheduled when enqueued.
+type searchLimiter struct {
+	limiter *rate.Limiter
+
+	mu          sync.Mutex
+	concurrency int           // the maximum number of searches running at once, or zero if unlimited
+	running     int           // the number of searches running
+	changed     chan struct{} // closed when a search finishes or the limits change
+}
+
+func newSearchLimiter(limit rate.Limit, concurrency int) *searchLimiter {
+	return &searchLimiter{
+		limiter:     rate.NewLimiter(limit, 1),
+		concurrency: concurrency,
+		changed:     make(chan struct{}),
+	}
+}
+
+// SetLimits changes the limits of the limiter. Searches already running are not affected, but no
+// search starts until the number of running searches is within the new concurrency.
+func (l *searchLimiter) SetLimits(limit rate.Limit, concurrency int) {
+	l.limiter.SetLimit(limit)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.concurrency = concurrency
+	l.notify()
+}
+
+// Acquire blocks until a search may start within the limits, or the given context is canceled.
+// The returned function must be called once the search finishes.
+//
+// A search first waits for one of the running searches to finish, and only then for the rate
+// limit, so that waiting on a slow search does not use up the searches allowed per second.
+func (l *searchLimiter) Acquire(ctx context.Context) (release func(), err error) {
+	for {
+		l.mu.Lock()
+		if l.concurrency <= 0 || l.running < l.concurrency {
+			l.running++
+			l.mu.Unlock()
+			break
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.running--
+			l.notify()
+		})
+	}
+	if err := l.limiter.Wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// notify wakes up all searches waiting for a search to finish. The caller must hold l.mu.
+func (l *searchLimiter) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
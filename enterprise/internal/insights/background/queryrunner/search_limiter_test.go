@@ -0,0 +1,86 @@
+package queryrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestSearchLimiterConcurrency(t *testing.T) {
+	ctx := context.Background()
+	limiter := newSearchLimiter(rate.Inf, 1)
+
+	release, err := limiter.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// A second search waits for the first one to finish.
+	acquired := make(chan func())
+	go func() {
+		release, err := limiter.Acquire(ctx)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		acquired <- release
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("unexpected search started while another search is running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	release() // releasing twice must not free another slot
+	select {
+	case secondRelease := <-acquired:
+		secondRelease()
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected search to start once the running search finished")
+	}
+	if limiter.running != 0 {
+		t.Errorf("unexpected number of running searches. want=%d have=%d", 0, limiter.running)
+	}
+}
+
+func TestSearchLimiterSetLimits(t *testing.T) {
+	ctx := context.Background()
+	limiter := newSearchLimiter(rate.Inf, 1)
+
+	if _, err := limiter.Acquire(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Raising the concurrency lets waiting searches start.
+	acquired := make(chan struct{})
+	go func() {
+		if _, err := limiter.Acquire(ctx); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+		close(acquired)
+	}()
+	limiter.SetLimits(rate.Inf, 2)
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected search to start once the concurrency was raised")
+	}
+}
+
+func TestSearchLimiterCanceled(t *testing.T) {
+	limiter := newSearchLimiter(rate.Inf, 1)
+	if _, err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := limiter.Acquire(ctx); err != context.Canceled {
+		t.Errorf("unexpected error. want=%q have=%q", context.Canceled, err)
+	}
+	if limiter.running != 1 {
+		t.Errorf("unexpected number of running searches. want=%d have=%d", 1, limiter.running)
+	}
+}
@@ -0,0 +1,149 @@
+package queryrunner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// This file contains the sharding of jobs whose search times out. Very broad queries, such as
+// regexp searches of every repository, may time out every time they are retried. Instead of
+// failing, such a job is split into jobs of shards of the repositories, whose search queries are
+// restricted to the repositories of their shard. The jobs of the shards record their data points,
+// one per repository, at the same time, so that they add up to the data point of the job. Shards
+// whose search times out again are split in halves, down to shards of a single repository.
+//
+// Jobs pinned to a repository are not sharded, as they search a single repository already.
+
+// maxShardRepos is the maximum number of repositories of the shards a job of all repositories is
+// split into. Like incremental recordings, shards search their repositories by name, which is
+// only cheap for a limited number of repositories.
+const maxShardRepos = 100
+
+// SearchTimeout returns the time a single search of a job may take, unless the job overrides it,
+// or zero if searches only time out on the search side.
+func SearchTimeout() time.Duration {
+	return time.Duration(conf.Get().InsightsQueryWorkerSearchTimeoutSeconds) * time.Second
+}
+
+// jobSearchTimeout returns the time a single search of the given job may take, or zero if its
+// searches only time out on the search side.
+func jobSearchTimeout(job *Job) time.Duration {
+	if job.SearchTimeout > 0 {
+		return job.SearchTimeout
+	}
+	return SearchTimeout()
+}
+
+// shardSearchQuery returns the given search query of the given job restricted to the repositories
+// of the shard of the job, if it is one.
+func shardSearchQuery(job *Job, query string) string {
+	if len(job.ShardRepos) == 0 {
+		return query
+	}
+	return discovery.ScopedQuery(insights.TimeSeries{Query: query, Repositories: job.ShardRepos})
+}
+
+// shardable returns true if the given job may be split into jobs of shards should its search time
+// out.
+func shardable(job *Job) bool {
+	return job.PinnedRepo == nil && len(job.ShardRepos) != 1
+}
+
+// searchTimedOut returns true if the given error of a search reports that the search exceeded the
+// search timeout of its job, rather than that the given context of the job itself was canceled.
+func searchTimedOut(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded)
+}
+
+// splitShards splits the given repository names into shards: the repositories of a job of all
+// repositories into shards of at most maxShardRepos repositories, and the repositories of a shard
+// into halves.
+func splitShards(repoNames []string, shard bool) [][]string {
+	size := maxShardRepos
+	if shard {
+		size = (len(repoNames) + 1) / 2
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	shards := make([][]string, 0, (len(repoNames)+size-1)/size)
+	for len(repoNames) > size {
+		shards = append(shards, repoNames[:size])
+		repoNames = repoNames[size:]
+	}
+	if len(repoNames) > 0 {
+		shards = append(shards, repoNames)
+	}
+	return shards
+}
+
+// shardJobs returns the jobs of the given shards of the given job, which record their data points
+// at the given time. The cost of the job is divided among its shards by their number of
+// repositories.
+func shardJobs(job *Job, shards [][]string, recordTime time.Time) []*Job {
+	total := 0
+	for _, shard := range shards {
+		total += len(shard)
+	}
+
+	jobs := make([]*Job, 0, len(shards))
+	for i, shard := range shards {
+		cost := job.Cost * len(shard) / total
+		if cost < 1 {
+			cost = 1
+		}
+		jobs = append(jobs, &Job{
+			SeriesID:      job.SeriesID,
+			SearchQuery:   job.SearchQuery,
+			RecordTime:    &recordTime,
+			Cost:          cost,
+			Priority:      job.Priority,
+			BatchedSeries: job.BatchedSeries,
+			SearchTimeout: job.SearchTimeout,
+			ShardRepos:    shard,
+			// Guards against enqueueing the shards twice should the job be retried after
+			// enqueueing some of them.
+			IdempotencyKey: fmt.Sprintf("shard:%d:%d", job.ID, i),
+			State:          "queued",
+		})
+	}
+	return jobs
+}
+
+// enqueueShards splits the given job, whose search timed out, into jobs of shards of its
+// repositories and enqueues them. The shards of a job of present-day data record their data points
+// at the current time.
+func (r *workHandler) enqueueShards(ctx context.Context, job *Job) error {
+	repoNames := job.ShardRepos
+	if len(repoNames) == 0 {
+		var err error
+		repoNames, err = r.listRepoNames(ctx)
+		if err != nil {
+			return errors.Wrap(err, "listing repositories to shard")
+		}
+		sort.Strings(repoNames)
+	}
+
+	recordTime := time.Now()
+	if job.RecordTime != nil {
+		recordTime = *job.RecordTime
+	}
+	jobs := shardJobs(job, splitShards(repoNames, len(job.ShardRepos) > 0), recordTime)
+	for _, shardJob := range jobs {
+		if _, err := EnqueueJob(ctx, r.workerBaseStore, shardJob); err != nil {
+			return errors.Wrap(err, "EnqueueJob")
+		}
+	}
+	log15.Warn("insights.queryrunner.workHandler: search timed out, split job into shards", "seriesID", job.SeriesID, "shards", len(jobs), "repositories", len(repoNames))
+	return nil
+}
@@ -0,0 +1,143 @@
+package queryrunner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplitShards(t *testing.T) {
+	repoNames := make([]string, 0, 250)
+	for i := 0; i < 250; i++ {
+		repoNames = append(repoNames, fmt.Sprintf("github.com/sourcegraph/repo-%03d", i))
+	}
+
+	shards := splitShards(repoNames, false)
+	if have := shardSizes(shards); !cmp.Equal([]int{100, 100, 50}, have) {
+		t.Errorf("unexpected shards of all repositories. want=%v have=%v", []int{100, 100, 50}, have)
+	}
+
+	// Shards are split in halves, down to shards of a single repository.
+	for _, testCase := range []struct {
+		repos int
+		want  []int
+	}{
+		{repos: 100, want: []int{50, 50}},
+		{repos: 5, want: []int{3, 2}},
+		{repos: 2, want: []int{1, 1}},
+	} {
+		if have := shardSizes(splitShards(repoNames[:testCase.repos], true)); !cmp.Equal(testCase.want, have) {
+			t.Errorf("unexpected halves of shard of %d repositories. want=%v have=%v", testCase.repos, testCase.want, have)
+		}
+	}
+}
+
+func shardSizes(shards [][]string) []int {
+	sizes := make([]int, 0, len(shards))
+	for _, shard := range shards {
+		sizes = append(sizes, len(shard))
+	}
+	return sizes
+}
+
+func TestShardJobs(t *testing.T) {
+	recordTime := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	job := &Job{
+		ID:            42,
+		SeriesID:      "s:regexp",
+		SearchQuery:   "patterntype:regexp fmt\\.Sprintf\\(.*\\)",
+		Cost:          5000,
+		Priority:      10,
+		SearchTimeout: time.Minute,
+	}
+
+	have := shardJobs(job, [][]string{{"a", "b", "c"}, {"d"}}, recordTime)
+	want := []*Job{
+		{
+			SeriesID:       "s:regexp",
+			SearchQuery:    "patterntype:regexp fmt\\.Sprintf\\(.*\\)",
+			RecordTime:     &recordTime,
+			Cost:           3750,
+			Priority:       10,
+			SearchTimeout:  time.Minute,
+			ShardRepos:     []string{"a", "b", "c"},
+			IdempotencyKey: "shard:42:0",
+			State:          "queued",
+		},
+		{
+			SeriesID:       "s:regexp",
+			SearchQuery:    "patterntype:regexp fmt\\.Sprintf\\(.*\\)",
+			RecordTime:     &recordTime,
+			Cost:           1250,
+			Priority:       10,
+			SearchTimeout:  time.Minute,
+			ShardRepos:     []string{"d"},
+			IdempotencyKey: "shard:42:1",
+			State:          "queued",
+		},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("unexpected shard jobs (-want +got):\n%s", diff)
+	}
+}
+
+func TestShardSearchQuery(t *testing.T) {
+	if have := shardSearchQuery(&Job{}, "errorf"); have != "errorf" {
+		t.Errorf("unexpected search query of job that is no shard. want=%q have=%q", "errorf", have)
+	}
+	job := &Job{ShardRepos: []string{"github.com/sourcegraph/zoekt", "github.com/sourcegraph/sourcegraph"}}
+	want := `repo:^(github\.com/sourcegraph/sourcegraph|github\.com/sourcegraph/zoekt)$ errorf`
+	if have := shardSearchQuery(job, "errorf"); have != want {
+		t.Errorf("unexpected search query of shard. want=%q have=%q", want, have)
+	}
+}
+
+func TestShardable(t *testing.T) {
+	repo := "github.com/sourcegraph/sourcegraph"
+	for _, testCase := range []struct {
+		name string
+		job  *Job
+		want bool
+	}{
+		{name: "all repositories", job: &Job{}, want: true},
+		{name: "shard", job: &Job{ShardRepos: []string{"a", "b"}}, want: true},
+		{name: "shard of a single repository", job: &Job{ShardRepos: []string{"a"}}, want: false},
+		{name: "pinned", job: &Job{PinnedRepo: &repo}, want: false},
+	} {
+		if have := shardable(testCase.job); have != testCase.want {
+			t.Errorf("unexpected shardable for %s. want=%t have=%t", testCase.name, testCase.want, have)
+		}
+	}
+}
+
+func TestSearchTimedOut(t *testing.T) {
+	ctx := context.Background()
+	searchCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	<-searchCtx.Done()
+
+	if !searchTimedOut(ctx, errors.Wrap(searchCtx.Err(), "Post")) {
+		t.Errorf("expected search to time out")
+	}
+	if searchTimedOut(ctx, errors.New("graphql: errors")) {
+		t.Errorf("unexpected time out of failed search")
+	}
+
+	// The job itself running out of time is no search timeout.
+	if searchTimedOut(searchCtx, searchCtx.Err()) {
+		t.Errorf("unexpected search time out of canceled job")
+	}
+}
+
+func TestJobSearchTimeout(t *testing.T) {
+	if have := jobSearchTimeout(&Job{SearchTimeout: time.Minute}); have != time.Minute {
+		t.Errorf("unexpected search timeout of job overriding it. want=%s have=%s", time.Minute, have)
+	}
+	if have := jobSearchTimeout(&Job{}); have != 0 {
+		t.Errorf("unexpected default search timeout. want=%s have=%s", time.Duration(0), have)
+	}
+}
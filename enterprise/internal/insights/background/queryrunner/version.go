@@ -0,0 +1,83 @@
+package queryrunner
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// This file contains the versioning of the payload of jobs, i.e. of the meaning of their search
+// query, pinned and batched series fields. Jobs outlive the workers that enqueue them, so jobs of
+// older versions are upgraded to the current version when they are dequeued, and jobs of newer
+// versions, enqueued by workers of the next release during a deploy, are left for those workers.
+//
+// The versions of the payload are:
+//
+// 1. The legacy version of the jobs enqueued before payloads were versioned. Historical jobs may
+//    have the revision they search appended to their search query as `repo:^<repo>$@<commit>`
+//    instead of being pinned, and have no batched series.
+// 2. Historical jobs are pinned to their repository (see pin.go), and the search query of a job
+//    may batch the search queries of several series (see batch.go).
+// 3. The search query of a job may be restricted to a shard of repositories (see shard.go), and
+//    jobs may override the search timeout. Jobs of version 2 have neither.
+//
+// When changing the meaning of the payload, add a version and a shim to upgradeJob.
+
+// CurrentJobVersion is the version of the payload of the jobs enqueued by this worker.
+const CurrentJobVersion = 3
+
+// legacyPinnedQueryPattern matches the repository and revision appended to the search query of
+// legacy historical jobs.
+var legacyPinnedQueryPattern = regexp.MustCompile(`^(.*) repo:\^(.+)\$@([0-9a-f]{40})$`)
+
+// upgradeJob upgrades the payload of the given job in place to the current version. Jobs of a
+// version newer than the current one are left as they are (see checkJobVersion).
+func upgradeJob(job *Job) {
+	if job.Version > CurrentJobVersion {
+		return
+	}
+	if job.Version <= 1 {
+		upgradeJobFromV1(job)
+	}
+	job.Version = CurrentJobVersion
+}
+
+// checkJobVersion returns an error for jobs of a version newer than the current one, which this
+// worker cannot handle. Such jobs fail and are retried, eventually by a worker of their version.
+func checkJobVersion(job *Job) error {
+	if job.Version > CurrentJobVersion {
+		return errors.Errorf("job payload version %d is newer than the supported version %d", job.Version, CurrentJobVersion)
+	}
+	return nil
+}
+
+// upgradeJobFromV1 pins legacy historical jobs to the repository and revision appended to their
+// search query, so that they are searched, recorded and reported like the jobs pinned when they
+// are enqueued.
+func upgradeJobFromV1(job *Job) {
+	if job.RecordTime == nil || job.PinnedRepo != nil {
+		return
+	}
+	match := legacyPinnedQueryPattern.FindStringSubmatch(job.SearchQuery)
+	if match == nil || strings.ContainsAny(match[2], " \t") {
+		return
+	}
+	repo := unquoteMeta(match[2])
+	revision := match[3]
+	job.SearchQuery = match[1]
+	job.PinnedRepo = &repo
+	job.PinnedRevision = &revision
+}
+
+// unquoteMeta reverses regexp.QuoteMeta.
+func unquoteMeta(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package queryrunner
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUpgradeJob(t *testing.T) {
+	recordTime := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := "github.com/sourcegraph/sourcegraph"
+	revision := "0123456789abcdef0123456789abcdef01234567"
+	legacyQuery := "errorf count:all repo:^" + regexp.QuoteMeta(repo) + "$@" + revision
+
+	for _, testCase := range []struct {
+		name string
+		job  Job
+		want Job
+	}{
+		{
+			name: "legacy pinned historical job",
+			job:  Job{SeriesID: "s", SearchQuery: legacyQuery, RecordTime: &recordTime},
+			want: Job{SeriesID: "s", SearchQuery: "errorf count:all", RecordTime: &recordTime, PinnedRepo: &repo, PinnedRevision: &revision, Version: CurrentJobVersion},
+		},
+		{
+			name: "legacy current job",
+			job:  Job{SeriesID: "s", SearchQuery: "errorf", Version: 1},
+			want: Job{SeriesID: "s", SearchQuery: "errorf", Version: CurrentJobVersion},
+		},
+		{
+			name: "legacy job searching a revision now",
+			job:  Job{SeriesID: "s", SearchQuery: legacyQuery, Version: 1},
+			want: Job{SeriesID: "s", SearchQuery: legacyQuery, Version: CurrentJobVersion},
+		},
+		{
+			name: "legacy historical job without revision",
+			job:  Job{SeriesID: "s", SearchQuery: "errorf repo:^github\\.com/sourcegraph/sourcegraph$", RecordTime: &recordTime, Version: 1},
+			want: Job{SeriesID: "s", SearchQuery: "errorf repo:^github\\.com/sourcegraph/sourcegraph$", RecordTime: &recordTime, Version: CurrentJobVersion},
+		},
+		{
+			name: "version 2 batched job",
+			job:  Job{SeriesID: "b", SearchQuery: "lang:go (errorf OR warnf)", BatchedSeries: []BatchedSeries{{SeriesID: "s1", SearchQuery: "lang:go errorf"}}, Version: 2},
+			want: Job{SeriesID: "b", SearchQuery: "lang:go (errorf OR warnf)", BatchedSeries: []BatchedSeries{{SeriesID: "s1", SearchQuery: "lang:go errorf"}}, Version: CurrentJobVersion},
+		},
+		{
+			name: "current pinned job",
+			job:  Job{SeriesID: "s", SearchQuery: "errorf count:all", RecordTime: &recordTime, PinnedRepo: &repo, Version: CurrentJobVersion},
+			want: Job{SeriesID: "s", SearchQuery: "errorf count:all", RecordTime: &recordTime, PinnedRepo: &repo, Version: CurrentJobVersion},
+		},
+		{
+			name: "newer job",
+			job:  Job{SeriesID: "s", SearchQuery: legacyQuery, RecordTime: &recordTime, Version: CurrentJobVersion + 1},
+			want: Job{SeriesID: "s", SearchQuery: legacyQuery, RecordTime: &recordTime, Version: CurrentJobVersion + 1},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			job := testCase.job
+			upgradeJob(&job)
+			if diff := cmp.Diff(testCase.want, job); diff != "" {
+				t.Errorf("unexpected upgraded job (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpgradedLegacyJobSearchesSameRevision(t *testing.T) {
+	recordTime := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	legacyQuery := "errorf count:all repo:^github\\.com/sourcegraph/sourcegraph$@0123456789abcdef0123456789abcdef01234567"
+	job := &Job{SeriesID: "s", SearchQuery: legacyQuery, RecordTime: &recordTime}
+	upgradeJob(job)
+
+	// The revision of the upgraded job is pinned already, so it is neither resolved nor stored.
+	query, ok, err := pinSearchQuery(context.Background(), nil, nil, job)
+	if err != nil {
+		t.Fatalf("unexpected error pinning search query: %s", err)
+	}
+	if !ok || query != legacyQuery {
+		t.Errorf("unexpected pinned search query. want=%q have=%q", legacyQuery, query)
+	}
+}
+
+func TestCheckJobVersion(t *testing.T) {
+	for _, version := range []int{1, CurrentJobVersion} {
+		if err := checkJobVersion(&Job{Version: version}); err != nil {
+			t.Errorf("unexpected error checking job of version %d: %s", version, err)
+		}
+	}
+	if err := checkJobVersion(&Job{Version: CurrentJobVersion + 1}); err == nil {
+		t.Errorf("expected error checking job of newer version")
+	}
+}
@@ -3,29 +3,49 @@ package queryrunner
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
-	"golang.org/x/time/rate"
-
 	"github.com/cockroachdb/errors"
 	"github.com/graph-gophers/graphql-go"
 	"github.com/inconshreveable/log15"
+	"github.com/keegancsmith/sqlf"
+	"github.com/opentracing/opentracing-go/log"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/compression"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 )
 
 var _ workerutil.Handler = &workHandler{}
+var _ workerutil.WithPreDequeue = &workHandler{}
+var _ workerutil.WithHooks = &workHandler{}
 
 // workHandler implements the dbworker.Handler interface by executing search queries and
 // inserting insights about them to the insights Timescale database.
 type workHandler struct {
 	workerBaseStore *basestore.Store
 	insightsStore   *store.Store
-	limiter         *rate.Limiter
+	commitStore     compression.CommitStore
+	limiter         *searchLimiter
+	operations      *operations
+
+	// listRepoNames lists the names of all repositories, which are split into shards when the
+	// search of a job of all repositories times out (see shard.go).
+	listRepoNames func(ctx context.Context) ([]string, error)
+
+	// handling is the number of jobs being handled (see Concurrency), and costInUse is their total
+	// cost (see CostBudget).
+	handling  int64
+	costInUse int64
+
+	gitFindNearestCommit findNearestCommitFunc
 }
 
 func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err error) {
@@ -40,11 +60,355 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 	if err != nil {
 		return err
 	}
+	if err := checkJobVersion(job); err != nil {
+		return err
+	}
+
+	// Series paused by their circuit breaker are not recorded until a site admin resumes them.
+	paused, err := r.insightsStore.CircuitBrokenSeries(ctx, jobSeriesIDs(job))
+	if err != nil {
+		return errors.Wrap(err, "CircuitBrokenSeries")
+	}
+	if ok, err := skipPausedSeries(job, paused); err != nil || !ok {
+		return err
+	}
+
+	ctx, endObservation := r.operations.handle.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("seriesID", job.SeriesID),
+		log.String("kind", recordingKind(job)),
+		log.Int("batchedSeries", len(job.BatchedSeries)),
+	}})
+	started := time.Now()
+	defer func() {
+		r.operations.observeSeries(job, time.Since(started), err)
+		r.operations.observeCost(job)
+		endObservation(1, observation.Args{})
+	}()
+
+	var (
+		plan       *incrementalPlan
+		searchTime time.Duration
+		sharded    bool
+	)
+	defer func() {
+		if dirtyErr := r.trackDirtyQuery(ctx, job, err); dirtyErr != nil {
+			log15.Error("insights.queryrunner.workHandler: failed to track dirty query", "seriesID", job.SeriesID, "error", dirtyErr)
+		}
+		// The data points of a sharded job are recorded by its shards, so it is no full recording.
+		if runErr := r.recordSeriesRuns(ctx, job, plan == nil && !sharded, searchTime, err); runErr != nil {
+			log15.Error("insights.queryrunner.workHandler: failed to record series run", "seriesID", job.SeriesID, "error", runErr)
+		}
+	}()
 
-	err = r.limiter.Wait(ctx)
+	query, ok, err := pinSearchQuery(ctx, r.workerBaseStore, r.gitFindNearestCommit, job)
 	if err != nil {
 		return err
 	}
+	if !ok {
+		return nil
+	}
+	query = shardSearchQuery(job, query)
+	timeout := jobSearchTimeout(job)
+
+	// Searches that time out are split into shards of their repositories, if possible, rather than
+	// failing or recording incomplete results.
+	shardOnTimeout := func(err error) (bool, error) {
+		if !searchTimedOut(ctx, err) || !shardable(job) {
+			return false, nil
+		}
+		sharded = true
+		return true, r.enqueueShards(ctx, job)
+	}
+
+	if incrementalEligible(job) {
+		var incremental bool
+		plan, incremental, err = planIncrementalRecording(ctx, r.insightsStore, r.commitStore, time.Now(), FullRecomputeInterval(), jobSeriesIDs(job))
+		if err != nil {
+			return err
+		}
+		if incremental {
+			return r.recordIncremental(ctx, job, query, plan, timeout, &searchTime)
+		}
+	}
+
+	if len(job.BatchedSeries) > 0 {
+		var seriesCounts map[string]MatchCounts
+		err := r.limitSearch(ctx, timeout, &searchTime, func(ctx context.Context) (err error) {
+			seriesCounts, err = SearchBatchedMatchCounts(ctx, query, job.BatchedSeries)
+			return err
+		})
+		if ok, err := shardOnTimeout(err); ok {
+			return err
+		}
+		approximate, err := acceptIncompleteResults(job, err)
+		if err != nil {
+			return err
+		}
+
+		return RecordBatchedMatchCounts(ctx, r.workerBaseStore, r.insightsStore, job, seriesCounts, approximate)
+	}
+
+	if groupBy := discovery.GroupByOf(job.SeriesID); groupBy != "" {
+		teams, err := AttributionTeams()
+		if err != nil {
+			return errcode.MakeNonRetryable(err)
+		}
+		var attributionCounts CaptureMatchCounts
+		err = r.limitSearch(ctx, timeout, &searchTime, func(ctx context.Context) (err error) {
+			attributionCounts, err = SearchAttributionMatchCounts(ctx, query, groupBy, teams)
+			return err
+		})
+		if ok, err := shardOnTimeout(err); ok {
+			return err
+		}
+		approximate, err := acceptIncompleteResults(job, err)
+		if err != nil {
+			return err
+		}
+
+		return RecordCaptureMatchCounts(ctx, r.workerBaseStore, r.insightsStore, job, attributionCounts, approximate)
+	}
+
+	if discovery.IsCaptureGroupSeries(job.SeriesID) {
+		var captureCounts CaptureMatchCounts
+		err := r.limitSearch(ctx, timeout, &searchTime, func(ctx context.Context) (err error) {
+			captureCounts, err = SearchCaptureMatchCounts(ctx, query)
+			return err
+		})
+		if ok, err := shardOnTimeout(err); ok {
+			return err
+		}
+		approximate, err := acceptIncompleteResults(job, err)
+		if err != nil {
+			return err
+		}
+
+		return RecordCaptureMatchCounts(ctx, r.workerBaseStore, r.insightsStore, job, captureCounts, approximate)
+	}
+
+	var matchCounts MatchCounts
+	err = r.limitSearch(ctx, timeout, &searchTime, func(ctx context.Context) (err error) {
+		matchCounts, err = SearchMatchCounts(ctx, query)
+		return err
+	})
+	if ok, err := shardOnTimeout(err); ok {
+		return err
+	}
+	approximate, err := acceptIncompleteResults(job, err)
+	if err != nil {
+		return err
+	}
+
+	return RecordMatchCounts(ctx, r.workerBaseStore, r.insightsStore, job, matchCounts, approximate)
+}
+
+// recordIncremental records the given job incrementally according to the given plan: only the
+// changed repositories are searched, and the data points of the other repositories are carried
+// forward. All the points are recorded at the same time, like those of a full search. Searches
+// are limited to the given timeout, and the time spent searching is added to searchTime.
+func (r *workHandler) recordIncremental(ctx context.Context, job *Job, query string, plan *incrementalPlan, timeout time.Duration, searchTime *time.Duration) error {
+	recordTime := time.Now()
+	recordJob := *job
+	recordJob.RecordTime = &recordTime
+
+	if len(plan.searchRepos) > 0 {
+		query = incrementalSearchQuery(query, plan)
+		if len(job.BatchedSeries) > 0 {
+			var seriesCounts map[string]MatchCounts
+			err := r.limitSearch(ctx, timeout, searchTime, func(ctx context.Context) (err error) {
+				seriesCounts, err = SearchBatchedMatchCounts(ctx, query, job.BatchedSeries)
+				return err
+			})
+			approximate, err := acceptIncompleteResults(job, err)
+			if err != nil {
+				return err
+			}
+			if err := RecordBatchedMatchCounts(ctx, r.workerBaseStore, r.insightsStore, &recordJob, seriesCounts, approximate); err != nil {
+				return err
+			}
+		} else {
+			var matchCounts MatchCounts
+			err := r.limitSearch(ctx, timeout, searchTime, func(ctx context.Context) (err error) {
+				matchCounts, err = SearchMatchCounts(ctx, query)
+				return err
+			})
+			approximate, err := acceptIncompleteResults(job, err)
+			if err != nil {
+				return err
+			}
+			if err := RecordMatchCounts(ctx, r.workerBaseStore, r.insightsStore, &recordJob, matchCounts, approximate); err != nil {
+				return err
+			}
+		}
+	}
+	return recordCarriedPoints(ctx, r.insightsStore, plan, recordTime)
+}
+
+// limitSearch runs the given search within the search limits of the worker. The limits only
+// cover the search itself, so that pinning the query or recording its results does not hold up
+// the searches of other jobs. The search is canceled once it takes longer than the given timeout,
+// if positive. The time spent searching, not waiting for the limits, is added to searchTime.
+func (r *workHandler) limitSearch(ctx context.Context, timeout time.Duration, searchTime *time.Duration, search func(ctx context.Context) error) (err error) {
+	_, endObservation := r.operations.search.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	release, err := r.limiter.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	defer func() { *searchTime += time.Since(started) }()
+	return search(ctx)
+}
+
+// acceptIncompleteResults returns whether the results of the search of the given job, which
+// failed with the given error, are recorded as approximate values. Searches whose results are
+// incomplete (see IncompleteResultsError) are retried, as timeouts are often transient, and the
+// incomplete results of the last attempt are recorded as approximate rather than not at all.
+// Other errors are returned as is.
+func acceptIncompleteResults(job *Job, err error) (approximate bool, _ error) {
+	var incomplete *IncompleteResultsError
+	if err == nil || !errors.As(err, &incomplete) {
+		return false, err
+	}
+	if int(job.NumFailures)+1 < workerStoreOptions.MaxNumRetries {
+		return false, err
+	}
+	log15.Warn("insights.queryrunner.workHandler: recording incomplete search results as approximate", "seriesID", job.SeriesID, "error", err)
+	return true, nil
+}
+
+// trackDirtyQuery records the data point of the given job as dirty if the job failed for the last
+// time, so that its query is retried in the background later on. Jobs that record their data
+// point at a fixed time resolve the dirty data point once they succeed, which includes the jobs
+// enqueued for those retries. Jobs of shards only record part of their data point, so they retry
+// the search of their shard and do not resolve the data point.
+func (r *workHandler) trackDirtyQuery(ctx context.Context, job *Job, handleErr error) error {
+	if handleErr == nil {
+		if job.RecordTime == nil || len(job.ShardRepos) > 0 {
+			return nil
+		}
+		return r.insightsStore.ResolveDirtyQuery(ctx, job.SeriesID, *job.RecordTime)
+	}
+
+	if !errcode.IsNonRetryable(handleErr) && int(job.NumFailures)+1 < workerStoreOptions.MaxNumRetries {
+		// The worker retries the job itself.
+		return nil
+	}
+	forTime := time.Now()
+	if job.RecordTime != nil {
+		forTime = *job.RecordTime
+	}
+	if len(job.BatchedSeries) > 0 {
+		// Each batched series retries its own search query.
+		for _, series := range job.BatchedSeries {
+			if err := r.insightsStore.MarkQueryDirty(ctx, store.DirtyQuery{
+				SeriesID: series.SeriesID,
+				Query:    shardSearchQuery(job, series.SearchQuery),
+				ForTime:  forTime,
+				Reason:   handleErr.Error(),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return r.insightsStore.MarkQueryDirty(ctx, store.DirtyQuery{
+		SeriesID:       job.SeriesID,
+		Query:          shardSearchQuery(job, job.SearchQuery),
+		ForTime:        forTime,
+		Reason:         handleErr.Error(),
+		PinnedRepo:     job.PinnedRepo,
+		PinnedRevision: job.PinnedRevision,
+	})
+}
+
+// recordSeriesRuns records the outcome of the given job for each series it records data points of,
+// so that the status of the series can be reported after the job itself is cleaned up. Series
+// batched together share the time of their search, so they exceed their time budget together.
+func (r *workHandler) recordSeriesRuns(ctx context.Context, job *Job, full bool, searchTime time.Duration, handleErr error) error {
+	over := overBudget(searchTime, SeriesTimeBudget())
+	if over {
+		log15.Warn("insights.queryrunner.workHandler: searches exceeded the time budget of a series", "seriesID", job.SeriesID, "searchTime", searchTime)
+	}
+	for _, seriesID := range jobSeriesIDs(job) {
+		if err := r.insightsStore.RecordSeriesRun(ctx, store.SeriesRun{
+			SeriesID:   seriesID,
+			Err:        handleErr,
+			Full:       full && job.RecordTime == nil && job.PinnedRepo == nil,
+			OverBudget: over,
+			BreakAfter: CircuitBreakerThreshold(),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jobSeriesIDs returns the IDs of the series whose data points the given job records.
+func jobSeriesIDs(job *Job) []string {
+	if len(job.BatchedSeries) == 0 {
+		return []string{job.SeriesID}
+	}
+	seriesIDs := make([]string, 0, len(job.BatchedSeries))
+	for _, series := range job.BatchedSeries {
+		seriesIDs = append(seriesIDs, series.SeriesID)
+	}
+	return seriesIDs
+}
+
+// PreDequeue does not dequeue any job while the query runner is paused, or while the worker handles
+// as many jobs as its concurrency. It leaves historical backfill jobs to executors when
+// backfilling on executors is enabled. Executors only count matches of a single series, so jobs of
+// series generated from capture groups or grouped by attribution and batched jobs are never left
+// to them. If the worker has
+// a cost budget, no job is dequeued while the budget is used up, and otherwise jobs are dequeued
+// within the remaining budget (see costBudgetConditions).
+func (r *workHandler) PreDequeue(ctx context.Context) (bool, interface{}, error) {
+	if Paused() || atomic.LoadInt64(&r.handling) >= int64(Concurrency()) {
+		return false, nil, nil
+	}
+
+	var conditions []*sqlf.Query
+	if BackfillOnExecutors() {
+		conditions = append(conditions, sqlf.Sprintf("(insights_query_runner_jobs.record_time IS NULL OR %s OR %s)", splitSeriesCondition, batchedSeriesCondition))
+	}
+
+	budget := CostBudget()
+	if budget <= 0 {
+		return true, conditions, nil
+	}
+	costInUse := atomic.LoadInt64(&r.costInUse)
+	if costInUse >= budget {
+		return false, nil, nil
+	}
+	return true, append(conditions, costBudgetConditions(budget, costInUse, conditions)...), nil
+}
+
+func (r *workHandler) PreHandle(ctx context.Context, record workerutil.Record) {
+	atomic.AddInt64(&r.handling, 1)
+	atomic.AddInt64(&r.costInUse, int64(record.(*Job).Cost))
+}
+
+func (r *workHandler) PostHandle(ctx context.Context, record workerutil.Record) {
+	atomic.AddInt64(&r.handling, -1)
+	atomic.AddInt64(&r.costInUse, -int64(record.(*Job).Cost))
+}
+
+// MatchCounts is the number of search matches in each repository, keyed by GraphQL repository ID.
+type MatchCounts map[string]int
+
+// SearchMatchCounts performs the given search query and counts the matches in each repository of
+// the results.
+func SearchMatchCounts(ctx context.Context, query string) (MatchCounts, error) {
 	// Actually perform the search query.
 	//
 	// 🚨 SECURITY: The request is performed without authentication, we get back results from every
@@ -52,12 +416,58 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 	// is OK to expose to every user on Sourcegraph (e.g. total result counts are fine, exposing
 	// that a repository exists may or may not be fine, exposing individual results is definitely
 	// not, etc.)
-	var results *gqlSearchResponse
-	results, err = search(ctx, job.SearchQuery)
+	results, err := search(ctx, query)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	if err := checkSearchResponse(results, query); err != nil {
+		return nil, err
+	}
+
+	// Figure out how many matches we got for every unique repository returned in the search
+	// results. Incomplete results are counted, but returned with an error.
+	matchCounts := make(MatchCounts, len(results.Data.Search.Results.Results)*4)
+	for _, result := range results.Data.Search.Results.Results {
+		decoded, err := decodeResult(result)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf(`for query "%s"`, query))
+		}
+		matchCounts[decoded.repoID()] = matchCounts[decoded.repoID()] + decoded.matchCount()
+	}
+
+	return matchCounts, incompleteResults(results, query)
+}
 
+// IncompleteResultsError is returned with the match counts of a search whose results are
+// incomplete, because the search hit a limit or timed out in some repositories. The match counts
+// of such a search undercount the matches.
+type IncompleteResultsError struct {
+	Query string
+
+	// LimitHit is true if the search hit a limit, and TimedoutRepos is the number of repositories
+	// the search timed out in.
+	LimitHit      bool
+	TimedoutRepos int
+}
+
+func (e *IncompleteResultsError) Error() string {
+	return fmt.Sprintf("incomplete search results (limit hit: %t, timed out repositories: %d) query=%q", e.LimitHit, e.TimedoutRepos, e.Query)
+}
+
+// incompleteResults returns an *IncompleteResultsError if the results of the given search response
+// are incomplete.
+func incompleteResults(results *gqlSearchResponse, query string) error {
+	limitHit, timedout := results.Data.Search.Results.LimitHit, len(results.Data.Search.Results.Timedout)
+	if !limitHit && timedout == 0 {
+		return nil
+	}
+	return &IncompleteResultsError{Query: query, LimitHit: limitHit, TimedoutRepos: timedout}
+}
+
+// checkSearchResponse returns an error if the given search response does not describe the
+// results of the query, and logs any issues that make the results incomplete. Results that are
+// incomplete because of limits or timeouts are reported by incompleteResults instead.
+func checkSearchResponse(results *gqlSearchResponse, query string) error {
 	// TODO(slimsag): future: Logs are not a good way to surface these errors to users.
 	if len(results.Errors) > 0 {
 		return errors.Errorf("GraphQL errors: %v", results.Errors)
@@ -77,22 +487,53 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 			// general.
 		} else {
 			// Maybe the user's search query is actually wrong.
-			return errors.Errorf("insights query issue: alert: %v query=%q", alert, job.SearchQuery)
+			return errors.Errorf("insights query issue: alert: %v query=%q", alert, query)
 		}
 	}
-	if results.Data.Search.Results.LimitHit {
-		log15.Error("insights query issue", "problem", "limit hit", "query", job.SearchQuery)
-	}
 	if cloning := len(results.Data.Search.Results.Cloning); cloning > 0 {
-		log15.Error("insights query issue", "cloning_repos", cloning, "query", job.SearchQuery)
+		log15.Error("insights query issue", "cloning_repos", cloning, "query", query)
 	}
 	if missing := len(results.Data.Search.Results.Missing); missing > 0 {
-		log15.Error("insights query issue", "missing_repos", missing, "query", job.SearchQuery)
+		log15.Error("insights query issue", "missing_repos", missing, "query", query)
 	}
-	if timedout := len(results.Data.Search.Results.Timedout); timedout > 0 {
-		log15.Error("insights query issue", "timedout_repos", timedout, "query", job.SearchQuery)
+	return nil
+}
+
+// RecordMatchCounts records the given match counts as points of the given job's series, one point
+// per repository. If approximate is true, the match counts are from incomplete search results and
+// the points are marked as approximate.
+func RecordMatchCounts(ctx context.Context, workerBaseStore *basestore.Store, insightsStore *store.Store, job *Job, matchCounts MatchCounts, approximate bool) error {
+	return recordMatchCounts(ctx, workerBaseStore, insightsStore, job, matchCounts, nil, approximate)
+}
+
+// RecordBatchedMatchCounts records the given match counts of each of the batched series of the
+// given job as points of that series, one point per repository.
+func RecordBatchedMatchCounts(ctx context.Context, workerBaseStore *basestore.Store, insightsStore *store.Store, job *Job, seriesCounts map[string]MatchCounts, approximate bool) error {
+	for _, series := range job.BatchedSeries {
+		seriesJob := *job
+		seriesJob.SeriesID = series.SeriesID
+		seriesJob.SearchQuery = series.SearchQuery
+		seriesJob.BatchedSeries = nil
+		if err := recordMatchCounts(ctx, workerBaseStore, insightsStore, &seriesJob, seriesCounts[series.SeriesID], nil, approximate); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+// RecordCaptureMatchCounts records the given match counts as points of the given job's series, one
+// point per captured value and repository.
+func RecordCaptureMatchCounts(ctx context.Context, workerBaseStore *basestore.Store, insightsStore *store.Store, job *Job, captureCounts CaptureMatchCounts, approximate bool) error {
+	for value, matchCounts := range captureCounts {
+		value := value
+		if err := recordMatchCounts(ctx, workerBaseStore, insightsStore, job, matchCounts, &value, approximate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func recordMatchCounts(ctx context.Context, workerBaseStore *basestore.Store, insightsStore *store.Store, job *Job, matchCounts MatchCounts, capture *string, approximate bool) error {
 	// 🚨 SECURITY: The request is performed without authentication, we get back results from every
 	// repository on Sourcegraph - so we must be careful to only record insightful information that
 	// is OK to expose to every user on Sourcegraph (e.g. total result counts are fine, exposing
@@ -103,21 +544,14 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 	if job.RecordTime != nil {
 		recordTime = *job.RecordTime
 	}
-
-	// Figure out how many matches we got for every unique repository returned in the search
-	// results.
-	matchesPerRepo := make(map[string]int, len(results.Data.Search.Results.Results)*4)
-	for _, result := range results.Data.Search.Results.Results {
-		decoded, err := decodeResult(result)
-		if err != nil {
-			return errors.Wrap(err, fmt.Sprintf(`for query "%s"`, job.SearchQuery))
-		}
-		matchesPerRepo[decoded.repoID()] = matchesPerRepo[decoded.repoID()] + decoded.matchCount()
+	var metadata interface{}
+	if job.PinnedRevision != nil {
+		metadata = pinnedRevisionMetadata{Revision: *job.PinnedRevision}
 	}
 
 	// Record the number of results we got, one data point per-repository.
-	repoStore := database.Repos(r.workerBaseStore.Handle().DB())
-	for graphQLRepoID, matchCount := range matchesPerRepo {
+	repoStore := database.Repos(workerBaseStore.Handle().DB())
+	for graphQLRepoID, matchCount := range matchCounts {
 		dbRepoID, err := graphqlbackend.UnmarshalRepositoryID(graphql.ID(graphQLRepoID))
 		if err != nil {
 			return errors.Wrap(err, "UnmarshalRepositoryID")
@@ -128,14 +562,17 @@ func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err
 		}
 
 		repoName := string(repo.Name)
-		err = r.insightsStore.RecordSeriesPoint(ctx, store.RecordSeriesPointArgs{
+		err = insightsStore.RecordSeriesPoint(ctx, store.RecordSeriesPointArgs{
 			SeriesID: job.SeriesID,
 			Point: store.SeriesPoint{
-				Time:  recordTime,
-				Value: float64(matchCount),
+				Time:        recordTime,
+				Value:       float64(matchCount),
+				Capture:     capture,
+				Approximate: approximate,
 			},
 			RepoName: &repoName,
 			RepoID:   &repo.ID,
+			Metadata: metadata,
 		})
 		if err != nil {
 			return errors.Wrap(err, "RecordSeriesPoint")
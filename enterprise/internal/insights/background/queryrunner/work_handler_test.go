@@ -0,0 +1,61 @@
+package queryrunner
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestIncompleteResults(t *testing.T) {
+	var complete gqlSearchResponse
+	if err := incompleteResults(&complete, "errorf"); err != nil {
+		t.Fatalf("unexpected error for complete results: %s", err)
+	}
+
+	var timedout gqlSearchResponse
+	timedout.Data.Search.Results.Timedout = []*api.Repo{{Name: "github.com/a/b"}, {Name: "github.com/c/d"}}
+	var incomplete *IncompleteResultsError
+	if err := incompleteResults(&timedout, "errorf"); !errors.As(err, &incomplete) {
+		t.Fatalf("unexpected error. want=%T have=%v", incomplete, err)
+	}
+	if incomplete.LimitHit || incomplete.TimedoutRepos != 2 {
+		t.Errorf("unexpected incomplete results. want limit not hit and 2 timed out repositories have=%+v", incomplete)
+	}
+
+	var limitHit gqlSearchResponse
+	limitHit.Data.Search.Results.LimitHit = true
+	if err := incompleteResults(&limitHit, "errorf"); !errors.As(err, &incomplete) || !incomplete.LimitHit {
+		t.Fatalf("unexpected error. want limit hit have=%v", err)
+	}
+}
+
+func TestAcceptIncompleteResults(t *testing.T) {
+	incomplete := errors.Wrap(&IncompleteResultsError{Query: "errorf", LimitHit: true}, "search")
+	otherErr := errors.New("search failed")
+	lastAttempt := int32(workerStoreOptions.MaxNumRetries - 1)
+
+	for _, testCase := range []struct {
+		name            string
+		numFailures     int32
+		err             error
+		wantApproximate bool
+		wantErr         error
+	}{
+		{"complete", 0, nil, false, nil},
+		{"other error", lastAttempt, otherErr, false, otherErr},
+		{"incomplete with retries left", 0, incomplete, false, incomplete},
+		{"incomplete on last attempt", lastAttempt, incomplete, true, nil},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			approximate, err := acceptIncompleteResults(&Job{NumFailures: testCase.numFailures}, testCase.err)
+			if err != testCase.wantErr {
+				t.Errorf("unexpected error. want=%v have=%v", testCase.wantErr, err)
+			}
+			if approximate != testCase.wantApproximate {
+				t.Errorf("unexpected approximate. want=%t have=%t", testCase.wantApproximate, approximate)
+			}
+		})
+	}
+}
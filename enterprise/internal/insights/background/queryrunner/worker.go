@@ -3,6 +3,7 @@ package queryrunner
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -16,8 +17,14 @@ import (
 	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
 
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/compression"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
@@ -33,17 +40,22 @@ import (
 
 // NewWorker returns a worker that will execute search queries and insert information about the
 // results into the code insights database.
-func NewWorker(ctx context.Context, workerBaseStore *basestore.Store, insightsStore *store.Store, metrics workerutil.WorkerMetrics) *workerutil.Worker {
-	workerStore := createDBWorkerStore(workerBaseStore)
-
-	numHandlers := conf.Get().InsightsQueryWorkerConcurrency
-	if numHandlers <= 0 {
-		numHandlers = 1
-	}
-
-	options := workerutil.WorkerOptions{
+//
+// Jobs are dequeued in order of priority, aged by the time they have spent queued, and within the
+// cost budget of the worker (see schedule.go).
+//
+// The duration and the errors of the jobs recording each series, and traces of the jobs, are
+// observed with the given observation context.
+func NewWorker(ctx context.Context, workerBaseStore *basestore.Store, insightsStore *store.Store, metrics workerutil.WorkerMetrics, observationContext *observation.Context) *workerutil.Worker {
+	options := workerStoreOptions
+	options.OrderByExpression = sqlf.Sprintf(agedPriorityOrderExpression, workerPriorityAgingInterval.Seconds())
+	workerStore := dbworkerstore.New(workerBaseStore.Handle(), options)
+
+	// The number of jobs handled at once is limited by the handler (see PreDequeue), so that it can
+	// be changed without restarting the worker.
+	workerOptions := workerutil.WorkerOptions{
 		Name:              "insights_query_runner_worker",
-		NumHandlers:       numHandlers,
+		NumHandlers:       maxWorkerConcurrency,
 		Interval:          5 * time.Second,
 		HeartbeatInterval: 15 * time.Second,
 		Metrics:           metrics,
@@ -52,19 +64,31 @@ func NewWorker(ctx context.Context, workerBaseStore *basestore.Store, insightsSt
 	defaultRateLimit := rate.Limit(2.0)
 	getRateLimit := getRateLimit(defaultRateLimit)
 
-	limiter := rate.NewLimiter(getRateLimit(), 1)
+	limiter := newSearchLimiter(getRateLimit(), SearchConcurrency())
 
+	paused := Paused()
 	go conf.Watch(func() {
-		val := getRateLimit()
-		log15.Info(fmt.Sprintf("Updating insights/query-worker rate limit value=%v", val))
-		limiter.SetLimit(val)
+		val, concurrency := getRateLimit(), SearchConcurrency()
+		log15.Info(fmt.Sprintf("Updating insights/query-worker limits rateLimit=%v concurrency=%v searchConcurrency=%v", val, Concurrency(), concurrency))
+		limiter.SetLimits(val, concurrency)
+
+		// Pausing takes effect on the next dequeue (see PreDequeue), so this only reports it.
+		if p := Paused(); p != paused {
+			paused = p
+			log15.Warn(fmt.Sprintf("Updating insights/query-worker paused=%v", paused))
+		}
 	})
 
 	return dbworker.NewWorker(ctx, workerStore, &workHandler{
 		workerBaseStore: workerBaseStore,
 		insightsStore:   insightsStore,
+		commitStore:     compression.NewCommitStore(insightsStore.Handle().DB()),
 		limiter:         limiter,
-	}, options)
+		operations:      newOperations(observationContext),
+		listRepoNames:   database.Repos(workerBaseStore.Handle().DB()).ListEnabledNames,
+
+		gitFindNearestCommit: git.FindNearestCommit,
+	}, workerOptions)
 }
 
 func getRateLimit(defaultValue rate.Limit) func() rate.Limit {
@@ -94,30 +118,93 @@ func NewResetter(ctx context.Context, workerBaseStore *basestore.Store, metrics
 	return dbworker.NewResetter(workerStore, options)
 }
 
+var workerStoreOptions = dbworkerstore.Options{
+	Name:              "insights_query_runner_jobs_store",
+	TableName:         "insights_query_runner_jobs",
+	ColumnExpressions: jobsColumns,
+	Scan:              scanJobs,
+
+	// We will let a search query run for up to 60s. After that, it times out and
+	// retries in 10s. If 3 timeouts occur, it is not retried.
+	//
+	// If you change this, be sure to adjust the interval that work is enqueued in
+	// enterprise/internal/insights/background:newInsightEnqueuer.
+	StalledMaxAge:     60 * time.Second,
+	RetryAfter:        10 * time.Second,
+	MaxNumRetries:     3,
+	OrderByExpression: sqlf.Sprintf("priority, id"),
+}
+
 // createDBWorkerStore creates the dbworker store for the query runner worker.
 //
 // See internal/workerutil/dbworker for more information about dbworkers.
 func createDBWorkerStore(s *basestore.Store) dbworkerstore.Store {
-	return dbworkerstore.New(s.Handle(), dbworkerstore.Options{
-		Name:              "insights_query_runner_jobs_store",
-		TableName:         "insights_query_runner_jobs",
-		ColumnExpressions: jobsColumns,
-		Scan:              scanJobs,
-
-		// We will let a search query or webhook run for up to 60s. After that, it times out and
-		// retries in 10s. If 3 timeouts occur, it is not retried.
-		//
-		// If you change this, be sure to adjust the interval that work is enqueued in
-		// enterprise/internal/insights/background:newInsightEnqueuer.
-		StalledMaxAge:     60 * time.Second,
-		RetryAfter:        10 * time.Second,
-		MaxNumRetries:     3,
-		OrderByExpression: sqlf.Sprintf("priority, id"),
-	})
+	return dbworkerstore.New(s.Handle(), workerStoreOptions)
 }
 
-// EnqueueJob enqueues a job for the query runner worker to execute later.
+// EnqueueJob enqueues a job for the query runner worker to execute later. If the number of queued
+// jobs has reached the insights.query.worker.maxQueueDepth site configuration limit, the job is not
+// enqueued and dbworkerstore.ErrQueueFull is returned. If the job has an idempotency key that was
+// used within IdempotencyWindow, the ID of the previously enqueued job is returned instead.
 func EnqueueJob(ctx context.Context, workerBaseStore *basestore.Store, job *Job) (id int, err error) {
+	if maxQueueDepth := conf.Get().InsightsQueryWorkerMaxQueueDepth; maxQueueDepth > 0 {
+		queued, err := createDBWorkerStore(workerBaseStore).QueuedCount(ctx, nil)
+		if err != nil {
+			return 0, errors.Wrap(err, "QueuedCount")
+		}
+		if queued >= maxQueueDepth {
+			return 0, dbworkerstore.ErrQueueFull
+		}
+	}
+
+	if job.IdempotencyKey == "" {
+		return insertJob(ctx, workerBaseStore, job)
+	}
+
+	id, _, err = dbworkerstore.EnqueueOnce(ctx, workerBaseStore, "insights_query_runner_jobs", job.IdempotencyKey, IdempotencyWindow, func(tx *basestore.Store) (int, error) {
+		return insertJob(ctx, tx, job)
+	})
+	return id, err
+}
+
+// WithCountUnlimited adds `count:all` to the given search query string iff the query has no
+// `count:` filter. This is extremely important as otherwise the number of results our search
+// query would return would be incomplete and fluctuate.
+//
+// The query is parsed to find its filters, so that e.g. `content:"count:"` is not mistaken for a
+// filter. Queries that cannot be parsed are searched for with `count:all` as well, and fail with
+// the parse error.
+func WithCountUnlimited(s string) string {
+	plan, err := discovery.ParseSearchQuery(s, query.SearchTypeLiteral)
+	if err == nil {
+		for _, basic := range plan {
+			if basic.GetCount() != "" {
+				return s
+			}
+		}
+	}
+	return s + " count:all"
+}
+
+// IdempotencyWindow is the period during which a job enqueued with an idempotency key prevents
+// jobs with the same key from being enqueued again.
+const IdempotencyWindow = 6 * time.Hour
+
+func insertJob(ctx context.Context, workerBaseStore *basestore.Store, job *Job) (id int, err error) {
+	var searchTimeoutSeconds *int
+	if job.SearchTimeout > 0 {
+		seconds := int(job.SearchTimeout / time.Second)
+		searchTimeoutSeconds = &seconds
+	}
+	var batchedSeries interface{}
+	if len(job.BatchedSeries) > 0 {
+		encoded, err := json.Marshal(job.BatchedSeries)
+		if err != nil {
+			return 0, errors.Wrap(err, "encoding batched series")
+		}
+		batchedSeries = encoded
+	}
+
 	id, _, err = basestore.ScanFirstInt(workerBaseStore.Query(
 		ctx,
 		sqlf.Sprintf(
@@ -129,6 +216,12 @@ func EnqueueJob(ctx context.Context, workerBaseStore *basestore.Store, job *Job)
 			job.ProcessAfter,
 			job.Cost,
 			job.Priority,
+			job.PinnedRepo,
+			job.PinnedRevision,
+			batchedSeries,
+			CurrentJobVersion,
+			searchTimeoutSeconds,
+			pq.Array(job.ShardRepos),
 		),
 	))
 	return
@@ -143,8 +236,14 @@ INSERT INTO insights_query_runner_jobs (
 	state,
 	process_after,
 	cost,
-	priority
-) VALUES (%s, %s, %s, %s, %s, %s, %s)
+	priority,
+	pinned_repo,
+	pinned_revision,
+	batched_series,
+	payload_version,
+	search_timeout_seconds,
+	shard_repos
+) VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)
 RETURNING id
 `
 
@@ -171,6 +270,12 @@ SELECT
 	record_time,
 	cost,
 	priority,
+	pinned_repo,
+	pinned_revision,
+	batched_series,
+	payload_version,
+	search_timeout_seconds,
+	shard_repos,
 	id,
 	state,
 	failure_message,
@@ -188,10 +293,21 @@ type JobsStatus struct {
 	Queued, Processing uint64
 	Completed          uint64
 	Errored, Failed    uint64
+
+	// PendingBackfill is the number of queued, processing and errored jobs that record data points
+	// in the past, i.e. jobs backfilling the historical data of the series or retrying to.
+	PendingBackfill uint64
 }
 
-// QueryJobsStatus queries the current status of jobs for the specified series.
+// QueryJobsStatus queries the current status of jobs for the specified series, including the jobs
+// of batched searches for the series.
 func QueryJobsStatus(ctx context.Context, workerBaseStore *basestore.Store, seriesID string) (*JobsStatus, error) {
+	// Jobs of batched searches count for each of their batched series.
+	batchedSeriesFilter, err := json.Marshal([]map[string]string{{"seriesId": seriesID}})
+	if err != nil {
+		return nil, err
+	}
+
 	var status JobsStatus
 	for _, work := range []struct {
 		stateName string
@@ -205,19 +321,62 @@ func QueryJobsStatus(ctx context.Context, workerBaseStore *basestore.Store, seri
 	} {
 		value, _, err := basestore.ScanFirstInt(workerBaseStore.Query(
 			ctx,
-			sqlf.Sprintf(queryJobsStatusFmtStr, seriesID, work.stateName)),
+			sqlf.Sprintf(queryJobsStatusFmtStr, seriesID, batchedSeriesFilter, work.stateName)),
 		)
 		if err != nil {
 			return nil, err
 		}
 		*work.result = uint64(value)
 	}
+
+	pendingBackfill, _, err := basestore.ScanFirstInt(workerBaseStore.Query(
+		ctx,
+		sqlf.Sprintf(queryPendingBackfillJobsFmtStr, seriesID, batchedSeriesFilter),
+	))
+	if err != nil {
+		return nil, err
+	}
+	status.PendingBackfill = uint64(pendingBackfill)
 	return &status, nil
 }
 
 const queryJobsStatusFmtStr = `
 -- source: enterprise/internal/insights/background/queryrunner/worker.go:JobsStatus
-SELECT COUNT(*) FROM insights_query_runner_jobs WHERE series_id=%s AND state=%s
+SELECT COUNT(*) FROM insights_query_runner_jobs WHERE (series_id=%s OR batched_series @> %s) AND state=%s
+`
+
+const queryPendingBackfillJobsFmtStr = `
+-- source: enterprise/internal/insights/background/queryrunner/worker.go:JobsStatus
+SELECT COUNT(*) FROM insights_query_runner_jobs
+WHERE (series_id=%s OR batched_series @> %s) AND record_time IS NOT NULL AND state IN ('queued', 'processing', 'errored')
+`
+
+// DeleteQueuedJobs deletes the jobs of the given series that are waiting to be executed, i.e. that
+// are queued or will be retried, and returns the number of deleted jobs. Jobs of batched searches
+// are only deleted if all of their batched series are given.
+func DeleteQueuedJobs(ctx context.Context, workerBaseStore *basestore.Store, seriesIDs []string) (int, error) {
+	if len(seriesIDs) == 0 {
+		return 0, nil
+	}
+	count, _, err := basestore.ScanFirstInt(workerBaseStore.Query(ctx, sqlf.Sprintf(deleteQueuedJobsFmtStr, pq.Array(seriesIDs), pq.Array(seriesIDs))))
+	return count, err
+}
+
+const deleteQueuedJobsFmtStr = `
+-- source: enterprise/internal/insights/background/queryrunner/worker.go:DeleteQueuedJobs
+WITH deleted AS (
+	DELETE FROM insights_query_runner_jobs
+	WHERE (state = 'queued' OR state = 'errored') AND (
+		series_id = ANY(%s) OR (
+			jsonb_array_length(COALESCE(batched_series, '[]'::jsonb)) > 0 AND
+			NOT EXISTS (
+				SELECT 1 FROM jsonb_array_elements(batched_series) AS batched
+				WHERE NOT (batched->>'seriesId' = ANY(%s))
+			)
+		)
+	)
+	RETURNING 1
+) SELECT count(*) FROM deleted
 `
 
 // Job represents a single job for the query runner worker to perform. When enqueued, it is stored
@@ -233,6 +392,38 @@ type Job struct {
 	Cost        int
 	Priority    int
 
+	// PinnedRepo, if non-nil, is the name of the repository the search query is restricted to at
+	// the revision the repository had at RecordTime. Unless PinnedRevision is set when enqueuing,
+	// the revision is resolved when the job is first executed and stored in PinnedRevision, so that
+	// retries search the same revision.
+	PinnedRepo     *string
+	PinnedRevision *string
+
+	// BatchedSeries, if non-empty, are the series whose search queries are batched into the
+	// search query of the job (see BatchSearchQuery). The matches of the search are recorded for
+	// these series, and the job's SeriesID (see BatchSeriesID) records no data of its own. Only
+	// current data points are batched, so batched jobs have no pinned revision, and no RecordTime
+	// unless they are the jobs of shards of a batched job.
+	BatchedSeries []BatchedSeries
+
+	// SearchTimeout, if positive, is the time a search of the job may take before the job is split
+	// into jobs of shards of its repositories (see shard.go), overriding SearchTimeout().
+	SearchTimeout time.Duration
+
+	// ShardRepos, if non-empty, are the names of the repositories the search query of the job is
+	// restricted to. Jobs of the shards of a job that timed out record their data points at the
+	// same time, so that they add up to the data point of that job.
+	ShardRepos []string
+
+	// Version is the version of the payload of the job (see version.go). Jobs are always enqueued
+	// with the current version, and jobs of older versions are upgraded when they are dequeued.
+	Version int
+
+	// IdempotencyKey, if set, makes EnqueueJob a no-op returning the existing job ID when a job
+	// was already enqueued with the same key within IdempotencyWindow. It is not persisted on the
+	// job itself.
+	IdempotencyKey string
+
 	// Standard/required dbworker fields. If enqueuing a job, these may all be zero values except State.
 	//
 	// See https://sourcegraph.com/github.com/sourcegraph/sourcegraph@cd0b3904c674ee3568eb2ef5d7953395b6432d20/-/blob/internal/workerutil/dbworker/store/store.go#L114-134
@@ -269,6 +460,8 @@ func doScanJobs(rows *sql.Rows, err error) ([]*Job, error) {
 	var jobs []*Job
 	for rows.Next() {
 		j := &Job{}
+		var batchedSeries []byte
+		var searchTimeoutSeconds sql.NullInt32
 		if err := rows.Scan(
 			// Query runner fields.
 			&j.SeriesID,
@@ -276,6 +469,12 @@ func doScanJobs(rows *sql.Rows, err error) ([]*Job, error) {
 			&j.RecordTime,
 			&j.Cost,
 			&j.Priority,
+			&j.PinnedRepo,
+			&j.PinnedRevision,
+			&batchedSeries,
+			&j.Version,
+			&searchTimeoutSeconds,
+			pq.Array(&j.ShardRepos),
 
 			// Standard/required dbworker fields.
 			&j.ID,
@@ -290,6 +489,15 @@ func doScanJobs(rows *sql.Rows, err error) ([]*Job, error) {
 		); err != nil {
 			return nil, err
 		}
+		if batchedSeries != nil {
+			if err := json.Unmarshal(batchedSeries, &j.BatchedSeries); err != nil {
+				return nil, errors.Wrap(err, "decoding batched series")
+			}
+		}
+		if searchTimeoutSeconds.Valid {
+			j.SearchTimeout = time.Duration(searchTimeoutSeconds.Int32) * time.Second
+		}
+		upgradeJob(j)
 		jobs = append(jobs, j)
 	}
 	if err != nil {
@@ -308,6 +516,12 @@ var jobsColumns = []*sqlf.Query{
 	sqlf.Sprintf("insights_query_runner_jobs.record_time"),
 	sqlf.Sprintf("insights_query_runner_jobs.cost"),
 	sqlf.Sprintf("insights_query_runner_jobs.priority"),
+	sqlf.Sprintf("insights_query_runner_jobs.pinned_repo"),
+	sqlf.Sprintf("insights_query_runner_jobs.pinned_revision"),
+	sqlf.Sprintf("insights_query_runner_jobs.batched_series"),
+	sqlf.Sprintf("insights_query_runner_jobs.payload_version"),
+	sqlf.Sprintf("insights_query_runner_jobs.search_timeout_seconds"),
+	sqlf.Sprintf("insights_query_runner_jobs.shard_repos"),
 	sqlf.Sprintf("id"),
 	sqlf.Sprintf("state"),
 	sqlf.Sprintf("failure_message"),
@@ -56,13 +56,97 @@ func TestJobQueue(t *testing.T) {
 	firstJob, err := dequeueJob(ctx, workerBaseStore, firstJobID)
 	autogold.Want("2", &Job{
 		SeriesID: "job 1", SearchQuery: "our search 1",
-		ID: 1,
+		Version: 3, ID: 1,
 	}).Equal(t, firstJob)
 	autogold.Want("3", "<nil>").Equal(t, fmt.Sprint(err))
 	secondJob, err := dequeueJob(ctx, workerBaseStore, secondJobID)
 	autogold.Want("4", &Job{
 		SeriesID: "job 2", SearchQuery: "our search 2",
-		ID: 2,
+		Version: 3, ID: 2,
 	}).Equal(t, secondJob)
 	autogold.Want("5", "<nil>").Equal(t, fmt.Sprint(err))
 }
+
+func TestDequeueLegacyJob(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	workerBaseStore := basestore.NewWithDB(dbtesting.GetDB(t), sql.TxOptions{})
+
+	// Jobs enqueued by workers of a release without payload versions.
+	var id int
+	if err := workerBaseStore.Handle().DB().QueryRowContext(ctx, `
+		INSERT INTO insights_query_runner_jobs (series_id, search_query, record_time, state)
+		VALUES ('s:legacy', 'errorf count:all repo:^github\.com/sourcegraph/sourcegraph$@0123456789abcdef0123456789abcdef01234567', '2021-06-01', 'queued')
+		RETURNING id
+	`).Scan(&id); err != nil {
+		t.Fatalf("unexpected error inserting legacy job: %s", err)
+	}
+
+	job, err := dequeueJob(ctx, workerBaseStore, id)
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing legacy job: %s", err)
+	}
+	if job.Version != CurrentJobVersion {
+		t.Errorf("unexpected job version. want=%d have=%d", CurrentJobVersion, job.Version)
+	}
+	if job.PinnedRepo == nil || *job.PinnedRepo != "github.com/sourcegraph/sourcegraph" {
+		t.Errorf("unexpected pinned repository: %v", job.PinnedRepo)
+	}
+	if want := "errorf count:all"; job.SearchQuery != want {
+		t.Errorf("unexpected search query. want=%q have=%q", want, job.SearchQuery)
+	}
+}
+
+func TestDeleteQueuedJobs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	workerBaseStore := basestore.NewWithDB(dbtesting.GetDB(t), sql.TxOptions{})
+
+	jobs := []*Job{
+		{SeriesID: "orphaned", SearchQuery: "a", State: "queued"},
+		{SeriesID: "orphaned", SearchQuery: "a", State: "completed"},
+		{SeriesID: "kept", SearchQuery: "b", State: "queued"},
+		{SeriesID: "batch-1", SearchQuery: "a or c", State: "queued", BatchedSeries: []BatchedSeries{{SeriesID: "orphaned", SearchQuery: "a"}, {SeriesID: "orphaned-2", SearchQuery: "c"}}},
+		{SeriesID: "batch-2", SearchQuery: "a or b", State: "queued", BatchedSeries: []BatchedSeries{{SeriesID: "orphaned", SearchQuery: "a"}, {SeriesID: "kept", SearchQuery: "b"}}},
+	}
+	for _, job := range jobs {
+		if _, err := EnqueueJob(ctx, workerBaseStore, job); err != nil {
+			t.Fatalf("unexpected error enqueueing job: %s", err)
+		}
+	}
+
+	// Completed jobs and batches of series that are still recorded are kept.
+	count, err := DeleteQueuedJobs(ctx, workerBaseStore, []string{"orphaned", "orphaned-2"})
+	if err != nil {
+		t.Fatalf("unexpected error deleting jobs: %s", err)
+	}
+	if count != 2 {
+		t.Errorf("unexpected number of deleted jobs. want=%d have=%d", 2, count)
+	}
+}
+
+func TestWithCountUnlimited(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{`errorf`, `errorf count:all`},
+		{`errorf count:100`, `errorf count:100`},
+		{`errorf COUNT:all`, `errorf COUNT:all`},
+		{`content:"count:" lang:go`, `content:"count:" lang:go count:all`},
+		{`repo:count:foo errorf`, `repo:count:foo errorf count:all`},
+		{`(a count:10) or b`, `(a count:10) or b`},
+	}
+
+	for _, testCase := range testCases {
+		if have := WithCountUnlimited(testCase.query); have != testCase.expected {
+			t.Errorf("unexpected query for %q. want=%q have=%q", testCase.query, testCase.expected, have)
+		}
+	}
+}
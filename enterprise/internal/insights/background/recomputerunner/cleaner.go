@@ -0,0 +1,57 @@
+package recomputerunner
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// recomputeRetention is the time for which the outcome of recomputations remains available after
+// they were processed, which is long enough to follow the backfill of a large series.
+const recomputeRetention = 7 * 24 * time.Hour
+
+// NewCleaner returns a background goroutine which will periodically find jobs left in the
+// "completed" or "failed" state that finished over recomputeRetention ago and removes them.
+func NewCleaner(ctx context.Context, workerBaseStore *basestore.Store, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_recompute_runner_cleaner",
+		metrics.WithCountHelp("Total number of insights recomputerunner cleaner executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "RecomputeRunner.Cleaner.Run",
+		Metrics: metrics,
+	})
+
+	// We look for jobs to cleanup every hour.
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 1*time.Hour, goroutine.NewHandlerWithErrorMessage(
+		"insights_recompute_runner_cleaner",
+		func(ctx context.Context) error {
+			_, err := cleanJobs(ctx, workerBaseStore)
+			return err
+		},
+	), operation)
+}
+
+// cleanJobs removes completed and failed jobs that finished over recomputeRetention ago, and
+// returns the number of removed jobs.
+func cleanJobs(ctx context.Context, workerBaseStore *basestore.Store) (numCleaned int, err error) {
+	numCleaned, _, err = basestore.ScanFirstInt(workerBaseStore.Query(
+		ctx,
+		sqlf.Sprintf(cleanJobsFmtStr, time.Now().Add(-recomputeRetention)),
+	))
+	return
+}
+
+const cleanJobsFmtStr = `
+-- source: enterprise/internal/insights/background/recomputerunner/cleaner.go:cleanJobs
+WITH deleted AS (
+	DELETE FROM insights_recompute_jobs WHERE (state='completed' OR state='failed') AND finished_at < %s RETURNING *
+) SELECT count(*) FROM deleted
+`
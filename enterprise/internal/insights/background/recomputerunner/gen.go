@@ -0,0 +1,3 @@
+package recomputerunner
+
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/recomputerunner -i RecomputeStore -o mock_recompute_store.go
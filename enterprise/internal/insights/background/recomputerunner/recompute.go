@@ -0,0 +1,66 @@
+package recomputerunner
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// RecomputeStore is a subset of the API exposed by the store.Store (only the subset used by the
+// recompute runner.)
+type RecomputeStore interface {
+	ResetSeriesData(ctx context.Context, seriesID string) (int, error)
+}
+
+// RecomputableSeries returns the series with the given ID, if its data can be recomputed. The data
+// of webhook series cannot be recomputed, as webhooks only report the current value of a series.
+//
+// 🚨 SECURITY: Series of all namespaces are returned, so only site admins may recompute series.
+func RecomputableSeries(ctx context.Context, insightStore discovery.InsightStore, settingStore discovery.SettingStore, loader insights.Loader, seriesID string) (insights.TimeSeries, error) {
+	discovered, err := discovery.Discover(ctx, insightStore, settingStore, loader, discovery.InsightFilterArgs{SeriesIDs: []string{seriesID}})
+	if err != nil {
+		return insights.TimeSeries{}, errors.Wrap(err, "Discover")
+	}
+	for _, insight := range discovered {
+		for _, series := range insight.Series {
+			if discovery.Encode(series) != seriesID {
+				continue
+			}
+			if series.Webhook != "" {
+				return insights.TimeSeries{}, errors.Errorf("insight series %q is fetched from a webhook, which only reports its current value, so its data cannot be recomputed", seriesID)
+			}
+			if err := discovery.ValidateSeries(series); err != nil {
+				return insights.TimeSeries{}, err
+			}
+			return series, nil
+		}
+	}
+	return insights.TimeSeries{}, errors.Errorf("insight series %q not found", seriesID)
+}
+
+// recompute deletes the recorded data of the given series and schedules its recomputation, and
+// returns the number of deleted data points.
+//
+// The pending jobs of the series are canceled first, so that they do not record data points
+// computed from an outdated definition of the series. The backfiller then enqueues the historical
+// data of the series again, and the current data point is enqueued right away. Derived series are
+// recomputed by the derived series recorder from the series they are derived from.
+func (r *workHandler) recompute(ctx context.Context, seriesID string, series insights.TimeSeries) (int, error) {
+	if _, err := r.deleteQueuedJobs(ctx, []string{seriesID}); err != nil {
+		return 0, errors.Wrap(err, "DeleteQueuedJobs")
+	}
+	deleted, err := r.recomputeStore.ResetSeriesData(ctx, seriesID)
+	if err != nil {
+		return 0, errors.Wrap(err, "ResetSeriesData")
+	}
+	if series.Expression != "" {
+		return deleted, nil
+	}
+	if err := r.enqueueCurrent(ctx, seriesID, series); err != nil {
+		return 0, errors.Wrap(err, "EnqueueJob")
+	}
+	return deleted, nil
+}
@@ -0,0 +1,115 @@
+package recomputerunner
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestRecomputableSeries(t *testing.T) {
+	ctx := context.Background()
+	search := insights.TimeSeries{Name: "errors", Query: "errorf", Interval: insights.Daily}
+	webhook := insights.TimeSeries{Name: "deploys", Webhook: "https://example.com/deploys", Interval: insights.Daily}
+	loader := insights.NewMockLoader()
+	loader.LoadAllFunc.SetDefaultReturn([]insights.SearchInsight{{
+		ID:     "insight",
+		Series: []insights.TimeSeries{search, webhook},
+	}}, nil)
+
+	have, err := RecomputableSeries(ctx, discovery.NewMockInsightStore(), discovery.NewMockSettingStore(), loader, discovery.Encode(search))
+	if err != nil {
+		t.Fatalf("unexpected error finding series: %s", err)
+	}
+	if diff := cmp.Diff(search.Query, have.Query); diff != "" {
+		t.Errorf("unexpected series query (-want +got):\n%s", diff)
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		seriesID string
+		want     string
+	}{
+		{name: "webhook", seriesID: discovery.Encode(webhook), want: "cannot be recomputed"},
+		{name: "unknown", seriesID: "s:unknown", want: "not found"},
+	} {
+		_, err := RecomputableSeries(ctx, discovery.NewMockInsightStore(), discovery.NewMockSettingStore(), loader, testCase.seriesID)
+		if err == nil || !strings.Contains(err.Error(), testCase.want) {
+			t.Errorf("unexpected error finding %s series. want=%q have=%v", testCase.name, testCase.want, err)
+		}
+	}
+}
+
+func TestRecompute(t *testing.T) {
+	ctx := context.Background()
+	search := insights.TimeSeries{Query: "errorf"}
+	derived := insights.TimeSeries{Expression: "$1 * 2"}
+
+	for _, testCase := range []struct {
+		name        string
+		series      insights.TimeSeries
+		wantEnqueue bool
+	}{
+		{name: "search", series: search, wantEnqueue: true},
+		// Derived series are recomputed from the series they are derived from.
+		{name: "derived", series: derived, wantEnqueue: false},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			var calls []string
+			recomputeStore := NewMockRecomputeStore()
+			recomputeStore.ResetSeriesDataFunc.SetDefaultHook(func(ctx context.Context, seriesID string) (int, error) {
+				calls = append(calls, "reset "+seriesID)
+				return 42, nil
+			})
+			handler := &workHandler{
+				recomputeStore: recomputeStore,
+				deleteQueuedJobs: func(ctx context.Context, seriesIDs []string) (int, error) {
+					calls = append(calls, "cancel "+strings.Join(seriesIDs, ","))
+					return 1, nil
+				},
+				enqueueCurrent: func(ctx context.Context, seriesID string, series insights.TimeSeries) error {
+					calls = append(calls, "enqueue "+seriesID)
+					return nil
+				},
+			}
+
+			deleted, err := handler.recompute(ctx, "s:series", testCase.series)
+			if err != nil {
+				t.Fatalf("unexpected error recomputing series: %s", err)
+			}
+			if deleted != 42 {
+				t.Errorf("unexpected number of deleted data points. want=%d have=%d", 42, deleted)
+			}
+			// Pending jobs are canceled before the data is deleted, so that they do not record
+			// outdated data points afterwards.
+			want := []string{"cancel s:series", "reset s:series"}
+			if testCase.wantEnqueue {
+				want = append(want, "enqueue s:series")
+			}
+			if diff := cmp.Diff(want, calls); diff != "" {
+				t.Errorf("unexpected calls (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRecomputeError(t *testing.T) {
+	recomputeStore := NewMockRecomputeStore()
+	recomputeStore.ResetSeriesDataFunc.SetDefaultReturn(0, errors.New("database unavailable"))
+	handler := &workHandler{
+		recomputeStore:   recomputeStore,
+		deleteQueuedJobs: func(ctx context.Context, seriesIDs []string) (int, error) { return 0, nil },
+		enqueueCurrent: func(ctx context.Context, seriesID string, series insights.TimeSeries) error {
+			t.Errorf("unexpected enqueue of series whose data could not be deleted")
+			return nil
+		},
+	}
+	if _, err := handler.recompute(context.Background(), "s:series", insights.TimeSeries{Query: "errorf"}); err == nil {
+		t.Errorf("expected error recomputing series")
+	}
+}
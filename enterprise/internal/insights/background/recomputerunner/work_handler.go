@@ -0,0 +1,85 @@
+package recomputerunner
+
+import (
+	"context"
+
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/insights/priority"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+var _ workerutil.Handler = &workHandler{}
+
+// workHandler implements the dbworker.Handler interface by deleting the recorded data of the
+// series of jobs and scheduling its recomputation.
+type workHandler struct {
+	workerBaseStore *basestore.Store
+	insightStore    discovery.InsightStore
+	settingStore    discovery.SettingStore
+	loader          insights.Loader
+	recomputeStore  RecomputeStore
+
+	deleteQueuedJobs func(ctx context.Context, seriesIDs []string) (int, error)
+	enqueueCurrent   func(ctx context.Context, seriesID string, series insights.TimeSeries) error
+}
+
+func newWorkHandler(workerBaseStore *basestore.Store, insightStore discovery.InsightStore, settingStore discovery.SettingStore, recomputeStore RecomputeStore) *workHandler {
+	return &workHandler{
+		workerBaseStore: workerBaseStore,
+		insightStore:    insightStore,
+		settingStore:    settingStore,
+		loader:          insights.NewLoader(workerBaseStore.Handle().DB()),
+		recomputeStore:  recomputeStore,
+		deleteQueuedJobs: func(ctx context.Context, seriesIDs []string) (int, error) {
+			return queryrunner.DeleteQueuedJobs(ctx, workerBaseStore, seriesIDs)
+		},
+		enqueueCurrent: func(ctx context.Context, seriesID string, series insights.TimeSeries) error {
+			cost, err := discovery.NewCostEstimator(database.Repos(workerBaseStore.Handle().DB())).SeriesCost(ctx, series)
+			if err != nil {
+				return err
+			}
+			_, err = queryrunner.EnqueueJob(ctx, workerBaseStore, &queryrunner.Job{
+				SeriesID:    seriesID,
+				SearchQuery: queryrunner.WithCountUnlimited(discovery.ScopedQuery(series)),
+				State:       "queued",
+				Priority:    int(priority.Critical),
+				Cost:        int(cost),
+			})
+			return err
+		},
+	}
+}
+
+func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err error) {
+	defer func() {
+		if err != nil {
+			log15.Error("insights.recomputerunner.workHandler", "error", err)
+		}
+	}()
+
+	// Dequeue the job to get information about it, like what series to recompute.
+	job, err := dequeueJob(ctx, r.workerBaseStore, record.RecordID())
+	if err != nil {
+		return err
+	}
+
+	series, err := RecomputableSeries(ctx, r.insightStore, r.settingStore, r.loader, job.SeriesID)
+	if err != nil {
+		// The series was deleted or changed since the recomputation was requested. Retrying would
+		// not change that.
+		return errcode.MakeNonRetryable(err)
+	}
+	deleted, err := r.recompute(ctx, job.SeriesID, series)
+	if err != nil {
+		return err
+	}
+	log15.Info("insights: recomputing series", "series_id", job.SeriesID, "deleted_points", deleted)
+	return setJobResult(ctx, r.workerBaseStore, job.ID, deleted)
+}
@@ -0,0 +1,245 @@
+package recomputerunner
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// This file contains all the methods required to:
+//
+// 1. Create the recompute runner worker
+// 2. Enqueue jobs for the recompute runner to execute.
+// 3. Dequeue jobs from the recompute runner.
+// 4. Serialize jobs for the recompute runner into the DB.
+//
+
+// NewWorker returns a worker that will delete the recorded data of series and schedule its
+// recomputation, and record the number of deleted data points on the job for the site admin who
+// requested the recomputation.
+func NewWorker(ctx context.Context, workerBaseStore *basestore.Store, insightStore discovery.InsightStore, settingStore discovery.SettingStore, recomputeStore RecomputeStore, metrics workerutil.WorkerMetrics) *workerutil.Worker {
+	workerStore := createDBWorkerStore(workerBaseStore)
+
+	options := workerutil.WorkerOptions{
+		Name:              "insights_recompute_runner_worker",
+		NumHandlers:       1,
+		Interval:          5 * time.Second,
+		HeartbeatInterval: 15 * time.Second,
+		Metrics:           metrics,
+	}
+
+	return dbworker.NewWorker(ctx, workerStore, newWorkHandler(workerBaseStore, insightStore, settingStore, recomputeStore), options)
+}
+
+// NewResetter returns a resetter that will reset pending recompute runner jobs if they take too
+// long to complete.
+func NewResetter(ctx context.Context, workerBaseStore *basestore.Store, metrics dbworker.ResetterMetrics) *dbworker.Resetter {
+	workerStore := createDBWorkerStore(workerBaseStore)
+	options := dbworker.ResetterOptions{
+		Name:     "insights_recompute_runner_worker_resetter",
+		Interval: 1 * time.Minute,
+		Metrics:  metrics,
+	}
+	return dbworker.NewResetter(workerStore, options)
+}
+
+var workerStoreOptions = dbworkerstore.Options{
+	Name:              "insights_recompute_runner_jobs_store",
+	TableName:         "insights_recompute_jobs",
+	ColumnExpressions: jobsColumns,
+	Scan:              scanJobs,
+
+	// The data of a series is deleted in a single transaction, so a retried recomputation starts
+	// over, deleting the data recorded in the meantime.
+	StalledMaxAge:     5 * time.Minute,
+	RetryAfter:        1 * time.Minute,
+	MaxNumRetries:     3,
+	OrderByExpression: sqlf.Sprintf("id"),
+}
+
+// createDBWorkerStore creates the dbworker store for the recompute runner worker.
+//
+// See internal/workerutil/dbworker for more information about dbworkers.
+func createDBWorkerStore(s *basestore.Store) dbworkerstore.Store {
+	return dbworkerstore.New(s.Handle(), workerStoreOptions)
+}
+
+// EnqueueJob enqueues a job for the recompute runner worker to execute later.
+func EnqueueJob(ctx context.Context, workerBaseStore *basestore.Store, job *Job) (id int, err error) {
+	id, _, err = basestore.ScanFirstInt(workerBaseStore.Query(
+		ctx,
+		sqlf.Sprintf(
+			enqueueJobFmtStr,
+			job.SeriesID,
+			job.UserID,
+			job.State,
+			job.ProcessAfter,
+		),
+	))
+	return
+}
+
+const enqueueJobFmtStr = `
+-- source: enterprise/internal/insights/background/recomputerunner/worker.go:EnqueueJob
+INSERT INTO insights_recompute_jobs (
+	series_id,
+	user_id,
+	state,
+	process_after
+) VALUES (%s, %s, %s, %s)
+RETURNING id
+`
+
+// GetJob returns the recompute job with the given ID, if it exists.
+func GetJob(ctx context.Context, workerBaseStore *basestore.Store, id int) (*Job, bool, error) {
+	rows, err := workerBaseStore.Query(ctx, sqlf.Sprintf(getJobFmtStr, id))
+	if err != nil {
+		return nil, false, err
+	}
+	jobs, err := doScanJobs(rows, nil)
+	if err != nil || len(jobs) == 0 {
+		return nil, false, err
+	}
+	return jobs[0], true, nil
+}
+
+func dequeueJob(ctx context.Context, workerBaseStore *basestore.Store, recordID int) (*Job, error) {
+	job, ok, err := GetJob(ctx, workerBaseStore, recordID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.Errorf("expected 1 job to dequeue, found 0")
+	}
+	return job, nil
+}
+
+const getJobFmtStr = `
+-- source: enterprise/internal/insights/background/recomputerunner/worker.go:GetJob
+SELECT
+	series_id,
+	user_id,
+	deleted_points,
+	id,
+	state,
+	failure_message,
+	started_at,
+	finished_at,
+	process_after,
+	num_resets,
+	num_failures,
+	execution_logs
+FROM insights_recompute_jobs
+WHERE id = %s;
+`
+
+// setJobResult records the number of deleted data points on the job with the given ID.
+func setJobResult(ctx context.Context, workerBaseStore *basestore.Store, id, deleted int) error {
+	return workerBaseStore.Exec(ctx, sqlf.Sprintf(setJobResultFmtStr, deleted, id))
+}
+
+const setJobResultFmtStr = `
+-- source: enterprise/internal/insights/background/recomputerunner/worker.go:setJobResult
+UPDATE insights_recompute_jobs SET deleted_points = %s WHERE id = %s
+`
+
+// Job represents a single job for the recompute runner worker to perform. When enqueued, it is
+// stored in the insights_recompute_jobs table - then the worker dequeues it by reading it from that
+// table.
+//
+// See internal/workerutil/dbworker for more information about dbworkers.
+type Job struct {
+	// Recompute runner fields.
+	SeriesID      string
+	UserID        int32 // The site admin who requested the recomputation.
+	DeletedPoints *int  // The number of deleted data points, once they are deleted.
+
+	// Standard/required dbworker fields. If enqueuing a job, these may all be zero values except State.
+	ID             int
+	State          string // If enqueing a job, set to "queued"
+	FailureMessage *string
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+	ProcessAfter   *time.Time
+	NumResets      int32
+	NumFailures    int32
+	ExecutionLogs  []workerutil.ExecutionLogEntry
+}
+
+// Implements the internal/workerutil.Record interface, used by the work handler to locate the job
+// once executing (see work_handler.go:Handle).
+func (j *Job) RecordID() int {
+	return j.ID
+}
+
+func scanJobs(rows *sql.Rows, err error) (workerutil.Record, bool, error) {
+	records, err := doScanJobs(rows, err)
+	if err != nil {
+		return &Job{}, false, err
+	}
+	return records[0], true, nil
+}
+
+func doScanJobs(rows *sql.Rows, err error) ([]*Job, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+	var jobs []*Job
+	for rows.Next() {
+		j := &Job{}
+		if err := rows.Scan(
+			// Recompute runner fields.
+			&j.SeriesID,
+			&j.UserID,
+			&j.DeletedPoints,
+
+			// Standard/required dbworker fields.
+			&j.ID,
+			&j.State,
+			&j.FailureMessage,
+			&j.StartedAt,
+			&j.FinishedAt,
+			&j.ProcessAfter,
+			&j.NumResets,
+			&j.NumFailures,
+			pq.Array(&j.ExecutionLogs),
+		); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+var jobsColumns = []*sqlf.Query{
+	sqlf.Sprintf("insights_recompute_jobs.series_id"),
+	sqlf.Sprintf("insights_recompute_jobs.user_id"),
+	sqlf.Sprintf("insights_recompute_jobs.deleted_points"),
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("state"),
+	sqlf.Sprintf("failure_message"),
+	sqlf.Sprintf("started_at"),
+	sqlf.Sprintf("finished_at"),
+	sqlf.Sprintf("process_after"),
+	sqlf.Sprintf("num_resets"),
+	sqlf.Sprintf("num_failures"),
+	sqlf.Sprintf("execution_logs"),
+}
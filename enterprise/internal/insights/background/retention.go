@@ -0,0 +1,131 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// newRetentionEnforcer returns a background goroutine which will periodically downsample and
+// prune old insights data points according to the retention policy in the site configuration.
+func newRetentionEnforcer(ctx context.Context, retentionStore RetentionStore, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_retention_enforcer",
+		metrics.WithCountHelp("Total number of insights retention enforcer executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "RetentionEnforcer.Run",
+		Metrics: metrics,
+	})
+
+	downsampled := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_insights_retention_points_downsampled_total",
+		Help: "The number of insights data points deleted by downsampling.",
+	})
+	observationContext.Registerer.MustRegister(downsampled)
+
+	pruned := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_insights_retention_points_pruned_total",
+		Help: "The number of insights data points deleted for exceeding the maximum age.",
+	})
+	observationContext.Registerer.MustRegister(pruned)
+
+	enforcer := &retentionEnforcer{
+		retentionStore: retentionStore,
+		policy:         retentionPolicyFromSiteConfig,
+		now:            time.Now,
+		downsampled:    downsampled,
+		pruned:         pruned,
+	}
+
+	// Historical data is recorded far in the past at any time, so every run considers all data
+	// points. Runs are infrequent since the data only needs to be compacted eventually.
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 12*time.Hour, goroutine.NewHandlerWithErrorMessage(
+		"insights_retention_enforcer",
+		enforcer.Handler,
+	), operation)
+}
+
+// RetentionStore is a subset of the API exposed by the store.Store (only the subset used by the
+// retention enforcer.)
+type RetentionStore interface {
+	DownsampleSeriesPoints(ctx context.Context, before time.Time) (int, error)
+	PruneSeriesPoints(ctx context.Context, before time.Time) (int, error)
+}
+
+// retentionPolicy describes the maximum age of data points at full resolution and in total. A
+// zero age disables the respective step.
+type retentionPolicy struct {
+	downsampleAfter time.Duration
+	pruneAfter      time.Duration
+}
+
+// defaultDownsampleAfterDays is the number of days after which data points are downsampled if
+// the site configuration does not specify otherwise.
+const defaultDownsampleAfterDays = 90
+
+func retentionPolicyFromSiteConfig() retentionPolicy {
+	c := conf.Get()
+
+	downsampleAfterDays := defaultDownsampleAfterDays
+	if c.InsightsRetentionDownsampleAfterDays != nil {
+		downsampleAfterDays = *c.InsightsRetentionDownsampleAfterDays
+	}
+
+	return retentionPolicy{
+		downsampleAfter: time.Duration(downsampleAfterDays) * 24 * time.Hour,
+		pruneAfter:      time.Duration(c.InsightsRetentionPruneAfterDays) * 24 * time.Hour,
+	}
+}
+
+// retentionEnforcer bounds the growth of insights data. Data points older than the downsampling
+// age are reduced to the latest data point of each series, repository, and week, and data points
+// older than the pruning age are deleted altogether.
+type retentionEnforcer struct {
+	retentionStore RetentionStore
+	policy         func() retentionPolicy
+	now            func() time.Time
+
+	downsampled, pruned prometheus.Counter
+}
+
+func (e *retentionEnforcer) Handler(ctx context.Context) error {
+	policy := e.policy()
+	now := e.now()
+
+	if policy.downsampleAfter > 0 {
+		// Only downsample whole weeks, so that the data points of a week are never split between
+		// the downsampled and the full resolution data.
+		before := insights.Weekly.Start(now.Add(-policy.downsampleAfter))
+
+		count, err := e.retentionStore.DownsampleSeriesPoints(ctx, before)
+		if err != nil {
+			return errors.Wrap(err, "DownsampleSeriesPoints")
+		}
+		e.downsampled.Add(float64(count))
+		log15.Debug("insights: downsampled data points", "before", before, "count", count)
+	}
+
+	if policy.pruneAfter > 0 {
+		before := now.Add(-policy.pruneAfter)
+
+		count, err := e.retentionStore.PruneSeriesPoints(ctx, before)
+		if err != nil {
+			return errors.Wrap(err, "PruneSeriesPoints")
+		}
+		e.pruned.Add(float64(count))
+		log15.Debug("insights: pruned data points", "before", before, "count", count)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package background
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRetentionEnforcer(t *testing.T) {
+	// Wednesday, 1 September 2021.
+	now := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	retentionStore := NewMockRetentionStore()
+	retentionStore.DownsampleSeriesPointsFunc.SetDefaultReturn(5, nil)
+	retentionStore.PruneSeriesPointsFunc.SetDefaultReturn(3, nil)
+
+	e := &retentionEnforcer{
+		retentionStore: retentionStore,
+		policy: func() retentionPolicy {
+			return retentionPolicy{downsampleAfter: 30 * day, pruneAfter: 365 * day}
+		},
+		now:         func() time.Time { return now },
+		downsampled: prometheus.NewCounter(prometheus.CounterOpts{}),
+		pruned:      prometheus.NewCounter(prometheus.CounterOpts{}),
+	}
+
+	if err := e.Handler(context.Background()); err != nil {
+		t.Fatalf("unexpected error enforcing retention: %s", err)
+	}
+
+	// Downsampling is aligned to the start of the week (Monday, 2 August 2021).
+	if history := retentionStore.DownsampleSeriesPointsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of downsampling calls. want=%d have=%d", 1, len(history))
+	} else if want := time.Date(2021, 8, 2, 0, 0, 0, 0, time.UTC); !history[0].Arg1.Equal(want) {
+		t.Errorf("unexpected downsampling time. want=%s have=%s", want, history[0].Arg1)
+	}
+	if history := retentionStore.PruneSeriesPointsFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of pruning calls. want=%d have=%d", 1, len(history))
+	} else if want := now.Add(-365 * day); !history[0].Arg1.Equal(want) {
+		t.Errorf("unexpected pruning time. want=%s have=%s", want, history[0].Arg1)
+	}
+
+	if value := testutil.ToFloat64(e.downsampled); value != 5 {
+		t.Errorf("unexpected downsampled count. want=%d have=%v", 5, value)
+	}
+	if value := testutil.ToFloat64(e.pruned); value != 3 {
+		t.Errorf("unexpected pruned count. want=%d have=%v", 3, value)
+	}
+}
+
+func TestRetentionEnforcerDisabled(t *testing.T) {
+	retentionStore := NewMockRetentionStore()
+
+	e := &retentionEnforcer{
+		retentionStore: retentionStore,
+		policy:         func() retentionPolicy { return retentionPolicy{} },
+		now:            time.Now,
+		downsampled:    prometheus.NewCounter(prometheus.CounterOpts{}),
+		pruned:         prometheus.NewCounter(prometheus.CounterOpts{}),
+	}
+
+	if err := e.Handler(context.Background()); err != nil {
+		t.Fatalf("unexpected error enforcing retention: %s", err)
+	}
+	if value := len(retentionStore.DownsampleSeriesPointsFunc.History()); value != 0 {
+		t.Errorf("unexpected number of downsampling calls. want=%d have=%d", 0, value)
+	}
+	if value := len(retentionStore.PruneSeriesPointsFunc.History()); value != 0 {
+		t.Errorf("unexpected number of pruning calls. want=%d have=%d", 0, value)
+	}
+}
+
+func TestRetentionEnforcerError(t *testing.T) {
+	retentionStore := NewMockRetentionStore()
+	retentionStore.DownsampleSeriesPointsFunc.SetDefaultReturn(0, errors.New("database unavailable"))
+
+	e := &retentionEnforcer{
+		retentionStore: retentionStore,
+		policy: func() retentionPolicy {
+			return retentionPolicy{downsampleAfter: time.Hour, pruneAfter: time.Hour}
+		},
+		now:         time.Now,
+		downsampled: prometheus.NewCounter(prometheus.CounterOpts{}),
+		pruned:      prometheus.NewCounter(prometheus.CounterOpts{}),
+	}
+
+	if err := e.Handler(context.Background()); err == nil {
+		t.Fatalf("expected an error enforcing retention")
+	}
+	if value := len(retentionStore.PruneSeriesPointsFunc.History()); value != 0 {
+		t.Errorf("unexpected number of pruning calls. want=%d have=%d", 0, value)
+	}
+}
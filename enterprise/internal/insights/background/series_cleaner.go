@@ -0,0 +1,114 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/webhookrunner"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// unreferencedSeriesRetention is the time for which the data of a series that is no longer used by
+// any insight is retained. Insights that start using the series again within that time, e.g. an
+// insight that was removed by mistake and restored, reuse its data instead of backfilling it again.
+const unreferencedSeriesRetention = 7 * 24 * time.Hour
+
+// newSeriesCleaner returns a background goroutine which will periodically cancel the pending work
+// of the data series no longer used by any insight, and purge those that have no longer been used
+// for unreferencedSeriesRetention along with their data.
+func newSeriesCleaner(ctx context.Context, workerBaseStore *basestore.Store, seriesStore SeriesCleanerStore, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_series_cleaner",
+		metrics.WithCountHelp("Total number of insights series cleaner executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "SeriesCleaner.Run",
+		Metrics: metrics,
+	})
+
+	purged := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_insights_series_purged_total",
+		Help: "The number of insights data series purged for no longer being used by any insight.",
+	})
+	observationContext.Registerer.MustRegister(purged)
+
+	canceled := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_insights_series_jobs_canceled_total",
+		Help: "The number of pending insights jobs canceled for recording series no longer used by any insight.",
+	})
+	observationContext.Registerer.MustRegister(canceled)
+
+	cleaner := &seriesCleaner{
+		seriesStore: seriesStore,
+		cancelJobs: func(ctx context.Context, seriesIDs []string) (int, error) {
+			queryJobs, err := queryrunner.DeleteQueuedJobs(ctx, workerBaseStore, seriesIDs)
+			if err != nil {
+				return 0, errors.Wrap(err, "queryrunner.DeleteQueuedJobs")
+			}
+			webhookJobs, err := webhookrunner.DeleteQueuedJobs(ctx, workerBaseStore, seriesIDs)
+			if err != nil {
+				return 0, errors.Wrap(err, "webhookrunner.DeleteQueuedJobs")
+			}
+			return queryJobs + webhookJobs, nil
+		},
+		now:      time.Now,
+		purged:   purged,
+		canceled: canceled,
+	}
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 1*time.Hour, goroutine.NewHandlerWithErrorMessage(
+		"insights_series_cleaner",
+		cleaner.Handler,
+	), operation)
+}
+
+// SeriesCleanerStore is a subset of the API exposed by the store.InsightStore (only the subset
+// used by the series cleaner.)
+type SeriesCleanerStore interface {
+	GetUnreferencedSeriesIDs(ctx context.Context) ([]string, error)
+	PurgeUnreferencedSeries(ctx context.Context, before time.Time) (int, error)
+}
+
+// seriesCleaner cleans up after data series that are no longer referenced. Identical series of
+// different insights share a single data series, which is only soft-deleted once the last insight
+// using it is removed (see store.InsightStore.DeleteView).
+type seriesCleaner struct {
+	seriesStore SeriesCleanerStore
+	cancelJobs  func(ctx context.Context, seriesIDs []string) (int, error)
+	now         func() time.Time
+
+	purged, canceled prometheus.Counter
+}
+
+// Handler cancels the jobs waiting to record unreferenced series right away, since the series are
+// no longer recorded. If an insight starts using a series again, its enqueuers record it again.
+// Unreferenced series are purged after unreferencedSeriesRetention.
+func (c *seriesCleaner) Handler(ctx context.Context) error {
+	seriesIDs, err := c.seriesStore.GetUnreferencedSeriesIDs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "GetUnreferencedSeriesIDs")
+	}
+	canceled, err := c.cancelJobs(ctx, seriesIDs)
+	if err != nil {
+		return err
+	}
+	c.canceled.Add(float64(canceled))
+	log15.Debug("insights: canceled jobs of unreferenced series", "series", len(seriesIDs), "count", canceled)
+
+	before := c.now().Add(-unreferencedSeriesRetention)
+	count, err := c.seriesStore.PurgeUnreferencedSeries(ctx, before)
+	if err != nil {
+		return errors.Wrap(err, "PurgeUnreferencedSeries")
+	}
+	c.purged.Add(float64(count))
+	log15.Debug("insights: purged unreferenced series", "before", before, "count", count)
+	return nil
+}
@@ -0,0 +1,50 @@
+package background
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSeriesCleaner(t *testing.T) {
+	now := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+
+	seriesStore := NewMockSeriesCleanerStore()
+	seriesStore.GetUnreferencedSeriesIDsFunc.SetDefaultReturn([]string{"s1", "s2"}, nil)
+	seriesStore.PurgeUnreferencedSeriesFunc.SetDefaultReturn(2, nil)
+
+	var canceledSeriesIDs []string
+	c := &seriesCleaner{
+		seriesStore: seriesStore,
+		cancelJobs: func(ctx context.Context, seriesIDs []string) (int, error) {
+			canceledSeriesIDs = seriesIDs
+			return 5, nil
+		},
+		now:      func() time.Time { return now },
+		purged:   prometheus.NewCounter(prometheus.CounterOpts{}),
+		canceled: prometheus.NewCounter(prometheus.CounterOpts{}),
+	}
+
+	if err := c.Handler(context.Background()); err != nil {
+		t.Fatalf("unexpected error purging series: %s", err)
+	}
+
+	if history := seriesStore.PurgeUnreferencedSeriesFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of purge calls. want=%d have=%d", 1, len(history))
+	} else if want := now.Add(-unreferencedSeriesRetention); !history[0].Arg1.Equal(want) {
+		t.Errorf("unexpected purge time. want=%s have=%s", want, history[0].Arg1)
+	}
+	if diff := cmp.Diff([]string{"s1", "s2"}, canceledSeriesIDs); diff != "" {
+		t.Errorf("unexpected series of canceled jobs (-want +got):\n%s", diff)
+	}
+	if value := testutil.ToFloat64(c.canceled); value != 5 {
+		t.Errorf("unexpected canceled count. want=%d have=%v", 5, value)
+	}
+	if value := testutil.ToFloat64(c.purged); value != 2 {
+		t.Errorf("unexpected purged count. want=%d have=%v", 2, value)
+	}
+}
@@ -0,0 +1,110 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// settingsPollInterval is the interval at which the settings watcher checks for settings changes.
+const settingsPollInterval = 30 * time.Second
+
+// newSettingsWatcher returns a background goroutine which will periodically check whether any
+// user, organization, or global settings have changed, and invoke the given function once for all
+// changes made since the last check with the namespaces of the changed settings. Insights defined
+// in settings are discovered by the insight enqueuer, so this lets it pick up new insights without
+// waiting for its next run.
+func newSettingsWatcher(ctx context.Context, workerBaseStore *basestore.Store, onChange func(ctx context.Context, namespaces []insights.Namespace) error, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_settings_watcher",
+		metrics.WithCountHelp("Total number of insights settings watcher executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "SettingsWatcher.Run",
+		Metrics: metrics,
+	})
+
+	watcher := &settingsWatcher{
+		latestSettingsID: func(ctx context.Context) (int, error) {
+			id, _, err := basestore.ScanFirstInt(workerBaseStore.Query(ctx, sqlf.Sprintf(latestSettingsIDSql)))
+			return id, err
+		},
+		changedNamespaces: func(ctx context.Context, afterID int) (_ []insights.Namespace, err error) {
+			rows, err := workerBaseStore.Query(ctx, sqlf.Sprintf(changedSettingsNamespacesSql, afterID))
+			if err != nil {
+				return nil, err
+			}
+			defer func() { err = basestore.CloseRows(rows, err) }()
+
+			var namespaces []insights.Namespace
+			for rows.Next() {
+				var namespace insights.Namespace
+				if err := rows.Scan(&namespace.UserID, &namespace.OrgID); err != nil {
+					return nil, err
+				}
+				namespaces = append(namespaces, namespace)
+			}
+			return namespaces, nil
+		},
+		onChange: onChange,
+	}
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, settingsPollInterval, goroutine.NewHandlerWithErrorMessage(
+		"insights_settings_watcher",
+		watcher.Handler,
+	), operation)
+}
+
+// settingsWatcher detects settings changes by the ID of the latest settings, which increases with
+// every change to any settings.
+type settingsWatcher struct {
+	latestSettingsID  func(ctx context.Context) (int, error)
+	changedNamespaces func(ctx context.Context, afterID int) ([]insights.Namespace, error)
+	onChange          func(ctx context.Context, namespaces []insights.Namespace) error
+
+	lastID  int  // the latest settings ID that changes were handled for
+	started bool // whether lastID has been initialized
+}
+
+// Handler checks for settings changes. The first check only records the latest settings ID, since
+// the settings at startup are picked up by the regular runs of the insight enqueuer. If handling a
+// change fails, it is handled again on the next check.
+func (w *settingsWatcher) Handler(ctx context.Context) error {
+	id, err := w.latestSettingsID(ctx)
+	if err != nil {
+		return err
+	}
+	if !w.started {
+		w.lastID, w.started = id, true
+		return nil
+	}
+	if id == w.lastID {
+		return nil
+	}
+	namespaces, err := w.changedNamespaces(ctx, w.lastID)
+	if err != nil {
+		return err
+	}
+	if err := w.onChange(ctx, namespaces); err != nil {
+		return err
+	}
+	w.lastID = id
+	return nil
+}
+
+const latestSettingsIDSql = `
+-- source: enterprise/internal/insights/background/settings_watcher.go:newSettingsWatcher
+SELECT COALESCE(MAX(id), 0) FROM settings
+`
+
+const changedSettingsNamespacesSql = `
+-- source: enterprise/internal/insights/background/settings_watcher.go:newSettingsWatcher
+SELECT DISTINCT COALESCE(user_id, 0), COALESCE(org_id, 0) FROM settings WHERE id > %s
+`
@@ -0,0 +1,59 @@
+package background
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestSettingsWatcher(t *testing.T) {
+	ctx := context.Background()
+	latestID := 1
+	var changes int
+	var changeErr error
+	var changedAfter []int
+	watcher := &settingsWatcher{
+		latestSettingsID: func(ctx context.Context) (int, error) { return latestID, nil },
+		changedNamespaces: func(ctx context.Context, afterID int) ([]insights.Namespace, error) {
+			changedAfter = append(changedAfter, afterID)
+			return []insights.Namespace{{UserID: int32(afterID)}}, nil
+		},
+		onChange: func(ctx context.Context, namespaces []insights.Namespace) error {
+			if want := []insights.Namespace{{UserID: int32(changedAfter[len(changedAfter)-1])}}; !cmp.Equal(want, namespaces) {
+				t.Errorf("unexpected namespaces (-want +got):\n%s", cmp.Diff(want, namespaces))
+			}
+			changes++
+			return changeErr
+		},
+	}
+
+	for _, step := range []struct {
+		latestID  int
+		changeErr error
+		expected  int
+	}{
+		{1, nil, 0}, // the settings at startup are not a change
+		{1, nil, 0},
+		{3, nil, 1}, // several changes are handled at once
+		{3, nil, 1},
+		{4, errors.New("enqueue failed"), 2},
+		{4, nil, 3}, // failed changes are handled again
+		{4, nil, 3},
+	} {
+		latestID, changeErr = step.latestID, step.changeErr
+		if err := watcher.Handler(ctx); err != step.changeErr {
+			t.Fatalf("unexpected error. want=%v have=%v", step.changeErr, err)
+		}
+		if changes != step.expected {
+			t.Errorf("unexpected number of handled changes at settings ID %d. want=%d have=%d", step.latestID, step.expected, changes)
+		}
+	}
+
+	if want := []int{1, 3, 3}; !cmp.Equal(want, changedAfter) {
+		t.Errorf("unexpected settings IDs that changes were looked up after (-want +got):\n%s", cmp.Diff(want, changedAfter))
+	}
+}
@@ -0,0 +1,59 @@
+package snapshotrunner
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// comparisonRetention is the time for which comparisons remain available after they were computed.
+// The data points they compare rarely change once recorded, so comparisons are kept for long enough
+// to be shared and revisited, unlike exports.
+const comparisonRetention = 7 * 24 * time.Hour
+
+// NewCleaner returns a background goroutine which will periodically find jobs left in the
+// "completed" or "failed" state that finished over comparisonRetention ago and removes them, along
+// with the comparisons they hold.
+func NewCleaner(ctx context.Context, workerBaseStore *basestore.Store, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_snapshot_runner_cleaner",
+		metrics.WithCountHelp("Total number of insights snapshotrunner cleaner executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "SnapshotRunner.Cleaner.Run",
+		Metrics: metrics,
+	})
+
+	// We look for jobs to cleanup every hour.
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 1*time.Hour, goroutine.NewHandlerWithErrorMessage(
+		"insights_snapshot_runner_cleaner",
+		func(ctx context.Context) error {
+			_, err := cleanJobs(ctx, workerBaseStore)
+			return err
+		},
+	), operation)
+}
+
+// cleanJobs removes completed and failed jobs that finished over comparisonRetention ago, and
+// returns the number of removed jobs.
+func cleanJobs(ctx context.Context, workerBaseStore *basestore.Store) (numCleaned int, err error) {
+	numCleaned, _, err = basestore.ScanFirstInt(workerBaseStore.Query(
+		ctx,
+		sqlf.Sprintf(cleanJobsFmtStr, time.Now().Add(-comparisonRetention)),
+	))
+	return
+}
+
+const cleanJobsFmtStr = `
+-- source: enterprise/internal/insights/background/snapshotrunner/cleaner.go:cleanJobs
+WITH deleted AS (
+	DELETE FROM insights_snapshot_jobs WHERE (state='completed' OR state='failed') AND finished_at < %s RETURNING *
+) SELECT count(*) FROM deleted
+`
@@ -0,0 +1,105 @@
+package snapshotrunner
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+)
+
+// SnapshotStore is a subset of the API exposed by the store.Store (only the subset used by the
+// snapshot runner.)
+type SnapshotStore interface {
+	RepoPointsAt(ctx context.Context, opts store.RepoPointsAtOpts) ([]store.RepoPoint, error)
+}
+
+// Comparison is the JSON representation of the comparison of a series between two points in time.
+type Comparison struct {
+	SeriesID string       `json:"seriesId"`
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"`
+	Changes  []RepoChange `json:"changes"`
+}
+
+// RepoChange is the change of the value of a series in a single repository between the two points
+// in time of a comparison. Repositories that gained their first matches have a FromValue of 0, and
+// repositories that lost all their matches have a ToValue of 0.
+type RepoChange struct {
+	Repository string  `json:"repository"`
+	FromValue  float64 `json:"fromValue"`
+	ToValue    float64 `json:"toValue"`
+}
+
+// Delta returns the change of the value of the repository.
+func (c RepoChange) Delta() float64 {
+	return c.ToValue - c.FromValue
+}
+
+// Compare returns the JSON comparison of the values of the given series in each repository at the
+// given points in time. Only the repositories whose value changed are included, largest changes
+// first. The value of a repository at a point in time is the value of its latest data point
+// recorded at or before that time, as for store.RepoPointsAt. Data points are recorded per
+// repository, so changes within the files of a repository are not known.
+//
+// 🚨 SECURITY: The values are restricted to the repositories the actor of the given context can
+// access, so comparisons must be computed as the user who requested them.
+func Compare(ctx context.Context, snapshotStore SnapshotStore, seriesID string, from, to time.Time) ([]byte, error) {
+	if !from.Before(to) {
+		return nil, errors.Errorf("comparison must be from an earlier point in time, got from %s to %s", from, to)
+	}
+
+	fromPoints, err := snapshotStore.RepoPointsAt(ctx, store.RepoPointsAtOpts{SeriesID: seriesID, Time: from})
+	if err != nil {
+		return nil, errors.Wrap(err, "RepoPointsAt")
+	}
+	toPoints, err := snapshotStore.RepoPointsAt(ctx, store.RepoPointsAtOpts{SeriesID: seriesID, Time: to})
+	if err != nil {
+		return nil, errors.Wrap(err, "RepoPointsAt")
+	}
+
+	return json.Marshal(Comparison{
+		SeriesID: seriesID,
+		From:     from.UTC(),
+		To:       to.UTC(),
+		Changes:  diffRepoPoints(fromPoints, toPoints),
+	})
+}
+
+// diffRepoPoints returns the changes between the given data points, ordered by decreasing
+// magnitude of change and then by repository name.
+func diffRepoPoints(fromPoints, toPoints []store.RepoPoint) []RepoChange {
+	byRepo := map[string]*RepoChange{}
+	change := func(repoName string) *RepoChange {
+		c, ok := byRepo[repoName]
+		if !ok {
+			c = &RepoChange{Repository: repoName}
+			byRepo[repoName] = c
+		}
+		return c
+	}
+	for _, point := range fromPoints {
+		change(point.RepoName).FromValue = point.Value
+	}
+	for _, point := range toPoints {
+		change(point.RepoName).ToValue = point.Value
+	}
+
+	changes := make([]RepoChange, 0, len(byRepo))
+	for _, c := range byRepo {
+		if c.Delta() != 0 {
+			changes = append(changes, *c)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if di, dj := math.Abs(changes[i].Delta()), math.Abs(changes[j].Delta()); di != dj {
+			return di > dj
+		}
+		return changes[i].Repository < changes[j].Repository
+	})
+	return changes
+}
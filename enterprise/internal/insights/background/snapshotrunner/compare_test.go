@@ -0,0 +1,70 @@
+package snapshotrunner
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+)
+
+func TestCompare(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	snapshotStore := NewMockSnapshotStore()
+	snapshotStore.RepoPointsAtFunc.SetDefaultHook(func(ctx context.Context, opts store.RepoPointsAtOpts) ([]store.RepoPoint, error) {
+		if opts.SeriesID != "s:errors" {
+			t.Errorf("unexpected series ID. want=%q have=%q", "s:errors", opts.SeriesID)
+		}
+		switch {
+		case opts.Time.Equal(from):
+			return []store.RepoPoint{
+				{RepoName: "unchanged", Value: 3},
+				{RepoName: "lost", Value: 2},
+				{RepoName: "grew", Value: 1},
+			}, nil
+		case opts.Time.Equal(to):
+			return []store.RepoPoint{
+				{RepoName: "gained", Value: 2},
+				{RepoName: "grew", Value: 5},
+				{RepoName: "unchanged", Value: 3},
+			}, nil
+		}
+		t.Fatalf("unexpected time %s", opts.Time)
+		return nil, nil
+	})
+
+	data, err := Compare(ctx, snapshotStore, "s:errors", from, to)
+	if err != nil {
+		t.Fatalf("unexpected error comparing: %s", err)
+	}
+	var comparison Comparison
+	if err := json.Unmarshal(data, &comparison); err != nil {
+		t.Fatalf("unexpected error decoding comparison: %s", err)
+	}
+	want := Comparison{
+		SeriesID: "s:errors",
+		From:     from,
+		To:       to,
+		Changes: []RepoChange{
+			{Repository: "grew", FromValue: 1, ToValue: 5},
+			{Repository: "gained", FromValue: 0, ToValue: 2},
+			{Repository: "lost", FromValue: 2, ToValue: 0},
+		},
+	}
+	if diff := cmp.Diff(want, comparison); diff != "" {
+		t.Errorf("unexpected comparison (-want +got):\n%s", diff)
+	}
+}
+
+func TestCompareInvalidTimes(t *testing.T) {
+	at := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := Compare(context.Background(), NewMockSnapshotStore(), "s:errors", at, at); err == nil {
+		t.Fatalf("expected an error comparing a point in time to itself")
+	}
+}
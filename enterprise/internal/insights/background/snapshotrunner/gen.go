@@ -0,0 +1,3 @@
+package snapshotrunner
+
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/snapshotrunner -i SnapshotStore -o mock_snapshot_store.go
@@ -0,0 +1,66 @@
+package snapshotrunner
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+var _ workerutil.Handler = &workHandler{}
+
+// workHandler implements the dbworker.Handler interface by comparing series between two points in
+// time and storing the comparisons on their jobs.
+type workHandler struct {
+	workerBaseStore *basestore.Store
+	insightStore    discovery.InsightStore
+	settingStore    discovery.SettingStore
+	snapshotStore   SnapshotStore
+}
+
+func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err error) {
+	defer func() {
+		if err != nil {
+			log15.Error("insights.snapshotrunner.workHandler", "error", err)
+		}
+	}()
+
+	// Dequeue the job to get information about it, like what series to compare.
+	job, err := dequeueJob(ctx, r.workerBaseStore, record.RecordID())
+	if err != nil {
+		return err
+	}
+
+	// 🚨 SECURITY: Compute the comparison as the user who requested it, so that it only holds the
+	// series and repositories visible to them.
+	ctx = actor.WithActor(ctx, actor.FromUser(job.UserID))
+	namespaces, err := discovery.VisibleNamespaces(ctx, r.workerBaseStore.Handle().DB())
+	if err != nil {
+		return errors.Wrap(err, "VisibleNamespaces")
+	}
+	discovered, err := discovery.Discover(ctx, r.insightStore, r.settingStore, insights.NewLoader(r.workerBaseStore.Handle().DB()), discovery.InsightFilterArgs{
+		Namespaces: namespaces,
+		SeriesIDs:  []string{job.SeriesID},
+	})
+	if err != nil {
+		return errors.Wrap(err, "Discover")
+	}
+	if len(discovered) == 0 {
+		// The series was deleted, or is no longer visible to the user since the comparison was
+		// requested. Retrying would not change that.
+		return errcode.MakeNonRetryable(errors.Errorf("insight series %q not found", job.SeriesID))
+	}
+
+	data, err := Compare(ctx, r.snapshotStore, job.SeriesID, job.From, job.To)
+	if err != nil {
+		return err
+	}
+	return setJobData(ctx, r.workerBaseStore, job.ID, data)
+}
@@ -0,0 +1,54 @@
+package webhookrunner
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// NewCleaner returns a background goroutine which will periodically find jobs left in the
+// "completed" or "failed" state that finished over 12 hours ago and removes them. The outcome of
+// the most recent requests to each webhook is kept by the insights store.
+func NewCleaner(ctx context.Context, workerBaseStore *basestore.Store, observationContext *observation.Context) goroutine.BackgroundRoutine {
+	metrics := metrics.NewOperationMetrics(
+		observationContext.Registerer,
+		"insights_webhook_runner_cleaner",
+		metrics.WithCountHelp("Total number of insights webhookrunner cleaner executions"),
+	)
+	operation := observationContext.Operation(observation.Op{
+		Name:    "WebhookRunner.Cleaner.Run",
+		Metrics: metrics,
+	})
+
+	// We look for jobs to cleanup every hour.
+	return goroutine.NewPeriodicGoroutineWithMetrics(ctx, 1*time.Hour, goroutine.NewHandlerWithErrorMessage(
+		"insights_webhook_runner_cleaner",
+		func(ctx context.Context) error {
+			_, err := cleanJobs(ctx, workerBaseStore)
+			return err
+		},
+	), operation)
+}
+
+// cleanJobs removes completed and failed jobs that finished over 12 hours ago, and returns the
+// number of removed jobs.
+func cleanJobs(ctx context.Context, workerBaseStore *basestore.Store) (numCleaned int, err error) {
+	numCleaned, _, err = basestore.ScanFirstInt(workerBaseStore.Query(
+		ctx,
+		sqlf.Sprintf(cleanJobsFmtStr, time.Now().Add(-12*time.Hour)),
+	))
+	return
+}
+
+const cleanJobsFmtStr = `
+-- source: enterprise/internal/insights/background/webhookrunner/cleaner.go:cleanJobs
+WITH deleted AS (
+	DELETE FROM insights_webhook_runner_jobs WHERE (state='completed' OR state='failed') AND finished_at < %s RETURNING *
+) SELECT count(*) FROM deleted
+`
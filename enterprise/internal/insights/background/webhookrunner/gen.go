@@ -0,0 +1,3 @@
+package webhookrunner
+
+//go:generate ../../../../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/webhookrunner -i WebhookStore -o mock_webhook_store.go
@@ -0,0 +1,163 @@
+package webhookrunner
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+)
+
+var _ workerutil.Handler = &workHandler{}
+
+// WebhookStore is a subset of the API exposed by the store.Store (only the subset used by the
+// webhook runner.)
+type WebhookStore interface {
+	RecordSeriesPoint(ctx context.Context, v store.RecordSeriesPointArgs) error
+	RecordWebhookDelivery(ctx context.Context, d store.WebhookDelivery) error
+}
+
+// workHandler implements the dbworker.Handler interface by requesting the data points of webhook
+// series from their webhooks and inserting them into the insights Timescale database.
+type workHandler struct {
+	workerBaseStore *basestore.Store
+	insightsStore   WebhookStore
+	doer            httpcli.Doer
+	secret          func() string
+}
+
+func (r *workHandler) Handle(ctx context.Context, record workerutil.Record) (err error) {
+	defer func() {
+		if err != nil {
+			log15.Error("insights.webhookrunner.workHandler", "error", err)
+		}
+	}()
+
+	// Dequeue the job to get information about it, like what webhook to request.
+	job, err := dequeueJob(ctx, r.workerBaseStore, record.RecordID())
+	if err != nil {
+		return err
+	}
+	return r.handle(ctx, job)
+}
+
+// handle requests the data point of the given job from its webhook and records it, along with the
+// outcome of the request.
+func (r *workHandler) handle(ctx context.Context, job *Job) error {
+	recordTime := time.Now()
+	if job.RecordTime != nil {
+		recordTime = *job.RecordTime
+	}
+
+	value, statusCode, err := requestDataPoint(ctx, r.doer, r.secret(), job.SeriesID, job.WebhookURL, recordTime)
+	if err == nil {
+		err = r.insightsStore.RecordSeriesPoint(ctx, store.RecordSeriesPointArgs{
+			SeriesID: job.SeriesID,
+			Point: store.SeriesPoint{
+				Time:  recordTime,
+				Value: value,
+			},
+		})
+		if err != nil {
+			err = errors.Wrap(err, "RecordSeriesPoint")
+		}
+	}
+
+	if deliveryErr := r.insightsStore.RecordWebhookDelivery(ctx, store.WebhookDelivery{
+		SeriesID:   job.SeriesID,
+		StatusCode: statusCode,
+		Err:        err,
+	}); deliveryErr != nil {
+		log15.Error("insights.webhookrunner.workHandler: failed to record webhook delivery", "seriesID", job.SeriesID, "error", deliveryErr)
+	}
+	return err
+}
+
+// requestTimeout is the time after which requests to webhooks time out.
+const requestTimeout = 30 * time.Second
+
+// maxResponseSize is the maximum size of webhook responses that are read.
+const maxResponseSize = 1024 * 1024
+
+// SignatureHeader is the header of webhook requests which holds the signature of the request
+// body, if the insights.webhook.secret site configuration is set.
+const SignatureHeader = "X-Sourcegraph-Signature"
+
+// webhookRequest is the body of the requests sent to webhooks.
+type webhookRequest struct {
+	SeriesID   string    `json:"seriesId"`
+	RecordTime time.Time `json:"recordTime"`
+}
+
+// webhookResponse is the expected body of the responses of webhooks.
+type webhookResponse struct {
+	Value *float64 `json:"value"`
+}
+
+// requestDataPoint sends a POST request for the data point of the given series at the given time
+// to the given webhook, and returns the value of the data point. The request is signed with the
+// given secret, unless it is empty. It also returns the status code of the response, if the webhook
+// responded.
+//
+// Client errors other than timeouts and rate limits are not retried, as retrying the request would
+// not change the response.
+func requestDataPoint(ctx context.Context, doer httpcli.Doer, secret, seriesID, webhookURL string, recordTime time.Time) (_ float64, statusCode *int, err error) {
+	body, err := json.Marshal(webhookRequest{SeriesID: seriesID, RecordTime: recordTime.UTC()})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, errcode.MakeNonRetryable(errors.Wrap(err, "invalid webhook URL"))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "requesting webhook")
+	}
+	defer resp.Body.Close()
+	statusCode = &resp.StatusCode
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := errors.Errorf("unexpected status code %d", resp.StatusCode)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+			err = errcode.MakeNonRetryable(err)
+		}
+		return 0, statusCode, err
+	}
+
+	var result webhookResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&result); err != nil {
+		return 0, statusCode, errors.Wrap(err, "decoding webhook response")
+	}
+	if result.Value == nil {
+		return 0, statusCode, errors.New("webhook response has no value")
+	}
+	return *result.Value, statusCode, nil
+}
+
+// Sign returns the signature of the given webhook request body for the given secret: the hex
+// encoded HMAC-SHA256 of the body, prefixed with "sha256=".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,127 @@
+package webhookrunner
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func TestWorkHandler(t *testing.T) {
+	recordTime := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+
+	var gotSignature string
+	var gotRequest webhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading request body: %s", err)
+		}
+		if err := json.Unmarshal(body, &gotRequest); err != nil {
+			t.Fatalf("unexpected error decoding request body: %s", err)
+		}
+		gotSignature = r.Header.Get(SignatureHeader)
+		if want := Sign("secret", body); gotSignature != want {
+			t.Errorf("unexpected signature. want=%q have=%q", want, gotSignature)
+		}
+		_, _ = w.Write([]byte(`{"value": 42}`))
+	}))
+	defer server.Close()
+
+	insightsStore := NewMockWebhookStore()
+	h := &workHandler{
+		insightsStore: insightsStore,
+		doer:          http.DefaultClient,
+		secret:        func() string { return "secret" },
+	}
+	if err := h.handle(context.Background(), &Job{SeriesID: "w:one", WebhookURL: server.URL, RecordTime: &recordTime}); err != nil {
+		t.Fatalf("unexpected error handling job: %s", err)
+	}
+
+	if diff := cmp.Diff(webhookRequest{SeriesID: "w:one", RecordTime: recordTime}, gotRequest); diff != "" {
+		t.Errorf("unexpected request (-want +got):\n%s", diff)
+	}
+	if gotSignature == "" {
+		t.Errorf("expected signed request")
+	}
+
+	pointHistory := insightsStore.RecordSeriesPointFunc.History()
+	if len(pointHistory) != 1 {
+		t.Fatalf("unexpected number of recorded points. want=%d have=%d", 1, len(pointHistory))
+	}
+	wantPoint := store.RecordSeriesPointArgs{SeriesID: "w:one", Point: store.SeriesPoint{Time: recordTime, Value: 42}}
+	if diff := cmp.Diff(wantPoint, pointHistory[0].Arg1); diff != "" {
+		t.Errorf("unexpected recorded point (-want +got):\n%s", diff)
+	}
+
+	deliveryHistory := insightsStore.RecordWebhookDeliveryFunc.History()
+	if len(deliveryHistory) != 1 {
+		t.Fatalf("unexpected number of recorded deliveries. want=%d have=%d", 1, len(deliveryHistory))
+	}
+	if delivery := deliveryHistory[0].Arg1; delivery.Err != nil || delivery.StatusCode == nil || *delivery.StatusCode != http.StatusOK {
+		t.Errorf("unexpected recorded delivery: %+v", delivery)
+	}
+}
+
+func TestWorkHandlerFailure(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		statusCode       int
+		body             string
+		wantNonRetryable bool
+	}{
+		{name: "server error", statusCode: http.StatusBadGateway},
+		{name: "rate limited", statusCode: http.StatusTooManyRequests},
+		{name: "client error", statusCode: http.StatusNotFound, wantNonRetryable: true},
+		{name: "no value", statusCode: http.StatusOK, body: `{}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if signature := r.Header.Get(SignatureHeader); signature != "" {
+					t.Errorf("unexpected signature of request without secret: %q", signature)
+				}
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			insightsStore := NewMockWebhookStore()
+			h := &workHandler{
+				insightsStore: insightsStore,
+				doer:          http.DefaultClient,
+				secret:        func() string { return "" },
+			}
+			err := h.handle(context.Background(), &Job{SeriesID: "w:one", WebhookURL: server.URL})
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if nonRetryable := errcode.IsNonRetryable(err); nonRetryable != tc.wantNonRetryable {
+				t.Errorf("unexpected retryability. want non-retryable=%v have=%v", tc.wantNonRetryable, nonRetryable)
+			}
+
+			if value := len(insightsStore.RecordSeriesPointFunc.History()); value != 0 {
+				t.Errorf("unexpected number of recorded points. want=%d have=%d", 0, value)
+			}
+			deliveryHistory := insightsStore.RecordWebhookDeliveryFunc.History()
+			if len(deliveryHistory) != 1 {
+				t.Fatalf("unexpected number of recorded deliveries. want=%d have=%d", 1, len(deliveryHistory))
+			}
+			delivery := deliveryHistory[0].Arg1
+			if !errors.Is(delivery.Err, err) {
+				t.Errorf("unexpected delivery error. want=%q have=%q", err, delivery.Err)
+			}
+			if delivery.StatusCode == nil || *delivery.StatusCode != tc.statusCode {
+				t.Errorf("unexpected delivery status code. want=%d have=%v", tc.statusCode, delivery.StatusCode)
+			}
+		})
+	}
+}
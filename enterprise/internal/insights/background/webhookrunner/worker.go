@@ -0,0 +1,290 @@
+package webhookrunner
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
+	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
+)
+
+// This file contains all the methods required to:
+//
+// 1. Create the webhook runner worker
+// 2. Enqueue jobs for the webhook runner to execute.
+// 3. Dequeue jobs from the webhook runner.
+// 4. Serialize jobs for the webhook runner into the DB.
+//
+
+// NewWorker returns a worker that will fetch the data points of webhook series from their webhooks
+// and insert them into the code insights database.
+func NewWorker(ctx context.Context, workerBaseStore *basestore.Store, insightsStore *store.Store, metrics workerutil.WorkerMetrics) *workerutil.Worker {
+	workerStore := createDBWorkerStore(workerBaseStore)
+
+	options := workerutil.WorkerOptions{
+		Name:              "insights_webhook_runner_worker",
+		NumHandlers:       1,
+		Interval:          5 * time.Second,
+		HeartbeatInterval: 15 * time.Second,
+		Metrics:           metrics,
+	}
+
+	// Failed requests are retried by the worker, and responses describe a single data point, so
+	// unlike httpcli.ExternalDoer the client neither retries nor caches requests.
+	doer, err := httpcli.NewFactory(
+		httpcli.NewMiddleware(httpcli.ContextErrorMiddleware),
+		httpcli.NewTimeoutOpt(requestTimeout),
+		httpcli.ExternalTransportOpt,
+		httpcli.TracedTransportOpt,
+	).Doer()
+	if err != nil {
+		panic("insights: failed to create the webhook runner HTTP client. This should not happen: " + err.Error())
+	}
+
+	return dbworker.NewWorker(ctx, workerStore, &workHandler{
+		workerBaseStore: workerBaseStore,
+		insightsStore:   insightsStore,
+		doer:            doer,
+		secret: func() string {
+			return conf.Get().InsightsWebhookSecret
+		},
+	}, options)
+}
+
+// NewResetter returns a resetter that will reset pending webhook runner jobs if they take too long
+// to complete.
+func NewResetter(ctx context.Context, workerBaseStore *basestore.Store, metrics dbworker.ResetterMetrics) *dbworker.Resetter {
+	workerStore := createDBWorkerStore(workerBaseStore)
+	options := dbworker.ResetterOptions{
+		Name:     "insights_webhook_runner_worker_resetter",
+		Interval: 1 * time.Minute,
+		Metrics:  metrics,
+	}
+	return dbworker.NewResetter(workerStore, options)
+}
+
+var workerStoreOptions = dbworkerstore.Options{
+	Name:              "insights_webhook_runner_jobs_store",
+	TableName:         "insights_webhook_runner_jobs",
+	ColumnExpressions: jobsColumns,
+	Scan:              scanJobs,
+
+	// Requests to webhooks time out after requestTimeout. Failed requests are retried after a
+	// minute, which gives webhooks that are briefly unavailable a chance to recover. If 5 attempts
+	// fail, the data point is not recorded.
+	StalledMaxAge:     60 * time.Second,
+	RetryAfter:        1 * time.Minute,
+	MaxNumRetries:     5,
+	OrderByExpression: sqlf.Sprintf("id"),
+}
+
+// createDBWorkerStore creates the dbworker store for the webhook runner worker.
+//
+// See internal/workerutil/dbworker for more information about dbworkers.
+func createDBWorkerStore(s *basestore.Store) dbworkerstore.Store {
+	return dbworkerstore.New(s.Handle(), workerStoreOptions)
+}
+
+// EnqueueJob enqueues a job for the webhook runner worker to execute later. If the job has an
+// idempotency key that was used within IdempotencyWindow, the ID of the previously enqueued job is
+// returned instead.
+func EnqueueJob(ctx context.Context, workerBaseStore *basestore.Store, job *Job) (id int, err error) {
+	if job.IdempotencyKey == "" {
+		return insertJob(ctx, workerBaseStore, job)
+	}
+
+	id, _, err = dbworkerstore.EnqueueOnce(ctx, workerBaseStore, "insights_webhook_runner_jobs", job.IdempotencyKey, IdempotencyWindow, func(tx *basestore.Store) (int, error) {
+		return insertJob(ctx, tx, job)
+	})
+	return id, err
+}
+
+// IdempotencyWindow is the period during which a job enqueued with an idempotency key prevents
+// jobs with the same key from being enqueued again.
+const IdempotencyWindow = 6 * time.Hour
+
+func insertJob(ctx context.Context, workerBaseStore *basestore.Store, job *Job) (id int, err error) {
+	id, _, err = basestore.ScanFirstInt(workerBaseStore.Query(
+		ctx,
+		sqlf.Sprintf(
+			enqueueJobFmtStr,
+			job.SeriesID,
+			job.WebhookURL,
+			job.RecordTime,
+			job.State,
+			job.ProcessAfter,
+		),
+	))
+	return
+}
+
+const enqueueJobFmtStr = `
+-- source: enterprise/internal/insights/background/webhookrunner/worker.go:EnqueueJob
+INSERT INTO insights_webhook_runner_jobs (
+	series_id,
+	webhook_url,
+	record_time,
+	state,
+	process_after
+) VALUES (%s, %s, %s, %s, %s)
+RETURNING id
+`
+
+// DeleteQueuedJobs deletes the jobs of the given series that are waiting to be executed, i.e. that
+// are queued or will be retried, and returns the number of deleted jobs.
+func DeleteQueuedJobs(ctx context.Context, workerBaseStore *basestore.Store, seriesIDs []string) (int, error) {
+	if len(seriesIDs) == 0 {
+		return 0, nil
+	}
+	count, _, err := basestore.ScanFirstInt(workerBaseStore.Query(ctx, sqlf.Sprintf(deleteQueuedJobsFmtStr, pq.Array(seriesIDs))))
+	return count, err
+}
+
+const deleteQueuedJobsFmtStr = `
+-- source: enterprise/internal/insights/background/webhookrunner/worker.go:DeleteQueuedJobs
+WITH deleted AS (
+	DELETE FROM insights_webhook_runner_jobs
+	WHERE (state = 'queued' OR state = 'errored') AND series_id = ANY(%s)
+	RETURNING 1
+) SELECT count(*) FROM deleted
+`
+
+func dequeueJob(ctx context.Context, workerBaseStore *basestore.Store, recordID int) (*Job, error) {
+	rows, err := workerBaseStore.Query(ctx, sqlf.Sprintf(dequeueJobFmtStr, recordID))
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := doScanJobs(rows, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) != 1 {
+		return nil, errors.Errorf("expected 1 job to dequeue, found %v", len(jobs))
+	}
+	return jobs[0], nil
+}
+
+const dequeueJobFmtStr = `
+-- source: enterprise/internal/insights/background/webhookrunner/worker.go:dequeueJob
+SELECT
+	series_id,
+	webhook_url,
+	record_time,
+	id,
+	state,
+	failure_message,
+	started_at,
+	finished_at,
+	process_after,
+	num_resets,
+	num_failures,
+	execution_logs
+FROM insights_webhook_runner_jobs
+WHERE id = %s;
+`
+
+// Job represents a single job for the webhook runner worker to perform. When enqueued, it is
+// stored in the insights_webhook_runner_jobs table - then the worker dequeues it by reading it
+// from that table.
+//
+// See internal/workerutil/dbworker for more information about dbworkers.
+type Job struct {
+	// Webhook runner fields.
+	SeriesID   string
+	WebhookURL string
+	RecordTime *time.Time // If non-nil, record the data point at this time instead of the time at which the webhook responded.
+
+	// IdempotencyKey, if set, makes EnqueueJob a no-op returning the existing job ID when a job
+	// was already enqueued with the same key within IdempotencyWindow. It is not persisted on the
+	// job itself.
+	IdempotencyKey string
+
+	// Standard/required dbworker fields. If enqueuing a job, these may all be zero values except State.
+	ID             int
+	State          string // If enqueing a job, set to "queued"
+	FailureMessage *string
+	StartedAt      *time.Time
+	FinishedAt     *time.Time
+	ProcessAfter   *time.Time
+	NumResets      int32
+	NumFailures    int32
+	ExecutionLogs  []workerutil.ExecutionLogEntry
+}
+
+// Implements the internal/workerutil.Record interface, used by the work handler to locate the job
+// once executing (see work_handler.go:Handle).
+func (j *Job) RecordID() int {
+	return j.ID
+}
+
+func scanJobs(rows *sql.Rows, err error) (workerutil.Record, bool, error) {
+	records, err := doScanJobs(rows, err)
+	if err != nil {
+		return &Job{}, false, err
+	}
+	return records[0], true, nil
+}
+
+func doScanJobs(rows *sql.Rows, err error) ([]*Job, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+	var jobs []*Job
+	for rows.Next() {
+		j := &Job{}
+		if err := rows.Scan(
+			// Webhook runner fields.
+			&j.SeriesID,
+			&j.WebhookURL,
+			&j.RecordTime,
+
+			// Standard/required dbworker fields.
+			&j.ID,
+			&j.State,
+			&j.FailureMessage,
+			&j.StartedAt,
+			&j.FinishedAt,
+			&j.ProcessAfter,
+			&j.NumResets,
+			&j.NumFailures,
+			pq.Array(&j.ExecutionLogs),
+		); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Rows.Err will report the last error encountered by Rows.Scan.
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+var jobsColumns = []*sqlf.Query{
+	sqlf.Sprintf("insights_webhook_runner_jobs.series_id"),
+	sqlf.Sprintf("insights_webhook_runner_jobs.webhook_url"),
+	sqlf.Sprintf("insights_webhook_runner_jobs.record_time"),
+	sqlf.Sprintf("id"),
+	sqlf.Sprintf("state"),
+	sqlf.Sprintf("failure_message"),
+	sqlf.Sprintf("started_at"),
+	sqlf.Sprintf("finished_at"),
+	sqlf.Sprintf("process_after"),
+	sqlf.Sprintf("num_resets"),
+	sqlf.Sprintf("num_failures"),
+	sqlf.Sprintf("execution_logs"),
+}
@@ -0,0 +1,76 @@
+package discovery
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// SiteRecordingCalendar returns the recording calendar configured by the insights.recording site
+// settings.
+func SiteRecordingCalendar() (insights.RecordingCalendar, error) {
+	c := conf.Get()
+	return parseRecordingCalendar(c.InsightsRecordingTimeZone, c.InsightsRecordingWeekendDays, c.InsightsRecordingHolidays)
+}
+
+// ValidateRecordingCalendar is a site configuration validator which reports invalid
+// insights.recording site settings.
+func ValidateRecordingCalendar(c conf.Unified) conf.Problems {
+	if _, err := parseRecordingCalendar(c.InsightsRecordingTimeZone, c.InsightsRecordingWeekendDays, c.InsightsRecordingHolidays); err != nil {
+		return conf.NewSiteProblems(err.Error())
+	}
+	return nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+	"sunday":    time.Sunday,
+}
+
+// parseRecordingCalendar returns the recording calendar of the given time zone, weekend days and
+// holidays. Weekend days default to Saturday and Sunday if nil.
+func parseRecordingCalendar(timeZone string, weekendDays, holidays []string) (insights.RecordingCalendar, error) {
+	var calendar insights.RecordingCalendar
+	if timeZone != "" {
+		location, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return insights.RecordingCalendar{}, errors.Wrapf(err, "invalid insights.recording.timeZone %q", timeZone)
+		}
+		calendar.Location = location
+	}
+
+	if weekendDays == nil {
+		weekendDays = []string{"saturday", "sunday"}
+	}
+	seen := map[time.Weekday]struct{}{}
+	for _, name := range weekendDays {
+		day, ok := weekdays[strings.ToLower(name)]
+		if !ok {
+			return insights.RecordingCalendar{}, errors.Errorf("invalid insights.recording.weekendDays: %q is not a day of the week", name)
+		}
+		if _, ok := seen[day]; !ok {
+			seen[day] = struct{}{}
+			calendar.WeekendDays = append(calendar.WeekendDays, day)
+		}
+	}
+	if len(calendar.WeekendDays) == len(weekdays) {
+		return insights.RecordingCalendar{}, errors.New("invalid insights.recording.weekendDays: there must be at least one business day per week")
+	}
+
+	for _, holiday := range holidays {
+		if _, err := time.Parse("2006-01-02", holiday); err != nil {
+			return insights.RecordingCalendar{}, errors.Errorf("invalid insights.recording.holidays: %q is not a date of the format YYYY-MM-DD", holiday)
+		}
+		calendar.Holidays = append(calendar.Holidays, holiday)
+	}
+	return calendar, nil
+}
@@ -0,0 +1,51 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRecordingCalendar(t *testing.T) {
+	calendar, err := parseRecordingCalendar("", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error parsing default calendar: %s", err)
+	}
+	if calendar.Location != nil {
+		t.Errorf("unexpected location of default calendar. want=UTC have=%s", calendar.Location)
+	}
+	if diff := cmp.Diff([]time.Weekday{time.Saturday, time.Sunday}, calendar.WeekendDays); diff != "" {
+		t.Errorf("unexpected weekend days of default calendar (-want +got):\n%s", diff)
+	}
+
+	calendar, err = parseRecordingCalendar("UTC", []string{"Friday", "saturday", "friday"}, []string{"2021-12-24"})
+	if err != nil {
+		t.Fatalf("unexpected error parsing calendar: %s", err)
+	}
+	if diff := cmp.Diff([]time.Weekday{time.Friday, time.Saturday}, calendar.WeekendDays); diff != "" {
+		t.Errorf("unexpected weekend days (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"2021-12-24"}, calendar.Holidays); diff != "" {
+		t.Errorf("unexpected holidays (-want +got):\n%s", diff)
+	}
+
+	if calendar, err = parseRecordingCalendar("", []string{}, nil); err != nil || len(calendar.WeekendDays) != 0 {
+		t.Errorf("unexpected calendar without weekend days. want no weekend days have=%v (error %v)", calendar.WeekendDays, err)
+	}
+
+	for name, args := range map[string]struct {
+		timeZone    string
+		weekendDays []string
+		holidays    []string
+	}{
+		"time zone":       {timeZone: "Mars/Olympus_Mons"},
+		"weekend day":     {weekendDays: []string{"caturday"}},
+		"no business day": {weekendDays: []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}},
+		"holiday":         {holidays: []string{"24/12/2021"}},
+	} {
+		if _, err := parseRecordingCalendar(args.timeZone, args.weekendDays, args.holidays); err == nil {
+			t.Errorf("expected an error parsing calendar with invalid %s", name)
+		}
+	}
+}
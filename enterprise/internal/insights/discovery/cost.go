@@ -0,0 +1,139 @@
+package discovery
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/insights/priority"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+// RepoCounter is a subset of the API exposed by the database.Repos() store.
+type RepoCounter interface {
+	Count(ctx context.Context, opt database.ReposListOptions) (int, error)
+}
+
+// CostEstimator estimates the cost of the searches of insight series from the shape of their search
+// queries and the number of repositories they search.
+//
+// Repository counts are cached for the lifetime of the estimator, so a new estimator should be used
+// for every pass over the insights of the instance.
+type CostEstimator struct {
+	repos RepoCounter
+
+	totalRepos *int
+	repoCounts map[string]int
+}
+
+// NewCostEstimator returns a new cost estimator that counts repositories with the given store.
+func NewCostEstimator(repos RepoCounter) *CostEstimator {
+	return &CostEstimator{repos: repos, repoCounts: map[string]int{}}
+}
+
+// SeriesCost estimates the cost of searching for the current data point of the given series.
+func (e *CostEstimator) SeriesCost(ctx context.Context, series insights.TimeSeries) (priority.Cost, error) {
+	return e.queryCost(ctx, series, false)
+}
+
+// PinnedRepoCost estimates the cost of searching a single repository at a historical revision for a
+// data point of the given series.
+func (e *CostEstimator) PinnedRepoCost(ctx context.Context, series insights.TimeSeries) (priority.Cost, error) {
+	return e.queryCost(ctx, series, true)
+}
+
+func (e *CostEstimator) queryCost(ctx context.Context, series insights.TimeSeries, pinned bool) (priority.Cost, error) {
+	// Series generated from capture groups are searched for as regular expressions.
+	searchType := query.SearchTypeLiteral
+	if series.GeneratedFromCaptureGroups {
+		searchType = query.SearchTypeRegex
+	}
+	plan, err := ParseSearchQuery(ScopedQuery(series), searchType)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid search query %q", series.Query)
+	}
+	totalRepos, err := e.countTotalRepos(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// Every operand of an OR expression is searched for separately.
+	var cost priority.Cost
+	for _, basic := range plan {
+		shape := QueryShapeOf(basic)
+		shape.TotalRepos = totalRepos
+		switch {
+		case pinned:
+			shape.Unindexed = true
+			shape.Repos = 1
+		case len(series.Repositories) > 0:
+			shape.Repos = len(series.Repositories)
+		default:
+			if shape.Repos, err = e.countRepos(ctx, basic); err != nil {
+				return 0, err
+			}
+		}
+		cost += priority.EstimateCost(shape)
+	}
+	return cost, nil
+}
+
+// QueryShapeOf returns the shape of the given search query. The number of repositories the query
+// searches is not known from the query alone, and is left unset.
+func QueryShapeOf(basic query.Basic) priority.QueryShape {
+	var shape priority.QueryShape
+	switch {
+	case basic.IsStructural():
+		shape.PatternType = priority.Structural
+	case basic.IsRegexp():
+		shape.PatternType = priority.Regexp
+	}
+	shape.Unindexed = basic.Index() == query.No
+	basic.VisitParameter(query.FieldType, func(value string, negated bool, _ query.Annotation) {
+		if !negated && (value == "commit" || value == "diff") {
+			shape.History = true
+		}
+	})
+	return shape
+}
+
+// countRepos returns the number of repositories matched by the repo: filters of the given search
+// query, or zero if the query searches all repositories.
+func (e *CostEstimator) countRepos(ctx context.Context, basic query.Basic) (int, error) {
+	var patterns []string
+	basic.VisitParameter(query.FieldRepo, func(value string, negated bool, _ query.Annotation) {
+		if !negated {
+			patterns = append(patterns, value)
+		}
+	})
+	if len(patterns) == 0 {
+		return 0, nil
+	}
+
+	key := strings.Join(patterns, " ")
+	if count, ok := e.repoCounts[key]; ok {
+		return count, nil
+	}
+	count, err := e.repos.Count(ctx, database.ReposListOptions{IncludePatterns: patterns})
+	if err != nil {
+		return 0, errors.Wrap(err, "RepoCounter.Count")
+	}
+	e.repoCounts[key] = count
+	return count, nil
+}
+
+// countTotalRepos returns the number of repositories on the instance.
+func (e *CostEstimator) countTotalRepos(ctx context.Context) (int, error) {
+	if e.totalRepos != nil {
+		return *e.totalRepos, nil
+	}
+	count, err := e.repos.Count(ctx, database.ReposListOptions{})
+	if err != nil {
+		return 0, errors.Wrap(err, "RepoCounter.Count")
+	}
+	e.totalRepos = &count
+	return count, nil
+}
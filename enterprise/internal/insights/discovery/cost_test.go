@@ -0,0 +1,102 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/insights/priority"
+)
+
+func TestCostEstimator(t *testing.T) {
+	ctx := context.Background()
+	repoCounter := NewMockRepoCounter()
+	repoCounter.CountFunc.SetDefaultHook(func(ctx context.Context, opt database.ReposListOptions) (int, error) {
+		if len(opt.IncludePatterns) == 0 {
+			return 1000, nil
+		}
+		return 100, nil
+	})
+	estimator := NewCostEstimator(repoCounter)
+
+	tests := []struct {
+		name   string
+		series insights.TimeSeries
+		pinned bool
+		want   priority.Cost
+	}{
+		{
+			name:   "all repositories",
+			series: insights.TimeSeries{Query: "errorf"},
+			want:   priority.Indexed,
+		},
+		{
+			name:   "structural",
+			series: insights.TimeSeries{Query: "fmt.Errorf(:[args]) patterntype:structural"},
+			want:   10 * priority.Indexed,
+		},
+		{
+			name:   "capture groups",
+			series: insights.TimeSeries{Query: "errorf\\((.*)\\)", GeneratedFromCaptureGroups: true},
+			want:   2 * priority.Indexed,
+		},
+		{
+			name:   "commit history",
+			series: insights.TimeSeries{Query: "type:commit errorf"},
+			want:   10 * priority.Indexed,
+		},
+		{
+			name:   "unindexed",
+			series: insights.TimeSeries{Query: "index:no errorf"},
+			want:   priority.Unindexed,
+		},
+		{
+			name:   "repo filter",
+			series: insights.TimeSeries{Query: "repo:sourcegraph errorf"},
+			want:   priority.Indexed / 10,
+		},
+		{
+			name:   "repository pattern",
+			series: insights.TimeSeries{Query: "errorf", RepositoryPattern: "sourcegraph"},
+			want:   priority.Indexed / 10,
+		},
+		{
+			name:   "repositories",
+			series: insights.TimeSeries{Query: "errorf", Repositories: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}},
+			want:   priority.Indexed / 100,
+		},
+		{
+			name:   "or expression",
+			series: insights.TimeSeries{Query: "errorf or panic"},
+			want:   2 * priority.Indexed,
+		},
+		{
+			name:   "pinned repository",
+			series: insights.TimeSeries{Query: "errorf"},
+			pinned: true,
+			want:   priority.Unindexed / 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate := estimator.SeriesCost
+			if tt.pinned {
+				estimate = estimator.PinnedRepoCost
+			}
+			cost, err := estimate(ctx, tt.series)
+			if err != nil {
+				t.Fatalf("unexpected error estimating cost: %s", err)
+			}
+			if cost != tt.want {
+				t.Errorf("unexpected cost. want=%d have=%d", tt.want, cost)
+			}
+		})
+	}
+
+	// Repository counts are cached: one count of all repositories, and one of the repositories
+	// matching sourcegraph for both the repo: filter and the repository pattern.
+	if have := len(repoCounter.CountFunc.History()); have != 2 {
+		t.Errorf("unexpected number of repository counts. want=%d have=%d", 2, have)
+	}
+}
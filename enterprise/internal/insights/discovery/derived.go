@@ -0,0 +1,107 @@
+package discovery
+
+import (
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/insights/expression"
+)
+
+// resolveExpressions resolves the expressions of the derived series of the given insights, which
+// refer to the series of their insight by position, to refer to them by series ID. Derived series
+// may refer to other derived series, whose expressions are resolved first. Expressions that cannot
+// be resolved, e.g. because they refer to a series that does not exist or to themselves, are left
+// as they are and reported as invalid by ValidateSeries.
+func resolveExpressions(discovered []insights.SearchInsight) {
+	for _, insight := range discovered {
+		resolveInsightExpressions(insight.Series)
+	}
+}
+
+func resolveInsightExpressions(series []insights.TimeSeries) {
+	const (
+		pending = iota
+		resolved
+		unresolvable
+	)
+	states := make([]int, len(series))
+	exprs := make([]*expression.Expr, len(series))
+	for i, s := range series {
+		if s.Expression == "" {
+			states[i] = resolved
+			continue
+		}
+		expr, err := expression.Parse(s.Expression)
+		if err != nil {
+			states[i] = unresolvable
+			continue
+		}
+		exprs[i] = expr
+	}
+
+	// Every pass resolves the expressions whose operands are all resolved, until no more can be.
+	for progress := true; progress; {
+		progress = false
+		seriesIDs := make([]string, len(series))
+		for i := range series {
+			if states[i] == resolved {
+				seriesIDs[i] = Encode(series[i])
+			}
+		}
+
+		for i, expr := range exprs {
+			if states[i] != pending {
+				continue
+			}
+			ready := true
+			for _, operand := range expr.Operands() {
+				if operand.Position == 0 {
+					continue
+				}
+				if operand.Position > len(series) || states[operand.Position-1] == unresolvable {
+					states[i] = unresolvable
+				}
+				if operand.Position > len(series) || states[operand.Position-1] != resolved {
+					ready = false
+				}
+			}
+			if !ready {
+				continue
+			}
+
+			resolvedExpr, err := expr.Resolve(seriesIDs)
+			if err != nil {
+				states[i] = unresolvable
+				continue
+			}
+			series[i].Expression = resolvedExpr.String()
+			states[i] = resolved
+			progress = true
+		}
+	}
+}
+
+// validateExpression returns an error if the expression of the given derived series is invalid,
+// has not been resolved, or refers to series split into one series per label, i.e. series
+// generated from capture groups or grouped by attribution.
+func validateExpression(series insights.TimeSeries) error {
+	if series.Query != "" || series.Webhook != "" || series.GeneratedFromCaptureGroups {
+		return errors.Errorf("invalid derived series %q: derived series cannot have a search query or a webhook", series.Expression)
+	}
+	expr, err := expression.Parse(series.Expression)
+	if err != nil {
+		return err
+	}
+	for _, operand := range expr.Operands() {
+		if operand.Position != 0 {
+			return errors.Errorf("invalid derived series %q: %s does not refer to a valid series of the insight", series.Expression, operand)
+		}
+		if IsCaptureGroupSeries(operand.SeriesID) {
+			return errors.Errorf("invalid derived series %q: derived series cannot refer to series generated from capture groups", series.Expression)
+		}
+		if GroupByOf(operand.SeriesID) != "" {
+			return errors.Errorf("invalid derived series %q: derived series cannot refer to series grouped by attribution", series.Expression)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestResolveInsightExpressions(t *testing.T) {
+	todos := insights.TimeSeries{Name: "TODOs", Query: "TODO"}
+	lines := insights.TimeSeries{Name: "lines", Webhook: "https://example.com/lines"}
+	series := []insights.TimeSeries{
+		todos,
+		lines,
+		{Name: "derived from derived", Expression: "$4 - 1"},
+		{Name: "TODOs per KLOC", Expression: "$1/$2 * 1000"},
+		{Name: "self", Expression: "$5 + 1"},
+		{Name: "out of range", Expression: "$9"},
+		{Name: "derived from invalid", Expression: "$6 * 2"},
+		{Name: "syntax error", Expression: "$1 +"},
+	}
+	resolveInsightExpressions(series)
+
+	perKLOC := "(${" + Encode(todos) + "} / ${" + Encode(lines) + "}) * 1000"
+	want := []string{
+		"",
+		"",
+		"${" + Encode(insights.TimeSeries{Expression: perKLOC}) + "} - 1",
+		perKLOC,
+		"$5 + 1",
+		"$9",
+		"$6 * 2",
+		"$1 +",
+	}
+	var have []string
+	for _, s := range series {
+		have = append(have, s.Expression)
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Errorf("unexpected expressions (-want +got):\n%s", diff)
+	}
+
+	for _, s := range series[2:] {
+		err := ValidateSeries(s)
+		if valid := s.Name == "TODOs per KLOC" || s.Name == "derived from derived"; valid != (err == nil) {
+			t.Errorf("unexpected validation of series %q. want valid=%v have error=%v", s.Name, valid, err)
+		}
+	}
+}
+
+func TestValidateExpression(t *testing.T) {
+	for _, series := range []insights.TimeSeries{
+		{Expression: "${s:A} + 1", Query: "TODO"},
+		{Expression: "${s:A} + 1", GeneratedFromCaptureGroups: true},
+		{Expression: "${c:A} + 1"},
+	} {
+		if err := validateExpression(series); err == nil {
+			t.Errorf("expected an error validating series %+v", series)
+		}
+	}
+	if err := validateExpression(insights.TimeSeries{Expression: "${s:A} / ${w:B}"}); err != nil {
+		t.Errorf("unexpected error validating series: %s", err)
+	}
+}
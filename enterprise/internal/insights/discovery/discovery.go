@@ -2,6 +2,8 @@ package discovery
 
 import (
 	"context"
+	"reflect"
+	"sort"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
 
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
 
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
@@ -29,18 +32,95 @@ type SettingStore interface {
 	GetLastestSchemaSettings(context.Context, api.SettingsSubject) (*schema.Settings, error)
 }
 
+// InsightStore is a subset of the API exposed by the store.InsightStore (only the subset used by
+// discovery.)
+type InsightStore interface {
+	Get(ctx context.Context, args store.InsightQueryArgs) ([]types.InsightViewSeries, error)
+}
+
+// DashboardStore is a subset of the API exposed by the store.DashboardStore (only the subset used
+// by discovery.)
+type DashboardStore interface {
+	GetDashboards(ctx context.Context, args store.DashboardQueryArgs) ([]types.Dashboard, error)
+	CreateDashboard(ctx context.Context, args store.CreateDashboardArgs) (int, error)
+	UpdateDashboard(ctx context.Context, args store.UpdateDashboardArgs) error
+	DeleteDashboard(ctx context.Context, id int) error
+}
+
 // InsightFilterArgs contains arguments that will filter out insights when discovered if matched.
 type InsightFilterArgs struct {
 	Ids []string
+
+	// Namespaces, if non-empty, restricts the discovered insights to the insights of the given
+	// namespaces. Insights shown to users must be restricted to the namespaces of the user.
+	Namespaces []insights.Namespace
+
+	// DashboardID, if non-zero, restricts the discovered insights to the insights of this
+	// dashboard. Insights that have not been migrated to the database are not on any dashboard.
+	DashboardID int
+
+	// SeriesIDs, if non-empty, restricts the series of the discovered insights to the series with
+	// the given series IDs (see Encode). Insights without any of these series are not discovered.
+	SeriesIDs []string
 }
 
-// Discover uses the given settings store to look for insights in the global user settings.
-func Discover(ctx context.Context, settingStore SettingStore, loader insights.Loader, args InsightFilterArgs) ([]insights.SearchInsight, error) {
-	discovered, err := discoverAll(ctx, settingStore, loader)
-	if err != nil {
-		return []insights.SearchInsight{}, err
+// Unfiltered reports whether the arguments discover every insight.
+func (a InsightFilterArgs) Unfiltered() bool {
+	return len(a.Ids) == 0 && len(a.Namespaces) == 0 && a.DashboardID == 0 && len(a.SeriesIDs) == 0
+}
+
+// Discover returns the insights defined in the database. Insights defined in the global user
+// settings or in extension settings that have not been migrated to the database yet (see
+// NewMigrateSettingInsightsJob) are discovered from the settings as a deprecated fallback, followed
+// by the insights of the sources added with RegisterSource.
+//
+// 🚨 SECURITY: Insights of all namespaces are discovered unless args restricts the namespaces.
+func Discover(ctx context.Context, insightStore InsightStore, settingStore SettingStore, loader insights.Loader, args InsightFilterArgs) ([]insights.SearchInsight, error) {
+	sources := append([]Source{
+		NewDatabaseSource(insightStore),
+		NewSettingsSource(settingStore, loader),
+	}, registeredSources()...)
+	return DiscoverFrom(ctx, sources, args)
+}
+
+// convertFromViewSeries groups the given insight view series, ordered by view, into insights.
+func convertFromViewSeries(viewSeries []types.InsightViewSeries) []insights.SearchInsight {
+	converted := make([]insights.SearchInsight, 0)
+	for _, s := range viewSeries {
+		if len(converted) == 0 || converted[len(converted)-1].ID != s.UniqueID {
+			var namespace insights.Namespace
+			if s.UserID != nil {
+				namespace.UserID = *s.UserID
+			}
+			if s.OrgID != nil {
+				namespace.OrgID = *s.OrgID
+			}
+			converted = append(converted, insights.SearchInsight{
+				ID:          s.UniqueID,
+				Title:       s.Title,
+				Description: s.Description,
+				Namespace:   namespace,
+			})
+		}
+		insight := &converted[len(converted)-1]
+		insight.Series = append(insight.Series, insights.TimeSeries{
+			Name:       s.Label,
+			Stroke:     s.Stroke,
+			Query:      s.Query,
+			Interval:   insights.RecordingInterval(s.RecordingInterval),
+			Webhook:    s.Webhook,
+			Expression: s.Expression,
+			Namespace:  insight.Namespace,
+
+			GeneratedFromCaptureGroups: IsCaptureGroupSeries(s.SeriesID),
+			GroupBy:                    GroupByOf(s.SeriesID),
+			BusinessDaysOnly:           s.BusinessDaysOnly,
+
+			Repositories:      s.Repositories,
+			RepositoryPattern: s.RepositoryPattern,
+		})
 	}
-	return applyFilters(discovered, args), nil
+	return converted
 }
 
 // discoverIntegrated will load any insights that are integrated (meaning backend capable) from the extensions settings
@@ -64,8 +144,20 @@ func discoverAll(ctx context.Context, settingStore SettingStore, loader insights
 	if err != nil {
 		return nil, err
 	}
+	for _, insight := range integrated {
+		// Series are recorded separately for each namespace, and search the repositories of their insight.
+		for i := range insight.Series {
+			insight.Series[i].Namespace = insight.Namespace
+			insight.Series[i].Repositories = insight.Repositories
+			insight.Series[i].RepositoryPattern = insight.RepositoryPattern
+		}
+	}
 
-	return append(results, integrated...), nil
+	results = append(results, integrated...)
+	// Series IDs depend on the namespace and repository scope of series, so the expressions of
+	// derived series are resolved once those are known.
+	resolveExpressions(results)
+	return results, nil
 }
 
 // convertFromBackendInsight is an adapter method that will transform the 'backend' insight schema to the schema that is
@@ -77,10 +169,22 @@ func convertFromBackendInsight(backendInsights []*schema.Insight) []insights.Sea
 		var temp insights.SearchInsight
 		temp.Title = backendInsight.Title
 		temp.Description = backendInsight.Description
+		temp.Repositories = backendInsight.Repositories
+		temp.RepositoryPattern = backendInsight.RepositoryPattern
 		for _, series := range backendInsight.Series {
 			temp.Series = append(temp.Series, insights.TimeSeries{
-				Name:  series.Label,
-				Query: series.Search,
+				Name:       series.Label,
+				Query:      series.Search,
+				Interval:   insights.RecordingInterval(series.Interval),
+				Webhook:    series.Webhook,
+				Expression: series.Expression,
+
+				GeneratedFromCaptureGroups: series.GeneratedFromCaptureGroups,
+				GroupBy:                    insights.Attribution(series.GroupBy),
+				BusinessDaysOnly:           series.BusinessDaysOnly,
+
+				Repositories:      backendInsight.Repositories,
+				RepositoryPattern: backendInsight.RepositoryPattern,
 			})
 		}
 		temp.ID = backendInsight.Id
@@ -128,6 +232,43 @@ func filterByIds(ids []string, insight []insights.SearchInsight) []insights.Sear
 	return filtered
 }
 
+func filterByNamespaces(namespaces []insights.Namespace, insight []insights.SearchInsight) []insights.SearchInsight {
+	filtered := make([]insights.SearchInsight, 0)
+	keys := make(map[insights.Namespace]bool)
+	for _, namespace := range namespaces {
+		keys[namespace] = true
+	}
+
+	for _, searchInsight := range insight {
+		if keys[searchInsight.Namespace] {
+			filtered = append(filtered, searchInsight)
+		}
+	}
+	return filtered
+}
+
+func filterBySeriesIDs(seriesIDs []string, insight []insights.SearchInsight) []insights.SearchInsight {
+	filtered := make([]insights.SearchInsight, 0)
+	keys := make(map[string]bool)
+	for _, seriesID := range seriesIDs {
+		keys[seriesID] = true
+	}
+
+	for _, searchInsight := range insight {
+		var series []insights.TimeSeries
+		for _, s := range searchInsight.Series {
+			if keys[Encode(s)] {
+				series = append(series, s)
+			}
+		}
+		if len(series) > 0 {
+			searchInsight.Series = series
+			filtered = append(filtered, searchInsight)
+		}
+	}
+	return filtered
+}
+
 type settingMigrator struct {
 	base     dbutil.DB
 	insights dbutil.DB
@@ -135,8 +276,13 @@ type settingMigrator struct {
 
 // NewMigrateSettingInsightsJob will migrate insights from settings into the database. This is a job that will be
 // deprecated as soon as this functionality is available over an API.
+//
+// Until then, settings remain the source of truth: changes to the definition of an insight in settings are
+// migrated as well, and insights that are removed from settings are removed from the database.
 func NewMigrateSettingInsightsJob(ctx context.Context, base dbutil.DB, insights dbutil.DB) goroutine.BackgroundRoutine {
-	interval := time.Hour
+	// Discovery reads insights from the database once they are migrated, so changes in settings take effect
+	// within this interval.
+	interval := 10 * time.Minute
 	m := settingMigrator{
 		base:     base,
 		insights: insights,
@@ -147,47 +293,136 @@ func NewMigrateSettingInsightsJob(ctx context.Context, base dbutil.DB, insights
 }
 
 func (m *settingMigrator) migrate(ctx context.Context) error {
-	insightStore := store.NewInsightStore(m.insights)
-	loader := insights.NewLoader(m.base)
+	templates, err := insights.NewTemplateLoader(m.base).LoadTemplates(ctx)
+	if err != nil {
+		return errors.Wrap(err, "LoadTemplates")
+	}
+	// Instances of templates are migrated along with the insights of settings, so that instances
+	// are removed once their repository no longer exists or no longer matches their template.
+	instances, err := instantiateTemplates(ctx, database.Repos(m.base), templates)
+	if err != nil {
+		return err
+	}
+	if err := migrateSettingInsights(ctx, store.NewInsightStore(m.insights), database.Settings(m.base), insights.NewLoader(m.base), instances); err != nil {
+		return err
+	}
+	return migrateSettingDashboards(ctx, store.NewDashboardStore(m.insights), insights.NewDashboardLoader(m.base))
+}
 
-	discovered, err := discoverIntegrated(ctx, loader)
+// migrateSettingInsights synchronizes the insights stored in the database with the insights defined in the global
+// user settings and in extension settings, and with the given instances of insight templates.
+func migrateSettingInsights(ctx context.Context, insightStore *store.InsightStore, settingStore SettingStore, loader insights.Loader, instances []insights.SearchInsight) error {
+	discovered, err := discoverAll(ctx, settingStore, loader)
 	if err != nil {
 		return err
 	}
+	discovered = append(discovered, instances...)
 
-	var count, skipped, errors int
+	viewSeries, err := insightStore.Get(ctx, store.InsightQueryArgs{})
+	if err != nil {
+		return err
+	}
+	migrated := map[string]insights.SearchInsight{}
+	for _, insight := range convertFromViewSeries(viewSeries) {
+		migrated[insight.ID] = insight
+	}
+
+	var count, skipped, errors, removed int
+	seen := map[string]struct{}{}
 	for _, d := range discovered {
 		if d.ID == "" {
 			// we need a unique ID, and if for some reason this insight doesn't have one, it can't be migrated.
 			skipped++
 			continue
 		}
-		results, err := insightStore.Get(ctx, store.InsightQueryArgs{
-			UniqueID: d.ID,
-		})
-		if err != nil {
-			return err
+		if len(d.Series) == 0 {
+			// insights are stored as views of their series, so an insight without series can't be migrated.
+			skipped++
+			continue
 		}
-		if len(results) != 0 {
-			// this insight has already been ingested, so let's skip it. Technically this insight could have been edited
-			// but for now we are going to ignore any edits to display settings.
+		if _, ok := seen[d.ID]; ok {
+			// the first definition of an insight wins, in the same way as it does in discovery.
 			skipped++
 			continue
 		}
+		seen[d.ID] = struct{}{}
 
-		err = migrateSeries(ctx, insightStore, d)
-		if err != nil {
+		d = normalizeInsight(d)
+		if existing, ok := migrated[d.ID]; ok && reflect.DeepEqual(normalizeInsight(existing), d) {
+			// this insight has already been ingested and has not changed since.
+			skipped++
+			continue
+		}
+
+		if err := migrateSeries(ctx, insightStore, d); err != nil {
 			// we can't do anything about errors, so we will just skip it and log it
 			errors++
 			log15.Error("error while migrating insight", "error", err)
+			continue
 		}
 		count++
 	}
-	log15.Info("insights settings migration complete", "count", count, "skipped", skipped, "errors", errors)
+
+	for id := range migrated {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		if err := insightStore.DeleteView(ctx, id); err != nil {
+			errors++
+			log15.Error("error while removing insight", "unique_id", id, "error", err)
+			continue
+		}
+		removed++
+	}
+	log15.Info("insights settings migration complete", "count", count, "skipped", skipped, "removed", removed, "errors", errors)
 	return nil
 }
 
-// migrateSeries will attempt to take an insight defined in Sourcegraph settings and migrate it to the database.
+// normalizeInsight returns the given insight as it is stored in the database: only the fields that are migrated are
+// retained, series with the same data are only included once, series are ordered by their series ID, and the default
+// recording interval and the repository scope of series are made canonical.
+func normalizeInsight(from insights.SearchInsight) insights.SearchInsight {
+	normalized := insights.SearchInsight{
+		ID:          from.ID,
+		Title:       from.Title,
+		Description: from.Description,
+		Namespace:   from.Namespace,
+	}
+
+	seen := map[string]struct{}{}
+	for _, series := range from.Series {
+		seriesID := Encode(series)
+		if _, ok := seen[seriesID]; ok {
+			continue
+		}
+		seen[seriesID] = struct{}{}
+
+		if series.Interval == "" {
+			series.Interval = insights.Daily
+		}
+		series.Repositories = normalizeRepositories(series.Repositories)
+		normalized.Series = append(normalized.Series, series)
+	}
+	sort.SliceStable(normalized.Series, func(i, j int) bool {
+		return Encode(normalized.Series[i]) < Encode(normalized.Series[j])
+	})
+
+	return normalized
+}
+
+// normalizeRepositories returns the given repository names sorted, or nil if there are none.
+func normalizeRepositories(repositories []string) []string {
+	if len(repositories) == 0 {
+		return nil
+	}
+	normalized := append([]string(nil), repositories...)
+	sort.Strings(normalized)
+	return normalized
+}
+
+// migrateSeries will attempt to take an insight defined in Sourcegraph settings and migrate it to the database,
+// replacing any previously migrated definition of the insight. The given insight must be normalized. Data series
+// are shared with other insights using the same data.
 func migrateSeries(ctx context.Context, insightStore *store.InsightStore, from insights.SearchInsight) (err error) {
 	tx, err := insightStore.Transact(ctx)
 	if err != nil {
@@ -196,24 +431,34 @@ func migrateSeries(ctx context.Context, insightStore *store.InsightStore, from i
 	defer func() { err = tx.Store.Done(err) }()
 
 	log15.Info("attempting to migrate insight", "unique_id", from.ID)
+	if err := tx.DeleteView(ctx, from.ID); err != nil {
+		return errors.Wrapf(err, "unable to replace insight unique_id: %s", from.ID)
+	}
+
 	series := make([]types.InsightSeries, len(from.Series))
 	metadata := make([]types.InsightViewSeriesMetadata, len(from.Series))
 
 	for i, timeSeries := range from.Series {
-		temp := types.InsightSeries{
-			SeriesID:              Encode(timeSeries),
+		seriesID := Encode(timeSeries)
+		result, err := tx.GetOrCreateSeries(ctx, types.InsightSeries{
+			SeriesID:              seriesID,
 			Query:                 timeSeries.Query,
+			Webhook:               timeSeries.Webhook,
+			Expression:            timeSeries.Expression,
 			RecordingIntervalDays: 1,
-		}
-		result, err := tx.CreateSeries(ctx, temp)
+			Repositories:          timeSeries.Repositories,
+			RepositoryPattern:     timeSeries.RepositoryPattern,
+		})
 		if err != nil {
-			return errors.Wrapf(err, "unable to migrate insight unique_id: %s series_id: %s", from.ID, temp.SeriesID)
+			return errors.Wrapf(err, "unable to migrate insight unique_id: %s series_id: %s", from.ID, seriesID)
 		}
 		series[i] = result
 
 		metadata[i] = types.InsightViewSeriesMetadata{
-			Label:  timeSeries.Name,
-			Stroke: timeSeries.Stroke,
+			Label:             timeSeries.Name,
+			Stroke:            timeSeries.Stroke,
+			RecordingInterval: string(timeSeries.Interval),
+			BusinessDaysOnly:  timeSeries.BusinessDaysOnly,
 		}
 	}
 
@@ -222,6 +467,12 @@ func migrateSeries(ctx context.Context, insightStore *store.InsightStore, from i
 		Description: from.Description,
 		UniqueID:    from.ID,
 	}
+	if from.Namespace.UserID != 0 {
+		view.UserID = &from.Namespace.UserID
+	}
+	if from.Namespace.OrgID != 0 {
+		view.OrgID = &from.Namespace.OrgID
+	}
 
 	view, err = tx.CreateView(ctx, view)
 	if err != nil {
@@ -236,3 +487,125 @@ func migrateSeries(ctx context.Context, insightStore *store.InsightStore, from i
 	}
 	return nil
 }
+
+// migrateSettingDashboards synchronizes the dashboards stored in the database with the dashboards defined in
+// settings. Dashboards are matched by their ID in settings, and are shared with the subject of the settings that
+// define them.
+func migrateSettingDashboards(ctx context.Context, dashboardStore DashboardStore, loader insights.DashboardLoader) error {
+	discovered, err := loader.LoadDashboards(ctx)
+	if err != nil {
+		return err
+	}
+	// Dashboards are loaded in no particular order; order them so that the same definition wins every time.
+	sort.SliceStable(discovered, func(i, j int) bool {
+		if discovered[i].ID != discovered[j].ID {
+			return discovered[i].ID < discovered[j].ID
+		}
+		return discovered[i].Namespace.String() < discovered[j].Namespace.String()
+	})
+
+	existing, err := dashboardStore.GetDashboards(ctx, store.DashboardQueryArgs{})
+	if err != nil {
+		return err
+	}
+	migrated := map[string]types.Dashboard{}
+	for _, dashboard := range existing {
+		if dashboard.UniqueID != nil {
+			migrated[*dashboard.UniqueID] = dashboard
+		}
+	}
+
+	var count, skipped, errors, removed int
+	seen := map[string]struct{}{}
+	for _, d := range discovered {
+		if d.ID == "" {
+			skipped++
+			continue
+		}
+		if _, ok := seen[d.ID]; ok {
+			// the first definition of a dashboard wins, in the same way as it does for insights.
+			skipped++
+			continue
+		}
+		seen[d.ID] = struct{}{}
+
+		insightIDs := d.InsightIDs
+		if insightIDs == nil {
+			insightIDs = []string{}
+		}
+		grant := namespaceDashboardGrant(d.Namespace)
+
+		dashboard, ok := migrated[d.ID]
+		if !ok {
+			uniqueID := d.ID
+			if _, err := dashboardStore.CreateDashboard(ctx, store.CreateDashboardArgs{
+				Title:      d.Title,
+				UniqueID:   &uniqueID,
+				InsightIDs: insightIDs,
+				Grants:     []store.DashboardGrant{grant},
+			}); err != nil {
+				errors++
+				log15.Error("error while migrating dashboard", "unique_id", d.ID, "error", err)
+				continue
+			}
+			count++
+			continue
+		}
+
+		if dashboard.Title == d.Title && reflect.DeepEqual(dashboard.InsightIDs, insightIDs) && dashboardHasOnlyGrant(dashboard, grant) {
+			// this dashboard has already been migrated and has not changed since.
+			skipped++
+			continue
+		}
+		title := d.Title
+		if err := dashboardStore.UpdateDashboard(ctx, store.UpdateDashboardArgs{
+			ID:         dashboard.ID,
+			Title:      &title,
+			InsightIDs: insightIDs,
+			Grants:     []store.DashboardGrant{grant},
+		}); err != nil {
+			errors++
+			log15.Error("error while migrating dashboard", "unique_id", d.ID, "error", err)
+			continue
+		}
+		count++
+	}
+
+	for id, dashboard := range migrated {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		if err := dashboardStore.DeleteDashboard(ctx, dashboard.ID); err != nil {
+			errors++
+			log15.Error("error while removing dashboard", "unique_id", id, "error", err)
+			continue
+		}
+		removed++
+	}
+	log15.Info("insights dashboards migration complete", "count", count, "skipped", skipped, "removed", removed, "errors", errors)
+	return nil
+}
+
+// namespaceDashboardGrant returns the grant of the dashboards defined in the settings of the given namespace.
+func namespaceDashboardGrant(namespace insights.Namespace) store.DashboardGrant {
+	switch {
+	case namespace.UserID != 0:
+		return store.UserDashboardGrant(namespace.UserID)
+	case namespace.OrgID != 0:
+		return store.OrgDashboardGrant(namespace.OrgID)
+	default:
+		return store.GlobalDashboardGrant()
+	}
+}
+
+// dashboardHasOnlyGrant reports whether the given grant is the only grant of the dashboard.
+func dashboardHasOnlyGrant(dashboard types.Dashboard, grant store.DashboardGrant) bool {
+	switch {
+	case grant.UserID != nil:
+		return !dashboard.GlobalGrant && len(dashboard.OrgIDs) == 0 && len(dashboard.UserIDs) == 1 && dashboard.UserIDs[0] == *grant.UserID
+	case grant.OrgID != nil:
+		return !dashboard.GlobalGrant && len(dashboard.UserIDs) == 0 && len(dashboard.OrgIDs) == 1 && dashboard.OrgIDs[0] == *grant.OrgID
+	default:
+		return dashboard.GlobalGrant && len(dashboard.UserIDs) == 0 && len(dashboard.OrgIDs) == 0
+	}
+}
@@ -6,8 +6,13 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/insights"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hexops/autogold"
 
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -59,9 +64,10 @@ func TestDiscover(t *testing.T) {
 	ctx := context.Background()
 
 	loader := insights.NewMockLoader()
+	insightStore := NewMockInsightStore()
 
 	t.Run("test_with_no_id_filter", func(t *testing.T) {
-		discovered, err := Discover(ctx, settingStore, loader, InsightFilterArgs{})
+		discovered, err := Discover(ctx, insightStore, settingStore, loader, InsightFilterArgs{})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -100,7 +106,7 @@ func TestDiscover(t *testing.T) {
 	})
 
 	t.Run("test_with_id_filter", func(t *testing.T) {
-		discovered, err := Discover(ctx, settingStore, loader, InsightFilterArgs{Ids: []string{"1"}})
+		discovered, err := Discover(ctx, insightStore, settingStore, loader, InsightFilterArgs{Ids: []string{"1"}})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -131,7 +137,7 @@ func TestDiscover(t *testing.T) {
 		}}
 
 		loader.LoadAllFunc.SetDefaultReturn(integrated, nil)
-		discovered, err := Discover(ctx, settingStore, loader, InsightFilterArgs{Ids: []string{"1"}})
+		discovered, err := Discover(ctx, insightStore, settingStore, loader, InsightFilterArgs{Ids: []string{"1"}})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -154,6 +160,214 @@ func TestDiscover(t *testing.T) {
 	})
 }
 
+func TestDiscoverFromDatabase(t *testing.T) {
+	settingStore := NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultReturn(settingsExample, nil)
+	insightStore := NewMockInsightStore()
+	insightStore.GetFunc.SetDefaultReturn([]types.InsightViewSeries{
+		{
+			UniqueID:          "1",
+			SeriesID:          "s:1",
+			Title:             "fmt usage (migrated)",
+			Description:       "errors.Errorf usage",
+			Query:             "errorf",
+			Label:             "errors.Errorf",
+			Stroke:            "blue",
+			RecordingInterval: "weekly",
+		},
+		{
+			UniqueID:          "9",
+			SeriesID:          "c:9",
+			Title:             "go versions",
+			Query:             `go (\d\.\d+)`,
+			Label:             "go",
+			RecordingInterval: "daily",
+		},
+	}, nil)
+	ctx := context.Background()
+
+	discovered, err := Discover(ctx, insightStore, settingStore, insights.NewMockLoader(), InsightFilterArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Insights in the database take precedence over their definition in settings, and insights
+	// that are only defined in settings are discovered from the settings.
+	autogold.Want("discovered_from_database", []insights.SearchInsight{
+		{
+			ID:          "1",
+			Title:       "fmt usage (migrated)",
+			Description: "errors.Errorf usage",
+			Series: []insights.TimeSeries{{
+				Name:     "errors.Errorf",
+				Stroke:   "blue",
+				Query:    "errorf",
+				Interval: insights.RecordingInterval("weekly"),
+			}},
+		},
+		{
+			ID:    "9",
+			Title: "go versions",
+			Series: []insights.TimeSeries{{
+				Name:                       "go",
+				Query:                      `go (\d\.\d+)`,
+				Interval:                   insights.RecordingInterval("daily"),
+				GeneratedFromCaptureGroups: true,
+			}},
+		},
+		{
+			ID:          "5",
+			Title:       "gitserver usage",
+			Description: "gitserver exec & close usage",
+			Series: []insights.TimeSeries{
+				{
+					Name:  "exec",
+					Query: "gitserver.Exec",
+				},
+				{
+					Name:  "close",
+					Query: "gitserver.Close",
+				},
+			},
+		},
+	}).Equal(t, discovered)
+
+	// Filters are applied to the insights in the database as well as those in settings.
+	insightStore.GetFunc.PushReturn(nil, nil)
+	discovered, err = Discover(ctx, insightStore, settingStore, insights.NewMockLoader(), InsightFilterArgs{Ids: []string{"5"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := insightStore.GetFunc.History()
+	if diff := cmp.Diff([]string{"5"}, history[len(history)-1].Arg1.UniqueIDs); diff != "" {
+		t.Errorf("unexpected unique IDs queried (-want +got):\n%s", diff)
+	}
+	if len(discovered) != 1 || discovered[0].ID != "5" {
+		t.Errorf("unexpected insights discovered by ID: %v", discovered)
+	}
+}
+
+func TestDiscoverNamespaces(t *testing.T) {
+	settingStore := NewMockSettingStore()
+	orgID := int32(1)
+	insightStore := NewMockInsightStore()
+	insightStore.GetFunc.SetDefaultReturn([]types.InsightViewSeries{
+		{UniqueID: "global", SeriesID: "s:1", Query: "errorf"},
+		{UniqueID: "org", SeriesID: "s:2", Query: "errorf", OrgID: &orgID},
+	}, nil)
+	loader := insights.NewMockLoader()
+	loader.LoadAllFunc.SetDefaultReturn([]insights.SearchInsight{{
+		ID:        "user",
+		Series:    []insights.TimeSeries{{Query: "errorf"}},
+		Namespace: insights.Namespace{UserID: 2},
+	}}, nil)
+	ctx := context.Background()
+
+	discovered, err := Discover(ctx, insightStore, settingStore, loader, InsightFilterArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seriesIDs := map[string]string{}
+	for _, insight := range discovered {
+		for _, series := range insight.Series {
+			if series.Namespace != insight.Namespace {
+				t.Errorf("unexpected namespace of series of insight %q. want=%v have=%v", insight.ID, insight.Namespace, series.Namespace)
+			}
+			seriesIDs[insight.ID] = Encode(series)
+		}
+	}
+	if len(seriesIDs) != 3 || seriesIDs["global"] == seriesIDs["org"] || seriesIDs["global"] == seriesIDs["user"] {
+		t.Errorf("expected series of each namespace to be recorded separately. have=%v", seriesIDs)
+	}
+
+	discovered, err = Discover(ctx, insightStore, settingStore, loader, InsightFilterArgs{
+		Namespaces: []insights.Namespace{{}, {OrgID: orgID}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, insight := range discovered {
+		ids = append(ids, insight.ID)
+	}
+	if diff := cmp.Diff([]string{"global", "org"}, ids); diff != "" {
+		t.Errorf("unexpected insights discovered in namespaces (-want +got):\n%s", diff)
+	}
+
+	discovered, err = Discover(ctx, insightStore, settingStore, loader, InsightFilterArgs{
+		SeriesIDs: []string{seriesIDs["org"], seriesIDs["user"]},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids = nil
+	for _, insight := range discovered {
+		ids = append(ids, insight.ID)
+	}
+	if diff := cmp.Diff([]string{"org", "user"}, ids); diff != "" {
+		t.Errorf("unexpected insights discovered by series IDs (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiscoverRepositoryScope(t *testing.T) {
+	settingStore := NewMockSettingStore()
+	insightStore := NewMockInsightStore()
+	insightStore.GetFunc.SetDefaultReturn([]types.InsightViewSeries{
+		{UniqueID: "migrated", SeriesID: "s:1", Query: "errorf", Repositories: []string{"github.com/a/b"}},
+	}, nil)
+	loader := insights.NewMockLoader()
+	loader.LoadAllFunc.SetDefaultReturn([]insights.SearchInsight{{
+		ID:                "integrated",
+		Series:            []insights.TimeSeries{{Query: "errorf"}},
+		RepositoryPattern: "^github\\.com/a/",
+	}}, nil)
+	ctx := context.Background()
+
+	discovered, err := Discover(ctx, insightStore, settingStore, loader, InsightFilterArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scopedQueries := map[string]string{}
+	for _, insight := range discovered {
+		for _, series := range insight.Series {
+			scopedQueries[insight.ID] = ScopedQuery(series)
+		}
+	}
+	expected := map[string]string{
+		"migrated":   `repo:^(github\.com/a/b)$ errorf`,
+		"integrated": `repo:^github\.com/a/ errorf`,
+	}
+	if diff := cmp.Diff(expected, scopedQueries); diff != "" {
+		t.Errorf("unexpected scoped queries (-want +got):\n%s", diff)
+	}
+}
+
+func TestNormalizeInsight(t *testing.T) {
+	normalized := normalizeInsight(insights.SearchInsight{
+		ID:           "1",
+		Title:        "fmt usage",
+		Repositories: []string{"github.com/sourcegraph/sourcegraph"},
+		Series: []insights.TimeSeries{
+			{Name: "printf", Query: "fmt.Printf", Interval: insights.Weekly},
+			{Name: "errorf", Query: "errorf"},
+			{Name: "printf again", Query: "fmt.Printf"},
+		},
+	})
+
+	// Series are ordered by their series ID, which is derived from their query.
+	expected := insights.SearchInsight{
+		ID:    "1",
+		Title: "fmt usage",
+		Series: []insights.TimeSeries{
+			{Name: "errorf", Query: "errorf", Interval: insights.Daily},
+			{Name: "printf", Query: "fmt.Printf", Interval: insights.Weekly},
+		},
+	}
+	if diff := cmp.Diff(expected, normalized); diff != "" {
+		t.Errorf("unexpected normalized insight (-want +got):\n%s", diff)
+	}
+}
+
 func Test_parseUserSettings(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -220,3 +434,165 @@ func Test_parseUserSettings(t *testing.T) {
 	}
 
 }
+
+func TestMigrateSettingInsights(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	ctx := context.Background()
+	insightStore := store.NewInsightStore(timescale)
+
+	settings := settingsExample
+	settingStore := NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultHook(func(ctx context.Context, subject api.SettingsSubject) (*api.Settings, error) {
+		return settings, nil
+	})
+	loader := insights.NewMockLoader()
+	var instances []insights.SearchInsight
+
+	migrate := func() []insights.SearchInsight {
+		if err := migrateSettingInsights(ctx, insightStore, settingStore, loader, instances); err != nil {
+			t.Fatal(err)
+		}
+		viewSeries, err := insightStore.Get(ctx, store.InsightQueryArgs{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return convertFromViewSeries(viewSeries)
+	}
+	viewID := func(uniqueID string) (id int) {
+		if err := timescale.QueryRow(`SELECT id FROM insight_view WHERE unique_id = $1`, uniqueID).Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	migrated := migrate()
+	if len(migrated) != 2 || migrated[0].ID != "1" || migrated[1].ID != "5" {
+		t.Fatalf("unexpected migrated insights: %v", migrated)
+	}
+	for _, insight := range migrated {
+		if diff := cmp.Diff(normalizeInsight(insight), insight); diff != "" {
+			t.Errorf("unexpected migrated insight (-want +got):\n%s", diff)
+		}
+	}
+
+	// Unchanged insights are not migrated again.
+	firstViewID := viewID("1")
+	migrate()
+	if id := viewID("1"); id != firstViewID {
+		t.Errorf("unexpected view ID after migrating unchanged settings. want=%d have=%d", firstViewID, id)
+	}
+
+	// Changed insights are replaced, and removed insights are removed.
+	settings = &api.Settings{ID: 2, Contents: `{
+		"insights": [
+			{
+				"title": "fmt usage",
+				"description": "errors.Errorf usage",
+				"id": "1",
+				"series": [
+					{
+						"label": "errors.Errorf",
+						"search": "errorf",
+						"interval": "weekly",
+					}
+				]
+			}
+		]
+	}`}
+	migrated = migrate()
+	expected := []insights.SearchInsight{{
+		ID:          "1",
+		Title:       "fmt usage",
+		Description: "errors.Errorf usage",
+		Series:      []insights.TimeSeries{{Name: "errors.Errorf", Query: "errorf", Interval: insights.Weekly}},
+	}}
+	if diff := cmp.Diff(expected, migrated); diff != "" {
+		t.Errorf("unexpected migrated insights (-want +got):\n%s", diff)
+	}
+
+	// The series no longer used by any insight are soft-deleted, but retained until they are purged.
+	series, err := insightStore.GetDataSeries(ctx, store.GetDataSeriesArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || series[0].ReferenceCount != 1 {
+		t.Errorf("unexpected data series: %v", series)
+	}
+	var count int
+	if err := timescale.QueryRow(`SELECT COUNT(*) FROM insight_series`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 4 {
+		t.Errorf("unexpected number of retained data series. want=%d have=%d", 4, count)
+	}
+
+	// Instances of templates are migrated along with the insights of settings, and removed once
+	// they are no longer instantiated.
+	instances = []insights.SearchInsight{instantiateTemplate(insights.InsightTemplate{
+		ID:     "go-version",
+		Title:  "Go version adoption in {repository}",
+		Series: []insights.TimeSeries{{Name: "Go 1.16", Query: "file:go.mod go 1.16"}},
+	}, "github.com/sourcegraph/sourcegraph", 42)}
+	migrated = migrate()
+	if len(migrated) != 2 || migrated[1].ID != "go-version.repo-42" {
+		t.Fatalf("unexpected migrated insights: %v", migrated)
+	}
+	instances = nil
+	migrated = migrate()
+	if len(migrated) != 1 || migrated[0].ID != "1" {
+		t.Errorf("unexpected migrated insights after removing template instance: %v", migrated)
+	}
+}
+
+func TestMigrateSettingDashboards(t *testing.T) {
+	ctx := context.Background()
+	unchanged, renamed, removed := "unchanged", "renamed", "removed"
+	dashboardStore := NewMockDashboardStore()
+	dashboardStore.GetDashboardsFunc.SetDefaultReturn([]types.Dashboard{
+		{ID: 1, Title: "unchanged", UniqueID: &unchanged, InsightIDs: []string{"a"}, UserIDs: []int32{}, OrgIDs: []int32{5}},
+		{ID: 2, Title: "old title", UniqueID: &renamed, InsightIDs: []string{"a", "b"}, UserIDs: []int32{}, OrgIDs: []int32{}, GlobalGrant: true},
+		{ID: 3, Title: "removed", UniqueID: &removed, InsightIDs: []string{}, UserIDs: []int32{1}, OrgIDs: []int32{}},
+		{ID: 4, Title: "not from settings"},
+	}, nil)
+	loader := insights.NewMockDashboardLoader()
+	loader.LoadDashboardsFunc.SetDefaultReturn([]insights.SettingDashboard{
+		{ID: "renamed", Title: "new title", InsightIDs: []string{"b", "a"}},
+		{ID: "created", Title: "created", Namespace: insights.Namespace{UserID: 2}},
+		{ID: "unchanged", Title: "unchanged", InsightIDs: []string{"a"}, Namespace: insights.Namespace{OrgID: 5}},
+		{ID: "unchanged", Title: "duplicate", Namespace: insights.Namespace{UserID: 3}},
+	}, nil)
+
+	if err := migrateSettingDashboards(ctx, dashboardStore, loader); err != nil {
+		t.Fatalf("unexpected error migrating dashboards: %s", err)
+	}
+
+	created := "created"
+	wantCreated := []store.CreateDashboardArgs{{Title: "created", UniqueID: &created, InsightIDs: []string{}, Grants: []store.DashboardGrant{store.UserDashboardGrant(2)}}}
+	var haveCreated []store.CreateDashboardArgs
+	for _, call := range dashboardStore.CreateDashboardFunc.History() {
+		haveCreated = append(haveCreated, call.Arg1)
+	}
+	if diff := cmp.Diff(wantCreated, haveCreated); diff != "" {
+		t.Errorf("unexpected created dashboards (-want +got):\n%s", diff)
+	}
+
+	title := "new title"
+	wantUpdated := []store.UpdateDashboardArgs{{ID: 2, Title: &title, InsightIDs: []string{"b", "a"}, Grants: []store.DashboardGrant{store.GlobalDashboardGrant()}}}
+	var haveUpdated []store.UpdateDashboardArgs
+	for _, call := range dashboardStore.UpdateDashboardFunc.History() {
+		haveUpdated = append(haveUpdated, call.Arg1)
+	}
+	if diff := cmp.Diff(wantUpdated, haveUpdated); diff != "" {
+		t.Errorf("unexpected updated dashboards (-want +got):\n%s", diff)
+	}
+
+	deleted := dashboardStore.DeleteDashboardFunc.History()
+	if len(deleted) != 1 || deleted[0].Arg1 != 3 {
+		t.Errorf("unexpected deleted dashboards: %v", deleted)
+	}
+}
@@ -0,0 +1,104 @@
+package discovery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// DetectDuplicateInsights returns the insights of the given insights that are defined in the
+// settings of more than one namespace with identical series, grouped by GroupID. Identical
+// insights in different namespaces are recorded separately, so every duplicate multiplies the
+// search load of the insight: each group has a canonical insight that the others can be merged
+// into by sharing it with their users.
+//
+// Insights are identical if their series record the same data: the same search queries, webhooks
+// or expressions, with the same repository scope, grouping and recording interval. Titles, labels
+// and colors are ignored.
+func DetectDuplicateInsights(discovered []insights.SearchInsight) []types.InsightDuplicate {
+	groups := map[string][]insights.SearchInsight{}
+	var keys []string
+	for _, insight := range discovered {
+		if insight.ID == "" || len(insight.Series) == 0 {
+			continue
+		}
+		key := duplicateKey(insight)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], insight)
+	}
+	sort.Strings(keys)
+
+	duplicates := make([]types.InsightDuplicate, 0)
+	for _, key := range keys {
+		group := groups[key]
+		namespaces := map[insights.Namespace]struct{}{}
+		for _, insight := range group {
+			namespaces[insight.Namespace] = struct{}{}
+		}
+		if len(namespaces) < 2 {
+			continue
+		}
+
+		sort.SliceStable(group, func(i, j int) bool { return canonicalBefore(group[i], group[j]) })
+		for i, insight := range group {
+			duplicate := types.InsightDuplicate{
+				GroupID:   key,
+				InsightID: insight.ID,
+				Title:     insight.Title,
+				Canonical: i == 0,
+			}
+			if userID := insight.Namespace.UserID; userID != 0 {
+				duplicate.UserID = &userID
+			}
+			if orgID := insight.Namespace.OrgID; orgID != 0 {
+				duplicate.OrgID = &orgID
+			}
+			duplicates = append(duplicates, duplicate)
+		}
+	}
+	return duplicates
+}
+
+// duplicateKey returns a key that is equal for insights whose series record the same data,
+// regardless of the namespace defining them.
+func duplicateKey(insight insights.SearchInsight) string {
+	series := make([]string, 0, len(insight.Series))
+	for _, s := range insight.Series {
+		// Series IDs are hashed with the namespace of the series, so that the data of identical
+		// series in different namespaces are recorded separately.
+		s.Namespace = insights.Namespace{}
+		series = append(series, fmt.Sprintf("%s/%s/%t", Encode(s), s.Interval, s.BusinessDaysOnly))
+	}
+	sort.Strings(series)
+	return sha256String(strings.Join(series, "\n"))
+}
+
+// canonicalBefore returns true if the first insight should be preferred over the second as the
+// canonical insight of a group of duplicates: global insights are visible to all users, and
+// organization insights to more users than user insights. Ties are broken by the namespace with the
+// lowest ID, which was created first.
+func canonicalBefore(a, b insights.SearchInsight) bool {
+	if rank(a.Namespace) != rank(b.Namespace) {
+		return rank(a.Namespace) < rank(b.Namespace)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace.OrgID+a.Namespace.UserID < b.Namespace.OrgID+b.Namespace.UserID
+	}
+	return a.ID < b.ID
+}
+
+func rank(namespace insights.Namespace) int {
+	switch {
+	case namespace.IsGlobal():
+		return 0
+	case namespace.OrgID != 0:
+		return 1
+	default:
+		return 2
+	}
+}
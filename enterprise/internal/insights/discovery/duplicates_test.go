@@ -0,0 +1,70 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestDetectDuplicateInsights(t *testing.T) {
+	alice, bob, acme := insights.Namespace{UserID: 1}, insights.Namespace{UserID: 2}, insights.Namespace{OrgID: 3}
+	errorf := func(namespace insights.Namespace, name string) insights.TimeSeries {
+		return insights.TimeSeries{Name: name, Query: "errorf", Repositories: []string{"github.com/a/b"}, Namespace: namespace}
+	}
+	todo := func(namespace insights.Namespace) insights.TimeSeries {
+		return insights.TimeSeries{Name: "TODOs", Query: "TODO", Namespace: namespace}
+	}
+
+	duplicates := DetectDuplicateInsights([]insights.SearchInsight{
+		// Labels and the order of series are ignored.
+		{ID: "errors", Title: "Errors", Namespace: alice, Series: []insights.TimeSeries{errorf(alice, "errorf"), todo(alice)}},
+		{ID: "my-errors", Title: "My errors", Namespace: bob, Series: []insights.TimeSeries{todo(bob), errorf(bob, "calls to errorf")}},
+		{ID: "errors", Title: "Errors", Namespace: acme, Series: []insights.TimeSeries{errorf(acme, "errorf"), todo(acme)}},
+		// The same series in a single namespace.
+		{ID: "todos", Title: "TODOs", Namespace: alice, Series: []insights.TimeSeries{todo(alice)}},
+		{ID: "more-todos", Title: "TODOs", Namespace: alice, Series: []insights.TimeSeries{todo(alice)}},
+		// A different repository scope or recording interval records different data.
+		{ID: "all-errors", Namespace: bob, Series: []insights.TimeSeries{{Query: "errorf", Namespace: bob}, todo(bob)}},
+		{ID: "hourly-errors", Namespace: acme, Series: []insights.TimeSeries{{Query: "errorf", Repositories: []string{"github.com/a/b"}, Interval: insights.Hourly, Namespace: acme}, todo(acme)}},
+		{ID: "no-series", Namespace: bob},
+		{ID: "no-series", Namespace: acme},
+	})
+
+	if len(duplicates) != 3 {
+		t.Fatalf("unexpected number of duplicates. want=%d have=%d: %v", 3, len(duplicates), duplicates)
+	}
+	groupID := duplicates[0].GroupID
+	aliceID, bobID, acmeID := alice.UserID, bob.UserID, acme.OrgID
+	want := []types.InsightDuplicate{
+		{GroupID: groupID, InsightID: "errors", Title: "Errors", OrgID: &acmeID, Canonical: true},
+		{GroupID: groupID, InsightID: "errors", Title: "Errors", UserID: &aliceID},
+		{GroupID: groupID, InsightID: "my-errors", Title: "My errors", UserID: &bobID},
+	}
+	if diff := cmp.Diff(want, duplicates); diff != "" {
+		t.Errorf("unexpected duplicates (-want +got):\n%s", diff)
+	}
+}
+
+func TestCanonicalBefore(t *testing.T) {
+	global := insights.SearchInsight{ID: "b"}
+	org := insights.SearchInsight{ID: "a", Namespace: insights.Namespace{OrgID: 5}}
+	olderOrg := insights.SearchInsight{ID: "a", Namespace: insights.Namespace{OrgID: 2}}
+	user := insights.SearchInsight{ID: "a", Namespace: insights.Namespace{UserID: 1}}
+
+	for _, testCase := range []struct {
+		name string
+		a, b insights.SearchInsight
+	}{
+		{"global before org", global, org},
+		{"org before user", org, user},
+		{"older org before org", olderOrg, org},
+		{"lower ID first", insights.SearchInsight{ID: "a"}, global},
+	} {
+		if !canonicalBefore(testCase.a, testCase.b) || canonicalBefore(testCase.b, testCase.a) {
+			t.Errorf("unexpected order of %s", testCase.name)
+		}
+	}
+}
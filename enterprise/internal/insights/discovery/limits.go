@@ -0,0 +1,100 @@
+package discovery
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// InsightLimits limits the number of insights that are recorded, and the number of series recorded
+// per insight. A limit of 0 means unlimited.
+type InsightLimits struct {
+	MaxInsights         int
+	MaxSeriesPerInsight int
+}
+
+// LicenseLimits returns the insight limits of the license of the instance. Insights are unlimited
+// unless license tiers are enforced.
+func LicenseLimits() (InsightLimits, error) {
+	if !licensing.EnforceTiers {
+		return InsightLimits{}, nil
+	}
+	info, err := licensing.GetConfiguredProductLicenseInfo()
+	if err != nil {
+		return InsightLimits{}, err
+	}
+	if info == nil {
+		return InsightLimits{
+			MaxInsights:         licensing.NoLicenseMaximumCodeInsights,
+			MaxSeriesPerInsight: licensing.NoLicenseMaximumSeriesPerCodeInsight,
+		}, nil
+	}
+	return InsightLimits{
+		MaxInsights:         info.Plan().MaxCodeInsights(),
+		MaxSeriesPerInsight: info.Plan().MaxSeriesPerCodeInsight(),
+	}, nil
+}
+
+// ApplyLimits returns the given insights within the given limits, in their original order, and
+// a problem for every series left out. The insights within the limit are those with the smallest
+// IDs, so that the same insights are left out every time; the series within the limit of an
+// insight are its first ones.
+func ApplyLimits(discovered []insights.SearchInsight, limits InsightLimits) ([]insights.SearchInsight, []types.InsightProblem) {
+	if limits.MaxInsights == 0 && limits.MaxSeriesPerInsight == 0 {
+		return discovered, nil
+	}
+
+	overLimit := map[int]struct{}{}
+	if limits.MaxInsights > 0 && len(discovered) > limits.MaxInsights {
+		order := make([]int, len(discovered))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return discovered[order[i]].ID < discovered[order[j]].ID
+		})
+		for _, i := range order[limits.MaxInsights:] {
+			overLimit[i] = struct{}{}
+		}
+	}
+
+	var (
+		within   = make([]insights.SearchInsight, 0, len(discovered))
+		problems []types.InsightProblem
+	)
+	for i, insight := range discovered {
+		problem := func(series insights.TimeSeries, format string, args ...interface{}) types.InsightProblem {
+			p := types.InsightProblem{
+				InsightID:   insight.ID,
+				SeriesID:    Encode(series),
+				SeriesLabel: series.Name,
+				Problem:     fmt.Sprintf(format, args...),
+			}
+			if userID := insight.Namespace.UserID; userID != 0 {
+				p.UserID = &userID
+			}
+			if orgID := insight.Namespace.OrgID; orgID != 0 {
+				p.OrgID = &orgID
+			}
+			return p
+		}
+
+		if _, ok := overLimit[i]; ok {
+			for _, series := range insight.Series {
+				problems = append(problems, problem(series, "insight is not recorded: the license allows at most %d insights", limits.MaxInsights))
+			}
+			continue
+		}
+		if limits.MaxSeriesPerInsight > 0 && len(insight.Series) > limits.MaxSeriesPerInsight {
+			for _, series := range insight.Series[limits.MaxSeriesPerInsight:] {
+				problems = append(problems, problem(series, "series is not recorded: the license allows at most %d series per insight", limits.MaxSeriesPerInsight))
+			}
+			insight.Series = insight.Series[:limits.MaxSeriesPerInsight]
+		}
+		within = append(within, insight)
+	}
+	return within, problems
+}
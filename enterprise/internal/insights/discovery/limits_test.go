@@ -0,0 +1,53 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestApplyLimits(t *testing.T) {
+	orgID := int32(1)
+	errorf := insights.TimeSeries{Name: "errorf", Query: "errorf"}
+	printf := insights.TimeSeries{Name: "printf", Query: "printf"}
+	panics := insights.TimeSeries{Name: "panic", Query: "panic("}
+	discovered := []insights.SearchInsight{
+		{ID: "c", Namespace: insights.Namespace{OrgID: orgID}, Series: []insights.TimeSeries{errorf, printf}},
+		{ID: "a", Series: []insights.TimeSeries{errorf, printf, panics}},
+		{ID: "b", Series: []insights.TimeSeries{panics}},
+	}
+
+	t.Run("unlimited", func(t *testing.T) {
+		within, problems := ApplyLimits(discovered, InsightLimits{})
+		if diff := cmp.Diff(discovered, within); diff != "" {
+			t.Errorf("unexpected insights within limits (-want +got):\n%s", diff)
+		}
+		if len(problems) != 0 {
+			t.Errorf("unexpected problems: %v", problems)
+		}
+	})
+
+	t.Run("limited", func(t *testing.T) {
+		within, problems := ApplyLimits(discovered, InsightLimits{MaxInsights: 2, MaxSeriesPerInsight: 2})
+
+		// The insight with the greatest ID is left out, regardless of its position.
+		wantWithin := []insights.SearchInsight{
+			{ID: "a", Series: []insights.TimeSeries{errorf, printf}},
+			{ID: "b", Series: []insights.TimeSeries{panics}},
+		}
+		if diff := cmp.Diff(wantWithin, within); diff != "" {
+			t.Errorf("unexpected insights within limits (-want +got):\n%s", diff)
+		}
+		wantProblems := []types.InsightProblem{
+			{InsightID: "c", SeriesID: Encode(errorf), SeriesLabel: "errorf", OrgID: &orgID, Problem: "insight is not recorded: the license allows at most 2 insights"},
+			{InsightID: "c", SeriesID: Encode(printf), SeriesLabel: "printf", OrgID: &orgID, Problem: "insight is not recorded: the license allows at most 2 insights"},
+			{InsightID: "a", SeriesID: Encode(panics), SeriesLabel: "panic", Problem: "series is not recorded: the license allows at most 2 series per insight"},
+		}
+		if diff := cmp.Diff(wantProblems, problems); diff != "" {
+			t.Errorf("unexpected problems (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// VisibleNamespaces returns the namespaces whose insights the current user may see: the global
+// namespace, the namespace of the user, and the namespaces of the organizations the user is a
+// member of. Anonymous users only see global insights.
+func VisibleNamespaces(ctx context.Context, db dbutil.DB) ([]insights.Namespace, error) {
+	namespaces := []insights.Namespace{{}}
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return namespaces, nil
+	}
+
+	namespaces = append(namespaces, insights.Namespace{UserID: a.UID})
+	orgs, err := database.Orgs(db).GetByUserID(ctx, a.UID)
+	if err != nil {
+		return nil, err
+	}
+	for _, org := range orgs {
+		namespaces = append(namespaces, insights.Namespace{OrgID: org.ID})
+	}
+	return namespaces, nil
+}
@@ -0,0 +1,197 @@
+package discovery
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+// ParseSearchQuery parses the given search query of a series the way search does when the query
+// runner performs it: as a query of the given search type, unless the query sets its own
+// `patternType:`. The returned plan is normalized, e.g. field aliases are resolved.
+func ParseSearchQuery(searchQuery string, searchType query.SearchType) (query.Plan, error) {
+	return query.Pipeline(query.Init(searchQuery, overrideSearchType(searchQuery, searchType)))
+}
+
+// overrideSearchType returns the search type set by the `patternType:` filter of the given search
+// query, or the given search type if there is none.
+func overrideSearchType(searchQuery string, searchType query.SearchType) query.SearchType {
+	nodes, err := query.Parse(searchQuery, query.SearchTypeLiteral)
+	if err != nil {
+		// Parse errors are reported when the query is parsed with its search type.
+		return searchType
+	}
+	query.VisitField(query.LowercaseFieldNames(nodes), query.FieldPatternType, func(value string, _ bool, _ query.Annotation) {
+		switch value {
+		case "regex", "regexp":
+			searchType = query.SearchTypeRegex
+		case "literal":
+			searchType = query.SearchTypeLiteral
+		case "structural":
+			searchType = query.SearchTypeStructural
+		}
+	})
+	return searchType
+}
+
+// ValidateSeries returns an error if the search query of the given series is not a valid search
+// query, uses filters that insights do not support, or cannot be restricted to the repository scope
+// of the series. Webhook series are valid as long as they are not restricted to repositories, and
+// derived series as long as their expression is valid and resolved.
+func ValidateSeries(series insights.TimeSeries) error {
+	if err := validateGroupBy(series); err != nil {
+		return err
+	}
+	if series.Expression != "" {
+		return validateExpression(series)
+	}
+	if series.Webhook != "" {
+		if len(series.Repositories) > 0 || series.RepositoryPattern != "" {
+			return errors.Errorf("invalid webhook series %q: webhook series cannot be restricted to repositories", series.Webhook)
+		}
+		return nil
+	}
+
+	if err := validateRepositoryScope(series); err != nil {
+		return err
+	}
+
+	// Series generated from capture groups are searched for as regular expressions.
+	searchType := query.SearchTypeLiteral
+	if series.GeneratedFromCaptureGroups {
+		searchType = query.SearchTypeRegex
+	}
+	// The repository scope of the series is validated as part of its scoped query.
+	plan, err := ParseSearchQuery(ScopedQuery(series), searchType)
+	if err != nil {
+		return errors.Wrapf(err, "invalid search query %q", series.Query)
+	}
+	if len(plan) > 1 && (len(series.Repositories) > 0 || series.RepositoryPattern != "") {
+		// The repo: filter of the scoped query would only apply to the first operand of the OR
+		// expression.
+		return errors.Errorf("invalid search query %q: queries with OR expressions cannot be restricted to repositories", series.Query)
+	}
+
+	if series.GroupBy == insights.AttributeToAuthor && !commitSearch(plan) {
+		return errors.Errorf("invalid search query %q: series grouped by author must search for commits or diffs with type:commit or type:diff", series.Query)
+	}
+
+	for _, basic := range plan {
+		for _, parameter := range basic.Parameters {
+			// Data points are recorded for the default branch of repositories, and historical data
+			// points are searched for at the revision of the default branch at their time.
+			if parameter.Field == query.FieldRev || (parameter.Field == query.FieldRepo && strings.Contains(parameter.Value, "@")) {
+				return errors.Errorf("unsupported filter %s:%s in search query %q: insights do not support searching for revisions", parameter.Field, parameter.Value, series.Query)
+			}
+		}
+	}
+	return nil
+}
+
+// validateGroupBy returns an error if the given series is grouped by an unsupported attribution,
+// or is grouped by attribution without being a search series split by nothing else.
+func validateGroupBy(series insights.TimeSeries) error {
+	switch series.GroupBy {
+	case "":
+		return nil
+	case insights.AttributeToAuthor, insights.AttributeToTeam:
+	default:
+		return errors.Errorf("invalid groupBy %q: series can only be grouped by author or team", series.GroupBy)
+	}
+	if series.Query == "" {
+		return errors.Errorf("invalid groupBy %q: only series with a search query can be grouped by attribution", series.GroupBy)
+	}
+	if series.GeneratedFromCaptureGroups {
+		return errors.Errorf("invalid groupBy %q: series generated from capture groups cannot be grouped by attribution", series.GroupBy)
+	}
+	return nil
+}
+
+// commitSearch returns true if every operand of the given plan only searches for commits or diffs,
+// the only results that have an author.
+func commitSearch(plan query.Plan) bool {
+	for _, basic := range plan {
+		found := false
+		for _, parameter := range basic.Parameters {
+			if parameter.Field == query.FieldType && !parameter.Negated {
+				if parameter.Value != "commit" && parameter.Value != "diff" {
+					return false
+				}
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// validateRepositoryScope returns an error if the repository scope of the given series is invalid.
+func validateRepositoryScope(series insights.TimeSeries) error {
+	if len(series.Repositories) == 0 && series.RepositoryPattern == "" {
+		return nil
+	}
+	if len(series.Repositories) > 0 && series.RepositoryPattern != "" {
+		return errors.Errorf("invalid repository scope of search query %q: repositories and a repository pattern cannot be combined", series.Query)
+	}
+	for _, name := range series.Repositories {
+		if strings.TrimSpace(name) == "" {
+			return errors.Errorf("invalid repository scope of search query %q: empty repository name", series.Query)
+		}
+	}
+	if series.RepositoryPattern != "" {
+		if strings.ContainsAny(series.RepositoryPattern, " \t\n") {
+			return errors.Errorf("invalid repository pattern %q: patterns cannot contain whitespace", series.RepositoryPattern)
+		}
+		if _, err := regexp.Compile(series.RepositoryPattern); err != nil {
+			return errors.Wrapf(err, "invalid repository pattern %q", series.RepositoryPattern)
+		}
+	}
+	return nil
+}
+
+// ScopedQuery returns the search query of the given series restricted to its repository scope by
+// a repo: filter, or the search query itself if the series searches all repositories.
+func ScopedQuery(series insights.TimeSeries) string {
+	switch {
+	case len(series.Repositories) > 0:
+		names := make([]string, 0, len(series.Repositories))
+		for _, name := range series.Repositories {
+			names = append(names, regexp.QuoteMeta(name))
+		}
+		sort.Strings(names)
+		return "repo:^(" + strings.Join(names, "|") + ")$ " + series.Query
+	case series.RepositoryPattern != "":
+		return "repo:" + series.RepositoryPattern + " " + series.Query
+	default:
+		return series.Query
+	}
+}
+
+// InRepositoryScope returns true if the repository with the given name is in the repository scope
+// of the given series. As with repo: filters, repository names are matched case-insensitively.
+func InRepositoryScope(series insights.TimeSeries, repoName string) bool {
+	switch {
+	case len(series.Repositories) > 0:
+		for _, name := range series.Repositories {
+			if strings.EqualFold(name, repoName) {
+				return true
+			}
+		}
+		return false
+	case series.RepositoryPattern != "":
+		pattern, err := regexp.Compile("(?i)" + series.RepositoryPattern)
+		if err != nil {
+			return false
+		}
+		return pattern.MatchString(repoName)
+	default:
+		return true
+	}
+}
@@ -0,0 +1,83 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestValidateSeries(t *testing.T) {
+	for _, series := range []insights.TimeSeries{
+		{Query: "errorf"},
+		{Query: "lang:go errorf count:all"},
+		{Query: `content:"rev:" lang:go`},
+		{Query: `go (\d+)`, GeneratedFromCaptureGroups: true},
+		{Webhook: "https://example.com/insight"},
+		{Query: "errorf", Repositories: []string{"github.com/sourcegraph/sourcegraph"}},
+		{Query: "errorf", RepositoryPattern: "^github\\.com/sourcegraph/"},
+		{Query: "type:diff select:commit.diff.added errorf", GroupBy: insights.AttributeToAuthor},
+		{Query: "type:commit fix", GroupBy: insights.AttributeToTeam},
+		{Query: "errorf", GroupBy: insights.AttributeToTeam},
+	} {
+		if err := ValidateSeries(series); err != nil {
+			t.Errorf("unexpected error validating series %+v: %s", series, err)
+		}
+	}
+
+	for _, series := range []insights.TimeSeries{
+		{Query: "errorf fork:maybe"},
+		{Query: "repo:sourcegraph rev:main errorf"},
+		{Query: "repo:sourcegraph revision:main errorf"},
+		{Query: "repo:sourcegraph@main errorf"},
+		{Query: "errorf", Repositories: []string{"github.com/a/b"}, RepositoryPattern: "github"},
+		{Query: "errorf", Repositories: []string{""}},
+		{Query: "errorf", RepositoryPattern: "github.com/(a"},
+		{Query: "errorf", RepositoryPattern: "github.com/a errorf"},
+		{Query: "errorf", RepositoryPattern: "github.com/a@main"},
+		{Query: "errorf OR fmt.Printf", Repositories: []string{"github.com/a/b"}},
+		{Webhook: "https://example.com/insight", RepositoryPattern: "github"},
+		{Query: "errorf", GroupBy: insights.AttributeToAuthor},
+		{Query: "type:file errorf", GroupBy: insights.AttributeToAuthor},
+		{Query: "type:commit fix", GroupBy: "reviewer"},
+		{Query: `go (\d+)`, GeneratedFromCaptureGroups: true, GroupBy: insights.AttributeToTeam},
+		{Webhook: "https://example.com/insight", GroupBy: insights.AttributeToTeam},
+	} {
+		if err := ValidateSeries(series); err == nil {
+			t.Errorf("expected an error validating series %+v", series)
+		}
+	}
+}
+
+func TestScopedQuery(t *testing.T) {
+	for _, testCase := range []struct {
+		series   insights.TimeSeries
+		expected string
+	}{
+		{insights.TimeSeries{Query: "errorf"}, "errorf"},
+		{insights.TimeSeries{Query: "errorf", Repositories: []string{"github.com/c/d", "github.com/a/b"}}, `repo:^(github\.com/a/b|github\.com/c/d)$ errorf`},
+		{insights.TimeSeries{Query: "errorf", RepositoryPattern: "^github\\.com/a/"}, `repo:^github\.com/a/ errorf`},
+	} {
+		if have := ScopedQuery(testCase.series); have != testCase.expected {
+			t.Errorf("unexpected scoped query. want=%q have=%q", testCase.expected, have)
+		}
+	}
+}
+
+func TestInRepositoryScope(t *testing.T) {
+	for _, testCase := range []struct {
+		series   insights.TimeSeries
+		repoName string
+		expected bool
+	}{
+		{insights.TimeSeries{Query: "errorf"}, "github.com/a/b", true},
+		{insights.TimeSeries{Query: "errorf", Repositories: []string{"github.com/a/b"}}, "github.com/a/b", true},
+		{insights.TimeSeries{Query: "errorf", Repositories: []string{"github.com/a/b"}}, "GitHub.com/A/B", true},
+		{insights.TimeSeries{Query: "errorf", Repositories: []string{"github.com/a/b"}}, "github.com/a/bc", false},
+		{insights.TimeSeries{Query: "errorf", RepositoryPattern: "^github\\.com/a/"}, "github.com/a/b", true},
+		{insights.TimeSeries{Query: "errorf", RepositoryPattern: "^github\\.com/a/"}, "github.com/c/d", false},
+	} {
+		if have := InRepositoryScope(testCase.series, testCase.repoName); have != testCase.expected {
+			t.Errorf("unexpected scope of series %+v for repository %q. want=%v have=%v", testCase.series, testCase.repoName, testCase.expected, have)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package discovery
 import (
 	"crypto/sha256"
 	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/errors"
 
@@ -20,17 +21,123 @@ import (
 // data will not be queryable.
 func EncodeSeriesID(series *schema.InsightSeries) (string, error) {
 	switch {
+	case series.Search != "" && series.GroupBy == string(insights.AttributeToAuthor):
+		return fmt.Sprintf("%s%s", authorSeriesPrefix, sha256String(series.Search)), nil
+	case series.Search != "" && series.GroupBy == string(insights.AttributeToTeam):
+		return fmt.Sprintf("%s%s", teamSeriesPrefix, sha256String(series.Search)), nil
+	case series.Search != "" && series.GeneratedFromCaptureGroups:
+		return fmt.Sprintf("%s%s", captureGroupSeriesPrefix, sha256String(series.Search)), nil
 	case series.Search != "":
 		return fmt.Sprintf("s:%s", sha256String(series.Search)), nil
 	case series.Webhook != "":
-		return fmt.Sprintf("w:%s", sha256String(series.Webhook)), nil
+		return fmt.Sprintf("%s%s", webhookSeriesPrefix, sha256String(series.Webhook)), nil
+	case series.Expression != "":
+		return "", errors.Errorf("derived series %q are identified by the series they refer to (see Encode)", series.Label)
 	default:
 		return "", errors.Errorf("invalid series %+v", series)
 	}
 }
 
+// Encode returns the series ID of the given series under the current encoding of series IDs (see
+// CurrentSeriesIDVersion). Series generated from capture groups or grouped by attribution record
+// different data than a regular series with the same query, so they are identified separately.
+//
+// Series of user or organization insights are identified separately from the series of other
+// namespaces, so that their data is only ever shared within their namespace. The series IDs of
+// global insights do not depend on their namespace, so that they remain stable. Likewise, series
+// restricted to a repository scope are identified by their scoped query, and derived series by
+// their expression, which refers to the series IDs of their operands once resolved.
 func Encode(series insights.TimeSeries) string {
-	return fmt.Sprintf("s:%s", sha256String(series.Query))
+	hash := func(s string) string {
+		if series.Namespace.IsGlobal() {
+			return sha256String(s)
+		}
+		return sha256String(series.Namespace.String() + ":" + s)
+	}
+	if series.Webhook != "" {
+		return fmt.Sprintf("%s%s", webhookSeriesPrefix, hash(series.Webhook))
+	}
+	if series.Expression != "" {
+		return fmt.Sprintf("%s%s", derivedSeriesPrefix, hash(series.Expression))
+	}
+	switch series.GroupBy {
+	case insights.AttributeToAuthor:
+		return fmt.Sprintf("%s%s", authorSeriesPrefix, hash(ScopedQuery(series)))
+	case insights.AttributeToTeam:
+		return fmt.Sprintf("%s%s", teamSeriesPrefix, hash(ScopedQuery(series)))
+	}
+	if series.GeneratedFromCaptureGroups {
+		return fmt.Sprintf("%s%s", captureGroupSeriesPrefix, hash(ScopedQuery(series)))
+	}
+	return fmt.Sprintf("s:%s", hash(ScopedQuery(series)))
+}
+
+// CurrentSeriesIDVersion is the version of the encoding of series IDs implemented by Encode.
+//
+// The data of series is stored by series ID, so changing the series IDs of existing series would
+// orphan their data. When changing Encode in such a way, bump CurrentSeriesIDVersion and add the
+// previous implementation to legacySeriesIDEncodings: the series ID migrator (see
+// NewMigrateSeriesIDsJob) then re-links the data of the series to their new series IDs.
+const CurrentSeriesIDVersion = 1
+
+// SeriesIDEncoding is a version of the encoding of series IDs.
+type SeriesIDEncoding struct {
+	Version int
+
+	// Encode returns the series ID of the given series under this encoding, or an empty string if
+	// the series had no series ID under this encoding, e.g. because its kind of series did not
+	// exist yet.
+	Encode func(series insights.TimeSeries) string
+}
+
+// legacySeriesIDEncodings are the encodings of series IDs that preceded the current encoding,
+// oldest first. Encodings are given the series as discovered, so the expressions of derived series
+// refer to the operands by their current series IDs.
+var legacySeriesIDEncodings []SeriesIDEncoding
+
+const (
+	captureGroupSeriesPrefix = "c:"
+	webhookSeriesPrefix      = "w:"
+	derivedSeriesPrefix      = "d:"
+	authorSeriesPrefix       = "a:"
+	teamSeriesPrefix         = "t:"
+)
+
+// IsCaptureGroupSeries returns true if the given series ID identifies a series generated from
+// capture groups.
+func IsCaptureGroupSeries(seriesID string) bool {
+	return strings.HasPrefix(seriesID, captureGroupSeriesPrefix)
+}
+
+// GroupByOf returns what the matches of the series identified by the given series ID are
+// attributed to, or an empty attribution if the series is not grouped by attribution.
+func GroupByOf(seriesID string) insights.Attribution {
+	switch {
+	case strings.HasPrefix(seriesID, authorSeriesPrefix):
+		return insights.AttributeToAuthor
+	case strings.HasPrefix(seriesID, teamSeriesPrefix):
+		return insights.AttributeToTeam
+	default:
+		return ""
+	}
+}
+
+// IsSplitSeries returns true if the given series ID identifies a series split into one series per
+// label, i.e. a series generated from capture groups or grouped by attribution.
+func IsSplitSeries(seriesID string) bool {
+	return IsCaptureGroupSeries(seriesID) || GroupByOf(seriesID) != ""
+}
+
+// IsWebhookSeries returns true if the given series ID identifies a series whose data is fetched
+// from a webhook.
+func IsWebhookSeries(seriesID string) bool {
+	return strings.HasPrefix(seriesID, webhookSeriesPrefix)
+}
+
+// IsDerivedSeries returns true if the given series ID identifies a series computed from other
+// series with an expression.
+func IsDerivedSeries(seriesID string) bool {
+	return strings.HasPrefix(seriesID, derivedSeriesPrefix)
 }
 
 func sha256String(s string) string {
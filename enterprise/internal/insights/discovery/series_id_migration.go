@@ -0,0 +1,115 @@
+package discovery
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// SeriesIDAliasStore is a subset of the API exposed by the store.Store (only the subset used by the
+// series ID migrator.)
+type SeriesIDAliasStore interface {
+	RecordSeriesIDAliases(ctx context.Context, aliases []store.SeriesIDAlias) error
+	SeriesIDAliases(ctx context.Context, opts store.SeriesIDAliasesOpts) ([]store.SeriesIDAlias, error)
+	RelinkSeriesIDAlias(ctx context.Context, alias store.SeriesIDAlias) (int, error)
+}
+
+type seriesIDMigrator struct {
+	base     dbutil.DB
+	insights dbutil.DB
+}
+
+// NewMigrateSeriesIDsJob returns a background routine that periodically records aliases from the
+// series IDs of the discovered series under the legacy encodings of series IDs to their series IDs
+// under the current encoding, and re-links the data of the aliased series IDs to the current ones.
+func NewMigrateSeriesIDsJob(ctx context.Context, base dbutil.DB, insights dbutil.DB) goroutine.BackgroundRoutine {
+	// Series IDs only change with a new release, but series defined in settings are only
+	// discovered once they are migrated, which happens at the same interval.
+	interval := 10 * time.Minute
+	m := seriesIDMigrator{
+		base:     base,
+		insights: insights,
+	}
+
+	return goroutine.NewPeriodicGoroutine(ctx, interval,
+		goroutine.NewHandlerWithErrorMessage("insight_series_id_migrator", m.migrate))
+}
+
+func (m *seriesIDMigrator) migrate(ctx context.Context) error {
+	if len(legacySeriesIDEncodings) == 0 {
+		return nil
+	}
+	discovered, err := Discover(ctx, store.NewInsightStore(m.insights), database.Settings(m.base), insights.NewLoader(m.base), InsightFilterArgs{})
+	if err != nil {
+		return err
+	}
+	return migrateSeriesIDs(ctx, store.New(m.insights, store.NewInsightPermissionStore(m.base)), discovered, legacySeriesIDEncodings)
+}
+
+// migrateSeriesIDs records the aliases of the series of the given insights under the given legacy
+// encodings, and re-links the data of all aliases that have not been re-linked yet.
+func migrateSeriesIDs(ctx context.Context, aliasStore SeriesIDAliasStore, discovered []insights.SearchInsight, encodings []SeriesIDEncoding) error {
+	if err := aliasStore.RecordSeriesIDAliases(ctx, seriesIDAliases(discovered, encodings)); err != nil {
+		return errors.Wrap(err, "RecordSeriesIDAliases")
+	}
+
+	unrelinked, err := aliasStore.SeriesIDAliases(ctx, store.SeriesIDAliasesOpts{Unrelinked: true})
+	if err != nil {
+		return errors.Wrap(err, "SeriesIDAliases")
+	}
+	for _, alias := range unrelinked {
+		points, err := aliasStore.RelinkSeriesIDAlias(ctx, alias)
+		if err != nil {
+			return errors.Wrapf(err, "unable to re-link series ID %s to %s", alias.OldSeriesID, alias.NewSeriesID)
+		}
+		log15.Info("insights: re-linked series ID", "old_series_id", alias.OldSeriesID, "new_series_id", alias.NewSeriesID, "encoding_version", alias.EncodingVersion, "points", points)
+	}
+	return nil
+}
+
+// seriesIDAliases returns the aliases from the series IDs of the series of the given insights under
+// the given encodings to their current series IDs. Old series IDs that are the current series ID of
+// a series are not aliased, so that the data of a series is never re-linked to another series.
+func seriesIDAliases(discovered []insights.SearchInsight, encodings []SeriesIDEncoding) []store.SeriesIDAlias {
+	current := map[string]struct{}{}
+	for _, insight := range discovered {
+		for _, series := range insight.Series {
+			current[Encode(series)] = struct{}{}
+		}
+	}
+
+	aliases := make([]store.SeriesIDAlias, 0)
+	seen := map[string]struct{}{}
+	for _, insight := range discovered {
+		for _, series := range insight.Series {
+			seriesID := Encode(series)
+			for _, encoding := range encodings {
+				oldSeriesID := encoding.Encode(series)
+				if oldSeriesID == "" {
+					continue
+				}
+				if _, ok := current[oldSeriesID]; ok {
+					continue
+				}
+				if _, ok := seen[oldSeriesID]; ok {
+					continue
+				}
+				seen[oldSeriesID] = struct{}{}
+				aliases = append(aliases, store.SeriesIDAlias{
+					OldSeriesID:     oldSeriesID,
+					NewSeriesID:     seriesID,
+					EncodingVersion: encoding.Version,
+				})
+			}
+		}
+	}
+	return aliases
+}
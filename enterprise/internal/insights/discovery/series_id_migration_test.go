@@ -0,0 +1,83 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestSeriesIDAliases(t *testing.T) {
+	errorf := insights.TimeSeries{Query: "errorf"}
+	panics := insights.TimeSeries{Query: "panic("}
+	webhook := insights.TimeSeries{Webhook: "https://example.com/getData"}
+	discovered := []insights.SearchInsight{
+		{ID: "one", Series: []insights.TimeSeries{errorf, panics, webhook}},
+		{ID: "two", Series: []insights.TimeSeries{errorf}},
+	}
+	encodings := []SeriesIDEncoding{
+		{Version: 1, Encode: func(series insights.TimeSeries) string {
+			if series.Webhook != "" {
+				// Webhook series did not exist yet.
+				return ""
+			}
+			return "v1:" + series.Query
+		}},
+		{Version: 2, Encode: func(series insights.TimeSeries) string {
+			if series.Query == "panic(" {
+				// Collides with the current series ID of another series.
+				return Encode(errorf)
+			}
+			return Encode(series)
+		}},
+	}
+
+	want := []store.SeriesIDAlias{
+		{OldSeriesID: "v1:errorf", NewSeriesID: Encode(errorf), EncodingVersion: 1},
+		{OldSeriesID: "v1:panic(", NewSeriesID: Encode(panics), EncodingVersion: 1},
+	}
+	if diff := cmp.Diff(want, seriesIDAliases(discovered, encodings)); diff != "" {
+		t.Errorf("unexpected aliases (-want +got):\n%s", diff)
+	}
+}
+
+func TestMigrateSeriesIDs(t *testing.T) {
+	ctx := context.Background()
+	series := insights.TimeSeries{Query: "errorf"}
+	encodings := []SeriesIDEncoding{{Version: 1, Encode: func(series insights.TimeSeries) string {
+		return "v1:" + series.Query
+	}}}
+	unrelinked := []store.SeriesIDAlias{
+		{OldSeriesID: "v1:errorf", NewSeriesID: Encode(series), EncodingVersion: 1},
+		{OldSeriesID: "v1:removed", NewSeriesID: "s:removed", EncodingVersion: 1},
+	}
+
+	aliasStore := NewMockSeriesIDAliasStore()
+	aliasStore.SeriesIDAliasesFunc.SetDefaultReturn(unrelinked, nil)
+	if err := migrateSeriesIDs(ctx, aliasStore, []insights.SearchInsight{{ID: "one", Series: []insights.TimeSeries{series}}}, encodings); err != nil {
+		t.Fatalf("unexpected error migrating series IDs: %s", err)
+	}
+
+	recorded := aliasStore.RecordSeriesIDAliasesFunc.History()
+	if len(recorded) != 1 {
+		t.Fatalf("unexpected number of alias recordings. want=%d have=%d", 1, len(recorded))
+	}
+	if diff := cmp.Diff(unrelinked[:1], recorded[0].Arg1); diff != "" {
+		t.Errorf("unexpected recorded aliases (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(store.SeriesIDAliasesOpts{Unrelinked: true}, aliasStore.SeriesIDAliasesFunc.History()[0].Arg1); diff != "" {
+		t.Errorf("unexpected alias options (-want +got):\n%s", diff)
+	}
+
+	// Aliases recorded earlier are re-linked even if their series are no longer discovered.
+	var relinked []store.SeriesIDAlias
+	for _, call := range aliasStore.RelinkSeriesIDAliasFunc.History() {
+		relinked = append(relinked, call.Arg1)
+	}
+	if diff := cmp.Diff(unrelinked, relinked); diff != "" {
+		t.Errorf("unexpected re-linked aliases (-want +got):\n%s", diff)
+	}
+}
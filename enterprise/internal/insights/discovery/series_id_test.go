@@ -6,6 +6,7 @@ import (
 
 	"github.com/hexops/autogold"
 
+	"github.com/sourcegraph/sourcegraph/internal/insights"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -21,6 +22,27 @@ func TestEncodeSeriesID(t *testing.T) {
 				"<nil>",
 			}),
 		},
+		{
+			input: &schema.InsightSeries{Search: "go (\\d\\.\\d+) patternType:regexp", GeneratedFromCaptureGroups: true},
+			want: autogold.Want("capture_group_search", [2]interface{}{
+				"c:97AF0143BC76DB6F5C7575468506848AC427D022F207A34E603EA45E3B9B8DD4",
+				"<nil>",
+			}),
+		},
+		{
+			input: &schema.InsightSeries{Search: "type:commit fix", GroupBy: "author"},
+			want: autogold.Want("author_search", [2]interface{}{
+				"a:44347BC44C092ED5F0BA439091F688A5CB47DFBD634499B851656F2E87EE91D1",
+				"<nil>",
+			}),
+		},
+		{
+			input: &schema.InsightSeries{Search: "type:commit fix", GroupBy: "team"},
+			want: autogold.Want("team_search", [2]interface{}{
+				"t:44347BC44C092ED5F0BA439091F688A5CB47DFBD634499B851656F2E87EE91D1",
+				"<nil>",
+			}),
+		},
 		{
 			input: &schema.InsightSeries{Webhook: "https://example.com/getData?foo=bar"},
 			want: autogold.Want("basic_webhook", [2]interface{}{
@@ -30,7 +52,7 @@ func TestEncodeSeriesID(t *testing.T) {
 		},
 		{
 			input: &schema.InsightSeries{},
-			want:  autogold.Want("invalid", [2]interface{}{"", "invalid series &{Label: RepositoriesList:[] Search: Webhook:}"}),
+			want:  autogold.Want("invalid", [2]interface{}{"", "invalid series &{BusinessDaysOnly:false Expression: GeneratedFromCaptureGroups:false GroupBy: Interval: Label: RepositoriesList:[] Search: Webhook:}"}),
 		},
 	}
 	for _, tc := range testCases {
@@ -40,3 +62,67 @@ func TestEncodeSeriesID(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeWebhookSeries(t *testing.T) {
+	webhook := "https://example.com/getData?foo=bar"
+	want, err := EncodeSeriesID(&schema.InsightSeries{Webhook: webhook})
+	if err != nil {
+		t.Fatalf("unexpected error encoding series ID: %s", err)
+	}
+	if have := Encode(insights.TimeSeries{Webhook: webhook}); have != want {
+		t.Errorf("unexpected series ID. want=%q have=%q", want, have)
+	}
+	if !IsWebhookSeries(want) {
+		t.Errorf("expected %q to identify a webhook series", want)
+	}
+}
+
+func TestEncodeNamespacedSeries(t *testing.T) {
+	global := Encode(insights.TimeSeries{Query: "errorf"})
+	orgSeries := Encode(insights.TimeSeries{Query: "errorf", Namespace: insights.Namespace{OrgID: 1}})
+	userSeries := Encode(insights.TimeSeries{Query: "errorf", Namespace: insights.Namespace{UserID: 1}})
+
+	seriesIDs := map[string]struct{}{global: {}, orgSeries: {}, userSeries: {}}
+	if len(seriesIDs) != 3 {
+		t.Errorf("expected distinct series IDs for each namespace. have=%q", []string{global, orgSeries, userSeries})
+	}
+	if have := Encode(insights.TimeSeries{Query: "errorf", Namespace: insights.Namespace{OrgID: 1}}); have != orgSeries {
+		t.Errorf("unexpected series ID. want=%q have=%q", orgSeries, have)
+	}
+	if !IsCaptureGroupSeries(Encode(insights.TimeSeries{Query: `(\w+)`, GeneratedFromCaptureGroups: true, Namespace: insights.Namespace{OrgID: 1}})) {
+		t.Errorf("expected capture group series ID in a namespace")
+	}
+}
+
+func TestEncodeAttributionSeries(t *testing.T) {
+	for _, groupBy := range []insights.Attribution{insights.AttributeToAuthor, insights.AttributeToTeam} {
+		want, err := EncodeSeriesID(&schema.InsightSeries{Search: "type:commit fix", GroupBy: string(groupBy)})
+		if err != nil {
+			t.Fatalf("unexpected error encoding series ID: %s", err)
+		}
+		if have := Encode(insights.TimeSeries{Query: "type:commit fix", GroupBy: groupBy}); have != want {
+			t.Errorf("unexpected series ID. want=%q have=%q", want, have)
+		}
+		if have := GroupByOf(want); have != groupBy {
+			t.Errorf("unexpected attribution of series ID %q. want=%q have=%q", want, groupBy, have)
+		}
+		if !IsSplitSeries(want) {
+			t.Errorf("expected %q to identify a split series", want)
+		}
+	}
+	if seriesID := Encode(insights.TimeSeries{Query: "type:commit fix"}); GroupByOf(seriesID) != "" || IsSplitSeries(seriesID) {
+		t.Errorf("expected %q to identify a series that is not split", seriesID)
+	}
+}
+
+func TestEncodeScopedSeries(t *testing.T) {
+	unscoped := Encode(insights.TimeSeries{Query: "errorf"})
+	scoped := Encode(insights.TimeSeries{Query: "errorf", Repositories: []string{"github.com/a/b", "github.com/c/d"}})
+
+	if scoped == unscoped {
+		t.Errorf("expected distinct series IDs for scoped and unscoped series. have=%q", scoped)
+	}
+	if have := Encode(insights.TimeSeries{Query: "errorf", Repositories: []string{"github.com/c/d", "github.com/a/b"}}); have != scoped {
+		t.Errorf("unexpected series ID for reordered repositories. want=%q have=%q", scoped, have)
+	}
+}
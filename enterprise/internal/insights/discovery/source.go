@@ -0,0 +1,163 @@
+package discovery
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// Source is an origin of insight definitions, such as the database or settings. The insights of
+// all sources are merged and deduplicated by DiscoverFrom, so that insights can be defined in new
+// places without changing the consumers of discovery, such as the enqueuers.
+type Source interface {
+	// Discover returns the insights of the source. Sources only need to apply the Ids and
+	// DashboardID filters of the given arguments, which they may apply more efficiently than
+	// DiscoverFrom; the other filters are applied by DiscoverFrom. Sources of insights that cannot
+	// be on a dashboard return no insights if a dashboard is given.
+	Discover(ctx context.Context, args InsightFilterArgs) ([]insights.SearchInsight, error)
+}
+
+// DiscoverFrom returns the insights of the given sources, filtered by the given arguments. An
+// insight defined by several sources is discovered from the first of them, so sources are given
+// in order of precedence.
+//
+// 🚨 SECURITY: Insights of all namespaces are discovered unless args restricts the namespaces.
+func DiscoverFrom(ctx context.Context, sources []Source, args InsightFilterArgs) ([]insights.SearchInsight, error) {
+	discovered := make([]insights.SearchInsight, 0)
+	seen := map[string]struct{}{}
+	for _, source := range sources {
+		fromSource, err := source.Discover(ctx, args)
+		if err != nil {
+			return []insights.SearchInsight{}, err
+		}
+		fromSource = applyFilters(fromSource, args)
+		for _, insight := range fromSource {
+			if _, ok := seen[insight.ID]; ok {
+				continue
+			}
+			discovered = append(discovered, insight)
+		}
+		// Only insights of earlier sources are skipped, as a source is responsible for the
+		// uniqueness of its own insights.
+		for _, insight := range fromSource {
+			seen[insight.ID] = struct{}{}
+		}
+	}
+	if len(args.Namespaces) > 0 {
+		discovered = filterByNamespaces(args.Namespaces, discovered)
+	}
+	if len(args.SeriesIDs) > 0 {
+		discovered = filterBySeriesIDs(args.SeriesIDs, discovered)
+	}
+	return discovered, nil
+}
+
+// NewDatabaseSource returns a source of the insights stored in the insights database.
+func NewDatabaseSource(insightStore InsightStore) Source {
+	return &databaseSource{insightStore: insightStore}
+}
+
+type databaseSource struct {
+	insightStore InsightStore
+}
+
+func (s *databaseSource) Discover(ctx context.Context, args InsightFilterArgs) ([]insights.SearchInsight, error) {
+	viewSeries, err := s.insightStore.Get(ctx, store.InsightQueryArgs{UniqueIDs: args.Ids, DashboardID: args.DashboardID})
+	if err != nil {
+		return nil, errors.Wrap(err, "Get")
+	}
+	return convertFromViewSeries(viewSeries), nil
+}
+
+// NewSettingsSource returns a source of the insights defined in the global user settings and in
+// extension settings.
+//
+// TODO(insights): stop discovering insights from settings once insights can no longer be defined
+// in settings.
+func NewSettingsSource(settingStore SettingStore, loader insights.Loader) Source {
+	return &settingsSource{settingStore: settingStore, loader: loader}
+}
+
+type settingsSource struct {
+	settingStore SettingStore
+	loader       insights.Loader
+}
+
+func (s *settingsSource) Discover(ctx context.Context, args InsightFilterArgs) ([]insights.SearchInsight, error) {
+	if args.DashboardID != 0 {
+		// Insights that have not been migrated to the database are not on any dashboard.
+		return nil, nil
+	}
+	return discoverAll(ctx, s.settingStore, s.loader)
+}
+
+// RegistrySource is a source of insights registered at runtime, e.g. by an extension or through an
+// API, rather than stored in the database or defined in settings. Registered insights are not on
+// any dashboard. It is safe for concurrent use.
+type RegistrySource struct {
+	mu       sync.RWMutex
+	insights map[string]insights.SearchInsight
+}
+
+// NewRegistrySource returns a source without any registered insights.
+func NewRegistrySource() *RegistrySource {
+	return &RegistrySource{insights: map[string]insights.SearchInsight{}}
+}
+
+// Register registers the given insight, replacing any insight registered with the same ID.
+func (s *RegistrySource) Register(insight insights.SearchInsight) error {
+	if insight.ID == "" {
+		return errors.New("registered insight has no ID")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.insights[insight.ID] = insight
+	return nil
+}
+
+// Unregister removes the insight with the given ID, if it is registered.
+func (s *RegistrySource) Unregister(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.insights, id)
+}
+
+func (s *RegistrySource) Discover(ctx context.Context, args InsightFilterArgs) ([]insights.SearchInsight, error) {
+	if args.DashboardID != 0 {
+		return nil, nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	registered := make([]insights.SearchInsight, 0, len(s.insights))
+	for _, insight := range s.insights {
+		registered = append(registered, insight)
+	}
+	sort.Slice(registered, func(i, j int) bool { return registered[i].ID < registered[j].ID })
+	return registered, nil
+}
+
+var (
+	additionalSourcesMu sync.RWMutex
+	additionalSources   []Source
+)
+
+// RegisterSource adds the given source to the sources of Discover, after the database and settings,
+// e.g. when an extension or an API is initialized. Sources of the same insights as the database or
+// settings do not override them.
+func RegisterSource(source Source) {
+	additionalSourcesMu.Lock()
+	defer additionalSourcesMu.Unlock()
+	additionalSources = append(additionalSources, source)
+}
+
+// registeredSources returns the sources added with RegisterSource.
+func registeredSources() []Source {
+	additionalSourcesMu.RLock()
+	defer additionalSourcesMu.RUnlock()
+	return append([]Source(nil), additionalSources...)
+}
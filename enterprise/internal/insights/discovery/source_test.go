@@ -0,0 +1,120 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestDiscoverFrom(t *testing.T) {
+	ctx := context.Background()
+	org := insights.Namespace{OrgID: 1}
+
+	first := NewRegistrySource()
+	second := NewRegistrySource()
+	for _, insight := range []insights.SearchInsight{
+		{ID: "shared", Title: "first"},
+		{ID: "org", Title: "first", Namespace: org},
+	} {
+		if err := first.Register(insight); err != nil {
+			t.Fatalf("unexpected error registering insight: %s", err)
+		}
+	}
+	for _, insight := range []insights.SearchInsight{
+		{ID: "shared", Title: "second"},
+		{ID: "only-second", Title: "second"},
+	} {
+		if err := second.Register(insight); err != nil {
+			t.Fatalf("unexpected error registering insight: %s", err)
+		}
+	}
+	sources := []Source{first, second}
+
+	// Insights defined by several sources are discovered from the first of them.
+	discovered, err := DiscoverFrom(ctx, sources, InsightFilterArgs{})
+	if err != nil {
+		t.Fatalf("unexpected error discovering insights: %s", err)
+	}
+	want := []insights.SearchInsight{
+		{ID: "org", Title: "first", Namespace: org},
+		{ID: "shared", Title: "first"},
+		{ID: "only-second", Title: "second"},
+	}
+	if diff := cmp.Diff(want, discovered); diff != "" {
+		t.Errorf("unexpected insights (-want +got):\n%s", diff)
+	}
+
+	discovered, err = DiscoverFrom(ctx, sources, InsightFilterArgs{Ids: []string{"only-second"}})
+	if err != nil {
+		t.Fatalf("unexpected error discovering insights: %s", err)
+	}
+	if diff := cmp.Diff([]insights.SearchInsight{{ID: "only-second", Title: "second"}}, discovered); diff != "" {
+		t.Errorf("unexpected insights filtered by ID (-want +got):\n%s", diff)
+	}
+
+	discovered, err = DiscoverFrom(ctx, sources, InsightFilterArgs{Namespaces: []insights.Namespace{org}})
+	if err != nil {
+		t.Fatalf("unexpected error discovering insights: %s", err)
+	}
+	if diff := cmp.Diff([]insights.SearchInsight{{ID: "org", Title: "first", Namespace: org}}, discovered); diff != "" {
+		t.Errorf("unexpected insights filtered by namespace (-want +got):\n%s", diff)
+	}
+
+	// Registered insights are not on any dashboard.
+	discovered, err = DiscoverFrom(ctx, sources, InsightFilterArgs{DashboardID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error discovering insights: %s", err)
+	}
+	if len(discovered) != 0 {
+		t.Errorf("unexpected number of insights on dashboard. want=%d have=%d", 0, len(discovered))
+	}
+
+	first.Unregister("shared")
+	discovered, err = DiscoverFrom(ctx, sources, InsightFilterArgs{Ids: []string{"shared"}})
+	if err != nil {
+		t.Fatalf("unexpected error discovering insights: %s", err)
+	}
+	if diff := cmp.Diff([]insights.SearchInsight{{ID: "shared", Title: "second"}}, discovered); diff != "" {
+		t.Errorf("unexpected insights after unregistering (-want +got):\n%s", diff)
+	}
+
+	if err := first.Register(insights.SearchInsight{Title: "no ID"}); err == nil {
+		t.Errorf("expected error registering insight without ID")
+	}
+}
+
+func TestDiscoverFromError(t *testing.T) {
+	insightStore := NewMockInsightStore()
+	insightStore.GetFunc.SetDefaultReturn(nil, errors.New("database unavailable"))
+
+	if _, err := DiscoverFrom(context.Background(), []Source{NewDatabaseSource(insightStore), NewRegistrySource()}, InsightFilterArgs{}); err == nil {
+		t.Errorf("expected error discovering insights from failing source")
+	}
+}
+
+func TestDiscoverRegisteredSources(t *testing.T) {
+	defer func() { additionalSources = nil }()
+
+	settingStore := NewMockSettingStore()
+	settingStore.GetLatestFunc.SetDefaultReturn(settingsExample, nil)
+	loader := insights.NewMockLoader()
+	loader.LoadAllFunc.SetDefaultReturn(nil, nil)
+
+	registry := NewRegistrySource()
+	if err := registry.Register(insights.SearchInsight{ID: "registered", Title: "Registered insight"}); err != nil {
+		t.Fatalf("unexpected error registering insight: %s", err)
+	}
+	RegisterSource(registry)
+
+	discovered, err := Discover(context.Background(), NewMockInsightStore(), settingStore, loader, InsightFilterArgs{Ids: []string{"registered"}})
+	if err != nil {
+		t.Fatalf("unexpected error discovering insights: %s", err)
+	}
+	if diff := cmp.Diff([]insights.SearchInsight{{ID: "registered", Title: "Registered insight"}}, discovered); diff != "" {
+		t.Errorf("unexpected insights (-want +got):\n%s", diff)
+	}
+}
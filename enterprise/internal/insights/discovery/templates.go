@@ -0,0 +1,84 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// maxTemplateInstances is the maximum number of repositories a single insight template is
+// instantiated for, so that a broad repository pattern cannot create an insight for every
+// repository of the instance.
+const maxTemplateInstances = 1000
+
+// instantiateTemplates returns the instances of the given insight templates: an insight for every
+// repository whose name matches the repository pattern of a template, whose series only search that
+// repository. Instances are identified by their template and repository, so that the same
+// repository always yields the same instance, and belong to the namespace of their template.
+//
+// Templates with an invalid repository pattern are skipped. Templates are instantiated for the
+// first maxTemplateInstances matching repositories, in the order they were added, so that the
+// instances of a template do not change as further repositories are added.
+func instantiateTemplates(ctx context.Context, repoStore RepoStore, templates []insights.InsightTemplate) ([]insights.SearchInsight, error) {
+	instances := make([]insights.SearchInsight, 0)
+	for _, template := range templates {
+		if template.ID == "" || len(template.Series) == 0 {
+			continue
+		}
+		if template.RepositoryPattern == "" {
+			log15.Error("insights: insight template without repository pattern", "template", template.ID)
+			continue
+		}
+		if _, err := regexp.Compile(template.RepositoryPattern); err != nil {
+			log15.Error("insights: invalid repository pattern of insight template", "template", template.ID, "error", err)
+			continue
+		}
+
+		repos, err := repoStore.List(ctx, database.ReposListOptions{
+			IncludePatterns: []string{template.RepositoryPattern},
+			OrderBy:         database.RepoListOrderBy{{Field: database.RepoListID}},
+			LimitOffset:     &database.LimitOffset{Limit: maxTemplateInstances + 1},
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing repositories of insight template %q", template.ID)
+		}
+		if len(repos) > maxTemplateInstances {
+			log15.Warn("insights: insight template matches too many repositories, only the first are instantiated", "template", template.ID, "limit", maxTemplateInstances)
+			repos = repos[:maxTemplateInstances]
+		}
+
+		for _, repo := range repos {
+			instances = append(instances, instantiateTemplate(template, string(repo.Name), int32(repo.ID)))
+		}
+	}
+	return instances, nil
+}
+
+// instantiateTemplate returns the instance of the given template for the given repository.
+func instantiateTemplate(template insights.InsightTemplate, repoName string, repoID int32) insights.SearchInsight {
+	instance := insights.SearchInsight{
+		ID:           fmt.Sprintf("%s.repo-%d", template.ID, repoID),
+		Title:        strings.ReplaceAll(template.Title, insights.TemplateRepositoryPlaceholder, repoName),
+		Description:  strings.ReplaceAll(template.Description, insights.TemplateRepositoryPlaceholder, repoName),
+		Repositories: []string{repoName},
+		Namespace:    template.Namespace,
+	}
+	for _, series := range template.Series {
+		instance.Series = append(instance.Series, insights.TimeSeries{
+			Name:         series.Name,
+			Stroke:       series.Stroke,
+			Query:        series.Query,
+			Interval:     series.Interval,
+			Namespace:    template.Namespace,
+			Repositories: []string{repoName},
+		})
+	}
+	return instance
+}
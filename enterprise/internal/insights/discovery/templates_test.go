@@ -0,0 +1,105 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestInstantiateTemplates(t *testing.T) {
+	ctx := context.Background()
+	namespace := insights.Namespace{OrgID: 1}
+	templates := []insights.InsightTemplate{
+		{
+			ID:                "go-version",
+			Title:             "Go version adoption in {repository}",
+			Description:       "Go versions of {repository}",
+			RepositoryPattern: "^github\\.com/sourcegraph/",
+			Series: []insights.TimeSeries{{
+				Name:     "Go 1.16",
+				Query:    "file:go.mod go 1.16",
+				Interval: insights.Weekly,
+			}},
+			Namespace: namespace,
+		},
+		{
+			ID:                "invalid",
+			Title:             "Invalid repository pattern",
+			RepositoryPattern: "(",
+			Series:            []insights.TimeSeries{{Name: "TODO", Query: "TODO"}},
+			Namespace:         namespace,
+		},
+	}
+
+	repoStore := NewMockRepoStore()
+	repoStore.ListFunc.SetDefaultHook(func(ctx context.Context, opt database.ReposListOptions) ([]*types.Repo, error) {
+		if diff := cmp.Diff([]string{"^github\\.com/sourcegraph/"}, opt.IncludePatterns); diff != "" {
+			t.Errorf("unexpected include patterns (-want +got):\n%s", diff)
+		}
+		return []*types.Repo{
+			{ID: 1, Name: "github.com/sourcegraph/sourcegraph"},
+			{ID: 2, Name: "github.com/sourcegraph/zoekt"},
+		}, nil
+	})
+
+	instances, err := instantiateTemplates(ctx, repoStore, templates)
+	if err != nil {
+		t.Fatalf("unexpected error instantiating templates: %s", err)
+	}
+
+	instance := func(id, repoName string) insights.SearchInsight {
+		return insights.SearchInsight{
+			ID:           id,
+			Title:        "Go version adoption in " + repoName,
+			Description:  "Go versions of " + repoName,
+			Repositories: []string{repoName},
+			Series: []insights.TimeSeries{{
+				Name:         "Go 1.16",
+				Query:        "file:go.mod go 1.16",
+				Interval:     insights.Weekly,
+				Namespace:    namespace,
+				Repositories: []string{repoName},
+			}},
+			Namespace: namespace,
+		}
+	}
+	want := []insights.SearchInsight{
+		instance("go-version.repo-1", "github.com/sourcegraph/sourcegraph"),
+		instance("go-version.repo-2", "github.com/sourcegraph/zoekt"),
+	}
+	if diff := cmp.Diff(want, instances); diff != "" {
+		t.Errorf("unexpected template instances (-want +got):\n%s", diff)
+	}
+	if calls := len(repoStore.ListFunc.History()); calls != 1 {
+		t.Errorf("unexpected number of repository listings. want=%d have=%d", 1, calls)
+	}
+}
+
+func TestInstantiateTemplatesLimit(t *testing.T) {
+	repoStore := NewMockRepoStore()
+	repoStore.ListFunc.SetDefaultHook(func(ctx context.Context, opt database.ReposListOptions) ([]*types.Repo, error) {
+		repos := make([]*types.Repo, 0, opt.Limit)
+		for i := 1; i <= opt.Limit; i++ {
+			repos = append(repos, &types.Repo{ID: api.RepoID(i), Name: "github.com/sourcegraph/repo"})
+		}
+		return repos, nil
+	})
+
+	instances, err := instantiateTemplates(context.Background(), repoStore, []insights.InsightTemplate{{
+		ID:                "broad",
+		RepositoryPattern: ".*",
+		Series:            []insights.TimeSeries{{Name: "TODO", Query: "TODO"}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error instantiating templates: %s", err)
+	}
+	if len(instances) != maxTemplateInstances {
+		t.Errorf("unexpected number of template instances. want=%d have=%d", maxTemplateInstances, len(instances))
+	}
+}
@@ -0,0 +1,113 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// ValidateInsights returns the problems of the given insights that keep them, or some of their
+// series, from being recorded: series with invalid search queries, unsupported filters or invalid
+// repository scopes, and insights that cannot be identified or have nothing to record.
+//
+// Validation is a dry run: nothing is enqueued or recorded.
+func ValidateInsights(discovered []insights.SearchInsight) []types.InsightProblem {
+	problems := make([]types.InsightProblem, 0)
+	seen := make(map[string]struct{}, len(discovered))
+	for _, insight := range discovered {
+		problem := func(series *insights.TimeSeries, format string, args ...interface{}) types.InsightProblem {
+			p := types.InsightProblem{InsightID: insight.ID, Problem: fmt.Sprintf(format, args...)}
+			if series != nil {
+				p.SeriesID = Encode(*series)
+				p.SeriesLabel = series.Name
+			}
+			if userID := insight.Namespace.UserID; userID != 0 {
+				p.UserID = &userID
+			}
+			if orgID := insight.Namespace.OrgID; orgID != 0 {
+				p.OrgID = &orgID
+			}
+			return p
+		}
+
+		if insight.ID == "" {
+			problems = append(problems, problem(nil, "insight %q has no ID", insight.Title))
+			continue
+		}
+		if _, ok := seen[insight.ID]; ok {
+			problems = append(problems, problem(nil, "insight ID %q is defined more than once", insight.ID))
+			continue
+		}
+		seen[insight.ID] = struct{}{}
+		if len(insight.Series) == 0 {
+			problems = append(problems, problem(nil, "insight has no series"))
+			continue
+		}
+
+		for i := range insight.Series {
+			series := insight.Series[i]
+			switch series.Interval {
+			case "", insights.Hourly, insights.Daily, insights.Weekly, insights.Monthly:
+			default:
+				problems = append(problems, problem(&series, "unknown recording interval %q: the series is recorded daily", series.Interval))
+			}
+			if series.Webhook == "" && series.Expression == "" && strings.TrimSpace(series.Query) == "" {
+				problems = append(problems, problem(&series, "series has neither a search query, a webhook, nor an expression"))
+				continue
+			}
+			if err := ValidateSeries(series); err != nil {
+				problems = append(problems, problem(&series, "%s", err))
+			}
+		}
+	}
+	return problems
+}
+
+type insightValidator struct {
+	base     dbutil.DB
+	insights dbutil.DB
+}
+
+// NewValidateInsightsJob returns a background routine that periodically validates all discovered
+// insights (see ValidateInsights), checks them against the limits of the license (see
+// ApplyLimits), and replaces the insight problems stored in the database with
+// the problems it finds, so that users and site admins can find out why insights are not recorded.
+// It also replaces the stored duplicate insights (see DetectDuplicateInsights), so that site admins
+// can merge them.
+func NewValidateInsightsJob(ctx context.Context, base dbutil.DB, insights dbutil.DB) goroutine.BackgroundRoutine {
+	// Problems are introduced by changes to insight definitions, which are migrated from settings
+	// at the same interval.
+	interval := 10 * time.Minute
+	v := insightValidator{
+		base:     base,
+		insights: insights,
+	}
+
+	return goroutine.NewPeriodicGoroutine(ctx, interval,
+		goroutine.NewHandlerWithErrorMessage("insight_validator", v.validate))
+}
+
+func (v *insightValidator) validate(ctx context.Context) error {
+	insightStore := store.NewInsightStore(v.insights)
+	discovered, err := Discover(ctx, insightStore, database.Settings(v.base), insights.NewLoader(v.base), InsightFilterArgs{})
+	if err != nil {
+		return err
+	}
+	limits, err := LicenseLimits()
+	if err != nil {
+		return err
+	}
+	_, overLimit := ApplyLimits(discovered, limits)
+	if err := insightStore.ReplaceInsightProblems(ctx, append(ValidateInsights(discovered), overLimit...)); err != nil {
+		return err
+	}
+	return insightStore.ReplaceInsightDuplicates(ctx, DetectDuplicateInsights(discovered))
+}
@@ -0,0 +1,41 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestValidateInsights(t *testing.T) {
+	userID := int32(1)
+	unknownInterval := insights.TimeSeries{Name: "yearly", Query: "errorf", Interval: "yearly", Namespace: insights.Namespace{UserID: userID}}
+	badQuery := insights.TimeSeries{Name: "revisions", Query: "repo:sourcegraph@main errorf", Namespace: insights.Namespace{UserID: userID}}
+	noQuery := insights.TimeSeries{Name: "empty", Query: " ", Namespace: insights.Namespace{UserID: userID}}
+
+	problems := ValidateInsights([]insights.SearchInsight{
+		{ID: "valid", Series: []insights.TimeSeries{{Name: "errorf", Query: "errorf"}}},
+		{Title: "no ID", Series: []insights.TimeSeries{{Name: "errorf", Query: "errorf"}}},
+		{ID: "valid", Series: []insights.TimeSeries{{Name: "errorf", Query: "errorf"}}},
+		{ID: "no-series"},
+		{
+			ID:        "invalid-series",
+			Namespace: insights.Namespace{UserID: userID},
+			Series:    []insights.TimeSeries{unknownInterval, {Name: "valid", Query: "errorf"}, badQuery, noQuery},
+		},
+	})
+
+	want := []types.InsightProblem{
+		{Problem: `insight "no ID" has no ID`},
+		{InsightID: "valid", Problem: `insight ID "valid" is defined more than once`},
+		{InsightID: "no-series", Problem: "insight has no series"},
+		{InsightID: "invalid-series", SeriesID: Encode(unknownInterval), SeriesLabel: "yearly", UserID: &userID, Problem: `unknown recording interval "yearly": the series is recorded daily`},
+		{InsightID: "invalid-series", SeriesID: Encode(badQuery), SeriesLabel: "revisions", UserID: &userID, Problem: `unsupported filter repo:sourcegraph@main in search query "repo:sourcegraph@main errorf": insights do not support searching for revisions`},
+		{InsightID: "invalid-series", SeriesID: Encode(noQuery), SeriesLabel: "empty", UserID: &userID, Problem: "series has neither a search query, a webhook, nor an expression"},
+	}
+	if diff := cmp.Diff(want, problems); diff != "" {
+		t.Errorf("unexpected problems (-want +got):\n%s", diff)
+	}
+}
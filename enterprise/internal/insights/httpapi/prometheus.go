@@ -0,0 +1,388 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/inconshreveable/log15"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+)
+
+// metricName is the name of the only metric served by the Prometheus API, whose series are
+// selected by the series_id label (and the capture label, for series generated from capture
+// groups).
+const metricName = "insight_series"
+
+type prometheusHandler struct {
+	insightsStore store.Interface
+	isEnabled     func() bool
+	now           func() time.Time
+
+	// isVisibleSeries reports whether the given series belongs to an insight the current user
+	// can see.
+	isVisibleSeries func(ctx context.Context, seriesID string) (bool, error)
+}
+
+// NewPrometheusHandler returns a handler serving the data points of insight series through a
+// read-only subset of the Prometheus HTTP API, so that they can be overlaid on Grafana dashboards
+// with a Prometheus data source. Only the /api/v1/query and /api/v1/query_range endpoints are
+// supported, and only for queries selecting a single series, e.g.:
+//
+//     insight_series{series_id="s:087855E6A24440837303FD8A252E9893E8ABDFECA55B61AC83DA1B521906626E"}
+//
+// The handler responds 404 unless insights.prometheusAPI.enabled is set in the site configuration.
+// Series of insights outside the namespaces visible to the user have no data points.
+//
+// 🚨 SECURITY: The caller MUST wrap the returned handler in middleware that checks authentication
+// and sets the actor in the request context.
+func NewPrometheusHandler(timescale, postgres dbutil.DB) http.Handler {
+	insightStore := store.NewInsightStore(timescale)
+	settingStore := database.Settings(postgres)
+	loader := insights.NewLoader(postgres)
+
+	return &prometheusHandler{
+		insightsStore: store.New(timescale, store.NewInsightPermissionStore(postgres)),
+		isEnabled:     func() bool { return conf.Get().InsightsPrometheusAPIEnabled },
+		now:           time.Now,
+		isVisibleSeries: func(ctx context.Context, seriesID string) (bool, error) {
+			namespaces, err := discovery.VisibleNamespaces(ctx, postgres)
+			if err != nil {
+				return false, err
+			}
+			discovered, err := discovery.Discover(ctx, insightStore, settingStore, loader, discovery.InsightFilterArgs{Namespaces: namespaces, SeriesIDs: []string{seriesID}})
+			if err != nil {
+				return false, errors.Wrap(err, "Discover")
+			}
+			for _, insight := range discovered {
+				for _, series := range insight.Series {
+					if discovery.Encode(series) == seriesID {
+						return true, nil
+					}
+				}
+			}
+			return false, nil
+		},
+	}
+}
+
+// GET/POST /insights/prometheus/api/v1/{query,query_range}
+func (h *prometheusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.isEnabled() {
+		http.Error(w, "the insights Prometheus API is not enabled, set insights.prometheusAPI.enabled in the site configuration to enable it", http.StatusNotFound)
+		return
+	}
+	// 🚨 SECURITY: Data points are filtered by the repositories the actor can access, so anonymous
+	// requests would only see the data points of public repositories. Require authentication
+	// rather than returning partial data silently.
+	if !actor.FromContext(r.Context()).IsAuthenticated() {
+		writeError(w, http.StatusUnauthorized, "unauthorized", errors.New("authentication required"))
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_data", err)
+		return
+	}
+
+	var (
+		data interface{}
+		err  error
+	)
+	switch path.Base(r.URL.Path) {
+	case "query":
+		data, err = h.query(r)
+	case "query_range":
+		data, err = h.queryRange(r)
+	default:
+		writeError(w, http.StatusNotFound, "not_found", errors.Errorf("unsupported endpoint %q", r.URL.Path))
+		return
+	}
+	if err != nil {
+		var e *badDataError
+		if errors.As(err, &e) {
+			writeError(w, http.StatusBadRequest, "bad_data", err)
+			return
+		}
+		log15.Error("insights: failed to serve Prometheus API request", "path", r.URL.Path, "error", err)
+		writeError(w, http.StatusInternalServerError, "internal", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response{Status: "success", Data: data})
+}
+
+// query serves an instant query, returning the latest data point of the selected series at the
+// given time.
+func (h *prometheusHandler) query(r *http.Request) (interface{}, error) {
+	selector, err := parseSelector(r.Form.Get("query"))
+	if err != nil {
+		return nil, err
+	}
+	at := h.now()
+	if v := r.Form.Get("time"); v != "" {
+		if at, err = parseTime(v); err != nil {
+			return nil, err
+		}
+	}
+
+	points, err := h.seriesPoints(r.Context(), selector, nil, &at)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []sample{}
+	for _, s := range groupPoints(selector.seriesID, points) {
+		// Points are returned from the latest to the earliest.
+		result = append(result, sample{Metric: s.Metric, Value: s.Values[0]})
+	}
+	return queryData{ResultType: "vector", Result: result}, nil
+}
+
+// queryRange serves a range query, returning the data points of the selected series between the
+// given start and end times. The step is validated but ignored: data points are returned at the
+// times they were recorded.
+func (h *prometheusHandler) queryRange(r *http.Request) (interface{}, error) {
+	selector, err := parseSelector(r.Form.Get("query"))
+	if err != nil {
+		return nil, err
+	}
+	start, err := parseTime(r.Form.Get("start"))
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseTime(r.Form.Get("end"))
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, &badDataError{msg: "end timestamp must not be before start time"}
+	}
+	if _, err := parseDuration(r.Form.Get("step")); err != nil {
+		return nil, err
+	}
+
+	points, err := h.seriesPoints(r.Context(), selector, &start, &end)
+	if err != nil {
+		return nil, err
+	}
+
+	result := groupPoints(selector.seriesID, points)
+	for _, s := range result {
+		// Prometheus returns the values of a series from the earliest to the latest.
+		for i, j := 0, len(s.Values)-1; i < j; i, j = i+1, j-1 {
+			s.Values[i], s.Values[j] = s.Values[j], s.Values[i]
+		}
+	}
+	return queryData{ResultType: "matrix", Result: result}, nil
+}
+
+// seriesPoints returns the data points of the selected series between the given times, from the
+// latest to the earliest.
+func (h *prometheusHandler) seriesPoints(ctx context.Context, selector *seriesSelector, from, to *time.Time) ([]store.SeriesPoint, error) {
+	// 🚨 SECURITY: Users may only read the series of insights they can see. Series of other
+	// namespaces are indistinguishable from unknown series.
+	visible, err := h.isVisibleSeries(ctx, selector.seriesID)
+	if err != nil {
+		return nil, errors.Wrap(err, "isVisibleSeries")
+	}
+	if !visible {
+		return nil, nil
+	}
+
+	points, err := h.insightsStore.SeriesPoints(ctx, store.SeriesPointsOpts{
+		SeriesID: &selector.seriesID,
+		Capture:  selector.capture,
+		From:     from,
+		To:       to,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "SeriesPoints")
+	}
+	return points, nil
+}
+
+// groupPoints groups the given data points into one series per captured value, preserving their
+// order.
+func groupPoints(seriesID string, points []store.SeriesPoint) []*series {
+	byCapture := map[string]*series{}
+	var result []*series
+	for _, p := range points {
+		var capture string
+		if p.Capture != nil {
+			capture = *p.Capture
+		}
+		s, ok := byCapture[capture]
+		if !ok {
+			s = &series{Metric: map[string]string{"__name__": metricName, "series_id": seriesID}}
+			if p.Capture != nil {
+				s.Metric["capture"] = capture
+			}
+			byCapture[capture] = s
+			result = append(result, s)
+		}
+		s.Values = append(s.Values, samplePair{Time: p.Time, Value: p.Value})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Metric["capture"] < result[j].Metric["capture"] })
+	if result == nil {
+		result = []*series{}
+	}
+	return result
+}
+
+type seriesSelector struct {
+	seriesID string
+	capture  *string
+}
+
+var (
+	selectorPattern     = lazyregexp.New(`^\s*` + metricName + `\s*\{(.*)\}\s*$`)
+	labelMatcherPattern = lazyregexp.New(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*=\s*("(?:[^"\\]|\\.)*")\s*$`)
+)
+
+// parseSelector parses a query selecting a single series by its labels. Only equality matchers
+// are supported, and the series_id label is required.
+func parseSelector(query string) (*seriesSelector, error) {
+	match := selectorPattern.FindStringSubmatch(query)
+	if match == nil {
+		return nil, &badDataError{msg: fmt.Sprintf(`unsupported query %q, only selectors of the form %s{series_id="..."} are supported`, query, metricName)}
+	}
+
+	var selector seriesSelector
+	for _, matcher := range splitMatchers(match[1]) {
+		if strings.TrimSpace(matcher) == "" {
+			continue
+		}
+		m := labelMatcherPattern.FindStringSubmatch(matcher)
+		if m == nil {
+			return nil, &badDataError{msg: fmt.Sprintf("unsupported label matcher %q, only equality matchers are supported", strings.TrimSpace(matcher))}
+		}
+		value, err := strconv.Unquote(m[2])
+		if err != nil {
+			return nil, &badDataError{msg: fmt.Sprintf("invalid label value %s", m[2])}
+		}
+		switch m[1] {
+		case "series_id":
+			selector.seriesID = value
+		case "capture":
+			selector.capture = &value
+		default:
+			return nil, &badDataError{msg: fmt.Sprintf("unsupported label %q, only series_id and capture are supported", m[1])}
+		}
+	}
+	if selector.seriesID == "" {
+		return nil, &badDataError{msg: "a series_id label matcher is required"}
+	}
+	return &selector, nil
+}
+
+// splitMatchers splits the given label matchers on the commas outside of quoted values.
+func splitMatchers(s string) []string {
+	var (
+		matchers []string
+		quoted   bool
+		start    int
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				matchers = append(matchers, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(matchers, s[start:])
+}
+
+// parseTime parses a RFC 3339 or Unix timestamp, like Prometheus does.
+func parseTime(s string) (time.Time, error) {
+	if t, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, frac := math.Modf(t)
+		return time.Unix(int64(sec), int64(math.Round(frac*1000))*int64(time.Millisecond)).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Time{}, &badDataError{msg: fmt.Sprintf("cannot parse %q to a valid timestamp", s)}
+}
+
+// parseDuration parses a duration in seconds or in the Go duration format.
+func parseDuration(s string) (time.Duration, error) {
+	if d, err := strconv.ParseFloat(s, 64); err == nil && d > 0 {
+		return time.Duration(d * float64(time.Second)), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, &badDataError{msg: fmt.Sprintf("cannot parse %q to a valid positive duration", s)}
+}
+
+type badDataError struct{ msg string }
+
+func (e *badDataError) Error() string { return e.msg }
+
+type response struct {
+	Status    string      `json:"status"`
+	Data      interface{} `json:"data,omitempty"`
+	ErrorType string      `json:"errorType,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+type queryData struct {
+	ResultType string      `json:"resultType"`
+	Result     interface{} `json:"result"`
+}
+
+type series struct {
+	Metric map[string]string `json:"metric"`
+	Values []samplePair      `json:"values"`
+}
+
+type sample struct {
+	Metric map[string]string `json:"metric"`
+	Value  samplePair        `json:"value"`
+}
+
+// samplePair is encoded like Prometheus does, as [<unix time in seconds>, "<value>"].
+type samplePair struct {
+	Time  time.Time
+	Value float64
+}
+
+func (p samplePair) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{
+		json.Number(strconv.FormatFloat(float64(p.Time.UnixNano()/int64(time.Millisecond))/1000, 'f', -1, 64)),
+		strconv.FormatFloat(p.Value, 'f', -1, 64),
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, errorType string, err error) {
+	writeJSON(w, status, response{Status: "error", ErrorType: errorType, Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log15.Error("insights: failed to write Prometheus API response", "error", err)
+	}
+}
@@ -0,0 +1,155 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func TestPrometheusHandler(t *testing.T) {
+	t1 := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(14 * 24 * time.Hour)
+	errorf, fatalf := "errorf", "fatalf"
+
+	insightsStore := store.NewMockInterface()
+	insightsStore.SeriesPointsFunc.SetDefaultHook(func(ctx context.Context, opts store.SeriesPointsOpts) ([]store.SeriesPoint, error) {
+		if *opts.SeriesID == "s:other" {
+			return []store.SeriesPoint{{SeriesID: "s:other", Time: t2, Value: 42}}, nil
+		}
+		if *opts.SeriesID != "s:series" {
+			return nil, nil
+		}
+		// Points are returned from the latest to the earliest.
+		var points []store.SeriesPoint
+		for _, p := range []store.SeriesPoint{
+			{SeriesID: "s:series", Time: t2, Value: 3, Capture: &fatalf},
+			{SeriesID: "s:series", Time: t2, Value: 12.5, Capture: &errorf},
+			{SeriesID: "s:series", Time: t1, Value: 1, Capture: &fatalf},
+			{SeriesID: "s:series", Time: t1, Value: 10, Capture: &errorf},
+		} {
+			if opts.Capture == nil || *opts.Capture == *p.Capture {
+				points = append(points, p)
+			}
+		}
+		return points, nil
+	})
+	enabled := true
+	handler := &prometheusHandler{
+		insightsStore: insightsStore,
+		isEnabled:     func() bool { return enabled },
+		now:           func() time.Time { return t2 },
+		isVisibleSeries: func(ctx context.Context, seriesID string) (bool, error) {
+			// s:other belongs to an insight in the namespace of another user.
+			return seriesID != "s:other", nil
+		},
+	}
+
+	for _, testCase := range []struct {
+		name       string
+		path       string
+		query      url.Values
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "range query",
+			path:       "/insights/prometheus/api/v1/query_range",
+			query:      url.Values{"query": {`insight_series{series_id="s:series"}`}, "start": {"1630454400"}, "end": {"2021-09-15T00:00:00Z"}, "step": {"86400"}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"__name__":"insight_series","capture":"errorf","series_id":"s:series"},"values":[[1630454400,"10"],[1631664000,"12.5"]]},{"metric":{"__name__":"insight_series","capture":"fatalf","series_id":"s:series"},"values":[[1630454400,"1"],[1631664000,"3"]]}]}}`,
+		},
+		{
+			name:       "instant query",
+			path:       "/insights/prometheus/api/v1/query",
+			query:      url.Values{"query": {`insight_series{series_id="s:series", capture="errorf"}`}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"insight_series","capture":"errorf","series_id":"s:series"},"value":[1631664000,"12.5"]}]}}`,
+		},
+		{
+			name:       "unknown series",
+			path:       "/insights/prometheus/api/v1/query",
+			query:      url.Values{"query": {`insight_series{series_id="s:unknown"}`}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		},
+		{
+			name:       "series of another namespace",
+			path:       "/insights/prometheus/api/v1/query",
+			query:      url.Values{"query": {`insight_series{series_id="s:other"}`}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		},
+		{
+			name:       "range query of series of another namespace",
+			path:       "/insights/prometheus/api/v1/query_range",
+			query:      url.Values{"query": {`insight_series{series_id="s:other"}`}, "start": {"1630454400"}, "end": {"1631664000"}, "step": {"86400"}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+		},
+		{
+			name:       "unsupported query",
+			path:       "/insights/prometheus/api/v1/query",
+			query:      url.Values{"query": {`sum(rate(insight_series[5m]))`}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"status":"error","errorType":"bad_data","error":"unsupported query \"sum(rate(insight_series[5m]))\", only selectors of the form insight_series{series_id=\"...\"} are supported"}`,
+		},
+		{
+			name:       "unsupported matcher",
+			path:       "/insights/prometheus/api/v1/query",
+			query:      url.Values{"query": {`insight_series{series_id=~"s:.*"}`}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"status":"error","errorType":"bad_data","error":"unsupported label matcher \"series_id=~\\\"s:.*\\\"\", only equality matchers are supported"}`,
+		},
+		{
+			name:       "invalid time range",
+			path:       "/insights/prometheus/api/v1/query_range",
+			query:      url.Values{"query": {`insight_series{series_id="s:series"}`}, "start": {"1631664000"}, "end": {"1630454400"}, "step": {"60"}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"status":"error","errorType":"bad_data","error":"end timestamp must not be before start time"}`,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", testCase.path+"?"+testCase.query.Encode(), nil)
+			req = req.WithContext(actor.WithActor(req.Context(), actor.FromUser(1)))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != testCase.wantStatus {
+				t.Errorf("unexpected status. want=%d have=%d", testCase.wantStatus, w.Code)
+			}
+			if diff := cmp.Diff(testCase.wantBody, strings.TrimSpace(w.Body.String())); diff != "" {
+				t.Errorf("unexpected body (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/insights/prometheus/api/v1/query?query=insight_series{series_id=\"s:series\"}", nil))
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("unexpected status. want=%d have=%d", http.StatusUnauthorized, w.Code)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		enabled = false
+		defer func() { enabled = true }()
+
+		req := httptest.NewRequest("GET", "/insights/prometheus/api/v1/query", nil)
+		req = req.WithContext(actor.WithActor(req.Context(), actor.FromUser(1)))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("unexpected status. want=%d have=%d", http.StatusNotFound, w.Code)
+		}
+	})
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/cockroachdb/errors"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/httpapi"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/resolvers"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbconn"
@@ -45,11 +48,14 @@ func Init(ctx context.Context, postgres dbutil.DB, outOfBandMigrationRunner *oob
 		}
 		return nil
 	}
+	conf.ContributeValidator(discovery.ValidateRecordingCalendar)
+	conf.ContributeValidator(queryrunner.ValidateAttributionTeams)
 	timescale, err := InitializeCodeInsightsDB("frontend")
 	if err != nil {
 		return err
 	}
 	enterpriseServices.InsightsResolver = resolvers.New(timescale, postgres)
+	enterpriseServices.InsightsPrometheusHandler = httpapi.NewPrometheusHandler(timescale, postgres)
 	return nil
 }
 
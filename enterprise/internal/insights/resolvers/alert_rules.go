@@ -0,0 +1,186 @@
+package resolvers
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+const insightSeriesAlertRuleIDKind = "InsightSeriesAlertRule"
+
+const (
+	// maxAlertRulesPerSeries is the maximum number of alert rules a user may create on a series.
+	maxAlertRulesPerSeries = 10
+
+	// maxAlertWindowDays is the maximum window of percent change alert rules.
+	maxAlertWindowDays = 365
+)
+
+// CreateInsightSeriesAlertRule creates an alert rule of the current user on the given series.
+func (r *Resolver) CreateInsightSeriesAlertRule(ctx context.Context, args *graphqlbackend.CreateInsightSeriesAlertRuleArgs) (graphqlbackend.InsightSeriesAlertRuleResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+	rule, err := validateAlertRule(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Users may only create rules on the series of insights they can see.
+	namespaces, err := discovery.VisibleNamespaces(ctx, r.workerBaseStore.Handle().DB())
+	if err != nil {
+		return nil, err
+	}
+	discovered, err := discovery.Discover(ctx, r.insightStore, r.settingStore, insights.NewLoader(r.workerBaseStore.Handle().DB()), discovery.InsightFilterArgs{Namespaces: namespaces})
+	if err != nil {
+		return nil, errors.Wrap(err, "Discover")
+	}
+	series, ok := findSeries(discovered, rule.SeriesID)
+	if !ok {
+		return nil, errors.Errorf("insight series %q not found", rule.SeriesID)
+	}
+
+	existing, err := r.insightsStore.ListAlertRules(ctx, store.ListAlertRulesOpts{SeriesID: rule.SeriesID, UserID: a.UID})
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxAlertRulesPerSeries {
+		return nil, errors.Errorf("at most %d alert rules can be created on a series", maxAlertRulesPerSeries)
+	}
+
+	rule.SeriesLabel = series.Name
+	rule.UserID = a.UID
+	rule, err = r.insightsStore.CreateAlertRule(ctx, rule)
+	if err != nil {
+		return nil, errors.Wrap(err, "CreateAlertRule")
+	}
+	return &insightSeriesAlertRuleResolver{rule: rule}, nil
+}
+
+// validateAlertRule returns the alert rule described by the given arguments, or an error if they
+// do not describe a valid rule.
+func validateAlertRule(args *graphqlbackend.CreateInsightSeriesAlertRuleArgs) (store.AlertRule, error) {
+	input := args.Input
+	rule := store.AlertRule{
+		SeriesID:    input.SeriesID,
+		Condition:   store.AlertCondition(strings.ToLower(input.Condition)),
+		Threshold:   input.Threshold,
+		NotifyEmail: input.NotifyEmail,
+	}
+	switch rule.Condition {
+	case store.AlertAbove, store.AlertBelow:
+		if input.WindowDays != nil {
+			return rule, errors.New("windowDays is only supported by PERCENT_CHANGE rules")
+		}
+	case store.AlertPercentChange:
+		if input.WindowDays == nil || *input.WindowDays <= 0 || *input.WindowDays > maxAlertWindowDays {
+			return rule, errors.Errorf("windowDays must be between 1 and %d for PERCENT_CHANGE rules", maxAlertWindowDays)
+		}
+		if input.Threshold == 0 {
+			return rule, errors.New("threshold of PERCENT_CHANGE rules must not be zero")
+		}
+		rule.WindowDays = int(*input.WindowDays)
+	default:
+		return rule, errors.Errorf("unsupported alert condition %q", input.Condition)
+	}
+
+	if input.WebhookURL != nil && *input.WebhookURL != "" {
+		u, err := url.Parse(*input.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return rule, errors.Errorf("invalid webhook URL %q", *input.WebhookURL)
+		}
+		rule.WebhookURL = input.WebhookURL
+	}
+	if !rule.NotifyEmail && rule.WebhookURL == nil {
+		return rule, errors.New("alert rules must notify by email or webhook")
+	}
+	return rule, nil
+}
+
+// DeleteInsightSeriesAlertRule deletes the given alert rule, if it belongs to the current user.
+func (r *Resolver) DeleteInsightSeriesAlertRule(ctx context.Context, args *graphqlbackend.DeleteInsightSeriesAlertRuleArgs) (*graphqlbackend.EmptyResponse, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+	if kind := relay.UnmarshalKind(args.ID); kind != insightSeriesAlertRuleIDKind {
+		return nil, errors.Errorf("invalid alert rule ID kind %q", kind)
+	}
+	var id int
+	if err := relay.UnmarshalSpec(args.ID, &id); err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Users may only delete their own rules.
+	deleted, err := r.insightsStore.DeleteAlertRule(ctx, id, a.UID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, errors.Errorf("alert rule %q not found", args.ID)
+	}
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+// AlertRules returns the alert rules of the current user on the series.
+func (r *insightSeriesResolver) AlertRules(ctx context.Context) ([]graphqlbackend.InsightSeriesAlertRuleResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, nil
+	}
+	rules, err := r.insightsStore.ListAlertRules(ctx, store.ListAlertRulesOpts{SeriesID: discovery.Encode(r.series), UserID: a.UID})
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]graphqlbackend.InsightSeriesAlertRuleResolver, 0, len(rules))
+	for _, rule := range rules {
+		resolvers = append(resolvers, &insightSeriesAlertRuleResolver{rule: rule})
+	}
+	return resolvers, nil
+}
+
+var _ graphqlbackend.InsightSeriesAlertRuleResolver = &insightSeriesAlertRuleResolver{}
+
+type insightSeriesAlertRuleResolver struct {
+	rule store.AlertRule
+}
+
+func (r *insightSeriesAlertRuleResolver) ID() graphql.ID {
+	return relay.MarshalID(insightSeriesAlertRuleIDKind, r.rule.ID)
+}
+
+func (r *insightSeriesAlertRuleResolver) Condition() string {
+	return strings.ToUpper(string(r.rule.Condition))
+}
+
+func (r *insightSeriesAlertRuleResolver) Threshold() float64 { return r.rule.Threshold }
+
+func (r *insightSeriesAlertRuleResolver) WindowDays() *int32 {
+	if r.rule.Condition != store.AlertPercentChange {
+		return nil
+	}
+	windowDays := int32(r.rule.WindowDays)
+	return &windowDays
+}
+
+func (r *insightSeriesAlertRuleResolver) NotifyEmail() bool { return r.rule.NotifyEmail }
+
+func (r *insightSeriesAlertRuleResolver) WebhookURL() *string { return r.rule.WebhookURL }
+
+func (r *insightSeriesAlertRuleResolver) Firing() bool { return r.rule.Firing }
+
+func (r *insightSeriesAlertRuleResolver) LastNotifiedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(r.rule.LastNotifiedAt)
+}
@@ -0,0 +1,113 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestCreateInsightSeriesAlertRuleRequiresAuthentication(t *testing.T) {
+	r := &Resolver{}
+
+	_, err := r.CreateInsightSeriesAlertRule(context.Background(), &graphqlbackend.CreateInsightSeriesAlertRuleArgs{})
+	if !errors.Is(err, backend.ErrNotAuthenticated) {
+		t.Fatalf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+}
+
+func TestValidateAlertRule(t *testing.T) {
+	windowDays, zero := int32(7), int32(0)
+	webhookURL, invalidURL := "https://example.com/alerts", "ftp://example.com"
+	newArgs := func(condition string, threshold float64, windowDays *int32, notifyEmail bool, webhookURL *string) *graphqlbackend.CreateInsightSeriesAlertRuleArgs {
+		args := &graphqlbackend.CreateInsightSeriesAlertRuleArgs{}
+		args.Input.SeriesID = "s:one"
+		args.Input.Condition = condition
+		args.Input.Threshold = threshold
+		args.Input.WindowDays = windowDays
+		args.Input.NotifyEmail = notifyEmail
+		args.Input.WebhookURL = webhookURL
+		return args
+	}
+
+	for _, testCase := range []struct {
+		name    string
+		args    *graphqlbackend.CreateInsightSeriesAlertRuleArgs
+		wantErr bool
+	}{
+		{"above", newArgs("ABOVE", 10, nil, true, nil), false},
+		{"percent change", newArgs("PERCENT_CHANGE", -20, &windowDays, false, &webhookURL), false},
+		{"unknown condition", newArgs("EQUAL", 10, nil, true, nil), true},
+		{"window of threshold rule", newArgs("BELOW", 10, &windowDays, true, nil), true},
+		{"percent change without window", newArgs("PERCENT_CHANGE", 10, nil, true, nil), true},
+		{"percent change with empty window", newArgs("PERCENT_CHANGE", 10, &zero, true, nil), true},
+		{"percent change without threshold", newArgs("PERCENT_CHANGE", 0, &windowDays, true, nil), true},
+		{"invalid webhook URL", newArgs("ABOVE", 10, nil, true, &invalidURL), true},
+		{"no notification", newArgs("ABOVE", 10, nil, false, nil), true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := validateAlertRule(testCase.args)
+			if (err != nil) != testCase.wantErr {
+				t.Errorf("unexpected error. wantErr=%v have=%v", testCase.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDeleteInsightSeriesAlertRule(t *testing.T) {
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+	insightsStore := store.NewMockInterface()
+	insightsStore.DeleteAlertRuleFunc.SetDefaultReturn(false, nil)
+	r := &Resolver{insightsStore: insightsStore}
+
+	// Rules of other users are not found.
+	if _, err := r.DeleteInsightSeriesAlertRule(ctx, &graphqlbackend.DeleteInsightSeriesAlertRuleArgs{ID: relay.MarshalID(insightSeriesAlertRuleIDKind, 2)}); err == nil {
+		t.Fatalf("expected error deleting the rule of another user")
+	}
+	history := insightsStore.DeleteAlertRuleFunc.History()
+	if len(history) != 1 {
+		t.Fatalf("unexpected number of deletions. want=%d have=%d", 1, len(history))
+	}
+	if history[0].Arg1 != 2 || history[0].Arg2 != 1 {
+		t.Errorf("unexpected deletion of rule %d of user %d", history[0].Arg1, history[0].Arg2)
+	}
+
+	if _, err := r.DeleteInsightSeriesAlertRule(ctx, &graphqlbackend.DeleteInsightSeriesAlertRuleArgs{ID: relay.MarshalID(insightExportIDKind, 2)}); err == nil {
+		t.Fatalf("expected error deleting an ID of another kind")
+	}
+}
+
+func TestInsightSeriesResolverAlertRules(t *testing.T) {
+	series := insights.TimeSeries{Query: "foo"}
+	insightsStore := store.NewMockInterface()
+	insightsStore.ListAlertRulesFunc.SetDefaultReturn([]store.AlertRule{
+		{ID: 1, SeriesID: discovery.Encode(series), UserID: 1, Condition: store.AlertPercentChange, Threshold: 10, WindowDays: 7},
+	}, nil)
+	r := &insightSeriesResolver{insightsStore: insightsStore, series: series}
+
+	if rules, err := r.AlertRules(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if len(rules) != 0 {
+		t.Errorf("unexpected rules of anonymous user: %d", len(rules))
+	}
+
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+	rules, err := r.AlertRules(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rules) != 1 || rules[0].Condition() != "PERCENT_CHANGE" || rules[0].WindowDays() == nil || *rules[0].WindowDays() != 7 {
+		t.Fatalf("unexpected rules: %+v", rules)
+	}
+	if want, have := (store.ListAlertRulesOpts{SeriesID: discovery.Encode(series), UserID: 1}), insightsStore.ListAlertRulesFunc.History()[0].Arg1; have != want {
+		t.Errorf("unexpected options. want=%+v have=%+v", want, have)
+	}
+}
@@ -0,0 +1,29 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+)
+
+// ResumeInsightSeries resumes a series paused by its circuit breaker, after its queries exceeded the
+// time budget of a series too many times in a row (see queryrunner.SeriesTimeBudget).
+func (r *Resolver) ResumeInsightSeries(ctx context.Context, args *graphqlbackend.ResumeInsightSeriesArgs) (*graphqlbackend.EmptyResponse, error) {
+	// 🚨 SECURITY: Series are paused to protect the search capacity shared by all users, so only
+	// site admins can resume them.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.workerBaseStore.Handle().DB()); err != nil {
+		return nil, err
+	}
+
+	paused, err := r.insightsStore.ResetSeriesCircuit(ctx, args.SeriesID)
+	if err != nil {
+		return nil, errors.Wrap(err, "ResetSeriesCircuit")
+	}
+	if !paused {
+		return nil, errors.Errorf("insight series %q is not paused", args.SeriesID)
+	}
+	return &graphqlbackend.EmptyResponse{}, nil
+}
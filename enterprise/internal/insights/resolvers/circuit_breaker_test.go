@@ -0,0 +1,38 @@
+package resolvers
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+func TestResumeInsightSeries(t *testing.T) {
+	insightsStore := store.NewMockInterface()
+	insightsStore.ResetSeriesCircuitFunc.SetDefaultHook(func(ctx context.Context, seriesID string) (bool, error) {
+		return seriesID == "s:paused", nil
+	})
+	r := &Resolver{insightsStore: insightsStore, workerBaseStore: basestore.NewWithDB(nil, sql.TxOptions{})}
+
+	if _, err := r.ResumeInsightSeries(context.Background(), &graphqlbackend.ResumeInsightSeriesArgs{SeriesID: "s:paused"}); !errors.Is(err, backend.ErrNotAuthenticated) {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+	if calls := len(insightsStore.ResetSeriesCircuitFunc.History()); calls != 0 {
+		t.Fatalf("unexpected number of resumed series. want=%d have=%d", 0, calls)
+	}
+
+	ctx := backend.WithAuthzBypass(context.Background())
+	if _, err := r.ResumeInsightSeries(ctx, &graphqlbackend.ResumeInsightSeriesArgs{SeriesID: "s:paused"}); err != nil {
+		t.Errorf("unexpected error resuming paused series: %s", err)
+	}
+	want := `insight series "s:running" is not paused`
+	if _, err := r.ResumeInsightSeries(ctx, &graphqlbackend.ResumeInsightSeriesArgs{SeriesID: "s:running"}); err == nil || err.Error() != want {
+		t.Errorf("unexpected error. want=%q have=%v", want, err)
+	}
+}
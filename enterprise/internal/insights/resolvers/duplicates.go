@@ -0,0 +1,89 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// InsightDuplicates returns the groups of duplicate insights found by the most recent validation
+// pass (see discovery.DetectDuplicateInsights).
+func (r *Resolver) InsightDuplicates(ctx context.Context) ([]graphqlbackend.InsightDuplicateGroupResolver, error) {
+	// 🚨 SECURITY: Duplicates span the insights of several users and organizations, so only site
+	// admins can list them.
+	db := r.workerBaseStore.Handle().DB()
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, db); err != nil {
+		return nil, err
+	}
+	duplicates, err := r.insightStore.GetInsightDuplicates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toInsightDuplicateGroupResolvers(db, duplicates), nil
+}
+
+// toInsightDuplicateGroupResolvers groups the given duplicates, ordered by group with the canonical
+// insight of each group first.
+func toInsightDuplicateGroupResolvers(db dbutil.DB, duplicates []types.InsightDuplicate) []graphqlbackend.InsightDuplicateGroupResolver {
+	resolvers := make([]graphqlbackend.InsightDuplicateGroupResolver, 0)
+	var group *insightDuplicateGroupResolver
+	for _, duplicate := range duplicates {
+		if group == nil || duplicate.GroupID != group.canonical.duplicate.GroupID {
+			group = &insightDuplicateGroupResolver{canonical: &insightDuplicateResolver{db: db, duplicate: duplicate}}
+			resolvers = append(resolvers, group)
+			continue
+		}
+		group.duplicates = append(group.duplicates, &insightDuplicateResolver{db: db, duplicate: duplicate})
+	}
+	return resolvers
+}
+
+var _ graphqlbackend.InsightDuplicateGroupResolver = &insightDuplicateGroupResolver{}
+
+type insightDuplicateGroupResolver struct {
+	canonical  *insightDuplicateResolver
+	duplicates []graphqlbackend.InsightDuplicateResolver
+}
+
+func (r *insightDuplicateGroupResolver) Canonical() graphqlbackend.InsightDuplicateResolver {
+	return r.canonical
+}
+
+func (r *insightDuplicateGroupResolver) Duplicates() []graphqlbackend.InsightDuplicateResolver {
+	if r.duplicates == nil {
+		return []graphqlbackend.InsightDuplicateResolver{}
+	}
+	return r.duplicates
+}
+
+func (r *insightDuplicateGroupResolver) DetectedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.canonical.duplicate.DetectedAt}
+}
+
+var _ graphqlbackend.InsightDuplicateResolver = &insightDuplicateResolver{}
+
+type insightDuplicateResolver struct {
+	db        dbutil.DB
+	duplicate types.InsightDuplicate
+}
+
+func (r *insightDuplicateResolver) InsightID() string { return r.duplicate.InsightID }
+
+func (r *insightDuplicateResolver) Title() string { return r.duplicate.Title }
+
+func (r *insightDuplicateResolver) User(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	if r.duplicate.UserID == nil {
+		return nil, nil
+	}
+	return graphqlbackend.UserByIDInt32(ctx, r.db, *r.duplicate.UserID)
+}
+
+func (r *insightDuplicateResolver) Organization(ctx context.Context) (*graphqlbackend.OrgResolver, error) {
+	if r.duplicate.OrgID == nil {
+		return nil, nil
+	}
+	return graphqlbackend.OrgByIDInt32(ctx, r.db, *r.duplicate.OrgID)
+}
@@ -0,0 +1,59 @@
+package resolvers
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+func TestInsightDuplicatesNotSiteAdmin(t *testing.T) {
+	r := &Resolver{workerBaseStore: basestore.NewWithDB(nil, sql.TxOptions{})}
+	if _, err := r.InsightDuplicates(context.Background()); !errors.Is(err, backend.ErrNotAuthenticated) {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+}
+
+func TestInsightDuplicateGroupResolvers(t *testing.T) {
+	userID, orgID := int32(1), int32(2)
+	groups := toInsightDuplicateGroupResolvers(nil, []types.InsightDuplicate{
+		{GroupID: "A", InsightID: "errors", Title: "Errors", OrgID: &orgID, Canonical: true},
+		{GroupID: "A", InsightID: "errors", Title: "Errors", UserID: &userID},
+		{GroupID: "A", InsightID: "my-errors", Title: "My errors", UserID: &userID},
+		{GroupID: "B", InsightID: "todos", Title: "TODOs", Canonical: true},
+		{GroupID: "B", InsightID: "todos", Title: "TODOs", OrgID: &orgID},
+	})
+	if len(groups) != 2 {
+		t.Fatalf("unexpected number of groups. want=%d have=%d", 2, len(groups))
+	}
+
+	for i, want := range []struct {
+		canonical  string
+		duplicates []string
+	}{
+		{"errors", []string{"errors", "my-errors"}},
+		{"todos", []string{"todos"}},
+	} {
+		if have := groups[i].Canonical().InsightID(); have != want.canonical {
+			t.Errorf("unexpected canonical insight of group %d. want=%q have=%q", i, want.canonical, have)
+		}
+		duplicates := groups[i].Duplicates()
+		if len(duplicates) != len(want.duplicates) {
+			t.Fatalf("unexpected number of duplicates of group %d. want=%d have=%d", i, len(want.duplicates), len(duplicates))
+		}
+		for j, duplicate := range duplicates {
+			if duplicate.InsightID() != want.duplicates[j] {
+				t.Errorf("unexpected duplicate of group %d. want=%q have=%q", i, want.duplicates[j], duplicate.InsightID())
+			}
+		}
+	}
+
+	if user, err := groups[1].Canonical().User(context.Background()); user != nil || err != nil {
+		t.Errorf("unexpected user of global insight. want=nil have=%v %v", user, err)
+	}
+}
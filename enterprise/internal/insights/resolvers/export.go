@@ -0,0 +1,126 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/exportrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+const insightExportIDKind = "InsightExport"
+
+// ExportInsight exports the data of the given insight for the current user. Insights with at most
+// exportrunner.MaxInlineSeries series are exported right away, and the exports of larger insights
+// are enqueued for the export runner worker.
+func (r *Resolver) ExportInsight(ctx context.Context, args *graphqlbackend.ExportInsightArgs) (graphqlbackend.InsightExportResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+	format := strings.ToLower(args.Format)
+	if !exportrunner.ValidFormat(format) {
+		return nil, errors.Errorf("unsupported export format %q", args.Format)
+	}
+
+	// 🚨 SECURITY: Users may only export insights they can see.
+	namespaces, err := discovery.VisibleNamespaces(ctx, r.workerBaseStore.Handle().DB())
+	if err != nil {
+		return nil, err
+	}
+	discovered, err := discovery.Discover(ctx, r.insightStore, r.settingStore, insights.NewLoader(r.workerBaseStore.Handle().DB()), discovery.InsightFilterArgs{
+		Ids:        []string{args.InsightID},
+		Namespaces: namespaces,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Discover")
+	}
+	var insight *insights.SearchInsight
+	for i := range discovered {
+		if discovered[i].ID == args.InsightID {
+			insight = &discovered[i]
+			break
+		}
+	}
+	if insight == nil {
+		return nil, errors.Errorf("insight %q not found", args.InsightID)
+	}
+
+	job := &exportrunner.Job{
+		InsightID: args.InsightID,
+		Format:    format,
+		UserID:    a.UID,
+		State:     "queued",
+	}
+	if len(insight.Series) <= exportrunner.MaxInlineSeries {
+		data, err := exportrunner.Export(ctx, r.insightsStore, *insight, format)
+		if err != nil {
+			return nil, errors.Wrap(err, "Export")
+		}
+		finishedAt := time.Now()
+		job.Data, job.State, job.FinishedAt = data, "completed", &finishedAt
+	}
+	job.ID, err = exportrunner.EnqueueJob(ctx, r.workerBaseStore, job)
+	if err != nil {
+		return nil, errors.Wrap(err, "EnqueueJob")
+	}
+	return &insightExportResolver{job: job}, nil
+}
+
+// InsightExport returns the given export, if it was requested by the current user.
+func (r *Resolver) InsightExport(ctx context.Context, args *graphqlbackend.InsightExportArgs) (graphqlbackend.InsightExportResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+	var id int
+	if err := relay.UnmarshalSpec(args.ID, &id); err != nil {
+		return nil, err
+	}
+
+	job, ok, err := exportrunner.GetJob(ctx, r.workerBaseStore, id)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Exports hold the data visible to the user who requested them, so they are only
+	// visible to that user.
+	if !ok || job.UserID != a.UID {
+		return nil, nil
+	}
+	return &insightExportResolver{job: job}, nil
+}
+
+var _ graphqlbackend.InsightExportResolver = &insightExportResolver{}
+
+type insightExportResolver struct {
+	job *exportrunner.Job
+}
+
+func (r *insightExportResolver) ID() graphql.ID {
+	return relay.MarshalID(insightExportIDKind, r.job.ID)
+}
+
+func (r *insightExportResolver) InsightID() string { return r.job.InsightID }
+
+func (r *insightExportResolver) Format() string { return strings.ToUpper(r.job.Format) }
+
+func (r *insightExportResolver) State() string { return strings.ToUpper(r.job.State) }
+
+func (r *insightExportResolver) Failure() *string { return r.job.FailureMessage }
+
+func (r *insightExportResolver) Data() *string {
+	if r.job.State != "completed" {
+		return nil
+	}
+	data := string(r.job.Data)
+	return &data
+}
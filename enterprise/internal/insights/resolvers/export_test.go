@@ -0,0 +1,40 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/exportrunner"
+)
+
+func TestExportInsightNotAuthenticated(t *testing.T) {
+	r := &Resolver{}
+	if _, err := r.ExportInsight(context.Background(), &graphqlbackend.ExportInsightArgs{InsightID: "insight", Format: "CSV"}); err != backend.ErrNotAuthenticated {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+	if _, err := r.InsightExport(context.Background(), &graphqlbackend.InsightExportArgs{ID: "export"}); err != backend.ErrNotAuthenticated {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+}
+
+func TestInsightExportResolver(t *testing.T) {
+	job := &exportrunner.Job{ID: 1, InsightID: "insight", Format: exportrunner.FormatJSON, Data: []byte("{}"), State: "processing"}
+	r := &insightExportResolver{job: job}
+
+	if format := r.Format(); format != "JSON" {
+		t.Errorf("unexpected format. want=%q have=%q", "JSON", format)
+	}
+	if data := r.Data(); data != nil {
+		t.Errorf("unexpected data of export being processed. want=nil have=%q", *data)
+	}
+
+	job.State = "completed"
+	if state := r.State(); state != "COMPLETED" {
+		t.Errorf("unexpected state. want=%q have=%q", "COMPLETED", state)
+	}
+	if data := r.Data(); data == nil || *data != "{}" {
+		t.Errorf("unexpected data of completed export. want=%q have=%v", "{}", data)
+	}
+}
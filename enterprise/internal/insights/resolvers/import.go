@@ -0,0 +1,124 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/importrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+const insightSeriesImportIDKind = "InsightSeriesImport"
+
+// ImportInsightSeriesPoints validates the given data points, and enqueues their import into the
+// given series for the import runner worker, on behalf of the current user.
+func (r *Resolver) ImportInsightSeriesPoints(ctx context.Context, args *graphqlbackend.ImportInsightSeriesPointsArgs) (graphqlbackend.InsightSeriesImportResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+	format := strings.ToLower(args.Format)
+	if !importrunner.ValidFormat(format) {
+		return nil, errors.Errorf("unsupported import format %q", args.Format)
+	}
+	onConflict := strings.ToLower(args.OnConflict)
+	if !importrunner.ValidOnConflict(onConflict) {
+		return nil, errors.Errorf("unsupported conflict resolution %q", args.OnConflict)
+	}
+
+	series, err := importrunner.ImportableSeries(ctx, r.workerBaseStore.Handle().DB(), r.insightStore, r.settingStore, args.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report invalid data right away rather than through a failed job. Repositories are only
+	// looked up once the data points are imported.
+	calendar, err := discovery.SiteRecordingCalendar()
+	if err != nil {
+		return nil, errors.Wrap(err, "SiteRecordingCalendar")
+	}
+	points, err := importrunner.ParsePoints(format, []byte(args.Data), calendar, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := importrunner.ValidatePoints(series, calendar, points); err != nil {
+		return nil, err
+	}
+
+	job := &importrunner.Job{
+		SeriesID:   args.SeriesID,
+		Format:     format,
+		OnConflict: onConflict,
+		UserID:     a.UID,
+		Data:       []byte(args.Data),
+		State:      "queued",
+	}
+	job.ID, err = importrunner.EnqueueJob(ctx, r.workerBaseStore, job)
+	if err != nil {
+		return nil, errors.Wrap(err, "EnqueueJob")
+	}
+	return &insightSeriesImportResolver{job: job}, nil
+}
+
+// InsightSeriesImport returns the given import, if it was requested by the current user.
+func (r *Resolver) InsightSeriesImport(ctx context.Context, args *graphqlbackend.InsightSeriesImportArgs) (graphqlbackend.InsightSeriesImportResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+	var id int
+	if err := relay.UnmarshalSpec(args.ID, &id); err != nil {
+		return nil, err
+	}
+
+	job, ok, err := importrunner.GetJob(ctx, r.workerBaseStore, id)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Failures of imports may name repositories that other users cannot access, so
+	// imports are only visible to the user who requested them.
+	if !ok || job.UserID != a.UID {
+		return nil, nil
+	}
+	return &insightSeriesImportResolver{job: job}, nil
+}
+
+var _ graphqlbackend.InsightSeriesImportResolver = &insightSeriesImportResolver{}
+
+type insightSeriesImportResolver struct {
+	job *importrunner.Job
+}
+
+func (r *insightSeriesImportResolver) ID() graphql.ID {
+	return relay.MarshalID(insightSeriesImportIDKind, r.job.ID)
+}
+
+func (r *insightSeriesImportResolver) SeriesID() string { return r.job.SeriesID }
+
+func (r *insightSeriesImportResolver) State() string { return strings.ToUpper(r.job.State) }
+
+func (r *insightSeriesImportResolver) Failure() *string { return r.job.FailureMessage }
+
+func (r *insightSeriesImportResolver) ImportedPoints() *int32 {
+	return optionalInt32(r.job.ImportedPoints)
+}
+
+func (r *insightSeriesImportResolver) SkippedPoints() *int32 {
+	return optionalInt32(r.job.SkippedPoints)
+}
+
+func optionalInt32(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v)
+	return &i
+}
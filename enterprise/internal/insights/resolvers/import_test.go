@@ -0,0 +1,45 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/importrunner"
+)
+
+func TestImportInsightSeriesPointsNotAuthenticated(t *testing.T) {
+	r := &Resolver{}
+	args := &graphqlbackend.ImportInsightSeriesPointsArgs{SeriesID: "series", Format: "CSV", Data: "time,value\n2021-09-01,1\n", OnConflict: "SKIP"}
+	if _, err := r.ImportInsightSeriesPoints(context.Background(), args); err != backend.ErrNotAuthenticated {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+	if _, err := r.InsightSeriesImport(context.Background(), &graphqlbackend.InsightSeriesImportArgs{ID: "import"}); err != backend.ErrNotAuthenticated {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+}
+
+func TestInsightSeriesImportResolver(t *testing.T) {
+	job := &importrunner.Job{
+		ID:       1,
+		SeriesID: "series",
+		State:    "processing",
+	}
+	r := &insightSeriesImportResolver{job: job}
+	if imported := r.ImportedPoints(); imported != nil {
+		t.Errorf("unexpected imported points of import being processed. want=nil have=%d", *imported)
+	}
+
+	imported, skipped := 7, 2
+	job.State, job.ImportedPoints, job.SkippedPoints = "completed", &imported, &skipped
+	if state := r.State(); state != "COMPLETED" {
+		t.Errorf("unexpected state. want=%q have=%q", "COMPLETED", state)
+	}
+	if have := r.ImportedPoints(); have == nil || *have != 7 {
+		t.Errorf("unexpected imported points. want=%d have=%v", 7, have)
+	}
+	if have := r.SkippedPoints(); have == nil || *have != 2 {
+		t.Errorf("unexpected skipped points. want=%d have=%v", 2, have)
+	}
+}
@@ -18,6 +18,7 @@ var _ graphqlbackend.InsightConnectionResolver = &insightConnectionResolver{}
 
 type insightConnectionResolver struct {
 	insightsStore   store.Interface
+	insightStore    *store.InsightStore
 	workerBaseStore *basestore.Store
 	settingStore    discovery.SettingStore
 
@@ -40,6 +41,7 @@ func (r *insightConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend
 	for _, insight := range nodes {
 		resolvers = append(resolvers, &insightResolver{
 			insightsStore:   r.insightsStore,
+			insightStore:    r.insightStore,
 			workerBaseStore: r.workerBaseStore,
 			insight:         insight,
 		})
@@ -65,7 +67,13 @@ func (r *insightConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.
 
 func (r *insightConnectionResolver) compute(ctx context.Context) ([]insights.SearchInsight, int64, error) {
 	r.once.Do(func() {
-		r.insights, r.err = discovery.Discover(ctx, r.settingStore, insights.NewLoader(r.workerBaseStore.Handle().DB()), discovery.InsightFilterArgs{Ids: r.ids})
+		// 🚨 SECURITY: Only the insights of the namespaces of the current user are visible to them.
+		namespaces, err := discovery.VisibleNamespaces(ctx, r.workerBaseStore.Handle().DB())
+		if err != nil {
+			r.err = err
+			return
+		}
+		r.insights, r.err = discovery.Discover(ctx, r.insightStore, r.settingStore, insights.NewLoader(r.workerBaseStore.Handle().DB()), discovery.InsightFilterArgs{Ids: r.ids, Namespaces: namespaces})
 	})
 	return r.insights, r.next, r.err
 }
@@ -76,6 +84,7 @@ var _ graphqlbackend.InsightResolver = &insightResolver{}
 
 type insightResolver struct {
 	insightsStore   store.Interface
+	insightStore    *store.InsightStore
 	workerBaseStore *basestore.Store
 	insight         insights.SearchInsight
 }
@@ -88,15 +97,36 @@ func (r *insightResolver) Title() string { return r.insight.Title }
 
 func (r *insightResolver) Description() string { return r.insight.Description }
 
-func (r *insightResolver) Series() []graphqlbackend.InsightSeriesResolver {
+func (r *insightResolver) Series(ctx context.Context) ([]graphqlbackend.InsightSeriesResolver, error) {
 	series := r.insight.Series
 	resolvers := make([]graphqlbackend.InsightSeriesResolver, 0, len(series))
 	for _, series := range series {
-		resolvers = append(resolvers, &insightSeriesResolver{
-			insightsStore:   r.insightsStore,
-			workerBaseStore: r.workerBaseStore,
-			series:          series,
-		})
+		if !series.Split() {
+			resolvers = append(resolvers, &insightSeriesResolver{
+				insightsStore:   r.insightsStore,
+				insightStore:    r.insightStore,
+				workerBaseStore: r.workerBaseStore,
+				series:          series,
+			})
+			continue
+		}
+
+		// Series generated from capture groups or grouped by attribution are created dynamically,
+		// one per captured value, author or team.
+		values, err := r.insightsStore.CaptureValues(ctx, discovery.Encode(series))
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			value := value
+			resolvers = append(resolvers, &insightSeriesResolver{
+				insightsStore:   r.insightsStore,
+				insightStore:    r.insightStore,
+				workerBaseStore: r.workerBaseStore,
+				series:          series,
+				capture:         &value,
+			})
+		}
 	}
-	return resolvers
+	return resolvers, nil
 }
@@ -141,13 +141,21 @@ func TestResolver_InsightConnection(t *testing.T) {
 			"description": nodes[0].Description(),
 		})
 		// TODO(slimsag): put series length into map (autogold bug, omits the field for some reason?)
-		autogold.Want("first insight: series length", int(2)).Equal(t, len(nodes[0].Series()))
+		series, err := nodes[0].Series(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		autogold.Want("first insight: series length", int(2)).Equal(t, len(series))
 
 		autogold.Want("second insight", map[string]interface{}{"description": "gitserver exec & close usage", "title": "gitserver usage"}).Equal(t, map[string]interface{}{
 			"title":       nodes[1].Title(),
 			"description": nodes[1].Description(),
 		})
-		autogold.Want("second insight: series length", int(2)).Equal(t, len(nodes[1].Series()))
+		series, err = nodes[1].Series(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		autogold.Want("second insight: series length", int(2)).Equal(t, len(series))
 	})
 }
 
@@ -283,7 +291,10 @@ func resolvePoints(ctx context.Context, conn graphqlbackend.InsightConnectionRes
 	}
 
 	expected := nodes[0]
-	seriesResolvers := expected.Series()
+	seriesResolvers, err := expected.Series(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if len(seriesResolvers) != 1 {
 		t.Errorf("unexpected length of series resolvers: want: %v got: %v", 1, len(seriesResolvers))
 	}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"time"
 
+	"github.com/cockroachdb/errors"
+
 	"github.com/sourcegraph/sourcegraph/internal/insights"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
@@ -17,11 +20,23 @@ var _ graphqlbackend.InsightSeriesResolver = &insightSeriesResolver{}
 
 type insightSeriesResolver struct {
 	insightsStore   store.Interface
+	insightStore    *store.InsightStore
 	workerBaseStore *basestore.Store
 	series          insights.TimeSeries
+
+	// capture is the captured value represented by this resolver if the series is generated from
+	// capture groups.
+	capture *string
 }
 
-func (r *insightSeriesResolver) Label() string { return r.series.Name }
+func (r *insightSeriesResolver) SeriesID() string { return discovery.Encode(r.series) }
+
+func (r *insightSeriesResolver) Label() string {
+	if r.capture != nil {
+		return *r.capture
+	}
+	return r.series.Name
+}
 
 func (r *insightSeriesResolver) Points(ctx context.Context, args *graphqlbackend.InsightsPointsArgs) ([]graphqlbackend.InsightsDataPointResolver, error) {
 	var opts store.SeriesPointsOpts
@@ -29,6 +44,7 @@ func (r *insightSeriesResolver) Points(ctx context.Context, args *graphqlbackend
 	// Query data points only for the series we are representing.
 	seriesID := discovery.Encode(r.series)
 	opts.SeriesID = &seriesID
+	opts.Capture = r.capture
 
 	if args.From == nil {
 		// Default to last 6mo of data.
@@ -59,6 +75,41 @@ func (r *insightSeriesResolver) Points(ctx context.Context, args *graphqlbackend
 	return resolvers, nil
 }
 
+// maxRepositoryPoints is the maximum number of repository data points returned at once.
+const maxRepositoryPoints = 1000
+
+func (r *insightSeriesResolver) RepositoryPoints(ctx context.Context, args *graphqlbackend.InsightsRepositoryPointsArgs) ([]graphqlbackend.InsightsRepositoryDataPointResolver, error) {
+	if args.First < 0 || args.First > maxRepositoryPoints {
+		return nil, errors.Errorf("first must be between 0 and %d", maxRepositoryPoints)
+	}
+	if args.First == 0 {
+		return []graphqlbackend.InsightsRepositoryDataPointResolver{}, nil
+	}
+
+	opts := store.RepoPointsAtOpts{
+		SeriesID: discovery.Encode(r.series),
+		Capture:  r.capture,
+		Time:     args.DateTime.Time,
+		Limit:    int(args.First),
+	}
+	if args.IncludeRepoRegex != nil {
+		opts.IncludeRepoRegex = *args.IncludeRepoRegex
+	}
+	if args.ExcludeRepoRegex != nil {
+		opts.ExcludeRepoRegex = *args.ExcludeRepoRegex
+	}
+
+	points, err := r.insightsStore.RepoPointsAt(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]graphqlbackend.InsightsRepositoryDataPointResolver, 0, len(points))
+	for _, point := range points {
+		resolvers = append(resolvers, insightsRepositoryDataPointResolver{point})
+	}
+	return resolvers, nil
+}
+
 func (r *insightSeriesResolver) Status(ctx context.Context) (graphqlbackend.InsightStatusResolver, error) {
 	seriesID := discovery.Encode(r.series)
 
@@ -74,15 +125,60 @@ func (r *insightSeriesResolver) Status(ctx context.Context) (graphqlbackend.Insi
 		return nil, err
 	}
 
-	return insightStatusResolver{
+	resolver := insightStatusResolver{
 		totalPoints: int32(totalPoints),
 
 		// Include errored because they'll be retried before becoming failures
 		pendingJobs: int32(status.Queued + status.Processing + status.Errored),
 
-		completedJobs: int32(status.Completed),
-		failedJobs:    int32(status.Failed),
-	}, nil
+		completedJobs:       int32(status.Completed),
+		failedJobs:          int32(status.Failed),
+		pendingBackfillJobs: int32(status.PendingBackfill),
+	}
+
+	// Webhook series are recorded by the webhook runner, which records the outcome of its
+	// requests separately.
+	if r.series.Webhook != "" {
+		delivery, ok, err := r.insightsStore.WebhookDeliveryStatus(ctx, seriesID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			resolver.lastAttemptAt = &delivery.LastAttemptAt
+			resolver.lastSuccessAt = delivery.LastSuccessAt
+			resolver.lastError = delivery.LastError
+			resolver.consecutiveFailures = int32(delivery.ConsecutiveFailures)
+		}
+	} else {
+		run, ok, err := r.insightsStore.SeriesRunStatus(ctx, seriesID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			resolver.lastAttemptAt = &run.LastAttemptAt
+			resolver.lastSuccessAt = run.LastSuccessAt
+			resolver.lastError = run.LastError
+			resolver.consecutiveFailures = int32(run.ConsecutiveFailures)
+			resolver.pausedAt = run.CircuitBrokenAt
+		}
+	}
+
+	// 🚨 SECURITY: Errors of searches run on behalf of all users may mention repositories the
+	// current user cannot access, so only site admins can see them.
+	if resolver.lastError != nil {
+		if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.workerBaseStore.Handle().DB()); err != nil {
+			resolver.lastError = nil
+		}
+	}
+
+	series, err := r.insightStore.GetDataSeries(ctx, store.GetDataSeriesArgs{SeriesID: seriesID})
+	if err != nil {
+		return nil, err
+	}
+	if len(series) > 0 {
+		resolver.backfillQueuedAt = series[0].BackfillQueuedAt
+	}
+	return resolver, nil
 }
 
 var _ graphqlbackend.InsightsDataPointResolver = insightsDataPointResolver{}
@@ -95,11 +191,57 @@ func (i insightsDataPointResolver) DateTime() graphqlbackend.DateTime {
 
 func (i insightsDataPointResolver) Value() float64 { return i.p.Value }
 
+func (i insightsDataPointResolver) Approximate() bool { return i.p.Approximate }
+
+var _ graphqlbackend.InsightsRepositoryDataPointResolver = insightsRepositoryDataPointResolver{}
+
+type insightsRepositoryDataPointResolver struct{ p store.RepoPoint }
+
+func (i insightsRepositoryDataPointResolver) Repository() string { return i.p.RepoName }
+
+func (i insightsRepositoryDataPointResolver) DateTime() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: i.p.Time}
+}
+
+func (i insightsRepositoryDataPointResolver) Value() float64 { return i.p.Value }
+
+func (i insightsRepositoryDataPointResolver) Approximate() bool { return i.p.Approximate }
+
 type insightStatusResolver struct {
 	totalPoints, pendingJobs, completedJobs, failedJobs int32
+
+	lastAttemptAt, lastSuccessAt *time.Time
+	lastError                    *string
+	consecutiveFailures          int32
+
+	backfillQueuedAt    *time.Time
+	pendingBackfillJobs int32
+
+	pausedAt *time.Time
 }
 
 func (i insightStatusResolver) TotalPoints() int32   { return i.totalPoints }
 func (i insightStatusResolver) PendingJobs() int32   { return i.pendingJobs }
 func (i insightStatusResolver) CompletedJobs() int32 { return i.completedJobs }
 func (i insightStatusResolver) FailedJobs() int32    { return i.failedJobs }
+
+func (i insightStatusResolver) LastAttemptAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(i.lastAttemptAt)
+}
+
+func (i insightStatusResolver) LastSuccessAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(i.lastSuccessAt)
+}
+
+func (i insightStatusResolver) LastError() *string         { return i.lastError }
+func (i insightStatusResolver) ConsecutiveFailures() int32 { return i.consecutiveFailures }
+
+func (i insightStatusResolver) BackfillQueuedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(i.backfillQueuedAt)
+}
+
+func (i insightStatusResolver) PendingBackfillJobs() int32 { return i.pendingBackfillJobs }
+
+func (i insightStatusResolver) PausedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(i.pausedAt)
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hexops/autogold"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
@@ -15,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
 )
 
 // TestResolver_InsightSeries tests that the InsightSeries GraphQL resolver works.
@@ -56,7 +58,12 @@ func TestResolver_InsightSeries(t *testing.T) {
 		}
 		var series [][]graphqlbackend.InsightSeriesResolver
 		for _, node := range nodes {
-			series = append(series, node.Series())
+			nodeSeries, err := node.Series(ctx)
+			if err != nil {
+				cleanup()
+				t.Fatal(err)
+			}
+			series = append(series, nodeSeries)
 		}
 		return ctx, series, mockStore, cleanup
 	}
@@ -103,7 +110,7 @@ func TestResolver_InsightSeries(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			autogold.Want("insights[0][0].Points store opts", `{"SeriesID":"s:087855E6A24440837303FD8A252E9893E8ABDFECA55B61AC83DA1B521906626E","RepoID":null,"Excluded":null,"Included":null,"IncludeRepoRegex":"","ExcludeRepoRegex":"","From":"2006-01-02T15:04:05Z","To":"2006-01-03T15:04:05Z","Limit":0}`).Equal(t, string(json))
+			autogold.Want("insights[0][0].Points store opts", `{"SeriesID":"s:087855E6A24440837303FD8A252E9893E8ABDFECA55B61AC83DA1B521906626E","RepoID":null,"Excluded":null,"Included":null,"IncludeRepoRegex":"","ExcludeRepoRegex":"","From":"2006-01-02T15:04:05Z","To":"2006-01-03T15:04:05Z","Limit":0,"Capture":null}`).Equal(t, string(json))
 			return []store.SeriesPoint{
 				{Time: args.From.Time, Value: 1},
 				{Time: args.From.Time, Value: 2},
@@ -114,6 +121,70 @@ func TestResolver_InsightSeries(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		autogold.Want("insights[0][0].Points mocked", "[{p:{SeriesID: Time:{wall:0 ext:63271811045 loc:<nil>} Value:1 Metadata:[]}} {p:{SeriesID: Time:{wall:0 ext:63271811045 loc:<nil>} Value:2 Metadata:[]}} {p:{SeriesID: Time:{wall:0 ext:63271811045 loc:<nil>} Value:3 Metadata:[]}}]").Equal(t, fmt.Sprintf("%+v", points))
+		autogold.Want("insights[0][0].Points mocked", "[{p:{SeriesID: Time:{wall:0 ext:63271811045 loc:<nil>} Value:1 Metadata:[] Capture:<nil>}} {p:{SeriesID: Time:{wall:0 ext:63271811045 loc:<nil>} Value:2 Metadata:[] Capture:<nil>}} {p:{SeriesID: Time:{wall:0 ext:63271811045 loc:<nil>} Value:3 Metadata:[] Capture:<nil>}}]").Equal(t, fmt.Sprintf("%+v", points))
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		ctx, insights, mock, cleanup := testSetup(t)
+		defer cleanup()
+
+		lastSuccessAt := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+		lastError := "search timed out"
+		mock.SeriesRunStatusFunc.SetDefaultReturn(store.SeriesRunStatus{
+			LastAttemptAt:       lastSuccessAt.Add(time.Hour),
+			LastSuccessAt:       &lastSuccessAt,
+			LastError:           &lastError,
+			ConsecutiveFailures: 1,
+		}, true, nil)
+
+		status, err := insights[0][0].Status(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := status.LastSuccessAt(); have == nil || !have.Time.Equal(lastSuccessAt) {
+			t.Errorf("unexpected last success time. want=%s have=%v", lastSuccessAt, have)
+		}
+		// Errors are visible to site admins, which the authz bypass of the test context includes.
+		if have := status.LastError(); have == nil || *have != lastError {
+			t.Errorf("unexpected last error. want=%q have=%v", lastError, have)
+		}
+		if have := status.ConsecutiveFailures(); have != 1 {
+			t.Errorf("unexpected number of consecutive failures. want=%d have=%d", 1, have)
+		}
 	})
 }
+
+func TestInsightSeriesResolverRepositoryPoints(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	insightsStore := store.NewMockInterface()
+	insightsStore.RepoPointsAtFunc.SetDefaultReturn([]store.RepoPoint{
+		{RepoID: 1, RepoName: "github.com/a/one", Time: at, Value: 5},
+		{RepoID: 2, RepoName: "github.com/a/two", Time: at.Add(-time.Hour), Value: 2},
+	}, nil)
+	capture := "v1"
+	r := &insightSeriesResolver{insightsStore: insightsStore, series: insights.TimeSeries{Query: "foo"}, capture: &capture}
+
+	exclude := "two"
+	points, err := r.RepositoryPoints(ctx, &graphqlbackend.InsightsRepositoryPointsArgs{DateTime: graphqlbackend.DateTime{Time: at}, First: 10, ExcludeRepoRegex: &exclude})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(points) != 2 || points[0].Repository() != "github.com/a/one" || points[0].Value() != 5 {
+		t.Errorf("unexpected points: %+v", points)
+	}
+
+	if history := insightsStore.RepoPointsAtFunc.History(); len(history) != 1 {
+		t.Fatalf("unexpected number of calls. want=%d have=%d", 1, len(history))
+	} else {
+		want := store.RepoPointsAtOpts{SeriesID: discovery.Encode(r.series), Capture: &capture, Time: at, ExcludeRepoRegex: exclude, Limit: 10}
+		if diff := cmp.Diff(want, history[0].Arg1); diff != "" {
+			t.Errorf("unexpected store options (-want +got):\n%s", diff)
+		}
+	}
+
+	if _, err := r.RepositoryPoints(ctx, &graphqlbackend.InsightsRepositoryPointsArgs{DateTime: graphqlbackend.DateTime{Time: at}, First: maxRepositoryPoints + 1}); err == nil {
+		t.Errorf("expected error for too many points")
+	}
+}
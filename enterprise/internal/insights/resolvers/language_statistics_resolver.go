@@ -0,0 +1,26 @@
+package resolvers
+
+import (
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+)
+
+var _ graphqlbackend.InsightsLanguageStatisticsResolver = &insightsLanguageStatisticsResolver{}
+
+type insightsLanguageStatisticsResolver struct {
+	stats store.LanguageStats
+}
+
+func (r *insightsLanguageStatisticsResolver) Commit() string { return string(r.stats.Commit) }
+
+func (r *insightsLanguageStatisticsResolver) ComputedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.stats.RecordedAt}
+}
+
+func (r *insightsLanguageStatisticsResolver) Languages() []graphqlbackend.LanguageStatisticsResolver {
+	resolvers := make([]graphqlbackend.LanguageStatisticsResolver, 0, len(r.stats.Languages))
+	for _, language := range r.stats.Languages {
+		resolvers = append(resolvers, graphqlbackend.NewLanguageStatisticsResolver(language))
+	}
+	return resolvers
+}
@@ -0,0 +1,83 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/inventory"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestResolver_InsightsLanguageStatistics(t *testing.T) {
+	ctx := context.Background()
+	recordedAt := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	database.Mocks.Repos.GetByName = func(ctx context.Context, name api.RepoName) (*types.Repo, error) {
+		if name != "github.com/sourcegraph/sourcegraph" {
+			return nil, &database.RepoNotFoundErr{Name: name}
+		}
+		return &types.Repo{ID: 7, Name: name}, nil
+	}
+	defer func() { database.Mocks.Repos = database.MockRepos{} }()
+
+	insightsStore := store.NewMockInterface()
+	insightsStore.LanguageStatsFunc.SetDefaultHook(func(ctx context.Context, repoID api.RepoID) (*store.LanguageStats, error) {
+		if repoID != 7 {
+			return nil, nil
+		}
+		return &store.LanguageStats{
+			RepoID:     7,
+			RepoName:   "github.com/sourcegraph/sourcegraph",
+			Commit:     "deadbeef",
+			Languages:  []inventory.Lang{{Name: "Go", TotalBytes: 1024, TotalLines: 40}},
+			RecordedAt: recordedAt,
+		}, nil
+	})
+	resolver := &Resolver{insightsStore: insightsStore, repoStore: database.Repos(nil)}
+
+	stats, err := resolver.InsightsLanguageStatistics(ctx, &graphqlbackend.InsightsLanguageStatisticsArgs{Repository: "github.com/sourcegraph/sourcegraph"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if stats == nil {
+		t.Fatal("expected language statistics")
+	}
+	if value := stats.Commit(); value != "deadbeef" {
+		t.Errorf("unexpected commit. want=%q have=%q", "deadbeef", value)
+	}
+	if value := stats.ComputedAt().Time; !value.Equal(recordedAt) {
+		t.Errorf("unexpected computed at. want=%s have=%s", recordedAt, value)
+	}
+
+	type language struct {
+		Name       string
+		TotalBytes float64
+		TotalLines int32
+	}
+	var languages []language
+	for _, l := range stats.Languages() {
+		languages = append(languages, language{l.Name(), l.TotalBytes(), l.TotalLines()})
+	}
+	if diff := cmp.Diff([]language{{"Go", 1024, 40}}, languages); diff != "" {
+		t.Errorf("unexpected languages (-want +got):\n%s", diff)
+	}
+
+	// Repositories that are unknown or inaccessible resolve to null.
+	stats, err = resolver.InsightsLanguageStatistics(ctx, &graphqlbackend.InsightsLanguageStatisticsArgs{Repository: "github.com/sourcegraph/private"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if stats != nil {
+		t.Errorf("unexpected language statistics for inaccessible repository")
+	}
+	if value := len(insightsStore.LanguageStatsFunc.History()); value != 1 {
+		t.Errorf("unexpected number of language statistics lookups. want=%d have=%d", 1, value)
+	}
+}
@@ -0,0 +1,86 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+// InsightProblems returns the problems of all insights found by the most recent validation pass
+// (see discovery.NewValidateInsightsJob).
+func (r *Resolver) InsightProblems(ctx context.Context) ([]graphqlbackend.InsightProblemResolver, error) {
+	// 🚨 SECURITY: Problems are recorded for the insights of all namespaces, so only site admins
+	// can list them all. Users see the problems of the insights they can see (see
+	// insightResolver.Problems).
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.workerBaseStore.Handle().DB()); err != nil {
+		return nil, err
+	}
+	problems, err := r.insightStore.GetInsightProblems(ctx, store.InsightProblemQueryArgs{})
+	if err != nil {
+		return nil, err
+	}
+	return toInsightProblemResolvers(problems), nil
+}
+
+func (r *insightResolver) Problems(ctx context.Context) ([]graphqlbackend.InsightProblemResolver, error) {
+	problems, err := r.insightStore.GetInsightProblems(ctx, store.InsightProblemQueryArgs{InsightIDs: []string{r.insight.ID}})
+	if err != nil {
+		return nil, err
+	}
+	// Insights of different namespaces may share an ID.
+	filtered := problems[:0]
+	for _, problem := range problems {
+		if problemNamespace(problem) == r.insight.Namespace {
+			filtered = append(filtered, problem)
+		}
+	}
+	return toInsightProblemResolvers(filtered), nil
+}
+
+func problemNamespace(problem types.InsightProblem) insights.Namespace {
+	var namespace insights.Namespace
+	if problem.UserID != nil {
+		namespace.UserID = *problem.UserID
+	}
+	if problem.OrgID != nil {
+		namespace.OrgID = *problem.OrgID
+	}
+	return namespace
+}
+
+func toInsightProblemResolvers(problems []types.InsightProblem) []graphqlbackend.InsightProblemResolver {
+	resolvers := make([]graphqlbackend.InsightProblemResolver, 0, len(problems))
+	for _, problem := range problems {
+		resolvers = append(resolvers, &insightProblemResolver{problem: problem})
+	}
+	return resolvers
+}
+
+var _ graphqlbackend.InsightProblemResolver = &insightProblemResolver{}
+
+type insightProblemResolver struct {
+	problem types.InsightProblem
+}
+
+func (r *insightProblemResolver) InsightID() string { return r.problem.InsightID }
+
+func (r *insightProblemResolver) SeriesID() *string { return optionalString(r.problem.SeriesID) }
+
+func (r *insightProblemResolver) SeriesLabel() *string { return optionalString(r.problem.SeriesLabel) }
+
+func (r *insightProblemResolver) Message() string { return r.problem.Problem }
+
+func (r *insightProblemResolver) DetectedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.problem.DetectedAt}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
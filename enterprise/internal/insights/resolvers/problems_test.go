@@ -0,0 +1,37 @@
+package resolvers
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestInsightProblemResolver(t *testing.T) {
+	orgID := int32(3)
+	resolvers := toInsightProblemResolvers([]types.InsightProblem{
+		{InsightID: "insight-1", Problem: "insight has no series"},
+		{InsightID: "insight-2", SeriesID: "s:1", SeriesLabel: "todos", OrgID: &orgID, Problem: "invalid search query"},
+	})
+	if len(resolvers) != 2 {
+		t.Fatalf("unexpected number of resolvers. want=%d have=%d", 2, len(resolvers))
+	}
+	if seriesID, seriesLabel := resolvers[0].SeriesID(), resolvers[0].SeriesLabel(); seriesID != nil || seriesLabel != nil {
+		t.Errorf("unexpected series of insight problem. want=nil have=%v %v", seriesID, seriesLabel)
+	}
+	if seriesID := resolvers[1].SeriesID(); seriesID == nil || *seriesID != "s:1" {
+		t.Errorf("unexpected series ID. want=%q have=%v", "s:1", seriesID)
+	}
+
+	for _, testCase := range []struct {
+		problem types.InsightProblem
+		want    insights.Namespace
+	}{
+		{types.InsightProblem{}, insights.Namespace{}},
+		{types.InsightProblem{OrgID: &orgID}, insights.Namespace{OrgID: orgID}},
+	} {
+		if have := problemNamespace(testCase.problem); have != testCase.want {
+			t.Errorf("unexpected namespace. want=%v have=%v", testCase.want, have)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/recomputerunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+const insightSeriesRecomputationIDKind = "InsightSeriesRecomputation"
+
+// RecomputeInsightSeries enqueues the recomputation of the given series for the recompute runner
+// worker, which deletes its data and has it recorded again from scratch.
+func (r *Resolver) RecomputeInsightSeries(ctx context.Context, args *graphqlbackend.RecomputeInsightSeriesArgs) (graphqlbackend.InsightSeriesRecomputationResolver, error) {
+	// 🚨 SECURITY: Recomputing a series deletes its data for every user, and runs its searches
+	// across all repositories again, so only site admins can recompute series.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.workerBaseStore.Handle().DB()); err != nil {
+		return nil, err
+	}
+
+	// Report series that cannot be recomputed right away rather than through a failed job.
+	if _, err := recomputerunner.RecomputableSeries(ctx, r.insightStore, r.settingStore, insights.NewLoader(r.workerBaseStore.Handle().DB()), args.SeriesID); err != nil {
+		return nil, err
+	}
+
+	job := &recomputerunner.Job{
+		SeriesID: args.SeriesID,
+		UserID:   actor.FromContext(ctx).UID,
+		State:    "queued",
+	}
+	id, err := recomputerunner.EnqueueJob(ctx, r.workerBaseStore, job)
+	if err != nil {
+		return nil, errors.Wrap(err, "EnqueueJob")
+	}
+	job.ID = id
+	return r.newInsightSeriesRecomputationResolver(ctx, job)
+}
+
+// InsightSeriesRecomputation returns the given recomputation.
+func (r *Resolver) InsightSeriesRecomputation(ctx context.Context, args *graphqlbackend.InsightSeriesRecomputationArgs) (graphqlbackend.InsightSeriesRecomputationResolver, error) {
+	// 🚨 SECURITY: Only site admins can recompute series, and failures may name repositories other
+	// users cannot access.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx, r.workerBaseStore.Handle().DB()); err != nil {
+		return nil, err
+	}
+	var id int
+	if err := relay.UnmarshalSpec(args.ID, &id); err != nil {
+		return nil, err
+	}
+
+	job, ok, err := recomputerunner.GetJob(ctx, r.workerBaseStore, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	return r.newInsightSeriesRecomputationResolver(ctx, job)
+}
+
+// newInsightSeriesRecomputationResolver returns a resolver of the given recomputation, with the
+// progress of the backfill of its series.
+func (r *Resolver) newInsightSeriesRecomputationResolver(ctx context.Context, job *recomputerunner.Job) (*insightSeriesRecomputationResolver, error) {
+	seriesID := job.SeriesID
+	recordedPoints, err := r.insightsStore.CountData(ctx, store.CountDataOpts{SeriesID: &seriesID})
+	if err != nil {
+		return nil, errors.Wrap(err, "CountData")
+	}
+	status, err := queryrunner.QueryJobsStatus(ctx, r.workerBaseStore, seriesID)
+	if err != nil {
+		return nil, errors.Wrap(err, "QueryJobsStatus")
+	}
+	series, err := r.insightStore.GetDataSeries(ctx, store.GetDataSeriesArgs{SeriesID: seriesID})
+	if err != nil {
+		return nil, errors.Wrap(err, "GetDataSeries")
+	}
+
+	// Series that are not stored in the database yet are backfilled by the historical enqueuer,
+	// rather than by the backfiller.
+	backfilled := true
+	if len(series) > 0 {
+		backfilled = backfilledSince(series[0].BackfillQueuedAt, job.FinishedAt)
+	}
+	return &insightSeriesRecomputationResolver{
+		job:                 job,
+		recordedPoints:      int32(recordedPoints),
+		pendingBackfillJobs: int32(status.PendingBackfill),
+		backfilling:         !backfilled || status.PendingBackfill > 0,
+	}, nil
+}
+
+// backfilledSince returns true if the historical data of a series was enqueued again after its
+// data was deleted at the given time.
+func backfilledSince(backfillQueuedAt, deletedAt *time.Time) bool {
+	return backfillQueuedAt != nil && deletedAt != nil && !backfillQueuedAt.Before(*deletedAt)
+}
+
+var _ graphqlbackend.InsightSeriesRecomputationResolver = &insightSeriesRecomputationResolver{}
+
+type insightSeriesRecomputationResolver struct {
+	job                 *recomputerunner.Job
+	recordedPoints      int32
+	pendingBackfillJobs int32
+
+	// backfilling is true if the historical data of the series is not recorded again yet.
+	backfilling bool
+}
+
+func (r *insightSeriesRecomputationResolver) ID() graphql.ID {
+	return relay.MarshalID(insightSeriesRecomputationIDKind, r.job.ID)
+}
+
+func (r *insightSeriesRecomputationResolver) SeriesID() string { return r.job.SeriesID }
+
+func (r *insightSeriesRecomputationResolver) State() string {
+	if r.job.State == "completed" && r.backfilling {
+		return "BACKFILLING"
+	}
+	return strings.ToUpper(r.job.State)
+}
+
+func (r *insightSeriesRecomputationResolver) Failure() *string { return r.job.FailureMessage }
+
+func (r *insightSeriesRecomputationResolver) DeletedPoints() *int32 {
+	return optionalInt32(r.job.DeletedPoints)
+}
+
+func (r *insightSeriesRecomputationResolver) RecordedPoints() int32 { return r.recordedPoints }
+
+func (r *insightSeriesRecomputationResolver) PendingBackfillJobs() int32 {
+	return r.pendingBackfillJobs
+}
@@ -0,0 +1,70 @@
+package resolvers
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/recomputerunner"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+func TestRecomputeInsightSeriesNotSiteAdmin(t *testing.T) {
+	r := &Resolver{workerBaseStore: basestore.NewWithDB(nil, sql.TxOptions{})}
+	if _, err := r.RecomputeInsightSeries(context.Background(), &graphqlbackend.RecomputeInsightSeriesArgs{SeriesID: "s:series"}); !errors.Is(err, backend.ErrNotAuthenticated) {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+	if _, err := r.InsightSeriesRecomputation(context.Background(), &graphqlbackend.InsightSeriesRecomputationArgs{ID: "recomputation"}); !errors.Is(err, backend.ErrNotAuthenticated) {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+}
+
+func TestInsightSeriesRecomputationResolver(t *testing.T) {
+	job := &recomputerunner.Job{ID: 1, SeriesID: "s:series", State: "processing"}
+	r := &insightSeriesRecomputationResolver{job: job, backfilling: true}
+	if state := r.State(); state != "PROCESSING" {
+		t.Errorf("unexpected state. want=%q have=%q", "PROCESSING", state)
+	}
+	if deleted := r.DeletedPoints(); deleted != nil {
+		t.Errorf("unexpected deleted points of recomputation being processed. want=nil have=%d", *deleted)
+	}
+
+	deleted := 12
+	job.State, job.DeletedPoints = "completed", &deleted
+	if state := r.State(); state != "BACKFILLING" {
+		t.Errorf("unexpected state. want=%q have=%q", "BACKFILLING", state)
+	}
+	if have := r.DeletedPoints(); have == nil || *have != 12 {
+		t.Errorf("unexpected deleted points. want=%d have=%v", 12, have)
+	}
+
+	r.backfilling = false
+	if state := r.State(); state != "COMPLETED" {
+		t.Errorf("unexpected state. want=%q have=%q", "COMPLETED", state)
+	}
+}
+
+func TestBackfilledSince(t *testing.T) {
+	deletedAt := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+	before, after := deletedAt.Add(-time.Hour), deletedAt.Add(time.Minute)
+	for _, testCase := range []struct {
+		name             string
+		backfillQueuedAt *time.Time
+		deletedAt        *time.Time
+		want             bool
+	}{
+		{name: "not backfilled", backfillQueuedAt: nil, deletedAt: &deletedAt, want: false},
+		{name: "backfilled before deletion", backfillQueuedAt: &before, deletedAt: &deletedAt, want: false},
+		{name: "backfilled after deletion", backfillQueuedAt: &after, deletedAt: &deletedAt, want: true},
+		{name: "not deleted yet", backfillQueuedAt: &before, deletedAt: nil, want: false},
+	} {
+		if have := backfilledSince(testCase.backfillQueuedAt, testCase.deletedAt); have != testCase.want {
+			t.Errorf("unexpected backfilled for %s. want=%t have=%t", testCase.name, testCase.want, have)
+		}
+	}
+}
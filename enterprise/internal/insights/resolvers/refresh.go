@@ -0,0 +1,134 @@
+package resolvers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/queryrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/webhookrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+	"github.com/sourcegraph/sourcegraph/internal/insights/priority"
+)
+
+// ErrRefreshRateLimited is returned when a user refreshes insight series more often than allowed.
+var ErrRefreshRateLimited = errors.New("too many insight series refreshes, try again later")
+
+// RefreshInsightSeries enqueues a query for the current data point of the given series at critical
+// priority, so that the data point is recorded ahead of all other queued work, e.g. right after a
+// user edited the series. The current data point of webhook series is requested from their webhook.
+func (r *Resolver) RefreshInsightSeries(ctx context.Context, args *graphqlbackend.RefreshInsightSeriesArgs) (*graphqlbackend.EmptyResponse, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+	// Every refresh runs a search query across all repositories, so limit how often each user can
+	// trigger one.
+	if !r.refreshLimiter.Allow(a.UID) {
+		return nil, ErrRefreshRateLimited
+	}
+
+	// 🚨 SECURITY: Users may only refresh the series of insights they can see.
+	namespaces, err := discovery.VisibleNamespaces(ctx, r.workerBaseStore.Handle().DB())
+	if err != nil {
+		return nil, err
+	}
+	discovered, err := discovery.Discover(ctx, r.insightStore, r.settingStore, insights.NewLoader(r.workerBaseStore.Handle().DB()), discovery.InsightFilterArgs{Namespaces: namespaces, SeriesIDs: []string{args.SeriesID}})
+	if err != nil {
+		return nil, errors.Wrap(err, "Discover")
+	}
+	series, ok := findSeries(discovered, args.SeriesID)
+	if !ok {
+		return nil, errors.Errorf("insight series %q not found", args.SeriesID)
+	}
+	if err := discovery.ValidateSeries(series); err != nil {
+		return nil, err
+	}
+
+	if series.Expression != "" {
+		return nil, errors.Errorf("insight series %q is derived from other series and is refreshed with them", args.SeriesID)
+	}
+	if series.Webhook != "" {
+		if _, err := webhookrunner.EnqueueJob(ctx, r.workerBaseStore, &webhookrunner.Job{
+			SeriesID:   args.SeriesID,
+			WebhookURL: series.Webhook,
+			State:      "queued",
+		}); err != nil {
+			return nil, errors.Wrap(err, "EnqueueJob")
+		}
+		return &graphqlbackend.EmptyResponse{}, nil
+	}
+
+	cost, err := discovery.NewCostEstimator(database.Repos(r.workerBaseStore.Handle().DB())).SeriesCost(ctx, series)
+	if err != nil {
+		return nil, errors.Wrap(err, "SeriesCost")
+	}
+	if _, err := queryrunner.EnqueueJob(ctx, r.workerBaseStore, &queryrunner.Job{
+		SeriesID:    args.SeriesID,
+		SearchQuery: queryrunner.WithCountUnlimited(discovery.ScopedQuery(series)),
+		State:       "queued",
+		Priority:    int(priority.Critical),
+		Cost:        int(cost),
+	}); err != nil {
+		return nil, errors.Wrap(err, "EnqueueJob")
+	}
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+// findSeries returns the series with the given series ID among the series of the given insights.
+func findSeries(foundInsights []insights.SearchInsight, seriesID string) (insights.TimeSeries, bool) {
+	for _, insight := range foundInsights {
+		for _, series := range insight.Series {
+			if discovery.Encode(series) == seriesID {
+				return series, true
+			}
+		}
+	}
+	return insights.TimeSeries{}, false
+}
+
+const (
+	// refreshInterval is the interval at which a user regains a refresh after exhausting their
+	// refreshBurst.
+	refreshInterval = time.Minute
+	refreshBurst    = 5
+)
+
+// userRateLimiter rate limits operations of each user independently.
+type userRateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	limiters map[int32]*rate.Limiter
+}
+
+func newUserRateLimiter(limit rate.Limit, burst int) *userRateLimiter {
+	return &userRateLimiter{
+		limit:    limit,
+		burst:    burst,
+		limiters: map[int32]*rate.Limiter{},
+	}
+}
+
+// Allow reports whether the given user may perform an operation now, consuming one of their
+// operations if so.
+func (l *userRateLimiter) Allow(userID int32) bool {
+	l.mu.Lock()
+	limiter, ok := l.limiters[userID]
+	if !ok {
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.limiters[userID] = limiter
+	}
+	l.mu.Unlock()
+
+	return limiter.Allow()
+}
@@ -0,0 +1,69 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/time/rate"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+func TestRefreshInsightSeriesRequiresAuthentication(t *testing.T) {
+	r := &Resolver{refreshLimiter: newUserRateLimiter(rate.Inf, 1)}
+
+	_, err := r.RefreshInsightSeries(context.Background(), &graphqlbackend.RefreshInsightSeriesArgs{SeriesID: "s:1"})
+	if !errors.Is(err, backend.ErrNotAuthenticated) {
+		t.Fatalf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+}
+
+func TestRefreshInsightSeriesRateLimited(t *testing.T) {
+	r := &Resolver{refreshLimiter: newUserRateLimiter(0, 0)}
+	ctx := actor.WithActor(context.Background(), actor.FromUser(1))
+
+	_, err := r.RefreshInsightSeries(ctx, &graphqlbackend.RefreshInsightSeriesArgs{SeriesID: "s:1"})
+	if !errors.Is(err, ErrRefreshRateLimited) {
+		t.Fatalf("unexpected error. want=%q have=%q", ErrRefreshRateLimited, err)
+	}
+}
+
+func TestUserRateLimiter(t *testing.T) {
+	l := newUserRateLimiter(rate.Every(refreshInterval), 2)
+
+	for i, want := range []bool{true, true, false} {
+		if have := l.Allow(1); have != want {
+			t.Errorf("unexpected result of attempt %d of user 1. want=%v have=%v", i, want, have)
+		}
+	}
+	// Other users are limited independently.
+	if !l.Allow(2) {
+		t.Errorf("unexpected result of attempt of user 2. want=%v have=%v", true, false)
+	}
+}
+
+func TestFindSeries(t *testing.T) {
+	first := insights.TimeSeries{Name: "first", Query: "errorf"}
+	second := insights.TimeSeries{Name: "second", Query: "fmt.Printf"}
+	found := []insights.SearchInsight{
+		{ID: "a", Series: []insights.TimeSeries{first}},
+		{ID: "b", Series: []insights.TimeSeries{second}},
+	}
+
+	series, ok := findSeries(found, discovery.Encode(second))
+	if !ok {
+		t.Fatalf("expected series to be found")
+	}
+	if series.Name != "second" {
+		t.Errorf("unexpected series. want=%q have=%q", "second", series.Name)
+	}
+
+	if _, ok := findSeries(found, "unknown"); ok {
+		t.Errorf("expected unknown series not to be found")
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"golang.org/x/time/rate"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/timeutil"
 )
 
@@ -20,8 +23,11 @@ var _ graphqlbackend.InsightsResolver = &Resolver{}
 // Resolver is the GraphQL resolver of all things related to Insights.
 type Resolver struct {
 	insightsStore   store.Interface
+	insightStore    *store.InsightStore
 	workerBaseStore *basestore.Store
 	settingStore    *database.SettingStore
+	repoStore       *database.RepoStore
+	refreshLimiter  *userRateLimiter
 }
 
 // New returns a new Resolver whose store uses the given Timescale and Postgres DBs.
@@ -34,8 +40,11 @@ func New(timescale, postgres dbutil.DB) graphqlbackend.InsightsResolver {
 func newWithClock(timescale, postgres dbutil.DB, clock func() time.Time) *Resolver {
 	return &Resolver{
 		insightsStore:   store.NewWithClock(timescale, store.NewInsightPermissionStore(postgres), clock),
+		insightStore:    store.NewInsightStore(timescale),
 		workerBaseStore: basestore.NewWithDB(postgres, sql.TxOptions{}),
 		settingStore:    database.Settings(postgres),
+		repoStore:       database.Repos(postgres),
+		refreshLimiter:  newUserRateLimiter(rate.Every(refreshInterval), refreshBurst),
 	}
 }
 
@@ -49,12 +58,31 @@ func (r *Resolver) Insights(ctx context.Context, args *graphqlbackend.InsightsAr
 	}
 	return &insightConnectionResolver{
 		insightsStore:   r.insightsStore,
+		insightStore:    r.insightStore,
 		workerBaseStore: r.workerBaseStore,
 		settingStore:    r.settingStore,
 		ids:             idList,
 	}, nil
 }
 
+func (r *Resolver) InsightsLanguageStatistics(ctx context.Context, args *graphqlbackend.InsightsLanguageStatisticsArgs) (graphqlbackend.InsightsLanguageStatisticsResolver, error) {
+	// 🚨 SECURITY: Statistics are recorded for all configured repositories, so resolve the
+	// repository as the current user first to ensure they can access it.
+	repo, err := r.repoStore.GetByName(ctx, api.RepoName(args.Repository))
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	stats, err := r.insightsStore.LanguageStats(ctx, repo.ID)
+	if err != nil || stats == nil {
+		return nil, err
+	}
+	return &insightsLanguageStatisticsResolver{stats: *stats}, nil
+}
+
 type disabledResolver struct {
 	reason string
 }
@@ -66,3 +94,63 @@ func NewDisabledResolver(reason string) graphqlbackend.InsightsResolver {
 func (r *disabledResolver) Insights(ctx context.Context, args *graphqlbackend.InsightsArgs) (graphqlbackend.InsightConnectionResolver, error) {
 	return nil, errors.New(r.reason)
 }
+
+func (r *disabledResolver) InsightsLanguageStatistics(ctx context.Context, args *graphqlbackend.InsightsLanguageStatisticsArgs) (graphqlbackend.InsightsLanguageStatisticsResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) RefreshInsightSeries(ctx context.Context, args *graphqlbackend.RefreshInsightSeriesArgs) (*graphqlbackend.EmptyResponse, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) ResumeInsightSeries(ctx context.Context, args *graphqlbackend.ResumeInsightSeriesArgs) (*graphqlbackend.EmptyResponse, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) InsightExport(ctx context.Context, args *graphqlbackend.InsightExportArgs) (graphqlbackend.InsightExportResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) InsightProblems(ctx context.Context) ([]graphqlbackend.InsightProblemResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) InsightDuplicates(ctx context.Context) ([]graphqlbackend.InsightDuplicateGroupResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) ExportInsight(ctx context.Context, args *graphqlbackend.ExportInsightArgs) (graphqlbackend.InsightExportResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) InsightSnapshotComparison(ctx context.Context, args *graphqlbackend.InsightSnapshotComparisonArgs) (graphqlbackend.InsightSnapshotComparisonResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) CompareInsightSeriesSnapshots(ctx context.Context, args *graphqlbackend.CompareInsightSeriesSnapshotsArgs) (graphqlbackend.InsightSnapshotComparisonResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) InsightSeriesImport(ctx context.Context, args *graphqlbackend.InsightSeriesImportArgs) (graphqlbackend.InsightSeriesImportResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) ImportInsightSeriesPoints(ctx context.Context, args *graphqlbackend.ImportInsightSeriesPointsArgs) (graphqlbackend.InsightSeriesImportResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) InsightSeriesRecomputation(ctx context.Context, args *graphqlbackend.InsightSeriesRecomputationArgs) (graphqlbackend.InsightSeriesRecomputationResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) RecomputeInsightSeries(ctx context.Context, args *graphqlbackend.RecomputeInsightSeriesArgs) (graphqlbackend.InsightSeriesRecomputationResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) CreateInsightSeriesAlertRule(ctx context.Context, args *graphqlbackend.CreateInsightSeriesAlertRuleArgs) (graphqlbackend.InsightSeriesAlertRuleResolver, error) {
+	return nil, errors.New(r.reason)
+}
+
+func (r *disabledResolver) DeleteInsightSeriesAlertRule(ctx context.Context, args *graphqlbackend.DeleteInsightSeriesAlertRuleArgs) (*graphqlbackend.EmptyResponse, error) {
+	return nil, errors.New(r.reason)
+}
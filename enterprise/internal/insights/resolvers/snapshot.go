@@ -0,0 +1,141 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/snapshotrunner"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/discovery"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/insights"
+)
+
+const insightSnapshotComparisonIDKind = "InsightSnapshotComparison"
+
+// CompareInsightSeriesSnapshots enqueues the comparison of the given series between two points in
+// time for the snapshot runner worker, on behalf of the current user.
+func (r *Resolver) CompareInsightSeriesSnapshots(ctx context.Context, args *graphqlbackend.CompareInsightSeriesSnapshotsArgs) (graphqlbackend.InsightSnapshotComparisonResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+	if !args.From.Before(args.To.Time) {
+		return nil, errors.New("from must be before to")
+	}
+
+	// 🚨 SECURITY: Users may only compare the series of insights they can see.
+	namespaces, err := discovery.VisibleNamespaces(ctx, r.workerBaseStore.Handle().DB())
+	if err != nil {
+		return nil, err
+	}
+	discovered, err := discovery.Discover(ctx, r.insightStore, r.settingStore, insights.NewLoader(r.workerBaseStore.Handle().DB()), discovery.InsightFilterArgs{Namespaces: namespaces, SeriesIDs: []string{args.SeriesID}})
+	if err != nil {
+		return nil, errors.Wrap(err, "Discover")
+	}
+	series, ok := findSeries(discovered, args.SeriesID)
+	if !ok {
+		return nil, errors.Errorf("insight series %q not found", args.SeriesID)
+	}
+	if series.GeneratedFromCaptureGroups {
+		return nil, errors.Errorf("insight series %q is generated from capture groups and cannot be compared", args.SeriesID)
+	}
+	if series.GroupBy != "" {
+		return nil, errors.Errorf("insight series %q is grouped by %s and cannot be compared", args.SeriesID, series.GroupBy)
+	}
+
+	job := &snapshotrunner.Job{
+		SeriesID: args.SeriesID,
+		From:     args.From.Time,
+		To:       args.To.Time,
+		UserID:   a.UID,
+		State:    "queued",
+	}
+	job.ID, err = snapshotrunner.EnqueueJob(ctx, r.workerBaseStore, job)
+	if err != nil {
+		return nil, errors.Wrap(err, "EnqueueJob")
+	}
+	return &insightSnapshotComparisonResolver{job: job}, nil
+}
+
+// InsightSnapshotComparison returns the given comparison, if it was requested by the current user.
+func (r *Resolver) InsightSnapshotComparison(ctx context.Context, args *graphqlbackend.InsightSnapshotComparisonArgs) (graphqlbackend.InsightSnapshotComparisonResolver, error) {
+	a := actor.FromContext(ctx)
+	if !a.IsAuthenticated() {
+		return nil, backend.ErrNotAuthenticated
+	}
+	var id int
+	if err := relay.UnmarshalSpec(args.ID, &id); err != nil {
+		return nil, err
+	}
+
+	job, ok, err := snapshotrunner.GetJob(ctx, r.workerBaseStore, id)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Comparisons hold the repositories visible to the user who requested them, so
+	// they are only visible to that user.
+	if !ok || job.UserID != a.UID {
+		return nil, nil
+	}
+	return &insightSnapshotComparisonResolver{job: job}, nil
+}
+
+var _ graphqlbackend.InsightSnapshotComparisonResolver = &insightSnapshotComparisonResolver{}
+
+type insightSnapshotComparisonResolver struct {
+	job *snapshotrunner.Job
+}
+
+func (r *insightSnapshotComparisonResolver) ID() graphql.ID {
+	return relay.MarshalID(insightSnapshotComparisonIDKind, r.job.ID)
+}
+
+func (r *insightSnapshotComparisonResolver) SeriesID() string { return r.job.SeriesID }
+
+func (r *insightSnapshotComparisonResolver) From() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.job.From}
+}
+
+func (r *insightSnapshotComparisonResolver) To() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.job.To}
+}
+
+func (r *insightSnapshotComparisonResolver) State() string { return strings.ToUpper(r.job.State) }
+
+func (r *insightSnapshotComparisonResolver) Failure() *string { return r.job.FailureMessage }
+
+func (r *insightSnapshotComparisonResolver) Changes() (*[]graphqlbackend.InsightSnapshotRepositoryChangeResolver, error) {
+	if r.job.State != "completed" {
+		return nil, nil
+	}
+	var comparison snapshotrunner.Comparison
+	if err := json.Unmarshal(r.job.Data, &comparison); err != nil {
+		return nil, errors.Wrap(err, "decoding comparison")
+	}
+	resolvers := make([]graphqlbackend.InsightSnapshotRepositoryChangeResolver, 0, len(comparison.Changes))
+	for _, change := range comparison.Changes {
+		resolvers = append(resolvers, &insightSnapshotRepositoryChangeResolver{change: change})
+	}
+	return &resolvers, nil
+}
+
+var _ graphqlbackend.InsightSnapshotRepositoryChangeResolver = &insightSnapshotRepositoryChangeResolver{}
+
+type insightSnapshotRepositoryChangeResolver struct {
+	change snapshotrunner.RepoChange
+}
+
+func (r *insightSnapshotRepositoryChangeResolver) Repository() string { return r.change.Repository }
+
+func (r *insightSnapshotRepositoryChangeResolver) FromValue() float64 { return r.change.FromValue }
+
+func (r *insightSnapshotRepositoryChangeResolver) ToValue() float64 { return r.change.ToValue }
+
+func (r *insightSnapshotRepositoryChangeResolver) Delta() float64 { return r.change.Delta() }
@@ -0,0 +1,56 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/background/snapshotrunner"
+)
+
+func TestCompareInsightSeriesSnapshotsNotAuthenticated(t *testing.T) {
+	r := &Resolver{}
+	from := graphqlbackend.DateTime{Time: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)}
+	to := graphqlbackend.DateTime{Time: time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)}
+	if _, err := r.CompareInsightSeriesSnapshots(context.Background(), &graphqlbackend.CompareInsightSeriesSnapshotsArgs{SeriesID: "series", From: from, To: to}); err != backend.ErrNotAuthenticated {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+	if _, err := r.InsightSnapshotComparison(context.Background(), &graphqlbackend.InsightSnapshotComparisonArgs{ID: "comparison"}); err != backend.ErrNotAuthenticated {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrNotAuthenticated, err)
+	}
+}
+
+func TestInsightSnapshotComparisonResolver(t *testing.T) {
+	job := &snapshotrunner.Job{
+		ID:       1,
+		SeriesID: "series",
+		Data:     []byte(`{"changes": [{"repository": "github.com/sourcegraph/sourcegraph", "fromValue": 5, "toValue": 2}]}`),
+		State:    "processing",
+	}
+	r := &insightSnapshotComparisonResolver{job: job}
+
+	changes, err := r.Changes()
+	if err != nil {
+		t.Fatalf("unexpected error getting changes: %s", err)
+	}
+	if changes != nil {
+		t.Errorf("unexpected changes of comparison being processed. want=nil have=%v", *changes)
+	}
+
+	job.State = "completed"
+	if state := r.State(); state != "COMPLETED" {
+		t.Errorf("unexpected state. want=%q have=%q", "COMPLETED", state)
+	}
+	changes, err = r.Changes()
+	if err != nil {
+		t.Fatalf("unexpected error getting changes: %s", err)
+	}
+	if changes == nil || len(*changes) != 1 {
+		t.Fatalf("unexpected changes of completed comparison. want=1 change have=%v", changes)
+	}
+	if change := (*changes)[0]; change.Repository() != "github.com/sourcegraph/sourcegraph" || change.Delta() != -3 {
+		t.Errorf("unexpected change. want=%q with delta -3 have=%q with delta %v", "github.com/sourcegraph/sourcegraph", change.Repository(), change.Delta())
+	}
+}
@@ -0,0 +1,223 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// AlertCondition is the condition under which an alert rule fires.
+type AlertCondition string
+
+const (
+	// AlertAbove rules fire when the value of the series is above the threshold.
+	AlertAbove AlertCondition = "above"
+
+	// AlertBelow rules fire when the value of the series is below the threshold.
+	AlertBelow AlertCondition = "below"
+
+	// AlertPercentChange rules fire when the value of the series changed by at least the threshold,
+	// in percent, over the window of the rule. Negative thresholds are decreases.
+	AlertPercentChange AlertCondition = "percent_change"
+)
+
+// AlertRule is a rule notifying a user when the values of a series cross a threshold.
+type AlertRule struct {
+	ID          int
+	SeriesID    string
+	SeriesLabel string
+
+	// UserID is the user who created the rule. Rules check the values of the series visible to
+	// this user, and notify this user.
+	UserID int32
+
+	Condition  AlertCondition
+	Threshold  float64
+	WindowDays int
+
+	NotifyEmail bool
+	WebhookURL  *string
+
+	CreatedAt time.Time
+
+	// LastEvaluatedTime is the time of the most recent recording of the series the rule was
+	// checked against, if any.
+	LastEvaluatedTime *time.Time
+
+	// Firing is true if the condition held for the most recent recording checked.
+	Firing bool
+
+	LastNotifiedAt *time.Time
+}
+
+// CreateAlertRule creates the given alert rule, and returns it with its ID and creation time.
+func (s *Store) CreateAlertRule(ctx context.Context, rule AlertRule) (AlertRule, error) {
+	rule.CreatedAt = s.now().UTC()
+	id, _, err := basestore.ScanFirstInt(s.Store.Query(ctx, sqlf.Sprintf(
+		createAlertRuleFmtstr,
+		rule.SeriesID,
+		rule.SeriesLabel,
+		rule.UserID,
+		rule.Condition,
+		rule.Threshold,
+		rule.WindowDays,
+		rule.NotifyEmail,
+		rule.WebhookURL,
+		rule.CreatedAt,
+	)))
+	rule.ID = id
+	return rule, err
+}
+
+const createAlertRuleFmtstr = `
+-- source: enterprise/internal/insights/store/alert_rules.go:CreateAlertRule
+INSERT INTO insight_series_alert_rules (series_id, series_label, user_id, condition, threshold, window_days, notify_email, webhook_url, created_at)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s)
+RETURNING id
+`
+
+// DeleteAlertRule deletes the given alert rule of the given user. It returns false if the user has
+// no such rule.
+func (s *Store) DeleteAlertRule(ctx context.Context, id int, userID int32) (bool, error) {
+	count, _, err := basestore.ScanFirstInt(s.Store.Query(ctx, sqlf.Sprintf(deleteAlertRuleFmtstr, id, userID)))
+	return count > 0, err
+}
+
+const deleteAlertRuleFmtstr = `
+-- source: enterprise/internal/insights/store/alert_rules.go:DeleteAlertRule
+WITH deleted AS (
+	DELETE FROM insight_series_alert_rules WHERE id = %s AND user_id = %s RETURNING id
+) SELECT count(*) FROM deleted
+`
+
+// ListAlertRulesOpts describes options for listing alert rules.
+type ListAlertRulesOpts struct {
+	// SeriesID, if non-empty, restricts the rules to the rules of this series.
+	SeriesID string
+
+	// UserID, if non-zero, restricts the rules to the rules of this user.
+	UserID int32
+}
+
+// ListAlertRules returns the alert rules matching the given options, ordered by ID.
+func (s *Store) ListAlertRules(ctx context.Context, opts ListAlertRulesOpts) ([]AlertRule, error) {
+	preds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if opts.SeriesID != "" {
+		preds = append(preds, sqlf.Sprintf("series_id = %s", opts.SeriesID))
+	}
+	if opts.UserID != 0 {
+		preds = append(preds, sqlf.Sprintf("user_id = %s", opts.UserID))
+	}
+
+	var rules []AlertRule
+	err := s.query(ctx, sqlf.Sprintf(listAlertRulesFmtstr, sqlf.Join(preds, "\n AND ")), func(sc scanner) error {
+		var rule AlertRule
+		if err := sc.Scan(
+			&rule.ID,
+			&rule.SeriesID,
+			&rule.SeriesLabel,
+			&rule.UserID,
+			&rule.Condition,
+			&rule.Threshold,
+			&rule.WindowDays,
+			&rule.NotifyEmail,
+			&rule.WebhookURL,
+			&rule.CreatedAt,
+			&rule.LastEvaluatedTime,
+			&rule.Firing,
+			&rule.LastNotifiedAt,
+		); err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+		return nil
+	})
+	return rules, err
+}
+
+const listAlertRulesFmtstr = `
+-- source: enterprise/internal/insights/store/alert_rules.go:ListAlertRules
+SELECT id, series_id, series_label, user_id, condition, threshold, window_days, notify_email, webhook_url,
+	created_at, last_evaluated_time, firing, last_notified_at
+FROM insight_series_alert_rules
+WHERE %s
+ORDER BY id
+`
+
+// RecordAlertEvaluation records that the given alert rule was checked against the recording of its
+// series at the given time, and whether it fired. If notified is true, the time of the last
+// notification of the rule is set to the store's clock.
+func (s *Store) RecordAlertEvaluation(ctx context.Context, id int, evaluatedTime time.Time, firing, notified bool) error {
+	var notifiedAt *time.Time
+	if notified {
+		now := s.now().UTC()
+		notifiedAt = &now
+	}
+	return s.Exec(ctx, sqlf.Sprintf(recordAlertEvaluationFmtstr, evaluatedTime.UTC(), firing, notifiedAt, id))
+}
+
+const recordAlertEvaluationFmtstr = `
+-- source: enterprise/internal/insights/store/alert_rules.go:RecordAlertEvaluation
+UPDATE insight_series_alert_rules SET
+	last_evaluated_time = %s,
+	firing = %s,
+	last_notified_at = COALESCE(%s, last_notified_at)
+WHERE id = %s
+`
+
+// LatestSeriesPointTime returns the time of the most recent recording of the given series. It
+// returns false if the series has no data points.
+func (s *Store) LatestSeriesPointTime(ctx context.Context, seriesID string) (_ time.Time, ok bool, err error) {
+	var latest *time.Time
+	err = s.query(ctx, sqlf.Sprintf(latestSeriesPointTimeFmtstr, seriesID), func(sc scanner) error {
+		return sc.Scan(&latest)
+	})
+	if err != nil || latest == nil {
+		return time.Time{}, false, err
+	}
+	return *latest, true, nil
+}
+
+const latestSeriesPointTimeFmtstr = `
+-- source: enterprise/internal/insights/store/alert_rules.go:LatestSeriesPointTime
+SELECT max(time) FROM series_points WHERE series_id = %s
+`
+
+// SeriesValueAt returns the value of the given series at the given time: the sum of the values of
+// the latest data point of each repository (and of the series itself, for data points not recorded
+// per repository) recorded at or before that time. It returns false if no data point was recorded
+// at or before that time.
+func (s *Store) SeriesValueAt(ctx context.Context, seriesID string, t time.Time) (_ float64, ok bool, err error) {
+	// 🚨 SECURITY: As for SeriesPoints, exclude the repositories the current user cannot see.
+	denylist, err := s.permStore.GetUnauthorizedRepoIDs(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	excluded := sqlf.Sprintf("TRUE")
+	if len(denylist) > 0 {
+		excluded = sqlf.Sprintf(fmt.Sprintf("(repo_id IS NULL OR repo_id != all(%v))", values(denylist)))
+	}
+
+	var value *float64
+	err = s.query(ctx, sqlf.Sprintf(seriesValueAtFmtstr, seriesID, t.UTC(), excluded), func(sc scanner) error {
+		return sc.Scan(&value)
+	})
+	if err != nil || value == nil {
+		return 0, false, err
+	}
+	return *value, true, nil
+}
+
+const seriesValueAtFmtstr = `
+-- source: enterprise/internal/insights/store/alert_rules.go:SeriesValueAt
+SELECT sum(sub.value) FROM (
+	SELECT DISTINCT ON (repo_id, capture) value
+	FROM series_points
+	WHERE series_id = %s AND time <= %s AND %s
+	ORDER BY repo_id, capture, time DESC
+) sub
+`
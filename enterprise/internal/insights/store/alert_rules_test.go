@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestAlertRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	now := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	webhookURL := "https://example.com/alerts"
+	above, err := store.CreateAlertRule(ctx, AlertRule{SeriesID: "s:one", SeriesLabel: "one", UserID: 1, Condition: AlertAbove, Threshold: 10, NotifyEmail: true})
+	if err != nil {
+		t.Fatalf("unexpected error creating rule: %s", err)
+	}
+	change, err := store.CreateAlertRule(ctx, AlertRule{SeriesID: "s:one", UserID: 2, Condition: AlertPercentChange, Threshold: -50, WindowDays: 7, WebhookURL: &webhookURL})
+	if err != nil {
+		t.Fatalf("unexpected error creating rule: %s", err)
+	}
+	if _, err := store.CreateAlertRule(ctx, AlertRule{SeriesID: "s:two", UserID: 1, Condition: AlertBelow, Threshold: 1}); err != nil {
+		t.Fatalf("unexpected error creating rule: %s", err)
+	}
+
+	evaluatedTime := now.Add(-time.Hour)
+	if err := store.RecordAlertEvaluation(ctx, above.ID, evaluatedTime, true, true); err != nil {
+		t.Fatalf("unexpected error recording evaluation: %s", err)
+	}
+	now = now.Add(time.Hour)
+	// Rules keep the time of their last notification until they notify again.
+	if err := store.RecordAlertEvaluation(ctx, above.ID, evaluatedTime, true, false); err != nil {
+		t.Fatalf("unexpected error recording evaluation: %s", err)
+	}
+
+	rules, err := store.ListAlertRules(ctx, ListAlertRulesOpts{SeriesID: "s:one"})
+	if err != nil {
+		t.Fatalf("unexpected error listing rules: %s", err)
+	}
+	notifiedAt := now.Add(-time.Hour)
+	above.LastEvaluatedTime, above.Firing, above.LastNotifiedAt = &evaluatedTime, true, &notifiedAt
+	want := []AlertRule{above, change}
+	if diff := cmp.Diff(want, rules, cmp.Transformer("UTC", func(t time.Time) time.Time { return t.UTC() })); diff != "" {
+		t.Errorf("unexpected rules (-want +got):\n%s", diff)
+	}
+
+	// Users may only delete their own rules.
+	if deleted, err := store.DeleteAlertRule(ctx, change.ID, 1); err != nil {
+		t.Fatalf("unexpected error deleting rule: %s", err)
+	} else if deleted {
+		t.Errorf("unexpected deletion of the rule of another user")
+	}
+	if deleted, err := store.DeleteAlertRule(ctx, change.ID, 2); err != nil {
+		t.Fatalf("unexpected error deleting rule: %s", err)
+	} else if !deleted {
+		t.Errorf("expected rule to be deleted")
+	}
+
+	rules, err = store.ListAlertRules(ctx, ListAlertRulesOpts{UserID: 2})
+	if err != nil {
+		t.Fatalf("unexpected error listing rules: %s", err)
+	}
+	if len(rules) != 0 {
+		t.Errorf("unexpected rules of deleted rule: %+v", rules)
+	}
+}
+
+func TestSeriesValueAt(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := New(timescale, permStore)
+
+	first := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	record := func(t time.Time, repoID api.RepoID, repoName string, value float64) RecordSeriesPointArgs {
+		return RecordSeriesPointArgs{
+			SeriesID: "s:one",
+			Point:    SeriesPoint{Time: t, Value: value},
+			RepoName: &repoName,
+			RepoID:   &repoID,
+		}
+	}
+	for _, args := range []RecordSeriesPointArgs{
+		record(first, 1, "repo1", 1),
+		record(first, 2, "repo2", 2),
+		record(second, 1, "repo1", 5),
+	} {
+		if err := store.RecordSeriesPoint(ctx, args); err != nil {
+			t.Fatalf("unexpected error recording point: %s", err)
+		}
+	}
+
+	if latest, ok, err := store.LatestSeriesPointTime(ctx, "s:one"); err != nil {
+		t.Fatalf("unexpected error getting latest time: %s", err)
+	} else if !ok || !latest.Equal(second) {
+		t.Errorf("unexpected latest time. want=%s have=%s", second, latest)
+	}
+	if _, ok, err := store.LatestSeriesPointTime(ctx, "s:none"); err != nil {
+		t.Fatalf("unexpected error getting latest time: %s", err)
+	} else if ok {
+		t.Errorf("unexpected latest time of a series without data points")
+	}
+
+	for _, testCase := range []struct {
+		name string
+		at   time.Time
+		want float64
+		ok   bool
+	}{
+		{"before any point", first.Add(-time.Hour), 0, false},
+		{"first", first, 3, true},
+		// repo2 was not recorded at the second time, so its value is carried forward.
+		{"second", second, 7, true},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			value, ok, err := store.SeriesValueAt(ctx, "s:one", testCase.at)
+			if err != nil {
+				t.Fatalf("unexpected error getting value: %s", err)
+			}
+			if ok != testCase.ok || value != testCase.want {
+				t.Errorf("unexpected value. want=%v (%v) have=%v (%v)", testCase.want, testCase.ok, value, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+)
+
+// BackfillCheckpoint describes the progress of the historical enqueuer over a series in its current
+// pass over all repositories, so that an interrupted backfill resumes where it stopped instead of
+// starting over.
+type BackfillCheckpoint struct {
+	SeriesID string
+
+	// RepoName is the repository being backfilled. Repositories are backfilled in a stable order,
+	// so the repositories before it are done.
+	RepoName string
+
+	// FrameFrom is the start of the oldest timeframe backfilled in the repository. Timeframes are
+	// backfilled from the newest to the oldest, so the timeframes starting at or after it are done.
+	FrameFrom time.Time
+}
+
+// BackfillCheckpoints returns the checkpoints of the given series, by series ID. Series without
+// a checkpoint are not backfilled yet in the current pass.
+func (s *Store) BackfillCheckpoints(ctx context.Context, seriesIDs []string) (map[string]BackfillCheckpoint, error) {
+	checkpoints := make(map[string]BackfillCheckpoint, len(seriesIDs))
+	err := s.query(ctx, sqlf.Sprintf(backfillCheckpointsFmtstr, pq.Array(seriesIDs)), func(sc scanner) error {
+		var c BackfillCheckpoint
+		if err := sc.Scan(&c.SeriesID, &c.RepoName, &c.FrameFrom); err != nil {
+			return err
+		}
+		checkpoints[c.SeriesID] = c
+		return nil
+	})
+	return checkpoints, err
+}
+
+const backfillCheckpointsFmtstr = `
+-- source: enterprise/internal/insights/store/backfill_checkpoints.go:BackfillCheckpoints
+SELECT series_id, repo_name, frame_from
+FROM insight_series_backfill_checkpoints
+WHERE series_id = ANY(%s)
+`
+
+// SaveBackfillCheckpoint records the progress of the historical enqueuer over a series, replacing
+// its previous checkpoint.
+func (s *Store) SaveBackfillCheckpoint(ctx context.Context, c BackfillCheckpoint) error {
+	return s.Exec(ctx, sqlf.Sprintf(saveBackfillCheckpointFmtstr, c.SeriesID, c.RepoName, c.FrameFrom.UTC(), s.now().UTC()))
+}
+
+const saveBackfillCheckpointFmtstr = `
+-- source: enterprise/internal/insights/store/backfill_checkpoints.go:SaveBackfillCheckpoint
+INSERT INTO insight_series_backfill_checkpoints (series_id, repo_name, frame_from, updated_at)
+VALUES (%s, %s, %s, %s)
+ON CONFLICT (series_id) DO UPDATE SET
+	repo_name = EXCLUDED.repo_name,
+	frame_from = EXCLUDED.frame_from,
+	updated_at = EXCLUDED.updated_at
+`
+
+// ClearBackfillCheckpoints removes the checkpoints of the given series once a pass over all
+// repositories completed, so that the next pass starts from the first repository.
+func (s *Store) ClearBackfillCheckpoints(ctx context.Context, seriesIDs []string) error {
+	return s.Exec(ctx, sqlf.Sprintf(clearBackfillCheckpointsFmtstr, pq.Array(seriesIDs)))
+}
+
+const clearBackfillCheckpointsFmtstr = `
+-- source: enterprise/internal/insights/store/backfill_checkpoints.go:ClearBackfillCheckpoints
+DELETE FROM insight_series_backfill_checkpoints WHERE series_id = ANY(%s)
+`
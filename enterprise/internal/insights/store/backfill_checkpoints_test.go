@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestBackfillCheckpoints(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	now := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, func() time.Time { return now })
+
+	frameFrom := now.Add(-30 * 24 * time.Hour)
+	for _, c := range []BackfillCheckpoint{
+		{SeriesID: "s:one", RepoName: "github.com/a/a", FrameFrom: now},
+		{SeriesID: "s:one", RepoName: "github.com/b/b", FrameFrom: frameFrom},
+		{SeriesID: "s:two", RepoName: "github.com/a/a", FrameFrom: frameFrom},
+	} {
+		if err := store.SaveBackfillCheckpoint(ctx, c); err != nil {
+			t.Fatalf("unexpected error saving checkpoint: %s", err)
+		}
+	}
+
+	// Saving a checkpoint replaces the previous checkpoint of the series.
+	checkpoints, err := store.BackfillCheckpoints(ctx, []string{"s:one", "s:three"})
+	if err != nil {
+		t.Fatalf("unexpected error getting checkpoints: %s", err)
+	}
+	want := map[string]BackfillCheckpoint{
+		"s:one": {SeriesID: "s:one", RepoName: "github.com/b/b", FrameFrom: frameFrom},
+	}
+	if diff := cmp.Diff(want, checkpoints); diff != "" {
+		t.Errorf("unexpected checkpoints (-want +got):\n%s", diff)
+	}
+
+	if err := store.ClearBackfillCheckpoints(ctx, []string{"s:one"}); err != nil {
+		t.Fatalf("unexpected error clearing checkpoints: %s", err)
+	}
+	checkpoints, err = store.BackfillCheckpoints(ctx, []string{"s:one", "s:two"})
+	if err != nil {
+		t.Fatalf("unexpected error getting checkpoints: %s", err)
+	}
+	if len(checkpoints) != 1 || checkpoints["s:two"].RepoName != "github.com/a/a" {
+		t.Errorf("unexpected checkpoints after clearing: %v", checkpoints)
+	}
+}
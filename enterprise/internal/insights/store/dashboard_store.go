@@ -0,0 +1,269 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// DashboardStore exposes methods to read and write insight dashboards. It does not read the actor
+// of the context: callers restrict the dashboards to those granted to a user explicitly, so that it
+// is also safe to use from background jobs.
+type DashboardStore struct {
+	*basestore.Store
+	Now func() time.Time
+}
+
+// NewDashboardStore returns a new DashboardStore backed by the given Timescale db.
+func NewDashboardStore(db dbutil.DB) *DashboardStore {
+	return &DashboardStore{Store: basestore.NewWithDB(db, sql.TxOptions{}), Now: time.Now}
+}
+
+// Handle returns the underlying transactable database handle.
+func (s *DashboardStore) Handle() *basestore.TransactableHandle { return s.Store.Handle() }
+
+func (s *DashboardStore) Transact(ctx context.Context) (*DashboardStore, error) {
+	txBase, err := s.Store.Transact(ctx)
+	return &DashboardStore{Store: txBase, Now: s.Now}, err
+}
+
+// DashboardGrant grants access to a dashboard to a single user, to the members of a single
+// organization, or to all users.
+type DashboardGrant struct {
+	UserID *int32
+	OrgID  *int32
+	Global *bool
+}
+
+// UserDashboardGrant returns a grant of a dashboard to the given user.
+func UserDashboardGrant(userID int32) DashboardGrant {
+	return DashboardGrant{UserID: &userID}
+}
+
+// OrgDashboardGrant returns a grant of a dashboard to the members of the given organization.
+func OrgDashboardGrant(orgID int32) DashboardGrant {
+	return DashboardGrant{OrgID: &orgID}
+}
+
+// GlobalDashboardGrant returns a grant of a dashboard to all users.
+func GlobalDashboardGrant() DashboardGrant {
+	global := true
+	return DashboardGrant{Global: &global}
+}
+
+// DashboardQueryArgs contains query predicates for fetching dashboards.
+type DashboardQueryArgs struct {
+	IDs       []int
+	UniqueIDs []string
+
+	// UserIDs and OrgIDs, if either is non-empty, restrict the dashboards to those shared with any
+	// of these users or organizations, or with all users.
+	//
+	// 🚨 SECURITY: Dashboards shared with anyone are returned unless they are restricted.
+	UserIDs []int32
+	OrgIDs  []int32
+}
+
+// GetDashboards returns the dashboards matching the given arguments that have not been deleted,
+// ordered by ID.
+func (s *DashboardStore) GetDashboards(ctx context.Context, args DashboardQueryArgs) (_ []types.Dashboard, err error) {
+	preds := []*sqlf.Query{sqlf.Sprintf("d.deleted_at IS NULL")}
+	if len(args.IDs) > 0 {
+		preds = append(preds, sqlf.Sprintf("d.id = ANY(%s)", pq.Array(args.IDs)))
+	}
+	if len(args.UniqueIDs) > 0 {
+		preds = append(preds, sqlf.Sprintf("d.unique_id = ANY(%s)", pq.Array(args.UniqueIDs)))
+	}
+	if len(args.UserIDs) > 0 || len(args.OrgIDs) > 0 {
+		preds = append(preds, sqlf.Sprintf(dashboardGrantedFmtstr, pq.Array(args.UserIDs), pq.Array(args.OrgIDs)))
+	}
+
+	rows, err := s.Query(ctx, sqlf.Sprintf(getDashboardsFmtstr, sqlf.Join(preds, "\n AND ")))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	dashboards := make([]types.Dashboard, 0)
+	for rows.Next() {
+		var d types.Dashboard
+		if err := rows.Scan(
+			&d.ID,
+			&d.UniqueID,
+			&d.Title,
+			pq.Array(&d.InsightIDs),
+			pq.Array(&d.UserIDs),
+			pq.Array(&d.OrgIDs),
+			&d.GlobalGrant,
+		); err != nil {
+			return nil, err
+		}
+		dashboards = append(dashboards, d)
+	}
+	return dashboards, nil
+}
+
+const getDashboardsFmtstr = `
+-- source: enterprise/internal/insights/store/dashboard_store.go:GetDashboards
+SELECT
+	d.id,
+	d.unique_id,
+	d.title,
+	ARRAY(SELECT div.insight_view_unique_id FROM dashboard_insight_view div WHERE div.dashboard_id = d.id ORDER BY div.id),
+	ARRAY(SELECT g.user_id FROM dashboard_grants g WHERE g.dashboard_id = d.id AND g.user_id IS NOT NULL ORDER BY g.user_id),
+	ARRAY(SELECT g.org_id FROM dashboard_grants g WHERE g.dashboard_id = d.id AND g.org_id IS NOT NULL ORDER BY g.org_id),
+	EXISTS(SELECT 1 FROM dashboard_grants g WHERE g.dashboard_id = d.id AND g.global IS TRUE)
+FROM dashboard d
+WHERE %s
+ORDER BY d.id
+`
+
+const dashboardGrantedFmtstr = `
+d.id IN (SELECT g.dashboard_id FROM dashboard_grants g WHERE g.global IS TRUE OR g.user_id = ANY(%s) OR g.org_id = ANY(%s))
+`
+
+// CreateDashboardArgs describes a dashboard to create.
+type CreateDashboardArgs struct {
+	Title      string
+	UniqueID   *string
+	InsightIDs []string
+	Grants     []DashboardGrant
+}
+
+// CreateDashboard creates a dashboard holding the given insights, shared with the subjects of the
+// given grants, and returns its ID.
+func (s *DashboardStore) CreateDashboard(ctx context.Context, args CreateDashboardArgs) (_ int, err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = tx.Store.Done(err) }()
+
+	now := s.Now().UTC()
+	id, _, err := basestore.ScanFirstInt(tx.Query(ctx, sqlf.Sprintf(createDashboardFmtstr, args.UniqueID, args.Title, now, now)))
+	if err != nil {
+		return 0, errors.Wrap(err, "creating dashboard")
+	}
+	if err := tx.replaceInsights(ctx, id, args.InsightIDs); err != nil {
+		return 0, err
+	}
+	if err := tx.replaceGrants(ctx, id, args.Grants); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+const createDashboardFmtstr = `
+-- source: enterprise/internal/insights/store/dashboard_store.go:CreateDashboard
+INSERT INTO dashboard (unique_id, title, created_at, last_updated_at) VALUES (%s, %s, %s, %s) RETURNING id
+`
+
+// UpdateDashboardArgs describes changes to a dashboard. Fields that are nil are left unchanged.
+type UpdateDashboardArgs struct {
+	ID         int
+	Title      *string
+	InsightIDs []string
+	Grants     []DashboardGrant
+}
+
+// UpdateDashboard applies the given changes to a dashboard. The insights and the grants of the
+// dashboard are replaced as a whole.
+func (s *DashboardStore) UpdateDashboard(ctx context.Context, args UpdateDashboardArgs) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Store.Done(err) }()
+
+	if err := tx.Exec(ctx, sqlf.Sprintf(updateDashboardFmtstr, args.Title, s.Now().UTC(), args.ID)); err != nil {
+		return errors.Wrap(err, "updating dashboard")
+	}
+	if args.InsightIDs != nil {
+		if err := tx.replaceInsights(ctx, args.ID, args.InsightIDs); err != nil {
+			return err
+		}
+	}
+	if args.Grants != nil {
+		if err := tx.replaceGrants(ctx, args.ID, args.Grants); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const updateDashboardFmtstr = `
+-- source: enterprise/internal/insights/store/dashboard_store.go:UpdateDashboard
+UPDATE dashboard SET title = COALESCE(%s, title), last_updated_at = %s WHERE id = %s
+`
+
+func (s *DashboardStore) replaceInsights(ctx context.Context, dashboardID int, insightIDs []string) error {
+	if err := s.Exec(ctx, sqlf.Sprintf(deleteDashboardInsightsFmtstr, dashboardID)); err != nil {
+		return errors.Wrap(err, "removing dashboard insights")
+	}
+	if len(insightIDs) == 0 {
+		return nil
+	}
+	values := make([]*sqlf.Query, 0, len(insightIDs))
+	for _, insightID := range insightIDs {
+		values = append(values, sqlf.Sprintf("(%s, %s)", dashboardID, insightID))
+	}
+	if err := s.Exec(ctx, sqlf.Sprintf(insertDashboardInsightsFmtstr, sqlf.Join(values, ", "))); err != nil {
+		return errors.Wrap(err, "adding dashboard insights")
+	}
+	return nil
+}
+
+const deleteDashboardInsightsFmtstr = `
+-- source: enterprise/internal/insights/store/dashboard_store.go:replaceInsights
+DELETE FROM dashboard_insight_view WHERE dashboard_id = %s
+`
+
+const insertDashboardInsightsFmtstr = `
+-- source: enterprise/internal/insights/store/dashboard_store.go:replaceInsights
+INSERT INTO dashboard_insight_view (dashboard_id, insight_view_unique_id) VALUES %s ON CONFLICT DO NOTHING
+`
+
+func (s *DashboardStore) replaceGrants(ctx context.Context, dashboardID int, grants []DashboardGrant) error {
+	if err := s.Exec(ctx, sqlf.Sprintf(deleteDashboardGrantsFmtstr, dashboardID)); err != nil {
+		return errors.Wrap(err, "removing dashboard grants")
+	}
+	if len(grants) == 0 {
+		return nil
+	}
+	values := make([]*sqlf.Query, 0, len(grants))
+	for _, grant := range grants {
+		values = append(values, sqlf.Sprintf("(%s, %s, %s, %s)", dashboardID, grant.UserID, grant.OrgID, grant.Global))
+	}
+	if err := s.Exec(ctx, sqlf.Sprintf(insertDashboardGrantsFmtstr, sqlf.Join(values, ", "))); err != nil {
+		return errors.Wrap(err, "adding dashboard grants")
+	}
+	return nil
+}
+
+const deleteDashboardGrantsFmtstr = `
+-- source: enterprise/internal/insights/store/dashboard_store.go:replaceGrants
+DELETE FROM dashboard_grants WHERE dashboard_id = %s
+`
+
+const insertDashboardGrantsFmtstr = `
+-- source: enterprise/internal/insights/store/dashboard_store.go:replaceGrants
+INSERT INTO dashboard_grants (dashboard_id, user_id, org_id, global) VALUES %s
+`
+
+// DeleteDashboard deletes the given dashboard. The insights of the dashboard are not deleted.
+func (s *DashboardStore) DeleteDashboard(ctx context.Context, id int) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteDashboardFmtstr, s.Now().UTC(), id))
+}
+
+const deleteDashboardFmtstr = `
+-- source: enterprise/internal/insights/store/dashboard_store.go:DeleteDashboard
+UPDATE dashboard SET deleted_at = %s WHERE id = %s AND deleted_at IS NULL
+`
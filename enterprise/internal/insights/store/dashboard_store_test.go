@@ -0,0 +1,96 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+)
+
+func TestDashboardStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	store := NewDashboardStore(timescale)
+
+	uniqueID := "dashboard-1"
+	global, err := store.CreateDashboard(ctx, CreateDashboardArgs{
+		Title:      "global",
+		UniqueID:   &uniqueID,
+		InsightIDs: []string{"insight-2", "insight-1"},
+		Grants:     []DashboardGrant{GlobalDashboardGrant()},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating dashboard: %s", err)
+	}
+	shared, err := store.CreateDashboard(ctx, CreateDashboardArgs{
+		Title:      "shared",
+		InsightIDs: []string{"insight-3"},
+		Grants:     []DashboardGrant{UserDashboardGrant(1), OrgDashboardGrant(5)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating dashboard: %s", err)
+	}
+
+	globalDashboard := types.Dashboard{ID: global, Title: "global", UniqueID: &uniqueID, InsightIDs: []string{"insight-2", "insight-1"}, UserIDs: []int32{}, OrgIDs: []int32{}, GlobalGrant: true}
+	sharedDashboard := types.Dashboard{ID: shared, Title: "shared", InsightIDs: []string{"insight-3"}, UserIDs: []int32{1}, OrgIDs: []int32{5}}
+
+	for _, testCase := range []struct {
+		name string
+		args DashboardQueryArgs
+		want []types.Dashboard
+	}{
+		{"all", DashboardQueryArgs{}, []types.Dashboard{globalDashboard, sharedDashboard}},
+		{"by unique ID", DashboardQueryArgs{UniqueIDs: []string{uniqueID}}, []types.Dashboard{globalDashboard}},
+		{"granted to user", DashboardQueryArgs{UserIDs: []int32{1}}, []types.Dashboard{globalDashboard, sharedDashboard}},
+		{"granted to org", DashboardQueryArgs{UserIDs: []int32{2}, OrgIDs: []int32{5}}, []types.Dashboard{globalDashboard, sharedDashboard}},
+		{"not granted", DashboardQueryArgs{UserIDs: []int32{2}, OrgIDs: []int32{6}}, []types.Dashboard{globalDashboard}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			dashboards, err := store.GetDashboards(ctx, testCase.args)
+			if err != nil {
+				t.Fatalf("unexpected error getting dashboards: %s", err)
+			}
+			if diff := cmp.Diff(testCase.want, dashboards); diff != "" {
+				t.Errorf("unexpected dashboards (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	// Updates replace the insights and the grants of the dashboard.
+	title := "renamed"
+	if err := store.UpdateDashboard(ctx, UpdateDashboardArgs{ID: shared, Title: &title, InsightIDs: []string{"insight-1"}, Grants: []DashboardGrant{UserDashboardGrant(2)}}); err != nil {
+		t.Fatalf("unexpected error updating dashboard: %s", err)
+	}
+	dashboards, err := store.GetDashboards(ctx, DashboardQueryArgs{IDs: []int{shared}})
+	if err != nil {
+		t.Fatalf("unexpected error getting dashboards: %s", err)
+	}
+	want := []types.Dashboard{{ID: shared, Title: "renamed", InsightIDs: []string{"insight-1"}, UserIDs: []int32{2}, OrgIDs: []int32{}}}
+	if diff := cmp.Diff(want, dashboards); diff != "" {
+		t.Errorf("unexpected dashboards (-want +got):\n%s", diff)
+	}
+
+	if err := store.DeleteDashboard(ctx, global); err != nil {
+		t.Fatalf("unexpected error deleting dashboard: %s", err)
+	}
+	dashboards, err = store.GetDashboards(ctx, DashboardQueryArgs{})
+	if err != nil {
+		t.Fatalf("unexpected error getting dashboards: %s", err)
+	}
+	if len(dashboards) != 1 || dashboards[0].ID != shared {
+		t.Errorf("unexpected dashboards after deletion: %+v", dashboards)
+	}
+
+	// The unique ID of a deleted dashboard can be reused.
+	if _, err := store.CreateDashboard(ctx, CreateDashboardArgs{Title: "global", UniqueID: &uniqueID}); err != nil {
+		t.Fatalf("unexpected error recreating dashboard: %s", err)
+	}
+}
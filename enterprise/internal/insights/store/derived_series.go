@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+)
+
+// DerivedSeriesPendingTimes returns the times, in order, at which any of the given operand series
+// recorded data points before the given time, but at which the given derived series has no data
+// point yet.
+func (s *Store) DerivedSeriesPendingTimes(ctx context.Context, seriesID string, operandSeriesIDs []string, before time.Time) ([]time.Time, error) {
+	var times []time.Time
+	err := s.query(ctx, sqlf.Sprintf(derivedSeriesPendingTimesFmtstr, pq.Array(operandSeriesIDs), before, seriesID), func(sc scanner) error {
+		var t time.Time
+		if err := sc.Scan(&t); err != nil {
+			return err
+		}
+		times = append(times, t)
+		return nil
+	})
+	return times, err
+}
+
+const derivedSeriesPendingTimesFmtstr = `
+-- source: enterprise/internal/insights/store/derived_series.go:DerivedSeriesPendingTimes
+SELECT DISTINCT sp.time FROM series_points sp
+WHERE sp.series_id = ANY(%s) AND sp.time < %s
+	AND NOT EXISTS (SELECT 1 FROM series_points d WHERE d.series_id = %s AND d.time = sp.time)
+ORDER BY sp.time
+`
+
+// SeriesTotal is the value of a series at a point in time.
+type SeriesTotal struct {
+	Time  time.Time
+	Value float64
+
+	// Approximate is true if any of the data points the value is the sum of is approximate.
+	Approximate bool
+}
+
+// SeriesTotalsAt returns the value of the given series at each of the given times, computed as by
+// SeriesValueAt. Times at or before which the series has no data point are omitted.
+//
+// 🚨 SECURITY: Unlike SeriesValueAt, the values sum the data points of every repository, regardless
+// of the repositories the current user can see. They must only be used to record data points that
+// are not recorded per repository, which SeriesPoints only serves to users that can see every
+// repository.
+func (s *Store) SeriesTotalsAt(ctx context.Context, seriesID string, times []time.Time) ([]SeriesTotal, error) {
+	if len(times) == 0 {
+		return nil, nil
+	}
+	formatted := make([]string, 0, len(times))
+	for _, t := range times {
+		formatted = append(formatted, t.UTC().Format(time.RFC3339Nano))
+	}
+
+	var totals []SeriesTotal
+	err := s.query(ctx, sqlf.Sprintf(seriesTotalsAtFmtstr, pq.Array(formatted), seriesID), func(sc scanner) error {
+		var total SeriesTotal
+		if err := sc.Scan(&total.Time, &total.Value, &total.Approximate); err != nil {
+			return err
+		}
+		totals = append(totals, total)
+		return nil
+	})
+	return totals, err
+}
+
+const seriesTotalsAtFmtstr = `
+-- source: enterprise/internal/insights/store/derived_series.go:SeriesTotalsAt
+SELECT t.time, sum(sub.value), bool_or(sub.approximate)
+FROM unnest(%s::timestamptz[]) AS t(time)
+JOIN LATERAL (
+	SELECT DISTINCT ON (repo_id, capture) value, approximate
+	FROM series_points
+	WHERE series_id = %s AND time <= t.time
+	ORDER BY repo_id, capture, time DESC
+) sub ON TRUE
+GROUP BY t.time
+ORDER BY t.time
+`
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+)
+
+func TestDerivedSeries(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, timeutil.Now)
+
+	optionalString := func(v string) *string { return &v }
+	optionalRepoID := func(v api.RepoID) *api.RepoID { return &v }
+
+	t1 := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	t3 := t2.Add(24 * time.Hour)
+	for _, record := range []RecordSeriesPointArgs{
+		{SeriesID: "a", Point: SeriesPoint{Time: t1, Value: 1}, RepoName: optionalString("repo1"), RepoID: optionalRepoID(1)},
+		{SeriesID: "a", Point: SeriesPoint{Time: t1, Value: 2}, RepoName: optionalString("repo2"), RepoID: optionalRepoID(2)},
+		{SeriesID: "a", Point: SeriesPoint{Time: t2, Value: 5, Approximate: true}, RepoName: optionalString("repo1"), RepoID: optionalRepoID(1)},
+		{SeriesID: "b", Point: SeriesPoint{Time: t2, Value: 10}},
+		{SeriesID: "a", Point: SeriesPoint{Time: t3, Value: 1}, RepoName: optionalString("repo2"), RepoID: optionalRepoID(2)},
+		{SeriesID: "d", Point: SeriesPoint{Time: t1, Value: 0}},
+	} {
+		if err := store.RecordSeriesPoint(ctx, record); err != nil {
+			t.Fatalf("unexpected error recording series point: %s", err)
+		}
+	}
+
+	pending, err := store.DerivedSeriesPendingTimes(ctx, "d", []string{"a", "b"}, t3)
+	if err != nil {
+		t.Fatalf("unexpected error getting pending times: %s", err)
+	}
+	if diff := cmp.Diff([]time.Time{t2}, pending); diff != "" {
+		t.Errorf("unexpected pending times (-want +got):\n%s", diff)
+	}
+
+	totals, err := store.SeriesTotalsAt(ctx, "a", []time.Time{t1.Add(-time.Hour), t1, t2, t3})
+	if err != nil {
+		t.Fatalf("unexpected error getting series totals: %s", err)
+	}
+	want := []SeriesTotal{
+		{Time: t1, Value: 3},
+		{Time: t2, Value: 7, Approximate: true},
+		{Time: t3, Value: 6, Approximate: true},
+	}
+	if diff := cmp.Diff(want, totals); diff != "" {
+		t.Errorf("unexpected series totals (-want +got):\n%s", diff)
+	}
+}
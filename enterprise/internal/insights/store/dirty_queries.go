@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+// DirtyQuery describes a data point of a series which could not be recorded because its query
+// failed, and which is retried in the background.
+type DirtyQuery struct {
+	ID       int
+	SeriesID string
+	Query    string
+	ForTime  time.Time
+	Reason   string
+
+	// PinnedRepo and PinnedRevision describe the revision the query is pinned to, if any (see
+	// queryrunner.Job).
+	PinnedRepo     *string
+	PinnedRevision *string
+
+	DirtyAt       time.Time
+	Attempts      int
+	NextAttemptAt time.Time
+}
+
+// MarkQueryDirty records that the query of the given dirty query failed. Only its series ID, query,
+// time of the data point, reason, and pinned revision are used. The query is due for a retry
+// immediately. If the data point is already dirty, only its query, reason, and time of failure are
+// updated, so that its retries keep backing off. The time of failure is taken from the store's clock.
+func (s *Store) MarkQueryDirty(ctx context.Context, q DirtyQuery) error {
+	now := s.now().UTC()
+	return s.Exec(ctx, sqlf.Sprintf(
+		markQueryDirtyFmtstr,
+		q.SeriesID,       // series_id
+		q.Query,          // query
+		q.ForTime.UTC(),  // for_time
+		q.Reason,         // reason
+		now,              // dirty_at
+		now,              // next_attempt_at
+		q.PinnedRepo,     // pinned_repo
+		q.PinnedRevision, // pinned_revision
+	))
+}
+
+const markQueryDirtyFmtstr = `
+-- source: enterprise/internal/insights/store/dirty_queries.go:MarkQueryDirty
+INSERT INTO insight_dirty_queries(series_id, query, for_time, reason, dirty_at, next_attempt_at, pinned_repo, pinned_revision)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT (series_id, for_time) DO UPDATE SET
+	query = EXCLUDED.query,
+	reason = EXCLUDED.reason,
+	dirty_at = EXCLUDED.dirty_at,
+	pinned_repo = EXCLUDED.pinned_repo,
+	pinned_revision = EXCLUDED.pinned_revision
+`
+
+// ResolveDirtyQuery forgets the dirty query for the data point of the given series at the given
+// time, if any, because the data point was recorded.
+func (s *Store) ResolveDirtyQuery(ctx context.Context, seriesID string, forTime time.Time) error {
+	return s.Exec(ctx, sqlf.Sprintf(resolveDirtyQueryFmtstr, seriesID, forTime.UTC()))
+}
+
+const resolveDirtyQueryFmtstr = `
+-- source: enterprise/internal/insights/store/dirty_queries.go:ResolveDirtyQuery
+DELETE FROM insight_dirty_queries WHERE series_id = %s AND for_time = %s
+`
+
+// DirtyQueriesToRetry returns up to limit dirty queries which are due for a retry according to the
+// store's clock and were retried fewer than maxAttempts times, the longest overdue first.
+func (s *Store) DirtyQueriesToRetry(ctx context.Context, maxAttempts, limit int) ([]DirtyQuery, error) {
+	var dirtyQueries []DirtyQuery
+	err := s.query(ctx, sqlf.Sprintf(dirtyQueriesToRetryFmtstr, s.now().UTC(), maxAttempts, limit), func(sc scanner) error {
+		var q DirtyQuery
+		if err := sc.Scan(
+			&q.ID,
+			&q.SeriesID,
+			&q.Query,
+			&q.ForTime,
+			&q.Reason,
+			&q.DirtyAt,
+			&q.Attempts,
+			&q.NextAttemptAt,
+			&q.PinnedRepo,
+			&q.PinnedRevision,
+		); err != nil {
+			return err
+		}
+		dirtyQueries = append(dirtyQueries, q)
+		return nil
+	})
+	return dirtyQueries, err
+}
+
+const dirtyQueriesToRetryFmtstr = `
+-- source: enterprise/internal/insights/store/dirty_queries.go:DirtyQueriesToRetry
+SELECT id, series_id, query, for_time, reason, dirty_at, attempts, next_attempt_at, pinned_repo, pinned_revision
+FROM insight_dirty_queries
+WHERE next_attempt_at <= %s AND attempts < %s
+ORDER BY next_attempt_at, id
+LIMIT %s
+`
+
+// RecordDirtyQueryAttempt records that the dirty query with the given ID was retried, and that it
+// is due for its next retry at the given time should the retry fail as well.
+func (s *Store) RecordDirtyQueryAttempt(ctx context.Context, id int, nextAttemptAt time.Time) error {
+	return s.Exec(ctx, sqlf.Sprintf(recordDirtyQueryAttemptFmtstr, nextAttemptAt.UTC(), id))
+}
+
+const recordDirtyQueryAttemptFmtstr = `
+-- source: enterprise/internal/insights/store/dirty_queries.go:RecordDirtyQueryAttempt
+UPDATE insight_dirty_queries SET attempts = attempts + 1, next_attempt_at = %s WHERE id = %s
+`
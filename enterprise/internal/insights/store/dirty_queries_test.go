@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestDirtyQueries(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	now := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	forTime := now.Add(-time.Hour)
+	repo, revision := "github.com/sourcegraph/sourcegraph", "deadbeef"
+	if err := store.MarkQueryDirty(ctx, DirtyQuery{SeriesID: "one", Query: "errorf", ForTime: forTime, Reason: "search timed out"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.MarkQueryDirty(ctx, DirtyQuery{SeriesID: "two", Query: "printf", ForTime: forTime, Reason: "repositories are cloning", PinnedRepo: &repo, PinnedRevision: &revision}); err != nil {
+		t.Fatal(err)
+	}
+
+	dirtyQueries, err := store.DirtyQueriesToRetry(ctx, 3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DirtyQuery{
+		{SeriesID: "one", Query: "errorf", ForTime: forTime, Reason: "search timed out", DirtyAt: now, NextAttemptAt: now},
+		{SeriesID: "two", Query: "printf", ForTime: forTime, Reason: "repositories are cloning", DirtyAt: now, NextAttemptAt: now, PinnedRepo: &repo, PinnedRevision: &revision},
+	}
+	if diff := cmp.Diff(want, dirtyQueries, cmpopts.IgnoreFields(DirtyQuery{}, "ID")); diff != "" {
+		t.Fatalf("unexpected dirty queries (-want +got):\n%s", diff)
+	}
+
+	// Retrying the first query postpones it, and failing again must not make it due immediately.
+	if err := store.RecordDirtyQueryAttempt(ctx, dirtyQueries[0].ID, now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.MarkQueryDirty(ctx, DirtyQuery{SeriesID: "one", Query: "errorf", ForTime: forTime, Reason: "search timed out again"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ResolveDirtyQuery(ctx, "two", forTime); err != nil {
+		t.Fatal(err)
+	}
+
+	dirtyQueries, err = store.DirtyQueriesToRetry(ctx, 3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirtyQueries) != 0 {
+		t.Fatalf("unexpected number of dirty queries. want=%d have=%d", 0, len(dirtyQueries))
+	}
+
+	now = now.Add(time.Hour)
+	dirtyQueries, err = store.DirtyQueriesToRetry(ctx, 3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []DirtyQuery{
+		{SeriesID: "one", Query: "errorf", ForTime: forTime, Reason: "search timed out again", DirtyAt: now.Add(-time.Hour), Attempts: 1, NextAttemptAt: now},
+	}
+	if diff := cmp.Diff(want, dirtyQueries, cmpopts.IgnoreFields(DirtyQuery{}, "ID")); diff != "" {
+		t.Fatalf("unexpected dirty queries (-want +got):\n%s", diff)
+	}
+
+	// Queries are no longer retried after the maximum number of attempts.
+	dirtyQueries, err = store.DirtyQueriesToRetry(ctx, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirtyQueries) != 0 {
+		t.Fatalf("unexpected number of dirty queries. want=%d have=%d", 0, len(dirtyQueries))
+	}
+}
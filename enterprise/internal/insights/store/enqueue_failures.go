@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+)
+
+// SeriesEnqueueFailure describes the failed attempts of the insight enqueuer to enqueue a series
+// since it was last enqueued.
+type SeriesEnqueueFailure struct {
+	SeriesID string
+
+	// Cause is the kind of failure of the most recent attempt, e.g. "invalid" for series whose
+	// definition is invalid. It is meant for grouping failures, and LastError describes them.
+	Cause     string
+	LastError string
+
+	FirstFailedAt       time.Time
+	LastFailedAt        time.Time
+	ConsecutiveFailures int
+}
+
+// RecordSeriesEnqueueFailure records a failed attempt to enqueue a series. The time of the attempt
+// is taken from the store's clock.
+func (s *Store) RecordSeriesEnqueueFailure(ctx context.Context, seriesID, cause string, err error) error {
+	now := s.now().UTC()
+	return s.Exec(ctx, sqlf.Sprintf(recordSeriesEnqueueFailureFmtstr, seriesID, cause, err.Error(), now, now))
+}
+
+const recordSeriesEnqueueFailureFmtstr = `
+-- source: enterprise/internal/insights/store/enqueue_failures.go:RecordSeriesEnqueueFailure
+INSERT INTO insight_series_enqueue_failures (series_id, cause, last_error, first_failed_at, last_failed_at)
+VALUES (%s, %s, %s, %s, %s)
+ON CONFLICT (series_id) DO UPDATE SET
+	cause = EXCLUDED.cause,
+	last_error = EXCLUDED.last_error,
+	last_failed_at = EXCLUDED.last_failed_at,
+	consecutive_failures = insight_series_enqueue_failures.consecutive_failures + 1
+`
+
+// ClearSeriesEnqueueFailures removes the failures of the given series once they are enqueued.
+func (s *Store) ClearSeriesEnqueueFailures(ctx context.Context, seriesIDs []string) error {
+	return s.Exec(ctx, sqlf.Sprintf(clearSeriesEnqueueFailuresFmtstr, pq.Array(seriesIDs)))
+}
+
+const clearSeriesEnqueueFailuresFmtstr = `
+-- source: enterprise/internal/insights/store/enqueue_failures.go:ClearSeriesEnqueueFailures
+DELETE FROM insight_series_enqueue_failures WHERE series_id = ANY(%s)
+`
+
+// SeriesEnqueueFailuresOpts contains query predicates for fetching series enqueue failures.
+type SeriesEnqueueFailuresOpts struct {
+	// SeriesIDs, if non-empty, restricts the failures to the failures of these series.
+	SeriesIDs []string
+}
+
+// SeriesEnqueueFailures returns the series that the insight enqueuer failed to enqueue in its most
+// recent attempt, ordered by series ID.
+func (s *Store) SeriesEnqueueFailures(ctx context.Context, opts SeriesEnqueueFailuresOpts) ([]SeriesEnqueueFailure, error) {
+	pred := sqlf.Sprintf("TRUE")
+	if len(opts.SeriesIDs) > 0 {
+		pred = sqlf.Sprintf("series_id = ANY(%s)", pq.Array(opts.SeriesIDs))
+	}
+	var failures []SeriesEnqueueFailure
+	err := s.query(ctx, sqlf.Sprintf(seriesEnqueueFailuresFmtstr, pred), func(sc scanner) error {
+		var f SeriesEnqueueFailure
+		if err := sc.Scan(&f.SeriesID, &f.Cause, &f.LastError, &f.FirstFailedAt, &f.LastFailedAt, &f.ConsecutiveFailures); err != nil {
+			return err
+		}
+		failures = append(failures, f)
+		return nil
+	})
+	return failures, err
+}
+
+const seriesEnqueueFailuresFmtstr = `
+-- source: enterprise/internal/insights/store/enqueue_failures.go:SeriesEnqueueFailures
+SELECT series_id, cause, last_error, first_failed_at, last_failed_at, consecutive_failures
+FROM insight_series_enqueue_failures
+WHERE %s
+ORDER BY series_id
+`
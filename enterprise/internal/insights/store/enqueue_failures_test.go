@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestSeriesEnqueueFailures(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	now := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	firstFailedAt := now
+	if err := store.RecordSeriesEnqueueFailure(ctx, "s:one", "enqueue", errors.New("connection refused")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordSeriesEnqueueFailure(ctx, "s:two", "invalid", errors.New("invalid search query")); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(10 * time.Minute)
+	if err := store.RecordSeriesEnqueueFailure(ctx, "s:one", "queue_full", errors.New("queue is full")); err != nil {
+		t.Fatal(err)
+	}
+
+	failures, err := store.SeriesEnqueueFailures(ctx, SeriesEnqueueFailuresOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SeriesEnqueueFailure{
+		{SeriesID: "s:one", Cause: "queue_full", LastError: "queue is full", FirstFailedAt: firstFailedAt, LastFailedAt: now, ConsecutiveFailures: 2},
+		{SeriesID: "s:two", Cause: "invalid", LastError: "invalid search query", FirstFailedAt: firstFailedAt, LastFailedAt: firstFailedAt, ConsecutiveFailures: 1},
+	}
+	if diff := cmp.Diff(want, failures); diff != "" {
+		t.Errorf("unexpected failures (-want +got):\n%s", diff)
+	}
+
+	if err := store.ClearSeriesEnqueueFailures(ctx, []string{"s:one"}); err != nil {
+		t.Fatal(err)
+	}
+	failures, err = store.SeriesEnqueueFailures(ctx, SeriesEnqueueFailuresOpts{SeriesIDs: []string{"s:one", "s:two"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want[1:], failures); diff != "" {
+		t.Errorf("unexpected failures after clearing (-want +got):\n%s", diff)
+	}
+}
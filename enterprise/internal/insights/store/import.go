@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// ImportSeriesPointArgs describes arguments for the ImportSeriesPoint method.
+type ImportSeriesPointArgs struct {
+	RecordSeriesPointArgs
+
+	// ConflictFrom and ConflictTo delimit the period [ConflictFrom, ConflictTo) in which data
+	// points of the same series and repository conflict with the imported data point, usually the
+	// recording interval of the series that contains it.
+	ConflictFrom, ConflictTo time.Time
+
+	// Replace indicates to delete the conflicting data points and record the imported data point
+	// instead. Otherwise, the imported data point is not recorded if there are conflicting data
+	// points.
+	Replace bool
+}
+
+// ImportSeriesPoint records a data point imported from outside of Sourcegraph, resolving conflicts
+// with the data points recorded already. It reports whether the data point was recorded.
+func (s *Store) ImportSeriesPoint(ctx context.Context, args ImportSeriesPointArgs) (imported bool, err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	conflictQuery := sqlf.Sprintf(conflictingSeriesPointsFmtstr, args.SeriesID, args.RepoID, args.ConflictFrom.UTC(), args.ConflictTo.UTC())
+	if args.Replace {
+		if err := tx.Exec(ctx, sqlf.Sprintf(deleteConflictingSeriesPointsFmtstr, conflictQuery)); err != nil {
+			return false, err
+		}
+	} else {
+		conflicts, _, err := basestore.ScanFirstInt(tx.Query(ctx, sqlf.Sprintf(countConflictingSeriesPointsFmtstr, conflictQuery)))
+		if err != nil {
+			return false, err
+		}
+		if conflicts > 0 {
+			return false, nil
+		}
+	}
+
+	if err := s.With(tx).RecordSeriesPoint(ctx, args.RecordSeriesPointArgs); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+const conflictingSeriesPointsFmtstr = `
+series_id = %s AND repo_id IS NOT DISTINCT FROM %s AND time >= %s AND time < %s
+`
+
+const countConflictingSeriesPointsFmtstr = `
+-- source: enterprise/internal/insights/store/import.go:ImportSeriesPoint
+SELECT COUNT(*) FROM series_points WHERE %s
+`
+
+const deleteConflictingSeriesPointsFmtstr = `
+-- source: enterprise/internal/insights/store/import.go:ImportSeriesPoint
+DELETE FROM series_points WHERE %s
+`
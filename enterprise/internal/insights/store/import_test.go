@@ -0,0 +1,83 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+)
+
+func TestImportSeriesPoint(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, timeutil.Now)
+
+	optionalString := func(v string) *string { return &v }
+	optionalRepoID := func(v api.RepoID) *api.RepoID { return &v }
+
+	day := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	if err := store.RecordSeriesPoint(ctx, RecordSeriesPointArgs{
+		SeriesID: "s",
+		Point:    SeriesPoint{Time: day.Add(time.Hour), Value: 1},
+		RepoName: optionalString("repo1"),
+		RepoID:   optionalRepoID(1),
+	}); err != nil {
+		t.Fatalf("unexpected error recording series point: %s", err)
+	}
+
+	importPoint := func(repoID api.RepoID, replace bool) bool {
+		args := ImportSeriesPointArgs{
+			RecordSeriesPointArgs: RecordSeriesPointArgs{SeriesID: "s", Point: SeriesPoint{Time: day.Add(3 * time.Hour), Value: 5}},
+			ConflictFrom:          day,
+			ConflictTo:            day.Add(24 * time.Hour),
+			Replace:               replace,
+		}
+		if repoID != 0 {
+			args.RepoName = optionalString("repo2")
+			args.RepoID = optionalRepoID(repoID)
+		}
+		imported, err := store.ImportSeriesPoint(ctx, args)
+		if err != nil {
+			t.Fatalf("unexpected error importing series point: %s", err)
+		}
+		return imported
+	}
+	countData := func(repoID api.RepoID) int {
+		from, to, seriesID := day, day.Add(24*time.Hour), "s"
+		count, err := store.CountData(ctx, CountDataOpts{From: &from, To: &to, SeriesID: &seriesID, RepoID: &repoID})
+		if err != nil {
+			t.Fatalf("unexpected error counting data: %s", err)
+		}
+		return count
+	}
+
+	// The data point of repo1 conflicts with the recorded one.
+	if importPoint(1, false) {
+		t.Errorf("expected the conflicting data point to be skipped")
+	}
+	if !importPoint(1, true) {
+		t.Errorf("expected the conflicting data point to replace the recorded one")
+	}
+	if count := countData(1); count != 1 {
+		t.Errorf("unexpected number of data points after replacing. want=%d have=%d", 1, count)
+	}
+
+	// Data points of other repositories, or without repository, do not conflict.
+	if !importPoint(2, false) {
+		t.Errorf("expected the data point of another repository to be imported")
+	}
+	if !importPoint(0, false) {
+		t.Errorf("expected the data point without repository to be imported")
+	}
+}
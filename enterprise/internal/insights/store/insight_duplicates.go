@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// GetInsightDuplicates returns the duplicate insights found by the most recent validation pass over
+// the discovered insights, ordered by group with the canonical insight of each group first.
+func (s *InsightStore) GetInsightDuplicates(ctx context.Context) ([]types.InsightDuplicate, error) {
+	return scanInsightDuplicates(s.Query(ctx, sqlf.Sprintf(getInsightDuplicatesFmtstr)))
+}
+
+const getInsightDuplicatesFmtstr = `
+-- source: enterprise/internal/insights/store/insight_duplicates.go:GetInsightDuplicates
+SELECT group_id, insight_id, title, user_id, org_id, canonical, detected_at
+FROM insight_duplicates
+ORDER BY group_id, canonical DESC, id
+`
+
+func scanInsightDuplicates(rows *sql.Rows, queryErr error) (_ []types.InsightDuplicate, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	results := make([]types.InsightDuplicate, 0)
+	for rows.Next() {
+		var temp types.InsightDuplicate
+		if err := rows.Scan(
+			&temp.GroupID,
+			&temp.InsightID,
+			&temp.Title,
+			&temp.UserID,
+			&temp.OrgID,
+			&temp.Canonical,
+			&temp.DetectedAt,
+		); err != nil {
+			return []types.InsightDuplicate{}, err
+		}
+		results = append(results, temp)
+	}
+	return results, nil
+}
+
+// ReplaceInsightDuplicates replaces the stored duplicate insights with the given duplicates, found
+// by a validation pass over the discovered insights. All duplicates are stamped with the current
+// time.
+func (s *InsightStore) ReplaceInsightDuplicates(ctx context.Context, duplicates []types.InsightDuplicate) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Store.Done(err) }()
+
+	if err := tx.Exec(ctx, sqlf.Sprintf(deleteInsightDuplicatesFmtstr)); err != nil {
+		return err
+	}
+	now := s.Now().UTC()
+	for _, duplicate := range duplicates {
+		if err := tx.Exec(ctx, sqlf.Sprintf(
+			insertInsightDuplicateFmtstr,
+			duplicate.GroupID,
+			duplicate.InsightID,
+			duplicate.Title,
+			duplicate.UserID,
+			duplicate.OrgID,
+			duplicate.Canonical,
+			now,
+		)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const deleteInsightDuplicatesFmtstr = `
+-- source: enterprise/internal/insights/store/insight_duplicates.go:ReplaceInsightDuplicates
+DELETE FROM insight_duplicates
+`
+
+const insertInsightDuplicateFmtstr = `
+-- source: enterprise/internal/insights/store/insight_duplicates.go:ReplaceInsightDuplicates
+INSERT INTO insight_duplicates (group_id, insight_id, title, user_id, org_id, canonical, detected_at)
+VALUES (%s, %s, %s, %s, %s, %s, %s)
+`
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+)
+
+func TestInsightDuplicates(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	now := time.Date(2021, 8, 1, 12, 0, 0, 0, time.UTC)
+	store := NewInsightStore(timescale)
+	store.Now = func() time.Time { return now }
+
+	userID, orgID := int32(1), int32(2)
+	if err := store.ReplaceInsightDuplicates(ctx, []types.InsightDuplicate{
+		{GroupID: "A", InsightID: "insight-1", Title: "TODOs", Canonical: true},
+		{GroupID: "A", InsightID: "insight-1", Title: "TODOs", UserID: &userID},
+	}); err != nil {
+		t.Fatalf("unexpected error replacing duplicates: %s", err)
+	}
+	if err := store.ReplaceInsightDuplicates(ctx, []types.InsightDuplicate{
+		{GroupID: "B", InsightID: "insight-3", Title: "Errors", UserID: &userID},
+		{GroupID: "B", InsightID: "insight-2", Title: "Errors", OrgID: &orgID, Canonical: true},
+	}); err != nil {
+		t.Fatalf("unexpected error replacing duplicates: %s", err)
+	}
+
+	duplicates, err := store.GetInsightDuplicates(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error getting duplicates: %s", err)
+	}
+	want := []types.InsightDuplicate{
+		{GroupID: "B", InsightID: "insight-2", Title: "Errors", OrgID: &orgID, Canonical: true, DetectedAt: now},
+		{GroupID: "B", InsightID: "insight-3", Title: "Errors", UserID: &userID, DetectedAt: now},
+	}
+	if diff := cmp.Diff(want, duplicates); diff != "" {
+		t.Errorf("unexpected duplicates (-want +got):\n%s", diff)
+	}
+}
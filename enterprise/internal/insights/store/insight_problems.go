@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// InsightProblemQueryArgs contains query predicates for fetching insight problems.
+type InsightProblemQueryArgs struct {
+	// InsightIDs, if non-empty, restricts the problems to the problems of these insights.
+	InsightIDs []string
+}
+
+// GetInsightProblems returns the problems found by the most recent validation pass over the
+// discovered insights, ordered by insight.
+func (s *InsightStore) GetInsightProblems(ctx context.Context, args InsightProblemQueryArgs) ([]types.InsightProblem, error) {
+	pred := sqlf.Sprintf("TRUE")
+	if len(args.InsightIDs) > 0 {
+		pred = sqlf.Sprintf("insight_id = ANY(%s)", pq.Array(args.InsightIDs))
+	}
+	return scanInsightProblems(s.Query(ctx, sqlf.Sprintf(getInsightProblemsFmtstr, pred)))
+}
+
+const getInsightProblemsFmtstr = `
+-- source: enterprise/internal/insights/store/insight_problems.go:GetInsightProblems
+SELECT insight_id, COALESCE(series_id, ''), COALESCE(series_label, ''), user_id, org_id, problem, detected_at
+FROM insight_problems
+WHERE %s
+ORDER BY insight_id, id
+`
+
+func scanInsightProblems(rows *sql.Rows, queryErr error) (_ []types.InsightProblem, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	results := make([]types.InsightProblem, 0)
+	for rows.Next() {
+		var temp types.InsightProblem
+		if err := rows.Scan(
+			&temp.InsightID,
+			&temp.SeriesID,
+			&temp.SeriesLabel,
+			&temp.UserID,
+			&temp.OrgID,
+			&temp.Problem,
+			&temp.DetectedAt,
+		); err != nil {
+			return []types.InsightProblem{}, err
+		}
+		results = append(results, temp)
+	}
+	return results, nil
+}
+
+// ReplaceInsightProblems replaces the problems of all insights with the given problems, found by a
+// validation pass over the discovered insights. All problems are stamped with the current time.
+func (s *InsightStore) ReplaceInsightProblems(ctx context.Context, problems []types.InsightProblem) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Store.Done(err) }()
+
+	if err := tx.Exec(ctx, sqlf.Sprintf(deleteInsightProblemsFmtstr)); err != nil {
+		return err
+	}
+	now := s.Now().UTC()
+	for _, problem := range problems {
+		if err := tx.Exec(ctx, sqlf.Sprintf(
+			insertInsightProblemFmtstr,
+			problem.InsightID,
+			nullString(problem.SeriesID),
+			nullString(problem.SeriesLabel),
+			problem.UserID,
+			problem.OrgID,
+			problem.Problem,
+			now,
+		)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const deleteInsightProblemsFmtstr = `
+-- source: enterprise/internal/insights/store/insight_problems.go:ReplaceInsightProblems
+DELETE FROM insight_problems
+`
+
+const insertInsightProblemFmtstr = `
+-- source: enterprise/internal/insights/store/insight_problems.go:ReplaceInsightProblems
+INSERT INTO insight_problems (insight_id, series_id, series_label, user_id, org_id, problem, detected_at)
+VALUES (%s, %s, %s, %s, %s, %s, %s)
+`
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
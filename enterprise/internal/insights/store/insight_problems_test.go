@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
+)
+
+func TestInsightProblems(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	now := time.Date(2021, 8, 1, 12, 0, 0, 0, time.UTC)
+	store := NewInsightStore(timescale)
+	store.Now = func() time.Time { return now }
+
+	userID := int32(1)
+	if err := store.ReplaceInsightProblems(ctx, []types.InsightProblem{
+		{InsightID: "insight-1", Problem: "insight has no series"},
+	}); err != nil {
+		t.Fatalf("unexpected error replacing problems: %s", err)
+	}
+	if err := store.ReplaceInsightProblems(ctx, []types.InsightProblem{
+		{InsightID: "insight-2", SeriesID: "s:1", SeriesLabel: "todos", UserID: &userID, Problem: "invalid search query"},
+		{InsightID: "insight-3", Problem: "insight has no series"},
+	}); err != nil {
+		t.Fatalf("unexpected error replacing problems: %s", err)
+	}
+
+	for _, testCase := range []struct {
+		name string
+		args InsightProblemQueryArgs
+		want []types.InsightProblem
+	}{
+		{"all", InsightProblemQueryArgs{}, []types.InsightProblem{
+			{InsightID: "insight-2", SeriesID: "s:1", SeriesLabel: "todos", UserID: &userID, Problem: "invalid search query", DetectedAt: now},
+			{InsightID: "insight-3", Problem: "insight has no series", DetectedAt: now},
+		}},
+		{"by insight ID", InsightProblemQueryArgs{InsightIDs: []string{"insight-1", "insight-3"}}, []types.InsightProblem{
+			{InsightID: "insight-3", Problem: "insight has no series", DetectedAt: now},
+		}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			problems, err := store.GetInsightProblems(ctx, testCase.args)
+			if err != nil {
+				t.Fatalf("unexpected error getting problems: %s", err)
+			}
+			if diff := cmp.Diff(testCase.want, problems); diff != "" {
+				t.Errorf("unexpected problems (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/insights/types"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
@@ -45,6 +46,9 @@ func (s *InsightStore) Transact(ctx context.Context) (*InsightStore, error) {
 type InsightQueryArgs struct {
 	UniqueIDs []string
 	UniqueID  string
+
+	// DashboardID, if non-zero, restricts the insights to the insights of this dashboard.
+	DashboardID int
 }
 
 // Get returns all matching viewable insight series.
@@ -61,6 +65,9 @@ func (s *InsightStore) Get(ctx context.Context, args InsightQueryArgs) ([]types.
 	if len(args.UniqueID) > 0 {
 		preds = append(preds, sqlf.Sprintf("iv.unique_id = %s", args.UniqueID))
 	}
+	if args.DashboardID != 0 {
+		preds = append(preds, sqlf.Sprintf(insightOnDashboardSql, args.DashboardID))
+	}
 
 	if len(preds) == 0 {
 		preds = append(preds, sqlf.Sprintf("%s", "TRUE"))
@@ -87,11 +94,19 @@ func scanInsightViewSeries(rows *sql.Rows, queryErr error) (_ []types.InsightVie
 			&temp.Stroke,
 			&temp.SeriesID,
 			&temp.Query,
+			&temp.Webhook,
+			&temp.Expression,
 			&temp.CreatedAt,
 			&temp.OldestHistoricalAt,
 			&temp.LastRecordedAt,
 			&temp.NextRecordingAfter,
 			&temp.RecordingIntervalDays,
+			&temp.RecordingInterval,
+			&temp.BusinessDaysOnly,
+			&temp.UserID,
+			&temp.OrgID,
+			pq.Array(&temp.Repositories),
+			&temp.RepositoryPattern,
 		); err != nil {
 			return []types.InsightViewSeries{}, err
 		}
@@ -100,7 +115,8 @@ func scanInsightViewSeries(rows *sql.Rows, queryErr error) (_ []types.InsightVie
 	return results, nil
 }
 
-// AttachSeriesToView will associate a given insight data series with a given insight view.
+// AttachSeriesToView will associate a given insight data series with a given insight view. The series gains a
+// reference, and is restored if it was deleted for no longer being referenced.
 func (s *InsightStore) AttachSeriesToView(ctx context.Context,
 	series types.InsightSeries,
 	view types.InsightView,
@@ -108,7 +124,48 @@ func (s *InsightStore) AttachSeriesToView(ctx context.Context,
 	if series.ID == 0 || view.ID == 0 {
 		return errors.New("input series or view not found")
 	}
-	return s.Exec(ctx, sqlf.Sprintf(attachSeriesToViewSql, series.ID, view.ID, metadata.Label, metadata.Stroke))
+	if metadata.RecordingInterval == "" {
+		metadata.RecordingInterval = defaultRecordingInterval
+	}
+	return s.Exec(ctx, sqlf.Sprintf(attachSeriesToViewSql, series.ID, view.ID, metadata.Label, metadata.Stroke, metadata.RecordingInterval, metadata.BusinessDaysOnly))
+}
+
+// defaultRecordingInterval is the recording interval of series attached to a view without one.
+const defaultRecordingInterval = "daily"
+
+// DeleteView will delete the insight view with the given unique identifier along with its associations to data
+// series. The data series lose a reference, as they may be shared with other views. Data series that are no longer
+// referenced by any view are soft-deleted, so that they stop being recorded; their data is retained until they are
+// purged (see PurgeUnreferencedSeries), in case another view starts using them again.
+func (s *InsightStore) DeleteView(ctx context.Context, uniqueID string) error {
+	return s.Exec(ctx, sqlf.Sprintf(deleteViewSql, uniqueID, s.Now()))
+}
+
+// GetUnreferencedSeriesIDs returns the series IDs of the data series that were soft-deleted for no longer being
+// referenced by any view, and have not been purged yet.
+func (s *InsightStore) GetUnreferencedSeriesIDs(ctx context.Context) ([]string, error) {
+	return basestore.ScanStrings(s.Query(ctx, sqlf.Sprintf(getUnreferencedSeriesIDsSql)))
+}
+
+// PurgeUnreferencedSeries permanently deletes the data series that were soft-deleted before the given time for no
+// longer being referenced by any view, along with all their data. It returns the number of purged series.
+func (s *InsightStore) PurgeUnreferencedSeries(ctx context.Context, before time.Time) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, sqlf.Sprintf(purgeUnreferencedSeriesSql, before)))
+	return count, err
+}
+
+// GetDataSeriesArgs contains query predicates for fetching insight data series.
+type GetDataSeriesArgs struct {
+	SeriesID string
+}
+
+// GetDataSeries returns all matching insight data series that have not been deleted.
+func (s *InsightStore) GetDataSeries(ctx context.Context, args GetDataSeriesArgs) ([]types.InsightSeries, error) {
+	preds := []*sqlf.Query{sqlf.Sprintf("deleted_at IS NULL")}
+	if args.SeriesID != "" {
+		preds = append(preds, sqlf.Sprintf("series_id = %s", args.SeriesID))
+	}
+	return scanInsightSeries(s.Query(ctx, sqlf.Sprintf(getDataSeriesSql, sqlf.Join(preds, "\n AND "))))
 }
 
 // CreateView will create a new insight view with no associated data series. This view must have a unique identifier.
@@ -117,6 +174,8 @@ func (s *InsightStore) CreateView(ctx context.Context, view types.InsightView) (
 		view.Title,
 		view.Description,
 		view.UniqueID,
+		view.UserID,
+		view.OrgID,
 	))
 	if row.Err() != nil {
 		return types.InsightView{}, row.Err()
@@ -132,6 +191,35 @@ func (s *InsightStore) CreateView(ctx context.Context, view types.InsightView) (
 
 // CreateSeries will create a new insight data series. This series must be uniquely identified by the series ID.
 func (s *InsightStore) CreateSeries(ctx context.Context, series types.InsightSeries) (types.InsightSeries, error) {
+	series = s.withSeriesDefaults(series)
+	row := s.QueryRow(ctx, sqlf.Sprintf(createInsightSeriesSql, s.seriesValues(series)))
+	var id int
+	err := row.Scan(&id)
+	if err != nil {
+		return types.InsightSeries{}, err
+	}
+	series.ID = id
+	return series, nil
+}
+
+// GetOrCreateSeries returns the insight data series with the series ID of the given series, creating it from the
+// given series if it does not exist. Series IDs identify series by their data, so identical series share a single
+// data series and its data. Series that were soft-deleted but not purged yet are returned as well, and are
+// restored once attached to a view.
+func (s *InsightStore) GetOrCreateSeries(ctx context.Context, series types.InsightSeries) (types.InsightSeries, error) {
+	series = s.withSeriesDefaults(series)
+	results, err := scanInsightSeries(s.Query(ctx, sqlf.Sprintf(getOrCreateInsightSeriesSql, s.seriesValues(series), series.SeriesID)))
+	if err != nil {
+		return types.InsightSeries{}, err
+	}
+	if len(results) == 0 {
+		return types.InsightSeries{}, errors.Errorf("series %q was neither found nor created", series.SeriesID)
+	}
+	return results[0], nil
+}
+
+// withSeriesDefaults returns the given series with the default values of the fields that are not set.
+func (s *InsightStore) withSeriesDefaults(series types.InsightSeries) types.InsightSeries {
 	if series.CreatedAt.IsZero() {
 		series.CreatedAt = s.Now()
 	}
@@ -142,51 +230,244 @@ func (s *InsightStore) CreateSeries(ctx context.Context, series types.InsightSer
 		// TODO(insights): this value should probably somewhere more discoverable / obvious than here
 		series.OldestHistoricalAt = s.Now().Add(-time.Hour * 24 * 365)
 	}
-	row := s.QueryRow(ctx, sqlf.Sprintf(createInsightSeriesSql,
+	return series
+}
+
+// seriesValues returns the values of the columns set when creating the given series.
+func (s *InsightStore) seriesValues(series types.InsightSeries) *sqlf.Query {
+	return sqlf.Sprintf("%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s",
 		series.SeriesID,
 		series.Query,
+		series.Webhook,
+		series.Expression,
 		series.CreatedAt,
 		series.OldestHistoricalAt,
 		series.LastRecordedAt,
 		series.NextRecordingAfter,
 		series.RecordingIntervalDays,
-	))
-	var id int
-	err := row.Scan(&id)
-	if err != nil {
+		pq.Array(series.Repositories),
+		series.RepositoryPattern,
+	)
+}
+
+// GetSeriesToBackfill returns the insight data series that have not been enqueued for backfilling
+// yet, oldest first. Deleted series are never backfilled.
+func (s *InsightStore) GetSeriesToBackfill(ctx context.Context) ([]types.InsightSeries, error) {
+	return scanInsightSeries(s.Query(ctx, sqlf.Sprintf(getSeriesToBackfillSql)))
+}
+
+// StampBackfill records that the historical data of the given insight data series has been
+// enqueued for backfilling.
+func (s *InsightStore) StampBackfill(ctx context.Context, series types.InsightSeries) (types.InsightSeries, error) {
+	now := s.Now()
+	if err := s.Exec(ctx, sqlf.Sprintf(stampBackfillSql, now, series.ID)); err != nil {
 		return types.InsightSeries{}, err
 	}
-	series.ID = id
+	series.BackfillQueuedAt = &now
 	return series, nil
 }
 
+// BusinessDaysOnlySeries returns the IDs of the given insight data series that are recorded on
+// business days only, which is the case if every view of the series records it on business days only.
+func (s *InsightStore) BusinessDaysOnlySeries(ctx context.Context, seriesIDs []string) (_ map[string]bool, err error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(businessDaysOnlySeriesSql, pq.Array(seriesIDs)))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	results := map[string]bool{}
+	for rows.Next() {
+		var seriesID string
+		if err := rows.Scan(&seriesID); err != nil {
+			return nil, err
+		}
+		results[seriesID] = true
+	}
+	return results, nil
+}
+
+func scanInsightSeries(rows *sql.Rows, queryErr error) (_ []types.InsightSeries, err error) {
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	results := make([]types.InsightSeries, 0)
+	for rows.Next() {
+		var temp types.InsightSeries
+		if err := rows.Scan(
+			&temp.ID,
+			&temp.SeriesID,
+			&temp.Query,
+			&temp.Webhook,
+			&temp.Expression,
+			&temp.CreatedAt,
+			&temp.OldestHistoricalAt,
+			&temp.LastRecordedAt,
+			&temp.NextRecordingAfter,
+			&temp.RecordingIntervalDays,
+			&temp.BackfillQueuedAt,
+			pq.Array(&temp.Repositories),
+			&temp.RepositoryPattern,
+			&temp.ReferenceCount,
+		); err != nil {
+			return []types.InsightSeries{}, err
+		}
+		results = append(results, temp)
+	}
+	return results, nil
+}
+
 const attachSeriesToViewSql = `
 -- source: enterprise/internal/insights/store/insight_store.go:AttachSeriesToView
-INSERT INTO insight_view_series (insight_series_id, insight_view_id, label, stroke)
-VALUES (%s, %s, %s, %s);
+WITH attached AS (
+	INSERT INTO insight_view_series (insight_series_id, insight_view_id, label, stroke, recording_interval, business_days_only)
+	VALUES (%s, %s, %s, %s, %s, %s)
+	RETURNING insight_series_id
+)
+UPDATE insight_series
+SET reference_count = reference_count + 1, deleted_at = NULL
+WHERE id IN (SELECT insight_series_id FROM attached);
+`
+
+const deleteViewSql = `
+-- source: enterprise/internal/insights/store/insight_store.go:DeleteView
+WITH deleted_view AS (
+	SELECT id FROM insight_view WHERE unique_id = %s
+),
+detached AS (
+	DELETE FROM insight_view_series
+	WHERE insight_view_id IN (SELECT id FROM deleted_view)
+	RETURNING insight_series_id
+),
+released AS (
+	UPDATE insight_series
+	SET reference_count = reference_count - 1,
+		deleted_at = CASE WHEN reference_count <= 1 THEN %s ELSE deleted_at END
+	WHERE id IN (SELECT insight_series_id FROM detached)
+)
+DELETE FROM insight_view WHERE id IN (SELECT id FROM deleted_view);
+`
+
+const getUnreferencedSeriesIDsSql = `
+-- source: enterprise/internal/insights/store/insight_store.go:GetUnreferencedSeriesIDs
+SELECT series_id FROM insight_series WHERE reference_count = 0 AND deleted_at IS NOT NULL ORDER BY series_id
+`
+
+const purgeUnreferencedSeriesSql = `
+-- source: enterprise/internal/insights/store/insight_store.go:PurgeUnreferencedSeries
+WITH purged AS (
+	DELETE FROM insight_series
+	WHERE reference_count = 0 AND deleted_at < %s
+	RETURNING series_id
+),
+points AS (
+	DELETE FROM series_points WHERE series_id IN (SELECT series_id FROM purged)
+),
+dirty_queries AS (
+	DELETE FROM insight_dirty_queries WHERE series_id IN (SELECT series_id FROM purged)
+),
+webhook_deliveries AS (
+	DELETE FROM insight_webhook_deliveries WHERE series_id IN (SELECT series_id FROM purged)
+),
+runs AS (
+	DELETE FROM insight_series_runs WHERE series_id IN (SELECT series_id FROM purged)
+),
+alert_rules AS (
+	DELETE FROM insight_series_alert_rules WHERE series_id IN (SELECT series_id FROM purged)
+),
+backfill_checkpoints AS (
+	DELETE FROM insight_series_backfill_checkpoints WHERE series_id IN (SELECT series_id FROM purged)
+),
+enqueue_failures AS (
+	DELETE FROM insight_series_enqueue_failures WHERE series_id IN (SELECT series_id FROM purged)
+)
+SELECT count(*) FROM purged
 `
 
 const createInsightViewSql = `
 -- source: enterprise/internal/insights/store/insight_store.go:CreateView
-INSERT INTO insight_view (title, description, unique_id)
-VALUES (%s, %s, %s)
+INSERT INTO insight_view (title, description, unique_id, user_id, org_id)
+VALUES (%s, %s, %s, %s, %s)
 returning id;`
 
 const createInsightSeriesSql = `
 -- source: enterprise/internal/insights/store/insight_store.go:CreateSeries
-INSERT INTO insight_series (series_id, query, created_at, oldest_historical_at, last_recorded_at,
-                            next_recording_after, recording_interval_days)
-VALUES (%s, %s, %s, %s, %s, %s, %s)
+INSERT INTO insight_series (series_id, query, webhook, expression, created_at, oldest_historical_at, last_recorded_at,
+                            next_recording_after, recording_interval_days, repositories, repository_pattern)
+VALUES (%s)
 RETURNING id;`
 
+const getOrCreateInsightSeriesSql = `
+-- source: enterprise/internal/insights/store/insight_store.go:GetOrCreateSeries
+WITH inserted AS (
+	INSERT INTO insight_series (series_id, query, webhook, expression, created_at, oldest_historical_at, last_recorded_at,
+	                            next_recording_after, recording_interval_days, repositories, repository_pattern)
+	VALUES (%s)
+	ON CONFLICT (series_id) DO NOTHING
+	RETURNING id, series_id, query, webhook, expression, created_at, oldest_historical_at, last_recorded_at,
+	next_recording_after, recording_interval_days, backfill_queued_at, repositories, repository_pattern, reference_count
+)
+SELECT * FROM inserted
+UNION ALL
+SELECT id, series_id, query, webhook, expression, created_at, oldest_historical_at, last_recorded_at,
+next_recording_after, recording_interval_days, backfill_queued_at, repositories, repository_pattern, reference_count
+FROM insight_series
+WHERE series_id = %s
+`
+
 const getInsightByViewSql = `
 -- source: enterprise/internal/insights/store/insight_store.go:Get
 SELECT iv.unique_id, iv.title, iv.description, ivs.label, ivs.stroke,
-i.series_id, i.query, i.created_at, i.oldest_historical_at, i.last_recorded_at,
-i.next_recording_after, i.recording_interval_days
+i.series_id, i.query, i.webhook, i.expression, i.created_at, i.oldest_historical_at, i.last_recorded_at,
+i.next_recording_after, i.recording_interval_days, ivs.recording_interval, ivs.business_days_only, iv.user_id, iv.org_id,
+i.repositories, i.repository_pattern
 FROM insight_view iv
          JOIN insight_view_series ivs ON iv.id = ivs.insight_view_id
          JOIN insight_series i ON ivs.insight_series_id = i.id
-WHERE %s
+WHERE i.deleted_at IS NULL AND %s
 ORDER BY iv.unique_id, i.series_id
 `
+
+const insightOnDashboardSql = `
+iv.unique_id IN (
+	SELECT div.insight_view_unique_id FROM dashboard_insight_view div
+	JOIN dashboard d ON d.id = div.dashboard_id
+	WHERE d.id = %s AND d.deleted_at IS NULL
+)`
+
+const getDataSeriesSql = `
+-- source: enterprise/internal/insights/store/insight_store.go:GetDataSeries
+SELECT id, series_id, query, webhook, expression, created_at, oldest_historical_at, last_recorded_at,
+next_recording_after, recording_interval_days, backfill_queued_at, repositories, repository_pattern, reference_count
+FROM insight_series
+WHERE %s
+ORDER BY series_id
+`
+
+const getSeriesToBackfillSql = `
+-- source: enterprise/internal/insights/store/insight_store.go:GetSeriesToBackfill
+SELECT id, series_id, query, webhook, expression, created_at, oldest_historical_at, last_recorded_at,
+next_recording_after, recording_interval_days, backfill_queued_at, repositories, repository_pattern, reference_count
+FROM insight_series
+WHERE backfill_queued_at IS NULL AND deleted_at IS NULL
+ORDER BY created_at, id
+`
+
+const businessDaysOnlySeriesSql = `
+-- source: enterprise/internal/insights/store/insight_store.go:BusinessDaysOnlySeries
+SELECT i.series_id
+FROM insight_series i
+JOIN insight_view_series ivs ON ivs.insight_series_id = i.id
+WHERE i.series_id = ANY(%s)
+GROUP BY i.series_id
+HAVING bool_and(ivs.business_days_only)
+`
+
+const stampBackfillSql = `
+-- source: enterprise/internal/insights/store/insight_store.go:StampBackfill
+UPDATE insight_series
+SET backfill_queued_at = %s
+WHERE id = %s
+`
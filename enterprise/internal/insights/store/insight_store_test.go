@@ -63,6 +63,7 @@ func TestGet(t *testing.T) {
 				RecordingIntervalDays: 5,
 				Label:                 "label1",
 				Stroke:                "color1",
+				RecordingInterval:     "daily",
 			},
 			{
 				UniqueID:              "unique-1",
@@ -77,6 +78,7 @@ func TestGet(t *testing.T) {
 				RecordingIntervalDays: 6,
 				Label:                 "label2",
 				Stroke:                "color2",
+				RecordingInterval:     "daily",
 			},
 			{
 				UniqueID:              "unique-2",
@@ -91,6 +93,7 @@ func TestGet(t *testing.T) {
 				RecordingIntervalDays: 6,
 				Label:                 "second-label-2",
 				Stroke:                "second-color-2",
+				RecordingInterval:     "daily",
 			},
 		}
 
@@ -121,6 +124,7 @@ func TestGet(t *testing.T) {
 				RecordingIntervalDays: 5,
 				Label:                 "label1",
 				Stroke:                "color1",
+				RecordingInterval:     "daily",
 			},
 			{
 				UniqueID:              "unique-1",
@@ -135,6 +139,7 @@ func TestGet(t *testing.T) {
 				RecordingIntervalDays: 6,
 				Label:                 "label2",
 				Stroke:                "color2",
+				RecordingInterval:     "daily",
 			},
 		}
 
@@ -164,6 +169,7 @@ func TestGet(t *testing.T) {
 				RecordingIntervalDays: 5,
 				Label:                 "label1",
 				Stroke:                "color1",
+				RecordingInterval:     "daily",
 			},
 			{
 				UniqueID:              "unique-1",
@@ -178,6 +184,7 @@ func TestGet(t *testing.T) {
 				RecordingIntervalDays: 6,
 				Label:                 "label2",
 				Stroke:                "color2",
+				RecordingInterval:     "daily",
 			},
 		}
 
@@ -185,6 +192,35 @@ func TestGet(t *testing.T) {
 			t.Errorf("unexpected insight view series want/got: %s", diff)
 		}
 	})
+
+	t.Run("test get by dashboard", func(t *testing.T) {
+		store := NewInsightStore(timescale)
+		dashboardStore := NewDashboardStore(timescale)
+
+		dashboardID, err := dashboardStore.CreateDashboard(ctx, CreateDashboardArgs{Title: "dashboard", InsightIDs: []string{"unique-2"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := store.Get(ctx, InsightQueryArgs{DashboardID: dashboardID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0].UniqueID != "unique-2" {
+			t.Errorf("unexpected insight view series of dashboard: %v", got)
+		}
+
+		// The insights of deleted dashboards are not returned.
+		if err := dashboardStore.DeleteDashboard(ctx, dashboardID); err != nil {
+			t.Fatal(err)
+		}
+		got, err = store.Get(ctx, InsightQueryArgs{DashboardID: dashboardID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 0 {
+			t.Errorf("unexpected insight view series of deleted dashboard: %v", got)
+		}
+	})
 }
 
 func TestCreateSeries(t *testing.T) {
@@ -295,18 +331,22 @@ func TestAttachSeriesView(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
+		orgID := int32(1)
 		view := types.InsightView{
 			Title:       "my view",
 			Description: "my view description",
 			UniqueID:    "1234567",
+			OrgID:       &orgID,
 		}
 		view, err = store.CreateView(ctx, view)
 		if err != nil {
 			t.Fatal(err)
 		}
 		metadata := types.InsightViewSeriesMetadata{
-			Label:  "my label",
-			Stroke: "my stroke",
+			Label:             "my label",
+			Stroke:            "my stroke",
+			RecordingInterval: "weekly",
+			BusinessDaysOnly:  true,
 		}
 		err = store.AttachSeriesToView(ctx, series, view, metadata)
 		if err != nil {
@@ -330,6 +370,9 @@ func TestAttachSeriesView(t *testing.T) {
 			RecordingIntervalDays: series.RecordingIntervalDays,
 			Label:                 "my label",
 			Stroke:                "my stroke",
+			RecordingInterval:     "weekly",
+			BusinessDaysOnly:      true,
+			OrgID:                 &orgID,
 		}}
 
 		if diff := cmp.Diff(want, got); diff != "" {
@@ -337,3 +380,282 @@ func TestAttachSeriesView(t *testing.T) {
 		}
 	})
 }
+
+func TestSeriesBackfill(t *testing.T) {
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	now := time.Now().Truncate(time.Microsecond).Round(0)
+
+	store := NewInsightStore(timescale)
+	store.Now = func() time.Time {
+		return now
+	}
+
+	ctx := context.Background()
+
+	_, err := timescale.Exec(`INSERT INTO insight_series (series_id, query, created_at, oldest_historical_at, last_recorded_at,
+                            next_recording_after, recording_interval_days, deleted_at)
+                            VALUES ('series-id-1', 'query-1', $1, $1, $1, $1, 5, NULL),
+                                   ('series-id-2', 'query-2', $1, $1, $1, $1, 6, NULL),
+                                   ('series-id-3', 'query-3', $1, $1, $1, $1, 7, $1);`, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.GetSeriesToBackfill(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"series-id-1", "series-id-2"}, seriesIDs(got)); diff != "" {
+		t.Fatalf("unexpected series to backfill (want/got): %s", diff)
+	}
+
+	stamped, err := store.StampBackfill(ctx, got[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stamped.BackfillQueuedAt == nil || !stamped.BackfillQueuedAt.Equal(now) {
+		t.Errorf("unexpected backfill time. want=%s have=%v", now, stamped.BackfillQueuedAt)
+	}
+
+	got, err = store.GetSeriesToBackfill(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"series-id-2"}, seriesIDs(got)); diff != "" {
+		t.Errorf("unexpected series to backfill after stamping (want/got): %s", diff)
+	}
+}
+
+func TestBusinessDaysOnlySeries(t *testing.T) {
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	store := NewInsightStore(timescale)
+
+	// series-id-1 is recorded on business days only by both of its views, series-id-2 by only one
+	// of them.
+	for _, seriesID := range []string{"series-id-1", "series-id-2"} {
+		series, err := store.CreateSeries(ctx, types.InsightSeries{SeriesID: seriesID, Query: seriesID, RecordingIntervalDays: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, uniqueID := range []string{"unique-1", "unique-2"} {
+			view, err := store.CreateView(ctx, types.InsightView{Title: uniqueID, UniqueID: seriesID + uniqueID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			businessDaysOnly := seriesID == "series-id-1" || uniqueID == "unique-1"
+			if err := store.AttachSeriesToView(ctx, series, view, types.InsightViewSeriesMetadata{BusinessDaysOnly: businessDaysOnly}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	got, err := store.BusinessDaysOnlySeries(ctx, []string{"series-id-1", "series-id-2", "series-id-3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]bool{"series-id-1": true}, got); diff != "" {
+		t.Errorf("unexpected business days only series (want/got): %s", diff)
+	}
+}
+
+func TestDeleteView(t *testing.T) {
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	now := time.Now().Truncate(time.Microsecond).Round(0)
+	ctx := context.Background()
+
+	store := NewInsightStore(timescale)
+	store.Now = func() time.Time {
+		return now
+	}
+
+	series, err := store.CreateSeries(ctx, types.InsightSeries{SeriesID: "series-id-1", Query: "query-1", RecordingIntervalDays: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, uniqueID := range []string{"unique-1", "unique-2"} {
+		view, err := store.CreateView(ctx, types.InsightView{Title: uniqueID, UniqueID: uniqueID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.AttachSeriesToView(ctx, series, view, types.InsightViewSeriesMetadata{Label: uniqueID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.DeleteView(ctx, "unique-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.Get(ctx, InsightQueryArgs{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].UniqueID != "unique-2" {
+		t.Errorf("unexpected views after deleting a view: %v", got)
+	}
+
+	// The series is retained, as it is shared with the other view.
+	dataSeries, err := store.GetDataSeries(ctx, GetDataSeriesArgs{SeriesID: "series-id-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"series-id-1"}, seriesIDs(dataSeries)); diff != "" {
+		t.Errorf("unexpected data series after deleting a view (want/got): %s", diff)
+	}
+	if dataSeries[0].ReferenceCount != 1 {
+		t.Errorf("unexpected reference count. want=%d have=%d", 1, dataSeries[0].ReferenceCount)
+	}
+
+	dataSeries, err = store.GetDataSeries(ctx, GetDataSeriesArgs{SeriesID: "series-id-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dataSeries) != 0 {
+		t.Errorf("unexpected data series: %v", dataSeries)
+	}
+
+	// The series is soft-deleted once no view references it.
+	if err := store.DeleteView(ctx, "unique-2"); err != nil {
+		t.Fatal(err)
+	}
+	dataSeries, err = store.GetDataSeries(ctx, GetDataSeriesArgs{SeriesID: "series-id-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dataSeries) != 0 {
+		t.Errorf("unexpected data series after deleting all views: %v", dataSeries)
+	}
+}
+
+func TestGetOrCreateSeries(t *testing.T) {
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	now := time.Now().Truncate(time.Microsecond).Round(0)
+	ctx := context.Background()
+
+	store := NewInsightStore(timescale)
+	store.Now = func() time.Time {
+		return now
+	}
+
+	created, err := store.GetOrCreateSeries(ctx, types.InsightSeries{SeriesID: "series-id-1", Query: "query-1", RecordingIntervalDays: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	view, err := store.CreateView(ctx, types.InsightView{Title: "unique-1", UniqueID: "unique-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AttachSeriesToView(ctx, created, view, types.InsightViewSeriesMetadata{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Identical series share the existing data series.
+	existing, err := store.GetOrCreateSeries(ctx, types.InsightSeries{SeriesID: "series-id-1", Query: "query-1", RecordingIntervalDays: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if existing.ID != created.ID || existing.ReferenceCount != 1 {
+		t.Errorf("unexpected existing series. want ID=%d references=%d have ID=%d references=%d", created.ID, 1, existing.ID, existing.ReferenceCount)
+	}
+
+	// Soft-deleted series are restored once attached to a view again, until they are purged.
+	if err := store.DeleteView(ctx, "unique-1"); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := store.GetOrCreateSeries(ctx, types.InsightSeries{SeriesID: "series-id-1", Query: "query-1", RecordingIntervalDays: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.ID != created.ID {
+		t.Errorf("unexpected restored series ID. want=%d have=%d", created.ID, restored.ID)
+	}
+	view, err = store.CreateView(ctx, types.InsightView{Title: "unique-2", UniqueID: "unique-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AttachSeriesToView(ctx, restored, view, types.InsightViewSeriesMetadata{}); err != nil {
+		t.Fatal(err)
+	}
+	dataSeries, err := store.GetDataSeries(ctx, GetDataSeriesArgs{SeriesID: "series-id-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dataSeries) != 1 || dataSeries[0].ReferenceCount != 1 {
+		t.Errorf("unexpected data series after restoring: %v", dataSeries)
+	}
+}
+
+func TestPurgeUnreferencedSeries(t *testing.T) {
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	now := time.Now().Truncate(time.Microsecond).Round(0)
+	ctx := context.Background()
+
+	store := NewInsightStore(timescale)
+	store.Now = func() time.Time {
+		return now
+	}
+
+	for _, seriesID := range []string{"series-id-1", "series-id-2"} {
+		series, err := store.CreateSeries(ctx, types.InsightSeries{SeriesID: seriesID, Query: seriesID, RecordingIntervalDays: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		view, err := store.CreateView(ctx, types.InsightView{Title: seriesID, UniqueID: seriesID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.AttachSeriesToView(ctx, series, view, types.InsightViewSeriesMetadata{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := timescale.Exec(`INSERT INTO series_points (series_id, time, value) VALUES ($1, $2, 1)`, seriesID, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.DeleteView(ctx, "series-id-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Series are only purged once they have been unreferenced for long enough.
+	if count, err := store.PurgeUnreferencedSeries(ctx, now); err != nil {
+		t.Fatal(err)
+	} else if count != 0 {
+		t.Errorf("unexpected number of purged series. want=%d have=%d", 0, count)
+	}
+	if count, err := store.PurgeUnreferencedSeries(ctx, now.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	} else if count != 1 {
+		t.Errorf("unexpected number of purged series. want=%d have=%d", 1, count)
+	}
+
+	var seriesIDs []string
+	rows, err := timescale.Query(`SELECT DISTINCT series_id FROM series_points ORDER BY series_id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var seriesID string
+		if err := rows.Scan(&seriesID); err != nil {
+			t.Fatal(err)
+		}
+		seriesIDs = append(seriesIDs, seriesID)
+	}
+	if diff := cmp.Diff([]string{"series-id-2"}, seriesIDs); diff != "" {
+		t.Errorf("unexpected series with data points after purging (want/got): %s", diff)
+	}
+}
+
+func seriesIDs(series []types.InsightSeries) []string {
+	ids := make([]string, 0, len(series))
+	for _, s := range series {
+		ids = append(ids, s.SeriesID)
+	}
+	return ids
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/inventory"
+)
+
+// LanguageStats describes the language statistics of a repository at a commit, as computed in the
+// background for language statistics insights.
+type LanguageStats struct {
+	RepoID     api.RepoID
+	RepoName   string
+	Commit     api.CommitID
+	Languages  []inventory.Lang
+	RecordedAt time.Time
+}
+
+// LanguageStats returns the latest language statistics recorded for the given repository, or nil
+// if none have been recorded yet.
+func (s *Store) LanguageStats(ctx context.Context, repoID api.RepoID) (*LanguageStats, error) {
+	var (
+		stats     LanguageStats
+		languages []byte
+	)
+	err := s.Store.QueryRow(ctx, sqlf.Sprintf(languageStatsFmtstr, int32(repoID))).Scan(
+		&stats.RepoID,
+		&stats.RepoName,
+		&stats.Commit,
+		&languages,
+		&stats.RecordedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(languages, &stats.Languages); err != nil {
+		return nil, errors.Wrap(err, "decoding languages")
+	}
+	return &stats, nil
+}
+
+const languageStatsFmtstr = `
+-- source: enterprise/internal/insights/store/language_stats.go:LanguageStats
+SELECT repo_id, repo_name, commit, languages, recorded_at FROM repo_language_stats WHERE repo_id = %s
+`
+
+// RecordLanguageStats records the given language statistics of a repository, replacing those
+// recorded previously. The time of recording is taken from the store's clock.
+func (s *Store) RecordLanguageStats(ctx context.Context, stats LanguageStats) error {
+	languages := stats.Languages
+	if languages == nil {
+		languages = []inventory.Lang{}
+	}
+	jsonLanguages, err := json.Marshal(languages)
+	if err != nil {
+		return errors.Wrap(err, "encoding languages")
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(
+		recordLanguageStatsFmtstr,
+		int32(stats.RepoID),  // repo_id
+		stats.RepoName,       // repo_name
+		string(stats.Commit), // commit
+		jsonLanguages,        // languages
+		s.now().UTC(),        // recorded_at
+	))
+}
+
+const recordLanguageStatsFmtstr = `
+-- source: enterprise/internal/insights/store/language_stats.go:RecordLanguageStats
+INSERT INTO repo_language_stats(repo_id, repo_name, commit, languages, recorded_at)
+VALUES (%s, %s, %s, %s, %s)
+ON CONFLICT (repo_id) DO UPDATE SET
+	repo_name = EXCLUDED.repo_name,
+	commit = EXCLUDED.commit,
+	languages = EXCLUDED.languages,
+	recorded_at = EXCLUDED.recorded_at
+`
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/inventory"
+)
+
+func TestLanguageStats(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	clock := func() time.Time { return now }
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	// Confirm we get no results initially.
+	stats, err := store.LanguageStats(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats != nil {
+		t.Fatalf("unexpected language stats before recording: %+v", stats)
+	}
+
+	for _, record := range []LanguageStats{
+		{
+			RepoID:    3,
+			RepoName:  "repo1",
+			Commit:    "deadbeef",
+			Languages: []inventory.Lang{{Name: "Go", TotalBytes: 100, TotalLines: 10}},
+		},
+		{
+			RepoID:   3,
+			RepoName: "repo1-renamed",
+			Commit:   "cafebabe",
+			Languages: []inventory.Lang{
+				{Name: "Go", TotalBytes: 200, TotalLines: 20},
+				{Name: "Markdown", TotalBytes: 50, TotalLines: 5},
+			},
+		},
+		{
+			RepoID:   4,
+			RepoName: "repo2",
+			Commit:   "f00dface",
+		},
+	} {
+		if err := store.RecordLanguageStats(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Recording replaces the previous statistics of a repository.
+	stats, err = store.LanguageStats(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &LanguageStats{
+		RepoID:   3,
+		RepoName: "repo1-renamed",
+		Commit:   "cafebabe",
+		Languages: []inventory.Lang{
+			{Name: "Go", TotalBytes: 200, TotalLines: 20},
+			{Name: "Markdown", TotalBytes: 50, TotalLines: 5},
+		},
+		RecordedAt: now,
+	}
+	if diff := cmp.Diff(want, stats); diff != "" {
+		t.Errorf("unexpected language stats (-want +got):\n%s", diff)
+	}
+
+	stats, err = store.LanguageStats(ctx, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = &LanguageStats{RepoID: 4, RepoName: "repo2", Commit: "f00dface", Languages: []inventory.Lang{}, RecordedAt: now}
+	if diff := cmp.Diff(want, stats); diff != "" {
+		t.Errorf("unexpected language stats (-want +got):\n%s", diff)
+	}
+}
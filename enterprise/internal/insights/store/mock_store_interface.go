@@ -5,6 +5,9 @@ package store
 import (
 	"context"
 	"sync"
+	"time"
+
+	api "github.com/sourcegraph/sourcegraph/internal/api"
 )
 
 // MockInterface is a mock implementation of the Interface interface (from
@@ -12,36 +15,166 @@ import (
 // github.com/sourcegraph/sourcegraph/enterprise/internal/insights/store)
 // used for unit testing.
 type MockInterface struct {
+	// BackfillCheckpointsFunc is an instance of a mock function object
+	// controlling the behavior of the method BackfillCheckpoints.
+	BackfillCheckpointsFunc *InterfaceBackfillCheckpointsFunc
+	// CaptureValuesFunc is an instance of a mock function object
+	// controlling the behavior of the method CaptureValues.
+	CaptureValuesFunc *InterfaceCaptureValuesFunc
+	// ClearBackfillCheckpointsFunc is an instance of a mock function object
+	// controlling the behavior of the method ClearBackfillCheckpoints.
+	ClearBackfillCheckpointsFunc *InterfaceClearBackfillCheckpointsFunc
+	// ClearSeriesEnqueueFailuresFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// ClearSeriesEnqueueFailures.
+	ClearSeriesEnqueueFailuresFunc *InterfaceClearSeriesEnqueueFailuresFunc
 	// CountDataFunc is an instance of a mock function object controlling
 	// the behavior of the method CountData.
 	CountDataFunc *InterfaceCountDataFunc
+	// CreateAlertRuleFunc is an instance of a mock function object
+	// controlling the behavior of the method CreateAlertRule.
+	CreateAlertRuleFunc *InterfaceCreateAlertRuleFunc
+	// DeleteAlertRuleFunc is an instance of a mock function object
+	// controlling the behavior of the method DeleteAlertRule.
+	DeleteAlertRuleFunc *InterfaceDeleteAlertRuleFunc
+	// LanguageStatsFunc is an instance of a mock function object
+	// controlling the behavior of the method LanguageStats.
+	LanguageStatsFunc *InterfaceLanguageStatsFunc
+	// LatestSeriesPointTimesFunc is an instance of a mock function object
+	// controlling the behavior of the method LatestSeriesPointTimes.
+	LatestSeriesPointTimesFunc *InterfaceLatestSeriesPointTimesFunc
+	// ListAlertRulesFunc is an instance of a mock function object
+	// controlling the behavior of the method ListAlertRules.
+	ListAlertRulesFunc *InterfaceListAlertRulesFunc
+	// RecordSeriesEnqueueFailureFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// RecordSeriesEnqueueFailure.
+	RecordSeriesEnqueueFailureFunc *InterfaceRecordSeriesEnqueueFailureFunc
 	// RecordSeriesPointFunc is an instance of a mock function object
 	// controlling the behavior of the method RecordSeriesPoint.
 	RecordSeriesPointFunc *InterfaceRecordSeriesPointFunc
+	// RepoPointsAtFunc is an instance of a mock function object controlling
+	// the behavior of the method RepoPointsAt.
+	RepoPointsAtFunc *InterfaceRepoPointsAtFunc
+	// ResetSeriesCircuitFunc is an instance of a mock function object
+	// controlling the behavior of the method ResetSeriesCircuit.
+	ResetSeriesCircuitFunc *InterfaceResetSeriesCircuitFunc
+	// SaveBackfillCheckpointFunc is an instance of a mock function object
+	// controlling the behavior of the method SaveBackfillCheckpoint.
+	SaveBackfillCheckpointFunc *InterfaceSaveBackfillCheckpointFunc
+	// SeriesEnqueueFailuresFunc is an instance of a mock function object
+	// controlling the behavior of the method SeriesEnqueueFailures.
+	SeriesEnqueueFailuresFunc *InterfaceSeriesEnqueueFailuresFunc
 	// SeriesPointsFunc is an instance of a mock function object controlling
 	// the behavior of the method SeriesPoints.
 	SeriesPointsFunc *InterfaceSeriesPointsFunc
+	// SeriesRunStatusFunc is an instance of a mock function object
+	// controlling the behavior of the method SeriesRunStatus.
+	SeriesRunStatusFunc *InterfaceSeriesRunStatusFunc
+	// WebhookDeliveryStatusFunc is an instance of a mock function object
+	// controlling the behavior of the method WebhookDeliveryStatus.
+	WebhookDeliveryStatusFunc *InterfaceWebhookDeliveryStatusFunc
 }
 
 // NewMockInterface creates a new mock of the Interface interface. All
 // methods return zero values for all results, unless overwritten.
 func NewMockInterface() *MockInterface {
 	return &MockInterface{
+		BackfillCheckpointsFunc: &InterfaceBackfillCheckpointsFunc{
+			defaultHook: func(context.Context, []string) (map[string]BackfillCheckpoint, error) {
+				return nil, nil
+			},
+		},
+		CaptureValuesFunc: &InterfaceCaptureValuesFunc{
+			defaultHook: func(context.Context, string) ([]string, error) {
+				return nil, nil
+			},
+		},
+		ClearBackfillCheckpointsFunc: &InterfaceClearBackfillCheckpointsFunc{
+			defaultHook: func(context.Context, []string) error {
+				return nil
+			},
+		},
+		ClearSeriesEnqueueFailuresFunc: &InterfaceClearSeriesEnqueueFailuresFunc{
+			defaultHook: func(context.Context, []string) error {
+				return nil
+			},
+		},
 		CountDataFunc: &InterfaceCountDataFunc{
 			defaultHook: func(context.Context, CountDataOpts) (int, error) {
 				return 0, nil
 			},
 		},
+		CreateAlertRuleFunc: &InterfaceCreateAlertRuleFunc{
+			defaultHook: func(context.Context, AlertRule) (AlertRule, error) {
+				return AlertRule{}, nil
+			},
+		},
+		DeleteAlertRuleFunc: &InterfaceDeleteAlertRuleFunc{
+			defaultHook: func(context.Context, int, int32) (bool, error) {
+				return false, nil
+			},
+		},
+		LanguageStatsFunc: &InterfaceLanguageStatsFunc{
+			defaultHook: func(context.Context, api.RepoID) (*LanguageStats, error) {
+				return nil, nil
+			},
+		},
+		LatestSeriesPointTimesFunc: &InterfaceLatestSeriesPointTimesFunc{
+			defaultHook: func(context.Context, []string, time.Time) (map[string]time.Time, error) {
+				return nil, nil
+			},
+		},
+		ListAlertRulesFunc: &InterfaceListAlertRulesFunc{
+			defaultHook: func(context.Context, ListAlertRulesOpts) ([]AlertRule, error) {
+				return nil, nil
+			},
+		},
+		RecordSeriesEnqueueFailureFunc: &InterfaceRecordSeriesEnqueueFailureFunc{
+			defaultHook: func(context.Context, string, string, error) error {
+				return nil
+			},
+		},
 		RecordSeriesPointFunc: &InterfaceRecordSeriesPointFunc{
 			defaultHook: func(context.Context, RecordSeriesPointArgs) error {
 				return nil
 			},
 		},
+		RepoPointsAtFunc: &InterfaceRepoPointsAtFunc{
+			defaultHook: func(context.Context, RepoPointsAtOpts) ([]RepoPoint, error) {
+				return nil, nil
+			},
+		},
+		ResetSeriesCircuitFunc: &InterfaceResetSeriesCircuitFunc{
+			defaultHook: func(context.Context, string) (bool, error) {
+				return false, nil
+			},
+		},
+		SaveBackfillCheckpointFunc: &InterfaceSaveBackfillCheckpointFunc{
+			defaultHook: func(context.Context, BackfillCheckpoint) error {
+				return nil
+			},
+		},
+		SeriesEnqueueFailuresFunc: &InterfaceSeriesEnqueueFailuresFunc{
+			defaultHook: func(context.Context, SeriesEnqueueFailuresOpts) ([]SeriesEnqueueFailure, error) {
+				return nil, nil
+			},
+		},
 		SeriesPointsFunc: &InterfaceSeriesPointsFunc{
 			defaultHook: func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error) {
 				return nil, nil
 			},
 		},
+		SeriesRunStatusFunc: &InterfaceSeriesRunStatusFunc{
+			defaultHook: func(context.Context, string) (SeriesRunStatus, bool, error) {
+				return SeriesRunStatus{}, false, nil
+			},
+		},
+		WebhookDeliveryStatusFunc: &InterfaceWebhookDeliveryStatusFunc{
+			defaultHook: func(context.Context, string) (WebhookDeliveryStatus, bool, error) {
+				return WebhookDeliveryStatus{}, false, nil
+			},
+		},
 	}
 }
 
@@ -49,46 +182,96 @@ func NewMockInterface() *MockInterface {
 // All methods delegate to the given implementation, unless overwritten.
 func NewMockInterfaceFrom(i Interface) *MockInterface {
 	return &MockInterface{
+		BackfillCheckpointsFunc: &InterfaceBackfillCheckpointsFunc{
+			defaultHook: i.BackfillCheckpoints,
+		},
+		CaptureValuesFunc: &InterfaceCaptureValuesFunc{
+			defaultHook: i.CaptureValues,
+		},
+		ClearBackfillCheckpointsFunc: &InterfaceClearBackfillCheckpointsFunc{
+			defaultHook: i.ClearBackfillCheckpoints,
+		},
+		ClearSeriesEnqueueFailuresFunc: &InterfaceClearSeriesEnqueueFailuresFunc{
+			defaultHook: i.ClearSeriesEnqueueFailures,
+		},
 		CountDataFunc: &InterfaceCountDataFunc{
 			defaultHook: i.CountData,
 		},
+		CreateAlertRuleFunc: &InterfaceCreateAlertRuleFunc{
+			defaultHook: i.CreateAlertRule,
+		},
+		DeleteAlertRuleFunc: &InterfaceDeleteAlertRuleFunc{
+			defaultHook: i.DeleteAlertRule,
+		},
+		LanguageStatsFunc: &InterfaceLanguageStatsFunc{
+			defaultHook: i.LanguageStats,
+		},
+		LatestSeriesPointTimesFunc: &InterfaceLatestSeriesPointTimesFunc{
+			defaultHook: i.LatestSeriesPointTimes,
+		},
+		ListAlertRulesFunc: &InterfaceListAlertRulesFunc{
+			defaultHook: i.ListAlertRules,
+		},
+		RecordSeriesEnqueueFailureFunc: &InterfaceRecordSeriesEnqueueFailureFunc{
+			defaultHook: i.RecordSeriesEnqueueFailure,
+		},
 		RecordSeriesPointFunc: &InterfaceRecordSeriesPointFunc{
 			defaultHook: i.RecordSeriesPoint,
 		},
+		RepoPointsAtFunc: &InterfaceRepoPointsAtFunc{
+			defaultHook: i.RepoPointsAt,
+		},
+		ResetSeriesCircuitFunc: &InterfaceResetSeriesCircuitFunc{
+			defaultHook: i.ResetSeriesCircuit,
+		},
+		SaveBackfillCheckpointFunc: &InterfaceSaveBackfillCheckpointFunc{
+			defaultHook: i.SaveBackfillCheckpoint,
+		},
+		SeriesEnqueueFailuresFunc: &InterfaceSeriesEnqueueFailuresFunc{
+			defaultHook: i.SeriesEnqueueFailures,
+		},
 		SeriesPointsFunc: &InterfaceSeriesPointsFunc{
 			defaultHook: i.SeriesPoints,
 		},
+		SeriesRunStatusFunc: &InterfaceSeriesRunStatusFunc{
+			defaultHook: i.SeriesRunStatus,
+		},
+		WebhookDeliveryStatusFunc: &InterfaceWebhookDeliveryStatusFunc{
+			defaultHook: i.WebhookDeliveryStatus,
+		},
 	}
 }
 
-// InterfaceCountDataFunc describes the behavior when the CountData method
-// of the parent MockInterface instance is invoked.
-type InterfaceCountDataFunc struct {
-	defaultHook func(context.Context, CountDataOpts) (int, error)
-	hooks       []func(context.Context, CountDataOpts) (int, error)
-	history     []InterfaceCountDataFuncCall
+// InterfaceBackfillCheckpointsFunc describes the behavior when the
+// BackfillCheckpoints method of the parent MockInterface instance is
+// invoked.
+type InterfaceBackfillCheckpointsFunc struct {
+	defaultHook func(context.Context, []string) (map[string]BackfillCheckpoint, error)
+	hooks       []func(context.Context, []string) (map[string]BackfillCheckpoint, error)
+	history     []InterfaceBackfillCheckpointsFuncCall
 	mutex       sync.Mutex
 }
 
-// CountData delegates to the next hook function in the queue and stores the
-// parameter and result values of this invocation.
-func (m *MockInterface) CountData(v0 context.Context, v1 CountDataOpts) (int, error) {
-	r0, r1 := m.CountDataFunc.nextHook()(v0, v1)
-	m.CountDataFunc.appendCall(InterfaceCountDataFuncCall{v0, v1, r0, r1})
+// BackfillCheckpoints delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockInterface) BackfillCheckpoints(v0 context.Context, v1 []string) (map[string]BackfillCheckpoint, error) {
+	r0, r1 := m.BackfillCheckpointsFunc.nextHook()(v0, v1)
+	m.BackfillCheckpointsFunc.appendCall(InterfaceBackfillCheckpointsFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the CountData method of
-// the parent MockInterface instance is invoked and the hook queue is empty.
-func (f *InterfaceCountDataFunc) SetDefaultHook(hook func(context.Context, CountDataOpts) (int, error)) {
+// SetDefaultHook sets function that is called when the BackfillCheckpoints
+// method of the parent MockInterface instance is invoked and the hook queue
+// is empty.
+func (f *InterfaceBackfillCheckpointsFunc) SetDefaultHook(hook func(context.Context, []string) (map[string]BackfillCheckpoint, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// CountData method of the parent MockInterface instance invokes the hook at
-// the front of the queue and discards it. After the queue is empty, the
-// default hook function is invoked for any future action.
-func (f *InterfaceCountDataFunc) PushHook(hook func(context.Context, CountDataOpts) (int, error)) {
+// BackfillCheckpoints method of the parent MockInterface instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *InterfaceBackfillCheckpointsFunc) PushHook(hook func(context.Context, []string) (map[string]BackfillCheckpoint, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -96,21 +279,21 @@ func (f *InterfaceCountDataFunc) PushHook(hook func(context.Context, CountDataOp
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *InterfaceCountDataFunc) SetDefaultReturn(r0 int, r1 error) {
-	f.SetDefaultHook(func(context.Context, CountDataOpts) (int, error) {
+func (f *InterfaceBackfillCheckpointsFunc) SetDefaultReturn(r0 map[string]BackfillCheckpoint, r1 error) {
+	f.SetDefaultHook(func(context.Context, []string) (map[string]BackfillCheckpoint, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *InterfaceCountDataFunc) PushReturn(r0 int, r1 error) {
-	f.PushHook(func(context.Context, CountDataOpts) (int, error) {
+func (f *InterfaceBackfillCheckpointsFunc) PushReturn(r0 map[string]BackfillCheckpoint, r1 error) {
+	f.PushHook(func(context.Context, []string) (map[string]BackfillCheckpoint, error) {
 		return r0, r1
 	})
 }
 
-func (f *InterfaceCountDataFunc) nextHook() func(context.Context, CountDataOpts) (int, error) {
+func (f *InterfaceBackfillCheckpointsFunc) nextHook() func(context.Context, []string) (map[string]BackfillCheckpoint, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -123,35 +306,35 @@ func (f *InterfaceCountDataFunc) nextHook() func(context.Context, CountDataOpts)
 	return hook
 }
 
-func (f *InterfaceCountDataFunc) appendCall(r0 InterfaceCountDataFuncCall) {
+func (f *InterfaceBackfillCheckpointsFunc) appendCall(r0 InterfaceBackfillCheckpointsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of InterfaceCountDataFuncCall objects
-// describing the invocations of this function.
-func (f *InterfaceCountDataFunc) History() []InterfaceCountDataFuncCall {
+// History returns a sequence of InterfaceBackfillCheckpointsFuncCall
+// objects describing the invocations of this function.
+func (f *InterfaceBackfillCheckpointsFunc) History() []InterfaceBackfillCheckpointsFuncCall {
 	f.mutex.Lock()
-	history := make([]InterfaceCountDataFuncCall, len(f.history))
+	history := make([]InterfaceBackfillCheckpointsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// InterfaceCountDataFuncCall is an object that describes an invocation of
-// method CountData on an instance of MockInterface.
-type InterfaceCountDataFuncCall struct {
+// InterfaceBackfillCheckpointsFuncCall is an object that describes an
+// invocation of method BackfillCheckpoints on an instance of MockInterface.
+type InterfaceBackfillCheckpointsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 CountDataOpts
+	Arg1 []string
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 int
+	Result0 map[string]BackfillCheckpoint
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -159,45 +342,45 @@ type InterfaceCountDataFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c InterfaceCountDataFuncCall) Args() []interface{} {
+func (c InterfaceBackfillCheckpointsFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c InterfaceCountDataFuncCall) Results() []interface{} {
+func (c InterfaceBackfillCheckpointsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// InterfaceRecordSeriesPointFunc describes the behavior when the
-// RecordSeriesPoint method of the parent MockInterface instance is invoked.
-type InterfaceRecordSeriesPointFunc struct {
-	defaultHook func(context.Context, RecordSeriesPointArgs) error
-	hooks       []func(context.Context, RecordSeriesPointArgs) error
-	history     []InterfaceRecordSeriesPointFuncCall
+// InterfaceCaptureValuesFunc describes the behavior when the CaptureValues
+// method of the parent MockInterface instance is invoked.
+type InterfaceCaptureValuesFunc struct {
+	defaultHook func(context.Context, string) ([]string, error)
+	hooks       []func(context.Context, string) ([]string, error)
+	history     []InterfaceCaptureValuesFuncCall
 	mutex       sync.Mutex
 }
 
-// RecordSeriesPoint delegates to the next hook function in the queue and
-// stores the parameter and result values of this invocation.
-func (m *MockInterface) RecordSeriesPoint(v0 context.Context, v1 RecordSeriesPointArgs) error {
-	r0 := m.RecordSeriesPointFunc.nextHook()(v0, v1)
-	m.RecordSeriesPointFunc.appendCall(InterfaceRecordSeriesPointFuncCall{v0, v1, r0})
-	return r0
+// CaptureValues delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockInterface) CaptureValues(v0 context.Context, v1 string) ([]string, error) {
+	r0, r1 := m.CaptureValuesFunc.nextHook()(v0, v1)
+	m.CaptureValuesFunc.appendCall(InterfaceCaptureValuesFuncCall{v0, v1, r0, r1})
+	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the RecordSeriesPoint
-// method of the parent MockInterface instance is invoked and the hook queue
-// is empty.
-func (f *InterfaceRecordSeriesPointFunc) SetDefaultHook(hook func(context.Context, RecordSeriesPointArgs) error) {
+// SetDefaultHook sets function that is called when the CaptureValues method
+// of the parent MockInterface instance is invoked and the hook queue is
+// empty.
+func (f *InterfaceCaptureValuesFunc) SetDefaultHook(hook func(context.Context, string) ([]string, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// RecordSeriesPoint method of the parent MockInterface instance invokes the
+// CaptureValues method of the parent MockInterface instance invokes the
 // hook at the front of the queue and discards it. After the queue is empty,
 // the default hook function is invoked for any future action.
-func (f *InterfaceRecordSeriesPointFunc) PushHook(hook func(context.Context, RecordSeriesPointArgs) error) {
+func (f *InterfaceCaptureValuesFunc) PushHook(hook func(context.Context, string) ([]string, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -205,21 +388,132 @@ func (f *InterfaceRecordSeriesPointFunc) PushHook(hook func(context.Context, Rec
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *InterfaceRecordSeriesPointFunc) SetDefaultReturn(r0 error) {
-	f.SetDefaultHook(func(context.Context, RecordSeriesPointArgs) error {
+func (f *InterfaceCaptureValuesFunc) SetDefaultReturn(r0 []string, r1 error) {
+	f.SetDefaultHook(func(context.Context, string) ([]string, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceCaptureValuesFunc) PushReturn(r0 []string, r1 error) {
+	f.PushHook(func(context.Context, string) ([]string, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceCaptureValuesFunc) nextHook() func(context.Context, string) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceCaptureValuesFunc) appendCall(r0 InterfaceCaptureValuesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceCaptureValuesFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceCaptureValuesFunc) History() []InterfaceCaptureValuesFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceCaptureValuesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceCaptureValuesFuncCall is an object that describes an invocation
+// of method CaptureValues on an instance of MockInterface.
+type InterfaceCaptureValuesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceCaptureValuesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceCaptureValuesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// InterfaceClearBackfillCheckpointsFunc describes the behavior when the
+// ClearBackfillCheckpoints method of the parent MockInterface instance is
+// invoked.
+type InterfaceClearBackfillCheckpointsFunc struct {
+	defaultHook func(context.Context, []string) error
+	hooks       []func(context.Context, []string) error
+	history     []InterfaceClearBackfillCheckpointsFuncCall
+	mutex       sync.Mutex
+}
+
+// ClearBackfillCheckpoints delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockInterface) ClearBackfillCheckpoints(v0 context.Context, v1 []string) error {
+	r0 := m.ClearBackfillCheckpointsFunc.nextHook()(v0, v1)
+	m.ClearBackfillCheckpointsFunc.appendCall(InterfaceClearBackfillCheckpointsFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// ClearBackfillCheckpoints method of the parent MockInterface instance is
+// invoked and the hook queue is empty.
+func (f *InterfaceClearBackfillCheckpointsFunc) SetDefaultHook(hook func(context.Context, []string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ClearBackfillCheckpoints method of the parent MockInterface instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *InterfaceClearBackfillCheckpointsFunc) PushHook(hook func(context.Context, []string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceClearBackfillCheckpointsFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, []string) error {
 		return r0
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *InterfaceRecordSeriesPointFunc) PushReturn(r0 error) {
-	f.PushHook(func(context.Context, RecordSeriesPointArgs) error {
+func (f *InterfaceClearBackfillCheckpointsFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, []string) error {
 		return r0
 	})
 }
 
-func (f *InterfaceRecordSeriesPointFunc) nextHook() func(context.Context, RecordSeriesPointArgs) error {
+func (f *InterfaceClearBackfillCheckpointsFunc) nextHook() func(context.Context, []string) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -232,32 +526,33 @@ func (f *InterfaceRecordSeriesPointFunc) nextHook() func(context.Context, Record
 	return hook
 }
 
-func (f *InterfaceRecordSeriesPointFunc) appendCall(r0 InterfaceRecordSeriesPointFuncCall) {
+func (f *InterfaceClearBackfillCheckpointsFunc) appendCall(r0 InterfaceClearBackfillCheckpointsFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of InterfaceRecordSeriesPointFuncCall objects
-// describing the invocations of this function.
-func (f *InterfaceRecordSeriesPointFunc) History() []InterfaceRecordSeriesPointFuncCall {
+// History returns a sequence of InterfaceClearBackfillCheckpointsFuncCall
+// objects describing the invocations of this function.
+func (f *InterfaceClearBackfillCheckpointsFunc) History() []InterfaceClearBackfillCheckpointsFuncCall {
 	f.mutex.Lock()
-	history := make([]InterfaceRecordSeriesPointFuncCall, len(f.history))
+	history := make([]InterfaceClearBackfillCheckpointsFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// InterfaceRecordSeriesPointFuncCall is an object that describes an
-// invocation of method RecordSeriesPoint on an instance of MockInterface.
-type InterfaceRecordSeriesPointFuncCall struct {
+// InterfaceClearBackfillCheckpointsFuncCall is an object that describes an
+// invocation of method ClearBackfillCheckpoints on an instance of
+// MockInterface.
+type InterfaceClearBackfillCheckpointsFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 RecordSeriesPointArgs
+	Arg1 []string
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 error
@@ -265,45 +560,47 @@ type InterfaceRecordSeriesPointFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c InterfaceRecordSeriesPointFuncCall) Args() []interface{} {
+func (c InterfaceClearBackfillCheckpointsFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c InterfaceRecordSeriesPointFuncCall) Results() []interface{} {
+func (c InterfaceClearBackfillCheckpointsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
-// InterfaceSeriesPointsFunc describes the behavior when the SeriesPoints
-// method of the parent MockInterface instance is invoked.
-type InterfaceSeriesPointsFunc struct {
-	defaultHook func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error)
-	hooks       []func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error)
-	history     []InterfaceSeriesPointsFuncCall
+// InterfaceClearSeriesEnqueueFailuresFunc describes the behavior when the
+// ClearSeriesEnqueueFailures method of the parent MockInterface instance is
+// invoked.
+type InterfaceClearSeriesEnqueueFailuresFunc struct {
+	defaultHook func(context.Context, []string) error
+	hooks       []func(context.Context, []string) error
+	history     []InterfaceClearSeriesEnqueueFailuresFuncCall
 	mutex       sync.Mutex
 }
 
-// SeriesPoints delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockInterface) SeriesPoints(v0 context.Context, v1 SeriesPointsOpts) ([]SeriesPoint, error) {
-	r0, r1 := m.SeriesPointsFunc.nextHook()(v0, v1)
-	m.SeriesPointsFunc.appendCall(InterfaceSeriesPointsFuncCall{v0, v1, r0, r1})
-	return r0, r1
+// ClearSeriesEnqueueFailures delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockInterface) ClearSeriesEnqueueFailures(v0 context.Context, v1 []string) error {
+	r0 := m.ClearSeriesEnqueueFailuresFunc.nextHook()(v0, v1)
+	m.ClearSeriesEnqueueFailuresFunc.appendCall(InterfaceClearSeriesEnqueueFailuresFuncCall{v0, v1, r0})
+	return r0
 }
 
-// SetDefaultHook sets function that is called when the SeriesPoints method
-// of the parent MockInterface instance is invoked and the hook queue is
-// empty.
-func (f *InterfaceSeriesPointsFunc) SetDefaultHook(hook func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error)) {
+// SetDefaultHook sets function that is called when the
+// ClearSeriesEnqueueFailures method of the parent MockInterface instance is
+// invoked and the hook queue is empty.
+func (f *InterfaceClearSeriesEnqueueFailuresFunc) SetDefaultHook(hook func(context.Context, []string) error) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// SeriesPoints method of the parent MockInterface instance invokes the hook
-// at the front of the queue and discards it. After the queue is empty, the
-// default hook function is invoked for any future action.
-func (f *InterfaceSeriesPointsFunc) PushHook(hook func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error)) {
+// ClearSeriesEnqueueFailures method of the parent MockInterface instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *InterfaceClearSeriesEnqueueFailuresFunc) PushHook(hook func(context.Context, []string) error) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -311,21 +608,21 @@ func (f *InterfaceSeriesPointsFunc) PushHook(hook func(context.Context, SeriesPo
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *InterfaceSeriesPointsFunc) SetDefaultReturn(r0 []SeriesPoint, r1 error) {
-	f.SetDefaultHook(func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error) {
-		return r0, r1
+func (f *InterfaceClearSeriesEnqueueFailuresFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, []string) error {
+		return r0
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *InterfaceSeriesPointsFunc) PushReturn(r0 []SeriesPoint, r1 error) {
-	f.PushHook(func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error) {
-		return r0, r1
+func (f *InterfaceClearSeriesEnqueueFailuresFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, []string) error {
+		return r0
 	})
 }
 
-func (f *InterfaceSeriesPointsFunc) nextHook() func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error) {
+func (f *InterfaceClearSeriesEnqueueFailuresFunc) nextHook() func(context.Context, []string) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -338,43 +635,1472 @@ func (f *InterfaceSeriesPointsFunc) nextHook() func(context.Context, SeriesPoint
 	return hook
 }
 
-func (f *InterfaceSeriesPointsFunc) appendCall(r0 InterfaceSeriesPointsFuncCall) {
+func (f *InterfaceClearSeriesEnqueueFailuresFunc) appendCall(r0 InterfaceClearSeriesEnqueueFailuresFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of InterfaceSeriesPointsFuncCall objects
-// describing the invocations of this function.
-func (f *InterfaceSeriesPointsFunc) History() []InterfaceSeriesPointsFuncCall {
+// History returns a sequence of InterfaceClearSeriesEnqueueFailuresFuncCall
+// objects describing the invocations of this function.
+func (f *InterfaceClearSeriesEnqueueFailuresFunc) History() []InterfaceClearSeriesEnqueueFailuresFuncCall {
 	f.mutex.Lock()
-	history := make([]InterfaceSeriesPointsFuncCall, len(f.history))
+	history := make([]InterfaceClearSeriesEnqueueFailuresFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// InterfaceSeriesPointsFuncCall is an object that describes an invocation
-// of method SeriesPoints on an instance of MockInterface.
-type InterfaceSeriesPointsFuncCall struct {
+// InterfaceClearSeriesEnqueueFailuresFuncCall is an object that describes
+// an invocation of method ClearSeriesEnqueueFailures on an instance of
+// MockInterface.
+type InterfaceClearSeriesEnqueueFailuresFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 SeriesPointsOpts
+	Arg1 []string
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []SeriesPoint
-	// Result1 is the value of the 2nd result returned from this method
-	// invocation.
-	Result1 error
+	Result0 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c InterfaceSeriesPointsFuncCall) Args() []interface{} {
+func (c InterfaceClearSeriesEnqueueFailuresFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceClearSeriesEnqueueFailuresFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// InterfaceCountDataFunc describes the behavior when the CountData method
+// of the parent MockInterface instance is invoked.
+type InterfaceCountDataFunc struct {
+	defaultHook func(context.Context, CountDataOpts) (int, error)
+	hooks       []func(context.Context, CountDataOpts) (int, error)
+	history     []InterfaceCountDataFuncCall
+	mutex       sync.Mutex
+}
+
+// CountData delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockInterface) CountData(v0 context.Context, v1 CountDataOpts) (int, error) {
+	r0, r1 := m.CountDataFunc.nextHook()(v0, v1)
+	m.CountDataFunc.appendCall(InterfaceCountDataFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CountData method of
+// the parent MockInterface instance is invoked and the hook queue is empty.
+func (f *InterfaceCountDataFunc) SetDefaultHook(hook func(context.Context, CountDataOpts) (int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CountData method of the parent MockInterface instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *InterfaceCountDataFunc) PushHook(hook func(context.Context, CountDataOpts) (int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceCountDataFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context, CountDataOpts) (int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceCountDataFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context, CountDataOpts) (int, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceCountDataFunc) nextHook() func(context.Context, CountDataOpts) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceCountDataFunc) appendCall(r0 InterfaceCountDataFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceCountDataFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceCountDataFunc) History() []InterfaceCountDataFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceCountDataFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceCountDataFuncCall is an object that describes an invocation of
+// method CountData on an instance of MockInterface.
+type InterfaceCountDataFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 CountDataOpts
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceCountDataFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceCountDataFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// InterfaceCreateAlertRuleFunc describes the behavior when the
+// CreateAlertRule method of the parent MockInterface instance is invoked.
+type InterfaceCreateAlertRuleFunc struct {
+	defaultHook func(context.Context, AlertRule) (AlertRule, error)
+	hooks       []func(context.Context, AlertRule) (AlertRule, error)
+	history     []InterfaceCreateAlertRuleFuncCall
+	mutex       sync.Mutex
+}
+
+// CreateAlertRule delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockInterface) CreateAlertRule(v0 context.Context, v1 AlertRule) (AlertRule, error) {
+	r0, r1 := m.CreateAlertRuleFunc.nextHook()(v0, v1)
+	m.CreateAlertRuleFunc.appendCall(InterfaceCreateAlertRuleFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the CreateAlertRule
+// method of the parent MockInterface instance is invoked and the hook queue
+// is empty.
+func (f *InterfaceCreateAlertRuleFunc) SetDefaultHook(hook func(context.Context, AlertRule) (AlertRule, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// CreateAlertRule method of the parent MockInterface instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *InterfaceCreateAlertRuleFunc) PushHook(hook func(context.Context, AlertRule) (AlertRule, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceCreateAlertRuleFunc) SetDefaultReturn(r0 AlertRule, r1 error) {
+	f.SetDefaultHook(func(context.Context, AlertRule) (AlertRule, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceCreateAlertRuleFunc) PushReturn(r0 AlertRule, r1 error) {
+	f.PushHook(func(context.Context, AlertRule) (AlertRule, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceCreateAlertRuleFunc) nextHook() func(context.Context, AlertRule) (AlertRule, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceCreateAlertRuleFunc) appendCall(r0 InterfaceCreateAlertRuleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceCreateAlertRuleFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceCreateAlertRuleFunc) History() []InterfaceCreateAlertRuleFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceCreateAlertRuleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceCreateAlertRuleFuncCall is an object that describes an
+// invocation of method CreateAlertRule on an instance of MockInterface.
+type InterfaceCreateAlertRuleFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 AlertRule
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 AlertRule
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceCreateAlertRuleFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceCreateAlertRuleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// InterfaceDeleteAlertRuleFunc describes the behavior when the
+// DeleteAlertRule method of the parent MockInterface instance is invoked.
+type InterfaceDeleteAlertRuleFunc struct {
+	defaultHook func(context.Context, int, int32) (bool, error)
+	hooks       []func(context.Context, int, int32) (bool, error)
+	history     []InterfaceDeleteAlertRuleFuncCall
+	mutex       sync.Mutex
+}
+
+// DeleteAlertRule delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockInterface) DeleteAlertRule(v0 context.Context, v1 int, v2 int32) (bool, error) {
+	r0, r1 := m.DeleteAlertRuleFunc.nextHook()(v0, v1, v2)
+	m.DeleteAlertRuleFunc.appendCall(InterfaceDeleteAlertRuleFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the DeleteAlertRule
+// method of the parent MockInterface instance is invoked and the hook queue
+// is empty.
+func (f *InterfaceDeleteAlertRuleFunc) SetDefaultHook(hook func(context.Context, int, int32) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DeleteAlertRule method of the parent MockInterface instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *InterfaceDeleteAlertRuleFunc) PushHook(hook func(context.Context, int, int32) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceDeleteAlertRuleFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int32) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceDeleteAlertRuleFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, int, int32) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceDeleteAlertRuleFunc) nextHook() func(context.Context, int, int32) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceDeleteAlertRuleFunc) appendCall(r0 InterfaceDeleteAlertRuleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceDeleteAlertRuleFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceDeleteAlertRuleFunc) History() []InterfaceDeleteAlertRuleFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceDeleteAlertRuleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceDeleteAlertRuleFuncCall is an object that describes an
+// invocation of method DeleteAlertRule on an instance of MockInterface.
+type InterfaceDeleteAlertRuleFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int32
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceDeleteAlertRuleFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceDeleteAlertRuleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// InterfaceLanguageStatsFunc describes the behavior when the LanguageStats
+// method of the parent MockInterface instance is invoked.
+type InterfaceLanguageStatsFunc struct {
+	defaultHook func(context.Context, api.RepoID) (*LanguageStats, error)
+	hooks       []func(context.Context, api.RepoID) (*LanguageStats, error)
+	history     []InterfaceLanguageStatsFuncCall
+	mutex       sync.Mutex
+}
+
+// LanguageStats delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockInterface) LanguageStats(v0 context.Context, v1 api.RepoID) (*LanguageStats, error) {
+	r0, r1 := m.LanguageStatsFunc.nextHook()(v0, v1)
+	m.LanguageStatsFunc.appendCall(InterfaceLanguageStatsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the LanguageStats method
+// of the parent MockInterface instance is invoked and the hook queue is
+// empty.
+func (f *InterfaceLanguageStatsFunc) SetDefaultHook(hook func(context.Context, api.RepoID) (*LanguageStats, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// LanguageStats method of the parent MockInterface instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *InterfaceLanguageStatsFunc) PushHook(hook func(context.Context, api.RepoID) (*LanguageStats, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceLanguageStatsFunc) SetDefaultReturn(r0 *LanguageStats, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoID) (*LanguageStats, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceLanguageStatsFunc) PushReturn(r0 *LanguageStats, r1 error) {
+	f.PushHook(func(context.Context, api.RepoID) (*LanguageStats, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceLanguageStatsFunc) nextHook() func(context.Context, api.RepoID) (*LanguageStats, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceLanguageStatsFunc) appendCall(r0 InterfaceLanguageStatsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceLanguageStatsFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceLanguageStatsFunc) History() []InterfaceLanguageStatsFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceLanguageStatsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceLanguageStatsFuncCall is an object that describes an invocation
+// of method LanguageStats on an instance of MockInterface.
+type InterfaceLanguageStatsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *LanguageStats
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceLanguageStatsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceLanguageStatsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// InterfaceLatestSeriesPointTimesFunc describes the behavior when the
+// LatestSeriesPointTimes method of the parent MockInterface instance is
+// invoked.
+type InterfaceLatestSeriesPointTimesFunc struct {
+	defaultHook func(context.Context, []string, time.Time) (map[string]time.Time, error)
+	hooks       []func(context.Context, []string, time.Time) (map[string]time.Time, error)
+	history     []InterfaceLatestSeriesPointTimesFuncCall
+	mutex       sync.Mutex
+}
+
+// LatestSeriesPointTimes delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockInterface) LatestSeriesPointTimes(v0 context.Context, v1 []string, v2 time.Time) (map[string]time.Time, error) {
+	r0, r1 := m.LatestSeriesPointTimesFunc.nextHook()(v0, v1, v2)
+	m.LatestSeriesPointTimesFunc.appendCall(InterfaceLatestSeriesPointTimesFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// LatestSeriesPointTimes method of the parent MockInterface instance is
+// invoked and the hook queue is empty.
+func (f *InterfaceLatestSeriesPointTimesFunc) SetDefaultHook(hook func(context.Context, []string, time.Time) (map[string]time.Time, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// LatestSeriesPointTimes method of the parent MockInterface instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *InterfaceLatestSeriesPointTimesFunc) PushHook(hook func(context.Context, []string, time.Time) (map[string]time.Time, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceLatestSeriesPointTimesFunc) SetDefaultReturn(r0 map[string]time.Time, r1 error) {
+	f.SetDefaultHook(func(context.Context, []string, time.Time) (map[string]time.Time, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceLatestSeriesPointTimesFunc) PushReturn(r0 map[string]time.Time, r1 error) {
+	f.PushHook(func(context.Context, []string, time.Time) (map[string]time.Time, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceLatestSeriesPointTimesFunc) nextHook() func(context.Context, []string, time.Time) (map[string]time.Time, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceLatestSeriesPointTimesFunc) appendCall(r0 InterfaceLatestSeriesPointTimesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceLatestSeriesPointTimesFuncCall
+// objects describing the invocations of this function.
+func (f *InterfaceLatestSeriesPointTimesFunc) History() []InterfaceLatestSeriesPointTimesFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceLatestSeriesPointTimesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceLatestSeriesPointTimesFuncCall is an object that describes an
+// invocation of method LatestSeriesPointTimes on an instance of
+// MockInterface.
+type InterfaceLatestSeriesPointTimesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 time.Time
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 map[string]time.Time
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceLatestSeriesPointTimesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceLatestSeriesPointTimesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// InterfaceListAlertRulesFunc describes the behavior when the
+// ListAlertRules method of the parent MockInterface instance is invoked.
+type InterfaceListAlertRulesFunc struct {
+	defaultHook func(context.Context, ListAlertRulesOpts) ([]AlertRule, error)
+	hooks       []func(context.Context, ListAlertRulesOpts) ([]AlertRule, error)
+	history     []InterfaceListAlertRulesFuncCall
+	mutex       sync.Mutex
+}
+
+// ListAlertRules delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockInterface) ListAlertRules(v0 context.Context, v1 ListAlertRulesOpts) ([]AlertRule, error) {
+	r0, r1 := m.ListAlertRulesFunc.nextHook()(v0, v1)
+	m.ListAlertRulesFunc.appendCall(InterfaceListAlertRulesFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListAlertRules
+// method of the parent MockInterface instance is invoked and the hook queue
+// is empty.
+func (f *InterfaceListAlertRulesFunc) SetDefaultHook(hook func(context.Context, ListAlertRulesOpts) ([]AlertRule, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListAlertRules method of the parent MockInterface instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *InterfaceListAlertRulesFunc) PushHook(hook func(context.Context, ListAlertRulesOpts) ([]AlertRule, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceListAlertRulesFunc) SetDefaultReturn(r0 []AlertRule, r1 error) {
+	f.SetDefaultHook(func(context.Context, ListAlertRulesOpts) ([]AlertRule, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceListAlertRulesFunc) PushReturn(r0 []AlertRule, r1 error) {
+	f.PushHook(func(context.Context, ListAlertRulesOpts) ([]AlertRule, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceListAlertRulesFunc) nextHook() func(context.Context, ListAlertRulesOpts) ([]AlertRule, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceListAlertRulesFunc) appendCall(r0 InterfaceListAlertRulesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceListAlertRulesFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceListAlertRulesFunc) History() []InterfaceListAlertRulesFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceListAlertRulesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceListAlertRulesFuncCall is an object that describes an invocation
+// of method ListAlertRules on an instance of MockInterface.
+type InterfaceListAlertRulesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 ListAlertRulesOpts
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []AlertRule
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceListAlertRulesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceListAlertRulesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// InterfaceRecordSeriesEnqueueFailureFunc describes the behavior when the
+// RecordSeriesEnqueueFailure method of the parent MockInterface instance is
+// invoked.
+type InterfaceRecordSeriesEnqueueFailureFunc struct {
+	defaultHook func(context.Context, string, string, error) error
+	hooks       []func(context.Context, string, string, error) error
+	history     []InterfaceRecordSeriesEnqueueFailureFuncCall
+	mutex       sync.Mutex
+}
+
+// RecordSeriesEnqueueFailure delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockInterface) RecordSeriesEnqueueFailure(v0 context.Context, v1 string, v2 string, v3 error) error {
+	r0 := m.RecordSeriesEnqueueFailureFunc.nextHook()(v0, v1, v2, v3)
+	m.RecordSeriesEnqueueFailureFunc.appendCall(InterfaceRecordSeriesEnqueueFailureFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// RecordSeriesEnqueueFailure method of the parent MockInterface instance is
+// invoked and the hook queue is empty.
+func (f *InterfaceRecordSeriesEnqueueFailureFunc) SetDefaultHook(hook func(context.Context, string, string, error) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RecordSeriesEnqueueFailure method of the parent MockInterface instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *InterfaceRecordSeriesEnqueueFailureFunc) PushHook(hook func(context.Context, string, string, error) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceRecordSeriesEnqueueFailureFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, string, string, error) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceRecordSeriesEnqueueFailureFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, string, string, error) error {
+		return r0
+	})
+}
+
+func (f *InterfaceRecordSeriesEnqueueFailureFunc) nextHook() func(context.Context, string, string, error) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceRecordSeriesEnqueueFailureFunc) appendCall(r0 InterfaceRecordSeriesEnqueueFailureFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceRecordSeriesEnqueueFailureFuncCall
+// objects describing the invocations of this function.
+func (f *InterfaceRecordSeriesEnqueueFailureFunc) History() []InterfaceRecordSeriesEnqueueFailureFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceRecordSeriesEnqueueFailureFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceRecordSeriesEnqueueFailureFuncCall is an object that describes
+// an invocation of method RecordSeriesEnqueueFailure on an instance of
+// MockInterface.
+type InterfaceRecordSeriesEnqueueFailureFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 error
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceRecordSeriesEnqueueFailureFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceRecordSeriesEnqueueFailureFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// InterfaceRecordSeriesPointFunc describes the behavior when the
+// RecordSeriesPoint method of the parent MockInterface instance is invoked.
+type InterfaceRecordSeriesPointFunc struct {
+	defaultHook func(context.Context, RecordSeriesPointArgs) error
+	hooks       []func(context.Context, RecordSeriesPointArgs) error
+	history     []InterfaceRecordSeriesPointFuncCall
+	mutex       sync.Mutex
+}
+
+// RecordSeriesPoint delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockInterface) RecordSeriesPoint(v0 context.Context, v1 RecordSeriesPointArgs) error {
+	r0 := m.RecordSeriesPointFunc.nextHook()(v0, v1)
+	m.RecordSeriesPointFunc.appendCall(InterfaceRecordSeriesPointFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the RecordSeriesPoint
+// method of the parent MockInterface instance is invoked and the hook queue
+// is empty.
+func (f *InterfaceRecordSeriesPointFunc) SetDefaultHook(hook func(context.Context, RecordSeriesPointArgs) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RecordSeriesPoint method of the parent MockInterface instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *InterfaceRecordSeriesPointFunc) PushHook(hook func(context.Context, RecordSeriesPointArgs) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceRecordSeriesPointFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, RecordSeriesPointArgs) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceRecordSeriesPointFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, RecordSeriesPointArgs) error {
+		return r0
+	})
+}
+
+func (f *InterfaceRecordSeriesPointFunc) nextHook() func(context.Context, RecordSeriesPointArgs) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceRecordSeriesPointFunc) appendCall(r0 InterfaceRecordSeriesPointFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceRecordSeriesPointFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceRecordSeriesPointFunc) History() []InterfaceRecordSeriesPointFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceRecordSeriesPointFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceRecordSeriesPointFuncCall is an object that describes an
+// invocation of method RecordSeriesPoint on an instance of MockInterface.
+type InterfaceRecordSeriesPointFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 RecordSeriesPointArgs
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceRecordSeriesPointFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceRecordSeriesPointFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// InterfaceRepoPointsAtFunc describes the behavior when the RepoPointsAt
+// method of the parent MockInterface instance is invoked.
+type InterfaceRepoPointsAtFunc struct {
+	defaultHook func(context.Context, RepoPointsAtOpts) ([]RepoPoint, error)
+	hooks       []func(context.Context, RepoPointsAtOpts) ([]RepoPoint, error)
+	history     []InterfaceRepoPointsAtFuncCall
+	mutex       sync.Mutex
+}
+
+// RepoPointsAt delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockInterface) RepoPointsAt(v0 context.Context, v1 RepoPointsAtOpts) ([]RepoPoint, error) {
+	r0, r1 := m.RepoPointsAtFunc.nextHook()(v0, v1)
+	m.RepoPointsAtFunc.appendCall(InterfaceRepoPointsAtFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the RepoPointsAt method
+// of the parent MockInterface instance is invoked and the hook queue is
+// empty.
+func (f *InterfaceRepoPointsAtFunc) SetDefaultHook(hook func(context.Context, RepoPointsAtOpts) ([]RepoPoint, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RepoPointsAt method of the parent MockInterface instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *InterfaceRepoPointsAtFunc) PushHook(hook func(context.Context, RepoPointsAtOpts) ([]RepoPoint, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceRepoPointsAtFunc) SetDefaultReturn(r0 []RepoPoint, r1 error) {
+	f.SetDefaultHook(func(context.Context, RepoPointsAtOpts) ([]RepoPoint, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceRepoPointsAtFunc) PushReturn(r0 []RepoPoint, r1 error) {
+	f.PushHook(func(context.Context, RepoPointsAtOpts) ([]RepoPoint, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceRepoPointsAtFunc) nextHook() func(context.Context, RepoPointsAtOpts) ([]RepoPoint, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceRepoPointsAtFunc) appendCall(r0 InterfaceRepoPointsAtFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceRepoPointsAtFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceRepoPointsAtFunc) History() []InterfaceRepoPointsAtFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceRepoPointsAtFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceRepoPointsAtFuncCall is an object that describes an invocation
+// of method RepoPointsAt on an instance of MockInterface.
+type InterfaceRepoPointsAtFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 RepoPointsAtOpts
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []RepoPoint
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceRepoPointsAtFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceRepoPointsAtFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// InterfaceResetSeriesCircuitFunc describes the behavior when the
+// ResetSeriesCircuit method of the parent MockInterface instance is
+// invoked.
+type InterfaceResetSeriesCircuitFunc struct {
+	defaultHook func(context.Context, string) (bool, error)
+	hooks       []func(context.Context, string) (bool, error)
+	history     []InterfaceResetSeriesCircuitFuncCall
+	mutex       sync.Mutex
+}
+
+// ResetSeriesCircuit delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockInterface) ResetSeriesCircuit(v0 context.Context, v1 string) (bool, error) {
+	r0, r1 := m.ResetSeriesCircuitFunc.nextHook()(v0, v1)
+	m.ResetSeriesCircuitFunc.appendCall(InterfaceResetSeriesCircuitFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ResetSeriesCircuit
+// method of the parent MockInterface instance is invoked and the hook queue
+// is empty.
+func (f *InterfaceResetSeriesCircuitFunc) SetDefaultHook(hook func(context.Context, string) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ResetSeriesCircuit method of the parent MockInterface instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *InterfaceResetSeriesCircuitFunc) PushHook(hook func(context.Context, string) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceResetSeriesCircuitFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, string) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceResetSeriesCircuitFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, string) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceResetSeriesCircuitFunc) nextHook() func(context.Context, string) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceResetSeriesCircuitFunc) appendCall(r0 InterfaceResetSeriesCircuitFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceResetSeriesCircuitFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceResetSeriesCircuitFunc) History() []InterfaceResetSeriesCircuitFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceResetSeriesCircuitFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceResetSeriesCircuitFuncCall is an object that describes an
+// invocation of method ResetSeriesCircuit on an instance of MockInterface.
+type InterfaceResetSeriesCircuitFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceResetSeriesCircuitFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceResetSeriesCircuitFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// InterfaceSaveBackfillCheckpointFunc describes the behavior when the
+// SaveBackfillCheckpoint method of the parent MockInterface instance is
+// invoked.
+type InterfaceSaveBackfillCheckpointFunc struct {
+	defaultHook func(context.Context, BackfillCheckpoint) error
+	hooks       []func(context.Context, BackfillCheckpoint) error
+	history     []InterfaceSaveBackfillCheckpointFuncCall
+	mutex       sync.Mutex
+}
+
+// SaveBackfillCheckpoint delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockInterface) SaveBackfillCheckpoint(v0 context.Context, v1 BackfillCheckpoint) error {
+	r0 := m.SaveBackfillCheckpointFunc.nextHook()(v0, v1)
+	m.SaveBackfillCheckpointFunc.appendCall(InterfaceSaveBackfillCheckpointFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// SaveBackfillCheckpoint method of the parent MockInterface instance is
+// invoked and the hook queue is empty.
+func (f *InterfaceSaveBackfillCheckpointFunc) SetDefaultHook(hook func(context.Context, BackfillCheckpoint) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SaveBackfillCheckpoint method of the parent MockInterface instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *InterfaceSaveBackfillCheckpointFunc) PushHook(hook func(context.Context, BackfillCheckpoint) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceSaveBackfillCheckpointFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, BackfillCheckpoint) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceSaveBackfillCheckpointFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, BackfillCheckpoint) error {
+		return r0
+	})
+}
+
+func (f *InterfaceSaveBackfillCheckpointFunc) nextHook() func(context.Context, BackfillCheckpoint) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceSaveBackfillCheckpointFunc) appendCall(r0 InterfaceSaveBackfillCheckpointFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceSaveBackfillCheckpointFuncCall
+// objects describing the invocations of this function.
+func (f *InterfaceSaveBackfillCheckpointFunc) History() []InterfaceSaveBackfillCheckpointFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceSaveBackfillCheckpointFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceSaveBackfillCheckpointFuncCall is an object that describes an
+// invocation of method SaveBackfillCheckpoint on an instance of
+// MockInterface.
+type InterfaceSaveBackfillCheckpointFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 BackfillCheckpoint
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceSaveBackfillCheckpointFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceSaveBackfillCheckpointFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// InterfaceSeriesEnqueueFailuresFunc describes the behavior when the
+// SeriesEnqueueFailures method of the parent MockInterface instance is
+// invoked.
+type InterfaceSeriesEnqueueFailuresFunc struct {
+	defaultHook func(context.Context, SeriesEnqueueFailuresOpts) ([]SeriesEnqueueFailure, error)
+	hooks       []func(context.Context, SeriesEnqueueFailuresOpts) ([]SeriesEnqueueFailure, error)
+	history     []InterfaceSeriesEnqueueFailuresFuncCall
+	mutex       sync.Mutex
+}
+
+// SeriesEnqueueFailures delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockInterface) SeriesEnqueueFailures(v0 context.Context, v1 SeriesEnqueueFailuresOpts) ([]SeriesEnqueueFailure, error) {
+	r0, r1 := m.SeriesEnqueueFailuresFunc.nextHook()(v0, v1)
+	m.SeriesEnqueueFailuresFunc.appendCall(InterfaceSeriesEnqueueFailuresFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// SeriesEnqueueFailures method of the parent MockInterface instance is
+// invoked and the hook queue is empty.
+func (f *InterfaceSeriesEnqueueFailuresFunc) SetDefaultHook(hook func(context.Context, SeriesEnqueueFailuresOpts) ([]SeriesEnqueueFailure, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SeriesEnqueueFailures method of the parent MockInterface instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *InterfaceSeriesEnqueueFailuresFunc) PushHook(hook func(context.Context, SeriesEnqueueFailuresOpts) ([]SeriesEnqueueFailure, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceSeriesEnqueueFailuresFunc) SetDefaultReturn(r0 []SeriesEnqueueFailure, r1 error) {
+	f.SetDefaultHook(func(context.Context, SeriesEnqueueFailuresOpts) ([]SeriesEnqueueFailure, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceSeriesEnqueueFailuresFunc) PushReturn(r0 []SeriesEnqueueFailure, r1 error) {
+	f.PushHook(func(context.Context, SeriesEnqueueFailuresOpts) ([]SeriesEnqueueFailure, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceSeriesEnqueueFailuresFunc) nextHook() func(context.Context, SeriesEnqueueFailuresOpts) ([]SeriesEnqueueFailure, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceSeriesEnqueueFailuresFunc) appendCall(r0 InterfaceSeriesEnqueueFailuresFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceSeriesEnqueueFailuresFuncCall
+// objects describing the invocations of this function.
+func (f *InterfaceSeriesEnqueueFailuresFunc) History() []InterfaceSeriesEnqueueFailuresFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceSeriesEnqueueFailuresFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceSeriesEnqueueFailuresFuncCall is an object that describes an
+// invocation of method SeriesEnqueueFailures on an instance of
+// MockInterface.
+type InterfaceSeriesEnqueueFailuresFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 SeriesEnqueueFailuresOpts
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []SeriesEnqueueFailure
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceSeriesEnqueueFailuresFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceSeriesEnqueueFailuresFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// InterfaceSeriesPointsFunc describes the behavior when the SeriesPoints
+// method of the parent MockInterface instance is invoked.
+type InterfaceSeriesPointsFunc struct {
+	defaultHook func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error)
+	hooks       []func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error)
+	history     []InterfaceSeriesPointsFuncCall
+	mutex       sync.Mutex
+}
+
+// SeriesPoints delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockInterface) SeriesPoints(v0 context.Context, v1 SeriesPointsOpts) ([]SeriesPoint, error) {
+	r0, r1 := m.SeriesPointsFunc.nextHook()(v0, v1)
+	m.SeriesPointsFunc.appendCall(InterfaceSeriesPointsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SeriesPoints method
+// of the parent MockInterface instance is invoked and the hook queue is
+// empty.
+func (f *InterfaceSeriesPointsFunc) SetDefaultHook(hook func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SeriesPoints method of the parent MockInterface instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *InterfaceSeriesPointsFunc) PushHook(hook func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceSeriesPointsFunc) SetDefaultReturn(r0 []SeriesPoint, r1 error) {
+	f.SetDefaultHook(func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceSeriesPointsFunc) PushReturn(r0 []SeriesPoint, r1 error) {
+	f.PushHook(func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error) {
+		return r0, r1
+	})
+}
+
+func (f *InterfaceSeriesPointsFunc) nextHook() func(context.Context, SeriesPointsOpts) ([]SeriesPoint, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceSeriesPointsFunc) appendCall(r0 InterfaceSeriesPointsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceSeriesPointsFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceSeriesPointsFunc) History() []InterfaceSeriesPointsFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceSeriesPointsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceSeriesPointsFuncCall is an object that describes an invocation
+// of method SeriesPoints on an instance of MockInterface.
+type InterfaceSeriesPointsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 SeriesPointsOpts
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []SeriesPoint
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceSeriesPointsFuncCall) Args() []interface{} {
 	return []interface{}{c.Arg0, c.Arg1}
 }
 
@@ -383,3 +2109,229 @@ func (c InterfaceSeriesPointsFuncCall) Args() []interface{} {
 func (c InterfaceSeriesPointsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
+
+// InterfaceSeriesRunStatusFunc describes the behavior when the
+// SeriesRunStatus method of the parent MockInterface instance is invoked.
+type InterfaceSeriesRunStatusFunc struct {
+	defaultHook func(context.Context, string) (SeriesRunStatus, bool, error)
+	hooks       []func(context.Context, string) (SeriesRunStatus, bool, error)
+	history     []InterfaceSeriesRunStatusFuncCall
+	mutex       sync.Mutex
+}
+
+// SeriesRunStatus delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockInterface) SeriesRunStatus(v0 context.Context, v1 string) (SeriesRunStatus, bool, error) {
+	r0, r1, r2 := m.SeriesRunStatusFunc.nextHook()(v0, v1)
+	m.SeriesRunStatusFunc.appendCall(InterfaceSeriesRunStatusFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the SeriesRunStatus
+// method of the parent MockInterface instance is invoked and the hook queue
+// is empty.
+func (f *InterfaceSeriesRunStatusFunc) SetDefaultHook(hook func(context.Context, string) (SeriesRunStatus, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SeriesRunStatus method of the parent MockInterface instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *InterfaceSeriesRunStatusFunc) PushHook(hook func(context.Context, string) (SeriesRunStatus, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceSeriesRunStatusFunc) SetDefaultReturn(r0 SeriesRunStatus, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, string) (SeriesRunStatus, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceSeriesRunStatusFunc) PushReturn(r0 SeriesRunStatus, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, string) (SeriesRunStatus, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *InterfaceSeriesRunStatusFunc) nextHook() func(context.Context, string) (SeriesRunStatus, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceSeriesRunStatusFunc) appendCall(r0 InterfaceSeriesRunStatusFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceSeriesRunStatusFuncCall objects
+// describing the invocations of this function.
+func (f *InterfaceSeriesRunStatusFunc) History() []InterfaceSeriesRunStatusFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceSeriesRunStatusFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceSeriesRunStatusFuncCall is an object that describes an
+// invocation of method SeriesRunStatus on an instance of MockInterface.
+type InterfaceSeriesRunStatusFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 SeriesRunStatus
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceSeriesRunStatusFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceSeriesRunStatusFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// InterfaceWebhookDeliveryStatusFunc describes the behavior when the
+// WebhookDeliveryStatus method of the parent MockInterface instance is
+// invoked.
+type InterfaceWebhookDeliveryStatusFunc struct {
+	defaultHook func(context.Context, string) (WebhookDeliveryStatus, bool, error)
+	hooks       []func(context.Context, string) (WebhookDeliveryStatus, bool, error)
+	history     []InterfaceWebhookDeliveryStatusFuncCall
+	mutex       sync.Mutex
+}
+
+// WebhookDeliveryStatus delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockInterface) WebhookDeliveryStatus(v0 context.Context, v1 string) (WebhookDeliveryStatus, bool, error) {
+	r0, r1, r2 := m.WebhookDeliveryStatusFunc.nextHook()(v0, v1)
+	m.WebhookDeliveryStatusFunc.appendCall(InterfaceWebhookDeliveryStatusFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the
+// WebhookDeliveryStatus method of the parent MockInterface instance is
+// invoked and the hook queue is empty.
+func (f *InterfaceWebhookDeliveryStatusFunc) SetDefaultHook(hook func(context.Context, string) (WebhookDeliveryStatus, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// WebhookDeliveryStatus method of the parent MockInterface instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *InterfaceWebhookDeliveryStatusFunc) PushHook(hook func(context.Context, string) (WebhookDeliveryStatus, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *InterfaceWebhookDeliveryStatusFunc) SetDefaultReturn(r0 WebhookDeliveryStatus, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, string) (WebhookDeliveryStatus, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *InterfaceWebhookDeliveryStatusFunc) PushReturn(r0 WebhookDeliveryStatus, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, string) (WebhookDeliveryStatus, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *InterfaceWebhookDeliveryStatusFunc) nextHook() func(context.Context, string) (WebhookDeliveryStatus, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *InterfaceWebhookDeliveryStatusFunc) appendCall(r0 InterfaceWebhookDeliveryStatusFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of InterfaceWebhookDeliveryStatusFuncCall
+// objects describing the invocations of this function.
+func (f *InterfaceWebhookDeliveryStatusFunc) History() []InterfaceWebhookDeliveryStatusFuncCall {
+	f.mutex.Lock()
+	history := make([]InterfaceWebhookDeliveryStatusFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// InterfaceWebhookDeliveryStatusFuncCall is an object that describes an
+// invocation of method WebhookDeliveryStatus on an instance of
+// MockInterface.
+type InterfaceWebhookDeliveryStatusFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 WebhookDeliveryStatus
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c InterfaceWebhookDeliveryStatusFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c InterfaceWebhookDeliveryStatusFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// ResetSeriesData deletes all the recorded data of the given series in a single transaction, so
+// that it is recomputed from scratch: its data points, its dirty queries, and its backfill
+// checkpoints. The series is marked as not backfilled, so that the backfiller enqueues its
+// historical data again. It returns the number of deleted data points.
+func (s *Store) ResetSeriesData(ctx context.Context, seriesID string) (points int, err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	points, _, err = basestore.ScanFirstInt(tx.Query(ctx, sqlf.Sprintf(deleteSeriesPointsFmtstr, seriesID)))
+	if err != nil {
+		return 0, err
+	}
+	for _, q := range []string{deleteSeriesDirtyQueriesFmtstr, deleteSeriesBackfillCheckpointsFmtstr, resetSeriesBackfillFmtstr} {
+		if err := tx.Exec(ctx, sqlf.Sprintf(q, seriesID)); err != nil {
+			return 0, err
+		}
+	}
+	return points, nil
+}
+
+const deleteSeriesPointsFmtstr = `
+-- source: enterprise/internal/insights/store/recompute.go:ResetSeriesData
+WITH deleted AS (
+	DELETE FROM series_points WHERE series_id = %s RETURNING 1
+) SELECT count(*) FROM deleted
+`
+
+const deleteSeriesDirtyQueriesFmtstr = `
+-- source: enterprise/internal/insights/store/recompute.go:ResetSeriesData
+DELETE FROM insight_dirty_queries WHERE series_id = %s
+`
+
+const deleteSeriesBackfillCheckpointsFmtstr = `
+-- source: enterprise/internal/insights/store/recompute.go:ResetSeriesData
+DELETE FROM insight_series_backfill_checkpoints WHERE series_id = %s
+`
+
+const resetSeriesBackfillFmtstr = `
+-- source: enterprise/internal/insights/store/recompute.go:ResetSeriesData
+UPDATE insight_series SET backfill_queued_at = NULL WHERE series_id = %s
+`
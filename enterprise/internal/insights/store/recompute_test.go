@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestResetSeriesData(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	now := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	for _, seriesID := range []string{"one", "one", "two"} {
+		if err := store.RecordSeriesPoint(ctx, RecordSeriesPointArgs{
+			SeriesID: seriesID,
+			Point:    SeriesPoint{Time: now.Add(-24 * time.Hour), Value: 1},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.MarkQueryDirty(ctx, DirtyQuery{SeriesID: seriesID, Query: "errorf", ForTime: now, Reason: "search timed out"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SaveBackfillCheckpoint(ctx, BackfillCheckpoint{SeriesID: seriesID, RepoName: "github.com/sourcegraph/sourcegraph", FrameFrom: now}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := timescale.Exec(`INSERT INTO insight_series (series_id, query, created_at, oldest_historical_at, last_recorded_at,
+                            next_recording_after, recording_interval_days, backfill_queued_at)
+                            VALUES ('one', 'errorf', $1, $1, $1, $1, 1, $1),
+                                   ('two', 'errorf', $1, $1, $1, $1, 1, $1);`, now); err != nil {
+		t.Fatal(err)
+	}
+
+	points, err := store.ResetSeriesData(ctx, "one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if points != 2 {
+		t.Errorf("unexpected number of deleted data points. want=%d have=%d", 2, points)
+	}
+
+	// The data of other series is kept.
+	for seriesID, want := range map[string]int{"one": 0, "two": 1} {
+		count, err := store.CountData(ctx, CountDataOpts{SeriesID: &seriesID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != want {
+			t.Errorf("unexpected number of data points of series %s. want=%d have=%d", seriesID, want, count)
+		}
+	}
+	checkpoints, err := store.BackfillCheckpoints(ctx, []string{"one", "two"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := checkpoints["one"]; ok || len(checkpoints) != 1 {
+		t.Errorf("unexpected backfill checkpoints %v", checkpoints)
+	}
+	var dirty int
+	if err := timescale.QueryRow(`SELECT count(*) FROM insight_dirty_queries WHERE series_id = 'one'`).Scan(&dirty); err != nil {
+		t.Fatal(err)
+	}
+	if dirty != 0 {
+		t.Errorf("unexpected number of dirty queries. want=%d have=%d", 0, dirty)
+	}
+	var unstamped []string
+	rows, err := timescale.Query(`SELECT series_id FROM insight_series WHERE backfill_queued_at IS NULL`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var seriesID string
+		if err := rows.Scan(&seriesID); err != nil {
+			t.Fatal(err)
+		}
+		unstamped = append(unstamped, seriesID)
+	}
+	if len(unstamped) != 1 || unstamped[0] != "one" {
+		t.Errorf("unexpected series to backfill. want=%v have=%v", []string{"one"}, unstamped)
+	}
+}
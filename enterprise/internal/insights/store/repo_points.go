@@ -0,0 +1,153 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// RepoPoint is a data point of a series recorded for a single repository.
+type RepoPoint struct {
+	RepoID   api.RepoID
+	RepoName string
+	Time     time.Time
+	Value    float64
+
+	// Metadata is the JSON metadata recorded with the data point, if any.
+	Metadata []byte
+
+	// Approximate is true if the value was recorded from incomplete search results (see
+	// SeriesPoint).
+	Approximate bool
+}
+
+// LatestRepoPoints returns the data points of the most recent recording of the given series, one
+// per repository. All the points of a recording share its time, so repositories that were not
+// recorded at that time (e.g. because they no longer had any match) are not returned. Points of
+// series generated from capture groups are never returned.
+func (s *Store) LatestRepoPoints(ctx context.Context, seriesID string) ([]RepoPoint, error) {
+	var points []RepoPoint
+	err := s.query(ctx, sqlf.Sprintf(latestRepoPointsFmtstr, seriesID, seriesID), func(sc scanner) error {
+		var point RepoPoint
+		if err := sc.Scan(
+			&point.RepoID,
+			&point.RepoName,
+			&point.Time,
+			&point.Value,
+			&point.Metadata,
+			&point.Approximate,
+		); err != nil {
+			return err
+		}
+		points = append(points, point)
+		return nil
+	})
+	return points, err
+}
+
+const latestRepoPointsFmtstr = `
+-- source: enterprise/internal/insights/store/repo_points.go:LatestRepoPoints
+SELECT DISTINCT ON (sp.repo_id) sp.repo_id, rn.name, sp.time, sp.value, m.metadata, sp.approximate
+FROM series_points sp
+JOIN repo_names rn ON rn.id = sp.repo_name_id
+LEFT JOIN metadata m ON m.id = sp.metadata_id
+WHERE sp.series_id = %s AND sp.repo_id IS NOT NULL AND sp.capture IS NULL
+	AND sp.time = (SELECT max(time) FROM series_points WHERE series_id = %s)
+ORDER BY sp.repo_id
+`
+
+// RepoPointsAtOpts describes options for querying the data points of the repositories of a series
+// at a point in time.
+type RepoPointsAtOpts struct {
+	// SeriesID is the unique series ID to query.
+	SeriesID string
+
+	// Capture, if non-nil, indicates to query the points recorded for this captured value only.
+	Capture *string
+
+	// Time is the point in time to query the data points at.
+	Time time.Time
+
+	// Excluded are the repositories whose data points are not returned.
+	Excluded []api.RepoID
+
+	IncludeRepoRegex string
+	ExcludeRepoRegex string
+
+	// Limit is the number of data points to query, if non-zero.
+	Limit int
+}
+
+// RepoPointsAt returns the data point of each repository that contributes to the value of a series
+// at a point in time, in descending order of value. As for SeriesPoints, the value of a repository
+// is the value of its latest data point recorded at or before that time, so the values of the
+// returned points add up to the value of the series at that time.
+func (s *Store) RepoPointsAt(ctx context.Context, opts RepoPointsAtOpts) ([]RepoPoint, error) {
+	// 🚨 SECURITY: As for SeriesPoints, exclude the repositories the current user cannot see.
+	denylist, err := s.permStore.GetUnauthorizedRepoIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts.Excluded = append(opts.Excluded, denylist...)
+
+	var points []RepoPoint
+	err = s.query(ctx, repoPointsAtQuery(opts), func(sc scanner) error {
+		var point RepoPoint
+		if err := sc.Scan(
+			&point.RepoID,
+			&point.RepoName,
+			&point.Time,
+			&point.Value,
+			&point.Approximate,
+		); err != nil {
+			return err
+		}
+		points = append(points, point)
+		return nil
+	})
+	return points, err
+}
+
+const repoPointsAtFmtstr = `
+-- source: enterprise/internal/insights/store/repo_points.go:RepoPointsAt
+SELECT sub.repo_id, rn.name, sub.time, sub.value, sub.approximate
+FROM (
+	SELECT DISTINCT ON (sp.repo_id) sp.repo_id, sp.repo_name_id, sp.time, sp.value, sp.approximate
+	FROM series_points sp
+	WHERE %s
+	ORDER BY sp.repo_id, sp.time DESC
+) sub
+JOIN repo_names rn ON rn.id = sub.repo_name_id
+WHERE %s
+ORDER BY sub.value DESC, rn.name
+`
+
+func repoPointsAtQuery(opts RepoPointsAtOpts) *sqlf.Query {
+	pointPreds := []*sqlf.Query{
+		sqlf.Sprintf("sp.series_id = %s", opts.SeriesID),
+		sqlf.Sprintf("sp.capture IS NOT DISTINCT FROM %s", opts.Capture),
+		sqlf.Sprintf("sp.time <= %s", opts.Time.UTC()),
+		sqlf.Sprintf("sp.repo_id IS NOT NULL"),
+	}
+	if len(opts.Excluded) > 0 {
+		pointPreds = append(pointPreds, sqlf.Sprintf(fmt.Sprintf("sp.repo_id != all(%v)", values(opts.Excluded))))
+	}
+
+	namePreds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if opts.IncludeRepoRegex != "" {
+		namePreds = append(namePreds, sqlf.Sprintf("rn.name ~ %s", opts.IncludeRepoRegex))
+	}
+	if opts.ExcludeRepoRegex != "" {
+		namePreds = append(namePreds, sqlf.Sprintf("rn.name !~ %s", opts.ExcludeRepoRegex))
+	}
+
+	q := sqlf.Sprintf(repoPointsAtFmtstr, sqlf.Join(pointPreds, "\n AND "), sqlf.Join(namePreds, "\n AND "))
+	if opts.Limit > 0 {
+		q = sqlf.Sprintf("%s LIMIT %s", q, opts.Limit)
+	}
+	return q
+}
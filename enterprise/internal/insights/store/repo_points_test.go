@@ -0,0 +1,156 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestLatestRepoPoints(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := New(timescale, permStore)
+
+	previous := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	latest := previous.Add(24 * time.Hour)
+	capture := "v1"
+	record := func(seriesID string, t time.Time, repoID api.RepoID, repoName string, value float64, capture *string, metadata interface{}) RecordSeriesPointArgs {
+		return RecordSeriesPointArgs{
+			SeriesID: seriesID,
+			Point:    SeriesPoint{Time: t, Value: value, Capture: capture},
+			RepoName: &repoName,
+			RepoID:   &repoID,
+			Metadata: metadata,
+		}
+	}
+	approximate := func(args RecordSeriesPointArgs) RecordSeriesPointArgs {
+		args.Point.Approximate = true
+		return args
+	}
+	for _, args := range []RecordSeriesPointArgs{
+		record("s:one", previous, 1, "repo1", 1, nil, nil),
+		record("s:one", previous, 2, "repo2", 2, nil, nil), // no longer matched in the latest recording
+		record("s:one", latest, 1, "repo1", 3, nil, map[string]string{"asOf": "2021-09-02T00:00:00Z"}),
+		approximate(record("s:one", latest, 3, "repo3", 4, nil, nil)),
+		record("s:one", latest, 3, "repo3", 4, &capture, nil),
+		record("s:two", latest.Add(time.Hour), 1, "repo1", 5, nil, nil),
+	} {
+		if err := store.RecordSeriesPoint(ctx, args); err != nil {
+			t.Fatalf("unexpected error recording point: %s", err)
+		}
+	}
+
+	points, err := store.LatestRepoPoints(ctx, "s:one")
+	if err != nil {
+		t.Fatalf("unexpected error getting latest points: %s", err)
+	}
+	want := []RepoPoint{
+		{RepoID: 1, RepoName: "repo1", Time: latest, Value: 3, Metadata: []byte(`{"asOf": "2021-09-02T00:00:00Z"}`)},
+		{RepoID: 3, RepoName: "repo3", Time: latest, Value: 4, Approximate: true},
+	}
+	if diff := cmp.Diff(want, points, cmp.Transformer("UTC", func(t time.Time) time.Time { return t.UTC() })); diff != "" {
+		t.Errorf("unexpected latest points (-want +got):\n%s", diff)
+	}
+}
+
+func TestRepoPointsAt(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := New(timescale, permStore)
+
+	first := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	record := func(t time.Time, repoID api.RepoID, repoName string, value float64) RecordSeriesPointArgs {
+		return RecordSeriesPointArgs{
+			SeriesID: "s:one",
+			Point:    SeriesPoint{Time: t, Value: value},
+			RepoName: &repoName,
+			RepoID:   &repoID,
+		}
+	}
+	for _, args := range []RecordSeriesPointArgs{
+		record(first, 1, "github.com/a/one", 1),
+		record(first, 2, "github.com/a/two", 2),
+		record(second, 1, "github.com/a/one", 5),
+		record(second, 3, "github.com/b/three", 3),
+	} {
+		if err := store.RecordSeriesPoint(ctx, args); err != nil {
+			t.Fatalf("unexpected error recording point: %s", err)
+		}
+	}
+
+	transform := cmp.Transformer("UTC", func(t time.Time) time.Time { return t.UTC() })
+	for _, testCase := range []struct {
+		name string
+		opts RepoPointsAtOpts
+		want []RepoPoint
+	}{
+		{
+			name: "first",
+			opts: RepoPointsAtOpts{SeriesID: "s:one", Time: first},
+			want: []RepoPoint{
+				{RepoID: 2, RepoName: "github.com/a/two", Time: first, Value: 2},
+				{RepoID: 1, RepoName: "github.com/a/one", Time: first, Value: 1},
+			},
+		},
+		{
+			// Repositories not recorded at the time carry their latest value forward.
+			name: "later",
+			opts: RepoPointsAtOpts{SeriesID: "s:one", Time: second.Add(time.Hour)},
+			want: []RepoPoint{
+				{RepoID: 1, RepoName: "github.com/a/one", Time: second, Value: 5},
+				{RepoID: 3, RepoName: "github.com/b/three", Time: second, Value: 3},
+				{RepoID: 2, RepoName: "github.com/a/two", Time: first, Value: 2},
+			},
+		},
+		{
+			name: "filtered",
+			opts: RepoPointsAtOpts{SeriesID: "s:one", Time: second, IncludeRepoRegex: "github.com/a/", Excluded: []api.RepoID{1}},
+			want: []RepoPoint{
+				{RepoID: 2, RepoName: "github.com/a/two", Time: first, Value: 2},
+			},
+		},
+		{
+			name: "limit",
+			opts: RepoPointsAtOpts{SeriesID: "s:one", Time: second, Limit: 1},
+			want: []RepoPoint{
+				{RepoID: 1, RepoName: "github.com/a/one", Time: second, Value: 5},
+			},
+		},
+		{
+			name: "before any point",
+			opts: RepoPointsAtOpts{SeriesID: "s:one", Time: first.Add(-time.Hour)},
+			want: nil,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			points, err := store.RepoPointsAt(ctx, testCase.opts)
+			if err != nil {
+				t.Fatalf("unexpected error getting points: %s", err)
+			}
+			if diff := cmp.Diff(testCase.want, points, transform); diff != "" {
+				t.Errorf("unexpected points (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// DownsampleSeriesPoints downsamples the data points recorded before the given time to a single
+// data point per series, repository, captured value, and week: the latest one. Data points are
+// observations of a value at a point in time rather than increments, so the latest data point of
+// a week represents the whole week. It returns the number of data points deleted.
+func (s *Store) DownsampleSeriesPoints(ctx context.Context, before time.Time) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Store.Query(ctx, sqlf.Sprintf(downsampleSeriesPointsFmtstr, before.UTC())))
+	return count, err
+}
+
+const downsampleSeriesPointsFmtstr = `
+-- source: enterprise/internal/insights/store/retention.go:DownsampleSeriesPoints
+WITH deleted AS (
+	DELETE FROM series_points sp
+	WHERE sp.time < %s
+	AND EXISTS (
+		SELECT 1 FROM series_points later
+		WHERE later.series_id = sp.series_id
+		AND later.repo_id IS NOT DISTINCT FROM sp.repo_id
+		AND later.capture IS NOT DISTINCT FROM sp.capture
+		AND later.time > sp.time
+		AND time_bucket(INTERVAL '1 week', later.time) = time_bucket(INTERVAL '1 week', sp.time)
+	)
+	RETURNING 1
+) SELECT count(*) FROM deleted
+`
+
+// PruneSeriesPoints deletes the data points recorded before the given time. It returns the number
+// of data points deleted.
+func (s *Store) PruneSeriesPoints(ctx context.Context, before time.Time) (int, error) {
+	count, _, err := basestore.ScanFirstInt(s.Store.Query(ctx, sqlf.Sprintf(pruneSeriesPointsFmtstr, before.UTC())))
+	return count, err
+}
+
+const pruneSeriesPointsFmtstr = `
+-- source: enterprise/internal/insights/store/retention.go:PruneSeriesPoints
+WITH deleted AS (
+	DELETE FROM series_points WHERE time < %s RETURNING 1
+) SELECT count(*) FROM deleted
+`
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/timeutil"
+)
+
+func TestRetention(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, timeutil.Now)
+
+	optionalString := func(v string) *string { return &v }
+	optionalRepoID := func(v api.RepoID) *api.RepoID { return &v }
+
+	// Monday, 4 January 2021.
+	monday := time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	for _, record := range []struct {
+		time  time.Time
+		value float64
+		repo  api.RepoID
+	}{
+		// The first week of data points; the latest one of each repository is kept.
+		{monday, 1, 1},
+		{monday.Add(2 * day), 2, 1},
+		{monday.Add(6 * day), 3, 1},
+		{monday.Add(3 * day), 4, 2},
+		// The second week of data points, which is not downsampled.
+		{monday.Add(7 * day), 5, 1},
+		{monday.Add(8 * day), 6, 1},
+	} {
+		if err := store.RecordSeriesPoint(ctx, RecordSeriesPointArgs{
+			SeriesID: "one",
+			Point:    SeriesPoint{Time: record.time, Value: record.value},
+			RepoName: optionalString("repo"),
+			RepoID:   optionalRepoID(record.repo),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Query the raw data points of the first repository, as SeriesPoints carries observations forward.
+	values := func() []float64 {
+		rows, err := timescale.Query(`SELECT value FROM series_points WHERE repo_id = 1 ORDER BY time`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		var values []float64
+		for rows.Next() {
+			var value float64
+			if err := rows.Scan(&value); err != nil {
+				t.Fatal(err)
+			}
+			values = append(values, value)
+		}
+		return values
+	}
+
+	downsampled, err := store.DownsampleSeriesPoints(ctx, monday.Add(7*day))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if downsampled != 2 {
+		t.Errorf("unexpected number of downsampled points. want=%d have=%d", 2, downsampled)
+	}
+	if diff := cmp.Diff([]float64{3, 5, 6}, values()); diff != "" {
+		t.Errorf("unexpected values after downsampling (-want +got):\n%s", diff)
+	}
+
+	pruned, err := store.PruneSeriesPoints(ctx, monday.Add(7*day))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 2 {
+		t.Errorf("unexpected number of pruned points. want=%d have=%d", 2, pruned)
+	}
+	if diff := cmp.Diff([]float64{5, 6}, values()); diff != "" {
+		t.Errorf("unexpected values after pruning (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// SeriesIDAlias maps the series ID of a series under an older encoding of series IDs to its series
+// ID under the current encoding.
+type SeriesIDAlias struct {
+	OldSeriesID string
+	NewSeriesID string
+
+	// EncodingVersion is the version of the encoding of the old series ID.
+	EncodingVersion int
+
+	CreatedAt time.Time
+
+	// RelinkedAt is the time the data of the old series ID was re-linked to the new series ID, or
+	// nil if it has not been re-linked yet.
+	RelinkedAt *time.Time
+}
+
+// RecordSeriesIDAliases records the given aliases, ignoring aliases of old series IDs that are
+// recorded already. The time they are recorded is taken from the store's clock.
+func (s *Store) RecordSeriesIDAliases(ctx context.Context, aliases []SeriesIDAlias) error {
+	if len(aliases) == 0 {
+		return nil
+	}
+	now := s.now().UTC()
+	values := make([]*sqlf.Query, 0, len(aliases))
+	for _, alias := range aliases {
+		values = append(values, sqlf.Sprintf("(%s, %s, %s, %s)", alias.OldSeriesID, alias.NewSeriesID, alias.EncodingVersion, now))
+	}
+	return s.Exec(ctx, sqlf.Sprintf(recordSeriesIDAliasesFmtstr, sqlf.Join(values, ", ")))
+}
+
+const recordSeriesIDAliasesFmtstr = `
+-- source: enterprise/internal/insights/store/series_id_aliases.go:RecordSeriesIDAliases
+INSERT INTO series_id_aliases (old_series_id, new_series_id, encoding_version, created_at)
+VALUES %s
+ON CONFLICT (old_series_id) DO NOTHING
+`
+
+// SeriesIDAliasesOpts contains query predicates for fetching series ID aliases.
+type SeriesIDAliasesOpts struct {
+	// OldSeriesIDs, if non-empty, restricts the aliases to the aliases of these old series IDs.
+	OldSeriesIDs []string
+
+	// Unrelinked restricts the aliases to the aliases whose data has not been re-linked yet.
+	Unrelinked bool
+}
+
+// SeriesIDAliases returns the series ID aliases matching the given options, oldest first.
+func (s *Store) SeriesIDAliases(ctx context.Context, opts SeriesIDAliasesOpts) ([]SeriesIDAlias, error) {
+	preds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if len(opts.OldSeriesIDs) > 0 {
+		preds = append(preds, sqlf.Sprintf("old_series_id = ANY(%s)", pq.Array(opts.OldSeriesIDs)))
+	}
+	if opts.Unrelinked {
+		preds = append(preds, sqlf.Sprintf("relinked_at IS NULL"))
+	}
+	var aliases []SeriesIDAlias
+	err := s.query(ctx, sqlf.Sprintf(seriesIDAliasesFmtstr, sqlf.Join(preds, "AND")), func(sc scanner) error {
+		var a SeriesIDAlias
+		if err := sc.Scan(&a.OldSeriesID, &a.NewSeriesID, &a.EncodingVersion, &a.CreatedAt, &a.RelinkedAt); err != nil {
+			return err
+		}
+		aliases = append(aliases, a)
+		return nil
+	})
+	return aliases, err
+}
+
+const seriesIDAliasesFmtstr = `
+-- source: enterprise/internal/insights/store/series_id_aliases.go:SeriesIDAliases
+SELECT old_series_id, new_series_id, encoding_version, created_at, relinked_at
+FROM series_id_aliases
+WHERE %s
+ORDER BY created_at, old_series_id
+`
+
+// RelinkSeriesIDAlias re-links the data of the old series ID of the given alias to its new series
+// ID in a single transaction: its data points, its alert rules, and its series definition, unless
+// a series of the new series ID exists already. The series of the old series ID is then
+// unreferenced, and is eventually purged. It returns the number of data points re-linked.
+func (s *Store) RelinkSeriesIDAlias(ctx context.Context, alias SeriesIDAlias) (points int, err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	points, _, err = basestore.ScanFirstInt(tx.Query(ctx, sqlf.Sprintf(relinkSeriesPointsFmtstr, alias.NewSeriesID, alias.OldSeriesID)))
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Exec(ctx, sqlf.Sprintf(relinkAlertRulesFmtstr, alias.NewSeriesID, alias.OldSeriesID)); err != nil {
+		return 0, err
+	}
+	if err := tx.Exec(ctx, sqlf.Sprintf(relinkInsightSeriesFmtstr, alias.NewSeriesID, alias.OldSeriesID, alias.NewSeriesID)); err != nil {
+		return 0, err
+	}
+	if err := tx.Exec(ctx, sqlf.Sprintf(markSeriesIDAliasRelinkedFmtstr, s.now().UTC(), alias.OldSeriesID)); err != nil {
+		return 0, err
+	}
+	return points, nil
+}
+
+const relinkSeriesPointsFmtstr = `
+-- source: enterprise/internal/insights/store/series_id_aliases.go:RelinkSeriesIDAlias
+WITH relinked AS (
+	UPDATE series_points SET series_id = %s WHERE series_id = %s RETURNING 1
+) SELECT count(*) FROM relinked
+`
+
+const relinkAlertRulesFmtstr = `
+-- source: enterprise/internal/insights/store/series_id_aliases.go:RelinkSeriesIDAlias
+UPDATE insight_series_alert_rules SET series_id = %s WHERE series_id = %s
+`
+
+const relinkInsightSeriesFmtstr = `
+-- source: enterprise/internal/insights/store/series_id_aliases.go:RelinkSeriesIDAlias
+UPDATE insight_series SET series_id = %s
+WHERE series_id = %s
+AND NOT EXISTS (SELECT 1 FROM insight_series WHERE series_id = %s)
+`
+
+const markSeriesIDAliasRelinkedFmtstr = `
+-- source: enterprise/internal/insights/store/series_id_aliases.go:RelinkSeriesIDAlias
+UPDATE series_id_aliases SET relinked_at = %s WHERE old_series_id = %s
+`
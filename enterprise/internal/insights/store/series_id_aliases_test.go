@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestSeriesIDAliases(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	now := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	for _, seriesID := range []string{"s:old", "s:old", "s:other"} {
+		if err := store.RecordSeriesPoint(ctx, RecordSeriesPointArgs{
+			SeriesID: seriesID,
+			Point:    SeriesPoint{Time: now.Add(-24 * time.Hour), Value: 1},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := timescale.Exec(`INSERT INTO insight_series (series_id, query, created_at, oldest_historical_at, last_recorded_at, next_recording_after, recording_interval_days) VALUES ('s:old', 'errorf', now(), now(), now(), now(), 1)`); err != nil {
+		t.Fatal(err)
+	}
+
+	createdAt := now
+	if err := store.RecordSeriesIDAliases(ctx, []SeriesIDAlias{{OldSeriesID: "s:old", NewSeriesID: "s:new", EncodingVersion: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+	// Aliases of old series IDs that are recorded already are ignored.
+	if err := store.RecordSeriesIDAliases(ctx, []SeriesIDAlias{{OldSeriesID: "s:old", NewSeriesID: "s:newer", EncodingVersion: 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	aliases, err := store.SeriesIDAliases(ctx, SeriesIDAliasesOpts{Unrelinked: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SeriesIDAlias{{OldSeriesID: "s:old", NewSeriesID: "s:new", EncodingVersion: 1, CreatedAt: createdAt}}
+	if diff := cmp.Diff(want, aliases); diff != "" {
+		t.Errorf("unexpected aliases (-want +got):\n%s", diff)
+	}
+
+	points, err := store.RelinkSeriesIDAlias(ctx, aliases[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if points != 2 {
+		t.Errorf("unexpected number of re-linked data points. want=%d have=%d", 2, points)
+	}
+	for seriesID, want := range map[string]int{"s:old": 0, "s:new": 2, "s:other": 1} {
+		count, err := store.CountData(ctx, CountDataOpts{SeriesID: &seriesID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != want {
+			t.Errorf("unexpected number of data points of series %s. want=%d have=%d", seriesID, want, count)
+		}
+	}
+	var seriesID string
+	if err := timescale.QueryRow(`SELECT series_id FROM insight_series`).Scan(&seriesID); err != nil {
+		t.Fatal(err)
+	}
+	if seriesID != "s:new" {
+		t.Errorf("unexpected series ID of series definition. want=%q have=%q", "s:new", seriesID)
+	}
+
+	aliases, err = store.SeriesIDAliases(ctx, SeriesIDAliasesOpts{Unrelinked: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aliases) != 0 {
+		t.Errorf("unexpected number of aliases that have not been re-linked. want=%d have=%d", 0, len(aliases))
+	}
+	aliases, err = store.SeriesIDAliases(ctx, SeriesIDAliasesOpts{OldSeriesIDs: []string{"s:old"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	relinkedAt := now
+	want[0].RelinkedAt = &relinkedAt
+	if diff := cmp.Diff(want, aliases); diff != "" {
+		t.Errorf("unexpected re-linked aliases (-want +got):\n%s", diff)
+	}
+}
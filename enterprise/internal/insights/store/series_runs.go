@@ -0,0 +1,177 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// SeriesRun describes the outcome of a single query runner job recording data points of a series.
+type SeriesRun struct {
+	SeriesID string
+
+	// Err is the error of the job, or nil if it succeeded.
+	Err error
+
+	// Full is true if the job recorded the present-day data of the series from a full search, as
+	// opposed to an incremental search of the repositories that changed since the previous job.
+	Full bool
+
+	// OverBudget is true if the searches of the job took longer than the time budget of a series.
+	// The circuit of the series is broken once BreakAfter consecutive jobs are over budget, unless
+	// BreakAfter is zero.
+	OverBudget bool
+	BreakAfter int
+}
+
+// SeriesRunStatus describes the most recent query runner jobs recording data points of a series.
+type SeriesRunStatus struct {
+	SeriesID            string
+	LastAttemptAt       time.Time
+	LastSuccessAt       *time.Time
+	LastError           *string
+	ConsecutiveFailures int
+
+	// LastFullRunAt is the time of the most recent successful job that recorded the present-day
+	// data of the series from a full search, if any.
+	LastFullRunAt *time.Time
+
+	// ConsecutiveOverBudget is the number of consecutive jobs whose searches took longer than the
+	// time budget of a series, and CircuitBrokenAt is the time the series was paused for it, if it
+	// is paused.
+	ConsecutiveOverBudget int
+	CircuitBrokenAt       *time.Time
+}
+
+// RecordSeriesRun records the outcome of a query runner job recording data points of a series. The
+// time of the job is taken from the store's clock.
+func (s *Store) RecordSeriesRun(ctx context.Context, r SeriesRun) error {
+	now := s.now().UTC()
+
+	var (
+		lastSuccessAt       *time.Time
+		lastFullRunAt       *time.Time
+		lastError           *string
+		consecutiveFailures = 0
+		overBudget          = 0
+		circuitBrokenAt     *time.Time
+	)
+	if r.Err == nil {
+		lastSuccessAt = &now
+		if r.Full {
+			lastFullRunAt = &now
+		}
+	} else {
+		message := r.Err.Error()
+		lastError = &message
+		consecutiveFailures = 1
+	}
+	if r.OverBudget {
+		overBudget = 1
+		if r.BreakAfter == 1 {
+			circuitBrokenAt = &now
+		}
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(
+		recordSeriesRunFmtstr,
+		r.SeriesID,          // series_id
+		now,                 // last_attempt_at
+		lastSuccessAt,       // last_success_at
+		lastError,           // last_error
+		consecutiveFailures, // consecutive_failures
+		lastFullRunAt,       // last_full_run_at
+		overBudget,          // consecutive_over_budget
+		circuitBrokenAt,     // circuit_broken_at
+		r.BreakAfter,        // consecutive jobs over budget that break the circuit
+		r.BreakAfter,
+	))
+}
+
+const recordSeriesRunFmtstr = `
+-- source: enterprise/internal/insights/store/series_runs.go:RecordSeriesRun
+INSERT INTO insight_series_runs(series_id, last_attempt_at, last_success_at, last_error, consecutive_failures, last_full_run_at, consecutive_over_budget, circuit_broken_at)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+ON CONFLICT (series_id) DO UPDATE SET
+	last_attempt_at = EXCLUDED.last_attempt_at,
+	last_success_at = COALESCE(EXCLUDED.last_success_at, insight_series_runs.last_success_at),
+	last_error = EXCLUDED.last_error,
+	consecutive_failures = CASE
+		WHEN EXCLUDED.consecutive_failures = 0 THEN 0
+		ELSE insight_series_runs.consecutive_failures + 1
+	END,
+	last_full_run_at = COALESCE(EXCLUDED.last_full_run_at, insight_series_runs.last_full_run_at),
+	consecutive_over_budget = CASE
+		WHEN EXCLUDED.consecutive_over_budget = 0 THEN 0
+		ELSE insight_series_runs.consecutive_over_budget + 1
+	END,
+	circuit_broken_at = CASE
+		WHEN insight_series_runs.circuit_broken_at IS NOT NULL THEN insight_series_runs.circuit_broken_at
+		WHEN EXCLUDED.consecutive_over_budget > 0 AND %s > 0 AND insight_series_runs.consecutive_over_budget + 1 >= %s THEN EXCLUDED.last_attempt_at
+	END
+`
+
+// SeriesRunStatus returns the status of the query runner jobs of the given series. It returns false
+// if no job of the series was handled yet.
+func (s *Store) SeriesRunStatus(ctx context.Context, seriesID string) (_ SeriesRunStatus, ok bool, err error) {
+	var status SeriesRunStatus
+	err = s.query(ctx, sqlf.Sprintf(seriesRunStatusFmtstr, seriesID), func(sc scanner) error {
+		ok = true
+		return sc.Scan(
+			&status.SeriesID,
+			&status.LastAttemptAt,
+			&status.LastSuccessAt,
+			&status.LastError,
+			&status.ConsecutiveFailures,
+			&status.LastFullRunAt,
+			&status.ConsecutiveOverBudget,
+			&status.CircuitBrokenAt,
+		)
+	})
+	return status, ok, err
+}
+
+const seriesRunStatusFmtstr = `
+-- source: enterprise/internal/insights/store/series_runs.go:SeriesRunStatus
+SELECT series_id, last_attempt_at, last_success_at, last_error, consecutive_failures, last_full_run_at, consecutive_over_budget, circuit_broken_at
+FROM insight_series_runs
+WHERE series_id = %s
+`
+
+// CircuitBrokenSeries returns the IDs of the given series that are paused because their circuit is
+// broken (see SeriesRun).
+func (s *Store) CircuitBrokenSeries(ctx context.Context, seriesIDs []string) (_ map[string]bool, err error) {
+	broken := make(map[string]bool)
+	err = s.query(ctx, sqlf.Sprintf(circuitBrokenSeriesFmtstr, pq.Array(seriesIDs)), func(sc scanner) error {
+		var seriesID string
+		if err := sc.Scan(&seriesID); err != nil {
+			return err
+		}
+		broken[seriesID] = true
+		return nil
+	})
+	return broken, err
+}
+
+const circuitBrokenSeriesFmtstr = `
+-- source: enterprise/internal/insights/store/series_runs.go:CircuitBrokenSeries
+SELECT series_id FROM insight_series_runs WHERE series_id = ANY(%s) AND circuit_broken_at IS NOT NULL
+`
+
+// ResetSeriesCircuit resumes the given series if its circuit is broken, and resets the count of its
+// jobs over budget. It reports whether the series was paused.
+func (s *Store) ResetSeriesCircuit(ctx context.Context, seriesID string) (bool, error) {
+	_, paused, err := basestore.ScanFirstString(s.Query(ctx, sqlf.Sprintf(resetSeriesCircuitFmtstr, seriesID)))
+	return paused, err
+}
+
+const resetSeriesCircuitFmtstr = `
+-- source: enterprise/internal/insights/store/series_runs.go:ResetSeriesCircuit
+UPDATE insight_series_runs SET consecutive_over_budget = 0, circuit_broken_at = NULL
+WHERE series_id = %s AND circuit_broken_at IS NOT NULL
+RETURNING series_id
+`
@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestSeriesRuns(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	now := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	if _, ok, err := store.SeriesRunStatus(ctx, "s:one"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatalf("unexpected run status of a series that was never recorded")
+	}
+
+	successAt := now
+	if err := store.RecordSeriesRun(ctx, SeriesRun{SeriesID: "s:one", Full: true}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	if err := store.RecordSeriesRun(ctx, SeriesRun{SeriesID: "s:one", Err: errors.New("search timed out"), Full: true}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	if err := store.RecordSeriesRun(ctx, SeriesRun{SeriesID: "s:one", Err: errors.New("invalid query")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Failures keep the time of the last success and full run, and are counted until the next
+	// success.
+	status, found, err := store.SeriesRunStatus(ctx, "s:one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatalf("expected run status")
+	}
+	lastError := "invalid query"
+	want := SeriesRunStatus{
+		SeriesID:            "s:one",
+		LastAttemptAt:       now,
+		LastSuccessAt:       &successAt,
+		LastError:           &lastError,
+		ConsecutiveFailures: 2,
+		LastFullRunAt:       &successAt,
+	}
+	if diff := cmp.Diff(want, status); diff != "" {
+		t.Fatalf("unexpected run status (-want +got):\n%s", diff)
+	}
+
+	now = now.Add(time.Hour)
+	if err := store.RecordSeriesRun(ctx, SeriesRun{SeriesID: "s:one"}); err != nil {
+		t.Fatal(err)
+	}
+	status, _, err = store.SeriesRunStatus(ctx, "s:one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = SeriesRunStatus{
+		SeriesID:      "s:one",
+		LastAttemptAt: now,
+		LastSuccessAt: &now,
+		LastFullRunAt: &successAt,
+	}
+	if diff := cmp.Diff(want, status); diff != "" {
+		t.Fatalf("unexpected run status (-want +got):\n%s", diff)
+	}
+}
+
+func TestSeriesRunsCircuitBreaker(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	now := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	record := func(seriesID string, overBudget bool) {
+		now = now.Add(time.Hour)
+		if err := store.RecordSeriesRun(ctx, SeriesRun{SeriesID: seriesID, OverBudget: overBudget, BreakAfter: 3}); err != nil {
+			t.Fatalf("unexpected error recording series run: %s", err)
+		}
+	}
+	broken := func() map[string]bool {
+		broken, err := store.CircuitBrokenSeries(ctx, []string{"s:slow", "s:fast"})
+		if err != nil {
+			t.Fatalf("unexpected error listing circuit broken series: %s", err)
+		}
+		return broken
+	}
+
+	// A run within budget resets the count of runs over budget.
+	record("s:slow", true)
+	record("s:slow", true)
+	record("s:slow", false)
+	record("s:slow", true)
+	record("s:slow", true)
+	record("s:fast", false)
+	if diff := cmp.Diff(map[string]bool{}, broken()); diff != "" {
+		t.Fatalf("unexpected circuit broken series (-want +got):\n%s", diff)
+	}
+
+	record("s:slow", true)
+	brokenAt := now
+	record("s:slow", true)
+	if diff := cmp.Diff(map[string]bool{"s:slow": true}, broken()); diff != "" {
+		t.Fatalf("unexpected circuit broken series (-want +got):\n%s", diff)
+	}
+	status, _, err := store.SeriesRunStatus(ctx, "s:slow")
+	if err != nil {
+		t.Fatalf("unexpected error getting run status: %s", err)
+	}
+	if status.ConsecutiveOverBudget != 4 || status.CircuitBrokenAt == nil || !status.CircuitBrokenAt.Equal(brokenAt) {
+		t.Errorf("unexpected run status. want 4 runs over budget and broken at %s have=%+v", brokenAt, status)
+	}
+
+	// Only paused series are reported as resumed.
+	for _, testCase := range []struct {
+		seriesID   string
+		wantPaused bool
+	}{
+		{seriesID: "s:slow", wantPaused: true},
+		{seriesID: "s:slow", wantPaused: false},
+		{seriesID: "s:fast", wantPaused: false},
+	} {
+		paused, err := store.ResetSeriesCircuit(ctx, testCase.seriesID)
+		if err != nil {
+			t.Fatalf("unexpected error resetting circuit: %s", err)
+		}
+		if paused != testCase.wantPaused {
+			t.Errorf("unexpected paused series %q. want=%t have=%t", testCase.seriesID, testCase.wantPaused, paused)
+		}
+	}
+	if diff := cmp.Diff(map[string]bool{}, broken()); diff != "" {
+		t.Fatalf("unexpected circuit broken series after reset (-want +got):\n%s", diff)
+	}
+}
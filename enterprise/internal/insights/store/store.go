@@ -10,6 +10,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
@@ -23,6 +24,22 @@ type Interface interface {
 	SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]SeriesPoint, error)
 	RecordSeriesPoint(ctx context.Context, v RecordSeriesPointArgs) error
 	CountData(ctx context.Context, opts CountDataOpts) (int, error)
+	CaptureValues(ctx context.Context, seriesID string) ([]string, error)
+	LatestSeriesPointTimes(ctx context.Context, seriesIDs []string, since time.Time) (map[string]time.Time, error)
+	LanguageStats(ctx context.Context, repoID api.RepoID) (*LanguageStats, error)
+	RepoPointsAt(ctx context.Context, opts RepoPointsAtOpts) ([]RepoPoint, error)
+	SeriesRunStatus(ctx context.Context, seriesID string) (SeriesRunStatus, bool, error)
+	ResetSeriesCircuit(ctx context.Context, seriesID string) (bool, error)
+	WebhookDeliveryStatus(ctx context.Context, seriesID string) (WebhookDeliveryStatus, bool, error)
+	CreateAlertRule(ctx context.Context, rule AlertRule) (AlertRule, error)
+	DeleteAlertRule(ctx context.Context, id int, userID int32) (bool, error)
+	ListAlertRules(ctx context.Context, opts ListAlertRulesOpts) ([]AlertRule, error)
+	BackfillCheckpoints(ctx context.Context, seriesIDs []string) (map[string]BackfillCheckpoint, error)
+	SaveBackfillCheckpoint(ctx context.Context, c BackfillCheckpoint) error
+	ClearBackfillCheckpoints(ctx context.Context, seriesIDs []string) error
+	RecordSeriesEnqueueFailure(ctx context.Context, seriesID, cause string, err error) error
+	ClearSeriesEnqueueFailures(ctx context.Context, seriesIDs []string) error
+	SeriesEnqueueFailures(ctx context.Context, opts SeriesEnqueueFailuresOpts) ([]SeriesEnqueueFailure, error)
 }
 
 var _ Interface = &Store{}
@@ -72,10 +89,25 @@ type SeriesPoint struct {
 	Time     time.Time
 	Value    float64
 	Metadata []byte
+
+	// Capture is the value captured by the regexp capture group of a series generated from
+	// capture groups, or nil for other series.
+	Capture *string
+
+	// Approximate is true if the value was recorded from incomplete search results, e.g. because
+	// the search timed out in some repositories, and so may undercount.
+	Approximate bool
 }
 
 func (s *SeriesPoint) String() string {
-	return fmt.Sprintf("SeriesPoint{Time: %q, Value: %v, Metadata: %s}", s.Time, s.Value, s.Metadata)
+	var approximate string
+	if s.Approximate {
+		approximate = ", Approximate: true"
+	}
+	if s.Capture != nil {
+		return fmt.Sprintf("SeriesPoint{Time: %q, Value: %v, Metadata: %s, Capture: %q%s}", s.Time, s.Value, s.Metadata, *s.Capture, approximate)
+	}
+	return fmt.Sprintf("SeriesPoint{Time: %q, Value: %v, Metadata: %s%s}", s.Time, s.Value, s.Metadata, approximate)
 }
 
 // SeriesPointsOpts describes options for querying insights' series data points.
@@ -100,6 +132,10 @@ type SeriesPointsOpts struct {
 
 	// Limit is the number of data points to query, if non-zero.
 	Limit int
+
+	// Capture, if non-nil, indicates to filter results to only points recorded for this
+	// captured value.
+	Capture *string
 }
 
 // SeriesPoints queries data points over time for a specific insights' series.
@@ -117,6 +153,11 @@ func (s *Store) SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]Seri
 	//
 	// Since Code Insights is in a different database, we can't trivially join the repo table directly, so this approach is preferred.
 
+	//
+	// 🚨 SECURITY: Data points recorded without a repository (e.g. those of derived series) may
+	// aggregate the data points of every repository, so they are only returned when no repository
+	// is excluded. seriesPointsQuery excludes them whenever results are filtered by repository. 🚨
+
 	denylist, err := s.permStore.GetUnauthorizedRepoIDs(ctx)
 	if err != nil {
 		return []SeriesPoint{}, err
@@ -131,6 +172,8 @@ func (s *Store) SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]Seri
 			&point.Time,
 			&point.Value,
 			&point.Metadata,
+			&point.Capture,
+			&point.Approximate,
 		)
 		if err != nil {
 			return err
@@ -143,21 +186,21 @@ func (s *Store) SeriesPoints(ctx context.Context, opts SeriesPointsOpts) ([]Seri
 
 // This query is a barebones implementation of per-repo per-series last-observation carried forward. Long term
 // this query is too expensive to run in real-time and should be moved to a materialized view.
-const lastObservationCarriedPointsSql = `select sub.series_id, sub.interval_time, sum(value) as value, null as metadata from (WITH target_times AS (SELECT *
+const lastObservationCarriedPointsSql = `select sub.series_id, sub.interval_time, sum(value) as value, null as metadata, sub.capture, bool_or(sub.approximate) as approximate from (WITH target_times AS (SELECT *
 FROM GENERATE_SERIES(CURRENT_TIMESTAMP::date - INTERVAL '26 weeks', CURRENT_TIMESTAMP::date, '2 weeks') as interval_time)
-SELECT sub.series_id, sub.repo_id, sub.value, interval_time, repo_name_id
-FROM (select distinct repo_id, series_id from series_points) as r
+SELECT sub.series_id, sub.repo_id, sub.value, interval_time, repo_name_id, sub.capture, sub.approximate
+FROM (select distinct repo_id, series_id, capture from series_points) as r
 cross join target_times tt
 join LATERAL (
     select sp.* from series_points as sp
-    where sp.repo_id = r.repo_id and sp.time <= tt.interval_time and sp.series_id = r.series_id
+    where sp.repo_id IS NOT DISTINCT FROM r.repo_id and sp.time <= tt.interval_time and sp.series_id = r.series_id and sp.capture IS NOT DISTINCT FROM r.capture
     order by time DESC
     limit 1
-    ) sub on sub.repo_id = r.repo_id and r.series_id = sub.series_id
+    ) sub on sub.repo_id IS NOT DISTINCT FROM r.repo_id and r.series_id = sub.series_id
 order by interval_time, repo_id) as sub
-join repo_names rn on sub.repo_name_id = rn.id
+left join repo_names rn on sub.repo_name_id = rn.id
 where %s
-group by sub.series_id, sub.interval_time
+group by sub.series_id, sub.interval_time, sub.capture
 order by interval_time desc
 `
 
@@ -178,7 +221,8 @@ order by interval_time desc
 // 5. Intervals that are missing a data point will need to resolve the last observation and carry it forward.
 //
 // Additionally, it is important to note that there may be data points associated with a repo OR not
-// associated with a repo at all (global.)
+// associated with a repo at all (global.) Global data points cannot be attributed to repositories,
+// so they are excluded by every filter on repositories (whose predicates are NULL for them.)
 
 func seriesPointsQuery(opts SeriesPointsOpts) *sqlf.Query {
 	preds := []*sqlf.Query{}
@@ -189,6 +233,9 @@ func seriesPointsQuery(opts SeriesPointsOpts) *sqlf.Query {
 	if opts.RepoID != nil {
 		preds = append(preds, sqlf.Sprintf("repo_id = %d", int32(*opts.RepoID)))
 	}
+	if opts.Capture != nil {
+		preds = append(preds, sqlf.Sprintf("capture = %s", *opts.Capture))
+	}
 	if opts.From != nil {
 		preds = append(preds, sqlf.Sprintf("interval_time >= %s", *opts.From))
 	}
@@ -292,6 +339,42 @@ func countDataQuery(opts CountDataOpts) *sqlf.Query {
 	)
 }
 
+// CaptureValues returns the distinct values recorded for the given series generated from capture
+// groups, in lexicographic order. Each value forms its own series.
+func (s *Store) CaptureValues(ctx context.Context, seriesID string) ([]string, error) {
+	return basestore.ScanStrings(s.Store.Query(ctx, sqlf.Sprintf(captureValuesFmtstr, seriesID)))
+}
+
+const captureValuesFmtstr = `
+-- source: enterprise/internal/insights/store/store.go:CaptureValues
+SELECT DISTINCT capture FROM series_points WHERE series_id = %s AND capture IS NOT NULL ORDER BY capture
+`
+
+// LatestSeriesPointTimes returns the time of the latest data point of each of the given series,
+// for the series that have data points at or after the given time.
+func (s *Store) LatestSeriesPointTimes(ctx context.Context, seriesIDs []string, since time.Time) (map[string]time.Time, error) {
+	latest := make(map[string]time.Time, len(seriesIDs))
+	err := s.query(ctx, sqlf.Sprintf(latestSeriesPointTimesFmtstr, pq.Array(seriesIDs), since), func(sc scanner) error {
+		var (
+			seriesID string
+			t        time.Time
+		)
+		if err := sc.Scan(&seriesID, &t); err != nil {
+			return err
+		}
+		latest[seriesID] = t
+		return nil
+	})
+	return latest, err
+}
+
+const latestSeriesPointTimesFmtstr = `
+-- source: enterprise/internal/insights/store/store.go:LatestSeriesPointTimes
+SELECT series_id, MAX(time) FROM series_points
+WHERE series_id = ANY(%s) AND time >= %s
+GROUP BY series_id
+`
+
 // RecordSeriesPointArgs describes arguments for the RecordSeriesPoint method.
 type RecordSeriesPointArgs struct {
 	// SeriesID is the unique series ID to query. It should describe the series of data uniquely,
@@ -364,13 +447,15 @@ func (s *Store) RecordSeriesPoint(ctx context.Context, v RecordSeriesPointArgs)
 	// Insert the actual data point.
 	return txStore.Exec(ctx, sqlf.Sprintf(
 		recordSeriesPointFmtstr,
-		v.SeriesID,         // series_id
-		v.Point.Time.UTC(), // time
-		v.Point.Value,      // value
-		metadataID,         // metadata_id
-		v.RepoID,           // repo_id
-		repoNameID,         // repo_name_id
-		repoNameID,         // original_repo_name_id
+		v.SeriesID,          // series_id
+		v.Point.Time.UTC(),  // time
+		v.Point.Value,       // value
+		metadataID,          // metadata_id
+		v.RepoID,            // repo_id
+		repoNameID,          // repo_name_id
+		repoNameID,          // original_repo_name_id
+		v.Point.Capture,     // capture
+		v.Point.Approximate, // approximate
 	))
 }
 
@@ -409,8 +494,10 @@ INSERT INTO series_points(
 	metadata_id,
 	repo_id,
 	repo_name_id,
-	original_repo_name_id)
-VALUES (%s, %s, %s, %s, %s, %s, %s);
+	original_repo_name_id,
+	capture,
+	approximate)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s);
 `
 
 func (s *Store) query(ctx context.Context, q *sqlf.Query, sc scanFunc) error {
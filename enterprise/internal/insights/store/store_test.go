@@ -330,6 +330,82 @@ func TestRecordSeriesPoints(t *testing.T) {
 	// autogold.Want("forOriginalRepoNamePoints[0].String()", nil).Equal(t, forOriginalRepoNamePoints[0].String())
 }
 
+func TestRecordSeriesPointsCapture(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	clock := timeutil.Now
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	optionalString := func(v string) *string { return &v }
+	optionalRepoID := func(v api.RepoID) *api.RepoID { return &v }
+
+	current := time.Now().Truncate(24 * time.Hour)
+
+	for _, record := range []RecordSeriesPointArgs{
+		{
+			SeriesID: "one",
+			Point:    SeriesPoint{Time: current, Value: 1, Capture: optionalString("1.16")},
+			RepoName: optionalString("repo1"),
+			RepoID:   optionalRepoID(3),
+		},
+		{
+			SeriesID: "one",
+			Point:    SeriesPoint{Time: current, Value: 2, Capture: optionalString("1.15")},
+			RepoName: optionalString("repo1"),
+			RepoID:   optionalRepoID(3),
+		},
+		{
+			SeriesID: "one",
+			Point:    SeriesPoint{Time: current, Value: 4, Capture: optionalString("1.16")},
+			RepoName: optionalString("repo2"),
+			RepoID:   optionalRepoID(4),
+		},
+		{
+			SeriesID: "two",
+			Point:    SeriesPoint{Time: current, Value: 8},
+			RepoName: optionalString("repo1"),
+			RepoID:   optionalRepoID(3),
+		},
+	} {
+		if err := store.RecordSeriesPoint(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	captures, err := store.CaptureValues(ctx, "one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"1.15", "1.16"}, captures); diff != "" {
+		t.Errorf("unexpected capture values (-want +got):\n%s", diff)
+	}
+
+	captures, err = store.CaptureValues(ctx, "two")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(captures) != 0 {
+		t.Errorf("unexpected capture values for series without captures: %v", captures)
+	}
+
+	// Points of each captured value are summed across repositories separately.
+	points, err := store.SeriesPoints(ctx, SeriesPointsOpts{SeriesID: optionalString("one"), Capture: optionalString("1.16"), Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SeriesPoint{{SeriesID: "one", Time: current, Value: 5, Capture: optionalString("1.16")}}
+	if diff := cmp.Diff(want, points); diff != "" {
+		t.Errorf("unexpected points (-want +got):\n%s", diff)
+	}
+}
+
 func TestValues(t *testing.T) {
 	ids := []api.RepoID{1, 2, 3, 4, 5, 6}
 	got := values(ids)
@@ -339,3 +415,38 @@ func TestValues(t *testing.T) {
 		t.Errorf("unexpected values string: %v", diff)
 	}
 }
+
+func TestLatestSeriesPointTimes(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	clock := timeutil.Now
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	since := time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)
+	for _, record := range []RecordSeriesPointArgs{
+		{SeriesID: "recent", Point: SeriesPoint{Time: since.Add(time.Hour), Value: 1}},
+		{SeriesID: "recent", Point: SeriesPoint{Time: since.Add(2 * time.Hour), Value: 2}},
+		{SeriesID: "old", Point: SeriesPoint{Time: since.Add(-time.Hour), Value: 1}},
+		{SeriesID: "other", Point: SeriesPoint{Time: since.Add(time.Hour), Value: 1}},
+	} {
+		if err := store.RecordSeriesPoint(ctx, record); err != nil {
+			t.Fatalf("unexpected error recording series point: %s", err)
+		}
+	}
+
+	latest, err := store.LatestSeriesPointTimes(ctx, []string{"recent", "old", "unknown"}, since)
+	if err != nil {
+		t.Fatalf("unexpected error getting latest series point times: %s", err)
+	}
+	want := map[string]time.Time{"recent": since.Add(2 * time.Hour)}
+	if diff := cmp.Diff(want, latest); diff != "" {
+		t.Errorf("unexpected latest series point times (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,98 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+// WebhookDelivery describes a single request sent to the webhook of a webhook series.
+type WebhookDelivery struct {
+	SeriesID string
+
+	// StatusCode is the HTTP status code of the response, or nil if the webhook did not respond.
+	StatusCode *int
+
+	// Err is the error of the request, or nil if a data point was recorded from its response.
+	Err error
+}
+
+// WebhookDeliveryStatus describes the most recent requests sent to the webhook of a webhook series.
+type WebhookDeliveryStatus struct {
+	SeriesID            string
+	LastAttemptAt       time.Time
+	LastSuccessAt       *time.Time
+	LastStatusCode      *int
+	LastError           *string
+	ConsecutiveFailures int
+}
+
+// RecordWebhookDelivery records the outcome of a request sent to the webhook of a webhook series.
+// The time of the request is taken from the store's clock.
+func (s *Store) RecordWebhookDelivery(ctx context.Context, d WebhookDelivery) error {
+	now := s.now().UTC()
+
+	var (
+		lastSuccessAt       *time.Time
+		lastError           *string
+		consecutiveFailures = 0
+	)
+	if d.Err == nil {
+		lastSuccessAt = &now
+	} else {
+		message := d.Err.Error()
+		lastError = &message
+		consecutiveFailures = 1
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(
+		recordWebhookDeliveryFmtstr,
+		d.SeriesID,          // series_id
+		now,                 // last_attempt_at
+		lastSuccessAt,       // last_success_at
+		d.StatusCode,        // last_status_code
+		lastError,           // last_error
+		consecutiveFailures, // consecutive_failures
+	))
+}
+
+const recordWebhookDeliveryFmtstr = `
+-- source: enterprise/internal/insights/store/webhook_deliveries.go:RecordWebhookDelivery
+INSERT INTO insight_webhook_deliveries(series_id, last_attempt_at, last_success_at, last_status_code, last_error, consecutive_failures)
+VALUES (%s, %s, %s, %s, %s, %s)
+ON CONFLICT (series_id) DO UPDATE SET
+	last_attempt_at = EXCLUDED.last_attempt_at,
+	last_success_at = COALESCE(EXCLUDED.last_success_at, insight_webhook_deliveries.last_success_at),
+	last_status_code = EXCLUDED.last_status_code,
+	last_error = EXCLUDED.last_error,
+	consecutive_failures = CASE
+		WHEN EXCLUDED.consecutive_failures = 0 THEN 0
+		ELSE insight_webhook_deliveries.consecutive_failures + 1
+	END
+`
+
+// WebhookDeliveryStatus returns the delivery status of the webhook of the given series. It returns
+// false if no request was sent to the webhook yet.
+func (s *Store) WebhookDeliveryStatus(ctx context.Context, seriesID string) (_ WebhookDeliveryStatus, ok bool, err error) {
+	var status WebhookDeliveryStatus
+	err = s.query(ctx, sqlf.Sprintf(webhookDeliveryStatusFmtstr, seriesID), func(sc scanner) error {
+		ok = true
+		return sc.Scan(
+			&status.SeriesID,
+			&status.LastAttemptAt,
+			&status.LastSuccessAt,
+			&status.LastStatusCode,
+			&status.LastError,
+			&status.ConsecutiveFailures,
+		)
+	})
+	return status, ok, err
+}
+
+const webhookDeliveryStatusFmtstr = `
+-- source: enterprise/internal/insights/store/webhook_deliveries.go:WebhookDeliveryStatus
+SELECT series_id, last_attempt_at, last_success_at, last_status_code, last_error, consecutive_failures
+FROM insight_webhook_deliveries
+WHERE series_id = %s
+`
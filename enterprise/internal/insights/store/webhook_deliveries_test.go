@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	insightsdbtesting "github.com/sourcegraph/sourcegraph/enterprise/internal/insights/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+)
+
+func TestWebhookDeliveries(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	now := time.Date(2021, 9, 1, 15, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	timescale, cleanup := insightsdbtesting.TimescaleDB(t)
+	defer cleanup()
+	postgres := dbtest.NewDB(t, "")
+	permStore := NewInsightPermissionStore(postgres)
+	store := NewWithClock(timescale, permStore, clock)
+
+	if _, ok, err := store.WebhookDeliveryStatus(ctx, "w:one"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatalf("unexpected delivery status of a webhook that was never requested")
+	}
+
+	ok, notFound := 200, 404
+	successAt := now
+	if err := store.RecordWebhookDelivery(ctx, WebhookDelivery{SeriesID: "w:one", StatusCode: &ok}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	if err := store.RecordWebhookDelivery(ctx, WebhookDelivery{SeriesID: "w:one", StatusCode: &notFound, Err: errors.New("unexpected status code 404")}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	if err := store.RecordWebhookDelivery(ctx, WebhookDelivery{SeriesID: "w:one", Err: errors.New("connection refused")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Failures keep the time of the last success, and are counted until the next success.
+	status, found, err := store.WebhookDeliveryStatus(ctx, "w:one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatalf("expected delivery status")
+	}
+	lastError := "connection refused"
+	want := WebhookDeliveryStatus{
+		SeriesID:            "w:one",
+		LastAttemptAt:       now,
+		LastSuccessAt:       &successAt,
+		LastError:           &lastError,
+		ConsecutiveFailures: 2,
+	}
+	if diff := cmp.Diff(want, status); diff != "" {
+		t.Fatalf("unexpected delivery status (-want +got):\n%s", diff)
+	}
+
+	now = now.Add(time.Hour)
+	if err := store.RecordWebhookDelivery(ctx, WebhookDelivery{SeriesID: "w:one", StatusCode: &ok}); err != nil {
+		t.Fatal(err)
+	}
+	status, _, err = store.WebhookDeliveryStatus(ctx, "w:one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = WebhookDeliveryStatus{
+		SeriesID:       "w:one",
+		LastAttemptAt:  now,
+		LastSuccessAt:  &now,
+		LastStatusCode: &ok,
+	}
+	if diff := cmp.Diff(want, status); diff != "" {
+		t.Fatalf("unexpected delivery status (-want +got):\n%s", diff)
+	}
+}
@@ -11,6 +11,8 @@ type InsightViewSeries struct {
 	Title                 string
 	Description           string
 	Query                 string
+	Webhook               string
+	Expression            string
 	CreatedAt             time.Time
 	OldestHistoricalAt    time.Time
 	LastRecordedAt        time.Time
@@ -18,12 +20,33 @@ type InsightViewSeries struct {
 	RecordingIntervalDays int
 	Label                 string
 	Stroke                string
+
+	// RecordingInterval is the interval at which the series is recorded for this view.
+	RecordingInterval string
+
+	// BusinessDaysOnly is true if the series is only recorded on business days for this view.
+	BusinessDaysOnly bool
+
+	// UserID and OrgID are the user or organization whose settings define the insight, if any.
+	UserID *int32
+	OrgID  *int32
+
+	// Repositories and RepositoryPattern are the repository scope of the series, if any.
+	Repositories      []string
+	RepositoryPattern string
 }
 
 // InsightViewSeriesMetadata contains metadata about a viewable insight series such as render properties.
 type InsightViewSeriesMetadata struct {
 	Label  string
 	Stroke string
+
+	// RecordingInterval is the interval at which the series is recorded for the view. It
+	// defaults to daily.
+	RecordingInterval string
+
+	// BusinessDaysOnly is true if the series is only recorded on business days for the view.
+	BusinessDaysOnly bool
 }
 
 // InsightView is a single insight view that may or may not have any associated series.
@@ -32,6 +55,11 @@ type InsightView struct {
 	Title       string
 	Description string
 	UniqueID    string
+
+	// UserID and OrgID are the user or organization whose settings define the insight. Insights
+	// defined by neither are global.
+	UserID *int32
+	OrgID  *int32
 }
 
 // InsightSeries is a single data series for a Code Insight. This contains some metadata about the data series, as well
@@ -40,9 +68,81 @@ type InsightSeries struct {
 	ID                    int
 	SeriesID              string
 	Query                 string
+	Webhook               string
+	Expression            string
 	CreatedAt             time.Time
 	OldestHistoricalAt    time.Time
 	LastRecordedAt        time.Time
 	NextRecordingAfter    time.Time
 	RecordingIntervalDays int
+
+	// BackfillQueuedAt is the time at which the historical data of the series was enqueued for
+	// backfilling, or nil if the series has not been backfilled yet.
+	BackfillQueuedAt *time.Time
+
+	// Repositories and RepositoryPattern restrict the search query of the series to the named
+	// repositories, or to the repositories whose names match the pattern. Series restricted to
+	// neither search all repositories.
+	Repositories      []string
+	RepositoryPattern string
+
+	// ReferenceCount is the number of insight views the series is attached to. Series with the
+	// same data are shared by all the views using them, and are deleted once no view uses them.
+	ReferenceCount int
+}
+
+// Dashboard is a dashboard of insights, shared with the subjects of its grants.
+type Dashboard struct {
+	ID    int
+	Title string
+
+	// UniqueID is the ID of the dashboard in the settings that define it, if it is defined in
+	// settings.
+	UniqueID *string
+
+	// InsightIDs are the unique IDs of the insight views of the dashboard, in the order they are
+	// shown.
+	InsightIDs []string
+
+	// UserIDs, OrgIDs and GlobalGrant describe the grants of the dashboard: the users and the
+	// organizations it is shared with, and whether it is shared with all users.
+	UserIDs     []int32
+	OrgIDs      []int32
+	GlobalGrant bool
+}
+
+// InsightProblem is a problem found in the definition of an insight that keeps it, or one of its
+// series, from being recorded.
+type InsightProblem struct {
+	InsightID string
+
+	// SeriesID and SeriesLabel are the series with the problem. They are empty if the problem
+	// concerns the whole insight.
+	SeriesID    string
+	SeriesLabel string
+
+	// UserID and OrgID are the user or organization whose settings define the insight, if any.
+	UserID *int32
+	OrgID  *int32
+
+	Problem    string
+	DetectedAt time.Time
+}
+
+// InsightDuplicate is an insight whose series are identical to those of an insight defined in the
+// settings of another namespace.
+type InsightDuplicate struct {
+	// GroupID identifies the group of insights that are duplicates of each other.
+	GroupID string
+
+	InsightID string
+	Title     string
+
+	// UserID and OrgID are the user or organization whose settings define the insight, if any.
+	UserID *int32
+	OrgID  *int32
+
+	// Canonical is true for the insight of the group that the other insights should be merged into.
+	Canonical  bool
+	DetectedAt time.Time
 }
@@ -76,3 +76,11 @@ var planFeatures = map[Plan][]Feature{
 // NoLicenseMaximumExternalServiceCount is the maximum number of external services that the
 // instance supports when running without a license.
 const NoLicenseMaximumExternalServiceCount = 1
+
+// NoLicenseMaximumCodeInsights is the maximum number of code insights that the instance
+// supports when running without a license.
+const NoLicenseMaximumCodeInsights = 2
+
+// NoLicenseMaximumSeriesPerCodeInsight is the maximum number of data series per code
+// insight that the instance supports when running without a license.
+const NoLicenseMaximumSeriesPerCodeInsight = 5
@@ -45,6 +45,28 @@ func (p Plan) MaxExternalServiceCount() int {
 	}
 }
 
+// MaxCodeInsights returns the number of code insights that the plan supports.
+// We treat 0 as "unlimited".
+func (p Plan) MaxCodeInsights() int {
+	switch p {
+	case team:
+		return 10
+	default:
+		return 0
+	}
+}
+
+// MaxSeriesPerCodeInsight returns the number of data series per code insight
+// that the plan supports. We treat 0 as "unlimited".
+func (p Plan) MaxSeriesPerCodeInsight() int {
+	switch p {
+	case team:
+		return 10
+	default:
+		return 0
+	}
+}
+
 // Plan is the pricing plan of the license.
 func (info *Info) Plan() Plan {
 	for _, tag := range info.Tags {
@@ -82,6 +82,18 @@ func SetupGlobalConnection(opts Opts) (err error) {
 // also use the value of PGDATASOURCE if supplied and dataSource is the empty
 // string.
 func New(opts Opts) (*sql.DB, error) {
+	db, err := newPool(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	prometheus.MustRegister(newMetricsCollector(db.Stats, opts.DBName, opts.AppName))
+	return db, nil
+}
+
+// newPool connects to the given data source and configures the connection pool
+// of the resulting handle. Metrics are not registered.
+func newPool(opts Opts) (*sql.DB, error) {
 	cfg, err := buildConfig(opts.DSN, opts.AppName)
 	if err != nil {
 		return nil, err
@@ -92,9 +104,7 @@ func New(opts Opts) (*sql.DB, error) {
 		return nil, err
 	}
 
-	prometheus.MustRegister(newMetricsCollector(db, opts.DBName, opts.AppName))
 	configureConnectionPool(db)
-
 	return db, nil
 }
 
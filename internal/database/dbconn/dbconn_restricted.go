@@ -64,7 +64,7 @@ func NewRestricted(opts Opts) (*sql.DB, error) {
 		return nil, errors.New("unable to open restricted db connection")
 	}
 
-	prometheus.MustRegister(newMetricsCollector(db, opts.DBName, opts.AppName))
+	prometheus.MustRegister(newMetricsCollector(db.Stats, opts.DBName, opts.AppName))
 	configureConnectionPool(db)
 	return db, nil
 }
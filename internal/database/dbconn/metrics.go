@@ -10,7 +10,7 @@ import (
 // It reports all metrics returned by sql.DB.Stats().
 // Adapted from github.com/dlmiddlecote/sqlstats
 type metricsCollector struct {
-	db *sql.DB
+	stats func() sql.DBStats
 
 	// descriptions of exported metrics
 	maxOpenDesc           *prometheus.Desc
@@ -24,7 +24,7 @@ type metricsCollector struct {
 	closedMaxIdleTimeDesc *prometheus.Desc
 }
 
-func newMetricsCollector(db *sql.DB, dbname, app string) *metricsCollector {
+func newMetricsCollector(stats func() sql.DBStats, dbname, app string) *metricsCollector {
 	const (
 		namespace = "src"
 		subsystem = "pgsql_conns"
@@ -36,7 +36,7 @@ func newMetricsCollector(db *sql.DB, dbname, app string) *metricsCollector {
 	}
 
 	return &metricsCollector{
-		db: db,
+		stats: stats,
 		maxOpenDesc: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, subsystem, "max_open"),
 			"Maximum number of open connections to the database.",
@@ -109,7 +109,7 @@ func (c metricsCollector) Describe(ch chan<- *prometheus.Desc) {
 
 // Collect implements the prometheus.Collector interface.
 func (c metricsCollector) Collect(ch chan<- prometheus.Metric) {
-	stats := c.db.Stats()
+	stats := c.stats()
 
 	ch <- prometheus.MustNewConstMetric(
 		c.maxOpenDesc,
@@ -0,0 +1,119 @@
+package dbconn
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SwappableDB is a database handle whose underlying connection pool can be replaced at
+// runtime, e.g. when the configured DSN changes. It implements dbutil.DB and dbutil.TxBeginner.
+//
+// Queries and transactions started before a swap complete on the connection pool they
+// started on. The previous pool is closed once all of its connections have been released.
+type SwappableDB struct {
+	opts Opts
+	open func(opts Opts) (*sql.DB, error)
+
+	swapMu sync.Mutex // serializes calls to Swap
+	mu     sync.RWMutex
+	db     *sql.DB
+}
+
+// drainTimeout is the maximum time to wait for the connections of a replaced pool to be
+// released before the pool is closed regardless.
+const drainTimeout = 5 * time.Minute
+
+// drainPollInterval is the interval at which a replaced pool is checked for connections
+// that are still in use.
+var drainPollInterval = time.Second
+
+// NewSwappable connects to the given data source and returns a handle whose connection pool
+// can be replaced by calling Swap. Metrics are reported for the current pool.
+func NewSwappable(opts Opts) (*SwappableDB, error) {
+	s, err := newSwappable(opts, newPool)
+	if err != nil {
+		return nil, err
+	}
+
+	prometheus.MustRegister(newMetricsCollector(func() sql.DBStats { return s.current().Stats() }, opts.DBName, opts.AppName))
+	return s, nil
+}
+
+func newSwappable(opts Opts, open func(opts Opts) (*sql.DB, error)) (*SwappableDB, error) {
+	db, err := open(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SwappableDB{opts: opts, open: open, db: db}, nil
+}
+
+// Swap connects to the given data source and replaces the current connection pool with
+// the new one. The previous pool is drained and closed in the background. If the new data
+// source cannot be reached, an error is returned and the current pool remains in use.
+func (s *SwappableDB) Swap(dsn string) error {
+	s.swapMu.Lock()
+	defer s.swapMu.Unlock()
+
+	opts := s.opts
+	opts.DSN = dsn
+
+	db, err := s.open(opts)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	old := s.db
+	s.db = db
+	s.opts = opts
+	s.mu.Unlock()
+
+	go drain(old)
+	return nil
+}
+
+// drain closes the given connection pool once none of its connections are in use, or once
+// drainTimeout has elapsed. The pool is checked only after a first poll interval so that
+// callers which obtained the pool just before the swap can still acquire a connection.
+func drain(db *sql.DB) {
+	deadline := time.Now().Add(drainTimeout)
+	for {
+		time.Sleep(drainPollInterval)
+
+		if db.Stats().InUse == 0 || time.Now().After(deadline) {
+			break
+		}
+	}
+
+	if err := db.Close(); err != nil {
+		log15.Error("Failed to close replaced database connection pool", "error", err)
+	}
+}
+
+func (s *SwappableDB) current() *sql.DB {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db
+}
+
+func (s *SwappableDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return s.current().QueryContext(ctx, query, args...)
+}
+
+func (s *SwappableDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return s.current().ExecContext(ctx, query, args...)
+}
+
+func (s *SwappableDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return s.current().QueryRowContext(ctx, query, args...)
+}
+
+func (s *SwappableDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return s.current().BeginTx(ctx, opts)
+}
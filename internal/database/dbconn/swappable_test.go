@@ -0,0 +1,82 @@
+package dbconn
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+func TestSwappableDB(t *testing.T) {
+	old := drainPollInterval
+	drainPollInterval = time.Millisecond
+	t.Cleanup(func() { drainPollInterval = old })
+
+	dsns := map[*sql.DB]string{}
+	open := func(opts Opts) (*sql.DB, error) {
+		if opts.DSN == "unreachable" {
+			return nil, errors.New("unreachable")
+		}
+
+		db := sql.OpenDB(fakeConnector{})
+		dsns[db] = opts.DSN
+		return db, nil
+	}
+
+	s, err := newSwappable(Opts{DSN: "first"}, open)
+	if err != nil {
+		t.Fatalf("unexpected error creating handle: %s", err)
+	}
+	first := s.current()
+
+	// Hold a connection of the first pool, as an in-flight transaction would
+	conn, err := first.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error acquiring connection: %s", err)
+	}
+
+	if err := s.Swap("unreachable"); err == nil {
+		t.Fatalf("expected error swapping to unreachable data source")
+	}
+	if s.current() != first {
+		t.Fatalf("expected pool to remain in use after failed swap")
+	}
+
+	if err := s.Swap("second"); err != nil {
+		t.Fatalf("unexpected error swapping pool: %s", err)
+	}
+	if dsn := dsns[s.current()]; dsn != "second" {
+		t.Errorf("unexpected pool in use. want=%q have=%q", "second", dsn)
+	}
+
+	// The first pool stays open while its connection is in use
+	time.Sleep(20 * time.Millisecond)
+	if err := conn.PingContext(context.Background()); err != nil {
+		t.Fatalf("unexpected error using connection of replaced pool: %s", err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatalf("unexpected error releasing connection: %s", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for first.PingContext(context.Background()) == nil {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected replaced pool to be closed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type fakeConnector struct{}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{}, nil }
+func (c fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("unsupported") }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("unsupported") }
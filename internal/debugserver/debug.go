@@ -80,6 +80,17 @@ type Dumper interface {
 // The given channel should be closed once the ready endpoint should begin to return 200 OK.
 // Any extra endpoints supplied will be registered via their own declared path.
 func NewServerRoutine(ready <-chan struct{}, extra ...Endpoint) goroutine.BackgroundRoutine {
+	return newServerRoutine(http.HandlerFunc(healthzHandler), readyHandler(ready), extra)
+}
+
+// NewServerRoutineWithReadiness returns a background routine that exposes pprof and metrics
+// endpoints. Both the healthz and ready endpoints report the status of each component tracked
+// by the given readiness, and return 200 OK only once all components are ready.
+func NewServerRoutineWithReadiness(readiness *Readiness, extra ...Endpoint) goroutine.BackgroundRoutine {
+	return newServerRoutine(readiness, readiness, extra)
+}
+
+func newServerRoutine(healthz, ready http.Handler, extra []Endpoint) goroutine.BackgroundRoutine {
 	if addr == "" {
 		return goroutine.NoopRoutine()
 	}
@@ -111,8 +122,8 @@ func NewServerRoutine(ready <-chan struct{}, extra ...Endpoint) goroutine.Backgr
 		})
 
 		router.Handle("/", index)
-		router.Handle("/healthz", http.HandlerFunc(healthzHandler))
-		router.Handle("/ready", readyHandler(ready))
+		router.Handle("/healthz", healthz)
+		router.Handle("/ready", ready)
 		router.Handle("/debug", index)
 		router.Handle("/vars", http.HandlerFunc(expvarHandler))
 		router.Handle("/gc", http.HandlerFunc(gcHandler))
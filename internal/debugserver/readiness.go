@@ -0,0 +1,104 @@
+package debugserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// Readiness tracks the readiness of the individual components of a service, such as its
+// database connection or the queues it serves. The service is ready once every component
+// is ready.
+type Readiness struct {
+	mu         sync.RWMutex
+	components []string
+	statuses   map[string]ComponentStatus
+}
+
+// ComponentStatus is the readiness of a single component.
+type ComponentStatus struct {
+	Name    string `json:"name"`
+	Ready   bool   `json:"ready"`
+	Message string `json:"message,omitempty"`
+}
+
+// ReadinessStatus is the body of a response from a readiness endpoint.
+type ReadinessStatus struct {
+	Ready      bool              `json:"ready"`
+	Components []ComponentStatus `json:"components"`
+}
+
+// NewReadiness creates a new readiness tracker with the given components, none of which are
+// initially ready.
+func NewReadiness(components ...string) *Readiness {
+	r := &Readiness{statuses: map[string]ComponentStatus{}}
+	for _, component := range components {
+		r.Register(component)
+	}
+
+	return r
+}
+
+// Register adds a component that is not yet ready. Registering a known component is a no-op.
+func (r *Readiness) Register(component string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.register(component)
+}
+
+// SetReady marks the given component as ready, registering it if necessary.
+func (r *Readiness) SetReady(component string) {
+	r.set(ComponentStatus{Name: component, Ready: true})
+}
+
+// SetNotReady marks the given component as not ready for the given reason, registering it
+// if necessary.
+func (r *Readiness) SetNotReady(component, message string) {
+	r.set(ComponentStatus{Name: component, Message: message})
+}
+
+func (r *Readiness) set(status ComponentStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.register(status.Name)
+	r.statuses[status.Name] = status
+}
+
+func (r *Readiness) register(component string) {
+	if _, ok := r.statuses[component]; ok {
+		return
+	}
+
+	r.components = append(r.components, component)
+	r.statuses[component] = ComponentStatus{Name: component, Message: "not initialized"}
+}
+
+// Status returns the readiness of each component in the order in which they were registered.
+func (r *Readiness) Status() ReadinessStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	status := ReadinessStatus{Ready: true, Components: make([]ComponentStatus, 0, len(r.components))}
+	for _, component := range r.components {
+		componentStatus := r.statuses[component]
+		status.Ready = status.Ready && componentStatus.Ready
+		status.Components = append(status.Components, componentStatus)
+	}
+
+	return status
+}
+
+// ServeHTTP responds with the readiness of each component as JSON. The response has a status
+// of 200 OK once all components are ready, and 503 Service Unavailable otherwise.
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	status := r.Status()
+
+	w.Header().Set("Content-Type", "application/json")
+	if status.Ready {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	_ = json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,56 @@
+package debugserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadiness(t *testing.T) {
+	readiness := NewReadiness("database", "migrations")
+
+	serve := func() (int, ReadinessStatus) {
+		w := httptest.NewRecorder()
+		readiness.ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+
+		var status ReadinessStatus
+		if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+			t.Fatalf("unexpected error decoding response: %s", err)
+		}
+		return w.Code, status
+	}
+
+	readiness.SetReady("database")
+	readiness.SetNotReady("migrations", "schema version 3 is behind 4")
+	readiness.Register("queue:codeintel")
+
+	code, status := serve()
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("unexpected status code. want=%d have=%d", http.StatusServiceUnavailable, code)
+	}
+	expected := ReadinessStatus{
+		Ready: false,
+		Components: []ComponentStatus{
+			{Name: "database", Ready: true},
+			{Name: "migrations", Message: "schema version 3 is behind 4"},
+			{Name: "queue:codeintel", Message: "not initialized"},
+		},
+	}
+	if diff := cmp.Diff(expected, status); diff != "" {
+		t.Errorf("unexpected status (-want +got):\n%s", diff)
+	}
+
+	readiness.SetReady("migrations")
+	readiness.SetReady("queue:codeintel")
+
+	code, status = serve()
+	if code != http.StatusOK {
+		t.Errorf("unexpected status code. want=%d have=%d", http.StatusOK, code)
+	}
+	if !status.Ready {
+		t.Errorf("expected service to be ready: %+v", status)
+	}
+}
@@ -0,0 +1,106 @@
+// Package envelope implements envelope encryption on top of an encryption.Key.
+//
+// Each value is encrypted locally with a freshly generated data key, and only the data key
+// is encrypted with the given encryption.Key. This keeps large values such as job payloads
+// off of remote key management services, which limit the size of the values they encrypt.
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+)
+
+// dataKeySize is the size of the generated data keys, selecting AES-256.
+const dataKeySize = 32
+
+// envelope is the serialized form of an encrypted value. The byte slices are base64 encoded
+// by encoding/json, so the serialized envelope can be stored in text columns.
+type envelope struct {
+	// EncryptedKey is the data key, encrypted with the key encryption key.
+	EncryptedKey []byte `json:"key"`
+
+	// Data is the nonce followed by the value encrypted with the data key.
+	Data []byte `json:"data"`
+}
+
+// Encrypt encrypts the given value with a new data key and returns the serialized envelope
+// containing the ciphertext and the data key encrypted with the given key.
+func Encrypt(ctx context.Context, key encryption.Encrypter, plaintext []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, errors.Wrap(err, "generating data key")
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "generating nonce")
+	}
+
+	encryptedKey, err := key.Encrypt(ctx, dataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypting data key")
+	}
+
+	return json.Marshal(envelope{
+		EncryptedKey: encryptedKey,
+		Data:         gcm.Seal(nonce, nonce, plaintext, nil),
+	})
+}
+
+// Decrypt decrypts the data key of the given serialized envelope with the given key, and
+// returns the value decrypted with the data key.
+func Decrypt(ctx context.Context, key encryption.Decrypter, ciphertext []byte) ([]byte, error) {
+	var e envelope
+	if err := json.Unmarshal(ciphertext, &e); err != nil {
+		return nil, errors.Wrap(err, "decoding envelope")
+	}
+
+	secret, err := key.Decrypt(ctx, e.EncryptedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting data key")
+	}
+
+	gcm, err := newGCM([]byte(secret.Secret()))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(e.Data) < gcm.NonceSize() {
+		return nil, errors.New("malformed envelope: data is shorter than nonce")
+	}
+	nonce, data := e.Data[:gcm.NonceSize()], e.Data[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, data, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting data")
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating AES cipher")
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating GCM block cipher")
+	}
+
+	return gcm, nil
+}
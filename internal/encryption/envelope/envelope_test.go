@@ -0,0 +1,82 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+
+	et "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	plaintext := []byte("SRC_ACCESS_TOKEN=secret")
+
+	ciphertext, err := Encrypt(ctx, et.TestKey{}, plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error encrypting value: %s", err)
+	}
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Fatalf("ciphertext contains plaintext: %s", ciphertext)
+	}
+
+	// Each value is encrypted with a new data key
+	other, err := Encrypt(ctx, et.TestKey{}, plaintext)
+	if err != nil {
+		t.Fatalf("unexpected error encrypting value: %s", err)
+	}
+	if bytes.Equal(ciphertext, other) {
+		t.Errorf("expected distinct ciphertexts")
+	}
+
+	decrypted, err := Decrypt(ctx, et.TestKey{}, ciphertext)
+	if err != nil {
+		t.Fatalf("unexpected error decrypting value: %s", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("unexpected plaintext. want=%q have=%q", plaintext, decrypted)
+	}
+}
+
+func TestDecryptTampered(t *testing.T) {
+	ctx := context.Background()
+
+	ciphertext, err := Encrypt(ctx, et.TestKey{}, []byte("payload"))
+	if err != nil {
+		t.Fatalf("unexpected error encrypting value: %s", err)
+	}
+
+	var e envelope
+	if err := json.Unmarshal(ciphertext, &e); err != nil {
+		t.Fatalf("unexpected error decoding envelope: %s", err)
+	}
+	e.Data[len(e.Data)-1] ^= 0xFF
+	tampered, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("unexpected error encoding envelope: %s", err)
+	}
+
+	if _, err := Decrypt(ctx, et.TestKey{}, tampered); err == nil {
+		t.Errorf("expected error decrypting tampered value")
+	}
+}
+
+func TestKeyErrors(t *testing.T) {
+	ctx := context.Background()
+	key := &et.BadKey{Err: errors.New("oops")}
+
+	if _, err := Encrypt(ctx, key, []byte("payload")); err == nil {
+		t.Errorf("expected error encrypting value")
+	}
+
+	ciphertext, err := Encrypt(ctx, et.TestKey{}, []byte("payload"))
+	if err != nil {
+		t.Fatalf("unexpected error encrypting value: %s", err)
+	}
+	if _, err := Decrypt(ctx, key, ciphertext); err == nil {
+		t.Errorf("expected error decrypting value")
+	}
+}
@@ -2,6 +2,8 @@ package goroutine
 
 import (
 	"context"
+	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -18,13 +20,17 @@ import (
 // for more information and a step-by-step guide on how to implement a
 // PeriodicBackgroundRoutine.
 type PeriodicGoroutine struct {
-	interval  time.Duration
-	handler   Handler
-	operation *observation.Operation
-	clock     glock.Clock
-	ctx       context.Context    // root context passed to the handler
-	cancel    context.CancelFunc // cancels the root context
-	finished  chan struct{}      // signals that Start has finished
+	handler         Handler
+	operation       *observation.Operation
+	clock           glock.Clock
+	options         periodicOptions
+	ctx             context.Context    // root context passed to the handler
+	cancel          context.CancelFunc // cancels the root context
+	finished        chan struct{}      // signals that Start has finished
+	intervalChanged chan struct{}      // signals that SetInterval was called
+
+	mu       sync.Mutex // protects interval
+	interval time.Duration
 }
 
 var _ BackgroundRoutine = &PeriodicGoroutine{}
@@ -49,6 +55,16 @@ type Finalizer interface {
 	OnShutdown()
 }
 
+// IntervalAdjuster is an optional extension of the Handler interface.
+type IntervalAdjuster interface {
+	// AdjustInterval is called after each call to Handle with the current interval and
+	// the error value returned from Handle, and returns the interval to wait before the
+	// next call, e.g. a longer interval to back off while a queue is full. A non-positive
+	// interval keeps the current interval. This will not be called during a graceful
+	// shutdown.
+	AdjustInterval(interval time.Duration, err error) time.Duration
+}
+
 // HandlerFunc wraps a function so it can be used as a Handler.
 type HandlerFunc func(ctx context.Context) error
 
@@ -75,57 +91,197 @@ func (h *simpleHandler) HandleError(err error) {
 	log15.Error("An error occurred in a background task", "handler", h.name, "error", err)
 }
 
+type periodicOptions struct {
+	jitter           float64
+	initialDelay     time.Duration
+	intervalOverride func() time.Duration
+	random           func() float64 // returns a number in [0.0,1.0)
+}
+
+// PeriodicOption alters the default behavior of NewPeriodicGoroutine and
+// NewPeriodicGoroutineWithMetrics.
+type PeriodicOption func(o *periodicOptions)
+
+// maxJitter is the largest jitter fraction, so that a jittered wait is at least half the
+// interval and a handler is never invoked in a tight loop.
+const maxJitter = 0.5
+
+// WithJitter randomizes each wait between invocations of the handler by up to the given
+// fraction of the interval, in either direction: a fraction of 0.1 waits between 90% and
+// 110% of the interval. This keeps routines with the same interval that were started at the
+// same time, e.g. by a deploy, from invoking their handlers in lockstep. The fraction is
+// clamped to [0, 0.5].
+func WithJitter(fraction float64) PeriodicOption {
+	return func(o *periodicOptions) {
+		switch {
+		case fraction < 0:
+			fraction = 0
+		case fraction > maxJitter:
+			fraction = maxJitter
+		}
+		o.jitter = fraction
+	}
+}
+
+// WithInitialDelay delays the first invocation of the handler by the given duration, instead
+// of invoking it as soon as the routine starts. The delay is jittered like the interval if
+// WithJitter is also given.
+func WithInitialDelay(delay time.Duration) PeriodicOption {
+	return func(o *periodicOptions) { o.initialDelay = delay }
+}
+
+// WithIntervalOverride overrides the interval of the routine with the positive intervals
+// returned by the given function, e.g. an interval read from the site configuration. The
+// function is called before each wait between invocations of the handler, and whenever
+// SetInterval is called, so it must be cheap. A positive interval takes precedence over
+// the intervals given to SetInterval or returned by an IntervalAdjuster, and a non-positive
+// interval does not override the interval of the routine.
+func WithIntervalOverride(override func() time.Duration) PeriodicOption {
+	return func(o *periodicOptions) { o.intervalOverride = override }
+}
+
 // NewPeriodicGoroutine creates a new PeriodicGoroutine with the given handler. The context provided will propagate into
 // the executing goroutine and will terminate the goroutine if cancelled.
-func NewPeriodicGoroutine(ctx context.Context, interval time.Duration, handler Handler) *PeriodicGoroutine {
-	return NewPeriodicGoroutineWithMetrics(ctx, interval, handler, nil)
+func NewPeriodicGoroutine(ctx context.Context, interval time.Duration, handler Handler, options ...PeriodicOption) *PeriodicGoroutine {
+	return NewPeriodicGoroutineWithMetrics(ctx, interval, handler, nil, options...)
 }
 
 // NewPeriodicGoroutineWithMetrics creates a new PeriodicGoroutine with the given handler. The context provided will propagate into
 // the executing goroutine and will terminate the goroutine if cancelled.
-func NewPeriodicGoroutineWithMetrics(ctx context.Context, interval time.Duration, handler Handler, operation *observation.Operation) *PeriodicGoroutine {
-	return newPeriodicGoroutine(ctx, interval, handler, operation, glock.NewRealClock())
+func NewPeriodicGoroutineWithMetrics(ctx context.Context, interval time.Duration, handler Handler, operation *observation.Operation, options ...PeriodicOption) *PeriodicGoroutine {
+	return newPeriodicGoroutine(ctx, interval, handler, operation, glock.NewRealClock(), options...)
 }
 
-func newPeriodicGoroutine(ctx context.Context, interval time.Duration, handler Handler, operation *observation.Operation, clock glock.Clock) *PeriodicGoroutine {
+func newPeriodicGoroutine(ctx context.Context, interval time.Duration, handler Handler, operation *observation.Operation, clock glock.Clock, fns ...PeriodicOption) *PeriodicGoroutine {
 	ctx, cancel := context.WithCancel(ctx)
 
+	options := periodicOptions{random: rand.Float64}
+	for _, fn := range fns {
+		fn(&options)
+	}
+
 	return &PeriodicGoroutine{
-		handler:   handler,
-		interval:  interval,
-		operation: operation,
-		clock:     clock,
-		ctx:       ctx,
-		cancel:    cancel,
-		finished:  make(chan struct{}),
+		handler:         handler,
+		interval:        interval,
+		operation:       operation,
+		clock:           clock,
+		options:         options,
+		ctx:             ctx,
+		cancel:          cancel,
+		finished:        make(chan struct{}),
+		intervalChanged: make(chan struct{}, 1),
 	}
 }
 
+// Interval returns the interval the routine currently waits between invocations of its
+// handler, before jitter.
+func (r *PeriodicGoroutine) Interval() time.Duration {
+	if r.options.intervalOverride != nil {
+		if interval := r.options.intervalOverride(); interval > 0 {
+			return interval
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.interval
+}
+
+// SetInterval changes the interval the routine waits between invocations of its handler.
+// If the routine is currently waiting, the new interval applies to the current wait, which
+// is counted from the end of the previous invocation. A non-positive interval keeps the
+// current interval, but the current wait still takes changes of the interval override (see
+// WithIntervalOverride) into account.
+func (r *PeriodicGoroutine) SetInterval(interval time.Duration) {
+	r.setInterval(interval)
+
+	select {
+	case r.intervalChanged <- struct{}{}:
+	default:
+	}
+}
+
+func (r *PeriodicGoroutine) setInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	r.interval = interval
+	r.mu.Unlock()
+}
+
 // Start begins the process of calling the registered handler in a loop. This process will
-// wait the interval supplied at construction between invocations.
+// wait the interval supplied at construction, or set since, between invocations.
 func (r *PeriodicGoroutine) Start() {
 	defer close(r.finished)
 
+	if r.options.initialDelay > 0 {
+		select {
+		case <-r.clock.After(r.jittered(r.options.initialDelay)):
+		case <-r.ctx.Done():
+			r.finalize()
+			return
+		}
+	}
+
 loop:
 	for {
-		if shutdown, err := runPeriodicHandler(r.ctx, r.handler, r.operation); shutdown {
+		shutdown, err := runPeriodicHandler(r.ctx, r.handler, r.operation)
+		if shutdown {
 			break
-		} else if h, ok := r.handler.(ErrorHandler); ok && err != nil {
+		}
+		if h, ok := r.handler.(ErrorHandler); ok && err != nil {
 			h.HandleError(err)
 		}
+		if h, ok := r.handler.(IntervalAdjuster); ok {
+			r.mu.Lock()
+			interval := r.interval
+			r.mu.Unlock()
+			r.setInterval(h.AdjustInterval(interval, err))
+		}
 
+		if !r.wait() {
+			break loop
+		}
+	}
+
+	r.finalize()
+}
+
+// wait blocks for the interval of the routine, recomputing the remaining time whenever the
+// interval changes. It returns false if the routine is shut down while waiting.
+func (r *PeriodicGoroutine) wait() bool {
+	start := r.clock.Now()
+	interval := r.jittered(r.Interval())
+
+	for {
 		select {
-		case <-r.clock.After(r.interval):
+		case <-r.clock.After(interval - r.clock.Since(start)):
+			return true
+		case <-r.intervalChanged:
+			interval = r.jittered(r.Interval())
 		case <-r.ctx.Done():
-			break loop
+			return false
 		}
 	}
+}
 
+func (r *PeriodicGoroutine) finalize() {
 	if h, ok := r.handler.(Finalizer); ok {
 		h.OnShutdown()
 	}
 }
 
+// jittered returns the given duration randomized by up to the jitter fraction of the options
+// in either direction.
+func (r *PeriodicGoroutine) jittered(d time.Duration) time.Duration {
+	if r.options.jitter == 0 {
+		return d
+	}
+	return d + time.Duration(float64(d)*r.options.jitter*(2*r.options.random()-1))
+}
+
 // Stop will cancel the context passed to the handler function to stop the current
 // iteration of work, then break the loop in the Start method so that no new work
 // is accepted. This method blocks until Start has returned.
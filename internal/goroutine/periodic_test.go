@@ -87,6 +87,148 @@ func TestPeriodicGoroutineFinalizer(t *testing.T) {
 	}
 }
 
+func TestPeriodicGoroutineJitter(t *testing.T) {
+	clock := glock.NewMockClock()
+	handler := NewMockHandler()
+
+	goroutine := newPeriodicGoroutine(context.Background(), time.Second, handler, nil, clock,
+		WithJitter(0.5),
+		func(o *periodicOptions) { o.random = func() float64 { return 0 } },
+	)
+	go goroutine.Start()
+	clock.BlockingAdvance(500 * time.Millisecond)
+	clock.BlockingAdvance(500 * time.Millisecond)
+	clock.BlockingAdvance(500 * time.Millisecond)
+	goroutine.Stop()
+
+	if calls := len(handler.HandleFunc.History()); calls != 4 {
+		t.Errorf("unexpected number of handler invocations. want=%d have=%d", 4, calls)
+	}
+	for _, d := range clock.GetAfterArgs() {
+		if d != 500*time.Millisecond {
+			t.Errorf("unexpected wait between invocations. want=%s have=%s", 500*time.Millisecond, d)
+		}
+	}
+}
+
+func TestWithJitterClamped(t *testing.T) {
+	for fraction, want := range map[float64]float64{-1: 0, 0.1: 0.1, 0.5: 0.5, 1: 0.5, 2: 0.5} {
+		var options periodicOptions
+		WithJitter(fraction)(&options)
+		if options.jitter != want {
+			t.Errorf("unexpected jitter for fraction %v. want=%v have=%v", fraction, want, options.jitter)
+		}
+	}
+}
+
+func TestPeriodicGoroutineInitialDelay(t *testing.T) {
+	clock := glock.NewMockClock()
+	handler := NewMockHandler()
+
+	goroutine := newPeriodicGoroutine(context.Background(), time.Second, handler, nil, clock,
+		WithInitialDelay(time.Minute),
+		WithJitter(0.5),
+		func(o *periodicOptions) { o.random = func() float64 { return 0.75 } },
+	)
+	go goroutine.Start()
+	clock.BlockingAdvance(75 * time.Second)
+	clock.BlockingAdvance(1250 * time.Millisecond)
+	goroutine.Stop()
+
+	if calls := len(handler.HandleFunc.History()); calls != 2 {
+		t.Errorf("unexpected number of handler invocations. want=%d have=%d", 2, calls)
+	}
+	if args := clock.GetAfterArgs(); len(args) < 2 || args[0] != 75*time.Second || args[1] != 1250*time.Millisecond {
+		t.Errorf("unexpected waits. want=[%s %s ...] have=%v", 75*time.Second, 1250*time.Millisecond, args)
+	}
+}
+
+func TestPeriodicGoroutineStoppedDuringInitialDelay(t *testing.T) {
+	clock := glock.NewMockClock()
+	handler := NewMockHandlerWithFinalizer()
+
+	goroutine := newPeriodicGoroutine(context.Background(), time.Second, handler, nil, clock, WithInitialDelay(time.Minute))
+	go goroutine.Start()
+	goroutine.Stop()
+
+	if calls := len(handler.HandleFunc.History()); calls != 0 {
+		t.Errorf("unexpected number of handler invocations. want=%d have=%d", 0, calls)
+	}
+	if calls := len(handler.OnShutdownFunc.History()); calls != 1 {
+		t.Errorf("unexpected number of finalizer invocations. want=%d have=%d", 1, calls)
+	}
+}
+
+func TestPeriodicGoroutineIntervalAdjuster(t *testing.T) {
+	clock := glock.NewMockClock()
+	handler := NewMockHandlerWithIntervalAdjuster()
+	handler.AdjustIntervalFunc.SetDefaultHook(func(interval time.Duration, err error) time.Duration { return 2 * interval })
+
+	goroutine := newPeriodicGoroutine(context.Background(), time.Second, handler, nil, clock)
+	go goroutine.Start()
+	clock.BlockingAdvance(2 * time.Second)
+	clock.BlockingAdvance(4 * time.Second)
+	goroutine.Stop()
+
+	if calls := len(handler.HandleFunc.History()); calls != 3 {
+		t.Errorf("unexpected number of handler invocations. want=%d have=%d", 3, calls)
+	}
+	if args := clock.GetAfterArgs(); len(args) < 2 || args[0] != 2*time.Second || args[1] != 4*time.Second {
+		t.Errorf("unexpected waits. want=[%s %s ...] have=%v", 2*time.Second, 4*time.Second, args)
+	}
+	if interval := goroutine.Interval(); interval != 8*time.Second {
+		t.Errorf("unexpected interval. want=%s have=%s", 8*time.Second, interval)
+	}
+}
+
+func TestPeriodicGoroutineSetInterval(t *testing.T) {
+	clock := glock.NewMockClock()
+	handler := NewMockHandler()
+
+	goroutine := newPeriodicGoroutine(context.Background(), time.Hour, handler, nil, clock)
+	go goroutine.Start()
+	waitFor(t, func() bool { return clock.BlockedOnAfter() == 1 })
+
+	// The current wait is shortened: the new interval is counted from its start.
+	goroutine.SetInterval(time.Second)
+	clock.BlockingAdvance(time.Second)
+	waitFor(t, func() bool { return len(handler.HandleFunc.History()) == 2 })
+	goroutine.Stop()
+
+	// Non-positive intervals are ignored.
+	goroutine.SetInterval(0)
+	if interval := goroutine.Interval(); interval != time.Second {
+		t.Errorf("unexpected interval. want=%s have=%s", time.Second, interval)
+	}
+}
+
+func TestPeriodicGoroutineIntervalOverride(t *testing.T) {
+	override := time.Minute
+	goroutine := newPeriodicGoroutine(context.Background(), time.Second, NewMockHandler(), nil, glock.NewMockClock(),
+		WithIntervalOverride(func() time.Duration { return override }),
+	)
+
+	goroutine.SetInterval(time.Hour)
+	if interval := goroutine.Interval(); interval != time.Minute {
+		t.Errorf("unexpected interval. want=%s have=%s", time.Minute, interval)
+	}
+
+	override = 0
+	if interval := goroutine.Interval(); interval != time.Hour {
+		t.Errorf("unexpected interval. want=%s have=%s", time.Hour, interval)
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	for i := 0; i < 1000; i++ {
+		if condition() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("condition not met after 1s")
+}
+
 type MockHandlerWithErrorHandler struct {
 	*MockHandler
 	*MockErrorHandler
@@ -110,3 +252,15 @@ func NewMockHandlerWithFinalizer() *MockHandlerWithFinalizer {
 		MockFinalizer: NewMockFinalizer(),
 	}
 }
+
+type MockHandlerWithIntervalAdjuster struct {
+	*MockHandler
+	*MockIntervalAdjuster
+}
+
+func NewMockHandlerWithIntervalAdjuster() *MockHandlerWithIntervalAdjuster {
+	return &MockHandlerWithIntervalAdjuster{
+		MockHandler:          NewMockHandler(),
+		MockIntervalAdjuster: NewMockIntervalAdjuster(),
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/inconshreveable/log15"
 
@@ -13,9 +14,10 @@ import (
 )
 
 type server struct {
-	server       *http.Server
-	makeListener func() (net.Listener, error)
-	once         sync.Once
+	server          *http.Server
+	makeListener    func() (net.Listener, error)
+	shutdownTimeout time.Duration
+	once            sync.Once
 }
 
 // New returns a BackgroundRoutine that serves the given server on the given listener.
@@ -34,6 +36,17 @@ func NewFromAddr(addr string, httpServer *http.Server) goroutine.BackgroundRouti
 	}
 }
 
+// NewFromAddrWithShutdownTimeout returns a BackgroundRoutine that serves the given handler on
+// the given address. On shutdown, in-flight requests are given the supplied timeout to complete
+// before their connections are forcibly closed.
+func NewFromAddrWithShutdownTimeout(addr string, httpServer *http.Server, shutdownTimeout time.Duration) goroutine.BackgroundRoutine {
+	return &server{
+		server:          httpServer,
+		makeListener:    func() (net.Listener, error) { return NewListener(addr) },
+		shutdownTimeout: shutdownTimeout,
+	}
+}
+
 func (s *server) Start() {
 	listener, err := s.makeListener()
 	if err != nil {
@@ -49,8 +62,19 @@ func (s *server) Start() {
 
 func (s *server) Stop() {
 	s.once.Do(func() {
-		if err := s.server.Shutdown(context.Background()); err != nil {
+		ctx := context.Background()
+		if s.shutdownTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.shutdownTimeout)
+			defer cancel()
+		}
+
+		if err := s.server.Shutdown(ctx); err != nil {
 			log15.Error("Failed to shutdown server", "error", err)
+
+			if err := s.server.Close(); err != nil {
+				log15.Error("Failed to close server", "error", err)
+			}
 		}
 	})
 }
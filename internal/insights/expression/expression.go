@@ -0,0 +1,384 @@
+// Package expression implements the arithmetic expressions that derived insight series are computed
+// from the values of other series with.
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ErrDivisionByZero is returned when evaluating an expression divides by zero.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// Operand refers to a series whose value an expression is computed from. Expressions defined in
+// settings refer to the series of their insight by position, e.g. $1, and are resolved to refer to
+// them by series ID, e.g. ${s:1234}, before they are recorded.
+type Operand struct {
+	Position int    // the 1-based position of the series in its insight, if non-zero
+	SeriesID string // the series ID of the series, if Position is zero
+}
+
+func (o Operand) String() string {
+	if o.Position != 0 {
+		return fmt.Sprintf("$%d", o.Position)
+	}
+	return fmt.Sprintf("${%s}", o.SeriesID)
+}
+
+// Expr is a parsed arithmetic expression over operands and numbers.
+type Expr struct {
+	root node
+}
+
+// Parse parses the given expression. Expressions support numbers, operands, the binary operators
+// +, -, *, and /, unary minus, and parentheses.
+func Parse(s string) (*Expr, error) {
+	p := &parser{input: s}
+	p.next()
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, p.errorf("unexpected %q", p.tok.text)
+	}
+	return &Expr{root: root}, nil
+}
+
+// String returns the canonical form of the expression, which parses to the same expression. Every
+// nested binary operation is parenthesized, so that equivalent expressions with different
+// whitespace or redundant parentheses have the same canonical form.
+func (e *Expr) String() string {
+	return e.root.String()
+}
+
+// Operands returns the distinct operands of the expression, in order of first appearance.
+func (e *Expr) Operands() []Operand {
+	var operands []Operand
+	seen := map[Operand]struct{}{}
+	e.root.walk(func(n node) {
+		if o, ok := n.(operandNode); ok {
+			if _, ok := seen[o.Operand]; !ok {
+				seen[o.Operand] = struct{}{}
+				operands = append(operands, o.Operand)
+			}
+		}
+	})
+	return operands
+}
+
+// Resolved reports whether every operand of the expression refers to a series by series ID.
+func (e *Expr) Resolved() bool {
+	for _, o := range e.Operands() {
+		if o.Position != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolve returns the expression with its operands referring to series by position replaced by the
+// given series IDs, where seriesIDs[0] is the series ID of the series at position 1.
+func (e *Expr) Resolve(seriesIDs []string) (*Expr, error) {
+	root, err := e.root.resolve(seriesIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{root: root}, nil
+}
+
+// Eval returns the value of the expression, given the values of the series its operands refer to
+// by series ID.
+func (e *Expr) Eval(values map[string]float64) (float64, error) {
+	return e.root.eval(values)
+}
+
+type node interface {
+	String() string
+	walk(f func(node))
+	resolve(seriesIDs []string) (node, error)
+	eval(values map[string]float64) (float64, error)
+}
+
+type numberNode float64
+
+// String formats the number without an exponent, which the lexer does not read.
+func (n numberNode) String() string { return strconv.FormatFloat(float64(n), 'f', -1, 64) }
+
+func (n numberNode) walk(f func(node)) { f(n) }
+
+func (n numberNode) resolve([]string) (node, error) { return n, nil }
+
+func (n numberNode) eval(map[string]float64) (float64, error) { return float64(n), nil }
+
+type operandNode struct{ Operand }
+
+func (n operandNode) walk(f func(node)) { f(n) }
+
+func (n operandNode) resolve(seriesIDs []string) (node, error) {
+	if n.Position == 0 {
+		return n, nil
+	}
+	if n.Position > len(seriesIDs) {
+		return nil, errors.Errorf("%s does not refer to a series: the insight has %d series", n, len(seriesIDs))
+	}
+	return operandNode{Operand{SeriesID: seriesIDs[n.Position-1]}}, nil
+}
+
+func (n operandNode) eval(values map[string]float64) (float64, error) {
+	if n.Position != 0 {
+		return 0, errors.Errorf("unresolved operand %s", n)
+	}
+	value, ok := values[n.SeriesID]
+	if !ok {
+		return 0, errors.Errorf("no value for operand %s", n)
+	}
+	return value, nil
+}
+
+type negateNode struct{ x node }
+
+func (n negateNode) String() string {
+	if _, ok := n.x.(binaryNode); ok {
+		return fmt.Sprintf("-(%s)", n.x)
+	}
+	return "-" + n.x.String()
+}
+
+func (n negateNode) walk(f func(node)) {
+	f(n)
+	n.x.walk(f)
+}
+
+func (n negateNode) resolve(seriesIDs []string) (node, error) {
+	x, err := n.x.resolve(seriesIDs)
+	return negateNode{x}, err
+}
+
+func (n negateNode) eval(values map[string]float64) (float64, error) {
+	x, err := n.x.eval(values)
+	return -x, err
+}
+
+type binaryNode struct {
+	op   byte
+	x, y node
+}
+
+func (n binaryNode) String() string {
+	operand := func(x node) string {
+		if _, ok := x.(binaryNode); ok {
+			return fmt.Sprintf("(%s)", x)
+		}
+		return x.String()
+	}
+	return fmt.Sprintf("%s %c %s", operand(n.x), n.op, operand(n.y))
+}
+
+func (n binaryNode) walk(f func(node)) {
+	f(n)
+	n.x.walk(f)
+	n.y.walk(f)
+}
+
+func (n binaryNode) resolve(seriesIDs []string) (node, error) {
+	x, err := n.x.resolve(seriesIDs)
+	if err != nil {
+		return nil, err
+	}
+	y, err := n.y.resolve(seriesIDs)
+	if err != nil {
+		return nil, err
+	}
+	return binaryNode{op: n.op, x: x, y: y}, nil
+}
+
+func (n binaryNode) eval(values map[string]float64) (float64, error) {
+	x, err := n.x.eval(values)
+	if err != nil {
+		return 0, err
+	}
+	y, err := n.y.eval(values)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case '+':
+		return x + y, nil
+	case '-':
+		return x - y, nil
+	case '*':
+		return x * y, nil
+	default:
+		if y == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return x / y, nil
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenOperand
+	tokenOperator // one of + - * / ( )
+	tokenInvalid
+)
+
+type token struct {
+	kind    tokenKind
+	text    string
+	operand Operand
+	number  float64
+}
+
+// parser is a recursive descent parser of expressions, with the usual precedence of arithmetic
+// operators.
+type parser struct {
+	input string
+	pos   int
+	tok   token
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return errors.Errorf("invalid expression %q: %s", p.input, fmt.Sprintf(format, args...))
+}
+
+// next scans the next token into p.tok.
+func (p *parser) next() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	if p.pos == len(p.input) {
+		p.tok = token{kind: tokenEOF, text: "end of expression"}
+		return
+	}
+
+	start := p.pos
+	c := p.input[p.pos]
+	switch {
+	case strings.IndexByte("+-*/()", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokenOperator, text: string(c)}
+
+	case c == '$' && strings.HasPrefix(p.input[p.pos:], "${"):
+		end := strings.IndexByte(p.input[p.pos:], '}')
+		if end < 0 || end == 2 {
+			p.pos = len(p.input)
+			p.tok = token{kind: tokenInvalid, text: p.input[start:]}
+			return
+		}
+		p.pos += end + 1
+		p.tok = token{kind: tokenOperand, text: p.input[start:p.pos], operand: Operand{SeriesID: p.input[start+2 : p.pos-1]}}
+
+	case c == '$':
+		p.pos++
+		for p.pos < len(p.input) && isDigit(p.input[p.pos]) {
+			p.pos++
+		}
+		position, err := strconv.Atoi(p.input[start+1 : p.pos])
+		if err != nil || position == 0 {
+			p.tok = token{kind: tokenInvalid, text: p.input[start:p.pos]}
+			return
+		}
+		p.tok = token{kind: tokenOperand, text: p.input[start:p.pos], operand: Operand{Position: position}}
+
+	case isDigit(c) || c == '.':
+		for p.pos < len(p.input) && (isDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		number, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			p.tok = token{kind: tokenInvalid, text: p.input[start:p.pos]}
+			return
+		}
+		p.tok = token{kind: tokenNumber, text: p.input[start:p.pos], number: number}
+
+	default:
+		p.pos++
+		p.tok = token{kind: tokenInvalid, text: string(c)}
+	}
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+func (p *parser) isOperator(ops string) bool {
+	return p.tok.kind == tokenOperator && strings.Contains(ops, p.tok.text)
+}
+
+// parseSum parses a sum or difference of products.
+func (p *parser) parseSum() (node, error) {
+	x, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+-") {
+		op := p.tok.text[0]
+		p.next()
+		y, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		x = binaryNode{op: op, x: x, y: y}
+	}
+	return x, nil
+}
+
+// parseProduct parses a product or quotient of factors.
+func (p *parser) parseProduct() (node, error) {
+	x, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*/") {
+		op := p.tok.text[0]
+		p.next()
+		y, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		x = binaryNode{op: op, x: x, y: y}
+	}
+	return x, nil
+}
+
+// parseFactor parses a number, an operand, a negated factor, or a parenthesized expression.
+func (p *parser) parseFactor() (node, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokenNumber:
+		p.next()
+		return numberNode(tok.number), nil
+
+	case tok.kind == tokenOperand:
+		p.next()
+		return operandNode{tok.operand}, nil
+
+	case p.isOperator("-"):
+		p.next()
+		x, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return negateNode{x}, nil
+
+	case p.isOperator("("):
+		p.next()
+		x, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isOperator(")") {
+			return nil, p.errorf("expected \")\", got %q", p.tok.text)
+		}
+		p.next()
+		return x, nil
+	}
+	return nil, p.errorf("unexpected %q", tok.text)
+}
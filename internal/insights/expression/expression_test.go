@@ -0,0 +1,165 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		canonical string
+		operands  []Operand
+	}{
+		{
+			name:      "ratio",
+			input:     "$1/$2",
+			canonical: "$1 / $2",
+			operands:  []Operand{{Position: 1}, {Position: 2}},
+		},
+		{
+			name:      "precedence",
+			input:     "$1 / $2 * 1000 + 1",
+			canonical: "(($1 / $2) * 1000) + 1",
+			operands:  []Operand{{Position: 1}, {Position: 2}},
+		},
+		{
+			name:      "parentheses",
+			input:     "100 * $1 / ($1 + $2)",
+			canonical: "(100 * $1) / ($1 + $2)",
+			operands:  []Operand{{Position: 1}, {Position: 2}},
+		},
+		{
+			name:      "redundant parentheses",
+			input:     "(($1)) - (0.5)",
+			canonical: "$1 - 0.5",
+			operands:  []Operand{{Position: 1}},
+		},
+		{
+			name:      "negation",
+			input:     "-$1 - -($2 - 1)",
+			canonical: "-$1 - -($2 - 1)",
+			operands:  []Operand{{Position: 1}, {Position: 2}},
+		},
+		{
+			name:      "series IDs",
+			input:     "${s:AB} - ${w:CD}",
+			canonical: "${s:AB} - ${w:CD}",
+			operands:  []Operand{{SeriesID: "s:AB"}, {SeriesID: "w:CD"}},
+		},
+		{
+			name:      "small constant",
+			input:     "$1 * 0.00001",
+			canonical: "$1 * 0.00001",
+			operands:  []Operand{{Position: 1}},
+		},
+		{
+			name:      "large constant",
+			input:     "$1 + 1000000000000000000000",
+			canonical: "$1 + 1000000000000000000000",
+			operands:  []Operand{{Position: 1}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := Parse(test.input)
+			if err != nil {
+				t.Fatalf("unexpected error parsing expression: %s", err)
+			}
+			if canonical := expr.String(); canonical != test.canonical {
+				t.Errorf("unexpected canonical form. want=%q have=%q", test.canonical, canonical)
+			}
+			if diff := cmp.Diff(test.operands, expr.Operands()); diff != "" {
+				t.Errorf("unexpected operands (-want +got):\n%s", diff)
+			}
+
+			reparsed, err := Parse(expr.String())
+			if err != nil {
+				t.Fatalf("unexpected error parsing canonical form: %s", err)
+			}
+			if reparsed.String() != expr.String() {
+				t.Errorf("unexpected canonical form of canonical form. want=%q have=%q", expr.String(), reparsed.String())
+			}
+		})
+	}
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	for _, value := range []float64{0, 1, 0.5, 1e-05, 1e-300, 1e+21, 1.7976931348623157e+308, 123456.789} {
+		expr := &Expr{root: numberNode(value)}
+
+		reparsed, err := Parse(expr.String())
+		if err != nil {
+			t.Fatalf("unexpected error parsing %q: %s", expr.String(), err)
+		}
+		if reparsed.String() != expr.String() {
+			t.Errorf("unexpected canonical form of canonical form. want=%q have=%q", expr.String(), reparsed.String())
+		}
+		if have, err := reparsed.Eval(nil); err != nil || have != value {
+			t.Errorf("unexpected value of %q. want=%v have=%v err=%v", expr.String(), value, have, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"$1 +",
+		"$0",
+		"$",
+		"${}",
+		"${s:AB",
+		"($1",
+		"$1)",
+		"$1 $2",
+		"1.2.3",
+		"$1 % $2",
+	} {
+		t.Run(input, func(t *testing.T) {
+			if _, err := Parse(input); err == nil {
+				t.Errorf("expected an error parsing %q", input)
+			}
+		})
+	}
+}
+
+func TestResolveAndEval(t *testing.T) {
+	expr, err := Parse("100 * $1 / ($1 + $2)")
+	if err != nil {
+		t.Fatalf("unexpected error parsing expression: %s", err)
+	}
+	if expr.Resolved() {
+		t.Errorf("expected expression referring to positions not to be resolved")
+	}
+	if _, err := expr.Resolve([]string{"s:A"}); err == nil {
+		t.Errorf("expected an error resolving a position out of range")
+	}
+
+	resolved, err := expr.Resolve([]string{"s:A", "s:B"})
+	if err != nil {
+		t.Fatalf("unexpected error resolving expression: %s", err)
+	}
+	if !resolved.Resolved() {
+		t.Errorf("expected resolved expression to be resolved")
+	}
+	if want := "(100 * ${s:A}) / (${s:A} + ${s:B})"; resolved.String() != want {
+		t.Errorf("unexpected resolved expression. want=%q have=%q", want, resolved.String())
+	}
+
+	value, err := resolved.Eval(map[string]float64{"s:A": 1, "s:B": 3})
+	if err != nil {
+		t.Fatalf("unexpected error evaluating expression: %s", err)
+	}
+	if value != 25 {
+		t.Errorf("unexpected value. want=%v have=%v", 25, value)
+	}
+
+	if _, err := resolved.Eval(map[string]float64{"s:A": 0, "s:B": 0}); err != ErrDivisionByZero {
+		t.Errorf("unexpected error dividing by zero. want=%q have=%v", ErrDivisionByZero, err)
+	}
+	if _, err := resolved.Eval(map[string]float64{"s:A": 1}); err == nil {
+		t.Errorf("expected an error evaluating without the value of an operand")
+	}
+}
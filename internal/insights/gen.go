@@ -1,3 +1,5 @@
 package insights
 
 //go:generate ../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/internal/insights -i Loader -o mock_loader.go
+//go:generate ../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/internal/insights -i DashboardLoader -o mock_dashboard_loader.go
+//go:generate ../../dev/mockgen.sh github.com/sourcegraph/sourcegraph/internal/insights -i TemplateLoader -o mock_template_loader.go
@@ -3,7 +3,9 @@ package insights
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/inconshreveable/log15"
 
@@ -34,6 +36,32 @@ func NewLoader(db dbutil.DB) Loader {
 	return &DBLoader{db: db}
 }
 
+// DashboardLoader will load insight dashboards from some persistent storage.
+type DashboardLoader interface {
+	LoadDashboards(ctx context.Context) ([]SettingDashboard, error)
+}
+
+func (d *DBLoader) LoadDashboards(ctx context.Context) ([]SettingDashboard, error) {
+	return GetSettingDashboards(ctx, d.db)
+}
+
+func NewDashboardLoader(db dbutil.DB) DashboardLoader {
+	return &DBLoader{db: db}
+}
+
+// TemplateLoader will load insight templates from some persistent storage.
+type TemplateLoader interface {
+	LoadTemplates(ctx context.Context) ([]InsightTemplate, error)
+}
+
+func (d *DBLoader) LoadTemplates(ctx context.Context) ([]InsightTemplate, error) {
+	return GetInsightTemplates(ctx, d.db)
+}
+
+func NewTemplateLoader(db dbutil.DB) TemplateLoader {
+	return &DBLoader{db: db}
+}
+
 // GetSettings returns all settings on the Sourcegraph installation that can be filtered by a type. This is useful for
 // generating aggregates for code insights which are currently stored in the settings.
 // 🚨 SECURITY: This method bypasses any user permissions to fetch a list of all settings on the Sourcegraph installation.
@@ -164,6 +192,7 @@ func GetIntegratedInsights(ctx context.Context, db dbutil.DB) ([]SearchInsight,
 			continue
 		}
 
+		namespace := SubjectNamespace(setting.Subject)
 		for _, val := range raw {
 			// iterate for each instance of the prefix key in the settings. This should never be len > 1, but it's technically a map.
 			temp, err := unmarshalIntegrated(val)
@@ -171,7 +200,10 @@ func GetIntegratedInsights(ctx context.Context, db dbutil.DB) ([]SearchInsight,
 				// this isn't actually a total failure case, we could have partially parsed this dictionary.
 				multi = multierror.Append(multi, err)
 			}
-			results = append(results, temp.Insights()...)
+			for _, insight := range temp.Insights() {
+				insight.Namespace = namespace
+				results = append(results, insight)
+			}
 		}
 	}
 
@@ -182,6 +214,133 @@ func GetIntegratedInsights(ctx context.Context, db dbutil.DB) ([]SearchInsight,
 	return results, nil
 }
 
+// SettingDashboard is a dashboard of insights defined in the `insights.dashboards` setting object.
+type SettingDashboard struct {
+	ID         string   `json:"-"`
+	Title      string   `json:"title"`
+	InsightIDs []string `json:"insightIds"`
+
+	// Namespace is the namespace of the settings that define the dashboard. Dashboards are shared
+	// with the subject of their settings.
+	Namespace Namespace `json:"-"`
+}
+
+// GetSettingDashboards returns all of the insight dashboards defined in the `insights.dashboards` setting object, which is
+// a dictionary of unique keys to dashboards. Like GetIntegratedInsights, deserialization errors are logged but do not
+// cause any errors to surface.
+func GetSettingDashboards(ctx context.Context, db dbutil.DB) ([]SettingDashboard, error) {
+	prefix := "insights.dashboards"
+
+	settings, err := GetSettings(ctx, db, All, prefix)
+	if err != nil {
+		return []SettingDashboard{}, err
+	}
+
+	var multi error
+
+	results := make([]SettingDashboard, 0)
+	for _, setting := range settings {
+		var raw map[string]json.RawMessage
+		raw, err = FilterSettingJson(setting.Contents, prefix)
+		if err != nil {
+			multi = multierror.Append(multi, err)
+			continue
+		}
+
+		namespace := SubjectNamespace(setting.Subject)
+		for _, val := range raw {
+			var dict map[string]SettingDashboard
+			if err := json.Unmarshal(val, &dict); err != nil {
+				multi = multierror.Append(multi, err)
+				continue
+			}
+			for id, dashboard := range dict {
+				dashboard.ID = id // the dashboard ID is the value of the dict key
+				dashboard.Namespace = namespace
+				results = append(results, dashboard)
+			}
+		}
+	}
+
+	if multi != nil {
+		log15.Error("insights: deserialization errors parsing insight dashboards", "error", multi)
+	}
+
+	return results, nil
+}
+
+// InsightTemplate is an insight defined in the `insights.templates` setting object of an
+// organization, which is instantiated as a separate insight for every repository whose name matches
+// the repository pattern of the template.
+type InsightTemplate struct {
+	ID          string `json:"-"`
+	Title       string
+	Description string
+	Series      []TimeSeries
+
+	// RepositoryPattern is a regular expression matching the names of the repositories the
+	// template is instantiated for.
+	RepositoryPattern string
+
+	// Namespace is the namespace of the organization whose settings define the template.
+	// Instances of the template are shared with the organization.
+	Namespace Namespace `json:"-"`
+}
+
+// TemplateRepositoryPlaceholder is replaced with the name of the repository of an instance of an
+// insight template in the title and description of the instance.
+const TemplateRepositoryPlaceholder = "{repository}"
+
+// GetInsightTemplates returns all of the insight templates defined in the `insights.templates`
+// setting object of organizations, which is a dictionary of unique keys to templates. Templates
+// defined in user or global settings are ignored. Like GetIntegratedInsights, deserialization
+// errors are logged but do not cause any errors to surface.
+func GetInsightTemplates(ctx context.Context, db dbutil.DB) ([]InsightTemplate, error) {
+	prefix := "insights.templates"
+
+	settings, err := GetSettings(ctx, db, Org, prefix)
+	if err != nil {
+		return []InsightTemplate{}, err
+	}
+
+	var multi error
+
+	results := make([]InsightTemplate, 0)
+	for _, setting := range settings {
+		var raw map[string]json.RawMessage
+		raw, err = FilterSettingJson(setting.Contents, prefix)
+		if err != nil {
+			multi = multierror.Append(multi, err)
+			continue
+		}
+
+		namespace := SubjectNamespace(setting.Subject)
+		for _, val := range raw {
+			var dict map[string]json.RawMessage
+			if err := json.Unmarshal(val, &dict); err != nil {
+				multi = multierror.Append(multi, err)
+				continue
+			}
+			for id, body := range dict {
+				var template InsightTemplate
+				if err := json.Unmarshal(body, &template); err != nil {
+					multi = multierror.Append(multi, err)
+					continue
+				}
+				template.ID = id // the template ID is the value of the dict key
+				template.Namespace = namespace
+				results = append(results, template)
+			}
+		}
+	}
+
+	if multi != nil {
+		log15.Error("insights: deserialization errors parsing insight templates", "error", multi)
+	}
+
+	return results, nil
+}
+
 // IntegratedInsights represents a settings dictionary of valid insights that are integrated across the extensions API and the backend.
 type IntegratedInsights map[string]SearchInsight
 
@@ -219,9 +378,226 @@ func (i IntegratedInsights) Insights() []SearchInsight {
 }
 
 type TimeSeries struct {
-	Name   string
-	Stroke string
-	Query  string
+	Name     string
+	Stroke   string
+	Query    string
+	Interval RecordingInterval
+
+	// Webhook is the URL the data of the series is fetched from. Series have either a query, a
+	// webhook, or an expression.
+	Webhook string
+
+	// Expression is the arithmetic expression the series is computed from the other series of its
+	// insight with (see the expression package). Expressions defined in settings refer to the
+	// series of their insight by position, and are resolved to refer to them by series ID when
+	// discovered.
+	Expression string
+
+	// GeneratedFromCaptureGroups indicates that the series is split into one series per distinct
+	// value matched by the first capture group of its regexp query.
+	GeneratedFromCaptureGroups bool
+
+	// GroupBy, if set, indicates that the series is split into one series per author or team the
+	// matches of its search query are attributed to.
+	GroupBy Attribution
+
+	// BusinessDaysOnly indicates that the series is only recorded on the business days of the
+	// recording calendar.
+	BusinessDaysOnly bool
+
+	// Namespace is the namespace of the insight that defines the series. Series of different
+	// namespaces record their data separately.
+	Namespace Namespace `json:"-"`
+
+	// Repositories and RepositoryPattern are the repository scope of the insight that defines the
+	// series: the search query of the series only searches the named repositories, or the
+	// repositories whose names match the pattern. Both are empty for series of all repositories.
+	Repositories      []string `json:"-"`
+	RepositoryPattern string   `json:"-"`
+}
+
+// Split returns true if the series is split into one series per label, i.e. per captured value or
+// per author or team.
+func (s TimeSeries) Split() bool {
+	return s.GeneratedFromCaptureGroups || s.GroupBy != ""
+}
+
+// Attribution describes what the matches of a series grouped by attribution are attributed to.
+type Attribution string
+
+const (
+	// AttributeToAuthor attributes commit and diff matches to the email of their commit author.
+	AttributeToAuthor Attribution = "author"
+
+	// AttributeToTeam attributes matches to the teams of the insights.attribution.teams site
+	// setting: commit and diff matches to the teams of their commit author, and file matches to the
+	// team owning their path.
+	AttributeToTeam Attribution = "team"
+)
+
+// Namespace is the user or organization whose settings define an insight. The zero value is the
+// global namespace, for insights defined in the global settings, which are visible to all users.
+type Namespace struct {
+	UserID int32
+	OrgID  int32
+}
+
+// SubjectNamespace returns the namespace of the insights defined in the settings of the given
+// subject.
+func SubjectNamespace(subject api.SettingsSubject) Namespace {
+	switch {
+	case subject.User != nil:
+		return Namespace{UserID: *subject.User}
+	case subject.Org != nil:
+		return Namespace{OrgID: *subject.Org}
+	default:
+		return Namespace{}
+	}
+}
+
+// IsGlobal reports whether n is the global namespace.
+func (n Namespace) IsGlobal() bool {
+	return n == Namespace{}
+}
+
+// String returns a representation of n that identifies it, e.g. "org:1". It is empty for the
+// global namespace.
+func (n Namespace) String() string {
+	switch {
+	case n.UserID != 0:
+		return fmt.Sprintf("user:%d", n.UserID)
+	case n.OrgID != 0:
+		return fmt.Sprintf("org:%d", n.OrgID)
+	default:
+		return ""
+	}
+}
+
+// RecordingInterval describes how often a new data point is recorded for a series. Recordings
+// are aligned to the start of each interval in the time zone of the recording calendar (UTC by
+// default), e.g. weekly series are recorded on Mondays.
+type RecordingInterval string
+
+const (
+	Hourly  RecordingInterval = "hourly"
+	Daily   RecordingInterval = "daily"
+	Weekly  RecordingInterval = "weekly"
+	Monthly RecordingInterval = "monthly"
+)
+
+// Start returns the start of the interval that contains t, in UTC. Unknown or empty intervals are
+// treated as daily.
+func (i RecordingInterval) Start(t time.Time) time.Time {
+	return RecordingCalendar{}.Start(i, t)
+}
+
+// Next returns the start of the interval that follows the one containing t, in UTC.
+func (i RecordingInterval) Next(t time.Time) time.Time {
+	return RecordingCalendar{}.Next(i, t)
+}
+
+// Shorter reports whether i records more often than other.
+func (i RecordingInterval) Shorter(other RecordingInterval) bool {
+	return i.rank() < other.rank()
+}
+
+func (i RecordingInterval) rank() int {
+	switch i {
+	case Hourly:
+		return 0
+	case Weekly:
+		return 2
+	case Monthly:
+		return 3
+	default:
+		return 1
+	}
+}
+
+// RecordingCalendar describes the calendar series are recorded by: the time zone their recording
+// intervals are aligned in, and the days that are not business days, on which series recorded on
+// business days only are not recorded. The zero value aligns intervals in UTC, and every day is a
+// business day.
+type RecordingCalendar struct {
+	// Location is the time zone of the calendar, or nil for UTC.
+	Location *time.Location
+
+	// WeekendDays and Holidays are the days that are not business days. Holidays are dates in the
+	// format 2006-01-02.
+	WeekendDays []time.Weekday
+	Holidays    []string
+}
+
+func (c RecordingCalendar) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
+
+// Start returns the start of the interval i that contains t, in the time zone of the calendar.
+// Unknown or empty intervals are treated as daily.
+func (c RecordingCalendar) Start(i RecordingInterval, t time.Time) time.Time {
+	t = t.In(c.location())
+	switch i {
+	case Hourly:
+		// Truncating the time of day rather than the absolute time keeps hours aligned in time
+		// zones whose offset is not a whole number of hours.
+		return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case Weekly:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case Monthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+}
+
+// Next returns the start of the interval i that follows the one containing t, in the time zone of
+// the calendar.
+func (c RecordingCalendar) Next(i RecordingInterval, t time.Time) time.Time {
+	start := c.Start(i, t)
+	switch i {
+	case Hourly:
+		return start.Add(time.Hour)
+	case Weekly:
+		return start.AddDate(0, 0, 7)
+	case Monthly:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// IsBusinessDay reports whether t falls on a business day in the time zone of the calendar.
+func (c RecordingCalendar) IsBusinessDay(t time.Time) bool {
+	t = t.In(c.location())
+	for _, day := range c.WeekendDays {
+		if t.Weekday() == day {
+			return false
+		}
+	}
+	date := t.Format("2006-01-02")
+	for _, holiday := range c.Holidays {
+		if date == holiday {
+			return false
+		}
+	}
+	return true
+}
+
+// LastBusinessDay returns the time of day of t on the latest business day at or before t. It
+// returns t if there is no business day in the year before t.
+func (c RecordingCalendar) LastBusinessDay(t time.Time) time.Time {
+	day := t.In(c.location())
+	for i := 0; i <= 366; i++ {
+		if c.IsBusinessDay(day) {
+			return day
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+	return t
 }
 
 type Interval struct {
@@ -240,6 +616,14 @@ type SearchInsight struct {
 	Series       []TimeSeries
 	Step         Interval
 	Visibility   string
+
+	// RepositoryPattern is a regular expression matching the names of the repositories the
+	// insight is restricted to, as an alternative to listing them in Repositories.
+	RepositoryPattern string
+
+	// Namespace is the namespace whose settings define the insight. Only users of the namespace
+	// may see the insight and its data.
+	Namespace Namespace `json:"-"`
 }
 
 type LangStatsInsight struct {
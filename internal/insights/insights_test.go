@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/inconshreveable/log15"
 
@@ -123,6 +124,60 @@ func TestGetIntegrationInsights(t *testing.T) {
 	log15.Info("msg", "got", got)
 }
 
+func TestGetInsightTemplates(t *testing.T) {
+	ctx := context.Background()
+
+	db := dbtesting.GetDB(t)
+	_, err := db.Exec(`INSERT INTO orgs(id, name) VALUES (1, 'first-org');`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Templates of global settings are ignored.
+	_, err = db.Exec(`
+			INSERT INTO settings (id, org_id, contents, created_at, user_id, author_user_id)
+			VALUES  (1, 1, $1, CURRENT_TIMESTAMP, NULL, NULL),
+			        (2, NULL, $1, CURRENT_TIMESTAMP, NULL, NULL)`, insightTemplateSimple)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GetInsightTemplates(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []InsightTemplate{{
+		ID:                "go-version",
+		Title:             "Go version adoption in {repository}",
+		RepositoryPattern: "^github\\.com/sourcegraph/",
+		Series: []TimeSeries{{
+			Name:     "Go 1.16",
+			Query:    "file:go.mod go 1.16",
+			Interval: Weekly,
+		}},
+		Namespace: Namespace{OrgID: 1},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected insight templates (-want +got):\n%s", diff)
+	}
+}
+
+const insightTemplateSimple = `{
+	"insights.templates": {
+		"go-version": {
+			"title": "Go version adoption in {repository}",
+			"repositoryPattern": "^github\\.com/sourcegraph/",
+			"series": [
+				{
+					"name": "Go 1.16",
+					"query": "file:go.mod go 1.16",
+					"interval": "weekly"
+				}
+			]
+		}
+	}
+}`
+
 const integratedInsightSimple = `{
 	"insights.allrepos":{
 		"unique-id1": {
@@ -174,3 +229,86 @@ const insightSettingSimple = `{"searchInsights.insight.global.simple": {
       "weeks": 2
     }
   }}`
+
+func TestRecordingInterval(t *testing.T) {
+	// A Wednesday afternoon in a non-UTC time zone.
+	now := time.Date(2021, time.July, 14, 15, 30, 0, 0, time.FixedZone("UTC-2", -2*60*60))
+
+	testCases := []struct {
+		interval      RecordingInterval
+		expectedStart time.Time
+		expectedNext  time.Time
+	}{
+		{Hourly, time.Date(2021, time.July, 14, 17, 0, 0, 0, time.UTC), time.Date(2021, time.July, 14, 18, 0, 0, 0, time.UTC)},
+		{Daily, time.Date(2021, time.July, 14, 0, 0, 0, 0, time.UTC), time.Date(2021, time.July, 15, 0, 0, 0, 0, time.UTC)},
+		{Weekly, time.Date(2021, time.July, 12, 0, 0, 0, 0, time.UTC), time.Date(2021, time.July, 19, 0, 0, 0, 0, time.UTC)},
+		{Monthly, time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, time.August, 1, 0, 0, 0, 0, time.UTC)},
+		{"", time.Date(2021, time.July, 14, 0, 0, 0, 0, time.UTC), time.Date(2021, time.July, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.interval), func(t *testing.T) {
+			if start := testCase.interval.Start(now); !start.Equal(testCase.expectedStart) {
+				t.Errorf("unexpected start. want=%s have=%s", testCase.expectedStart, start)
+			}
+			if next := testCase.interval.Next(now); !next.Equal(testCase.expectedNext) {
+				t.Errorf("unexpected next. want=%s have=%s", testCase.expectedNext, next)
+			}
+		})
+	}
+
+	// Sundays belong to the week that started on the previous Monday.
+	sunday := time.Date(2021, time.July, 18, 12, 0, 0, 0, time.UTC)
+	if start := Weekly.Start(sunday); !start.Equal(time.Date(2021, time.July, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected start of week. have=%s", start)
+	}
+}
+
+func TestRecordingCalendar(t *testing.T) {
+	india := time.FixedZone("IST", 5*60*60+30*60)
+	calendar := RecordingCalendar{
+		Location:    india,
+		WeekendDays: []time.Weekday{time.Saturday, time.Sunday},
+		Holidays:    []string{"2021-07-16"},
+	}
+	// A Wednesday evening in UTC, which is already Thursday in India.
+	now := time.Date(2021, time.July, 14, 20, 45, 0, 0, time.UTC)
+
+	testCases := []struct {
+		interval      RecordingInterval
+		expectedStart time.Time
+		expectedNext  time.Time
+	}{
+		{Hourly, time.Date(2021, time.July, 15, 2, 0, 0, 0, india), time.Date(2021, time.July, 15, 3, 0, 0, 0, india)},
+		{Daily, time.Date(2021, time.July, 15, 0, 0, 0, 0, india), time.Date(2021, time.July, 16, 0, 0, 0, 0, india)},
+		{Weekly, time.Date(2021, time.July, 12, 0, 0, 0, 0, india), time.Date(2021, time.July, 19, 0, 0, 0, 0, india)},
+		{Monthly, time.Date(2021, time.July, 1, 0, 0, 0, 0, india), time.Date(2021, time.August, 1, 0, 0, 0, 0, india)},
+	}
+	for _, testCase := range testCases {
+		t.Run(string(testCase.interval), func(t *testing.T) {
+			if start := calendar.Start(testCase.interval, now); !start.Equal(testCase.expectedStart) {
+				t.Errorf("unexpected start. want=%s have=%s", testCase.expectedStart, start)
+			}
+			if next := calendar.Next(testCase.interval, now); !next.Equal(testCase.expectedNext) {
+				t.Errorf("unexpected next. want=%s have=%s", testCase.expectedNext, next)
+			}
+		})
+	}
+
+	if !calendar.IsBusinessDay(now) {
+		t.Errorf("expected Thursday to be a business day")
+	}
+	// Friday is a holiday, followed by the weekend.
+	sunday := time.Date(2021, time.July, 18, 10, 0, 0, 0, india)
+	for _, day := range []time.Time{sunday.AddDate(0, 0, -2), sunday.AddDate(0, 0, -1), sunday} {
+		if calendar.IsBusinessDay(day) {
+			t.Errorf("expected %s not to be a business day", day.Weekday())
+		}
+	}
+	if last := calendar.LastBusinessDay(sunday); !last.Equal(sunday.AddDate(0, 0, -3)) {
+		t.Errorf("unexpected last business day. want=%s have=%s", sunday.AddDate(0, 0, -3), last)
+	}
+	if last := (RecordingCalendar{}).LastBusinessDay(sunday); !last.Equal(sunday) {
+		t.Errorf("unexpected last business day without weekend days. want=%s have=%s", sunday, last)
+	}
+}
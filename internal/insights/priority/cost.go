@@ -4,7 +4,77 @@ package priority
 // for insight query execution strategies.
 type Cost int
 
+// The costs of a literal search over every repository of the instance, which are the basis of the
+// costs estimated by EstimateCost.
 const (
 	Indexed   Cost = 500
-	Unindexed Cost = 5000 // using an order of magnitude approximation at the moment. Eventually this should become something a little smarter.
+	Unindexed Cost = 5000 // an order of magnitude approximation.
 )
+
+// PatternType is the kind of pattern a search query matches with.
+type PatternType int
+
+const (
+	Literal PatternType = iota
+	Regexp
+	Structural
+)
+
+// QueryShape describes the aspects of a search query that determine its cost.
+type QueryShape struct {
+	PatternType PatternType
+
+	// Unindexed is true if the query searches revisions that are not indexed, e.g. a repository at a
+	// historical commit.
+	Unindexed bool
+
+	// History is true if the query searches commits or diffs rather than file contents.
+	History bool
+
+	// Repos is the number of repositories the query searches, and TotalRepos the number of
+	// repositories on the instance. When either is unknown (zero) the query is assumed to search
+	// every repository.
+	Repos      int
+	TotalRepos int
+}
+
+// minBreadth bounds how much cheaper a query is considered for searching only a fraction of the
+// repositories, since every search has a fixed overhead regardless of how many repositories it
+// searches.
+const minBreadth = 0.01
+
+// EstimateCost estimates the cost of a query of the given shape. The Indexed and Unindexed costs
+// are those of a literal search over every repository; regexp, structural and commit/diff searches
+// cost more, and searches scoped to a subset of repositories cost proportionally less.
+func EstimateCost(shape QueryShape) Cost {
+	cost := float64(Indexed)
+	if shape.Unindexed {
+		cost = float64(Unindexed)
+	}
+
+	switch shape.PatternType {
+	case Regexp:
+		cost *= 2
+	case Structural:
+		// Structural search runs on unindexed file contents even for indexed revisions.
+		cost *= 10
+	}
+	if shape.History {
+		cost *= 10
+	}
+
+	if shape.Repos > 0 && shape.TotalRepos > 0 {
+		breadth := float64(shape.Repos) / float64(shape.TotalRepos)
+		if breadth < minBreadth {
+			breadth = minBreadth
+		}
+		if breadth < 1 {
+			cost *= breadth
+		}
+	}
+
+	if cost < 1 {
+		return 1
+	}
+	return Cost(cost)
+}
@@ -0,0 +1,64 @@
+package priority
+
+import "testing"
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape QueryShape
+		want  Cost
+	}{
+		{
+			name:  "indexed literal",
+			shape: QueryShape{},
+			want:  Indexed,
+		},
+		{
+			name:  "unindexed literal",
+			shape: QueryShape{Unindexed: true},
+			want:  Unindexed,
+		},
+		{
+			name:  "regexp",
+			shape: QueryShape{PatternType: Regexp},
+			want:  1000,
+		},
+		{
+			name:  "structural",
+			shape: QueryShape{PatternType: Structural},
+			want:  5000,
+		},
+		{
+			name:  "commit history",
+			shape: QueryShape{History: true},
+			want:  5000,
+		},
+		{
+			name:  "half of the repositories",
+			shape: QueryShape{Repos: 50, TotalRepos: 100},
+			want:  250,
+		},
+		{
+			name:  "single repository at a historical commit",
+			shape: QueryShape{Unindexed: true, Repos: 1, TotalRepos: 10000},
+			want:  50,
+		},
+		{
+			name:  "more repositories than known",
+			shape: QueryShape{Repos: 200, TotalRepos: 100},
+			want:  Indexed,
+		},
+		{
+			name:  "unknown repository count",
+			shape: QueryShape{Repos: 3},
+			want:  Indexed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateCost(tt.shape); got != tt.want {
+				t.Errorf("EstimateCost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package ot
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+)
+
+// InjectSpanContext returns the context of the span in the given context encoded as a text map,
+// so that it can be persisted with work that is picked up by another process, such as a job in
+// a work queue. Nil is returned if the context holds no span or is not being traced.
+func InjectSpanContext(ctx context.Context) map[string]string {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+
+	carrier := map[string]string{}
+	if err := GetTracer(ctx).Inject(span.Context(), opentracing.TextMap, opentracing.TextMapCarrier(carrier)); err != nil || len(carrier) == 0 {
+		return nil
+	}
+
+	return carrier
+}
+
+// ExtractSpanContext decodes a span context encoded by InjectSpanContext with the tracer returned
+// by GetTracer. Nil is returned if the carrier is empty or cannot be decoded.
+func ExtractSpanContext(ctx context.Context, carrier map[string]string) opentracing.SpanContext {
+	if len(carrier) == 0 {
+		return nil
+	}
+
+	spanContext, err := GetTracer(ctx).Extract(opentracing.TextMap, opentracing.TextMapCarrier(carrier))
+	if err != nil {
+		return nil
+	}
+
+	return spanContext
+}
@@ -19,7 +19,7 @@ import (
 // state for more than a few seconds are very likely to be stuck after the worker processing
 // them has crashed.
 type Resetter struct {
-	store    store.Store
+	store    ResetterStore
 	options  ResetterOptions
 	clock    glock.Clock
 	ctx      context.Context // root context passed to the database
@@ -27,6 +27,13 @@ type Resetter struct {
 	finished chan struct{}   // signals that Start has finished
 }
 
+// ResetterStore is the subset of the store interface used by the resetter.
+type ResetterStore interface {
+	ResetStalled(ctx context.Context) (resetIDs, erroredIDs []int, err error)
+}
+
+var _ ResetterStore = store.Store(nil)
+
 type ResetterOptions struct {
 	Name     string
 	Interval time.Duration
@@ -39,11 +46,11 @@ type ResetterMetrics struct {
 	Errors              prometheus.Counter
 }
 
-func NewResetter(store store.Store, options ResetterOptions) *Resetter {
+func NewResetter(store ResetterStore, options ResetterOptions) *Resetter {
 	return newResetter(store, options, glock.NewRealClock())
 }
 
-func newResetter(store store.Store, options ResetterOptions, clock glock.Clock) *Resetter {
+func newResetter(store ResetterStore, options ResetterOptions, clock glock.Clock) *Resetter {
 	if options.Name == "" {
 		panic("no name supplied to github.com/sourcegraph/sourcegraph/internal/dbworker/newResetter")
 	}
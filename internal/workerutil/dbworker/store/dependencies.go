@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/opentracing/opentracing-go/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// ErrDependencyCycle is returned by AddDependencies when the dependencies would make a job depend
+// on itself, directly or transitively.
+var ErrDependencyCycle = errors.New("job dependency cycle")
+
+// AddDependencies records that the given job of the given queue may only be dequeued once all of
+// the given jobs of the same queue have completed. If one of those jobs fails or is deleted, the
+// job is marked as failed by MarkDependentsFailed instead. The queue name must match the
+// DependencyQueueName option of the queue's store.
+//
+// Dependencies are best added in the transaction that enqueues the job, so that the job cannot be
+// dequeued before its dependencies are recorded.
+func AddDependencies(ctx context.Context, s *basestore.Store, queueName string, jobID int, dependsOn []int) (err error) {
+	if len(dependsOn) == 0 {
+		return nil
+	}
+	for _, id := range dependsOn {
+		if id == jobID {
+			return ErrDependencyCycle
+		}
+	}
+
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	// Serialize the modification of the queue's dependency graph so that concurrent calls cannot
+	// introduce a cycle that neither call observes
+	if err := tx.Exec(ctx, sqlf.Sprintf(lockDependenciesQuery, queueName)); err != nil {
+		return err
+	}
+
+	_, cyclic, err := basestore.ScanFirstInt(tx.Query(ctx, sqlf.Sprintf(dependencyCycleQuery, queueName, pq.Array(dependsOn), queueName, jobID)))
+	if err != nil {
+		return err
+	}
+	if cyclic {
+		return ErrDependencyCycle
+	}
+
+	return tx.Exec(ctx, sqlf.Sprintf(insertDependenciesQuery, queueName, jobID, pq.Array(dependsOn)))
+}
+
+const lockDependenciesQuery = `
+-- source: internal/workerutil/dbworker/store/dependencies.go:AddDependencies
+SELECT pg_advisory_xact_lock(hashtext('workerutil_job_dependencies/' || %s))
+`
+
+// dependencyCycleQuery returns a row if the given job is among the transitive dependencies of the
+// given new dependencies.
+const dependencyCycleQuery = `
+-- source: internal/workerutil/dbworker/store/dependencies.go:AddDependencies
+WITH RECURSIVE ancestors(id) AS (
+	SELECT d.depends_on_job_id
+	FROM workerutil_job_dependencies d
+	WHERE d.queue_name = %s AND d.job_id = ANY(%s)
+	UNION
+	SELECT d.depends_on_job_id
+	FROM workerutil_job_dependencies d
+	JOIN ancestors a ON a.id = d.job_id
+	WHERE d.queue_name = %s
+)
+SELECT id FROM ancestors WHERE id = %s
+`
+
+const insertDependenciesQuery = `
+-- source: internal/workerutil/dbworker/store/dependencies.go:AddDependencies
+INSERT INTO workerutil_job_dependencies (queue_name, job_id, depends_on_job_id)
+SELECT %s, %s, unnest(%s::integer[])
+ON CONFLICT DO NOTHING
+`
+
+// dependencyConditions returns the conditions restricting dequeued records to records whose
+// dependencies have all completed. No conditions are returned if the store does not support
+// dependencies.
+func (s *store) dependencyConditions() []*sqlf.Query {
+	if s.options.DependencyQueueName == "" {
+		return nil
+	}
+
+	return []*sqlf.Query{s.formatQuery(
+		dependenciesCompleteCondition,
+		s.options.DependencyQueueName,
+		quote(s.viewAlias()),
+		quote(s.tableBaseName()),
+	)}
+}
+
+const dependenciesCompleteCondition = `
+NOT EXISTS (
+	SELECT 1
+	FROM workerutil_job_dependencies d
+	WHERE
+		d.queue_name = %s AND
+		d.job_id = %s.{id} AND
+		NOT EXISTS (SELECT 1 FROM %s p WHERE p.{id} = d.depends_on_job_id AND p.{state} = 'completed')
+)
+`
+
+// tableBaseName returns the name of TableName without its alias, if one was supplied.
+func (s *store) tableBaseName() string {
+	return strings.Fields(s.options.TableName)[0]
+}
+
+// viewAlias returns the name by which the columns of ViewName are qualified in a query.
+func (s *store) viewAlias() string {
+	fields := strings.Fields(s.options.ViewName)
+	return fields[len(fields)-1]
+}
+
+// MarkDependentsFailed moves all queued records depending on a failed or deleted record, directly or
+// transitively, into the failed state. This method returns the identifiers of the records that were
+// updated, and has no effect if the store does not support dependencies.
+func (s *store) MarkDependentsFailed(ctx context.Context) (ids []int, err error) {
+	ctx, traceLog, endObservation := s.operations.markDependentsFailed.WithAndLogger(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	if s.options.DependencyQueueName == "" {
+		return nil, nil
+	}
+
+	condition := s.formatQuery(
+		dependencyFailedCondition,
+		quote(s.tableBaseName()),
+		s.options.DependencyQueueName,
+		quote(s.viewAlias()),
+	)
+
+	// Each pass fails the direct dependents of the records failed by the previous pass
+	for {
+		failedIDs, err := s.MarkQueuedFailed(ctx, []*sqlf.Query{condition}, "a job this job depends on failed or was deleted")
+		if err != nil {
+			return nil, err
+		}
+		if len(failedIDs) == 0 {
+			break
+		}
+
+		ids = append(ids, failedIDs...)
+	}
+	traceLog(log.Int("numIDs", len(ids)))
+
+	return ids, nil
+}
+
+const dependencyFailedCondition = `
+EXISTS (
+	SELECT 1
+	FROM workerutil_job_dependencies d
+	LEFT JOIN %s p ON p.{id} = d.depends_on_job_id
+	WHERE
+		d.queue_name = %s AND
+		d.job_id = %s.{id} AND
+		(p.{id} IS NULL OR p.{state} = 'failed')
+)
+`
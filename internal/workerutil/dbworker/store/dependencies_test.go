@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+func TestAddDependencies(t *testing.T) {
+	db := setupStoreTest(t)
+	s := basestore.NewWithDB(db, sql.TxOptions{})
+	ctx := context.Background()
+
+	if err := AddDependencies(ctx, s, "test", 2, []int{1}); err != nil {
+		t.Fatalf("unexpected error adding dependencies: %s", err)
+	}
+	if err := AddDependencies(ctx, s, "test", 3, []int{1, 2}); err != nil {
+		t.Fatalf("unexpected error adding dependencies: %s", err)
+	}
+	// Cycles are only detected within a queue
+	if err := AddDependencies(ctx, s, "other", 1, []int{3}); err != nil {
+		t.Fatalf("unexpected error adding dependencies: %s", err)
+	}
+
+	cycles := []struct {
+		jobID     int
+		dependsOn []int
+	}{
+		{1, []int{1}},    // self-dependency
+		{1, []int{3}},    // 3 -> 1
+		{1, []int{4, 2}}, // 2 -> 1
+	}
+	for _, cycle := range cycles {
+		if err := AddDependencies(ctx, s, "test", cycle.jobID, cycle.dependsOn); !errors.Is(err, ErrDependencyCycle) {
+			t.Errorf("unexpected error adding dependencies %d -> %v. want=%q have=%v", cycle.jobID, cycle.dependsOn, ErrDependencyCycle, err)
+		}
+	}
+
+	count, _, err := basestore.ScanFirstInt(db.QueryContext(ctx, `SELECT COUNT(*) FROM workerutil_job_dependencies`))
+	if err != nil {
+		t.Fatalf("unexpected error counting dependencies: %s", err)
+	}
+	if count != 4 {
+		t.Errorf("unexpected number of dependencies. want=%d have=%d", 4, count)
+	}
+}
+
+func TestStoreDequeueDependencies(t *testing.T) {
+	db := setupStoreTest(t)
+	ctx := context.Background()
+
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO workerutil_test (id, state, uploaded_at)
+		VALUES
+			(1, 'queued', NOW() - '1 minute'::interval),
+			(2, 'queued', NOW() - '2 minute'::interval),
+			(3, 'queued', NOW() - '3 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+	if err := AddDependencies(ctx, basestore.NewWithDB(db, sql.TxOptions{}), "test", 3, []int{1, 2}); err != nil {
+		t.Fatalf("unexpected error adding dependencies: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.DependencyQueueName = "test"
+	store := testStore(db, options)
+
+	record, ok, err := store.Dequeue(ctx, "test", nil)
+	assertDequeueRecordResult(t, 2, record, ok, err)
+	if _, err := store.MarkComplete(ctx, 2, MarkFinalOptions{}); err != nil {
+		t.Fatalf("unexpected error marking record as complete: %s", err)
+	}
+
+	// Record 3 still depends on the queued record 1
+	record, ok, err = store.Dequeue(ctx, "test", nil)
+	assertDequeueRecordResult(t, 1, record, ok, err)
+	if _, ok, err := store.Dequeue(ctx, "test", nil); err != nil || ok {
+		t.Fatalf("unexpected dequeue result. want=(false, nil) have=(%v, %v)", ok, err)
+	}
+	if _, err := store.MarkComplete(ctx, 1, MarkFinalOptions{}); err != nil {
+		t.Fatalf("unexpected error marking record as complete: %s", err)
+	}
+
+	record, ok, err = store.Dequeue(ctx, "test", nil)
+	assertDequeueRecordResult(t, 3, record, ok, err)
+}
+
+func TestStoreMarkDependentsFailed(t *testing.T) {
+	db := setupStoreTest(t)
+	ctx := context.Background()
+
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO workerutil_test (id, state)
+		VALUES
+			(1, 'failed'),
+			(2, 'queued'),
+			(3, 'queued'),
+			(4, 'completed'),
+			(5, 'queued'),
+			(6, 'queued'),
+			(7, 'queued')
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	s := basestore.NewWithDB(db, sql.TxOptions{})
+	for jobID, dependsOn := range map[int][]int{
+		2: {1},    // depends on a failed record
+		3: {2, 4}, // transitively depends on a failed record
+		5: {4},    // depends on a completed record
+		6: {8},    // depends on a deleted record
+		7: {5},    // depends on a queued record
+	} {
+		if err := AddDependencies(ctx, s, "test", jobID, dependsOn); err != nil {
+			t.Fatalf("unexpected error adding dependencies: %s", err)
+		}
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.DependencyQueueName = "test"
+
+	ids, err := testStore(db, options).MarkDependentsFailed(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error marking dependents as failed: %s", err)
+	}
+	sort.Ints(ids)
+	if diff := cmp.Diff([]int{2, 3, 6}, ids); diff != "" {
+		t.Errorf("unexpected ids (-want +got):\n%s", diff)
+	}
+
+	states, err := basestore.ScanStrings(db.QueryContext(ctx, `SELECT state FROM workerutil_test ORDER BY id`))
+	if err != nil {
+		t.Fatalf("unexpected error querying states: %s", err)
+	}
+	if diff := cmp.Diff([]string{"failed", "failed", "failed", "completed", "queued", "failed", "queued"}, states); diff != "" {
+		t.Errorf("unexpected states (-want +got):\n%s", diff)
+	}
+
+	// Stores without a dependency queue do not consider dependencies
+	if ids, err := testStore(db, defaultTestStoreOptions(nil)).MarkDependentsFailed(ctx); err != nil || len(ids) != 0 {
+		t.Errorf("unexpected result. want=([], nil) have=(%v, %v)", ids, err)
+	}
+}
@@ -10,3 +10,7 @@ var ErrDequeueRace = errors.New("dequeue race")
 
 // ErrNoRecord occurs when a record cannot be selected after it has been locked.
 var ErrNoRecord = errors.New("locked record not found")
+
+// ErrQueueFull occurs when a producer attempts to enqueue a record into a queue whose
+// depth has reached its configured maximum. Producers should back off and retry later.
+var ErrQueueFull = errors.New("queue is full")
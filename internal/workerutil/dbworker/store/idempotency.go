@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+// EnqueueOnce calls enqueue to insert a record into the given queue unless a record was already
+// enqueued into that queue with the same idempotency key within the given window, in which case
+// the identifier of the existing record is returned and enqueue is not called. The returned flag
+// indicates whether a new record was enqueued.
+//
+// The enqueue function is invoked within a transaction and must perform its writes through the
+// store it is given. Concurrent calls with the same queue name and key are serialized.
+func EnqueueOnce(ctx context.Context, s *basestore.Store, queueName, key string, window time.Duration, enqueue func(tx *basestore.Store) (int, error)) (_ int, enqueued bool, err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	if err := tx.Exec(ctx, sqlf.Sprintf(lockIdempotencyKeyQuery, queueName, key)); err != nil {
+		return 0, false, err
+	}
+
+	windowSeconds := int(window / time.Second)
+
+	id, ok, err := basestore.ScanFirstInt(tx.Query(ctx, sqlf.Sprintf(selectIdempotencyKeyQuery, queueName, key, windowSeconds)))
+	if err != nil {
+		return 0, false, err
+	}
+	if ok {
+		return id, false, nil
+	}
+
+	id, err = enqueue(tx)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if err := tx.Exec(ctx, sqlf.Sprintf(upsertIdempotencyKeyQuery, queueName, windowSeconds, queueName, key, id)); err != nil {
+		return 0, false, err
+	}
+
+	return id, true, nil
+}
+
+const lockIdempotencyKeyQuery = `
+-- source: internal/workerutil/dbworker/store/idempotency.go:EnqueueOnce
+SELECT pg_advisory_xact_lock(hashtext(%s || '/' || %s))
+`
+
+const selectIdempotencyKeyQuery = `
+-- source: internal/workerutil/dbworker/store/idempotency.go:EnqueueOnce
+SELECT record_id
+FROM workerutil_idempotency_keys
+WHERE
+	queue_name = %s AND
+	idempotency_key = %s AND
+	created_at > NOW() - (%s * '1 second'::interval)
+`
+
+// The expired keys of the queue are removed along with the upsert, which keeps the table bounded
+// by the number of keys used within the window.
+const upsertIdempotencyKeyQuery = `
+-- source: internal/workerutil/dbworker/store/idempotency.go:EnqueueOnce
+WITH expired AS (
+	DELETE FROM workerutil_idempotency_keys
+	WHERE queue_name = %s AND created_at <= NOW() - (%s * '1 second'::interval)
+)
+INSERT INTO workerutil_idempotency_keys (queue_name, idempotency_key, record_id)
+VALUES (%s, %s, %s)
+ON CONFLICT (queue_name, idempotency_key) DO UPDATE
+SET
+	record_id = EXCLUDED.record_id,
+	created_at = NOW()
+`
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+)
+
+func TestEnqueueOnce(t *testing.T) {
+	db := setupStoreTest(t)
+	s := basestore.NewWithDB(db, sql.TxOptions{})
+	ctx := context.Background()
+
+	nextID := 0
+	enqueue := func(tx *basestore.Store) (int, error) {
+		nextID++
+		if err := tx.Exec(ctx, sqlf.Sprintf("INSERT INTO workerutil_test (id, state) VALUES (%s, 'queued')", nextID)); err != nil {
+			return 0, err
+		}
+		return nextID, nil
+	}
+
+	testCases := []struct {
+		queueName        string
+		key              string
+		expectedID       int
+		expectedEnqueued bool
+	}{
+		{"test", "a", 1, true},
+		{"test", "a", 1, false},
+		{"test", "b", 2, true},
+		{"other", "a", 3, true},
+		{"test", "a", 1, false},
+	}
+
+	for _, testCase := range testCases {
+		id, enqueued, err := EnqueueOnce(ctx, s, testCase.queueName, testCase.key, time.Hour, enqueue)
+		if err != nil {
+			t.Fatalf("unexpected error enqueueing record: %s", err)
+		}
+		if id != testCase.expectedID || enqueued != testCase.expectedEnqueued {
+			t.Errorf("unexpected result for %s/%s. want=(%d, %v) have=(%d, %v)", testCase.queueName, testCase.key, testCase.expectedID, testCase.expectedEnqueued, id, enqueued)
+		}
+	}
+
+	// Expire the key of the first record
+	if _, err := db.ExecContext(ctx, `UPDATE workerutil_idempotency_keys SET created_at = NOW() - '2 hours'::interval WHERE record_id = 1`); err != nil {
+		t.Fatalf("unexpected error updating keys: %s", err)
+	}
+
+	id, enqueued, err := EnqueueOnce(ctx, s, "test", "a", time.Hour, enqueue)
+	if err != nil {
+		t.Fatalf("unexpected error enqueueing record: %s", err)
+	}
+	if id != 4 || !enqueued {
+		t.Errorf("unexpected result for expired key. want=(%d, %v) have=(%d, %v)", 4, true, id, enqueued)
+	}
+
+	count, _, err := basestore.ScanFirstInt(db.QueryContext(ctx, `SELECT COUNT(*) FROM workerutil_test`))
+	if err != nil {
+		t.Fatalf("unexpected error counting records: %s", err)
+	}
+	if count != 4 {
+		t.Errorf("unexpected record count. want=%d have=%d", 4, count)
+	}
+}
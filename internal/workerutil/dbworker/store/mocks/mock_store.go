@@ -21,24 +21,45 @@ type MockStore struct {
 	// AddExecutionLogEntryFunc is an instance of a mock function object
 	// controlling the behavior of the method AddExecutionLogEntry.
 	AddExecutionLogEntryFunc *StoreAddExecutionLogEntryFunc
+	// DeleteFunc is an instance of a mock function object controlling the
+	// behavior of the method Delete.
+	DeleteFunc *StoreDeleteFunc
 	// DequeueFunc is an instance of a mock function object controlling the
 	// behavior of the method Dequeue.
 	DequeueFunc *StoreDequeueFunc
+	// DequeueBatchFunc is an instance of a mock function object controlling
+	// the behavior of the method DequeueBatch.
+	DequeueBatchFunc *StoreDequeueBatchFunc
+	// GetFunc is an instance of a mock function object controlling the
+	// behavior of the method Get.
+	GetFunc *StoreGetFunc
 	// HandleFunc is an instance of a mock function object controlling the
 	// behavior of the method Handle.
 	HandleFunc *StoreHandleFunc
 	// HeartbeatFunc is an instance of a mock function object controlling
 	// the behavior of the method Heartbeat.
 	HeartbeatFunc *StoreHeartbeatFunc
+	// ListFunc is an instance of a mock function object controlling the
+	// behavior of the method List.
+	ListFunc *StoreListFunc
 	// MarkCompleteFunc is an instance of a mock function object controlling
 	// the behavior of the method MarkComplete.
 	MarkCompleteFunc *StoreMarkCompleteFunc
+	// MarkDependentsFailedFunc is an instance of a mock function object
+	// controlling the behavior of the method MarkDependentsFailed.
+	MarkDependentsFailedFunc *StoreMarkDependentsFailedFunc
 	// MarkErroredFunc is an instance of a mock function object controlling
 	// the behavior of the method MarkErrored.
 	MarkErroredFunc *StoreMarkErroredFunc
 	// MarkFailedFunc is an instance of a mock function object controlling
 	// the behavior of the method MarkFailed.
 	MarkFailedFunc *StoreMarkFailedFunc
+	// MarkQueuedFailedFunc is an instance of a mock function object
+	// controlling the behavior of the method MarkQueuedFailed.
+	MarkQueuedFailedFunc *StoreMarkQueuedFailedFunc
+	// ProcessingCountFunc is an instance of a mock function object
+	// controlling the behavior of the method ProcessingCount.
+	ProcessingCountFunc *StoreProcessingCountFunc
 	// QueuedCountFunc is an instance of a mock function object controlling
 	// the behavior of the method QueuedCount.
 	QueuedCountFunc *StoreQueuedCountFunc
@@ -48,6 +69,9 @@ type MockStore struct {
 	// ResetStalledFunc is an instance of a mock function object controlling
 	// the behavior of the method ResetStalled.
 	ResetStalledFunc *StoreResetStalledFunc
+	// ResetWorkerFunc is an instance of a mock function object controlling
+	// the behavior of the method ResetWorker.
+	ResetWorkerFunc *StoreResetWorkerFunc
 	// UpdateExecutionLogEntryFunc is an instance of a mock function object
 	// controlling the behavior of the method UpdateExecutionLogEntry.
 	UpdateExecutionLogEntryFunc *StoreUpdateExecutionLogEntryFunc
@@ -62,11 +86,26 @@ func NewMockStore() *MockStore {
 				return 0, nil
 			},
 		},
+		DeleteFunc: &StoreDeleteFunc{
+			defaultHook: func(context.Context, int) (bool, error) {
+				return false, nil
+			},
+		},
 		DequeueFunc: &StoreDequeueFunc{
 			defaultHook: func(context.Context, string, []*sqlf.Query) (workerutil.Record, bool, error) {
 				return nil, false, nil
 			},
 		},
+		DequeueBatchFunc: &StoreDequeueBatchFunc{
+			defaultHook: func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error) {
+				return nil, nil
+			},
+		},
+		GetFunc: &StoreGetFunc{
+			defaultHook: func(context.Context, int) (workerutil.Record, bool, error) {
+				return nil, false, nil
+			},
+		},
 		HandleFunc: &StoreHandleFunc{
 			defaultHook: func() *basestore.TransactableHandle {
 				return nil
@@ -77,11 +116,21 @@ func NewMockStore() *MockStore {
 				return nil, nil
 			},
 		},
+		ListFunc: &StoreListFunc{
+			defaultHook: func(context.Context, store.ListOptions) ([]workerutil.Record, error) {
+				return nil, nil
+			},
+		},
 		MarkCompleteFunc: &StoreMarkCompleteFunc{
 			defaultHook: func(context.Context, int, store.MarkFinalOptions) (bool, error) {
 				return false, nil
 			},
 		},
+		MarkDependentsFailedFunc: &StoreMarkDependentsFailedFunc{
+			defaultHook: func(context.Context) ([]int, error) {
+				return nil, nil
+			},
+		},
 		MarkErroredFunc: &StoreMarkErroredFunc{
 			defaultHook: func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
 				return false, nil
@@ -92,6 +141,16 @@ func NewMockStore() *MockStore {
 				return false, nil
 			},
 		},
+		MarkQueuedFailedFunc: &StoreMarkQueuedFailedFunc{
+			defaultHook: func(context.Context, []*sqlf.Query, string) ([]int, error) {
+				return nil, nil
+			},
+		},
+		ProcessingCountFunc: &StoreProcessingCountFunc{
+			defaultHook: func(context.Context, []*sqlf.Query) (int, error) {
+				return 0, nil
+			},
+		},
 		QueuedCountFunc: &StoreQueuedCountFunc{
 			defaultHook: func(context.Context, []*sqlf.Query) (int, error) {
 				return 0, nil
@@ -107,6 +166,11 @@ func NewMockStore() *MockStore {
 				return nil, nil, nil
 			},
 		},
+		ResetWorkerFunc: &StoreResetWorkerFunc{
+			defaultHook: func(context.Context, string) ([]int, []int, error) {
+				return nil, nil, nil
+			},
+		},
 		UpdateExecutionLogEntryFunc: &StoreUpdateExecutionLogEntryFunc{
 			defaultHook: func(context.Context, int, int, workerutil.ExecutionLogEntry, store.ExecutionLogEntryOptions) error {
 				return nil
@@ -122,24 +186,45 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		AddExecutionLogEntryFunc: &StoreAddExecutionLogEntryFunc{
 			defaultHook: i.AddExecutionLogEntry,
 		},
+		DeleteFunc: &StoreDeleteFunc{
+			defaultHook: i.Delete,
+		},
 		DequeueFunc: &StoreDequeueFunc{
 			defaultHook: i.Dequeue,
 		},
+		DequeueBatchFunc: &StoreDequeueBatchFunc{
+			defaultHook: i.DequeueBatch,
+		},
+		GetFunc: &StoreGetFunc{
+			defaultHook: i.Get,
+		},
 		HandleFunc: &StoreHandleFunc{
 			defaultHook: i.Handle,
 		},
 		HeartbeatFunc: &StoreHeartbeatFunc{
 			defaultHook: i.Heartbeat,
 		},
+		ListFunc: &StoreListFunc{
+			defaultHook: i.List,
+		},
 		MarkCompleteFunc: &StoreMarkCompleteFunc{
 			defaultHook: i.MarkComplete,
 		},
+		MarkDependentsFailedFunc: &StoreMarkDependentsFailedFunc{
+			defaultHook: i.MarkDependentsFailed,
+		},
 		MarkErroredFunc: &StoreMarkErroredFunc{
 			defaultHook: i.MarkErrored,
 		},
 		MarkFailedFunc: &StoreMarkFailedFunc{
 			defaultHook: i.MarkFailed,
 		},
+		MarkQueuedFailedFunc: &StoreMarkQueuedFailedFunc{
+			defaultHook: i.MarkQueuedFailed,
+		},
+		ProcessingCountFunc: &StoreProcessingCountFunc{
+			defaultHook: i.ProcessingCount,
+		},
 		QueuedCountFunc: &StoreQueuedCountFunc{
 			defaultHook: i.QueuedCount,
 		},
@@ -149,6 +234,9 @@ func NewMockStoreFrom(i store.Store) *MockStore {
 		ResetStalledFunc: &StoreResetStalledFunc{
 			defaultHook: i.ResetStalled,
 		},
+		ResetWorkerFunc: &StoreResetWorkerFunc{
+			defaultHook: i.ResetWorker,
+		},
 		UpdateExecutionLogEntryFunc: &StoreUpdateExecutionLogEntryFunc{
 			defaultHook: i.UpdateExecutionLogEntry,
 		},
@@ -270,6 +358,114 @@ func (c StoreAddExecutionLogEntryFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// StoreDeleteFunc describes the behavior when the Delete method of the
+// parent MockStore instance is invoked.
+type StoreDeleteFunc struct {
+	defaultHook func(context.Context, int) (bool, error)
+	hooks       []func(context.Context, int) (bool, error)
+	history     []StoreDeleteFuncCall
+	mutex       sync.Mutex
+}
+
+// Delete delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockStore) Delete(v0 context.Context, v1 int) (bool, error) {
+	r0, r1 := m.DeleteFunc.nextHook()(v0, v1)
+	m.DeleteFunc.appendCall(StoreDeleteFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Delete method of the
+// parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreDeleteFunc) SetDefaultHook(hook func(context.Context, int) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Delete method of the parent MockStore instance invokes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *StoreDeleteFunc) PushHook(hook func(context.Context, int) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreDeleteFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreDeleteFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, int) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreDeleteFunc) nextHook() func(context.Context, int) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreDeleteFunc) appendCall(r0 StoreDeleteFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreDeleteFuncCall objects describing the
+// invocations of this function.
+func (f *StoreDeleteFunc) History() []StoreDeleteFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreDeleteFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreDeleteFuncCall is an object that describes an invocation of method
+// Delete on an instance of MockStore.
+type StoreDeleteFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreDeleteFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreDeleteFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // StoreDequeueFunc describes the behavior when the Dequeue method of the
 // parent MockStore instance is invoked.
 type StoreDequeueFunc struct {
@@ -384,34 +580,34 @@ func (c StoreDequeueFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
-// StoreHandleFunc describes the behavior when the Handle method of the
-// parent MockStore instance is invoked.
-type StoreHandleFunc struct {
-	defaultHook func() *basestore.TransactableHandle
-	hooks       []func() *basestore.TransactableHandle
-	history     []StoreHandleFuncCall
+// StoreDequeueBatchFunc describes the behavior when the DequeueBatch method
+// of the parent MockStore instance is invoked.
+type StoreDequeueBatchFunc struct {
+	defaultHook func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error)
+	hooks       []func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error)
+	history     []StoreDequeueBatchFuncCall
 	mutex       sync.Mutex
 }
 
-// Handle delegates to the next hook function in the queue and stores the
-// parameter and result values of this invocation.
-func (m *MockStore) Handle() *basestore.TransactableHandle {
-	r0 := m.HandleFunc.nextHook()()
-	m.HandleFunc.appendCall(StoreHandleFuncCall{r0})
-	return r0
+// DequeueBatch delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockStore) DequeueBatch(v0 context.Context, v1 string, v2 []*sqlf.Query, v3 int) ([]workerutil.Record, error) {
+	r0, r1 := m.DequeueBatchFunc.nextHook()(v0, v1, v2, v3)
+	m.DequeueBatchFunc.appendCall(StoreDequeueBatchFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the Handle method of the
-// parent MockStore instance is invoked and the hook queue is empty.
-func (f *StoreHandleFunc) SetDefaultHook(hook func() *basestore.TransactableHandle) {
+// SetDefaultHook sets function that is called when the DequeueBatch method
+// of the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreDequeueBatchFunc) SetDefaultHook(hook func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// Handle method of the parent MockStore instance invokes the hook at the
-// front of the queue and discards it. After the queue is empty, the default
-// hook function is invoked for any future action.
-func (f *StoreHandleFunc) PushHook(hook func() *basestore.TransactableHandle) {
+// DequeueBatch method of the parent MockStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreDequeueBatchFunc) PushHook(hook func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -419,21 +615,21 @@ func (f *StoreHandleFunc) PushHook(hook func() *basestore.TransactableHandle) {
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *StoreHandleFunc) SetDefaultReturn(r0 *basestore.TransactableHandle) {
-	f.SetDefaultHook(func() *basestore.TransactableHandle {
-		return r0
+func (f *StoreDequeueBatchFunc) SetDefaultReturn(r0 []workerutil.Record, r1 error) {
+	f.SetDefaultHook(func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error) {
+		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *StoreHandleFunc) PushReturn(r0 *basestore.TransactableHandle) {
-	f.PushHook(func() *basestore.TransactableHandle {
-		return r0
+func (f *StoreDequeueBatchFunc) PushReturn(r0 []workerutil.Record, r1 error) {
+	f.PushHook(func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error) {
+		return r0, r1
 	})
 }
 
-func (f *StoreHandleFunc) nextHook() func() *basestore.TransactableHandle {
+func (f *StoreDequeueBatchFunc) nextHook() func(context.Context, string, []*sqlf.Query, int) ([]workerutil.Record, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -446,71 +642,86 @@ func (f *StoreHandleFunc) nextHook() func() *basestore.TransactableHandle {
 	return hook
 }
 
-func (f *StoreHandleFunc) appendCall(r0 StoreHandleFuncCall) {
+func (f *StoreDequeueBatchFunc) appendCall(r0 StoreDequeueBatchFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of StoreHandleFuncCall objects describing the
-// invocations of this function.
-func (f *StoreHandleFunc) History() []StoreHandleFuncCall {
+// History returns a sequence of StoreDequeueBatchFuncCall objects
+// describing the invocations of this function.
+func (f *StoreDequeueBatchFunc) History() []StoreDequeueBatchFuncCall {
 	f.mutex.Lock()
-	history := make([]StoreHandleFuncCall, len(f.history))
+	history := make([]StoreDequeueBatchFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// StoreHandleFuncCall is an object that describes an invocation of method
-// Handle on an instance of MockStore.
-type StoreHandleFuncCall struct {
+// StoreDequeueBatchFuncCall is an object that describes an invocation of
+// method DequeueBatch on an instance of MockStore.
+type StoreDequeueBatchFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []*sqlf.Query
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 *basestore.TransactableHandle
+	Result0 []workerutil.Record
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
 }
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c StoreHandleFuncCall) Args() []interface{} {
-	return []interface{}{}
+func (c StoreDequeueBatchFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c StoreHandleFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0}
+func (c StoreDequeueBatchFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
-// StoreHeartbeatFunc describes the behavior when the Heartbeat method of
-// the parent MockStore instance is invoked.
-type StoreHeartbeatFunc struct {
-	defaultHook func(context.Context, []int, store.HeartbeatOptions) ([]int, error)
-	hooks       []func(context.Context, []int, store.HeartbeatOptions) ([]int, error)
-	history     []StoreHeartbeatFuncCall
+// StoreGetFunc describes the behavior when the Get method of the parent
+// MockStore instance is invoked.
+type StoreGetFunc struct {
+	defaultHook func(context.Context, int) (workerutil.Record, bool, error)
+	hooks       []func(context.Context, int) (workerutil.Record, bool, error)
+	history     []StoreGetFuncCall
 	mutex       sync.Mutex
 }
 
-// Heartbeat delegates to the next hook function in the queue and stores the
+// Get delegates to the next hook function in the queue and stores the
 // parameter and result values of this invocation.
-func (m *MockStore) Heartbeat(v0 context.Context, v1 []int, v2 store.HeartbeatOptions) ([]int, error) {
-	r0, r1 := m.HeartbeatFunc.nextHook()(v0, v1, v2)
-	m.HeartbeatFunc.appendCall(StoreHeartbeatFuncCall{v0, v1, v2, r0, r1})
-	return r0, r1
+func (m *MockStore) Get(v0 context.Context, v1 int) (workerutil.Record, bool, error) {
+	r0, r1, r2 := m.GetFunc.nextHook()(v0, v1)
+	m.GetFunc.appendCall(StoreGetFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
 }
 
-// SetDefaultHook sets function that is called when the Heartbeat method of
-// the parent MockStore instance is invoked and the hook queue is empty.
-func (f *StoreHeartbeatFunc) SetDefaultHook(hook func(context.Context, []int, store.HeartbeatOptions) ([]int, error)) {
+// SetDefaultHook sets function that is called when the Get method of the
+// parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreGetFunc) SetDefaultHook(hook func(context.Context, int) (workerutil.Record, bool, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// Heartbeat method of the parent MockStore instance invokes the hook at the
-// front of the queue and discards it. After the queue is empty, the default
-// hook function is invoked for any future action.
-func (f *StoreHeartbeatFunc) PushHook(hook func(context.Context, []int, store.HeartbeatOptions) ([]int, error)) {
+// Get method of the parent MockStore instance invokes the hook at the front
+// of the queue and discards it. After the queue is empty, the default hook
+// function is invoked for any future action.
+func (f *StoreGetFunc) PushHook(hook func(context.Context, int) (workerutil.Record, bool, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -518,21 +729,21 @@ func (f *StoreHeartbeatFunc) PushHook(hook func(context.Context, []int, store.He
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *StoreHeartbeatFunc) SetDefaultReturn(r0 []int, r1 error) {
-	f.SetDefaultHook(func(context.Context, []int, store.HeartbeatOptions) ([]int, error) {
-		return r0, r1
+func (f *StoreGetFunc) SetDefaultReturn(r0 workerutil.Record, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) (workerutil.Record, bool, error) {
+		return r0, r1, r2
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *StoreHeartbeatFunc) PushReturn(r0 []int, r1 error) {
-	f.PushHook(func(context.Context, []int, store.HeartbeatOptions) ([]int, error) {
-		return r0, r1
+func (f *StoreGetFunc) PushReturn(r0 workerutil.Record, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int) (workerutil.Record, bool, error) {
+		return r0, r1, r2
 	})
 }
 
-func (f *StoreHeartbeatFunc) nextHook() func(context.Context, []int, store.HeartbeatOptions) ([]int, error) {
+func (f *StoreGetFunc) nextHook() func(context.Context, int) (workerutil.Record, bool, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -545,38 +756,687 @@ func (f *StoreHeartbeatFunc) nextHook() func(context.Context, []int, store.Heart
 	return hook
 }
 
-func (f *StoreHeartbeatFunc) appendCall(r0 StoreHeartbeatFuncCall) {
+func (f *StoreGetFunc) appendCall(r0 StoreGetFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of StoreHeartbeatFuncCall objects describing
-// the invocations of this function.
-func (f *StoreHeartbeatFunc) History() []StoreHeartbeatFuncCall {
+// History returns a sequence of StoreGetFuncCall objects describing the
+// invocations of this function.
+func (f *StoreGetFunc) History() []StoreGetFuncCall {
 	f.mutex.Lock()
-	history := make([]StoreHeartbeatFuncCall, len(f.history))
+	history := make([]StoreGetFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// StoreHeartbeatFuncCall is an object that describes an invocation of
-// method Heartbeat on an instance of MockStore.
-type StoreHeartbeatFuncCall struct {
+// StoreGetFuncCall is an object that describes an invocation of method Get
+// on an instance of MockStore.
+type StoreGetFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 []int
-	// Arg2 is the value of the 3rd argument passed to this method
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Arg2 store.HeartbeatOptions
+	Result0 workerutil.Record
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreGetFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreGetFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// StoreHandleFunc describes the behavior when the Handle method of the
+// parent MockStore instance is invoked.
+type StoreHandleFunc struct {
+	defaultHook func() *basestore.TransactableHandle
+	hooks       []func() *basestore.TransactableHandle
+	history     []StoreHandleFuncCall
+	mutex       sync.Mutex
+}
+
+// Handle delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockStore) Handle() *basestore.TransactableHandle {
+	r0 := m.HandleFunc.nextHook()()
+	m.HandleFunc.appendCall(StoreHandleFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Handle method of the
+// parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreHandleFunc) SetDefaultHook(hook func() *basestore.TransactableHandle) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Handle method of the parent MockStore instance invokes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *StoreHandleFunc) PushHook(hook func() *basestore.TransactableHandle) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreHandleFunc) SetDefaultReturn(r0 *basestore.TransactableHandle) {
+	f.SetDefaultHook(func() *basestore.TransactableHandle {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreHandleFunc) PushReturn(r0 *basestore.TransactableHandle) {
+	f.PushHook(func() *basestore.TransactableHandle {
+		return r0
+	})
+}
+
+func (f *StoreHandleFunc) nextHook() func() *basestore.TransactableHandle {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreHandleFunc) appendCall(r0 StoreHandleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreHandleFuncCall objects describing the
+// invocations of this function.
+func (f *StoreHandleFunc) History() []StoreHandleFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreHandleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreHandleFuncCall is an object that describes an invocation of method
+// Handle on an instance of MockStore.
+type StoreHandleFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 *basestore.TransactableHandle
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreHandleFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreHandleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// StoreHeartbeatFunc describes the behavior when the Heartbeat method of
+// the parent MockStore instance is invoked.
+type StoreHeartbeatFunc struct {
+	defaultHook func(context.Context, []int, store.HeartbeatOptions) ([]int, error)
+	hooks       []func(context.Context, []int, store.HeartbeatOptions) ([]int, error)
+	history     []StoreHeartbeatFuncCall
+	mutex       sync.Mutex
+}
+
+// Heartbeat delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockStore) Heartbeat(v0 context.Context, v1 []int, v2 store.HeartbeatOptions) ([]int, error) {
+	r0, r1 := m.HeartbeatFunc.nextHook()(v0, v1, v2)
+	m.HeartbeatFunc.appendCall(StoreHeartbeatFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Heartbeat method of
+// the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreHeartbeatFunc) SetDefaultHook(hook func(context.Context, []int, store.HeartbeatOptions) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Heartbeat method of the parent MockStore instance invokes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *StoreHeartbeatFunc) PushHook(hook func(context.Context, []int, store.HeartbeatOptions) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreHeartbeatFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context, []int, store.HeartbeatOptions) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreHeartbeatFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context, []int, store.HeartbeatOptions) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreHeartbeatFunc) nextHook() func(context.Context, []int, store.HeartbeatOptions) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreHeartbeatFunc) appendCall(r0 StoreHeartbeatFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreHeartbeatFuncCall objects describing
+// the invocations of this function.
+func (f *StoreHeartbeatFunc) History() []StoreHeartbeatFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreHeartbeatFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreHeartbeatFuncCall is an object that describes an invocation of
+// method Heartbeat on an instance of MockStore.
+type StoreHeartbeatFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 store.HeartbeatOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreHeartbeatFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreHeartbeatFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// StoreListFunc describes the behavior when the List method of the parent
+// MockStore instance is invoked.
+type StoreListFunc struct {
+	defaultHook func(context.Context, store.ListOptions) ([]workerutil.Record, error)
+	hooks       []func(context.Context, store.ListOptions) ([]workerutil.Record, error)
+	history     []StoreListFuncCall
+	mutex       sync.Mutex
+}
+
+// List delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockStore) List(v0 context.Context, v1 store.ListOptions) ([]workerutil.Record, error) {
+	r0, r1 := m.ListFunc.nextHook()(v0, v1)
+	m.ListFunc.appendCall(StoreListFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the List method of the
+// parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreListFunc) SetDefaultHook(hook func(context.Context, store.ListOptions) ([]workerutil.Record, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// List method of the parent MockStore instance invokes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *StoreListFunc) PushHook(hook func(context.Context, store.ListOptions) ([]workerutil.Record, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreListFunc) SetDefaultReturn(r0 []workerutil.Record, r1 error) {
+	f.SetDefaultHook(func(context.Context, store.ListOptions) ([]workerutil.Record, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreListFunc) PushReturn(r0 []workerutil.Record, r1 error) {
+	f.PushHook(func(context.Context, store.ListOptions) ([]workerutil.Record, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreListFunc) nextHook() func(context.Context, store.ListOptions) ([]workerutil.Record, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreListFunc) appendCall(r0 StoreListFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreListFuncCall objects describing the
+// invocations of this function.
+func (f *StoreListFunc) History() []StoreListFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreListFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreListFuncCall is an object that describes an invocation of method
+// List on an instance of MockStore.
+type StoreListFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 store.ListOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []workerutil.Record
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreListFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreListFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// StoreMarkCompleteFunc describes the behavior when the MarkComplete method
+// of the parent MockStore instance is invoked.
+type StoreMarkCompleteFunc struct {
+	defaultHook func(context.Context, int, store.MarkFinalOptions) (bool, error)
+	hooks       []func(context.Context, int, store.MarkFinalOptions) (bool, error)
+	history     []StoreMarkCompleteFuncCall
+	mutex       sync.Mutex
+}
+
+// MarkComplete delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockStore) MarkComplete(v0 context.Context, v1 int, v2 store.MarkFinalOptions) (bool, error) {
+	r0, r1 := m.MarkCompleteFunc.nextHook()(v0, v1, v2)
+	m.MarkCompleteFunc.appendCall(StoreMarkCompleteFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the MarkComplete method
+// of the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreMarkCompleteFunc) SetDefaultHook(hook func(context.Context, int, store.MarkFinalOptions) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// MarkComplete method of the parent MockStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreMarkCompleteFunc) PushHook(hook func(context.Context, int, store.MarkFinalOptions) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreMarkCompleteFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, store.MarkFinalOptions) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreMarkCompleteFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, int, store.MarkFinalOptions) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreMarkCompleteFunc) nextHook() func(context.Context, int, store.MarkFinalOptions) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreMarkCompleteFunc) appendCall(r0 StoreMarkCompleteFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreMarkCompleteFuncCall objects
+// describing the invocations of this function.
+func (f *StoreMarkCompleteFunc) History() []StoreMarkCompleteFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreMarkCompleteFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreMarkCompleteFuncCall is an object that describes an invocation of
+// method MarkComplete on an instance of MockStore.
+type StoreMarkCompleteFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 store.MarkFinalOptions
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 bool
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreMarkCompleteFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreMarkCompleteFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// StoreMarkDependentsFailedFunc describes the behavior when the
+// MarkDependentsFailed method of the parent MockStore instance is invoked.
+type StoreMarkDependentsFailedFunc struct {
+	defaultHook func(context.Context) ([]int, error)
+	hooks       []func(context.Context) ([]int, error)
+	history     []StoreMarkDependentsFailedFuncCall
+	mutex       sync.Mutex
+}
+
+// MarkDependentsFailed delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) MarkDependentsFailed(v0 context.Context) ([]int, error) {
+	r0, r1 := m.MarkDependentsFailedFunc.nextHook()(v0)
+	m.MarkDependentsFailedFunc.appendCall(StoreMarkDependentsFailedFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the MarkDependentsFailed
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreMarkDependentsFailedFunc) SetDefaultHook(hook func(context.Context) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// MarkDependentsFailed method of the parent MockStore instance invokes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *StoreMarkDependentsFailedFunc) PushHook(hook func(context.Context) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreMarkDependentsFailedFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreMarkDependentsFailedFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreMarkDependentsFailedFunc) nextHook() func(context.Context) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreMarkDependentsFailedFunc) appendCall(r0 StoreMarkDependentsFailedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreMarkDependentsFailedFuncCall objects
+// describing the invocations of this function.
+func (f *StoreMarkDependentsFailedFunc) History() []StoreMarkDependentsFailedFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreMarkDependentsFailedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreMarkDependentsFailedFuncCall is an object that describes an
+// invocation of method MarkDependentsFailed on an instance of MockStore.
+type StoreMarkDependentsFailedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreMarkDependentsFailedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreMarkDependentsFailedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// StoreMarkErroredFunc describes the behavior when the MarkErrored method
+// of the parent MockStore instance is invoked.
+type StoreMarkErroredFunc struct {
+	defaultHook func(context.Context, int, string, store.MarkFinalOptions) (bool, error)
+	hooks       []func(context.Context, int, string, store.MarkFinalOptions) (bool, error)
+	history     []StoreMarkErroredFuncCall
+	mutex       sync.Mutex
+}
+
+// MarkErrored delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockStore) MarkErrored(v0 context.Context, v1 int, v2 string, v3 store.MarkFinalOptions) (bool, error) {
+	r0, r1 := m.MarkErroredFunc.nextHook()(v0, v1, v2, v3)
+	m.MarkErroredFunc.appendCall(StoreMarkErroredFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the MarkErrored method
+// of the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreMarkErroredFunc) SetDefaultHook(hook func(context.Context, int, string, store.MarkFinalOptions) (bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// MarkErrored method of the parent MockStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreMarkErroredFunc) PushHook(hook func(context.Context, int, string, store.MarkFinalOptions) (bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreMarkErroredFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreMarkErroredFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
+		return r0, r1
+	})
+}
+
+func (f *StoreMarkErroredFunc) nextHook() func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreMarkErroredFunc) appendCall(r0 StoreMarkErroredFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreMarkErroredFuncCall objects describing
+// the invocations of this function.
+func (f *StoreMarkErroredFunc) History() []StoreMarkErroredFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreMarkErroredFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreMarkErroredFuncCall is an object that describes an invocation of
+// method MarkErrored on an instance of MockStore.
+type StoreMarkErroredFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 store.MarkFinalOptions
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 []int
+	Result0 bool
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -584,44 +1444,44 @@ type StoreHeartbeatFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c StoreHeartbeatFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+func (c StoreMarkErroredFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c StoreHeartbeatFuncCall) Results() []interface{} {
+func (c StoreMarkErroredFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// StoreMarkCompleteFunc describes the behavior when the MarkComplete method
-// of the parent MockStore instance is invoked.
-type StoreMarkCompleteFunc struct {
-	defaultHook func(context.Context, int, store.MarkFinalOptions) (bool, error)
-	hooks       []func(context.Context, int, store.MarkFinalOptions) (bool, error)
-	history     []StoreMarkCompleteFuncCall
+// StoreMarkFailedFunc describes the behavior when the MarkFailed method of
+// the parent MockStore instance is invoked.
+type StoreMarkFailedFunc struct {
+	defaultHook func(context.Context, int, string, store.MarkFinalOptions) (bool, error)
+	hooks       []func(context.Context, int, string, store.MarkFinalOptions) (bool, error)
+	history     []StoreMarkFailedFuncCall
 	mutex       sync.Mutex
 }
 
-// MarkComplete delegates to the next hook function in the queue and stores
+// MarkFailed delegates to the next hook function in the queue and stores
 // the parameter and result values of this invocation.
-func (m *MockStore) MarkComplete(v0 context.Context, v1 int, v2 store.MarkFinalOptions) (bool, error) {
-	r0, r1 := m.MarkCompleteFunc.nextHook()(v0, v1, v2)
-	m.MarkCompleteFunc.appendCall(StoreMarkCompleteFuncCall{v0, v1, v2, r0, r1})
+func (m *MockStore) MarkFailed(v0 context.Context, v1 int, v2 string, v3 store.MarkFinalOptions) (bool, error) {
+	r0, r1 := m.MarkFailedFunc.nextHook()(v0, v1, v2, v3)
+	m.MarkFailedFunc.appendCall(StoreMarkFailedFuncCall{v0, v1, v2, v3, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the MarkComplete method
-// of the parent MockStore instance is invoked and the hook queue is empty.
-func (f *StoreMarkCompleteFunc) SetDefaultHook(hook func(context.Context, int, store.MarkFinalOptions) (bool, error)) {
+// SetDefaultHook sets function that is called when the MarkFailed method of
+// the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreMarkFailedFunc) SetDefaultHook(hook func(context.Context, int, string, store.MarkFinalOptions) (bool, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// MarkComplete method of the parent MockStore instance invokes the hook at
+// MarkFailed method of the parent MockStore instance invokes the hook at
 // the front of the queue and discards it. After the queue is empty, the
 // default hook function is invoked for any future action.
-func (f *StoreMarkCompleteFunc) PushHook(hook func(context.Context, int, store.MarkFinalOptions) (bool, error)) {
+func (f *StoreMarkFailedFunc) PushHook(hook func(context.Context, int, string, store.MarkFinalOptions) (bool, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -629,21 +1489,21 @@ func (f *StoreMarkCompleteFunc) PushHook(hook func(context.Context, int, store.M
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *StoreMarkCompleteFunc) SetDefaultReturn(r0 bool, r1 error) {
-	f.SetDefaultHook(func(context.Context, int, store.MarkFinalOptions) (bool, error) {
+func (f *StoreMarkFailedFunc) SetDefaultReturn(r0 bool, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *StoreMarkCompleteFunc) PushReturn(r0 bool, r1 error) {
-	f.PushHook(func(context.Context, int, store.MarkFinalOptions) (bool, error) {
+func (f *StoreMarkFailedFunc) PushReturn(r0 bool, r1 error) {
+	f.PushHook(func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
 		return r0, r1
 	})
 }
 
-func (f *StoreMarkCompleteFunc) nextHook() func(context.Context, int, store.MarkFinalOptions) (bool, error) {
+func (f *StoreMarkFailedFunc) nextHook() func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -656,26 +1516,26 @@ func (f *StoreMarkCompleteFunc) nextHook() func(context.Context, int, store.Mark
 	return hook
 }
 
-func (f *StoreMarkCompleteFunc) appendCall(r0 StoreMarkCompleteFuncCall) {
+func (f *StoreMarkFailedFunc) appendCall(r0 StoreMarkFailedFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of StoreMarkCompleteFuncCall objects
-// describing the invocations of this function.
-func (f *StoreMarkCompleteFunc) History() []StoreMarkCompleteFuncCall {
+// History returns a sequence of StoreMarkFailedFuncCall objects describing
+// the invocations of this function.
+func (f *StoreMarkFailedFunc) History() []StoreMarkFailedFuncCall {
 	f.mutex.Lock()
-	history := make([]StoreMarkCompleteFuncCall, len(f.history))
+	history := make([]StoreMarkFailedFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// StoreMarkCompleteFuncCall is an object that describes an invocation of
-// method MarkComplete on an instance of MockStore.
-type StoreMarkCompleteFuncCall struct {
+// StoreMarkFailedFuncCall is an object that describes an invocation of
+// method MarkFailed on an instance of MockStore.
+type StoreMarkFailedFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
@@ -684,7 +1544,10 @@ type StoreMarkCompleteFuncCall struct {
 	Arg1 int
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
-	Arg2 store.MarkFinalOptions
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 store.MarkFinalOptions
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 bool
@@ -695,44 +1558,45 @@ type StoreMarkCompleteFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c StoreMarkCompleteFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+func (c StoreMarkFailedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c StoreMarkCompleteFuncCall) Results() []interface{} {
+func (c StoreMarkFailedFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// StoreMarkErroredFunc describes the behavior when the MarkErrored method
-// of the parent MockStore instance is invoked.
-type StoreMarkErroredFunc struct {
-	defaultHook func(context.Context, int, string, store.MarkFinalOptions) (bool, error)
-	hooks       []func(context.Context, int, string, store.MarkFinalOptions) (bool, error)
-	history     []StoreMarkErroredFuncCall
+// StoreMarkQueuedFailedFunc describes the behavior when the
+// MarkQueuedFailed method of the parent MockStore instance is invoked.
+type StoreMarkQueuedFailedFunc struct {
+	defaultHook func(context.Context, []*sqlf.Query, string) ([]int, error)
+	hooks       []func(context.Context, []*sqlf.Query, string) ([]int, error)
+	history     []StoreMarkQueuedFailedFuncCall
 	mutex       sync.Mutex
 }
 
-// MarkErrored delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockStore) MarkErrored(v0 context.Context, v1 int, v2 string, v3 store.MarkFinalOptions) (bool, error) {
-	r0, r1 := m.MarkErroredFunc.nextHook()(v0, v1, v2, v3)
-	m.MarkErroredFunc.appendCall(StoreMarkErroredFuncCall{v0, v1, v2, v3, r0, r1})
+// MarkQueuedFailed delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) MarkQueuedFailed(v0 context.Context, v1 []*sqlf.Query, v2 string) ([]int, error) {
+	r0, r1 := m.MarkQueuedFailedFunc.nextHook()(v0, v1, v2)
+	m.MarkQueuedFailedFunc.appendCall(StoreMarkQueuedFailedFuncCall{v0, v1, v2, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the MarkErrored method
-// of the parent MockStore instance is invoked and the hook queue is empty.
-func (f *StoreMarkErroredFunc) SetDefaultHook(hook func(context.Context, int, string, store.MarkFinalOptions) (bool, error)) {
+// SetDefaultHook sets function that is called when the MarkQueuedFailed
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreMarkQueuedFailedFunc) SetDefaultHook(hook func(context.Context, []*sqlf.Query, string) ([]int, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// MarkErrored method of the parent MockStore instance invokes the hook at
-// the front of the queue and discards it. After the queue is empty, the
+// MarkQueuedFailed method of the parent MockStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
 // default hook function is invoked for any future action.
-func (f *StoreMarkErroredFunc) PushHook(hook func(context.Context, int, string, store.MarkFinalOptions) (bool, error)) {
+func (f *StoreMarkQueuedFailedFunc) PushHook(hook func(context.Context, []*sqlf.Query, string) ([]int, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -740,21 +1604,21 @@ func (f *StoreMarkErroredFunc) PushHook(hook func(context.Context, int, string,
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *StoreMarkErroredFunc) SetDefaultReturn(r0 bool, r1 error) {
-	f.SetDefaultHook(func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
+func (f *StoreMarkQueuedFailedFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context, []*sqlf.Query, string) ([]int, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *StoreMarkErroredFunc) PushReturn(r0 bool, r1 error) {
-	f.PushHook(func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
+func (f *StoreMarkQueuedFailedFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context, []*sqlf.Query, string) ([]int, error) {
 		return r0, r1
 	})
 }
 
-func (f *StoreMarkErroredFunc) nextHook() func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
+func (f *StoreMarkQueuedFailedFunc) nextHook() func(context.Context, []*sqlf.Query, string) ([]int, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -767,41 +1631,38 @@ func (f *StoreMarkErroredFunc) nextHook() func(context.Context, int, string, sto
 	return hook
 }
 
-func (f *StoreMarkErroredFunc) appendCall(r0 StoreMarkErroredFuncCall) {
+func (f *StoreMarkQueuedFailedFunc) appendCall(r0 StoreMarkQueuedFailedFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of StoreMarkErroredFuncCall objects describing
-// the invocations of this function.
-func (f *StoreMarkErroredFunc) History() []StoreMarkErroredFuncCall {
+// History returns a sequence of StoreMarkQueuedFailedFuncCall objects
+// describing the invocations of this function.
+func (f *StoreMarkQueuedFailedFunc) History() []StoreMarkQueuedFailedFuncCall {
 	f.mutex.Lock()
-	history := make([]StoreMarkErroredFuncCall, len(f.history))
+	history := make([]StoreMarkQueuedFailedFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// StoreMarkErroredFuncCall is an object that describes an invocation of
-// method MarkErrored on an instance of MockStore.
-type StoreMarkErroredFuncCall struct {
+// StoreMarkQueuedFailedFuncCall is an object that describes an invocation
+// of method MarkQueuedFailed on an instance of MockStore.
+type StoreMarkQueuedFailedFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 int
+	Arg1 []*sqlf.Query
 	// Arg2 is the value of the 3rd argument passed to this method
 	// invocation.
 	Arg2 string
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 store.MarkFinalOptions
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 bool
+	Result0 []int
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -809,44 +1670,45 @@ type StoreMarkErroredFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c StoreMarkErroredFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+func (c StoreMarkQueuedFailedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c StoreMarkErroredFuncCall) Results() []interface{} {
+func (c StoreMarkQueuedFailedFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// StoreMarkFailedFunc describes the behavior when the MarkFailed method of
-// the parent MockStore instance is invoked.
-type StoreMarkFailedFunc struct {
-	defaultHook func(context.Context, int, string, store.MarkFinalOptions) (bool, error)
-	hooks       []func(context.Context, int, string, store.MarkFinalOptions) (bool, error)
-	history     []StoreMarkFailedFuncCall
+// StoreProcessingCountFunc describes the behavior when the ProcessingCount
+// method of the parent MockStore instance is invoked.
+type StoreProcessingCountFunc struct {
+	defaultHook func(context.Context, []*sqlf.Query) (int, error)
+	hooks       []func(context.Context, []*sqlf.Query) (int, error)
+	history     []StoreProcessingCountFuncCall
 	mutex       sync.Mutex
 }
 
-// MarkFailed delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockStore) MarkFailed(v0 context.Context, v1 int, v2 string, v3 store.MarkFinalOptions) (bool, error) {
-	r0, r1 := m.MarkFailedFunc.nextHook()(v0, v1, v2, v3)
-	m.MarkFailedFunc.appendCall(StoreMarkFailedFuncCall{v0, v1, v2, v3, r0, r1})
+// ProcessingCount delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockStore) ProcessingCount(v0 context.Context, v1 []*sqlf.Query) (int, error) {
+	r0, r1 := m.ProcessingCountFunc.nextHook()(v0, v1)
+	m.ProcessingCountFunc.appendCall(StoreProcessingCountFuncCall{v0, v1, r0, r1})
 	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the MarkFailed method of
-// the parent MockStore instance is invoked and the hook queue is empty.
-func (f *StoreMarkFailedFunc) SetDefaultHook(hook func(context.Context, int, string, store.MarkFinalOptions) (bool, error)) {
+// SetDefaultHook sets function that is called when the ProcessingCount
+// method of the parent MockStore instance is invoked and the hook queue is
+// empty.
+func (f *StoreProcessingCountFunc) SetDefaultHook(hook func(context.Context, []*sqlf.Query) (int, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// MarkFailed method of the parent MockStore instance invokes the hook at
-// the front of the queue and discards it. After the queue is empty, the
+// ProcessingCount method of the parent MockStore instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
 // default hook function is invoked for any future action.
-func (f *StoreMarkFailedFunc) PushHook(hook func(context.Context, int, string, store.MarkFinalOptions) (bool, error)) {
+func (f *StoreProcessingCountFunc) PushHook(hook func(context.Context, []*sqlf.Query) (int, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -854,21 +1716,21 @@ func (f *StoreMarkFailedFunc) PushHook(hook func(context.Context, int, string, s
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *StoreMarkFailedFunc) SetDefaultReturn(r0 bool, r1 error) {
-	f.SetDefaultHook(func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
+func (f *StoreProcessingCountFunc) SetDefaultReturn(r0 int, r1 error) {
+	f.SetDefaultHook(func(context.Context, []*sqlf.Query) (int, error) {
 		return r0, r1
 	})
 }
 
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
-func (f *StoreMarkFailedFunc) PushReturn(r0 bool, r1 error) {
-	f.PushHook(func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
+func (f *StoreProcessingCountFunc) PushReturn(r0 int, r1 error) {
+	f.PushHook(func(context.Context, []*sqlf.Query) (int, error) {
 		return r0, r1
 	})
 }
 
-func (f *StoreMarkFailedFunc) nextHook() func(context.Context, int, string, store.MarkFinalOptions) (bool, error) {
+func (f *StoreProcessingCountFunc) nextHook() func(context.Context, []*sqlf.Query) (int, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -881,41 +1743,35 @@ func (f *StoreMarkFailedFunc) nextHook() func(context.Context, int, string, stor
 	return hook
 }
 
-func (f *StoreMarkFailedFunc) appendCall(r0 StoreMarkFailedFuncCall) {
+func (f *StoreProcessingCountFunc) appendCall(r0 StoreProcessingCountFuncCall) {
 	f.mutex.Lock()
 	f.history = append(f.history, r0)
 	f.mutex.Unlock()
 }
 
-// History returns a sequence of StoreMarkFailedFuncCall objects describing
-// the invocations of this function.
-func (f *StoreMarkFailedFunc) History() []StoreMarkFailedFuncCall {
+// History returns a sequence of StoreProcessingCountFuncCall objects
+// describing the invocations of this function.
+func (f *StoreProcessingCountFunc) History() []StoreProcessingCountFuncCall {
 	f.mutex.Lock()
-	history := make([]StoreMarkFailedFuncCall, len(f.history))
+	history := make([]StoreProcessingCountFuncCall, len(f.history))
 	copy(history, f.history)
 	f.mutex.Unlock()
 
 	return history
 }
 
-// StoreMarkFailedFuncCall is an object that describes an invocation of
-// method MarkFailed on an instance of MockStore.
-type StoreMarkFailedFuncCall struct {
+// StoreProcessingCountFuncCall is an object that describes an invocation of
+// method ProcessingCount on an instance of MockStore.
+type StoreProcessingCountFuncCall struct {
 	// Arg0 is the value of the 1st argument passed to this method
 	// invocation.
 	Arg0 context.Context
 	// Arg1 is the value of the 2nd argument passed to this method
 	// invocation.
-	Arg1 int
-	// Arg2 is the value of the 3rd argument passed to this method
-	// invocation.
-	Arg2 string
-	// Arg3 is the value of the 4th argument passed to this method
-	// invocation.
-	Arg3 store.MarkFinalOptions
+	Arg1 []*sqlf.Query
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
-	Result0 bool
+	Result0 int
 	// Result1 is the value of the 2nd result returned from this method
 	// invocation.
 	Result1 error
@@ -923,13 +1779,13 @@ type StoreMarkFailedFuncCall struct {
 
 // Args returns an interface slice containing the arguments of this
 // invocation.
-func (c StoreMarkFailedFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+func (c StoreProcessingCountFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
 }
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c StoreMarkFailedFuncCall) Results() []interface{} {
+func (c StoreProcessingCountFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
@@ -1257,6 +2113,117 @@ func (c StoreResetStalledFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// StoreResetWorkerFunc describes the behavior when the ResetWorker method
+// of the parent MockStore instance is invoked.
+type StoreResetWorkerFunc struct {
+	defaultHook func(context.Context, string) ([]int, []int, error)
+	hooks       []func(context.Context, string) ([]int, []int, error)
+	history     []StoreResetWorkerFuncCall
+	mutex       sync.Mutex
+}
+
+// ResetWorker delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockStore) ResetWorker(v0 context.Context, v1 string) ([]int, []int, error) {
+	r0, r1, r2 := m.ResetWorkerFunc.nextHook()(v0, v1)
+	m.ResetWorkerFunc.appendCall(StoreResetWorkerFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the ResetWorker method
+// of the parent MockStore instance is invoked and the hook queue is empty.
+func (f *StoreResetWorkerFunc) SetDefaultHook(hook func(context.Context, string) ([]int, []int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ResetWorker method of the parent MockStore instance invokes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *StoreResetWorkerFunc) PushHook(hook func(context.Context, string) ([]int, []int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *StoreResetWorkerFunc) SetDefaultReturn(r0 []int, r1 []int, r2 error) {
+	f.SetDefaultHook(func(context.Context, string) ([]int, []int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *StoreResetWorkerFunc) PushReturn(r0 []int, r1 []int, r2 error) {
+	f.PushHook(func(context.Context, string) ([]int, []int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *StoreResetWorkerFunc) nextHook() func(context.Context, string) ([]int, []int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *StoreResetWorkerFunc) appendCall(r0 StoreResetWorkerFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of StoreResetWorkerFuncCall objects describing
+// the invocations of this function.
+func (f *StoreResetWorkerFunc) History() []StoreResetWorkerFuncCall {
+	f.mutex.Lock()
+	history := make([]StoreResetWorkerFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// StoreResetWorkerFuncCall is an object that describes an invocation of
+// method ResetWorker on an instance of MockStore.
+type StoreResetWorkerFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 []int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c StoreResetWorkerFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c StoreResetWorkerFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // StoreUpdateExecutionLogEntryFunc describes the behavior when the
 // UpdateExecutionLogEntry method of the parent MockStore instance is
 // invoked.
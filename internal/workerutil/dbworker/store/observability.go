@@ -9,14 +9,22 @@ import (
 
 type operations struct {
 	queuedCount             *observation.Operation
+	processingCount         *observation.Operation
 	dequeue                 *observation.Operation
+	dequeueBatch            *observation.Operation
 	requeue                 *observation.Operation
+	list                    *observation.Operation
+	get                     *observation.Operation
+	delete                  *observation.Operation
 	addExecutionLogEntry    *observation.Operation
 	updateExecutionLogEntry *observation.Operation
 	markComplete            *observation.Operation
 	markErrored             *observation.Operation
 	markFailed              *observation.Operation
+	markQueuedFailed        *observation.Operation
+	markDependentsFailed    *observation.Operation
 	resetStalled            *observation.Operation
+	resetWorker             *observation.Operation
 	heartbeat               *observation.Operation
 }
 
@@ -38,14 +46,22 @@ func newOperations(storeName string, observationContext *observation.Context) *o
 
 	return &operations{
 		queuedCount:             op("QueuedCount"),
+		processingCount:         op("ProcessingCount"),
 		dequeue:                 op("Dequeue"),
+		dequeueBatch:            op("DequeueBatch"),
 		requeue:                 op("Requeue"),
+		list:                    op("List"),
+		get:                     op("Get"),
+		delete:                  op("Delete"),
 		addExecutionLogEntry:    op("AddExecutionLogEntry"),
 		updateExecutionLogEntry: op("UpdateExecutionLogEntry"),
 		markComplete:            op("MarkComplete"),
 		markErrored:             op("MarkErrored"),
 		markFailed:              op("MarkFailed"),
+		markQueuedFailed:        op("MarkQueuedFailed"),
+		markDependentsFailed:    op("MarkDependentsFailed"),
 		resetStalled:            op("ResetStalled"),
+		resetWorker:             op("ResetWorker"),
 		heartbeat:               op("Heartbeat"),
 	}
 }
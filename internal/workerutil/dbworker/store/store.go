@@ -13,6 +13,7 @@ import (
 	"github.com/derision-test/glock"
 	"github.com/keegancsmith/sqlf"
 	"github.com/opentracing/opentracing-go/log"
+	"github.com/segmentio/fasthash/fnv1"
 
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -53,6 +54,25 @@ func (o *ExecutionLogEntryOptions) ToSQLConds(formatQuery func(query string, arg
 type MarkFinalOptions struct {
 	// WorkerHostname, if set, enforces worker_hostname to be set to a specific value.
 	WorkerHostname string
+
+	// Backoff, if set, is honored only by MarkErrored. It moves the record back into the queued
+	// state with an exponentially increasing delay instead of into the errored state.
+	Backoff *BackoffOptions
+}
+
+// BackoffOptions controls how MarkErrored requeues a record that should be retried with
+// exponential backoff.
+type BackoffOptions struct {
+	// MaxNumFailures is the number of failures after which the record is marked as failed
+	// instead of being requeued.
+	MaxNumFailures int
+
+	// Delay is the delay before the record is dequeued again after its first failure. The
+	// delay doubles with each subsequent failure.
+	Delay time.Duration
+
+	// MaxDelay, if positive, caps the delay before the record is dequeued again.
+	MaxDelay time.Duration
 }
 
 func (o *MarkFinalOptions) ToSQLConds(formatQuery func(query string, args ...interface{}) *sqlf.Query) []*sqlf.Query {
@@ -63,6 +83,29 @@ func (o *MarkFinalOptions) ToSQLConds(formatQuery func(query string, args ...int
 	return conds
 }
 
+type ListOptions struct {
+	// States, if non-empty, restricts the listing to records in one of the given states.
+	States []string
+	// Conditions are additional conditions that may use the alias provided in `ViewName`.
+	Conditions []*sqlf.Query
+	// Limit, if positive, restricts the number of records returned.
+	Limit int
+	// Offset is the number of matching records to skip.
+	Offset int
+}
+
+func (o *ListOptions) ToSQLConds(formatQuery func(query string, args ...interface{}) *sqlf.Query) []*sqlf.Query {
+	conds := []*sqlf.Query{}
+	if len(o.States) > 0 {
+		states := make([]*sqlf.Query, 0, len(o.States))
+		for _, state := range o.States {
+			states = append(states, sqlf.Sprintf("%s", state))
+		}
+		conds = append(conds, formatQuery("{state} IN (%s)", sqlf.Join(states, ", ")))
+	}
+	return append(conds, o.Conditions...)
+}
+
 // ErrExecutionLogEntryNotUpdated is retured by AddExecutionLogEntry and UpdateExecutionLogEntry, when
 // the log entry was not updated.
 var ErrExecutionLogEntryNotUpdated = errors.New("execution log entry not updated")
@@ -75,6 +118,9 @@ type Store interface {
 	// QueuedCount returns the number of records in the queued state matching the given conditions.
 	QueuedCount(ctx context.Context, conditions []*sqlf.Query) (int, error)
 
+	// ProcessingCount returns the number of records in the processing state matching the given conditions.
+	ProcessingCount(ctx context.Context, conditions []*sqlf.Query) (int, error)
+
 	// Dequeue selects the first queued record matching the given conditions and updates the state to processing. If there
 	// is such a record, it is returned. If there is no such unclaimed record, a nil record and and a nil cancel function
 	// will be returned along with a false-valued flag. This method must not be called from within a transaction.
@@ -82,6 +128,24 @@ type Store interface {
 	// The supplied conditions may use the alias provided in `ViewName`, if one was supplied.
 	Dequeue(ctx context.Context, workerHostname string, conditions []*sqlf.Query) (workerutil.Record, bool, error)
 
+	// DequeueBatch selects up to limit queued records matching the given conditions and atomically updates their state
+	// to processing. The claimed records are returned; an empty slice indicates that there were no unclaimed records.
+	// This method must not be called from within a transaction.
+	//
+	// The supplied conditions may use the alias provided in `ViewName`, if one was supplied.
+	DequeueBatch(ctx context.Context, workerHostname string, conditions []*sqlf.Query, limit int) ([]workerutil.Record, error)
+
+	// List returns the records matching the given options, ordered by `OrderByExpression`. Records are
+	// returned in the shape produced by the `Scan` option.
+	List(ctx context.Context, options ListOptions) ([]workerutil.Record, error)
+
+	// Get returns the record with the given identifier, along with a flag indicating its existence.
+	Get(ctx context.Context, id int) (workerutil.Record, bool, error)
+
+	// Delete removes the record with the given identifier. This method returns a boolean flag indicating
+	// if the record existed.
+	Delete(ctx context.Context, id int) (bool, error)
+
 	// Heartbeat marks the given record as currently being processed.
 	Heartbeat(ctx context.Context, ids []int, options HeartbeatOptions) (knownIDs []int, err error)
 
@@ -113,11 +177,27 @@ type Store interface {
 	// with an error will not be updated. This method returns a boolean flag indicating if the record was updated.
 	MarkFailed(ctx context.Context, id int, failureMessage string, options MarkFinalOptions) (bool, error)
 
+	// MarkQueuedFailed moves all queued records matching the given conditions into the failed state with the
+	// given failure message. This method returns the identifiers of the records that were updated. The supplied
+	// conditions may use the alias provided in `ViewName`, if one was supplied.
+	MarkQueuedFailed(ctx context.Context, conditions []*sqlf.Query, failureMessage string) ([]int, error)
+
+	// MarkDependentsFailed moves all queued records depending on a failed or deleted record, directly or
+	// transitively, into the failed state. This method returns the identifiers of the records that were
+	// updated. This method has no effect unless the store was configured with a DependencyQueueName.
+	MarkDependentsFailed(ctx context.Context) ([]int, error)
+
 	// ResetStalled moves all processing records that have not received a heartbeat within `StalledMaxAge` back to the
 	// queued state. In order to prevent input that continually crashes worker instances, records that have been reset
 	// more than `MaxNumResets` times will be marked as errored. This method returns a list of record identifiers that
 	// have been reset and a list of record identifiers that have been marked as errored.
 	ResetStalled(ctx context.Context) (resetIDs, erroredIDs []int, err error)
+
+	// ResetWorker moves all processing records claimed by the given worker back to the queued state,
+	// regardless of their last heartbeat. This is used to requeue the records of a worker known to be
+	// gone without waiting for `StalledMaxAge` to pass. Records that have been reset more than
+	// `MaxNumResets` times are marked as errored, as in ResetStalled.
+	ResetWorker(ctx context.Context, workerHostname string) (resetIDs, erroredIDs []int, err error)
 }
 
 type ExecutionLogEntry workerutil.ExecutionLogEntry
@@ -238,10 +318,52 @@ type Options struct {
 	// Setting this value to zero will disable retries entirely.
 	MaxNumRetries int
 
+	// DependencyQueueName is the name under which dependencies between records of this store are
+	// recorded in the workerutil_job_dependencies table (see AddDependencies). If supplied, records
+	// are not dequeued until all of their dependencies have completed, and MarkDependentsFailed fails
+	// queued records whose dependencies have failed or were deleted. Records are not checked for
+	// dependencies if this field is empty.
+	DependencyQueueName string
+
+	// Dequeue configures how Dequeue and DequeueBatch select and claim records.
+	Dequeue DequeueOptions
+
 	// clock is used to mock out the wall clock used for heartbeat updates.
 	clock glock.Clock
 }
 
+// LockStrategy determines how concurrent dequeues avoid claiming the same record.
+type LockStrategy string
+
+const (
+	// LockStrategySkipLocked locks candidate rows with FOR UPDATE SKIP LOCKED. Concurrent
+	// dequeues skip the rows locked by one another.
+	LockStrategySkipLocked LockStrategy = "skip-locked"
+
+	// LockStrategyAdvisory selects candidate rows without row locks and claims them via
+	// transaction-scoped advisory locks keyed on the record identifier. This avoids lock waits
+	// on the rows themselves when many dequeues contend for the head of a large queue.
+	LockStrategyAdvisory LockStrategy = "advisory"
+)
+
+// DequeueOptions configure how records are selected and claimed by a dequeue.
+type DequeueOptions struct {
+	// LockStrategy determines how concurrent dequeues avoid claiming the same record. Defaults
+	// to LockStrategySkipLocked.
+	LockStrategy LockStrategy
+
+	// CandidateBatchSize is the number of candidate records considered by each dequeue under
+	// the advisory lock strategy, of which the first unlocked records are claimed. Larger values
+	// reduce the chance that every candidate is claimed by a concurrent dequeue, at the cost of
+	// a more expensive candidate scan. Defaults to DefaultCandidateBatchSize, and is never less
+	// than the number of records requested.
+	CandidateBatchSize int
+}
+
+// DefaultCandidateBatchSize is the number of candidate records considered by each dequeue under
+// the advisory lock strategy if no candidate batch size is configured.
+const DefaultCandidateBatchSize = 10
+
 // RecordScanFn is a function that interprets row values as a particular record. This function should
 // return a false-valued flag if the given result set was empty. This function must close the rows
 // value if the given error value is nil.
@@ -267,6 +389,17 @@ func newStore(handle *basestore.TransactableHandle, options Options, observation
 		options.ViewName = options.TableName
 	}
 
+	switch options.Dequeue.LockStrategy {
+	case "":
+		options.Dequeue.LockStrategy = LockStrategySkipLocked
+	case LockStrategySkipLocked, LockStrategyAdvisory:
+	default:
+		panic(fmt.Sprintf("unknown lock strategy %q supplied to github.com/sourcegraph/sourcegraph/internal/dbworker/store:newStore", options.Dequeue.LockStrategy))
+	}
+	if options.Dequeue.CandidateBatchSize <= 0 {
+		options.Dequeue.CandidateBatchSize = DefaultCandidateBatchSize
+	}
+
 	if options.clock == nil {
 		options.clock = glock.NewRealClock()
 	}
@@ -345,6 +478,25 @@ SELECT COUNT(*) FROM %s WHERE (
 ) %s
 `
 
+// ProcessingCount returns the number of records in the processing state matching the given conditions.
+func (s *store) ProcessingCount(ctx context.Context, conditions []*sqlf.Query) (_ int, err error) {
+	ctx, endObservation := s.operations.processingCount.With(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	count, _, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
+		processingCountQuery,
+		quote(s.options.ViewName),
+		makeConditionSuffix(conditions),
+	)))
+
+	return count, err
+}
+
+const processingCountQuery = `
+-- source: internal/workerutil/store.go:ProcessingCount
+SELECT COUNT(*) FROM %s WHERE {state} = 'processing' %s
+`
+
 // Dequeue selects the first queued record matching the given conditions and updates the state to processing. If there
 // is such a record, it is returned. If there is no such unclaimed record, a nil record and and a nil cancel function
 // will be returned along with a false-valued flag. This method must not be called from within a transaction.
@@ -362,39 +514,19 @@ func (s *store) Dequeue(ctx context.Context, workerHostname string, conditions [
 		return nil, false, ErrDequeueTransaction
 	}
 
-	now := s.now()
-
 	// Select and "lock" candidate record
-	id, exists, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(
-		selectCandidateQuery,
-		quote(s.options.ViewName),
-		now,
-		int(s.options.RetryAfter/time.Second),
-		now,
-		int(s.options.RetryAfter/time.Second),
-		s.options.MaxNumRetries,
-		makeConditionSuffix(conditions),
-		s.options.OrderByExpression,
-		quote(s.options.TableName),
-		now,
-		now,
-		workerHostname,
-	)))
+	ids, err := s.selectCandidates(ctx, workerHostname, conditions, 1)
 	if err != nil {
 		return nil, false, err
 	}
-	if !exists {
+	if len(ids) == 0 {
 		return nil, false, nil
 	}
+	id := ids[0]
 	traceLog(log.Int("id", id))
 
 	// Scan the actual record after updating its state
-	record, exists, err := s.options.Scan(s.Query(ctx, s.formatQuery(
-		selectRecordQuery,
-		sqlf.Join(s.options.ColumnExpressions, ", "),
-		quote(s.options.ViewName),
-		id,
-	)))
+	record, exists, err := s.get(ctx, id)
 	if err != nil {
 		return nil, false, err
 	}
@@ -405,27 +537,156 @@ func (s *store) Dequeue(ctx context.Context, workerHostname string, conditions [
 	return record, true, nil
 }
 
-const selectCandidateQuery = `
--- source: internal/workerutil/store.go:Dequeue
-WITH candidate AS (
-	SELECT {id} FROM %s
-	WHERE
-		(
-			(
-				{state} = 'queued' AND
-				({process_after} IS NULL OR {process_after} <= %s)
-			) OR (
-				%s > 0 AND
-				{state} = 'errored' AND
-				%s - {finished_at} > (%s * '1 second'::interval) AND
-				{num_failures} < %s
-			)
+// DequeueBatch selects up to limit queued records matching the given conditions and updates their state to
+// processing in a single statement, so that either all of the selected records are claimed by the given worker
+// or none are. Records are selected in the order defined by `OrderByExpression`, but are not necessarily
+// returned in that order. This method must not be called from within a transaction.
+//
+// The supplied conditions may use the alias provided in `ViewName`, if one was supplied.
+func (s *store) DequeueBatch(ctx context.Context, workerHostname string, conditions []*sqlf.Query, limit int) (_ []workerutil.Record, err error) {
+	ctx, traceLog, endObservation := s.operations.dequeueBatch.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("limit", limit),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if s.InTransaction() {
+		return nil, ErrDequeueTransaction
+	}
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	ids, err := s.selectCandidates(ctx, workerHostname, conditions, limit)
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numRecords", len(ids)))
+
+	// Scan the actual records after updating their state
+	records := make([]workerutil.Record, 0, len(ids))
+	for _, id := range ids {
+		record, exists, err := s.get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			// Deleted between the two queries
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// selectCandidates moves up to limit dequeueable records matching the given conditions into the
+// processing state and returns their identifiers.
+func (s *store) selectCandidates(ctx context.Context, workerHostname string, conditions []*sqlf.Query, limit int) ([]int, error) {
+	now := s.now()
+	conditions = append(append([]*sqlf.Query(nil), conditions...), s.dependencyConditions()...)
+
+	candidateConditions := s.formatQuery(
+		candidateConditionsQuery,
+		now,
+		int(s.options.RetryAfter/time.Second),
+		now,
+		int(s.options.RetryAfter/time.Second),
+		s.options.MaxNumRetries,
+		makeConditionSuffix(conditions),
+	)
+
+	var candidates *sqlf.Query
+	if s.options.Dequeue.LockStrategy == LockStrategyAdvisory {
+		candidateBatchSize := s.options.Dequeue.CandidateBatchSize
+		if candidateBatchSize < limit {
+			candidateBatchSize = limit
+		}
+
+		candidates = s.formatQuery(
+			advisoryLockCandidatesQuery,
+			quote(s.options.ViewName),
+			candidateConditions,
+			s.options.OrderByExpression,
+			candidateBatchSize,
+			s.advisoryLockNamespace(),
+			limit,
 		)
-		%s
+	} else {
+		candidates = s.formatQuery(
+			skipLockedCandidatesQuery,
+			quote(s.options.ViewName),
+			candidateConditions,
+			s.options.OrderByExpression,
+			limit,
+		)
+	}
+
+	return basestore.ScanInts(s.Query(ctx, s.formatQuery(
+		selectCandidateQuery,
+		candidates,
+		quote(s.options.TableName),
+		now,
+		now,
+		workerHostname,
+	)))
+}
+
+// advisoryLockNamespace returns the namespace of the advisory locks taken on records of the
+// target table by the advisory lock strategy. Stores over the same table share a namespace
+// regardless of the alias given to the table.
+func (s *store) advisoryLockNamespace() int32 {
+	return int32(fnv1.HashString32(strings.Fields(s.options.TableName)[0]))
+}
+
+const candidateConditionsQuery = `
+(
+	(
+		{state} = 'queued' AND
+		({process_after} IS NULL OR {process_after} <= %s)
+	) OR (
+		%s > 0 AND
+		{state} = 'errored' AND
+		%s - {finished_at} > (%s * '1 second'::interval) AND
+		{num_failures} < %s
+	)
+)
+%s
+`
+
+const skipLockedCandidatesQuery = `
+candidate AS (
+	SELECT {id} FROM %s
+	WHERE %s
 	ORDER BY %s
 	FOR UPDATE SKIP LOCKED
-	LIMIT 1
+	LIMIT %s
 )
+`
+
+// advisoryLockCandidatesQuery claims the first unlocked records among the candidates. The
+// locks are released when the enclosing statement commits, after which the records are no
+// longer dequeueable.
+const advisoryLockCandidatesQuery = `
+unlocked_candidate AS (
+	SELECT {id} FROM %s
+	WHERE %s
+	ORDER BY %s
+	LIMIT %s
+),
+candidate AS (
+	SELECT {id} FROM unlocked_candidate
+	WHERE pg_try_advisory_xact_lock(%s, {id})
+	LIMIT %s
+)
+`
+
+// selectCandidateQuery re-checks the state of each candidate as part of the update, as
+// candidates claimed via advisory locks may have been dequeued by a concurrent statement
+// that committed after this statement's snapshot was taken.
+const selectCandidateQuery = `
+-- source: internal/workerutil/store.go:Dequeue
+WITH %s
 UPDATE %s
 SET
 	{state} = 'processing',
@@ -435,7 +696,7 @@ SET
 	{failure_message} = NULL,
 	{execution_logs} = NULL,
 	{worker_hostname} = %s
-WHERE {id} IN (SELECT {id} FROM candidate)
+WHERE {id} IN (SELECT {id} FROM candidate) AND {state} IN ('queued', 'errored')
 RETURNING {id}
 `
 
@@ -444,6 +705,88 @@ const selectRecordQuery = `
 SELECT %s FROM %s WHERE {id} = %s
 `
 
+// List returns the records matching the given options, ordered by `OrderByExpression`. Records are
+// returned in the shape produced by the `Scan` option.
+func (s *store) List(ctx context.Context, options ListOptions) (_ []workerutil.Record, err error) {
+	ctx, traceLog, endObservation := s.operations.list.WithAndLogger(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	limit := sqlf.Sprintf("")
+	if options.Limit > 0 {
+		limit = sqlf.Sprintf("LIMIT %s", options.Limit)
+	}
+
+	ids, err := basestore.ScanInts(s.Query(ctx, s.formatQuery(
+		listQuery,
+		quote(s.options.ViewName),
+		makeConditionSuffix(options.ToSQLConds(s.formatQuery)),
+		s.options.OrderByExpression,
+		limit,
+		options.Offset,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numRecords", len(ids)))
+
+	records := make([]workerutil.Record, 0, len(ids))
+	for _, id := range ids {
+		record, exists, err := s.get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			// Deleted between the two queries
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+const listQuery = `
+-- source: internal/workerutil/store.go:List
+SELECT {id} FROM %s WHERE TRUE %s ORDER BY %s %s OFFSET %s
+`
+
+// Get returns the record with the given identifier, along with a flag indicating its existence.
+func (s *store) Get(ctx context.Context, id int) (_ workerutil.Record, _ bool, err error) {
+	ctx, endObservation := s.operations.get.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("id", id),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.get(ctx, id)
+}
+
+func (s *store) get(ctx context.Context, id int) (workerutil.Record, bool, error) {
+	return s.options.Scan(s.Query(ctx, s.formatQuery(
+		selectRecordQuery,
+		sqlf.Join(s.options.ColumnExpressions, ", "),
+		quote(s.options.ViewName),
+		id,
+	)))
+}
+
+// Delete removes the record with the given identifier. This method returns a boolean flag indicating
+// if the record existed.
+func (s *store) Delete(ctx context.Context, id int) (_ bool, err error) {
+	ctx, endObservation := s.operations.delete.With(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.Int("id", id),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	_, ok, err := basestore.ScanFirstInt(s.Query(ctx, s.formatQuery(deleteQuery, quote(s.options.TableName), id)))
+	return ok, err
+}
+
+const deleteQuery = `
+-- source: internal/workerutil/store.go:Delete
+DELETE FROM %s WHERE {id} = %s RETURNING {id}
+`
+
 func (s *store) Heartbeat(ctx context.Context, ids []int, options HeartbeatOptions) (knownIDs []int, err error) {
 	ctx, endObservation := s.operations.heartbeat.With(ctx, &err, observation.Args{})
 	defer endObservation(1, observation.Args{})
@@ -639,10 +982,32 @@ func (s *store) MarkErrored(ctx context.Context, id int, failureMessage string,
 	conds = append(conds, options.ToSQLConds(s.formatQuery)...)
 
 	q := s.formatQuery(markErroredQuery, quote(s.options.TableName), s.options.MaxNumRetries, failureMessage, sqlf.Join(conds, "AND"))
+	if backoff := options.Backoff; backoff != nil {
+		maxDelay := backoff.MaxDelay
+		if maxDelay <= 0 {
+			maxDelay = backoff.Delay << maxBackoffDoublings
+		}
+
+		q = s.formatQuery(
+			markErroredWithBackoffQuery,
+			quote(s.options.TableName),
+			backoff.MaxNumFailures,
+			failureMessage,
+			backoff.Delay.Seconds(),
+			maxBackoffDoublings,
+			maxDelay.Seconds(),
+			sqlf.Join(conds, "AND"),
+		)
+	}
+
 	_, ok, err := basestore.ScanFirstInt(s.Query(ctx, q))
 	return ok, err
 }
 
+// maxBackoffDoublings bounds the exponent of the backoff delay so that the computed interval
+// cannot overflow for records with many failures.
+const maxBackoffDoublings = 16
+
 const markErroredQuery = `
 -- source: internal/workerutil/store.go:MarkErrored
 UPDATE %s
@@ -654,6 +1019,18 @@ WHERE %s
 RETURNING {id}
 `
 
+const markErroredWithBackoffQuery = `
+-- source: internal/workerutil/store.go:MarkErrored
+UPDATE %s
+SET {state} = CASE WHEN {num_failures} + 1 >= %d THEN 'failed' ELSE 'queued' END,
+	{finished_at} = clock_timestamp(),
+	{failure_message} = %s,
+	{process_after} = clock_timestamp() + LEAST(%s * power(2, LEAST({num_failures}, %s)), %s) * '1 second'::interval,
+	{num_failures} = {num_failures} + 1
+WHERE %s
+RETURNING {id}
+`
+
 // MarkFailed attempts to update the state of the record to failed. This method will only have an effect
 // if the current state of the record is processing or completed. A requeued record or a record already marked
 // with an error will not be updated. This method returns a boolean flag indicating if the record was updated.
@@ -685,6 +1062,44 @@ WHERE %s
 RETURNING {id}
 `
 
+// MarkQueuedFailed moves all queued records matching the given conditions into the failed state with the
+// given failure message. This method returns the identifiers of the records that were updated. The supplied
+// conditions may use the alias provided in `ViewName`, if one was supplied.
+func (s *store) MarkQueuedFailed(ctx context.Context, conditions []*sqlf.Query, failureMessage string) (ids []int, err error) {
+	ctx, traceLog, endObservation := s.operations.markQueuedFailed.WithAndLogger(ctx, &err, observation.Args{})
+	defer endObservation(1, observation.Args{})
+
+	ids, err = basestore.ScanInts(s.Query(ctx, s.formatQuery(
+		markQueuedFailedQuery,
+		quote(s.options.ViewName),
+		makeConditionSuffix(conditions),
+		quote(s.options.TableName),
+		failureMessage,
+	)))
+	if err != nil {
+		return nil, err
+	}
+	traceLog(log.Int("numIDs", len(ids)))
+
+	return ids, nil
+}
+
+const markQueuedFailedQuery = `
+-- source: internal/workerutil/store.go:MarkQueuedFailed
+WITH candidates AS (
+	SELECT {id} FROM %s
+	WHERE {state} = 'queued' %s
+	FOR UPDATE SKIP LOCKED
+)
+UPDATE %s
+SET
+	{state} = 'failed',
+	{finished_at} = clock_timestamp(),
+	{failure_message} = %s
+WHERE {id} IN (SELECT {id} FROM candidates)
+RETURNING {id}
+`
+
 // ResetStalled moves all processing records that have not received a heartbeat within `StalledMaxAge` back to the
 // queued state. In order to prevent input that continually crashes worker instances, records that have been reset
 // more than `MaxNumResets` times will be marked as errored. This method returns a list of record identifiers that
@@ -693,13 +1108,46 @@ func (s *store) ResetStalled(ctx context.Context) (resetIDs, erroredIDs []int, e
 	ctx, traceLog, endObservation := s.operations.resetStalled.WithAndLogger(ctx, &err, observation.Args{})
 	defer endObservation(1, observation.Args{})
 
-	resetIDs, err = s.resetStalled(ctx, resetStalledQuery)
+	stalled := s.formatQuery(
+		"%s - {last_heartbeat_at} > (%s * '1 second'::interval)",
+		s.now(),
+		int(s.options.StalledMaxAge/time.Second),
+	)
+
+	resetIDs, err = s.resetStalled(ctx, resetStalledQuery, stalled)
+	if err != nil {
+		return resetIDs, erroredIDs, err
+	}
+	traceLog(log.Int("numResetIDs", len(resetIDs)))
+
+	erroredIDs, err = s.resetStalled(ctx, resetStalledMaxResetsQuery, stalled)
+	if err != nil {
+		return resetIDs, erroredIDs, err
+	}
+	traceLog(log.Int("numErroredIDs", len(erroredIDs)))
+
+	return resetIDs, erroredIDs, nil
+}
+
+// ResetWorker moves all processing records claimed by the given worker back to the queued state,
+// regardless of their last heartbeat. Records that have been reset more than `MaxNumResets` times
+// are marked as errored. This method returns a list of record identifiers that have been reset and
+// a list of record identifiers that have been marked as errored.
+func (s *store) ResetWorker(ctx context.Context, workerHostname string) (resetIDs, erroredIDs []int, err error) {
+	ctx, traceLog, endObservation := s.operations.resetWorker.WithAndLogger(ctx, &err, observation.Args{LogFields: []log.Field{
+		log.String("workerHostname", workerHostname),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	claimed := s.formatQuery("{worker_hostname} = %s", workerHostname)
+
+	resetIDs, err = s.resetStalled(ctx, resetStalledQuery, claimed)
 	if err != nil {
 		return resetIDs, erroredIDs, err
 	}
 	traceLog(log.Int("numResetIDs", len(resetIDs)))
 
-	erroredIDs, err = s.resetStalled(ctx, resetStalledMaxResetsQuery)
+	erroredIDs, err = s.resetStalled(ctx, resetStalledMaxResetsQuery, claimed)
 	if err != nil {
 		return resetIDs, erroredIDs, err
 	}
@@ -708,14 +1156,15 @@ func (s *store) ResetStalled(ctx context.Context) (resetIDs, erroredIDs []int, e
 	return resetIDs, erroredIDs, nil
 }
 
-func (s *store) resetStalled(ctx context.Context, q string) ([]int, error) {
+// resetStalled resets or errors the processing records matching the given condition. The
+// query determines which of the two happens based on the record's reset count.
+func (s *store) resetStalled(ctx context.Context, q string, condition *sqlf.Query) ([]int, error) {
 	return basestore.ScanInts(s.Query(
 		ctx,
 		s.formatQuery(
 			q,
 			quote(s.options.TableName),
-			s.now(),
-			int(s.options.StalledMaxAge/time.Second),
+			condition,
 			s.options.MaxNumResets,
 			quote(s.options.TableName),
 		),
@@ -723,12 +1172,12 @@ func (s *store) resetStalled(ctx context.Context, q string) ([]int, error) {
 }
 
 const resetStalledQuery = `
--- source: internal/workerutil/store.go:ResetStalled
+-- source: internal/workerutil/store.go:resetStalled
 WITH stalled AS (
 	SELECT {id} FROM %s
 	WHERE
 		{state} = 'processing' AND
-		%s - {last_heartbeat_at} > (%s * '1 second'::interval) AND
+		%s AND
 		{num_resets} < %s
 	FOR UPDATE SKIP LOCKED
 )
@@ -742,12 +1191,12 @@ RETURNING {id}
 `
 
 const resetStalledMaxResetsQuery = `
--- source: internal/workerutil/store.go:ResetStalled
+-- source: internal/workerutil/store.go:resetStalled
 WITH stalled AS (
 	SELECT {id} FROM %s
 	WHERE
 		{state} = 'processing' AND
-		%s - {last_heartbeat_at} > (%s * '1 second'::interval) AND
+		%s AND
 		{num_resets} >= %s
 	FOR UPDATE SKIP LOCKED
 )
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -91,6 +92,31 @@ func TestStoreQueuedCountConditions(t *testing.T) {
 	}
 }
 
+func TestStoreProcessingCount(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at)
+		VALUES
+			(1, 'processing', NOW() - '1 minute'::interval),
+			(2, 'queued', NOW() - '2 minute'::interval),
+			(3, 'processing', NOW() - '3 minute'::interval),
+			(4, 'processing', NOW() - '4 minute'::interval),
+			(5, 'completed', NOW() - '5 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	conditions := []*sqlf.Query{sqlf.Sprintf("w.id < 4")}
+	count, err := testStore(db, defaultTestStoreOptions(nil)).ProcessingCount(context.Background(), conditions)
+	if err != nil {
+		t.Fatalf("unexpected error getting processing count: %s", err)
+	}
+	if count != 2 {
+		t.Errorf("unexpected count. want=%d have=%d", 2, count)
+	}
+}
+
 func TestStoreDequeueState(t *testing.T) {
 	db := setupStoreTest(t)
 
@@ -149,6 +175,57 @@ func TestStoreDequeueConditions(t *testing.T) {
 	assertDequeueRecordResult(t, 3, record, ok, err)
 }
 
+func TestStoreDequeueBatch(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at)
+		VALUES
+			(1, 'queued', NOW() - '1 minute'::interval),
+			(2, 'queued', NOW() - '2 minute'::interval),
+			(3, 'state2', NOW() - '3 minute'::interval),
+			(4, 'queued', NOW() - '4 minute'::interval),
+			(5, 'queued', NOW() - '5 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	store := testStore(db, defaultTestStoreOptions(nil))
+
+	records, err := store.DequeueBatch(context.Background(), "test", nil, 2)
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing records: %s", err)
+	}
+	var ids []int
+	for _, record := range records {
+		if state := record.(TestRecord).State; state != "processing" {
+			t.Errorf("unexpected state. want=%s have=%s", "processing", state)
+		}
+		ids = append(ids, record.RecordID())
+	}
+	sort.Ints(ids)
+	if diff := cmp.Diff([]int{4, 5}, ids); diff != "" {
+		t.Errorf("unexpected records (-want +got):\n%s", diff)
+	}
+
+	// Fewer records than requested remain
+	records, err = store.DequeueBatch(context.Background(), "test", nil, 5)
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing records: %s", err)
+	}
+	if len(records) != 2 {
+		t.Errorf("unexpected number of records. want=%d have=%d", 2, len(records))
+	}
+
+	records, err = store.DequeueBatch(context.Background(), "test", nil, 5)
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing records: %s", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("unexpected number of records. want=%d have=%d", 0, len(records))
+	}
+}
+
 func TestStoreDequeueResetExecutionLogs(t *testing.T) {
 	db := setupStoreTest(t)
 
@@ -263,6 +340,61 @@ func TestStoreDequeueConcurrent(t *testing.T) {
 	}
 }
 
+func TestStoreDequeueAdvisoryLock(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at)
+		VALUES
+			(1, 'queued', NOW() - '5 minute'::interval),
+			(2, 'queued', NOW() - '4 minute'::interval),
+			(3, 'queued', NOW() - '3 minute'::interval),
+			(4, 'queued', NOW() - '2 minute'::interval),
+			(5, 'state2', NOW() - '1 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	options := defaultTestStoreOptions(nil)
+	options.Dequeue = DequeueOptions{LockStrategy: LockStrategyAdvisory, CandidateBatchSize: 3}
+	store := testStore(db, options)
+
+	// Simulate a concurrent dequeue holding the lock of the second record
+	tx, err := db.(*sql.DB).Begin()
+	if err != nil {
+		t.Fatalf("unexpected error starting transaction: %s", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1, 2)", store.advisoryLockNamespace()); err != nil {
+		t.Fatalf("unexpected error taking advisory lock: %s", err)
+	}
+
+	records, err := store.DequeueBatch(context.Background(), "test", nil, 2)
+	if err != nil {
+		t.Fatalf("unexpected error dequeueing records: %s", err)
+	}
+
+	var ids []int
+	for _, record := range records {
+		ids = append(ids, record.RecordID())
+	}
+	sort.Ints(ids)
+	if diff := cmp.Diff([]int{1, 3}, ids); diff != "" {
+		t.Errorf("unexpected record ids (-want +got):\n%s", diff)
+	}
+
+	// The locked record is skipped in favor of the next candidate
+	record, ok, err := store.Dequeue(context.Background(), "test", nil)
+	assertDequeueRecordResult(t, 4, record, ok, err)
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("unexpected error releasing advisory lock: %s", err)
+	}
+
+	record, ok, err = store.Dequeue(context.Background(), "test", nil)
+	assertDequeueRecordResult(t, 2, record, ok, err)
+}
+
 func TestStoreDequeueRetryAfter(t *testing.T) {
 	db := setupStoreTest(t)
 
@@ -379,6 +511,91 @@ func TestStoreRequeue(t *testing.T) {
 	}
 }
 
+func TestStoreList(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at)
+		VALUES
+			(1, 'queued', NOW() - '1 minute'::interval),
+			(2, 'processing', NOW() - '2 minute'::interval),
+			(3, 'queued', NOW() - '3 minute'::interval),
+			(4, 'failed', NOW() - '4 minute'::interval),
+			(5, 'queued', NOW() - '5 minute'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	testCases := []struct {
+		options     ListOptions
+		expectedIDs []int
+	}{
+		{options: ListOptions{}, expectedIDs: []int{5, 4, 3, 2, 1}},
+		{options: ListOptions{States: []string{"queued"}}, expectedIDs: []int{5, 3, 1}},
+		{options: ListOptions{States: []string{"queued", "failed"}, Limit: 2}, expectedIDs: []int{5, 4}},
+		{options: ListOptions{States: []string{"queued"}, Offset: 1}, expectedIDs: []int{3, 1}},
+		{options: ListOptions{Conditions: []*sqlf.Query{sqlf.Sprintf("w.id > 2")}}, expectedIDs: []int{5, 4, 3}},
+	}
+
+	for _, testCase := range testCases {
+		records, err := testStore(db, defaultTestStoreOptions(nil)).List(context.Background(), testCase.options)
+		if err != nil {
+			t.Fatalf("unexpected error listing records: %s", err)
+		}
+
+		var ids []int
+		for _, record := range records {
+			ids = append(ids, record.RecordID())
+		}
+		if diff := cmp.Diff(testCase.expectedIDs, ids); diff != "" {
+			t.Errorf("unexpected record ids (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestStoreGetAndDelete(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state)
+		VALUES
+			(1, 'queued')
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	store := testStore(db, defaultTestStoreOptions(nil))
+
+	record, exists, err := store.Get(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error getting record: %s", err)
+	}
+	if !exists {
+		t.Fatalf("expected record to exist")
+	}
+	if record.RecordID() != 1 {
+		t.Errorf("unexpected id. want=%d have=%d", 1, record.RecordID())
+	}
+
+	if ok, err := store.Delete(context.Background(), 1); err != nil {
+		t.Fatalf("unexpected error deleting record: %s", err)
+	} else if !ok {
+		t.Fatalf("expected record to be deleted")
+	}
+
+	if _, exists, err := store.Get(context.Background(), 1); err != nil {
+		t.Fatalf("unexpected error getting record: %s", err)
+	} else if exists {
+		t.Fatalf("expected record to be deleted")
+	}
+
+	if ok, err := store.Delete(context.Background(), 1); err != nil {
+		t.Fatalf("unexpected error deleting record: %s", err)
+	} else if ok {
+		t.Fatalf("did not expect a record to be deleted")
+	}
+}
+
 func TestStoreAddExecutionLogEntry(t *testing.T) {
 	db := setupStoreTest(t)
 
@@ -836,6 +1053,98 @@ func TestStoreMarkErroredRetriesExhausted(t *testing.T) {
 	assertState(2, "failed")
 }
 
+func TestStoreMarkErroredWithBackoff(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, num_failures)
+		VALUES
+			(1, 'processing', 0),
+			(2, 'processing', 2),
+			(3, 'processing', 3)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	store := testStore(db, defaultTestStoreOptions(nil))
+	backoff := &BackoffOptions{MaxNumFailures: 4, Delay: time.Minute, MaxDelay: 3 * time.Minute}
+
+	for i := 1; i <= 3; i++ {
+		marked, err := store.MarkErrored(context.Background(), i, "new message", MarkFinalOptions{Backoff: backoff})
+		if err != nil {
+			t.Fatalf("unexpected error marking record as errored: %s", err)
+		}
+		if !marked {
+			t.Fatalf("expected record to be marked")
+		}
+	}
+
+	rows, err := db.QueryContext(context.Background(), `
+		SELECT id, state, EXTRACT(EPOCH FROM process_after - finished_at)::integer
+		FROM workerutil_test
+		ORDER BY id
+	`)
+	if err != nil {
+		t.Fatalf("unexpected error querying records: %s", err)
+	}
+	defer func() { _ = basestore.CloseRows(rows, nil) }()
+
+	type result struct {
+		ID    int
+		State string
+		Delay int
+	}
+	var results []result
+	for rows.Next() {
+		var r result
+		if err := rows.Scan(&r.ID, &r.State, &r.Delay); err != nil {
+			t.Fatalf("unexpected error scanning record: %s", err)
+		}
+		results = append(results, r)
+	}
+
+	expected := []result{
+		{ID: 1, State: "queued", Delay: 60},
+		{ID: 2, State: "queued", Delay: 180}, // capped at MaxDelay
+		{ID: 3, State: "failed", Delay: 180},
+	}
+	if diff := cmp.Diff(expected, results); diff != "" {
+		t.Errorf("unexpected records (-want +got):\n%s", diff)
+	}
+}
+
+func TestStoreMarkQueuedFailed(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, uploaded_at)
+		VALUES
+			(1, 'queued', NOW() - '1 hour'::interval),
+			(2, 'queued', NOW() - '3 day'::interval),
+			(3, 'processing', NOW() - '3 day'::interval),
+			(4, 'queued', NOW() - '5 day'::interval)
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	ids, err := testStore(db, defaultTestStoreOptions(nil)).MarkQueuedFailed(context.Background(), []*sqlf.Query{sqlf.Sprintf("w.uploaded_at < NOW() - '1 day'::interval")}, "expired")
+	if err != nil {
+		t.Fatalf("unexpected error marking records as failed: %s", err)
+	}
+	sort.Ints(ids)
+	if diff := cmp.Diff([]int{2, 4}, ids); diff != "" {
+		t.Errorf("unexpected ids (-want +got):\n%s", diff)
+	}
+
+	states, err := basestore.ScanStrings(db.QueryContext(context.Background(), `SELECT state FROM workerutil_test ORDER BY id`))
+	if err != nil {
+		t.Fatalf("unexpected error querying states: %s", err)
+	}
+	if diff := cmp.Diff([]string{"queued", "failed", "processing", "failed"}, states); diff != "" {
+		t.Errorf("unexpected states (-want +got):\n%s", diff)
+	}
+}
+
 func TestStoreResetStalled(t *testing.T) {
 	db := setupStoreTest(t)
 
@@ -919,6 +1228,42 @@ func TestStoreResetStalled(t *testing.T) {
 	}
 }
 
+func TestStoreResetWorker(t *testing.T) {
+	db := setupStoreTest(t)
+
+	if _, err := db.ExecContext(context.Background(), `
+		INSERT INTO workerutil_test (id, state, last_heartbeat_at, num_resets, worker_hostname)
+		VALUES
+			(1, 'processing', NOW(), 0, 'dead'),
+			(2, 'processing', NOW(), 0, 'alive'),
+			(3, 'processing', NOW(), 5, 'dead'),
+			(4, 'queued', NOW(), 0, 'dead'),
+			(5, 'completed', NOW(), 0, 'dead')
+	`); err != nil {
+		t.Fatalf("unexpected error inserting records: %s", err)
+	}
+
+	resetIDs, erroredIDs, err := testStore(db, defaultTestStoreOptions(nil)).ResetWorker(context.Background(), "dead")
+	if err != nil {
+		t.Fatalf("unexpected error resetting worker records: %s", err)
+	}
+
+	if diff := cmp.Diff([]int{1}, resetIDs); diff != "" {
+		t.Errorf("unexpected reset ids (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{3}, erroredIDs); diff != "" {
+		t.Errorf("unexpected errored ids (-want +got):\n%s", diff)
+	}
+
+	states, err := basestore.ScanStrings(db.QueryContext(context.Background(), `SELECT state FROM workerutil_test ORDER BY id`))
+	if err != nil {
+		t.Fatalf("unexpected error querying states: %s", err)
+	}
+	if diff := cmp.Diff([]string{"queued", "processing", "errored", "queued", "completed"}, states); diff != "" {
+		t.Errorf("unexpected states (-want +got):\n%s", diff)
+	}
+}
+
 func TestStoreHeartbeat(t *testing.T) {
 	db := setupStoreTest(t)
 
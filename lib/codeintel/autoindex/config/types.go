@@ -12,6 +12,13 @@ type IndexJob struct {
 	Indexer     string       `json:"indexer" yaml:"indexer"`
 	IndexerArgs []string     `json:"indexer_args" yaml:"indexer_args"`
 	Outfile     string       `json:"outfile" yaml:"outfile"`
+
+	// ExecutorLabels restricts the job to executors that have all of the given labels.
+	ExecutorLabels []string `json:"executor_labels,omitempty" yaml:"executor_labels,omitempty"`
+
+	// DependencyRepositories names the repositories whose code this job's index refers to. The
+	// job is preferably run once those repositories have fresh uploads.
+	DependencyRepositories []string `json:"dependency_repositories,omitempty" yaml:"dependency_repositories,omitempty"`
 }
 
 type DockerStep struct {
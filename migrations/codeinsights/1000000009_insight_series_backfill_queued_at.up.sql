@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS backfill_queued_at TIMESTAMP;
+
+COMMENT ON COLUMN insight_series.backfill_queued_at IS 'Timestamp when the historical data of this series was enqueued for backfilling. Series that have not been backfilled are picked up by the backfiller.';
+
+COMMIT;
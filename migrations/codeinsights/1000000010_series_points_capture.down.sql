@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS series_points_series_id_capture_idx;
+ALTER TABLE series_points DROP COLUMN IF EXISTS capture;
+
+COMMIT;
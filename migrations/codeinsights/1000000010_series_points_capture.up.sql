@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE series_points ADD COLUMN IF NOT EXISTS capture TEXT;
+
+COMMENT ON COLUMN series_points.capture IS 'The value captured by the regexp capture group of the query of a series generated from capture groups. Each distinct value forms its own series. Null for other series.';
+
+CREATE INDEX IF NOT EXISTS series_points_series_id_capture_idx ON series_points (series_id, capture) WHERE capture IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS repo_language_stats
+(
+    repo_id     INT       NOT NULL PRIMARY KEY,
+    repo_name   TEXT      NOT NULL,
+    commit      TEXT      NOT NULL,
+    languages   JSONB     NOT NULL,
+    recorded_at TIMESTAMP NOT NULL
+);
+
+COMMENT ON TABLE repo_language_stats IS 'The latest language statistics of each repository of a language statistics insight, computed in the background.';
+
+COMMENT ON COLUMN repo_language_stats.repo_id IS 'The repository ID (from the main application DB) the statistics describe.';
+COMMENT ON COLUMN repo_language_stats.repo_name IS 'The name of the repository at the time the statistics were recorded.';
+COMMENT ON COLUMN repo_language_stats.commit IS 'The commit the statistics were computed at.';
+COMMENT ON COLUMN repo_language_stats.languages IS 'The number of bytes and lines of each language in the repository at the commit, as a JSON array of inventory.Lang.';
+COMMENT ON COLUMN repo_language_stats.recorded_at IS 'Timestamp when the statistics were recorded.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE insight_view_series ADD COLUMN IF NOT EXISTS recording_interval TEXT NOT NULL DEFAULT 'daily';
+
+COMMENT ON COLUMN insight_view_series.recording_interval IS 'Interval at which this data series is recorded for this view (non-historical): hourly, daily, weekly, or monthly. A data series shared by multiple views is recorded at the shortest interval of its views.';
+
+COMMIT;
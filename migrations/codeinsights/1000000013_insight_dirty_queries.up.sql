@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insight_dirty_queries
+(
+    id              SERIAL    NOT NULL PRIMARY KEY,
+    series_id       TEXT      NOT NULL,
+    query           TEXT      NOT NULL,
+    for_time        TIMESTAMP NOT NULL,
+    reason          TEXT      NOT NULL,
+    dirty_at        TIMESTAMP NOT NULL,
+    attempts        INT       NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    UNIQUE (series_id, for_time)
+);
+
+CREATE INDEX IF NOT EXISTS insight_dirty_queries_next_attempt_at_idx ON insight_dirty_queries (next_attempt_at);
+
+COMMENT ON TABLE insight_dirty_queries IS 'Data points of insight series which could not be recorded because their query failed, and which are retried in the background.';
+
+COMMENT ON COLUMN insight_dirty_queries.series_id IS 'The series ID of the data point.';
+COMMENT ON COLUMN insight_dirty_queries.query IS 'The search query that failed.';
+COMMENT ON COLUMN insight_dirty_queries.for_time IS 'The time the data point is recorded at.';
+COMMENT ON COLUMN insight_dirty_queries.reason IS 'The error of the most recent failure of the query.';
+COMMENT ON COLUMN insight_dirty_queries.dirty_at IS 'Timestamp when the query most recently failed.';
+COMMENT ON COLUMN insight_dirty_queries.attempts IS 'The number of times the query was retried.';
+COMMENT ON COLUMN insight_dirty_queries.next_attempt_at IS 'Timestamp after which the query is retried next.';
+
+COMMIT;
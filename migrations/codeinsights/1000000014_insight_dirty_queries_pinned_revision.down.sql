@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE insight_dirty_queries DROP COLUMN IF EXISTS pinned_repo;
+ALTER TABLE insight_dirty_queries DROP COLUMN IF EXISTS pinned_revision;
+
+COMMIT;
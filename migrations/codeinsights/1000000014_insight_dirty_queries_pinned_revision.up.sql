@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE insight_dirty_queries ADD COLUMN IF NOT EXISTS pinned_repo TEXT;
+ALTER TABLE insight_dirty_queries ADD COLUMN IF NOT EXISTS pinned_revision TEXT;
+
+COMMENT ON COLUMN insight_dirty_queries.pinned_repo IS 'The name of the repository whose revision at for_time the query is pinned to, if any.';
+COMMENT ON COLUMN insight_dirty_queries.pinned_revision IS 'The commit of the pinned repository at for_time, if it was resolved before the query failed.';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS insight_webhook_deliveries;
+ALTER TABLE insight_series DROP COLUMN IF EXISTS webhook;
+
+COMMIT;
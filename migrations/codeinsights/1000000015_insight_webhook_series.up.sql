@@ -0,0 +1,26 @@
+BEGIN;
+
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS webhook TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN insight_series.webhook IS 'The URL the data of the series is fetched from, if the series is a webhook series.';
+
+CREATE TABLE IF NOT EXISTS insight_webhook_deliveries
+(
+    series_id            TEXT      NOT NULL PRIMARY KEY,
+    last_attempt_at      TIMESTAMP NOT NULL,
+    last_success_at      TIMESTAMP,
+    last_status_code     INT,
+    last_error           TEXT,
+    consecutive_failures INT       NOT NULL DEFAULT 0
+);
+
+COMMENT ON TABLE insight_webhook_deliveries IS 'The status of the most recent requests sent to the webhooks of webhook series.';
+
+COMMENT ON COLUMN insight_webhook_deliveries.series_id IS 'The series ID of the webhook series.';
+COMMENT ON COLUMN insight_webhook_deliveries.last_attempt_at IS 'Timestamp of the most recent request to the webhook.';
+COMMENT ON COLUMN insight_webhook_deliveries.last_success_at IS 'Timestamp of the most recent request to the webhook that recorded a data point.';
+COMMENT ON COLUMN insight_webhook_deliveries.last_status_code IS 'The HTTP status code of the response to the most recent request, if the webhook responded.';
+COMMENT ON COLUMN insight_webhook_deliveries.last_error IS 'The error of the most recent request, if it failed.';
+COMMENT ON COLUMN insight_webhook_deliveries.consecutive_failures IS 'The number of requests that failed since the most recent successful request.';
+
+COMMIT;
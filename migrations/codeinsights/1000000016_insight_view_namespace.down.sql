@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE insight_view DROP COLUMN IF EXISTS user_id;
+ALTER TABLE insight_view DROP COLUMN IF EXISTS org_id;
+
+COMMIT;
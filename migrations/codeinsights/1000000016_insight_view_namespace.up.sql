@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE insight_view ADD COLUMN IF NOT EXISTS user_id INT;
+ALTER TABLE insight_view ADD COLUMN IF NOT EXISTS org_id INT;
+
+COMMENT ON COLUMN insight_view.user_id IS 'The ID of the user whose settings define the insight, if the insight is private to a user.';
+COMMENT ON COLUMN insight_view.org_id IS 'The ID of the organization whose settings define the insight, if the insight is private to an organization. Insights without a user or organization are global.';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE insight_series DROP COLUMN IF EXISTS repositories;
+ALTER TABLE insight_series DROP COLUMN IF EXISTS repository_pattern;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS repositories TEXT[];
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS repository_pattern TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN insight_series.repositories IS 'The names of the repositories the search query of the series is restricted to, if any.';
+COMMENT ON COLUMN insight_series.repository_pattern IS 'A regular expression matching the names of the repositories the search query of the series is restricted to, or an empty string. Series restricted to neither search all repositories.';
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS reference_count INT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN insight_series.reference_count IS 'The number of insight views the series is attached to. Series with the same data are stored once and shared by all the views using them. Series that are no longer referenced are soft-deleted, and purged along with their data some time later.';
+
+UPDATE insight_series
+SET reference_count = (SELECT COUNT(*) FROM insight_view_series WHERE insight_series_id = insight_series.id);
+
+UPDATE insight_series
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE reference_count = 0 AND deleted_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insight_series_runs
+(
+    series_id            TEXT      NOT NULL PRIMARY KEY,
+    last_attempt_at      TIMESTAMP NOT NULL,
+    last_success_at      TIMESTAMP,
+    last_error           TEXT,
+    consecutive_failures INT       NOT NULL DEFAULT 0
+);
+
+COMMENT ON TABLE insight_series_runs IS 'The status of the most recent query runner jobs that recorded data points of each series.';
+
+COMMENT ON COLUMN insight_series_runs.series_id IS 'The series ID of the series.';
+COMMENT ON COLUMN insight_series_runs.last_attempt_at IS 'Timestamp of the most recent job of the series.';
+COMMENT ON COLUMN insight_series_runs.last_success_at IS 'Timestamp of the most recent job of the series that succeeded.';
+COMMENT ON COLUMN insight_series_runs.last_error IS 'The error of the most recent job of the series, if it failed.';
+COMMENT ON COLUMN insight_series_runs.consecutive_failures IS 'The number of jobs of the series that failed since the most recent successful job.';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE insight_series_runs DROP COLUMN IF EXISTS last_full_run_at;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE insight_series_runs ADD COLUMN IF NOT EXISTS last_full_run_at TIMESTAMP;
+
+COMMENT ON COLUMN insight_series_runs.last_full_run_at IS 'Timestamp of the most recent successful job of the series that recorded its present-day data from a full search, as opposed to an incremental search.';
+
+COMMIT;
@@ -0,0 +1,37 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insight_series_alert_rules
+(
+    id                  SERIAL           NOT NULL PRIMARY KEY,
+    series_id           TEXT             NOT NULL,
+    series_label        TEXT             NOT NULL DEFAULT '',
+    user_id             INT              NOT NULL,
+    condition           TEXT             NOT NULL,
+    threshold           DOUBLE PRECISION NOT NULL,
+    window_days         INT              NOT NULL DEFAULT 0,
+    notify_email        BOOLEAN          NOT NULL DEFAULT TRUE,
+    webhook_url         TEXT,
+    created_at          TIMESTAMP        NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_evaluated_time TIMESTAMP,
+    firing              BOOLEAN          NOT NULL DEFAULT FALSE,
+    last_notified_at    TIMESTAMP,
+    CONSTRAINT insight_series_alert_rules_condition_check CHECK (condition IN ('above', 'below', 'percent_change'))
+);
+
+CREATE INDEX IF NOT EXISTS insight_series_alert_rules_series_id_idx ON insight_series_alert_rules (series_id);
+
+COMMENT ON TABLE insight_series_alert_rules IS 'Rules notifying users when the values of a series cross a threshold.';
+
+COMMENT ON COLUMN insight_series_alert_rules.series_id IS 'The series ID of the series the rule checks.';
+COMMENT ON COLUMN insight_series_alert_rules.series_label IS 'The label of the series when the rule was created, used in notifications.';
+COMMENT ON COLUMN insight_series_alert_rules.user_id IS 'The user who created the rule. Rules check the values of the series visible to this user, and notify this user.';
+COMMENT ON COLUMN insight_series_alert_rules.condition IS 'One of above, below, or percent_change.';
+COMMENT ON COLUMN insight_series_alert_rules.threshold IS 'The value the series is compared to, or the percent change over window_days for percent_change rules. Negative percent changes are decreases.';
+COMMENT ON COLUMN insight_series_alert_rules.window_days IS 'The number of days the change of percent_change rules is computed over.';
+COMMENT ON COLUMN insight_series_alert_rules.notify_email IS 'Whether the user is notified by email.';
+COMMENT ON COLUMN insight_series_alert_rules.webhook_url IS 'The URL notifications are posted to, if any.';
+COMMENT ON COLUMN insight_series_alert_rules.last_evaluated_time IS 'The time of the most recent recording of the series the rule was checked against.';
+COMMENT ON COLUMN insight_series_alert_rules.firing IS 'Whether the condition held for the most recent recording checked. Notifications are only sent when a rule starts firing.';
+COMMENT ON COLUMN insight_series_alert_rules.last_notified_at IS 'Timestamp of the most recent notification of the rule.';
+
+COMMIT;
@@ -0,0 +1,55 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS dashboard
+(
+    id              SERIAL    NOT NULL PRIMARY KEY,
+    unique_id       TEXT,
+    title           TEXT      NOT NULL DEFAULT '',
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at      TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS dashboard_unique_id_unique_idx ON dashboard (unique_id) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE dashboard IS 'Dashboards of insights.';
+
+COMMENT ON COLUMN dashboard.unique_id IS 'The ID of the dashboard in the settings that define it, if it is defined in settings.';
+COMMENT ON COLUMN dashboard.title IS 'Title of the dashboard.';
+COMMENT ON COLUMN dashboard.deleted_at IS 'Timestamp at which the dashboard was deleted.';
+
+CREATE TABLE IF NOT EXISTS dashboard_insight_view
+(
+    id                     SERIAL NOT NULL PRIMARY KEY,
+    dashboard_id           INT    NOT NULL REFERENCES dashboard (id) ON DELETE CASCADE,
+    insight_view_unique_id TEXT   NOT NULL,
+    CONSTRAINT dashboard_insight_view_unique UNIQUE (dashboard_id, insight_view_unique_id)
+);
+
+CREATE INDEX IF NOT EXISTS dashboard_insight_view_insight_view_unique_id_idx ON dashboard_insight_view (insight_view_unique_id);
+
+COMMENT ON TABLE dashboard_insight_view IS 'The insights of each dashboard, in the order they are shown.';
+
+COMMENT ON COLUMN dashboard_insight_view.insight_view_unique_id IS 'The unique ID of the insight view. Insights are referenced by unique ID rather than by view ID, as views are recreated when their definition changes.';
+
+CREATE TABLE IF NOT EXISTS dashboard_grants
+(
+    id           SERIAL NOT NULL PRIMARY KEY,
+    dashboard_id INT    NOT NULL REFERENCES dashboard (id) ON DELETE CASCADE,
+    user_id      INT,
+    org_id       INT,
+    global       BOOLEAN,
+    CONSTRAINT dashboard_grants_single_subject CHECK (num_nonnulls(user_id, org_id, global) = 1)
+);
+
+CREATE INDEX IF NOT EXISTS dashboard_grants_dashboard_id_idx ON dashboard_grants (dashboard_id);
+CREATE INDEX IF NOT EXISTS dashboard_grants_user_id_idx ON dashboard_grants (user_id);
+CREATE INDEX IF NOT EXISTS dashboard_grants_org_id_idx ON dashboard_grants (org_id);
+
+COMMENT ON TABLE dashboard_grants IS 'The users, organizations, or everyone a dashboard is shared with. Each grant grants access to a single subject.';
+
+COMMENT ON COLUMN dashboard_grants.user_id IS 'The user the dashboard is shared with, if any.';
+COMMENT ON COLUMN dashboard_grants.org_id IS 'The organization whose members the dashboard is shared with, if any.';
+COMMENT ON COLUMN dashboard_grants.global IS 'True if the dashboard is shared with all users.';
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insight_series_backfill_checkpoints
+(
+    series_id  TEXT      NOT NULL PRIMARY KEY,
+    repo_name  TEXT      NOT NULL,
+    frame_from TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE insight_series_backfill_checkpoints IS 'The progress of the current pass of the historical enqueuer over each series, so that interrupted backfills resume where they stopped.';
+
+COMMENT ON COLUMN insight_series_backfill_checkpoints.series_id IS 'The series ID of the series.';
+COMMENT ON COLUMN insight_series_backfill_checkpoints.repo_name IS 'The name of the repository being backfilled. Repositories are backfilled in a stable order, and the repositories before this one are done.';
+COMMENT ON COLUMN insight_series_backfill_checkpoints.frame_from IS 'The start of the oldest timeframe backfilled in the repository. Timeframes are backfilled from the newest to the oldest.';
+COMMENT ON COLUMN insight_series_backfill_checkpoints.updated_at IS 'Timestamp of the most recent progress.';
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insight_problems
+(
+    id           SERIAL    NOT NULL PRIMARY KEY,
+    insight_id   TEXT      NOT NULL,
+    series_id    TEXT,
+    series_label TEXT,
+    user_id      INT,
+    org_id       INT,
+    problem      TEXT      NOT NULL,
+    detected_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS insight_problems_insight_id_idx ON insight_problems (insight_id);
+
+COMMENT ON TABLE insight_problems IS 'The problems found in the definitions of the discovered insights by the most recent validation pass. Insights with problems are not recorded, or only in part.';
+
+COMMENT ON COLUMN insight_problems.insight_id IS 'The unique ID of the insight.';
+COMMENT ON COLUMN insight_problems.series_id IS 'The series ID of the series with the problem, or NULL if the problem concerns the whole insight.';
+COMMENT ON COLUMN insight_problems.series_label IS 'The label of the series with the problem, or NULL if the problem concerns the whole insight.';
+COMMENT ON COLUMN insight_problems.user_id IS 'The user whose settings define the insight, if any.';
+COMMENT ON COLUMN insight_problems.org_id IS 'The organization whose settings define the insight, if any.';
+COMMENT ON COLUMN insight_problems.problem IS 'A description of the problem.';
+COMMENT ON COLUMN insight_problems.detected_at IS 'Timestamp of the validation pass that found the problem.';
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insight_series_enqueue_failures
+(
+    series_id            TEXT      NOT NULL PRIMARY KEY,
+    cause                TEXT      NOT NULL,
+    last_error           TEXT      NOT NULL,
+    first_failed_at      TIMESTAMP NOT NULL,
+    last_failed_at       TIMESTAMP NOT NULL,
+    consecutive_failures INT       NOT NULL DEFAULT 1
+);
+
+COMMENT ON TABLE insight_series_enqueue_failures IS 'The series that the insight enqueuer failed to enqueue in its most recent attempt. Series are removed once they are enqueued.';
+
+COMMENT ON COLUMN insight_series_enqueue_failures.series_id IS 'The series ID of the series.';
+COMMENT ON COLUMN insight_series_enqueue_failures.cause IS 'The kind of failure: invalid (the series definition is invalid), queue_full (the query runner queue is full) or enqueue (the job could not be enqueued).';
+COMMENT ON COLUMN insight_series_enqueue_failures.last_error IS 'The error of the most recent attempt to enqueue the series.';
+COMMENT ON COLUMN insight_series_enqueue_failures.first_failed_at IS 'Timestamp of the first attempt to enqueue the series that failed since it was last enqueued.';
+COMMENT ON COLUMN insight_series_enqueue_failures.last_failed_at IS 'Timestamp of the most recent attempt to enqueue the series.';
+COMMENT ON COLUMN insight_series_enqueue_failures.consecutive_failures IS 'The number of attempts to enqueue the series that failed since it was last enqueued.';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE series_points DROP COLUMN IF EXISTS approximate;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE series_points ADD COLUMN IF NOT EXISTS approximate BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN series_points.approximate IS 'Whether the value of the data point is approximate, because the search it was recorded from hit a limit or timed out in some repositories. Such values undercount.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE insight_series ADD COLUMN IF NOT EXISTS expression TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN insight_series.expression IS 'The arithmetic expression a derived series is computed from, referring to the series it is derived from by series ID. Empty for series recorded from a search query or a webhook.';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE insight_view_series DROP COLUMN IF EXISTS business_days_only;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE insight_view_series ADD COLUMN IF NOT EXISTS business_days_only BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN insight_view_series.business_days_only IS 'Whether this data series is only recorded on business days for this view. A data series shared by multiple views is recorded on every day unless all of its views record it on business days only.';
+
+COMMIT;
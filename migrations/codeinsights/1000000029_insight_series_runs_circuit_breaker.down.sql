@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE insight_series_runs DROP COLUMN IF EXISTS consecutive_over_budget;
+ALTER TABLE insight_series_runs DROP COLUMN IF EXISTS circuit_broken_at;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE insight_series_runs ADD COLUMN IF NOT EXISTS consecutive_over_budget INT NOT NULL DEFAULT 0;
+ALTER TABLE insight_series_runs ADD COLUMN IF NOT EXISTS circuit_broken_at TIMESTAMP;
+
+COMMENT ON COLUMN insight_series_runs.consecutive_over_budget IS 'Number of consecutive jobs of the series whose searches took longer than the time budget of a series.';
+COMMENT ON COLUMN insight_series_runs.circuit_broken_at IS 'Timestamp at which the series was paused for exceeding its time budget too many times in a row, until a site admin resumes it.';
+
+COMMIT;
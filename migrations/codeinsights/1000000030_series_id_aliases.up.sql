@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS series_id_aliases
+(
+    old_series_id    TEXT      NOT NULL PRIMARY KEY,
+    new_series_id    TEXT      NOT NULL,
+    encoding_version INT       NOT NULL,
+    created_at       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    relinked_at      TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS series_id_aliases_unrelinked_idx ON series_id_aliases (created_at) WHERE relinked_at IS NULL;
+
+COMMENT ON TABLE series_id_aliases IS 'Maps the series IDs of series under older encodings of series IDs to their series IDs under the current encoding, so that the data of series is re-linked when the encoding changes.';
+
+COMMENT ON COLUMN series_id_aliases.old_series_id IS 'The series ID of the series under an older encoding.';
+COMMENT ON COLUMN series_id_aliases.new_series_id IS 'The series ID of the series under the current encoding.';
+COMMENT ON COLUMN series_id_aliases.encoding_version IS 'The version of the encoding of the old series ID.';
+COMMENT ON COLUMN series_id_aliases.created_at IS 'Timestamp at which the alias was recorded.';
+COMMENT ON COLUMN series_id_aliases.relinked_at IS 'Timestamp at which the data of the old series ID was re-linked to the new series ID, or null if it has not been re-linked yet.';
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insight_duplicates
+(
+    id           SERIAL    NOT NULL PRIMARY KEY,
+    group_id     TEXT      NOT NULL,
+    insight_id   TEXT      NOT NULL,
+    title        TEXT      NOT NULL,
+    user_id      INT,
+    org_id       INT,
+    canonical    BOOLEAN   NOT NULL DEFAULT FALSE,
+    detected_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS insight_duplicates_group_id_idx ON insight_duplicates (group_id);
+
+COMMENT ON TABLE insight_duplicates IS 'The insights defined in the settings of more than one namespace with identical series, as found by the most recent validation pass. Duplicate insights are recorded separately, multiplying their search load.';
+
+COMMENT ON COLUMN insight_duplicates.group_id IS 'Identifies the group of insights that are duplicates of each other.';
+COMMENT ON COLUMN insight_duplicates.insight_id IS 'The unique ID of the insight.';
+COMMENT ON COLUMN insight_duplicates.title IS 'The title of the insight.';
+COMMENT ON COLUMN insight_duplicates.user_id IS 'The user whose settings define the insight, if any.';
+COMMENT ON COLUMN insight_duplicates.org_id IS 'The organization whose settings define the insight, if any.';
+COMMENT ON COLUMN insight_duplicates.canonical IS 'Whether the insight is the one of its group that the other insights should be merged into.';
+COMMENT ON COLUMN insight_duplicates.detected_at IS 'Timestamp of the validation pass that found the duplicate.';
+
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN;
+
+DROP VIEW lsif_indexes_with_repository_name;
+
+CREATE VIEW lsif_indexes_with_repository_name AS SELECT u.id,
+    u.commit,
+    u.queued_at,
+    u.state,
+    u.failure_message,
+    u.started_at,
+    u.finished_at,
+    u.repository_id,
+    u.process_after,
+    u.num_resets,
+    u.num_failures,
+    u.docker_steps,
+    u.root,
+    u.indexer,
+    u.indexer_args,
+    u.outfile,
+    u.log_contents,
+    u.execution_logs,
+    u.local_steps,
+    r.name AS repository_name
+FROM lsif_indexes u
+JOIN repo r ON r.id = u.repository_id
+WHERE r.deleted_at IS NULL;
+
+ALTER TABLE lsif_indexes DROP COLUMN IF EXISTS executor_labels;
+
+COMMIT;
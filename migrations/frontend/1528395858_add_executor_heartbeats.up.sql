@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS executor_heartbeats (
+    id SERIAL PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    hostname text NOT NULL,
+    queue_name text NOT NULL,
+    os text NOT NULL,
+    architecture text NOT NULL,
+    executor_version text NOT NULL,
+    first_seen_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    last_seen_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE executor_heartbeats IS 'Tracks the most recent activity of executors attached to this Sourcegraph instance.';
+COMMENT ON COLUMN executor_heartbeats.name IS 'The unique name of the executor. This value is regenerated each time the executor process starts.';
+COMMENT ON COLUMN executor_heartbeats.hostname IS 'The hostname of the machine running the executor.';
+COMMENT ON COLUMN executor_heartbeats.queue_name IS 'The queue name that the executor polls for work.';
+COMMENT ON COLUMN executor_heartbeats.os IS 'The operating system running the executor.';
+COMMENT ON COLUMN executor_heartbeats.architecture IS 'The machine architecture running the executor.';
+COMMENT ON COLUMN executor_heartbeats.executor_version IS 'The version of the executor.';
+COMMENT ON COLUMN executor_heartbeats.first_seen_at IS 'The first time a heartbeat from the executor was received.';
+COMMENT ON COLUMN executor_heartbeats.last_seen_at IS 'The last time a heartbeat from the executor was received.';
+
+CREATE INDEX IF NOT EXISTS executor_heartbeats_last_seen_at ON executor_heartbeats(last_seen_at);
+
+COMMIT;
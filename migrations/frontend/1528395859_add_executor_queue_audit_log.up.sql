@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS executor_queue_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    queue_name text NOT NULL,
+    job_id integer NOT NULL,
+    operation text NOT NULL,
+    actor text NOT NULL,
+    message text NOT NULL DEFAULT '',
+    created_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE executor_queue_audit_log IS 'An append-only record of state transitions of jobs in the executor queues.';
+COMMENT ON COLUMN executor_queue_audit_log.operation IS 'The operation performed on the job, e.g. dequeue, markComplete, or requeue.';
+COMMENT ON COLUMN executor_queue_audit_log.actor IS 'The executor name or admin user that performed the operation.';
+COMMENT ON COLUMN executor_queue_audit_log.message IS 'Additional context for the operation, such as the error message supplied with markErrored.';
+
+CREATE INDEX IF NOT EXISTS executor_queue_audit_log_created_at ON executor_queue_audit_log(created_at);
+CREATE INDEX IF NOT EXISTS executor_queue_audit_log_queue_name_job_id ON executor_queue_audit_log(queue_name, job_id);
+
+COMMIT;
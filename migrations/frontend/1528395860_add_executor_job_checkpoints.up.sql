@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS executor_job_checkpoints (
+    queue_name text NOT NULL,
+    job_id integer NOT NULL,
+    data bytea NOT NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (queue_name, job_id)
+);
+
+COMMENT ON TABLE executor_job_checkpoints IS 'The most recent progress checkpoint reported by an executor for a job in an executor queue.';
+COMMENT ON COLUMN executor_job_checkpoints.data IS 'An opaque blob written by the job and handed back to the executor that next dequeues the job.';
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS workerutil_idempotency_keys (
+    queue_name text NOT NULL,
+    idempotency_key text NOT NULL,
+    record_id integer NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (queue_name, idempotency_key)
+);
+
+COMMENT ON TABLE workerutil_idempotency_keys IS 'The idempotency keys supplied when enqueueing records into a dbworker queue, and the record created for each key.';
+COMMENT ON COLUMN workerutil_idempotency_keys.record_id IS 'The identifier of the record enqueued with this key. Enqueues with the same key within the idempotency window return this record instead of creating a new one.';
+
+CREATE INDEX IF NOT EXISTS workerutil_idempotency_keys_created_at ON workerutil_idempotency_keys(created_at);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE batch_spec_executions DROP COLUMN IF EXISTS encryption_key_id;
+
+COMMIT;
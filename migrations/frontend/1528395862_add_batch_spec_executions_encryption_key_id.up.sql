@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE batch_spec_executions ADD COLUMN IF NOT EXISTS encryption_key_id text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN batch_spec_executions.encryption_key_id IS 'The version of the key used to encrypt the batch spec, which is envelope encrypted if non-empty.';
+
+COMMIT;
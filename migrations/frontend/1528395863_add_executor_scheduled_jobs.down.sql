@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS executor_scheduled_jobs;
+
+COMMIT;
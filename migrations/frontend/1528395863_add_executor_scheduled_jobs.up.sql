@@ -0,0 +1,26 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS executor_scheduled_jobs (
+    id SERIAL PRIMARY KEY,
+    queue_name text NOT NULL,
+    schedule text NOT NULL,
+    payload jsonb NOT NULL DEFAULT '{}'::jsonb,
+    paused boolean NOT NULL DEFAULT false,
+    next_run_at timestamp with time zone NOT NULL,
+    last_run_at timestamp with time zone,
+    last_job_id integer,
+    last_error text,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE executor_scheduled_jobs IS 'Schedules on which the executor-queue enqueues jobs into an executor queue.';
+COMMENT ON COLUMN executor_scheduled_jobs.schedule IS 'A five-field cron expression (minute, hour, day of month, month, day of week) evaluated in UTC.';
+COMMENT ON COLUMN executor_scheduled_jobs.payload IS 'The queue-specific description of the job enqueued on each run.';
+COMMENT ON COLUMN executor_scheduled_jobs.next_run_at IS 'The time at which the next job is enqueued.';
+COMMENT ON COLUMN executor_scheduled_jobs.last_job_id IS 'The identifier of the job enqueued by the most recent successful run.';
+COMMENT ON COLUMN executor_scheduled_jobs.last_error IS 'The error of the most recent run, or null if it succeeded.';
+
+CREATE INDEX IF NOT EXISTS executor_scheduled_jobs_next_run_at ON executor_scheduled_jobs(next_run_at) WHERE NOT paused;
+
+COMMIT;
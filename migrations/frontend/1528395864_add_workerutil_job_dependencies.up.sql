@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS workerutil_job_dependencies (
+    queue_name text NOT NULL,
+    job_id integer NOT NULL,
+    depends_on_job_id integer NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (queue_name, job_id, depends_on_job_id)
+);
+
+COMMENT ON TABLE workerutil_job_dependencies IS 'Dependencies between the records of a dbworker queue. A record is not dequeued until all records it depends on have completed.';
+COMMENT ON COLUMN workerutil_job_dependencies.job_id IS 'The identifier of the dependent record.';
+COMMENT ON COLUMN workerutil_job_dependencies.depends_on_job_id IS 'The identifier of the record that must complete before the dependent record is dequeued. If this record fails or is deleted, the dependent record fails.';
+
+CREATE INDEX IF NOT EXISTS workerutil_job_dependencies_depends_on_job_id ON workerutil_job_dependencies(queue_name, depends_on_job_id);
+
+COMMIT;
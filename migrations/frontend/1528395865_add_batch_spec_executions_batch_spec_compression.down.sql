@@ -0,0 +1,6 @@
+BEGIN;
+
+-- The column must remain in place so that the out-of-band down migration can
+-- decompress existing batch specs, so no changes here.
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+ALTER TABLE batch_spec_executions ADD COLUMN IF NOT EXISTS batch_spec_compression text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN batch_spec_executions.batch_spec_compression IS 'The algorithm with which the batch spec was compressed before it was stored (and encrypted), or empty if the batch spec is not compressed.';
+
+INSERT INTO out_of_band_migrations (id, team, component, description, introduced_version_major, introduced_version_minor, non_destructive, is_enterprise)
+VALUES (
+    11,                                              -- This must be consistent across all Sourcegraph instances
+    'batch-changes',                                 -- Team owning migration
+    'frontend-db.batch-spec-executions',             -- Component being migrated
+    'Compress batch spec execution payloads',        -- Description
+    3,                                               -- The next major release
+    31,                                              -- The next minor release
+    false,                                           -- Compressed payloads cannot be read by previous versions
+    true                                             -- Enterprise only
+)
+ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE executor_heartbeats DROP COLUMN IF EXISTS declared_dead_at;
+
+COMMIT;
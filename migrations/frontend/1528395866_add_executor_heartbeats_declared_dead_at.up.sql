@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE executor_heartbeats ADD COLUMN IF NOT EXISTS declared_dead_at timestamp with time zone;
+
+COMMENT ON COLUMN executor_heartbeats.declared_dead_at IS 'The time at which the executor was declared dead and its jobs were requeued. Cleared on the next heartbeat from the executor.';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS executor_queue_concurrency_limits;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS executor_queue_concurrency_limits (
+    queue_name text PRIMARY KEY,
+    max_processing integer NOT NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE executor_queue_concurrency_limits IS 'Runtime overrides of the maximum number of jobs of an executor queue that may be processing at once.';
+COMMENT ON COLUMN executor_queue_concurrency_limits.max_processing IS 'The maximum number of processing jobs. Zero removes the limit.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS executor_queue_region_stats;
+DROP TABLE IF EXISTS executor_job_regions;
+ALTER TABLE executor_heartbeats DROP COLUMN IF EXISTS region;
+
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN;
+
+ALTER TABLE executor_heartbeats ADD COLUMN IF NOT EXISTS region text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN executor_heartbeats.region IS 'The region of the executor-queue instance that received the most recent heartbeat. Empty outside of federated deployments.';
+
+CREATE TABLE IF NOT EXISTS executor_job_regions (
+    queue_name text NOT NULL,
+    job_id integer NOT NULL,
+    region text NOT NULL,
+    PRIMARY KEY (queue_name, job_id)
+);
+
+COMMENT ON TABLE executor_job_regions IS 'The region of executor-queue instances allowed to hand out a job in an executor queue. Jobs without a row may be handed out by any instance.';
+
+CREATE TABLE IF NOT EXISTS executor_queue_region_stats (
+    region text NOT NULL,
+    queue_name text NOT NULL,
+    queued integer NOT NULL,
+    processing integer NOT NULL,
+    active_executors integer NOT NULL,
+    reported_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (region, queue_name)
+);
+
+COMMENT ON TABLE executor_queue_region_stats IS 'The most recent queue statistics reported by a regional executor-queue instance to its parent instance.';
+COMMENT ON COLUMN executor_queue_region_stats.queued IS 'The number of queued jobs the region may hand out.';
+COMMENT ON COLUMN executor_queue_region_stats.processing IS 'The number of jobs tagged for the region that are being processed.';
+COMMENT ON COLUMN executor_queue_region_stats.active_executors IS 'The number of executors of the region that recently sent a heartbeat.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE insights_query_runner_jobs ADD COLUMN IF NOT EXISTS queued_at timestamp with time zone DEFAULT NOW();
+
+COMMENT ON COLUMN insights_query_runner_jobs.queued_at IS 'The time at which the job was enqueued. Used to raise the effective priority of jobs that have waited long.';
+
+COMMIT;
@@ -0,0 +1,38 @@
+BEGIN;
+
+ALTER TABLE lsif_indexes ADD COLUMN IF NOT EXISTS trace_context jsonb;
+ALTER TABLE batch_spec_executions ADD COLUMN IF NOT EXISTS trace_context jsonb;
+
+COMMENT ON COLUMN lsif_indexes.trace_context IS 'The span context of the request that enqueued the index job, encoded as an OpenTracing text map. Handed to executors so that their spans link back to the originating trace.';
+COMMENT ON COLUMN batch_spec_executions.trace_context IS 'The span context of the request that enqueued the execution, encoded as an OpenTracing text map. Handed to executors so that their spans link back to the originating trace.';
+
+DROP VIEW lsif_indexes_with_repository_name;
+
+CREATE VIEW lsif_indexes_with_repository_name AS SELECT u.id,
+    u.commit,
+    u.queued_at,
+    u.state,
+    u.failure_message,
+    u.started_at,
+    u.finished_at,
+    u.repository_id,
+    u.process_after,
+    u.num_resets,
+    u.num_failures,
+    u.docker_steps,
+    u.root,
+    u.indexer,
+    u.indexer_args,
+    u.outfile,
+    u.log_contents,
+    u.execution_logs,
+    u.local_steps,
+    u.executor_labels,
+    u.dependency_repositories,
+    u.trace_context,
+    r.name AS repository_name
+FROM lsif_indexes u
+JOIN repo r ON r.id = u.repository_id
+WHERE r.deleted_at IS NULL;
+
+COMMIT;
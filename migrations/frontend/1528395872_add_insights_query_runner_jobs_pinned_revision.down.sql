@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE insights_query_runner_jobs DROP COLUMN IF EXISTS pinned_repo;
+ALTER TABLE insights_query_runner_jobs DROP COLUMN IF EXISTS pinned_revision;
+
+COMMIT;
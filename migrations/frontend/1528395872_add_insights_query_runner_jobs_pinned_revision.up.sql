@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE insights_query_runner_jobs ADD COLUMN IF NOT EXISTS pinned_repo text;
+ALTER TABLE insights_query_runner_jobs ADD COLUMN IF NOT EXISTS pinned_revision text;
+
+COMMENT ON COLUMN insights_query_runner_jobs.pinned_repo IS 'The name of the repository whose revision at the record time the search query is pinned to, if any.';
+COMMENT ON COLUMN insights_query_runner_jobs.pinned_revision IS 'The commit of the pinned repository at the record time, resolved when the job is first executed.';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS insights_webhook_runner_jobs;
+
+COMMIT;
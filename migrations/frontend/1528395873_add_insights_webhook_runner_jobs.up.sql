@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insights_webhook_runner_jobs (
+    id                SERIAL PRIMARY KEY,
+    series_id         text NOT NULL,
+    webhook_url       text NOT NULL,
+    record_time       timestamp with time zone,
+    state             text DEFAULT 'queued',
+    failure_message   text,
+    started_at        timestamp with time zone,
+    finished_at       timestamp with time zone,
+    process_after     timestamp with time zone,
+    num_resets        integer NOT NULL DEFAULT 0,
+    num_failures      integer NOT NULL DEFAULT 0,
+    execution_logs    json[],
+    worker_hostname   text NOT NULL DEFAULT '',
+    last_heartbeat_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS insights_webhook_runner_jobs_state_btree ON insights_webhook_runner_jobs USING btree (state);
+
+COMMENT ON TABLE insights_webhook_runner_jobs IS 'See [enterprise/internal/insights/background/webhookrunner/worker.go:Job](https://sourcegraph.com/search?q=repo:%5Egithub%5C.com/sourcegraph/sourcegraph%24+file:enterprise/internal/insights/background/webhookrunner/worker.go+type+Job&patternType=literal)';
+
+COMMENT ON COLUMN insights_webhook_runner_jobs.webhook_url IS 'The URL the data point of the series is fetched from.';
+COMMENT ON COLUMN insights_webhook_runner_jobs.record_time IS 'The time the data point is recorded at.';
+
+COMMIT;
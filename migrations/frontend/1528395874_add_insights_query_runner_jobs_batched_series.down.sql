@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE insights_query_runner_jobs DROP COLUMN IF EXISTS batched_series;
+
+COMMIT;
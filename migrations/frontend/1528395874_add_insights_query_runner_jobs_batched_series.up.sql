@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE insights_query_runner_jobs ADD COLUMN IF NOT EXISTS batched_series jsonb;
+
+COMMENT ON COLUMN insights_query_runner_jobs.batched_series IS 'The series whose search queries are batched into the search query of the job, if any. The matches of the search are recorded for each of these series.';
+
+COMMIT;
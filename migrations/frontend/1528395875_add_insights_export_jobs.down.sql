@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS insights_export_jobs;
+
+COMMIT;
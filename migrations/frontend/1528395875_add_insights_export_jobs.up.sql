@@ -0,0 +1,31 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insights_export_jobs (
+    id                SERIAL PRIMARY KEY,
+    insight_id        text NOT NULL,
+    format            text NOT NULL,
+    user_id           integer NOT NULL,
+    data              bytea,
+    created_at        timestamp with time zone NOT NULL DEFAULT NOW(),
+    state             text DEFAULT 'queued',
+    failure_message   text,
+    started_at        timestamp with time zone,
+    finished_at       timestamp with time zone,
+    process_after     timestamp with time zone,
+    num_resets        integer NOT NULL DEFAULT 0,
+    num_failures      integer NOT NULL DEFAULT 0,
+    execution_logs    json[],
+    worker_hostname   text NOT NULL DEFAULT '',
+    last_heartbeat_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS insights_export_jobs_state_btree ON insights_export_jobs USING btree (state);
+
+COMMENT ON TABLE insights_export_jobs IS 'See [enterprise/internal/insights/background/exportrunner/worker.go:Job](https://sourcegraph.com/search?q=repo:%5Egithub%5C.com/sourcegraph/sourcegraph%24+file:enterprise/internal/insights/background/exportrunner/worker.go+type+Job&patternType=literal)';
+
+COMMENT ON COLUMN insights_export_jobs.insight_id IS 'The unique ID of the exported insight.';
+COMMENT ON COLUMN insights_export_jobs.format IS 'The format of the export, csv or json.';
+COMMENT ON COLUMN insights_export_jobs.user_id IS 'The ID of the user who requested the export. The data of the export is restricted to the repositories the user can access.';
+COMMENT ON COLUMN insights_export_jobs.data IS 'The exported data, once the export is completed.';
+
+COMMIT;
@@ -0,0 +1,33 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insights_snapshot_jobs (
+    id                SERIAL PRIMARY KEY,
+    series_id         text NOT NULL,
+    from_time         timestamp with time zone NOT NULL,
+    to_time           timestamp with time zone NOT NULL,
+    user_id           integer NOT NULL,
+    data              bytea,
+    created_at        timestamp with time zone NOT NULL DEFAULT NOW(),
+    state             text DEFAULT 'queued',
+    failure_message   text,
+    started_at        timestamp with time zone,
+    finished_at       timestamp with time zone,
+    process_after     timestamp with time zone,
+    num_resets        integer NOT NULL DEFAULT 0,
+    num_failures      integer NOT NULL DEFAULT 0,
+    execution_logs    json[],
+    worker_hostname   text NOT NULL DEFAULT '',
+    last_heartbeat_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS insights_snapshot_jobs_state_btree ON insights_snapshot_jobs USING btree (state);
+
+COMMENT ON TABLE insights_snapshot_jobs IS 'See [enterprise/internal/insights/background/snapshotrunner/worker.go:Job](https://sourcegraph.com/search?q=repo:%5Egithub%5C.com/sourcegraph/sourcegraph%24+file:enterprise/internal/insights/background/snapshotrunner/worker.go+type+Job&patternType=literal)';
+
+COMMENT ON COLUMN insights_snapshot_jobs.series_id IS 'The unique ID of the compared series.';
+COMMENT ON COLUMN insights_snapshot_jobs.from_time IS 'The earlier of the two points in time the series is compared at.';
+COMMENT ON COLUMN insights_snapshot_jobs.to_time IS 'The later of the two points in time the series is compared at.';
+COMMENT ON COLUMN insights_snapshot_jobs.user_id IS 'The ID of the user who requested the comparison. The comparison is restricted to the repositories the user can access.';
+COMMENT ON COLUMN insights_snapshot_jobs.data IS 'The comparison, as JSON, once it is completed.';
+
+COMMIT;
@@ -0,0 +1,37 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insights_import_jobs (
+    id                SERIAL PRIMARY KEY,
+    series_id         text NOT NULL,
+    format            text NOT NULL,
+    on_conflict       text NOT NULL,
+    user_id           integer NOT NULL,
+    data              bytea NOT NULL,
+    imported_points   integer,
+    skipped_points    integer,
+    created_at        timestamp with time zone NOT NULL DEFAULT NOW(),
+    state             text DEFAULT 'queued',
+    failure_message   text,
+    started_at        timestamp with time zone,
+    finished_at       timestamp with time zone,
+    process_after     timestamp with time zone,
+    num_resets        integer NOT NULL DEFAULT 0,
+    num_failures      integer NOT NULL DEFAULT 0,
+    execution_logs    json[],
+    worker_hostname   text NOT NULL DEFAULT '',
+    last_heartbeat_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS insights_import_jobs_state_btree ON insights_import_jobs USING btree (state);
+
+COMMENT ON TABLE insights_import_jobs IS 'See [enterprise/internal/insights/background/importrunner/worker.go:Job](https://sourcegraph.com/search?q=repo:%5Egithub%5C.com/sourcegraph/sourcegraph%24+file:enterprise/internal/insights/background/importrunner/worker.go+type+Job&patternType=literal)';
+
+COMMENT ON COLUMN insights_import_jobs.series_id IS 'The unique ID of the series the data points are imported into.';
+COMMENT ON COLUMN insights_import_jobs.format IS 'The format of the imported data, csv or json.';
+COMMENT ON COLUMN insights_import_jobs.on_conflict IS 'How imported data points that conflict with recorded data points are resolved, skip or replace.';
+COMMENT ON COLUMN insights_import_jobs.user_id IS 'The ID of the user who requested the import. Repositories of imported data points are resolved as this user.';
+COMMENT ON COLUMN insights_import_jobs.data IS 'The imported data.';
+COMMENT ON COLUMN insights_import_jobs.imported_points IS 'The number of imported data points, once the import is completed.';
+COMMENT ON COLUMN insights_import_jobs.skipped_points IS 'The number of data points that were not imported because they conflict with recorded data points, once the import is completed.';
+
+COMMIT;
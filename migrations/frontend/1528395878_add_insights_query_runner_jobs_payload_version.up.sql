@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Jobs enqueued before payload versions existed, and jobs enqueued by workers of a previous
+-- release during a deploy, are of the legacy version 1.
+ALTER TABLE insights_query_runner_jobs ADD COLUMN IF NOT EXISTS payload_version integer NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN insights_query_runner_jobs.payload_version IS 'The version of the payload of the job, i.e. of the meaning of its search query, pinned and batched series columns. Jobs of older versions are upgraded when they are dequeued.';
+
+COMMIT;
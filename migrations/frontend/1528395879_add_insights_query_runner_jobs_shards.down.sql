@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE insights_query_runner_jobs DROP COLUMN IF EXISTS search_timeout_seconds;
+ALTER TABLE insights_query_runner_jobs DROP COLUMN IF EXISTS shard_repos;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE insights_query_runner_jobs ADD COLUMN IF NOT EXISTS search_timeout_seconds integer;
+ALTER TABLE insights_query_runner_jobs ADD COLUMN IF NOT EXISTS shard_repos text[];
+
+COMMENT ON COLUMN insights_query_runner_jobs.search_timeout_seconds IS 'The time in seconds a search of the job may take before the job is split into jobs of shards of its repositories, overriding the site configuration, if any.';
+COMMENT ON COLUMN insights_query_runner_jobs.shard_repos IS 'The names of the repositories the search query of the job is restricted to, if the job searches a shard of the repositories of a job that timed out.';
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS insights_recompute_jobs (
+    id                SERIAL PRIMARY KEY,
+    series_id         text NOT NULL,
+    user_id           integer NOT NULL,
+    deleted_points    integer,
+    created_at        timestamp with time zone NOT NULL DEFAULT NOW(),
+    state             text DEFAULT 'queued',
+    failure_message   text,
+    started_at        timestamp with time zone,
+    finished_at       timestamp with time zone,
+    process_after     timestamp with time zone,
+    num_resets        integer NOT NULL DEFAULT 0,
+    num_failures      integer NOT NULL DEFAULT 0,
+    execution_logs    json[],
+    worker_hostname   text NOT NULL DEFAULT '',
+    last_heartbeat_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS insights_recompute_jobs_state_btree ON insights_recompute_jobs USING btree (state);
+
+COMMENT ON TABLE insights_recompute_jobs IS 'See [enterprise/internal/insights/background/recomputerunner/worker.go:Job](https://sourcegraph.com/search?q=repo:%5Egithub%5C.com/sourcegraph/sourcegraph%24+file:enterprise/internal/insights/background/recomputerunner/worker.go+type+Job&patternType=literal)';
+
+COMMENT ON COLUMN insights_recompute_jobs.series_id IS 'The unique ID of the series whose data is recomputed.';
+COMMENT ON COLUMN insights_recompute_jobs.user_id IS 'The ID of the site admin who requested the recomputation.';
+COMMENT ON COLUMN insights_recompute_jobs.deleted_points IS 'The number of recorded data points deleted to recompute the series, once they are deleted.';
+
+COMMIT;
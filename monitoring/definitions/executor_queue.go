@@ -1,6 +1,8 @@
 package definitions
 
 import (
+	"time"
+
 	"github.com/sourcegraph/sourcegraph/monitoring/definitions/shared"
 	"github.com/sourcegraph/sourcegraph/monitoring/monitoring"
 )
@@ -19,6 +21,71 @@ func ExecutorQueue() *monitoring.Container {
 			shared.CodeIntelligence.NewExecutorQueueGroup(containerName),
 			shared.CodeIntelligence.NewIndexDBWorkerStoreGroup(containerName),
 
+			{
+				Title: "Queue SLOs",
+				Rows: []monitoring.Row{
+					{
+						{
+							Name:        "oldest_queued_job_age",
+							Description: "age of the oldest queued job",
+							Query:       `max by (queue)(src_executor_queue_oldest_queued_job_age_seconds)`,
+							Warning:     monitoring.Alert().GreaterOrEqual(time.Hour.Seconds(), nil).For(5 * time.Minute),
+							Panel:       monitoring.Panel().LegendFormat("{{queue}}").Unit(monitoring.Seconds),
+							Owner:       monitoring.ObservableOwnerCodeIntel,
+							PossibleSolutions: `
+								- **Check that executors are running** for the affected queue and that the src_executor_queue_active_executors metric is non-zero.
+								- **Provision more executors** if jobs are dequeued but the queue is growing faster than it is drained.
+								- **Check whether the queue is paused** via the site configuration setting 'executors.pausedQueues'.
+							`,
+						},
+						{
+							Name:        "failure_rate",
+							Description: "percentage of recently finished jobs marked as errored or failed",
+							Query:       `max by (queue)(src_executor_queue_failure_rate) * 100`,
+							Warning:     monitoring.Alert().GreaterOrEqual(25, nil).For(15 * time.Minute),
+							Panel:       monitoring.Panel().LegendFormat("{{queue}}").Unit(monitoring.Percentage),
+							Owner:       monitoring.ObservableOwnerCodeIntel,
+							PossibleSolutions: `
+								- **Inspect the failure messages** of recently failed jobs via the executor-queue admin API or audit log.
+								- **Check the executor logs** for infrastructure errors such as failed Docker or Firecracker setup.
+							`,
+						},
+						{
+							Name:        "heartbeat_gap",
+							Description: "longest time since the last heartbeat of a recently seen executor",
+							Query:       `max by (queue)(src_executor_queue_heartbeat_gap_seconds)`,
+							Warning:     monitoring.Alert().GreaterOrEqual((5 * time.Minute).Seconds(), nil),
+							Panel:       monitoring.Panel().LegendFormat("{{queue}}").Unit(monitoring.Seconds),
+							Owner:       monitoring.ObservableOwnerCodeIntel,
+							PossibleSolutions: `
+								- **Check that the executor is still running** and can reach the executor-queue. Executors that were intentionally scaled down stop counting towards this value after 'EXECUTOR_QUEUE_SLO_HEARTBEAT_GAP_LOOKBACK'.
+							`,
+						},
+					},
+				},
+			},
+
+			// src_executor_queue_record_resets_total
+			// src_executor_queue_record_reset_failures_total
+			// src_executor_queue_record_reset_errors_total
+			shared.WorkerutilResetter.NewGroup(containerName, monitoring.ObservableOwnerCodeIntel, shared.ResetterGroupOptions{
+				GroupConstructorOptions: shared.GroupConstructorOptions{
+					Namespace:       "executor-queue",
+					DescriptionRoot: "stalled job resetter",
+					Hidden:          true,
+
+					ObservableConstructorOptions: shared.ObservableConstructorOptions{
+						MetricNameRoot:        "executor_queue",
+						MetricDescriptionRoot: "job",
+						By:                    []string{"queue"},
+					},
+				},
+
+				RecordResets:        shared.NoAlertsOption("none"),
+				RecordResetFailures: shared.NoAlertsOption("none"),
+				Errors:              shared.NoAlertsOption("none"),
+			}),
+
 			// Resource monitoring
 			shared.NewFrontendInternalAPIErrorResponseMonitoringGroup(containerName, monitoring.ObservableOwnerCodeIntel, nil),
 			shared.NewDatabaseConnectionsMonitoringGroup(containerName),
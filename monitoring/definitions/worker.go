@@ -120,26 +120,6 @@ func Worker() *monitoring.Container {
 				Errors:              shared.NoAlertsOption("none"),
 			}),
 
-			// src_codeintel_background_index_resets_total
-			// src_codeintel_background_index_reset_failures_total
-			// src_codeintel_background_index_reset_errors_total
-			shared.WorkerutilResetter.NewGroup(containerName, monitoring.ObservableOwnerCodeIntel, shared.ResetterGroupOptions{
-				GroupConstructorOptions: shared.GroupConstructorOptions{
-					Namespace:       "codeintel",
-					DescriptionRoot: "lsif_index record resetter",
-					Hidden:          true,
-
-					ObservableConstructorOptions: shared.ObservableConstructorOptions{
-						MetricNameRoot:        "codeintel_background_index",
-						MetricDescriptionRoot: "lsif_index",
-					},
-				},
-
-				RecordResets:        shared.NoAlertsOption("none"),
-				RecordResetFailures: shared.NoAlertsOption("none"),
-				Errors:              shared.NoAlertsOption("none"),
-			}),
-
 			// src_codeintel_background_dependency_index_resets_total
 			// src_codeintel_background_dependency_index_reset_failures_total
 			// src_codeintel_background_dependency_index_reset_errors_total
@@ -148,6 +148,14 @@ type BatchChangeRolloutWindow struct {
 	Start string `json:"start,omitempty"`
 }
 
+// BatchChangesExecutionQuotas description: Limits on the number of server-side batch spec executions per namespace (user or organization). Omitted or zero limits are unlimited.
+type BatchChangesExecutionQuotas struct {
+	// MaxProcessingPerNamespace description: The maximum number of batch spec executions per namespace that executors may process concurrently. Additional executions remain queued until a slot frees up.
+	MaxProcessingPerNamespace int `json:"maxProcessingPerNamespace,omitempty"`
+	// MaxQueuedPerNamespace description: The maximum number of queued batch spec executions per namespace. Creating an execution beyond this limit fails.
+	MaxQueuedPerNamespace int `json:"maxQueuedPerNamespace,omitempty"`
+}
+
 // BatchSpec description: A batch specification, which describes the batch change and what kinds of changes to make (or what existing changesets to track).
 type BatchSpec struct {
 	// ChangesetTemplate description: A template describing how to create (and update) changesets with the file changes produced by the command steps.
@@ -368,6 +376,8 @@ type ChangesetTemplate struct {
 	Branch string `json:"branch"`
 	// Commit description: The Git commit to create with the changes.
 	Commit ExpandedGitCommitDescription `json:"commit"`
+	// MaxExecutionTime description: The maximum time an executor may spend executing the batch spec server-side, as a duration such as "2h" or "90m". If omitted, the executor's default is used. Durations above the limit configured by the site admin are capped.
+	MaxExecutionTime string `json:"maxExecutionTime,omitempty"`
 	// Published description: Whether to publish the changeset. An unpublished changeset can be previewed on Sourcegraph by any person who can view the batch change, but its commit, branch, and pull request aren't created on the code host. A published changeset results in a commit, branch, and pull request being created on the code host. If omitted, the publication state is controlled from the Batch Changes UI.
 	Published interface{} `json:"published,omitempty"`
 	// Title description: The title of the changeset.
@@ -852,6 +862,10 @@ type Insight struct {
 	Description string `json:"description"`
 	// Id description: A globally  unique identifier for this insight.
 	Id string `json:"id"`
+	// Repositories description: The names of the repositories the search queries of the series of this insight are restricted to, e.g. ["github.com/sourcegraph/sourcegraph"]. By default, all repositories are searched.
+	Repositories []string `json:"repositories,omitempty"`
+	// RepositoryPattern description: A regular expression matching the names of the repositories the search queries of the series of this insight are restricted to, as in a repo: filter. Cannot be combined with repositories.
+	RepositoryPattern string `json:"repositoryPattern,omitempty"`
 	// Series description: Series of data to show for this insight
 	Series []*InsightSeries `json:"series"`
 	// Title description: The short title of this insight
@@ -865,15 +879,55 @@ type InsightDashboard struct {
 	Title string `json:"title"`
 }
 type InsightSeries struct {
+	// BusinessDaysOnly description: Whether the series is only recorded on business days, e.g. for business metrics that do not change on weekends. Weekend days and holidays are configured by the insights.recording site settings. Backfilled data points that would fall on a weekend day or holiday are recorded on the business day before.
+	BusinessDaysOnly bool `json:"businessDaysOnly,omitempty"`
+	// Expression description: Compute the series from the other series of the insight with an arithmetic expression, e.g. `$1 / $2 * 1000` for the value of the first series per thousand of the second. `$n` is the value of the n-th series of the insight. Expressions support numbers, `+`, `-`, `*`, `/`, and parentheses. Data points are computed whenever the series they refer to record a data point.
+	Expression string `json:"expression,omitempty"`
+	// GeneratedFromCaptureGroups description: Whether the series is split into one series per distinct value matched by the first capture group of the regexp search query, e.g. `go (\d\.\d+)`.
+	GeneratedFromCaptureGroups bool `json:"generatedFromCaptureGroups,omitempty"`
+	// GroupBy description: Split the series into one series per author or team its matches are attributed to. `author` attributes the matches of commit and diff searches (`type:commit` or `type:diff`) to the email of their commit author. `team` attributes them to the teams of their commit author, and file matches to the team owning their path, according to the insights.attribution.teams site setting. Matches that cannot be attributed are not counted.
+	GroupBy string `json:"groupBy,omitempty"`
+	// Interval description: How often a new data point is recorded for this series.
+	Interval string `json:"interval,omitempty"`
 	// Label description: The label to use for the series in the graph.
 	Label string `json:"label"`
 	// RepositoriesList description: Performs a search query and shows the number of results returned.
 	RepositoriesList []interface{} `json:"repositoriesList,omitempty"`
 	// Search description: Performs a search query and shows the number of results returned.
 	Search string `json:"search,omitempty"`
-	// Webhook description: (not yet supported) Fetch data from a webhook URL.
+	// Webhook description: Fetch data from a webhook URL. The URL receives a POST request with the series ID and record time of each data point, and must respond with a JSON object holding the value of the data point, e.g. {"value": 42}.
 	Webhook string `json:"webhook,omitempty"`
 }
+type InsightTemplate struct {
+	// Description description: Description of the instances of the template. `{repository}` is replaced with the name of the repository of an instance.
+	Description string `json:"description,omitempty"`
+	// RepositoryPattern description: Regular expression matching the names of the repositories the template is instantiated for, e.g. `^github\.com/myorg/`.
+	RepositoryPattern string `json:"repositoryPattern"`
+	// Series description: Series of the instances of the template, which only search the repository of their instance.
+	Series []*InsightTemplateSeries `json:"series"`
+	// Title description: Title of the instances of the template. `{repository}` is replaced with the name of the repository of an instance, e.g. `Go version adoption in {repository}`.
+	Title string `json:"title"`
+}
+type InsightTemplateSeries struct {
+	// Interval description: How often a new data point is recorded for the series.
+	Interval string `json:"interval,omitempty"`
+	// Name description: The label of the series.
+	Name string `json:"name"`
+	// Query description: The search query of the series, which is restricted to the repository of the instance.
+	Query string `json:"query"`
+	// Stroke description: The color of the series.
+	Stroke string `json:"stroke,omitempty"`
+}
+
+// InsightsAttributionTeam description: A team that the matches of Code Insights series grouped by team are attributed to.
+type InsightsAttributionTeam struct {
+	// Members description: The commit author emails of the members of the team.
+	Members []string `json:"members,omitempty"`
+	// Name description: The name of the team, which labels its series.
+	Name string `json:"name"`
+	// Paths description: The paths owned by the team, in the syntax of CODEOWNERS files: paths starting with / are relative to the repository root and otherwise match at any depth, paths ending with / match the files of a directory, and * and ** match any characters except /, and any characters.
+	Paths []string `json:"paths,omitempty"`
+}
 
 // JVMPackagesConnection description: Configuration for a connection to a JVM packages repository.
 type JVMPackagesConnection struct {
@@ -1310,6 +1364,8 @@ type Settings struct {
 	InsightsDisplayLocationDirectory    *bool                       `json:"insights.displayLocation.directory,omitempty"`
 	InsightsDisplayLocationHomepage     *bool                       `json:"insights.displayLocation.homepage,omitempty"`
 	InsightsDisplayLocationInsightsPage *bool                       `json:"insights.displayLocation.insightsPage,omitempty"`
+	// InsightsTemplates description: EXPERIMENTAL: Code Insights templates of an organization. Every template is instantiated as a separate insight for each repository whose name matches its repository pattern, and instances are added and removed as repositories are added and removed. Templates are only read from organization settings.
+	InsightsTemplates map[string]InsightTemplate `json:"insights.templates,omitempty"`
 	// Motd description: DEPRECATED: Use `notices` instead.
 	//
 	// An array (often with just one element) of messages to display at the top of all pages, including for unauthenticated users. Users may dismiss a message (and any message with the same string value will remain dismissed for the user).
@@ -1438,6 +1494,10 @@ type SiteConfiguration struct {
 	AuthzEnforceForSiteAdmins bool `json:"authz.enforceForSiteAdmins,omitempty"`
 	// BatchChangesEnabled description: Enables/disables the Batch Changes feature.
 	BatchChangesEnabled *bool `json:"batchChanges.enabled,omitempty"`
+	// BatchChangesExecutionPayloadCompression description: The algorithm used to compress the batch specs of server-side batch spec executions before they are stored. Once compression is enabled, the batch specs of existing executions are compressed in the background. Batch specs that are already compressed, with any algorithm, remain readable when this setting changes.
+	BatchChangesExecutionPayloadCompression string `json:"batchChanges.executionPayloadCompression,omitempty"`
+	// BatchChangesExecutionQuotas description: Limits on the number of server-side batch spec executions per namespace (user or organization). Omitted or zero limits are unlimited.
+	BatchChangesExecutionQuotas *BatchChangesExecutionQuotas `json:"batchChanges.executionQuotas,omitempty"`
 	// BatchChangesRestrictToAdmins description: When enabled, only site admins can create and apply batch changes.
 	BatchChangesRestrictToAdmins *bool `json:"batchChanges.restrictToAdmins,omitempty"`
 	// BatchChangesRolloutWindows description: Specifies specific windows, which can have associated rate limits, to be used when publishing changesets. All days and times are handled in UTC.
@@ -1476,6 +1536,8 @@ type SiteConfiguration struct {
 	EmailSmtp *SMTPServerConfig `json:"email.smtp,omitempty"`
 	// EncryptionKeys description: Configuration for encryption keys used to encrypt data at rest in the database.
 	EncryptionKeys *EncryptionKeys `json:"encryption.keys,omitempty"`
+	// ExecutorsPausedQueues description: The names of executor queues (e.g. codeintel or batches) that do not hand out new jobs to executors. Jobs that were already dequeued continue to be processed. Remove a queue from this list to resume it.
+	ExecutorsPausedQueues []string `json:"executors.pausedQueues,omitempty"`
 	// ExperimentalFeatures description: Experimental features to enable or disable. Features that are now enabled by default are marked as deprecated.
 	ExperimentalFeatures *ExperimentalFeatures `json:"experimentalFeatures,omitempty"`
 	// Extensions description: Configures Sourcegraph extensions.
@@ -1504,6 +1566,10 @@ type SiteConfiguration struct {
 	HtmlHeadBottom string `json:"htmlHeadBottom,omitempty"`
 	// HtmlHeadTop description: HTML to inject at the top of the `<head>` element on each page, for analytics scripts
 	HtmlHeadTop string `json:"htmlHeadTop,omitempty"`
+	// InsightsAttributionTeams description: Teams that the matches of Code Insights series grouped by team are attributed to. Commit and diff matches are attributed to the teams whose members include their commit author, and file matches to the team owning their path. Like in a CODEOWNERS file, when several paths match a file, the last one wins.
+	InsightsAttributionTeams []*InsightsAttributionTeam `json:"insights.attribution.teams,omitempty"`
+	// InsightsEnqueuerIntervalSeconds description: Interval in seconds between the runs of the Code Insights enqueuer, which enqueues the queries of the series that are due to be recorded. Changes take effect without restarting the worker, including on the wait for the next run. Zero uses the default interval of 10 minutes.
+	InsightsEnqueuerIntervalSeconds int `json:"insights.enqueuer.intervalSeconds,omitempty"`
 	// InsightsHistoricalFrameLength description: (debug) duration of historical insights timeframes, one point per repository will be recorded in each timeframe.
 	InsightsHistoricalFrameLength string `json:"insights.historical.frameLength,omitempty"`
 	// InsightsHistoricalFrames description: (debug) number of historical insights timeframes to populate
@@ -1512,10 +1578,42 @@ type SiteConfiguration struct {
 	InsightsHistoricalSpeedFactor *float64 `json:"insights.historical.speedFactor,omitempty"`
 	// InsightsHistoricalWorkerRateLimit description: Maximum number of historical Code Insights data frames that may be analyzed per second.
 	InsightsHistoricalWorkerRateLimit *float64 `json:"insights.historical.worker.rateLimit,omitempty"`
-	// InsightsQueryWorkerConcurrency description: Number of concurrent executions of a code insight query on a worker node
+	// InsightsIncrementalFullRecomputeAfterDays description: Enables incremental recording of Code Insights: instead of searching all repositories every time a series is recorded, only the repositories with new commits since the previous recording are searched, and the previous values of the other repositories are carried forward. Series are still recorded from a full search once this number of days has passed since their last full recording, and whenever their previous recording failed. Zero disables incremental recording.
+	InsightsIncrementalFullRecomputeAfterDays int `json:"insights.incremental.fullRecomputeAfterDays,omitempty"`
+	// InsightsPrometheusAPIEnabled description: Serves the data points of Code Insights series at /.api/insights/prometheus through a read-only subset of the Prometheus HTTP API (the query and query_range endpoints), so that they can be added to Grafana dashboards with a Prometheus data source. Queries select a series by its ID, e.g. insight_series{series_id="..."}. Requests are authenticated like other API requests, and only return data points of repositories the user can access.
+	InsightsPrometheusAPIEnabled bool `json:"insights.prometheusAPI.enabled,omitempty"`
+	// InsightsQueryWorkerBackfillOnExecutors description: Hands historical backfill queries of Code Insights to the insights queue of the executor-queue instead of running them on worker nodes. Executors must be deployed to process the queue.
+	InsightsQueryWorkerBackfillOnExecutors bool `json:"insights.query.worker.backfillOnExecutors,omitempty"`
+	// InsightsQueryWorkerCircuitBreakerThreshold description: Number of consecutive Code Insights queries of a series exceeding insights.query.worker.seriesTimeBudgetSeconds after which the series is paused.
+	InsightsQueryWorkerCircuitBreakerThreshold int `json:"insights.query.worker.circuitBreakerThreshold,omitempty"`
+	// InsightsQueryWorkerConcurrency description: Number of concurrent executions of a code insight query on a worker node, at most 64. Changes take effect without restarting the worker. The INSIGHTS_QUERY_WORKER_CONCURRENCY environment variable of the worker overrides this setting.
 	InsightsQueryWorkerConcurrency int `json:"insights.query.worker.concurrency,omitempty"`
-	// InsightsQueryWorkerRateLimit description: Maximum number of Code Insights queries initiated per second on a worker node.
+	// InsightsQueryWorkerCostBudget description: Maximum total cost of the Code Insights queries running at once on a worker node, where a literal query of all indexed repositories costs 500 and of unindexed repositories (e.g. a historical query) 5000. Queries are estimated to cost more for regexp, structural and commit searches, and less for searching fewer repositories. Cheap queries run alongside expensive ones within the budget, and queries that waited long enough are run before any other. A query is always run if no other query is running. Zero disables the budget.
+	InsightsQueryWorkerCostBudget int `json:"insights.query.worker.costBudget,omitempty"`
+	// InsightsQueryWorkerMaxQueueDepth description: Maximum number of queued Code Insights queries. Insights stop enqueueing new queries while the queue is at this depth and resume once it drains. Zero disables the limit.
+	InsightsQueryWorkerMaxQueueDepth int `json:"insights.query.worker.maxQueueDepth,omitempty"`
+	// InsightsQueryWorkerPaused description: Pauses the execution of Code Insights queries on worker nodes and executors, e.g. to shed search load during an incident. Queued queries remain queued, and queries already running are completed. Changes take effect without restarting the worker.
+	InsightsQueryWorkerPaused bool `json:"insights.query.worker.paused,omitempty"`
+	// InsightsQueryWorkerRateLimit description: Maximum number of Code Insights searches initiated per second on a worker node, shared by all concurrent executions of queries.
 	InsightsQueryWorkerRateLimit *float64 `json:"insights.query.worker.rateLimit,omitempty"`
+	// InsightsQueryWorkerSearchConcurrency description: Maximum number of Code Insights searches running at once on a worker node, shared by all concurrent executions of queries. Unlike insights.query.worker.concurrency, only the searches themselves are limited, not the rest of the work of a query such as recording its results. Zero leaves searches limited by insights.query.worker.concurrency only. Changes take effect without restarting the worker. The INSIGHTS_QUERY_WORKER_SEARCH_CONCURRENCY environment variable of the worker overrides this setting.
+	InsightsQueryWorkerSearchConcurrency int `json:"insights.query.worker.searchConcurrency,omitempty"`
+	// InsightsQueryWorkerSearchTimeoutSeconds description: Maximum time in seconds a single search of a Code Insights query may take. A query whose search of all repositories times out is split into queries of shards of the repositories, which record a single data point together, so that very broad queries eventually complete. Shards whose search times out again are split further. Zero disables the timeout.
+	InsightsQueryWorkerSearchTimeoutSeconds int `json:"insights.query.worker.searchTimeoutSeconds,omitempty"`
+	// InsightsQueryWorkerSeriesTimeBudgetSeconds description: Maximum time in seconds the searches of a single Code Insights query may take to record a series. A query that takes longer is recorded, but counts against the series: once the queries of a series exceed the budget insights.query.worker.circuitBreakerThreshold times in a row, the series is paused until a site admin resumes it. Zero disables the budget.
+	InsightsQueryWorkerSeriesTimeBudgetSeconds int `json:"insights.query.worker.seriesTimeBudgetSeconds,omitempty"`
+	// InsightsRecordingHolidays description: Dates (YYYY-MM-DD) on which Code Insights series recorded on business days only are not recorded, nor backfilled, in addition to weekend days.
+	InsightsRecordingHolidays []string `json:"insights.recording.holidays,omitempty"`
+	// InsightsRecordingTimeZone description: IANA time zone in which the recording intervals of Code Insights series are aligned, e.g. daily series are recorded once per day from midnight in this time zone, and weekly series from Monday midnight. Also determines the dates of weekend days and holidays.
+	InsightsRecordingTimeZone string `json:"insights.recording.timeZone,omitempty"`
+	// InsightsRecordingWeekendDays description: Days of the week on which Code Insights series recorded on business days only are not recorded, nor backfilled.
+	InsightsRecordingWeekendDays []string `json:"insights.recording.weekendDays,omitempty"`
+	// InsightsRetentionDownsampleAfterDays description: Number of days after which the data points of Code Insights are downsampled to the latest data point of each repository and week. Zero disables downsampling.
+	InsightsRetentionDownsampleAfterDays *int `json:"insights.retention.downsampleAfterDays,omitempty"`
+	// InsightsRetentionPruneAfterDays description: Number of days after which the data points of Code Insights are deleted. Zero keeps data points forever.
+	InsightsRetentionPruneAfterDays int `json:"insights.retention.pruneAfterDays,omitempty"`
+	// InsightsWebhookSecret description: Secret used to sign the requests sent to the webhooks of Code Insights webhook series. If set, requests carry an HMAC-SHA256 signature of their body in the X-Sourcegraph-Signature header.
+	InsightsWebhookSecret string `json:"insights.webhook.secret,omitempty"`
 	// LicenseKey description: The license key associated with a Sourcegraph product subscription, which is necessary to activate Sourcegraph Enterprise functionality. To obtain this value, contact Sourcegraph to purchase a subscription. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
 	LicenseKey string `json:"licenseKey,omitempty"`
 	// Log description: Configuration for logging and alerting, including to external services.